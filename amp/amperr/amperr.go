@@ -0,0 +1,115 @@
+// Package amperr forms structured amp errors -- an amp.Err having a stable code, a retryability flag, and key / value
+// details -- so that a client receiving one can branch on the kind of failure rather than parsing its message.
+//
+// An app returns these errors as it would any other, and a host closing a request that failed sends it to the client via
+// amp.MarshalErrTx, setting the tx's ErrCode alongside the amp.Err it holds.  A client recovers the error via
+// amp.TxMsg.CloseErr and inspects it with Is, Retryable, and Detail (which also see through errors wrapping it).
+package amperr
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/amp-3d/amp-sdk-go/amp"
+)
+
+// New returns an *amp.Err having the given code and message, retryable if the code is (see amp.ErrCode.Retryable),
+// with the given details as alternating keys and values (e.g. "path", path, "size", n), where each value is formatted
+// via fmt.Sprint.  A trailing key having no value is given the value "".
+func New(code amp.ErrCode, msg string, keyvals ...any) error {
+	err := &amp.Err{
+		Code:      code,
+		Msg:       msg,
+		Retryable: code.Retryable(),
+	}
+	setDetails(err, keyvals)
+	return err
+}
+
+// Wrap is like New, taking its message from cause and keeping the details of the *amp.Err cause is or wraps (if any).
+// Wrap returns nil if cause is nil.
+func Wrap(code amp.ErrCode, cause error, keyvals ...any) error {
+	if cause == nil {
+		return nil
+	}
+	err := code.Wrap(cause).(*amp.Err)
+	setDetails(err, keyvals)
+	return err
+}
+
+// WithRetryable returns a copy of the given error as an *amp.Err (see amp.ErrorToValue) marked as retryable or not.
+// WithRetryable returns nil if err is nil.
+func WithRetryable(err error, retryable bool) error {
+	if err == nil {
+		return nil
+	}
+	ampErr := copyErr(amp.ErrorToValue(err).(*amp.Err))
+	ampErr.Retryable = retryable
+	return ampErr
+}
+
+// WithDetails returns a copy of the given error as an *amp.Err (see amp.ErrorToValue) having the given details set as in
+// New.  WithDetails returns nil if err is nil.
+func WithDetails(err error, keyvals ...any) error {
+	if err == nil {
+		return nil
+	}
+	ampErr := copyErr(amp.ErrorToValue(err).(*amp.Err))
+	setDetails(ampErr, keyvals)
+	return ampErr
+}
+
+// Is returns true if the given error is or wraps an *amp.Err having the given code.
+func Is(err error, code amp.ErrCode) bool {
+	return errors.Is(err, &amp.Err{Code: code})
+}
+
+// Retryable returns true if the given error is or wraps an *amp.Err marked as retryable.
+func Retryable(err error) bool {
+	return amp.IsRetryable(err)
+}
+
+// Detail returns the value of the given detail of the *amp.Err the given error is or wraps, or false if there is none.
+func Detail(err error, key string) (string, bool) {
+	var ampErr *amp.Err
+	if !errors.As(err, &ampErr) {
+		return "", false
+	}
+	return ampErr.Detail(key)
+}
+
+// RetryAfter returns how long the given error asks a client to wait before issuing the request again (see
+// amp.RetryAfterDetail), or false if it does not say.
+func RetryAfter(err error) (time.Duration, bool) {
+	val, ok := Detail(err, amp.RetryAfterDetail)
+	if !ok {
+		return 0, false
+	}
+	ms, parseErr := strconv.ParseInt(val, 10, 64)
+	if parseErr != nil || ms < 0 {
+		return 0, false
+	}
+	return time.Duration(ms) * time.Millisecond, true
+}
+
+func setDetails(err *amp.Err, keyvals []any) {
+	for i := 0; i < len(keyvals); i += 2 {
+		key := fmt.Sprint(keyvals[i])
+		val := ""
+		if i+1 < len(keyvals) {
+			val = fmt.Sprint(keyvals[i+1])
+		}
+		err.WithDetail(key, val)
+	}
+}
+
+func copyErr(err *amp.Err) *amp.Err {
+	dup := *err
+	dup.Details = nil
+	for _, detail := range err.Details {
+		dup.Details = append(dup.Details, &amp.ErrDetail{Key: detail.Key, Value: detail.Value})
+	}
+	return &dup
+}
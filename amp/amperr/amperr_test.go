@@ -0,0 +1,82 @@
+package amperr_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/amp-3d/amp-sdk-go/amp"
+	"github.com/amp-3d/amp-sdk-go/amp/amperr"
+	"github.com/amp-3d/amp-sdk-go/stdlib/tag"
+)
+
+func TestErrors(t *testing.T) {
+	err := amperr.New(amp.ErrCode_CellNotFound, "no such cell", "path", "/a/b", "size", 42)
+	if !amperr.Is(err, amp.ErrCode_CellNotFound) || amperr.Is(err, amp.ErrCode_Timeout) || amperr.Retryable(err) {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if size, ok := amperr.Detail(err, "size"); !ok || size != "42" {
+		t.Fatalf("unexpected size %q", size)
+	}
+	if _, ok := amperr.Detail(err, "nope"); ok {
+		t.Fatal("expected no such detail")
+	}
+
+	// Errors wrapping an amp error are seen through
+	wrapped := fmt.Errorf("loading: %w", err)
+	if !amperr.Is(wrapped, amp.ErrCode_CellNotFound) || amp.GetErrCode(wrapped) != amp.ErrCode_CellNotFound || !errors.Is(wrapped, err) {
+		t.Fatalf("unexpected wrapped error %v", wrapped)
+	}
+	if path, _ := amperr.Detail(wrapped, "path"); path != "/a/b" {
+		t.Fatalf("unexpected path %q", path)
+	}
+	if errors.Is(amp.ErrPinIdle, amp.ErrTimeout) || !errors.Is(amp.ErrPinIdle, &amp.Err{Code: amp.ErrCode_Timeout}) {
+		t.Fatal("expected sentinels to match by code and message")
+	}
+
+	// Codes imply whether a request may be retried, which can be overridden
+	timeout := amperr.New(amp.ErrCode_Timeout, "upstream slow")
+	if !amperr.Retryable(timeout) || amperr.Retryable(amperr.WithRetryable(timeout, false)) || !amperr.Retryable(timeout) {
+		t.Fatal("unexpected retryability")
+	}
+	if !amperr.Retryable(amperr.WithRetryable(errors.New("flaky"), true)) {
+		t.Fatal("expected plain error to be made retryable")
+	}
+
+	// Wrapping keeps the details of the cause
+	err = amperr.Wrap(amp.ErrCode_ProviderErr, wrapped, "provider", "disk")
+	if path, _ := amperr.Detail(err, "path"); path != "/a/b" || !amperr.Is(err, amp.ErrCode_ProviderErr) {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if provider, _ := amperr.Detail(err, "provider"); provider != "disk" {
+		t.Fatalf("unexpected provider %q", provider)
+	}
+	if amperr.Wrap(amp.ErrCode_ProviderErr, nil) != nil || amperr.WithDetails(nil, "k", "v") != nil {
+		t.Fatal("expected nil")
+	}
+	if after, ok := amperr.RetryAfter(amperr.WithDetails(timeout, amp.RetryAfterDetail, 1500)); !ok || after != 1500*time.Millisecond {
+		t.Fatalf("unexpected retry after %v", after)
+	}
+
+	// A tx closing a failed request carries its code and the error itself
+	reqID := tag.New()
+	tx, txErr := amp.MarshalErrTx(reqID, wrapped)
+	if txErr != nil {
+		t.Fatal(txErr)
+	}
+	var buf []byte
+	tx.MarshalToBuffer(&buf)
+	recv, txErr := amp.ReadTxMsg(bytes.NewReader(buf))
+	if txErr != nil {
+		t.Fatal(txErr)
+	}
+	if recv.Status != amp.OpStatus_Closed || recv.ErrCode != amp.ErrCode_CellNotFound || recv.RequestID() != reqID {
+		t.Fatalf("unexpected tx %+v", recv.TxInfo)
+	}
+	closeErr := recv.CloseErr()
+	if path, _ := amperr.Detail(closeErr, "path"); !amperr.Is(closeErr, amp.ErrCode_CellNotFound) || path != "/a/b" {
+		t.Fatalf("unexpected close error %v", closeErr)
+	}
+}
@@ -0,0 +1,429 @@
+// Package amptest provides an in-process fake amp.HostSession so that an amp.App can be unit tested without a host or client.
+//
+// A test registers its app(s) with NewSession, issues pin requests, and asserts on the txs the app pushes:
+//
+//	sess := amptest.NewSession(t, myapp.App)
+//	req := sess.PinURL("myapp://cells/home")
+//	req.RequireComplete()
+//	var tab amp.TagTab
+//	req.RequireAttr(req.Cells()[0], amp.PinnedTabSpec.ID, &tab)
+package amptest
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/amp-3d/amp-sdk-go/amp"
+	"github.com/amp-3d/amp-sdk-go/stdlib/media"
+	"github.com/amp-3d/amp-sdk-go/stdlib/symbol"
+	"github.com/amp-3d/amp-sdk-go/stdlib/tag"
+	"github.com/amp-3d/amp-sdk-go/stdlib/task"
+)
+
+// DefaultTimeout is the default for Session.Timeout.
+const DefaultTimeout = 5 * time.Second
+
+// Instrumenter wraps the instances of an app as they are issued, e.g. scripting.Engine (via its Instrument method).
+type Instrumenter interface {
+	Instrument(app *amp.App, inst amp.AppInstance) amp.AppInstance
+}
+
+// Session is a fake amp.HostSession that runs apps in-process and captures everything they send.
+// As on a host, an app's pending migrations are run when it is first started (see amp.RunMigrations), and app instances validate
+// commits against the schemas they declare (see amp.ValidateCommits) and honor each request's PinAttrs (see amp.SelectAttrs).
+type Session struct {
+	task.Context
+	amp.Registry
+
+	Login     amp.Login           // returned by Auth()
+	User      amp.Identity        // returned by Identity()
+	Policy    amp.PolicyProvider  // if set, app instances are guarded by this policy (see amp.GuardAppInstance)
+	Limiter   *amp.SessionLimiter // if set, app instances are throttled by this limiter (see amp.SessionLimiter)
+	Space     *amp.Space          // if set, apps keep their LocalDataPath and Store within this space (see amp.Spaces)
+	Metrics   *amp.HostMetrics    // if set, app instances are measured by these metrics (see amp.HostMetrics.Instrument)
+	Telemetry *amp.PinTelemetry   // if set, the pins app instances serve are recorded (see amp.PinTelemetry.Instrument)
+	Scripts   Instrumenter        // if set, app instances run the hooks of these scripts (e.g. a scripting.Engine)
+	Flow      *amp.FlowController // if set, pins honor the SendWindow of their requests, acknowledged via Flow.Ack (see amp.FlowController)
+	Versions  *amp.AttrVersions   // if set, commits are checked against and advance these versions (see amp.AttrVersions.Enforce)
+	Timeout   time.Duration       // how long Request waits before failing the test
+
+	t         testing.TB
+	dataDir   string
+	bus       amp.MessageBus
+	instMu    sync.Mutex // serializes app instance creation and protects instances
+	instances map[tag.ID]amp.AppInstance
+	mu        sync.Mutex // protects the fields below
+	appAttrs  map[[2]tag.ID][]byte
+	kvStores  map[tag.ID]amp.KVStore
+	sent      []*amp.TxMsg
+	assets    []media.Asset
+}
+
+// NewSession starts a Session with the given apps registered (along with amp's builtin types).
+// The session is closed when the test completes.
+func NewSession(t testing.TB, apps ...*amp.App) *Session {
+	t.Helper()
+
+	sess := &Session{
+		Registry: amp.NewRegistry(),
+		Login: amp.Login{
+			UserUID:  "amptest",
+			HostAddr: "localhost",
+		},
+		User: amp.Identity{
+			UserUID: "amptest",
+		},
+		Timeout:   DefaultTimeout,
+		t:         t,
+		dataDir:   t.TempDir(),
+		bus:       amp.NewMessageBus(),
+		instances: make(map[tag.ID]amp.AppInstance),
+		appAttrs:  make(map[[2]tag.ID][]byte),
+		kvStores:  make(map[tag.ID]amp.KVStore),
+	}
+
+	amp.RegisterBuiltinTypes(sess.Registry)
+	for _, app := range apps {
+		if err := sess.RegisterApp(app); err != nil {
+			t.Fatalf("amptest: failed to register app %s: %v", app.AppSpec.Canonic, err)
+		}
+	}
+
+	var err error
+	sess.Context, err = task.Start(&task.Task{
+		Label: "amptest.Session",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		sess.Close()
+		select {
+		case <-sess.Done():
+		case <-time.After(sess.Timeout):
+			t.Errorf("amptest: timed out waiting for session to close")
+		}
+	})
+	return sess
+}
+
+// Implements amp.HostSession
+func (sess *Session) AssetPublisher() media.Publisher {
+	return sess
+}
+
+// Implements media.Publisher by retaining the asset (see Assets) and returning a placeholder URL.
+func (sess *Session) PublishAsset(asset media.Asset, opts media.PublishOpts) (string, error) {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+
+	sess.assets = append(sess.assets, asset)
+	return fmt.Sprintf("http://localhost/amptest/asset/%d", len(sess.assets)), nil
+}
+
+// Implements amp.HostSession
+func (sess *Session) Auth() amp.Login {
+	return sess.Login
+}
+
+// Implements amp.HostSession
+func (sess *Session) Identity() *amp.Identity {
+	return &sess.User
+}
+
+// Implements amp.HostSession by capturing the tx (see SentTxs).
+func (sess *Session) SendTx(tx *amp.TxMsg) error {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+
+	sess.sent = append(sess.sent, tx)
+	return nil
+}
+
+// Implements amp.HostSession
+func (sess *Session) GetAppInstance(appID tag.ID, autoCreate bool) (amp.AppInstance, error) {
+	sess.instMu.Lock()
+	defer sess.instMu.Unlock()
+
+	if inst := sess.instances[appID]; inst != nil {
+		return inst, nil
+	}
+	if !autoCreate {
+		return nil, amp.ErrCode_AppNotFound.Errorf("app instance not running: %s", appID)
+	}
+
+	app, err := sess.GetAppByTag(appID)
+	if err != nil {
+		return nil, err
+	}
+
+	appCtx := &appContext{
+		sess: sess,
+		app:  app,
+	}
+	appCtx.Context, err = sess.StartChild(&task.Task{
+		Label: app.AppSpec.Canonic,
+		OnClosing: func() {
+			sess.instMu.Lock()
+			inst := sess.instances[appID]
+			delete(sess.instances, appID)
+			sess.instMu.Unlock()
+			if inst != nil {
+				inst.OnClosing()
+			}
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = amp.RunMigrations(appCtx, app); err != nil {
+		appCtx.Close()
+		return nil, err
+	}
+	inst, err := app.NewAppInstance(appCtx)
+	if err != nil {
+		appCtx.Close()
+		return nil, err
+	}
+	if sess.Versions != nil {
+		inst = sess.Versions.Enforce(inst)
+	}
+	inst = amp.ValidateCommits(inst)
+	inst = amp.SelectAttrs(inst)
+	if sess.Scripts != nil {
+		inst = sess.Scripts.Instrument(app, inst)
+	}
+	if sess.Policy != nil {
+		inst = amp.GuardAppInstance(appID, inst, sess.Identity(), sess.Policy)
+	}
+	if sess.Limiter != nil {
+		inst = sess.Limiter.Limit(inst)
+	}
+	if sess.Metrics != nil {
+		inst = sess.Metrics.Instrument(app, inst)
+	}
+	if sess.Telemetry != nil {
+		inst = sess.Telemetry.Instrument(app, inst)
+	}
+	if sess.Flow != nil {
+		inst = sess.Flow.Control(inst)
+	}
+	sess.instances[appID] = inst
+	return inst, nil
+}
+
+// Bus returns the MessageBus shared by all app instances on this session, allowing a test to publish to and observe apps.
+func (sess *Session) Bus() amp.MessageBus {
+	return sess.bus
+}
+
+// SentTxs returns the txs sent to the session controller via SendTx (e.g. by amp.SendMetaAttr), in the order sent.
+func (sess *Session) SentTxs() []*amp.TxMsg {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	return append([]*amp.TxMsg{}, sess.sent...)
+}
+
+// Assets returns the assets published by apps on this session, in the order published.
+func (sess *Session) Assets() []media.Asset {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	return append([]media.Asset{}, sess.assets...)
+}
+
+// PinURL issues a pin request for the given URL to the app it invokes (see Pin).
+func (sess *Session) PinURL(pinURL string) *Request {
+	sess.t.Helper()
+	return sess.Pin(amp.PinRequest{
+		PinTarget: &amp.Tag{URL: pinURL},
+	})
+}
+
+// Pin issues the given pin request and returns the Request capturing the app's response, failing the test if the app rejects it.
+//
+// The app is selected by the target URL's scheme (or by its host when the scheme is "amp") via Registry.GetAppForInvocation.
+func (sess *Session) Pin(pinReq amp.PinRequest) *Request {
+	sess.t.Helper()
+	req, err := sess.TryPin(pinReq)
+	if err != nil {
+		sess.t.Fatalf("amptest: pin failed: %v", err)
+	}
+	return req
+}
+
+// TryPin is like Pin but returns an error rather than failing the test.
+func (sess *Session) TryPin(pinReq amp.PinRequest) (*Request, error) {
+	return sess.TryCommit(pinReq, nil)
+}
+
+// TryCommit is like TryPin except the request includes the given tx to be committed (see amp.Request.CommitTx).
+func (sess *Session) TryCommit(pinReq amp.PinRequest, commitTx *amp.TxMsg) (*Request, error) {
+	req, err := sess.issue(pinReq, commitTx, tag.New())
+	if err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// Replay issues each pin request recorded in the given journal (see amp.JournalTransport) in the order recorded, returning the
+// resulting Requests keyed by request ID.  Replayed requests keep their recorded IDs so that the txs an app pushes can be compared
+// with those the journal recorded as sent.
+//
+// Each request is given until it completes or is synced (or Timeout elapses) before the next is issued, so apps see the same
+// sequence of requests on every replay.  Requests the app rejects are included, having completed with the rejection error.
+func (sess *Session) Replay(journal *amp.JournalReader) (map[tag.ID]*Request, error) {
+	replayed := make(map[tag.ID]*Request)
+	for {
+		rec, err := journal.Next()
+		if err == io.EOF {
+			return replayed, nil
+		}
+		if err != nil {
+			return replayed, err
+		}
+		if rec.Kind != amp.JournalRecv {
+			rec.Tx.ReleaseRef()
+			continue
+		}
+		pinReq, commitTx, ok := amp.ParsePinRequest(rec.Tx)
+		reqID := rec.Tx.GenesisID()
+		rec.Tx.ReleaseRef()
+		if !ok {
+			continue
+		}
+
+		req, err := sess.issue(*pinReq, commitTx, reqID)
+		if err != nil {
+			req.OnComplete(err)
+		} else {
+			req.settle(sess.Timeout)
+		}
+		replayed[reqID] = req
+	}
+}
+
+func (sess *Session) issue(pinReq amp.PinRequest, commitTx *amp.TxMsg, reqID tag.ID) (*Request, error) {
+	req := &Request{
+		sess:     sess,
+		done:     make(chan struct{}),
+		progress: make(chan struct{}, 1),
+		req: &amp.Request{
+			PinRequest: pinReq,
+			ID:         reqID,
+			CommitTx:   commitTx,
+		},
+	}
+
+	var invocation string
+	if pinReq.PinTarget != nil && pinReq.PinTarget.URL != "" {
+		var err error
+		if req.req.URL, err = url.Parse(pinReq.PinTarget.URL); err != nil {
+			return req, amp.ErrCode_InvalidURI.Errorf("bad pin URL: %v", err)
+		}
+		req.req.Values = req.req.URL.Query()
+		invocation = req.req.URL.Scheme
+		if invocation == "amp" || invocation == "" {
+			invocation = req.req.URL.Host
+		}
+	}
+
+	app, err := sess.GetAppForInvocation(invocation)
+	if err != nil {
+		return req, err
+	}
+	inst, err := sess.GetAppInstance(app.AppSpec.ID, true)
+	if err != nil {
+		return req, err
+	}
+	if err = inst.MakeReady(req); err != nil {
+		return req, err
+	}
+	if req.pin, err = inst.ServeRequest(req); err != nil {
+		return req, err
+	}
+	return req, nil
+}
+
+// appContext implements amp.AppContext for an app instance running on a Session.
+type appContext struct {
+	task.Context
+	sess *Session
+	app  *amp.App
+}
+
+func (ctx *appContext) PublishAsset(asset media.Asset, opts media.PublishOpts) (string, error) {
+	return ctx.sess.PublishAsset(asset, opts)
+}
+
+func (ctx *appContext) Session() amp.HostSession {
+	return ctx.sess
+}
+
+func (ctx *appContext) Bus() amp.MessageBus {
+	return ctx.sess.bus
+}
+
+func (ctx *appContext) Store() amp.KVStore {
+	if space := ctx.sess.Space; space != nil {
+		kv, err := space.AppKVStore(ctx.app)
+		if err == nil {
+			return kv
+		}
+		ctx.sess.t.Errorf("amptest: %v", err)
+	}
+
+	ctx.sess.mu.Lock()
+	defer ctx.sess.mu.Unlock()
+
+	kv := ctx.sess.kvStores[ctx.app.AppSpec.ID]
+	if kv == nil {
+		kv = amp.NewKVStore(symbol.NewMemoryStore())
+		ctx.sess.kvStores[ctx.app.AppSpec.ID] = kv
+	}
+	return kv
+}
+
+func (ctx *appContext) LocalDataPath() string {
+	if space := ctx.sess.Space; space != nil {
+		path, err := space.AppDataPath(ctx.app)
+		if err != nil {
+			ctx.sess.t.Errorf("amptest: %v", err)
+		}
+		return path
+	}
+	path := filepath.Join(ctx.sess.dataDir, ctx.app.AppSpec.Canonic)
+	if err := os.MkdirAll(path, 0700); err != nil {
+		ctx.sess.t.Errorf("amptest: %v", err)
+	}
+	return path
+}
+
+func (ctx *appContext) GetAppAttr(attrSpec tag.ID, dst amp.ElemVal) error {
+	ctx.sess.mu.Lock()
+	buf, exists := ctx.sess.appAttrs[[2]tag.ID{ctx.app.AppSpec.ID, attrSpec}]
+	ctx.sess.mu.Unlock()
+
+	if !exists {
+		return amp.ErrCode_AttrNotFound.Errorf("app attr not found: %s", attrSpec)
+	}
+	return dst.Unmarshal(buf)
+}
+
+func (ctx *appContext) PutAppAttr(attrSpec tag.ID, src amp.ElemVal) error {
+	buf, err := src.MarshalToStore(nil)
+	if err != nil {
+		return err
+	}
+
+	ctx.sess.mu.Lock()
+	ctx.sess.appAttrs[[2]tag.ID{ctx.app.AppSpec.ID, attrSpec}] = buf
+	ctx.sess.mu.Unlock()
+	return nil
+}
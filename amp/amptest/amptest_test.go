@@ -0,0 +1,543 @@
+package amptest_test
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/amp-3d/amp-sdk-go/amp"
+	"github.com/amp-3d/amp-sdk-go/amp/amptest"
+	"github.com/amp-3d/amp-sdk-go/amp/basic"
+	"github.com/amp-3d/amp-sdk-go/stdlib/metrics"
+	"github.com/amp-3d/amp-sdk-go/stdlib/tag"
+)
+
+var launchesSpec = tag.FormSpec(amp.AttrSpec, "test.launches.TagTab")
+
+var testApp = &amp.App{
+	AppSpec:     tag.FormSpec(amp.AppSpec, "test.amptest"),
+	Invocations: []string{"testapp"},
+	NewAppInstance: func(ctx amp.AppContext) (amp.AppInstance, error) {
+		app := &appInst{}
+		app.AppContext = ctx
+		app.Instance = app
+		return app, nil
+	},
+}
+
+type appInst struct {
+	basic.App[*appInst]
+}
+
+func (app *appInst) ServeRequest(req amp.Requester) (amp.Pin, error) {
+	home := &cell{}
+	home.Tab.Label = req.Request().Values.Get("label")
+	return app.PinAndServe(home, req)
+}
+
+func (app *appInst) MakeReady(req amp.Requester) error {
+	var launches amp.TagTab
+	app.GetAppAttr(launchesSpec.ID, &launches)
+	launches.Tags = append(launches.Tags, &amp.Tag{URL: req.Request().PinTarget.URL})
+	return app.PutAppAttr(launchesSpec.ID, &launches)
+}
+
+// Cells of the test app must have a pinned tab with a short label.
+var cellSchema = &amp.Schema{
+	Archetype: tag.FormSpec(tag.Spec{}, "test.amptest.cell"),
+	Strict:    true,
+	Attrs: []amp.AttrSchema{
+		{
+			Attr:     amp.PinnedTabSpec,
+			Required: true,
+			TypeName: "TagTab",
+			Fields:   []amp.FieldRange{{Field: "Label", MinLen: 1, MaxLen: 16}},
+		},
+	},
+}
+
+func (app *appInst) CellSchema(cellID tag.ID) *amp.Schema {
+	return cellSchema
+}
+
+func (app *appInst) CurrentAttr(cellID, attrID, SI tag.ID) amp.ElemVal {
+	return nil
+}
+
+type cell struct {
+	basic.CellInfo[*appInst]
+}
+
+func (c *cell) PinInto(dst *basic.Pinned[*appInst]) error {
+	for _, label := range []string{"one", "two"} {
+		child := &cell{}
+		child.Tab.Label = label
+		dst.AddChild(child)
+	}
+	return nil
+}
+
+func TestSession(t *testing.T) {
+	sess := amptest.NewSession(t, testApp)
+
+	req := sess.PinURL("testapp://cells/home?label=Home")
+	req.RequireComplete()
+
+	cells := req.Cells()
+	if len(cells) != 3 {
+		t.Fatalf("expected 3 cells, got %d", len(cells))
+	}
+	var tab amp.TagTab
+	req.RequireAttr(cells[0], amp.PinnedTabSpec.ID, &tab)
+	if tab.Label != "Home" {
+		t.Fatalf("unexpected label %q", tab.Label)
+	}
+	children := map[string]bool{}
+	for _, cellID := range cells[1:] {
+		req.RequireAttr(cellID, amp.ChildTabSpec.ID, &tab)
+		children[tab.Label] = true
+	}
+	if !children["one"] || !children["two"] {
+		t.Fatalf("unexpected children %v", children)
+	}
+
+	// A maintained pin stays open until the request is closed
+	req = sess.Pin(amp.PinRequest{
+		PinTarget: &amp.Tag{URL: "amp://testapp/cells/home"},
+		PinSync:   amp.PinSync_Maintain,
+	})
+	req.WaitForStatus(amp.OpStatus_Synced)
+	req.Close()
+	if err := req.Wait(); err != nil {
+		t.Fatal(err)
+	}
+
+	// App attrs persist across requests for the session
+	inst, err := sess.GetAppInstance(testApp.AppSpec.ID, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var launches amp.TagTab
+	if err = inst.GetAppAttr(launchesSpec.ID, &launches); err != nil || len(launches.Tags) != 2 {
+		t.Fatalf("unexpected launches %v: %v", launches.Tags, err)
+	}
+
+	if _, err = sess.TryPin(amp.PinRequest{PinTarget: &amp.Tag{URL: "nope://x"}}); err == nil {
+		t.Fatal("expected app not found")
+	}
+}
+
+func TestSessionAccessControl(t *testing.T) {
+	sess := amptest.NewSession(t, testApp)
+	sess.User.Roles = []string{"guest"}
+	sess.Policy = &amp.RulePolicy{
+		Rules: []amp.AccessRule{
+			{Effect: amp.AccessAllow, Ops: amp.AccessOp_Pin, Roles: []string{"guest", "owner"}},
+			{Effect: amp.AccessDeny, Ops: amp.AccessOp_Pin, Roles: []string{"guest"}, AttrID: amp.ChildTabSpec.ID},
+			{Effect: amp.AccessAllow, Ops: amp.AccessOp_Commit, Roles: []string{"owner"}},
+		},
+	}
+
+	// Attrs the guest may not pin are withheld
+	req := sess.PinURL("testapp://cells/home?label=Home")
+	req.RequireComplete()
+	cells := req.Cells()
+	if len(cells) != 1 {
+		t.Fatalf("expected only the pinned cell, got %d cells", len(cells))
+	}
+	var tab amp.TagTab
+	req.RequireAttr(cells[0], amp.PinnedTabSpec.ID, &tab)
+
+	// Explicitly requesting a denied attr fails the pin
+	_, err := sess.TryPin(amp.PinRequest{
+		PinTarget: &amp.Tag{URL: "testapp://cells/home"},
+		PinAttrs:  []*amp.Tag{amp.FormPinnableTag(amp.ChildTabSpec)},
+	})
+	if ampErr, ok := err.(*amp.Err); !ok || ampErr.Code != amp.ErrCode_InsufficientPermissions {
+		t.Fatalf("expected insufficient permissions, got %v", err)
+	}
+
+	// Commits are denied unless the session is an owner
+	commit := amp.NewTxMsg(true)
+	commit.MarshalUpsert(cells[0], amp.PinnedTabSpec.ID, &amp.TagTab{Label: "renamed"})
+	commitReq := amp.PinRequest{PinTarget: &amp.Tag{URL: "testapp://cells/home"}}
+	if _, err = sess.TryCommit(commitReq, commit); err == nil {
+		t.Fatal("expected commit to be denied")
+	}
+	sess.User.Roles = []string{"owner"}
+	if _, err = sess.TryCommit(commitReq, commit); err != nil {
+		t.Fatal(err)
+	}
+
+	// Unmatched operations are denied by default
+	sess.User.Roles = nil
+	if _, err = sess.TryPin(amp.PinRequest{PinTarget: &amp.Tag{URL: "testapp://cells/home"}}); err == nil {
+		t.Fatal("expected pin to be denied")
+	}
+}
+
+func TestSessionSchema(t *testing.T) {
+	sess := amptest.NewSession(t, testApp)
+	cellID := tag.New()
+	commitReq := amp.PinRequest{PinTarget: &amp.Tag{URL: "testapp://cells/home"}}
+	commit := func(build func(tx *amp.TxMsg)) error {
+		tx := amp.NewTxMsg(true)
+		build(tx)
+		_, err := sess.TryCommit(commitReq, tx)
+		return err
+	}
+
+	if err := commit(func(tx *amp.TxMsg) {
+		tx.MarshalUpsert(cellID, amp.PinnedTabSpec.ID, &amp.TagTab{Label: "renamed"})
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Each violation is reported, and the commit is rejected before the app sees it
+	err := commit(func(tx *amp.TxMsg) {
+		tx.MarshalUpsert(cellID, amp.PinnedTabSpec.ID, &amp.TagTab{Label: "a label far longer than allowed"})
+		tx.MarshalUpsert(cellID, launchesSpec.ID, &amp.TagTab{})
+	})
+	ampErr, ok := err.(*amp.Err)
+	if !ok || ampErr.Code != amp.ErrCode_BadSchema {
+		t.Fatalf("expected schema violation, got %v", err)
+	}
+	if !strings.Contains(ampErr.Msg, "Label length 31 outside [1, 16]") || !strings.Contains(ampErr.Msg, "attr not declared") {
+		t.Fatalf("unexpected violations %q", ampErr.Msg)
+	}
+	if err = commit(func(tx *amp.TxMsg) {
+		tx.MarshalOpWithBuf(&amp.TxOp{OpCode: amp.TxOpCode_DeleteAttr, TargetID: cellID, AttrID: amp.PinnedTabSpec.ID}, nil)
+	}); err == nil || !strings.Contains(err.Error(), "required attr missing") {
+		t.Fatalf("expected required attr to be enforced, got %v", err)
+	}
+	if err = commit(func(tx *amp.TxMsg) {
+		tx.MarshalOpWithBuf(&amp.TxOp{OpCode: amp.TxOpCode_UpsertAttr, TargetID: cellID, AttrID: amp.PinnedTabSpec.ID}, []byte{0xff})
+	}); err == nil || !strings.Contains(err.Error(), "malformed value") {
+		t.Fatalf("expected malformed value to be rejected, got %v", err)
+	}
+}
+
+func TestSessionMigrations(t *testing.T) {
+	migrated := &amp.App{
+		AppSpec:        tag.FormSpec(amp.AppSpec, "test.migrated"),
+		Invocations:    []string{"migrated"},
+		NewAppInstance: testApp.NewAppInstance,
+		Migrations: []amp.Migration{
+			{Version: 1, Desc: "seed launches", Migrate: func(ctx amp.AppContext) error {
+				return ctx.PutAppAttr(launchesSpec.ID, &amp.TagTab{Label: "v1"})
+			}},
+			{Version: 2, Desc: "rename launches", Migrate: func(ctx amp.AppContext) error {
+				var launches amp.TagTab
+				if err := ctx.GetAppAttr(launchesSpec.ID, &launches); err != nil {
+					return err
+				}
+				launches.Label += "+v2"
+				return ctx.PutAppAttr(launchesSpec.ID, &launches)
+			}},
+		},
+	}
+	sess := amptest.NewSession(t, migrated)
+	start := func() (amp.AppInstance, error) {
+		return sess.GetAppInstance(migrated.AppSpec.ID, true)
+	}
+	stop := func(inst amp.AppInstance) {
+		inst.Close()
+		<-inst.Done()
+	}
+
+	// Pending migrations run in order when the app is first started
+	inst, err := start()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var launches amp.TagTab
+	var log amp.MigrationLog
+	if err = inst.GetAppAttr(launchesSpec.ID, &launches); err != nil || launches.Label != "v1+v2" {
+		t.Fatalf("unexpected launches %q (%v)", launches.Label, err)
+	}
+	if err = inst.GetAppAttr(amp.MigrationLogSpec.ID, &log); err != nil || log.SchemaVersion() != 2 || len(log.Applied) != 2 {
+		t.Fatalf("unexpected migration log %v (%v)", log.Applied, err)
+	}
+	stop(inst)
+
+	// Applied migrations are not run again, and a failed migration prevents the app from starting
+	migrated.Migrations = append(migrated.Migrations, amp.Migration{
+		Version: 3,
+		Migrate: func(ctx amp.AppContext) error {
+			return amp.ErrCode_BadValue.Error("unreadable state")
+		},
+	})
+	if _, err = start(); amp.GetErrCode(err) != amp.ErrCode_DataFailure {
+		t.Fatalf("expected migration failure, got %v", err)
+	}
+	migrated.Migrations[2].Migrate = func(ctx amp.AppContext) error { return nil }
+	if inst, err = start(); err != nil {
+		t.Fatal(err)
+	}
+	if err = inst.GetAppAttr(launchesSpec.ID, &launches); err != nil || launches.Label != "v1+v2" {
+		t.Fatalf("migration reapplied: %q (%v)", launches.Label, err)
+	}
+	if err = inst.GetAppAttr(amp.MigrationLogSpec.ID, &log); err != nil || log.SchemaVersion() != 3 {
+		t.Fatalf("unexpected migration log %v (%v)", log.Applied, err)
+	}
+
+	// Migrations must be listed in version order
+	unordered := &amp.App{
+		AppSpec:        tag.FormSpec(amp.AppSpec, "test.unordered"),
+		NewAppInstance: testApp.NewAppInstance,
+		Migrations: []amp.Migration{
+			{Version: 2, Migrate: migrated.Migrations[0].Migrate},
+			{Version: 1, Migrate: migrated.Migrations[0].Migrate},
+		},
+	}
+	if err = amp.NewRegistry().RegisterApp(unordered); amp.GetErrCode(err) != amp.ErrCode_BadValue {
+		t.Fatalf("expected out of order migrations to be rejected, got %v", err)
+	}
+}
+
+func TestSessionStore(t *testing.T) {
+	stores := make(map[string]amp.KVStore) // the store of each app, as seen by its instances
+	newAppInstance := func(name string) func(ctx amp.AppContext) (amp.AppInstance, error) {
+		return func(ctx amp.AppContext) (amp.AppInstance, error) {
+			stores[name] = amp.StoreOf(ctx)
+			return testApp.NewAppInstance(ctx)
+		}
+	}
+	stateful := &amp.App{
+		AppSpec:        tag.FormSpec(amp.AppSpec, "test.stateful"),
+		NewAppInstance: newAppInstance("stateful"),
+		Migrations: []amp.Migration{
+			{Version: 1, Desc: "seed state", Migrate: func(ctx amp.AppContext) error {
+				return amp.StoreOf(ctx).Set([]byte("cursor"), []byte("v1"), 0)
+			}},
+		},
+	}
+	other := &amp.App{
+		AppSpec:        tag.FormSpec(amp.AppSpec, "test.other"),
+		NewAppInstance: newAppInstance("other"),
+	}
+	sess := amptest.NewSession(t, stateful, other)
+
+	// An app's store is shared by its migrations and instances but not with other apps
+	if _, err := sess.GetAppInstance(stateful.AppSpec.ID, true); err != nil {
+		t.Fatal(err)
+	}
+	if val, err := stores["stateful"].Get([]byte("cursor")); err != nil || string(val) != "v1" {
+		t.Fatalf("unexpected cursor %q (%v)", val, err)
+	}
+	if _, err := sess.GetAppInstance(other.AppSpec.ID, true); err != nil {
+		t.Fatal(err)
+	}
+	if val, _ := stores["other"].Get([]byte("cursor")); val != nil {
+		t.Fatalf("app store leaked across apps: %q", val)
+	}
+}
+
+func TestSessionMetrics(t *testing.T) {
+	sess := amptest.NewSession(t, testApp)
+	reg := prometheus.NewRegistry()
+	sess.Metrics = amp.NewHostMetrics(reg, sess)
+	scrape := func() string {
+		var buf bytes.Buffer
+		metrics.WriteText(&buf, reg)
+		return buf.String()
+	}
+	expect := func(lines ...string) {
+		t.Helper()
+		exposition := scrape()
+		for _, line := range lines {
+			if !strings.Contains(exposition, line+"\n") {
+				t.Fatalf("missing %q in:\n%s", line, exposition)
+			}
+		}
+	}
+
+	req := sess.Pin(amp.PinRequest{
+		PinTarget: &amp.Tag{URL: "testapp://cells/home"},
+		PinSync:   amp.PinSync_Maintain,
+	})
+	req.WaitForStatus(amp.OpStatus_Synced)
+	expect(`amp_pins_open{app="amp.app.test.amptest"} 1`, `amp_pins_total{app="amp.app.test.amptest"} 1`, `amp_tasks{state="running"} 3`)
+
+	// Accepted and rejected commits are both measured
+	commitReq := amp.PinRequest{PinTarget: &amp.Tag{URL: "testapp://cells/home"}}
+	for _, label := range []string{"ok", ""} {
+		tx := amp.NewTxMsg(true)
+		tx.MarshalUpsert(tag.New(), amp.PinnedTabSpec.ID, &amp.TagTab{Label: label})
+		sess.TryCommit(commitReq, tx)
+	}
+	expect(`amp_commit_seconds_count{app="amp.app.test.amptest"} 1`, `amp_commit_errors_total{app="amp.app.test.amptest"} 1`)
+
+	req.Close()
+	req.Wait()
+	deadline := time.Now().Add(sess.Timeout)
+	for !strings.Contains(scrape(), `amp_pins_open{app="amp.app.test.amptest"} 0`) {
+		if time.Now().After(deadline) {
+			t.Fatalf("pins still open:\n%s", scrape())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestSessionTelemetry(t *testing.T) {
+	telemetry := amp.NewPinTelemetry(amp.PinTelemetryOpts{Retain: 1})
+	diagnostics := &amp.App{
+		AppSpec:     tag.FormSpec(amp.AppSpec, "test.diagnostics"),
+		Invocations: []string{"diag"},
+		NewAppInstance: func(ctx amp.AppContext) (amp.AppInstance, error) {
+			app := &diagInst{telemetry: telemetry}
+			app.AppContext = ctx
+			app.Instance = &app.appInst
+			return app, nil
+		},
+	}
+	sess := amptest.NewSession(t, testApp, diagnostics)
+	sess.Telemetry = telemetry
+
+	req := sess.Pin(amp.PinRequest{
+		PinTarget: &amp.Tag{URL: "testapp://cells/home?label=Home"},
+		PinSync:   amp.PinSync_Maintain,
+	})
+	req.WaitForStatus(amp.OpStatus_Synced)
+	pins := telemetry.Pins()
+	if len(pins) != 1 {
+		t.Fatalf("expected 1 pin, got %d", len(pins))
+	}
+	pin := pins[0]
+	if pin.App != "amp.app.test.amptest" || pin.Target != "testapp://cells/home?label=Home" || pin.ReqID != req.Request().ID.String() {
+		t.Fatalf("unexpected pin %+v", pin)
+	}
+	if pin.FirstTxMicros <= 0 || pin.TxsOut != 1 || pin.BytesOut <= 0 || pin.ClosedAt != 0 {
+		t.Fatalf("unexpected stats %+v", pin)
+	}
+
+	// Closed pins are retained up to PinTelemetryOpts.Retain
+	req.Close()
+	req.Wait()
+	sess.PinURL("testapp://cells/other").RequireComplete()
+	deadline := time.Now().Add(sess.Timeout)
+	for pins = telemetry.Pins(); len(pins) != 1 || pins[0].Target != "testapp://cells/other" || pins[0].ClosedAt == 0; pins = telemetry.Pins() {
+		if time.Now().After(deadline) {
+			t.Fatalf("unexpected pins %+v", pins)
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	w := httptest.NewRecorder()
+	telemetry.Handler().ServeHTTP(w, httptest.NewRequest("GET", "/?app=amp.app.test.amptest&sort=first_tx", nil))
+	if !strings.Contains(w.Body.String(), `"Target": "testapp://cells/other"`) {
+		t.Fatalf("unexpected response %s", w.Body.String())
+	}
+
+	// The diagnostic cell reports each pin (including its own) as a child cell
+	diag := sess.PinURL("diag://pins")
+	diag.RequireComplete()
+	var stats amp.PinStats
+	found := map[string]bool{}
+	for _, cellID := range diag.Cells() {
+		diag.RequireAttr(cellID, amp.PinStatsSpec.ID, &stats)
+		found[stats.Target] = true
+	}
+	if !found["testapp://cells/other"] || !found["diag://pins"] {
+		t.Fatalf("unexpected diagnostic cells %v", found)
+	}
+}
+
+type diagInst struct {
+	appInst
+	telemetry *amp.PinTelemetry
+}
+
+func (app *diagInst) ServeRequest(req amp.Requester) (amp.Pin, error) {
+	return app.telemetry.ServeDiagnostics(app, req, 0)
+}
+
+func TestSessionLimits(t *testing.T) {
+	sess := amptest.NewSession(t, testApp)
+	sess.Limiter = amp.NewSessionLimiter(amp.SessionLimits{
+		RequestsPerSec: 1,
+		RequestBurst:   3,
+		MaxPins:        2,
+	})
+	expectRateLimited := func(err error) {
+		t.Helper()
+		if ampErr, ok := err.(*amp.Err); !ok || ampErr.Code != amp.ErrCode_RateLimited {
+			t.Fatalf("expected rate limited, got %v", err)
+		}
+	}
+	maintain := amp.PinRequest{
+		PinTarget: &amp.Tag{URL: "testapp://cells/home"},
+		PinSync:   amp.PinSync_Maintain,
+	}
+
+	// Concurrent pins are capped
+	first := sess.Pin(maintain)
+	first.WaitForStatus(amp.OpStatus_Synced)
+	sess.Pin(maintain).WaitForStatus(amp.OpStatus_Synced)
+	_, err := sess.TryPin(maintain)
+	expectRateLimited(err)
+
+	// Closing a pin frees its slot, but the request burst is now spent
+	first.Close()
+	first.Wait()
+	_, err = sess.TryPin(maintain)
+	expectRateLimited(err)
+
+	stats := sess.Limiter.Stats()
+	if stats.Requests != 3 || stats.Rejected != 2 || stats.OpenPins != 1 || stats.BytesSent == 0 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+}
+
+func TestSessionReplay(t *testing.T) {
+	var buf bytes.Buffer
+	jw, err := amp.NewJournalWriter(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reqIDs := []tag.ID{tag.New(), tag.New()}
+	for i, url := range []string{"testapp://cells/home?label=Replayed", "nope://x"} {
+		pinReq := &amp.PinRequest{PinTarget: &amp.Tag{URL: url}}
+		val, err := pinReq.MarshalToStore(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tx := amp.NewTxMsg(true)
+		tx.SetGenesisID(reqIDs[i])
+		tx.MarshalOpWithBuf(&amp.TxOp{OpCode: amp.TxOpCode_MetaAttr, AttrID: amp.PinRequestSpec.ID}, val)
+		jw.Record(amp.JournalRecv, tx)
+		jw.Record(amp.JournalSend, amp.NewTxMsg(true))
+	}
+	if err = jw.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	journal, err := amp.NewJournalReader(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sess := amptest.NewSession(t, testApp)
+	replayed, err := sess.Replay(journal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(replayed) != 2 {
+		t.Fatalf("expected 2 replayed requests, got %d", len(replayed))
+	}
+
+	// Replayed requests keep their recorded IDs
+	req := replayed[reqIDs[0]]
+	req.RequireComplete()
+	var tab amp.TagTab
+	req.RequireAttr(req.Cells()[0], amp.PinnedTabSpec.ID, &tab)
+	if tab.Label != "Replayed" {
+		t.Fatalf("unexpected label %q", tab.Label)
+	}
+	if err = replayed[reqIDs[1]].Wait(); err == nil {
+		t.Fatal("expected the rejected request to complete with an error")
+	}
+}
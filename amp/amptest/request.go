@@ -0,0 +1,202 @@
+package amptest
+
+import (
+	"sync"
+	"time"
+
+	"github.com/amp-3d/amp-sdk-go/amp"
+	"github.com/amp-3d/amp-sdk-go/stdlib/tag"
+)
+
+// Request is an amp.Requester issued by a Session that captures the txs an app pushes in response.
+type Request struct {
+	sess     *Session
+	req      *amp.Request
+	pin      amp.Pin
+	done     chan struct{} // closed on OnComplete()
+	progress chan struct{} // signaled on each PushTx()
+
+	mu       sync.Mutex
+	txs      []*amp.TxMsg
+	complete bool
+	err      error
+}
+
+// Implements amp.Requester
+func (req *Request) Request() *amp.Request {
+	return req.req
+}
+
+// Implements amp.Requester
+func (req *Request) PushTx(tx *amp.TxMsg) error {
+	req.mu.Lock()
+	defer req.mu.Unlock()
+
+	if req.complete {
+		return amp.ErrRequestClosed
+	}
+	req.txs = append(req.txs, tx)
+	select {
+	case req.progress <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Implements amp.Requester
+func (req *Request) OnComplete(err error) {
+	req.mu.Lock()
+	defer req.mu.Unlock()
+
+	if req.complete {
+		return
+	}
+	req.complete = true
+	req.err = err
+	close(req.done)
+}
+
+// Pin returns the amp.Pin the app returned for this request.
+func (req *Request) Pin() amp.Pin {
+	return req.pin
+}
+
+// Close closes this request's Pin, as a host does when a client cancels a request.
+func (req *Request) Close() {
+	req.pin.Context().Close()
+}
+
+// Wait blocks until the app completes this request, returning the error it completed with.
+// The test fails if the app does not complete the request within Session.Timeout.
+func (req *Request) Wait() error {
+	req.sess.t.Helper()
+
+	select {
+	case <-req.done:
+	case <-time.After(req.sess.Timeout):
+		req.sess.t.Fatalf("amptest: timed out waiting for request %s to complete", req.req.ID)
+	}
+
+	req.mu.Lock()
+	defer req.mu.Unlock()
+	return req.err
+}
+
+// RequireComplete is like Wait but fails the test if the request completed with an error.
+func (req *Request) RequireComplete() {
+	req.sess.t.Helper()
+	if err := req.Wait(); err != nil {
+		req.sess.t.Fatalf("amptest: request %s failed: %v", req.req.ID, err)
+	}
+}
+
+// WaitForStatus blocks until the app pushes a tx with at least the given status (e.g. amp.OpStatus_Synced).
+// This is how a test waits on a request that the app maintains (see amp.PinSync_Maintain).
+func (req *Request) WaitForStatus(status amp.OpStatus) {
+	req.sess.t.Helper()
+
+	timeout := time.After(req.sess.Timeout)
+	for {
+		req.mu.Lock()
+		reached := false
+		for _, tx := range req.txs {
+			if tx.Status >= status {
+				reached = true
+				break
+			}
+		}
+		req.mu.Unlock()
+		if reached {
+			return
+		}
+
+		select {
+		case <-req.progress:
+		case <-req.done:
+			req.mu.Lock()
+			err := req.err
+			req.mu.Unlock()
+			req.sess.t.Fatalf("amptest: request %s completed (%v) before reaching %v", req.req.ID, err, status)
+		case <-timeout:
+			req.sess.t.Fatalf("amptest: timed out waiting for request %s to reach %v", req.req.ID, status)
+		}
+	}
+}
+
+// settle blocks until the request completes, the app pushes a tx with at least OpStatus_Synced, or the timeout elapses.
+func (req *Request) settle(timeout time.Duration) {
+	expired := time.After(timeout)
+	for {
+		req.mu.Lock()
+		settled := req.complete
+		for _, tx := range req.txs {
+			settled = settled || tx.Status >= amp.OpStatus_Synced
+		}
+		req.mu.Unlock()
+		if settled {
+			return
+		}
+
+		select {
+		case <-req.progress:
+		case <-req.done:
+		case <-expired:
+			return
+		}
+	}
+}
+
+// Txs returns the txs pushed for this request so far, in the order pushed.
+func (req *Request) Txs() []*amp.TxMsg {
+	req.mu.Lock()
+	defer req.mu.Unlock()
+	return append([]*amp.TxMsg{}, req.txs...)
+}
+
+// Cells returns the IDs of the cells targeted by the ops pushed so far, in the order first seen.
+func (req *Request) Cells() []tag.ID {
+	req.mu.Lock()
+	defer req.mu.Unlock()
+
+	var cells []tag.ID
+	seen := make(map[tag.ID]struct{})
+	for _, tx := range req.txs {
+		for _, op := range tx.Ops {
+			if _, exists := seen[op.TargetID]; !exists {
+				seen[op.TargetID] = struct{}{}
+				cells = append(cells, op.TargetID)
+			}
+		}
+	}
+	return cells
+}
+
+// Attr unmarshals the most recently pushed value of the given cell attr item into dst, returning false if no such value was pushed.
+func (req *Request) Attr(targetID, attrID, SI tag.ID, dst amp.ElemVal) (bool, error) {
+	req.mu.Lock()
+	defer req.mu.Unlock()
+
+	for i := len(req.txs) - 1; i >= 0; i-- {
+		tx := req.txs[i]
+		for j := len(tx.Ops) - 1; j >= 0; j-- {
+			op := tx.Ops[j]
+			if op.OpCode == amp.TxOpCode_UpsertAttr && op.TargetID == targetID && op.AttrID == attrID && op.SI == SI {
+				return true, tx.UnmarshalOpValue(j, dst)
+			}
+		}
+	}
+	return false, nil
+}
+
+// RequireAttr unmarshals the most recently pushed value of the given cell attr (with a nil SI) into dst, failing the test if there is none.
+func (req *Request) RequireAttr(targetID, attrID tag.ID, dst amp.ElemVal) {
+	req.sess.t.Helper()
+
+	found, err := req.Attr(targetID, attrID, tag.Nil, dst)
+	if err != nil {
+		req.sess.t.Fatalf("amptest: failed to unmarshal attr %s of cell %s: %v", attrID, targetID, err)
+	}
+	if !found {
+		req.sess.t.Fatalf("amptest: attr %s of cell %s was not pushed", attrID, targetID)
+	}
+}
@@ -0,0 +1,77 @@
+package amp
+
+import (
+	"github.com/amp-3d/amp-sdk-go/stdlib/tag"
+)
+
+// Identifier is optionally implemented by a HostSession that acts on behalf of an authenticated Identity (see IdentityOf).
+type Identifier interface {
+
+	// Returns the identity this session acts on behalf of, used to authorize access to cells and attrs (see GuardAppInstance).
+	Identity() *Identity
+}
+
+// Identity is who a HostSession acts on behalf of, used to evaluate access to cells and attrs (see PolicyProvider).
+type Identity struct {
+	UserUID string   `json:"user_uid"`        // from Login.UserUID
+	Roles   []string `json:"roles,omitempty"` // roles granted to this session (e.g. "owner", "guest")
+}
+
+// HasRole returns true if this Identity has been granted the given role.
+func (id *Identity) HasRole(role string) bool {
+	for _, ri := range id.Roles {
+		if ri == role {
+			return true
+		}
+	}
+	return false
+}
+
+// AccessOp is an operation a session performs on a cell or attr.
+type AccessOp int32
+
+const (
+	AccessOp_Pin    AccessOp = 1 << iota // read access: pinning a cell and receiving its attrs
+	AccessOp_Commit                      // write access: committing a TxMsg that mutates a cell's attrs
+
+	AccessOp_All = AccessOp_Pin | AccessOp_Commit
+)
+
+// AccessRequest describes an operation to be authorized.  CellID and AttrID are nil when the operation is not specific to one.
+type AccessRequest struct {
+	Identity *Identity
+	Op       AccessOp
+	AppID    tag.ID // AppSpec.ID of the app serving the cell
+	CellID   tag.ID
+	AttrID   tag.ID
+}
+
+// PolicyProvider decides whether sessions may perform operations on cells and attrs, allowing a host to plug in any policy source
+// (e.g. RulePolicy, a policy file, or an external authorization service).
+//
+// A host checks each pin request (at cell granularity and for each requested attr), each committed TxOp, and each attr pushed to
+// a session (see GuardAppInstance), so implementations should be fast and must be safe for concurrent use.
+type PolicyProvider interface {
+
+	// Returns nil if the given operation is allowed, otherwise an error (typically with ErrCode_InsufficientPermissions).
+	Authorize(req *AccessRequest) error
+}
+
+// AccessEffect is the outcome of an AccessRule that matches an AccessRequest.
+type AccessEffect int32
+
+const (
+	AccessAllow AccessEffect = iota
+	AccessDeny
+)
+
+// AccessRule allows or denies the operations it matches.  Empty fields match anything.
+type AccessRule struct {
+	Effect AccessEffect `json:"effect"`
+	Ops    AccessOp     `json:"ops,omitempty"`   // operations this rule applies to (0 denotes AccessOp_All)
+	Users  []string     `json:"users,omitempty"` // matches identities with any of these UserUIDs
+	Roles  []string     `json:"roles,omitempty"` // matches identities with any of these roles
+	AppID  tag.ID       `json:"app_id,omitempty"`
+	CellID tag.ID       `json:"cell_id,omitempty"`
+	AttrID tag.ID       `json:"attr_id,omitempty"` // a rule with an AttrID only matches requests for that attr
+}
@@ -56,7 +56,7 @@ const (
 	//          03:04 -- Const_TxHeader_Version
 	//          04:08 -- TxMsg body size: header + serialized TxOp(s)
 	//          08:12 -- TxMsg.DataStore size
-	//          12:16 -- Reserved
+	//          12:16 -- TxMsg.Seq (0 if unsequenced)
 	Const_TxHeader_Size Const = 16
 	// Version of the TxHeader -- first byte
 	Const_TxHeader_Version Const = 51
@@ -98,33 +98,39 @@ func (Const) EnumDescriptor() ([]byte, []int) {
 type TxOpCode int32
 
 const (
-	TxOpCode_Nil        TxOpCode = 0
-	TxOpCode_MetaAttr   TxOpCode = 1
-	TxOpCode_UpsertAttr TxOpCode = 2
-	TxOpCode_DeleteAttr TxOpCode = 4
-	TxOpCode_DeleteCell TxOpCode = 5
-	TxOpCode_UpsertLink TxOpCode = 7
-	TxOpCode_DeleteLink TxOpCode = 8
+	TxOpCode_Nil          TxOpCode = 0
+	TxOpCode_MetaAttr     TxOpCode = 1
+	TxOpCode_UpsertAttr   TxOpCode = 2
+	TxOpCode_DeleteAttr   TxOpCode = 4
+	TxOpCode_DeleteCell   TxOpCode = 5
+	TxOpCode_UpsertLink   TxOpCode = 7
+	TxOpCode_DeleteLink   TxOpCode = 8
+	TxOpCode_SnapshotAttr TxOpCode = 9
+	TxOpCode_PatchAttr    TxOpCode = 10
 )
 
 var TxOpCode_name = map[int32]string{
-	0: "TxOpCode_Nil",
-	1: "TxOpCode_MetaAttr",
-	2: "TxOpCode_UpsertAttr",
-	4: "TxOpCode_DeleteAttr",
-	5: "TxOpCode_DeleteCell",
-	7: "TxOpCode_UpsertLink",
-	8: "TxOpCode_DeleteLink",
+	0:  "TxOpCode_Nil",
+	1:  "TxOpCode_MetaAttr",
+	2:  "TxOpCode_UpsertAttr",
+	4:  "TxOpCode_DeleteAttr",
+	5:  "TxOpCode_DeleteCell",
+	7:  "TxOpCode_UpsertLink",
+	8:  "TxOpCode_DeleteLink",
+	9:  "TxOpCode_SnapshotAttr",
+	10: "TxOpCode_PatchAttr",
 }
 
 var TxOpCode_value = map[string]int32{
-	"TxOpCode_Nil":        0,
-	"TxOpCode_MetaAttr":   1,
-	"TxOpCode_UpsertAttr": 2,
-	"TxOpCode_DeleteAttr": 4,
-	"TxOpCode_DeleteCell": 5,
-	"TxOpCode_UpsertLink": 7,
-	"TxOpCode_DeleteLink": 8,
+	"TxOpCode_Nil":          0,
+	"TxOpCode_MetaAttr":     1,
+	"TxOpCode_UpsertAttr":   2,
+	"TxOpCode_DeleteAttr":   4,
+	"TxOpCode_DeleteCell":   5,
+	"TxOpCode_UpsertLink":   7,
+	"TxOpCode_DeleteLink":   8,
+	"TxOpCode_SnapshotAttr": 9,
+	"TxOpCode_PatchAttr":    10,
 }
 
 func (TxOpCode) EnumDescriptor() ([]byte, []int) {
@@ -511,6 +517,7 @@ const (
 	ErrCode_PlanetNotFound          ErrCode = 5032
 	ErrCode_PlanetFailure           ErrCode = 5033
 	ErrCode_AppNotFound             ErrCode = 5034
+	ErrCode_VersionConflict         ErrCode = 5035
 	ErrCode_MalformedTx             ErrCode = 5040
 	ErrCode_BadSchema               ErrCode = 5052
 	ErrCode_DataFailure             ErrCode = 5053
@@ -520,6 +527,7 @@ const (
 	ErrCode_ProviderErr             ErrCode = 5059
 	ErrCode_ViolatesAppendOnly      ErrCode = 5100
 	ErrCode_InsufficientPermissions ErrCode = 5101
+	ErrCode_RateLimited             ErrCode = 5102
 )
 
 var ErrCode_name = map[int32]string{
@@ -547,6 +555,7 @@ var ErrCode_name = map[int32]string{
 	5032: "ErrCode_PlanetNotFound",
 	5033: "ErrCode_PlanetFailure",
 	5034: "ErrCode_AppNotFound",
+	5035: "ErrCode_VersionConflict",
 	5040: "ErrCode_MalformedTx",
 	5052: "ErrCode_BadSchema",
 	5053: "ErrCode_DataFailure",
@@ -556,6 +565,7 @@ var ErrCode_name = map[int32]string{
 	5059: "ErrCode_ProviderErr",
 	5100: "ErrCode_ViolatesAppendOnly",
 	5101: "ErrCode_InsufficientPermissions",
+	5102: "ErrCode_RateLimited",
 }
 
 var ErrCode_value = map[string]int32{
@@ -583,6 +593,7 @@ var ErrCode_value = map[string]int32{
 	"ErrCode_PlanetNotFound":          5032,
 	"ErrCode_PlanetFailure":           5033,
 	"ErrCode_AppNotFound":             5034,
+	"ErrCode_VersionConflict":         5035,
 	"ErrCode_MalformedTx":             5040,
 	"ErrCode_BadSchema":               5052,
 	"ErrCode_DataFailure":             5053,
@@ -592,6 +603,7 @@ var ErrCode_value = map[string]int32{
 	"ErrCode_ProviderErr":             5059,
 	"ErrCode_ViolatesAppendOnly":      5100,
 	"ErrCode_InsufficientPermissions": 5101,
+	"ErrCode_RateLimited":             5102,
 }
 
 func (ErrCode) EnumDescriptor() ([]byte, []int) {
@@ -622,6 +634,80 @@ func (LogLevel) EnumDescriptor() ([]byte, []int) {
 	return fileDescriptor_f4505e0ac3ae98d9, []int{13}
 }
 
+// PlaybackState is what a client is doing with a playable item (see PlaybackEvent).
+type PlaybackState int32
+
+const (
+	PlaybackState_Unspecified PlaybackState = 0
+	PlaybackState_Started     PlaybackState = 1
+	PlaybackState_Paused      PlaybackState = 2
+	PlaybackState_Progress    PlaybackState = 3
+	PlaybackState_Stopped     PlaybackState = 4
+	PlaybackState_Finished    PlaybackState = 5
+)
+
+var PlaybackState_name = map[int32]string{
+	0: "PlaybackState_Unspecified",
+	1: "PlaybackState_Started",
+	2: "PlaybackState_Paused",
+	3: "PlaybackState_Progress",
+	4: "PlaybackState_Stopped",
+	5: "PlaybackState_Finished",
+}
+
+var PlaybackState_value = map[string]int32{
+	"PlaybackState_Unspecified": 0,
+	"PlaybackState_Started":     1,
+	"PlaybackState_Paused":      2,
+	"PlaybackState_Progress":    3,
+	"PlaybackState_Stopped":     4,
+	"PlaybackState_Finished":    5,
+}
+
+func (PlaybackState) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_f4505e0ac3ae98d9, []int{14}
+}
+
+// ColumnType is the type of the values of a TableColumn, and so which field of a TableValue holds them.
+type ColumnType int32
+
+const (
+	// TableValue.Text
+	ColumnType_Text ColumnType = 0
+	// TableValue.Int
+	ColumnType_Int ColumnType = 1
+	// TableValue.Float
+	ColumnType_Float ColumnType = 2
+	// TableValue.Bool
+	ColumnType_Bool ColumnType = 3
+	// TableValue.Int, in unix milliseconds
+	ColumnType_Time ColumnType = 4
+	// TableValue.Bytes
+	ColumnType_Bytes ColumnType = 5
+)
+
+var ColumnType_name = map[int32]string{
+	0: "ColumnType_Text",
+	1: "ColumnType_Int",
+	2: "ColumnType_Float",
+	3: "ColumnType_Bool",
+	4: "ColumnType_Time",
+	5: "ColumnType_Bytes",
+}
+
+var ColumnType_value = map[string]int32{
+	"ColumnType_Text":  0,
+	"ColumnType_Int":   1,
+	"ColumnType_Float": 2,
+	"ColumnType_Bool":  3,
+	"ColumnType_Time":  4,
+	"ColumnType_Bytes": 5,
+}
+
+func (ColumnType) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_f4505e0ac3ae98d9, []int{15}
+}
+
 type TRS_VisualScaleMode int32
 
 const (
@@ -640,7 +726,7 @@ var TRS_VisualScaleMode_value = map[string]int32{
 }
 
 func (TRS_VisualScaleMode) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_f4505e0ac3ae98d9, []int{19, 0}
+	return fileDescriptor_f4505e0ac3ae98d9, []int{45, 0}
 }
 
 // TxInfo contains information for a TxMsg
@@ -663,6 +749,8 @@ type TxInfo struct {
 	RootElementID_0 int64  `protobuf:"varint,13,opt,name=RootElementID_0,json=RootElementID0,proto3" json:"RootElementID_0,omitempty"`
 	RootElementID_1 uint64 `protobuf:"fixed64,14,opt,name=RootElementID_1,json=RootElementID1,proto3" json:"RootElementID_1,omitempty"`
 	RootElementID_2 uint64 `protobuf:"fixed64,15,opt,name=RootElementID_2,json=RootElementID2,proto3" json:"RootElementID_2,omitempty"`
+	// If Status is OpStatus_Closed, the code of the error the request failed with (see TxMsg.CloseErr)
+	ErrCode ErrCode `protobuf:"varint,3,opt,name=ErrCode,proto3,enum=amp.ErrCode" json:"ErrCode,omitempty"`
 }
 
 func (m *TxInfo) Reset()      { *m = TxInfo{} }
@@ -774,6 +862,13 @@ func (m *TxInfo) GetRootElementID_2() uint64 {
 	return 0
 }
 
+func (m *TxInfo) GetErrCode() ErrCode {
+	if m != nil {
+		return m.ErrCode
+	}
+	return ErrCode_NoErr
+}
+
 // Login -- STEP 1: client -> host
 type Login struct {
 	// A byte string identifying user who is logging in (lot limited to UTF8)
@@ -786,6 +881,14 @@ type Login struct {
 	DeviceUID   string `protobuf:"bytes,9,opt,name=DeviceUID,proto3" json:"DeviceUID,omitempty"`
 	// Current or previous checkpoint -- optional
 	Checkpoint *AuthCheckpoint `protobuf:"bytes,10,opt,name=Checkpoint,proto3" json:"Checkpoint,omitempty"`
+	// Names the space (tenant) on the host this session is scoped to -- optional (see amp.Spaces)
+	Space string `protobuf:"bytes,11,opt,name=Space,proto3" json:"Space,omitempty"`
+	// Media types the client can play, each a MIME type optionally qualified by the codecs it plays (RFC 6381), e.g. `video/mp4; codecs="avc1.42E01E, mp4a.40.2"`.
+	// If set, the host may transcode media assets into one of these types (see package media/transcode) -- optional
+	MediaTypes []string `protobuf:"bytes,12,rep,name=MediaTypes,proto3" json:"MediaTypes,omitempty"`
+	// Locales the client prefers, best first, each a BCP 47 language tag (e.g. "pt-BR").
+	// If set, text attrs having per-locale variants are emitted in the best matching locale (see TagTab.Locales) -- optional
+	Locales []string `protobuf:"bytes,13,rep,name=Locales,proto3" json:"Locales,omitempty"`
 }
 
 func (m *Login) Reset()      { *m = Login{} }
@@ -855,6 +958,27 @@ func (m *Login) GetCheckpoint() *AuthCheckpoint {
 	return nil
 }
 
+func (m *Login) GetSpace() string {
+	if m != nil {
+		return m.Space
+	}
+	return ""
+}
+
+func (m *Login) GetMediaTypes() []string {
+	if m != nil {
+		return m.MediaTypes
+	}
+	return nil
+}
+
+func (m *Login) GetLocales() []string {
+	if m != nil {
+		return m.Locales
+	}
+	return nil
+}
+
 // LoginChallenge -- STEP 2: host -> client
 type LoginChallenge struct {
 	Hash []byte `protobuf:"bytes,1,opt,name=Hash,proto3" json:"Hash,omitempty"`
@@ -1020,6 +1144,14 @@ type PinRequest struct {
 	PinAttrs []*Tag `protobuf:"bytes,4,rep,name=PinAttrs,proto3" json:"PinAttrs,omitempty"`
 	// Options for this request.
 	PinSync PinSync `protobuf:"varint,6,opt,name=PinSync,proto3,enum=amp.PinSync" json:"PinSync,omitempty"`
+	// If set, only this window of the pinned cell's children is pinned (for a cell that enumerates its children in pages).
+	PinWindow *PinWindow `protobuf:"bytes,8,opt,name=PinWindow,proto3" json:"PinWindow,omitempty"`
+	// If set, the pinned cell's children are filtered, sorted, and limited by this expression (see ParseFilter).
+	PinFilter string `protobuf:"bytes,10,opt,name=PinFilter,proto3" json:"PinFilter,omitempty"`
+	// If set, hints what the client expects to pin next, which an app may service at lower priority than this request.
+	Prefetch *PrefetchHint `protobuf:"bytes,12,opt,name=Prefetch,proto3" json:"Prefetch,omitempty"`
+	// If > 0, the max txs the host may push for this request before the client acknowledges them (see FlowAckAttrSpec) -- if 0, pushes are not flow controlled.
+	SendWindow int64 `protobuf:"varint,14,opt,name=SendWindow,proto3" json:"SendWindow,omitempty"`
 }
 
 func (m *PinRequest) Reset()      { *m = PinRequest{} }
@@ -1075,22 +1207,55 @@ func (m *PinRequest) GetPinSync() PinSync {
 	return PinSync_None
 }
 
-// LaunchURL is used as a meta attribute handle a URL, such as an oauth request (host to client) or an oauth response (client to host).
-type LaunchURL struct {
-	URL string `protobuf:"bytes,1,opt,name=URL,proto3" json:"URL,omitempty"`
+func (m *PinRequest) GetPinWindow() *PinWindow {
+	if m != nil {
+		return m.PinWindow
+	}
+	return nil
 }
 
-func (m *LaunchURL) Reset()      { *m = LaunchURL{} }
-func (*LaunchURL) ProtoMessage() {}
-func (*LaunchURL) Descriptor() ([]byte, []int) {
+func (m *PinRequest) GetPinFilter() string {
+	if m != nil {
+		return m.PinFilter
+	}
+	return ""
+}
+
+func (m *PinRequest) GetPrefetch() *PrefetchHint {
+	if m != nil {
+		return m.Prefetch
+	}
+	return nil
+}
+
+func (m *PinRequest) GetSendWindow() int64 {
+	if m != nil {
+		return m.SendWindow
+	}
+	return 0
+}
+
+// PinWindow specifies a window of a cell's children to be pinned -- either by position or by a cursor from a PageInfo.
+type PinWindow struct {
+	// Index of the first child in the window (ignored if Cursor is set)
+	Offset int64 `protobuf:"varint,1,opt,name=Offset,proto3" json:"Offset,omitempty"`
+	// Max number of children in the window -- if 0, the app's default is used
+	Limit int64 `protobuf:"varint,2,opt,name=Limit,proto3" json:"Limit,omitempty"`
+	// If set, the window starts where PageInfo.NextCursor or PageInfo.PrevCursor indicates.
+	Cursor string `protobuf:"bytes,3,opt,name=Cursor,proto3" json:"Cursor,omitempty"`
+}
+
+func (m *PinWindow) Reset()      { *m = PinWindow{} }
+func (*PinWindow) ProtoMessage() {}
+func (*PinWindow) Descriptor() ([]byte, []int) {
 	return fileDescriptor_f4505e0ac3ae98d9, []int{6}
 }
-func (m *LaunchURL) XXX_Unmarshal(b []byte) error {
+func (m *PinWindow) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *LaunchURL) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *PinWindow) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_LaunchURL.Marshal(b, m, deterministic)
+		return xxx_messageInfo_PinWindow.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -1100,45 +1265,59 @@ func (m *LaunchURL) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 		return b[:n], nil
 	}
 }
-func (m *LaunchURL) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_LaunchURL.Merge(m, src)
+func (m *PinWindow) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PinWindow.Merge(m, src)
 }
-func (m *LaunchURL) XXX_Size() int {
+func (m *PinWindow) XXX_Size() int {
 	return m.Size()
 }
-func (m *LaunchURL) XXX_DiscardUnknown() {
-	xxx_messageInfo_LaunchURL.DiscardUnknown(m)
+func (m *PinWindow) XXX_DiscardUnknown() {
+	xxx_messageInfo_PinWindow.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_LaunchURL proto.InternalMessageInfo
+var xxx_messageInfo_PinWindow proto.InternalMessageInfo
 
-func (m *LaunchURL) GetURL() string {
+func (m *PinWindow) GetOffset() int64 {
 	if m != nil {
-		return m.URL
+		return m.Offset
+	}
+	return 0
+}
+
+func (m *PinWindow) GetLimit() int64 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+func (m *PinWindow) GetCursor() string {
+	if m != nil {
+		return m.Cursor
 	}
 	return ""
 }
 
-// Position describes a position in space and/or time using a given coordinate system.
-type Position struct {
-	CordType CordType `protobuf:"varint,1,opt,name=CordType,proto3,enum=amp.CordType" json:"CordType,omitempty"`
-	U        float64  `protobuf:"fixed64,3,opt,name=U,proto3" json:"U,omitempty"`
-	V        float64  `protobuf:"fixed64,4,opt,name=V,proto3" json:"V,omitempty"`
-	W        float64  `protobuf:"fixed64,5,opt,name=W,proto3" json:"W,omitempty"`
-	ROU      float32  `protobuf:"fixed32,6,opt,name=ROU,proto3" json:"ROU,omitempty"`
+// PrefetchHint hints what a client expects to pin next (see PinRequest.Prefetch), e.g. the children it will soon scroll to.
+// Hints are advisory: an app may ignore them, and servicing them never delays or alters the pin they accompany.
+type PrefetchHint struct {
+	// Number of the first children pushed by the pin that the client expects to pin next (or 0 for none)
+	Children int64 `protobuf:"varint,1,opt,name=Children,proto3" json:"Children,omitempty"`
+	// Attrs the client expects to pin next of the hinted children, identified as in PinRequest.PinAttrs -- if nil, all attrs
+	Attrs []*Tag `protobuf:"bytes,2,rep,name=Attrs,proto3" json:"Attrs,omitempty"`
 }
 
-func (m *Position) Reset()      { *m = Position{} }
-func (*Position) ProtoMessage() {}
-func (*Position) Descriptor() ([]byte, []int) {
+func (m *PrefetchHint) Reset()      { *m = PrefetchHint{} }
+func (*PrefetchHint) ProtoMessage() {}
+func (*PrefetchHint) Descriptor() ([]byte, []int) {
 	return fileDescriptor_f4505e0ac3ae98d9, []int{7}
 }
-func (m *Position) XXX_Unmarshal(b []byte) error {
+func (m *PrefetchHint) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *Position) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *PrefetchHint) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_Position.Marshal(b, m, deterministic)
+		return xxx_messageInfo_PrefetchHint.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -1148,87 +1327,59 @@ func (m *Position) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 		return b[:n], nil
 	}
 }
-func (m *Position) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Position.Merge(m, src)
+func (m *PrefetchHint) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PrefetchHint.Merge(m, src)
 }
-func (m *Position) XXX_Size() int {
+func (m *PrefetchHint) XXX_Size() int {
 	return m.Size()
 }
-func (m *Position) XXX_DiscardUnknown() {
-	xxx_messageInfo_Position.DiscardUnknown(m)
-}
-
-var xxx_messageInfo_Position proto.InternalMessageInfo
-
-func (m *Position) GetCordType() CordType {
-	if m != nil {
-		return m.CordType
-	}
-	return CordType_Unspecified
-}
-
-func (m *Position) GetU() float64 {
-	if m != nil {
-		return m.U
-	}
-	return 0
+func (m *PrefetchHint) XXX_DiscardUnknown() {
+	xxx_messageInfo_PrefetchHint.DiscardUnknown(m)
 }
 
-func (m *Position) GetV() float64 {
-	if m != nil {
-		return m.V
-	}
-	return 0
-}
+var xxx_messageInfo_PrefetchHint proto.InternalMessageInfo
 
-func (m *Position) GetW() float64 {
+func (m *PrefetchHint) GetChildren() int64 {
 	if m != nil {
-		return m.W
+		return m.Children
 	}
 	return 0
 }
 
-func (m *Position) GetROU() float32 {
+func (m *PrefetchHint) GetAttrs() []*Tag {
 	if m != nil {
-		return m.ROU
+		return m.Attrs
 	}
-	return 0
-}
-
-// Tag is a multi-use workhorse. All fields are optional and whose meaning is contextual.
-//
-// Often used to reference an asset, a Link can reference any resource, a show, project, episode, or XR beacon.
-// The tagging naming convention describes a semi-ordered list of UTF tags.
-//      As tags first appear when going from left to right in the list, they are considered "more significant" or "higher priority" than tags that appear later.
-//      It is up to amp-search-dev-tag-specification to order search results based on tag filters (case sensitive, time ranges, or any UTF8 enum identifier)
-//      By convention, tags are case sensitive by default, however there are many filter presets -- This is how people "type or speak search"
-//      "Two tag rule" -- if you can think of two or more other tags in an order ranking, then do that instead.
-type Tag struct {
-	Use TagUse `protobuf:"varint,1,opt,name=Use,proto3,enum=amp.TagUse" json:"Use,omitempty"`
-	// Identifies a specific target tag ID this link points to.
-	TagID_0     int64   `protobuf:"varint,2,opt,name=TagID_0,json=TagID0,proto3" json:"TagID_0,omitempty"`
-	TagID_1     uint64  `protobuf:"fixed64,3,opt,name=TagID_1,json=TagID1,proto3" json:"TagID_1,omitempty"`
-	TagID_2     uint64  `protobuf:"fixed64,4,opt,name=TagID_2,json=TagID2,proto3" json:"TagID_2,omitempty"`
-	URL         string  `protobuf:"bytes,13,opt,name=URL,proto3" json:"URL,omitempty"`
-	ContentType string  `protobuf:"bytes,14,opt,name=ContentType,proto3" json:"ContentType,omitempty"`
-	Attachment  []byte  `protobuf:"bytes,15,opt,name=Attachment,proto3" json:"Attachment,omitempty"`
-	Metric      Metric  `protobuf:"varint,26,opt,name=Metric,proto3,enum=amp.Metric" json:"Metric,omitempty"`
-	Size_0      float32 `protobuf:"fixed32,27,opt,name=Size_0,json=Size0,proto3" json:"Size_0,omitempty"`
-	Size_1      float32 `protobuf:"fixed32,28,opt,name=Size_1,json=Size1,proto3" json:"Size_1,omitempty"`
-	Size_2      float32 `protobuf:"fixed32,29,opt,name=Size_2,json=Size2,proto3" json:"Size_2,omitempty"`
+	return nil
 }
 
-func (m *Tag) Reset()      { *m = Tag{} }
-func (*Tag) ProtoMessage() {}
-func (*Tag) Descriptor() ([]byte, []int) {
+// PageInfo is pushed as an attr of a pinned cell whose children are pinned in windows, describing the window pushed.
+type PageInfo struct {
+	// Total number of children (or -1 if not known)
+	Total int64 `protobuf:"varint,1,opt,name=Total,proto3" json:"Total,omitempty"`
+	// Index of the first child in the window
+	Offset int64 `protobuf:"varint,2,opt,name=Offset,proto3" json:"Offset,omitempty"`
+	// Number of children in the window
+	Count int64 `protobuf:"varint,3,opt,name=Count,proto3" json:"Count,omitempty"`
+	// Cursor for the window following this one (or "" if this is the last window)
+	NextCursor string `protobuf:"bytes,4,opt,name=NextCursor,proto3" json:"NextCursor,omitempty"`
+	// Cursor for the window preceding this one (or "" if this is the first window)
+	PrevCursor string `protobuf:"bytes,5,opt,name=PrevCursor,proto3" json:"PrevCursor,omitempty"`
+	// Incremented each time the children change, invalidating windows (and cursors) of a prior epoch.
+	Epoch uint64 `protobuf:"varint,6,opt,name=Epoch,proto3" json:"Epoch,omitempty"`
+}
+
+func (m *PageInfo) Reset()      { *m = PageInfo{} }
+func (*PageInfo) ProtoMessage() {}
+func (*PageInfo) Descriptor() ([]byte, []int) {
 	return fileDescriptor_f4505e0ac3ae98d9, []int{8}
 }
-func (m *Tag) XXX_Unmarshal(b []byte) error {
+func (m *PageInfo) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *Tag) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *PageInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_Tag.Marshal(b, m, deterministic)
+		return xxx_messageInfo_PageInfo.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -1238,116 +1389,152 @@ func (m *Tag) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 		return b[:n], nil
 	}
 }
-func (m *Tag) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Tag.Merge(m, src)
+func (m *PageInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PageInfo.Merge(m, src)
 }
-func (m *Tag) XXX_Size() int {
+func (m *PageInfo) XXX_Size() int {
 	return m.Size()
 }
-func (m *Tag) XXX_DiscardUnknown() {
-	xxx_messageInfo_Tag.DiscardUnknown(m)
+func (m *PageInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_PageInfo.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_Tag proto.InternalMessageInfo
+var xxx_messageInfo_PageInfo proto.InternalMessageInfo
 
-func (m *Tag) GetUse() TagUse {
+func (m *PageInfo) GetTotal() int64 {
 	if m != nil {
-		return m.Use
+		return m.Total
 	}
-	return TagUse_Unspecified
+	return 0
 }
 
-func (m *Tag) GetTagID_0() int64 {
+func (m *PageInfo) GetOffset() int64 {
 	if m != nil {
-		return m.TagID_0
+		return m.Offset
 	}
 	return 0
 }
 
-func (m *Tag) GetTagID_1() uint64 {
+func (m *PageInfo) GetCount() int64 {
 	if m != nil {
-		return m.TagID_1
+		return m.Count
 	}
 	return 0
 }
 
-func (m *Tag) GetTagID_2() uint64 {
+func (m *PageInfo) GetNextCursor() string {
 	if m != nil {
-		return m.TagID_2
+		return m.NextCursor
 	}
-	return 0
+	return ""
 }
 
-func (m *Tag) GetURL() string {
+func (m *PageInfo) GetPrevCursor() string {
 	if m != nil {
-		return m.URL
+		return m.PrevCursor
 	}
 	return ""
 }
 
-func (m *Tag) GetContentType() string {
+func (m *PageInfo) GetEpoch() uint64 {
 	if m != nil {
-		return m.ContentType
+		return m.Epoch
 	}
-	return ""
+	return 0
 }
 
-func (m *Tag) GetAttachment() []byte {
-	if m != nil {
-		return m.Attachment
+// Progress is pushed as an attr of a pinned cell populated by slow work (e.g. a library scan or a remote search) in each tx
+// having OpStatus_Syncing, along with the results so far, until the work is done and the cell is pushed with OpStatus_Synced.
+type Progress struct {
+	// Fraction of the work complete, in [0, 1]
+	Fraction float32 `protobuf:"fixed32,1,opt,name=Fraction,proto3" json:"Fraction,omitempty"`
+	// Label of the current stage of the work, e.g. "Scanning"
+	Stage string `protobuf:"bytes,2,opt,name=Stage,proto3" json:"Stage,omitempty"`
+	// Number of items of work done, if counted
+	Done int64 `protobuf:"varint,3,opt,name=Done,proto3" json:"Done,omitempty"`
+	// Number of items of work in all, or 0 if not known
+	Total int64 `protobuf:"varint,4,opt,name=Total,proto3" json:"Total,omitempty"`
+}
+
+func (m *Progress) Reset()      { *m = Progress{} }
+func (*Progress) ProtoMessage() {}
+func (*Progress) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f4505e0ac3ae98d9, []int{9}
+}
+func (m *Progress) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Progress) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Progress.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	return nil
+}
+func (m *Progress) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Progress.Merge(m, src)
+}
+func (m *Progress) XXX_Size() int {
+	return m.Size()
+}
+func (m *Progress) XXX_DiscardUnknown() {
+	xxx_messageInfo_Progress.DiscardUnknown(m)
 }
 
-func (m *Tag) GetMetric() Metric {
+var xxx_messageInfo_Progress proto.InternalMessageInfo
+
+func (m *Progress) GetFraction() float32 {
 	if m != nil {
-		return m.Metric
+		return m.Fraction
 	}
-	return Metric_Nil
+	return 0
 }
 
-func (m *Tag) GetSize_0() float32 {
+func (m *Progress) GetStage() string {
 	if m != nil {
-		return m.Size_0
+		return m.Stage
 	}
-	return 0
+	return ""
 }
 
-func (m *Tag) GetSize_1() float32 {
+func (m *Progress) GetDone() int64 {
 	if m != nil {
-		return m.Size_1
+		return m.Done
 	}
 	return 0
 }
 
-func (m *Tag) GetSize_2() float32 {
+func (m *Progress) GetTotal() int64 {
 	if m != nil {
-		return m.Size_2
+		return m.Total
 	}
 	return 0
 }
 
-// TagTab maps to a visual tab for a Tag -- complete with fields for user consumption.
-type TagTab struct {
-	Label      string `protobuf:"bytes,1,opt,name=Label,proto3" json:"Label,omitempty"`
-	Caption    string `protobuf:"bytes,2,opt,name=Caption,proto3" json:"Caption,omitempty"`
-	About      string `protobuf:"bytes,3,opt,name=About,proto3" json:"About,omitempty"`
-	CreatedAt  int64  `protobuf:"varint,6,opt,name=CreatedAt,proto3" json:"CreatedAt,omitempty"`
-	ModifiedAt int64  `protobuf:"varint,7,opt,name=ModifiedAt,proto3" json:"ModifiedAt,omitempty"`
-	Tags       []*Tag `protobuf:"bytes,9,rep,name=Tags,proto3" json:"Tags,omitempty"`
+// SearchHit is pushed as an attr of each cell a search request matches, ranking it among the request's hits.
+type SearchHit struct {
+	// Position of this hit among all hits (0 is the best match)
+	Rank int64 `protobuf:"varint,1,opt,name=Rank,proto3" json:"Rank,omitempty"`
+	// Relevance of this hit to the search query (higher is more relevant)
+	Score float32 `protobuf:"fixed32,2,opt,name=Score,proto3" json:"Score,omitempty"`
 }
 
-func (m *TagTab) Reset()      { *m = TagTab{} }
-func (*TagTab) ProtoMessage() {}
-func (*TagTab) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f4505e0ac3ae98d9, []int{9}
+func (m *SearchHit) Reset()      { *m = SearchHit{} }
+func (*SearchHit) ProtoMessage() {}
+func (*SearchHit) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f4505e0ac3ae98d9, []int{10}
 }
-func (m *TagTab) XXX_Unmarshal(b []byte) error {
+func (m *SearchHit) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *TagTab) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *SearchHit) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_TagTab.Marshal(b, m, deterministic)
+		return xxx_messageInfo_SearchHit.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -1357,159 +1544,53 @@ func (m *TagTab) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 		return b[:n], nil
 	}
 }
-func (m *TagTab) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_TagTab.Merge(m, src)
+func (m *SearchHit) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SearchHit.Merge(m, src)
 }
-func (m *TagTab) XXX_Size() int {
+func (m *SearchHit) XXX_Size() int {
 	return m.Size()
 }
-func (m *TagTab) XXX_DiscardUnknown() {
-	xxx_messageInfo_TagTab.DiscardUnknown(m)
+func (m *SearchHit) XXX_DiscardUnknown() {
+	xxx_messageInfo_SearchHit.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_TagTab proto.InternalMessageInfo
+var xxx_messageInfo_SearchHit proto.InternalMessageInfo
 
-func (m *TagTab) GetLabel() string {
+func (m *SearchHit) GetRank() int64 {
 	if m != nil {
-		return m.Label
+		return m.Rank
 	}
-	return ""
+	return 0
 }
 
-func (m *TagTab) GetCaption() string {
+func (m *SearchHit) GetScore() float32 {
 	if m != nil {
-		return m.Caption
+		return m.Score
 	}
-	return ""
+	return 0
 }
 
-func (m *TagTab) GetAbout() string {
-	if m != nil {
-		return m.About
-	}
-	return ""
-}
-
-func (m *TagTab) GetCreatedAt() int64 {
-	if m != nil {
-		return m.CreatedAt
-	}
-	return 0
-}
-
-func (m *TagTab) GetModifiedAt() int64 {
-	if m != nil {
-		return m.ModifiedAt
-	}
-	return 0
-}
-
-func (m *TagTab) GetTags() []*Tag {
-	if m != nil {
-		return m.Tags
-	}
-	return nil
-}
-
-//
-//
-//// ChannelSpec declares the presence of a particular set of attr series that, if pinned, have a particular format and intended usage.
-////
-//// In general, for incoming FeedInfo, a corresponding client pin op, UI tab, and UI sheet (though the sheet may often be hidden or obscured).
-////
-//// Most streams have 3-4 AttrFeeds:
-////      amp.tag.feed.[item.ID]TagTab
-////      amp.tag.feed.items.genesis.[]EpochDelta
-//message ChannelSpec {  // AttrLayer, AttrSheet, AttrBundle, AttrSet
-//
-//// Specifies an attr element and/or sequence format.
-////    e.g. "amp.app.attr.os.EpochDelta" -
-////
-//// This value implies an format and interpretation of the data elements in this series and their collective meaning,
-//// and typically invokes particular UI elements that specifically handle or visualize the elements of this series.
-////
-//// Any particular format spec can imply the existence of any number of attr series (e.g. an attr that binds particular attr series as columns).
-//string              FormatSpec = 2;
-//
-//// Specifies an attr usage and/or intention -- e.g. "index"
-////
-//// This can be considered a suffix to the SeriesSpec, and is used to refine UI presentation and behavior as appropriate.
-//string              ContextSpec = 3;
-//
-//// Specifies a specific instance of this format and context spec.
-////
-//// This means the spec that is actually pinned is "{FormatSpec}.{ContextSpec}.{InstanceID}"
-////Tag                 InstanceID = 4;
-//
-//// Optional sub collection of feeds also part of this feed - e.g. columns of a spreadsheet
-////repeated Feed       Elements = 9;
-//}
-//
-//
-//
-//// By convention, every cell carries a "amp.feed.genesis.EpochDelta" feed which in turn is a tag.Time series of []EpochDelta.
-////    series expressing state for the cell's permissions and the channel catalog.
-//message EpochDelta {
-//
-//// typically 1-4 ops that define default user and permission hive transactions, corresponding permissions tokens, and tag-wide public tokens
-//// by convention, the first follows the "constitution rule" where you list things like organization or group mission and offer a purpose.Tag that goes into ore detail about purpose and mission.
-////
-//// These essentially sequential edits to a permissions key-value store
-//// TagIDs (ops)
-////      "text/amp.tag.epoch.entries.{clear|write}.{admin|commons|group}"
-////      "text/amp.tag.epoch.entries.{clear|write}.{admin|commons|group}"
-//repeated Tag        PermissionsOps = 2;
-//
-//repeated string     ChannelSpecs = 4;
-//repeated FeedInfo   RemoveFeeds = 5;
-//
-//}
-//
-//
-//
-//
-//
-//
-//
-//
-//
-//
-//
-//
-//
-//
-//
-//
-//
-//
-//
-//
-//
-//
-//
-//
-//
-//
-//The U.S.C Social Experiment
-//- people "vote" on what they want
-//- we're making a voting os app -- invoke blockchains
-//
-//-- there were penned as  channels, but the actual pb declarations are update elements.
-//-- suffix ideas: Rev, Delta, Op, Tx, Item, Entry, Edit
-type Ballot struct {
+// LWWRegister is a last-writer-wins register CRDT: of concurrent writes, the one with the greatest (Time, Replica) wins.
+type LWWRegister struct {
+	// Serialized value of the winning write
+	Value []byte `protobuf:"bytes,1,opt,name=Value,proto3" json:"Value,omitempty"`
+	// Stamp of the winning write (see CRDTClock)
+	Time int64 `protobuf:"varint,2,opt,name=Time,proto3" json:"Time,omitempty"`
+	// ID of the replica that made the winning write
+	Replica uint64 `protobuf:"varint,3,opt,name=Replica,proto3" json:"Replica,omitempty"`
 }
 
-func (m *Ballot) Reset()      { *m = Ballot{} }
-func (*Ballot) ProtoMessage() {}
-func (*Ballot) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f4505e0ac3ae98d9, []int{10}
+func (m *LWWRegister) Reset()      { *m = LWWRegister{} }
+func (*LWWRegister) ProtoMessage() {}
+func (*LWWRegister) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f4505e0ac3ae98d9, []int{11}
 }
-func (m *Ballot) XXX_Unmarshal(b []byte) error {
+func (m *LWWRegister) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *Ballot) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *LWWRegister) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_Ballot.Marshal(b, m, deterministic)
+		return xxx_messageInfo_LWWRegister.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -1519,68 +1600,60 @@ func (m *Ballot) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 		return b[:n], nil
 	}
 }
-func (m *Ballot) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Ballot.Merge(m, src)
+func (m *LWWRegister) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LWWRegister.Merge(m, src)
 }
-func (m *Ballot) XXX_Size() int {
+func (m *LWWRegister) XXX_Size() int {
 	return m.Size()
 }
-func (m *Ballot) XXX_DiscardUnknown() {
-	xxx_messageInfo_Ballot.DiscardUnknown(m)
+func (m *LWWRegister) XXX_DiscardUnknown() {
+	xxx_messageInfo_LWWRegister.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_Ballot proto.InternalMessageInfo
+var xxx_messageInfo_LWWRegister proto.InternalMessageInfo
 
-type NotesEntry struct {
+func (m *LWWRegister) GetValue() []byte {
+	if m != nil {
+		return m.Value
+	}
+	return nil
 }
 
-func (m *NotesEntry) Reset()      { *m = NotesEntry{} }
-func (*NotesEntry) ProtoMessage() {}
-func (*NotesEntry) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f4505e0ac3ae98d9, []int{11}
-}
-func (m *NotesEntry) XXX_Unmarshal(b []byte) error {
-	return m.Unmarshal(b)
-}
-func (m *NotesEntry) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	if deterministic {
-		return xxx_messageInfo_NotesEntry.Marshal(b, m, deterministic)
-	} else {
-		b = b[:cap(b)]
-		n, err := m.MarshalToSizedBuffer(b)
-		if err != nil {
-			return nil, err
-		}
-		return b[:n], nil
+func (m *LWWRegister) GetTime() int64 {
+	if m != nil {
+		return m.Time
 	}
-}
-func (m *NotesEntry) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_NotesEntry.Merge(m, src)
-}
-func (m *NotesEntry) XXX_Size() int {
-	return m.Size()
-}
-func (m *NotesEntry) XXX_DiscardUnknown() {
-	xxx_messageInfo_NotesEntry.DiscardUnknown(m)
+	return 0
 }
 
-var xxx_messageInfo_NotesEntry proto.InternalMessageInfo
+func (m *LWWRegister) GetReplica() uint64 {
+	if m != nil {
+		return m.Replica
+	}
+	return 0
+}
 
-//  "amp.tag.spec.talk.spec.message"
-type ChatEntry struct {
+// ORSetEntry is an add to an ORSet, uniquely identified by its (Time, Replica) stamp.
+type ORSetEntry struct {
+	// Serialized element value (omitted in ORSet.Removes)
+	Value []byte `protobuf:"bytes,1,opt,name=Value,proto3" json:"Value,omitempty"`
+	// Stamp of the add (see CRDTClock)
+	Time int64 `protobuf:"varint,2,opt,name=Time,proto3" json:"Time,omitempty"`
+	// ID of the replica that made the add
+	Replica uint64 `protobuf:"varint,3,opt,name=Replica,proto3" json:"Replica,omitempty"`
 }
 
-func (m *ChatEntry) Reset()      { *m = ChatEntry{} }
-func (*ChatEntry) ProtoMessage() {}
-func (*ChatEntry) Descriptor() ([]byte, []int) {
+func (m *ORSetEntry) Reset()      { *m = ORSetEntry{} }
+func (*ORSetEntry) ProtoMessage() {}
+func (*ORSetEntry) Descriptor() ([]byte, []int) {
 	return fileDescriptor_f4505e0ac3ae98d9, []int{12}
 }
-func (m *ChatEntry) XXX_Unmarshal(b []byte) error {
+func (m *ORSetEntry) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *ChatEntry) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *ORSetEntry) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_ChatEntry.Marshal(b, m, deterministic)
+		return xxx_messageInfo_ORSetEntry.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -1590,32 +1663,58 @@ func (m *ChatEntry) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 		return b[:n], nil
 	}
 }
-func (m *ChatEntry) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_ChatEntry.Merge(m, src)
+func (m *ORSetEntry) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ORSetEntry.Merge(m, src)
 }
-func (m *ChatEntry) XXX_Size() int {
+func (m *ORSetEntry) XXX_Size() int {
 	return m.Size()
 }
-func (m *ChatEntry) XXX_DiscardUnknown() {
-	xxx_messageInfo_ChatEntry.DiscardUnknown(m)
+func (m *ORSetEntry) XXX_DiscardUnknown() {
+	xxx_messageInfo_ORSetEntry.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_ChatEntry proto.InternalMessageInfo
+var xxx_messageInfo_ORSetEntry proto.InternalMessageInfo
 
-type SpreadsheetEntry struct {
+func (m *ORSetEntry) GetValue() []byte {
+	if m != nil {
+		return m.Value
+	}
+	return nil
 }
 
-func (m *SpreadsheetEntry) Reset()      { *m = SpreadsheetEntry{} }
-func (*SpreadsheetEntry) ProtoMessage() {}
-func (*SpreadsheetEntry) Descriptor() ([]byte, []int) {
+func (m *ORSetEntry) GetTime() int64 {
+	if m != nil {
+		return m.Time
+	}
+	return 0
+}
+
+func (m *ORSetEntry) GetReplica() uint64 {
+	if m != nil {
+		return m.Replica
+	}
+	return 0
+}
+
+// ORSet is an observed-remove set CRDT: an element is present if any add of it has not been removed, so a concurrent add wins over a remove.
+type ORSet struct {
+	// Adds not yet removed
+	Adds []*ORSetEntry `protobuf:"bytes,1,rep,name=Adds,proto3" json:"Adds,omitempty"`
+	// Stamps of removed adds, retained so that merging a replica that has not seen a remove does not restore it
+	Removes []*ORSetEntry `protobuf:"bytes,2,rep,name=Removes,proto3" json:"Removes,omitempty"`
+}
+
+func (m *ORSet) Reset()      { *m = ORSet{} }
+func (*ORSet) ProtoMessage() {}
+func (*ORSet) Descriptor() ([]byte, []int) {
 	return fileDescriptor_f4505e0ac3ae98d9, []int{13}
 }
-func (m *SpreadsheetEntry) XXX_Unmarshal(b []byte) error {
+func (m *ORSet) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *SpreadsheetEntry) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *ORSet) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_SpreadsheetEntry.Marshal(b, m, deterministic)
+		return xxx_messageInfo_ORSet.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -1625,48 +1724,59 @@ func (m *SpreadsheetEntry) XXX_Marshal(b []byte, deterministic bool) ([]byte, er
 		return b[:n], nil
 	}
 }
-func (m *SpreadsheetEntry) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_SpreadsheetEntry.Merge(m, src)
+func (m *ORSet) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ORSet.Merge(m, src)
 }
-func (m *SpreadsheetEntry) XXX_Size() int {
+func (m *ORSet) XXX_Size() int {
 	return m.Size()
 }
-func (m *SpreadsheetEntry) XXX_DiscardUnknown() {
-	xxx_messageInfo_SpreadsheetEntry.DiscardUnknown(m)
+func (m *ORSet) XXX_DiscardUnknown() {
+	xxx_messageInfo_ORSet.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_SpreadsheetEntry proto.InternalMessageInfo
+var xxx_messageInfo_ORSet proto.InternalMessageInfo
 
-type SpatialPlacement struct {
-	// Expresses the position of this placement in space.
-	// The coordinate system is specified within (or implied) from the hosting attribute spec.
-	// Here's to the 3 domains that reflect completeness: alpha (finite), omega (unending), and the inaccessible cardinal(s) -- https://www.youtube.com/watch?v=SrU9YDoXE88
-	Pos0 float64 `protobuf:"fixed64,1,opt,name=Pos0,proto3" json:"Pos0,omitempty"`
-	Pos1 float64 `protobuf:"fixed64,2,opt,name=Pos1,proto3" json:"Pos1,omitempty"`
-	Pos2 float64 `protobuf:"fixed64,3,opt,name=Pos2,proto3" json:"Pos2,omitempty"`
-	// Expresses the scale of this Node.
-	// If all three values are 0, they are all implicitly 1.
-	// If Scale2 or Scale3 == 0, then they are each implicitly Scale1.
-	Scale0 float32 `protobuf:"fixed32,4,opt,name=Scale0,proto3" json:"Scale0,omitempty"`
-	Scale1 float32 `protobuf:"fixed32,5,opt,name=Scale1,proto3" json:"Scale1,omitempty"`
-	Scale2 float32 `protobuf:"fixed32,6,opt,name=Scale2,proto3" json:"Scale2,omitempty"`
-	// Expresses the orientation of this placement using Euler angles.
-	EulerX float32 `protobuf:"fixed32,7,opt,name=EulerX,proto3" json:"EulerX,omitempty"`
-	EulerY float32 `protobuf:"fixed32,8,opt,name=EulerY,proto3" json:"EulerY,omitempty"`
-	EulerZ float32 `protobuf:"fixed32,9,opt,name=EulerZ,proto3" json:"EulerZ,omitempty"`
+func (m *ORSet) GetAdds() []*ORSetEntry {
+	if m != nil {
+		return m.Adds
+	}
+	return nil
 }
 
-func (m *SpatialPlacement) Reset()      { *m = SpatialPlacement{} }
-func (*SpatialPlacement) ProtoMessage() {}
-func (*SpatialPlacement) Descriptor() ([]byte, []int) {
+func (m *ORSet) GetRemoves() []*ORSetEntry {
+	if m != nil {
+		return m.Removes
+	}
+	return nil
+}
+
+// RGAElem is an element of an RGAList, uniquely identified by its (Time, Replica) stamp.
+type RGAElem struct {
+	// Serialized element value
+	Value []byte `protobuf:"bytes,1,opt,name=Value,proto3" json:"Value,omitempty"`
+	// Stamp of the insert (see CRDTClock)
+	Time int64 `protobuf:"varint,2,opt,name=Time,proto3" json:"Time,omitempty"`
+	// ID of the replica that made the insert
+	Replica uint64 `protobuf:"varint,3,opt,name=Replica,proto3" json:"Replica,omitempty"`
+	// Stamp of the element this was inserted after (zero if inserted at the head)
+	AfterTime int64 `protobuf:"varint,4,opt,name=AfterTime,proto3" json:"AfterTime,omitempty"`
+	// Replica of the element this was inserted after
+	AfterReplica uint64 `protobuf:"varint,5,opt,name=AfterReplica,proto3" json:"AfterReplica,omitempty"`
+	// Set once removed -- a removed element is retained as a tombstone so that inserts after it stay in place
+	Removed bool `protobuf:"varint,6,opt,name=Removed,proto3" json:"Removed,omitempty"`
+}
+
+func (m *RGAElem) Reset()      { *m = RGAElem{} }
+func (*RGAElem) ProtoMessage() {}
+func (*RGAElem) Descriptor() ([]byte, []int) {
 	return fileDescriptor_f4505e0ac3ae98d9, []int{14}
 }
-func (m *SpatialPlacement) XXX_Unmarshal(b []byte) error {
+func (m *RGAElem) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *SpatialPlacement) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *RGAElem) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_SpatialPlacement.Marshal(b, m, deterministic)
+		return xxx_messageInfo_RGAElem.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -1676,95 +1786,77 @@ func (m *SpatialPlacement) XXX_Marshal(b []byte, deterministic bool) ([]byte, er
 		return b[:n], nil
 	}
 }
-func (m *SpatialPlacement) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_SpatialPlacement.Merge(m, src)
+func (m *RGAElem) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RGAElem.Merge(m, src)
 }
-func (m *SpatialPlacement) XXX_Size() int {
+func (m *RGAElem) XXX_Size() int {
 	return m.Size()
 }
-func (m *SpatialPlacement) XXX_DiscardUnknown() {
-	xxx_messageInfo_SpatialPlacement.DiscardUnknown(m)
+func (m *RGAElem) XXX_DiscardUnknown() {
+	xxx_messageInfo_RGAElem.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_SpatialPlacement proto.InternalMessageInfo
+var xxx_messageInfo_RGAElem proto.InternalMessageInfo
 
-func (m *SpatialPlacement) GetPos0() float64 {
+func (m *RGAElem) GetValue() []byte {
 	if m != nil {
-		return m.Pos0
+		return m.Value
 	}
-	return 0
+	return nil
 }
 
-func (m *SpatialPlacement) GetPos1() float64 {
+func (m *RGAElem) GetTime() int64 {
 	if m != nil {
-		return m.Pos1
+		return m.Time
 	}
 	return 0
 }
 
-func (m *SpatialPlacement) GetPos2() float64 {
+func (m *RGAElem) GetReplica() uint64 {
 	if m != nil {
-		return m.Pos2
+		return m.Replica
 	}
 	return 0
 }
 
-func (m *SpatialPlacement) GetScale0() float32 {
+func (m *RGAElem) GetAfterTime() int64 {
 	if m != nil {
-		return m.Scale0
+		return m.AfterTime
 	}
 	return 0
 }
 
-func (m *SpatialPlacement) GetScale1() float32 {
+func (m *RGAElem) GetAfterReplica() uint64 {
 	if m != nil {
-		return m.Scale1
+		return m.AfterReplica
 	}
 	return 0
 }
 
-func (m *SpatialPlacement) GetScale2() float32 {
+func (m *RGAElem) GetRemoved() bool {
 	if m != nil {
-		return m.Scale2
+		return m.Removed
 	}
-	return 0
-}
-
-func (m *SpatialPlacement) GetEulerX() float32 {
-	if m != nil {
-		return m.EulerX
-	}
-	return 0
-}
-
-func (m *SpatialPlacement) GetEulerY() float32 {
-	if m != nil {
-		return m.EulerY
-	}
-	return 0
-}
-
-func (m *SpatialPlacement) GetEulerZ() float32 {
-	if m != nil {
-		return m.EulerZ
-	}
-	return 0
+	return false
 }
 
-type TagPlayableMedia struct {
+// RGAList is a replicated growable array CRDT: an ordered list where concurrent inserts at the same position are ordered by stamp, so all replicas converge on the same order.
+type RGAList struct {
+	// All elements ever inserted, including tombstones (in no particular order)
+	Elems []*RGAElem `protobuf:"bytes,1,rep,name=Elems,proto3" json:"Elems,omitempty"`
 }
 
-func (m *TagPlayableMedia) Reset()      { *m = TagPlayableMedia{} }
-func (*TagPlayableMedia) ProtoMessage() {}
-func (*TagPlayableMedia) Descriptor() ([]byte, []int) {
+func (m *RGAList) Reset()      { *m = RGAList{} }
+func (*RGAList) ProtoMessage() {}
+func (*RGAList) Descriptor() ([]byte, []int) {
 	return fileDescriptor_f4505e0ac3ae98d9, []int{15}
 }
-func (m *TagPlayableMedia) XXX_Unmarshal(b []byte) error {
+func (m *RGAList) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *TagPlayableMedia) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *RGAList) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_TagPlayableMedia.Marshal(b, m, deterministic)
+		return xxx_messageInfo_RGAList.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -1774,32 +1866,46 @@ func (m *TagPlayableMedia) XXX_Marshal(b []byte, deterministic bool) ([]byte, er
 		return b[:n], nil
 	}
 }
-func (m *TagPlayableMedia) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_TagPlayableMedia.Merge(m, src)
+func (m *RGAList) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RGAList.Merge(m, src)
 }
-func (m *TagPlayableMedia) XXX_Size() int {
+func (m *RGAList) XXX_Size() int {
 	return m.Size()
 }
-func (m *TagPlayableMedia) XXX_DiscardUnknown() {
-	xxx_messageInfo_TagPlayableMedia.DiscardUnknown(m)
+func (m *RGAList) XXX_DiscardUnknown() {
+	xxx_messageInfo_RGAList.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_TagPlayableMedia proto.InternalMessageInfo
+var xxx_messageInfo_RGAList proto.InternalMessageInfo
 
-type TagPlaylist struct {
+func (m *RGAList) GetElems() []*RGAElem {
+	if m != nil {
+		return m.Elems
+	}
+	return nil
 }
 
-func (m *TagPlaylist) Reset()      { *m = TagPlaylist{} }
-func (*TagPlaylist) ProtoMessage() {}
-func (*TagPlaylist) Descriptor() ([]byte, []int) {
+// MigrationRecord records a migration applied to an app's persisted state (see Migration).
+type MigrationRecord struct {
+	// Schema version the migration upgraded to
+	Version uint64 `protobuf:"varint,1,opt,name=Version,proto3" json:"Version,omitempty"`
+	// When the migration was applied (unix milliseconds)
+	AppliedAt int64 `protobuf:"varint,2,opt,name=AppliedAt,proto3" json:"AppliedAt,omitempty"`
+	// Description of the migration
+	Desc string `protobuf:"bytes,3,opt,name=Desc,proto3" json:"Desc,omitempty"`
+}
+
+func (m *MigrationRecord) Reset()      { *m = MigrationRecord{} }
+func (*MigrationRecord) ProtoMessage() {}
+func (*MigrationRecord) Descriptor() ([]byte, []int) {
 	return fileDescriptor_f4505e0ac3ae98d9, []int{16}
 }
-func (m *TagPlaylist) XXX_Unmarshal(b []byte) error {
+func (m *MigrationRecord) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *TagPlaylist) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *MigrationRecord) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_TagPlaylist.Marshal(b, m, deterministic)
+		return xxx_messageInfo_MigrationRecord.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -1809,34 +1915,56 @@ func (m *TagPlaylist) XXX_Marshal(b []byte, deterministic bool) ([]byte, error)
 		return b[:n], nil
 	}
 }
-func (m *TagPlaylist) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_TagPlaylist.Merge(m, src)
+func (m *MigrationRecord) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MigrationRecord.Merge(m, src)
 }
-func (m *TagPlaylist) XXX_Size() int {
+func (m *MigrationRecord) XXX_Size() int {
 	return m.Size()
 }
-func (m *TagPlaylist) XXX_DiscardUnknown() {
-	xxx_messageInfo_TagPlaylist.DiscardUnknown(m)
+func (m *MigrationRecord) XXX_DiscardUnknown() {
+	xxx_messageInfo_MigrationRecord.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_TagPlaylist proto.InternalMessageInfo
+var xxx_messageInfo_MigrationRecord proto.InternalMessageInfo
 
-type CryptoKey struct {
-	CryptoKitID CryptoKitID `protobuf:"varint,1,opt,name=CryptoKitID,proto3,enum=amp.CryptoKitID" json:"CryptoKitID,omitempty"`
-	KeyBytes    []byte      `protobuf:"bytes,4,opt,name=KeyBytes,proto3" json:"KeyBytes,omitempty"`
+func (m *MigrationRecord) GetVersion() uint64 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
 }
 
-func (m *CryptoKey) Reset()      { *m = CryptoKey{} }
-func (*CryptoKey) ProtoMessage() {}
-func (*CryptoKey) Descriptor() ([]byte, []int) {
+func (m *MigrationRecord) GetAppliedAt() int64 {
+	if m != nil {
+		return m.AppliedAt
+	}
+	return 0
+}
+
+func (m *MigrationRecord) GetDesc() string {
+	if m != nil {
+		return m.Desc
+	}
+	return ""
+}
+
+// MigrationLog is persisted as an app attr, recording the migrations applied to the app's state (see RunMigrations).
+type MigrationLog struct {
+	// Migrations applied, in the order applied
+	Applied []*MigrationRecord `protobuf:"bytes,1,rep,name=Applied,proto3" json:"Applied,omitempty"`
+}
+
+func (m *MigrationLog) Reset()      { *m = MigrationLog{} }
+func (*MigrationLog) ProtoMessage() {}
+func (*MigrationLog) Descriptor() ([]byte, []int) {
 	return fileDescriptor_f4505e0ac3ae98d9, []int{17}
 }
-func (m *CryptoKey) XXX_Unmarshal(b []byte) error {
+func (m *MigrationLog) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *CryptoKey) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *MigrationLog) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_CryptoKey.Marshal(b, m, deterministic)
+		return xxx_messageInfo_MigrationLog.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -1846,51 +1974,60 @@ func (m *CryptoKey) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 		return b[:n], nil
 	}
 }
-func (m *CryptoKey) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_CryptoKey.Merge(m, src)
+func (m *MigrationLog) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MigrationLog.Merge(m, src)
 }
-func (m *CryptoKey) XXX_Size() int {
+func (m *MigrationLog) XXX_Size() int {
 	return m.Size()
 }
-func (m *CryptoKey) XXX_DiscardUnknown() {
-	xxx_messageInfo_CryptoKey.DiscardUnknown(m)
+func (m *MigrationLog) XXX_DiscardUnknown() {
+	xxx_messageInfo_MigrationLog.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_CryptoKey proto.InternalMessageInfo
-
-func (m *CryptoKey) GetCryptoKitID() CryptoKitID {
-	if m != nil {
-		return m.CryptoKitID
-	}
-	return CryptoKit_Nil
-}
+var xxx_messageInfo_MigrationLog proto.InternalMessageInfo
 
-func (m *CryptoKey) GetKeyBytes() []byte {
+func (m *MigrationLog) GetApplied() []*MigrationRecord {
 	if m != nil {
-		return m.KeyBytes
+		return m.Applied
 	}
 	return nil
 }
 
-// AuthToken is an oauth token -- see oauth2.Token
-type AuthToken struct {
-	AccessToken  string `protobuf:"bytes,1,opt,name=AccessToken,proto3" json:"AccessToken,omitempty"`
-	TokenType    string `protobuf:"bytes,2,opt,name=TokenType,proto3" json:"TokenType,omitempty"`
-	RefreshToken string `protobuf:"bytes,3,opt,name=RefreshToken,proto3" json:"RefreshToken,omitempty"`
-	Expiry       int64  `protobuf:"varint,4,opt,name=Expiry,proto3" json:"Expiry,omitempty"`
-}
-
-func (m *AuthToken) Reset()      { *m = AuthToken{} }
-func (*AuthToken) ProtoMessage() {}
-func (*AuthToken) Descriptor() ([]byte, []int) {
+// PinStats reports the latency and throughput of a pin (see PinTelemetry).
+type PinStats struct {
+	// ID of the request that opened the pin (see Request.ID)
+	ReqID string `protobuf:"bytes,1,opt,name=ReqID,proto3" json:"ReqID,omitempty"`
+	// Canonic spec of the app serving the pin
+	App string `protobuf:"bytes,2,opt,name=App,proto3" json:"App,omitempty"`
+	// URL or tag pinned
+	Target string `protobuf:"bytes,3,opt,name=Target,proto3" json:"Target,omitempty"`
+	// When the pin was requested (unix milliseconds)
+	OpenedAt int64 `protobuf:"varint,4,opt,name=OpenedAt,proto3" json:"OpenedAt,omitempty"`
+	// Time from the request to the first tx pushed (microseconds), or zero if none yet
+	FirstTxMicros int64 `protobuf:"varint,5,opt,name=FirstTxMicros,proto3" json:"FirstTxMicros,omitempty"`
+	// Txs pushed to the client
+	TxsOut int64 `protobuf:"varint,6,opt,name=TxsOut,proto3" json:"TxsOut,omitempty"`
+	// Approximate tx bytes pushed to the client
+	BytesOut int64 `protobuf:"varint,7,opt,name=BytesOut,proto3" json:"BytesOut,omitempty"`
+	// Txs committed via the pin
+	TxsIn int64 `protobuf:"varint,8,opt,name=TxsIn,proto3" json:"TxsIn,omitempty"`
+	// When the most recent tx was pushed (unix milliseconds), or zero if none yet
+	LastTxAt int64 `protobuf:"varint,9,opt,name=LastTxAt,proto3" json:"LastTxAt,omitempty"`
+	// When the pin closed (unix milliseconds), or zero if open
+	ClosedAt int64 `protobuf:"varint,10,opt,name=ClosedAt,proto3" json:"ClosedAt,omitempty"`
+}
+
+func (m *PinStats) Reset()      { *m = PinStats{} }
+func (*PinStats) ProtoMessage() {}
+func (*PinStats) Descriptor() ([]byte, []int) {
 	return fileDescriptor_f4505e0ac3ae98d9, []int{18}
 }
-func (m *AuthToken) XXX_Unmarshal(b []byte) error {
+func (m *PinStats) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *AuthToken) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *PinStats) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_AuthToken.Marshal(b, m, deterministic)
+		return xxx_messageInfo_PinStats.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -1900,189 +2037,238 @@ func (m *AuthToken) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 		return b[:n], nil
 	}
 }
-func (m *AuthToken) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_AuthToken.Merge(m, src)
+func (m *PinStats) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PinStats.Merge(m, src)
 }
-func (m *AuthToken) XXX_Size() int {
+func (m *PinStats) XXX_Size() int {
 	return m.Size()
 }
-func (m *AuthToken) XXX_DiscardUnknown() {
-	xxx_messageInfo_AuthToken.DiscardUnknown(m)
+func (m *PinStats) XXX_DiscardUnknown() {
+	xxx_messageInfo_PinStats.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_AuthToken proto.InternalMessageInfo
+var xxx_messageInfo_PinStats proto.InternalMessageInfo
 
-func (m *AuthToken) GetAccessToken() string {
+func (m *PinStats) GetReqID() string {
 	if m != nil {
-		return m.AccessToken
+		return m.ReqID
 	}
 	return ""
 }
 
-func (m *AuthToken) GetTokenType() string {
+func (m *PinStats) GetApp() string {
 	if m != nil {
-		return m.TokenType
+		return m.App
 	}
 	return ""
 }
 
-func (m *AuthToken) GetRefreshToken() string {
+func (m *PinStats) GetTarget() string {
 	if m != nil {
-		return m.RefreshToken
+		return m.Target
 	}
 	return ""
 }
 
-func (m *AuthToken) GetExpiry() int64 {
+func (m *PinStats) GetOpenedAt() int64 {
 	if m != nil {
-		return m.Expiry
+		return m.OpenedAt
 	}
 	return 0
 }
 
-type TRS struct {
-	// X1, X2, and X3 are coordinates or values expressed in any unit.
-	// A channel client can later declare how to interpret these coordinates so that a channel server and provide indexed services.
-	// Shoutout to the 3 domains that reflect all theoretical completeness: alpha (finite), omega (unending), and the inaccessible cardinal(s).
-	// Special thanks to Michael at Vsauce: https://www.youtube.com/watch?v=SrU9YDoXE88
-	X1 float64 `protobuf:"fixed64,41,opt,name=X1,proto3" json:"X1,omitempty"`
-	X2 float64 `protobuf:"fixed64,42,opt,name=X2,proto3" json:"X2,omitempty"`
-	X3 float64 `protobuf:"fixed64,43,opt,name=X3,proto3" json:"X3,omitempty"`
-	// Specifies how scale dynamically changes based on observer position.
-	ScaleMode TRS_VisualScaleMode `protobuf:"varint,50,opt,name=ScaleMode,proto3,enum=amp.TRS_VisualScaleMode" json:"ScaleMode,omitempty"`
-	// Scale1..3 express the scale of this placement.
-	// If all three values are 0, they are all implicitly 1.
-	// If Scale2 or Scale3 == 0, then it is implicitly Scale1.
-	Scale1 float32 `protobuf:"fixed32,51,opt,name=Scale1,proto3" json:"Scale1,omitempty"`
-	Scale2 float32 `protobuf:"fixed32,52,opt,name=Scale2,proto3" json:"Scale2,omitempty"`
-	Scale3 float32 `protobuf:"fixed32,53,opt,name=Scale3,proto3" json:"Scale3,omitempty"`
-	// Rotate1 - Rotate3 the orientation of this placement using Euler angles.
-	Rotate1 float32 `protobuf:"fixed32,61,opt,name=Rotate1,proto3" json:"Rotate1,omitempty"`
-	Rotate2 float32 `protobuf:"fixed32,62,opt,name=Rotate2,proto3" json:"Rotate2,omitempty"`
-	Rotate3 float32 `protobuf:"fixed32,63,opt,name=Rotate3,proto3" json:"Rotate3,omitempty"`
+func (m *PinStats) GetFirstTxMicros() int64 {
+	if m != nil {
+		return m.FirstTxMicros
+	}
+	return 0
 }
 
-func (m *TRS) Reset()      { *m = TRS{} }
-func (*TRS) ProtoMessage() {}
-func (*TRS) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f4505e0ac3ae98d9, []int{19}
-}
-func (m *TRS) XXX_Unmarshal(b []byte) error {
-	return m.Unmarshal(b)
-}
-func (m *TRS) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	if deterministic {
-		return xxx_messageInfo_TRS.Marshal(b, m, deterministic)
-	} else {
-		b = b[:cap(b)]
-		n, err := m.MarshalToSizedBuffer(b)
-		if err != nil {
-			return nil, err
-		}
-		return b[:n], nil
+func (m *PinStats) GetTxsOut() int64 {
+	if m != nil {
+		return m.TxsOut
 	}
-}
-func (m *TRS) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_TRS.Merge(m, src)
-}
-func (m *TRS) XXX_Size() int {
-	return m.Size()
-}
-func (m *TRS) XXX_DiscardUnknown() {
-	xxx_messageInfo_TRS.DiscardUnknown(m)
+	return 0
 }
 
-var xxx_messageInfo_TRS proto.InternalMessageInfo
-
-func (m *TRS) GetX1() float64 {
+func (m *PinStats) GetBytesOut() int64 {
 	if m != nil {
-		return m.X1
+		return m.BytesOut
 	}
 	return 0
 }
 
-func (m *TRS) GetX2() float64 {
+func (m *PinStats) GetTxsIn() int64 {
 	if m != nil {
-		return m.X2
+		return m.TxsIn
 	}
 	return 0
 }
 
-func (m *TRS) GetX3() float64 {
+func (m *PinStats) GetLastTxAt() int64 {
 	if m != nil {
-		return m.X3
+		return m.LastTxAt
 	}
 	return 0
 }
 
-func (m *TRS) GetScaleMode() TRS_VisualScaleMode {
+func (m *PinStats) GetClosedAt() int64 {
 	if m != nil {
-		return m.ScaleMode
+		return m.ClosedAt
 	}
-	return AutoScale
+	return 0
 }
 
-func (m *TRS) GetScale1() float32 {
+// BlobChunk is a frame of a blob too large for one tx, pushed as the value of a BlobChunkSpec attr (see PushBlob and BlobAssembler).
+type BlobChunk struct {
+	// Random ID shared by the frames of a blob
+	BlobID uint64 `protobuf:"varint,1,opt,name=BlobID,proto3" json:"BlobID,omitempty"`
+	// Offset of Data within the blob
+	Offset int64 `protobuf:"varint,2,opt,name=Offset,proto3" json:"Offset,omitempty"`
+	// Size of the whole blob, or zero if not known until its final frame
+	TotalSize int64 `protobuf:"varint,3,opt,name=TotalSize,proto3" json:"TotalSize,omitempty"`
+	// Set on the last frame of a blob
+	Final bool `protobuf:"varint,4,opt,name=Final,proto3" json:"Final,omitempty"`
+	// Set on a frame ending a blob the sender abandoned
+	Canceled bool `protobuf:"varint,5,opt,name=Canceled,proto3" json:"Canceled,omitempty"`
+	// Media type of the blob, set on its first frame
+	ContentType string `protobuf:"bytes,6,opt,name=ContentType,proto3" json:"ContentType,omitempty"`
+	// The blob's bytes from Offset
+	Data []byte `protobuf:"bytes,7,opt,name=Data,proto3" json:"Data,omitempty"`
+}
+
+func (m *BlobChunk) Reset()      { *m = BlobChunk{} }
+func (*BlobChunk) ProtoMessage() {}
+func (*BlobChunk) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f4505e0ac3ae98d9, []int{19}
+}
+func (m *BlobChunk) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *BlobChunk) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_BlobChunk.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *BlobChunk) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BlobChunk.Merge(m, src)
+}
+func (m *BlobChunk) XXX_Size() int {
+	return m.Size()
+}
+func (m *BlobChunk) XXX_DiscardUnknown() {
+	xxx_messageInfo_BlobChunk.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BlobChunk proto.InternalMessageInfo
+
+func (m *BlobChunk) GetBlobID() uint64 {
 	if m != nil {
-		return m.Scale1
+		return m.BlobID
 	}
 	return 0
 }
 
-func (m *TRS) GetScale2() float32 {
+func (m *BlobChunk) GetOffset() int64 {
 	if m != nil {
-		return m.Scale2
+		return m.Offset
 	}
 	return 0
 }
 
-func (m *TRS) GetScale3() float32 {
+func (m *BlobChunk) GetTotalSize() int64 {
 	if m != nil {
-		return m.Scale3
+		return m.TotalSize
 	}
 	return 0
 }
 
-func (m *TRS) GetRotate1() float32 {
+func (m *BlobChunk) GetFinal() bool {
 	if m != nil {
-		return m.Rotate1
+		return m.Final
 	}
-	return 0
+	return false
 }
 
-func (m *TRS) GetRotate2() float32 {
+func (m *BlobChunk) GetCanceled() bool {
 	if m != nil {
-		return m.Rotate2
+		return m.Canceled
 	}
-	return 0
+	return false
 }
 
-func (m *TRS) GetRotate3() float32 {
+func (m *BlobChunk) GetContentType() string {
 	if m != nil {
-		return m.Rotate3
+		return m.ContentType
 	}
-	return 0
+	return ""
 }
 
-type DataSegment struct {
-	ByteOfs    uint64 `protobuf:"varint,5,opt,name=ByteOfs,proto3" json:"ByteOfs,omitempty"`
-	ByteSz     uint64 `protobuf:"varint,6,opt,name=ByteSz,proto3" json:"ByteSz,omitempty"`
-	InlineData []byte `protobuf:"bytes,7,opt,name=InlineData,proto3" json:"InlineData,omitempty"`
-	StreamURI  string `protobuf:"bytes,9,opt,name=StreamURI,proto3" json:"StreamURI,omitempty"`
-	BlobID     int64  `protobuf:"varint,10,opt,name=BlobID,proto3" json:"BlobID,omitempty"`
+func (m *BlobChunk) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
 }
 
-func (m *DataSegment) Reset()      { *m = DataSegment{} }
-func (*DataSegment) ProtoMessage() {}
-func (*DataSegment) Descriptor() ([]byte, []int) {
+// MediaInfo is the metadata of a media file -- tags, stream properties, and EXIF -- as extracted by package media/metadata.
+type MediaInfo struct {
+	// e.g. "audio/mpeg", "image/jpeg"
+	ContentType string `protobuf:"bytes,1,opt,name=ContentType,proto3" json:"ContentType,omitempty"`
+	Title       string `protobuf:"bytes,2,opt,name=Title,proto3" json:"Title,omitempty"`
+	Artist      string `protobuf:"bytes,3,opt,name=Artist,proto3" json:"Artist,omitempty"`
+	Album       string `protobuf:"bytes,4,opt,name=Album,proto3" json:"Album,omitempty"`
+	AlbumArtist string `protobuf:"bytes,5,opt,name=AlbumArtist,proto3" json:"AlbumArtist,omitempty"`
+	Composer    string `protobuf:"bytes,6,opt,name=Composer,proto3" json:"Composer,omitempty"`
+	Genre       string `protobuf:"bytes,7,opt,name=Genre,proto3" json:"Genre,omitempty"`
+	Comment     string `protobuf:"bytes,8,opt,name=Comment,proto3" json:"Comment,omitempty"`
+	Year        int32  `protobuf:"varint,9,opt,name=Year,proto3" json:"Year,omitempty"`
+	TrackNum    int32  `protobuf:"varint,10,opt,name=TrackNum,proto3" json:"TrackNum,omitempty"`
+	TrackCount  int32  `protobuf:"varint,11,opt,name=TrackCount,proto3" json:"TrackCount,omitempty"`
+	DiscNum     int32  `protobuf:"varint,12,opt,name=DiscNum,proto3" json:"DiscNum,omitempty"`
+	DiscCount   int32  `protobuf:"varint,13,opt,name=DiscCount,proto3" json:"DiscCount,omitempty"`
+	// Duration of the audio / video
+	DurationMs int64 `protobuf:"varint,14,opt,name=DurationMs,proto3" json:"DurationMs,omitempty"`
+	SampleRate int32 `protobuf:"varint,15,opt,name=SampleRate,proto3" json:"SampleRate,omitempty"`
+	Channels   int32 `protobuf:"varint,16,opt,name=Channels,proto3" json:"Channels,omitempty"`
+	Width      int32 `protobuf:"varint,17,opt,name=Width,proto3" json:"Width,omitempty"`
+	Height     int32 `protobuf:"varint,18,opt,name=Height,proto3" json:"Height,omitempty"`
+	// EXIF orientation (1-8), where 1 is upright
+	Orientation int32  `protobuf:"varint,19,opt,name=Orientation,proto3" json:"Orientation,omitempty"`
+	CameraMake  string `protobuf:"bytes,20,opt,name=CameraMake,proto3" json:"CameraMake,omitempty"`
+	CameraModel string `protobuf:"bytes,21,opt,name=CameraModel,proto3" json:"CameraModel,omitempty"`
+	// When the photo was taken (Unix UTC seconds)
+	TakenAt int64 `protobuf:"varint,22,opt,name=TakenAt,proto3" json:"TakenAt,omitempty"`
+	// Set if Latitude and Longitude are present
+	HasLocation bool `protobuf:"varint,23,opt,name=HasLocation,proto3" json:"HasLocation,omitempty"`
+	// Degrees, positive north
+	Latitude float64 `protobuf:"fixed64,24,opt,name=Latitude,proto3" json:"Latitude,omitempty"`
+	// Degrees, positive east
+	Longitude float64 `protobuf:"fixed64,25,opt,name=Longitude,proto3" json:"Longitude,omitempty"`
+	// Samples of encoder priming at the start of the stream, trimmed for gapless playback
+	EncoderDelay int32 `protobuf:"varint,26,opt,name=EncoderDelay,proto3" json:"EncoderDelay,omitempty"`
+	// Samples of padding at the end of the stream, trimmed for gapless playback
+	EncoderPadding int32 `protobuf:"varint,27,opt,name=EncoderPadding,proto3" json:"EncoderPadding,omitempty"`
+}
+
+func (m *MediaInfo) Reset()      { *m = MediaInfo{} }
+func (*MediaInfo) ProtoMessage() {}
+func (*MediaInfo) Descriptor() ([]byte, []int) {
 	return fileDescriptor_f4505e0ac3ae98d9, []int{20}
 }
-func (m *DataSegment) XXX_Unmarshal(b []byte) error {
+func (m *MediaInfo) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *DataSegment) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *MediaInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_DataSegment.Marshal(b, m, deterministic)
+		return xxx_messageInfo_MediaInfo.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -2092,3303 +2278,13644 @@ func (m *DataSegment) XXX_Marshal(b []byte, deterministic bool) ([]byte, error)
 		return b[:n], nil
 	}
 }
-func (m *DataSegment) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_DataSegment.Merge(m, src)
+func (m *MediaInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MediaInfo.Merge(m, src)
 }
-func (m *DataSegment) XXX_Size() int {
+func (m *MediaInfo) XXX_Size() int {
 	return m.Size()
 }
-func (m *DataSegment) XXX_DiscardUnknown() {
-	xxx_messageInfo_DataSegment.DiscardUnknown(m)
+func (m *MediaInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_MediaInfo.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_DataSegment proto.InternalMessageInfo
+var xxx_messageInfo_MediaInfo proto.InternalMessageInfo
 
-func (m *DataSegment) GetByteOfs() uint64 {
+func (m *MediaInfo) GetContentType() string {
 	if m != nil {
-		return m.ByteOfs
+		return m.ContentType
 	}
-	return 0
+	return ""
 }
 
-func (m *DataSegment) GetByteSz() uint64 {
+func (m *MediaInfo) GetTitle() string {
 	if m != nil {
-		return m.ByteSz
+		return m.Title
 	}
-	return 0
+	return ""
 }
 
-func (m *DataSegment) GetInlineData() []byte {
+func (m *MediaInfo) GetArtist() string {
 	if m != nil {
-		return m.InlineData
+		return m.Artist
 	}
-	return nil
+	return ""
 }
 
-func (m *DataSegment) GetStreamURI() string {
+func (m *MediaInfo) GetAlbum() string {
 	if m != nil {
-		return m.StreamURI
+		return m.Album
 	}
 	return ""
 }
 
-func (m *DataSegment) GetBlobID() int64 {
+func (m *MediaInfo) GetAlbumArtist() string {
 	if m != nil {
-		return m.BlobID
+		return m.AlbumArtist
 	}
-	return 0
+	return ""
 }
 
-// Err is a general purpose error / warning / log message.
-type Err struct {
-	// Identifies the type of error.
-	Code ErrCode `protobuf:"varint,1,opt,name=Code,proto3,enum=amp.ErrCode" json:"Code,omitempty"`
-	// Severity level
-	Level LogLevel `protobuf:"varint,2,opt,name=Level,proto3,enum=amp.LogLevel" json:"Level,omitempty"`
-	// human-readable info
-	Msg string `protobuf:"bytes,4,opt,name=Msg,proto3" json:"Msg,omitempty"`
+func (m *MediaInfo) GetComposer() string {
+	if m != nil {
+		return m.Composer
+	}
+	return ""
 }
 
-func (m *Err) Reset()      { *m = Err{} }
-func (*Err) ProtoMessage() {}
-func (*Err) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f4505e0ac3ae98d9, []int{21}
-}
-func (m *Err) XXX_Unmarshal(b []byte) error {
-	return m.Unmarshal(b)
-}
-func (m *Err) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	if deterministic {
-		return xxx_messageInfo_Err.Marshal(b, m, deterministic)
-	} else {
-		b = b[:cap(b)]
-		n, err := m.MarshalToSizedBuffer(b)
-		if err != nil {
-			return nil, err
-		}
-		return b[:n], nil
+func (m *MediaInfo) GetGenre() string {
+	if m != nil {
+		return m.Genre
 	}
-}
-func (m *Err) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Err.Merge(m, src)
-}
-func (m *Err) XXX_Size() int {
-	return m.Size()
-}
-func (m *Err) XXX_DiscardUnknown() {
-	xxx_messageInfo_Err.DiscardUnknown(m)
+	return ""
 }
 
-var xxx_messageInfo_Err proto.InternalMessageInfo
+func (m *MediaInfo) GetComment() string {
+	if m != nil {
+		return m.Comment
+	}
+	return ""
+}
 
-func (m *Err) GetCode() ErrCode {
+func (m *MediaInfo) GetYear() int32 {
 	if m != nil {
-		return m.Code
+		return m.Year
 	}
-	return ErrCode_NoErr
+	return 0
 }
 
-func (m *Err) GetLevel() LogLevel {
+func (m *MediaInfo) GetTrackNum() int32 {
 	if m != nil {
-		return m.Level
+		return m.TrackNum
 	}
-	return LogLevel_Error
+	return 0
 }
 
-func (m *Err) GetMsg() string {
+func (m *MediaInfo) GetTrackCount() int32 {
 	if m != nil {
-		return m.Msg
+		return m.TrackCount
 	}
-	return ""
+	return 0
 }
 
-func init() {
-	proto.RegisterEnum("amp.Const", Const_name, Const_value)
-	proto.RegisterEnum("amp.TxOpCode", TxOpCode_name, TxOpCode_value)
-	proto.RegisterEnum("amp.TxField", TxField_name, TxField_value)
-	proto.RegisterEnum("amp.SelectOp", SelectOp_name, SelectOp_value)
-	proto.RegisterEnum("amp.OpStatus", OpStatus_name, OpStatus_value)
-	proto.RegisterEnum("amp.PinSync", PinSync_name, PinSync_value)
-	proto.RegisterEnum("amp.CordType", CordType_name, CordType_value)
-	proto.RegisterEnum("amp.Enable", Enable_name, Enable_value)
-	proto.RegisterEnum("amp.UrlScheme", UrlScheme_name, UrlScheme_value)
-	proto.RegisterEnum("amp.Metric", Metric_name, Metric_value)
-	proto.RegisterEnum("amp.TagUse", TagUse_name, TagUse_value)
-	proto.RegisterEnum("amp.CryptoKitID", CryptoKitID_name, CryptoKitID_value)
-	proto.RegisterEnum("amp.ErrCode", ErrCode_name, ErrCode_value)
-	proto.RegisterEnum("amp.LogLevel", LogLevel_name, LogLevel_value)
-	proto.RegisterEnum("amp.TRS_VisualScaleMode", TRS_VisualScaleMode_name, TRS_VisualScaleMode_value)
-	proto.RegisterType((*TxInfo)(nil), "amp.TxInfo")
-	proto.RegisterType((*Login)(nil), "amp.Login")
-	proto.RegisterType((*LoginChallenge)(nil), "amp.LoginChallenge")
-	proto.RegisterType((*LoginResponse)(nil), "amp.LoginResponse")
-	proto.RegisterType((*AuthCheckpoint)(nil), "amp.AuthCheckpoint")
-	proto.RegisterType((*PinRequest)(nil), "amp.PinRequest")
-	proto.RegisterType((*LaunchURL)(nil), "amp.LaunchURL")
-	proto.RegisterType((*Position)(nil), "amp.Position")
-	proto.RegisterType((*Tag)(nil), "amp.Tag")
-	proto.RegisterType((*TagTab)(nil), "amp.TagTab")
-	proto.RegisterType((*Ballot)(nil), "amp.Ballot")
-	proto.RegisterType((*NotesEntry)(nil), "amp.NotesEntry")
-	proto.RegisterType((*ChatEntry)(nil), "amp.ChatEntry")
-	proto.RegisterType((*SpreadsheetEntry)(nil), "amp.SpreadsheetEntry")
-	proto.RegisterType((*SpatialPlacement)(nil), "amp.SpatialPlacement")
-	proto.RegisterType((*TagPlayableMedia)(nil), "amp.TagPlayableMedia")
-	proto.RegisterType((*TagPlaylist)(nil), "amp.TagPlaylist")
-	proto.RegisterType((*CryptoKey)(nil), "amp.CryptoKey")
-	proto.RegisterType((*AuthToken)(nil), "amp.AuthToken")
-	proto.RegisterType((*TRS)(nil), "amp.TRS")
-	proto.RegisterType((*DataSegment)(nil), "amp.DataSegment")
-	proto.RegisterType((*Err)(nil), "amp.Err")
+func (m *MediaInfo) GetDiscNum() int32 {
+	if m != nil {
+		return m.DiscNum
+	}
+	return 0
 }
 
-func init() { proto.RegisterFile("amp/api.amp.proto", fileDescriptor_f4505e0ac3ae98d9) }
+func (m *MediaInfo) GetDiscCount() int32 {
+	if m != nil {
+		return m.DiscCount
+	}
+	return 0
+}
 
-var fileDescriptor_f4505e0ac3ae98d9 = []byte{
-	// 2640 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x98, 0x4f, 0x6c, 0x1b, 0xc7,
-	0xf5, 0xc7, 0xb5, 0xa4, 0x44, 0x89, 0xa3, 0x3f, 0x1e, 0x8f, 0xff, 0x6d, 0x1c, 0x9b, 0x11, 0x18,
-	0xff, 0x22, 0x85, 0x3f, 0xc4, 0x11, 0xa9, 0xa4, 0x68, 0x0f, 0x6d, 0x21, 0x53, 0xb2, 0xcd, 0x46,
-	0x94, 0x88, 0x25, 0x69, 0x27, 0x69, 0x11, 0x62, 0xc4, 0x7d, 0x24, 0x07, 0x5e, 0xce, 0x6c, 0x76,
-	0x87, 0x2e, 0xe5, 0x4b, 0x8b, 0x02, 0x45, 0xd3, 0xff, 0x69, 0x82, 0xf6, 0xd4, 0x7f, 0x97, 0xb6,
-	0xa9, 0x81, 0x02, 0x41, 0x81, 0xde, 0x9a, 0x16, 0x68, 0x2f, 0x41, 0x4f, 0x3e, 0x06, 0x39, 0x14,
-	0x8d, 0x73, 0xe9, 0xa1, 0x05, 0x72, 0xef, 0xa5, 0x98, 0xd9, 0xd9, 0xe5, 0xae, 0xa2, 0x9e, 0x34,
-	0xef, 0xf3, 0x7d, 0x33, 0x3b, 0xf3, 0xe6, 0xcd, 0x9b, 0x11, 0xd1, 0x59, 0x3a, 0xf6, 0x9f, 0xa7,
-	0x3e, 0xbb, 0x4e, 0xc7, 0xfe, 0x75, 0x3f, 0x10, 0x52, 0x90, 0x3c, 0x1d, 0xfb, 0xe5, 0x37, 0xf2,
-	0xa8, 0xd0, 0x99, 0x36, 0xf8, 0x40, 0x90, 0xff, 0x43, 0x85, 0xb6, 0xa4, 0x72, 0x12, 0xda, 0xb9,
-	0x75, 0x6b, 0x73, 0xad, 0xb6, 0xaa, 0x7d, 0x0f, 0xfd, 0x08, 0x3a, 0x46, 0x24, 0x17, 0x51, 0xe1,
-	0x60, 0x32, 0x3e, 0xf4, 0x43, 0x7b, 0x7e, 0xdd, 0xda, 0x9c, 0x77, 0x8c, 0x45, 0x9e, 0x42, 0xcb,
-	0xb7, 0x80, 0x43, 0xc8, 0xc2, 0xc6, 0x6e, 0x6f, 0xcb, 0x5e, 0x58, 0xb7, 0x36, 0xf3, 0x0e, 0x4a,
-	0xd0, 0x56, 0xd6, 0xa1, 0x6a, 0x17, 0xd6, 0xad, 0xcd, 0x42, 0xca, 0xa1, 0x9a, 0x75, 0xa8, 0xd9,
-	0x8b, 0x27, 0x1c, 0x6a, 0xca, 0xc1, 0x81, 0xd7, 0x27, 0x10, 0x4a, 0xfd, 0x09, 0x14, 0x7d, 0x22,
-	0x41, 0x5b, 0x59, 0x87, 0xaa, 0xbd, 0x1c, 0x8d, 0x90, 0xa0, 0x6a, 0xd6, 0xa1, 0x66, 0xaf, 0x9c,
-	0x70, 0xa8, 0x91, 0x0d, 0x74, 0xc6, 0x11, 0x42, 0xee, 0x79, 0x30, 0x06, 0x1e, 0x7d, 0x66, 0x55,
-	0x7f, 0x66, 0x2d, 0x83, 0xb7, 0x3e, 0xed, 0x58, 0xb5, 0xd7, 0xf4, 0x68, 0x59, 0xc7, 0xea, 0xa7,
-	0x1d, 0x6b, 0xf6, 0x99, 0x53, 0x1c, 0x6b, 0xe5, 0xdf, 0x5b, 0x68, 0x61, 0x5f, 0x0c, 0x19, 0x27,
-	0x36, 0x5a, 0xec, 0x86, 0x10, 0x74, 0x1b, 0xbb, 0xb6, 0xb5, 0x6e, 0x6d, 0x16, 0x9d, 0xd8, 0x24,
-	0x97, 0xd1, 0xd2, 0x6d, 0x11, 0xca, 0x1d, 0xd7, 0x0d, 0xf4, 0x2e, 0x15, 0x9d, 0xc4, 0x26, 0xeb,
-	0x68, 0x79, 0x17, 0xee, 0xb3, 0x3e, 0xec, 0xd3, 0x23, 0xf0, 0xec, 0x25, 0x2d, 0xa7, 0x11, 0xb9,
-	0x82, 0x8a, 0x91, 0xa9, 0x46, 0x2e, 0x6a, 0x7d, 0x06, 0xc8, 0x36, 0x42, 0xf5, 0x11, 0xf4, 0xef,
-	0xf9, 0x82, 0x71, 0xa9, 0x83, 0xbb, 0x5c, 0x3b, 0xa7, 0x73, 0x60, 0x67, 0x22, 0x47, 0x33, 0xc9,
-	0x49, 0xb9, 0x95, 0xaf, 0xa1, 0x35, 0x3d, 0xe7, 0xfa, 0x88, 0x7a, 0x1e, 0xf0, 0x21, 0x10, 0x82,
-	0xe6, 0x6f, 0xd3, 0x70, 0xa4, 0x67, 0xbe, 0xe2, 0xe8, 0x76, 0x79, 0x1b, 0xad, 0x6a, 0x2f, 0x07,
-	0x42, 0x5f, 0xf0, 0x10, 0x48, 0x19, 0xad, 0x28, 0x21, 0xb6, 0x8d, 0x73, 0x86, 0x95, 0xdf, 0xb2,
-	0xd0, 0x5a, 0xf6, 0xcb, 0xe4, 0x3c, 0x5a, 0xe8, 0x88, 0x7b, 0xc0, 0x4d, 0x58, 0x22, 0x83, 0x94,
-	0xd1, 0x62, 0x1b, 0xc2, 0x90, 0x09, 0x6e, 0x66, 0xbd, 0xa4, 0x67, 0xdd, 0xa1, 0x43, 0x27, 0x16,
-	0xc8, 0x3a, 0x2a, 0x34, 0x61, 0x7c, 0x04, 0x81, 0x4e, 0x8a, 0xb4, 0x8b, 0xe1, 0xe4, 0x9a, 0x0a,
-	0xed, 0x18, 0x6e, 0x02, 0xb8, 0x3a, 0x36, 0x69, 0x9f, 0x44, 0x29, 0x7f, 0xc3, 0x42, 0xa8, 0xa5,
-	0x16, 0xa2, 0x33, 0x86, 0x3c, 0x83, 0x8a, 0x2d, 0xc6, 0x3b, 0x34, 0x18, 0x82, 0xd4, 0x1b, 0x92,
-	0xee, 0x35, 0x93, 0xd4, 0xe0, 0x2d, 0xc6, 0x77, 0xa4, 0x0c, 0xd4, 0xb1, 0xc9, 0x67, 0x07, 0x8f,
-	0x15, 0xf2, 0x0c, 0x5a, 0x6c, 0x31, 0xde, 0x3e, 0xe6, 0x7d, 0x7d, 0x3a, 0xd6, 0x6a, 0x2b, 0xda,
-	0xc9, 0x30, 0x27, 0x16, 0xcb, 0x57, 0x51, 0x71, 0x9f, 0x4e, 0x78, 0x7f, 0xd4, 0x75, 0xf6, 0x09,
-	0x46, 0xf9, 0xae, 0xb3, 0x6f, 0x22, 0xa2, 0x9a, 0xe5, 0xd7, 0xd1, 0x52, 0x4b, 0x84, 0x4c, 0xaa,
-	0x75, 0x3f, 0x8b, 0x96, 0xea, 0x22, 0x70, 0x3b, 0xc7, 0x7e, 0x14, 0xe4, 0xf8, 0x58, 0xc7, 0xd0,
-	0x49, 0x64, 0xb2, 0x82, 0xac, 0xae, 0x9d, 0x5f, 0xb7, 0x36, 0x2d, 0xc7, 0xea, 0x2a, 0xeb, 0x8e,
-	0x3e, 0xe1, 0x96, 0x63, 0xdd, 0x51, 0xd6, 0x5d, 0x7d, 0xa4, 0x2d, 0xc7, 0xba, 0xab, 0x3e, 0xe9,
-	0x1c, 0x76, 0xf5, 0x1c, 0x73, 0x8e, 0x6a, 0x96, 0x7f, 0x97, 0x43, 0xf9, 0x0e, 0x1d, 0x92, 0xab,
-	0x28, 0xdf, 0x0d, 0xe3, 0x2f, 0x2d, 0xc7, 0x4b, 0xec, 0x86, 0xe0, 0x28, 0x4e, 0x2e, 0xa1, 0xc5,
-	0x0e, 0x1d, 0xea, 0x53, 0x95, 0xd3, 0xa7, 0xaa, 0xa0, 0xcd, 0xad, 0x99, 0x50, 0xd5, 0x33, 0x28,
-	0x18, 0xa1, 0x3a, 0x13, 0x6a, 0x7a, 0x32, 0xb1, 0x50, 0x8b, 0x97, 0xbd, 0x9a, 0x2c, 0x5b, 0xe5,
-	0x7f, 0x5d, 0x70, 0x09, 0x5c, 0xea, 0xd5, 0xae, 0x45, 0xf9, 0x9f, 0x42, 0xa4, 0x84, 0xd0, 0x8e,
-	0x94, 0xb4, 0x3f, 0x52, 0x47, 0x4e, 0x9f, 0xc2, 0x15, 0x27, 0x45, 0xc8, 0xd3, 0x2a, 0x49, 0x64,
-	0xc0, 0xfa, 0xf6, 0xe5, 0xd4, 0x02, 0x22, 0xe4, 0x18, 0x89, 0x5c, 0x40, 0x85, 0x36, 0x7b, 0x00,
-	0xbd, 0x2d, 0xfb, 0x49, 0xbd, 0xfe, 0x05, 0x65, 0x6d, 0x25, 0xb8, 0x6a, 0x5f, 0x99, 0xe1, 0x6a,
-	0x82, 0x6b, 0xf6, 0xd5, 0x19, 0xae, 0x95, 0x1f, 0x5a, 0x48, 0x2d, 0xa4, 0x43, 0x8f, 0x54, 0x4e,
-	0x47, 0x07, 0xd6, 0xe4, 0x74, 0x74, 0x54, 0x6d, 0xb4, 0x58, 0xa7, 0xbe, 0xda, 0x42, 0x73, 0xce,
-	0x63, 0x53, 0xf9, 0xef, 0x1c, 0x89, 0x89, 0xd4, 0x81, 0x2a, 0x3a, 0x91, 0xa1, 0x8e, 0x76, 0x3d,
-	0x00, 0x2a, 0xc1, 0xdd, 0x91, 0x7a, 0x63, 0xf2, 0xce, 0x0c, 0xa8, 0x85, 0x37, 0x85, 0xcb, 0x06,
-	0x4c, 0xcb, 0x8b, 0x51, 0xdd, 0x9c, 0x11, 0x72, 0x05, 0xcd, 0x77, 0xe8, 0x30, 0xb4, 0x8b, 0x27,
-	0x52, 0x53, 0xd3, 0xf2, 0x12, 0x2a, 0xdc, 0xa0, 0x9e, 0x27, 0x64, 0x79, 0x05, 0xa1, 0x03, 0x21,
-	0x21, 0xdc, 0xe3, 0x32, 0x38, 0x2e, 0x2f, 0xa3, 0x62, 0x7d, 0x44, 0x65, 0x64, 0x10, 0x84, 0xdb,
-	0x7e, 0x00, 0xd4, 0x0d, 0x47, 0x00, 0x86, 0xfd, 0xdd, 0x52, 0x90, 0x4a, 0x46, 0xbd, 0x96, 0x47,
-	0xfb, 0xba, 0xd2, 0xa9, 0xfa, 0xd0, 0x12, 0xe1, 0x96, 0x5e, 0xae, 0xe5, 0xe8, 0xb6, 0x61, 0x55,
-	0xbd, 0xd4, 0x88, 0x55, 0x0d, 0xab, 0x99, 0x8c, 0xd4, 0x6d, 0x75, 0xf7, 0xb4, 0xfb, 0xd4, 0x83,
-	0x2d, 0x9d, 0x0c, 0x39, 0xc7, 0x58, 0x09, 0xaf, 0xea, 0x1c, 0x8d, 0x79, 0x35, 0xe1, 0x35, 0x93,
-	0xab, 0xc6, 0x52, 0x7c, 0x6f, 0xe2, 0x41, 0xf0, 0xb2, 0x8e, 0x45, 0xce, 0x31, 0x56, 0xc2, 0x5f,
-	0xd1, 0xd5, 0x33, 0xe6, 0xaf, 0x24, 0xfc, 0x55, 0x5d, 0x19, 0x62, 0xfe, 0xaa, 0x5a, 0x74, 0x87,
-	0x0e, 0x5b, 0x1e, 0x3d, 0xa6, 0x47, 0x1e, 0x34, 0xc1, 0x65, 0xb4, 0xbc, 0x8a, 0x96, 0x0d, 0xf3,
-	0x58, 0x28, 0xcb, 0x5f, 0x56, 0x1b, 0x73, 0xec, 0x4b, 0xf1, 0x12, 0x1c, 0x93, 0x1a, 0x5a, 0x36,
-	0x06, 0x93, 0xa6, 0xb8, 0xaf, 0xd5, 0x70, 0x74, 0x20, 0x67, 0xdc, 0x49, 0x3b, 0xa9, 0x92, 0xff,
-	0x12, 0x1c, 0xdf, 0x38, 0x96, 0x10, 0xdd, 0xb8, 0x2b, 0x4e, 0x62, 0x97, 0xbf, 0x65, 0xa1, 0xa2,
-	0x2a, 0x91, 0x51, 0x1d, 0x5c, 0x47, 0xcb, 0x3b, 0xfd, 0x3e, 0x84, 0x61, 0xba, 0x46, 0xa6, 0x91,
-	0xca, 0x12, 0xdd, 0xd0, 0x07, 0x24, 0xca, 0xab, 0x19, 0x50, 0x45, 0xd9, 0x81, 0x41, 0x00, 0x61,
-	0x34, 0x9e, 0x49, 0xb0, 0x0c, 0xd3, 0x91, 0x98, 0xfa, 0x2c, 0x38, 0xd6, 0x73, 0xc9, 0x3b, 0xc6,
-	0x2a, 0xff, 0x41, 0x15, 0x00, 0xa7, 0x4d, 0xd6, 0x50, 0xee, 0xe5, 0xaa, 0xfd, 0xac, 0xde, 0xb3,
-	0xdc, 0xcb, 0x55, 0x6d, 0xd7, 0xec, 0x8a, 0xb1, 0x6b, 0xda, 0xde, 0xb6, 0xff, 0xdf, 0xd8, 0xdb,
-	0xe4, 0x33, 0xa8, 0xa8, 0xf7, 0xa4, 0x29, 0x5c, 0xb0, 0x6b, 0x3a, 0x1e, 0x76, 0x94, 0x7e, 0x4e,
-	0xfb, 0xfa, 0x1d, 0x16, 0x4e, 0xa8, 0x97, 0xe8, 0xce, 0xcc, 0x35, 0xb5, 0xe3, 0xdb, 0xff, 0x63,
-	0xc7, 0x5f, 0x38, 0xb9, 0xe3, 0xba, 0xb5, 0x6d, 0xbf, 0x98, 0xe2, 0xdb, 0xea, 0x9c, 0x39, 0x42,
-	0x52, 0x09, 0x55, 0xfb, 0xf3, 0x5a, 0x88, 0xcd, 0x99, 0x52, 0xb3, 0xbf, 0x90, 0x56, 0x6a, 0x33,
-	0x65, 0xdb, 0xfe, 0x62, 0x5a, 0xd9, 0x2e, 0x6f, 0xa1, 0x33, 0x27, 0xe6, 0x4c, 0x56, 0xf5, 0x0e,
-	0x09, 0x0d, 0xf0, 0x1c, 0x59, 0x43, 0xe8, 0x26, 0x9b, 0x82, 0x1b, 0xd9, 0x56, 0xf9, 0xc7, 0x16,
-	0x5a, 0xde, 0xa5, 0x92, 0xb6, 0x61, 0xa8, 0x4f, 0x87, 0x8d, 0x16, 0xd5, 0xd6, 0x1e, 0x0e, 0x42,
-	0x9d, 0xca, 0xf3, 0x4e, 0x6c, 0xaa, 0x15, 0xa8, 0x66, 0xfb, 0x81, 0xce, 0xe5, 0x79, 0xc7, 0x58,
-	0xea, 0x6c, 0x37, 0xb8, 0xc7, 0x38, 0xa8, 0x61, 0x74, 0x3e, 0xaf, 0x38, 0x29, 0xa2, 0xf6, 0xbc,
-	0x2d, 0x03, 0xa0, 0xe3, 0xae, 0xd3, 0x88, 0x2f, 0xfd, 0x04, 0xe8, 0x51, 0x3d, 0x71, 0xd4, 0xd8,
-	0x35, 0xaf, 0x29, 0x63, 0x95, 0x5f, 0x43, 0xf9, 0xbd, 0x40, 0xbd, 0x29, 0xe6, 0xeb, 0x6a, 0x67,
-	0xac, 0xd4, 0x75, 0xb4, 0x17, 0x04, 0x8a, 0x39, 0x5a, 0x21, 0x4f, 0xa3, 0x85, 0x7d, 0xb8, 0x0f,
-	0x5e, 0xe6, 0xd1, 0xb8, 0x2f, 0x86, 0x1a, 0x3a, 0x91, 0xa6, 0x8a, 0x75, 0x33, 0x1c, 0xea, 0x94,
-	0x29, 0x3a, 0xaa, 0x59, 0x79, 0x64, 0xa1, 0x85, 0xba, 0xe0, 0xa1, 0x54, 0x11, 0xd1, 0x8d, 0xde,
-	0x2e, 0x0c, 0x42, 0x3c, 0x47, 0x2e, 0x22, 0x12, 0xd9, 0x9d, 0xc6, 0xee, 0x0d, 0xc6, 0x69, 0x70,
-	0xbc, 0x0f, 0x1c, 0xaf, 0x67, 0x78, 0x5b, 0x06, 0x8c, 0x0f, 0x15, 0x7f, 0x81, 0x5c, 0x45, 0x76,
-	0xd2, 0x9f, 0x4e, 0x3c, 0xd9, 0x86, 0x40, 0xbd, 0x68, 0x5a, 0x22, 0x90, 0xf8, 0xfd, 0x4d, 0x72,
-	0x09, 0x9d, 0x33, 0xdd, 0xa6, 0xb7, 0x81, 0xba, 0x10, 0xf4, 0x54, 0x05, 0xc6, 0x98, 0x5c, 0x46,
-	0x17, 0x4f, 0x08, 0x77, 0x20, 0x50, 0x6f, 0x05, 0xbc, 0x4d, 0xae, 0xa0, 0x0b, 0x27, 0xb4, 0x26,
-	0x0d, 0xee, 0x41, 0x80, 0x3f, 0xf9, 0xf0, 0x9b, 0x79, 0x72, 0x01, 0xe1, 0x48, 0x6d, 0xf0, 0xfb,
-	0xa2, 0x4f, 0x55, 0x55, 0xc6, 0xef, 0x5d, 0xad, 0xbc, 0x6b, 0xa1, 0xa5, 0xce, 0xf4, 0xd0, 0xd7,
-	0x61, 0xc1, 0x68, 0x25, 0x6e, 0xf7, 0x0e, 0x98, 0x87, 0xe7, 0xc8, 0x05, 0x74, 0x36, 0x21, 0x4d,
-	0x90, 0x54, 0x5d, 0xf9, 0xd8, 0x52, 0xf3, 0x4b, 0x70, 0xd7, 0x0f, 0x21, 0x90, 0x5a, 0xc8, 0x65,
-	0x84, 0x5d, 0xf0, 0x40, 0x82, 0x16, 0xe6, 0x4f, 0x11, 0xea, 0xe0, 0x79, 0x78, 0xe1, 0x94, 0xa1,
-	0xf6, 0x19, 0xbf, 0x87, 0x17, 0x4f, 0xe9, 0xa1, 0x85, 0xa5, 0xca, 0x87, 0x39, 0xb4, 0xd8, 0x99,
-	0xde, 0x64, 0xe0, 0xb9, 0x2a, 0x51, 0x4d, 0xb3, 0xb7, 0x85, 0xe7, 0xc8, 0x79, 0x84, 0x63, 0xf3,
-	0x66, 0x20, 0xc6, 0xea, 0xce, 0xc6, 0xd6, 0x29, 0xb4, 0x8a, 0x73, 0xa7, 0xd0, 0x1a, 0xce, 0xab,
-	0x0d, 0x8b, 0x69, 0xf4, 0x0a, 0xd2, 0x63, 0xcc, 0x9f, 0xca, 0xab, 0x78, 0xe1, 0x54, 0x5e, 0xc3,
-	0x85, 0xf4, 0xe8, 0x2a, 0x02, 0x7a, 0x94, 0xc5, 0x53, 0x68, 0x15, 0x2f, 0x9d, 0x42, 0x6b, 0xb8,
-	0x18, 0x6d, 0x46, 0x44, 0xdb, 0x8d, 0xde, 0x16, 0x46, 0x27, 0x48, 0x15, 0x2f, 0x9f, 0x20, 0x35,
-	0xbc, 0x92, 0x26, 0xea, 0x5d, 0x8a, 0x57, 0xa3, 0x2d, 0x8c, 0xc8, 0xc1, 0x64, 0xac, 0x1b, 0x21,
-	0x5e, 0x4b, 0xe3, 0x26, 0x9d, 0x1a, 0x6c, 0x57, 0xf6, 0xd1, 0x52, 0x1b, 0x3c, 0xe8, 0xcb, 0x43,
-	0x5f, 0xcd, 0x2b, 0x6e, 0xf7, 0x0e, 0x60, 0x22, 0x03, 0xea, 0x45, 0x31, 0x4e, 0x68, 0x83, 0xf7,
-	0xbd, 0x89, 0x0b, 0x51, 0x8c, 0x13, 0xba, 0x37, 0x8d, 0x68, 0xae, 0xd2, 0x47, 0x4b, 0xf1, 0xbf,
-	0x62, 0x6a, 0x3f, 0xe3, 0x76, 0xef, 0x40, 0xc8, 0xb6, 0xa4, 0x81, 0x04, 0x37, 0x1a, 0x30, 0x11,
-	0xd4, 0x4b, 0x91, 0xf1, 0x21, 0xb6, 0xc8, 0x39, 0x74, 0x26, 0x43, 0xc1, 0xc5, 0xb9, 0x0c, 0xac,
-	0x7b, 0x22, 0x04, 0x17, 0xe7, 0x2b, 0x5f, 0x4a, 0x1e, 0xa0, 0x6a, 0xf5, 0xa6, 0xd9, 0x3b, 0x10,
-	0x5c, 0x95, 0xae, 0x4b, 0xe8, 0x5c, 0x4c, 0x74, 0x87, 0x43, 0xdd, 0x8e, 0x26, 0x1c, 0x0b, 0x4d,
-	0xca, 0xb8, 0xa4, 0x8c, 0xe3, 0x5c, 0xe5, 0xa1, 0x35, 0x7b, 0x7a, 0x12, 0x1b, 0x9d, 0x8f, 0xdb,
-	0xbd, 0x2e, 0x0f, 0x7d, 0xe8, 0xeb, 0xa7, 0x47, 0x34, 0xe5, 0x44, 0x39, 0x0c, 0x5c, 0x08, 0xc0,
-	0xc5, 0x16, 0xb9, 0xa2, 0x0e, 0xb5, 0xa1, 0x2d, 0x8f, 0x72, 0xe8, 0xd5, 0xd5, 0x1a, 0x43, 0x46,
-	0x39, 0x5e, 0x20, 0x4f, 0xa2, 0x4b, 0x27, 0xd4, 0xdb, 0x30, 0xdd, 0xbb, 0x0f, 0xdc, 0xc1, 0x05,
-	0xf2, 0x84, 0x3a, 0xbb, 0x46, 0xbc, 0x05, 0x82, 0xb9, 0xbd, 0xb6, 0x3f, 0x82, 0x00, 0x30, 0xca,
-	0xcc, 0x22, 0x92, 0xee, 0xde, 0x6a, 0x7f, 0xf6, 0x05, 0xbc, 0x5c, 0x79, 0x0d, 0x15, 0xf6, 0xb8,
-	0xba, 0xc3, 0xd5, 0x7c, 0xa2, 0x56, 0x6f, 0x9f, 0xaa, 0x87, 0xe3, 0xe1, 0x60, 0x80, 0xe7, 0x54,
-	0xb4, 0xb2, 0x94, 0x47, 0x71, 0x35, 0x70, 0xa7, 0x2f, 0xd9, 0x7d, 0x38, 0xe4, 0xd1, 0x59, 0xc8,
-	0xc2, 0xc1, 0x00, 0xe7, 0x2b, 0x1f, 0x5a, 0xa8, 0xd8, 0x0d, 0xbc, 0x76, 0x7f, 0x04, 0x63, 0x20,
-	0x67, 0xd1, 0x6a, 0x62, 0x98, 0xea, 0x70, 0x19, 0x5d, 0x9c, 0xa1, 0x2e, 0x0f, 0xa0, 0x2f, 0x86,
-	0x9c, 0x3d, 0xd0, 0xc1, 0x20, 0x68, 0x6d, 0xa6, 0xdd, 0x96, 0xd2, 0xc7, 0xb9, 0x2c, 0x53, 0x75,
-	0x1e, 0xe7, 0xb3, 0xec, 0x26, 0xf3, 0x00, 0xcf, 0x67, 0x3f, 0xb5, 0x33, 0xf6, 0xf1, 0x62, 0xd6,
-	0xad, 0xe1, 0x0f, 0x42, 0x7c, 0xf6, 0x24, 0xe3, 0x21, 0x26, 0x6a, 0x25, 0x33, 0xd6, 0xa4, 0x43,
-	0x0e, 0x12, 0x9f, 0xcb, 0x0e, 0x78, 0x8b, 0x49, 0x7c, 0xbe, 0xf2, 0xb6, 0x15, 0xbf, 0x9b, 0x55,
-	0x31, 0x8f, 0x5a, 0xb3, 0xa2, 0x67, 0xec, 0xc3, 0x40, 0x8e, 0x44, 0x8b, 0x4d, 0xc1, 0xc3, 0x96,
-	0x5a, 0x6d, 0x1a, 0x37, 0x99, 0xe7, 0xb1, 0x31, 0x48, 0x50, 0x75, 0xef, 0x0a, 0xb2, 0x8d, 0x76,
-	0x1b, 0xa6, 0xb7, 0x02, 0xe6, 0xa6, 0xd4, 0x3c, 0xd9, 0x44, 0xd7, 0x8c, 0xda, 0x09, 0xa8, 0x0f,
-	0x0f, 0xc4, 0xae, 0x70, 0xa1, 0x4f, 0x47, 0xe0, 0x06, 0x82, 0xa7, 0x3c, 0xe7, 0x2b, 0x5f, 0xd3,
-	0x2f, 0x6c, 0xf5, 0x5f, 0x87, 0x2a, 0x2c, 0xba, 0x75, 0x22, 0xf5, 0xce, 0xa1, 0x33, 0x86, 0xb7,
-	0x18, 0xd7, 0x7b, 0x86, 0x2d, 0x7d, 0xea, 0x23, 0x78, 0xcb, 0x3b, 0xf6, 0x47, 0x38, 0x47, 0xce,
-	0xe8, 0x07, 0x9d, 0x22, 0xba, 0x6a, 0xe6, 0x55, 0x08, 0x0c, 0x88, 0xee, 0x51, 0x3c, 0xaf, 0xe2,
-	0x67, 0x90, 0xf9, 0x7f, 0x03, 0x2f, 0x54, 0x7e, 0x62, 0x65, 0x5e, 0x7b, 0xaa, 0x5b, 0x62, 0x9a,
-	0xf0, 0xa8, 0x34, 0x4f, 0x50, 0x1b, 0xfa, 0x01, 0xc8, 0x1b, 0x62, 0xda, 0x3b, 0xa0, 0x75, 0x0f,
-	0xbb, 0xfa, 0x86, 0x4a, 0xd4, 0x9d, 0xf0, 0x78, 0xdc, 0x0c, 0x87, 0x91, 0x06, 0x59, 0xad, 0xcd,
-	0x86, 0x9c, 0x71, 0xa3, 0x0d, 0x48, 0x09, 0x3d, 0xf1, 0x69, 0x6d, 0x6f, 0xb7, 0xf6, 0xe2, 0x8b,
-	0xd5, 0xcf, 0xe1, 0xbf, 0x59, 0x95, 0xff, 0x14, 0xd0, 0xa2, 0xb9, 0xc4, 0xd5, 0xa4, 0x4c, 0xb3,
-	0x77, 0x20, 0xf6, 0x82, 0x40, 0x9f, 0x73, 0x12, 0xa3, 0x2e, 0xe7, 0x74, 0x0c, 0xae, 0xe2, 0x6f,
-	0x6c, 0x10, 0x1b, 0x9d, 0x8b, 0x85, 0x06, 0x97, 0x10, 0x70, 0xea, 0x29, 0xe5, 0xdb, 0x1b, 0xe4,
-	0x32, 0xba, 0x30, 0xeb, 0x12, 0x4e, 0x7c, 0x5f, 0xa8, 0x82, 0x74, 0xe8, 0xe3, 0xef, 0x9c, 0xd0,
-	0xd8, 0xd8, 0x8f, 0x7e, 0xf0, 0x00, 0x17, 0x7f, 0x77, 0x83, 0x9c, 0x47, 0x67, 0x62, 0xad, 0xc3,
-	0xc6, 0x20, 0x26, 0x12, 0x7f, 0x6f, 0x83, 0x3c, 0x81, 0xce, 0xc7, 0xb4, 0x3d, 0x9a, 0x48, 0xc9,
-	0xf8, 0x70, 0x57, 0x7c, 0x95, 0xe3, 0xef, 0x67, 0xa4, 0x03, 0x21, 0xeb, 0x82, 0x73, 0xe8, 0xab,
-	0xb1, 0x7e, 0xb0, 0x91, 0x9e, 0xb6, 0x7a, 0x12, 0xdf, 0xa4, 0xcc, 0x03, 0x17, 0xff, 0x30, 0x33,
-	0x6d, 0xfd, 0x23, 0x84, 0x51, 0xde, 0xdc, 0x20, 0x4f, 0xa2, 0x8b, 0xc9, 0x87, 0xa2, 0xdf, 0x09,
-	0xf4, 0x6b, 0x16, 0x5c, 0xfc, 0xa3, 0x0d, 0x72, 0x05, 0x5d, 0x8a, 0x45, 0xf3, 0xdf, 0xfe, 0x81,
-	0x90, 0x37, 0xc5, 0x84, 0xbb, 0xf8, 0xad, 0xcc, 0xaa, 0x8c, 0x6a, 0x8a, 0xe8, 0xdb, 0x99, 0x99,
-	0xdc, 0xa0, 0xae, 0x91, 0xf1, 0x4f, 0x33, 0x42, 0x83, 0xdf, 0xa7, 0x1e, 0x73, 0xbb, 0x4e, 0x03,
-	0xff, 0x6c, 0x43, 0xbd, 0x28, 0x52, 0x3d, 0xee, 0x50, 0x6f, 0x02, 0xf8, 0xe7, 0xa7, 0xf9, 0x77,
-	0xe8, 0x10, 0xff, 0x22, 0x33, 0xf1, 0x99, 0xd0, 0xf6, 0xa1, 0x8f, 0x7f, 0x99, 0x89, 0x91, 0xba,
-	0x03, 0x93, 0x59, 0xff, 0x2a, 0xb3, 0xa6, 0x03, 0x21, 0x47, 0x8c, 0x0f, 0x3b, 0xa2, 0x2e, 0xc6,
-	0x63, 0x26, 0xf1, 0xaf, 0x33, 0x1d, 0x23, 0x68, 0x22, 0xf5, 0x9b, 0xcc, 0x07, 0x75, 0xc1, 0x9d,
-	0xc5, 0xe2, 0x9d, 0x4c, 0x2c, 0x22, 0x51, 0xf5, 0x9b, 0x04, 0x80, 0x7f, 0x9b, 0x09, 0xfe, 0x8e,
-	0xef, 0x27, 0xbd, 0x1e, 0x66, 0x94, 0x26, 0xf5, 0x06, 0x22, 0x18, 0x83, 0xdb, 0x99, 0xe2, 0x77,
-	0x37, 0xc8, 0x45, 0x74, 0x36, 0x15, 0x0d, 0x5d, 0x6a, 0x28, 0xfe, 0x63, 0xa6, 0x87, 0xaa, 0x78,
-	0xf1, 0x57, 0xde, 0xcb, 0xf4, 0xd8, 0x9b, 0xaa, 0xe4, 0x53, 0x79, 0xf9, 0xa7, 0x0c, 0x6f, 0x25,
-	0x1b, 0xff, 0xe7, 0xec, 0x4a, 0xc1, 0xf3, 0x92, 0x69, 0xfd, 0x25, 0xf3, 0x91, 0x56, 0x20, 0xee,
-	0x33, 0x17, 0x02, 0x35, 0xd8, 0x5f, 0x37, 0xc8, 0x53, 0xe8, 0x72, 0xac, 0xdc, 0x61, 0xc2, 0xa3,
-	0x12, 0xc2, 0x1d, 0xdf, 0x07, 0xee, 0x1e, 0x72, 0xef, 0x18, 0xff, 0x6b, 0x83, 0x5c, 0x43, 0x4f,
-	0xcd, 0x76, 0x25, 0x9c, 0x0c, 0x06, 0xac, 0xcf, 0x80, 0xcb, 0x16, 0x04, 0x63, 0xa6, 0xb3, 0x2b,
-	0xc4, 0xff, 0xde, 0xa8, 0xec, 0xa2, 0xa5, 0xf8, 0x79, 0xac, 0xca, 0x46, 0xdc, 0xee, 0xed, 0x05,
-	0x81, 0x50, 0xc7, 0xef, 0xac, 0xfe, 0xad, 0x2c, 0x62, 0x77, 0x69, 0xa0, 0xee, 0x94, 0x34, 0x6a,
-	0xf0, 0x81, 0xc0, 0xf3, 0x37, 0xbe, 0xf2, 0xe8, 0xa3, 0xd2, 0xdc, 0x07, 0x1f, 0x95, 0xe6, 0x3e,
-	0xf9, 0xa8, 0x64, 0x7d, 0xfd, 0x71, 0xc9, 0x7a, 0xe7, 0x71, 0xc9, 0x7a, 0xff, 0x71, 0xc9, 0x7a,
-	0xf4, 0xb8, 0x64, 0xfd, 0xe3, 0x71, 0xc9, 0xfa, 0xe7, 0xe3, 0xd2, 0xdc, 0x27, 0x8f, 0x4b, 0xd6,
-	0x9b, 0x1f, 0x97, 0xe6, 0x1e, 0x7d, 0x5c, 0x9a, 0xfb, 0xe0, 0xe3, 0xd2, 0xdc, 0xab, 0xeb, 0x43,
-	0x26, 0x47, 0x93, 0xa3, 0xeb, 0x7d, 0x31, 0x7e, 0x9e, 0x8e, 0xfd, 0xe7, 0xb6, 0x5d, 0xfd, 0x27,
-	0x74, 0xef, 0x3d, 0x37, 0x14, 0xaa, 0xf9, 0x30, 0x97, 0xdf, 0x69, 0xb6, 0x8e, 0x0a, 0xfa, 0x17,
-	0xe2, 0xed, 0xff, 0x06, 0x00, 0x00, 0xff, 0xff, 0x34, 0xcc, 0xf9, 0xcb, 0x36, 0x16, 0x00, 0x00,
+func (m *MediaInfo) GetDurationMs() int64 {
+	if m != nil {
+		return m.DurationMs
+	}
+	return 0
 }
 
-func (x Const) String() string {
-	s, ok := Const_name[int32(x)]
-	if ok {
-		return s
+func (m *MediaInfo) GetSampleRate() int32 {
+	if m != nil {
+		return m.SampleRate
 	}
-	return strconv.Itoa(int(x))
+	return 0
 }
-func (x TxOpCode) String() string {
-	s, ok := TxOpCode_name[int32(x)]
-	if ok {
-		return s
+
+func (m *MediaInfo) GetChannels() int32 {
+	if m != nil {
+		return m.Channels
 	}
-	return strconv.Itoa(int(x))
+	return 0
 }
-func (x TxField) String() string {
-	s, ok := TxField_name[int32(x)]
-	if ok {
-		return s
+
+func (m *MediaInfo) GetWidth() int32 {
+	if m != nil {
+		return m.Width
 	}
-	return strconv.Itoa(int(x))
+	return 0
 }
-func (x SelectOp) String() string {
-	s, ok := SelectOp_name[int32(x)]
-	if ok {
-		return s
+
+func (m *MediaInfo) GetHeight() int32 {
+	if m != nil {
+		return m.Height
 	}
-	return strconv.Itoa(int(x))
+	return 0
 }
-func (x OpStatus) String() string {
-	s, ok := OpStatus_name[int32(x)]
-	if ok {
-		return s
+
+func (m *MediaInfo) GetOrientation() int32 {
+	if m != nil {
+		return m.Orientation
 	}
-	return strconv.Itoa(int(x))
+	return 0
 }
-func (x PinSync) String() string {
-	s, ok := PinSync_name[int32(x)]
-	if ok {
-		return s
+
+func (m *MediaInfo) GetCameraMake() string {
+	if m != nil {
+		return m.CameraMake
 	}
-	return strconv.Itoa(int(x))
+	return ""
 }
-func (x CordType) String() string {
-	s, ok := CordType_name[int32(x)]
-	if ok {
-		return s
+
+func (m *MediaInfo) GetCameraModel() string {
+	if m != nil {
+		return m.CameraModel
 	}
-	return strconv.Itoa(int(x))
+	return ""
 }
-func (x Enable) String() string {
-	s, ok := Enable_name[int32(x)]
-	if ok {
-		return s
+
+func (m *MediaInfo) GetTakenAt() int64 {
+	if m != nil {
+		return m.TakenAt
 	}
-	return strconv.Itoa(int(x))
+	return 0
 }
-func (x UrlScheme) String() string {
-	s, ok := UrlScheme_name[int32(x)]
-	if ok {
-		return s
+
+func (m *MediaInfo) GetHasLocation() bool {
+	if m != nil {
+		return m.HasLocation
 	}
-	return strconv.Itoa(int(x))
+	return false
 }
-func (x Metric) String() string {
-	s, ok := Metric_name[int32(x)]
-	if ok {
-		return s
+
+func (m *MediaInfo) GetLatitude() float64 {
+	if m != nil {
+		return m.Latitude
 	}
-	return strconv.Itoa(int(x))
+	return 0
 }
-func (x TagUse) String() string {
-	s, ok := TagUse_name[int32(x)]
-	if ok {
-		return s
+
+func (m *MediaInfo) GetLongitude() float64 {
+	if m != nil {
+		return m.Longitude
 	}
-	return strconv.Itoa(int(x))
+	return 0
 }
-func (x CryptoKitID) String() string {
-	s, ok := CryptoKitID_name[int32(x)]
-	if ok {
-		return s
+
+func (m *MediaInfo) GetEncoderDelay() int32 {
+	if m != nil {
+		return m.EncoderDelay
 	}
-	return strconv.Itoa(int(x))
+	return 0
 }
-func (x ErrCode) String() string {
-	s, ok := ErrCode_name[int32(x)]
-	if ok {
-		return s
+
+func (m *MediaInfo) GetEncoderPadding() int32 {
+	if m != nil {
+		return m.EncoderPadding
 	}
-	return strconv.Itoa(int(x))
+	return 0
 }
-func (x LogLevel) String() string {
-	s, ok := LogLevel_name[int32(x)]
-	if ok {
-		return s
+
+// WaveformPeaks is the peak amplitude of each of a series of equal slices of an audio or video asset, used to draw a scrubber.
+type WaveformPeaks struct {
+	// Duration of the audio spanned by Peaks
+	DurationMs int64 `protobuf:"varint,1,opt,name=DurationMs,proto3" json:"DurationMs,omitempty"`
+	// Peak amplitude of each slice, where 255 is full scale
+	Peaks []byte `protobuf:"bytes,2,opt,name=Peaks,proto3" json:"Peaks,omitempty"`
+}
+
+func (m *WaveformPeaks) Reset()      { *m = WaveformPeaks{} }
+func (*WaveformPeaks) ProtoMessage() {}
+func (*WaveformPeaks) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f4505e0ac3ae98d9, []int{21}
+}
+func (m *WaveformPeaks) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *WaveformPeaks) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_WaveformPeaks.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	return strconv.Itoa(int(x))
 }
-func (x TRS_VisualScaleMode) String() string {
-	s, ok := TRS_VisualScaleMode_name[int32(x)]
-	if ok {
-		return s
+func (m *WaveformPeaks) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WaveformPeaks.Merge(m, src)
+}
+func (m *WaveformPeaks) XXX_Size() int {
+	return m.Size()
+}
+func (m *WaveformPeaks) XXX_DiscardUnknown() {
+	xxx_messageInfo_WaveformPeaks.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WaveformPeaks proto.InternalMessageInfo
+
+func (m *WaveformPeaks) GetDurationMs() int64 {
+	if m != nil {
+		return m.DurationMs
 	}
-	return strconv.Itoa(int(x))
+	return 0
 }
-func (this *TxInfo) Equal(that interface{}) bool {
-	if that == nil {
-		return this == nil
+
+func (m *WaveformPeaks) GetPeaks() []byte {
+	if m != nil {
+		return m.Peaks
 	}
+	return nil
+}
 
-	that1, ok := that.(*TxInfo)
-	if !ok {
-		that2, ok := that.(TxInfo)
-		if ok {
-			that1 = &that2
-		} else {
-			return false
+// PlaylistEntry is an entry of a playlist cell, referring to a media item by its location.
+type PlaylistEntry struct {
+	// Path or URL of the item, as given by the imported playlist
+	Location string `protobuf:"bytes,1,opt,name=Location,proto3" json:"Location,omitempty"`
+	// Title of the item, if known
+	Title string `protobuf:"bytes,2,opt,name=Title,proto3" json:"Title,omitempty"`
+	// Artist of the item, if known
+	Artist string `protobuf:"bytes,3,opt,name=Artist,proto3" json:"Artist,omitempty"`
+	// Album of the item, if known
+	Album string `protobuf:"bytes,4,opt,name=Album,proto3" json:"Album,omitempty"`
+	// Duration of the item, or zero if not known
+	DurationMs int64 `protobuf:"varint,5,opt,name=DurationMs,proto3" json:"DurationMs,omitempty"`
+	// Path of the library item the entry resolves to, if resolved
+	Resolved string `protobuf:"bytes,6,opt,name=Resolved,proto3" json:"Resolved,omitempty"`
+}
+
+func (m *PlaylistEntry) Reset()      { *m = PlaylistEntry{} }
+func (*PlaylistEntry) ProtoMessage() {}
+func (*PlaylistEntry) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f4505e0ac3ae98d9, []int{22}
+}
+func (m *PlaylistEntry) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *PlaylistEntry) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_PlaylistEntry.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
+		return b[:n], nil
 	}
-	if that1 == nil {
-		return this == nil
-	} else if this == nil {
-		return false
-	}
-	if this.Status != that1.Status {
-		return false
-	}
-	if this.NumOps != that1.NumOps {
-		return false
-	}
-	if this.GenesisID_0 != that1.GenesisID_0 {
-		return false
-	}
-	if this.GenesisID_1 != that1.GenesisID_1 {
-		return false
-	}
-	if this.GenesisID_2 != that1.GenesisID_2 {
-		return false
-	}
-	if this.RequestID_0 != that1.RequestID_0 {
-		return false
-	}
-	if this.RequestID_1 != that1.RequestID_1 {
-		return false
+}
+func (m *PlaylistEntry) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PlaylistEntry.Merge(m, src)
+}
+func (m *PlaylistEntry) XXX_Size() int {
+	return m.Size()
+}
+func (m *PlaylistEntry) XXX_DiscardUnknown() {
+	xxx_messageInfo_PlaylistEntry.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PlaylistEntry proto.InternalMessageInfo
+
+func (m *PlaylistEntry) GetLocation() string {
+	if m != nil {
+		return m.Location
 	}
-	if this.RequestID_2 != that1.RequestID_2 {
-		return false
+	return ""
+}
+
+func (m *PlaylistEntry) GetTitle() string {
+	if m != nil {
+		return m.Title
 	}
-	if this.RootElementID_0 != that1.RootElementID_0 {
-		return false
+	return ""
+}
+
+func (m *PlaylistEntry) GetArtist() string {
+	if m != nil {
+		return m.Artist
 	}
-	if this.RootElementID_1 != that1.RootElementID_1 {
-		return false
+	return ""
+}
+
+func (m *PlaylistEntry) GetAlbum() string {
+	if m != nil {
+		return m.Album
 	}
-	if this.RootElementID_2 != that1.RootElementID_2 {
-		return false
+	return ""
+}
+
+func (m *PlaylistEntry) GetDurationMs() int64 {
+	if m != nil {
+		return m.DurationMs
 	}
-	return true
+	return 0
 }
-func (this *Login) Equal(that interface{}) bool {
-	if that == nil {
-		return this == nil
+
+func (m *PlaylistEntry) GetResolved() string {
+	if m != nil {
+		return m.Resolved
 	}
+	return ""
+}
 
-	that1, ok := that.(*Login)
-	if !ok {
-		that2, ok := that.(Login)
-		if ok {
-			that1 = &that2
-		} else {
-			return false
+// PlaybackEvent is committed by a client to a playable cell to report its playback of the item (e.g. so a streaming service can count plays and resume where the user left off).
+type PlaybackEvent struct {
+	State PlaybackState `protobuf:"varint,1,opt,name=State,proto3,enum=amp.PlaybackState" json:"State,omitempty"`
+	// Playback position when the event occurred
+	PositionMs int64 `protobuf:"varint,2,opt,name=PositionMs,proto3" json:"PositionMs,omitempty"`
+	// When the event occurred, in unix milliseconds
+	At int64 `protobuf:"varint,3,opt,name=At,proto3" json:"At,omitempty"`
+}
+
+func (m *PlaybackEvent) Reset()      { *m = PlaybackEvent{} }
+func (*PlaybackEvent) ProtoMessage() {}
+func (*PlaybackEvent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f4505e0ac3ae98d9, []int{23}
+}
+func (m *PlaybackEvent) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *PlaybackEvent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_PlaybackEvent.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
+		return b[:n], nil
 	}
-	if that1 == nil {
-		return this == nil
-	} else if this == nil {
-		return false
+}
+func (m *PlaybackEvent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PlaybackEvent.Merge(m, src)
+}
+func (m *PlaybackEvent) XXX_Size() int {
+	return m.Size()
+}
+func (m *PlaybackEvent) XXX_DiscardUnknown() {
+	xxx_messageInfo_PlaybackEvent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PlaybackEvent proto.InternalMessageInfo
+
+func (m *PlaybackEvent) GetState() PlaybackState {
+	if m != nil {
+		return m.State
 	}
-	if this.UserUID != that1.UserUID {
-		return false
+	return PlaybackState_Unspecified
+}
+
+func (m *PlaybackEvent) GetPositionMs() int64 {
+	if m != nil {
+		return m.PositionMs
 	}
-	if this.HostAddr != that1.HostAddr {
-		return false
+	return 0
+}
+
+func (m *PlaybackEvent) GetAt() int64 {
+	if m != nil {
+		return m.At
 	}
-	if this.DeviceLabel != that1.DeviceLabel {
-		return false
+	return 0
+}
+
+// TrackOffset locates a track within a media file holding several (e.g. an album ripped to a single file and described by a cue sheet).
+type TrackOffset struct {
+	// Where the track starts within the file
+	StartMs int64 `protobuf:"varint,1,opt,name=StartMs,proto3" json:"StartMs,omitempty"`
+	// Length of the track, or 0 if it runs to the end of the file
+	DurationMs int64 `protobuf:"varint,2,opt,name=DurationMs,proto3" json:"DurationMs,omitempty"`
+	// Length of the gap preceding StartMs, played when playing through from the previous track
+	PregapMs int64 `protobuf:"varint,3,opt,name=PregapMs,proto3" json:"PregapMs,omitempty"`
+}
+
+func (m *TrackOffset) Reset()      { *m = TrackOffset{} }
+func (*TrackOffset) ProtoMessage() {}
+func (*TrackOffset) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f4505e0ac3ae98d9, []int{24}
+}
+func (m *TrackOffset) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TrackOffset) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_TrackOffset.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	if this.DeviceUID != that1.DeviceUID {
-		return false
+}
+func (m *TrackOffset) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TrackOffset.Merge(m, src)
+}
+func (m *TrackOffset) XXX_Size() int {
+	return m.Size()
+}
+func (m *TrackOffset) XXX_DiscardUnknown() {
+	xxx_messageInfo_TrackOffset.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TrackOffset proto.InternalMessageInfo
+
+func (m *TrackOffset) GetStartMs() int64 {
+	if m != nil {
+		return m.StartMs
 	}
-	if !this.Checkpoint.Equal(that1.Checkpoint) {
-		return false
+	return 0
+}
+
+func (m *TrackOffset) GetDurationMs() int64 {
+	if m != nil {
+		return m.DurationMs
 	}
-	return true
+	return 0
 }
-func (this *LoginChallenge) Equal(that interface{}) bool {
-	if that == nil {
-		return this == nil
+
+func (m *TrackOffset) GetPregapMs() int64 {
+	if m != nil {
+		return m.PregapMs
 	}
+	return 0
+}
 
-	that1, ok := that.(*LoginChallenge)
-	if !ok {
-		that2, ok := that.(LoginChallenge)
-		if ok {
-			that1 = &that2
-		} else {
-			return false
+// GeoPoint is a location on the Earth, as for a photo, a place, or an AR anchor.
+type GeoPoint struct {
+	// Degrees, positive north
+	Lat float64 `protobuf:"fixed64,1,opt,name=Lat,proto3" json:"Lat,omitempty"`
+	// Degrees, positive east
+	Lon float64 `protobuf:"fixed64,2,opt,name=Lon,proto3" json:"Lon,omitempty"`
+	// Meters above mean sea level
+	Alt float64 `protobuf:"fixed64,3,opt,name=Alt,proto3" json:"Alt,omitempty"`
+}
+
+func (m *GeoPoint) Reset()      { *m = GeoPoint{} }
+func (*GeoPoint) ProtoMessage() {}
+func (*GeoPoint) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f4505e0ac3ae98d9, []int{25}
+}
+func (m *GeoPoint) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *GeoPoint) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_GeoPoint.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
+		return b[:n], nil
 	}
-	if that1 == nil {
-		return this == nil
-	} else if this == nil {
-		return false
+}
+func (m *GeoPoint) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GeoPoint.Merge(m, src)
+}
+func (m *GeoPoint) XXX_Size() int {
+	return m.Size()
+}
+func (m *GeoPoint) XXX_DiscardUnknown() {
+	xxx_messageInfo_GeoPoint.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GeoPoint proto.InternalMessageInfo
+
+func (m *GeoPoint) GetLat() float64 {
+	if m != nil {
+		return m.Lat
 	}
-	if !bytes.Equal(this.Hash, that1.Hash) {
-		return false
+	return 0
+}
+
+func (m *GeoPoint) GetLon() float64 {
+	if m != nil {
+		return m.Lon
 	}
-	return true
+	return 0
 }
-func (this *LoginResponse) Equal(that interface{}) bool {
-	if that == nil {
-		return this == nil
+
+func (m *GeoPoint) GetAlt() float64 {
+	if m != nil {
+		return m.Alt
 	}
+	return 0
+}
 
-	that1, ok := that.(*LoginResponse)
-	if !ok {
-		that2, ok := that.(LoginResponse)
-		if ok {
-			that1 = &that2
-		} else {
-			return false
+// TableColumn declares a column of a table cell (see package amp/std).
+type TableColumn struct {
+	// Identifies the column in sort keys and filters
+	Name string `protobuf:"bytes,1,opt,name=Name,proto3" json:"Name,omitempty"`
+	// Column heading for display
+	Label string     `protobuf:"bytes,2,opt,name=Label,proto3" json:"Label,omitempty"`
+	Type  ColumnType `protobuf:"varint,3,opt,name=Type,proto3,enum=amp.ColumnType" json:"Type,omitempty"`
+	// Unit of the column's values (e.g. "ms" or "bytes") -- optional
+	Unit string `protobuf:"bytes,4,opt,name=Unit,proto3" json:"Unit,omitempty"`
+}
+
+func (m *TableColumn) Reset()      { *m = TableColumn{} }
+func (*TableColumn) ProtoMessage() {}
+func (*TableColumn) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f4505e0ac3ae98d9, []int{26}
+}
+func (m *TableColumn) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TableColumn) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_TableColumn.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
+		return b[:n], nil
 	}
-	if that1 == nil {
-		return this == nil
-	} else if this == nil {
-		return false
-	}
-	if !bytes.Equal(this.HashResponse, that1.HashResponse) {
-		return false
+}
+func (m *TableColumn) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TableColumn.Merge(m, src)
+}
+func (m *TableColumn) XXX_Size() int {
+	return m.Size()
+}
+func (m *TableColumn) XXX_DiscardUnknown() {
+	xxx_messageInfo_TableColumn.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TableColumn proto.InternalMessageInfo
+
+func (m *TableColumn) GetName() string {
+	if m != nil {
+		return m.Name
 	}
-	return true
+	return ""
 }
-func (this *AuthCheckpoint) Equal(that interface{}) bool {
-	if that == nil {
-		return this == nil
+
+func (m *TableColumn) GetLabel() string {
+	if m != nil {
+		return m.Label
 	}
+	return ""
+}
 
-	that1, ok := that.(*AuthCheckpoint)
-	if !ok {
-		that2, ok := that.(AuthCheckpoint)
-		if ok {
-			that1 = &that2
-		} else {
-			return false
-		}
-	}
-	if that1 == nil {
-		return this == nil
-	} else if this == nil {
-		return false
-	}
-	if this.Token != that1.Token {
-		return false
-	}
-	if !this.Session.Equal(that1.Session) {
-		return false
-	}
-	if !this.Member.Equal(that1.Member) {
-		return false
-	}
-	if !this.HomeFeed.Equal(that1.HomeFeed) {
-		return false
+func (m *TableColumn) GetType() ColumnType {
+	if m != nil {
+		return m.Type
 	}
-	return true
+	return ColumnType_Text
 }
-func (this *PinRequest) Equal(that interface{}) bool {
-	if that == nil {
-		return this == nil
-	}
 
-	that1, ok := that.(*PinRequest)
-	if !ok {
-		that2, ok := that.(PinRequest)
-		if ok {
-			that1 = &that2
-		} else {
-			return false
-		}
-	}
-	if that1 == nil {
-		return this == nil
-	} else if this == nil {
-		return false
-	}
-	if !this.PinTarget.Equal(that1.PinTarget) {
-		return false
-	}
-	if len(this.PinAttrs) != len(that1.PinAttrs) {
-		return false
+func (m *TableColumn) GetUnit() string {
+	if m != nil {
+		return m.Unit
 	}
-	for i := range this.PinAttrs {
-		if !this.PinAttrs[i].Equal(that1.PinAttrs[i]) {
-			return false
+	return ""
+}
+
+// TableSortKey is a column a table's rows are ordered by.
+type TableSortKey struct {
+	Column string `protobuf:"bytes,1,opt,name=Column,proto3" json:"Column,omitempty"`
+	Desc   bool   `protobuf:"varint,2,opt,name=Desc,proto3" json:"Desc,omitempty"`
+}
+
+func (m *TableSortKey) Reset()      { *m = TableSortKey{} }
+func (*TableSortKey) ProtoMessage() {}
+func (*TableSortKey) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f4505e0ac3ae98d9, []int{27}
+}
+func (m *TableSortKey) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TableSortKey) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_TableSortKey.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
+		return b[:n], nil
 	}
-	if this.PinSync != that1.PinSync {
-		return false
+}
+func (m *TableSortKey) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TableSortKey.Merge(m, src)
+}
+func (m *TableSortKey) XXX_Size() int {
+	return m.Size()
+}
+func (m *TableSortKey) XXX_DiscardUnknown() {
+	xxx_messageInfo_TableSortKey.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TableSortKey proto.InternalMessageInfo
+
+func (m *TableSortKey) GetColumn() string {
+	if m != nil {
+		return m.Column
 	}
-	return true
+	return ""
 }
-func (this *LaunchURL) Equal(that interface{}) bool {
-	if that == nil {
-		return this == nil
+
+func (m *TableSortKey) GetDesc() bool {
+	if m != nil {
+		return m.Desc
 	}
+	return false
+}
 
-	that1, ok := that.(*LaunchURL)
-	if !ok {
-		that2, ok := that.(LaunchURL)
-		if ok {
-			that1 = &that2
-		} else {
-			return false
+// TableHeader declares the columns of a table cell and the order of its rows.
+type TableHeader struct {
+	Columns []*TableColumn `protobuf:"bytes,1,rep,name=Columns,proto3" json:"Columns,omitempty"`
+	// If empty, rows are ordered by their SI
+	Sort []*TableSortKey `protobuf:"bytes,2,rep,name=Sort,proto3" json:"Sort,omitempty"`
+}
+
+func (m *TableHeader) Reset()      { *m = TableHeader{} }
+func (*TableHeader) ProtoMessage() {}
+func (*TableHeader) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f4505e0ac3ae98d9, []int{28}
+}
+func (m *TableHeader) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TableHeader) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_TableHeader.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
+		return b[:n], nil
 	}
-	if that1 == nil {
-		return this == nil
-	} else if this == nil {
-		return false
-	}
-	if this.URL != that1.URL {
-		return false
+}
+func (m *TableHeader) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TableHeader.Merge(m, src)
+}
+func (m *TableHeader) XXX_Size() int {
+	return m.Size()
+}
+func (m *TableHeader) XXX_DiscardUnknown() {
+	xxx_messageInfo_TableHeader.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TableHeader proto.InternalMessageInfo
+
+func (m *TableHeader) GetColumns() []*TableColumn {
+	if m != nil {
+		return m.Columns
 	}
-	return true
+	return nil
 }
-func (this *Position) Equal(that interface{}) bool {
-	if that == nil {
-		return this == nil
+
+func (m *TableHeader) GetSort() []*TableSortKey {
+	if m != nil {
+		return m.Sort
 	}
+	return nil
+}
 
-	that1, ok := that.(*Position)
-	if !ok {
-		that2, ok := that.(Position)
-		if ok {
-			that1 = &that2
-		} else {
-			return false
+// TableValue is the value of a column of a TableRow, held in the field for the column's type (see ColumnType).
+type TableValue struct {
+	// Set if the row has no value for the column
+	Null  bool    `protobuf:"varint,1,opt,name=Null,proto3" json:"Null,omitempty"`
+	Text  string  `protobuf:"bytes,2,opt,name=Text,proto3" json:"Text,omitempty"`
+	Int   int64   `protobuf:"varint,3,opt,name=Int,proto3" json:"Int,omitempty"`
+	Float float64 `protobuf:"fixed64,4,opt,name=Float,proto3" json:"Float,omitempty"`
+	Bool  bool    `protobuf:"varint,5,opt,name=Bool,proto3" json:"Bool,omitempty"`
+	Bytes []byte  `protobuf:"bytes,6,opt,name=Bytes,proto3" json:"Bytes,omitempty"`
+}
+
+func (m *TableValue) Reset()      { *m = TableValue{} }
+func (*TableValue) ProtoMessage() {}
+func (*TableValue) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f4505e0ac3ae98d9, []int{29}
+}
+func (m *TableValue) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TableValue) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_TableValue.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
+		return b[:n], nil
 	}
-	if that1 == nil {
-		return this == nil
-	} else if this == nil {
-		return false
-	}
-	if this.CordType != that1.CordType {
-		return false
+}
+func (m *TableValue) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TableValue.Merge(m, src)
+}
+func (m *TableValue) XXX_Size() int {
+	return m.Size()
+}
+func (m *TableValue) XXX_DiscardUnknown() {
+	xxx_messageInfo_TableValue.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TableValue proto.InternalMessageInfo
+
+func (m *TableValue) GetNull() bool {
+	if m != nil {
+		return m.Null
 	}
-	if this.U != that1.U {
-		return false
+	return false
+}
+
+func (m *TableValue) GetText() string {
+	if m != nil {
+		return m.Text
 	}
-	if this.V != that1.V {
-		return false
+	return ""
+}
+
+func (m *TableValue) GetInt() int64 {
+	if m != nil {
+		return m.Int
 	}
-	if this.W != that1.W {
-		return false
+	return 0
+}
+
+func (m *TableValue) GetFloat() float64 {
+	if m != nil {
+		return m.Float
 	}
-	if this.ROU != that1.ROU {
-		return false
+	return 0
+}
+
+func (m *TableValue) GetBool() bool {
+	if m != nil {
+		return m.Bool
 	}
-	return true
+	return false
 }
-func (this *Tag) Equal(that interface{}) bool {
-	if that == nil {
-		return this == nil
+
+func (m *TableValue) GetBytes() []byte {
+	if m != nil {
+		return m.Bytes
 	}
+	return nil
+}
 
-	that1, ok := that.(*Tag)
-	if !ok {
-		that2, ok := that.(Tag)
-		if ok {
-			that1 = &that2
-		} else {
-			return false
+// TableRow is a row of a table cell, where the SI of the row's attr is the row's key.
+type TableRow struct {
+	// Value of each column, in the order declared by the TableHeader
+	Values []*TableValue `protobuf:"bytes,1,rep,name=Values,proto3" json:"Values,omitempty"`
+}
+
+func (m *TableRow) Reset()      { *m = TableRow{} }
+func (*TableRow) ProtoMessage() {}
+func (*TableRow) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f4505e0ac3ae98d9, []int{30}
+}
+func (m *TableRow) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TableRow) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_TableRow.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
+		return b[:n], nil
 	}
-	if that1 == nil {
-		return this == nil
-	} else if this == nil {
-		return false
-	}
-	if this.Use != that1.Use {
-		return false
+}
+func (m *TableRow) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TableRow.Merge(m, src)
+}
+func (m *TableRow) XXX_Size() int {
+	return m.Size()
+}
+func (m *TableRow) XXX_DiscardUnknown() {
+	xxx_messageInfo_TableRow.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TableRow proto.InternalMessageInfo
+
+func (m *TableRow) GetValues() []*TableValue {
+	if m != nil {
+		return m.Values
 	}
-	if this.TagID_0 != that1.TagID_0 {
-		return false
+	return nil
+}
+
+// LaunchURL is used as a meta attribute handle a URL, such as an oauth request (host to client) or an oauth response (client to host).
+type LaunchURL struct {
+	URL string `protobuf:"bytes,1,opt,name=URL,proto3" json:"URL,omitempty"`
+}
+
+func (m *LaunchURL) Reset()      { *m = LaunchURL{} }
+func (*LaunchURL) ProtoMessage() {}
+func (*LaunchURL) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f4505e0ac3ae98d9, []int{31}
+}
+func (m *LaunchURL) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *LaunchURL) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_LaunchURL.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	if this.TagID_1 != that1.TagID_1 {
-		return false
-	}
-	if this.TagID_2 != that1.TagID_2 {
-		return false
-	}
-	if this.URL != that1.URL {
-		return false
-	}
-	if this.ContentType != that1.ContentType {
-		return false
-	}
-	if !bytes.Equal(this.Attachment, that1.Attachment) {
-		return false
-	}
-	if this.Metric != that1.Metric {
-		return false
-	}
-	if this.Size_0 != that1.Size_0 {
-		return false
-	}
-	if this.Size_1 != that1.Size_1 {
-		return false
-	}
-	if this.Size_2 != that1.Size_2 {
-		return false
-	}
-	return true
 }
-func (this *TagTab) Equal(that interface{}) bool {
-	if that == nil {
-		return this == nil
+func (m *LaunchURL) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LaunchURL.Merge(m, src)
+}
+func (m *LaunchURL) XXX_Size() int {
+	return m.Size()
+}
+func (m *LaunchURL) XXX_DiscardUnknown() {
+	xxx_messageInfo_LaunchURL.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LaunchURL proto.InternalMessageInfo
+
+func (m *LaunchURL) GetURL() string {
+	if m != nil {
+		return m.URL
 	}
+	return ""
+}
 
-	that1, ok := that.(*TagTab)
-	if !ok {
-		that2, ok := that.(TagTab)
-		if ok {
-			that1 = &that2
-		} else {
-			return false
+// Position describes a position in space and/or time using a given coordinate system.
+type Position struct {
+	CordType CordType `protobuf:"varint,1,opt,name=CordType,proto3,enum=amp.CordType" json:"CordType,omitempty"`
+	U        float64  `protobuf:"fixed64,3,opt,name=U,proto3" json:"U,omitempty"`
+	V        float64  `protobuf:"fixed64,4,opt,name=V,proto3" json:"V,omitempty"`
+	W        float64  `protobuf:"fixed64,5,opt,name=W,proto3" json:"W,omitempty"`
+	ROU      float32  `protobuf:"fixed32,6,opt,name=ROU,proto3" json:"ROU,omitempty"`
+}
+
+func (m *Position) Reset()      { *m = Position{} }
+func (*Position) ProtoMessage() {}
+func (*Position) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f4505e0ac3ae98d9, []int{32}
+}
+func (m *Position) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Position) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Position.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
+		return b[:n], nil
 	}
-	if that1 == nil {
-		return this == nil
-	} else if this == nil {
-		return false
-	}
-	if this.Label != that1.Label {
-		return false
-	}
-	if this.Caption != that1.Caption {
-		return false
-	}
-	if this.About != that1.About {
-		return false
-	}
-	if this.CreatedAt != that1.CreatedAt {
-		return false
+}
+func (m *Position) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Position.Merge(m, src)
+}
+func (m *Position) XXX_Size() int {
+	return m.Size()
+}
+func (m *Position) XXX_DiscardUnknown() {
+	xxx_messageInfo_Position.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Position proto.InternalMessageInfo
+
+func (m *Position) GetCordType() CordType {
+	if m != nil {
+		return m.CordType
 	}
-	if this.ModifiedAt != that1.ModifiedAt {
-		return false
+	return CordType_Unspecified
+}
+
+func (m *Position) GetU() float64 {
+	if m != nil {
+		return m.U
 	}
-	if len(this.Tags) != len(that1.Tags) {
-		return false
+	return 0
+}
+
+func (m *Position) GetV() float64 {
+	if m != nil {
+		return m.V
 	}
-	for i := range this.Tags {
-		if !this.Tags[i].Equal(that1.Tags[i]) {
-			return false
-		}
+	return 0
+}
+
+func (m *Position) GetW() float64 {
+	if m != nil {
+		return m.W
 	}
-	return true
+	return 0
 }
-func (this *Ballot) Equal(that interface{}) bool {
-	if that == nil {
-		return this == nil
+
+func (m *Position) GetROU() float32 {
+	if m != nil {
+		return m.ROU
 	}
+	return 0
+}
 
-	that1, ok := that.(*Ballot)
-	if !ok {
-		that2, ok := that.(Ballot)
-		if ok {
-			that1 = &that2
-		} else {
-			return false
+// Tag is a multi-use workhorse. All fields are optional and whose meaning is contextual.
+//
+// Often used to reference an asset, a Link can reference any resource, a show, project, episode, or XR beacon.
+// The tagging naming convention describes a semi-ordered list of UTF tags.
+//      As tags first appear when going from left to right in the list, they are considered "more significant" or "higher priority" than tags that appear later.
+//      It is up to amp-search-dev-tag-specification to order search results based on tag filters (case sensitive, time ranges, or any UTF8 enum identifier)
+//      By convention, tags are case sensitive by default, however there are many filter presets -- This is how people "type or speak search"
+//      "Two tag rule" -- if you can think of two or more other tags in an order ranking, then do that instead.
+type Tag struct {
+	Use TagUse `protobuf:"varint,1,opt,name=Use,proto3,enum=amp.TagUse" json:"Use,omitempty"`
+	// Identifies a specific target tag ID this link points to.
+	TagID_0     int64   `protobuf:"varint,2,opt,name=TagID_0,json=TagID0,proto3" json:"TagID_0,omitempty"`
+	TagID_1     uint64  `protobuf:"fixed64,3,opt,name=TagID_1,json=TagID1,proto3" json:"TagID_1,omitempty"`
+	TagID_2     uint64  `protobuf:"fixed64,4,opt,name=TagID_2,json=TagID2,proto3" json:"TagID_2,omitempty"`
+	URL         string  `protobuf:"bytes,13,opt,name=URL,proto3" json:"URL,omitempty"`
+	ContentType string  `protobuf:"bytes,14,opt,name=ContentType,proto3" json:"ContentType,omitempty"`
+	Attachment  []byte  `protobuf:"bytes,15,opt,name=Attachment,proto3" json:"Attachment,omitempty"`
+	Metric      Metric  `protobuf:"varint,26,opt,name=Metric,proto3,enum=amp.Metric" json:"Metric,omitempty"`
+	Size_0      float32 `protobuf:"fixed32,27,opt,name=Size_0,json=Size0,proto3" json:"Size_0,omitempty"`
+	Size_1      float32 `protobuf:"fixed32,28,opt,name=Size_1,json=Size1,proto3" json:"Size_1,omitempty"`
+	Size_2      float32 `protobuf:"fixed32,29,opt,name=Size_2,json=Size2,proto3" json:"Size_2,omitempty"`
+}
+
+func (m *Tag) Reset()      { *m = Tag{} }
+func (*Tag) ProtoMessage() {}
+func (*Tag) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f4505e0ac3ae98d9, []int{33}
+}
+func (m *Tag) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Tag) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Tag.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
+		return b[:n], nil
 	}
-	if that1 == nil {
-		return this == nil
-	} else if this == nil {
-		return false
-	}
-	return true
 }
-func (this *NotesEntry) Equal(that interface{}) bool {
-	if that == nil {
-		return this == nil
+func (m *Tag) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Tag.Merge(m, src)
+}
+func (m *Tag) XXX_Size() int {
+	return m.Size()
+}
+func (m *Tag) XXX_DiscardUnknown() {
+	xxx_messageInfo_Tag.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Tag proto.InternalMessageInfo
+
+func (m *Tag) GetUse() TagUse {
+	if m != nil {
+		return m.Use
 	}
+	return TagUse_Unspecified
+}
 
-	that1, ok := that.(*NotesEntry)
-	if !ok {
-		that2, ok := that.(NotesEntry)
-		if ok {
-			that1 = &that2
-		} else {
-			return false
-		}
+func (m *Tag) GetTagID_0() int64 {
+	if m != nil {
+		return m.TagID_0
 	}
-	if that1 == nil {
-		return this == nil
-	} else if this == nil {
-		return false
+	return 0
+}
+
+func (m *Tag) GetTagID_1() uint64 {
+	if m != nil {
+		return m.TagID_1
 	}
-	return true
+	return 0
 }
-func (this *ChatEntry) Equal(that interface{}) bool {
-	if that == nil {
-		return this == nil
+
+func (m *Tag) GetTagID_2() uint64 {
+	if m != nil {
+		return m.TagID_2
 	}
+	return 0
+}
 
-	that1, ok := that.(*ChatEntry)
-	if !ok {
-		that2, ok := that.(ChatEntry)
-		if ok {
-			that1 = &that2
-		} else {
-			return false
-		}
-	}
-	if that1 == nil {
-		return this == nil
-	} else if this == nil {
-		return false
+func (m *Tag) GetURL() string {
+	if m != nil {
+		return m.URL
 	}
-	return true
+	return ""
 }
-func (this *SpreadsheetEntry) Equal(that interface{}) bool {
-	if that == nil {
-		return this == nil
-	}
 
-	that1, ok := that.(*SpreadsheetEntry)
-	if !ok {
-		that2, ok := that.(SpreadsheetEntry)
-		if ok {
-			that1 = &that2
-		} else {
-			return false
-		}
-	}
-	if that1 == nil {
-		return this == nil
-	} else if this == nil {
-		return false
+func (m *Tag) GetContentType() string {
+	if m != nil {
+		return m.ContentType
 	}
-	return true
+	return ""
 }
-func (this *SpatialPlacement) Equal(that interface{}) bool {
-	if that == nil {
-		return this == nil
+
+func (m *Tag) GetAttachment() []byte {
+	if m != nil {
+		return m.Attachment
 	}
+	return nil
+}
 
-	that1, ok := that.(*SpatialPlacement)
-	if !ok {
-		that2, ok := that.(SpatialPlacement)
-		if ok {
-			that1 = &that2
-		} else {
-			return false
-		}
+func (m *Tag) GetMetric() Metric {
+	if m != nil {
+		return m.Metric
 	}
-	if that1 == nil {
-		return this == nil
-	} else if this == nil {
-		return false
+	return Metric_Nil
+}
+
+func (m *Tag) GetSize_0() float32 {
+	if m != nil {
+		return m.Size_0
 	}
-	if this.Pos0 != that1.Pos0 {
-		return false
+	return 0
+}
+
+func (m *Tag) GetSize_1() float32 {
+	if m != nil {
+		return m.Size_1
 	}
-	if this.Pos1 != that1.Pos1 {
-		return false
+	return 0
+}
+
+func (m *Tag) GetSize_2() float32 {
+	if m != nil {
+		return m.Size_2
 	}
-	if this.Pos2 != that1.Pos2 {
-		return false
+	return 0
+}
+
+// TagTab maps to a visual tab for a Tag -- complete with fields for user consumption.
+type TagTab struct {
+	Label      string `protobuf:"bytes,1,opt,name=Label,proto3" json:"Label,omitempty"`
+	Caption    string `protobuf:"bytes,2,opt,name=Caption,proto3" json:"Caption,omitempty"`
+	About      string `protobuf:"bytes,3,opt,name=About,proto3" json:"About,omitempty"`
+	CreatedAt  int64  `protobuf:"varint,6,opt,name=CreatedAt,proto3" json:"CreatedAt,omitempty"`
+	ModifiedAt int64  `protobuf:"varint,7,opt,name=ModifiedAt,proto3" json:"ModifiedAt,omitempty"`
+	Tags       []*Tag `protobuf:"bytes,9,rep,name=Tags,proto3" json:"Tags,omitempty"`
+	// Per-locale variants of Label, Caption, and About, replaced by the variant best matching the client's Login.Locales as emitted (see TagTab.Localize)
+	Locales []*LocaleText `protobuf:"bytes,10,rep,name=Locales,proto3" json:"Locales,omitempty"`
+}
+
+func (m *TagTab) Reset()      { *m = TagTab{} }
+func (*TagTab) ProtoMessage() {}
+func (*TagTab) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f4505e0ac3ae98d9, []int{34}
+}
+func (m *TagTab) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TagTab) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_TagTab.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	if this.Scale0 != that1.Scale0 {
-		return false
+}
+func (m *TagTab) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TagTab.Merge(m, src)
+}
+func (m *TagTab) XXX_Size() int {
+	return m.Size()
+}
+func (m *TagTab) XXX_DiscardUnknown() {
+	xxx_messageInfo_TagTab.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TagTab proto.InternalMessageInfo
+
+func (m *TagTab) GetLabel() string {
+	if m != nil {
+		return m.Label
 	}
-	if this.Scale1 != that1.Scale1 {
-		return false
+	return ""
+}
+
+func (m *TagTab) GetCaption() string {
+	if m != nil {
+		return m.Caption
 	}
-	if this.Scale2 != that1.Scale2 {
-		return false
+	return ""
+}
+
+func (m *TagTab) GetAbout() string {
+	if m != nil {
+		return m.About
 	}
-	if this.EulerX != that1.EulerX {
-		return false
+	return ""
+}
+
+func (m *TagTab) GetCreatedAt() int64 {
+	if m != nil {
+		return m.CreatedAt
 	}
-	if this.EulerY != that1.EulerY {
-		return false
+	return 0
+}
+
+func (m *TagTab) GetModifiedAt() int64 {
+	if m != nil {
+		return m.ModifiedAt
 	}
-	if this.EulerZ != that1.EulerZ {
-		return false
+	return 0
+}
+
+func (m *TagTab) GetTags() []*Tag {
+	if m != nil {
+		return m.Tags
 	}
-	return true
+	return nil
 }
-func (this *TagPlayableMedia) Equal(that interface{}) bool {
-	if that == nil {
-		return this == nil
+
+func (m *TagTab) GetLocales() []*LocaleText {
+	if m != nil {
+		return m.Locales
 	}
+	return nil
+}
 
-	that1, ok := that.(*TagPlayableMedia)
-	if !ok {
-		that2, ok := that.(TagPlayableMedia)
-		if ok {
-			that1 = &that2
-		} else {
-			return false
+// LocaleText is the text of a TagTab in a given locale.
+type LocaleText struct {
+	// BCP 47 language tag, e.g. "en", "pt-BR", or "zh-Hant"
+	Locale string `protobuf:"bytes,1,opt,name=Locale,proto3" json:"Locale,omitempty"`
+	// If empty, falls back to the next best locale (and then to TagTab.Label)
+	Label   string `protobuf:"bytes,2,opt,name=Label,proto3" json:"Label,omitempty"`
+	Caption string `protobuf:"bytes,3,opt,name=Caption,proto3" json:"Caption,omitempty"`
+	About   string `protobuf:"bytes,4,opt,name=About,proto3" json:"About,omitempty"`
+}
+
+func (m *LocaleText) Reset()      { *m = LocaleText{} }
+func (*LocaleText) ProtoMessage() {}
+func (*LocaleText) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f4505e0ac3ae98d9, []int{35}
+}
+func (m *LocaleText) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *LocaleText) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_LocaleText.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
+		return b[:n], nil
 	}
-	if that1 == nil {
-		return this == nil
-	} else if this == nil {
-		return false
-	}
-	return true
 }
-func (this *TagPlaylist) Equal(that interface{}) bool {
-	if that == nil {
-		return this == nil
-	}
+func (m *LocaleText) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LocaleText.Merge(m, src)
+}
+func (m *LocaleText) XXX_Size() int {
+	return m.Size()
+}
+func (m *LocaleText) XXX_DiscardUnknown() {
+	xxx_messageInfo_LocaleText.DiscardUnknown(m)
+}
 
-	that1, ok := that.(*TagPlaylist)
-	if !ok {
-		that2, ok := that.(TagPlaylist)
-		if ok {
-			that1 = &that2
-		} else {
-			return false
-		}
+var xxx_messageInfo_LocaleText proto.InternalMessageInfo
+
+func (m *LocaleText) GetLocale() string {
+	if m != nil {
+		return m.Locale
 	}
-	if that1 == nil {
-		return this == nil
-	} else if this == nil {
-		return false
+	return ""
+}
+
+func (m *LocaleText) GetLabel() string {
+	if m != nil {
+		return m.Label
 	}
-	return true
+	return ""
 }
-func (this *CryptoKey) Equal(that interface{}) bool {
-	if that == nil {
-		return this == nil
+
+func (m *LocaleText) GetCaption() string {
+	if m != nil {
+		return m.Caption
 	}
+	return ""
+}
 
-	that1, ok := that.(*CryptoKey)
-	if !ok {
-		that2, ok := that.(CryptoKey)
-		if ok {
-			that1 = &that2
-		} else {
-			return false
-		}
+func (m *LocaleText) GetAbout() string {
+	if m != nil {
+		return m.About
 	}
-	if that1 == nil {
-		return this == nil
-	} else if this == nil {
-		return false
-	}
-	if this.CryptoKitID != that1.CryptoKitID {
-		return false
-	}
-	if !bytes.Equal(this.KeyBytes, that1.KeyBytes) {
-		return false
-	}
-	return true
+	return ""
 }
-func (this *AuthToken) Equal(that interface{}) bool {
-	if that == nil {
-		return this == nil
-	}
 
-	that1, ok := that.(*AuthToken)
-	if !ok {
-		that2, ok := that.(AuthToken)
-		if ok {
-			that1 = &that2
-		} else {
-			return false
-		}
-	}
-	if that1 == nil {
-		return this == nil
-	} else if this == nil {
-		return false
-	}
-	if this.AccessToken != that1.AccessToken {
-		return false
-	}
-	if this.TokenType != that1.TokenType {
-		return false
-	}
-	if this.RefreshToken != that1.RefreshToken {
-		return false
-	}
-	if this.Expiry != that1.Expiry {
-		return false
-	}
-	return true
+//
+//
+//// ChannelSpec declares the presence of a particular set of attr series that, if pinned, have a particular format and intended usage.
+////
+//// In general, for incoming FeedInfo, a corresponding client pin op, UI tab, and UI sheet (though the sheet may often be hidden or obscured).
+////
+//// Most streams have 3-4 AttrFeeds:
+////      amp.tag.feed.[item.ID]TagTab
+////      amp.tag.feed.items.genesis.[]EpochDelta
+//message ChannelSpec {  // AttrLayer, AttrSheet, AttrBundle, AttrSet
+//
+//// Specifies an attr element and/or sequence format.
+////    e.g. "amp.app.attr.os.EpochDelta" -
+////
+//// This value implies an format and interpretation of the data elements in this series and their collective meaning,
+//// and typically invokes particular UI elements that specifically handle or visualize the elements of this series.
+////
+//// Any particular format spec can imply the existence of any number of attr series (e.g. an attr that binds particular attr series as columns).
+//string              FormatSpec = 2;
+//
+//// Specifies an attr usage and/or intention -- e.g. "index"
+////
+//// This can be considered a suffix to the SeriesSpec, and is used to refine UI presentation and behavior as appropriate.
+//string              ContextSpec = 3;
+//
+//// Specifies a specific instance of this format and context spec.
+////
+//// This means the spec that is actually pinned is "{FormatSpec}.{ContextSpec}.{InstanceID}"
+////Tag                 InstanceID = 4;
+//
+//// Optional sub collection of feeds also part of this feed - e.g. columns of a spreadsheet
+////repeated Feed       Elements = 9;
+//}
+//
+//
+//
+//// By convention, every cell carries a "amp.feed.genesis.EpochDelta" feed which in turn is a tag.Time series of []EpochDelta.
+////    series expressing state for the cell's permissions and the channel catalog.
+//message EpochDelta {
+//
+//// typically 1-4 ops that define default user and permission hive transactions, corresponding permissions tokens, and tag-wide public tokens
+//// by convention, the first follows the "constitution rule" where you list things like organization or group mission and offer a purpose.Tag that goes into ore detail about purpose and mission.
+////
+//// These essentially sequential edits to a permissions key-value store
+//// TagIDs (ops)
+////      "text/amp.tag.epoch.entries.{clear|write}.{admin|commons|group}"
+////      "text/amp.tag.epoch.entries.{clear|write}.{admin|commons|group}"
+//repeated Tag        PermissionsOps = 2;
+//
+//repeated string     ChannelSpecs = 4;
+//repeated FeedInfo   RemoveFeeds = 5;
+//
+//}
+//
+//
+//
+//
+//
+//
+//
+//
+//
+//
+//
+//
+//
+//
+//
+//
+//
+//
+//
+//
+//
+//
+//
+//
+//
+//
+//The U.S.C Social Experiment
+//- people "vote" on what they want
+//- we're making a voting os app -- invoke blockchains
+//
+//-- there were penned as  channels, but the actual pb declarations are update elements.
+//-- suffix ideas: Rev, Delta, Op, Tx, Item, Entry, Edit
+type Ballot struct {
 }
-func (this *TRS) Equal(that interface{}) bool {
-	if that == nil {
-		return this == nil
-	}
 
-	that1, ok := that.(*TRS)
-	if !ok {
-		that2, ok := that.(TRS)
-		if ok {
-			that1 = &that2
-		} else {
-			return false
+func (m *Ballot) Reset()      { *m = Ballot{} }
+func (*Ballot) ProtoMessage() {}
+func (*Ballot) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f4505e0ac3ae98d9, []int{36}
+}
+func (m *Ballot) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Ballot) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Ballot.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
+		return b[:n], nil
 	}
-	if that1 == nil {
-		return this == nil
-	} else if this == nil {
-		return false
-	}
-	if this.X1 != that1.X1 {
-		return false
-	}
-	if this.X2 != that1.X2 {
-		return false
-	}
-	if this.X3 != that1.X3 {
-		return false
-	}
-	if this.ScaleMode != that1.ScaleMode {
-		return false
-	}
-	if this.Scale1 != that1.Scale1 {
-		return false
-	}
-	if this.Scale2 != that1.Scale2 {
-		return false
-	}
-	if this.Scale3 != that1.Scale3 {
-		return false
-	}
-	if this.Rotate1 != that1.Rotate1 {
-		return false
-	}
-	if this.Rotate2 != that1.Rotate2 {
-		return false
-	}
-	if this.Rotate3 != that1.Rotate3 {
-		return false
-	}
-	return true
 }
-func (this *DataSegment) Equal(that interface{}) bool {
-	if that == nil {
-		return this == nil
-	}
+func (m *Ballot) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Ballot.Merge(m, src)
+}
+func (m *Ballot) XXX_Size() int {
+	return m.Size()
+}
+func (m *Ballot) XXX_DiscardUnknown() {
+	xxx_messageInfo_Ballot.DiscardUnknown(m)
+}
 
-	that1, ok := that.(*DataSegment)
-	if !ok {
-		that2, ok := that.(DataSegment)
-		if ok {
-			that1 = &that2
-		} else {
-			return false
+var xxx_messageInfo_Ballot proto.InternalMessageInfo
+
+type NotesEntry struct {
+}
+
+func (m *NotesEntry) Reset()      { *m = NotesEntry{} }
+func (*NotesEntry) ProtoMessage() {}
+func (*NotesEntry) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f4505e0ac3ae98d9, []int{37}
+}
+func (m *NotesEntry) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *NotesEntry) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_NotesEntry.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
+		return b[:n], nil
 	}
-	if that1 == nil {
-		return this == nil
-	} else if this == nil {
-		return false
-	}
-	if this.ByteOfs != that1.ByteOfs {
-		return false
-	}
-	if this.ByteSz != that1.ByteSz {
-		return false
-	}
-	if !bytes.Equal(this.InlineData, that1.InlineData) {
-		return false
-	}
-	if this.StreamURI != that1.StreamURI {
-		return false
-	}
-	if this.BlobID != that1.BlobID {
-		return false
-	}
-	return true
 }
-func (this *Err) Equal(that interface{}) bool {
-	if that == nil {
-		return this == nil
-	}
+func (m *NotesEntry) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NotesEntry.Merge(m, src)
+}
+func (m *NotesEntry) XXX_Size() int {
+	return m.Size()
+}
+func (m *NotesEntry) XXX_DiscardUnknown() {
+	xxx_messageInfo_NotesEntry.DiscardUnknown(m)
+}
 
-	that1, ok := that.(*Err)
-	if !ok {
-		that2, ok := that.(Err)
-		if ok {
-			that1 = &that2
-		} else {
-			return false
+var xxx_messageInfo_NotesEntry proto.InternalMessageInfo
+
+//  "amp.tag.spec.talk.spec.message"
+type ChatEntry struct {
+}
+
+func (m *ChatEntry) Reset()      { *m = ChatEntry{} }
+func (*ChatEntry) ProtoMessage() {}
+func (*ChatEntry) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f4505e0ac3ae98d9, []int{38}
+}
+func (m *ChatEntry) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ChatEntry) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ChatEntry.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
+		return b[:n], nil
 	}
-	if that1 == nil {
-		return this == nil
-	} else if this == nil {
-		return false
-	}
-	if this.Code != that1.Code {
-		return false
-	}
-	if this.Level != that1.Level {
-		return false
+}
+func (m *ChatEntry) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ChatEntry.Merge(m, src)
+}
+func (m *ChatEntry) XXX_Size() int {
+	return m.Size()
+}
+func (m *ChatEntry) XXX_DiscardUnknown() {
+	xxx_messageInfo_ChatEntry.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ChatEntry proto.InternalMessageInfo
+
+type SpreadsheetEntry struct {
+}
+
+func (m *SpreadsheetEntry) Reset()      { *m = SpreadsheetEntry{} }
+func (*SpreadsheetEntry) ProtoMessage() {}
+func (*SpreadsheetEntry) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f4505e0ac3ae98d9, []int{39}
+}
+func (m *SpreadsheetEntry) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *SpreadsheetEntry) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_SpreadsheetEntry.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	if this.Msg != that1.Msg {
-		return false
+}
+func (m *SpreadsheetEntry) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SpreadsheetEntry.Merge(m, src)
+}
+func (m *SpreadsheetEntry) XXX_Size() int {
+	return m.Size()
+}
+func (m *SpreadsheetEntry) XXX_DiscardUnknown() {
+	xxx_messageInfo_SpreadsheetEntry.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SpreadsheetEntry proto.InternalMessageInfo
+
+type SpatialPlacement struct {
+	// Expresses the position of this placement in space.
+	// The coordinate system is specified within (or implied) from the hosting attribute spec.
+	// Here's to the 3 domains that reflect completeness: alpha (finite), omega (unending), and the inaccessible cardinal(s) -- https://www.youtube.com/watch?v=SrU9YDoXE88
+	Pos0 float64 `protobuf:"fixed64,1,opt,name=Pos0,proto3" json:"Pos0,omitempty"`
+	Pos1 float64 `protobuf:"fixed64,2,opt,name=Pos1,proto3" json:"Pos1,omitempty"`
+	Pos2 float64 `protobuf:"fixed64,3,opt,name=Pos2,proto3" json:"Pos2,omitempty"`
+	// Expresses the scale of this Node.
+	// If all three values are 0, they are all implicitly 1.
+	// If Scale2 or Scale3 == 0, then they are each implicitly Scale1.
+	Scale0 float32 `protobuf:"fixed32,4,opt,name=Scale0,proto3" json:"Scale0,omitempty"`
+	Scale1 float32 `protobuf:"fixed32,5,opt,name=Scale1,proto3" json:"Scale1,omitempty"`
+	Scale2 float32 `protobuf:"fixed32,6,opt,name=Scale2,proto3" json:"Scale2,omitempty"`
+	// Expresses the orientation of this placement using Euler angles.
+	EulerX float32 `protobuf:"fixed32,7,opt,name=EulerX,proto3" json:"EulerX,omitempty"`
+	EulerY float32 `protobuf:"fixed32,8,opt,name=EulerY,proto3" json:"EulerY,omitempty"`
+	EulerZ float32 `protobuf:"fixed32,9,opt,name=EulerZ,proto3" json:"EulerZ,omitempty"`
+}
+
+func (m *SpatialPlacement) Reset()      { *m = SpatialPlacement{} }
+func (*SpatialPlacement) ProtoMessage() {}
+func (*SpatialPlacement) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f4505e0ac3ae98d9, []int{40}
+}
+func (m *SpatialPlacement) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *SpatialPlacement) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_SpatialPlacement.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	return true
 }
-func (this *TxInfo) GoString() string {
-	if this == nil {
-		return "nil"
+func (m *SpatialPlacement) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SpatialPlacement.Merge(m, src)
+}
+func (m *SpatialPlacement) XXX_Size() int {
+	return m.Size()
+}
+func (m *SpatialPlacement) XXX_DiscardUnknown() {
+	xxx_messageInfo_SpatialPlacement.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SpatialPlacement proto.InternalMessageInfo
+
+func (m *SpatialPlacement) GetPos0() float64 {
+	if m != nil {
+		return m.Pos0
 	}
-	s := make([]string, 0, 15)
-	s = append(s, "&amp.TxInfo{")
-	s = append(s, "Status: "+fmt.Sprintf("%#v", this.Status)+",\n")
-	s = append(s, "NumOps: "+fmt.Sprintf("%#v", this.NumOps)+",\n")
-	s = append(s, "GenesisID_0: "+fmt.Sprintf("%#v", this.GenesisID_0)+",\n")
-	s = append(s, "GenesisID_1: "+fmt.Sprintf("%#v", this.GenesisID_1)+",\n")
-	s = append(s, "GenesisID_2: "+fmt.Sprintf("%#v", this.GenesisID_2)+",\n")
-	s = append(s, "RequestID_0: "+fmt.Sprintf("%#v", this.RequestID_0)+",\n")
-	s = append(s, "RequestID_1: "+fmt.Sprintf("%#v", this.RequestID_1)+",\n")
-	s = append(s, "RequestID_2: "+fmt.Sprintf("%#v", this.RequestID_2)+",\n")
-	s = append(s, "RootElementID_0: "+fmt.Sprintf("%#v", this.RootElementID_0)+",\n")
-	s = append(s, "RootElementID_1: "+fmt.Sprintf("%#v", this.RootElementID_1)+",\n")
-	s = append(s, "RootElementID_2: "+fmt.Sprintf("%#v", this.RootElementID_2)+",\n")
-	s = append(s, "}")
-	return strings.Join(s, "")
+	return 0
 }
-func (this *Login) GoString() string {
-	if this == nil {
-		return "nil"
+
+func (m *SpatialPlacement) GetPos1() float64 {
+	if m != nil {
+		return m.Pos1
 	}
-	s := make([]string, 0, 9)
-	s = append(s, "&amp.Login{")
-	s = append(s, "UserUID: "+fmt.Sprintf("%#v", this.UserUID)+",\n")
-	s = append(s, "HostAddr: "+fmt.Sprintf("%#v", this.HostAddr)+",\n")
-	s = append(s, "DeviceLabel: "+fmt.Sprintf("%#v", this.DeviceLabel)+",\n")
-	s = append(s, "DeviceUID: "+fmt.Sprintf("%#v", this.DeviceUID)+",\n")
-	if this.Checkpoint != nil {
-		s = append(s, "Checkpoint: "+fmt.Sprintf("%#v", this.Checkpoint)+",\n")
+	return 0
+}
+
+func (m *SpatialPlacement) GetPos2() float64 {
+	if m != nil {
+		return m.Pos2
 	}
-	s = append(s, "}")
-	return strings.Join(s, "")
+	return 0
 }
-func (this *LoginChallenge) GoString() string {
-	if this == nil {
-		return "nil"
+
+func (m *SpatialPlacement) GetScale0() float32 {
+	if m != nil {
+		return m.Scale0
 	}
-	s := make([]string, 0, 5)
-	s = append(s, "&amp.LoginChallenge{")
-	s = append(s, "Hash: "+fmt.Sprintf("%#v", this.Hash)+",\n")
-	s = append(s, "}")
-	return strings.Join(s, "")
+	return 0
 }
-func (this *LoginResponse) GoString() string {
-	if this == nil {
-		return "nil"
+
+func (m *SpatialPlacement) GetScale1() float32 {
+	if m != nil {
+		return m.Scale1
 	}
-	s := make([]string, 0, 5)
-	s = append(s, "&amp.LoginResponse{")
-	s = append(s, "HashResponse: "+fmt.Sprintf("%#v", this.HashResponse)+",\n")
-	s = append(s, "}")
-	return strings.Join(s, "")
+	return 0
 }
-func (this *AuthCheckpoint) GoString() string {
-	if this == nil {
-		return "nil"
+
+func (m *SpatialPlacement) GetScale2() float32 {
+	if m != nil {
+		return m.Scale2
 	}
-	s := make([]string, 0, 8)
-	s = append(s, "&amp.AuthCheckpoint{")
-	s = append(s, "Token: "+fmt.Sprintf("%#v", this.Token)+",\n")
-	if this.Session != nil {
-		s = append(s, "Session: "+fmt.Sprintf("%#v", this.Session)+",\n")
+	return 0
+}
+
+func (m *SpatialPlacement) GetEulerX() float32 {
+	if m != nil {
+		return m.EulerX
 	}
-	if this.Member != nil {
-		s = append(s, "Member: "+fmt.Sprintf("%#v", this.Member)+",\n")
+	return 0
+}
+
+func (m *SpatialPlacement) GetEulerY() float32 {
+	if m != nil {
+		return m.EulerY
 	}
-	if this.HomeFeed != nil {
-		s = append(s, "HomeFeed: "+fmt.Sprintf("%#v", this.HomeFeed)+",\n")
+	return 0
+}
+
+func (m *SpatialPlacement) GetEulerZ() float32 {
+	if m != nil {
+		return m.EulerZ
 	}
-	s = append(s, "}")
-	return strings.Join(s, "")
+	return 0
 }
-func (this *PinRequest) GoString() string {
-	if this == nil {
-		return "nil"
-	}
-	s := make([]string, 0, 7)
-	s = append(s, "&amp.PinRequest{")
-	if this.PinTarget != nil {
-		s = append(s, "PinTarget: "+fmt.Sprintf("%#v", this.PinTarget)+",\n")
-	}
-	if this.PinAttrs != nil {
-		s = append(s, "PinAttrs: "+fmt.Sprintf("%#v", this.PinAttrs)+",\n")
-	}
-	s = append(s, "PinSync: "+fmt.Sprintf("%#v", this.PinSync)+",\n")
-	s = append(s, "}")
-	return strings.Join(s, "")
+
+type TagPlayableMedia struct {
 }
-func (this *LaunchURL) GoString() string {
-	if this == nil {
-		return "nil"
-	}
-	s := make([]string, 0, 5)
-	s = append(s, "&amp.LaunchURL{")
-	s = append(s, "URL: "+fmt.Sprintf("%#v", this.URL)+",\n")
-	s = append(s, "}")
-	return strings.Join(s, "")
+
+func (m *TagPlayableMedia) Reset()      { *m = TagPlayableMedia{} }
+func (*TagPlayableMedia) ProtoMessage() {}
+func (*TagPlayableMedia) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f4505e0ac3ae98d9, []int{41}
 }
-func (this *Position) GoString() string {
-	if this == nil {
-		return "nil"
-	}
-	s := make([]string, 0, 9)
-	s = append(s, "&amp.Position{")
-	s = append(s, "CordType: "+fmt.Sprintf("%#v", this.CordType)+",\n")
-	s = append(s, "U: "+fmt.Sprintf("%#v", this.U)+",\n")
-	s = append(s, "V: "+fmt.Sprintf("%#v", this.V)+",\n")
-	s = append(s, "W: "+fmt.Sprintf("%#v", this.W)+",\n")
-	s = append(s, "ROU: "+fmt.Sprintf("%#v", this.ROU)+",\n")
-	s = append(s, "}")
-	return strings.Join(s, "")
+func (m *TagPlayableMedia) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
 }
-func (this *Tag) GoString() string {
-	if this == nil {
-		return "nil"
+func (m *TagPlayableMedia) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_TagPlayableMedia.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	s := make([]string, 0, 15)
-	s = append(s, "&amp.Tag{")
-	s = append(s, "Use: "+fmt.Sprintf("%#v", this.Use)+",\n")
-	s = append(s, "TagID_0: "+fmt.Sprintf("%#v", this.TagID_0)+",\n")
-	s = append(s, "TagID_1: "+fmt.Sprintf("%#v", this.TagID_1)+",\n")
-	s = append(s, "TagID_2: "+fmt.Sprintf("%#v", this.TagID_2)+",\n")
-	s = append(s, "URL: "+fmt.Sprintf("%#v", this.URL)+",\n")
-	s = append(s, "ContentType: "+fmt.Sprintf("%#v", this.ContentType)+",\n")
-	s = append(s, "Attachment: "+fmt.Sprintf("%#v", this.Attachment)+",\n")
-	s = append(s, "Metric: "+fmt.Sprintf("%#v", this.Metric)+",\n")
-	s = append(s, "Size_0: "+fmt.Sprintf("%#v", this.Size_0)+",\n")
-	s = append(s, "Size_1: "+fmt.Sprintf("%#v", this.Size_1)+",\n")
-	s = append(s, "Size_2: "+fmt.Sprintf("%#v", this.Size_2)+",\n")
-	s = append(s, "}")
-	return strings.Join(s, "")
 }
-func (this *TagTab) GoString() string {
-	if this == nil {
-		return "nil"
-	}
-	s := make([]string, 0, 10)
-	s = append(s, "&amp.TagTab{")
-	s = append(s, "Label: "+fmt.Sprintf("%#v", this.Label)+",\n")
-	s = append(s, "Caption: "+fmt.Sprintf("%#v", this.Caption)+",\n")
-	s = append(s, "About: "+fmt.Sprintf("%#v", this.About)+",\n")
-	s = append(s, "CreatedAt: "+fmt.Sprintf("%#v", this.CreatedAt)+",\n")
-	s = append(s, "ModifiedAt: "+fmt.Sprintf("%#v", this.ModifiedAt)+",\n")
-	if this.Tags != nil {
-		s = append(s, "Tags: "+fmt.Sprintf("%#v", this.Tags)+",\n")
-	}
-	s = append(s, "}")
-	return strings.Join(s, "")
+func (m *TagPlayableMedia) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TagPlayableMedia.Merge(m, src)
 }
-func (this *Ballot) GoString() string {
-	if this == nil {
-		return "nil"
-	}
-	s := make([]string, 0, 4)
-	s = append(s, "&amp.Ballot{")
-	s = append(s, "}")
-	return strings.Join(s, "")
+func (m *TagPlayableMedia) XXX_Size() int {
+	return m.Size()
 }
-func (this *NotesEntry) GoString() string {
-	if this == nil {
-		return "nil"
-	}
-	s := make([]string, 0, 4)
-	s = append(s, "&amp.NotesEntry{")
-	s = append(s, "}")
-	return strings.Join(s, "")
+func (m *TagPlayableMedia) XXX_DiscardUnknown() {
+	xxx_messageInfo_TagPlayableMedia.DiscardUnknown(m)
 }
-func (this *ChatEntry) GoString() string {
-	if this == nil {
-		return "nil"
-	}
-	s := make([]string, 0, 4)
-	s = append(s, "&amp.ChatEntry{")
-	s = append(s, "}")
-	return strings.Join(s, "")
+
+var xxx_messageInfo_TagPlayableMedia proto.InternalMessageInfo
+
+type TagPlaylist struct {
 }
-func (this *SpreadsheetEntry) GoString() string {
-	if this == nil {
-		return "nil"
-	}
-	s := make([]string, 0, 4)
-	s = append(s, "&amp.SpreadsheetEntry{")
-	s = append(s, "}")
-	return strings.Join(s, "")
+
+func (m *TagPlaylist) Reset()      { *m = TagPlaylist{} }
+func (*TagPlaylist) ProtoMessage() {}
+func (*TagPlaylist) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f4505e0ac3ae98d9, []int{42}
 }
-func (this *SpatialPlacement) GoString() string {
-	if this == nil {
-		return "nil"
-	}
-	s := make([]string, 0, 13)
-	s = append(s, "&amp.SpatialPlacement{")
-	s = append(s, "Pos0: "+fmt.Sprintf("%#v", this.Pos0)+",\n")
-	s = append(s, "Pos1: "+fmt.Sprintf("%#v", this.Pos1)+",\n")
-	s = append(s, "Pos2: "+fmt.Sprintf("%#v", this.Pos2)+",\n")
-	s = append(s, "Scale0: "+fmt.Sprintf("%#v", this.Scale0)+",\n")
-	s = append(s, "Scale1: "+fmt.Sprintf("%#v", this.Scale1)+",\n")
-	s = append(s, "Scale2: "+fmt.Sprintf("%#v", this.Scale2)+",\n")
-	s = append(s, "EulerX: "+fmt.Sprintf("%#v", this.EulerX)+",\n")
-	s = append(s, "EulerY: "+fmt.Sprintf("%#v", this.EulerY)+",\n")
-	s = append(s, "EulerZ: "+fmt.Sprintf("%#v", this.EulerZ)+",\n")
-	s = append(s, "}")
-	return strings.Join(s, "")
+func (m *TagPlaylist) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
 }
-func (this *TagPlayableMedia) GoString() string {
-	if this == nil {
-		return "nil"
+func (m *TagPlaylist) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_TagPlaylist.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	s := make([]string, 0, 4)
-	s = append(s, "&amp.TagPlayableMedia{")
-	s = append(s, "}")
-	return strings.Join(s, "")
 }
-func (this *TagPlaylist) GoString() string {
-	if this == nil {
-		return "nil"
-	}
-	s := make([]string, 0, 4)
-	s = append(s, "&amp.TagPlaylist{")
-	s = append(s, "}")
-	return strings.Join(s, "")
+func (m *TagPlaylist) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TagPlaylist.Merge(m, src)
 }
-func (this *CryptoKey) GoString() string {
-	if this == nil {
-		return "nil"
+func (m *TagPlaylist) XXX_Size() int {
+	return m.Size()
+}
+func (m *TagPlaylist) XXX_DiscardUnknown() {
+	xxx_messageInfo_TagPlaylist.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TagPlaylist proto.InternalMessageInfo
+
+type CryptoKey struct {
+	CryptoKitID CryptoKitID `protobuf:"varint,1,opt,name=CryptoKitID,proto3,enum=amp.CryptoKitID" json:"CryptoKitID,omitempty"`
+	KeyBytes    []byte      `protobuf:"bytes,4,opt,name=KeyBytes,proto3" json:"KeyBytes,omitempty"`
+}
+
+func (m *CryptoKey) Reset()      { *m = CryptoKey{} }
+func (*CryptoKey) ProtoMessage() {}
+func (*CryptoKey) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f4505e0ac3ae98d9, []int{43}
+}
+func (m *CryptoKey) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *CryptoKey) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_CryptoKey.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	s := make([]string, 0, 6)
-	s = append(s, "&amp.CryptoKey{")
-	s = append(s, "CryptoKitID: "+fmt.Sprintf("%#v", this.CryptoKitID)+",\n")
-	s = append(s, "KeyBytes: "+fmt.Sprintf("%#v", this.KeyBytes)+",\n")
-	s = append(s, "}")
-	return strings.Join(s, "")
 }
-func (this *AuthToken) GoString() string {
-	if this == nil {
-		return "nil"
+func (m *CryptoKey) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CryptoKey.Merge(m, src)
+}
+func (m *CryptoKey) XXX_Size() int {
+	return m.Size()
+}
+func (m *CryptoKey) XXX_DiscardUnknown() {
+	xxx_messageInfo_CryptoKey.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CryptoKey proto.InternalMessageInfo
+
+func (m *CryptoKey) GetCryptoKitID() CryptoKitID {
+	if m != nil {
+		return m.CryptoKitID
 	}
-	s := make([]string, 0, 8)
-	s = append(s, "&amp.AuthToken{")
-	s = append(s, "AccessToken: "+fmt.Sprintf("%#v", this.AccessToken)+",\n")
-	s = append(s, "TokenType: "+fmt.Sprintf("%#v", this.TokenType)+",\n")
-	s = append(s, "RefreshToken: "+fmt.Sprintf("%#v", this.RefreshToken)+",\n")
-	s = append(s, "Expiry: "+fmt.Sprintf("%#v", this.Expiry)+",\n")
-	s = append(s, "}")
-	return strings.Join(s, "")
+	return CryptoKit_Nil
 }
-func (this *TRS) GoString() string {
-	if this == nil {
-		return "nil"
+
+func (m *CryptoKey) GetKeyBytes() []byte {
+	if m != nil {
+		return m.KeyBytes
 	}
-	s := make([]string, 0, 14)
-	s = append(s, "&amp.TRS{")
-	s = append(s, "X1: "+fmt.Sprintf("%#v", this.X1)+",\n")
-	s = append(s, "X2: "+fmt.Sprintf("%#v", this.X2)+",\n")
-	s = append(s, "X3: "+fmt.Sprintf("%#v", this.X3)+",\n")
-	s = append(s, "ScaleMode: "+fmt.Sprintf("%#v", this.ScaleMode)+",\n")
-	s = append(s, "Scale1: "+fmt.Sprintf("%#v", this.Scale1)+",\n")
-	s = append(s, "Scale2: "+fmt.Sprintf("%#v", this.Scale2)+",\n")
-	s = append(s, "Scale3: "+fmt.Sprintf("%#v", this.Scale3)+",\n")
-	s = append(s, "Rotate1: "+fmt.Sprintf("%#v", this.Rotate1)+",\n")
-	s = append(s, "Rotate2: "+fmt.Sprintf("%#v", this.Rotate2)+",\n")
-	s = append(s, "Rotate3: "+fmt.Sprintf("%#v", this.Rotate3)+",\n")
-	s = append(s, "}")
-	return strings.Join(s, "")
+	return nil
 }
-func (this *DataSegment) GoString() string {
-	if this == nil {
-		return "nil"
-	}
-	s := make([]string, 0, 9)
-	s = append(s, "&amp.DataSegment{")
-	s = append(s, "ByteOfs: "+fmt.Sprintf("%#v", this.ByteOfs)+",\n")
-	s = append(s, "ByteSz: "+fmt.Sprintf("%#v", this.ByteSz)+",\n")
-	s = append(s, "InlineData: "+fmt.Sprintf("%#v", this.InlineData)+",\n")
-	s = append(s, "StreamURI: "+fmt.Sprintf("%#v", this.StreamURI)+",\n")
-	s = append(s, "BlobID: "+fmt.Sprintf("%#v", this.BlobID)+",\n")
-	s = append(s, "}")
-	return strings.Join(s, "")
+
+// AuthToken is an oauth token -- see oauth2.Token
+type AuthToken struct {
+	AccessToken  string `protobuf:"bytes,1,opt,name=AccessToken,proto3" json:"AccessToken,omitempty"`
+	TokenType    string `protobuf:"bytes,2,opt,name=TokenType,proto3" json:"TokenType,omitempty"`
+	RefreshToken string `protobuf:"bytes,3,opt,name=RefreshToken,proto3" json:"RefreshToken,omitempty"`
+	Expiry       int64  `protobuf:"varint,4,opt,name=Expiry,proto3" json:"Expiry,omitempty"`
 }
-func (this *Err) GoString() string {
-	if this == nil {
-		return "nil"
-	}
-	s := make([]string, 0, 7)
-	s = append(s, "&amp.Err{")
-	s = append(s, "Code: "+fmt.Sprintf("%#v", this.Code)+",\n")
-	s = append(s, "Level: "+fmt.Sprintf("%#v", this.Level)+",\n")
-	s = append(s, "Msg: "+fmt.Sprintf("%#v", this.Msg)+",\n")
-	s = append(s, "}")
-	return strings.Join(s, "")
+
+func (m *AuthToken) Reset()      { *m = AuthToken{} }
+func (*AuthToken) ProtoMessage() {}
+func (*AuthToken) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f4505e0ac3ae98d9, []int{44}
 }
-func valueToGoStringApiAmp(v interface{}, typ string) string {
-	rv := reflect.ValueOf(v)
-	if rv.IsNil() {
-		return "nil"
-	}
-	pv := reflect.Indirect(rv).Interface()
-	return fmt.Sprintf("func(v %v) *%v { return &v } ( %#v )", typ, typ, pv)
+func (m *AuthToken) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
 }
-func (m *TxInfo) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+func (m *AuthToken) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_AuthToken.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	return dAtA[:n], nil
 }
-
-func (m *TxInfo) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func (m *AuthToken) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AuthToken.Merge(m, src)
 }
-
-func (m *TxInfo) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.RootElementID_2 != 0 {
-		i -= 8
-		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(m.RootElementID_2))
-		i--
-		dAtA[i] = 0x79
-	}
-	if m.RootElementID_1 != 0 {
-		i -= 8
-		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(m.RootElementID_1))
-		i--
-		dAtA[i] = 0x71
-	}
-	if m.RootElementID_0 != 0 {
-		i = encodeVarintApiAmp(dAtA, i, uint64(m.RootElementID_0))
-		i--
-		dAtA[i] = 0x68
-	}
-	if m.RequestID_2 != 0 {
-		i -= 8
-		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(m.RequestID_2))
-		i--
-		dAtA[i] = 0x61
-	}
-	if m.RequestID_1 != 0 {
-		i -= 8
-		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(m.RequestID_1))
-		i--
-		dAtA[i] = 0x59
-	}
-	if m.RequestID_0 != 0 {
-		i = encodeVarintApiAmp(dAtA, i, uint64(m.RequestID_0))
-		i--
-		dAtA[i] = 0x50
-	}
-	if m.GenesisID_2 != 0 {
-		i -= 8
-		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(m.GenesisID_2))
-		i--
-		dAtA[i] = 0x39
-	}
-	if m.GenesisID_1 != 0 {
-		i -= 8
-		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(m.GenesisID_1))
-		i--
-		dAtA[i] = 0x31
-	}
-	if m.GenesisID_0 != 0 {
-		i = encodeVarintApiAmp(dAtA, i, uint64(m.GenesisID_0))
-		i--
-		dAtA[i] = 0x28
-	}
-	if m.NumOps != 0 {
-		i = encodeVarintApiAmp(dAtA, i, uint64(m.NumOps))
-		i--
-		dAtA[i] = 0x20
-	}
-	if m.Status != 0 {
-		i = encodeVarintApiAmp(dAtA, i, uint64(m.Status))
-		i--
-		dAtA[i] = 0x10
-	}
-	return len(dAtA) - i, nil
+func (m *AuthToken) XXX_Size() int {
+	return m.Size()
 }
-
-func (m *Login) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
-	}
-	return dAtA[:n], nil
+func (m *AuthToken) XXX_DiscardUnknown() {
+	xxx_messageInfo_AuthToken.DiscardUnknown(m)
 }
 
-func (m *Login) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
-}
+var xxx_messageInfo_AuthToken proto.InternalMessageInfo
 
-func (m *Login) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.Checkpoint != nil {
-		{
-			size, err := m.Checkpoint.MarshalToSizedBuffer(dAtA[:i])
-			if err != nil {
-				return 0, err
-			}
-			i -= size
-			i = encodeVarintApiAmp(dAtA, i, uint64(size))
-		}
-		i--
-		dAtA[i] = 0x52
-	}
-	if len(m.DeviceUID) > 0 {
-		i -= len(m.DeviceUID)
-		copy(dAtA[i:], m.DeviceUID)
-		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.DeviceUID)))
-		i--
-		dAtA[i] = 0x4a
-	}
-	if len(m.DeviceLabel) > 0 {
-		i -= len(m.DeviceLabel)
-		copy(dAtA[i:], m.DeviceLabel)
-		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.DeviceLabel)))
-		i--
-		dAtA[i] = 0x42
-	}
-	if len(m.HostAddr) > 0 {
-		i -= len(m.HostAddr)
-		copy(dAtA[i:], m.HostAddr)
-		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.HostAddr)))
-		i--
-		dAtA[i] = 0x12
-	}
-	if len(m.UserUID) > 0 {
-		i -= len(m.UserUID)
-		copy(dAtA[i:], m.UserUID)
-		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.UserUID)))
-		i--
-		dAtA[i] = 0xa
+func (m *AuthToken) GetAccessToken() string {
+	if m != nil {
+		return m.AccessToken
 	}
-	return len(dAtA) - i, nil
+	return ""
 }
 
-func (m *LoginChallenge) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+func (m *AuthToken) GetTokenType() string {
+	if m != nil {
+		return m.TokenType
 	}
-	return dAtA[:n], nil
+	return ""
 }
 
-func (m *LoginChallenge) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func (m *AuthToken) GetRefreshToken() string {
+	if m != nil {
+		return m.RefreshToken
+	}
+	return ""
 }
 
-func (m *LoginChallenge) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.Hash) > 0 {
-		i -= len(m.Hash)
-		copy(dAtA[i:], m.Hash)
-		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.Hash)))
-		i--
-		dAtA[i] = 0xa
+func (m *AuthToken) GetExpiry() int64 {
+	if m != nil {
+		return m.Expiry
 	}
-	return len(dAtA) - i, nil
+	return 0
 }
 
-func (m *LoginResponse) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
-	}
-	return dAtA[:n], nil
+type TRS struct {
+	// X1, X2, and X3 are coordinates or values expressed in any unit.
+	// A channel client can later declare how to interpret these coordinates so that a channel server and provide indexed services.
+	// Shoutout to the 3 domains that reflect all theoretical completeness: alpha (finite), omega (unending), and the inaccessible cardinal(s).
+	// Special thanks to Michael at Vsauce: https://www.youtube.com/watch?v=SrU9YDoXE88
+	X1 float64 `protobuf:"fixed64,41,opt,name=X1,proto3" json:"X1,omitempty"`
+	X2 float64 `protobuf:"fixed64,42,opt,name=X2,proto3" json:"X2,omitempty"`
+	X3 float64 `protobuf:"fixed64,43,opt,name=X3,proto3" json:"X3,omitempty"`
+	// Specifies how scale dynamically changes based on observer position.
+	ScaleMode TRS_VisualScaleMode `protobuf:"varint,50,opt,name=ScaleMode,proto3,enum=amp.TRS_VisualScaleMode" json:"ScaleMode,omitempty"`
+	// Scale1..3 express the scale of this placement.
+	// If all three values are 0, they are all implicitly 1.
+	// If Scale2 or Scale3 == 0, then it is implicitly Scale1.
+	Scale1 float32 `protobuf:"fixed32,51,opt,name=Scale1,proto3" json:"Scale1,omitempty"`
+	Scale2 float32 `protobuf:"fixed32,52,opt,name=Scale2,proto3" json:"Scale2,omitempty"`
+	Scale3 float32 `protobuf:"fixed32,53,opt,name=Scale3,proto3" json:"Scale3,omitempty"`
+	// Rotate1 - Rotate3 the orientation of this placement using Euler angles.
+	Rotate1 float32 `protobuf:"fixed32,61,opt,name=Rotate1,proto3" json:"Rotate1,omitempty"`
+	Rotate2 float32 `protobuf:"fixed32,62,opt,name=Rotate2,proto3" json:"Rotate2,omitempty"`
+	Rotate3 float32 `protobuf:"fixed32,63,opt,name=Rotate3,proto3" json:"Rotate3,omitempty"`
 }
 
-func (m *LoginResponse) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func (m *TRS) Reset()      { *m = TRS{} }
+func (*TRS) ProtoMessage() {}
+func (*TRS) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f4505e0ac3ae98d9, []int{45}
 }
-
-func (m *LoginResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.HashResponse) > 0 {
-		i -= len(m.HashResponse)
-		copy(dAtA[i:], m.HashResponse)
-		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.HashResponse)))
-		i--
-		dAtA[i] = 0xa
+func (m *TRS) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TRS) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_TRS.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	return len(dAtA) - i, nil
+}
+func (m *TRS) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TRS.Merge(m, src)
+}
+func (m *TRS) XXX_Size() int {
+	return m.Size()
+}
+func (m *TRS) XXX_DiscardUnknown() {
+	xxx_messageInfo_TRS.DiscardUnknown(m)
 }
 
-func (m *AuthCheckpoint) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+var xxx_messageInfo_TRS proto.InternalMessageInfo
+
+func (m *TRS) GetX1() float64 {
+	if m != nil {
+		return m.X1
 	}
-	return dAtA[:n], nil
+	return 0
 }
 
-func (m *AuthCheckpoint) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func (m *TRS) GetX2() float64 {
+	if m != nil {
+		return m.X2
+	}
+	return 0
 }
 
-func (m *AuthCheckpoint) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.Member != nil {
-		{
-			size, err := m.Member.MarshalToSizedBuffer(dAtA[:i])
-			if err != nil {
-				return 0, err
-			}
-			i -= size
-			i = encodeVarintApiAmp(dAtA, i, uint64(size))
-		}
-		i--
-		dAtA[i] = 0x5a
-	}
-	if m.Session != nil {
-		{
-			size, err := m.Session.MarshalToSizedBuffer(dAtA[:i])
-			if err != nil {
-				return 0, err
-			}
-			i -= size
-			i = encodeVarintApiAmp(dAtA, i, uint64(size))
-		}
-		i--
-		dAtA[i] = 0x52
-	}
-	if m.HomeFeed != nil {
-		{
-			size, err := m.HomeFeed.MarshalToSizedBuffer(dAtA[:i])
-			if err != nil {
-				return 0, err
-			}
-			i -= size
-			i = encodeVarintApiAmp(dAtA, i, uint64(size))
-		}
-		i--
-		dAtA[i] = 0x4a
-	}
-	if len(m.Token) > 0 {
-		i -= len(m.Token)
-		copy(dAtA[i:], m.Token)
-		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.Token)))
-		i--
-		dAtA[i] = 0xa
+func (m *TRS) GetX3() float64 {
+	if m != nil {
+		return m.X3
 	}
-	return len(dAtA) - i, nil
+	return 0
 }
 
-func (m *PinRequest) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+func (m *TRS) GetScaleMode() TRS_VisualScaleMode {
+	if m != nil {
+		return m.ScaleMode
 	}
-	return dAtA[:n], nil
+	return AutoScale
 }
 
-func (m *PinRequest) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func (m *TRS) GetScale1() float32 {
+	if m != nil {
+		return m.Scale1
+	}
+	return 0
 }
 
-func (m *PinRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.PinSync != 0 {
-		i = encodeVarintApiAmp(dAtA, i, uint64(m.PinSync))
-		i--
-		dAtA[i] = 0x30
-	}
-	if len(m.PinAttrs) > 0 {
-		for iNdEx := len(m.PinAttrs) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size, err := m.PinAttrs[iNdEx].MarshalToSizedBuffer(dAtA[:i])
-				if err != nil {
-					return 0, err
-				}
-				i -= size
-				i = encodeVarintApiAmp(dAtA, i, uint64(size))
-			}
-			i--
-			dAtA[i] = 0x22
-		}
-	}
-	if m.PinTarget != nil {
-		{
-			size, err := m.PinTarget.MarshalToSizedBuffer(dAtA[:i])
-			if err != nil {
-				return 0, err
-			}
-			i -= size
-			i = encodeVarintApiAmp(dAtA, i, uint64(size))
-		}
-		i--
-		dAtA[i] = 0x12
+func (m *TRS) GetScale2() float32 {
+	if m != nil {
+		return m.Scale2
 	}
-	return len(dAtA) - i, nil
+	return 0
 }
 
-func (m *LaunchURL) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+func (m *TRS) GetScale3() float32 {
+	if m != nil {
+		return m.Scale3
 	}
-	return dAtA[:n], nil
+	return 0
 }
 
-func (m *LaunchURL) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func (m *TRS) GetRotate1() float32 {
+	if m != nil {
+		return m.Rotate1
+	}
+	return 0
 }
 
-func (m *LaunchURL) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.URL) > 0 {
-		i -= len(m.URL)
-		copy(dAtA[i:], m.URL)
-		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.URL)))
-		i--
-		dAtA[i] = 0xa
+func (m *TRS) GetRotate2() float32 {
+	if m != nil {
+		return m.Rotate2
 	}
-	return len(dAtA) - i, nil
+	return 0
 }
 
-func (m *Position) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+func (m *TRS) GetRotate3() float32 {
+	if m != nil {
+		return m.Rotate3
 	}
-	return dAtA[:n], nil
+	return 0
 }
 
-func (m *Position) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+type DataSegment struct {
+	ByteOfs    uint64 `protobuf:"varint,5,opt,name=ByteOfs,proto3" json:"ByteOfs,omitempty"`
+	ByteSz     uint64 `protobuf:"varint,6,opt,name=ByteSz,proto3" json:"ByteSz,omitempty"`
+	InlineData []byte `protobuf:"bytes,7,opt,name=InlineData,proto3" json:"InlineData,omitempty"`
+	StreamURI  string `protobuf:"bytes,9,opt,name=StreamURI,proto3" json:"StreamURI,omitempty"`
+	BlobID     int64  `protobuf:"varint,10,opt,name=BlobID,proto3" json:"BlobID,omitempty"`
 }
 
-func (m *Position) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.ROU != 0 {
-		i -= 4
-		encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(m.ROU))))
-		i--
-		dAtA[i] = 0x35
+func (m *DataSegment) Reset()      { *m = DataSegment{} }
+func (*DataSegment) ProtoMessage() {}
+func (*DataSegment) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f4505e0ac3ae98d9, []int{46}
+}
+func (m *DataSegment) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *DataSegment) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_DataSegment.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	if m.W != 0 {
-		i -= 8
-		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.W))))
-		i--
-		dAtA[i] = 0x29
+}
+func (m *DataSegment) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DataSegment.Merge(m, src)
+}
+func (m *DataSegment) XXX_Size() int {
+	return m.Size()
+}
+func (m *DataSegment) XXX_DiscardUnknown() {
+	xxx_messageInfo_DataSegment.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DataSegment proto.InternalMessageInfo
+
+func (m *DataSegment) GetByteOfs() uint64 {
+	if m != nil {
+		return m.ByteOfs
 	}
-	if m.V != 0 {
-		i -= 8
-		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.V))))
-		i--
-		dAtA[i] = 0x21
+	return 0
+}
+
+func (m *DataSegment) GetByteSz() uint64 {
+	if m != nil {
+		return m.ByteSz
 	}
-	if m.U != 0 {
-		i -= 8
-		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.U))))
-		i--
-		dAtA[i] = 0x19
+	return 0
+}
+
+func (m *DataSegment) GetInlineData() []byte {
+	if m != nil {
+		return m.InlineData
 	}
-	if m.CordType != 0 {
-		i = encodeVarintApiAmp(dAtA, i, uint64(m.CordType))
-		i--
-		dAtA[i] = 0x8
+	return nil
+}
+
+func (m *DataSegment) GetStreamURI() string {
+	if m != nil {
+		return m.StreamURI
 	}
-	return len(dAtA) - i, nil
+	return ""
 }
 
-func (m *Tag) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+func (m *DataSegment) GetBlobID() int64 {
+	if m != nil {
+		return m.BlobID
 	}
-	return dAtA[:n], nil
+	return 0
 }
 
-func (m *Tag) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+// Err is a general purpose error / warning / log message.
+type Err struct {
+	// Identifies the type of error.
+	Code ErrCode `protobuf:"varint,1,opt,name=Code,proto3,enum=amp.ErrCode" json:"Code,omitempty"`
+	// Severity level
+	Level LogLevel `protobuf:"varint,2,opt,name=Level,proto3,enum=amp.LogLevel" json:"Level,omitempty"`
+	// human-readable info
+	Msg string `protobuf:"bytes,4,opt,name=Msg,proto3" json:"Msg,omitempty"`
+	// If set, the request that failed may succeed if issued again (e.g. after a timeout)
+	Retryable bool `protobuf:"varint,5,opt,name=Retryable,proto3" json:"Retryable,omitempty"`
+	// Key / value details of this error, for clients to branch on (in addition to Code)
+	Details []*ErrDetail `protobuf:"bytes,6,rep,name=Details,proto3" json:"Details,omitempty"`
 }
 
-func (m *Tag) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.Size_2 != 0 {
-		i -= 4
-		encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(m.Size_2))))
-		i--
-		dAtA[i] = 0x1
-		i--
-		dAtA[i] = 0xed
-	}
-	if m.Size_1 != 0 {
-		i -= 4
-		encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(m.Size_1))))
-		i--
-		dAtA[i] = 0x1
-		i--
-		dAtA[i] = 0xe5
-	}
-	if m.Size_0 != 0 {
-		i -= 4
-		encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(m.Size_0))))
-		i--
-		dAtA[i] = 0x1
-		i--
-		dAtA[i] = 0xdd
-	}
-	if m.Metric != 0 {
-		i = encodeVarintApiAmp(dAtA, i, uint64(m.Metric))
-		i--
-		dAtA[i] = 0x1
-		i--
-		dAtA[i] = 0xd0
-	}
-	if len(m.Attachment) > 0 {
-		i -= len(m.Attachment)
-		copy(dAtA[i:], m.Attachment)
-		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.Attachment)))
-		i--
-		dAtA[i] = 0x7a
-	}
-	if len(m.ContentType) > 0 {
-		i -= len(m.ContentType)
-		copy(dAtA[i:], m.ContentType)
-		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.ContentType)))
-		i--
-		dAtA[i] = 0x72
+func (m *Err) Reset()      { *m = Err{} }
+func (*Err) ProtoMessage() {}
+func (*Err) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f4505e0ac3ae98d9, []int{47}
+}
+func (m *Err) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Err) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Err.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	if len(m.URL) > 0 {
-		i -= len(m.URL)
-		copy(dAtA[i:], m.URL)
-		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.URL)))
-		i--
-		dAtA[i] = 0x6a
+}
+func (m *Err) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Err.Merge(m, src)
+}
+func (m *Err) XXX_Size() int {
+	return m.Size()
+}
+func (m *Err) XXX_DiscardUnknown() {
+	xxx_messageInfo_Err.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Err proto.InternalMessageInfo
+
+func (m *Err) GetCode() ErrCode {
+	if m != nil {
+		return m.Code
 	}
-	if m.TagID_2 != 0 {
-		i -= 8
-		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(m.TagID_2))
-		i--
-		dAtA[i] = 0x21
+	return ErrCode_NoErr
+}
+
+func (m *Err) GetLevel() LogLevel {
+	if m != nil {
+		return m.Level
 	}
-	if m.TagID_1 != 0 {
-		i -= 8
-		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(m.TagID_1))
-		i--
-		dAtA[i] = 0x19
+	return LogLevel_Error
+}
+
+func (m *Err) GetMsg() string {
+	if m != nil {
+		return m.Msg
 	}
-	if m.TagID_0 != 0 {
-		i = encodeVarintApiAmp(dAtA, i, uint64(m.TagID_0))
-		i--
-		dAtA[i] = 0x10
+	return ""
+}
+
+func (m *Err) GetRetryable() bool {
+	if m != nil {
+		return m.Retryable
 	}
-	if m.Use != 0 {
-		i = encodeVarintApiAmp(dAtA, i, uint64(m.Use))
-		i--
-		dAtA[i] = 0x8
+	return false
+}
+
+func (m *Err) GetDetails() []*ErrDetail {
+	if m != nil {
+		return m.Details
 	}
-	return len(dAtA) - i, nil
+	return nil
 }
 
-func (m *TagTab) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+// ErrDetail is a key / value detail of an Err, e.g. the path of a file not found.
+type ErrDetail struct {
+	// Identifies this detail, e.g. "path"
+	Key string `protobuf:"bytes,1,opt,name=Key,proto3" json:"Key,omitempty"`
+	// Value of this detail
+	Value string `protobuf:"bytes,2,opt,name=Value,proto3" json:"Value,omitempty"`
+}
+
+func (m *ErrDetail) Reset()      { *m = ErrDetail{} }
+func (*ErrDetail) ProtoMessage() {}
+func (*ErrDetail) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f4505e0ac3ae98d9, []int{48}
+}
+func (m *ErrDetail) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ErrDetail) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ErrDetail.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	return dAtA[:n], nil
+}
+func (m *ErrDetail) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ErrDetail.Merge(m, src)
+}
+func (m *ErrDetail) XXX_Size() int {
+	return m.Size()
+}
+func (m *ErrDetail) XXX_DiscardUnknown() {
+	xxx_messageInfo_ErrDetail.DiscardUnknown(m)
 }
 
-func (m *TagTab) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+var xxx_messageInfo_ErrDetail proto.InternalMessageInfo
+
+func (m *ErrDetail) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
 }
 
-func (m *TagTab) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.Tags) > 0 {
-		for iNdEx := len(m.Tags) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size, err := m.Tags[iNdEx].MarshalToSizedBuffer(dAtA[:i])
-				if err != nil {
-					return 0, err
-				}
-				i -= size
-				i = encodeVarintApiAmp(dAtA, i, uint64(size))
-			}
-			i--
-			dAtA[i] = 0x4a
-		}
-	}
-	if m.ModifiedAt != 0 {
-		i = encodeVarintApiAmp(dAtA, i, uint64(m.ModifiedAt))
-		i--
-		dAtA[i] = 0x38
-	}
-	if m.CreatedAt != 0 {
-		i = encodeVarintApiAmp(dAtA, i, uint64(m.CreatedAt))
-		i--
-		dAtA[i] = 0x30
-	}
-	if len(m.About) > 0 {
-		i -= len(m.About)
-		copy(dAtA[i:], m.About)
-		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.About)))
-		i--
-		dAtA[i] = 0x1a
-	}
-	if len(m.Caption) > 0 {
-		i -= len(m.Caption)
-		copy(dAtA[i:], m.Caption)
-		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.Caption)))
-		i--
-		dAtA[i] = 0x12
-	}
-	if len(m.Label) > 0 {
-		i -= len(m.Label)
-		copy(dAtA[i:], m.Label)
-		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.Label)))
-		i--
-		dAtA[i] = 0xa
+func (m *ErrDetail) GetValue() string {
+	if m != nil {
+		return m.Value
 	}
-	return len(dAtA) - i, nil
+	return ""
 }
 
-func (m *Ballot) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
-	}
-	return dAtA[:n], nil
+func init() {
+	proto.RegisterEnum("amp.Const", Const_name, Const_value)
+	proto.RegisterEnum("amp.TxOpCode", TxOpCode_name, TxOpCode_value)
+	proto.RegisterEnum("amp.TxField", TxField_name, TxField_value)
+	proto.RegisterEnum("amp.SelectOp", SelectOp_name, SelectOp_value)
+	proto.RegisterEnum("amp.OpStatus", OpStatus_name, OpStatus_value)
+	proto.RegisterEnum("amp.PinSync", PinSync_name, PinSync_value)
+	proto.RegisterEnum("amp.CordType", CordType_name, CordType_value)
+	proto.RegisterEnum("amp.Enable", Enable_name, Enable_value)
+	proto.RegisterEnum("amp.UrlScheme", UrlScheme_name, UrlScheme_value)
+	proto.RegisterEnum("amp.Metric", Metric_name, Metric_value)
+	proto.RegisterEnum("amp.TagUse", TagUse_name, TagUse_value)
+	proto.RegisterEnum("amp.CryptoKitID", CryptoKitID_name, CryptoKitID_value)
+	proto.RegisterEnum("amp.ErrCode", ErrCode_name, ErrCode_value)
+	proto.RegisterEnum("amp.LogLevel", LogLevel_name, LogLevel_value)
+	proto.RegisterEnum("amp.PlaybackState", PlaybackState_name, PlaybackState_value)
+	proto.RegisterEnum("amp.ColumnType", ColumnType_name, ColumnType_value)
+	proto.RegisterEnum("amp.TRS_VisualScaleMode", TRS_VisualScaleMode_name, TRS_VisualScaleMode_value)
+	proto.RegisterType((*TxInfo)(nil), "amp.TxInfo")
+	proto.RegisterType((*Login)(nil), "amp.Login")
+	proto.RegisterType((*LoginChallenge)(nil), "amp.LoginChallenge")
+	proto.RegisterType((*LoginResponse)(nil), "amp.LoginResponse")
+	proto.RegisterType((*AuthCheckpoint)(nil), "amp.AuthCheckpoint")
+	proto.RegisterType((*PinRequest)(nil), "amp.PinRequest")
+	proto.RegisterType((*PinWindow)(nil), "amp.PinWindow")
+	proto.RegisterType((*PrefetchHint)(nil), "amp.PrefetchHint")
+	proto.RegisterType((*PageInfo)(nil), "amp.PageInfo")
+	proto.RegisterType((*Progress)(nil), "amp.Progress")
+	proto.RegisterType((*SearchHit)(nil), "amp.SearchHit")
+	proto.RegisterType((*LWWRegister)(nil), "amp.LWWRegister")
+	proto.RegisterType((*ORSetEntry)(nil), "amp.ORSetEntry")
+	proto.RegisterType((*ORSet)(nil), "amp.ORSet")
+	proto.RegisterType((*RGAElem)(nil), "amp.RGAElem")
+	proto.RegisterType((*RGAList)(nil), "amp.RGAList")
+	proto.RegisterType((*MigrationRecord)(nil), "amp.MigrationRecord")
+	proto.RegisterType((*MigrationLog)(nil), "amp.MigrationLog")
+	proto.RegisterType((*PinStats)(nil), "amp.PinStats")
+	proto.RegisterType((*BlobChunk)(nil), "amp.BlobChunk")
+	proto.RegisterType((*MediaInfo)(nil), "amp.MediaInfo")
+	proto.RegisterType((*WaveformPeaks)(nil), "amp.WaveformPeaks")
+	proto.RegisterType((*PlaylistEntry)(nil), "amp.PlaylistEntry")
+	proto.RegisterType((*PlaybackEvent)(nil), "amp.PlaybackEvent")
+	proto.RegisterType((*TrackOffset)(nil), "amp.TrackOffset")
+	proto.RegisterType((*GeoPoint)(nil), "amp.GeoPoint")
+	proto.RegisterType((*TableColumn)(nil), "amp.TableColumn")
+	proto.RegisterType((*TableSortKey)(nil), "amp.TableSortKey")
+	proto.RegisterType((*TableHeader)(nil), "amp.TableHeader")
+	proto.RegisterType((*TableValue)(nil), "amp.TableValue")
+	proto.RegisterType((*TableRow)(nil), "amp.TableRow")
+	proto.RegisterType((*LaunchURL)(nil), "amp.LaunchURL")
+	proto.RegisterType((*Position)(nil), "amp.Position")
+	proto.RegisterType((*Tag)(nil), "amp.Tag")
+	proto.RegisterType((*TagTab)(nil), "amp.TagTab")
+	proto.RegisterType((*LocaleText)(nil), "amp.LocaleText")
+	proto.RegisterType((*Ballot)(nil), "amp.Ballot")
+	proto.RegisterType((*NotesEntry)(nil), "amp.NotesEntry")
+	proto.RegisterType((*ChatEntry)(nil), "amp.ChatEntry")
+	proto.RegisterType((*SpreadsheetEntry)(nil), "amp.SpreadsheetEntry")
+	proto.RegisterType((*SpatialPlacement)(nil), "amp.SpatialPlacement")
+	proto.RegisterType((*TagPlayableMedia)(nil), "amp.TagPlayableMedia")
+	proto.RegisterType((*TagPlaylist)(nil), "amp.TagPlaylist")
+	proto.RegisterType((*CryptoKey)(nil), "amp.CryptoKey")
+	proto.RegisterType((*AuthToken)(nil), "amp.AuthToken")
+	proto.RegisterType((*TRS)(nil), "amp.TRS")
+	proto.RegisterType((*DataSegment)(nil), "amp.DataSegment")
+	proto.RegisterType((*Err)(nil), "amp.Err")
+	proto.RegisterType((*ErrDetail)(nil), "amp.ErrDetail")
 }
 
-func (m *Ballot) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
-}
+func init() { proto.RegisterFile("amp/api.amp.proto", fileDescriptor_f4505e0ac3ae98d9) }
 
-func (m *Ballot) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	return len(dAtA) - i, nil
+var fileDescriptor_f4505e0ac3ae98d9 = []byte{
+	// 4264 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x5a, 0xcb, 0x73, 0x24, 0x47,
+	0x5a, 0x57, 0xf5, 0x43, 0x52, 0xa7, 0x5e, 0x39, 0x35, 0x9a, 0x99, 0x9a, 0xf1, 0x8c, 0xac, 0x28,
+	0x7b, 0x2d, 0x59, 0x60, 0xaf, 0xd4, 0xb2, 0x09, 0x20, 0x82, 0x05, 0x8d, 0x1e, 0x33, 0x5a, 0xeb,
+	0xd1, 0x9b, 0xdd, 0xf2, 0xd8, 0xe6, 0x21, 0x72, 0xba, 0x52, 0xdd, 0x89, 0xaa, 0xb3, 0xca, 0x55,
+	0xd9, 0x63, 0xc9, 0x17, 0x08, 0x22, 0x78, 0x2e, 0x2c, 0xcb, 0x6e, 0x00, 0x17, 0x16, 0x38, 0xc0,
+	0xb2, 0xeb, 0x0d, 0x22, 0x36, 0x82, 0x80, 0x13, 0x0b, 0x01, 0x5c, 0x36, 0x38, 0xf9, 0x42, 0xc4,
+	0x86, 0x0f, 0x04, 0x1e, 0x5f, 0x38, 0x00, 0xe1, 0x3f, 0x61, 0xe3, 0xfb, 0x32, 0xab, 0xba, 0xaa,
+	0x47, 0xbe, 0xf9, 0xa4, 0xfc, 0xfd, 0xbe, 0x7c, 0x7c, 0xf9, 0x65, 0x7e, 0x8f, 0xca, 0x16, 0xb9,
+	0xc6, 0x07, 0xf1, 0x17, 0x79, 0x2c, 0x5f, 0xe5, 0x83, 0xf8, 0xd5, 0x38, 0x89, 0x74, 0xe4, 0x56,
+	0xf9, 0x20, 0xf6, 0xbf, 0x5f, 0x25, 0x93, 0x9d, 0x8b, 0x7d, 0x75, 0x16, 0xb9, 0x5f, 0x20, 0x93,
+	0x6d, 0xcd, 0xf5, 0x30, 0xf5, 0x2a, 0xcb, 0xce, 0xea, 0x7c, 0x73, 0x0e, 0xfb, 0x1e, 0xc7, 0x86,
+	0x64, 0x56, 0xe8, 0xde, 0x24, 0x93, 0x47, 0xc3, 0xc1, 0x71, 0x9c, 0x7a, 0xb5, 0x65, 0x67, 0xb5,
+	0xc6, 0x2c, 0x72, 0x9f, 0x27, 0x33, 0x0f, 0x84, 0x12, 0xa9, 0x4c, 0xf7, 0x77, 0x4e, 0xd7, 0xbd,
+	0xfa, 0xb2, 0xb3, 0x5a, 0x65, 0x24, 0xa7, 0xd6, 0xcb, 0x1d, 0x36, 0xbc, 0xc9, 0x65, 0x67, 0x75,
+	0xb2, 0xd0, 0x61, 0xa3, 0xdc, 0xa1, 0xe9, 0x4d, 0x8d, 0x75, 0x68, 0x42, 0x07, 0x26, 0xde, 0x1d,
+	0x8a, 0x54, 0xe3, 0x12, 0xc4, 0x2c, 0x91, 0x53, 0xeb, 0xe5, 0x0e, 0x1b, 0xde, 0x8c, 0x99, 0x21,
+	0xa7, 0x36, 0xca, 0x1d, 0x9a, 0xde, 0xec, 0x58, 0x87, 0xa6, 0xbb, 0x42, 0x16, 0x58, 0x14, 0xe9,
+	0xdd, 0x50, 0x0c, 0x84, 0x32, 0xcb, 0xcc, 0xe1, 0x32, 0xf3, 0x25, 0x7a, 0xfd, 0xd9, 0x8e, 0x1b,
+	0xde, 0x3c, 0xce, 0x56, 0xee, 0xb8, 0xf1, 0x6c, 0xc7, 0xa6, 0xb7, 0x70, 0x45, 0xc7, 0xa6, 0xfb,
+	0x12, 0x99, 0xda, 0x4d, 0x92, 0xed, 0x28, 0x10, 0x5e, 0x15, 0x0f, 0x60, 0x16, 0x0f, 0xc0, 0x72,
+	0x2c, 0x13, 0xfa, 0xbf, 0x59, 0x21, 0xf5, 0x83, 0xa8, 0x27, 0x95, 0xeb, 0x91, 0xa9, 0x93, 0x54,
+	0x24, 0x27, 0xfb, 0x3b, 0x9e, 0xb3, 0xec, 0xac, 0x36, 0x58, 0x06, 0xdd, 0x3b, 0x64, 0xfa, 0x61,
+	0x94, 0xea, 0xad, 0x20, 0x48, 0xf0, 0x34, 0x1b, 0x2c, 0xc7, 0xee, 0x32, 0x99, 0xd9, 0x11, 0x4f,
+	0x64, 0x57, 0x1c, 0xf0, 0xc7, 0x22, 0xf4, 0xa6, 0x51, 0x5c, 0xa4, 0xdc, 0xbb, 0xa4, 0x61, 0x20,
+	0xcc, 0xdc, 0x40, 0xf9, 0x88, 0x70, 0x37, 0x09, 0xd9, 0xee, 0x8b, 0xee, 0x79, 0x1c, 0x49, 0xa5,
+	0xf1, 0x10, 0x66, 0x9a, 0xd7, 0x51, 0xd5, 0xad, 0xa1, 0xee, 0x8f, 0x44, 0xac, 0xd0, 0xcd, 0x5d,
+	0x24, 0xf5, 0x76, 0xcc, 0xbb, 0x02, 0xcf, 0xa4, 0xc1, 0x0c, 0x70, 0x97, 0x08, 0x39, 0x14, 0x81,
+	0xe4, 0x9d, 0xcb, 0x58, 0xa4, 0xde, 0xec, 0x72, 0x75, 0xb5, 0xc1, 0x0a, 0x0c, 0x6c, 0xf0, 0x20,
+	0xea, 0xf2, 0x50, 0xa4, 0xde, 0x1c, 0x0a, 0x33, 0xe8, 0xbf, 0x48, 0xe6, 0xd1, 0x06, 0xdb, 0x7d,
+	0x1e, 0x86, 0x42, 0xf5, 0x84, 0xeb, 0x92, 0xda, 0x43, 0x9e, 0xf6, 0xd1, 0x12, 0xb3, 0x0c, 0xdb,
+	0xfe, 0x26, 0x99, 0xc3, 0x5e, 0x4c, 0xa4, 0x71, 0xa4, 0x52, 0xe1, 0xfa, 0x64, 0x16, 0x04, 0x19,
+	0xb6, 0x9d, 0x4b, 0x9c, 0xff, 0x0d, 0x87, 0xcc, 0x97, 0x77, 0x02, 0xda, 0x77, 0xa2, 0x73, 0xa1,
+	0xac, 0x99, 0x0d, 0x70, 0x7d, 0x32, 0xd5, 0x16, 0x69, 0x2a, 0x23, 0x65, 0xad, 0x30, 0x8d, 0x56,
+	0xe8, 0xf0, 0x1e, 0xcb, 0x04, 0xee, 0x32, 0x99, 0x3c, 0x14, 0x83, 0xc7, 0x22, 0xf1, 0x66, 0xc6,
+	0xba, 0x58, 0xde, 0x7d, 0x11, 0x8e, 0x6a, 0x20, 0xf6, 0x84, 0x08, 0xbc, 0xc6, 0x58, 0x9f, 0x5c,
+	0xe2, 0x7f, 0xab, 0x42, 0x48, 0x4b, 0x2a, 0x7b, 0x53, 0xdd, 0x97, 0x48, 0xa3, 0x25, 0x55, 0x87,
+	0x27, 0x3d, 0xa1, 0xbd, 0xca, 0xd8, 0xa8, 0x91, 0x08, 0x26, 0x6f, 0x49, 0xb5, 0xa5, 0x75, 0x02,
+	0xee, 0x5a, 0x2d, 0x4f, 0x9e, 0x49, 0xe0, 0xe6, 0xb5, 0xa4, 0x6a, 0x5f, 0xaa, 0xae, 0x37, 0x59,
+	0xb8, 0x79, 0x96, 0x63, 0x99, 0xd0, 0xfd, 0x49, 0x5c, 0xf5, 0x91, 0x54, 0x41, 0xf4, 0x1e, 0xde,
+	0x9b, 0x99, 0xe6, 0x7c, 0xd6, 0xd3, 0xb0, 0x6c, 0xd4, 0x01, 0x6e, 0x51, 0x4b, 0xaa, 0x3d, 0x19,
+	0x6a, 0x91, 0xa0, 0x81, 0x1a, 0x6c, 0x44, 0xb8, 0xaf, 0x90, 0xe9, 0x56, 0x22, 0xce, 0x84, 0xee,
+	0xf6, 0xd1, 0x0d, 0x67, 0x9a, 0xd7, 0xcc, 0x54, 0x96, 0x7c, 0x08, 0x37, 0x28, 0xef, 0x02, 0x37,
+	0xa5, 0x2d, 0x54, 0x60, 0xd7, 0x9e, 0x37, 0x9e, 0x3f, 0x62, 0xfc, 0xaf, 0x14, 0x54, 0x83, 0x10,
+	0x75, 0x7c, 0x76, 0x96, 0x0a, 0x8d, 0xe7, 0x55, 0x65, 0x16, 0xc1, 0x31, 0x1e, 0xc8, 0x81, 0x34,
+	0x16, 0xab, 0x32, 0x03, 0xa0, 0xf7, 0xf6, 0x30, 0x49, 0xa3, 0x04, 0xdd, 0xae, 0xc1, 0x2c, 0xf2,
+	0xbf, 0x4c, 0x66, 0x8b, 0xca, 0x80, 0x4f, 0x6d, 0xf7, 0x65, 0x18, 0x24, 0xf6, 0x1e, 0x54, 0x59,
+	0x8e, 0xdd, 0x25, 0x52, 0x37, 0x46, 0xae, 0x8c, 0x19, 0xd9, 0xd0, 0xfe, 0x5f, 0x3b, 0x64, 0xba,
+	0xc5, 0x7b, 0x02, 0x03, 0x2d, 0xde, 0x26, 0xcd, 0x43, 0x3b, 0x8b, 0x01, 0x05, 0xa5, 0x2b, 0xe3,
+	0x4a, 0x6f, 0x47, 0x43, 0xa5, 0x51, 0xbb, 0x2a, 0x33, 0x00, 0xec, 0x71, 0x24, 0x2e, 0xb4, 0x55,
+	0xbc, 0x86, 0x8a, 0x17, 0x18, 0x90, 0xb7, 0x12, 0xf1, 0xc4, 0xca, 0xeb, 0x46, 0x3e, 0x62, 0x60,
+	0xd6, 0xdd, 0x38, 0xea, 0xf6, 0xf1, 0xc0, 0x6b, 0xcc, 0x00, 0xff, 0x0c, 0x0e, 0x25, 0xea, 0x25,
+	0x22, 0x4d, 0x61, 0xbb, 0x7b, 0x09, 0xef, 0x6a, 0xb8, 0xde, 0xa0, 0x68, 0x85, 0xe5, 0x18, 0xbd,
+	0x59, 0xf3, 0x9e, 0xb0, 0xb1, 0xc5, 0x00, 0xf0, 0xc0, 0x9d, 0x48, 0x09, 0xab, 0x28, 0xb6, 0x47,
+	0x7b, 0xad, 0x15, 0xf6, 0xea, 0xbf, 0x4e, 0x1a, 0x6d, 0xc1, 0x13, 0x30, 0xac, 0x86, 0x61, 0x8c,
+	0xab, 0x73, 0x6b, 0x0d, 0x6c, 0xe3, 0x02, 0xdd, 0x28, 0x31, 0x0b, 0x54, 0x98, 0x01, 0xfe, 0x57,
+	0xc8, 0xcc, 0xc1, 0xa3, 0x47, 0x4c, 0xf4, 0x64, 0x0a, 0x57, 0x68, 0x91, 0xd4, 0xdf, 0xe4, 0xe1,
+	0x30, 0xf3, 0x62, 0x03, 0x60, 0xba, 0x8e, 0x1c, 0x08, 0x6b, 0x45, 0x6c, 0x43, 0x1c, 0x61, 0x22,
+	0x0e, 0x65, 0x97, 0xa3, 0x72, 0x35, 0x96, 0x41, 0xbf, 0x45, 0xc8, 0x31, 0x6b, 0x0b, 0xbd, 0xab,
+	0x74, 0x72, 0xf9, 0xb9, 0xcc, 0xf8, 0x88, 0xd4, 0x71, 0x46, 0xf7, 0x05, 0x52, 0xdb, 0x0a, 0x82,
+	0xd4, 0x73, 0xf0, 0x4a, 0x2c, 0x98, 0x6c, 0x9a, 0xaf, 0xc5, 0x50, 0xe8, 0xbe, 0x0c, 0xf3, 0x0c,
+	0xa2, 0x27, 0x22, 0xbb, 0x3a, 0xcf, 0xf4, 0xcb, 0xe4, 0xfe, 0x77, 0x1d, 0x32, 0xc5, 0x1e, 0x6c,
+	0x41, 0xc6, 0xf8, 0x3c, 0x14, 0x05, 0xff, 0xdc, 0x3a, 0xd3, 0x22, 0xc1, 0x21, 0xe6, 0x78, 0x46,
+	0x04, 0x44, 0x4a, 0x04, 0xd9, 0xe0, 0x3a, 0x0e, 0x2e, 0x71, 0x66, 0x6e, 0x50, 0x2e, 0xc0, 0x6b,
+	0x34, 0x9d, 0xe9, 0x1a, 0xf8, 0xaf, 0xa0, 0xaa, 0x07, 0x32, 0xd5, 0xae, 0x4f, 0xea, 0xa0, 0x72,
+	0x66, 0x07, 0x13, 0x5a, 0xec, 0x3e, 0x98, 0x11, 0xf9, 0xbf, 0x4c, 0x16, 0x0e, 0x65, 0x2f, 0xe1,
+	0x70, 0xb9, 0x98, 0xe8, 0x46, 0x49, 0x00, 0x73, 0xbf, 0x29, 0x92, 0x34, 0xbb, 0x7d, 0x35, 0x96,
+	0x41, 0xd4, 0x3b, 0x8e, 0x43, 0x29, 0x82, 0xad, 0xcc, 0x57, 0x46, 0x04, 0x5e, 0x42, 0x91, 0x76,
+	0xad, 0x2f, 0x63, 0xdb, 0xff, 0x12, 0x99, 0xcd, 0xa7, 0x3f, 0x88, 0x7a, 0xee, 0xab, 0x64, 0xca,
+	0x0e, 0xb0, 0x4a, 0x2d, 0xa2, 0x52, 0x63, 0x2a, 0xb0, 0xac, 0x93, 0xff, 0xb5, 0x0a, 0x86, 0x51,
+	0x28, 0x80, 0x52, 0x30, 0x3d, 0x13, 0xef, 0xe6, 0x29, 0xd7, 0x00, 0x97, 0x92, 0xea, 0x56, 0x1c,
+	0x5b, 0x7f, 0x80, 0x26, 0xf8, 0xb3, 0x8d, 0xcf, 0x36, 0xac, 0x18, 0x04, 0x7e, 0x75, 0x1c, 0x0b,
+	0x85, 0xda, 0x1b, 0xab, 0xe7, 0xd8, 0x7d, 0x91, 0xcc, 0xed, 0xc9, 0x24, 0xd5, 0x9d, 0x8b, 0x43,
+	0xd9, 0x4d, 0xa2, 0xd4, 0x56, 0x51, 0x65, 0x12, 0x67, 0xbe, 0x48, 0x8f, 0x87, 0x1a, 0xad, 0x5e,
+	0x65, 0x16, 0xc1, 0xcc, 0xf7, 0x2f, 0xb5, 0x40, 0xc9, 0x94, 0x99, 0x39, 0xc3, 0xe8, 0x87, 0x17,
+	0xe9, 0xbe, 0xf2, 0xa6, 0xad, 0x1f, 0x02, 0x80, 0x11, 0x07, 0x1c, 0x66, 0xde, 0xd2, 0x98, 0x7b,
+	0xaa, 0x2c, 0xc7, 0x20, 0xdb, 0x0e, 0xa3, 0x14, 0xf5, 0x34, 0x95, 0x56, 0x8e, 0xfd, 0x7f, 0x75,
+	0x48, 0xe3, 0x7e, 0x18, 0x3d, 0xde, 0xee, 0x0f, 0xd5, 0x39, 0xe8, 0x03, 0xc0, 0x9a, 0xa4, 0xc6,
+	0x2c, 0xfa, 0xcc, 0x88, 0x76, 0x97, 0x34, 0x30, 0x0c, 0xb4, 0xe5, 0xfb, 0x59, 0xb0, 0x18, 0x11,
+	0xa0, 0xe9, 0x9e, 0x54, 0x36, 0x62, 0x4c, 0x33, 0x03, 0x50, 0x1b, 0xae, 0xba, 0x22, 0x14, 0x01,
+	0x1a, 0x65, 0x9a, 0xe5, 0x18, 0x0a, 0x9a, 0xed, 0x48, 0x69, 0xa1, 0x34, 0x54, 0x0d, 0x68, 0x94,
+	0x06, 0x2b, 0x52, 0x78, 0x29, 0xb8, 0xe6, 0x68, 0x95, 0x59, 0x86, 0x6d, 0xff, 0xcf, 0x26, 0x49,
+	0x03, 0x4b, 0x0d, 0x8c, 0xc9, 0x63, 0x73, 0x38, 0xcf, 0xce, 0x01, 0x16, 0x94, 0x3a, 0xcc, 0x63,
+	0x1e, 0x02, 0xd8, 0xe3, 0x56, 0xa2, 0x65, 0x9a, 0x9f, 0xb2, 0x41, 0xd0, 0x7b, 0x2b, 0x7c, 0x3c,
+	0x1c, 0xd8, 0xd0, 0x6c, 0x00, 0xac, 0x82, 0x0d, 0x3b, 0xc4, 0x84, 0xe5, 0x22, 0x85, 0xfb, 0x8c,
+	0x06, 0x71, 0x94, 0x8a, 0xc4, 0x6e, 0x24, 0xc7, 0x30, 0xe7, 0x03, 0xa1, 0x12, 0x81, 0xdb, 0x68,
+	0x30, 0x03, 0xc0, 0x51, 0xb6, 0xa3, 0x01, 0x14, 0x91, 0xb6, 0x94, 0xcb, 0x20, 0xec, 0xfa, 0x6d,
+	0xc1, 0x13, 0x3c, 0xd9, 0x3a, 0xc3, 0x36, 0xcc, 0xdf, 0x49, 0x78, 0xf7, 0xfc, 0x68, 0x38, 0xc0,
+	0x53, 0xad, 0xb3, 0x1c, 0x43, 0xce, 0xc0, 0xb6, 0x49, 0x37, 0x33, 0x28, 0x2d, 0x30, 0xb0, 0xd2,
+	0x8e, 0x4c, 0xbb, 0x30, 0x74, 0x16, 0x85, 0x19, 0xc4, 0x82, 0x51, 0xa6, 0x5d, 0x33, 0x70, 0x0e,
+	0x65, 0x23, 0x02, 0xe6, 0xdd, 0x19, 0x1a, 0xcf, 0x3a, 0x4c, 0xb3, 0xdc, 0x3d, 0x62, 0x30, 0xb7,
+	0xf3, 0x41, 0x1c, 0x0a, 0xc6, 0xb5, 0xc0, 0xe2, 0xb8, 0xce, 0x0a, 0x8c, 0x49, 0xbc, 0x5c, 0x29,
+	0x11, 0xa6, 0x1e, 0x35, 0x3a, 0x67, 0x18, 0x6c, 0xf2, 0x48, 0x06, 0xba, 0xef, 0x5d, 0x43, 0x81,
+	0x01, 0x70, 0x2a, 0x0f, 0x85, 0xec, 0xf5, 0xb5, 0xe7, 0x22, 0x6d, 0x11, 0xd8, 0xff, 0x38, 0x91,
+	0x42, 0x69, 0x5c, 0xda, 0xbb, 0x8e, 0xc2, 0x22, 0x05, 0xba, 0x6c, 0xf3, 0x81, 0x48, 0xf8, 0x21,
+	0x3f, 0x17, 0xde, 0xa2, 0xc9, 0x9b, 0x23, 0x06, 0xef, 0x89, 0x41, 0x51, 0x20, 0x42, 0xef, 0x86,
+	0xbd, 0x27, 0x23, 0x0a, 0xac, 0xd4, 0xe1, 0xe7, 0x42, 0x6d, 0x69, 0xef, 0x26, 0x6e, 0x35, 0x83,
+	0x30, 0xf6, 0x21, 0x4f, 0xa1, 0x82, 0xc5, 0xd5, 0x6f, 0xe1, 0x35, 0x2e, 0x52, 0xc6, 0x1f, 0xb5,
+	0xd4, 0xc3, 0x40, 0x78, 0xde, 0xb2, 0xb3, 0xea, 0xb0, 0x1c, 0x83, 0x8d, 0x0f, 0x22, 0xd5, 0x33,
+	0xc2, 0xdb, 0x28, 0x1c, 0x11, 0x10, 0xae, 0x77, 0x55, 0x37, 0x0a, 0x44, 0xb2, 0x23, 0x42, 0x7e,
+	0xe9, 0xdd, 0xc1, 0xad, 0x95, 0x38, 0xf7, 0x25, 0x32, 0x6f, 0x71, 0x8b, 0x07, 0x81, 0x54, 0x3d,
+	0xef, 0x39, 0xec, 0x35, 0xc6, 0xfa, 0xbb, 0x64, 0xee, 0x11, 0x7f, 0x22, 0xce, 0xa2, 0x64, 0xd0,
+	0x12, 0xfc, 0x3c, 0x1d, 0x3b, 0x40, 0xe7, 0x99, 0x03, 0x5c, 0x24, 0x75, 0xec, 0x88, 0xae, 0x31,
+	0xcb, 0x0c, 0xf0, 0xbf, 0xe7, 0x90, 0xb9, 0x56, 0xc8, 0x2f, 0x43, 0x99, 0xda, 0xf4, 0x0a, 0xdb,
+	0xcb, 0x76, 0x6f, 0x3c, 0x2c, 0xc7, 0x9f, 0x8b, 0x7b, 0x95, 0xf5, 0xac, 0x3f, 0xa3, 0xe7, 0x1d,
+	0x32, 0xcd, 0x44, 0x1a, 0x85, 0x59, 0xc2, 0x6a, 0xb0, 0x1c, 0xfb, 0xd2, 0x28, 0xfb, 0x98, 0x77,
+	0xcf, 0x77, 0x9f, 0x80, 0xf7, 0xac, 0x62, 0x8d, 0xa3, 0x4d, 0x2c, 0x98, 0x6f, 0xba, 0xa6, 0x3a,
+	0xb5, 0x5d, 0x50, 0xc2, 0x4c, 0x07, 0xac, 0xb5, 0xa2, 0x54, 0xda, 0x65, 0x4d, 0xac, 0x2b, 0x30,
+	0xee, 0x3c, 0xa9, 0x6c, 0x65, 0xe5, 0x5b, 0x65, 0x4b, 0xfb, 0x5d, 0x32, 0x83, 0x5e, 0x65, 0xc3,
+	0xa1, 0x47, 0xa6, 0xda, 0x9a, 0x27, 0x3a, 0x37, 0x6d, 0x06, 0xc7, 0xf6, 0x53, 0xb9, 0x6a, 0x3f,
+	0xad, 0x44, 0xf4, 0x78, 0x7c, 0x98, 0xda, 0xe9, 0x73, 0xec, 0xff, 0x02, 0x99, 0x7e, 0x20, 0xa2,
+	0x16, 0x7e, 0xbe, 0x50, 0x52, 0x3d, 0xe0, 0xa6, 0x18, 0x76, 0x18, 0x34, 0x91, 0x89, 0x94, 0x57,
+	0xb1, 0x4c, 0xa4, 0x30, 0x81, 0x85, 0x46, 0x4b, 0x87, 0x41, 0xd3, 0x8f, 0xc9, 0x4c, 0x87, 0x3f,
+	0x0e, 0xc5, 0x76, 0x14, 0x0e, 0x07, 0x0a, 0xa2, 0xc9, 0x11, 0x1f, 0x64, 0xa1, 0x11, 0xdb, 0x58,
+	0x50, 0xe3, 0x47, 0xa4, 0x3d, 0x34, 0x04, 0x50, 0xf8, 0x60, 0x10, 0x35, 0x5f, 0xb1, 0xa6, 0xa0,
+	0x31, 0x93, 0x00, 0xcd, 0x6a, 0x59, 0x48, 0x3e, 0x51, 0x52, 0xdb, 0x03, 0xc4, 0xb6, 0xff, 0xb3,
+	0x64, 0x16, 0x57, 0x6c, 0x47, 0x89, 0x7e, 0x43, 0x5c, 0x62, 0x65, 0x8e, 0xe3, 0xec, 0xa2, 0x93,
+	0x23, 0x55, 0x30, 0xc7, 0x57, 0xd0, 0x83, 0xb0, 0xed, 0xff, 0xaa, 0xd5, 0xf6, 0xa1, 0xe0, 0x81,
+	0x48, 0xdc, 0x35, 0x32, 0x65, 0x3a, 0x67, 0x75, 0x07, 0xb5, 0x25, 0x79, 0xbe, 0x21, 0x96, 0x75,
+	0x70, 0xbf, 0x40, 0x6a, 0xb0, 0xa2, 0x2d, 0xc0, 0xae, 0x8d, 0x3a, 0x5a, 0x3d, 0x18, 0x8a, 0xfd,
+	0xdf, 0x76, 0x08, 0x41, 0x3a, 0x2f, 0xb6, 0x8e, 0x86, 0xa1, 0x29, 0xe2, 0xa7, 0x19, 0xb6, 0x81,
+	0xeb, 0x88, 0x0b, 0x6d, 0xcd, 0x81, 0x6d, 0x30, 0xec, 0x7e, 0x5e, 0xbd, 0x43, 0x13, 0x33, 0x5c,
+	0x18, 0x71, 0xb3, 0x77, 0x87, 0x19, 0x00, 0x63, 0xef, 0x47, 0x51, 0x68, 0xb3, 0x1b, 0xb6, 0xa1,
+	0x27, 0x66, 0x70, 0xbc, 0xad, 0xb3, 0xcc, 0x00, 0x7f, 0x93, 0x4c, 0xa3, 0x1e, 0x2c, 0x7a, 0xcf,
+	0x5d, 0x21, 0x93, 0xa8, 0x4e, 0xb9, 0xcc, 0x1c, 0xa9, 0xc9, 0xac, 0xd8, 0xbf, 0x47, 0x1a, 0x07,
+	0x7c, 0xa8, 0xba, 0xfd, 0x13, 0x76, 0x00, 0x3a, 0x9d, 0xb0, 0x03, 0x6b, 0x55, 0x68, 0xfa, 0xef,
+	0x92, 0xe9, 0xec, 0xc6, 0xba, 0x2f, 0x43, 0x0e, 0x4a, 0x82, 0x3c, 0x11, 0x66, 0x4f, 0x41, 0x19,
+	0xc9, 0x72, 0xb1, 0x3b, 0x4b, 0x9c, 0x13, 0x7b, 0x67, 0x9c, 0x13, 0x40, 0x6f, 0xda, 0x4d, 0x39,
+	0x6f, 0x02, 0x7a, 0x84, 0xbb, 0x71, 0x98, 0xf3, 0x08, 0x96, 0x64, 0xc7, 0x27, 0xb8, 0x91, 0x0a,
+	0x83, 0xa6, 0xff, 0x77, 0x15, 0x52, 0xed, 0xf0, 0x9e, 0x7b, 0x8f, 0x54, 0x4f, 0xd2, 0x6c, 0xa5,
+	0x99, 0xec, 0xcb, 0xe9, 0x24, 0x15, 0x0c, 0x78, 0xf7, 0x16, 0xc4, 0xd3, 0x1e, 0xbe, 0xc4, 0xd8,
+	0x32, 0x02, 0xe1, 0xfa, 0x48, 0xb0, 0x81, 0x1a, 0x4c, 0x5a, 0xc1, 0xc6, 0x48, 0xd0, 0xf4, 0x6a,
+	0x05, 0x41, 0x33, 0xdb, 0xf6, 0x5c, 0xbe, 0xed, 0xf1, 0xb4, 0x3f, 0xff, 0x6c, 0xda, 0x5f, 0x22,
+	0x64, 0x4b, 0x6b, 0xde, 0xed, 0x63, 0x86, 0x5d, 0xc0, 0x73, 0x28, 0x30, 0xee, 0x0b, 0xf0, 0x81,
+	0xaf, 0x13, 0xd9, 0xf5, 0xee, 0x14, 0x36, 0x60, 0x28, 0x66, 0x45, 0xee, 0x0d, 0x32, 0x09, 0xb5,
+	0xcd, 0xe9, 0xba, 0xf7, 0x9c, 0xfd, 0x9e, 0x91, 0xef, 0x8b, 0xf5, 0x9c, 0xde, 0xf0, 0xee, 0x8e,
+	0xe8, 0x8d, 0x9c, 0x6e, 0x7a, 0xf7, 0x46, 0x74, 0xd3, 0xff, 0x4f, 0x07, 0x2a, 0xca, 0x5e, 0x87,
+	0x3f, 0x1e, 0xf9, 0x9d, 0x53, 0xf4, 0x3b, 0xa8, 0x04, 0x78, 0x8c, 0xd1, 0xb5, 0x62, 0x2b, 0x01,
+	0x03, 0x31, 0x5c, 0x3e, 0x8e, 0x86, 0x59, 0x14, 0x35, 0x00, 0x32, 0xca, 0x76, 0x22, 0xb8, 0xc6,
+	0x12, 0xcf, 0x94, 0x92, 0x23, 0x02, 0xdf, 0x66, 0xa2, 0x40, 0x9e, 0x99, 0x3a, 0xdb, 0xd4, 0x93,
+	0x05, 0xc6, 0xbd, 0x4b, 0x6a, 0x1d, 0xde, 0x4b, 0xbd, 0xc6, 0xd8, 0x17, 0x2f, 0xb2, 0xf0, 0x5d,
+	0x93, 0xbd, 0xdc, 0x90, 0xc2, 0xc5, 0x34, 0x1c, 0xf8, 0xc5, 0xe8, 0x29, 0xe7, 0xd7, 0x08, 0x19,
+	0xd1, 0xe0, 0xf3, 0x06, 0x65, 0x3e, 0x6f, 0xd0, 0x67, 0x84, 0x9a, 0xc2, 0x96, 0xab, 0x9f, 0xb1,
+	0xe5, 0x5a, 0x61, 0xcb, 0xfe, 0x34, 0x99, 0xbc, 0xcf, 0xc3, 0x30, 0xd2, 0xfe, 0x2c, 0x21, 0x47,
+	0x91, 0x16, 0x29, 0x66, 0x26, 0x7f, 0x86, 0x34, 0xb6, 0xfb, 0xdc, 0xa4, 0x29, 0xdf, 0x25, 0xb4,
+	0x1d, 0x27, 0x82, 0x07, 0x69, 0x5f, 0xd8, 0xaf, 0x30, 0xff, 0xbf, 0x1c, 0x20, 0xb9, 0x96, 0x3c,
+	0x6c, 0x85, 0xbc, 0x2b, 0xb2, 0x02, 0xab, 0x15, 0xa5, 0xeb, 0x36, 0xb0, 0x62, 0xdb, 0x72, 0x1b,
+	0x36, 0xb4, 0x62, 0xdb, 0x72, 0x4d, 0xeb, 0x28, 0xd8, 0x86, 0x7d, 0xb6, 0x61, 0x63, 0xeb, 0xa8,
+	0x60, 0x85, 0x59, 0x94, 0xf3, 0x1b, 0x5e, 0xbd, 0xc0, 0x6f, 0xe4, 0x7c, 0xd3, 0xba, 0x90, 0x45,
+	0xc0, 0xef, 0x0e, 0x43, 0x91, 0xbc, 0x85, 0x47, 0x54, 0x61, 0x16, 0xe5, 0xfc, 0xdb, 0xde, 0x74,
+	0x81, 0x7f, 0x3b, 0xe7, 0xdf, 0xf1, 0x1a, 0x05, 0xfe, 0x1d, 0xd8, 0x74, 0x87, 0xf7, 0x20, 0xbf,
+	0x41, 0xec, 0xc0, 0xc2, 0xd8, 0x9f, 0x23, 0x33, 0x96, 0x83, 0x1c, 0xee, 0xff, 0x22, 0xdc, 0x97,
+	0xcb, 0x58, 0x47, 0x10, 0x9b, 0x9b, 0x64, 0xc6, 0x02, 0xa9, 0x6d, 0xe5, 0x3f, 0x6f, 0x83, 0x6c,
+	0x81, 0x67, 0xc5, 0x4e, 0x90, 0xaf, 0xde, 0x10, 0x97, 0x26, 0xa2, 0xd5, 0xd0, 0x93, 0x72, 0xec,
+	0xff, 0x8e, 0x43, 0x1a, 0xf0, 0xea, 0x66, 0x9e, 0xd6, 0xa0, 0x50, 0xee, 0x76, 0x45, 0x9a, 0x16,
+	0x9f, 0xdd, 0x8a, 0x94, 0xf9, 0x88, 0x38, 0x17, 0x98, 0x52, 0xec, 0x9d, 0x18, 0x11, 0x50, 0x0e,
+	0x31, 0x71, 0x96, 0x88, 0xd4, 0xcc, 0x67, 0x2f, 0x47, 0x89, 0x43, 0x4b, 0x5c, 0xc4, 0x32, 0xb9,
+	0xb4, 0x9f, 0x61, 0x16, 0xf9, 0xff, 0x00, 0x71, 0x89, 0xb5, 0x21, 0x6d, 0xbf, 0xb5, 0xe1, 0xbd,
+	0x8c, 0x67, 0x56, 0x79, 0x6b, 0x03, 0x71, 0xd3, 0x5b, 0xb3, 0xb8, 0x89, 0x78, 0xd3, 0xfb, 0x09,
+	0x8b, 0x37, 0xdd, 0x9f, 0x22, 0x0d, 0x3c, 0x13, 0x28, 0x03, 0xbd, 0x26, 0xda, 0xc3, 0x33, 0x5e,
+	0xc1, 0xda, 0xaf, 0xbe, 0x29, 0xd3, 0x21, 0x0f, 0x73, 0x39, 0x1b, 0x75, 0x2d, 0x9c, 0xf8, 0xe6,
+	0x67, 0x9c, 0xf8, 0x6b, 0xe3, 0x27, 0x8e, 0xad, 0x4d, 0xef, 0xf5, 0x02, 0xbf, 0x89, 0x5f, 0xe3,
+	0x11, 0x14, 0x24, 0x1b, 0xde, 0xcf, 0xa1, 0x20, 0x83, 0x23, 0x49, 0xd3, 0xfb, 0x52, 0x51, 0xd2,
+	0x1c, 0x49, 0x36, 0xbd, 0x9f, 0x2f, 0x4a, 0x36, 0xfd, 0x75, 0xb2, 0x30, 0xa6, 0xb3, 0x3b, 0x87,
+	0x27, 0x14, 0x21, 0x41, 0x27, 0xdc, 0x79, 0x42, 0xf6, 0xe4, 0x85, 0x08, 0x0c, 0x76, 0xfc, 0x3f,
+	0x71, 0xc8, 0x0c, 0x7c, 0x59, 0xb5, 0x45, 0x0f, 0xbd, 0xc3, 0x23, 0x53, 0x70, 0xb4, 0xc7, 0x67,
+	0xa9, 0x7d, 0x3c, 0xc8, 0x20, 0x7e, 0x30, 0x5e, 0x6a, 0xd1, 0x7e, 0xdf, 0xbe, 0x3e, 0x59, 0x04,
+	0x21, 0x67, 0x5f, 0x85, 0x52, 0x89, 0xc2, 0xc7, 0x5a, 0x81, 0x81, 0x33, 0x6f, 0xeb, 0x44, 0xf0,
+	0xc1, 0x09, 0xdb, 0xcf, 0xde, 0xa5, 0x73, 0xa2, 0xf0, 0x19, 0x6a, 0x3e, 0x57, 0x2d, 0xf2, 0xbf,
+	0xed, 0x90, 0xea, 0x6e, 0x02, 0xef, 0xde, 0x35, 0x7c, 0x5c, 0x77, 0xae, 0x78, 0x5c, 0x47, 0x89,
+	0xfb, 0x02, 0xa9, 0x1f, 0x88, 0x27, 0x36, 0xc6, 0x64, 0x59, 0xef, 0x20, 0xea, 0x21, 0xc9, 0x8c,
+	0x0c, 0x92, 0xc8, 0x61, 0xda, 0xb3, 0x61, 0x05, 0x9a, 0xa0, 0x16, 0x13, 0x3a, 0x41, 0xc7, 0xb1,
+	0xe9, 0x7b, 0x44, 0xb8, 0xab, 0x64, 0x6a, 0x47, 0x68, 0x2e, 0x43, 0xc8, 0xe2, 0xd5, 0xfc, 0xc9,
+	0x74, 0x37, 0x49, 0x0c, 0xcd, 0x32, 0xb1, 0xbf, 0x49, 0x1a, 0x39, 0x0b, 0xcb, 0xbc, 0x21, 0x2e,
+	0xb3, 0x14, 0x0d, 0x1e, 0x97, 0xbf, 0xf9, 0xd8, 0x08, 0x88, 0x60, 0xed, 0x43, 0x07, 0xde, 0x07,
+	0x55, 0xaa, 0xe1, 0x3c, 0xb0, 0x71, 0xba, 0x23, 0xce, 0x52, 0x3a, 0xe1, 0xde, 0x24, 0xae, 0xc1,
+	0x9d, 0xfd, 0x9d, 0xfb, 0x52, 0xf1, 0xe4, 0xf2, 0x40, 0x28, 0xba, 0x5c, 0xe2, 0xdb, 0x3a, 0x91,
+	0xaa, 0x07, 0xfc, 0x6b, 0xee, 0x3d, 0xe2, 0xe5, 0xe3, 0xf9, 0x30, 0xd4, 0x6d, 0x91, 0xc0, 0x93,
+	0x7f, 0x2b, 0x4a, 0x34, 0xfd, 0xe1, 0xaa, 0x7b, 0x8b, 0x5c, 0xb7, 0xc3, 0x2e, 0x4c, 0x8d, 0x75,
+	0x0a, 0x69, 0x89, 0x52, 0xf7, 0x0e, 0xb9, 0x39, 0x26, 0xb0, 0x2f, 0x35, 0x74, 0xd3, 0xbd, 0x4b,
+	0x6e, 0x8c, 0xc9, 0x0e, 0x79, 0x72, 0x2e, 0x12, 0xfa, 0xe9, 0x47, 0xbf, 0x55, 0x75, 0x6f, 0x10,
+	0x6a, 0xa4, 0xfb, 0xea, 0x89, 0xfd, 0x0e, 0xa0, 0x3f, 0xb8, 0xb7, 0xf6, 0x89, 0x43, 0xa6, 0x3b,
+	0x17, 0xc7, 0x31, 0x9e, 0x09, 0x25, 0xb3, 0x59, 0xfb, 0xf4, 0x48, 0x86, 0x74, 0xc2, 0xbd, 0x41,
+	0xae, 0xe5, 0xcc, 0xa1, 0xd0, 0x1c, 0x5e, 0x58, 0xa9, 0x03, 0xfa, 0xe5, 0xf4, 0x49, 0x9c, 0x8a,
+	0x44, 0xa3, 0xa0, 0x52, 0x12, 0xec, 0x88, 0x50, 0x68, 0x81, 0x82, 0xda, 0x15, 0x82, 0x6d, 0x11,
+	0x86, 0xb4, 0x7e, 0xc5, 0x54, 0x07, 0x52, 0x9d, 0xd3, 0xa9, 0x2b, 0x46, 0xa0, 0x60, 0xda, 0xbd,
+	0x4d, 0x6e, 0xe4, 0x82, 0xb6, 0xe2, 0x71, 0xda, 0x8f, 0xcc, 0xf2, 0x0d, 0x30, 0x77, 0x2e, 0x6a,
+	0x71, 0xdd, 0xed, 0x23, 0x4f, 0xd6, 0x3e, 0xaa, 0x90, 0xa9, 0xce, 0xc5, 0x9e, 0x14, 0x61, 0x00,
+	0x9e, 0x65, 0x9b, 0xa7, 0xeb, 0x74, 0xc2, 0x5d, 0x24, 0x34, 0x83, 0x7b, 0x49, 0x34, 0x80, 0xda,
+	0x87, 0x3a, 0x57, 0xb0, 0x1b, 0xb4, 0x72, 0x05, 0xdb, 0xa4, 0x55, 0xb3, 0xa8, 0x61, 0xcd, 0xb3,
+	0x13, 0xce, 0x51, 0xbb, 0x92, 0xdf, 0xa0, 0xf5, 0x2b, 0xf9, 0x26, 0x9d, 0x2c, 0xce, 0x0e, 0x6a,
+	0xe3, 0x2c, 0x53, 0x57, 0xb0, 0x1b, 0x74, 0xfa, 0x0a, 0xb6, 0x49, 0x1b, 0xe6, 0xfc, 0x0c, 0xdb,
+	0xde, 0x3f, 0x5d, 0xa7, 0x64, 0x8c, 0xd9, 0xa0, 0x33, 0x63, 0x4c, 0x93, 0xce, 0x16, 0x19, 0xf8,
+	0x6d, 0x86, 0xce, 0x99, 0x53, 0x37, 0xcc, 0xd1, 0x70, 0x80, 0x8d, 0x94, 0xce, 0x17, 0xe9, 0x43,
+	0x7e, 0x61, 0x69, 0x6f, 0xed, 0x80, 0x4c, 0xb7, 0x45, 0x28, 0xba, 0xfa, 0x38, 0x06, 0xbd, 0xb2,
+	0xf6, 0xe9, 0x91, 0x18, 0xea, 0x84, 0x87, 0x74, 0xa2, 0xc4, 0xee, 0xab, 0x6e, 0x38, 0x0c, 0x04,
+	0x75, 0x4a, 0xec, 0xee, 0x85, 0x61, 0x2b, 0x6b, 0x5d, 0x78, 0xb2, 0xb3, 0x3f, 0x7f, 0xde, 0x22,
+	0xd7, 0xb3, 0xf6, 0xe9, 0x51, 0xa4, 0xf1, 0x53, 0x4d, 0x04, 0x66, 0xc2, 0x5c, 0x00, 0xbf, 0x96,
+	0x48, 0xd5, 0xa3, 0x8e, 0x7b, 0x9d, 0x2c, 0x94, 0x58, 0x11, 0xd0, 0x4a, 0x89, 0x34, 0x6f, 0x6a,
+	0xb4, 0xba, 0xf6, 0xe5, 0xfc, 0x47, 0x18, 0xd8, 0xbd, 0x6d, 0x9e, 0x1e, 0x45, 0x0a, 0x62, 0xed,
+	0x2d, 0x72, 0x3d, 0x63, 0x70, 0xc0, 0x31, 0xb6, 0x8d, 0xc2, 0x99, 0xe0, 0x90, 0x4b, 0xa5, 0xb9,
+	0x54, 0xb4, 0xb2, 0xf6, 0x81, 0x33, 0x2a, 0xe1, 0x5d, 0x8f, 0x2c, 0x66, 0xed, 0xd3, 0x13, 0x95,
+	0xc6, 0xa2, 0x8b, 0x25, 0x9c, 0x51, 0x39, 0x97, 0x1c, 0x27, 0x81, 0x48, 0x44, 0x40, 0x1d, 0xf7,
+	0x2e, 0xf1, 0x72, 0xb6, 0x15, 0x72, 0x25, 0x4e, 0xb7, 0x61, 0x8f, 0xa9, 0xe4, 0x8a, 0xd6, 0xdd,
+	0xe7, 0xc8, 0xad, 0x31, 0xe9, 0x43, 0x71, 0x01, 0x5f, 0xcc, 0x8c, 0x4e, 0x82, 0x1b, 0xe4, 0xc2,
+	0x07, 0x22, 0x92, 0xc1, 0x69, 0x3b, 0xee, 0x8b, 0x44, 0x50, 0x52, 0xd2, 0xc2, 0x88, 0x1e, 0x3d,
+	0x68, 0xff, 0xf4, 0x6b, 0x74, 0x66, 0xed, 0x57, 0xc8, 0xe4, 0xae, 0xc2, 0x50, 0xb9, 0x48, 0xa8,
+	0x69, 0x9d, 0x1e, 0x70, 0x28, 0xc0, 0x8f, 0xcf, 0xce, 0xe8, 0x04, 0x58, 0xab, 0xcc, 0x2a, 0xea,
+	0x14, 0xc8, 0xad, 0xae, 0x96, 0x4f, 0xc4, 0xb1, 0x32, 0xbe, 0x50, 0x26, 0xcf, 0xce, 0x68, 0x75,
+	0xed, 0x23, 0x87, 0x34, 0x4e, 0x92, 0xb0, 0xdd, 0xed, 0x8b, 0x81, 0x70, 0xaf, 0x91, 0xb9, 0x1c,
+	0xd8, 0x80, 0x72, 0x87, 0xdc, 0x1c, 0x51, 0x27, 0x2a, 0x11, 0xdd, 0xa8, 0xa7, 0xe4, 0xfb, 0x68,
+	0x0c, 0x97, 0xcc, 0x8f, 0x64, 0x0f, 0xb5, 0x8e, 0x69, 0xa5, 0xcc, 0x41, 0x62, 0xa2, 0xd5, 0x32,
+	0xb7, 0x27, 0x43, 0x41, 0x6b, 0xe5, 0xa5, 0xb6, 0x06, 0x31, 0x9d, 0x2a, 0x77, 0xdb, 0x8f, 0xcf,
+	0x52, 0x7a, 0x6d, 0x9c, 0x53, 0x29, 0x75, 0x61, 0x27, 0x23, 0xee, 0x90, 0xf7, 0x94, 0xd0, 0xf4,
+	0x7a, 0x79, 0xc2, 0x07, 0x52, 0xd3, 0xc5, 0xb5, 0x6f, 0x3a, 0xd9, 0xf7, 0x07, 0xc4, 0x7f, 0xd3,
+	0x1a, 0xc5, 0x49, 0x8b, 0x8f, 0x13, 0xdd, 0x8f, 0x5a, 0xf2, 0x42, 0x84, 0xd4, 0x81, 0xdd, 0x16,
+	0xe9, 0x43, 0x19, 0x86, 0x72, 0x20, 0xb4, 0x80, 0x50, 0x79, 0x97, 0x78, 0x56, 0xf6, 0x50, 0x5c,
+	0x3c, 0x48, 0x64, 0x50, 0x90, 0x56, 0xdd, 0x55, 0xf2, 0xa2, 0x95, 0x76, 0x12, 0x1e, 0x8b, 0xf7,
+	0xa3, 0x9d, 0x28, 0x10, 0x5d, 0xde, 0x17, 0x41, 0x12, 0xa9, 0x42, 0xcf, 0xda, 0xda, 0xaf, 0xe3,
+	0x97, 0x0a, 0x7c, 0xbd, 0x41, 0x60, 0xc1, 0xd6, 0xd8, 0xd5, 0xbb, 0x4e, 0x16, 0x2c, 0xdf, 0x92,
+	0x0a, 0xcf, 0x8c, 0x3a, 0xe8, 0xf5, 0x86, 0x7c, 0x10, 0x5e, 0xc6, 0x7d, 0x5a, 0x71, 0x17, 0xc8,
+	0x8c, 0x65, 0x30, 0xd0, 0x56, 0xc1, 0x04, 0x96, 0x30, 0x89, 0x9f, 0xd6, 0xc0, 0x7e, 0x96, 0xb2,
+	0xdf, 0x6d, 0xb4, 0xbe, 0xf6, 0xa7, 0x4e, 0xa9, 0x3c, 0x85, 0x61, 0x39, 0xb4, 0xe6, 0x81, 0x6b,
+	0x9e, 0x53, 0x6d, 0xd1, 0x4d, 0x84, 0xbe, 0x1f, 0x5d, 0x9c, 0x1e, 0xf1, 0xed, 0x90, 0x06, 0x98,
+	0xd4, 0x72, 0xe9, 0x56, 0x7a, 0x39, 0x38, 0x4c, 0x7b, 0x46, 0x26, 0xca, 0xb2, 0xb6, 0xec, 0x29,
+	0xa9, 0xac, 0xec, 0xcc, 0x5d, 0x22, 0xb7, 0x9f, 0x95, 0xed, 0xee, 0x34, 0x5f, 0x7f, 0x7d, 0xe3,
+	0x67, 0xe8, 0x7f, 0x38, 0x6b, 0x7f, 0x3f, 0x95, 0xff, 0xca, 0x0f, 0x4a, 0xd9, 0xe6, 0xe9, 0x51,
+	0xb4, 0x9b, 0x24, 0xe8, 0xe7, 0x6e, 0x46, 0x9d, 0x28, 0xc5, 0x07, 0x22, 0x00, 0xfe, 0x77, 0x57,
+	0x5c, 0x8f, 0x5c, 0xcf, 0x04, 0xfb, 0x4a, 0x8b, 0x44, 0xf1, 0x10, 0x24, 0xbf, 0xb7, 0xe2, 0xde,
+	0x21, 0x37, 0x46, 0x43, 0xd2, 0x61, 0x1c, 0x47, 0x10, 0x90, 0x8e, 0x63, 0xfa, 0xfb, 0x63, 0x32,
+	0x09, 0x0f, 0xaa, 0x50, 0x99, 0x89, 0x80, 0x7e, 0x75, 0xc5, 0x5d, 0x24, 0x0b, 0x99, 0x0c, 0x7e,
+	0xf0, 0x89, 0x86, 0x9a, 0xfe, 0xc1, 0x8a, 0x7b, 0x9b, 0x2c, 0x66, 0x6c, 0xbb, 0x3f, 0xd4, 0x5a,
+	0xaa, 0xde, 0x4e, 0xf4, 0x9e, 0xa2, 0x7f, 0x58, 0x12, 0x1d, 0x45, 0x7a, 0x3b, 0x52, 0x4a, 0x74,
+	0x61, 0xae, 0xaf, 0xad, 0x14, 0xd5, 0x86, 0x1a, 0x7e, 0x8f, 0xcb, 0x50, 0x04, 0xf4, 0x8f, 0x4a,
+	0x6a, 0xe3, 0x0f, 0xf1, 0x56, 0xf2, 0xf5, 0x15, 0xf7, 0x39, 0x72, 0x33, 0x5f, 0xc8, 0xfc, 0x56,
+	0x8e, 0xe5, 0xb7, 0x08, 0xe8, 0x1f, 0xaf, 0xb8, 0x77, 0xc9, 0xad, 0x4c, 0x68, 0x7f, 0xf1, 0x3e,
+	0x8a, 0xf4, 0x5e, 0x34, 0x54, 0x01, 0xfd, 0x46, 0x69, 0x57, 0x56, 0x6a, 0x83, 0xe8, 0x37, 0x4b,
+	0x9a, 0xdc, 0xe7, 0x81, 0x15, 0xd3, 0x3f, 0x2f, 0x09, 0xf6, 0xd5, 0x13, 0x1e, 0xca, 0xe0, 0x84,
+	0xed, 0xd3, 0x6f, 0xad, 0x40, 0x11, 0x52, 0x18, 0x81, 0x45, 0x15, 0xfd, 0x8b, 0xab, 0xfa, 0x77,
+	0x78, 0x8f, 0xfe, 0x65, 0x49, 0xf1, 0x91, 0xa0, 0x1d, 0x8b, 0x2e, 0xfd, 0xab, 0x92, 0x8d, 0x20,
+	0x07, 0xe6, 0x5a, 0xff, 0x4d, 0x69, 0x4f, 0x47, 0x91, 0xee, 0x4b, 0xd5, 0xeb, 0x44, 0xf0, 0x52,
+	0x2f, 0x35, 0xfd, 0x76, 0x69, 0xa0, 0x21, 0xad, 0xa5, 0xfe, 0xb6, 0xb4, 0x20, 0x06, 0xdc, 0x91,
+	0x2d, 0xbe, 0x53, 0xb2, 0x85, 0x11, 0xc2, 0xb8, 0x61, 0x22, 0xe8, 0x77, 0x4b, 0xc6, 0xdf, 0x8a,
+	0xe3, 0x7c, 0xd4, 0x07, 0x25, 0x5d, 0x6c, 0xad, 0xb6, 0x1d, 0xa9, 0xb3, 0x50, 0x76, 0x35, 0xfd,
+	0x5e, 0x69, 0xdc, 0x21, 0x0f, 0xe1, 0x19, 0x58, 0x04, 0x9d, 0x0b, 0xfa, 0xfd, 0x15, 0xf7, 0x26,
+	0xb9, 0x56, 0xb0, 0x15, 0x06, 0x22, 0x4e, 0xff, 0xa9, 0x34, 0x02, 0xe2, 0x61, 0xa6, 0xc3, 0x0f,
+	0x4a, 0x23, 0x76, 0x2f, 0xe0, 0x6a, 0xc2, 0xad, 0xfd, 0xe7, 0x12, 0xdf, 0xca, 0xaf, 0xc5, 0xbf,
+	0x94, 0xed, 0x20, 0xc2, 0x30, 0x57, 0xfa, 0xdf, 0x4a, 0x8b, 0xb4, 0x92, 0xe8, 0x89, 0x0c, 0x44,
+	0x02, 0x93, 0xfd, 0xfb, 0x8a, 0xfb, 0x3c, 0xb9, 0x93, 0x6f, 0x47, 0x46, 0x21, 0xd7, 0x22, 0xdd,
+	0x8a, 0x63, 0xa1, 0x82, 0x63, 0x15, 0x5e, 0xd2, 0xff, 0x5d, 0x71, 0x5f, 0x24, 0xcf, 0x8f, 0xce,
+	0x2c, 0x1d, 0x9e, 0x9d, 0xc9, 0x2e, 0x3c, 0xf9, 0xb7, 0x44, 0x32, 0x90, 0x78, 0xf7, 0x52, 0xfa,
+	0x7f, 0xa5, 0x05, 0xe0, 0x77, 0x07, 0xfc, 0xef, 0x00, 0x11, 0xd0, 0xff, 0x5f, 0x59, 0xdb, 0x21,
+	0xd3, 0xd9, 0x67, 0x00, 0x84, 0x9b, 0xac, 0x7d, 0xba, 0x9b, 0x24, 0x11, 0xb8, 0xed, 0x35, 0x32,
+	0x97, 0x73, 0x8f, 0x78, 0x02, 0xb9, 0xa8, 0x48, 0xc1, 0x2f, 0x4c, 0xb4, 0xb6, 0xf6, 0x8f, 0xce,
+	0xe8, 0x8d, 0xd9, 0xbc, 0x1c, 0xdf, 0x23, 0xb7, 0x4b, 0xc4, 0x58, 0x90, 0xbc, 0x4d, 0x6e, 0x94,
+	0xc5, 0x59, 0xb5, 0xe1, 0x40, 0x3a, 0x2d, 0x8b, 0x5a, 0x7c, 0x98, 0x62, 0x71, 0x71, 0x87, 0xdc,
+	0x1c, 0x93, 0xd8, 0x5f, 0xf4, 0x69, 0xf5, 0xaa, 0x09, 0xa3, 0x38, 0x16, 0x01, 0xad, 0x3d, 0x3b,
+	0x6c, 0x4f, 0x2a, 0x99, 0xf6, 0x45, 0x40, 0xeb, 0x6b, 0x5f, 0x75, 0x08, 0x31, 0x8f, 0xa5, 0x58,
+	0x50, 0x5c, 0x27, 0x0b, 0x23, 0x74, 0x0a, 0xaf, 0x36, 0x74, 0x02, 0xcc, 0x52, 0x20, 0xf7, 0x95,
+	0x36, 0xc5, 0x49, 0x81, 0xc3, 0x67, 0x4e, 0x53, 0xfd, 0x14, 0x58, 0x78, 0xe7, 0xa4, 0xd5, 0xf1,
+	0x39, 0xe5, 0x00, 0x12, 0x68, 0x79, 0x3c, 0xbe, 0x13, 0xd0, 0xfa, 0xfd, 0x5f, 0xfa, 0xf0, 0xe3,
+	0xa5, 0x89, 0x1f, 0x7d, 0xbc, 0x34, 0xf1, 0xe9, 0xc7, 0x4b, 0xce, 0x6f, 0x3c, 0x5d, 0x72, 0xbe,
+	0xf3, 0x74, 0xc9, 0xf9, 0xe1, 0xd3, 0x25, 0xe7, 0xc3, 0xa7, 0x4b, 0xce, 0x7f, 0x3f, 0x5d, 0x72,
+	0xfe, 0xe7, 0xe9, 0xd2, 0xc4, 0xa7, 0x4f, 0x97, 0x9c, 0xaf, 0x7f, 0xb2, 0x34, 0xf1, 0xe1, 0x27,
+	0x4b, 0x13, 0x3f, 0xfa, 0x64, 0x69, 0xe2, 0x9d, 0xe5, 0x9e, 0xd4, 0xfd, 0xe1, 0xe3, 0x57, 0xbb,
+	0xd1, 0xe0, 0x8b, 0x7c, 0x10, 0xbf, 0xb2, 0x19, 0xe0, 0x9f, 0x34, 0x38, 0x7f, 0xa5, 0x17, 0x41,
+	0xf3, 0x83, 0x4a, 0x75, 0xeb, 0xb0, 0xf5, 0x78, 0x12, 0xff, 0x39, 0x6e, 0xf3, 0xc7, 0x03, 0x00,
+	0x2e, 0x67, 0xdb, 0x75, 0x31, 0x27, 0x00, 0x00,
 }
 
-func (m *NotesEntry) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+func (x Const) String() string {
+	s, ok := Const_name[int32(x)]
+	if ok {
+		return s
 	}
-	return dAtA[:n], nil
+	return strconv.Itoa(int(x))
 }
-
-func (m *NotesEntry) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func (x TxOpCode) String() string {
+	s, ok := TxOpCode_name[int32(x)]
+	if ok {
+		return s
+	}
+	return strconv.Itoa(int(x))
 }
-
-func (m *NotesEntry) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	return len(dAtA) - i, nil
+func (x TxField) String() string {
+	s, ok := TxField_name[int32(x)]
+	if ok {
+		return s
+	}
+	return strconv.Itoa(int(x))
 }
-
-func (m *ChatEntry) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+func (x SelectOp) String() string {
+	s, ok := SelectOp_name[int32(x)]
+	if ok {
+		return s
 	}
-	return dAtA[:n], nil
+	return strconv.Itoa(int(x))
 }
-
-func (m *ChatEntry) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func (x OpStatus) String() string {
+	s, ok := OpStatus_name[int32(x)]
+	if ok {
+		return s
+	}
+	return strconv.Itoa(int(x))
 }
-
-func (m *ChatEntry) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	return len(dAtA) - i, nil
+func (x PinSync) String() string {
+	s, ok := PinSync_name[int32(x)]
+	if ok {
+		return s
+	}
+	return strconv.Itoa(int(x))
 }
-
-func (m *SpreadsheetEntry) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+func (x CordType) String() string {
+	s, ok := CordType_name[int32(x)]
+	if ok {
+		return s
 	}
-	return dAtA[:n], nil
+	return strconv.Itoa(int(x))
 }
-
-func (m *SpreadsheetEntry) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func (x Enable) String() string {
+	s, ok := Enable_name[int32(x)]
+	if ok {
+		return s
+	}
+	return strconv.Itoa(int(x))
 }
-
-func (m *SpreadsheetEntry) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	return len(dAtA) - i, nil
+func (x UrlScheme) String() string {
+	s, ok := UrlScheme_name[int32(x)]
+	if ok {
+		return s
+	}
+	return strconv.Itoa(int(x))
 }
-
-func (m *SpatialPlacement) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+func (x Metric) String() string {
+	s, ok := Metric_name[int32(x)]
+	if ok {
+		return s
 	}
-	return dAtA[:n], nil
+	return strconv.Itoa(int(x))
 }
-
-func (m *SpatialPlacement) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func (x TagUse) String() string {
+	s, ok := TagUse_name[int32(x)]
+	if ok {
+		return s
+	}
+	return strconv.Itoa(int(x))
 }
-
-func (m *SpatialPlacement) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.EulerZ != 0 {
-		i -= 4
-		encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(m.EulerZ))))
-		i--
-		dAtA[i] = 0x4d
+func (x CryptoKitID) String() string {
+	s, ok := CryptoKitID_name[int32(x)]
+	if ok {
+		return s
 	}
-	if m.EulerY != 0 {
-		i -= 4
-		encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(m.EulerY))))
-		i--
-		dAtA[i] = 0x45
+	return strconv.Itoa(int(x))
+}
+func (x ErrCode) String() string {
+	s, ok := ErrCode_name[int32(x)]
+	if ok {
+		return s
 	}
-	if m.EulerX != 0 {
-		i -= 4
-		encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(m.EulerX))))
-		i--
-		dAtA[i] = 0x3d
+	return strconv.Itoa(int(x))
+}
+func (x LogLevel) String() string {
+	s, ok := LogLevel_name[int32(x)]
+	if ok {
+		return s
 	}
-	if m.Scale2 != 0 {
-		i -= 4
-		encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(m.Scale2))))
-		i--
-		dAtA[i] = 0x35
+	return strconv.Itoa(int(x))
+}
+func (x PlaybackState) String() string {
+	s, ok := PlaybackState_name[int32(x)]
+	if ok {
+		return s
 	}
-	if m.Scale1 != 0 {
-		i -= 4
-		encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(m.Scale1))))
-		i--
-		dAtA[i] = 0x2d
+	return strconv.Itoa(int(x))
+}
+func (x ColumnType) String() string {
+	s, ok := ColumnType_name[int32(x)]
+	if ok {
+		return s
 	}
-	if m.Scale0 != 0 {
-		i -= 4
-		encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(m.Scale0))))
-		i--
-		dAtA[i] = 0x25
+	return strconv.Itoa(int(x))
+}
+func (x TRS_VisualScaleMode) String() string {
+	s, ok := TRS_VisualScaleMode_name[int32(x)]
+	if ok {
+		return s
 	}
-	if m.Pos2 != 0 {
-		i -= 8
-		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.Pos2))))
-		i--
-		dAtA[i] = 0x19
+	return strconv.Itoa(int(x))
+}
+func (this *TxInfo) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
 	}
-	if m.Pos1 != 0 {
-		i -= 8
-		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.Pos1))))
-		i--
-		dAtA[i] = 0x11
+
+	that1, ok := that.(*TxInfo)
+	if !ok {
+		that2, ok := that.(TxInfo)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
 	}
-	if m.Pos0 != 0 {
-		i -= 8
-		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.Pos0))))
-		i--
-		dAtA[i] = 0x9
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
 	}
-	return len(dAtA) - i, nil
-}
-
-func (m *TagPlayableMedia) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+	if this.Status != that1.Status {
+		return false
 	}
-	return dAtA[:n], nil
-}
-
-func (m *TagPlayableMedia) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
-}
-
-func (m *TagPlayableMedia) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	return len(dAtA) - i, nil
-}
-
-func (m *TagPlaylist) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+	if this.NumOps != that1.NumOps {
+		return false
 	}
-	return dAtA[:n], nil
-}
-
-func (m *TagPlaylist) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
-}
-
-func (m *TagPlaylist) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	return len(dAtA) - i, nil
-}
-
-func (m *CryptoKey) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+	if this.GenesisID_0 != that1.GenesisID_0 {
+		return false
 	}
-	return dAtA[:n], nil
-}
-
-func (m *CryptoKey) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
-}
-
-func (m *CryptoKey) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.KeyBytes) > 0 {
-		i -= len(m.KeyBytes)
-		copy(dAtA[i:], m.KeyBytes)
-		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.KeyBytes)))
-		i--
-		dAtA[i] = 0x22
+	if this.GenesisID_1 != that1.GenesisID_1 {
+		return false
 	}
-	if m.CryptoKitID != 0 {
-		i = encodeVarintApiAmp(dAtA, i, uint64(m.CryptoKitID))
-		i--
-		dAtA[i] = 0x8
+	if this.GenesisID_2 != that1.GenesisID_2 {
+		return false
 	}
-	return len(dAtA) - i, nil
-}
-
-func (m *AuthToken) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+	if this.RequestID_0 != that1.RequestID_0 {
+		return false
 	}
-	return dAtA[:n], nil
-}
-
-func (m *AuthToken) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
-}
-
-func (m *AuthToken) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.Expiry != 0 {
-		i = encodeVarintApiAmp(dAtA, i, uint64(m.Expiry))
-		i--
-		dAtA[i] = 0x20
+	if this.RequestID_1 != that1.RequestID_1 {
+		return false
 	}
-	if len(m.RefreshToken) > 0 {
-		i -= len(m.RefreshToken)
-		copy(dAtA[i:], m.RefreshToken)
-		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.RefreshToken)))
-		i--
-		dAtA[i] = 0x1a
+	if this.RequestID_2 != that1.RequestID_2 {
+		return false
 	}
-	if len(m.TokenType) > 0 {
-		i -= len(m.TokenType)
-		copy(dAtA[i:], m.TokenType)
-		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.TokenType)))
-		i--
-		dAtA[i] = 0x12
+	if this.RootElementID_0 != that1.RootElementID_0 {
+		return false
 	}
-	if len(m.AccessToken) > 0 {
-		i -= len(m.AccessToken)
-		copy(dAtA[i:], m.AccessToken)
-		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.AccessToken)))
-		i--
-		dAtA[i] = 0xa
+	if this.RootElementID_1 != that1.RootElementID_1 {
+		return false
 	}
-	return len(dAtA) - i, nil
-}
-
-func (m *TRS) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+	if this.RootElementID_2 != that1.RootElementID_2 {
+		return false
 	}
-	return dAtA[:n], nil
-}
-
-func (m *TRS) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+	if this.ErrCode != that1.ErrCode {
+		return false
+	}
+	return true
 }
+func (this *Login) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
 
-func (m *TRS) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.Rotate3 != 0 {
-		i -= 4
-		encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(m.Rotate3))))
-		i--
-		dAtA[i] = 0x3
-		i--
-		dAtA[i] = 0xfd
+	that1, ok := that.(*Login)
+	if !ok {
+		that2, ok := that.(Login)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
 	}
-	if m.Rotate2 != 0 {
-		i -= 4
-		encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(m.Rotate2))))
-		i--
-		dAtA[i] = 0x3
-		i--
-		dAtA[i] = 0xf5
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
 	}
-	if m.Rotate1 != 0 {
-		i -= 4
-		encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(m.Rotate1))))
-		i--
-		dAtA[i] = 0x3
-		i--
-		dAtA[i] = 0xed
+	if this.UserUID != that1.UserUID {
+		return false
 	}
-	if m.Scale3 != 0 {
-		i -= 4
-		encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(m.Scale3))))
-		i--
-		dAtA[i] = 0x3
-		i--
-		dAtA[i] = 0xad
+	if this.HostAddr != that1.HostAddr {
+		return false
 	}
-	if m.Scale2 != 0 {
-		i -= 4
-		encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(m.Scale2))))
-		i--
-		dAtA[i] = 0x3
-		i--
-		dAtA[i] = 0xa5
+	if this.DeviceLabel != that1.DeviceLabel {
+		return false
 	}
-	if m.Scale1 != 0 {
-		i -= 4
-		encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(m.Scale1))))
-		i--
-		dAtA[i] = 0x3
-		i--
-		dAtA[i] = 0x9d
+	if this.DeviceUID != that1.DeviceUID {
+		return false
 	}
-	if m.ScaleMode != 0 {
-		i = encodeVarintApiAmp(dAtA, i, uint64(m.ScaleMode))
-		i--
-		dAtA[i] = 0x3
-		i--
-		dAtA[i] = 0x90
+	if !this.Checkpoint.Equal(that1.Checkpoint) {
+		return false
 	}
-	if m.X3 != 0 {
-		i -= 8
-		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.X3))))
-		i--
-		dAtA[i] = 0x2
-		i--
-		dAtA[i] = 0xd9
+	if this.Space != that1.Space {
+		return false
 	}
-	if m.X2 != 0 {
-		i -= 8
-		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.X2))))
-		i--
-		dAtA[i] = 0x2
-		i--
-		dAtA[i] = 0xd1
+	if len(this.MediaTypes) != len(that1.MediaTypes) {
+		return false
 	}
-	if m.X1 != 0 {
-		i -= 8
-		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.X1))))
-		i--
-		dAtA[i] = 0x2
-		i--
-		dAtA[i] = 0xc9
+	for i := range this.MediaTypes {
+		if this.MediaTypes[i] != that1.MediaTypes[i] {
+			return false
+		}
 	}
-	return len(dAtA) - i, nil
-}
-
-func (m *DataSegment) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+	if len(this.Locales) != len(that1.Locales) {
+		return false
 	}
-	return dAtA[:n], nil
-}
-
-func (m *DataSegment) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
-}
-
-func (m *DataSegment) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.BlobID != 0 {
-		i = encodeVarintApiAmp(dAtA, i, uint64(m.BlobID))
-		i--
-		dAtA[i] = 0x50
+	for i := range this.Locales {
+		if this.Locales[i] != that1.Locales[i] {
+			return false
+		}
 	}
-	if len(m.StreamURI) > 0 {
-		i -= len(m.StreamURI)
-		copy(dAtA[i:], m.StreamURI)
-		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.StreamURI)))
-		i--
-		dAtA[i] = 0x4a
+	return true
+}
+func (this *LoginChallenge) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
 	}
-	if len(m.InlineData) > 0 {
-		i -= len(m.InlineData)
-		copy(dAtA[i:], m.InlineData)
-		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.InlineData)))
-		i--
-		dAtA[i] = 0x3a
+
+	that1, ok := that.(*LoginChallenge)
+	if !ok {
+		that2, ok := that.(LoginChallenge)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
 	}
-	if m.ByteSz != 0 {
-		i = encodeVarintApiAmp(dAtA, i, uint64(m.ByteSz))
-		i--
-		dAtA[i] = 0x30
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
 	}
-	if m.ByteOfs != 0 {
-		i = encodeVarintApiAmp(dAtA, i, uint64(m.ByteOfs))
-		i--
-		dAtA[i] = 0x28
+	if !bytes.Equal(this.Hash, that1.Hash) {
+		return false
 	}
-	return len(dAtA) - i, nil
+	return true
 }
-
-func (m *Err) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+func (this *LoginResponse) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
 	}
-	return dAtA[:n], nil
-}
-
-func (m *Err) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
-}
 
-func (m *Err) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.Msg) > 0 {
-		i -= len(m.Msg)
-		copy(dAtA[i:], m.Msg)
-		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.Msg)))
-		i--
-		dAtA[i] = 0x22
+	that1, ok := that.(*LoginResponse)
+	if !ok {
+		that2, ok := that.(LoginResponse)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
 	}
-	if m.Level != 0 {
-		i = encodeVarintApiAmp(dAtA, i, uint64(m.Level))
-		i--
-		dAtA[i] = 0x10
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
 	}
-	if m.Code != 0 {
-		i = encodeVarintApiAmp(dAtA, i, uint64(m.Code))
-		i--
-		dAtA[i] = 0x8
+	if !bytes.Equal(this.HashResponse, that1.HashResponse) {
+		return false
 	}
-	return len(dAtA) - i, nil
+	return true
 }
-
-func encodeVarintApiAmp(dAtA []byte, offset int, v uint64) int {
-	offset -= sovApiAmp(v)
-	base := offset
-	for v >= 1<<7 {
-		dAtA[offset] = uint8(v&0x7f | 0x80)
-		v >>= 7
-		offset++
+func (this *AuthCheckpoint) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
 	}
-	dAtA[offset] = uint8(v)
-	return base
-}
-func (m *TxInfo) Size() (n int) {
-	if m == nil {
-		return 0
+
+	that1, ok := that.(*AuthCheckpoint)
+	if !ok {
+		that2, ok := that.(AuthCheckpoint)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
 	}
-	var l int
-	_ = l
-	if m.Status != 0 {
-		n += 1 + sovApiAmp(uint64(m.Status))
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
 	}
-	if m.NumOps != 0 {
-		n += 1 + sovApiAmp(uint64(m.NumOps))
+	if this.Token != that1.Token {
+		return false
 	}
-	if m.GenesisID_0 != 0 {
-		n += 1 + sovApiAmp(uint64(m.GenesisID_0))
+	if !this.Session.Equal(that1.Session) {
+		return false
 	}
-	if m.GenesisID_1 != 0 {
-		n += 9
-	}
-	if m.GenesisID_2 != 0 {
-		n += 9
+	if !this.Member.Equal(that1.Member) {
+		return false
 	}
-	if m.RequestID_0 != 0 {
-		n += 1 + sovApiAmp(uint64(m.RequestID_0))
+	if !this.HomeFeed.Equal(that1.HomeFeed) {
+		return false
 	}
-	if m.RequestID_1 != 0 {
-		n += 9
+	return true
+}
+func (this *PinRequest) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
 	}
-	if m.RequestID_2 != 0 {
-		n += 9
+
+	that1, ok := that.(*PinRequest)
+	if !ok {
+		that2, ok := that.(PinRequest)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
 	}
-	if m.RootElementID_0 != 0 {
-		n += 1 + sovApiAmp(uint64(m.RootElementID_0))
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
 	}
-	if m.RootElementID_1 != 0 {
-		n += 9
+	if !this.PinTarget.Equal(that1.PinTarget) {
+		return false
 	}
-	if m.RootElementID_2 != 0 {
-		n += 9
+	if len(this.PinAttrs) != len(that1.PinAttrs) {
+		return false
 	}
-	return n
-}
-
-func (m *Login) Size() (n int) {
-	if m == nil {
-		return 0
+	for i := range this.PinAttrs {
+		if !this.PinAttrs[i].Equal(that1.PinAttrs[i]) {
+			return false
+		}
 	}
-	var l int
-	_ = l
-	l = len(m.UserUID)
-	if l > 0 {
-		n += 1 + l + sovApiAmp(uint64(l))
+	if this.PinSync != that1.PinSync {
+		return false
 	}
-	l = len(m.HostAddr)
-	if l > 0 {
-		n += 1 + l + sovApiAmp(uint64(l))
+	if !this.PinWindow.Equal(that1.PinWindow) {
+		return false
 	}
-	l = len(m.DeviceLabel)
-	if l > 0 {
-		n += 1 + l + sovApiAmp(uint64(l))
+	if this.PinFilter != that1.PinFilter {
+		return false
 	}
-	l = len(m.DeviceUID)
-	if l > 0 {
-		n += 1 + l + sovApiAmp(uint64(l))
+	if !this.Prefetch.Equal(that1.Prefetch) {
+		return false
 	}
-	if m.Checkpoint != nil {
-		l = m.Checkpoint.Size()
-		n += 1 + l + sovApiAmp(uint64(l))
+	if this.SendWindow != that1.SendWindow {
+		return false
 	}
-	return n
+	return true
 }
+func (this *PinWindow) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
 
-func (m *LoginChallenge) Size() (n int) {
-	if m == nil {
-		return 0
+	that1, ok := that.(*PinWindow)
+	if !ok {
+		that2, ok := that.(PinWindow)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
 	}
-	var l int
-	_ = l
-	l = len(m.Hash)
-	if l > 0 {
-		n += 1 + l + sovApiAmp(uint64(l))
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
 	}
-	return n
-}
-
-func (m *LoginResponse) Size() (n int) {
-	if m == nil {
-		return 0
+	if this.Offset != that1.Offset {
+		return false
 	}
-	var l int
-	_ = l
-	l = len(m.HashResponse)
-	if l > 0 {
-		n += 1 + l + sovApiAmp(uint64(l))
+	if this.Limit != that1.Limit {
+		return false
 	}
-	return n
+	if this.Cursor != that1.Cursor {
+		return false
+	}
+	return true
 }
+func (this *PrefetchHint) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
 
-func (m *AuthCheckpoint) Size() (n int) {
-	if m == nil {
-		return 0
+	that1, ok := that.(*PrefetchHint)
+	if !ok {
+		that2, ok := that.(PrefetchHint)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
 	}
-	var l int
-	_ = l
-	l = len(m.Token)
-	if l > 0 {
-		n += 1 + l + sovApiAmp(uint64(l))
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
 	}
-	if m.HomeFeed != nil {
-		l = m.HomeFeed.Size()
-		n += 1 + l + sovApiAmp(uint64(l))
+	if this.Children != that1.Children {
+		return false
 	}
-	if m.Session != nil {
-		l = m.Session.Size()
-		n += 1 + l + sovApiAmp(uint64(l))
+	if len(this.Attrs) != len(that1.Attrs) {
+		return false
 	}
-	if m.Member != nil {
-		l = m.Member.Size()
-		n += 1 + l + sovApiAmp(uint64(l))
+	for i := range this.Attrs {
+		if !this.Attrs[i].Equal(that1.Attrs[i]) {
+			return false
+		}
 	}
-	return n
+	return true
 }
+func (this *PageInfo) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
 
-func (m *PinRequest) Size() (n int) {
-	if m == nil {
-		return 0
+	that1, ok := that.(*PageInfo)
+	if !ok {
+		that2, ok := that.(PageInfo)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
 	}
-	var l int
-	_ = l
-	if m.PinTarget != nil {
-		l = m.PinTarget.Size()
-		n += 1 + l + sovApiAmp(uint64(l))
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
 	}
-	if len(m.PinAttrs) > 0 {
-		for _, e := range m.PinAttrs {
-			l = e.Size()
-			n += 1 + l + sovApiAmp(uint64(l))
-		}
+	if this.Total != that1.Total {
+		return false
 	}
-	if m.PinSync != 0 {
-		n += 1 + sovApiAmp(uint64(m.PinSync))
+	if this.Offset != that1.Offset {
+		return false
 	}
-	return n
-}
-
-func (m *LaunchURL) Size() (n int) {
-	if m == nil {
-		return 0
+	if this.Count != that1.Count {
+		return false
 	}
-	var l int
-	_ = l
-	l = len(m.URL)
-	if l > 0 {
-		n += 1 + l + sovApiAmp(uint64(l))
+	if this.NextCursor != that1.NextCursor {
+		return false
 	}
-	return n
+	if this.PrevCursor != that1.PrevCursor {
+		return false
+	}
+	if this.Epoch != that1.Epoch {
+		return false
+	}
+	return true
 }
+func (this *Progress) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
 
-func (m *Position) Size() (n int) {
-	if m == nil {
-		return 0
+	that1, ok := that.(*Progress)
+	if !ok {
+		that2, ok := that.(Progress)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
 	}
-	var l int
-	_ = l
-	if m.CordType != 0 {
-		n += 1 + sovApiAmp(uint64(m.CordType))
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
 	}
-	if m.U != 0 {
-		n += 9
+	if this.Fraction != that1.Fraction {
+		return false
 	}
-	if m.V != 0 {
-		n += 9
+	if this.Stage != that1.Stage {
+		return false
 	}
-	if m.W != 0 {
-		n += 9
+	if this.Done != that1.Done {
+		return false
 	}
-	if m.ROU != 0 {
-		n += 5
+	if this.Total != that1.Total {
+		return false
 	}
-	return n
+	return true
 }
+func (this *SearchHit) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
 
-func (m *Tag) Size() (n int) {
-	if m == nil {
-		return 0
+	that1, ok := that.(*SearchHit)
+	if !ok {
+		that2, ok := that.(SearchHit)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
 	}
-	var l int
-	_ = l
-	if m.Use != 0 {
-		n += 1 + sovApiAmp(uint64(m.Use))
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
 	}
-	if m.TagID_0 != 0 {
-		n += 1 + sovApiAmp(uint64(m.TagID_0))
-	}
-	if m.TagID_1 != 0 {
-		n += 9
-	}
-	if m.TagID_2 != 0 {
-		n += 9
+	if this.Rank != that1.Rank {
+		return false
 	}
-	l = len(m.URL)
-	if l > 0 {
-		n += 1 + l + sovApiAmp(uint64(l))
+	if this.Score != that1.Score {
+		return false
 	}
-	l = len(m.ContentType)
-	if l > 0 {
-		n += 1 + l + sovApiAmp(uint64(l))
+	return true
+}
+func (this *LWWRegister) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
 	}
-	l = len(m.Attachment)
-	if l > 0 {
-		n += 1 + l + sovApiAmp(uint64(l))
+
+	that1, ok := that.(*LWWRegister)
+	if !ok {
+		that2, ok := that.(LWWRegister)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
 	}
-	if m.Metric != 0 {
-		n += 2 + sovApiAmp(uint64(m.Metric))
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
 	}
-	if m.Size_0 != 0 {
-		n += 6
+	if !bytes.Equal(this.Value, that1.Value) {
+		return false
 	}
-	if m.Size_1 != 0 {
-		n += 6
+	if this.Time != that1.Time {
+		return false
 	}
-	if m.Size_2 != 0 {
-		n += 6
+	if this.Replica != that1.Replica {
+		return false
 	}
-	return n
+	return true
 }
+func (this *ORSetEntry) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
 
-func (m *TagTab) Size() (n int) {
-	if m == nil {
-		return 0
+	that1, ok := that.(*ORSetEntry)
+	if !ok {
+		that2, ok := that.(ORSetEntry)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
 	}
-	var l int
-	_ = l
-	l = len(m.Label)
-	if l > 0 {
-		n += 1 + l + sovApiAmp(uint64(l))
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
 	}
-	l = len(m.Caption)
-	if l > 0 {
-		n += 1 + l + sovApiAmp(uint64(l))
+	if !bytes.Equal(this.Value, that1.Value) {
+		return false
 	}
-	l = len(m.About)
-	if l > 0 {
-		n += 1 + l + sovApiAmp(uint64(l))
+	if this.Time != that1.Time {
+		return false
 	}
-	if m.CreatedAt != 0 {
-		n += 1 + sovApiAmp(uint64(m.CreatedAt))
+	if this.Replica != that1.Replica {
+		return false
 	}
-	if m.ModifiedAt != 0 {
-		n += 1 + sovApiAmp(uint64(m.ModifiedAt))
+	return true
+}
+func (this *ORSet) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
 	}
-	if len(m.Tags) > 0 {
-		for _, e := range m.Tags {
-			l = e.Size()
-			n += 1 + l + sovApiAmp(uint64(l))
+
+	that1, ok := that.(*ORSet)
+	if !ok {
+		that2, ok := that.(ORSet)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
 		}
 	}
-	return n
-}
-
-func (m *Ballot) Size() (n int) {
-	if m == nil {
-		return 0
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
 	}
-	var l int
-	_ = l
-	return n
-}
-
-func (m *NotesEntry) Size() (n int) {
-	if m == nil {
-		return 0
+	if len(this.Adds) != len(that1.Adds) {
+		return false
 	}
-	var l int
-	_ = l
-	return n
-}
-
-func (m *ChatEntry) Size() (n int) {
-	if m == nil {
-		return 0
+	for i := range this.Adds {
+		if !this.Adds[i].Equal(that1.Adds[i]) {
+			return false
+		}
 	}
-	var l int
-	_ = l
-	return n
-}
-
-func (m *SpreadsheetEntry) Size() (n int) {
-	if m == nil {
-		return 0
+	if len(this.Removes) != len(that1.Removes) {
+		return false
 	}
-	var l int
-	_ = l
-	return n
-}
-
-func (m *SpatialPlacement) Size() (n int) {
-	if m == nil {
-		return 0
+	for i := range this.Removes {
+		if !this.Removes[i].Equal(that1.Removes[i]) {
+			return false
+		}
 	}
-	var l int
-	_ = l
-	if m.Pos0 != 0 {
-		n += 9
+	return true
+}
+func (this *RGAElem) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
 	}
-	if m.Pos1 != 0 {
-		n += 9
+
+	that1, ok := that.(*RGAElem)
+	if !ok {
+		that2, ok := that.(RGAElem)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
 	}
-	if m.Pos2 != 0 {
-		n += 9
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
 	}
-	if m.Scale0 != 0 {
-		n += 5
+	if !bytes.Equal(this.Value, that1.Value) {
+		return false
 	}
-	if m.Scale1 != 0 {
-		n += 5
+	if this.Time != that1.Time {
+		return false
 	}
-	if m.Scale2 != 0 {
-		n += 5
+	if this.Replica != that1.Replica {
+		return false
 	}
-	if m.EulerX != 0 {
-		n += 5
+	if this.AfterTime != that1.AfterTime {
+		return false
 	}
-	if m.EulerY != 0 {
-		n += 5
+	if this.AfterReplica != that1.AfterReplica {
+		return false
 	}
-	if m.EulerZ != 0 {
-		n += 5
+	if this.Removed != that1.Removed {
+		return false
 	}
-	return n
+	return true
 }
-
-func (m *TagPlayableMedia) Size() (n int) {
-	if m == nil {
-		return 0
+func (this *RGAList) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
 	}
-	var l int
-	_ = l
-	return n
-}
 
-func (m *TagPlaylist) Size() (n int) {
-	if m == nil {
-		return 0
+	that1, ok := that.(*RGAList)
+	if !ok {
+		that2, ok := that.(RGAList)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
 	}
-	var l int
-	_ = l
-	return n
-}
-
-func (m *CryptoKey) Size() (n int) {
-	if m == nil {
-		return 0
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
 	}
-	var l int
-	_ = l
-	if m.CryptoKitID != 0 {
-		n += 1 + sovApiAmp(uint64(m.CryptoKitID))
+	if len(this.Elems) != len(that1.Elems) {
+		return false
 	}
-	l = len(m.KeyBytes)
-	if l > 0 {
-		n += 1 + l + sovApiAmp(uint64(l))
+	for i := range this.Elems {
+		if !this.Elems[i].Equal(that1.Elems[i]) {
+			return false
+		}
 	}
-	return n
+	return true
 }
+func (this *MigrationRecord) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
 
-func (m *AuthToken) Size() (n int) {
-	if m == nil {
-		return 0
+	that1, ok := that.(*MigrationRecord)
+	if !ok {
+		that2, ok := that.(MigrationRecord)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
 	}
-	var l int
-	_ = l
-	l = len(m.AccessToken)
-	if l > 0 {
-		n += 1 + l + sovApiAmp(uint64(l))
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
 	}
-	l = len(m.TokenType)
-	if l > 0 {
-		n += 1 + l + sovApiAmp(uint64(l))
+	if this.Version != that1.Version {
+		return false
 	}
-	l = len(m.RefreshToken)
-	if l > 0 {
-		n += 1 + l + sovApiAmp(uint64(l))
+	if this.AppliedAt != that1.AppliedAt {
+		return false
 	}
-	if m.Expiry != 0 {
-		n += 1 + sovApiAmp(uint64(m.Expiry))
+	if this.Desc != that1.Desc {
+		return false
 	}
-	return n
+	return true
 }
+func (this *MigrationLog) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
 
-func (m *TRS) Size() (n int) {
-	if m == nil {
-		return 0
+	that1, ok := that.(*MigrationLog)
+	if !ok {
+		that2, ok := that.(MigrationLog)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
 	}
-	var l int
-	_ = l
-	if m.X1 != 0 {
-		n += 10
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
 	}
-	if m.X2 != 0 {
-		n += 10
+	if len(this.Applied) != len(that1.Applied) {
+		return false
 	}
-	if m.X3 != 0 {
-		n += 10
+	for i := range this.Applied {
+		if !this.Applied[i].Equal(that1.Applied[i]) {
+			return false
+		}
 	}
-	if m.ScaleMode != 0 {
-		n += 2 + sovApiAmp(uint64(m.ScaleMode))
+	return true
+}
+func (this *PinStats) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
 	}
-	if m.Scale1 != 0 {
-		n += 6
+
+	that1, ok := that.(*PinStats)
+	if !ok {
+		that2, ok := that.(PinStats)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
 	}
-	if m.Scale2 != 0 {
-		n += 6
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
 	}
-	if m.Scale3 != 0 {
-		n += 6
+	if this.ReqID != that1.ReqID {
+		return false
 	}
-	if m.Rotate1 != 0 {
-		n += 6
+	if this.App != that1.App {
+		return false
 	}
-	if m.Rotate2 != 0 {
-		n += 6
+	if this.Target != that1.Target {
+		return false
 	}
-	if m.Rotate3 != 0 {
-		n += 6
+	if this.OpenedAt != that1.OpenedAt {
+		return false
 	}
-	return n
-}
-
-func (m *DataSegment) Size() (n int) {
-	if m == nil {
-		return 0
+	if this.FirstTxMicros != that1.FirstTxMicros {
+		return false
 	}
-	var l int
-	_ = l
-	if m.ByteOfs != 0 {
-		n += 1 + sovApiAmp(uint64(m.ByteOfs))
+	if this.TxsOut != that1.TxsOut {
+		return false
 	}
-	if m.ByteSz != 0 {
-		n += 1 + sovApiAmp(uint64(m.ByteSz))
+	if this.BytesOut != that1.BytesOut {
+		return false
 	}
-	l = len(m.InlineData)
-	if l > 0 {
-		n += 1 + l + sovApiAmp(uint64(l))
+	if this.TxsIn != that1.TxsIn {
+		return false
 	}
-	l = len(m.StreamURI)
-	if l > 0 {
-		n += 1 + l + sovApiAmp(uint64(l))
+	if this.LastTxAt != that1.LastTxAt {
+		return false
 	}
-	if m.BlobID != 0 {
-		n += 1 + sovApiAmp(uint64(m.BlobID))
+	if this.ClosedAt != that1.ClosedAt {
+		return false
 	}
-	return n
+	return true
 }
+func (this *BlobChunk) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
 
-func (m *Err) Size() (n int) {
-	if m == nil {
-		return 0
+	that1, ok := that.(*BlobChunk)
+	if !ok {
+		that2, ok := that.(BlobChunk)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
 	}
-	var l int
-	_ = l
-	if m.Code != 0 {
-		n += 1 + sovApiAmp(uint64(m.Code))
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
 	}
-	if m.Level != 0 {
-		n += 1 + sovApiAmp(uint64(m.Level))
+	if this.BlobID != that1.BlobID {
+		return false
 	}
-	l = len(m.Msg)
-	if l > 0 {
-		n += 1 + l + sovApiAmp(uint64(l))
+	if this.Offset != that1.Offset {
+		return false
 	}
-	return n
-}
-
-func sovApiAmp(x uint64) (n int) {
-	return (math_bits.Len64(x|1) + 6) / 7
-}
-func sozApiAmp(x uint64) (n int) {
-	return sovApiAmp(uint64((x << 1) ^ uint64((int64(x) >> 63))))
-}
-func (this *TxInfo) String() string {
-	if this == nil {
-		return "nil"
+	if this.TotalSize != that1.TotalSize {
+		return false
 	}
-	s := strings.Join([]string{`&TxInfo{`,
-		`Status:` + fmt.Sprintf("%v", this.Status) + `,`,
-		`NumOps:` + fmt.Sprintf("%v", this.NumOps) + `,`,
-		`GenesisID_0:` + fmt.Sprintf("%v", this.GenesisID_0) + `,`,
-		`GenesisID_1:` + fmt.Sprintf("%v", this.GenesisID_1) + `,`,
-		`GenesisID_2:` + fmt.Sprintf("%v", this.GenesisID_2) + `,`,
-		`RequestID_0:` + fmt.Sprintf("%v", this.RequestID_0) + `,`,
-		`RequestID_1:` + fmt.Sprintf("%v", this.RequestID_1) + `,`,
-		`RequestID_2:` + fmt.Sprintf("%v", this.RequestID_2) + `,`,
-		`RootElementID_0:` + fmt.Sprintf("%v", this.RootElementID_0) + `,`,
-		`RootElementID_1:` + fmt.Sprintf("%v", this.RootElementID_1) + `,`,
-		`RootElementID_2:` + fmt.Sprintf("%v", this.RootElementID_2) + `,`,
-		`}`,
-	}, "")
-	return s
-}
-func (this *Login) String() string {
-	if this == nil {
-		return "nil"
+	if this.Final != that1.Final {
+		return false
 	}
-	s := strings.Join([]string{`&Login{`,
-		`UserUID:` + fmt.Sprintf("%v", this.UserUID) + `,`,
-		`HostAddr:` + fmt.Sprintf("%v", this.HostAddr) + `,`,
-		`DeviceLabel:` + fmt.Sprintf("%v", this.DeviceLabel) + `,`,
-		`DeviceUID:` + fmt.Sprintf("%v", this.DeviceUID) + `,`,
-		`Checkpoint:` + strings.Replace(this.Checkpoint.String(), "AuthCheckpoint", "AuthCheckpoint", 1) + `,`,
-		`}`,
-	}, "")
-	return s
-}
-func (this *LoginChallenge) String() string {
-	if this == nil {
-		return "nil"
+	if this.Canceled != that1.Canceled {
+		return false
 	}
-	s := strings.Join([]string{`&LoginChallenge{`,
-		`Hash:` + fmt.Sprintf("%v", this.Hash) + `,`,
-		`}`,
-	}, "")
-	return s
-}
-func (this *LoginResponse) String() string {
-	if this == nil {
-		return "nil"
+	if this.ContentType != that1.ContentType {
+		return false
 	}
-	s := strings.Join([]string{`&LoginResponse{`,
-		`HashResponse:` + fmt.Sprintf("%v", this.HashResponse) + `,`,
-		`}`,
-	}, "")
-	return s
-}
-func (this *AuthCheckpoint) String() string {
-	if this == nil {
-		return "nil"
+	if !bytes.Equal(this.Data, that1.Data) {
+		return false
 	}
-	s := strings.Join([]string{`&AuthCheckpoint{`,
-		`Token:` + fmt.Sprintf("%v", this.Token) + `,`,
-		`HomeFeed:` + strings.Replace(this.HomeFeed.String(), "Tag", "Tag", 1) + `,`,
-		`Session:` + strings.Replace(this.Session.String(), "Tag", "Tag", 1) + `,`,
-		`Member:` + strings.Replace(this.Member.String(), "Tag", "Tag", 1) + `,`,
-		`}`,
-	}, "")
-	return s
+	return true
 }
-func (this *PinRequest) String() string {
-	if this == nil {
-		return "nil"
+func (this *MediaInfo) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
 	}
-	repeatedStringForPinAttrs := "[]*Tag{"
-	for _, f := range this.PinAttrs {
-		repeatedStringForPinAttrs += strings.Replace(f.String(), "Tag", "Tag", 1) + ","
+
+	that1, ok := that.(*MediaInfo)
+	if !ok {
+		that2, ok := that.(MediaInfo)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
 	}
-	repeatedStringForPinAttrs += "}"
-	s := strings.Join([]string{`&PinRequest{`,
-		`PinTarget:` + strings.Replace(this.PinTarget.String(), "Tag", "Tag", 1) + `,`,
-		`PinAttrs:` + repeatedStringForPinAttrs + `,`,
-		`PinSync:` + fmt.Sprintf("%v", this.PinSync) + `,`,
-		`}`,
-	}, "")
-	return s
-}
-func (this *LaunchURL) String() string {
-	if this == nil {
-		return "nil"
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
 	}
-	s := strings.Join([]string{`&LaunchURL{`,
-		`URL:` + fmt.Sprintf("%v", this.URL) + `,`,
-		`}`,
-	}, "")
-	return s
-}
-func (this *Position) String() string {
-	if this == nil {
-		return "nil"
+	if this.ContentType != that1.ContentType {
+		return false
 	}
-	s := strings.Join([]string{`&Position{`,
-		`CordType:` + fmt.Sprintf("%v", this.CordType) + `,`,
-		`U:` + fmt.Sprintf("%v", this.U) + `,`,
-		`V:` + fmt.Sprintf("%v", this.V) + `,`,
-		`W:` + fmt.Sprintf("%v", this.W) + `,`,
-		`ROU:` + fmt.Sprintf("%v", this.ROU) + `,`,
-		`}`,
-	}, "")
-	return s
-}
-func (this *Tag) String() string {
-	if this == nil {
-		return "nil"
+	if this.Title != that1.Title {
+		return false
 	}
-	s := strings.Join([]string{`&Tag{`,
-		`Use:` + fmt.Sprintf("%v", this.Use) + `,`,
-		`TagID_0:` + fmt.Sprintf("%v", this.TagID_0) + `,`,
-		`TagID_1:` + fmt.Sprintf("%v", this.TagID_1) + `,`,
-		`TagID_2:` + fmt.Sprintf("%v", this.TagID_2) + `,`,
-		`URL:` + fmt.Sprintf("%v", this.URL) + `,`,
-		`ContentType:` + fmt.Sprintf("%v", this.ContentType) + `,`,
-		`Attachment:` + fmt.Sprintf("%v", this.Attachment) + `,`,
-		`Metric:` + fmt.Sprintf("%v", this.Metric) + `,`,
-		`Size_0:` + fmt.Sprintf("%v", this.Size_0) + `,`,
-		`Size_1:` + fmt.Sprintf("%v", this.Size_1) + `,`,
-		`Size_2:` + fmt.Sprintf("%v", this.Size_2) + `,`,
-		`}`,
-	}, "")
-	return s
-}
-func (this *TagTab) String() string {
-	if this == nil {
-		return "nil"
+	if this.Artist != that1.Artist {
+		return false
 	}
-	repeatedStringForTags := "[]*Tag{"
-	for _, f := range this.Tags {
-		repeatedStringForTags += strings.Replace(f.String(), "Tag", "Tag", 1) + ","
+	if this.Album != that1.Album {
+		return false
 	}
-	repeatedStringForTags += "}"
-	s := strings.Join([]string{`&TagTab{`,
-		`Label:` + fmt.Sprintf("%v", this.Label) + `,`,
-		`Caption:` + fmt.Sprintf("%v", this.Caption) + `,`,
-		`About:` + fmt.Sprintf("%v", this.About) + `,`,
-		`CreatedAt:` + fmt.Sprintf("%v", this.CreatedAt) + `,`,
-		`ModifiedAt:` + fmt.Sprintf("%v", this.ModifiedAt) + `,`,
-		`Tags:` + repeatedStringForTags + `,`,
-		`}`,
-	}, "")
-	return s
-}
-func (this *Ballot) String() string {
-	if this == nil {
-		return "nil"
+	if this.AlbumArtist != that1.AlbumArtist {
+		return false
 	}
-	s := strings.Join([]string{`&Ballot{`,
-		`}`,
-	}, "")
-	return s
-}
-func (this *NotesEntry) String() string {
-	if this == nil {
-		return "nil"
+	if this.Composer != that1.Composer {
+		return false
 	}
-	s := strings.Join([]string{`&NotesEntry{`,
-		`}`,
-	}, "")
-	return s
-}
-func (this *ChatEntry) String() string {
-	if this == nil {
-		return "nil"
+	if this.Genre != that1.Genre {
+		return false
 	}
-	s := strings.Join([]string{`&ChatEntry{`,
-		`}`,
-	}, "")
-	return s
-}
-func (this *SpreadsheetEntry) String() string {
-	if this == nil {
-		return "nil"
+	if this.Comment != that1.Comment {
+		return false
 	}
-	s := strings.Join([]string{`&SpreadsheetEntry{`,
-		`}`,
-	}, "")
-	return s
-}
-func (this *SpatialPlacement) String() string {
-	if this == nil {
-		return "nil"
+	if this.Year != that1.Year {
+		return false
 	}
-	s := strings.Join([]string{`&SpatialPlacement{`,
-		`Pos0:` + fmt.Sprintf("%v", this.Pos0) + `,`,
-		`Pos1:` + fmt.Sprintf("%v", this.Pos1) + `,`,
-		`Pos2:` + fmt.Sprintf("%v", this.Pos2) + `,`,
-		`Scale0:` + fmt.Sprintf("%v", this.Scale0) + `,`,
-		`Scale1:` + fmt.Sprintf("%v", this.Scale1) + `,`,
-		`Scale2:` + fmt.Sprintf("%v", this.Scale2) + `,`,
-		`EulerX:` + fmt.Sprintf("%v", this.EulerX) + `,`,
-		`EulerY:` + fmt.Sprintf("%v", this.EulerY) + `,`,
-		`EulerZ:` + fmt.Sprintf("%v", this.EulerZ) + `,`,
-		`}`,
-	}, "")
-	return s
-}
-func (this *TagPlayableMedia) String() string {
-	if this == nil {
-		return "nil"
+	if this.TrackNum != that1.TrackNum {
+		return false
 	}
-	s := strings.Join([]string{`&TagPlayableMedia{`,
-		`}`,
-	}, "")
-	return s
-}
-func (this *TagPlaylist) String() string {
-	if this == nil {
-		return "nil"
+	if this.TrackCount != that1.TrackCount {
+		return false
 	}
-	s := strings.Join([]string{`&TagPlaylist{`,
-		`}`,
-	}, "")
-	return s
-}
-func (this *CryptoKey) String() string {
-	if this == nil {
-		return "nil"
+	if this.DiscNum != that1.DiscNum {
+		return false
 	}
-	s := strings.Join([]string{`&CryptoKey{`,
-		`CryptoKitID:` + fmt.Sprintf("%v", this.CryptoKitID) + `,`,
-		`KeyBytes:` + fmt.Sprintf("%v", this.KeyBytes) + `,`,
-		`}`,
-	}, "")
-	return s
-}
-func (this *AuthToken) String() string {
-	if this == nil {
-		return "nil"
+	if this.DiscCount != that1.DiscCount {
+		return false
 	}
-	s := strings.Join([]string{`&AuthToken{`,
-		`AccessToken:` + fmt.Sprintf("%v", this.AccessToken) + `,`,
-		`TokenType:` + fmt.Sprintf("%v", this.TokenType) + `,`,
-		`RefreshToken:` + fmt.Sprintf("%v", this.RefreshToken) + `,`,
-		`Expiry:` + fmt.Sprintf("%v", this.Expiry) + `,`,
-		`}`,
-	}, "")
-	return s
-}
-func (this *TRS) String() string {
-	if this == nil {
-		return "nil"
+	if this.DurationMs != that1.DurationMs {
+		return false
 	}
-	s := strings.Join([]string{`&TRS{`,
-		`X1:` + fmt.Sprintf("%v", this.X1) + `,`,
-		`X2:` + fmt.Sprintf("%v", this.X2) + `,`,
-		`X3:` + fmt.Sprintf("%v", this.X3) + `,`,
-		`ScaleMode:` + fmt.Sprintf("%v", this.ScaleMode) + `,`,
-		`Scale1:` + fmt.Sprintf("%v", this.Scale1) + `,`,
-		`Scale2:` + fmt.Sprintf("%v", this.Scale2) + `,`,
-		`Scale3:` + fmt.Sprintf("%v", this.Scale3) + `,`,
-		`Rotate1:` + fmt.Sprintf("%v", this.Rotate1) + `,`,
-		`Rotate2:` + fmt.Sprintf("%v", this.Rotate2) + `,`,
-		`Rotate3:` + fmt.Sprintf("%v", this.Rotate3) + `,`,
-		`}`,
-	}, "")
-	return s
+	if this.SampleRate != that1.SampleRate {
+		return false
+	}
+	if this.Channels != that1.Channels {
+		return false
+	}
+	if this.Width != that1.Width {
+		return false
+	}
+	if this.Height != that1.Height {
+		return false
+	}
+	if this.Orientation != that1.Orientation {
+		return false
+	}
+	if this.CameraMake != that1.CameraMake {
+		return false
+	}
+	if this.CameraModel != that1.CameraModel {
+		return false
+	}
+	if this.TakenAt != that1.TakenAt {
+		return false
+	}
+	if this.HasLocation != that1.HasLocation {
+		return false
+	}
+	if this.Latitude != that1.Latitude {
+		return false
+	}
+	if this.Longitude != that1.Longitude {
+		return false
+	}
+	if this.EncoderDelay != that1.EncoderDelay {
+		return false
+	}
+	if this.EncoderPadding != that1.EncoderPadding {
+		return false
+	}
+	return true
 }
-func (this *DataSegment) String() string {
-	if this == nil {
-		return "nil"
+func (this *WaveformPeaks) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
 	}
-	s := strings.Join([]string{`&DataSegment{`,
-		`ByteOfs:` + fmt.Sprintf("%v", this.ByteOfs) + `,`,
-		`ByteSz:` + fmt.Sprintf("%v", this.ByteSz) + `,`,
-		`InlineData:` + fmt.Sprintf("%v", this.InlineData) + `,`,
-		`StreamURI:` + fmt.Sprintf("%v", this.StreamURI) + `,`,
-		`BlobID:` + fmt.Sprintf("%v", this.BlobID) + `,`,
-		`}`,
-	}, "")
-	return s
+
+	that1, ok := that.(*WaveformPeaks)
+	if !ok {
+		that2, ok := that.(WaveformPeaks)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.DurationMs != that1.DurationMs {
+		return false
+	}
+	if !bytes.Equal(this.Peaks, that1.Peaks) {
+		return false
+	}
+	return true
 }
-func (this *Err) String() string {
-	if this == nil {
-		return "nil"
+func (this *PlaylistEntry) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
 	}
-	s := strings.Join([]string{`&Err{`,
-		`Code:` + fmt.Sprintf("%v", this.Code) + `,`,
-		`Level:` + fmt.Sprintf("%v", this.Level) + `,`,
-		`Msg:` + fmt.Sprintf("%v", this.Msg) + `,`,
-		`}`,
-	}, "")
-	return s
+
+	that1, ok := that.(*PlaylistEntry)
+	if !ok {
+		that2, ok := that.(PlaylistEntry)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Location != that1.Location {
+		return false
+	}
+	if this.Title != that1.Title {
+		return false
+	}
+	if this.Artist != that1.Artist {
+		return false
+	}
+	if this.Album != that1.Album {
+		return false
+	}
+	if this.DurationMs != that1.DurationMs {
+		return false
+	}
+	if this.Resolved != that1.Resolved {
+		return false
+	}
+	return true
 }
-func valueToStringApiAmp(v interface{}) string {
-	rv := reflect.ValueOf(v)
-	if rv.IsNil() {
-		return "nil"
+func (this *PlaybackEvent) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
 	}
-	pv := reflect.Indirect(rv).Interface()
-	return fmt.Sprintf("*%v", pv)
+
+	that1, ok := that.(*PlaybackEvent)
+	if !ok {
+		that2, ok := that.(PlaybackEvent)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.State != that1.State {
+		return false
+	}
+	if this.PositionMs != that1.PositionMs {
+		return false
+	}
+	if this.At != that1.At {
+		return false
+	}
+	return true
+}
+func (this *TrackOffset) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*TrackOffset)
+	if !ok {
+		that2, ok := that.(TrackOffset)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.StartMs != that1.StartMs {
+		return false
+	}
+	if this.DurationMs != that1.DurationMs {
+		return false
+	}
+	if this.PregapMs != that1.PregapMs {
+		return false
+	}
+	return true
+}
+func (this *GeoPoint) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*GeoPoint)
+	if !ok {
+		that2, ok := that.(GeoPoint)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Lat != that1.Lat {
+		return false
+	}
+	if this.Lon != that1.Lon {
+		return false
+	}
+	if this.Alt != that1.Alt {
+		return false
+	}
+	return true
+}
+func (this *TableColumn) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*TableColumn)
+	if !ok {
+		that2, ok := that.(TableColumn)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Name != that1.Name {
+		return false
+	}
+	if this.Label != that1.Label {
+		return false
+	}
+	if this.Type != that1.Type {
+		return false
+	}
+	if this.Unit != that1.Unit {
+		return false
+	}
+	return true
+}
+func (this *TableSortKey) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*TableSortKey)
+	if !ok {
+		that2, ok := that.(TableSortKey)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Column != that1.Column {
+		return false
+	}
+	if this.Desc != that1.Desc {
+		return false
+	}
+	return true
+}
+func (this *TableHeader) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*TableHeader)
+	if !ok {
+		that2, ok := that.(TableHeader)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if len(this.Columns) != len(that1.Columns) {
+		return false
+	}
+	for i := range this.Columns {
+		if !this.Columns[i].Equal(that1.Columns[i]) {
+			return false
+		}
+	}
+	if len(this.Sort) != len(that1.Sort) {
+		return false
+	}
+	for i := range this.Sort {
+		if !this.Sort[i].Equal(that1.Sort[i]) {
+			return false
+		}
+	}
+	return true
+}
+func (this *TableValue) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*TableValue)
+	if !ok {
+		that2, ok := that.(TableValue)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Null != that1.Null {
+		return false
+	}
+	if this.Text != that1.Text {
+		return false
+	}
+	if this.Int != that1.Int {
+		return false
+	}
+	if this.Float != that1.Float {
+		return false
+	}
+	if this.Bool != that1.Bool {
+		return false
+	}
+	if !bytes.Equal(this.Bytes, that1.Bytes) {
+		return false
+	}
+	return true
+}
+func (this *TableRow) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*TableRow)
+	if !ok {
+		that2, ok := that.(TableRow)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if len(this.Values) != len(that1.Values) {
+		return false
+	}
+	for i := range this.Values {
+		if !this.Values[i].Equal(that1.Values[i]) {
+			return false
+		}
+	}
+	return true
+}
+func (this *LaunchURL) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*LaunchURL)
+	if !ok {
+		that2, ok := that.(LaunchURL)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.URL != that1.URL {
+		return false
+	}
+	return true
+}
+func (this *Position) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*Position)
+	if !ok {
+		that2, ok := that.(Position)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.CordType != that1.CordType {
+		return false
+	}
+	if this.U != that1.U {
+		return false
+	}
+	if this.V != that1.V {
+		return false
+	}
+	if this.W != that1.W {
+		return false
+	}
+	if this.ROU != that1.ROU {
+		return false
+	}
+	return true
+}
+func (this *Tag) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*Tag)
+	if !ok {
+		that2, ok := that.(Tag)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Use != that1.Use {
+		return false
+	}
+	if this.TagID_0 != that1.TagID_0 {
+		return false
+	}
+	if this.TagID_1 != that1.TagID_1 {
+		return false
+	}
+	if this.TagID_2 != that1.TagID_2 {
+		return false
+	}
+	if this.URL != that1.URL {
+		return false
+	}
+	if this.ContentType != that1.ContentType {
+		return false
+	}
+	if !bytes.Equal(this.Attachment, that1.Attachment) {
+		return false
+	}
+	if this.Metric != that1.Metric {
+		return false
+	}
+	if this.Size_0 != that1.Size_0 {
+		return false
+	}
+	if this.Size_1 != that1.Size_1 {
+		return false
+	}
+	if this.Size_2 != that1.Size_2 {
+		return false
+	}
+	return true
+}
+func (this *TagTab) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*TagTab)
+	if !ok {
+		that2, ok := that.(TagTab)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Label != that1.Label {
+		return false
+	}
+	if this.Caption != that1.Caption {
+		return false
+	}
+	if this.About != that1.About {
+		return false
+	}
+	if this.CreatedAt != that1.CreatedAt {
+		return false
+	}
+	if this.ModifiedAt != that1.ModifiedAt {
+		return false
+	}
+	if len(this.Tags) != len(that1.Tags) {
+		return false
+	}
+	for i := range this.Tags {
+		if !this.Tags[i].Equal(that1.Tags[i]) {
+			return false
+		}
+	}
+	if len(this.Locales) != len(that1.Locales) {
+		return false
+	}
+	for i := range this.Locales {
+		if !this.Locales[i].Equal(that1.Locales[i]) {
+			return false
+		}
+	}
+	return true
+}
+func (this *LocaleText) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*LocaleText)
+	if !ok {
+		that2, ok := that.(LocaleText)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Locale != that1.Locale {
+		return false
+	}
+	if this.Label != that1.Label {
+		return false
+	}
+	if this.Caption != that1.Caption {
+		return false
+	}
+	if this.About != that1.About {
+		return false
+	}
+	return true
+}
+func (this *Ballot) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*Ballot)
+	if !ok {
+		that2, ok := that.(Ballot)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	return true
+}
+func (this *NotesEntry) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*NotesEntry)
+	if !ok {
+		that2, ok := that.(NotesEntry)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	return true
+}
+func (this *ChatEntry) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*ChatEntry)
+	if !ok {
+		that2, ok := that.(ChatEntry)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	return true
+}
+func (this *SpreadsheetEntry) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*SpreadsheetEntry)
+	if !ok {
+		that2, ok := that.(SpreadsheetEntry)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	return true
+}
+func (this *SpatialPlacement) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*SpatialPlacement)
+	if !ok {
+		that2, ok := that.(SpatialPlacement)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Pos0 != that1.Pos0 {
+		return false
+	}
+	if this.Pos1 != that1.Pos1 {
+		return false
+	}
+	if this.Pos2 != that1.Pos2 {
+		return false
+	}
+	if this.Scale0 != that1.Scale0 {
+		return false
+	}
+	if this.Scale1 != that1.Scale1 {
+		return false
+	}
+	if this.Scale2 != that1.Scale2 {
+		return false
+	}
+	if this.EulerX != that1.EulerX {
+		return false
+	}
+	if this.EulerY != that1.EulerY {
+		return false
+	}
+	if this.EulerZ != that1.EulerZ {
+		return false
+	}
+	return true
+}
+func (this *TagPlayableMedia) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*TagPlayableMedia)
+	if !ok {
+		that2, ok := that.(TagPlayableMedia)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	return true
+}
+func (this *TagPlaylist) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*TagPlaylist)
+	if !ok {
+		that2, ok := that.(TagPlaylist)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	return true
+}
+func (this *CryptoKey) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*CryptoKey)
+	if !ok {
+		that2, ok := that.(CryptoKey)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.CryptoKitID != that1.CryptoKitID {
+		return false
+	}
+	if !bytes.Equal(this.KeyBytes, that1.KeyBytes) {
+		return false
+	}
+	return true
+}
+func (this *AuthToken) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*AuthToken)
+	if !ok {
+		that2, ok := that.(AuthToken)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.AccessToken != that1.AccessToken {
+		return false
+	}
+	if this.TokenType != that1.TokenType {
+		return false
+	}
+	if this.RefreshToken != that1.RefreshToken {
+		return false
+	}
+	if this.Expiry != that1.Expiry {
+		return false
+	}
+	return true
+}
+func (this *TRS) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*TRS)
+	if !ok {
+		that2, ok := that.(TRS)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.X1 != that1.X1 {
+		return false
+	}
+	if this.X2 != that1.X2 {
+		return false
+	}
+	if this.X3 != that1.X3 {
+		return false
+	}
+	if this.ScaleMode != that1.ScaleMode {
+		return false
+	}
+	if this.Scale1 != that1.Scale1 {
+		return false
+	}
+	if this.Scale2 != that1.Scale2 {
+		return false
+	}
+	if this.Scale3 != that1.Scale3 {
+		return false
+	}
+	if this.Rotate1 != that1.Rotate1 {
+		return false
+	}
+	if this.Rotate2 != that1.Rotate2 {
+		return false
+	}
+	if this.Rotate3 != that1.Rotate3 {
+		return false
+	}
+	return true
+}
+func (this *DataSegment) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*DataSegment)
+	if !ok {
+		that2, ok := that.(DataSegment)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.ByteOfs != that1.ByteOfs {
+		return false
+	}
+	if this.ByteSz != that1.ByteSz {
+		return false
+	}
+	if !bytes.Equal(this.InlineData, that1.InlineData) {
+		return false
+	}
+	if this.StreamURI != that1.StreamURI {
+		return false
+	}
+	if this.BlobID != that1.BlobID {
+		return false
+	}
+	return true
+}
+func (this *Err) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*Err)
+	if !ok {
+		that2, ok := that.(Err)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Code != that1.Code {
+		return false
+	}
+	if this.Level != that1.Level {
+		return false
+	}
+	if this.Msg != that1.Msg {
+		return false
+	}
+	if this.Retryable != that1.Retryable {
+		return false
+	}
+	if len(this.Details) != len(that1.Details) {
+		return false
+	}
+	for i := range this.Details {
+		if !this.Details[i].Equal(that1.Details[i]) {
+			return false
+		}
+	}
+	return true
+}
+func (this *ErrDetail) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*ErrDetail)
+	if !ok {
+		that2, ok := that.(ErrDetail)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Key != that1.Key {
+		return false
+	}
+	if this.Value != that1.Value {
+		return false
+	}
+	return true
+}
+func (this *TxInfo) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 16)
+	s = append(s, "&amp.TxInfo{")
+	s = append(s, "Status: "+fmt.Sprintf("%#v", this.Status)+",\n")
+	s = append(s, "NumOps: "+fmt.Sprintf("%#v", this.NumOps)+",\n")
+	s = append(s, "GenesisID_0: "+fmt.Sprintf("%#v", this.GenesisID_0)+",\n")
+	s = append(s, "GenesisID_1: "+fmt.Sprintf("%#v", this.GenesisID_1)+",\n")
+	s = append(s, "GenesisID_2: "+fmt.Sprintf("%#v", this.GenesisID_2)+",\n")
+	s = append(s, "RequestID_0: "+fmt.Sprintf("%#v", this.RequestID_0)+",\n")
+	s = append(s, "RequestID_1: "+fmt.Sprintf("%#v", this.RequestID_1)+",\n")
+	s = append(s, "RequestID_2: "+fmt.Sprintf("%#v", this.RequestID_2)+",\n")
+	s = append(s, "RootElementID_0: "+fmt.Sprintf("%#v", this.RootElementID_0)+",\n")
+	s = append(s, "RootElementID_1: "+fmt.Sprintf("%#v", this.RootElementID_1)+",\n")
+	s = append(s, "RootElementID_2: "+fmt.Sprintf("%#v", this.RootElementID_2)+",\n")
+	s = append(s, "ErrCode: "+fmt.Sprintf("%#v", this.ErrCode)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *Login) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 12)
+	s = append(s, "&amp.Login{")
+	s = append(s, "UserUID: "+fmt.Sprintf("%#v", this.UserUID)+",\n")
+	s = append(s, "HostAddr: "+fmt.Sprintf("%#v", this.HostAddr)+",\n")
+	s = append(s, "DeviceLabel: "+fmt.Sprintf("%#v", this.DeviceLabel)+",\n")
+	s = append(s, "DeviceUID: "+fmt.Sprintf("%#v", this.DeviceUID)+",\n")
+	if this.Checkpoint != nil {
+		s = append(s, "Checkpoint: "+fmt.Sprintf("%#v", this.Checkpoint)+",\n")
+	}
+	s = append(s, "Space: "+fmt.Sprintf("%#v", this.Space)+",\n")
+	s = append(s, "MediaTypes: "+fmt.Sprintf("%#v", this.MediaTypes)+",\n")
+	s = append(s, "Locales: "+fmt.Sprintf("%#v", this.Locales)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *LoginChallenge) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 5)
+	s = append(s, "&amp.LoginChallenge{")
+	s = append(s, "Hash: "+fmt.Sprintf("%#v", this.Hash)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *LoginResponse) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 5)
+	s = append(s, "&amp.LoginResponse{")
+	s = append(s, "HashResponse: "+fmt.Sprintf("%#v", this.HashResponse)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *AuthCheckpoint) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 8)
+	s = append(s, "&amp.AuthCheckpoint{")
+	s = append(s, "Token: "+fmt.Sprintf("%#v", this.Token)+",\n")
+	if this.Session != nil {
+		s = append(s, "Session: "+fmt.Sprintf("%#v", this.Session)+",\n")
+	}
+	if this.Member != nil {
+		s = append(s, "Member: "+fmt.Sprintf("%#v", this.Member)+",\n")
+	}
+	if this.HomeFeed != nil {
+		s = append(s, "HomeFeed: "+fmt.Sprintf("%#v", this.HomeFeed)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *PinRequest) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 11)
+	s = append(s, "&amp.PinRequest{")
+	if this.PinTarget != nil {
+		s = append(s, "PinTarget: "+fmt.Sprintf("%#v", this.PinTarget)+",\n")
+	}
+	if this.PinAttrs != nil {
+		s = append(s, "PinAttrs: "+fmt.Sprintf("%#v", this.PinAttrs)+",\n")
+	}
+	s = append(s, "PinSync: "+fmt.Sprintf("%#v", this.PinSync)+",\n")
+	if this.PinWindow != nil {
+		s = append(s, "PinWindow: "+fmt.Sprintf("%#v", this.PinWindow)+",\n")
+	}
+	s = append(s, "PinFilter: "+fmt.Sprintf("%#v", this.PinFilter)+",\n")
+	if this.Prefetch != nil {
+		s = append(s, "Prefetch: "+fmt.Sprintf("%#v", this.Prefetch)+",\n")
+	}
+	s = append(s, "SendWindow: "+fmt.Sprintf("%#v", this.SendWindow)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *PinWindow) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 7)
+	s = append(s, "&amp.PinWindow{")
+	s = append(s, "Offset: "+fmt.Sprintf("%#v", this.Offset)+",\n")
+	s = append(s, "Limit: "+fmt.Sprintf("%#v", this.Limit)+",\n")
+	s = append(s, "Cursor: "+fmt.Sprintf("%#v", this.Cursor)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *PrefetchHint) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 6)
+	s = append(s, "&amp.PrefetchHint{")
+	s = append(s, "Children: "+fmt.Sprintf("%#v", this.Children)+",\n")
+	if this.Attrs != nil {
+		s = append(s, "Attrs: "+fmt.Sprintf("%#v", this.Attrs)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *PageInfo) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 10)
+	s = append(s, "&amp.PageInfo{")
+	s = append(s, "Total: "+fmt.Sprintf("%#v", this.Total)+",\n")
+	s = append(s, "Offset: "+fmt.Sprintf("%#v", this.Offset)+",\n")
+	s = append(s, "Count: "+fmt.Sprintf("%#v", this.Count)+",\n")
+	s = append(s, "NextCursor: "+fmt.Sprintf("%#v", this.NextCursor)+",\n")
+	s = append(s, "PrevCursor: "+fmt.Sprintf("%#v", this.PrevCursor)+",\n")
+	s = append(s, "Epoch: "+fmt.Sprintf("%#v", this.Epoch)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *Progress) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 8)
+	s = append(s, "&amp.Progress{")
+	s = append(s, "Fraction: "+fmt.Sprintf("%#v", this.Fraction)+",\n")
+	s = append(s, "Stage: "+fmt.Sprintf("%#v", this.Stage)+",\n")
+	s = append(s, "Done: "+fmt.Sprintf("%#v", this.Done)+",\n")
+	s = append(s, "Total: "+fmt.Sprintf("%#v", this.Total)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *SearchHit) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 6)
+	s = append(s, "&amp.SearchHit{")
+	s = append(s, "Rank: "+fmt.Sprintf("%#v", this.Rank)+",\n")
+	s = append(s, "Score: "+fmt.Sprintf("%#v", this.Score)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *LWWRegister) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 7)
+	s = append(s, "&amp.LWWRegister{")
+	s = append(s, "Value: "+fmt.Sprintf("%#v", this.Value)+",\n")
+	s = append(s, "Time: "+fmt.Sprintf("%#v", this.Time)+",\n")
+	s = append(s, "Replica: "+fmt.Sprintf("%#v", this.Replica)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *ORSetEntry) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 7)
+	s = append(s, "&amp.ORSetEntry{")
+	s = append(s, "Value: "+fmt.Sprintf("%#v", this.Value)+",\n")
+	s = append(s, "Time: "+fmt.Sprintf("%#v", this.Time)+",\n")
+	s = append(s, "Replica: "+fmt.Sprintf("%#v", this.Replica)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *ORSet) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 6)
+	s = append(s, "&amp.ORSet{")
+	if this.Adds != nil {
+		s = append(s, "Adds: "+fmt.Sprintf("%#v", this.Adds)+",\n")
+	}
+	if this.Removes != nil {
+		s = append(s, "Removes: "+fmt.Sprintf("%#v", this.Removes)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *RGAElem) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 10)
+	s = append(s, "&amp.RGAElem{")
+	s = append(s, "Value: "+fmt.Sprintf("%#v", this.Value)+",\n")
+	s = append(s, "Time: "+fmt.Sprintf("%#v", this.Time)+",\n")
+	s = append(s, "Replica: "+fmt.Sprintf("%#v", this.Replica)+",\n")
+	s = append(s, "AfterTime: "+fmt.Sprintf("%#v", this.AfterTime)+",\n")
+	s = append(s, "AfterReplica: "+fmt.Sprintf("%#v", this.AfterReplica)+",\n")
+	s = append(s, "Removed: "+fmt.Sprintf("%#v", this.Removed)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *RGAList) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 5)
+	s = append(s, "&amp.RGAList{")
+	if this.Elems != nil {
+		s = append(s, "Elems: "+fmt.Sprintf("%#v", this.Elems)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *MigrationRecord) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 7)
+	s = append(s, "&amp.MigrationRecord{")
+	s = append(s, "Version: "+fmt.Sprintf("%#v", this.Version)+",\n")
+	s = append(s, "AppliedAt: "+fmt.Sprintf("%#v", this.AppliedAt)+",\n")
+	s = append(s, "Desc: "+fmt.Sprintf("%#v", this.Desc)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *MigrationLog) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 5)
+	s = append(s, "&amp.MigrationLog{")
+	if this.Applied != nil {
+		s = append(s, "Applied: "+fmt.Sprintf("%#v", this.Applied)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *PinStats) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 14)
+	s = append(s, "&amp.PinStats{")
+	s = append(s, "ReqID: "+fmt.Sprintf("%#v", this.ReqID)+",\n")
+	s = append(s, "App: "+fmt.Sprintf("%#v", this.App)+",\n")
+	s = append(s, "Target: "+fmt.Sprintf("%#v", this.Target)+",\n")
+	s = append(s, "OpenedAt: "+fmt.Sprintf("%#v", this.OpenedAt)+",\n")
+	s = append(s, "FirstTxMicros: "+fmt.Sprintf("%#v", this.FirstTxMicros)+",\n")
+	s = append(s, "TxsOut: "+fmt.Sprintf("%#v", this.TxsOut)+",\n")
+	s = append(s, "BytesOut: "+fmt.Sprintf("%#v", this.BytesOut)+",\n")
+	s = append(s, "TxsIn: "+fmt.Sprintf("%#v", this.TxsIn)+",\n")
+	s = append(s, "LastTxAt: "+fmt.Sprintf("%#v", this.LastTxAt)+",\n")
+	s = append(s, "ClosedAt: "+fmt.Sprintf("%#v", this.ClosedAt)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *BlobChunk) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 11)
+	s = append(s, "&amp.BlobChunk{")
+	s = append(s, "BlobID: "+fmt.Sprintf("%#v", this.BlobID)+",\n")
+	s = append(s, "Offset: "+fmt.Sprintf("%#v", this.Offset)+",\n")
+	s = append(s, "TotalSize: "+fmt.Sprintf("%#v", this.TotalSize)+",\n")
+	s = append(s, "Final: "+fmt.Sprintf("%#v", this.Final)+",\n")
+	s = append(s, "Canceled: "+fmt.Sprintf("%#v", this.Canceled)+",\n")
+	s = append(s, "ContentType: "+fmt.Sprintf("%#v", this.ContentType)+",\n")
+	s = append(s, "Data: "+fmt.Sprintf("%#v", this.Data)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *MediaInfo) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 31)
+	s = append(s, "&amp.MediaInfo{")
+	s = append(s, "ContentType: "+fmt.Sprintf("%#v", this.ContentType)+",\n")
+	s = append(s, "Title: "+fmt.Sprintf("%#v", this.Title)+",\n")
+	s = append(s, "Artist: "+fmt.Sprintf("%#v", this.Artist)+",\n")
+	s = append(s, "Album: "+fmt.Sprintf("%#v", this.Album)+",\n")
+	s = append(s, "AlbumArtist: "+fmt.Sprintf("%#v", this.AlbumArtist)+",\n")
+	s = append(s, "Composer: "+fmt.Sprintf("%#v", this.Composer)+",\n")
+	s = append(s, "Genre: "+fmt.Sprintf("%#v", this.Genre)+",\n")
+	s = append(s, "Comment: "+fmt.Sprintf("%#v", this.Comment)+",\n")
+	s = append(s, "Year: "+fmt.Sprintf("%#v", this.Year)+",\n")
+	s = append(s, "TrackNum: "+fmt.Sprintf("%#v", this.TrackNum)+",\n")
+	s = append(s, "TrackCount: "+fmt.Sprintf("%#v", this.TrackCount)+",\n")
+	s = append(s, "DiscNum: "+fmt.Sprintf("%#v", this.DiscNum)+",\n")
+	s = append(s, "DiscCount: "+fmt.Sprintf("%#v", this.DiscCount)+",\n")
+	s = append(s, "DurationMs: "+fmt.Sprintf("%#v", this.DurationMs)+",\n")
+	s = append(s, "SampleRate: "+fmt.Sprintf("%#v", this.SampleRate)+",\n")
+	s = append(s, "Channels: "+fmt.Sprintf("%#v", this.Channels)+",\n")
+	s = append(s, "Width: "+fmt.Sprintf("%#v", this.Width)+",\n")
+	s = append(s, "Height: "+fmt.Sprintf("%#v", this.Height)+",\n")
+	s = append(s, "Orientation: "+fmt.Sprintf("%#v", this.Orientation)+",\n")
+	s = append(s, "CameraMake: "+fmt.Sprintf("%#v", this.CameraMake)+",\n")
+	s = append(s, "CameraModel: "+fmt.Sprintf("%#v", this.CameraModel)+",\n")
+	s = append(s, "TakenAt: "+fmt.Sprintf("%#v", this.TakenAt)+",\n")
+	s = append(s, "HasLocation: "+fmt.Sprintf("%#v", this.HasLocation)+",\n")
+	s = append(s, "Latitude: "+fmt.Sprintf("%#v", this.Latitude)+",\n")
+	s = append(s, "Longitude: "+fmt.Sprintf("%#v", this.Longitude)+",\n")
+	s = append(s, "EncoderDelay: "+fmt.Sprintf("%#v", this.EncoderDelay)+",\n")
+	s = append(s, "EncoderPadding: "+fmt.Sprintf("%#v", this.EncoderPadding)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *WaveformPeaks) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 6)
+	s = append(s, "&amp.WaveformPeaks{")
+	s = append(s, "DurationMs: "+fmt.Sprintf("%#v", this.DurationMs)+",\n")
+	s = append(s, "Peaks: "+fmt.Sprintf("%#v", this.Peaks)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *PlaylistEntry) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 10)
+	s = append(s, "&amp.PlaylistEntry{")
+	s = append(s, "Location: "+fmt.Sprintf("%#v", this.Location)+",\n")
+	s = append(s, "Title: "+fmt.Sprintf("%#v", this.Title)+",\n")
+	s = append(s, "Artist: "+fmt.Sprintf("%#v", this.Artist)+",\n")
+	s = append(s, "Album: "+fmt.Sprintf("%#v", this.Album)+",\n")
+	s = append(s, "DurationMs: "+fmt.Sprintf("%#v", this.DurationMs)+",\n")
+	s = append(s, "Resolved: "+fmt.Sprintf("%#v", this.Resolved)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *PlaybackEvent) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 7)
+	s = append(s, "&amp.PlaybackEvent{")
+	s = append(s, "State: "+fmt.Sprintf("%#v", this.State)+",\n")
+	s = append(s, "PositionMs: "+fmt.Sprintf("%#v", this.PositionMs)+",\n")
+	s = append(s, "At: "+fmt.Sprintf("%#v", this.At)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *TrackOffset) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 7)
+	s = append(s, "&amp.TrackOffset{")
+	s = append(s, "StartMs: "+fmt.Sprintf("%#v", this.StartMs)+",\n")
+	s = append(s, "DurationMs: "+fmt.Sprintf("%#v", this.DurationMs)+",\n")
+	s = append(s, "PregapMs: "+fmt.Sprintf("%#v", this.PregapMs)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *GeoPoint) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 7)
+	s = append(s, "&amp.GeoPoint{")
+	s = append(s, "Lat: "+fmt.Sprintf("%#v", this.Lat)+",\n")
+	s = append(s, "Lon: "+fmt.Sprintf("%#v", this.Lon)+",\n")
+	s = append(s, "Alt: "+fmt.Sprintf("%#v", this.Alt)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *TableColumn) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 8)
+	s = append(s, "&amp.TableColumn{")
+	s = append(s, "Name: "+fmt.Sprintf("%#v", this.Name)+",\n")
+	s = append(s, "Label: "+fmt.Sprintf("%#v", this.Label)+",\n")
+	s = append(s, "Type: "+fmt.Sprintf("%#v", this.Type)+",\n")
+	s = append(s, "Unit: "+fmt.Sprintf("%#v", this.Unit)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *TableSortKey) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 6)
+	s = append(s, "&amp.TableSortKey{")
+	s = append(s, "Column: "+fmt.Sprintf("%#v", this.Column)+",\n")
+	s = append(s, "Desc: "+fmt.Sprintf("%#v", this.Desc)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *TableHeader) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 6)
+	s = append(s, "&amp.TableHeader{")
+	if this.Columns != nil {
+		s = append(s, "Columns: "+fmt.Sprintf("%#v", this.Columns)+",\n")
+	}
+	if this.Sort != nil {
+		s = append(s, "Sort: "+fmt.Sprintf("%#v", this.Sort)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *TableValue) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 10)
+	s = append(s, "&amp.TableValue{")
+	s = append(s, "Null: "+fmt.Sprintf("%#v", this.Null)+",\n")
+	s = append(s, "Text: "+fmt.Sprintf("%#v", this.Text)+",\n")
+	s = append(s, "Int: "+fmt.Sprintf("%#v", this.Int)+",\n")
+	s = append(s, "Float: "+fmt.Sprintf("%#v", this.Float)+",\n")
+	s = append(s, "Bool: "+fmt.Sprintf("%#v", this.Bool)+",\n")
+	s = append(s, "Bytes: "+fmt.Sprintf("%#v", this.Bytes)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *TableRow) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 5)
+	s = append(s, "&amp.TableRow{")
+	if this.Values != nil {
+		s = append(s, "Values: "+fmt.Sprintf("%#v", this.Values)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *LaunchURL) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 5)
+	s = append(s, "&amp.LaunchURL{")
+	s = append(s, "URL: "+fmt.Sprintf("%#v", this.URL)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *Position) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 9)
+	s = append(s, "&amp.Position{")
+	s = append(s, "CordType: "+fmt.Sprintf("%#v", this.CordType)+",\n")
+	s = append(s, "U: "+fmt.Sprintf("%#v", this.U)+",\n")
+	s = append(s, "V: "+fmt.Sprintf("%#v", this.V)+",\n")
+	s = append(s, "W: "+fmt.Sprintf("%#v", this.W)+",\n")
+	s = append(s, "ROU: "+fmt.Sprintf("%#v", this.ROU)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *Tag) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 15)
+	s = append(s, "&amp.Tag{")
+	s = append(s, "Use: "+fmt.Sprintf("%#v", this.Use)+",\n")
+	s = append(s, "TagID_0: "+fmt.Sprintf("%#v", this.TagID_0)+",\n")
+	s = append(s, "TagID_1: "+fmt.Sprintf("%#v", this.TagID_1)+",\n")
+	s = append(s, "TagID_2: "+fmt.Sprintf("%#v", this.TagID_2)+",\n")
+	s = append(s, "URL: "+fmt.Sprintf("%#v", this.URL)+",\n")
+	s = append(s, "ContentType: "+fmt.Sprintf("%#v", this.ContentType)+",\n")
+	s = append(s, "Attachment: "+fmt.Sprintf("%#v", this.Attachment)+",\n")
+	s = append(s, "Metric: "+fmt.Sprintf("%#v", this.Metric)+",\n")
+	s = append(s, "Size_0: "+fmt.Sprintf("%#v", this.Size_0)+",\n")
+	s = append(s, "Size_1: "+fmt.Sprintf("%#v", this.Size_1)+",\n")
+	s = append(s, "Size_2: "+fmt.Sprintf("%#v", this.Size_2)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *TagTab) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 11)
+	s = append(s, "&amp.TagTab{")
+	s = append(s, "Label: "+fmt.Sprintf("%#v", this.Label)+",\n")
+	s = append(s, "Caption: "+fmt.Sprintf("%#v", this.Caption)+",\n")
+	s = append(s, "About: "+fmt.Sprintf("%#v", this.About)+",\n")
+	s = append(s, "CreatedAt: "+fmt.Sprintf("%#v", this.CreatedAt)+",\n")
+	s = append(s, "ModifiedAt: "+fmt.Sprintf("%#v", this.ModifiedAt)+",\n")
+	if this.Tags != nil {
+		s = append(s, "Tags: "+fmt.Sprintf("%#v", this.Tags)+",\n")
+	}
+	if this.Locales != nil {
+		s = append(s, "Locales: "+fmt.Sprintf("%#v", this.Locales)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *LocaleText) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 8)
+	s = append(s, "&amp.LocaleText{")
+	s = append(s, "Locale: "+fmt.Sprintf("%#v", this.Locale)+",\n")
+	s = append(s, "Label: "+fmt.Sprintf("%#v", this.Label)+",\n")
+	s = append(s, "Caption: "+fmt.Sprintf("%#v", this.Caption)+",\n")
+	s = append(s, "About: "+fmt.Sprintf("%#v", this.About)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *Ballot) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 4)
+	s = append(s, "&amp.Ballot{")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *NotesEntry) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 4)
+	s = append(s, "&amp.NotesEntry{")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *ChatEntry) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 4)
+	s = append(s, "&amp.ChatEntry{")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *SpreadsheetEntry) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 4)
+	s = append(s, "&amp.SpreadsheetEntry{")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *SpatialPlacement) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 13)
+	s = append(s, "&amp.SpatialPlacement{")
+	s = append(s, "Pos0: "+fmt.Sprintf("%#v", this.Pos0)+",\n")
+	s = append(s, "Pos1: "+fmt.Sprintf("%#v", this.Pos1)+",\n")
+	s = append(s, "Pos2: "+fmt.Sprintf("%#v", this.Pos2)+",\n")
+	s = append(s, "Scale0: "+fmt.Sprintf("%#v", this.Scale0)+",\n")
+	s = append(s, "Scale1: "+fmt.Sprintf("%#v", this.Scale1)+",\n")
+	s = append(s, "Scale2: "+fmt.Sprintf("%#v", this.Scale2)+",\n")
+	s = append(s, "EulerX: "+fmt.Sprintf("%#v", this.EulerX)+",\n")
+	s = append(s, "EulerY: "+fmt.Sprintf("%#v", this.EulerY)+",\n")
+	s = append(s, "EulerZ: "+fmt.Sprintf("%#v", this.EulerZ)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *TagPlayableMedia) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 4)
+	s = append(s, "&amp.TagPlayableMedia{")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *TagPlaylist) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 4)
+	s = append(s, "&amp.TagPlaylist{")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *CryptoKey) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 6)
+	s = append(s, "&amp.CryptoKey{")
+	s = append(s, "CryptoKitID: "+fmt.Sprintf("%#v", this.CryptoKitID)+",\n")
+	s = append(s, "KeyBytes: "+fmt.Sprintf("%#v", this.KeyBytes)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *AuthToken) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 8)
+	s = append(s, "&amp.AuthToken{")
+	s = append(s, "AccessToken: "+fmt.Sprintf("%#v", this.AccessToken)+",\n")
+	s = append(s, "TokenType: "+fmt.Sprintf("%#v", this.TokenType)+",\n")
+	s = append(s, "RefreshToken: "+fmt.Sprintf("%#v", this.RefreshToken)+",\n")
+	s = append(s, "Expiry: "+fmt.Sprintf("%#v", this.Expiry)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *TRS) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 14)
+	s = append(s, "&amp.TRS{")
+	s = append(s, "X1: "+fmt.Sprintf("%#v", this.X1)+",\n")
+	s = append(s, "X2: "+fmt.Sprintf("%#v", this.X2)+",\n")
+	s = append(s, "X3: "+fmt.Sprintf("%#v", this.X3)+",\n")
+	s = append(s, "ScaleMode: "+fmt.Sprintf("%#v", this.ScaleMode)+",\n")
+	s = append(s, "Scale1: "+fmt.Sprintf("%#v", this.Scale1)+",\n")
+	s = append(s, "Scale2: "+fmt.Sprintf("%#v", this.Scale2)+",\n")
+	s = append(s, "Scale3: "+fmt.Sprintf("%#v", this.Scale3)+",\n")
+	s = append(s, "Rotate1: "+fmt.Sprintf("%#v", this.Rotate1)+",\n")
+	s = append(s, "Rotate2: "+fmt.Sprintf("%#v", this.Rotate2)+",\n")
+	s = append(s, "Rotate3: "+fmt.Sprintf("%#v", this.Rotate3)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *DataSegment) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 9)
+	s = append(s, "&amp.DataSegment{")
+	s = append(s, "ByteOfs: "+fmt.Sprintf("%#v", this.ByteOfs)+",\n")
+	s = append(s, "ByteSz: "+fmt.Sprintf("%#v", this.ByteSz)+",\n")
+	s = append(s, "InlineData: "+fmt.Sprintf("%#v", this.InlineData)+",\n")
+	s = append(s, "StreamURI: "+fmt.Sprintf("%#v", this.StreamURI)+",\n")
+	s = append(s, "BlobID: "+fmt.Sprintf("%#v", this.BlobID)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *Err) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 9)
+	s = append(s, "&amp.Err{")
+	s = append(s, "Code: "+fmt.Sprintf("%#v", this.Code)+",\n")
+	s = append(s, "Level: "+fmt.Sprintf("%#v", this.Level)+",\n")
+	s = append(s, "Msg: "+fmt.Sprintf("%#v", this.Msg)+",\n")
+	s = append(s, "Retryable: "+fmt.Sprintf("%#v", this.Retryable)+",\n")
+	if this.Details != nil {
+		s = append(s, "Details: "+fmt.Sprintf("%#v", this.Details)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *ErrDetail) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 6)
+	s = append(s, "&amp.ErrDetail{")
+	s = append(s, "Key: "+fmt.Sprintf("%#v", this.Key)+",\n")
+	s = append(s, "Value: "+fmt.Sprintf("%#v", this.Value)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func valueToGoStringApiAmp(v interface{}, typ string) string {
+	rv := reflect.ValueOf(v)
+	if rv.IsNil() {
+		return "nil"
+	}
+	pv := reflect.Indirect(rv).Interface()
+	return fmt.Sprintf("func(v %v) *%v { return &v } ( %#v )", typ, typ, pv)
+}
+func (m *TxInfo) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TxInfo) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TxInfo) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.RootElementID_2 != 0 {
+		i -= 8
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(m.RootElementID_2))
+		i--
+		dAtA[i] = 0x79
+	}
+	if m.RootElementID_1 != 0 {
+		i -= 8
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(m.RootElementID_1))
+		i--
+		dAtA[i] = 0x71
+	}
+	if m.RootElementID_0 != 0 {
+		i = encodeVarintApiAmp(dAtA, i, uint64(m.RootElementID_0))
+		i--
+		dAtA[i] = 0x68
+	}
+	if m.RequestID_2 != 0 {
+		i -= 8
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(m.RequestID_2))
+		i--
+		dAtA[i] = 0x61
+	}
+	if m.RequestID_1 != 0 {
+		i -= 8
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(m.RequestID_1))
+		i--
+		dAtA[i] = 0x59
+	}
+	if m.RequestID_0 != 0 {
+		i = encodeVarintApiAmp(dAtA, i, uint64(m.RequestID_0))
+		i--
+		dAtA[i] = 0x50
+	}
+	if m.GenesisID_2 != 0 {
+		i -= 8
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(m.GenesisID_2))
+		i--
+		dAtA[i] = 0x39
+	}
+	if m.GenesisID_1 != 0 {
+		i -= 8
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(m.GenesisID_1))
+		i--
+		dAtA[i] = 0x31
+	}
+	if m.GenesisID_0 != 0 {
+		i = encodeVarintApiAmp(dAtA, i, uint64(m.GenesisID_0))
+		i--
+		dAtA[i] = 0x28
+	}
+	if m.NumOps != 0 {
+		i = encodeVarintApiAmp(dAtA, i, uint64(m.NumOps))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.ErrCode != 0 {
+		i = encodeVarintApiAmp(dAtA, i, uint64(m.ErrCode))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.Status != 0 {
+		i = encodeVarintApiAmp(dAtA, i, uint64(m.Status))
+		i--
+		dAtA[i] = 0x10
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Login) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Login) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Login) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Locales) > 0 {
+		for iNdEx := len(m.Locales) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Locales[iNdEx])
+			copy(dAtA[i:], m.Locales[iNdEx])
+			i = encodeVarintApiAmp(dAtA, i, uint64(len(m.Locales[iNdEx])))
+			i--
+			dAtA[i] = 0x6a
+		}
+	}
+	if len(m.MediaTypes) > 0 {
+		for iNdEx := len(m.MediaTypes) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.MediaTypes[iNdEx])
+			copy(dAtA[i:], m.MediaTypes[iNdEx])
+			i = encodeVarintApiAmp(dAtA, i, uint64(len(m.MediaTypes[iNdEx])))
+			i--
+			dAtA[i] = 0x62
+		}
+	}
+	if len(m.Space) > 0 {
+		i -= len(m.Space)
+		copy(dAtA[i:], m.Space)
+		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.Space)))
+		i--
+		dAtA[i] = 0x5a
+	}
+	if m.Checkpoint != nil {
+		{
+			size, err := m.Checkpoint.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintApiAmp(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x52
+	}
+	if len(m.DeviceUID) > 0 {
+		i -= len(m.DeviceUID)
+		copy(dAtA[i:], m.DeviceUID)
+		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.DeviceUID)))
+		i--
+		dAtA[i] = 0x4a
+	}
+	if len(m.DeviceLabel) > 0 {
+		i -= len(m.DeviceLabel)
+		copy(dAtA[i:], m.DeviceLabel)
+		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.DeviceLabel)))
+		i--
+		dAtA[i] = 0x42
+	}
+	if len(m.HostAddr) > 0 {
+		i -= len(m.HostAddr)
+		copy(dAtA[i:], m.HostAddr)
+		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.HostAddr)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.UserUID) > 0 {
+		i -= len(m.UserUID)
+		copy(dAtA[i:], m.UserUID)
+		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.UserUID)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *LoginChallenge) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *LoginChallenge) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *LoginChallenge) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Hash) > 0 {
+		i -= len(m.Hash)
+		copy(dAtA[i:], m.Hash)
+		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.Hash)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *LoginResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *LoginResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *LoginResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.HashResponse) > 0 {
+		i -= len(m.HashResponse)
+		copy(dAtA[i:], m.HashResponse)
+		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.HashResponse)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *AuthCheckpoint) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *AuthCheckpoint) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *AuthCheckpoint) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Member != nil {
+		{
+			size, err := m.Member.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintApiAmp(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x5a
+	}
+	if m.Session != nil {
+		{
+			size, err := m.Session.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintApiAmp(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x52
+	}
+	if m.HomeFeed != nil {
+		{
+			size, err := m.HomeFeed.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintApiAmp(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x4a
+	}
+	if len(m.Token) > 0 {
+		i -= len(m.Token)
+		copy(dAtA[i:], m.Token)
+		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.Token)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *PinRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *PinRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *PinRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.SendWindow != 0 {
+		i = encodeVarintApiAmp(dAtA, i, uint64(m.SendWindow))
+		i--
+		dAtA[i] = 0x70
+	}
+	if m.Prefetch != nil {
+		{
+			size, err := m.Prefetch.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintApiAmp(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x62
+	}
+	if len(m.PinFilter) > 0 {
+		i -= len(m.PinFilter)
+		copy(dAtA[i:], m.PinFilter)
+		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.PinFilter)))
+		i--
+		dAtA[i] = 0x52
+	}
+	if m.PinWindow != nil {
+		{
+			size, err := m.PinWindow.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintApiAmp(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x42
+	}
+	if m.PinSync != 0 {
+		i = encodeVarintApiAmp(dAtA, i, uint64(m.PinSync))
+		i--
+		dAtA[i] = 0x30
+	}
+	if len(m.PinAttrs) > 0 {
+		for iNdEx := len(m.PinAttrs) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.PinAttrs[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintApiAmp(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x22
+		}
+	}
+	if m.PinTarget != nil {
+		{
+			size, err := m.PinTarget.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintApiAmp(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x12
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *PinWindow) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *PinWindow) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *PinWindow) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Cursor) > 0 {
+		i -= len(m.Cursor)
+		copy(dAtA[i:], m.Cursor)
+		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.Cursor)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if m.Limit != 0 {
+		i = encodeVarintApiAmp(dAtA, i, uint64(m.Limit))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.Offset != 0 {
+		i = encodeVarintApiAmp(dAtA, i, uint64(m.Offset))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *PrefetchHint) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *PrefetchHint) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *PrefetchHint) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Attrs) > 0 {
+		for iNdEx := len(m.Attrs) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Attrs[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintApiAmp(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if m.Children != 0 {
+		i = encodeVarintApiAmp(dAtA, i, uint64(m.Children))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *PageInfo) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *PageInfo) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *PageInfo) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Epoch != 0 {
+		i = encodeVarintApiAmp(dAtA, i, uint64(m.Epoch))
+		i--
+		dAtA[i] = 0x30
+	}
+	if len(m.PrevCursor) > 0 {
+		i -= len(m.PrevCursor)
+		copy(dAtA[i:], m.PrevCursor)
+		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.PrevCursor)))
+		i--
+		dAtA[i] = 0x2a
+	}
+	if len(m.NextCursor) > 0 {
+		i -= len(m.NextCursor)
+		copy(dAtA[i:], m.NextCursor)
+		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.NextCursor)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if m.Count != 0 {
+		i = encodeVarintApiAmp(dAtA, i, uint64(m.Count))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.Offset != 0 {
+		i = encodeVarintApiAmp(dAtA, i, uint64(m.Offset))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.Total != 0 {
+		i = encodeVarintApiAmp(dAtA, i, uint64(m.Total))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Progress) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Progress) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Progress) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Total != 0 {
+		i = encodeVarintApiAmp(dAtA, i, uint64(m.Total))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.Done != 0 {
+		i = encodeVarintApiAmp(dAtA, i, uint64(m.Done))
+		i--
+		dAtA[i] = 0x18
+	}
+	if len(m.Stage) > 0 {
+		i -= len(m.Stage)
+		copy(dAtA[i:], m.Stage)
+		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.Stage)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if m.Fraction != 0 {
+		i -= 4
+		encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(m.Fraction))))
+		i--
+		dAtA[i] = 0xd
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *SearchHit) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SearchHit) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SearchHit) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Score != 0 {
+		i -= 4
+		encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(m.Score))))
+		i--
+		dAtA[i] = 0x15
+	}
+	if m.Rank != 0 {
+		i = encodeVarintApiAmp(dAtA, i, uint64(m.Rank))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *LWWRegister) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *LWWRegister) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *LWWRegister) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Replica != 0 {
+		i = encodeVarintApiAmp(dAtA, i, uint64(m.Replica))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.Time != 0 {
+		i = encodeVarintApiAmp(dAtA, i, uint64(m.Time))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Value) > 0 {
+		i -= len(m.Value)
+		copy(dAtA[i:], m.Value)
+		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.Value)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *ORSetEntry) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ORSetEntry) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ORSetEntry) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Replica != 0 {
+		i = encodeVarintApiAmp(dAtA, i, uint64(m.Replica))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.Time != 0 {
+		i = encodeVarintApiAmp(dAtA, i, uint64(m.Time))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Value) > 0 {
+		i -= len(m.Value)
+		copy(dAtA[i:], m.Value)
+		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.Value)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *ORSet) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ORSet) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ORSet) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Removes) > 0 {
+		for iNdEx := len(m.Removes) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Removes[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintApiAmp(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if len(m.Adds) > 0 {
+		for iNdEx := len(m.Adds) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Adds[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintApiAmp(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *RGAElem) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *RGAElem) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *RGAElem) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Removed {
+		i--
+		if m.Removed {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x30
+	}
+	if m.AfterReplica != 0 {
+		i = encodeVarintApiAmp(dAtA, i, uint64(m.AfterReplica))
+		i--
+		dAtA[i] = 0x28
+	}
+	if m.AfterTime != 0 {
+		i = encodeVarintApiAmp(dAtA, i, uint64(m.AfterTime))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.Replica != 0 {
+		i = encodeVarintApiAmp(dAtA, i, uint64(m.Replica))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.Time != 0 {
+		i = encodeVarintApiAmp(dAtA, i, uint64(m.Time))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Value) > 0 {
+		i -= len(m.Value)
+		copy(dAtA[i:], m.Value)
+		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.Value)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *RGAList) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *RGAList) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *RGAList) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Elems) > 0 {
+		for iNdEx := len(m.Elems) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Elems[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintApiAmp(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *MigrationRecord) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *MigrationRecord) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *MigrationRecord) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Desc) > 0 {
+		i -= len(m.Desc)
+		copy(dAtA[i:], m.Desc)
+		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.Desc)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if m.AppliedAt != 0 {
+		i = encodeVarintApiAmp(dAtA, i, uint64(m.AppliedAt))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.Version != 0 {
+		i = encodeVarintApiAmp(dAtA, i, uint64(m.Version))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *MigrationLog) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *MigrationLog) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *MigrationLog) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Applied) > 0 {
+		for iNdEx := len(m.Applied) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Applied[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintApiAmp(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *PinStats) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *PinStats) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *PinStats) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.ClosedAt != 0 {
+		i = encodeVarintApiAmp(dAtA, i, uint64(m.ClosedAt))
+		i--
+		dAtA[i] = 0x50
+	}
+	if m.LastTxAt != 0 {
+		i = encodeVarintApiAmp(dAtA, i, uint64(m.LastTxAt))
+		i--
+		dAtA[i] = 0x48
+	}
+	if m.TxsIn != 0 {
+		i = encodeVarintApiAmp(dAtA, i, uint64(m.TxsIn))
+		i--
+		dAtA[i] = 0x40
+	}
+	if m.BytesOut != 0 {
+		i = encodeVarintApiAmp(dAtA, i, uint64(m.BytesOut))
+		i--
+		dAtA[i] = 0x38
+	}
+	if m.TxsOut != 0 {
+		i = encodeVarintApiAmp(dAtA, i, uint64(m.TxsOut))
+		i--
+		dAtA[i] = 0x30
+	}
+	if m.FirstTxMicros != 0 {
+		i = encodeVarintApiAmp(dAtA, i, uint64(m.FirstTxMicros))
+		i--
+		dAtA[i] = 0x28
+	}
+	if m.OpenedAt != 0 {
+		i = encodeVarintApiAmp(dAtA, i, uint64(m.OpenedAt))
+		i--
+		dAtA[i] = 0x20
+	}
+	if len(m.Target) > 0 {
+		i -= len(m.Target)
+		copy(dAtA[i:], m.Target)
+		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.Target)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.App) > 0 {
+		i -= len(m.App)
+		copy(dAtA[i:], m.App)
+		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.App)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.ReqID) > 0 {
+		i -= len(m.ReqID)
+		copy(dAtA[i:], m.ReqID)
+		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.ReqID)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *BlobChunk) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *BlobChunk) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *BlobChunk) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Data) > 0 {
+		i -= len(m.Data)
+		copy(dAtA[i:], m.Data)
+		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.Data)))
+		i--
+		dAtA[i] = 0x3a
+	}
+	if len(m.ContentType) > 0 {
+		i -= len(m.ContentType)
+		copy(dAtA[i:], m.ContentType)
+		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.ContentType)))
+		i--
+		dAtA[i] = 0x32
+	}
+	if m.Canceled {
+		i--
+		if m.Canceled {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x28
+	}
+	if m.Final {
+		i--
+		if m.Final {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.TotalSize != 0 {
+		i = encodeVarintApiAmp(dAtA, i, uint64(m.TotalSize))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.Offset != 0 {
+		i = encodeVarintApiAmp(dAtA, i, uint64(m.Offset))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.BlobID != 0 {
+		i = encodeVarintApiAmp(dAtA, i, uint64(m.BlobID))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *MediaInfo) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *MediaInfo) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *MediaInfo) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.EncoderPadding != 0 {
+		i = encodeVarintApiAmp(dAtA, i, uint64(m.EncoderPadding))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xd8
+	}
+	if m.EncoderDelay != 0 {
+		i = encodeVarintApiAmp(dAtA, i, uint64(m.EncoderDelay))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xd0
+	}
+	if m.Longitude != 0 {
+		i -= 8
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.Longitude))))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xc9
+	}
+	if m.Latitude != 0 {
+		i -= 8
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.Latitude))))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xc1
+	}
+	if m.HasLocation {
+		i--
+		if m.HasLocation {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xb8
+	}
+	if m.TakenAt != 0 {
+		i = encodeVarintApiAmp(dAtA, i, uint64(m.TakenAt))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xb0
+	}
+	if len(m.CameraModel) > 0 {
+		i -= len(m.CameraModel)
+		copy(dAtA[i:], m.CameraModel)
+		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.CameraModel)))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xaa
+	}
+	if len(m.CameraMake) > 0 {
+		i -= len(m.CameraMake)
+		copy(dAtA[i:], m.CameraMake)
+		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.CameraMake)))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xa2
+	}
+	if m.Orientation != 0 {
+		i = encodeVarintApiAmp(dAtA, i, uint64(m.Orientation))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0x98
+	}
+	if m.Height != 0 {
+		i = encodeVarintApiAmp(dAtA, i, uint64(m.Height))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0x90
+	}
+	if m.Width != 0 {
+		i = encodeVarintApiAmp(dAtA, i, uint64(m.Width))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0x88
+	}
+	if m.Channels != 0 {
+		i = encodeVarintApiAmp(dAtA, i, uint64(m.Channels))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0x80
+	}
+	if m.SampleRate != 0 {
+		i = encodeVarintApiAmp(dAtA, i, uint64(m.SampleRate))
+		i--
+		dAtA[i] = 0x78
+	}
+	if m.DurationMs != 0 {
+		i = encodeVarintApiAmp(dAtA, i, uint64(m.DurationMs))
+		i--
+		dAtA[i] = 0x70
+	}
+	if m.DiscCount != 0 {
+		i = encodeVarintApiAmp(dAtA, i, uint64(m.DiscCount))
+		i--
+		dAtA[i] = 0x68
+	}
+	if m.DiscNum != 0 {
+		i = encodeVarintApiAmp(dAtA, i, uint64(m.DiscNum))
+		i--
+		dAtA[i] = 0x60
+	}
+	if m.TrackCount != 0 {
+		i = encodeVarintApiAmp(dAtA, i, uint64(m.TrackCount))
+		i--
+		dAtA[i] = 0x58
+	}
+	if m.TrackNum != 0 {
+		i = encodeVarintApiAmp(dAtA, i, uint64(m.TrackNum))
+		i--
+		dAtA[i] = 0x50
+	}
+	if m.Year != 0 {
+		i = encodeVarintApiAmp(dAtA, i, uint64(m.Year))
+		i--
+		dAtA[i] = 0x48
+	}
+	if len(m.Comment) > 0 {
+		i -= len(m.Comment)
+		copy(dAtA[i:], m.Comment)
+		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.Comment)))
+		i--
+		dAtA[i] = 0x42
+	}
+	if len(m.Genre) > 0 {
+		i -= len(m.Genre)
+		copy(dAtA[i:], m.Genre)
+		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.Genre)))
+		i--
+		dAtA[i] = 0x3a
+	}
+	if len(m.Composer) > 0 {
+		i -= len(m.Composer)
+		copy(dAtA[i:], m.Composer)
+		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.Composer)))
+		i--
+		dAtA[i] = 0x32
+	}
+	if len(m.AlbumArtist) > 0 {
+		i -= len(m.AlbumArtist)
+		copy(dAtA[i:], m.AlbumArtist)
+		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.AlbumArtist)))
+		i--
+		dAtA[i] = 0x2a
+	}
+	if len(m.Album) > 0 {
+		i -= len(m.Album)
+		copy(dAtA[i:], m.Album)
+		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.Album)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if len(m.Artist) > 0 {
+		i -= len(m.Artist)
+		copy(dAtA[i:], m.Artist)
+		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.Artist)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.Title) > 0 {
+		i -= len(m.Title)
+		copy(dAtA[i:], m.Title)
+		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.Title)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.ContentType) > 0 {
+		i -= len(m.ContentType)
+		copy(dAtA[i:], m.ContentType)
+		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.ContentType)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *WaveformPeaks) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *WaveformPeaks) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *WaveformPeaks) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Peaks) > 0 {
+		i -= len(m.Peaks)
+		copy(dAtA[i:], m.Peaks)
+		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.Peaks)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if m.DurationMs != 0 {
+		i = encodeVarintApiAmp(dAtA, i, uint64(m.DurationMs))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *PlaylistEntry) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *PlaylistEntry) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *PlaylistEntry) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Resolved) > 0 {
+		i -= len(m.Resolved)
+		copy(dAtA[i:], m.Resolved)
+		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.Resolved)))
+		i--
+		dAtA[i] = 0x32
+	}
+	if m.DurationMs != 0 {
+		i = encodeVarintApiAmp(dAtA, i, uint64(m.DurationMs))
+		i--
+		dAtA[i] = 0x28
+	}
+	if len(m.Album) > 0 {
+		i -= len(m.Album)
+		copy(dAtA[i:], m.Album)
+		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.Album)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if len(m.Artist) > 0 {
+		i -= len(m.Artist)
+		copy(dAtA[i:], m.Artist)
+		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.Artist)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.Title) > 0 {
+		i -= len(m.Title)
+		copy(dAtA[i:], m.Title)
+		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.Title)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Location) > 0 {
+		i -= len(m.Location)
+		copy(dAtA[i:], m.Location)
+		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.Location)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *PlaybackEvent) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *PlaybackEvent) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *PlaybackEvent) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.At != 0 {
+		i = encodeVarintApiAmp(dAtA, i, uint64(m.At))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.PositionMs != 0 {
+		i = encodeVarintApiAmp(dAtA, i, uint64(m.PositionMs))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.State != 0 {
+		i = encodeVarintApiAmp(dAtA, i, uint64(m.State))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *TrackOffset) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TrackOffset) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TrackOffset) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.PregapMs != 0 {
+		i = encodeVarintApiAmp(dAtA, i, uint64(m.PregapMs))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.DurationMs != 0 {
+		i = encodeVarintApiAmp(dAtA, i, uint64(m.DurationMs))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.StartMs != 0 {
+		i = encodeVarintApiAmp(dAtA, i, uint64(m.StartMs))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *GeoPoint) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *GeoPoint) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *GeoPoint) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Alt != 0 {
+		i -= 8
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.Alt))))
+		i--
+		dAtA[i] = 0x19
+	}
+	if m.Lon != 0 {
+		i -= 8
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.Lon))))
+		i--
+		dAtA[i] = 0x11
+	}
+	if m.Lat != 0 {
+		i -= 8
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.Lat))))
+		i--
+		dAtA[i] = 0x9
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *TableColumn) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TableColumn) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TableColumn) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Unit) > 0 {
+		i -= len(m.Unit)
+		copy(dAtA[i:], m.Unit)
+		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.Unit)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if m.Type != 0 {
+		i = encodeVarintApiAmp(dAtA, i, uint64(m.Type))
+		i--
+		dAtA[i] = 0x18
+	}
+	if len(m.Label) > 0 {
+		i -= len(m.Label)
+		copy(dAtA[i:], m.Label)
+		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.Label)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Name) > 0 {
+		i -= len(m.Name)
+		copy(dAtA[i:], m.Name)
+		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.Name)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *TableSortKey) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TableSortKey) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TableSortKey) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Desc {
+		i--
+		if m.Desc {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Column) > 0 {
+		i -= len(m.Column)
+		copy(dAtA[i:], m.Column)
+		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.Column)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *TableHeader) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TableHeader) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TableHeader) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Sort) > 0 {
+		for iNdEx := len(m.Sort) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Sort[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintApiAmp(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if len(m.Columns) > 0 {
+		for iNdEx := len(m.Columns) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Columns[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintApiAmp(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *TableValue) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TableValue) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TableValue) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Bytes) > 0 {
+		i -= len(m.Bytes)
+		copy(dAtA[i:], m.Bytes)
+		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.Bytes)))
+		i--
+		dAtA[i] = 0x32
+	}
+	if m.Bool {
+		i--
+		if m.Bool {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x28
+	}
+	if m.Float != 0 {
+		i -= 8
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.Float))))
+		i--
+		dAtA[i] = 0x21
+	}
+	if m.Int != 0 {
+		i = encodeVarintApiAmp(dAtA, i, uint64(m.Int))
+		i--
+		dAtA[i] = 0x18
+	}
+	if len(m.Text) > 0 {
+		i -= len(m.Text)
+		copy(dAtA[i:], m.Text)
+		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.Text)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if m.Null {
+		i--
+		if m.Null {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *TableRow) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TableRow) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TableRow) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Values) > 0 {
+		for iNdEx := len(m.Values) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Values[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintApiAmp(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *LaunchURL) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *LaunchURL) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *LaunchURL) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.URL) > 0 {
+		i -= len(m.URL)
+		copy(dAtA[i:], m.URL)
+		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.URL)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Position) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Position) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Position) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.ROU != 0 {
+		i -= 4
+		encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(m.ROU))))
+		i--
+		dAtA[i] = 0x35
+	}
+	if m.W != 0 {
+		i -= 8
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.W))))
+		i--
+		dAtA[i] = 0x29
+	}
+	if m.V != 0 {
+		i -= 8
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.V))))
+		i--
+		dAtA[i] = 0x21
+	}
+	if m.U != 0 {
+		i -= 8
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.U))))
+		i--
+		dAtA[i] = 0x19
+	}
+	if m.CordType != 0 {
+		i = encodeVarintApiAmp(dAtA, i, uint64(m.CordType))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Tag) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Tag) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Tag) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Size_2 != 0 {
+		i -= 4
+		encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(m.Size_2))))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xed
+	}
+	if m.Size_1 != 0 {
+		i -= 4
+		encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(m.Size_1))))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xe5
+	}
+	if m.Size_0 != 0 {
+		i -= 4
+		encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(m.Size_0))))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xdd
+	}
+	if m.Metric != 0 {
+		i = encodeVarintApiAmp(dAtA, i, uint64(m.Metric))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xd0
+	}
+	if len(m.Attachment) > 0 {
+		i -= len(m.Attachment)
+		copy(dAtA[i:], m.Attachment)
+		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.Attachment)))
+		i--
+		dAtA[i] = 0x7a
+	}
+	if len(m.ContentType) > 0 {
+		i -= len(m.ContentType)
+		copy(dAtA[i:], m.ContentType)
+		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.ContentType)))
+		i--
+		dAtA[i] = 0x72
+	}
+	if len(m.URL) > 0 {
+		i -= len(m.URL)
+		copy(dAtA[i:], m.URL)
+		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.URL)))
+		i--
+		dAtA[i] = 0x6a
+	}
+	if m.TagID_2 != 0 {
+		i -= 8
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(m.TagID_2))
+		i--
+		dAtA[i] = 0x21
+	}
+	if m.TagID_1 != 0 {
+		i -= 8
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(m.TagID_1))
+		i--
+		dAtA[i] = 0x19
+	}
+	if m.TagID_0 != 0 {
+		i = encodeVarintApiAmp(dAtA, i, uint64(m.TagID_0))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.Use != 0 {
+		i = encodeVarintApiAmp(dAtA, i, uint64(m.Use))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *TagTab) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TagTab) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TagTab) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Locales) > 0 {
+		for iNdEx := len(m.Locales) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Locales[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintApiAmp(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x52
+		}
+	}
+	if len(m.Tags) > 0 {
+		for iNdEx := len(m.Tags) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Tags[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintApiAmp(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x4a
+		}
+	}
+	if m.ModifiedAt != 0 {
+		i = encodeVarintApiAmp(dAtA, i, uint64(m.ModifiedAt))
+		i--
+		dAtA[i] = 0x38
+	}
+	if m.CreatedAt != 0 {
+		i = encodeVarintApiAmp(dAtA, i, uint64(m.CreatedAt))
+		i--
+		dAtA[i] = 0x30
+	}
+	if len(m.About) > 0 {
+		i -= len(m.About)
+		copy(dAtA[i:], m.About)
+		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.About)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.Caption) > 0 {
+		i -= len(m.Caption)
+		copy(dAtA[i:], m.Caption)
+		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.Caption)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Label) > 0 {
+		i -= len(m.Label)
+		copy(dAtA[i:], m.Label)
+		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.Label)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *LocaleText) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *LocaleText) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *LocaleText) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.About) > 0 {
+		i -= len(m.About)
+		copy(dAtA[i:], m.About)
+		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.About)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if len(m.Caption) > 0 {
+		i -= len(m.Caption)
+		copy(dAtA[i:], m.Caption)
+		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.Caption)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.Label) > 0 {
+		i -= len(m.Label)
+		copy(dAtA[i:], m.Label)
+		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.Label)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Locale) > 0 {
+		i -= len(m.Locale)
+		copy(dAtA[i:], m.Locale)
+		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.Locale)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Ballot) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Ballot) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Ballot) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	return len(dAtA) - i, nil
+}
+
+func (m *NotesEntry) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *NotesEntry) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *NotesEntry) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	return len(dAtA) - i, nil
+}
+
+func (m *ChatEntry) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ChatEntry) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ChatEntry) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	return len(dAtA) - i, nil
+}
+
+func (m *SpreadsheetEntry) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SpreadsheetEntry) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SpreadsheetEntry) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	return len(dAtA) - i, nil
+}
+
+func (m *SpatialPlacement) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SpatialPlacement) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SpatialPlacement) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.EulerZ != 0 {
+		i -= 4
+		encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(m.EulerZ))))
+		i--
+		dAtA[i] = 0x4d
+	}
+	if m.EulerY != 0 {
+		i -= 4
+		encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(m.EulerY))))
+		i--
+		dAtA[i] = 0x45
+	}
+	if m.EulerX != 0 {
+		i -= 4
+		encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(m.EulerX))))
+		i--
+		dAtA[i] = 0x3d
+	}
+	if m.Scale2 != 0 {
+		i -= 4
+		encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(m.Scale2))))
+		i--
+		dAtA[i] = 0x35
+	}
+	if m.Scale1 != 0 {
+		i -= 4
+		encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(m.Scale1))))
+		i--
+		dAtA[i] = 0x2d
+	}
+	if m.Scale0 != 0 {
+		i -= 4
+		encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(m.Scale0))))
+		i--
+		dAtA[i] = 0x25
+	}
+	if m.Pos2 != 0 {
+		i -= 8
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.Pos2))))
+		i--
+		dAtA[i] = 0x19
+	}
+	if m.Pos1 != 0 {
+		i -= 8
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.Pos1))))
+		i--
+		dAtA[i] = 0x11
+	}
+	if m.Pos0 != 0 {
+		i -= 8
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.Pos0))))
+		i--
+		dAtA[i] = 0x9
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *TagPlayableMedia) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TagPlayableMedia) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TagPlayableMedia) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	return len(dAtA) - i, nil
+}
+
+func (m *TagPlaylist) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TagPlaylist) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TagPlaylist) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	return len(dAtA) - i, nil
+}
+
+func (m *CryptoKey) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *CryptoKey) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *CryptoKey) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.KeyBytes) > 0 {
+		i -= len(m.KeyBytes)
+		copy(dAtA[i:], m.KeyBytes)
+		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.KeyBytes)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if m.CryptoKitID != 0 {
+		i = encodeVarintApiAmp(dAtA, i, uint64(m.CryptoKitID))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *AuthToken) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *AuthToken) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *AuthToken) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Expiry != 0 {
+		i = encodeVarintApiAmp(dAtA, i, uint64(m.Expiry))
+		i--
+		dAtA[i] = 0x20
+	}
+	if len(m.RefreshToken) > 0 {
+		i -= len(m.RefreshToken)
+		copy(dAtA[i:], m.RefreshToken)
+		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.RefreshToken)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.TokenType) > 0 {
+		i -= len(m.TokenType)
+		copy(dAtA[i:], m.TokenType)
+		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.TokenType)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.AccessToken) > 0 {
+		i -= len(m.AccessToken)
+		copy(dAtA[i:], m.AccessToken)
+		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.AccessToken)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *TRS) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TRS) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TRS) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Rotate3 != 0 {
+		i -= 4
+		encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(m.Rotate3))))
+		i--
+		dAtA[i] = 0x3
+		i--
+		dAtA[i] = 0xfd
+	}
+	if m.Rotate2 != 0 {
+		i -= 4
+		encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(m.Rotate2))))
+		i--
+		dAtA[i] = 0x3
+		i--
+		dAtA[i] = 0xf5
+	}
+	if m.Rotate1 != 0 {
+		i -= 4
+		encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(m.Rotate1))))
+		i--
+		dAtA[i] = 0x3
+		i--
+		dAtA[i] = 0xed
+	}
+	if m.Scale3 != 0 {
+		i -= 4
+		encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(m.Scale3))))
+		i--
+		dAtA[i] = 0x3
+		i--
+		dAtA[i] = 0xad
+	}
+	if m.Scale2 != 0 {
+		i -= 4
+		encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(m.Scale2))))
+		i--
+		dAtA[i] = 0x3
+		i--
+		dAtA[i] = 0xa5
+	}
+	if m.Scale1 != 0 {
+		i -= 4
+		encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(m.Scale1))))
+		i--
+		dAtA[i] = 0x3
+		i--
+		dAtA[i] = 0x9d
+	}
+	if m.ScaleMode != 0 {
+		i = encodeVarintApiAmp(dAtA, i, uint64(m.ScaleMode))
+		i--
+		dAtA[i] = 0x3
+		i--
+		dAtA[i] = 0x90
+	}
+	if m.X3 != 0 {
+		i -= 8
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.X3))))
+		i--
+		dAtA[i] = 0x2
+		i--
+		dAtA[i] = 0xd9
+	}
+	if m.X2 != 0 {
+		i -= 8
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.X2))))
+		i--
+		dAtA[i] = 0x2
+		i--
+		dAtA[i] = 0xd1
+	}
+	if m.X1 != 0 {
+		i -= 8
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.X1))))
+		i--
+		dAtA[i] = 0x2
+		i--
+		dAtA[i] = 0xc9
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *DataSegment) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *DataSegment) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *DataSegment) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.BlobID != 0 {
+		i = encodeVarintApiAmp(dAtA, i, uint64(m.BlobID))
+		i--
+		dAtA[i] = 0x50
+	}
+	if len(m.StreamURI) > 0 {
+		i -= len(m.StreamURI)
+		copy(dAtA[i:], m.StreamURI)
+		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.StreamURI)))
+		i--
+		dAtA[i] = 0x4a
+	}
+	if len(m.InlineData) > 0 {
+		i -= len(m.InlineData)
+		copy(dAtA[i:], m.InlineData)
+		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.InlineData)))
+		i--
+		dAtA[i] = 0x3a
+	}
+	if m.ByteSz != 0 {
+		i = encodeVarintApiAmp(dAtA, i, uint64(m.ByteSz))
+		i--
+		dAtA[i] = 0x30
+	}
+	if m.ByteOfs != 0 {
+		i = encodeVarintApiAmp(dAtA, i, uint64(m.ByteOfs))
+		i--
+		dAtA[i] = 0x28
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Err) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Err) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Err) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Details) > 0 {
+		for iNdEx := len(m.Details) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Details[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintApiAmp(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x32
+		}
+	}
+	if m.Retryable {
+		i--
+		if m.Retryable {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x28
+	}
+	if len(m.Msg) > 0 {
+		i -= len(m.Msg)
+		copy(dAtA[i:], m.Msg)
+		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.Msg)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if m.Level != 0 {
+		i = encodeVarintApiAmp(dAtA, i, uint64(m.Level))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.Code != 0 {
+		i = encodeVarintApiAmp(dAtA, i, uint64(m.Code))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *ErrDetail) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ErrDetail) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ErrDetail) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Value) > 0 {
+		i -= len(m.Value)
+		copy(dAtA[i:], m.Value)
+		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.Value)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Key) > 0 {
+		i -= len(m.Key)
+		copy(dAtA[i:], m.Key)
+		i = encodeVarintApiAmp(dAtA, i, uint64(len(m.Key)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func encodeVarintApiAmp(dAtA []byte, offset int, v uint64) int {
+	offset -= sovApiAmp(v)
+	base := offset
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return base
+}
+func (m *TxInfo) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Status != 0 {
+		n += 1 + sovApiAmp(uint64(m.Status))
+	}
+	if m.ErrCode != 0 {
+		n += 1 + sovApiAmp(uint64(m.ErrCode))
+	}
+	if m.NumOps != 0 {
+		n += 1 + sovApiAmp(uint64(m.NumOps))
+	}
+	if m.GenesisID_0 != 0 {
+		n += 1 + sovApiAmp(uint64(m.GenesisID_0))
+	}
+	if m.GenesisID_1 != 0 {
+		n += 9
+	}
+	if m.GenesisID_2 != 0 {
+		n += 9
+	}
+	if m.RequestID_0 != 0 {
+		n += 1 + sovApiAmp(uint64(m.RequestID_0))
+	}
+	if m.RequestID_1 != 0 {
+		n += 9
+	}
+	if m.RequestID_2 != 0 {
+		n += 9
+	}
+	if m.RootElementID_0 != 0 {
+		n += 1 + sovApiAmp(uint64(m.RootElementID_0))
+	}
+	if m.RootElementID_1 != 0 {
+		n += 9
+	}
+	if m.RootElementID_2 != 0 {
+		n += 9
+	}
+	return n
+}
+
+func (m *Login) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.UserUID)
+	if l > 0 {
+		n += 1 + l + sovApiAmp(uint64(l))
+	}
+	l = len(m.HostAddr)
+	if l > 0 {
+		n += 1 + l + sovApiAmp(uint64(l))
+	}
+	l = len(m.DeviceLabel)
+	if l > 0 {
+		n += 1 + l + sovApiAmp(uint64(l))
+	}
+	l = len(m.DeviceUID)
+	if l > 0 {
+		n += 1 + l + sovApiAmp(uint64(l))
+	}
+	if m.Checkpoint != nil {
+		l = m.Checkpoint.Size()
+		n += 1 + l + sovApiAmp(uint64(l))
+	}
+	l = len(m.Space)
+	if l > 0 {
+		n += 1 + l + sovApiAmp(uint64(l))
+	}
+	if len(m.MediaTypes) > 0 {
+		for _, s := range m.MediaTypes {
+			l = len(s)
+			n += 1 + l + sovApiAmp(uint64(l))
+		}
+	}
+	if len(m.Locales) > 0 {
+		for _, s := range m.Locales {
+			l = len(s)
+			n += 1 + l + sovApiAmp(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *LoginChallenge) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Hash)
+	if l > 0 {
+		n += 1 + l + sovApiAmp(uint64(l))
+	}
+	return n
+}
+
+func (m *LoginResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.HashResponse)
+	if l > 0 {
+		n += 1 + l + sovApiAmp(uint64(l))
+	}
+	return n
+}
+
+func (m *AuthCheckpoint) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Token)
+	if l > 0 {
+		n += 1 + l + sovApiAmp(uint64(l))
+	}
+	if m.HomeFeed != nil {
+		l = m.HomeFeed.Size()
+		n += 1 + l + sovApiAmp(uint64(l))
+	}
+	if m.Session != nil {
+		l = m.Session.Size()
+		n += 1 + l + sovApiAmp(uint64(l))
+	}
+	if m.Member != nil {
+		l = m.Member.Size()
+		n += 1 + l + sovApiAmp(uint64(l))
+	}
+	return n
+}
+
+func (m *PinRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.PinTarget != nil {
+		l = m.PinTarget.Size()
+		n += 1 + l + sovApiAmp(uint64(l))
+	}
+	if len(m.PinAttrs) > 0 {
+		for _, e := range m.PinAttrs {
+			l = e.Size()
+			n += 1 + l + sovApiAmp(uint64(l))
+		}
+	}
+	if m.PinSync != 0 {
+		n += 1 + sovApiAmp(uint64(m.PinSync))
+	}
+	if m.PinWindow != nil {
+		l = m.PinWindow.Size()
+		n += 1 + l + sovApiAmp(uint64(l))
+	}
+	l = len(m.PinFilter)
+	if l > 0 {
+		n += 1 + l + sovApiAmp(uint64(l))
+	}
+	if m.Prefetch != nil {
+		l = m.Prefetch.Size()
+		n += 1 + l + sovApiAmp(uint64(l))
+	}
+	if m.SendWindow != 0 {
+		n += 1 + sovApiAmp(uint64(m.SendWindow))
+	}
+	return n
+}
+
+func (m *PinWindow) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Offset != 0 {
+		n += 1 + sovApiAmp(uint64(m.Offset))
+	}
+	if m.Limit != 0 {
+		n += 1 + sovApiAmp(uint64(m.Limit))
+	}
+	l = len(m.Cursor)
+	if l > 0 {
+		n += 1 + l + sovApiAmp(uint64(l))
+	}
+	return n
+}
+
+func (m *PrefetchHint) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Children != 0 {
+		n += 1 + sovApiAmp(uint64(m.Children))
+	}
+	if len(m.Attrs) > 0 {
+		for _, e := range m.Attrs {
+			l = e.Size()
+			n += 1 + l + sovApiAmp(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *PageInfo) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Total != 0 {
+		n += 1 + sovApiAmp(uint64(m.Total))
+	}
+	if m.Offset != 0 {
+		n += 1 + sovApiAmp(uint64(m.Offset))
+	}
+	if m.Count != 0 {
+		n += 1 + sovApiAmp(uint64(m.Count))
+	}
+	l = len(m.NextCursor)
+	if l > 0 {
+		n += 1 + l + sovApiAmp(uint64(l))
+	}
+	l = len(m.PrevCursor)
+	if l > 0 {
+		n += 1 + l + sovApiAmp(uint64(l))
+	}
+	if m.Epoch != 0 {
+		n += 1 + sovApiAmp(uint64(m.Epoch))
+	}
+	return n
+}
+
+func (m *Progress) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Fraction != 0 {
+		n += 5
+	}
+	l = len(m.Stage)
+	if l > 0 {
+		n += 1 + l + sovApiAmp(uint64(l))
+	}
+	if m.Done != 0 {
+		n += 1 + sovApiAmp(uint64(m.Done))
+	}
+	if m.Total != 0 {
+		n += 1 + sovApiAmp(uint64(m.Total))
+	}
+	return n
+}
+
+func (m *SearchHit) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Rank != 0 {
+		n += 1 + sovApiAmp(uint64(m.Rank))
+	}
+	if m.Score != 0 {
+		n += 5
+	}
+	return n
+}
+
+func (m *LWWRegister) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Value)
+	if l > 0 {
+		n += 1 + l + sovApiAmp(uint64(l))
+	}
+	if m.Time != 0 {
+		n += 1 + sovApiAmp(uint64(m.Time))
+	}
+	if m.Replica != 0 {
+		n += 1 + sovApiAmp(uint64(m.Replica))
+	}
+	return n
+}
+
+func (m *ORSetEntry) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Value)
+	if l > 0 {
+		n += 1 + l + sovApiAmp(uint64(l))
+	}
+	if m.Time != 0 {
+		n += 1 + sovApiAmp(uint64(m.Time))
+	}
+	if m.Replica != 0 {
+		n += 1 + sovApiAmp(uint64(m.Replica))
+	}
+	return n
+}
+
+func (m *ORSet) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Adds) > 0 {
+		for _, e := range m.Adds {
+			l = e.Size()
+			n += 1 + l + sovApiAmp(uint64(l))
+		}
+	}
+	if len(m.Removes) > 0 {
+		for _, e := range m.Removes {
+			l = e.Size()
+			n += 1 + l + sovApiAmp(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *RGAElem) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Value)
+	if l > 0 {
+		n += 1 + l + sovApiAmp(uint64(l))
+	}
+	if m.Time != 0 {
+		n += 1 + sovApiAmp(uint64(m.Time))
+	}
+	if m.Replica != 0 {
+		n += 1 + sovApiAmp(uint64(m.Replica))
+	}
+	if m.AfterTime != 0 {
+		n += 1 + sovApiAmp(uint64(m.AfterTime))
+	}
+	if m.AfterReplica != 0 {
+		n += 1 + sovApiAmp(uint64(m.AfterReplica))
+	}
+	if m.Removed {
+		n += 2
+	}
+	return n
+}
+
+func (m *RGAList) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Elems) > 0 {
+		for _, e := range m.Elems {
+			l = e.Size()
+			n += 1 + l + sovApiAmp(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *MigrationRecord) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Version != 0 {
+		n += 1 + sovApiAmp(uint64(m.Version))
+	}
+	if m.AppliedAt != 0 {
+		n += 1 + sovApiAmp(uint64(m.AppliedAt))
+	}
+	l = len(m.Desc)
+	if l > 0 {
+		n += 1 + l + sovApiAmp(uint64(l))
+	}
+	return n
+}
+
+func (m *MigrationLog) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Applied) > 0 {
+		for _, e := range m.Applied {
+			l = e.Size()
+			n += 1 + l + sovApiAmp(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *PinStats) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.ReqID)
+	if l > 0 {
+		n += 1 + l + sovApiAmp(uint64(l))
+	}
+	l = len(m.App)
+	if l > 0 {
+		n += 1 + l + sovApiAmp(uint64(l))
+	}
+	l = len(m.Target)
+	if l > 0 {
+		n += 1 + l + sovApiAmp(uint64(l))
+	}
+	if m.OpenedAt != 0 {
+		n += 1 + sovApiAmp(uint64(m.OpenedAt))
+	}
+	if m.FirstTxMicros != 0 {
+		n += 1 + sovApiAmp(uint64(m.FirstTxMicros))
+	}
+	if m.TxsOut != 0 {
+		n += 1 + sovApiAmp(uint64(m.TxsOut))
+	}
+	if m.BytesOut != 0 {
+		n += 1 + sovApiAmp(uint64(m.BytesOut))
+	}
+	if m.TxsIn != 0 {
+		n += 1 + sovApiAmp(uint64(m.TxsIn))
+	}
+	if m.LastTxAt != 0 {
+		n += 1 + sovApiAmp(uint64(m.LastTxAt))
+	}
+	if m.ClosedAt != 0 {
+		n += 1 + sovApiAmp(uint64(m.ClosedAt))
+	}
+	return n
+}
+
+func (m *BlobChunk) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.BlobID != 0 {
+		n += 1 + sovApiAmp(uint64(m.BlobID))
+	}
+	if m.Offset != 0 {
+		n += 1 + sovApiAmp(uint64(m.Offset))
+	}
+	if m.TotalSize != 0 {
+		n += 1 + sovApiAmp(uint64(m.TotalSize))
+	}
+	if m.Final {
+		n += 2
+	}
+	if m.Canceled {
+		n += 2
+	}
+	l = len(m.ContentType)
+	if l > 0 {
+		n += 1 + l + sovApiAmp(uint64(l))
+	}
+	l = len(m.Data)
+	if l > 0 {
+		n += 1 + l + sovApiAmp(uint64(l))
+	}
+	return n
+}
+
+func (m *MediaInfo) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.ContentType)
+	if l > 0 {
+		n += 1 + l + sovApiAmp(uint64(l))
+	}
+	l = len(m.Title)
+	if l > 0 {
+		n += 1 + l + sovApiAmp(uint64(l))
+	}
+	l = len(m.Artist)
+	if l > 0 {
+		n += 1 + l + sovApiAmp(uint64(l))
+	}
+	l = len(m.Album)
+	if l > 0 {
+		n += 1 + l + sovApiAmp(uint64(l))
+	}
+	l = len(m.AlbumArtist)
+	if l > 0 {
+		n += 1 + l + sovApiAmp(uint64(l))
+	}
+	l = len(m.Composer)
+	if l > 0 {
+		n += 1 + l + sovApiAmp(uint64(l))
+	}
+	l = len(m.Genre)
+	if l > 0 {
+		n += 1 + l + sovApiAmp(uint64(l))
+	}
+	l = len(m.Comment)
+	if l > 0 {
+		n += 1 + l + sovApiAmp(uint64(l))
+	}
+	if m.Year != 0 {
+		n += 1 + sovApiAmp(uint64(m.Year))
+	}
+	if m.TrackNum != 0 {
+		n += 1 + sovApiAmp(uint64(m.TrackNum))
+	}
+	if m.TrackCount != 0 {
+		n += 1 + sovApiAmp(uint64(m.TrackCount))
+	}
+	if m.DiscNum != 0 {
+		n += 1 + sovApiAmp(uint64(m.DiscNum))
+	}
+	if m.DiscCount != 0 {
+		n += 1 + sovApiAmp(uint64(m.DiscCount))
+	}
+	if m.DurationMs != 0 {
+		n += 1 + sovApiAmp(uint64(m.DurationMs))
+	}
+	if m.SampleRate != 0 {
+		n += 1 + sovApiAmp(uint64(m.SampleRate))
+	}
+	if m.Channels != 0 {
+		n += 2 + sovApiAmp(uint64(m.Channels))
+	}
+	if m.Width != 0 {
+		n += 2 + sovApiAmp(uint64(m.Width))
+	}
+	if m.Height != 0 {
+		n += 2 + sovApiAmp(uint64(m.Height))
+	}
+	if m.Orientation != 0 {
+		n += 2 + sovApiAmp(uint64(m.Orientation))
+	}
+	l = len(m.CameraMake)
+	if l > 0 {
+		n += 2 + l + sovApiAmp(uint64(l))
+	}
+	l = len(m.CameraModel)
+	if l > 0 {
+		n += 2 + l + sovApiAmp(uint64(l))
+	}
+	if m.TakenAt != 0 {
+		n += 2 + sovApiAmp(uint64(m.TakenAt))
+	}
+	if m.HasLocation {
+		n += 3
+	}
+	if m.Latitude != 0 {
+		n += 10
+	}
+	if m.Longitude != 0 {
+		n += 10
+	}
+	if m.EncoderDelay != 0 {
+		n += 2 + sovApiAmp(uint64(m.EncoderDelay))
+	}
+	if m.EncoderPadding != 0 {
+		n += 2 + sovApiAmp(uint64(m.EncoderPadding))
+	}
+	return n
+}
+
+func (m *WaveformPeaks) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.DurationMs != 0 {
+		n += 1 + sovApiAmp(uint64(m.DurationMs))
+	}
+	l = len(m.Peaks)
+	if l > 0 {
+		n += 1 + l + sovApiAmp(uint64(l))
+	}
+	return n
+}
+
+func (m *PlaylistEntry) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Location)
+	if l > 0 {
+		n += 1 + l + sovApiAmp(uint64(l))
+	}
+	l = len(m.Title)
+	if l > 0 {
+		n += 1 + l + sovApiAmp(uint64(l))
+	}
+	l = len(m.Artist)
+	if l > 0 {
+		n += 1 + l + sovApiAmp(uint64(l))
+	}
+	l = len(m.Album)
+	if l > 0 {
+		n += 1 + l + sovApiAmp(uint64(l))
+	}
+	if m.DurationMs != 0 {
+		n += 1 + sovApiAmp(uint64(m.DurationMs))
+	}
+	l = len(m.Resolved)
+	if l > 0 {
+		n += 1 + l + sovApiAmp(uint64(l))
+	}
+	return n
+}
+
+func (m *PlaybackEvent) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.State != 0 {
+		n += 1 + sovApiAmp(uint64(m.State))
+	}
+	if m.PositionMs != 0 {
+		n += 1 + sovApiAmp(uint64(m.PositionMs))
+	}
+	if m.At != 0 {
+		n += 1 + sovApiAmp(uint64(m.At))
+	}
+	return n
+}
+
+func (m *TrackOffset) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.StartMs != 0 {
+		n += 1 + sovApiAmp(uint64(m.StartMs))
+	}
+	if m.DurationMs != 0 {
+		n += 1 + sovApiAmp(uint64(m.DurationMs))
+	}
+	if m.PregapMs != 0 {
+		n += 1 + sovApiAmp(uint64(m.PregapMs))
+	}
+	return n
+}
+
+func (m *GeoPoint) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Lat != 0 {
+		n += 9
+	}
+	if m.Lon != 0 {
+		n += 9
+	}
+	if m.Alt != 0 {
+		n += 9
+	}
+	return n
+}
+
+func (m *TableColumn) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovApiAmp(uint64(l))
+	}
+	l = len(m.Label)
+	if l > 0 {
+		n += 1 + l + sovApiAmp(uint64(l))
+	}
+	if m.Type != 0 {
+		n += 1 + sovApiAmp(uint64(m.Type))
+	}
+	l = len(m.Unit)
+	if l > 0 {
+		n += 1 + l + sovApiAmp(uint64(l))
+	}
+	return n
+}
+
+func (m *TableSortKey) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Column)
+	if l > 0 {
+		n += 1 + l + sovApiAmp(uint64(l))
+	}
+	if m.Desc {
+		n += 2
+	}
+	return n
+}
+
+func (m *TableHeader) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Columns) > 0 {
+		for _, e := range m.Columns {
+			l = e.Size()
+			n += 1 + l + sovApiAmp(uint64(l))
+		}
+	}
+	if len(m.Sort) > 0 {
+		for _, e := range m.Sort {
+			l = e.Size()
+			n += 1 + l + sovApiAmp(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *TableValue) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Null {
+		n += 2
+	}
+	l = len(m.Text)
+	if l > 0 {
+		n += 1 + l + sovApiAmp(uint64(l))
+	}
+	if m.Int != 0 {
+		n += 1 + sovApiAmp(uint64(m.Int))
+	}
+	if m.Float != 0 {
+		n += 9
+	}
+	if m.Bool {
+		n += 2
+	}
+	l = len(m.Bytes)
+	if l > 0 {
+		n += 1 + l + sovApiAmp(uint64(l))
+	}
+	return n
+}
+
+func (m *TableRow) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Values) > 0 {
+		for _, e := range m.Values {
+			l = e.Size()
+			n += 1 + l + sovApiAmp(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *LaunchURL) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.URL)
+	if l > 0 {
+		n += 1 + l + sovApiAmp(uint64(l))
+	}
+	return n
+}
+
+func (m *Position) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.CordType != 0 {
+		n += 1 + sovApiAmp(uint64(m.CordType))
+	}
+	if m.U != 0 {
+		n += 9
+	}
+	if m.V != 0 {
+		n += 9
+	}
+	if m.W != 0 {
+		n += 9
+	}
+	if m.ROU != 0 {
+		n += 5
+	}
+	return n
+}
+
+func (m *Tag) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Use != 0 {
+		n += 1 + sovApiAmp(uint64(m.Use))
+	}
+	if m.TagID_0 != 0 {
+		n += 1 + sovApiAmp(uint64(m.TagID_0))
+	}
+	if m.TagID_1 != 0 {
+		n += 9
+	}
+	if m.TagID_2 != 0 {
+		n += 9
+	}
+	l = len(m.URL)
+	if l > 0 {
+		n += 1 + l + sovApiAmp(uint64(l))
+	}
+	l = len(m.ContentType)
+	if l > 0 {
+		n += 1 + l + sovApiAmp(uint64(l))
+	}
+	l = len(m.Attachment)
+	if l > 0 {
+		n += 1 + l + sovApiAmp(uint64(l))
+	}
+	if m.Metric != 0 {
+		n += 2 + sovApiAmp(uint64(m.Metric))
+	}
+	if m.Size_0 != 0 {
+		n += 6
+	}
+	if m.Size_1 != 0 {
+		n += 6
+	}
+	if m.Size_2 != 0 {
+		n += 6
+	}
+	return n
+}
+
+func (m *TagTab) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Label)
+	if l > 0 {
+		n += 1 + l + sovApiAmp(uint64(l))
+	}
+	l = len(m.Caption)
+	if l > 0 {
+		n += 1 + l + sovApiAmp(uint64(l))
+	}
+	l = len(m.About)
+	if l > 0 {
+		n += 1 + l + sovApiAmp(uint64(l))
+	}
+	if m.CreatedAt != 0 {
+		n += 1 + sovApiAmp(uint64(m.CreatedAt))
+	}
+	if m.ModifiedAt != 0 {
+		n += 1 + sovApiAmp(uint64(m.ModifiedAt))
+	}
+	if len(m.Tags) > 0 {
+		for _, e := range m.Tags {
+			l = e.Size()
+			n += 1 + l + sovApiAmp(uint64(l))
+		}
+	}
+	if len(m.Locales) > 0 {
+		for _, e := range m.Locales {
+			l = e.Size()
+			n += 1 + l + sovApiAmp(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *LocaleText) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Locale)
+	if l > 0 {
+		n += 1 + l + sovApiAmp(uint64(l))
+	}
+	l = len(m.Label)
+	if l > 0 {
+		n += 1 + l + sovApiAmp(uint64(l))
+	}
+	l = len(m.Caption)
+	if l > 0 {
+		n += 1 + l + sovApiAmp(uint64(l))
+	}
+	l = len(m.About)
+	if l > 0 {
+		n += 1 + l + sovApiAmp(uint64(l))
+	}
+	return n
+}
+
+func (m *Ballot) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	return n
+}
+
+func (m *NotesEntry) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	return n
+}
+
+func (m *ChatEntry) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	return n
+}
+
+func (m *SpreadsheetEntry) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	return n
+}
+
+func (m *SpatialPlacement) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Pos0 != 0 {
+		n += 9
+	}
+	if m.Pos1 != 0 {
+		n += 9
+	}
+	if m.Pos2 != 0 {
+		n += 9
+	}
+	if m.Scale0 != 0 {
+		n += 5
+	}
+	if m.Scale1 != 0 {
+		n += 5
+	}
+	if m.Scale2 != 0 {
+		n += 5
+	}
+	if m.EulerX != 0 {
+		n += 5
+	}
+	if m.EulerY != 0 {
+		n += 5
+	}
+	if m.EulerZ != 0 {
+		n += 5
+	}
+	return n
+}
+
+func (m *TagPlayableMedia) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	return n
+}
+
+func (m *TagPlaylist) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	return n
+}
+
+func (m *CryptoKey) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.CryptoKitID != 0 {
+		n += 1 + sovApiAmp(uint64(m.CryptoKitID))
+	}
+	l = len(m.KeyBytes)
+	if l > 0 {
+		n += 1 + l + sovApiAmp(uint64(l))
+	}
+	return n
+}
+
+func (m *AuthToken) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.AccessToken)
+	if l > 0 {
+		n += 1 + l + sovApiAmp(uint64(l))
+	}
+	l = len(m.TokenType)
+	if l > 0 {
+		n += 1 + l + sovApiAmp(uint64(l))
+	}
+	l = len(m.RefreshToken)
+	if l > 0 {
+		n += 1 + l + sovApiAmp(uint64(l))
+	}
+	if m.Expiry != 0 {
+		n += 1 + sovApiAmp(uint64(m.Expiry))
+	}
+	return n
+}
+
+func (m *TRS) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.X1 != 0 {
+		n += 10
+	}
+	if m.X2 != 0 {
+		n += 10
+	}
+	if m.X3 != 0 {
+		n += 10
+	}
+	if m.ScaleMode != 0 {
+		n += 2 + sovApiAmp(uint64(m.ScaleMode))
+	}
+	if m.Scale1 != 0 {
+		n += 6
+	}
+	if m.Scale2 != 0 {
+		n += 6
+	}
+	if m.Scale3 != 0 {
+		n += 6
+	}
+	if m.Rotate1 != 0 {
+		n += 6
+	}
+	if m.Rotate2 != 0 {
+		n += 6
+	}
+	if m.Rotate3 != 0 {
+		n += 6
+	}
+	return n
+}
+
+func (m *DataSegment) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.ByteOfs != 0 {
+		n += 1 + sovApiAmp(uint64(m.ByteOfs))
+	}
+	if m.ByteSz != 0 {
+		n += 1 + sovApiAmp(uint64(m.ByteSz))
+	}
+	l = len(m.InlineData)
+	if l > 0 {
+		n += 1 + l + sovApiAmp(uint64(l))
+	}
+	l = len(m.StreamURI)
+	if l > 0 {
+		n += 1 + l + sovApiAmp(uint64(l))
+	}
+	if m.BlobID != 0 {
+		n += 1 + sovApiAmp(uint64(m.BlobID))
+	}
+	return n
+}
+
+func (m *Err) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Code != 0 {
+		n += 1 + sovApiAmp(uint64(m.Code))
+	}
+	if m.Level != 0 {
+		n += 1 + sovApiAmp(uint64(m.Level))
+	}
+	l = len(m.Msg)
+	if l > 0 {
+		n += 1 + l + sovApiAmp(uint64(l))
+	}
+	if m.Retryable {
+		n += 2
+	}
+	if len(m.Details) > 0 {
+		for _, e := range m.Details {
+			l = e.Size()
+			n += 1 + l + sovApiAmp(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *ErrDetail) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Key)
+	if l > 0 {
+		n += 1 + l + sovApiAmp(uint64(l))
+	}
+	l = len(m.Value)
+	if l > 0 {
+		n += 1 + l + sovApiAmp(uint64(l))
+	}
+	return n
+}
+
+func sovApiAmp(x uint64) (n int) {
+	return (math_bits.Len64(x|1) + 6) / 7
+}
+func sozApiAmp(x uint64) (n int) {
+	return sovApiAmp(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (this *TxInfo) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&TxInfo{`,
+		`Status:` + fmt.Sprintf("%v", this.Status) + `,`,
+		`ErrCode:` + fmt.Sprintf("%v", this.ErrCode) + `,`,
+		`NumOps:` + fmt.Sprintf("%v", this.NumOps) + `,`,
+		`GenesisID_0:` + fmt.Sprintf("%v", this.GenesisID_0) + `,`,
+		`GenesisID_1:` + fmt.Sprintf("%v", this.GenesisID_1) + `,`,
+		`GenesisID_2:` + fmt.Sprintf("%v", this.GenesisID_2) + `,`,
+		`RequestID_0:` + fmt.Sprintf("%v", this.RequestID_0) + `,`,
+		`RequestID_1:` + fmt.Sprintf("%v", this.RequestID_1) + `,`,
+		`RequestID_2:` + fmt.Sprintf("%v", this.RequestID_2) + `,`,
+		`RootElementID_0:` + fmt.Sprintf("%v", this.RootElementID_0) + `,`,
+		`RootElementID_1:` + fmt.Sprintf("%v", this.RootElementID_1) + `,`,
+		`RootElementID_2:` + fmt.Sprintf("%v", this.RootElementID_2) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Login) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&Login{`,
+		`UserUID:` + fmt.Sprintf("%v", this.UserUID) + `,`,
+		`HostAddr:` + fmt.Sprintf("%v", this.HostAddr) + `,`,
+		`DeviceLabel:` + fmt.Sprintf("%v", this.DeviceLabel) + `,`,
+		`DeviceUID:` + fmt.Sprintf("%v", this.DeviceUID) + `,`,
+		`Checkpoint:` + strings.Replace(this.Checkpoint.String(), "AuthCheckpoint", "AuthCheckpoint", 1) + `,`,
+		`Space:` + fmt.Sprintf("%v", this.Space) + `,`,
+		`MediaTypes:` + fmt.Sprintf("%v", this.MediaTypes) + `,`,
+		`Locales:` + fmt.Sprintf("%v", this.Locales) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *LoginChallenge) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&LoginChallenge{`,
+		`Hash:` + fmt.Sprintf("%v", this.Hash) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *LoginResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&LoginResponse{`,
+		`HashResponse:` + fmt.Sprintf("%v", this.HashResponse) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *AuthCheckpoint) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&AuthCheckpoint{`,
+		`Token:` + fmt.Sprintf("%v", this.Token) + `,`,
+		`HomeFeed:` + strings.Replace(this.HomeFeed.String(), "Tag", "Tag", 1) + `,`,
+		`Session:` + strings.Replace(this.Session.String(), "Tag", "Tag", 1) + `,`,
+		`Member:` + strings.Replace(this.Member.String(), "Tag", "Tag", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *PinRequest) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForPinAttrs := "[]*Tag{"
+	for _, f := range this.PinAttrs {
+		repeatedStringForPinAttrs += strings.Replace(f.String(), "Tag", "Tag", 1) + ","
+	}
+	repeatedStringForPinAttrs += "}"
+	s := strings.Join([]string{`&PinRequest{`,
+		`PinTarget:` + strings.Replace(this.PinTarget.String(), "Tag", "Tag", 1) + `,`,
+		`PinAttrs:` + repeatedStringForPinAttrs + `,`,
+		`PinSync:` + fmt.Sprintf("%v", this.PinSync) + `,`,
+		`PinWindow:` + strings.Replace(this.PinWindow.String(), "PinWindow", "PinWindow", 1) + `,`,
+		`PinFilter:` + fmt.Sprintf("%v", this.PinFilter) + `,`,
+		`Prefetch:` + strings.Replace(this.Prefetch.String(), "PrefetchHint", "PrefetchHint", 1) + `,`,
+		`SendWindow:` + fmt.Sprintf("%v", this.SendWindow) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *PinWindow) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&PinWindow{`,
+		`Offset:` + fmt.Sprintf("%v", this.Offset) + `,`,
+		`Limit:` + fmt.Sprintf("%v", this.Limit) + `,`,
+		`Cursor:` + fmt.Sprintf("%v", this.Cursor) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *PrefetchHint) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForAttrs := "[]*Tag{"
+	for _, f := range this.Attrs {
+		repeatedStringForAttrs += strings.Replace(f.String(), "Tag", "Tag", 1) + ","
+	}
+	repeatedStringForAttrs += "}"
+	s := strings.Join([]string{`&PrefetchHint{`,
+		`Children:` + fmt.Sprintf("%v", this.Children) + `,`,
+		`Attrs:` + repeatedStringForAttrs + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *PageInfo) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&PageInfo{`,
+		`Total:` + fmt.Sprintf("%v", this.Total) + `,`,
+		`Offset:` + fmt.Sprintf("%v", this.Offset) + `,`,
+		`Count:` + fmt.Sprintf("%v", this.Count) + `,`,
+		`NextCursor:` + fmt.Sprintf("%v", this.NextCursor) + `,`,
+		`PrevCursor:` + fmt.Sprintf("%v", this.PrevCursor) + `,`,
+		`Epoch:` + fmt.Sprintf("%v", this.Epoch) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Progress) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&Progress{`,
+		`Fraction:` + fmt.Sprintf("%v", this.Fraction) + `,`,
+		`Stage:` + fmt.Sprintf("%v", this.Stage) + `,`,
+		`Done:` + fmt.Sprintf("%v", this.Done) + `,`,
+		`Total:` + fmt.Sprintf("%v", this.Total) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *SearchHit) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&SearchHit{`,
+		`Rank:` + fmt.Sprintf("%v", this.Rank) + `,`,
+		`Score:` + fmt.Sprintf("%v", this.Score) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *LWWRegister) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&LWWRegister{`,
+		`Value:` + fmt.Sprintf("%v", this.Value) + `,`,
+		`Time:` + fmt.Sprintf("%v", this.Time) + `,`,
+		`Replica:` + fmt.Sprintf("%v", this.Replica) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *ORSetEntry) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&ORSetEntry{`,
+		`Value:` + fmt.Sprintf("%v", this.Value) + `,`,
+		`Time:` + fmt.Sprintf("%v", this.Time) + `,`,
+		`Replica:` + fmt.Sprintf("%v", this.Replica) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *ORSet) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForAdds := "[]*ORSetEntry{"
+	for _, f := range this.Adds {
+		repeatedStringForAdds += strings.Replace(f.String(), "ORSetEntry", "ORSetEntry", 1) + ","
+	}
+	repeatedStringForAdds += "}"
+	repeatedStringForRemoves := "[]*ORSetEntry{"
+	for _, f := range this.Removes {
+		repeatedStringForRemoves += strings.Replace(f.String(), "ORSetEntry", "ORSetEntry", 1) + ","
+	}
+	repeatedStringForRemoves += "}"
+	s := strings.Join([]string{`&ORSet{`,
+		`Adds:` + repeatedStringForAdds + `,`,
+		`Removes:` + repeatedStringForRemoves + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *RGAElem) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&RGAElem{`,
+		`Value:` + fmt.Sprintf("%v", this.Value) + `,`,
+		`Time:` + fmt.Sprintf("%v", this.Time) + `,`,
+		`Replica:` + fmt.Sprintf("%v", this.Replica) + `,`,
+		`AfterTime:` + fmt.Sprintf("%v", this.AfterTime) + `,`,
+		`AfterReplica:` + fmt.Sprintf("%v", this.AfterReplica) + `,`,
+		`Removed:` + fmt.Sprintf("%v", this.Removed) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *RGAList) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForElems := "[]*RGAElem{"
+	for _, f := range this.Elems {
+		repeatedStringForElems += strings.Replace(f.String(), "RGAElem", "RGAElem", 1) + ","
+	}
+	repeatedStringForElems += "}"
+	s := strings.Join([]string{`&RGAList{`,
+		`Elems:` + repeatedStringForElems + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *MigrationRecord) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&MigrationRecord{`,
+		`Version:` + fmt.Sprintf("%v", this.Version) + `,`,
+		`AppliedAt:` + fmt.Sprintf("%v", this.AppliedAt) + `,`,
+		`Desc:` + fmt.Sprintf("%v", this.Desc) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *MigrationLog) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForApplied := "[]*MigrationRecord{"
+	for _, f := range this.Applied {
+		repeatedStringForApplied += strings.Replace(f.String(), "MigrationRecord", "MigrationRecord", 1) + ","
+	}
+	repeatedStringForApplied += "}"
+	s := strings.Join([]string{`&MigrationLog{`,
+		`Applied:` + repeatedStringForApplied + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *PinStats) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&PinStats{`,
+		`ReqID:` + fmt.Sprintf("%v", this.ReqID) + `,`,
+		`App:` + fmt.Sprintf("%v", this.App) + `,`,
+		`Target:` + fmt.Sprintf("%v", this.Target) + `,`,
+		`OpenedAt:` + fmt.Sprintf("%v", this.OpenedAt) + `,`,
+		`FirstTxMicros:` + fmt.Sprintf("%v", this.FirstTxMicros) + `,`,
+		`TxsOut:` + fmt.Sprintf("%v", this.TxsOut) + `,`,
+		`BytesOut:` + fmt.Sprintf("%v", this.BytesOut) + `,`,
+		`TxsIn:` + fmt.Sprintf("%v", this.TxsIn) + `,`,
+		`LastTxAt:` + fmt.Sprintf("%v", this.LastTxAt) + `,`,
+		`ClosedAt:` + fmt.Sprintf("%v", this.ClosedAt) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *BlobChunk) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&BlobChunk{`,
+		`BlobID:` + fmt.Sprintf("%v", this.BlobID) + `,`,
+		`Offset:` + fmt.Sprintf("%v", this.Offset) + `,`,
+		`TotalSize:` + fmt.Sprintf("%v", this.TotalSize) + `,`,
+		`Final:` + fmt.Sprintf("%v", this.Final) + `,`,
+		`Canceled:` + fmt.Sprintf("%v", this.Canceled) + `,`,
+		`ContentType:` + fmt.Sprintf("%v", this.ContentType) + `,`,
+		`Data:` + fmt.Sprintf("%v", this.Data) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *MediaInfo) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&MediaInfo{`,
+		`ContentType:` + fmt.Sprintf("%v", this.ContentType) + `,`,
+		`Title:` + fmt.Sprintf("%v", this.Title) + `,`,
+		`Artist:` + fmt.Sprintf("%v", this.Artist) + `,`,
+		`Album:` + fmt.Sprintf("%v", this.Album) + `,`,
+		`AlbumArtist:` + fmt.Sprintf("%v", this.AlbumArtist) + `,`,
+		`Composer:` + fmt.Sprintf("%v", this.Composer) + `,`,
+		`Genre:` + fmt.Sprintf("%v", this.Genre) + `,`,
+		`Comment:` + fmt.Sprintf("%v", this.Comment) + `,`,
+		`Year:` + fmt.Sprintf("%v", this.Year) + `,`,
+		`TrackNum:` + fmt.Sprintf("%v", this.TrackNum) + `,`,
+		`TrackCount:` + fmt.Sprintf("%v", this.TrackCount) + `,`,
+		`DiscNum:` + fmt.Sprintf("%v", this.DiscNum) + `,`,
+		`DiscCount:` + fmt.Sprintf("%v", this.DiscCount) + `,`,
+		`DurationMs:` + fmt.Sprintf("%v", this.DurationMs) + `,`,
+		`SampleRate:` + fmt.Sprintf("%v", this.SampleRate) + `,`,
+		`Channels:` + fmt.Sprintf("%v", this.Channels) + `,`,
+		`Width:` + fmt.Sprintf("%v", this.Width) + `,`,
+		`Height:` + fmt.Sprintf("%v", this.Height) + `,`,
+		`Orientation:` + fmt.Sprintf("%v", this.Orientation) + `,`,
+		`CameraMake:` + fmt.Sprintf("%v", this.CameraMake) + `,`,
+		`CameraModel:` + fmt.Sprintf("%v", this.CameraModel) + `,`,
+		`TakenAt:` + fmt.Sprintf("%v", this.TakenAt) + `,`,
+		`HasLocation:` + fmt.Sprintf("%v", this.HasLocation) + `,`,
+		`Latitude:` + fmt.Sprintf("%v", this.Latitude) + `,`,
+		`Longitude:` + fmt.Sprintf("%v", this.Longitude) + `,`,
+		`EncoderDelay:` + fmt.Sprintf("%v", this.EncoderDelay) + `,`,
+		`EncoderPadding:` + fmt.Sprintf("%v", this.EncoderPadding) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *WaveformPeaks) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&WaveformPeaks{`,
+		`DurationMs:` + fmt.Sprintf("%v", this.DurationMs) + `,`,
+		`Peaks:` + fmt.Sprintf("%v", this.Peaks) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *PlaylistEntry) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&PlaylistEntry{`,
+		`Location:` + fmt.Sprintf("%v", this.Location) + `,`,
+		`Title:` + fmt.Sprintf("%v", this.Title) + `,`,
+		`Artist:` + fmt.Sprintf("%v", this.Artist) + `,`,
+		`Album:` + fmt.Sprintf("%v", this.Album) + `,`,
+		`DurationMs:` + fmt.Sprintf("%v", this.DurationMs) + `,`,
+		`Resolved:` + fmt.Sprintf("%v", this.Resolved) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *PlaybackEvent) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&PlaybackEvent{`,
+		`State:` + fmt.Sprintf("%v", this.State) + `,`,
+		`PositionMs:` + fmt.Sprintf("%v", this.PositionMs) + `,`,
+		`At:` + fmt.Sprintf("%v", this.At) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *TrackOffset) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&TrackOffset{`,
+		`StartMs:` + fmt.Sprintf("%v", this.StartMs) + `,`,
+		`DurationMs:` + fmt.Sprintf("%v", this.DurationMs) + `,`,
+		`PregapMs:` + fmt.Sprintf("%v", this.PregapMs) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *GeoPoint) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&GeoPoint{`,
+		`Lat:` + fmt.Sprintf("%v", this.Lat) + `,`,
+		`Lon:` + fmt.Sprintf("%v", this.Lon) + `,`,
+		`Alt:` + fmt.Sprintf("%v", this.Alt) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *TableColumn) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&TableColumn{`,
+		`Name:` + fmt.Sprintf("%v", this.Name) + `,`,
+		`Label:` + fmt.Sprintf("%v", this.Label) + `,`,
+		`Type:` + fmt.Sprintf("%v", this.Type) + `,`,
+		`Unit:` + fmt.Sprintf("%v", this.Unit) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *TableSortKey) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&TableSortKey{`,
+		`Column:` + fmt.Sprintf("%v", this.Column) + `,`,
+		`Desc:` + fmt.Sprintf("%v", this.Desc) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *TableHeader) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForColumns := "[]*TableColumn{"
+	for _, f := range this.Columns {
+		repeatedStringForColumns += strings.Replace(f.String(), "TableColumn", "TableColumn", 1) + ","
+	}
+	repeatedStringForColumns += "}"
+	repeatedStringForSort := "[]*TableSortKey{"
+	for _, f := range this.Sort {
+		repeatedStringForSort += strings.Replace(f.String(), "TableSortKey", "TableSortKey", 1) + ","
+	}
+	repeatedStringForSort += "}"
+	s := strings.Join([]string{`&TableHeader{`,
+		`Columns:` + repeatedStringForColumns + `,`,
+		`Sort:` + repeatedStringForSort + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *TableValue) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&TableValue{`,
+		`Null:` + fmt.Sprintf("%v", this.Null) + `,`,
+		`Text:` + fmt.Sprintf("%v", this.Text) + `,`,
+		`Int:` + fmt.Sprintf("%v", this.Int) + `,`,
+		`Float:` + fmt.Sprintf("%v", this.Float) + `,`,
+		`Bool:` + fmt.Sprintf("%v", this.Bool) + `,`,
+		`Bytes:` + fmt.Sprintf("%v", this.Bytes) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *TableRow) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForValues := "[]*TableValue{"
+	for _, f := range this.Values {
+		repeatedStringForValues += strings.Replace(f.String(), "TableValue", "TableValue", 1) + ","
+	}
+	repeatedStringForValues += "}"
+	s := strings.Join([]string{`&TableRow{`,
+		`Values:` + repeatedStringForValues + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *LaunchURL) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&LaunchURL{`,
+		`URL:` + fmt.Sprintf("%v", this.URL) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Position) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&Position{`,
+		`CordType:` + fmt.Sprintf("%v", this.CordType) + `,`,
+		`U:` + fmt.Sprintf("%v", this.U) + `,`,
+		`V:` + fmt.Sprintf("%v", this.V) + `,`,
+		`W:` + fmt.Sprintf("%v", this.W) + `,`,
+		`ROU:` + fmt.Sprintf("%v", this.ROU) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Tag) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&Tag{`,
+		`Use:` + fmt.Sprintf("%v", this.Use) + `,`,
+		`TagID_0:` + fmt.Sprintf("%v", this.TagID_0) + `,`,
+		`TagID_1:` + fmt.Sprintf("%v", this.TagID_1) + `,`,
+		`TagID_2:` + fmt.Sprintf("%v", this.TagID_2) + `,`,
+		`URL:` + fmt.Sprintf("%v", this.URL) + `,`,
+		`ContentType:` + fmt.Sprintf("%v", this.ContentType) + `,`,
+		`Attachment:` + fmt.Sprintf("%v", this.Attachment) + `,`,
+		`Metric:` + fmt.Sprintf("%v", this.Metric) + `,`,
+		`Size_0:` + fmt.Sprintf("%v", this.Size_0) + `,`,
+		`Size_1:` + fmt.Sprintf("%v", this.Size_1) + `,`,
+		`Size_2:` + fmt.Sprintf("%v", this.Size_2) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *TagTab) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForTags := "[]*Tag{"
+	for _, f := range this.Tags {
+		repeatedStringForTags += strings.Replace(f.String(), "Tag", "Tag", 1) + ","
+	}
+	repeatedStringForTags += "}"
+	repeatedStringForLocales := "[]*LocaleText{"
+	for _, f := range this.Locales {
+		repeatedStringForLocales += strings.Replace(f.String(), "LocaleText", "LocaleText", 1) + ","
+	}
+	repeatedStringForLocales += "}"
+	s := strings.Join([]string{`&TagTab{`,
+		`Label:` + fmt.Sprintf("%v", this.Label) + `,`,
+		`Caption:` + fmt.Sprintf("%v", this.Caption) + `,`,
+		`About:` + fmt.Sprintf("%v", this.About) + `,`,
+		`CreatedAt:` + fmt.Sprintf("%v", this.CreatedAt) + `,`,
+		`ModifiedAt:` + fmt.Sprintf("%v", this.ModifiedAt) + `,`,
+		`Tags:` + repeatedStringForTags + `,`,
+		`Locales:` + repeatedStringForLocales + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *LocaleText) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&LocaleText{`,
+		`Locale:` + fmt.Sprintf("%v", this.Locale) + `,`,
+		`Label:` + fmt.Sprintf("%v", this.Label) + `,`,
+		`Caption:` + fmt.Sprintf("%v", this.Caption) + `,`,
+		`About:` + fmt.Sprintf("%v", this.About) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Ballot) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&Ballot{`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *NotesEntry) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&NotesEntry{`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *ChatEntry) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&ChatEntry{`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *SpreadsheetEntry) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&SpreadsheetEntry{`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *SpatialPlacement) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&SpatialPlacement{`,
+		`Pos0:` + fmt.Sprintf("%v", this.Pos0) + `,`,
+		`Pos1:` + fmt.Sprintf("%v", this.Pos1) + `,`,
+		`Pos2:` + fmt.Sprintf("%v", this.Pos2) + `,`,
+		`Scale0:` + fmt.Sprintf("%v", this.Scale0) + `,`,
+		`Scale1:` + fmt.Sprintf("%v", this.Scale1) + `,`,
+		`Scale2:` + fmt.Sprintf("%v", this.Scale2) + `,`,
+		`EulerX:` + fmt.Sprintf("%v", this.EulerX) + `,`,
+		`EulerY:` + fmt.Sprintf("%v", this.EulerY) + `,`,
+		`EulerZ:` + fmt.Sprintf("%v", this.EulerZ) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *TagPlayableMedia) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&TagPlayableMedia{`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *TagPlaylist) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&TagPlaylist{`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *CryptoKey) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&CryptoKey{`,
+		`CryptoKitID:` + fmt.Sprintf("%v", this.CryptoKitID) + `,`,
+		`KeyBytes:` + fmt.Sprintf("%v", this.KeyBytes) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *AuthToken) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&AuthToken{`,
+		`AccessToken:` + fmt.Sprintf("%v", this.AccessToken) + `,`,
+		`TokenType:` + fmt.Sprintf("%v", this.TokenType) + `,`,
+		`RefreshToken:` + fmt.Sprintf("%v", this.RefreshToken) + `,`,
+		`Expiry:` + fmt.Sprintf("%v", this.Expiry) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *TRS) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&TRS{`,
+		`X1:` + fmt.Sprintf("%v", this.X1) + `,`,
+		`X2:` + fmt.Sprintf("%v", this.X2) + `,`,
+		`X3:` + fmt.Sprintf("%v", this.X3) + `,`,
+		`ScaleMode:` + fmt.Sprintf("%v", this.ScaleMode) + `,`,
+		`Scale1:` + fmt.Sprintf("%v", this.Scale1) + `,`,
+		`Scale2:` + fmt.Sprintf("%v", this.Scale2) + `,`,
+		`Scale3:` + fmt.Sprintf("%v", this.Scale3) + `,`,
+		`Rotate1:` + fmt.Sprintf("%v", this.Rotate1) + `,`,
+		`Rotate2:` + fmt.Sprintf("%v", this.Rotate2) + `,`,
+		`Rotate3:` + fmt.Sprintf("%v", this.Rotate3) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *DataSegment) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&DataSegment{`,
+		`ByteOfs:` + fmt.Sprintf("%v", this.ByteOfs) + `,`,
+		`ByteSz:` + fmt.Sprintf("%v", this.ByteSz) + `,`,
+		`InlineData:` + fmt.Sprintf("%v", this.InlineData) + `,`,
+		`StreamURI:` + fmt.Sprintf("%v", this.StreamURI) + `,`,
+		`BlobID:` + fmt.Sprintf("%v", this.BlobID) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Err) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForDetails := "[]*ErrDetail{"
+	for _, f := range this.Details {
+		repeatedStringForDetails += strings.Replace(f.String(), "ErrDetail", "ErrDetail", 1) + ","
+	}
+	repeatedStringForDetails += "}"
+	s := strings.Join([]string{`&Err{`,
+		`Code:` + fmt.Sprintf("%v", this.Code) + `,`,
+		`Level:` + fmt.Sprintf("%v", this.Level) + `,`,
+		`Msg:` + fmt.Sprintf("%v", this.Msg) + `,`,
+		`Retryable:` + fmt.Sprintf("%v", this.Retryable) + `,`,
+		`Details:` + repeatedStringForDetails + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *ErrDetail) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&ErrDetail{`,
+		`Key:` + fmt.Sprintf("%v", this.Key) + `,`,
+		`Value:` + fmt.Sprintf("%v", this.Value) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func valueToStringApiAmp(v interface{}) string {
+	rv := reflect.ValueOf(v)
+	if rv.IsNil() {
+		return "nil"
+	}
+	pv := reflect.Indirect(rv).Interface()
+	return fmt.Sprintf("*%v", pv)
+}
+func (m *TxInfo) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowApiAmp
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TxInfo: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TxInfo: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Status", wireType)
+			}
+			m.Status = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Status |= OpStatus(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ErrCode", wireType)
+			}
+			m.ErrCode = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ErrCode |= ErrCode(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NumOps", wireType)
+			}
+			m.NumOps = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.NumOps |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field GenesisID_0", wireType)
+			}
+			m.GenesisID_0 = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.GenesisID_0 |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field GenesisID_1", wireType)
+			}
+			m.GenesisID_1 = 0
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.GenesisID_1 = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+		case 7:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field GenesisID_2", wireType)
+			}
+			m.GenesisID_2 = 0
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.GenesisID_2 = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+		case 10:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RequestID_0", wireType)
+			}
+			m.RequestID_0 = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.RequestID_0 |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 11:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RequestID_1", wireType)
+			}
+			m.RequestID_1 = 0
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.RequestID_1 = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+		case 12:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RequestID_2", wireType)
+			}
+			m.RequestID_2 = 0
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.RequestID_2 = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+		case 13:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RootElementID_0", wireType)
+			}
+			m.RootElementID_0 = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.RootElementID_0 |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 14:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RootElementID_1", wireType)
+			}
+			m.RootElementID_1 = 0
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.RootElementID_1 = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+		case 15:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RootElementID_2", wireType)
+			}
+			m.RootElementID_2 = 0
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.RootElementID_2 = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+		default:
+			iNdEx = preIndex
+			skippy, err := skipApiAmp(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Login) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowApiAmp
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Login: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Login: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field UserUID", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.UserUID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field HostAddr", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.HostAddr = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DeviceLabel", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.DeviceLabel = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DeviceUID", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.DeviceUID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Checkpoint", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Checkpoint == nil {
+				m.Checkpoint = &AuthCheckpoint{}
+			}
+			if err := m.Checkpoint.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 11:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Space", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Space = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 12:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MediaTypes", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.MediaTypes = append(m.MediaTypes, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 13:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Locales", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Locales = append(m.Locales, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipApiAmp(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *LoginChallenge) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowApiAmp
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: LoginChallenge: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: LoginChallenge: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Hash", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Hash = append(m.Hash[:0], dAtA[iNdEx:postIndex]...)
+			if m.Hash == nil {
+				m.Hash = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipApiAmp(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *LoginResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowApiAmp
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: LoginResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: LoginResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field HashResponse", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.HashResponse = append(m.HashResponse[:0], dAtA[iNdEx:postIndex]...)
+			if m.HashResponse == nil {
+				m.HashResponse = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipApiAmp(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *AuthCheckpoint) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowApiAmp
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: AuthCheckpoint: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: AuthCheckpoint: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Token", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Token = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field HomeFeed", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.HomeFeed == nil {
+				m.HomeFeed = &Tag{}
+			}
+			if err := m.HomeFeed.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Session", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Session == nil {
+				m.Session = &Tag{}
+			}
+			if err := m.Session.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 11:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Member", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Member == nil {
+				m.Member = &Tag{}
+			}
+			if err := m.Member.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipApiAmp(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *PinRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowApiAmp
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: PinRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: PinRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PinTarget", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.PinTarget == nil {
+				m.PinTarget = &Tag{}
+			}
+			if err := m.PinTarget.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PinAttrs", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.PinAttrs = append(m.PinAttrs, &Tag{})
+			if err := m.PinAttrs[len(m.PinAttrs)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PinSync", wireType)
+			}
+			m.PinSync = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.PinSync |= PinSync(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PinWindow", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.PinWindow == nil {
+				m.PinWindow = &PinWindow{}
+			}
+			if err := m.PinWindow.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PinFilter", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.PinFilter = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 12:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Prefetch", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Prefetch == nil {
+				m.Prefetch = &PrefetchHint{}
+			}
+			if err := m.Prefetch.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 14:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SendWindow", wireType)
+			}
+			m.SendWindow = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.SendWindow |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipApiAmp(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *PinWindow) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowApiAmp
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: PinWindow: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: PinWindow: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Offset", wireType)
+			}
+			m.Offset = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Offset |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Limit", wireType)
+			}
+			m.Limit = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Limit |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Cursor", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Cursor = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipApiAmp(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *PrefetchHint) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowApiAmp
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: PrefetchHint: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: PrefetchHint: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Children", wireType)
+			}
+			m.Children = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Children |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Attrs", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Attrs = append(m.Attrs, &Tag{})
+			if err := m.Attrs[len(m.Attrs)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipApiAmp(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *PageInfo) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowApiAmp
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: PageInfo: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: PageInfo: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Total", wireType)
+			}
+			m.Total = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Total |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Offset", wireType)
+			}
+			m.Offset = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Offset |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Count", wireType)
+			}
+			m.Count = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Count |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NextCursor", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.NextCursor = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PrevCursor", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.PrevCursor = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Epoch", wireType)
+			}
+			m.Epoch = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Epoch |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipApiAmp(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Progress) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowApiAmp
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Progress: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Progress: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 5 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Fraction", wireType)
+			}
+			var v uint32
+			if (iNdEx + 4) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint32(encoding_binary.LittleEndian.Uint32(dAtA[iNdEx:]))
+			iNdEx += 4
+			m.Fraction = float32(math.Float32frombits(v))
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Stage", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Stage = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Done", wireType)
+			}
+			m.Done = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Done |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Total", wireType)
+			}
+			m.Total = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Total |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipApiAmp(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SearchHit) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowApiAmp
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SearchHit: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SearchHit: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Rank", wireType)
+			}
+			m.Rank = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Rank |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 5 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Score", wireType)
+			}
+			var v uint32
+			if (iNdEx + 4) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint32(encoding_binary.LittleEndian.Uint32(dAtA[iNdEx:]))
+			iNdEx += 4
+			m.Score = float32(math.Float32frombits(v))
+		default:
+			iNdEx = preIndex
+			skippy, err := skipApiAmp(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *LWWRegister) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowApiAmp
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: LWWRegister: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: LWWRegister: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Value", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Value = append(m.Value[:0], dAtA[iNdEx:postIndex]...)
+			if m.Value == nil {
+				m.Value = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Time", wireType)
+			}
+			m.Time = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Time |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Replica", wireType)
+			}
+			m.Replica = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Replica |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipApiAmp(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ORSetEntry) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowApiAmp
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ORSetEntry: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ORSetEntry: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Value", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Value = append(m.Value[:0], dAtA[iNdEx:postIndex]...)
+			if m.Value == nil {
+				m.Value = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Time", wireType)
+			}
+			m.Time = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Time |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Replica", wireType)
+			}
+			m.Replica = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Replica |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipApiAmp(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ORSet) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowApiAmp
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ORSet: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ORSet: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Adds", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Adds = append(m.Adds, &ORSetEntry{})
+			if err := m.Adds[len(m.Adds)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Removes", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Removes = append(m.Removes, &ORSetEntry{})
+			if err := m.Removes[len(m.Removes)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipApiAmp(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *RGAElem) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowApiAmp
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: RGAElem: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: RGAElem: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Value", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Value = append(m.Value[:0], dAtA[iNdEx:postIndex]...)
+			if m.Value == nil {
+				m.Value = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Time", wireType)
+			}
+			m.Time = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Time |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Replica", wireType)
+			}
+			m.Replica = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Replica |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AfterTime", wireType)
+			}
+			m.AfterTime = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.AfterTime |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AfterReplica", wireType)
+			}
+			m.AfterReplica = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.AfterReplica |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Removed", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Removed = bool(v != 0)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipApiAmp(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *RGAList) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowApiAmp
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: RGAList: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: RGAList: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Elems", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Elems = append(m.Elems, &RGAElem{})
+			if err := m.Elems[len(m.Elems)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipApiAmp(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *MigrationRecord) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowApiAmp
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: MigrationRecord: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: MigrationRecord: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Version", wireType)
+			}
+			m.Version = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Version |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AppliedAt", wireType)
+			}
+			m.AppliedAt = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.AppliedAt |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Desc", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Desc = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipApiAmp(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *MigrationLog) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowApiAmp
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: MigrationLog: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: MigrationLog: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Applied", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Applied = append(m.Applied, &MigrationRecord{})
+			if err := m.Applied[len(m.Applied)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipApiAmp(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *PinStats) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowApiAmp
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: PinStats: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: PinStats: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ReqID", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ReqID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field App", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.App = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Target", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Target = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field OpenedAt", wireType)
+			}
+			m.OpenedAt = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.OpenedAt |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FirstTxMicros", wireType)
+			}
+			m.FirstTxMicros = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.FirstTxMicros |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TxsOut", wireType)
+			}
+			m.TxsOut = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.TxsOut |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BytesOut", wireType)
+			}
+			m.BytesOut = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.BytesOut |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 8:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TxsIn", wireType)
+			}
+			m.TxsIn = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.TxsIn |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 9:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LastTxAt", wireType)
+			}
+			m.LastTxAt = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.LastTxAt |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 10:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ClosedAt", wireType)
+			}
+			m.ClosedAt = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ClosedAt |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipApiAmp(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *BlobChunk) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowApiAmp
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: BlobChunk: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: BlobChunk: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BlobID", wireType)
+			}
+			m.BlobID = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.BlobID |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Offset", wireType)
+			}
+			m.Offset = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Offset |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TotalSize", wireType)
+			}
+			m.TotalSize = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.TotalSize |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Final", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Final = bool(v != 0)
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Canceled", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Canceled = bool(v != 0)
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ContentType", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ContentType = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Data", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Data = append(m.Data[:0], dAtA[iNdEx:postIndex]...)
+			if m.Data == nil {
+				m.Data = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipApiAmp(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *MediaInfo) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowApiAmp
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: MediaInfo: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: MediaInfo: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ContentType", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ContentType = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Title", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Title = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Artist", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Artist = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Album", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Album = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AlbumArtist", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.AlbumArtist = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Composer", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Composer = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Genre", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Genre = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Comment", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Comment = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 9:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Year", wireType)
+			}
+			m.Year = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Year |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 10:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TrackNum", wireType)
+			}
+			m.TrackNum = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.TrackNum |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 11:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TrackCount", wireType)
+			}
+			m.TrackCount = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.TrackCount |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 12:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DiscNum", wireType)
+			}
+			m.DiscNum = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.DiscNum |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 13:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DiscCount", wireType)
+			}
+			m.DiscCount = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.DiscCount |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 14:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DurationMs", wireType)
+			}
+			m.DurationMs = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.DurationMs |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 15:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SampleRate", wireType)
+			}
+			m.SampleRate = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.SampleRate |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 16:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Channels", wireType)
+			}
+			m.Channels = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Channels |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 17:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Width", wireType)
+			}
+			m.Width = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Width |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 18:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Height", wireType)
+			}
+			m.Height = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Height |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 19:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Orientation", wireType)
+			}
+			m.Orientation = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Orientation |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 20:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CameraMake", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.CameraMake = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 21:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CameraModel", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.CameraModel = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 22:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TakenAt", wireType)
+			}
+			m.TakenAt = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.TakenAt |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 23:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field HasLocation", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.HasLocation = bool(v != 0)
+		case 24:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Latitude", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.Latitude = float64(math.Float64frombits(v))
+		case 25:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Longitude", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.Longitude = float64(math.Float64frombits(v))
+		case 26:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EncoderDelay", wireType)
+			}
+			m.EncoderDelay = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.EncoderDelay |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 27:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EncoderPadding", wireType)
+			}
+			m.EncoderPadding = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.EncoderPadding |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipApiAmp(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *WaveformPeaks) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowApiAmp
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: WaveformPeaks: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: WaveformPeaks: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DurationMs", wireType)
+			}
+			m.DurationMs = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.DurationMs |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Peaks", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Peaks = append(m.Peaks[:0], dAtA[iNdEx:postIndex]...)
+			if m.Peaks == nil {
+				m.Peaks = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipApiAmp(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *PlaylistEntry) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowApiAmp
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: PlaylistEntry: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: PlaylistEntry: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Location", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Location = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Title", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Title = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Artist", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Artist = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Album", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Album = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DurationMs", wireType)
+			}
+			m.DurationMs = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.DurationMs |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Resolved", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Resolved = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipApiAmp(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
 }
-func (m *TxInfo) Unmarshal(dAtA []byte) error {
+func (m *PlaybackEvent) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -5411,17 +15938,17 @@ func (m *TxInfo) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: TxInfo: wiretype end group for non-group")
+			return fmt.Errorf("proto: PlaybackEvent: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: TxInfo: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: PlaybackEvent: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
-		case 2:
+		case 1:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Status", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field State", wireType)
 			}
-			m.Status = 0
+			m.State = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowApiAmp
@@ -5431,16 +15958,16 @@ func (m *TxInfo) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Status |= OpStatus(b&0x7F) << shift
+				m.State |= PlaybackState(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 4:
+		case 2:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field NumOps", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field PositionMs", wireType)
 			}
-			m.NumOps = 0
+			m.PositionMs = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowApiAmp
@@ -5450,16 +15977,16 @@ func (m *TxInfo) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.NumOps |= uint64(b&0x7F) << shift
+				m.PositionMs |= int64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 5:
+		case 3:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field GenesisID_0", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field At", wireType)
 			}
-			m.GenesisID_0 = 0
+			m.At = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowApiAmp
@@ -5469,36 +15996,66 @@ func (m *TxInfo) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.GenesisID_0 |= int64(b&0x7F) << shift
+				m.At |= int64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 6:
-			if wireType != 1 {
-				return fmt.Errorf("proto: wrong wireType = %d for field GenesisID_1", wireType)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipApiAmp(dAtA[iNdEx:])
+			if err != nil {
+				return err
 			}
-			m.GenesisID_1 = 0
-			if (iNdEx + 8) > l {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if (iNdEx + skippy) > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.GenesisID_1 = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
-			iNdEx += 8
-		case 7:
-			if wireType != 1 {
-				return fmt.Errorf("proto: wrong wireType = %d for field GenesisID_2", wireType)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *TrackOffset) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowApiAmp
 			}
-			m.GenesisID_2 = 0
-			if (iNdEx + 8) > l {
+			if iNdEx >= l {
 				return io.ErrUnexpectedEOF
 			}
-			m.GenesisID_2 = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
-			iNdEx += 8
-		case 10:
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TrackOffset: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TrackOffset: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field RequestID_0", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field StartMs", wireType)
 			}
-			m.RequestID_0 = 0
+			m.StartMs = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowApiAmp
@@ -5508,36 +16065,35 @@ func (m *TxInfo) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.RequestID_0 |= int64(b&0x7F) << shift
+				m.StartMs |= int64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 11:
-			if wireType != 1 {
-				return fmt.Errorf("proto: wrong wireType = %d for field RequestID_1", wireType)
-			}
-			m.RequestID_1 = 0
-			if (iNdEx + 8) > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.RequestID_1 = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
-			iNdEx += 8
-		case 12:
-			if wireType != 1 {
-				return fmt.Errorf("proto: wrong wireType = %d for field RequestID_2", wireType)
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DurationMs", wireType)
 			}
-			m.RequestID_2 = 0
-			if (iNdEx + 8) > l {
-				return io.ErrUnexpectedEOF
+			m.DurationMs = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.DurationMs |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			m.RequestID_2 = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
-			iNdEx += 8
-		case 13:
+		case 3:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field RootElementID_0", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field PregapMs", wireType)
 			}
-			m.RootElementID_0 = 0
+			m.PregapMs = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowApiAmp
@@ -5547,31 +16103,94 @@ func (m *TxInfo) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.RootElementID_0 |= int64(b&0x7F) << shift
+				m.PregapMs |= int64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 14:
+		default:
+			iNdEx = preIndex
+			skippy, err := skipApiAmp(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *GeoPoint) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowApiAmp
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: GeoPoint: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: GeoPoint: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
 			if wireType != 1 {
-				return fmt.Errorf("proto: wrong wireType = %d for field RootElementID_1", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Lat", wireType)
 			}
-			m.RootElementID_1 = 0
+			var v uint64
 			if (iNdEx + 8) > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.RootElementID_1 = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
 			iNdEx += 8
-		case 15:
+			m.Lat = float64(math.Float64frombits(v))
+		case 2:
 			if wireType != 1 {
-				return fmt.Errorf("proto: wrong wireType = %d for field RootElementID_2", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Lon", wireType)
 			}
-			m.RootElementID_2 = 0
+			var v uint64
 			if (iNdEx + 8) > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.RootElementID_2 = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.Lon = float64(math.Float64frombits(v))
+		case 3:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Alt", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
 			iNdEx += 8
+			m.Alt = float64(math.Float64frombits(v))
 		default:
 			iNdEx = preIndex
 			skippy, err := skipApiAmp(dAtA[iNdEx:])
@@ -5593,7 +16212,7 @@ func (m *TxInfo) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *Login) Unmarshal(dAtA []byte) error {
+func (m *TableColumn) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -5616,15 +16235,15 @@ func (m *Login) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Login: wiretype end group for non-group")
+			return fmt.Errorf("proto: TableColumn: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Login: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: TableColumn: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field UserUID", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -5652,11 +16271,11 @@ func (m *Login) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.UserUID = string(dAtA[iNdEx:postIndex])
+			m.Name = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field HostAddr", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Label", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -5684,13 +16303,13 @@ func (m *Login) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.HostAddr = string(dAtA[iNdEx:postIndex])
+			m.Label = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 8:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field DeviceLabel", wireType)
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Type", wireType)
 			}
-			var stringLen uint64
+			m.Type = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowApiAmp
@@ -5700,27 +16319,14 @@ func (m *Login) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				m.Type |= ColumnType(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthApiAmp
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthApiAmp
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.DeviceLabel = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
-		case 9:
+		case 4:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field DeviceUID", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Unit", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -5748,43 +16354,7 @@ func (m *Login) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.DeviceUID = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
-		case 10:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Checkpoint", wireType)
-			}
-			var msglen int
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowApiAmp
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				msglen |= int(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			if msglen < 0 {
-				return ErrInvalidLengthApiAmp
-			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
-				return ErrInvalidLengthApiAmp
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			if m.Checkpoint == nil {
-				m.Checkpoint = &AuthCheckpoint{}
-			}
-			if err := m.Checkpoint.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.Unit = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -5807,7 +16377,7 @@ func (m *Login) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *LoginChallenge) Unmarshal(dAtA []byte) error {
+func (m *TableSortKey) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -5830,17 +16400,17 @@ func (m *LoginChallenge) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: LoginChallenge: wiretype end group for non-group")
+			return fmt.Errorf("proto: TableSortKey: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: LoginChallenge: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: TableSortKey: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Hash", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Column", wireType)
 			}
-			var byteLen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowApiAmp
@@ -5850,26 +16420,44 @@ func (m *LoginChallenge) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthApiAmp
 			}
-			postIndex := iNdEx + byteLen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthApiAmp
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Hash = append(m.Hash[:0], dAtA[iNdEx:postIndex]...)
-			if m.Hash == nil {
-				m.Hash = []byte{}
-			}
+			m.Column = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Desc", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Desc = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipApiAmp(dAtA[iNdEx:])
@@ -5891,7 +16479,7 @@ func (m *LoginChallenge) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *LoginResponse) Unmarshal(dAtA []byte) error {
+func (m *TableHeader) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -5914,17 +16502,17 @@ func (m *LoginResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: LoginResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: TableHeader: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: LoginResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: TableHeader: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field HashResponse", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Columns", wireType)
 			}
-			var byteLen int
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowApiAmp
@@ -5934,24 +16522,58 @@ func (m *LoginResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= int(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthApiAmp
 			}
-			postIndex := iNdEx + byteLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthApiAmp
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.HashResponse = append(m.HashResponse[:0], dAtA[iNdEx:postIndex]...)
-			if m.HashResponse == nil {
-				m.HashResponse = []byte{}
+			m.Columns = append(m.Columns, &TableColumn{})
+			if err := m.Columns[len(m.Columns)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Sort", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Sort = append(m.Sort, &TableSortKey{})
+			if err := m.Sort[len(m.Sort)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
 			}
 			iNdEx = postIndex
 		default:
@@ -5975,7 +16597,7 @@ func (m *LoginResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *AuthCheckpoint) Unmarshal(dAtA []byte) error {
+func (m *TableValue) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -5998,15 +16620,35 @@ func (m *AuthCheckpoint) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: AuthCheckpoint: wiretype end group for non-group")
+			return fmt.Errorf("proto: TableValue: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: AuthCheckpoint: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: TableValue: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Null", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Null = bool(v != 0)
+		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Token", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Text", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -6034,13 +16676,63 @@ func (m *AuthCheckpoint) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Token = string(dAtA[iNdEx:postIndex])
+			m.Text = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 9:
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Int", wireType)
+			}
+			m.Int = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Int |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Float", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.Float = float64(math.Float64frombits(v))
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Bool", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Bool = bool(v != 0)
+		case 6:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field HomeFeed", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Bytes", wireType)
 			}
-			var msglen int
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowApiAmp
@@ -6050,31 +16742,79 @@ func (m *AuthCheckpoint) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				byteLen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			if byteLen < 0 {
 				return ErrInvalidLengthApiAmp
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + byteLen
 			if postIndex < 0 {
 				return ErrInvalidLengthApiAmp
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.HomeFeed == nil {
-				m.HomeFeed = &Tag{}
+			m.Bytes = append(m.Bytes[:0], dAtA[iNdEx:postIndex]...)
+			if m.Bytes == nil {
+				m.Bytes = []byte{}
 			}
-			if err := m.HomeFeed.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipApiAmp(dAtA[iNdEx:])
+			if err != nil {
 				return err
 			}
-			iNdEx = postIndex
-		case 10:
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *TableRow) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowApiAmp
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TableRow: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TableRow: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Session", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Values", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -6101,18 +16841,66 @@ func (m *AuthCheckpoint) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Session == nil {
-				m.Session = &Tag{}
-			}
-			if err := m.Session.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.Values = append(m.Values, &TableValue{})
+			if err := m.Values[len(m.Values)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
-		case 11:
+		default:
+			iNdEx = preIndex
+			skippy, err := skipApiAmp(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *LaunchURL) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowApiAmp
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: LaunchURL: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: LaunchURL: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Member", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field URL", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowApiAmp
@@ -6122,27 +16910,23 @@ func (m *AuthCheckpoint) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthApiAmp
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthApiAmp
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Member == nil {
-				m.Member = &Tag{}
-			}
-			if err := m.Member.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.URL = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -6165,7 +16949,7 @@ func (m *AuthCheckpoint) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *PinRequest) Unmarshal(dAtA []byte) error {
+func (m *Position) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -6188,17 +16972,17 @@ func (m *PinRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: PinRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: Position: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: PinRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: Position: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
-		case 2:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field PinTarget", wireType)
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CordType", wireType)
 			}
-			var msglen int
+			m.CordType = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowApiAmp
@@ -6208,81 +16992,55 @@ func (m *PinRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				m.CordType |= CordType(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthApiAmp
-			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
-				return ErrInvalidLengthApiAmp
+		case 3:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field U", wireType)
 			}
-			if postIndex > l {
+			var v uint64
+			if (iNdEx + 8) > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.PinTarget == nil {
-				m.PinTarget = &Tag{}
-			}
-			if err := m.PinTarget.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
-			iNdEx = postIndex
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.U = float64(math.Float64frombits(v))
 		case 4:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field PinAttrs", wireType)
-			}
-			var msglen int
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowApiAmp
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				msglen |= int(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field V", wireType)
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthApiAmp
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
 			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
-				return ErrInvalidLengthApiAmp
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.V = float64(math.Float64frombits(v))
+		case 5:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field W", wireType)
 			}
-			if postIndex > l {
+			var v uint64
+			if (iNdEx + 8) > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.PinAttrs = append(m.PinAttrs, &Tag{})
-			if err := m.PinAttrs[len(m.PinAttrs)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
-			iNdEx = postIndex
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.W = float64(math.Float64frombits(v))
 		case 6:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field PinSync", wireType)
+			if wireType != 5 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ROU", wireType)
 			}
-			m.PinSync = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowApiAmp
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.PinSync |= PinSync(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
+			var v uint32
+			if (iNdEx + 4) > l {
+				return io.ErrUnexpectedEOF
 			}
+			v = uint32(encoding_binary.LittleEndian.Uint32(dAtA[iNdEx:]))
+			iNdEx += 4
+			m.ROU = float32(math.Float32frombits(v))
 		default:
 			iNdEx = preIndex
 			skippy, err := skipApiAmp(dAtA[iNdEx:])
@@ -6304,7 +17062,7 @@ func (m *PinRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *LaunchURL) Unmarshal(dAtA []byte) error {
+func (m *Tag) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -6327,13 +17085,71 @@ func (m *LaunchURL) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: LaunchURL: wiretype end group for non-group")
+			return fmt.Errorf("proto: Tag: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: LaunchURL: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: Tag: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Use", wireType)
+			}
+			m.Use = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Use |= TagUse(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TagID_0", wireType)
+			}
+			m.TagID_0 = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.TagID_0 |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TagID_1", wireType)
+			}
+			m.TagID_1 = 0
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TagID_1 = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+		case 4:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TagID_2", wireType)
+			}
+			m.TagID_2 = 0
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TagID_2 = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+		case 13:
 			if wireType != 2 {
 				return fmt.Errorf("proto: wrong wireType = %d for field URL", wireType)
 			}
@@ -6347,79 +17163,95 @@ func (m *LaunchURL) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.URL = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 14:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ContentType", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ContentType = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 15:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Attachment", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if byteLen < 0 {
 				return ErrInvalidLengthApiAmp
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + byteLen
 			if postIndex < 0 {
 				return ErrInvalidLengthApiAmp
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.URL = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
-		default:
-			iNdEx = preIndex
-			skippy, err := skipApiAmp(dAtA[iNdEx:])
-			if err != nil {
-				return err
-			}
-			if (skippy < 0) || (iNdEx+skippy) < 0 {
-				return ErrInvalidLengthApiAmp
-			}
-			if (iNdEx + skippy) > l {
-				return io.ErrUnexpectedEOF
-			}
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *Position) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowApiAmp
-			}
-			if iNdEx >= l {
-				return io.ErrUnexpectedEOF
-			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= uint64(b&0x7F) << shift
-			if b < 0x80 {
-				break
+			m.Attachment = append(m.Attachment[:0], dAtA[iNdEx:postIndex]...)
+			if m.Attachment == nil {
+				m.Attachment = []byte{}
 			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: Position: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Position: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
+			iNdEx = postIndex
+		case 26:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field CordType", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Metric", wireType)
 			}
-			m.CordType = 0
+			m.Metric = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowApiAmp
@@ -6429,47 +17261,36 @@ func (m *Position) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.CordType |= CordType(b&0x7F) << shift
+				m.Metric |= Metric(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 3:
-			if wireType != 1 {
-				return fmt.Errorf("proto: wrong wireType = %d for field U", wireType)
-			}
-			var v uint64
-			if (iNdEx + 8) > l {
-				return io.ErrUnexpectedEOF
-			}
-			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
-			iNdEx += 8
-			m.U = float64(math.Float64frombits(v))
-		case 4:
-			if wireType != 1 {
-				return fmt.Errorf("proto: wrong wireType = %d for field V", wireType)
+		case 27:
+			if wireType != 5 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Size_0", wireType)
 			}
-			var v uint64
-			if (iNdEx + 8) > l {
+			var v uint32
+			if (iNdEx + 4) > l {
 				return io.ErrUnexpectedEOF
 			}
-			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
-			iNdEx += 8
-			m.V = float64(math.Float64frombits(v))
-		case 5:
-			if wireType != 1 {
-				return fmt.Errorf("proto: wrong wireType = %d for field W", wireType)
+			v = uint32(encoding_binary.LittleEndian.Uint32(dAtA[iNdEx:]))
+			iNdEx += 4
+			m.Size_0 = float32(math.Float32frombits(v))
+		case 28:
+			if wireType != 5 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Size_1", wireType)
 			}
-			var v uint64
-			if (iNdEx + 8) > l {
+			var v uint32
+			if (iNdEx + 4) > l {
 				return io.ErrUnexpectedEOF
 			}
-			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
-			iNdEx += 8
-			m.W = float64(math.Float64frombits(v))
-		case 6:
+			v = uint32(encoding_binary.LittleEndian.Uint32(dAtA[iNdEx:]))
+			iNdEx += 4
+			m.Size_1 = float32(math.Float32frombits(v))
+		case 29:
 			if wireType != 5 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ROU", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Size_2", wireType)
 			}
 			var v uint32
 			if (iNdEx + 4) > l {
@@ -6477,7 +17298,7 @@ func (m *Position) Unmarshal(dAtA []byte) error {
 			}
 			v = uint32(encoding_binary.LittleEndian.Uint32(dAtA[iNdEx:]))
 			iNdEx += 4
-			m.ROU = float32(math.Float32frombits(v))
+			m.Size_2 = float32(math.Float32frombits(v))
 		default:
 			iNdEx = preIndex
 			skippy, err := skipApiAmp(dAtA[iNdEx:])
@@ -6499,7 +17320,7 @@ func (m *Position) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *Tag) Unmarshal(dAtA []byte) error {
+func (m *TagTab) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -6522,17 +17343,17 @@ func (m *Tag) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Tag: wiretype end group for non-group")
+			return fmt.Errorf("proto: TagTab: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Tag: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: TagTab: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Use", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Label", wireType)
 			}
-			m.Use = 0
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowApiAmp
@@ -6542,16 +17363,29 @@ func (m *Tag) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Use |= TagUse(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Label = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		case 2:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field TagID_0", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Caption", wireType)
 			}
-			m.TagID_0 = 0
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowApiAmp
@@ -6561,34 +17395,27 @@ func (m *Tag) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.TagID_0 |= int64(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 3:
-			if wireType != 1 {
-				return fmt.Errorf("proto: wrong wireType = %d for field TagID_1", wireType)
-			}
-			m.TagID_1 = 0
-			if (iNdEx + 8) > l {
-				return io.ErrUnexpectedEOF
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApiAmp
 			}
-			m.TagID_1 = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
-			iNdEx += 8
-		case 4:
-			if wireType != 1 {
-				return fmt.Errorf("proto: wrong wireType = %d for field TagID_2", wireType)
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApiAmp
 			}
-			m.TagID_2 = 0
-			if (iNdEx + 8) > l {
+			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.TagID_2 = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
-			iNdEx += 8
-		case 13:
+			m.Caption = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field URL", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field About", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -6616,13 +17443,13 @@ func (m *Tag) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.URL = string(dAtA[iNdEx:postIndex])
+			m.About = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 14:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ContentType", wireType)
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CreatedAt", wireType)
 			}
-			var stringLen uint64
+			m.CreatedAt = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowApiAmp
@@ -6632,29 +17459,35 @@ func (m *Tag) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				m.CreatedAt |= int64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthApiAmp
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthApiAmp
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ModifiedAt", wireType)
 			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
+			m.ModifiedAt = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ModifiedAt |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			m.ContentType = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
-		case 15:
+		case 9:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Attachment", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Tags", wireType)
 			}
-			var byteLen int
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowApiAmp
@@ -6664,31 +17497,31 @@ func (m *Tag) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= int(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthApiAmp
 			}
-			postIndex := iNdEx + byteLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthApiAmp
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Attachment = append(m.Attachment[:0], dAtA[iNdEx:postIndex]...)
-			if m.Attachment == nil {
-				m.Attachment = []byte{}
+			m.Tags = append(m.Tags, &Tag{})
+			if err := m.Tags[len(m.Tags)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
 			}
 			iNdEx = postIndex
-		case 26:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Metric", wireType)
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Locales", wireType)
 			}
-			m.Metric = 0
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowApiAmp
@@ -6698,44 +17531,26 @@ func (m *Tag) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Metric |= Metric(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
-				}
-			}
-		case 27:
-			if wireType != 5 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Size_0", wireType)
+				}
 			}
-			var v uint32
-			if (iNdEx + 4) > l {
-				return io.ErrUnexpectedEOF
+			if msglen < 0 {
+				return ErrInvalidLengthApiAmp
 			}
-			v = uint32(encoding_binary.LittleEndian.Uint32(dAtA[iNdEx:]))
-			iNdEx += 4
-			m.Size_0 = float32(math.Float32frombits(v))
-		case 28:
-			if wireType != 5 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Size_1", wireType)
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthApiAmp
 			}
-			var v uint32
-			if (iNdEx + 4) > l {
+			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			v = uint32(encoding_binary.LittleEndian.Uint32(dAtA[iNdEx:]))
-			iNdEx += 4
-			m.Size_1 = float32(math.Float32frombits(v))
-		case 29:
-			if wireType != 5 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Size_2", wireType)
-			}
-			var v uint32
-			if (iNdEx + 4) > l {
-				return io.ErrUnexpectedEOF
+			m.Locales = append(m.Locales, &LocaleText{})
+			if err := m.Locales[len(m.Locales)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
 			}
-			v = uint32(encoding_binary.LittleEndian.Uint32(dAtA[iNdEx:]))
-			iNdEx += 4
-			m.Size_2 = float32(math.Float32frombits(v))
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipApiAmp(dAtA[iNdEx:])
@@ -6757,7 +17572,7 @@ func (m *Tag) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *TagTab) Unmarshal(dAtA []byte) error {
+func (m *LocaleText) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -6780,15 +17595,15 @@ func (m *TagTab) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: TagTab: wiretype end group for non-group")
+			return fmt.Errorf("proto: LocaleText: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: TagTab: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: LocaleText: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Label", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Locale", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -6816,11 +17631,11 @@ func (m *TagTab) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Label = string(dAtA[iNdEx:postIndex])
+			m.Locale = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Caption", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Label", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -6848,11 +17663,11 @@ func (m *TagTab) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Caption = string(dAtA[iNdEx:postIndex])
+			m.Label = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field About", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Caption", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -6880,51 +17695,13 @@ func (m *TagTab) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.About = string(dAtA[iNdEx:postIndex])
+			m.Caption = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 6:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field CreatedAt", wireType)
-			}
-			m.CreatedAt = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowApiAmp
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.CreatedAt |= int64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-		case 7:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ModifiedAt", wireType)
-			}
-			m.ModifiedAt = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowApiAmp
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.ModifiedAt |= int64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-		case 9:
+		case 4:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Tags", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field About", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowApiAmp
@@ -6934,25 +17711,23 @@ func (m *TagTab) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthApiAmp
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthApiAmp
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Tags = append(m.Tags, &Tag{})
-			if err := m.Tags[len(m.Tags)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.About = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -8132,6 +18907,174 @@ func (m *Err) Unmarshal(dAtA []byte) error {
 			}
 			m.Msg = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Retryable", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Retryable = bool(v != 0)
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Details", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Details = append(m.Details, &ErrDetail{})
+			if err := m.Details[len(m.Details)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipApiAmp(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ErrDetail) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowApiAmp
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ErrDetail: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ErrDetail: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Key", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Key = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Value", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApiAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApiAmp
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Value = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipApiAmp(dAtA[iNdEx:])
@@ -1,6 +1,8 @@
 package amp
 
 import (
+	"time"
+
 	"github.com/amp-3d/amp-sdk-go/stdlib/media"
 	"github.com/amp-3d/amp-sdk-go/stdlib/tag"
 	"github.com/amp-3d/amp-sdk-go/stdlib/task"
@@ -16,12 +18,14 @@ type App struct {
 	//   - FamilyID:    encompassing namespace ID used to group related apps (no spaces or punctuation)
 	//   - AppNameID:   identifies this app within its parent family and domain (no spaces or punctuation)
 	//
-	AppSpec      tag.Spec // Universally unique and persistent ID for this module (and the module's "home" planet if present)
-	Desc         string   // Human-readable description of this app
-	Version      string   // "v{MajorVers}.{MinorID}.{RevID}"
-	Dependencies []tag.ID // Module Tags this app may access
-	Invocations  []string // Additional aliases that invoke this app
-	AttrDecl     []string // Attrs to be resolved and registered with a HostSession
+	AppSpec      tag.Spec        // Universally unique and persistent ID for this module (and the module's "home" planet if present)
+	Desc         string          // Human-readable description of this app
+	Version      string          // "v{MajorVers}.{MinorID}.{RevID}" (see ParseVersion)
+	Dependencies []tag.ID        // Module Tags this app may access
+	Requires     []AppDependency // Apps that must be registered for this app to run (see Registry.ResolveApps)
+	Invocations  []string        // Additional aliases that invoke this app
+	AttrDecl     []string        // Attrs to be resolved and registered with a HostSession
+	Migrations   []Migration     // Upgrades to the app's persisted state, in ascending Version order (see RunMigrations)
 
 	// NewAppInstance is the entry point for an App.
 	// Called when an App is first invoked on an active User session and is not yet running.
@@ -29,6 +33,12 @@ type App struct {
 	NewAppInstance func(ctx AppContext) (AppInstance, error)
 }
 
+// AppDependency declares that an App requires another App whose version satisfies a constraint.
+type AppDependency struct {
+	AppID      tag.ID `json:"app_id"`               // AppSpec.ID of the required app
+	Constraint string `json:"constraint,omitempty"` // version constraint (see ParseConstraint) -- "" allows any version
+}
+
 // AppContext is provided by the amp runtime to an AppInstance for support and context.
 type AppContext interface {
 	task.Context          // Allows select{} for graceful handling of app shutdown
@@ -48,6 +58,80 @@ type AppContext interface {
 	PutAppAttr(attrSpec tag.ID, src ElemVal) error
 }
 
+// BusProvider is optionally implemented by an AppContext whose host offers a MessageBus (see BusOf).
+type BusProvider interface {
+
+	// Returns the host-level bus apps use to message each other (see Publish and Subscribe).
+	Bus() MessageBus
+}
+
+// KVStoreProvider is optionally implemented by an AppContext whose host persists a KVStore for the app (see StoreOf).
+type KVStoreProvider interface {
+
+	// Returns the app's persisted key/value store, namespaced to the app -- used for app state beyond settings (see KVStore).
+	Store() KVStore
+}
+
+// KVStore is an app's persisted key/value store, provided by the host so an app need not keep state in memory or open DB files
+// of its own.  It is backed by the host's storage layer (see Space.AppKVStore), so its entries are included in host snapshots
+// (see WriteSnapshot) and are available to the app's migrations (see Migration).  Keys are scoped to the app, so collision
+// with other apps is not possible.
+//
+// Each Set, Delete, or Update is durable once it returns.  An entry set with a TTL appears absent once the TTL has passed and is
+// removed by the next Purge.  Concurrency safe.
+type KVStore interface {
+
+	// Returns the value of the given key, or nil if it is not set (or has expired).
+	Get(key []byte) ([]byte, error)
+
+	// Sets the value of the given key, expiring it after ttl (or never if ttl is 0).  Neither buffer is retained.
+	Set(key, value []byte, ttl time.Duration) error
+
+	// Removes the given key (if set).
+	Delete(key []byte) error
+
+	// Calls fn for each unexpired entry whose key has the given prefix, in ascending key order.
+	// The buffers given to fn are only valid during the call.  If fn returns an error, iteration stops and that error is returned.
+	Iterate(prefix []byte, fn func(key, value []byte) error) error
+
+	// Calls fn with a txn that sees its own writes, applying those writes together once fn returns nil (or none of them if fn
+	// returns an error, which is then returned).  Writes to this store are serialized with txns, so what a txn reads remains
+	// unchanged by others until it completes.  The txn is only valid during fn.
+	Update(fn func(txn KVTxn) error) error
+
+	// Removes all expired entries, returning how many were removed.
+	Purge() (int, error)
+}
+
+// KVTxn reads and writes a KVStore within KVStore.Update, with the same semantics as the corresponding KVStore methods.
+type KVTxn interface {
+	Get(key []byte) ([]byte, error)
+	Set(key, value []byte, ttl time.Duration) error
+	Delete(key []byte) error
+	Iterate(prefix []byte, fn func(key, value []byte) error) error
+}
+
+// MessageBus is a host-level pub/sub bus that allows apps to message each other (e.g. a file system app notifying a media indexer).
+// Apps typically use the typed Publish() and Subscribe() rather than calling a MessageBus directly.
+//
+// Delivery guarantees:
+//   - A message is delivered at most once to each subscriber that is subscribed when it is published -- there is no replay or persistence.
+//   - Messages from the same publishing goroutine on the same topic are received in the order published.
+//     There is no ordering between different publishers or different topics.
+//   - Each subscriber has its own bounded mailbox.  When it is full, its MailboxOpts.Overflow policy applies to it alone:
+//     DropOldest and DropNewest drop (and count via Stats().Dropped) without affecting other subscribers, while BlockDeliver
+//     blocks the publisher until there is room, applying backpressure.
+//   - Messages are shared by reference with every subscriber and must be treated as read-only once published.
+type MessageBus interface {
+
+	// Delivers msg to every current subscriber of the given topic, returning the number of subscribers it was delivered to.
+	Publish(topic tag.ID, msg any) int
+
+	// Calls deliver for each message subsequently published to the given topic until cancel is called.
+	// deliver must not block (unless intentionally applying backpressure to publishers).
+	Subscribe(topic tag.ID, deliver func(msg any)) (cancel func())
+}
+
 // Pinner is characterized by the ability to emit Pins.
 type Pinner interface {
 
@@ -102,6 +186,18 @@ type Pin interface {
 	// CommitTx(tx *TxMsg) error
 }
 
+// PinReapHandler is optionally implemented by a Pin to be notified when it is reaped -- closed by the host because the pin went idle
+// or its session stopped responding (see PinReaper).  OnPinReaped is called before the pin's Context is closed.
+type PinReapHandler interface {
+	OnPinReaped(reason error)
+}
+
+// TxFlusher is optionally implemented by a Requester that holds pushed txs before sending them (see CoalescingRequester).
+// A Pin calls Flush to send any held txs immediately -- e.g. after a latency-sensitive update.
+type TxFlusher interface {
+	Flush() error
+}
+
 // // Wraps the task an App issues in response to Pin.HandleRequest()
 // type RequestHandler interface {
 
@@ -138,11 +234,18 @@ type ElemVal interface {
 }
 
 // TxMsg is workhorse generic transport serialization sent between client and host.
+//
+// A TxMsg is reference counted (see AddRef and ReleaseRef), and passing one to PushTx or SendTx passes on the caller's
+// reference.  Its DataStore is a pooled buffer (see bufs.Pool) that is referenced rather than copied as the tx is passed
+// along: a tx holding some of another's ops references the other's DataStore (see Derive), and a transport writes it as is
+// after the tx's header and body (see WriteTo).
 type TxMsg struct {
 	TxInfo
 	refCount  int32  // see AddRef() / ReleaseRef()
+	Seq       uint32 // if non-zero, the per-request sequence number assigned by a ResumableTransport (TxHeader bytes 12:16)
 	Ops       []TxOp // ordered operations to perform on the target
 	DataStore []byte // marshalled data store for Ops serialized data
+	backing   *TxMsg // if set, the tx whose DataStore this tx references and holds a reference on (see Derive())
 }
 
 // TxOp is an atomic operation on a target cell and is a unit of change (or message) for any target.
@@ -1,6 +1,7 @@
 package amp
 
 import (
+	"io"
 	"net/url"
 
 	"github.com/amp-3d/amp-sdk-go/stdlib/media"
@@ -19,6 +20,15 @@ type Host interface {
 
 	// StartNewSession creates a new HostSession and binds its Msg transport to a stream.
 	StartNewSession(parent HostService, via Transport) (HostSession, error)
+
+	// SnapshotTo writes this Host's persisted state (symbol tables, registry description, and app cell stores) to w as a single
+	// versioned archive, suitable for backups or cloning a host environment (see WriteSnapshot).
+	// Not to be confused with task.SnapshotOf(), which describes this Host's task tree.
+	SnapshotTo(w io.Writer) error
+
+	// RestoreFrom replaces this Host's persisted state with that of an archive written by SnapshotTo (see RestoreSnapshot).
+	// A Host restores before starting sessions, or with all sessions drained, since app instances may hold stale state.
+	RestoreFrom(r io.Reader) error
 }
 
 // Transport wraps a Msg transport abstraction, allowing a Host to connect over any data transport layer.
@@ -91,8 +101,18 @@ type Registry interface {
 	RegisterPrototype(context tag.Spec, prototype ElemVal, registerAs string) tag.Spec
 
 	// Registers an app by its UTag, URI, and schemas it supports.
+	// This can be called at any time (e.g. while sessions are live) and replaces any app already registered with the same tag.
 	RegisterApp(app *App) error
 
+	// Removes a registered app given its tag so it can no longer be invoked.
+	// Watchers (see WatchApps) are notified so that running instances of the app can be drained (see DrainAppInstance).
+	DeregisterApp(appTag tag.ID) error
+
+	// Calls fn after each subsequent app registration or deregistration until the returned cancel func is called.
+	// Calls to fn are serialized and fn must not register or deregister apps.
+	// For example, a HostSession watches its Host's registry so that it can follow apps loaded and unloaded at runtime.
+	WatchApps(fn func(ev AppEvent)) (cancel func())
+
 	// Looks-up an app by Tag -- READ ONLY ACCESS
 	GetAppByTag(appTag tag.ID) (*App, error)
 
@@ -101,6 +121,68 @@ type Registry interface {
 
 	// Instantiates an attr element value for a given attr spec -- typically followed by ElemVal.Unmarshal()
 	NewAttrElem(attrSpec tag.ID) (ElemVal, error)
+
+	// Returns a point-in-time description of all registered apps and attr types, suitable for serializing to JSON.
+	Describe() RegistryInfo
+
+	// Resolves the dependencies (App.Requires) of all registered apps, returning the resulting graph.
+	// A Host calls this on startup so that missing apps, version conflicts, and cycles fail fast.
+	// The returned error lists every unresolved dependency found.
+	ResolveApps() (*AppGraph, error)
+}
+
+// AppGraph is the resolved dependency graph of the apps registered with a Registry (see Registry.ResolveApps).
+type AppGraph struct {
+	Nodes []*AppNode `json:"nodes"` // in dependency order: each app appears after all the apps it requires
+}
+
+// AppNode is a resolved App within an AppGraph.
+type AppNode struct {
+	App      *App     `json:"-"`
+	AppSpec  string   `json:"app_spec"`
+	AppID    tag.ID   `json:"app_id"`
+	Version  string   `json:"version,omitempty"`
+	Requires []tag.ID `json:"requires,omitempty"` // AppIDs of the apps this app requires, each present in the graph
+}
+
+// RegistryInfo describes the contents of a Registry (see Registry.Describe).
+type RegistryInfo struct {
+	Apps  []AppInfo  `json:"apps"`  // sorted by AppSpec
+	Attrs []AttrInfo `json:"attrs"` // sorted by Spec
+}
+
+// AppInfo describes a registered App.
+type AppInfo struct {
+	AppSpec      string          `json:"app_spec"`
+	AppID        tag.ID          `json:"app_id"`
+	Desc         string          `json:"desc,omitempty"`
+	Version      string          `json:"version,omitempty"`
+	Invocations  []string        `json:"invocations"` // invocation names and URI schemes that resolve to this app
+	AttrDecl     []string        `json:"attr_decl,omitempty"`
+	Dependencies []tag.ID        `json:"dependencies,omitempty"`
+	Requires     []AppDependency `json:"requires,omitempty"`
+}
+
+// AttrInfo describes a registered attr or element type.
+type AttrInfo struct {
+	Spec     string `json:"spec"`
+	ID       tag.ID `json:"id"`
+	ElemType string `json:"elem_type,omitempty"` // ElemVal.ElemTypeName() of the registered prototype
+}
+
+// AppEventOp describes a change to the apps registered with a Registry.
+type AppEventOp int
+
+const (
+	AppRegistered AppEventOp = iota + 1
+	AppDeregistered
+)
+
+// AppEvent is sent to Registry.WatchApps() subscribers when an app is registered or deregistered.
+// When an app is replaced by RegisterApp(), AppDeregistered is sent for the previous app before AppRegistered is sent.
+type AppEvent struct {
+	Op  AppEventOp
+	App *App
 }
 
 // Requester wraps a client request to receive a cell's state / updates.
@@ -114,6 +196,7 @@ type Requester interface {
 
 	// Called by a Pin to notify its Requester that service is complete (successfully or not)
 	// No-op if the Requester is was already complete or was cancelled.
+	// A host relays a non-nil error to the client via MarshalErrTx.
 	OnComplete(err error)
 }
 
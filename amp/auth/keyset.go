@@ -0,0 +1,174 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"golang.org/x/sync/singleflight"
+)
+
+// minKeysRefresh is how often (at most) signing keys are re-fetched when tokens name unknown keys.
+const minKeysRefresh = 30 * time.Second
+
+// keysFetchTimeout bounds a fetch of signing keys, which is shared by all the tokens waiting on it.
+const keysFetchTimeout = 30 * time.Second
+
+// keySet is the oidc.KeySet of a provider's signing keys (its JWKS), fetched as tokens name unknown keys.
+//
+// Unlike oidc.RemoteKeySet, it requires a token's "alg" to match the key it names (the key's JWK "alg", if any, and its
+// type and curve), since go-jose otherwise picks the signature's hash from the token alone.  A fetch is shared by all the
+// tokens waiting on it (see singleflight), while tokens signed by keys already known are verified without waiting.
+type keySet struct {
+	url        string
+	client     *http.Client
+	algs       []jose.SignatureAlgorithm
+	minRefresh time.Duration
+	fetch      singleflight.Group
+
+	mu      sync.RWMutex
+	keys    jose.JSONWebKeySet
+	fetched time.Time
+}
+
+func newKeySet(url string, client *http.Client, algs []string) *keySet {
+	ks := &keySet{
+		url:        url,
+		client:     client,
+		minRefresh: minKeysRefresh,
+	}
+	for _, alg := range algs {
+		ks.algs = append(ks.algs, jose.SignatureAlgorithm(alg))
+	}
+	return ks
+}
+
+// VerifySignature implements oidc.KeySet, whose caller wraps any error as an invalid token.
+func (ks *keySet) VerifySignature(ctx context.Context, token string) ([]byte, error) {
+	jws, err := jose.ParseSigned(token, ks.algs)
+	if err != nil {
+		return nil, err
+	}
+	if len(jws.Signatures) != 1 {
+		return nil, errors.New("expected one signature")
+	}
+	hdr := jws.Signatures[0].Header
+
+	key, found := ks.key(hdr.KeyID)
+	if !found {
+		if key, found, err = ks.refresh(ctx, hdr.KeyID); err != nil {
+			return nil, err
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("unknown signing key %q", hdr.KeyID)
+	}
+	if !keyFitsAlg(key, hdr.Algorithm) {
+		return nil, fmt.Errorf("algorithm %q does not match key %q", hdr.Algorithm, hdr.KeyID)
+	}
+	payload, err := jws.Verify(&key)
+	if err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+func (ks *keySet) key(kid string) (jose.JSONWebKey, bool) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	for _, key := range ks.keys.Key(kid) {
+		if key.Use == "" || key.Use == "sig" {
+			return key, true
+		}
+	}
+	return jose.JSONWebKey{}, false
+}
+
+// refresh re-fetches the keys (unless fetched within minRefresh), returning the key with the given ID.
+func (ks *keySet) refresh(ctx context.Context, kid string) (jose.JSONWebKey, bool, error) {
+	ks.mu.RLock()
+	recent := time.Since(ks.fetched) < ks.minRefresh
+	ks.mu.RUnlock()
+	if recent {
+		return jose.JSONWebKey{}, false, nil
+	}
+
+	_, err, _ := ks.fetch.Do("", func() (any, error) {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), keysFetchTimeout)
+		defer cancel()
+
+		var keys jose.JSONWebKeySet
+		if err := ks.get(ctx, &keys); err != nil {
+			return nil, err
+		}
+		ks.mu.Lock()
+		ks.keys = keys
+		ks.fetched = time.Now()
+		ks.mu.Unlock()
+		return nil, nil
+	})
+	if err != nil {
+		return jose.JSONWebKey{}, false, err
+	}
+	key, found := ks.key(kid)
+	return key, found, nil
+}
+
+func (ks *keySet) get(ctx context.Context, dst *jose.JSONWebKeySet) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ks.url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := ks.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("fetching keys: %s returned %s", req.URL, resp.Status)
+	}
+	if err = json.Unmarshal(body, dst); err != nil {
+		return fmt.Errorf("bad keys from %s: %v", req.URL, err)
+	}
+	return nil
+}
+
+// keyFitsAlg returns true if the given key may verify signatures made with the given algorithm.
+func keyFitsAlg(key jose.JSONWebKey, alg string) bool {
+	if key.Algorithm != "" && key.Algorithm != alg {
+		return false
+	}
+	switch pub := key.Key.(type) {
+	case *rsa.PublicKey:
+		switch jose.SignatureAlgorithm(alg) {
+		case jose.RS256, jose.RS384, jose.RS512, jose.PS256, jose.PS384, jose.PS512:
+			return true
+		}
+	case *ecdsa.PublicKey:
+		switch jose.SignatureAlgorithm(alg) {
+		case jose.ES256:
+			return pub.Curve == elliptic.P256()
+		case jose.ES384:
+			return pub.Curve == elliptic.P384()
+		case jose.ES512:
+			return pub.Curve == elliptic.P521()
+		}
+	}
+	return false
+}
@@ -0,0 +1,308 @@
+// Package auth lets a host require an OAuth2 / OpenID Connect login before a session is granted.
+//
+// A client signs in with an OIDC provider (e.g. Google) and sends the resulting ID token as Login.Checkpoint.Token.
+// The host passes the Login to Provider.Authenticate, which verifies the token (signature, issuer, audience, expiry, and
+// optionally nonce) via github.com/coreos/go-oidc and maps the external identity to an amp.Identity used for the session
+// (see amp.IdentityOf).  Tokens are refreshed via golang.org/x/oauth2.
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+
+	"github.com/amp-3d/amp-sdk-go/amp"
+)
+
+// Authenticator verifies a session's Login, returning the identity the session is granted.
+type Authenticator interface {
+	Authenticate(ctx context.Context, login *amp.Login) (*amp.Identity, error)
+}
+
+// ProviderConfig configures an OIDC Provider.
+type ProviderConfig struct {
+	Issuer       string   // e.g. "https://accounts.google.com"
+	ClientID     string   // this host's OAuth2 client ID, required in the "aud" claim of ID tokens
+	ClientSecret string   // used for token refresh
+	Scopes       []string // scopes requested by an authorization (see Provider.OAuth2Config)
+
+	// Endpoints -- if empty, they are read from the issuer's discovery document ({Issuer}/.well-known/openid-configuration).
+	JWKSURL  string
+	TokenURL string
+
+	// SigningAlgs are the algorithms ID tokens may be signed with (default as discovered, or else RS256).
+	SigningAlgs []string
+
+	ClockSkew time.Duration // tolerance used when checking token expiry (default 1 minute)
+
+	// CheckNonce, if set, is passed the "nonce" claim of each ID token (or "" if it has none) and fails verification if it
+	// returns an error -- e.g. so that a host issuing nonces for its clients' authorization requests accepts each only once.
+	// If nil, nonces are not checked, so an ID token is accepted from whoever presents it until it expires.
+	CheckNonce func(nonce string) error
+
+	// RolesClaim names a claim holding a string or array of strings that become the identity's roles (e.g. "groups").
+	RolesClaim string
+
+	// MapIdentity maps verified claims to an amp.Identity.  If nil, DefaultIdentity is used.
+	MapIdentity func(claims *Claims) (*amp.Identity, error)
+}
+
+// Claims are the claims of a verified OIDC ID token.
+type Claims struct {
+	Issuer        string    `json:"-"`
+	Subject       string    `json:"-"`
+	Audience      []string  `json:"-"`
+	Expiry        time.Time `json:"-"`
+	IssuedAt      time.Time `json:"-"`
+	Nonce         string    `json:"-"`
+	Email         string    `json:"email"`
+	EmailVerified bool      `json:"email_verified"`
+	Name          string    `json:"name"`
+
+	// All claims as decoded JSON, including those above (e.g. for ProviderConfig.RolesClaim).
+	Raw map[string]any `json:"-"`
+}
+
+// Google returns a ProviderConfig for Google sign-in.
+func Google(clientID, clientSecret string) ProviderConfig {
+	return ProviderConfig{
+		Issuer:       "https://accounts.google.com",
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		Scopes:       []string{oidc.ScopeOpenID, "email", "profile"},
+	}
+}
+
+// DefaultIdentity maps the given claims to an amp identity whose UserUID is the SHA-256 (in hex) of the issuer and subject,
+// so that the same external account always maps to the same amp user and distinct accounts never collide.
+func DefaultIdentity(claims *Claims) (*amp.Identity, error) {
+	if claims.Subject == "" {
+		return nil, errInvalidToken("missing subject")
+	}
+	sum := sha256.Sum256([]byte(claims.Issuer + "#" + claims.Subject))
+	return &amp.Identity{
+		UserUID: hex.EncodeToString(sum[:]),
+	}, nil
+}
+
+// Provider verifies ID tokens issued by an OIDC provider and refreshes access tokens.  It is safe for concurrent use.
+//
+// Tokens are verified by an oidc.IDTokenVerifier (github.com/coreos/go-oidc) over a key set that fetches the provider's keys
+// as tokens name unknown ones (allowing for key rotation) and binds each token's algorithm to the key it names.
+type Provider struct {
+	cfg      ProviderConfig
+	client   *http.Client
+	oauth2   oauth2.Config
+	keys     *keySet
+	verifier *oidc.IDTokenVerifier
+}
+
+// NewProvider returns a Provider for the given config, fetching the issuer's discovery document if any endpoints are unset.
+// If client is nil, http.DefaultClient is used.
+func NewProvider(ctx context.Context, cfg ProviderConfig, client *http.Client) (*Provider, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	if cfg.Issuer == "" || cfg.ClientID == "" {
+		return nil, amp.ErrCode_BadValue.Error("auth: Issuer and ClientID are required")
+	}
+	if cfg.ClockSkew <= 0 {
+		cfg.ClockSkew = time.Minute
+	}
+	if cfg.MapIdentity == nil {
+		cfg.MapIdentity = DefaultIdentity
+	}
+
+	ctx = oidc.ClientContext(ctx, client)
+	endpoints := oidc.ProviderConfig{
+		IssuerURL:  cfg.Issuer,
+		JWKSURL:    cfg.JWKSURL,
+		TokenURL:   cfg.TokenURL,
+		Algorithms: cfg.SigningAlgs,
+	}
+	if cfg.JWKSURL == "" || cfg.TokenURL == "" {
+		discovered, err := oidc.NewProvider(ctx, cfg.Issuer)
+		if err != nil {
+			return nil, amp.ErrCode_AuthFailed.Errorf("auth: %v", err)
+		}
+		var doc oidc.ProviderConfig
+		if err = discovered.Claims(&doc); err != nil {
+			return nil, amp.ErrCode_AuthFailed.Errorf("auth: %v", err)
+		}
+		if endpoints.JWKSURL == "" {
+			endpoints.JWKSURL = doc.JWKSURL
+		}
+		if endpoints.TokenURL == "" {
+			endpoints.TokenURL = doc.TokenURL
+		}
+		if len(endpoints.Algorithms) == 0 {
+			endpoints.Algorithms = doc.Algorithms
+		}
+		endpoints.AuthURL = doc.AuthURL
+	}
+	if len(endpoints.Algorithms) == 0 {
+		endpoints.Algorithms = []string{oidc.RS256}
+	}
+
+	keys := newKeySet(endpoints.JWKSURL, client, endpoints.Algorithms)
+	return &Provider{
+		cfg:    cfg,
+		client: client,
+		oauth2: oauth2.Config{
+			ClientID:     cfg.ClientID,
+			ClientSecret: cfg.ClientSecret,
+			Endpoint:     endpoints.NewProvider(ctx).Endpoint(),
+			Scopes:       cfg.Scopes,
+		},
+		keys: keys,
+		verifier: oidc.NewVerifier(cfg.Issuer, keys, &oidc.Config{
+			ClientID:             cfg.ClientID,
+			SupportedSigningAlgs: endpoints.Algorithms,
+			Now: func() time.Time {
+				return time.Now().Add(-cfg.ClockSkew)
+			},
+		}),
+	}, nil
+}
+
+// OAuth2Config returns the OAuth2 config of this provider's client, e.g. for a host to run an authorization code flow.
+func (prov *Provider) OAuth2Config() *oauth2.Config {
+	cfg := prov.oauth2
+	return &cfg
+}
+
+// Verify verifies the given raw ID token (its signature, issuer, audience, expiry, and nonce), returning its claims.
+func (prov *Provider) Verify(ctx context.Context, idToken string) (*Claims, error) {
+	tok, err := prov.verifier.Verify(oidc.ClientContext(ctx, prov.client), idToken)
+	if err != nil {
+		var expired *oidc.TokenExpiredError
+		if errors.As(err, &expired) {
+			return nil, amp.ErrCode_SessionExpired.Error("token expired")
+		}
+		return nil, errInvalidToken("%v", err)
+	}
+	if prov.cfg.CheckNonce != nil {
+		if err = prov.cfg.CheckNonce(tok.Nonce); err != nil {
+			return nil, errInvalidToken("nonce: %v", err)
+		}
+	}
+
+	claims := &Claims{
+		Issuer:   tok.Issuer,
+		Subject:  tok.Subject,
+		Audience: tok.Audience,
+		Expiry:   tok.Expiry,
+		IssuedAt: tok.IssuedAt,
+		Nonce:    tok.Nonce,
+	}
+	if err = tok.Claims(claims); err == nil {
+		err = tok.Claims(&claims.Raw)
+	}
+	if err != nil {
+		return nil, errInvalidToken("bad claims: %v", err)
+	}
+	return claims, nil
+}
+
+// Authenticate implements Authenticator by verifying the ID token in login.Checkpoint.Token.
+// On success, login.UserUID is set to the mapped identity's UserUID.
+func (prov *Provider) Authenticate(ctx context.Context, login *amp.Login) (*amp.Identity, error) {
+	if login.Checkpoint == nil || login.Checkpoint.Token == "" {
+		return nil, amp.ErrNoAuthToken
+	}
+	claims, err := prov.Verify(ctx, login.Checkpoint.Token)
+	if err != nil {
+		return nil, err
+	}
+	id, err := prov.cfg.MapIdentity(claims)
+	if err != nil {
+		return nil, err
+	}
+	if prov.cfg.RolesClaim != "" {
+		switch roles := claims.Raw[prov.cfg.RolesClaim].(type) {
+		case string:
+			id.Roles = append(id.Roles, roles)
+		case []any:
+			for _, role := range roles {
+				if str, ok := role.(string); ok {
+					id.Roles = append(id.Roles, str)
+				}
+			}
+		}
+	}
+	login.UserUID = id.UserUID
+	return id, nil
+}
+
+// Refresh exchanges the given refresh token for a new access token, also returning the new ID token (if one was issued).
+func (prov *Provider) Refresh(ctx context.Context, refreshToken string) (tok *amp.AuthToken, idToken string, err error) {
+	if prov.oauth2.Endpoint.TokenURL == "" {
+		return nil, "", amp.ErrCode_UnsupportedOp.Error("auth: provider has no token endpoint")
+	}
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, prov.client)
+	refreshed, err := prov.oauth2.TokenSource(ctx, &oauth2.Token{RefreshToken: refreshToken}).Token()
+	if err != nil {
+		var retrieveErr *oauth2.RetrieveError
+		if errors.As(err, &retrieveErr) {
+			return nil, "", amp.ErrCode_AuthFailed.Errorf("auth: refresh failed: %s %s", retrieveErr.ErrorCode, retrieveErr.ErrorDescription)
+		}
+		return nil, "", amp.ErrCode_NotConnected.Errorf("auth: %v", err)
+	}
+
+	tok = &amp.AuthToken{
+		AccessToken:  refreshed.AccessToken,
+		TokenType:    refreshed.TokenType,
+		RefreshToken: refreshed.RefreshToken, // oauth2 keeps an unchanged refresh token that a provider omits
+	}
+	if !refreshed.Expiry.IsZero() {
+		tok.Expiry = refreshed.Expiry.Unix()
+	}
+	idToken, _ = refreshed.Extra("id_token").(string)
+	return tok, idToken, nil
+}
+
+func errInvalidToken(format string, args ...any) error {
+	return amp.ErrCode_AuthFailed.Errorf("invalid token: %s", fmt.Sprintf(format, args...))
+}
+
+// TokenSource holds a session's OAuth2 token, refreshing it via its Provider shortly before it expires.  It is safe for concurrent use.
+type TokenSource struct {
+	prov *Provider
+	mu   sync.Mutex
+	tok  *amp.AuthToken
+}
+
+// NewTokenSource returns a TokenSource starting with the given token.
+func NewTokenSource(prov *Provider, tok *amp.AuthToken) *TokenSource {
+	return &TokenSource{
+		prov: prov,
+		tok:  tok,
+	}
+}
+
+// Token returns a token that is valid for at least the provider's ClockSkew, refreshing it if needed.
+func (ts *TokenSource) Token(ctx context.Context) (*amp.AuthToken, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	if ts.tok.Expiry == 0 || time.Now().Add(ts.prov.cfg.ClockSkew).Before(time.Unix(ts.tok.Expiry, 0)) {
+		return ts.tok, nil
+	}
+	if ts.tok.RefreshToken == "" {
+		return nil, amp.ErrCode_SessionExpired.Error("token expired and cannot be refreshed")
+	}
+	tok, _, err := ts.prov.Refresh(ctx, ts.tok.RefreshToken)
+	if err != nil {
+		return nil, err
+	}
+	ts.tok = tok
+	return tok, nil
+}
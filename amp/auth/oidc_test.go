@@ -0,0 +1,258 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+
+	"github.com/amp-3d/amp-sdk-go/amp"
+)
+
+// testIssuer is a minimal OIDC provider serving discovery, keys, and refresh.
+type testIssuer struct {
+	*httptest.Server
+	rsaKey *rsa.PrivateKey
+	ecKey  *ecdsa.PrivateKey
+
+	mu   sync.Mutex
+	keys jose.JSONWebKeySet
+}
+
+func newTestIssuer(t *testing.T) *testIssuer {
+	iss := &testIssuer{}
+	var err error
+	if iss.rsaKey, err = rsa.GenerateKey(rand.Reader, 2048); err != nil {
+		t.Fatal(err)
+	}
+	if iss.ecKey, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader); err != nil {
+		t.Fatal(err)
+	}
+	iss.keys.Keys = []jose.JSONWebKey{{Key: &iss.rsaKey.PublicKey, KeyID: "rsa1", Algorithm: "RS256", Use: "sig"}}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{
+			"issuer":                                iss.URL,
+			"jwks_uri":                              iss.URL + "/keys",
+			"token_endpoint":                        iss.URL + "/token",
+			"id_token_signing_alg_values_supported": []string{"RS256", "ES256", "ES384"},
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		iss.mu.Lock()
+		defer iss.mu.Unlock()
+		json.NewEncoder(w).Encode(iss.keys)
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.FormValue("grant_type") != "refresh_token" || r.FormValue("refresh_token") != "refresh-1" {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+		if _, secret, _ := r.BasicAuth(); secret != "secret" && r.FormValue("client_secret") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_client"})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{
+			"access_token": "access-2",
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+			"id_token":     "id-2",
+		})
+	})
+	iss.Server = httptest.NewServer(mux)
+	t.Cleanup(iss.Close)
+	return iss
+}
+
+func (iss *testIssuer) addKey(key jose.JSONWebKey) {
+	iss.mu.Lock()
+	defer iss.mu.Unlock()
+	iss.keys.Keys = append(iss.keys.Keys, key)
+}
+
+func (iss *testIssuer) sign(t *testing.T, alg jose.SignatureAlgorithm, kid string, claims map[string]any) string {
+	var key any = iss.rsaKey
+	if alg != jose.RS256 {
+		key = iss.ecKey
+	}
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: alg, Key: key}, (&jose.SignerOptions{}).WithHeader("kid", kid))
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload, _ := json.Marshal(claims)
+	jws, err := signer.Sign(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := jws.CompactSerialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func TestProvider(t *testing.T) {
+	ctx := context.Background()
+	iss := newTestIssuer(t)
+
+	prov, err := NewProvider(ctx, ProviderConfig{
+		Issuer:       iss.URL,
+		ClientID:     "amp-host",
+		ClientSecret: "secret",
+		RolesClaim:   "groups",
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now().Unix()
+	claims := map[string]any{
+		"iss":    iss.URL,
+		"sub":    "user-42",
+		"aud":    "amp-host",
+		"exp":    now + 300,
+		"iat":    now,
+		"email":  "user42@example.com",
+		"groups": []string{"owner"},
+	}
+
+	login := &amp.Login{
+		Checkpoint: &amp.AuthCheckpoint{Token: iss.sign(t, jose.RS256, "rsa1", claims)},
+	}
+	id, err := prov.Authenticate(ctx, login)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id.UserUID == "" || login.UserUID != id.UserUID || !id.HasRole("owner") {
+		t.Fatalf("unexpected identity %+v", id)
+	}
+
+	// The same external account always maps to the same amp user
+	again, err := prov.Authenticate(ctx, &amp.Login{Checkpoint: &amp.AuthCheckpoint{Token: iss.sign(t, jose.RS256, "rsa1", claims)}})
+	if err != nil || again.UserUID != id.UserUID {
+		t.Fatalf("identity not stable: %v %v", again, err)
+	}
+
+	// Invalid tokens are rejected
+	for name, mutate := range map[string]func(c map[string]any){
+		"audience": func(c map[string]any) { c["aud"] = "other-client" },
+		"issuer":   func(c map[string]any) { c["iss"] = "https://evil.example.com" },
+		"expired":  func(c map[string]any) { c["exp"] = now - 3600 },
+	} {
+		bad := map[string]any{}
+		for k, v := range claims {
+			bad[k] = v
+		}
+		mutate(bad)
+		if _, err = prov.Verify(ctx, iss.sign(t, jose.RS256, "rsa1", bad)); err == nil {
+			t.Errorf("%s: expected verify to fail", name)
+		}
+	}
+	tampered := iss.sign(t, jose.RS256, "rsa1", claims)
+	tampered = tampered[:len(tampered)-4] + "AAAA"
+	if _, err = prov.Verify(ctx, tampered); err == nil {
+		t.Error("expected bad signature to fail")
+	}
+	if _, err = prov.Authenticate(ctx, &amp.Login{}); err != amp.ErrNoAuthToken {
+		t.Errorf("expected ErrNoAuthToken, got %v", err)
+	}
+
+	// The same account of another issuer is a different user
+	if other, _ := DefaultIdentity(&Claims{Issuer: "https://other.example.com", Subject: "user-42"}); len(id.UserUID) != 64 || other.UserUID == id.UserUID {
+		t.Fatalf("expected full-width, issuer-scoped user IDs, got %q and %q", id.UserUID, other.UserUID)
+	}
+
+	// Rotated-in keys are fetched on demand
+	prov.keys.minRefresh = 0
+	iss.addKey(jose.JSONWebKey{Key: &iss.ecKey.PublicKey, KeyID: "ec1", Algorithm: "ES256", Use: "sig"})
+	if _, err = prov.Verify(ctx, iss.sign(t, jose.ES256, "ec1", claims)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = prov.Verify(ctx, iss.sign(t, jose.ES256, "rsa1", claims)); err == nil {
+		t.Error("expected algorithm mismatch to fail")
+	}
+
+	// The signature algorithm must match the key, e.g. an ES384 signature (by a P-256 key) is refused
+	{
+		b64 := base64.RawURLEncoding.EncodeToString
+		hdr, _ := json.Marshal(map[string]string{"alg": "ES384", "kid": "ec1"})
+		payload, _ := json.Marshal(claims)
+		signed := b64(hdr) + "." + b64(payload)
+		digest := sha512.Sum384([]byte(signed))
+		r, s, err := ecdsa.Sign(rand.Reader, iss.ecKey, digest[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		sig := make([]byte, 96)
+		r.FillBytes(sig[:48])
+		s.FillBytes(sig[48:])
+		if _, err = prov.Verify(ctx, signed+"."+b64(sig)); err == nil {
+			t.Error("expected an ES384 signature by a P-256 key to fail")
+		}
+	}
+
+	// A token's nonce is checked if the host issues them
+	used := map[string]bool{}
+	nonceProv, err := NewProvider(ctx, ProviderConfig{
+		Issuer:   iss.URL,
+		ClientID: "amp-host",
+		CheckNonce: func(nonce string) error {
+			if nonce == "" || used[nonce] {
+				return errors.New("unknown nonce")
+			}
+			used[nonce] = true
+			return nil
+		},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = nonceProv.Verify(ctx, iss.sign(t, jose.RS256, "rsa1", claims)); err == nil {
+		t.Error("expected a missing nonce to fail")
+	}
+	claims["nonce"] = "n-1"
+	withNonce := iss.sign(t, jose.RS256, "rsa1", claims)
+	if verified, err := nonceProv.Verify(ctx, withNonce); err != nil || verified.Nonce != "n-1" {
+		t.Fatalf("expected nonce to be accepted: %v", err)
+	}
+	if _, err = nonceProv.Verify(ctx, withNonce); err == nil || !strings.Contains(err.Error(), "nonce") {
+		t.Errorf("expected a reused nonce to fail, got %v", err)
+	}
+
+	// Expired tokens are refreshed
+	ts := NewTokenSource(prov, &amp.AuthToken{
+		AccessToken:  "access-1",
+		RefreshToken: "refresh-1",
+		Expiry:       now - 1,
+	})
+	tok, err := ts.Token(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, idToken, err := prov.Refresh(ctx, "refresh-1"); err != nil || idToken != "id-2" {
+		t.Fatalf("expected refresh to return the new ID token, got %q: %v", idToken, err)
+	}
+	if tok.AccessToken != "access-2" || tok.RefreshToken != "refresh-1" || tok.Expiry <= now {
+		t.Fatalf("unexpected refreshed token %+v", tok)
+	}
+	if _, _, err = prov.Refresh(ctx, "revoked"); err == nil {
+		t.Fatal("expected refresh to fail")
+	}
+}
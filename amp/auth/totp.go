@@ -0,0 +1,318 @@
+package auth
+
+import (
+	"cmp"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/amp-3d/amp-sdk-go/amp"
+)
+
+// TOTPChallenge is sent as LoginChallenge.Hash when a host requires a second factor for a login.
+// The client responds with a LoginResponse whose HashResponse is the user's current TOTP code (or one of their recovery codes).
+const TOTPChallenge = "amp.auth.totp"
+
+// TOTPConfig configures time-based one-time passwords (RFC 6238) using HMAC-SHA1, as supported by common authenticator apps.
+type TOTPConfig struct {
+	Issuer string        // shown by authenticator apps (e.g. "My amp host")
+	Digits int           // code length, from 6 to 8 (default 6)
+	Period time.Duration // time step (default 30s)
+	Skew   int           // number of time steps before or after the current step that are also accepted (default 1)
+}
+
+func (cfg *TOTPConfig) setDefaults() {
+	cfg.Digits = min(max(cfg.Digits, 6), 8) // RFC 4226 §5.3 -- and a uint32 holds at most 9 digits
+	if cfg.Period <= 0 {
+		cfg.Period = 30 * time.Second
+	}
+	if cfg.Skew < 0 {
+		cfg.Skew = 0
+	} else if cfg.Skew == 0 {
+		cfg.Skew = 1
+	}
+}
+
+var totpBase32 = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// TOTPCode returns the code for the given secret at the given time.
+func (cfg TOTPConfig) TOTPCode(secret []byte, t time.Time) string {
+	cfg.setDefaults()
+	return totpCode(secret, t.Unix()/int64(cfg.Period/time.Second), cfg.Digits)
+}
+
+// totpCode computes the HOTP value (RFC 4226) for the given counter.
+func totpCode(secret []byte, counter int64, digits int) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(counter))
+	mac := hmac.New(sha1.New, secret)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0F
+	value := binary.BigEndian.Uint32(sum[offset:]) & 0x7FFFFFFF
+	mod := uint32(1)
+	for i := 0; i < digits; i++ {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", digits, value%mod)
+}
+
+// TOTPEnrollment is a user's second factor state, persisted by a host via a TOTPStore.
+type TOTPEnrollment struct {
+	Secret        []byte   `json:"secret"`
+	Confirmed     bool     `json:"confirmed"`      // set once the user has proven their authenticator works (see ConfirmEnrollment)
+	LastStep      int64    `json:"last_step"`      // the most recently accepted time step, preventing code replay
+	RecoveryCodes [][]byte `json:"recovery_codes"` // SHA-256 hashes of unused recovery codes
+
+	Failures    int       `json:"failures,omitempty"` // consecutive codes refused since the last accepted one
+	LockedUntil time.Time `json:"locked_until"`       // codes are refused unchecked until this time (see TwoFactor.MaxFailures)
+}
+
+// TOTPStore persists TOTP enrollments by amp user ID.  Implementations must be safe for concurrent use.
+type TOTPStore interface {
+
+	// Returns the enrollment for the given user, or nil if the user is not enrolled.
+	GetTOTP(userUID string) (*TOTPEnrollment, error)
+
+	// Stores (or if nil, removes) the enrollment for the given user.
+	PutTOTP(userUID string, enrollment *TOTPEnrollment) error
+}
+
+// NewMemoryTOTPStore returns a volatile TOTPStore.
+func NewMemoryTOTPStore() TOTPStore {
+	return &memoryTOTPStore{
+		enrollments: make(map[string]TOTPEnrollment),
+	}
+}
+
+type memoryTOTPStore struct {
+	mu          sync.Mutex
+	enrollments map[string]TOTPEnrollment
+}
+
+func (store *memoryTOTPStore) GetTOTP(userUID string) (*TOTPEnrollment, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	enrollment, exists := store.enrollments[userUID]
+	if !exists {
+		return nil, nil
+	}
+	return &enrollment, nil
+}
+
+func (store *memoryTOTPStore) PutTOTP(userUID string, enrollment *TOTPEnrollment) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	if enrollment == nil {
+		delete(store.enrollments, userUID)
+	} else {
+		store.enrollments[userUID] = *enrollment
+	}
+	return nil
+}
+
+// TwoFactor implements TOTP enrollment and verification as an optional second login factor.
+//
+// A host's login handshake proceeds as follows once a login's primary factor succeeds (e.g. see Provider.Authenticate):
+//  1. If Required() returns true for the user, the host sends a LoginChallenge with Hash = TOTPChallenge.
+//  2. The client responds with a LoginResponse holding the user's code, which the host passes to VerifyResponse().
+type TwoFactor struct {
+	Config TOTPConfig
+	Store  TOTPStore
+
+	NumRecoveryCodes int // recovery codes issued on enrollment (default 10)
+
+	// Once a user's codes have been refused MaxFailures times in a row (default 5), verification is locked out for Lockout
+	// (default 1m), doubling with each further failure, so that codes can't be guessed at the rate logins can be attempted.
+	MaxFailures int
+	Lockout     time.Duration
+
+	mu sync.Mutex // serializes read-modify-write of enrollments
+}
+
+// Enroll provisions a new secret for the given user, returning the otpauth:// URI to present to the user (typically as a QR code)
+// and their recovery codes, which are only available now.  The enrollment takes effect once confirmed via ConfirmEnrollment.
+//
+// A pending enrollment is replaced, but a confirmed one is not: it must first be removed via Disable (having verified the user's
+// current code, e.g. via VerifyResponse), so that a hijacked session can't swap in an authenticator of its own.
+func (tf *TwoFactor) Enroll(userUID, accountName string) (otpauthURI string, recoveryCodes []string, err error) {
+	tf.mu.Lock()
+	defer tf.mu.Unlock()
+
+	if prev, err := tf.Store.GetTOTP(userUID); err != nil {
+		return "", nil, err
+	} else if prev != nil && prev.Confirmed {
+		return "", nil, amp.ErrCode_AuthFailed.Error("second factor already enrolled")
+	}
+
+	enrollment := &TOTPEnrollment{
+		Secret: make([]byte, 20),
+	}
+	if _, err = rand.Read(enrollment.Secret); err != nil {
+		return "", nil, err
+	}
+
+	numCodes := tf.NumRecoveryCodes
+	if numCodes <= 0 {
+		numCodes = 10
+	}
+	for i := 0; i < numCodes; i++ {
+		var buf [10]byte
+		if _, err = rand.Read(buf[:]); err != nil {
+			return "", nil, err
+		}
+		code := strings.ToLower(totpBase32.EncodeToString(buf[:]))
+		code = code[:4] + "-" + code[4:8] + "-" + code[8:12] + "-" + code[12:16]
+		recoveryCodes = append(recoveryCodes, code)
+		enrollment.RecoveryCodes = append(enrollment.RecoveryCodes, hashRecoveryCode(code))
+	}
+
+	if err = tf.Store.PutTOTP(userUID, enrollment); err != nil {
+		return "", nil, err
+	}
+	return tf.Config.URI(enrollment.Secret, accountName), recoveryCodes, nil
+}
+
+// URI returns the otpauth:// key URI for the given secret and account, as understood by authenticator apps.
+func (cfg TOTPConfig) URI(secret []byte, accountName string) string {
+	cfg.setDefaults()
+
+	label := accountName
+	if cfg.Issuer != "" {
+		label = cfg.Issuer + ":" + accountName
+	}
+	params := url.Values{
+		"secret":    {totpBase32.EncodeToString(secret)},
+		"algorithm": {"SHA1"},
+		"digits":    {fmt.Sprint(cfg.Digits)},
+		"period":    {fmt.Sprint(int64(cfg.Period / time.Second))},
+	}
+	if cfg.Issuer != "" {
+		params.Set("issuer", cfg.Issuer)
+	}
+	uri := url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     "/" + label,
+		RawQuery: params.Encode(),
+	}
+	return uri.String()
+}
+
+// ConfirmEnrollment activates a pending enrollment given a valid code from the user's authenticator.
+func (tf *TwoFactor) ConfirmEnrollment(userUID, code string) error {
+	return tf.verify(userUID, code, false)
+}
+
+// Required returns true if the given user has a confirmed enrollment and so must pass a second factor to log in.
+func (tf *TwoFactor) Required(userUID string) (bool, error) {
+	enrollment, err := tf.Store.GetTOTP(userUID)
+	if err != nil {
+		return false, err
+	}
+	return enrollment != nil && enrollment.Confirmed, nil
+}
+
+// Challenge returns the LoginChallenge a host sends when a second factor is required.
+func (tf *TwoFactor) Challenge() *amp.LoginChallenge {
+	return &amp.LoginChallenge{
+		Hash: []byte(TOTPChallenge),
+	}
+}
+
+// VerifyResponse verifies the TOTP code (or recovery code) in the given LoginResponse.
+// A TOTP code is accepted at most once, and each recovery code may only be used once.  While the user is locked out after
+// repeated failures (see MaxFailures), ErrCode_RateLimited is returned with a RetryAfterDetail.
+func (tf *TwoFactor) VerifyResponse(userUID string, resp *amp.LoginResponse) error {
+	return tf.verify(userUID, string(resp.HashResponse), true)
+}
+
+// Disable removes the given user's enrollment.
+func (tf *TwoFactor) Disable(userUID string) error {
+	tf.mu.Lock()
+	defer tf.mu.Unlock()
+	return tf.Store.PutTOTP(userUID, nil)
+}
+
+func (tf *TwoFactor) verify(userUID, code string, requireConfirmed bool) error {
+	tf.mu.Lock()
+	defer tf.mu.Unlock()
+
+	enrollment, err := tf.Store.GetTOTP(userUID)
+	if err != nil {
+		return err
+	}
+	if enrollment == nil || enrollment.Confirmed != requireConfirmed {
+		return amp.ErrCode_AuthFailed.Error("second factor not enrolled")
+	}
+
+	now := time.Now()
+	if wait := enrollment.LockedUntil.Sub(now); wait > 0 {
+		err := amp.ErrCode_RateLimited.Errorf("too many invalid second factor codes (retry in %v)", wait.Round(time.Second))
+		return err.(*amp.Err).WithDetail(amp.RetryAfterDetail, strconv.FormatInt(wait.Milliseconds(), 10))
+	}
+
+	if tf.accept(enrollment, strings.TrimSpace(code), now, requireConfirmed) {
+		enrollment.Failures = 0
+		enrollment.LockedUntil = time.Time{}
+		return tf.Store.PutTOTP(userUID, enrollment)
+	}
+
+	enrollment.Failures++
+	if maxFailures := cmp.Or(tf.MaxFailures, 5); enrollment.Failures >= maxFailures {
+		lockout := cmp.Or(tf.Lockout, time.Minute)
+		enrollment.LockedUntil = now.Add(lockout << min(enrollment.Failures-maxFailures, 10))
+	}
+	if err = tf.Store.PutTOTP(userUID, enrollment); err != nil {
+		return err
+	}
+	return amp.ErrCode_AuthFailed.Error("invalid second factor code")
+}
+
+// accept returns true if the given code is a current TOTP code (or unused recovery code, if allowed), updating enrollment to consume it.
+func (tf *TwoFactor) accept(enrollment *TOTPEnrollment, code string, now time.Time, allowRecovery bool) bool {
+	cfg := tf.Config
+	cfg.setDefaults()
+
+	if len(code) == cfg.Digits {
+		step := now.Unix() / int64(cfg.Period/time.Second)
+		for i := -cfg.Skew; i <= cfg.Skew; i++ {
+			if step+int64(i) <= enrollment.LastStep {
+				continue // prevent replay
+			}
+			if subtle.ConstantTimeCompare([]byte(totpCode(enrollment.Secret, step+int64(i), cfg.Digits)), []byte(code)) == 1 {
+				enrollment.LastStep = step + int64(i)
+				enrollment.Confirmed = true
+				return true
+			}
+		}
+	} else if allowRecovery {
+		hash := hashRecoveryCode(code)
+		for i, hi := range enrollment.RecoveryCodes {
+			if subtle.ConstantTimeCompare(hi, hash) == 1 {
+				enrollment.RecoveryCodes = append(enrollment.RecoveryCodes[:i:i], enrollment.RecoveryCodes[i+1:]...)
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func hashRecoveryCode(code string) []byte {
+	normalized := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+	sum := sha256.Sum256([]byte(normalized))
+	return sum[:]
+}
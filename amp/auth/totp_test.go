@@ -0,0 +1,149 @@
+package auth
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/amp-3d/amp-sdk-go/amp"
+)
+
+func TestTOTP(t *testing.T) {
+
+	// RFC 6238 appendix B (SHA1)
+	secret := []byte("12345678901234567890")
+	cfg := TOTPConfig{Digits: 8}
+	for unix, expect := range map[int64]string{
+		59:         "94287082",
+		1111111109: "07081804",
+		2000000000: "69279037",
+	} {
+		if code := cfg.TOTPCode(secret, time.Unix(unix, 0)); code != expect {
+			t.Errorf("t=%d: got %s, expected %s", unix, code, expect)
+		}
+	}
+
+	tf := &TwoFactor{
+		Config:           TOTPConfig{Issuer: "Amp Host"},
+		Store:            NewMemoryTOTPStore(),
+		NumRecoveryCodes: 3,
+	}
+	userUID := "user-1"
+
+	uri, recoveryCodes, err := tf.Enroll(userUID, "alice@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Scheme != "otpauth" || parsed.Host != "totp" || parsed.Path != "/Amp Host:alice@example.com" || parsed.Query().Get("issuer") != "Amp Host" {
+		t.Fatalf("unexpected URI %q", uri)
+	}
+	secret, err = totpBase32.DecodeString(parsed.Query().Get("secret"))
+	if err != nil || len(secret) != 20 || len(recoveryCodes) != 3 {
+		t.Fatalf("unexpected enrollment: %v %v", err, recoveryCodes)
+	}
+
+	// Not required until confirmed
+	if required, _ := tf.Required(userUID); required {
+		t.Fatal("expected enrollment to be pending")
+	}
+	if err = tf.ConfirmEnrollment(userUID, "000000x"); err == nil {
+		t.Fatal("expected bad code to fail")
+	}
+	prevCode := tf.Config.TOTPCode(secret, time.Now().Add(-30*time.Second))
+	if err = tf.ConfirmEnrollment(userUID, prevCode); err != nil {
+		t.Fatal(err)
+	}
+	if required, _ := tf.Required(userUID); !required {
+		t.Fatal("expected second factor to be required")
+	}
+
+	// Codes can't be replayed
+	respond := func(code string) error {
+		return tf.VerifyResponse(userUID, &amp.LoginResponse{HashResponse: []byte(code)})
+	}
+	if err = respond(prevCode); err == nil {
+		t.Fatal("expected replayed code to fail")
+	}
+	nextCode := tf.Config.TOTPCode(secret, time.Now().Add(30*time.Second))
+	if err = respond(nextCode); err != nil {
+		t.Fatal(err)
+	}
+
+	// Recovery codes work once
+	if err = respond(recoveryCodes[1]); err != nil {
+		t.Fatal(err)
+	}
+	if err = respond(recoveryCodes[1]); err == nil {
+		t.Fatal("expected used recovery code to fail")
+	}
+	if err = respond("zzzz-zzzz-zzzz-zzzz"); err == nil {
+		t.Fatal("expected unknown recovery code to fail")
+	}
+
+	// A confirmed enrollment can't be replaced until disabled
+	if _, _, err = tf.Enroll(userUID, "mallory@example.com"); err == nil {
+		t.Fatal("expected re-enrollment to be refused")
+	}
+
+	if err = tf.Disable(userUID); err != nil {
+		t.Fatal(err)
+	}
+	if required, _ := tf.Required(userUID); required {
+		t.Fatal("expected second factor to be disabled")
+	}
+}
+
+func TestTOTPLockout(t *testing.T) {
+	tf := &TwoFactor{
+		Config:      TOTPConfig{Digits: 12}, // clamped to 8
+		Store:       NewMemoryTOTPStore(),
+		MaxFailures: 3,
+		Lockout:     100 * time.Millisecond,
+	}
+	userUID := "user-1"
+	uri, _, err := tf.Enroll(userUID, "alice@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, _ := url.Parse(uri)
+	secret, _ := totpBase32.DecodeString(parsed.Query().Get("secret"))
+	if parsed.Query().Get("digits") != "8" {
+		t.Fatalf("expected digits to be clamped, got %q", uri)
+	}
+
+	// Once MaxFailures codes in a row are refused, even a valid code is refused until the lockout ends
+	for i := 0; i < 3; i++ {
+		if err = tf.ConfirmEnrollment(userUID, "00000000"); err == nil || err.(*amp.Err).Code != amp.ErrCode_AuthFailed {
+			t.Fatalf("expected bad code to fail, got %v", err)
+		}
+	}
+	code := tf.Config.TOTPCode(secret, time.Now())
+	if err = tf.ConfirmEnrollment(userUID, code); err == nil || err.(*amp.Err).Code != amp.ErrCode_RateLimited || !amp.IsRetryable(err) {
+		t.Fatalf("expected lockout, got %v", err)
+	}
+	enrollment, _ := tf.Store.GetTOTP(userUID)
+	if enrollment.Failures != 3 || enrollment.LockedUntil.IsZero() {
+		t.Fatalf("expected failures to be persisted, got %+v", enrollment)
+	}
+
+	// Each further failure doubles the lockout, and an accepted code resets it
+	time.Sleep(110 * time.Millisecond)
+	if err = tf.ConfirmEnrollment(userUID, "00000000"); err == nil {
+		t.Fatal("expected bad code to fail")
+	}
+	enrollment, _ = tf.Store.GetTOTP(userUID)
+	if lockout := time.Until(enrollment.LockedUntil); lockout < 150*time.Millisecond {
+		t.Fatalf("expected the lockout to double, got %v", lockout)
+	}
+	time.Sleep(210 * time.Millisecond)
+	if err = tf.ConfirmEnrollment(userUID, code); err != nil {
+		t.Fatal(err)
+	}
+	if enrollment, _ = tf.Store.GetTOTP(userUID); enrollment.Failures != 0 || !enrollment.LockedUntil.IsZero() {
+		t.Fatalf("expected failures to be reset, got %+v", enrollment)
+	}
+}
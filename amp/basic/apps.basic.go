@@ -1,9 +1,11 @@
 package basic
 
 import (
+	"sync"
 	"time"
 
 	"github.com/amp-3d/amp-sdk-go/amp"
+	"github.com/amp-3d/amp-sdk-go/stdlib/generics"
 	"github.com/amp-3d/amp-sdk-go/stdlib/tag"
 	"github.com/amp-3d/amp-sdk-go/stdlib/task"
 )
@@ -53,6 +55,32 @@ func (cell *CellInfo[AppT]) GetLogLabel() string {
 	return "???"
 }
 
+// FieldValue implements amp.FieldSource for a request's PinFilter, offering the fields of this cell's Tab:
+// label, caption, about, created, modified, and tags (the URL of each Tab tag).
+//
+// A cell having other fields to filter on implements FieldValue itself, deferring to this for the above.
+func (cell *CellInfo[AppT]) FieldValue(name string) (any, bool) {
+	switch name {
+	case "label":
+		return cell.Tab.Label, true
+	case "caption":
+		return cell.Tab.Caption, true
+	case "about":
+		return cell.Tab.About, true
+	case "created":
+		return cell.Tab.CreatedAt, true
+	case "modified":
+		return cell.Tab.ModifiedAt, true
+	case "tags":
+		tags := make([]string, 0, len(cell.Tab.Tags))
+		for _, tag := range cell.Tab.Tags {
+			tags = append(tags, tag.URL)
+		}
+		return tags, true
+	}
+	return nil, false
+}
+
 func (cell *CellInfo[AppT]) MarshalAttrs(pin *Pin[AppT]) {
 	var attrID tag.ID
 	if cell.Pinned != nil {
@@ -65,6 +93,12 @@ func (cell *CellInfo[AppT]) MarshalAttrs(pin *Pin[AppT]) {
 
 func PinAndServe[AppT amp.AppInstance](target Cell[AppT], app AppT, op amp.Requester) (amp.Pin, error) {
 
+	// Prefetch work waits until this pin's first push (see PrefetchingCell)
+	served := func() {}
+	if pf := prefetcherOf(app); pf != nil {
+		served = pf.Serving()
+	}
+
 	cell := target.Info()
 	if cell.Pinned == nil {
 		if cell.ID.IsNil() {
@@ -72,44 +106,73 @@ func PinAndServe[AppT amp.AppInstance](target Cell[AppT], app AppT, op amp.Reque
 		}
 
 		cell.Pinned = &Pinned[AppT]{
-			App:      app,
-			Cell:     target,
-			children: make(map[tag.ID]Cell[AppT]),
+			App:  app,
+			Cell: target,
+			pins: make(map[*Pin[AppT]]struct{}),
 		}
 
 		err := target.PinInto(cell.Pinned)
 		if err != nil {
 			cell.Pinned = nil
+			served()
+			return nil, err
+		}
+	} else if cell.Pinned.jobFailed() {
+		if err := target.PinInto(cell.Pinned); err != nil {
+			served()
 			return nil, err
 		}
 	}
 
+	filter, err := amp.ParseFilter(op.Request().PinFilter)
+	if err != nil {
+		served()
+		return nil, err
+	}
+
 	pin := &Pin[AppT]{
-		Op:     op,
-		Pinned: cell.Pinned,
+		Op:          op,
+		Pinned:      cell.Pinned,
+		filter:      filter,
+		attrs:       op.Request().AttrSelection(),
+		invalidated: make(chan struct{}, 1),
+	}
+	if sess := app.Session(); sess != nil {
+		pin.locales = sess.Auth().Locales
 	}
 
-	var err error
 	pin.ctx, err = app.StartChild(&task.Task{
 		Label:     "pin: " + target.GetLogLabel(),
 		IdleClose: time.Microsecond,
 		OnRun: func(pinContext task.Context) {
+			maintain := op.Request().PinSync == amp.PinSync_Maintain
+			pin.Pinned.addPin(pin)
+			defer pin.Pinned.removePin(pin)
+
+			// A maintained pin (or any pin while the cell's job runs) pushes its state again each time the pinned cell is invalidated
 			err := pin.pushTx()
-			if err != nil {
-				if err != amp.ErrShuttingDown {
-					pinContext.Warnf("op failed: %v", err)
+			served()
+			for err == nil && (maintain || pin.syncing) {
+				select {
+				case <-pin.invalidated:
+					err = pin.pushTx()
+				case <-pinContext.Closing():
+					maintain, pin.syncing = false, false
 				}
-			} else if op.Request().PinSync == amp.PinSync_Maintain {
-				<-pinContext.Closing()
+			}
+			if err != nil && err != amp.ErrShuttingDown {
+				pinContext.Warnf("op failed: %v", err)
 			}
 
 			op.OnComplete(err)
 		},
 		OnClosing: func() {
+			served()
 			target.ReleasePin()
 		},
 	})
 	if err != nil {
+		served()
 		return nil, err
 	}
 
@@ -121,6 +184,17 @@ func PinAndServe[AppT amp.AppInstance](target Cell[AppT], app AppT, op amp.Reque
 type App[AppT amp.AppInstance] struct {
 	amp.AppContext
 	Instance AppT
+
+	prefetchOnce sync.Once
+	prefetcher   *amp.Prefetcher
+}
+
+// Prefetcher returns the Prefetcher that runs the Prefetch work of this app's cells (see PrefetchingCell), created on first use.
+func (app *App[AppT]) Prefetcher() *amp.Prefetcher {
+	app.prefetchOnce.Do(func() {
+		app.prefetcher = amp.NewPrefetcher(app.AppContext, amp.PrefetchOpts{})
+	})
+	return app.prefetcher
 }
 
 func (app *App[AppT]) MakeReady(op amp.Requester) error {
@@ -135,9 +209,14 @@ func (app *App[AppT]) OnClosing() {
 }
 
 type Pinned[AppT amp.AppInstance] struct {
-	Cell     Cell[AppT]
-	App      AppT
-	children map[tag.ID]Cell[AppT]
+	Cell Cell[AppT]
+	App  AppT
+
+	mu       sync.Mutex
+	children generics.OrderedMap[tag.ID, Cell[AppT]] // in the order added
+	pins     map[*Pin[AppT]]struct{}                 // pins being served
+	epoch    uint64                                  // incremented on Invalidate()
+	job      *Job[AppT]                              // most recently started job (or nil)
 }
 
 /*
@@ -156,14 +235,62 @@ func (op *Pinned[AppT]) AddChild(sub Cell[AppT]) {
 	if cell.ID.IsNil() {
 		cell.ID = tag.New()
 	}
-	op.children[cell.ID] = sub
+	op.mu.Lock()
+	op.children.Set(cell.ID, sub)
+	op.mu.Unlock()
 }
 
 func (pin *Pinned[AppT]) GetCell(target tag.ID) Cell[AppT] {
 	if target == pin.Cell.Info().ID {
 		return pin.Cell
 	}
-	return pin.children[target]
+	pin.mu.Lock()
+	defer pin.mu.Unlock()
+	sub, _ := pin.children.Get(target)
+	return sub
+}
+
+// Invalidate tells each maintained pin of this cell that its children have changed, causing each to push its state again.
+// Children pushed previously but no longer present are deleted -- for a PagedCell, each pin's window is enumerated again.
+func (pin *Pinned[AppT]) Invalidate() {
+	pin.mu.Lock()
+	defer pin.mu.Unlock()
+
+	pin.epoch++
+	if _, paged := pin.Cell.(PagedCell[AppT]); paged {
+		pin.children.Clear() // repopulated as windows are pushed
+	}
+	for p := range pin.pins {
+		select {
+		case p.invalidated <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (pin *Pinned[AppT]) addPin(p *Pin[AppT]) {
+	pin.mu.Lock()
+	pin.pins[p] = struct{}{}
+	pin.mu.Unlock()
+}
+
+// removePin stops serving the given pin, cancelling the cell's job if no pins remain.
+func (pin *Pinned[AppT]) removePin(p *Pin[AppT]) {
+	pin.mu.Lock()
+	delete(pin.pins, p)
+	job := pin.job
+	idle := len(pin.pins) == 0
+	pin.mu.Unlock()
+	if idle && job != nil {
+		job.cancel()
+	}
+}
+
+func (pin *Pinned[AppT]) childList() []Cell[AppT] {
+	pin.mu.Lock()
+	defer pin.mu.Unlock()
+
+	return pin.children.Values()
 }
 
 type Pin[AppT amp.AppInstance] struct {
@@ -171,14 +298,22 @@ type Pin[AppT amp.AppInstance] struct {
 	Op     amp.Requester
 	Tx     *amp.TxMsg
 
-	err error
-	ctx task.Context
+	err         error
+	ctx         task.Context
+	filter      *amp.Filter         // from the request's PinFilter (or nil)
+	attrs       amp.AttrSelection   // from the request's PinAttrs (or nil)
+	locales     []string            // from the session's Login.Locales (or nil)
+	invalidated chan struct{}       // signaled by Pinned.Invalidate()
+	pushed      map[tag.ID]struct{} // children pushed by the last pushTx()
+	syncing     bool                // set if the last pushTx() was during the cell's job
+	prefetched  bool                // set once the children hinted by the request's Prefetch are queued
 }
 
 func (pin *Pin[AppT]) Context() task.Context {
 	return pin.ctx
 }
 
+// Upsert marshals the given attr value into the tx being pushed, localized for the session's locales (see amp.Localize).
 func (pin *Pin[AppT]) Upsert(targetID, attrID, SI tag.ID, val amp.ElemVal) {
 	txOp := amp.TxOp{
 		OpCode:   amp.TxOpCode_UpsertAttr,
@@ -186,30 +321,77 @@ func (pin *Pin[AppT]) Upsert(targetID, attrID, SI tag.ID, val amp.ElemVal) {
 		AttrID:   attrID,
 		SI:       SI,
 	}
-	if pin.err != nil {
+	if pin.err != nil || !pin.attrs.Selects(&txOp) {
 		return
 	}
-	pin.err = pin.Tx.MarshalOp(&txOp, val)
+	pin.err = pin.Tx.MarshalOp(&txOp, amp.Localize(val, pin.locales))
 }
 
 func (pin *Pin[AppT]) pushTx() error {
+	pin.Pinned.mu.Lock()
+	job := pin.Pinned.job
+	pin.Pinned.mu.Unlock()
+
+	var progress *amp.Progress
+	pin.syncing = false
+	if job != nil {
+		var err error
+		if progress, pin.syncing, err = job.state(); err != nil {
+			return err
+		}
+	}
+
 	pin.Tx = amp.NewTxMsg(true)
 	pin.Pinned.Cell.MarshalAttrs(pin)
+	if progress != nil {
+		pin.Upsert(pin.Pinned.Cell.Info().ID, amp.ProgressSpec.ID, tag.Nil, progress)
+	}
 	if pin.err != nil {
 		return pin.err
 	}
 
-	for _, sub := range pin.Pinned.children {
+	var children []Cell[AppT]
+	if paged, isPaged := pin.Pinned.Cell.(PagedCell[AppT]); isPaged {
+		children, pin.err = pin.marshalWindow(paged)
+	} else {
+		children = pin.Pinned.childList()
+	}
+	if pin.err != nil {
+		return pin.err
+	}
+	if pin.filter != nil {
+		children = filterChildren(pin.filter, children)
+	}
+
+	pushed := make(map[tag.ID]struct{}, len(children))
+	for _, sub := range children {
 		sub.MarshalAttrs(pin)
 		if pin.err != nil {
 			return pin.err
 		}
+		pushed[sub.Info().ID] = struct{}{}
 	}
+	for cellID := range pin.pushed {
+		if _, exists := pushed[cellID]; !exists {
+			pin.Tx.MarshalOpWithBuf(&amp.TxOp{
+				OpCode:   amp.TxOpCode_DeleteCell,
+				TargetID: cellID,
+			}, nil)
+		}
+	}
+	pin.pushed = pushed
 
 	tx := pin.Tx
 	tx.Status = amp.OpStatus_Synced
+	if pin.syncing {
+		tx.Status = amp.OpStatus_Syncing
+	}
 	pin.Tx = nil
-	return pin.Op.PushTx(tx)
+	if err := pin.Op.PushTx(tx); err != nil {
+		return err
+	}
+	pin.queuePrefetch(children)
+	return nil
 }
 
 // func (pin *Pinned[AppT]) ProcessTx(tx *TxMsg) error {
@@ -224,6 +406,31 @@ func (pin *Pin[AppT]) pushTx() error {
 // 	// override for cleanup
 // }
 
+// cellFields is a Cell as an amp.FieldSource.
+type cellFields[AppT amp.AppInstance] struct {
+	Cell[AppT]
+}
+
+func (sub cellFields[AppT]) FieldValue(name string) (any, bool) {
+	if src, ok := sub.Cell.(amp.FieldSource); ok {
+		return src.FieldValue(name)
+	}
+	return sub.Info().FieldValue(name)
+}
+
+func filterChildren[AppT amp.AppInstance](filter *amp.Filter, children []Cell[AppT]) []Cell[AppT] {
+	fields := make([]cellFields[AppT], len(children))
+	for i, sub := range children {
+		fields[i] = cellFields[AppT]{sub}
+	}
+	fields = amp.ApplyFilter(filter, fields)
+	filtered := make([]Cell[AppT], len(fields))
+	for i, sub := range fields {
+		filtered[i] = sub.Cell
+	}
+	return filtered
+}
+
 func (pin *Pin[AppT]) ServeRequest(op amp.Requester) (amp.Pin, error) {
 	req := op.Request()
 	cell := pin.Pinned.GetCell(req.TargetID())
@@ -0,0 +1,144 @@
+package basic
+
+import (
+	"sync"
+
+	"github.com/amp-3d/amp-sdk-go/amp"
+	"github.com/amp-3d/amp-sdk-go/stdlib/task"
+)
+
+// Job is slow work populating a pinned cell (e.g. a library scan or a remote search), started by the cell's PinInto() via
+// Pinned.StartJob so that pins of the cell are served as the work proceeds rather than once it is done.
+//
+// While a job runs, each push of the cell has OpStatus_Syncing and holds the job's latest progress as the cell's
+// amp.ProgressSpec attr, along with the children added so far (partial results) -- a pin pushes again each time the job
+// reports progress.  Once the job is done, the cell is pushed with OpStatus_Synced, or if the job failed (or was cancelled),
+// each pin of the cell completes with its error -- and a later pin of the cell calls its PinInto() again to retry.
+//
+// If every pin of the cell closes while the job runs (e.g. each client cancelled its request), the job's Context closes,
+// so work checking Context().Closing() can stop early.
+type Job[AppT amp.AppInstance] struct {
+	pinned *Pinned[AppT]
+	ctx    task.Context
+
+	mu        sync.Mutex
+	progress  amp.Progress
+	done      bool
+	cancelled bool
+	err       error
+}
+
+// StartJob starts the given work as a Job of this cell, returning once it is started.  A cell runs one job at a time.
+func (pin *Pinned[AppT]) StartJob(label string, work func(job *Job[AppT]) error) (*Job[AppT], error) {
+	job := &Job[AppT]{
+		pinned: pin,
+	}
+
+	pin.mu.Lock()
+	if prev := pin.job; prev != nil && !prev.isDone() {
+		pin.mu.Unlock()
+		return nil, amp.ErrCode_BadRequest.Errorf("job already running for %q", pin.Cell.GetLogLabel())
+	}
+	pin.job = job
+	pin.mu.Unlock()
+
+	_, err := pin.App.StartChild(&task.Task{
+		Label: "job: " + label,
+		OnStart: func(ctx task.Context) error {
+			job.mu.Lock()
+			job.ctx = ctx
+			job.mu.Unlock()
+			return nil
+		},
+		OnRun: func(ctx task.Context) {
+			job.finish(work(job))
+		},
+	})
+	if err != nil {
+		pin.mu.Lock()
+		pin.job = nil
+		pin.mu.Unlock()
+		return nil, err
+	}
+	return job, nil
+}
+
+// Context returns the Context of this job, which closes when the work is done or cancelled.
+func (job *Job[AppT]) Context() task.Context {
+	job.mu.Lock()
+	defer job.mu.Unlock()
+	return job.ctx
+}
+
+// Report sets the progress of this job, where stage labels the current stage of the work and done of total items of work
+// are complete (total is 0 if not known), pushing it to each pin of the cell.  It may be called from any goroutine.
+func (job *Job[AppT]) Report(stage string, done, total int64) {
+	job.mu.Lock()
+	job.progress = amp.Progress{
+		Stage: stage,
+		Done:  done,
+		Total: total,
+	}
+	if total > 0 {
+		job.progress.Fraction = float32(min(max(float64(done)/float64(total), 0), 1))
+	}
+	job.mu.Unlock()
+	job.pinned.Invalidate()
+}
+
+func (job *Job[AppT]) finish(err error) {
+	job.mu.Lock()
+	if err == nil && job.cancelled {
+		err = amp.ErrCode_RequestClosed.Error("job cancelled")
+	}
+	job.done = true
+	job.err = err
+	job.progress.Fraction = 1
+	if job.progress.Total > 0 {
+		job.progress.Done = job.progress.Total
+	}
+	job.mu.Unlock()
+	job.pinned.Invalidate()
+}
+
+// jobFailed returns true if this cell's most recent job failed.
+func (pin *Pinned[AppT]) jobFailed() bool {
+	pin.mu.Lock()
+	job := pin.job
+	pin.mu.Unlock()
+	if job == nil {
+		return false
+	}
+	job.mu.Lock()
+	defer job.mu.Unlock()
+	return job.done && job.err != nil
+}
+
+func (job *Job[AppT]) isDone() bool {
+	job.mu.Lock()
+	defer job.mu.Unlock()
+	return job.done
+}
+
+// state returns the progress of this job for a push and whether it is still running, or the error it failed with.
+func (job *Job[AppT]) state() (progress *amp.Progress, running bool, err error) {
+	job.mu.Lock()
+	defer job.mu.Unlock()
+	snapshot := job.progress
+	return &snapshot, !job.done, job.err
+}
+
+// cancel closes this job's Context if the job is still running.
+func (job *Job[AppT]) cancel() {
+	job.mu.Lock()
+	ctx := job.ctx
+	if job.done {
+		ctx = nil
+	} else {
+		job.cancelled = true
+	}
+	job.mu.Unlock()
+	if ctx != nil {
+		ctx.Close()
+	}
+}
@@ -0,0 +1,164 @@
+package basic_test
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/amp-3d/amp-sdk-go/amp"
+	"github.com/amp-3d/amp-sdk-go/amp/amptest"
+	"github.com/amp-3d/amp-sdk-go/amp/basic"
+	"github.com/amp-3d/amp-sdk-go/stdlib/tag"
+)
+
+var jobApp = &amp.App{
+	AppSpec:     tag.FormSpec(amp.AppSpec, "test.basic.jobs"),
+	Invocations: []string{"testjobs"},
+	NewAppInstance: func(ctx amp.AppContext) (amp.AppInstance, error) {
+		app := &jobInst{}
+		app.AppContext = ctx
+		app.Instance = app
+		app.scan = &scanCell{
+			steps:     make(chan error),
+			cancelled: make(chan struct{}, 1),
+		}
+		lastScan = app.scan
+		return app, nil
+	},
+}
+
+// lastScan is the scan cell of the most recently started app instance
+var lastScan *scanCell
+
+type jobInst struct {
+	basic.App[*jobInst]
+	scan *scanCell
+}
+
+func (app *jobInst) ServeRequest(req amp.Requester) (amp.Pin, error) {
+	return app.PinAndServe(app.scan, req)
+}
+
+// scanCell adds a child for each step sent to it, failing if the step is an error
+type scanCell struct {
+	basic.CellInfo[*jobInst]
+
+	runs      int
+	steps     chan error
+	cancelled chan struct{} // signaled when a job's Context closes
+}
+
+type scanItem struct {
+	basic.CellInfo[*jobInst]
+}
+
+func (item *scanItem) PinInto(dst *basic.Pinned[*jobInst]) error {
+	return nil
+}
+
+func (scan *scanCell) PinInto(dst *basic.Pinned[*jobInst]) error {
+	scan.runs++
+	_, err := dst.StartJob("scan", func(job *basic.Job[*jobInst]) error {
+		const total = 3
+		job.Report("scanning", 0, total)
+		for i := 0; i < total; i++ {
+			select {
+			case err := <-scan.steps:
+				if err != nil {
+					return err
+				}
+			case <-job.Context().Closing():
+				scan.cancelled <- struct{}{}
+				return nil
+			}
+			item := &scanItem{}
+			item.Tab.Label = strconv.Itoa(i)
+			dst.AddChild(item)
+			job.Report("scanning", int64(i+1), total)
+		}
+		return nil
+	})
+	return err
+}
+
+// waitProgress waits for the given request to be pushed the given progress, returning the tx it was pushed in.
+func waitProgress(t *testing.T, req *amptest.Request, done int64) *amp.TxMsg {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		for _, tx := range req.Txs() {
+			for i, op := range tx.Ops {
+				var progress amp.Progress
+				if op.AttrID == amp.ProgressSpec.ID && tx.UnmarshalOpValue(i, &progress) == nil && progress.Done == done {
+					return tx
+				}
+			}
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for progress %d", done)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestJob(t *testing.T) {
+	sess := amptest.NewSession(t, jobApp)
+
+	// Each push while the job runs is syncing, holding its progress and the children added so far
+	req := sess.PinURL("testjobs://scan")
+	req.WaitForStatus(amp.OpStatus_Syncing)
+	scan := lastScan
+	scan.steps <- nil
+	scan.steps <- nil
+	tx := waitProgress(t, req, 2)
+	if tx.Status != amp.OpStatus_Syncing || len(req.Cells()) != 3 {
+		t.Fatalf("unexpected push %v of %d cells", tx.Status, len(req.Cells()))
+	}
+	scan.steps <- nil
+	req.RequireComplete()
+	txs := req.Txs()
+	var progress amp.Progress
+	req.RequireAttr(req.Cells()[0], amp.ProgressSpec.ID, &progress)
+	if last := txs[len(txs)-1]; last.Status != amp.OpStatus_Synced || len(req.Cells()) != 4 || progress.Fraction != 1 || progress.Stage != "scanning" {
+		t.Fatalf("unexpected final push %v with progress %+v", last.Status, progress)
+	}
+
+	// A pin after the job is done is pushed its results without running it again
+	sess.PinURL("testjobs://scan").RequireComplete()
+	if scan.runs != 1 {
+		t.Fatalf("expected 1 run, got %d", scan.runs)
+	}
+
+	// Closing the only pin of a running job cancels it
+	sess = amptest.NewSession(t, jobApp)
+	req = sess.PinURL("testjobs://scan")
+	req.WaitForStatus(amp.OpStatus_Syncing)
+	scan = lastScan
+	req.Close()
+	req.Wait()
+	select {
+	case <-scan.cancelled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("job was not cancelled")
+	}
+
+	// A failed job fails its pins, and a later pin runs it again
+	sess = amptest.NewSession(t, jobApp)
+	req = sess.PinURL("testjobs://scan")
+	req.WaitForStatus(amp.OpStatus_Syncing)
+	scan = lastScan
+	scan.steps <- errors.New("disk on fire")
+	if err := req.Wait(); err == nil {
+		t.Fatal("expected job failure")
+	}
+	req = sess.PinURL("testjobs://scan")
+	req.WaitForStatus(amp.OpStatus_Syncing)
+	for i := 0; i < 3; i++ {
+		scan.steps <- nil
+	}
+	req.RequireComplete()
+	if scan.runs != 2 {
+		t.Fatalf("expected 2 runs, got %d", scan.runs)
+	}
+}
@@ -0,0 +1,103 @@
+package basic
+
+import (
+	"fmt"
+
+	"github.com/amp-3d/amp-sdk-go/amp"
+	"github.com/amp-3d/amp-sdk-go/stdlib/tag"
+)
+
+const (
+	DefaultPageLimit = 100  // window size used when a request's PinWindow has no limit
+	MaxPageLimit     = 1000 // larger windows are truncated to this size
+)
+
+// PagedCell is optionally implemented by a Cell having too many children to push at once.
+//
+// When a PagedCell is pinned, its children are not added via PinInto(); instead, only the window of children
+// a request specifies (see amp.PinRequest.PinWindow) is enumerated and pushed, along with an amp.PageInfo attr
+// on the pinned cell describing that window.  When its children change, a PagedCell calls Pinned.Invalidate()
+// so that each maintained pin pushes its window again.  A request's PinFilter applies to each window as enumerated.
+type PagedCell[AppT amp.AppInstance] interface {
+	Cell[AppT]
+
+	// Returns the number of children (or -1 if not known).
+	ChildCount() int64
+
+	// Returns up to limit children starting at the given index (or fewer if there are no more).
+	ChildrenAt(offset, limit int64) ([]Cell[AppT], error)
+}
+
+// A cursor is the window offset and the epoch it was formed in -- a cursor from a prior epoch still resolves
+// to its offset, and the PageInfo pushed for it has the current epoch so the client can tell the window has moved.
+func formCursor(offset int64, epoch uint64) string {
+	return fmt.Sprintf("%x.%x", epoch, offset)
+}
+
+func parseCursor(cursor string) (offset int64, err error) {
+	var epoch uint64
+	if _, err = fmt.Sscanf(cursor, "%x.%x", &epoch, &offset); err != nil || offset < 0 {
+		return 0, amp.ErrCode_BadRequest.Errorf("invalid page cursor %q", cursor)
+	}
+	return offset, nil
+}
+
+// marshalWindow marshals the PageInfo of the window this pin's request specifies, returning the children in it.
+func (pin *Pin[AppT]) marshalWindow(paged PagedCell[AppT]) ([]Cell[AppT], error) {
+	pinned := pin.Pinned
+	pinned.mu.Lock()
+	epoch := pinned.epoch
+	pinned.mu.Unlock()
+
+	offset, limit := int64(0), int64(DefaultPageLimit)
+	if win := pin.Op.Request().PinWindow; win != nil {
+		offset = win.Offset
+		if win.Cursor != "" {
+			var err error
+			if offset, err = parseCursor(win.Cursor); err != nil {
+				return nil, err
+			}
+		}
+		if win.Limit > 0 {
+			limit = min(win.Limit, MaxPageLimit)
+		}
+	}
+	if offset < 0 {
+		return nil, amp.ErrCode_BadRequest.Errorf("invalid window offset %d", offset)
+	}
+
+	total := paged.ChildCount()
+	children, err := paged.ChildrenAt(offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(children)) > limit {
+		children = children[:limit]
+	}
+
+	info := &amp.PageInfo{
+		Total:  total,
+		Offset: offset,
+		Count:  int64(len(children)),
+		Epoch:  epoch,
+	}
+	if offset > 0 {
+		info.PrevCursor = formCursor(max(offset-limit, 0), epoch)
+	}
+	if next := offset + info.Count; next < total || (total < 0 && info.Count == limit) {
+		info.NextCursor = formCursor(next, epoch)
+	}
+	pin.Upsert(paged.Info().ID, amp.PageInfoSpec.ID, tag.Nil, info)
+
+	// Children in the window can be pinned in turn (see Pin.ServeRequest)
+	pinned.mu.Lock()
+	for _, sub := range children {
+		cell := sub.Info()
+		if cell.ID.IsNil() {
+			cell.ID = tag.New()
+		}
+		pinned.children.Set(cell.ID, sub)
+	}
+	pinned.mu.Unlock()
+	return children, nil
+}
@@ -0,0 +1,229 @@
+package basic_test
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/amp-3d/amp-sdk-go/amp"
+	"github.com/amp-3d/amp-sdk-go/amp/amptest"
+	"github.com/amp-3d/amp-sdk-go/amp/basic"
+	"github.com/amp-3d/amp-sdk-go/stdlib/tag"
+)
+
+var testApp = &amp.App{
+	AppSpec:     tag.FormSpec(amp.AppSpec, "test.basic"),
+	Invocations: []string{"testapp"},
+	NewAppInstance: func(ctx amp.AppContext) (amp.AppInstance, error) {
+		app := &appInst{}
+		app.AppContext = ctx
+		app.Instance = app
+		app.list = &listCell{}
+		lastList = app.list
+		for i := 0; i < 250; i++ {
+			item := &itemCell{}
+			item.Tab.Label = strconv.Itoa(i)
+			if i == 1 {
+				item.Tab.SetLocale(&amp.LocaleText{Locale: "pt", Label: "um"})
+			}
+			app.list.items = append(app.list.items, item)
+		}
+		return app, nil
+	},
+}
+
+// lastList is the list cell of the most recently started app instance
+var lastList *listCell
+
+type appInst struct {
+	basic.App[*appInst]
+	list *listCell
+}
+
+func (app *appInst) ServeRequest(req amp.Requester) (amp.Pin, error) {
+	return app.PinAndServe(app.list, req)
+}
+
+// listCell pins its items in pages
+type listCell struct {
+	basic.CellInfo[*appInst]
+
+	mu    sync.Mutex
+	items []*itemCell
+}
+
+type itemCell struct {
+	basic.CellInfo[*appInst]
+}
+
+func (item *itemCell) PinInto(dst *basic.Pinned[*appInst]) error {
+	return nil
+}
+
+func (item *itemCell) FieldValue(name string) (any, bool) {
+	if name == "n" {
+		n, _ := strconv.Atoi(item.Tab.Label)
+		return n, true
+	}
+	return item.CellInfo.FieldValue(name)
+}
+
+func (list *listCell) PinInto(dst *basic.Pinned[*appInst]) error {
+	return nil
+}
+
+func (list *listCell) ChildCount() int64 {
+	list.mu.Lock()
+	defer list.mu.Unlock()
+	return int64(len(list.items))
+}
+
+func (list *listCell) ChildrenAt(offset, limit int64) ([]basic.Cell[*appInst], error) {
+	list.mu.Lock()
+	defer list.mu.Unlock()
+
+	var children []basic.Cell[*appInst]
+	for i := offset; i < offset+limit && i < int64(len(list.items)); i++ {
+		children = append(children, list.items[i])
+	}
+	return children, nil
+}
+
+func (list *listCell) truncate(n int) {
+	list.mu.Lock()
+	list.items = list.items[:n]
+	list.mu.Unlock()
+	list.Pinned.Invalidate()
+}
+
+func pinWindow(sess *amptest.Session, pinSync amp.PinSync, win *amp.PinWindow) *amptest.Request {
+	return sess.Pin(amp.PinRequest{
+		PinTarget: &amp.Tag{URL: "testapp://list"},
+		PinSync:   pinSync,
+		PinWindow: win,
+	})
+}
+
+func pageInfo(t *testing.T, req *amptest.Request) *amp.PageInfo {
+	t.Helper()
+	info := &amp.PageInfo{}
+	req.RequireAttr(req.Cells()[0], amp.PageInfoSpec.ID, info)
+	return info
+}
+
+func TestPagedCell(t *testing.T) {
+	sess := amptest.NewSession(t, testApp)
+
+	// Only the requested window of children is pushed
+	req := pinWindow(sess, amp.PinSync_CloseOnSync, &amp.PinWindow{Limit: 10})
+	req.RequireComplete()
+	info := pageInfo(t, req)
+	if info.Total != 250 || info.Offset != 0 || info.Count != 10 || info.PrevCursor != "" || info.NextCursor == "" {
+		t.Fatalf("unexpected page info %+v", info)
+	}
+	if cells := req.Cells(); len(cells) != 11 {
+		t.Fatalf("expected 11 cells, got %d", len(cells))
+	}
+
+	// A cursor continues where the prior window ended
+	req = pinWindow(sess, amp.PinSync_CloseOnSync, &amp.PinWindow{Limit: 10, Cursor: info.NextCursor})
+	req.RequireComplete()
+	var tab amp.TagTab
+	req.RequireAttr(req.Cells()[1], amp.ChildTabSpec.ID, &tab)
+	if info = pageInfo(t, req); info.Offset != 10 || tab.Label != "10" || info.PrevCursor == "" {
+		t.Fatalf("unexpected page info %+v (first child %q)", info, tab.Label)
+	}
+
+	// No window means the default window size
+	req = pinWindow(sess, amp.PinSync_CloseOnSync, nil)
+	req.RequireComplete()
+	if info = pageInfo(t, req); info.Count != basic.DefaultPageLimit {
+		t.Fatalf("unexpected page info %+v", info)
+	}
+
+	// A mutation causes a maintained window to be pushed again, deleting children that have left it
+	req = pinWindow(sess, amp.PinSync_Maintain, &amp.PinWindow{Offset: 245, Limit: 10})
+	req.WaitForStatus(amp.OpStatus_Synced)
+	if info = pageInfo(t, req); info.Count != 5 || info.NextCursor != "" {
+		t.Fatalf("unexpected page info %+v", info)
+	}
+	lastList.truncate(247)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(req.Txs()) < 2 {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for window to be pushed again")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	deleted := 0
+	for _, op := range req.Txs()[1].Ops {
+		if op.OpCode == amp.TxOpCode_DeleteCell {
+			deleted++
+		}
+	}
+	if info = pageInfo(t, req); deleted != 3 || info.Total != 247 || info.Count != 2 || info.Epoch != 1 {
+		t.Fatalf("unexpected page info %+v after %d deletes", info, deleted)
+	}
+	req.Close()
+	req.RequireComplete()
+
+	// A malformed cursor is rejected
+	req = pinWindow(sess, amp.PinSync_CloseOnSync, &amp.PinWindow{Cursor: "nope"})
+	if err, _ := req.Wait().(*amp.Err); err == nil || err.Code != amp.ErrCode_BadRequest {
+		t.Fatalf("expected bad request, got %v", req.Wait())
+	}
+}
+
+func TestPinFilter(t *testing.T) {
+	sess := amptest.NewSession(t, testApp)
+
+	req := sess.Pin(amp.PinRequest{
+		PinTarget: &amp.Tag{URL: "testapp://list"},
+		PinWindow: &amp.PinWindow{Limit: 250},
+		PinFilter: `n >= 100 && n < 200 && label ~ "5" sort n desc limit 3`,
+	})
+	req.RequireComplete()
+	var labels []string
+	for _, cellID := range req.Cells()[1:] {
+		var tab amp.TagTab
+		req.RequireAttr(cellID, amp.ChildTabSpec.ID, &tab)
+		labels = append(labels, tab.Label)
+	}
+	if got := strings.Join(labels, ","); got != "195,185,175" {
+		t.Fatalf("unexpected children %q", got)
+	}
+
+	// A malformed filter rejects the request
+	if _, err := sess.TryPin(amp.PinRequest{PinTarget: &amp.Tag{URL: "testapp://list"}, PinFilter: "n >"}); err == nil {
+		t.Fatal("expected bad filter to be rejected")
+	}
+}
+
+func TestLocalize(t *testing.T) {
+	labelOf := func(sess *amptest.Session) *amp.TagTab {
+		req := sess.Pin(amp.PinRequest{
+			PinTarget: &amp.Tag{URL: "testapp://list"},
+			PinWindow: &amp.PinWindow{Limit: 2},
+		})
+		req.RequireComplete()
+		var tab amp.TagTab
+		req.RequireAttr(req.Cells()[2], amp.ChildTabSpec.ID, &tab)
+		return &tab
+	}
+
+	// Each tab is pushed in the session's best locale, without its other variants
+	sess := amptest.NewSession(t, testApp)
+	sess.Login.Locales = []string{"pt-BR", "en"}
+	if tab := labelOf(sess); tab.Label != "um" || len(tab.Locales) != 0 {
+		t.Fatalf("unexpected tab %+v", tab)
+	}
+
+	// A session declaring no locales is pushed every variant
+	sess = amptest.NewSession(t, testApp)
+	if tab := labelOf(sess); tab.Label != "1" || len(tab.Locales) != 1 {
+		t.Fatalf("unexpected tab %+v", tab)
+	}
+}
@@ -0,0 +1,48 @@
+package basic
+
+import (
+	"github.com/amp-3d/amp-sdk-go/amp"
+	"github.com/amp-3d/amp-sdk-go/stdlib/task"
+)
+
+// PrefetchingCell is optionally implemented by a Cell able to warm the state a client hints it will pin next (see
+// amp.PinRequest.Prefetch) -- e.g. loading its artwork from a slow store so that a later pin of it is served promptly.
+//
+// Once a pin whose request has a PrefetchHint first pushes its cell's children, Prefetch is queued for each of the first
+// PrefetchHint.Children children pushed that implements it, where attrs are the attrs hinted (or nil for all attrs).
+// Prefetch is run by the app's amp.Prefetcher (see App.Prefetcher), so it only starts while none of the app's pins are
+// awaiting their first push, and it may still run after the pin that hinted it closes.
+type PrefetchingCell interface {
+	Prefetch(ctx task.Context, attrs amp.AttrSelection) error
+}
+
+// prefetcherOf returns the Prefetcher of the given app instance, or nil if it has none (see App.Prefetcher).
+func prefetcherOf(app amp.AppInstance) *amp.Prefetcher {
+	if src, ok := app.(interface{ Prefetcher() *amp.Prefetcher }); ok {
+		return src.Prefetcher()
+	}
+	return nil
+}
+
+// queuePrefetch queues the Prefetch of the children hinted by this pin's request, given the children just pushed.
+// The hint is serviced once per pin, so the pushes of a maintained pin do not queue it again.
+func (pin *Pin[AppT]) queuePrefetch(children []Cell[AppT]) {
+	hint := pin.Op.Request().Prefetch
+	if pin.prefetched || hint == nil || hint.Children <= 0 {
+		return
+	}
+	pin.prefetched = true
+
+	pf := prefetcherOf(pin.Pinned.App)
+	if pf == nil {
+		return
+	}
+	attrs := hint.AttrSelection()
+	for _, sub := range children[:min(int64(len(children)), hint.Children)] {
+		if cell, ok := sub.(PrefetchingCell); ok {
+			pf.Queue(sub.Info().ID, func(ctx task.Context) error {
+				return cell.Prefetch(ctx, attrs)
+			})
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package basic_test
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/amp-3d/amp-sdk-go/amp"
+	"github.com/amp-3d/amp-sdk-go/amp/amptest"
+	"github.com/amp-3d/amp-sdk-go/stdlib/task"
+)
+
+type prefetchedItem struct {
+	label string
+	attrs amp.AttrSelection
+}
+
+// prefetched receives each itemCell prefetched
+var prefetched = make(chan prefetchedItem, 100)
+
+func (item *itemCell) Prefetch(ctx task.Context, attrs amp.AttrSelection) error {
+	prefetched <- prefetchedItem{item.Tab.Label, attrs}
+	return nil
+}
+
+func TestPrefetch(t *testing.T) {
+	sess := amptest.NewSession(t, testApp)
+
+	// The first children of the window pushed are prefetched for the attrs hinted
+	req := sess.Pin(amp.PinRequest{
+		PinTarget: &amp.Tag{URL: "testapp://list"},
+		PinWindow: &amp.PinWindow{Offset: 20, Limit: 10},
+		Prefetch: &amp.PrefetchHint{
+			Children: 3,
+			Attrs:    []*amp.Tag{{URL: amp.ArtworkSpec.Canonic}},
+		},
+	})
+	req.RequireComplete()
+
+	var labels []string
+	for len(labels) < 3 {
+		select {
+		case item := <-prefetched:
+			if _, selected := item.attrs[amp.ArtworkSpec.ID]; !selected || len(item.attrs) != 1 {
+				t.Fatalf("unexpected prefetch attrs %v", item.attrs)
+			}
+			labels = append(labels, item.label)
+		case <-time.After(time.Second):
+			t.Fatalf("expected 3 children prefetched, got %v", labels)
+		}
+	}
+	sort.Strings(labels)
+	if labels[0] != "20" || labels[1] != "21" || labels[2] != "22" {
+		t.Fatalf("unexpected children prefetched %v", labels)
+	}
+
+	// A pin without a hint prefetches nothing
+	sess.Pin(amp.PinRequest{
+		PinTarget: &amp.Tag{URL: "testapp://list"},
+		PinWindow: &amp.PinWindow{Limit: 10},
+	}).RequireComplete()
+	select {
+	case item := <-prefetched:
+		t.Fatalf("unexpected prefetch of %q", item.label)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
@@ -0,0 +1,456 @@
+package client
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/fnv"
+	"sort"
+	"sync"
+
+	"github.com/amp-3d/amp-sdk-go/amp"
+	"github.com/amp-3d/amp-sdk-go/stdlib/symbol"
+	"github.com/amp-3d/amp-sdk-go/stdlib/tag"
+)
+
+// Cache persists the cells a Client receives, keyed by cell tag ID, so they can be served while offline and on a cold start.
+//
+// Each cached cell has a version stamp: a hash of its cached attr values (see Stamp).  When a Client having a Cache issues a pin,
+// it first delivers the cells cached for that request (see Update.Cached).  Once the host's synced state arrives, the Client
+// delivers only the cells whose stamps differ from those cached, plus a DeleteCell op for each cached cell the host no longer
+// sends.  A pin re-issued after the host starts a new session is reconciled likewise, so a reconnect only delivers what changed.
+//
+// Updates after a pin has synced are written through to the cache, which is made durable each time a pin syncs and on Close.
+// A Cache may be shared by Clients (e.g. one per host), and is safe for concurrent use.
+type Cache struct {
+	reg amp.Registry
+
+	mu    sync.Mutex // protects the fields below
+	store symbol.Store
+	err   error // first error writing to store
+}
+
+// cache key prefixes
+const (
+	cacheKey_Attr  = byte('a') // cellID | attrID | SI -> serialized attr value
+	cacheKey_Stamp = byte('s') // cellID -> version stamp
+	cacheKey_Pin   = byte('p') // pin key -> IDs of the cells of the pin's synced state
+)
+
+// OpenCache opens (or creates) a Cache persisted to the given file (see symbol.OpenFileStore).
+// reg is used to decode cached attr values (if nil, amp's builtin types).
+func OpenCache(pathname string, reg amp.Registry) (*Cache, error) {
+	store, err := symbol.OpenFileStore(pathname)
+	if err != nil {
+		return nil, err
+	}
+	return NewCache(store, reg), nil
+}
+
+// NewCache returns a Cache backed by the given store, which is closed by Cache.Close.
+// reg is used to decode cached attr values (if nil, amp's builtin types).
+func NewCache(store symbol.Store, reg amp.Registry) *Cache {
+	if reg == nil {
+		reg = amp.NewRegistry()
+		amp.RegisterBuiltinTypes(reg)
+	}
+	return &Cache{
+		reg:   reg,
+		store: store,
+	}
+}
+
+// Close commits and closes the backing store.
+func (cache *Cache) Close() error {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	return cache.store.Close()
+}
+
+// Err returns the first error writing to the backing store, or nil.
+// Once an error occurs, the cache continues to serve what it holds but is no longer updated.
+func (cache *Cache) Err() error {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	return cache.err
+}
+
+// Attr returns the cached value of the given cell attr item, or false if it is not cached or has no registered prototype.
+func (cache *Cache) Attr(cellID, attrID, SI tag.ID) (amp.ElemVal, bool) {
+	cache.mu.Lock()
+	raw, _ := cache.store.Get(attrKeyBytes(cellID, attrID, SI))
+	cache.mu.Unlock()
+	if raw == nil {
+		return nil, false
+	}
+	val := cache.decode(attrID, raw)
+	return val, val != nil
+}
+
+// Stamp returns the version stamp of the given cell, or 0 if it is not cached.
+func (cache *Cache) Stamp(cellID tag.ID) uint64 {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	return cache.stamp(cellID)
+}
+
+// Pinned returns the cells cached for the given pin request as an Update of upserts (having Cached set), or false if the request
+// has not synced with this cache.  This allows an app to show a pin's cells before (or without) connecting to the host.
+func (cache *Cache) Pinned(pinReq *amp.PinRequest) (*Update, bool) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	cellIDs, synced := cache.pinCells(pinKey(pinReq))
+	if !synced {
+		return nil, false
+	}
+	update := &Update{
+		Status: amp.OpStatus_Synced,
+		Cached: true,
+	}
+	for _, cellID := range cellIDs {
+		for _, attr := range cache.cellAttrs(cellID) {
+			update.Ops = append(update.Ops, Op{
+				OpCode:   amp.TxOpCode_UpsertAttr,
+				TargetID: cellID,
+				AttrID:   attr.attrID,
+				SI:       attr.SI,
+				Value:    cache.decode(attr.attrID, attr.raw),
+				Raw:      attr.raw,
+			})
+		}
+	}
+	return update, true
+}
+
+// reconcile replaces the cached state of the given pin with its synced state (ops), returning the ops to deliver to a client that
+// was last given the cached state: the ops of cells that are new or whose stamps changed (plus DeleteAttr ops for attrs no longer
+// sent), followed by DeleteCell ops for cached cells no longer sent.
+func (cache *Cache) reconcile(key []byte, ops []Op) []Op {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	prevIDs, _ := cache.pinCells(key)
+	prev := make(map[tag.ID]struct{}, len(prevIDs))
+	for _, cellID := range prevIDs {
+		prev[cellID] = struct{}{}
+	}
+
+	// Gather the synced state of each cell, in the order cells were first sent.
+	var order []tag.ID
+	synced := make(map[tag.ID]*syncedCell)
+	var out []Op
+	for _, op := range ops {
+		switch op.OpCode {
+		case amp.TxOpCode_UpsertAttr, amp.TxOpCode_DeleteAttr:
+			cell := synced[op.TargetID]
+			if cell == nil {
+				cell = &syncedCell{
+					attrs: make(map[[2]tag.ID][]byte),
+				}
+				synced[op.TargetID] = cell
+				order = append(order, op.TargetID)
+			}
+			if op.OpCode == amp.TxOpCode_UpsertAttr {
+				cell.attrs[[2]tag.ID{op.AttrID, op.SI}] = op.Raw
+				cell.deleted = false
+			} else {
+				delete(cell.attrs, [2]tag.ID{op.AttrID, op.SI})
+			}
+			cell.ops = append(cell.ops, op)
+		case amp.TxOpCode_DeleteCell:
+			if cell := synced[op.TargetID]; cell != nil {
+				cell.deleted = true
+			}
+		default:
+			out = append(out, op)
+		}
+	}
+
+	cellIDs := make([]tag.ID, 0, len(order))
+	for _, cellID := range order {
+		cell := synced[cellID]
+		if cell.deleted {
+			delete(synced, cellID)
+			continue
+		}
+		cellIDs = append(cellIDs, cellID)
+
+		attrs := cell.sortedAttrs()
+		stamp := stampOf(attrs)
+		_, delivered := prev[cellID]
+		if delivered && stamp == cache.stamp(cellID) {
+			continue // unchanged since last delivered
+		}
+		out = append(out, cell.ops...)
+		if delivered {
+			for _, cached := range cache.cellAttrs(cellID) {
+				if _, exists := cell.attrs[[2]tag.ID{cached.attrID, cached.SI}]; !exists {
+					out = append(out, Op{
+						OpCode:   amp.TxOpCode_DeleteAttr,
+						TargetID: cellID,
+						AttrID:   cached.attrID,
+						SI:       cached.SI,
+					})
+				}
+			}
+		}
+		cache.putCell(cellID, attrs, stamp)
+	}
+
+	cache.setPinCells(key, cellIDs)
+	for _, cellID := range prevIDs {
+		if _, exists := synced[cellID]; !exists {
+			out = append(out, Op{
+				OpCode:   amp.TxOpCode_DeleteCell,
+				TargetID: cellID,
+			})
+			cache.dropCell(cellID)
+		}
+	}
+
+	if cache.err == nil {
+		cache.err = cache.store.Commit()
+	}
+	return out
+}
+
+// apply writes the given ops of a synced pin through to the cache.
+func (cache *Cache) apply(key []byte, ops []Op) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	cellIDs, _ := cache.pinCells(key)
+	members := make(map[tag.ID]struct{}, len(cellIDs))
+	for _, cellID := range cellIDs {
+		members[cellID] = struct{}{}
+	}
+	membersChanged := false
+
+	dirty := make(map[tag.ID]struct{})
+	for _, op := range ops {
+		switch op.OpCode {
+		case amp.TxOpCode_UpsertAttr:
+			cache.set(attrKeyBytes(op.TargetID, op.AttrID, op.SI), op.Raw)
+		case amp.TxOpCode_DeleteAttr:
+			cache.delete(attrKeyBytes(op.TargetID, op.AttrID, op.SI))
+		case amp.TxOpCode_DeleteCell:
+			if _, exists := members[op.TargetID]; exists {
+				delete(members, op.TargetID)
+				delete(dirty, op.TargetID)
+				cellIDs = removeID(cellIDs, op.TargetID)
+				cache.setPinCells(key, cellIDs)
+				cache.dropCell(op.TargetID) // after storing cellIDs, so this pin no longer references it
+				membersChanged = false
+			}
+			continue
+		default:
+			continue
+		}
+		dirty[op.TargetID] = struct{}{}
+		if _, exists := members[op.TargetID]; !exists {
+			members[op.TargetID] = struct{}{}
+			cellIDs = append(cellIDs, op.TargetID)
+			membersChanged = true
+		}
+	}
+	if membersChanged {
+		cache.setPinCells(key, cellIDs)
+	}
+	for cellID := range dirty {
+		cache.set(stampKeyBytes(cellID), binary.BigEndian.AppendUint64(nil, stampOf(cache.cellAttrs(cellID))))
+	}
+}
+
+// syncedCell is the state of a cell sent by the host while a pin syncs.
+type syncedCell struct {
+	attrs   map[[2]tag.ID][]byte // (attrID, SI) -> serialized value
+	ops     []Op
+	deleted bool
+}
+
+func (cell *syncedCell) sortedAttrs() []cachedAttr {
+	attrs := make([]cachedAttr, 0, len(cell.attrs))
+	for item, raw := range cell.attrs {
+		attrs = append(attrs, cachedAttr{item[0], item[1], raw})
+	}
+	sortAttrs(attrs)
+	return attrs
+}
+
+// cachedAttr is a cached attr item of a cell.
+type cachedAttr struct {
+	attrID, SI tag.ID
+	raw        []byte
+}
+
+// sortAttrs sorts the given attrs in the order they are stored.
+func sortAttrs(attrs []cachedAttr) {
+	sort.Slice(attrs, func(i, j int) bool {
+		return compareAttrs(&attrs[i], &attrs[j]) < 0
+	})
+}
+
+func compareAttrs(a, b *cachedAttr) int {
+	var ka, kb [48]byte
+	a.attrID.Put24(ka[:])
+	a.SI.Put24(ka[24:])
+	b.attrID.Put24(kb[:])
+	b.SI.Put24(kb[24:])
+	return bytes.Compare(ka[:], kb[:])
+}
+
+// stampOf returns the version stamp of a cell having the given attrs (in stored order).
+func stampOf(attrs []cachedAttr) uint64 {
+	h := fnv.New64a()
+	var buf [56]byte
+	for _, attr := range attrs {
+		attr.attrID.Put24(buf[:])
+		attr.SI.Put24(buf[24:])
+		binary.BigEndian.PutUint64(buf[48:], uint64(len(attr.raw)))
+		h.Write(buf[:])
+		h.Write(attr.raw)
+	}
+	if stamp := h.Sum64(); stamp != 0 {
+		return stamp
+	}
+	return 1 // 0 denotes a cell that is not cached
+}
+
+// stamp returns the stored stamp of the given cell -- cache.mu must be locked.
+func (cache *Cache) stamp(cellID tag.ID) uint64 {
+	buf, _ := cache.store.Get(stampKeyBytes(cellID))
+	if len(buf) != 8 {
+		return 0
+	}
+	return binary.BigEndian.Uint64(buf)
+}
+
+// cellAttrs returns the cached attrs of the given cell in stored order -- cache.mu must be locked.
+func (cache *Cache) cellAttrs(cellID tag.ID) []cachedAttr {
+	var attrs []cachedAttr
+	prefix := cellKeyBytes(cacheKey_Attr, cellID)
+	cache.store.Iterate(prefix, func(key, value []byte) error {
+		if len(key) == len(prefix)+48 {
+			attrs = append(attrs, cachedAttr{
+				attrID: tag.From24(key[len(prefix):]),
+				SI:     tag.From24(key[len(prefix)+24:]),
+				raw:    append([]byte(nil), value...),
+			})
+		}
+		return nil
+	})
+	return attrs
+}
+
+// putCell replaces the cached attrs of the given cell -- cache.mu must be locked.
+func (cache *Cache) putCell(cellID tag.ID, attrs []cachedAttr, stamp uint64) {
+	for _, prev := range cache.cellAttrs(cellID) {
+		cache.delete(attrKeyBytes(cellID, prev.attrID, prev.SI))
+	}
+	for _, attr := range attrs {
+		cache.set(attrKeyBytes(cellID, attr.attrID, attr.SI), attr.raw)
+	}
+	cache.set(stampKeyBytes(cellID), binary.BigEndian.AppendUint64(nil, stamp))
+}
+
+// dropCell removes the given cell from the cache unless another pin's synced state includes it -- cache.mu must be locked.
+func (cache *Cache) dropCell(cellID tag.ID) {
+	var id [24]byte
+	cellID.Put24(id[:])
+	referenced := false
+	cache.store.Iterate([]byte{cacheKey_Pin}, func(key, value []byte) error {
+		for i := 0; i+24 <= len(value) && !referenced; i += 24 {
+			referenced = bytes.Equal(value[i:i+24], id[:])
+		}
+		return nil
+	})
+	if referenced {
+		return
+	}
+	for _, prev := range cache.cellAttrs(cellID) {
+		cache.delete(attrKeyBytes(cellID, prev.attrID, prev.SI))
+	}
+	cache.delete(stampKeyBytes(cellID))
+}
+
+// pinCells returns the cells of the synced state of the given pin, or false if it has not synced -- cache.mu must be locked.
+func (cache *Cache) pinCells(key []byte) ([]tag.ID, bool) {
+	buf, _ := cache.store.Get(key)
+	if buf == nil {
+		return nil, false
+	}
+	cellIDs := make([]tag.ID, 0, len(buf)/24)
+	for i := 0; i+24 <= len(buf); i += 24 {
+		cellIDs = append(cellIDs, tag.From24(buf[i:]))
+	}
+	return cellIDs, true
+}
+
+// setPinCells stores the cells of the synced state of the given pin -- cache.mu must be locked.
+func (cache *Cache) setPinCells(key []byte, cellIDs []tag.ID) {
+	buf := make([]byte, 24*len(cellIDs))
+	for i, cellID := range cellIDs {
+		cellID.Put24(buf[24*i:])
+	}
+	cache.set(key, buf)
+}
+
+func (cache *Cache) set(key, value []byte) {
+	if cache.err == nil {
+		cache.err = cache.store.Set(key, value)
+	}
+}
+
+func (cache *Cache) delete(key []byte) {
+	if cache.err == nil {
+		cache.err = cache.store.Delete(key)
+	}
+}
+
+func (cache *Cache) decode(attrID tag.ID, raw []byte) amp.ElemVal {
+	val, err := cache.reg.NewAttrElem(attrID)
+	if err != nil || val.Unmarshal(raw) != nil {
+		return nil
+	}
+	return val
+}
+
+// pinKey returns the cache key of the state synced by the given request: two requests for the same target, attrs, window, and
+// filter share the same state.
+func pinKey(pinReq *amp.PinRequest) []byte {
+	sel := amp.PinRequest{
+		PinTarget: pinReq.PinTarget,
+		PinAttrs:  pinReq.PinAttrs,
+		PinWindow: pinReq.PinWindow,
+		PinFilter: pinReq.PinFilter,
+	}
+	buf, _ := sel.Marshal()
+	return append([]byte{cacheKey_Pin}, buf...)
+}
+
+func cellKeyBytes(prefix byte, cellID tag.ID) []byte {
+	key := make([]byte, 1+24, 1+72)
+	key[0] = prefix
+	cellID.Put24(key[1:])
+	return key
+}
+
+func attrKeyBytes(cellID, attrID, SI tag.ID) []byte {
+	key := cellKeyBytes(cacheKey_Attr, cellID)[:1+72]
+	attrID.Put24(key[25:])
+	SI.Put24(key[49:])
+	return key
+}
+
+func stampKeyBytes(cellID tag.ID) []byte {
+	return cellKeyBytes(cacheKey_Stamp, cellID)
+}
+
+func removeID(ids []tag.ID, id tag.ID) []tag.ID {
+	for i, existing := range ids {
+		if existing == id {
+			return append(ids[:i], ids[i+1:]...)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,598 @@
+// Package client connects Go programs to an amp host as a client, so that tools can pin cells and consume attr updates without
+// reimplementing tx framing and request bookkeeping.
+//
+// A Client logs in over a Transport (e.g. from ws.Dial or quic.Dial) and issues pin requests, delivering the txs the host pushes
+// for each as Updates having decoded attr values:
+//
+//	c, err := client.Dial(ctx, client.Opts{
+//		Dial:  func() (amp.Transport, error) { return ws.Dial(ctx, "wss://example.com/amp", ws.DialOpts{}) },
+//		Login: amp.Login{UserUID: "me"},
+//	})
+//	pin, err := c.PinURL("amp://library/tracks")
+//	for update := range pin.Updates() {
+//		...
+//	}
+//
+// The most recent value of each attr received is cached (see Client.Attr), and if Opts.Cache is set, received cells are also
+// persisted so a pin is served from the cache first and then reconciled with the host (see Cache).  If Opts.Reconnect is set, a dropped transport is
+// redialed and resumed (see amp.ReconnectingTransport).  If the host starts a new session instead, the Client logs in again and
+// re-issues its open pins.
+//
+// Login follows the steps in amp.Login: the client sends a Login meta attr, and the host responds with either an AuthCheckpoint
+// (success), a LoginChallenge (answered via Opts.OnChallenge), or an Err.
+package client
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/amp-3d/amp-sdk-go/amp"
+	"github.com/amp-3d/amp-sdk-go/stdlib/tag"
+)
+
+const (
+	DefaultLoginTimeout = 30 * time.Second
+	DefaultUpdateBuffer = 64
+)
+
+var (
+	loginChallengeSpec = tag.FormSpec(amp.MetaAttrSpec, (&amp.LoginChallenge{}).ElemTypeName())
+	checkpointSpec     = tag.FormSpec(amp.MetaAttrSpec, (&amp.AuthCheckpoint{}).ElemTypeName())
+	loginSpec          = tag.FormSpec(amp.MetaAttrSpec, (&amp.Login{}).ElemTypeName())
+	loginResponseSpec  = tag.FormSpec(amp.MetaAttrSpec, (&amp.LoginResponse{}).ElemTypeName())
+)
+
+// Opts configures a Client.
+type Opts struct {
+	Dial  func() (amp.Transport, error) // connects to the host
+	Login amp.Login                     // sent to the host on connect
+
+	// Called when the host challenges a login, returning the response to send (e.g. a TOTP code, see auth.TOTPChallenge).
+	// If nil, a login challenge fails Dial.
+	OnChallenge func(challenge *amp.LoginChallenge) (*amp.LoginResponse, error)
+
+	// If set, a dropped transport is redialed and the session resumed using these options (Dial is set from the above).
+	Reconnect *amp.ReconnectOpts
+
+	// If set, received cells are persisted to this cache, each pin first delivers the cells cached for its request, and the host's
+	// synced state is reconciled with the cache so only changed cells are delivered (see Cache).  The Client does not close it.
+	Cache *Cache
+
+	Registry     amp.Registry  // used to decode attr values (default: amp's builtin types)
+	LoginTimeout time.Duration // how long to wait for the host to accept a login (default DefaultLoginTimeout)
+	UpdateBuffer int           // Updates buffered per pin before the client stops reading from the host (default DefaultUpdateBuffer)
+
+	// If > 0, pins request this send window unless their PinRequest sets one (see amp.PinRequest.SendWindow), so the host pauses
+	// a pin that gets this far ahead of its Updates rather than queuing without bound.  A pin acknowledges txs once they are
+	// delivered to Updates (or held for reconciling with Opts.Cache), every quarter window.
+	SendWindow int64
+}
+
+// Client is a client session with an amp host -- concurrency safe.
+type Client struct {
+	opts   Opts
+	tr     amp.Transport
+	done   chan struct{} // closed once the client has stopped reading from the host
+	logins chan *amp.TxMsg
+
+	mu         sync.Mutex // protects the fields below
+	checkpoint *amp.AuthCheckpoint
+	pins       map[tag.ID]*Pin
+	cache      map[attrKey]amp.ElemVal
+	err        error // why the client stopped
+}
+
+type attrKey struct {
+	cellID, attrID, SI tag.ID
+}
+
+// Dial connects to the host and logs in, returning once the host has accepted the login.
+func Dial(ctx context.Context, opts Opts) (*Client, error) {
+	if opts.Registry == nil {
+		opts.Registry = amp.NewRegistry()
+		amp.RegisterBuiltinTypes(opts.Registry)
+	}
+	if opts.LoginTimeout <= 0 {
+		opts.LoginTimeout = DefaultLoginTimeout
+	}
+	if opts.UpdateBuffer <= 0 {
+		opts.UpdateBuffer = DefaultUpdateBuffer
+	}
+
+	var tr amp.Transport
+	var err error
+	if opts.Reconnect != nil {
+		reconnect := *opts.Reconnect
+		reconnect.Dial = opts.Dial
+		tr, err = amp.DialReconnecting(reconnect)
+	} else {
+		tr, err = opts.Dial()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Client{
+		opts:   opts,
+		tr:     tr,
+		done:   make(chan struct{}),
+		logins: make(chan *amp.TxMsg, 1),
+		pins:   make(map[tag.ID]*Pin),
+		cache:  make(map[attrKey]amp.ElemVal),
+	}
+	go c.readTxs()
+
+	if err = c.login(ctx); err != nil {
+		c.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// Checkpoint returns the AuthCheckpoint the host sent on the most recent login.
+func (c *Client) Checkpoint() *amp.AuthCheckpoint {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.checkpoint
+}
+
+// Close closes the transport and all open pins.
+func (c *Client) Close() error {
+	err := c.tr.Close()
+	<-c.done
+	return err
+}
+
+// Done is closed once the client has stopped, after which Err returns why.
+func (c *Client) Done() <-chan struct{} {
+	return c.done
+}
+
+// Err returns the error the client stopped with (amp.ErrStreamClosed after Close), or nil if still running.
+func (c *Client) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+// Attr returns the most recently received value of the given cell attr item, or false if none has been received.
+// If none has been received since Dial, the value in Opts.Cache (if set) is returned.
+// The returned ElemVal is shared and must be treated as read-only.
+func (c *Client) Attr(cellID, attrID, SI tag.ID) (amp.ElemVal, bool) {
+	c.mu.Lock()
+	val, exists := c.cache[attrKey{cellID, attrID, SI}]
+	c.mu.Unlock()
+	if !exists && c.opts.Cache != nil {
+		return c.opts.Cache.Attr(cellID, attrID, SI)
+	}
+	return val, exists
+}
+
+// PinURL is a convenience for PinCell with a request for the given URL that remains open (see amp.PinSync_Maintain).
+func (c *Client) PinURL(pinURL string) (*Pin, error) {
+	return c.PinCell(amp.PinRequest{
+		PinTarget: &amp.Tag{URL: pinURL},
+		PinSync:   amp.PinSync_Maintain,
+	})
+}
+
+// PinCell issues the given pin request, returning the Pin delivering the host's response.
+// If Opts.Cache is set and has cells for this request, they are delivered first (see Update.Cached).
+func (c *Client) PinCell(pinReq amp.PinRequest) (*Pin, error) {
+	return c.Commit(pinReq, nil)
+}
+
+// Commit is like PinCell except the request includes the given tx to be committed (see amp.Request.CommitTx).
+// commitTx is not retained.
+func (c *Client) Commit(pinReq amp.PinRequest, commitTx *amp.TxMsg) (*Pin, error) {
+	pin := &Pin{
+		client:  c,
+		ID:      tag.New(),
+		req:     pinReq,
+		updates: make(chan *Update, c.opts.UpdateBuffer),
+	}
+	if pin.req.SendWindow <= 0 {
+		pin.req.SendWindow = c.opts.SendWindow
+	}
+	tx, err := amp.MarshalPinRequest(pin.ID, &pin.req, commitTx)
+	if err != nil {
+		return nil, err
+	}
+	if cache := c.opts.Cache; cache != nil {
+		pin.cacheKey = pinKey(&pin.req)
+		pin.reconciling = true
+		if cached, exists := cache.Pinned(&pin.req); exists {
+			pin.updates <- cached
+		}
+	}
+
+	c.mu.Lock()
+	if c.err != nil {
+		c.mu.Unlock()
+		tx.ReleaseRef()
+		return nil, c.err
+	}
+	c.pins[pin.ID] = pin
+	c.mu.Unlock()
+
+	if err = c.tr.SendTx(tx); err != nil {
+		c.mu.Lock()
+		delete(c.pins, pin.ID)
+		c.mu.Unlock()
+		return nil, err
+	}
+	return pin, nil
+}
+
+// login sends the login and blocks until the host accepts or rejects it.
+func (c *Client) login(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, c.opts.LoginTimeout)
+	defer cancel()
+
+	login := c.opts.Login
+	c.mu.Lock()
+	if c.checkpoint != nil && login.Checkpoint == nil {
+		login.Checkpoint = c.checkpoint
+	}
+	c.mu.Unlock()
+	if err := c.sendMetaAttr(loginSpec.ID, &login); err != nil {
+		return err
+	}
+
+	for {
+		var tx *amp.TxMsg
+		select {
+		case tx = <-c.logins:
+		case <-c.done:
+			return c.Err()
+		case <-ctx.Done():
+			return amp.ErrCode_LoginFailed.Errorf("login: %v", ctx.Err())
+		}
+
+		switch tx.Ops[0].AttrID {
+		case checkpointSpec.ID:
+			checkpoint := &amp.AuthCheckpoint{}
+			err := tx.UnmarshalOpValue(0, checkpoint)
+			tx.ReleaseRef()
+			if err != nil {
+				return err
+			}
+			c.mu.Lock()
+			c.checkpoint = checkpoint
+			c.mu.Unlock()
+			return nil
+
+		case loginChallengeSpec.ID:
+			challenge := &amp.LoginChallenge{}
+			err := tx.UnmarshalOpValue(0, challenge)
+			tx.ReleaseRef()
+			if err != nil {
+				return err
+			}
+			if c.opts.OnChallenge == nil {
+				return amp.ErrCode_LoginFailed.Error("login: host sent a challenge")
+			}
+			resp, err := c.opts.OnChallenge(challenge)
+			if err != nil {
+				return err
+			}
+			if err = c.sendMetaAttr(loginResponseSpec.ID, resp); err != nil {
+				return err
+			}
+
+		default: // amp.ErrAttrSpec
+			loginErr := tx.CloseErr()
+			tx.ReleaseRef()
+			return loginErr
+		}
+	}
+}
+
+func (c *Client) sendMetaAttr(attrID tag.ID, val amp.ElemVal) error {
+	tx, err := amp.MarshalMetaAttr(attrID, val)
+	if err != nil {
+		return err
+	}
+	return c.tr.SendTx(tx)
+}
+
+// restore logs in again and re-issues open pins after the host started a new session.
+func (c *Client) restore() {
+	if err := c.login(context.Background()); err != nil {
+		c.stop(err)
+		return
+	}
+
+	c.mu.Lock()
+	pins := make([]*Pin, 0, len(c.pins))
+	for _, pin := range c.pins {
+		pins = append(pins, pin)
+	}
+	c.mu.Unlock()
+
+	for _, pin := range pins {
+		pin.mu.Lock()
+		pin.consumed, pin.acked = 0, 0 // the re-issued request has a new send window
+		if c.opts.Cache != nil {
+			pin.reconciling = true
+			pin.pending = nil
+		}
+		pin.mu.Unlock()
+		tx, err := amp.MarshalPinRequest(pin.ID, &pin.req, nil)
+		if err == nil {
+			err = c.tr.SendTx(tx)
+		}
+		if err != nil {
+			pin.complete(err)
+		}
+	}
+}
+
+func (c *Client) readTxs() {
+	defer close(c.done)
+	for {
+		tx, err := c.tr.RecvTx()
+		if err != nil {
+			if ampErr, ok := err.(*amp.Err); ok && ampErr.Code == amp.ErrCode_SessionExpired {
+				go c.restore()
+				continue
+			}
+			c.stop(err)
+			return
+		}
+		c.dispatch(tx)
+	}
+}
+
+func (c *Client) dispatch(tx *amp.TxMsg) {
+	reqID := tx.RequestID()
+	if len(tx.Ops) > 0 && tx.Ops[0].OpCode == amp.TxOpCode_MetaAttr {
+		switch tx.Ops[0].AttrID {
+		case checkpointSpec.ID, loginChallengeSpec.ID:
+			c.deliverLogin(tx)
+			return
+		case amp.ErrAttrSpec.ID:
+			if reqID.IsNil() {
+				c.deliverLogin(tx)
+				return
+			}
+		}
+	}
+
+	c.mu.Lock()
+	pin := c.pins[reqID]
+	c.mu.Unlock()
+	if pin == nil {
+		tx.ReleaseRef()
+		return
+	}
+
+	update := c.decode(tx)
+	pinErr := tx.CloseErr()
+	closed := tx.Status == amp.OpStatus_Closed || pinErr != nil
+	tx.ReleaseRef()
+
+	if pinErr == nil && c.opts.Cache != nil {
+		if update = pin.sync(update, closed); update == nil {
+			pin.consume()
+			return // held until the pin syncs
+		}
+		c.evict(update.Ops) // reconciling may delete attrs and cells the host no longer sends
+	}
+	if pinErr == nil && (len(update.Ops) > 0 || !closed) {
+		pin.deliver(update)
+	}
+	if closed {
+		pin.complete(pinErr)
+	} else {
+		pin.consume()
+	}
+}
+
+func (c *Client) deliverLogin(tx *amp.TxMsg) {
+	select {
+	case c.logins <- tx:
+	default:
+		tx.ReleaseRef() // no login in progress
+	}
+}
+
+// decode returns the Update for the given tx, updating the attr cache.
+func (c *Client) decode(tx *amp.TxMsg) *Update {
+	update := &Update{
+		Status: tx.Status,
+		Ops:    make([]Op, 0, len(tx.Ops)),
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, txOp := range tx.Ops {
+		op := Op{
+			OpCode:   txOp.OpCode,
+			FromID:   txOp.FromID,
+			TargetID: txOp.TargetID,
+			AttrID:   txOp.AttrID,
+			SI:       txOp.SI,
+			Height:   txOp.Height,
+			Hash:     txOp.Hash,
+		}
+		switch txOp.OpCode {
+		case amp.TxOpCode_MetaAttr:
+			continue
+		case amp.TxOpCode_UpsertAttr, amp.TxOpCode_SnapshotAttr:
+			op.OpCode = amp.TxOpCode_UpsertAttr
+			op.Raw = append([]byte(nil), tx.DataStore[txOp.DataOfs:txOp.DataOfs+txOp.DataLen]...)
+			if val, err := c.opts.Registry.NewAttrElem(txOp.AttrID); err == nil && val.Unmarshal(op.Raw) == nil {
+				op.Value = val
+				c.cache[attrKey{txOp.TargetID, txOp.AttrID, txOp.SI}] = val
+			}
+		case amp.TxOpCode_DeleteAttr, amp.TxOpCode_DeleteCell:
+			c.evictOp(&op)
+		}
+		update.Ops = append(update.Ops, op)
+	}
+	return update
+}
+
+// evict removes the attrs deleted by the given ops from the attr cache.
+func (c *Client) evict(ops []Op) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i := range ops {
+		c.evictOp(&ops[i])
+	}
+}
+
+// evictOp removes the attrs deleted by the given op from the attr cache -- c.mu must be locked.
+func (c *Client) evictOp(op *Op) {
+	switch op.OpCode {
+	case amp.TxOpCode_DeleteAttr:
+		delete(c.cache, attrKey{op.TargetID, op.AttrID, op.SI})
+	case amp.TxOpCode_DeleteCell:
+		for key := range c.cache {
+			if key.cellID == op.TargetID {
+				delete(c.cache, key)
+			}
+		}
+	}
+}
+
+// stop completes all pins with the given error.
+func (c *Client) stop(err error) {
+	c.mu.Lock()
+	if c.err == nil {
+		c.err = err
+	}
+	pins := c.pins
+	c.pins = make(map[tag.ID]*Pin)
+	c.mu.Unlock()
+
+	for _, pin := range pins {
+		pin.complete(err)
+	}
+}
+
+// Update is a tx pushed by the host for a pin, with attr values decoded.
+type Update struct {
+	Status amp.OpStatus
+	Ops    []Op
+	Cached bool // set if this update was served from Opts.Cache rather than pushed by the host
+}
+
+// Op is a decoded amp.TxOp.
+type Op struct {
+	OpCode   amp.TxOpCode // amp.TxOpCode_SnapshotAttr is delivered as amp.TxOpCode_UpsertAttr
+	FromID   tag.ID
+	TargetID tag.ID
+	AttrID   tag.ID
+	SI       tag.ID
+	Height   uint64 // the version of the attr item an upsert or delete writes, if versioned (see amp.AttrVersions)
+	Hash     uint64
+	Value    amp.ElemVal // the decoded value of an upsert, or nil if its attr has no registered prototype (see Opts.Registry)
+	Raw      []byte      // the serialized value of an upsert
+}
+
+// Pin is an open pin request issued by a Client.
+type Pin struct {
+	client  *Client
+	ID      tag.ID // request ID
+	req     amp.PinRequest
+	updates chan *Update
+
+	cacheKey []byte // key of this pin's state in Opts.Cache
+
+	mu          sync.Mutex
+	completed   bool
+	err         error
+	reconciling bool   // set until the host's synced state is reconciled with Opts.Cache
+	pending     []Op   // ops received while reconciling
+	consumed    uint64 // txs received for this request (see amp.FlowAckAttrSpec)
+	acked       uint64 // consumed as of the most recent ack sent
+}
+
+// Updates returns the channel delivering each tx the host pushes for this pin, closed once the pin completes (see Err).
+// If the channel is left full, the client stops reading from the host until it is drained.
+func (pin *Pin) Updates() <-chan *Update {
+	return pin.updates
+}
+
+// Err returns the error this pin completed with, or nil if it is open or was closed normally.
+func (pin *Pin) Err() error {
+	pin.mu.Lock()
+	defer pin.mu.Unlock()
+	return pin.err
+}
+
+// Close asks the host to close this pin.  Updates is closed once the host confirms.
+func (pin *Pin) Close() error {
+	tx := amp.NewTxMsg(true)
+	tx.SetRequestID(pin.ID)
+	tx.SetGenesisID(pin.ID)
+	tx.Status = amp.OpStatus_Closed
+	return pin.client.tr.SendTx(tx)
+}
+
+func (pin *Pin) deliver(update *Update) {
+	pin.mu.Lock()
+	defer pin.mu.Unlock()
+	if !pin.completed {
+		pin.updates <- update
+	}
+}
+
+// consume counts a tx received for this pin, acknowledging consumed txs to the host every quarter of its send window.
+func (pin *Pin) consume() {
+	window := uint64(pin.req.SendWindow)
+	if window == 0 {
+		return
+	}
+	pin.mu.Lock()
+	pin.consumed++
+	consumed := pin.consumed
+	ack := consumed-pin.acked >= max(1, window/4)
+	if ack {
+		pin.acked = consumed
+	}
+	pin.mu.Unlock()
+
+	if ack {
+		pin.client.tr.SendTx(amp.MarshalFlowAck(pin.ID, consumed))
+	}
+}
+
+// sync applies the given update to Opts.Cache, returning the update to deliver, or nil if it is held until the pin syncs.
+func (pin *Pin) sync(update *Update, closed bool) *Update {
+	cache := pin.client.opts.Cache
+	pin.mu.Lock()
+	defer pin.mu.Unlock()
+
+	if !pin.reconciling {
+		cache.apply(pin.cacheKey, update.Ops)
+		return update
+	}
+	pin.pending = append(pin.pending, update.Ops...)
+	if update.Status != amp.OpStatus_Synced && !closed {
+		return nil
+	}
+	update.Ops = cache.reconcile(pin.cacheKey, pin.pending)
+	pin.pending = nil
+	pin.reconciling = false
+	return update
+}
+
+func (pin *Pin) complete(err error) {
+	pin.client.mu.Lock()
+	delete(pin.client.pins, pin.ID)
+	pin.client.mu.Unlock()
+
+	pin.mu.Lock()
+	defer pin.mu.Unlock()
+	if pin.completed {
+		return
+	}
+	pin.completed = true
+	pin.err = err
+	close(pin.updates)
+}
@@ -0,0 +1,383 @@
+package client_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/amp-3d/amp-sdk-go/amp"
+	"github.com/amp-3d/amp-sdk-go/amp/amperr"
+	"github.com/amp-3d/amp-sdk-go/amp/client"
+	"github.com/amp-3d/amp-sdk-go/stdlib/tag"
+)
+
+// pipeTransport is one end of an in-memory Transport pair.
+type pipeTransport struct {
+	send      chan<- []byte
+	recv      <-chan []byte
+	closing   chan struct{}
+	closeOnce *sync.Once
+}
+
+func newPipe() (client, host *pipeTransport) {
+	toHost, toClient := make(chan []byte, 64), make(chan []byte, 64)
+	closing, once := make(chan struct{}), &sync.Once{}
+	client = &pipeTransport{send: toHost, recv: toClient, closing: closing, closeOnce: once}
+	host = &pipeTransport{send: toClient, recv: toHost, closing: closing, closeOnce: once}
+	return client, host
+}
+
+func (tr *pipeTransport) Label() string { return "pipe" }
+
+func (tr *pipeTransport) Close() error {
+	tr.closeOnce.Do(func() { close(tr.closing) })
+	return nil
+}
+
+func (tr *pipeTransport) SendTx(tx *amp.TxMsg) error {
+	var buf []byte
+	tx.MarshalToBuffer(&buf)
+	tx.ReleaseRef()
+	select {
+	case tr.send <- buf:
+		return nil
+	case <-tr.closing:
+		return amp.ErrStreamClosed
+	}
+}
+
+func (tr *pipeTransport) RecvTx() (*amp.TxMsg, error) {
+	select {
+	case buf := <-tr.recv:
+		return amp.ReadTxMsg(bytes.NewReader(buf))
+	case <-tr.closing:
+		return nil, amp.ErrStreamClosed
+	}
+}
+
+func sendMeta(tr amp.Transport, reqID tag.ID, val amp.ElemVal) {
+	tx, _ := amp.MarshalMetaAttr(tag.FormSpec(amp.MetaAttrSpec, val.ElemTypeName()).ID, val)
+	tx.SetRequestID(reqID)
+	tr.SendTx(tx)
+}
+
+// fakeHost challenges each login with a code, then serves each pin request with a cell whose label is the pin URL, followed by
+// a cell for each of its children.
+type fakeHost struct {
+	t        *testing.T
+	mu       sync.Mutex
+	conns    []*pipeTransport
+	pinned   []tag.ID
+	children map[tag.ID]string // child cell ID -> label
+	acks     []uint64          // txs consumed, as acknowledged by each flow ack received
+}
+
+func (host *fakeHost) dial() (amp.Transport, error) {
+	clientTr, hostTr := newPipe()
+	host.mu.Lock()
+	host.conns = append(host.conns, hostTr)
+	host.mu.Unlock()
+	go host.serve(hostTr)
+	return clientTr, nil
+}
+
+func (host *fakeHost) serve(tr *pipeTransport) {
+	for {
+		tx, err := tr.RecvTx()
+		if err != nil {
+			return
+		}
+		if len(tx.Ops) == 0 || tx.Ops[0].OpCode != amp.TxOpCode_MetaAttr {
+			continue
+		}
+		switch tx.Ops[0].AttrID {
+		case amp.ResumeAttrSpec.ID:
+			tr.SendTx(amp.MarshalResumeTx(tag.New(), nil)) // always starts a new session
+		case tag.FormSpec(amp.MetaAttrSpec, "Login").ID:
+			sendMeta(tr, tag.Nil, &amp.LoginChallenge{Hash: []byte("code?")})
+		case tag.FormSpec(amp.MetaAttrSpec, "LoginResponse").ID:
+			resp := &amp.LoginResponse{}
+			tx.UnmarshalOpValue(0, resp)
+			if string(resp.HashResponse) == "123456" {
+				sendMeta(tr, tag.Nil, &amp.AuthCheckpoint{Token: "token"})
+			} else {
+				sendMeta(tr, tag.Nil, &amp.Err{Code: amp.ErrCode_LoginFailed, Msg: "bad code"})
+			}
+		case amp.FlowAckAttrSpec.ID:
+			host.mu.Lock()
+			host.acks = append(host.acks, tx.Ops[0].Height)
+			host.mu.Unlock()
+		case amp.PinRequestSpec.ID:
+			pinReq, _, _ := amp.ParsePinRequest(tx)
+			reqID := tx.RequestID()
+			host.mu.Lock()
+			host.pinned = append(host.pinned, reqID)
+			host.mu.Unlock()
+
+			if pinReq.PinTarget.URL == "amp://fail" {
+				failTx, _ := amp.MarshalErrTx(reqID, amperr.New(amp.ErrCode_CellNotFound, "no such cell", "url", pinReq.PinTarget.URL))
+				tr.SendTx(failTx)
+				continue
+			}
+			if pinReq.PinTarget.URL == "amp://stream" {
+				for i := int64(0); i < pinReq.SendWindow; i++ {
+					update := amp.NewTxMsg(true)
+					update.SetRequestID(reqID)
+					update.MarshalUpsert(reqID, amp.PinnedTabSpec.ID, &amp.TagTab{Label: fmt.Sprint(i)})
+					tr.SendTx(update)
+				}
+				continue
+			}
+			reply := amp.NewTxMsg(true)
+			reply.SetRequestID(reqID)
+			reply.Status = amp.OpStatus_Synced
+			reply.MarshalUpsert(reqID, amp.PinnedTabSpec.ID, &amp.TagTab{Label: pinReq.PinTarget.URL})
+			host.mu.Lock()
+			for childID, label := range host.children {
+				reply.MarshalUpsert(childID, amp.ChildTabSpec.ID, &amp.TagTab{Label: label})
+			}
+			host.mu.Unlock()
+			tr.SendTx(reply)
+		}
+	}
+}
+
+func nextUpdate(t *testing.T, pin *client.Pin) *client.Update {
+	t.Helper()
+	select {
+	case update, ok := <-pin.Updates():
+		if !ok {
+			t.Fatalf("pin completed: %v", pin.Err())
+		}
+		return update
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for update")
+		return nil
+	}
+}
+
+func TestClient(t *testing.T) {
+	host := &fakeHost{t: t}
+	code := "000000"
+	opts := client.Opts{
+		Dial:  host.dial,
+		Login: amp.Login{UserUID: "tester"},
+		OnChallenge: func(challenge *amp.LoginChallenge) (*amp.LoginResponse, error) {
+			return &amp.LoginResponse{HashResponse: []byte(code)}, nil
+		},
+		Reconnect: &amp.ReconnectOpts{MinBackoff: time.Millisecond},
+	}
+
+	// A rejected login fails Dial
+	if _, err := client.Dial(context.Background(), opts); err == nil {
+		t.Fatal("expected login to fail")
+	}
+	code = "123456"
+	c, err := client.Dial(context.Background(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	if c.Checkpoint().Token != "token" {
+		t.Fatalf("unexpected checkpoint %v", c.Checkpoint())
+	}
+
+	// Updates carry decoded attr values, which are also cached
+	pin, err := c.PinURL("amp://home")
+	if err != nil {
+		t.Fatal(err)
+	}
+	update := nextUpdate(t, pin)
+	if update.Status != amp.OpStatus_Synced || len(update.Ops) != 1 {
+		t.Fatalf("unexpected update %+v", update)
+	}
+	if tab, ok := update.Ops[0].Value.(*amp.TagTab); !ok || tab.Label != "amp://home" {
+		t.Fatalf("unexpected value %v", update.Ops[0].Value)
+	}
+	if val, cached := c.Attr(pin.ID, amp.PinnedTabSpec.ID, tag.Nil); !cached || val.(*amp.TagTab).Label != "amp://home" {
+		t.Fatal("expected attr to be cached")
+	}
+
+	// A pin the host rejects completes with the host's error
+	failed, err := c.PinURL("amp://fail")
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case _, ok := <-failed.Updates():
+		if ok {
+			t.Fatal("expected no updates")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for pin to fail")
+	}
+	if url, _ := amperr.Detail(failed.Err(), "url"); !amperr.Is(failed.Err(), amp.ErrCode_CellNotFound) || url != "amp://fail" {
+		t.Fatalf("unexpected pin error %v", failed.Err())
+	}
+
+	// When the transport drops and the host starts a new session, the client logs in again and re-issues open pins
+	host.mu.Lock()
+	host.conns[len(host.conns)-1].Close()
+	host.mu.Unlock()
+	update = nextUpdate(t, pin)
+	if tab, ok := update.Ops[0].Value.(*amp.TagTab); !ok || tab.Label != "amp://home" {
+		t.Fatalf("unexpected value after reconnect %v", update.Ops[0].Value)
+	}
+	host.mu.Lock()
+	pinned := host.pinned
+	host.mu.Unlock()
+	if len(pinned) != 3 || pinned[2] != pin.ID {
+		t.Fatalf("expected pin to be re-issued with its request ID, got %v", pinned)
+	}
+}
+
+func TestClientFlowAcks(t *testing.T) {
+	host := &fakeHost{t: t}
+	c, err := client.Dial(context.Background(), client.Opts{
+		Dial:  host.dial,
+		Login: amp.Login{UserUID: "tester"},
+		OnChallenge: func(challenge *amp.LoginChallenge) (*amp.LoginResponse, error) {
+			return &amp.LoginResponse{HashResponse: []byte("123456")}, nil
+		},
+		SendWindow: 8,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	// The host pushes a full window, which the client acknowledges every quarter window as it is delivered
+	pin, err := c.PinURL("amp://stream")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 8; i++ {
+		if label := nextUpdate(t, pin).Ops[0].Value.(*amp.TagTab).Label; label != fmt.Sprint(i) {
+			t.Fatalf("unexpected update %q", label)
+		}
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		host.mu.Lock()
+		acks := fmt.Sprint(host.acks)
+		host.mu.Unlock()
+		if acks == "[2 4 6 8]" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("unexpected acks %s", acks)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestCache(t *testing.T) {
+	pathname := filepath.Join(t.TempDir(), "cache")
+	one, two, three, four := tag.New(), tag.New(), tag.New(), tag.New()
+	host := &fakeHost{t: t, children: map[tag.ID]string{one: "one", two: "two", four: "four"}}
+	dial := func(cache *client.Cache) *client.Client {
+		c, err := client.Dial(context.Background(), client.Opts{
+			Dial:  host.dial,
+			Login: amp.Login{UserUID: "tester"},
+			OnChallenge: func(challenge *amp.LoginChallenge) (*amp.LoginResponse, error) {
+				return &amp.LoginResponse{HashResponse: []byte("123456")}, nil
+			},
+			Reconnect: &amp.ReconnectOpts{MinBackoff: time.Millisecond},
+			Cache:     cache,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+	labels := func(update *client.Update) map[tag.ID]string {
+		got := make(map[tag.ID]string)
+		for _, op := range update.Ops {
+			switch op.OpCode {
+			case amp.TxOpCode_UpsertAttr:
+				got[op.TargetID] = op.Value.(*amp.TagTab).Label
+			case amp.TxOpCode_DeleteCell:
+				got[op.TargetID] = "deleted"
+			}
+		}
+		return got
+	}
+
+	// A pin with nothing cached delivers the host's state, which is then cached
+	cache, err := client.OpenCache(pathname, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := dial(cache)
+	pin, err := c.PinURL("amp://home")
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := pin.ID
+	if update := nextUpdate(t, pin); update.Cached || len(update.Ops) != 4 {
+		t.Fatalf("unexpected update %+v", update)
+	}
+	c.Close()
+	if err = cache.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// On a cold start, the cache serves a pin's cells while offline and before the host responds
+	if cache, err = client.OpenCache(pathname, nil); err != nil {
+		t.Fatal(err)
+	}
+	defer cache.Close()
+	if val, cached := cache.Attr(two, amp.ChildTabSpec.ID, tag.Nil); !cached || val.(*amp.TagTab).Label != "two" {
+		t.Fatal("expected attr to be cached")
+	}
+	stamp := cache.Stamp(two)
+	if stamp == 0 || cache.Stamp(tag.New()) != 0 {
+		t.Fatal("unexpected stamps")
+	}
+
+	host.mu.Lock()
+	host.children = map[tag.ID]string{two: "TWO", three: "three", four: "four"}
+	host.mu.Unlock()
+	c = dial(cache)
+	defer c.Close()
+	if pin, err = c.PinURL("amp://home"); err != nil {
+		t.Fatal(err)
+	}
+	update := nextUpdate(t, pin)
+	if got := labels(update); !update.Cached || len(got) != 4 || got[first] != "amp://home" || got[one] != "one" {
+		t.Fatalf("unexpected cached update %v", got)
+	}
+
+	// Once synced, only cells whose stamps changed are delivered, plus deletes for cells the host no longer sends
+	update = nextUpdate(t, pin)
+	got := labels(update)
+	if update.Cached || update.Status != amp.OpStatus_Synced || len(got) != 5 {
+		t.Fatalf("unexpected synced update %v", got)
+	}
+	if got[pin.ID] != "amp://home" || got[two] != "TWO" || got[three] != "three" || got[first] != "deleted" || got[one] != "deleted" {
+		t.Fatalf("unexpected synced update %v", got)
+	}
+	if cache.Stamp(two) == stamp || cache.Stamp(one) != 0 {
+		t.Fatal("expected stamps to be updated")
+	}
+	if val, cached := c.Attr(two, amp.ChildTabSpec.ID, tag.Nil); !cached || val.(*amp.TagTab).Label != "TWO" {
+		t.Fatal("expected attr to be updated")
+	}
+
+	// A pin re-issued after a reconnect only delivers what changed meanwhile
+	host.mu.Lock()
+	delete(host.children, three)
+	host.conns[len(host.conns)-1].Close()
+	host.mu.Unlock()
+	update = nextUpdate(t, pin)
+	if got = labels(update); update.Status != amp.OpStatus_Synced || len(update.Ops) != 1 || got[three] != "deleted" {
+		t.Fatalf("unexpected update after reconnect %+v", update)
+	}
+	if cache.Err() != nil {
+		t.Fatal(cache.Err())
+	}
+}
@@ -0,0 +1,284 @@
+// Package cluster allows several host processes to serve the same users, sharing persisted state so that a session can land on
+// any node of the cluster.
+//
+// Nodes share state through a symbol.Store backed by a shared KV (e.g. a space's app stores, see amp.Space) and coordinate through
+// a Coordinator, which tracks the live members of the cluster and which node holds each session.  A session is keyed by a string the
+// host derives from the session (e.g. a ResumeTable token or a Login's DeviceUID) so that when a client reconnects to another node,
+// the host can redirect it to the node holding its suspended session (see Node.Route).  When a node leaves or stops heartbeating,
+// its sessions are claimed by whichever node the clients next land on.
+//
+// NewMemoryCoordinator coordinates nodes within a process (e.g. for tests), and package cluster/redis is a reference Coordinator and
+// shared Store backed by Redis.
+package cluster
+
+import (
+	"sync"
+	"time"
+
+	"github.com/amp-3d/amp-sdk-go/amp"
+	"github.com/amp-3d/amp-sdk-go/stdlib/symbol"
+)
+
+// DefaultTTL is the default for Opts.TTL.
+const DefaultTTL = 15 * time.Second
+
+// NodeInfo identifies a node of a cluster.
+type NodeInfo struct {
+	ID      string    `json:"id"`      // unique among the nodes of the cluster
+	Addr    string    `json:"addr"`    // address clients are redirected to (e.g. "host2.example.com:5192")
+	Started time.Time `json:"started"` // when the node joined
+}
+
+// Coordinator tracks the members of a cluster and the owners of claimed keys -- all methods must be safe for concurrent use.
+//
+// Membership and claims expire unless renewed within their TTL, so a node that fails without leaving is removed in due course.
+type Coordinator interface {
+
+	// Registers or renews the given node's membership for the given duration.
+	Heartbeat(node NodeInfo, ttl time.Duration) error
+
+	// Ends the given node's membership.
+	Leave(nodeID string) error
+
+	// Returns the nodes whose membership has not expired, in no particular order.
+	Members() ([]NodeInfo, error)
+
+	// Atomically claims the given key for owner for the given duration if it is unclaimed or already claimed by owner (renewing it).
+	// Returns the key's owner after the call, which is not owner if the key is held by another.
+	Claim(key, owner string, ttl time.Duration) (string, error)
+
+	// Releases the given key if it is claimed by owner.
+	Release(key, owner string) error
+
+	// Releases resources held by this Coordinator (e.g. connections).
+	Close() error
+}
+
+// Opts configures a Node.
+type Opts struct {
+	Node        NodeInfo      // this node (Started is set by Join if zero)
+	Coordinator Coordinator   // coordinates this node with the rest of the cluster
+	Store       symbol.Store  // state shared by all nodes of the cluster (optional)
+	TTL         time.Duration // how long this node's membership and session claims last unless renewed (default DefaultTTL)
+}
+
+// Node is a member of a cluster -- concurrency safe.
+type Node struct {
+	opts Opts
+
+	mu      sync.Mutex
+	held    map[string]struct{} // session keys claimed by this node
+	closing chan struct{}
+	done    chan struct{}
+}
+
+// Join makes this process a member of a cluster, renewing its membership and session claims every third of Opts.TTL until closed.
+func Join(opts Opts) (*Node, error) {
+	if opts.Coordinator == nil {
+		return nil, amp.ErrCode_BadValue.Error("cluster: no Coordinator given")
+	}
+	if opts.Node.ID == "" {
+		return nil, amp.ErrCode_BadValue.Error("cluster: no node ID given")
+	}
+	if opts.TTL <= 0 {
+		opts.TTL = DefaultTTL
+	}
+	if opts.Node.Started.IsZero() {
+		opts.Node.Started = time.Now()
+	}
+	if err := opts.Coordinator.Heartbeat(opts.Node, opts.TTL); err != nil {
+		return nil, err
+	}
+
+	n := &Node{
+		opts:    opts,
+		held:    make(map[string]struct{}),
+		closing: make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go n.heartbeat()
+	return n, nil
+}
+
+// Info returns this node's NodeInfo.
+func (n *Node) Info() NodeInfo {
+	return n.opts.Node
+}
+
+// Store returns the state shared by all nodes of the cluster (or nil if none was given).
+func (n *Node) Store() symbol.Store {
+	return n.opts.Store
+}
+
+// Members returns the live members of the cluster (including this node).
+func (n *Node) Members() ([]NodeInfo, error) {
+	return n.opts.Coordinator.Members()
+}
+
+// Route returns the node that is to serve the session with the given key.
+//
+// If the session is not held by another live node, it is claimed by this node (and held until Release), and local is returned true
+// so the host serves the session itself.  Otherwise the returned node holds the session and the host redirects the client to it.
+func (n *Node) Route(sessionKey string) (owner NodeInfo, local bool, err error) {
+	self := n.opts.Node.ID
+	ownerID, err := n.opts.Coordinator.Claim(sessionKey, self, n.opts.TTL)
+	if err != nil {
+		return NodeInfo{}, false, err
+	}
+	if ownerID == self {
+		n.mu.Lock()
+		n.held[sessionKey] = struct{}{}
+		n.mu.Unlock()
+		return n.opts.Node, true, nil
+	}
+
+	members, err := n.Members()
+	if err != nil {
+		return NodeInfo{}, false, err
+	}
+	for _, member := range members {
+		if member.ID == ownerID {
+			return member, false, nil
+		}
+	}
+	return NodeInfo{}, false, amp.ErrCode_NotConnected.Errorf("cluster: session %q is held by node %q, which is not a member", sessionKey, ownerID)
+}
+
+// Release releases this node's claim on the given session (e.g. once the session ends), allowing any node to claim it.
+func (n *Node) Release(sessionKey string) error {
+	n.mu.Lock()
+	delete(n.held, sessionKey)
+	n.mu.Unlock()
+	return n.opts.Coordinator.Release(sessionKey, n.opts.Node.ID)
+}
+
+// Close releases this node's session claims and leaves the cluster.  The Coordinator and Store are not closed.
+func (n *Node) Close() error {
+	n.mu.Lock()
+	select {
+	case <-n.closing:
+		n.mu.Unlock()
+		return nil
+	default:
+		close(n.closing)
+	}
+	held := n.held
+	n.held = make(map[string]struct{})
+	n.mu.Unlock()
+	<-n.done
+
+	var err error
+	for key := range held {
+		if relErr := n.opts.Coordinator.Release(key, n.opts.Node.ID); relErr != nil && err == nil {
+			err = relErr
+		}
+	}
+	if leaveErr := n.opts.Coordinator.Leave(n.opts.Node.ID); leaveErr != nil && err == nil {
+		err = leaveErr
+	}
+	return err
+}
+
+func (n *Node) heartbeat() {
+	defer close(n.done)
+
+	ticker := time.NewTicker(n.opts.TTL / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-n.closing:
+			return
+		case <-ticker.C:
+		}
+
+		// A failed renewal is retried on the next tick, which is still within the TTL.
+		n.opts.Coordinator.Heartbeat(n.opts.Node, n.opts.TTL)
+		n.mu.Lock()
+		held := make([]string, 0, len(n.held))
+		for key := range n.held {
+			held = append(held, key)
+		}
+		n.mu.Unlock()
+		for _, key := range held {
+			if owner, err := n.opts.Coordinator.Claim(key, n.opts.Node.ID, n.opts.TTL); err == nil && owner != n.opts.Node.ID {
+				n.mu.Lock()
+				delete(n.held, key)
+				n.mu.Unlock()
+			}
+		}
+	}
+}
+
+// NewMemoryCoordinator returns a Coordinator for nodes within a single process (e.g. for tests).
+func NewMemoryCoordinator() Coordinator {
+	return &memoryCoordinator{
+		members: make(map[string]memoryEntry[NodeInfo]),
+		claims:  make(map[string]memoryEntry[string]),
+	}
+}
+
+type memoryEntry[T any] struct {
+	val     T
+	expires time.Time
+}
+
+type memoryCoordinator struct {
+	mu      sync.Mutex
+	members map[string]memoryEntry[NodeInfo]
+	claims  map[string]memoryEntry[string]
+}
+
+func (mc *memoryCoordinator) Heartbeat(node NodeInfo, ttl time.Duration) error {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	mc.members[node.ID] = memoryEntry[NodeInfo]{node, time.Now().Add(ttl)}
+	return nil
+}
+
+func (mc *memoryCoordinator) Leave(nodeID string) error {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	delete(mc.members, nodeID)
+	return nil
+}
+
+func (mc *memoryCoordinator) Members() ([]NodeInfo, error) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	now := time.Now()
+	members := make([]NodeInfo, 0, len(mc.members))
+	for id, entry := range mc.members {
+		if now.After(entry.expires) {
+			delete(mc.members, id)
+			continue
+		}
+		members = append(members, entry.val)
+	}
+	return members, nil
+}
+
+func (mc *memoryCoordinator) Claim(key, owner string, ttl time.Duration) (string, error) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	now := time.Now()
+	if entry, exists := mc.claims[key]; exists && entry.val != owner && now.Before(entry.expires) {
+		return entry.val, nil
+	}
+	mc.claims[key] = memoryEntry[string]{owner, now.Add(ttl)}
+	return owner, nil
+}
+
+func (mc *memoryCoordinator) Release(key, owner string) error {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	if entry, exists := mc.claims[key]; exists && entry.val == owner {
+		delete(mc.claims, key)
+	}
+	return nil
+}
+
+func (mc *memoryCoordinator) Close() error {
+	return nil
+}
@@ -0,0 +1,93 @@
+package cluster_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/amp-3d/amp-sdk-go/amp/cluster"
+	"github.com/amp-3d/amp-sdk-go/stdlib/symbol"
+)
+
+func TestNode(t *testing.T) {
+	coord := cluster.NewMemoryCoordinator()
+	shared := symbol.NewMemoryStore()
+	join := func(id string) *cluster.Node {
+		node, err := cluster.Join(cluster.Opts{
+			Node:        cluster.NodeInfo{ID: id, Addr: id + ":5192"},
+			Coordinator: coord,
+			Store:       shared,
+			TTL:         150 * time.Millisecond,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return node
+	}
+	n1, n2 := join("n1"), join("n2")
+	defer n2.Close()
+
+	if members, _ := n2.Members(); len(members) != 2 {
+		t.Fatalf("expected 2 members, got %v", members)
+	}
+
+	// A session is served by the first node it lands on, and other nodes redirect to it
+	if owner, local, err := n1.Route("session-a"); err != nil || !local || owner.ID != "n1" {
+		t.Fatalf("expected n1 to claim the session, got %v %v (%v)", owner, local, err)
+	}
+	if owner, local, err := n2.Route("session-a"); err != nil || local || owner.Addr != "n1:5192" {
+		t.Fatalf("expected redirect to n1, got %v %v (%v)", owner, local, err)
+	}
+
+	// Claims are renewed by heartbeats, outliving their TTL
+	time.Sleep(300 * time.Millisecond)
+	if owner, local, _ := n2.Route("session-a"); local || owner.ID != "n1" {
+		t.Fatalf("expected n1 to keep the session, got %v", owner)
+	}
+
+	// Nodes share state through the cluster store
+	n1.Store().Set([]byte("cell"), []byte("value"))
+	if val, _ := n2.Store().Get([]byte("cell")); string(val) != "value" {
+		t.Fatalf("store not shared: %q", val)
+	}
+
+	// Once a node leaves, its sessions land on the next node routed to
+	if err := n1.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if members, _ := n2.Members(); len(members) != 1 || members[0].ID != "n2" {
+		t.Fatalf("expected n1 to have left, got %v", members)
+	}
+	if owner, local, err := n2.Route("session-a"); err != nil || !local || owner.ID != "n2" {
+		t.Fatalf("expected n2 to claim the session, got %v %v (%v)", owner, local, err)
+	}
+
+	// A released session may be claimed by any node
+	if err := n2.Release("session-a"); err != nil {
+		t.Fatal(err)
+	}
+	n3 := join("n3")
+	defer n3.Close()
+	if _, local, _ := n3.Route("session-a"); !local {
+		t.Fatal("expected n3 to claim the released session")
+	}
+}
+
+func TestMemoryCoordinatorExpiry(t *testing.T) {
+	coord := cluster.NewMemoryCoordinator()
+	coord.Heartbeat(cluster.NodeInfo{ID: "crashed"}, 20*time.Millisecond)
+	if owner, _ := coord.Claim("session", "crashed", 20*time.Millisecond); owner != "crashed" {
+		t.Fatalf("unexpected owner %q", owner)
+	}
+	if owner, _ := coord.Claim("session", "other", time.Second); owner != "crashed" {
+		t.Fatalf("expected claim to be held, got %q", owner)
+	}
+
+	// A node that stops heartbeating drops out of the cluster along with its claims
+	time.Sleep(40 * time.Millisecond)
+	if members, _ := coord.Members(); len(members) != 0 {
+		t.Fatalf("expected membership to expire, got %v", members)
+	}
+	if owner, _ := coord.Claim("session", "other", time.Second); owner != "other" {
+		t.Fatalf("expected expired claim to be taken, got %q", owner)
+	}
+}
@@ -0,0 +1,260 @@
+// Package redis is a reference cluster.Coordinator and shared symbol.Store backed by a Redis server (or a compatible one such as
+// Valkey or KeyDB), using github.com/redis/go-redis.
+//
+// All keys are prefixed by Opts.Namespace so that several clusters can share a server:
+//
+//	{ns}node:{id}    a node's NodeInfo as JSON, expiring with its membership
+//	{ns}nodes        set of the IDs of nodes that have heartbeated
+//	{ns}claim:{key}  the ID of the node holding a claimed key, expiring with the claim
+//	{ns}kv:{prefix}  entries of a Store (see Client.Store)
+//
+// It is a separate module so that hosts not using it do not depend on go-redis.
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/amp-3d/amp-sdk-go/amp"
+	"github.com/amp-3d/amp-sdk-go/amp/cluster"
+	"github.com/amp-3d/amp-sdk-go/stdlib/symbol"
+)
+
+// DefaultNamespace is the default for Opts.Namespace.
+const DefaultNamespace = "amp:"
+
+// Opts configures a Client.
+type Opts struct {
+	Addr        string        // server address (e.g. "localhost:6379")
+	Password    string        // if set, sent via AUTH when connecting
+	DB          int           // if > 0, selected via SELECT when connecting
+	Namespace   string        // prefixes all keys (default DefaultNamespace)
+	DialTimeout time.Duration // default 5s
+}
+
+// Client implements cluster.Coordinator using a go-redis client -- concurrency safe.
+type Client struct {
+	rdb goredis.UniversalClient
+	ns  string
+}
+
+var _ cluster.Coordinator = (*Client)(nil)
+
+// Dial connects to the Redis server given by opts.Addr.
+func Dial(opts Opts) (*Client, error) {
+	if opts.DialTimeout <= 0 {
+		opts.DialTimeout = 5 * time.Second
+	}
+	rdb := goredis.NewClient(&goredis.Options{
+		Addr:        opts.Addr,
+		Password:    opts.Password,
+		DB:          opts.DB,
+		DialTimeout: opts.DialTimeout,
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), opts.DialTimeout)
+	defer cancel()
+	if err := rdb.Ping(ctx).Err(); err != nil {
+		rdb.Close()
+		return nil, amp.ErrCode_NotConnected.Errorf("redis: %v", err)
+	}
+	return New(rdb, opts.Namespace), nil
+}
+
+// New returns a Client using the given go-redis client (e.g. one from goredis.NewFailoverClient() for Sentinel),
+// prefixing all keys by the given namespace (or DefaultNamespace if "").  Closing the Client closes rdb.
+//
+// Members() reads all node keys in one MGET, so rdb must not be a goredis.ClusterClient unless the namespace is a hash tag
+// (e.g. "{amp}:") that places all keys in one slot.
+func New(rdb goredis.UniversalClient, namespace string) *Client {
+	if namespace == "" {
+		namespace = DefaultNamespace
+	}
+	return &Client{
+		rdb: rdb,
+		ns:  namespace,
+	}
+}
+
+// Close closes the connection to the server.
+func (c *Client) Close() error {
+	return c.rdb.Close()
+}
+
+func (c *Client) key(parts ...string) string {
+	return c.ns + strings.Join(parts, "")
+}
+
+// Implements cluster.Coordinator
+func (c *Client) Heartbeat(node cluster.NodeInfo, ttl time.Duration) error {
+	info, err := json.Marshal(node)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	if err = c.rdb.Set(ctx, c.key("node:", node.ID), info, max(ttl, time.Millisecond)).Err(); err != nil {
+		return err
+	}
+	return c.rdb.SAdd(ctx, c.key("nodes"), node.ID).Err()
+}
+
+// Implements cluster.Coordinator
+func (c *Client) Leave(nodeID string) error {
+	ctx := context.Background()
+	if err := c.rdb.Del(ctx, c.key("node:", nodeID)).Err(); err != nil {
+		return err
+	}
+	return c.rdb.SRem(ctx, c.key("nodes"), nodeID).Err()
+}
+
+// Implements cluster.Coordinator
+func (c *Client) Members() ([]cluster.NodeInfo, error) {
+	ctx := context.Background()
+	ids, err := c.rdb.SMembers(ctx, c.key("nodes")).Result()
+	if err != nil || len(ids) == 0 {
+		return nil, err
+	}
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = c.key("node:", id)
+	}
+	infos, err := c.rdb.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	var members []cluster.NodeInfo
+	for i, elem := range infos {
+		info, isStr := elem.(string)
+		if !isStr {
+			c.rdb.SRem(ctx, c.key("nodes"), ids[i]) // membership expired
+			continue
+		}
+		var node cluster.NodeInfo
+		if err = json.Unmarshal([]byte(info), &node); err == nil {
+			members = append(members, node)
+		}
+	}
+	return members, nil
+}
+
+// Claims a key if unclaimed or held by the same owner: KEYS[1] = claim key, ARGV = owner, ttl ms.
+const claimScript = `
+local cur = redis.call('GET', KEYS[1])
+if cur == false or cur == ARGV[1] then
+	redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[2])
+	return ARGV[1]
+end
+return cur`
+
+// Releases a key if held by the given owner: KEYS[1] = claim key, ARGV = owner.
+const releaseScript = `
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0`
+
+var (
+	claim   = goredis.NewScript(claimScript)
+	release = goredis.NewScript(releaseScript)
+)
+
+// Implements cluster.Coordinator
+func (c *Client) Claim(key, owner string, ttl time.Duration) (string, error) {
+	return claim.Run(context.Background(), c.rdb, []string{c.key("claim:", key)}, owner, max(ttl.Milliseconds(), 1)).Text()
+}
+
+// Implements cluster.Coordinator
+func (c *Client) Release(key, owner string) error {
+	return release.Run(context.Background(), c.rdb, []string{c.key("claim:", key)}, owner).Err()
+}
+
+// Store returns a symbol.Store whose entries are kept on the server under the given prefix (within this Client's namespace).
+// Writes are applied as they are made, so Commit is a no-op and durability is that of the server's persistence config.
+// Closing the returned Store does not close this Client.
+func (c *Client) Store(prefix string) symbol.Store {
+	return &store{
+		rdb:    c.rdb,
+		prefix: c.key("kv:", prefix),
+	}
+}
+
+// store implements symbol.Store using a go-redis client.
+type store struct {
+	rdb    goredis.UniversalClient
+	prefix string
+}
+
+func (s *store) Get(key []byte) ([]byte, error) {
+	val, err := s.rdb.Get(context.Background(), s.prefix+string(key)).Bytes()
+	if errors.Is(err, goredis.Nil) {
+		return nil, nil
+	}
+	return val, err
+}
+
+func (s *store) Set(key, value []byte) error {
+	return s.rdb.Set(context.Background(), s.prefix+string(key), value, 0).Err()
+}
+
+func (s *store) Delete(key []byte) error {
+	return s.rdb.Del(context.Background(), s.prefix+string(key)).Err()
+}
+
+// Iterate scans for the keys having the given prefix, so it is O(N) in the number of keys on the server.
+// Entries set during iteration may or may not be visited.
+func (s *store) Iterate(prefix []byte, fn func(key, value []byte) error) error {
+	ctx := context.Background()
+	var keys []string
+	iter := s.rdb.Scan(ctx, 0, globEscape(s.prefix+string(prefix))+"*", 1000).Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+	if err := iter.Err(); err != nil {
+		return err
+	}
+	sort.Strings(keys)
+
+	for i, key := range keys {
+		if i > 0 && key == keys[i-1] {
+			continue // SCAN may return a key more than once
+		}
+		val, err := s.rdb.Get(ctx, key).Bytes()
+		if errors.Is(err, goredis.Nil) {
+			continue // deleted since scanned
+		}
+		if err != nil {
+			return err
+		}
+		if err = fn([]byte(key[len(s.prefix):]), val); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *store) Commit() error {
+	return nil
+}
+
+func (s *store) Close() error {
+	return nil
+}
+
+// globEscape escapes the glob metacharacters of a SCAN MATCH pattern.
+func globEscape(pattern string) string {
+	var b strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '*', '?', '[', ']', '\\':
+			b.WriteByte('\\')
+		}
+		b.WriteByte(pattern[i])
+	}
+	return b.String()
+}
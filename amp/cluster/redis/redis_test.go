@@ -0,0 +1,106 @@
+package redis_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+
+	"github.com/amp-3d/amp-sdk-go/amp/cluster"
+	"github.com/amp-3d/amp-sdk-go/amp/cluster/redis"
+	"github.com/amp-3d/amp-sdk-go/stdlib/symbol"
+	"github.com/amp-3d/amp-sdk-go/stdlib/symbol/tests"
+)
+
+func TestClient(t *testing.T) {
+	srv := miniredis.RunT(t)
+	srv.RequireAuth("secret")
+	if _, err := redis.Dial(redis.Opts{Addr: srv.Addr(), Password: "wrong"}); err == nil {
+		t.Fatal("expected a bad password to be rejected")
+	}
+	c, err := redis.Dial(redis.Opts{Addr: srv.Addr(), Password: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	// Membership
+	if err = c.Heartbeat(cluster.NodeInfo{ID: "n1", Addr: "n1:5192"}, time.Second); err != nil {
+		t.Fatal(err)
+	}
+	c.Heartbeat(cluster.NodeInfo{ID: "n2"}, 20*time.Millisecond)
+	srv.FastForward(40 * time.Millisecond)
+	if members, err := c.Members(); err != nil || len(members) != 1 || members[0].Addr != "n1:5192" {
+		t.Fatalf("unexpected members %v (%v)", members, err)
+	}
+	if !srv.Exists("amp:node:n1") {
+		t.Fatal("expected keys to be in the default namespace")
+	}
+
+	// Claims
+	if owner, err := c.Claim("session", "n1", time.Second); err != nil || owner != "n1" {
+		t.Fatalf("unexpected owner %q (%v)", owner, err)
+	}
+	if owner, _ := c.Claim("session", "n2", time.Second); owner != "n1" {
+		t.Fatalf("expected claim to be held, got %q", owner)
+	}
+	c.Release("session", "n2")
+	if owner, _ := c.Claim("session", "n2", time.Second); owner != "n1" {
+		t.Fatal("claim released by non-owner")
+	}
+	c.Release("session", "n1")
+	if owner, _ := c.Claim("session", "n2", time.Second); owner != "n2" {
+		t.Fatalf("expected released claim to be taken, got %q", owner)
+	}
+	srv.FastForward(2 * time.Second)
+	if owner, _ := c.Claim("session", "n1", time.Second); owner != "n1" {
+		t.Fatalf("expected expired claim to be taken, got %q", owner)
+	}
+
+	// Store, where keys are binary and may contain glob metacharacters
+	store := c.Store("app/")
+	for _, key := range []string{"b*", "a", "b?", "c\x00\xff"} {
+		store.Set([]byte(key), []byte("v:"+key))
+	}
+	c.Store("other/").Set([]byte("a"), []byte("x"))
+	store.Delete([]byte("a"))
+	var keys []string
+	err = store.Iterate([]byte("b"), func(key, value []byte) error {
+		if string(value) != "v:"+string(key) {
+			t.Errorf("key %q has value %q", key, value)
+		}
+		keys = append(keys, string(key))
+		return nil
+	})
+	if err != nil || strings.Join(keys, ",") != "b*,b?" {
+		t.Fatalf("unexpected keys %q (%v)", keys, err)
+	}
+	if val, _ := store.Get([]byte("a")); val != nil {
+		t.Fatalf("deleted key has value %q", val)
+	}
+
+	// The client reconnects once the server is back
+	srv.Close()
+	if _, err = store.Get([]byte("b*")); err == nil {
+		t.Fatal("expected an error while the server is down")
+	}
+	if err = srv.Restart(); err != nil {
+		t.Fatal(err)
+	}
+	if val, err := store.Get([]byte("c\x00\xff")); err != nil || string(val) != "v:c\x00\xff" {
+		t.Fatalf("expected reconnect, got %q (%v)", val, err)
+	}
+}
+
+func TestStore(t *testing.T) {
+	srv := miniredis.RunT(t)
+	c, err := redis.Dial(redis.Opts{Addr: srv.Addr(), Namespace: "test:"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	tests.DoStoreTest(t, func() (symbol.Store, error) {
+		return c.Store("symbols/"), nil
+	})
+}
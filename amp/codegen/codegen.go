@@ -0,0 +1,435 @@
+// Package codegen emits client bindings for the attr types registered with an amp.Registry, so that clients need not
+// hand-maintain mirrors of the Go attr structs that drift as the structs change.
+//
+// Describe walks a Registry's attrs (see amp.Registry.Describe) and the protobuf messages and enums their values are built
+// from, forming a language-neutral Schema that is then rendered via WriteTypeScript or WriteCSharp:
+//
+//	schema, err := codegen.Describe(reg)
+//	...
+//	err = schema.WriteTypeScript(w, codegen.Opts{})
+//
+// Each message is rendered as a type along with functions to encode and decode it in the protobuf wire format its Go struct
+// uses (see amp.MarshalPbToStore), and the attrs are rendered as a table mapping each attr spec to its ID and value type, so a
+// client can decode the value of any TxOp whose AttrID it finds in the table.
+package codegen
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/amp-3d/amp-sdk-go/amp"
+	"github.com/amp-3d/amp-sdk-go/stdlib/tag"
+	"github.com/gogo/protobuf/proto"
+)
+
+// Opts configures the output of WriteTypeScript and WriteCSharp.
+type Opts struct {
+	Namespace string // C# namespace of the emitted types (default DefaultNamespace)
+	Header    string // if set, emitted as a comment atop the output (e.g. the command that generated it)
+}
+
+// DefaultNamespace is the default for Opts.Namespace, distinct from the namespace of the protoc generated C# types ("AMP").
+const DefaultNamespace = "AMP.Attrs"
+
+// Schema is the language-neutral model of the attr types of a Registry.
+type Schema struct {
+	Attrs    []Attr     // sorted by Spec
+	Messages []*Message // messages reachable from Attrs, sorted by Name
+	Enums    []*Enum    // enums of the fields of Messages, sorted by Name
+	Skipped  []Attr     // attrs having no prototype or whose values are not protobuf messages, which are not rendered
+}
+
+// Attr is a registered attr spec and the message type of its values.
+type Attr struct {
+	Spec     string // canonic attr spec (e.g. "amp.attr.pinned.TagTab")
+	ID       tag.ID // ID of Spec, as found in TxOp.AttrID
+	ElemType string // name of the attr's Message (see amp.ElemVal.ElemTypeName)
+}
+
+// Message is a protobuf message type.
+type Message struct {
+	Name   string  // Go type name
+	Fields []Field // sorted by Num
+}
+
+// Field is a field of a Message or (as a Key or Value) the entry of a map field.
+type Field struct {
+	Name     string
+	Num      int  // protobuf field number
+	Kind     Kind // kind of each element if Repeated
+	Repeated bool
+	Packed   bool   // set if this is a repeated numeric field whose elements are encoded as one length-delimited field
+	Type     string // name of the field's Message or Enum (if KindMessage or KindEnum)
+	Key      *Field // entry key if KindMap
+	Value    *Field // entry value if KindMap
+}
+
+// Enum is a protobuf enum type.
+type Enum struct {
+	Name   string // Go type name
+	Values []EnumValue
+}
+
+// EnumValue is a named value of an Enum.
+type EnumValue struct {
+	Name string // value name with any "<Enum>_" prefix removed (e.g. "Pinnable" for TagUse_Pinnable)
+	Num  int32
+}
+
+// Kind is the protobuf type of a Field.
+type Kind int
+
+const (
+	KindBool Kind = iota + 1
+	KindInt32
+	KindInt64
+	KindUint32
+	KindUint64
+	KindSint32
+	KindSint64
+	KindFixed32
+	KindFixed64
+	KindSfixed32
+	KindSfixed64
+	KindFloat
+	KindDouble
+	KindString
+	KindBytes
+	KindEnum
+	KindMessage
+	KindMap
+)
+
+var kindNames = [...]string{
+	KindBool:     "bool",
+	KindInt32:    "int32",
+	KindInt64:    "int64",
+	KindUint32:   "uint32",
+	KindUint64:   "uint64",
+	KindSint32:   "sint32",
+	KindSint64:   "sint64",
+	KindFixed32:  "fixed32",
+	KindFixed64:  "fixed64",
+	KindSfixed32: "sfixed32",
+	KindSfixed64: "sfixed64",
+	KindFloat:    "float",
+	KindDouble:   "double",
+	KindString:   "string",
+	KindBytes:    "bytes",
+	KindEnum:     "enum",
+	KindMessage:  "message",
+	KindMap:      "map",
+}
+
+func (kind Kind) String() string {
+	if kind > 0 && int(kind) < len(kindNames) {
+		return kindNames[kind]
+	}
+	return fmt.Sprintf("Kind(%d)", int(kind))
+}
+
+// WireType returns the protobuf wire type of a (non-packed) value of this kind.
+func (kind Kind) WireType() int {
+	switch kind {
+	case KindFixed64, KindSfixed64, KindDouble:
+		return 1
+	case KindString, KindBytes, KindMessage, KindMap:
+		return 2
+	case KindFixed32, KindSfixed32, KindFloat:
+		return 5
+	}
+	return 0
+}
+
+// Is64 returns true if this is a kind of 64 bit integer (which some languages represent differently than other numbers).
+func (kind Kind) Is64() bool {
+	switch kind {
+	case KindInt64, KindUint64, KindSint64, KindFixed64, KindSfixed64:
+		return true
+	}
+	return false
+}
+
+// Describe returns the Schema of the attrs registered with the given registry, failing with ErrCode_ExportErr if an attr's
+// value type cannot be rendered (e.g. it has a oneof field).
+func Describe(reg amp.Registry) (*Schema, error) {
+	desc := &describer{
+		schema:   &Schema{},
+		messages: make(map[string]*Message),
+		types:    make(map[string]reflect.Type),
+		enums:    make(map[string]*Enum),
+	}
+	for _, info := range reg.Describe().Attrs {
+		attr := Attr{
+			Spec: info.Spec,
+			ID:   info.ID,
+		}
+		var rt reflect.Type
+		if info.ElemType != "" {
+			val, err := reg.NewAttrElem(info.ID)
+			if err != nil {
+				return nil, err
+			}
+			rt = reflect.TypeOf(val)
+		}
+		if rt == nil || rt.Kind() != reflect.Pointer || rt.Elem().Kind() != reflect.Struct || !isMessage(rt.Elem()) {
+			attr.ElemType = info.ElemType
+			desc.schema.Skipped = append(desc.schema.Skipped, attr)
+			continue
+		}
+		var err error
+		if attr.ElemType, err = desc.message(rt.Elem()); err != nil {
+			return nil, amp.ErrCode_ExportErr.Errorf("attr %s: %v", info.Spec, err)
+		}
+		desc.schema.Attrs = append(desc.schema.Attrs, attr)
+	}
+
+	schema := desc.schema
+	for _, msg := range desc.messages {
+		schema.Messages = append(schema.Messages, msg)
+	}
+	sort.Slice(schema.Messages, func(i, j int) bool { return schema.Messages[i].Name < schema.Messages[j].Name })
+	for _, enum := range desc.enums {
+		schema.Enums = append(schema.Enums, enum)
+	}
+	sort.Slice(schema.Enums, func(i, j int) bool { return schema.Enums[i].Name < schema.Enums[j].Name })
+	return schema, nil
+}
+
+// Message returns the named message of this schema, or nil if there is none.
+func (schema *Schema) Message(name string) *Message {
+	i := sort.Search(len(schema.Messages), func(i int) bool { return schema.Messages[i].Name >= name })
+	if i < len(schema.Messages) && schema.Messages[i].Name == name {
+		return schema.Messages[i]
+	}
+	return nil
+}
+
+type describer struct {
+	schema   *Schema
+	messages map[string]*Message
+	types    map[string]reflect.Type // Go type of each of messages, to detect distinct types of the same name
+	enums    map[string]*Enum
+}
+
+// isMessage returns true if the given struct type has protobuf fields.
+func isMessage(rt reflect.Type) bool {
+	for i := 0; i < rt.NumField(); i++ {
+		if _, tagged := rt.Field(i).Tag.Lookup("protobuf"); tagged {
+			return true
+		}
+	}
+	return false
+}
+
+// message adds the given message struct type (and those of its fields) if not already added, returning its name.
+func (desc *describer) message(rt reflect.Type) (string, error) {
+	name := rt.Name()
+	if prev, exists := desc.types[name]; exists {
+		if prev != rt {
+			return "", fmt.Errorf("distinct types named %s (%s and %s)", name, prev.PkgPath(), rt.PkgPath())
+		}
+		return name, nil
+	}
+	msg := &Message{
+		Name: name,
+	}
+	desc.types[name] = rt
+	desc.messages[name] = msg
+
+	props := proto.GetProperties(rt)
+	for i, prop := range props.Prop {
+		sf := rt.Field(i)
+		if _, isOneof := sf.Tag.Lookup("protobuf_oneof"); isOneof {
+			return "", fmt.Errorf("%s.%s: oneof fields are not supported", name, sf.Name)
+		}
+		if prop.Tag == 0 || strings.HasPrefix(sf.Name, "XXX_") {
+			continue
+		}
+		field, err := desc.field(prop, sf.Type)
+		if err != nil {
+			return "", fmt.Errorf("%s.%s: %w", name, sf.Name, err)
+		}
+		field.Name = sf.Name
+		msg.Fields = append(msg.Fields, field)
+	}
+	sort.Slice(msg.Fields, func(i, j int) bool { return msg.Fields[i].Num < msg.Fields[j].Num })
+	return name, nil
+}
+
+// field returns the Field of the given protobuf properties of a struct field of the given Go type.
+func (desc *describer) field(prop *proto.Properties, rt reflect.Type) (Field, error) {
+	field := Field{
+		Name: prop.OrigName,
+		Num:  prop.Tag,
+	}
+	if rt.Kind() == reflect.Map {
+		key, err := desc.field(prop.MapKeyProp, rt.Key())
+		if err != nil {
+			return field, err
+		}
+		val, err := desc.field(prop.MapValProp, rt.Elem())
+		if err != nil {
+			return field, err
+		}
+		key.Num, val.Num = 1, 2
+		field.Kind, field.Key, field.Value = KindMap, &key, &val
+		return field, nil
+	}
+	if rt.Kind() == reflect.Slice && rt.Elem().Kind() != reflect.Uint8 {
+		field.Repeated = true
+		rt = rt.Elem()
+	}
+	if rt.Kind() == reflect.Pointer {
+		rt = rt.Elem()
+	}
+
+	var err error
+	switch rt.Kind() {
+	case reflect.Struct:
+		field.Kind = KindMessage
+		field.Type, err = desc.message(rt)
+	case reflect.String:
+		field.Kind = KindString
+	case reflect.Slice:
+		field.Kind = KindBytes
+	case reflect.Bool:
+		field.Kind = KindBool
+	default:
+		field.Kind, err = numericKind(prop.Wire, rt.Kind())
+		if err == nil && prop.Enum != "" {
+			field.Kind = KindEnum
+			field.Type, err = desc.enum(prop.Enum, rt)
+		}
+		field.Packed = field.Repeated && prop.Packed
+	}
+	return field, err
+}
+
+func numericKind(wire string, kind reflect.Kind) (Kind, error) {
+	switch wire {
+	case "varint":
+		switch kind {
+		case reflect.Int32:
+			return KindInt32, nil
+		case reflect.Int64:
+			return KindInt64, nil
+		case reflect.Uint32:
+			return KindUint32, nil
+		case reflect.Uint64:
+			return KindUint64, nil
+		}
+	case "zigzag32":
+		return KindSint32, nil
+	case "zigzag64":
+		return KindSint64, nil
+	case "fixed32":
+		switch kind {
+		case reflect.Float32:
+			return KindFloat, nil
+		case reflect.Uint32:
+			return KindFixed32, nil
+		case reflect.Int32:
+			return KindSfixed32, nil
+		}
+	case "fixed64":
+		switch kind {
+		case reflect.Float64:
+			return KindDouble, nil
+		case reflect.Uint64:
+			return KindFixed64, nil
+		case reflect.Int64:
+			return KindSfixed64, nil
+		}
+	}
+	return 0, fmt.Errorf("unsupported %s field of Go type %s", wire, kind)
+}
+
+// enum adds the given registered protobuf enum (e.g. "amp.TagUse") if not already added, returning its name.
+func (desc *describer) enum(protoName string, rt reflect.Type) (string, error) {
+	name := rt.Name()
+	if desc.enums[name] != nil {
+		return name, nil
+	}
+	values := proto.EnumValueMap(protoName)
+	if values == nil {
+		return "", fmt.Errorf("enum %s is not registered", protoName)
+	}
+	enum := &Enum{
+		Name: name,
+	}
+	for valueName, num := range values {
+		if trimmed := strings.TrimPrefix(valueName, name+"_"); trimmed != "" && !isDigit(trimmed[0]) {
+			valueName = trimmed
+		}
+		enum.Values = append(enum.Values, EnumValue{Name: valueName, Num: num})
+	}
+	sort.Slice(enum.Values, func(i, j int) bool {
+		if enum.Values[i].Num != enum.Values[j].Num {
+			return enum.Values[i].Num < enum.Values[j].Num
+		}
+		return enum.Values[i].Name < enum.Values[j].Name
+	})
+	desc.enums[name] = enum
+	return name, nil
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// emitter accumulates generated source, indenting each line per the current depth.
+type emitter struct {
+	buf    strings.Builder
+	indent string // unit of indentation
+	depth  int
+}
+
+// line emits the given formatted line, where a leading "}" (or ")" or "]") outdents before and a trailing "{" (or "(" or "[")
+// indents after.  As in Go, a switch's case labels are emitted at the depth of its switch.
+func (e *emitter) line(format string, args ...any) {
+	text := format
+	if len(args) > 0 {
+		text = fmt.Sprintf(format, args...)
+	}
+	if text == "" {
+		e.buf.WriteByte('\n')
+		return
+	}
+	if strings.IndexByte("})]", text[0]) >= 0 {
+		e.depth--
+	}
+	depth := e.depth
+	if strings.HasPrefix(text, "case ") || text == "default:" {
+		depth--
+	}
+	e.buf.WriteString(strings.Repeat(e.indent, max(depth, 0)))
+	e.buf.WriteString(text)
+	e.buf.WriteByte('\n')
+	if last := text[len(text)-1]; last == '{' || last == '(' || last == '[' {
+		e.depth++
+	}
+}
+
+// lines emits the given text at the current depth, replacing each of its leading tabs with a unit of indentation.
+func (e *emitter) lines(text string) {
+	for _, line := range strings.Split(strings.Trim(text, "\n"), "\n") {
+		if line == "" {
+			e.buf.WriteByte('\n')
+			continue
+		}
+		body := strings.TrimLeft(line, "\t")
+		e.buf.WriteString(strings.Repeat(e.indent, e.depth+len(line)-len(body)))
+		e.buf.WriteString(body)
+		e.buf.WriteByte('\n')
+	}
+}
+
+// comment emits the given text as comment lines having the given prefix (e.g. "// ").
+func (e *emitter) comment(prefix, text string) {
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		e.line("%s", strings.TrimRight(prefix+line, " "))
+	}
+}
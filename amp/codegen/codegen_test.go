@@ -0,0 +1,190 @@
+package codegen_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/amp-3d/amp-sdk-go/amp"
+	"github.com/amp-3d/amp-sdk-go/amp/codegen"
+	"github.com/gogo/protobuf/proto"
+)
+
+// Sample has the kinds of fields the builtin attr types lack.
+type Sample struct {
+	Counts []int64             `protobuf:"varint,1,rep,packed,name=Counts,proto3"`
+	Deltas []int32             `protobuf:"zigzag32,2,rep,name=Deltas,proto3"`
+	Links  map[string]*amp.Tag `protobuf:"bytes,3,rep,name=Links,proto3" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Weight float64             `protobuf:"fixed64,4,opt,name=Weight,proto3"`
+	Flags  map[uint32]bool     `protobuf:"bytes,5,rep,name=Flags,proto3" protobuf_key:"fixed32,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	Sample string              `protobuf:"bytes,6,opt,name=Sample,proto3"`
+	Extra  map[string][]byte   // not a protobuf field
+}
+
+func (v *Sample) Reset()                     { *v = Sample{} }
+func (v *Sample) String() string             { return proto.CompactTextString(v) }
+func (v *Sample) ProtoMessage()              {}
+func (v *Sample) ElemTypeName() string       { return "Sample" }
+func (v *Sample) New() amp.ElemVal           { return &Sample{} }
+func (v *Sample) Unmarshal(src []byte) error { return proto.Unmarshal(src, v) }
+func (v *Sample) MarshalToStore(in []byte) ([]byte, error) {
+	out, err := proto.Marshal(v)
+	return append(in, out...), err
+}
+
+func newSchema(t *testing.T) *codegen.Schema {
+	reg := amp.NewRegistry()
+	if err := amp.RegisterBuiltinTypes(reg); err != nil {
+		t.Fatal(err)
+	}
+	reg.RegisterPrototype(amp.AttrSpec, &Sample{}, "test.Sample")
+	schema, err := codegen.Describe(reg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return schema
+}
+
+func TestDescribe(t *testing.T) {
+	schema := newSchema(t)
+	if len(schema.Skipped) != 0 {
+		t.Fatalf("unexpected skipped attrs %v", schema.Skipped)
+	}
+
+	specs := map[string]string{}
+	for _, attr := range schema.Attrs {
+		specs[attr.Spec] = attr.ElemType
+	}
+	for spec, elemType := range map[string]string{
+		amp.ChildTabSpec.Canonic:  "TagTab",
+		amp.PinnedTabSpec.Canonic: "TagTab",
+		amp.ArtworkSpec.Canonic:   "Tag",
+		"amp.attr.test.Sample":    "Sample",
+	} {
+		if specs[spec] != elemType {
+			t.Fatalf("expected attr %s of type %s, got %q", spec, elemType, specs[spec])
+		}
+	}
+
+	tag := schema.Message("Tag")
+	if tag == nil || tag.Fields[0].Name != "Use" || tag.Fields[0].Kind != codegen.KindEnum || tag.Fields[0].Type != "TagUse" {
+		t.Fatalf("unexpected Tag message %+v", tag)
+	}
+	if f := tag.Fields[2]; f.Name != "TagID_1" || f.Num != 3 || f.Kind != codegen.KindFixed64 {
+		t.Fatalf("unexpected Tag field %+v", f)
+	}
+
+	sample := schema.Message("Sample")
+	if sample == nil || len(sample.Fields) != 6 {
+		t.Fatalf("unexpected Sample message %+v", sample)
+	}
+	if f := sample.Fields[0]; f.Kind != codegen.KindInt64 || !f.Repeated || !f.Packed {
+		t.Fatalf("expected packed int64s, got %+v", f)
+	}
+	if f := sample.Fields[1]; f.Kind != codegen.KindSint32 || !f.Repeated || f.Packed {
+		t.Fatalf("expected unpacked sint32s, got %+v", f)
+	}
+	if f := sample.Fields[2]; f.Kind != codegen.KindMap || f.Key.Kind != codegen.KindString || f.Value.Kind != codegen.KindMessage || f.Value.Type != "Tag" {
+		t.Fatalf("expected map of Tags, got %+v", f)
+	}
+
+	var tagUse *codegen.Enum
+	for _, enum := range schema.Enums {
+		if enum.Name == "TagUse" {
+			tagUse = enum
+		}
+	}
+	if tagUse == nil || tagUse.Values[0] != (codegen.EnumValue{Name: "Unspecified", Num: 0}) || tagUse.Values[1].Name != "Pinnable" {
+		t.Fatalf("unexpected TagUse enum %+v", tagUse)
+	}
+}
+
+func TestWriteTypeScript(t *testing.T) {
+	schema := newSchema(t)
+	var out bytes.Buffer
+	if err := schema.WriteTypeScript(&out, codegen.Opts{Header: "generated by TestWriteTypeScript"}); err != nil {
+		t.Fatal(err)
+	}
+	ts := out.String()
+	for _, want := range []string{
+		"// Code generated by amp/codegen. DO NOT EDIT.\n// generated by TestWriteTypeScript\n",
+		"export class ProtoWriter {",
+		"export enum TagUse {\n  Unspecified = 0,\n  Pinnable = 1,",
+		"export interface TagTab {\n  Label: string;\n  Caption: string;\n  About: string;\n  CreatedAt: bigint;",
+		"  Tags: Tag[];\n",
+		"  Counts: bigint[];\n  Deltas: number[];\n  Links: Map<string, Tag>;\n  Weight: number;\n  Flags: Map<number, boolean>;\n",
+		"export function encodeTagTab(v: TagTab): Uint8Array {",
+		"export function decodeTagTab(buf: Uint8Array): TagTab {",
+		"w.message((p) => v.Counts.forEach((x) => p.int64(x)));",
+		"while (!p.done()) v.Deltas.push(p.sint32());",
+		"if (n === 2 && t === 2) x = readTag(m.sub());",
+		`"amp.attr.pinned.TagTab": { spec: "amp.attr.pinned.TagTab", id: "` + amp.PinnedTabSpec.ID.Base16() + `", elemType: "TagTab", encode: encodeTagTab, decode: decodeTagTab },`,
+	} {
+		if !strings.Contains(ts, want) {
+			t.Fatalf("expected TypeScript to contain %q", want)
+		}
+	}
+	if strings.Contains(ts, "Extra") {
+		t.Fatal("expected non-protobuf field to be omitted")
+	}
+
+	var again bytes.Buffer
+	if err := newSchema(t).WriteTypeScript(&again, codegen.Opts{Header: "generated by TestWriteTypeScript"}); err != nil {
+		t.Fatal(err)
+	}
+	if again.String() != ts {
+		t.Fatal("expected output to be deterministic")
+	}
+}
+
+func TestWriteCSharp(t *testing.T) {
+	schema := newSchema(t)
+	var out bytes.Buffer
+	if err := schema.WriteCSharp(&out, codegen.Opts{}); err != nil {
+		t.Fatal(err)
+	}
+	cs := out.String()
+	for _, want := range []string{
+		"namespace AMP.Attrs\n{\n",
+		"public enum TagUse\n    {\n        Unspecified = 0,",
+		"public sealed partial class TagTab\n    {\n        public string Label = \"\";",
+		"public List<Tag> Tags = new List<Tag>();",
+		"public Dictionary<uint, bool> Flags = new Dictionary<uint, bool>();",
+		"public string Sample_ = \"\";",
+		"public static TagTab Decode(byte[] buf) => ReadFrom(new ProtoReader(buf));",
+		"foreach (var x in Counts) p.Int64(x);",
+		"w.Int32((int)Use);",
+		`["amp.attr.pinned.TagTab"] = new AttrType("amp.attr.pinned.TagTab", "` + amp.PinnedTabSpec.ID.Base16() + `", "TagTab", v => ((TagTab)v).Encode(), TagTab.Decode),`,
+	} {
+		if !strings.Contains(cs, want) {
+			t.Fatalf("expected C# to contain %q", want)
+		}
+	}
+
+	out.Reset()
+	if err := schema.WriteCSharp(&out, codegen.Opts{Namespace: "Client.Amp"}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "namespace Client.Amp\n") {
+		t.Fatal("expected namespace option to apply")
+	}
+}
+
+type withOneof struct {
+	Sample
+	Label  string   `protobuf:"bytes,1,opt,name=Label,proto3"`
+	Choice isChoice `protobuf_oneof:"Choice"`
+}
+
+type isChoice interface{ isChoice() }
+
+func (v *withOneof) New() amp.ElemVal { return &withOneof{} }
+
+func TestDescribeErrors(t *testing.T) {
+	reg := amp.NewRegistry()
+	reg.RegisterPrototype(amp.AttrSpec, &withOneof{}, "test.Oneof")
+	_, err := codegen.Describe(reg)
+	if ampErr, ok := err.(*amp.Err); !ok || ampErr.Code != amp.ErrCode_ExportErr || !strings.Contains(ampErr.Msg, "oneof fields are not supported") {
+		t.Fatalf("expected oneof to be refused, got %v", err)
+	}
+}
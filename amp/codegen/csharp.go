@@ -0,0 +1,551 @@
+package codegen
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteCSharp writes a self-contained C# source file declaring, in namespace opts.Namespace, an enum for each of the schema's
+// enums and a class for each of its messages, where each class X has the members:
+//
+//	byte[] Encode()                    returns the protobuf encoding of this X
+//	void WriteTo(ProtoWriter w)        writes the encoding of this X to w
+//	static X Decode(byte[] buf)        returns the X encoded in buf (skipping fields it does not know)
+//	static X ReadFrom(ProtoReader r)   returns the X read from r
+//
+// along with the static class AttrTypes, which maps each attr spec (and attr ID) to its AttrType.  A field whose name
+// collides with one of these members or with its class is suffixed with "_".
+func (schema *Schema) WriteCSharp(w io.Writer, opts Opts) error {
+	if opts.Namespace == "" {
+		opts.Namespace = DefaultNamespace
+	}
+	e := &emitter{indent: "    "}
+	e.line("// Code generated by amp/codegen. DO NOT EDIT.")
+	if opts.Header != "" {
+		e.comment("// ", opts.Header)
+	}
+	e.line("")
+	e.line("#nullable enable")
+	e.line("")
+	e.line("using System;")
+	e.line("using System.Buffers.Binary;")
+	e.line("using System.Collections.Generic;")
+	e.line("using System.Linq;")
+	e.line("using System.Text;")
+	e.line("")
+	e.line("namespace %s", opts.Namespace)
+	e.line("{")
+
+	for _, enum := range schema.Enums {
+		e.line("public enum %s", enum.Name)
+		e.line("{")
+		for _, val := range enum.Values {
+			e.line("%s = %d,", val.Name, val.Num)
+		}
+		e.line("}")
+		e.line("")
+	}
+
+	for _, msg := range schema.Messages {
+		csMessage(e, msg)
+		e.line("")
+	}
+
+	e.line("public sealed class AttrType")
+	e.line("{")
+	e.line("public readonly string Spec;     // canonic attr spec")
+	e.line("public readonly string ID;       // attr ID as 48 hex digits (see AttrTypes.Of)")
+	e.line("public readonly string ElemType; // name of the class of the attr's values")
+	e.line("public readonly Func<object, byte[]> Encode;")
+	e.line("public readonly Func<byte[], object> Decode;")
+	e.line("")
+	e.line("public AttrType(string spec, string id, string elemType, Func<object, byte[]> encode, Func<byte[], object> decode)")
+	e.line("{")
+	e.line("Spec = spec;")
+	e.line("ID = id;")
+	e.line("ElemType = elemType;")
+	e.line("Encode = encode;")
+	e.line("Decode = decode;")
+	e.line("}")
+	e.line("}")
+	e.line("")
+	e.line("public static class AttrTypes")
+	e.line("{")
+	e.line("public static readonly IReadOnlyDictionary<string, AttrType> BySpec = new Dictionary<string, AttrType>")
+	e.line("{")
+	for _, attr := range schema.Attrs {
+		e.line("[%q] = new AttrType(%q, %q, %q, v => ((%s)v).Encode(), %s.Decode),",
+			attr.Spec, attr.Spec, attr.ID.Base16(), attr.ElemType, attr.ElemType, attr.ElemType)
+	}
+	e.line("};")
+	e.line("")
+	e.line("public static readonly IReadOnlyDictionary<string, AttrType> ByID = BySpec.Values.ToDictionary(t => t.ID);")
+	e.line("")
+	e.line("// Of returns the AttrType of the given attr ID (as the three words of TxOp.AttrID), or null if unknown.")
+	e.line("public static AttrType? Of(ulong id0, ulong id1, ulong id2)")
+	e.line("{")
+	e.line(`return ByID.TryGetValue($"{id0:x16}{id1:x16}{id2:x16}", out var t) ? t : null;`)
+	e.line("}")
+	e.line("}")
+	e.line("")
+	e.lines(csRuntime)
+	e.line("}")
+
+	_, err := io.WriteString(w, e.buf.String())
+	return err
+}
+
+// csFieldName returns the C# member name of the given field of msg.
+func csFieldName(msg *Message, field *Field) string {
+	switch field.Name {
+	case msg.Name, "Encode", "Decode", "WriteTo", "ReadFrom":
+		return field.Name + "_"
+	}
+	return field.Name
+}
+
+var csTypes = [...]string{
+	KindBool:     "bool",
+	KindInt32:    "int",
+	KindInt64:    "long",
+	KindUint32:   "uint",
+	KindUint64:   "ulong",
+	KindSint32:   "int",
+	KindSint64:   "long",
+	KindFixed32:  "uint",
+	KindFixed64:  "ulong",
+	KindSfixed32: "int",
+	KindSfixed64: "long",
+	KindFloat:    "float",
+	KindDouble:   "double",
+	KindString:   "string",
+	KindBytes:    "byte[]",
+}
+
+func csType(field *Field) string {
+	var typ string
+	switch field.Kind {
+	case KindEnum, KindMessage:
+		typ = field.Type
+	case KindMap:
+		typ = fmt.Sprintf("Dictionary<%s, %s>", csType(field.Key), csType(field.Value))
+	default:
+		typ = csTypes[field.Kind]
+	}
+	if field.Repeated {
+		typ = fmt.Sprintf("List<%s>", typ)
+	}
+	return typ
+}
+
+// csMethod returns the ProtoWriter / ProtoReader method of the given scalar kind.
+func csMethod(kind Kind) string {
+	switch kind {
+	case KindEnum:
+		return "Int32"
+	case KindUint32:
+		return "UInt32"
+	case KindUint64:
+		return "UInt64"
+	case KindSint32:
+		return "SInt32"
+	case KindSint64:
+		return "SInt64"
+	case KindSfixed32:
+		return "SFixed32"
+	case KindSfixed64:
+		return "SFixed64"
+	}
+	name := kind.String()
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+func csMessage(e *emitter, msg *Message) {
+	e.line("public sealed partial class %s", msg.Name)
+	e.line("{")
+	for _, field := range msg.Fields {
+		name, typ := csFieldName(msg, &field), csType(&field)
+		switch {
+		case field.Repeated || field.Kind == KindMap:
+			e.line("public %s %s = new %s();", typ, name, typ)
+		case field.Kind == KindMessage:
+			e.line("public %s? %s;", typ, name)
+		case field.Kind == KindString:
+			e.line(`public string %s = "";`, name)
+		case field.Kind == KindBytes:
+			e.line("public byte[] %s = Array.Empty<byte>();", name)
+		default:
+			e.line("public %s %s;", typ, name)
+		}
+	}
+	if len(msg.Fields) > 0 {
+		e.line("")
+	}
+	e.line("public byte[] Encode()")
+	e.line("{")
+	e.line("var w = new ProtoWriter();")
+	e.line("WriteTo(w);")
+	e.line("return w.ToArray();")
+	e.line("}")
+	e.line("")
+	e.line("public static %s Decode(byte[] buf) => ReadFrom(new ProtoReader(buf));", msg.Name)
+	e.line("")
+	csWrite(e, msg)
+	e.line("")
+	csRead(e, msg)
+	e.line("}")
+}
+
+func csWrite(e *emitter, msg *Message) {
+	e.line("public void WriteTo(ProtoWriter w)")
+	e.line("{")
+	for _, field := range msg.Fields {
+		name := csFieldName(msg, &field)
+		switch {
+		case field.Kind == KindMap:
+			e.line("foreach (var (k, x) in %s)", name)
+			e.line("{")
+			e.line("w.Tag(%d, 2);", field.Num)
+			e.line("w.Message(e =>")
+			e.line("{")
+			csWriteValue(e, "e", field.Key, "k")
+			csWriteValue(e, "e", field.Value, "x")
+			e.line("});")
+			e.line("}")
+		case field.Packed:
+			e.line("if (%s.Count > 0)", name)
+			e.line("{")
+			e.line("w.Tag(%d, 2);", field.Num)
+			e.line("w.Message(p =>")
+			e.line("{")
+			e.line("foreach (var x in %s) p.%s(%s);", name, csMethod(field.Kind), csScalar(&field, "x"))
+			e.line("});")
+			e.line("}")
+		case field.Repeated:
+			e.line("foreach (var x in %s)", name)
+			e.line("{")
+			csWriteValue(e, "w", &field, "x")
+			e.line("}")
+		default:
+			e.line("if (%s)", csNonDefault(&field, name))
+			e.line("{")
+			csWriteValue(e, "w", &field, name)
+			e.line("}")
+		}
+	}
+	e.line("}")
+}
+
+func csNonDefault(field *Field, expr string) string {
+	switch field.Kind {
+	case KindMessage:
+		return expr + " != null"
+	case KindBool:
+		return expr
+	case KindString, KindBytes:
+		return expr + ".Length > 0"
+	}
+	return expr + " != 0"
+}
+
+// csScalar returns the given expression of the given field as passed to a ProtoWriter.
+func csScalar(field *Field, expr string) string {
+	if field.Kind == KindEnum {
+		return "(int)" + expr
+	}
+	return expr
+}
+
+func csWriteValue(e *emitter, w string, field *Field, expr string) {
+	e.line("%s.Tag(%d, %d);", w, field.Num, field.Kind.WireType())
+	if field.Kind == KindMessage {
+		e.line("%s.Message(%s.WriteTo);", w, expr)
+	} else {
+		e.line("%s.%s(%s);", w, csMethod(field.Kind), csScalar(field, expr))
+	}
+}
+
+func csRead(e *emitter, msg *Message) {
+	e.line("public static %s ReadFrom(ProtoReader r)", msg.Name)
+	e.line("{")
+	e.line("var v = new %s();", msg.Name)
+	e.line("while (!r.Done)")
+	e.line("{")
+	e.line("var (num, wt) = r.Tag();")
+	if len(msg.Fields) > 0 {
+		e.line("switch (num)")
+		e.line("{")
+		for _, field := range msg.Fields {
+			expr := "v." + csFieldName(msg, &field)
+			e.line("case %d:", field.Num)
+			switch {
+			case field.Kind == KindMap:
+				e.line("if (wt == 2)")
+				e.line("{")
+				e.line("var m = r.Sub();")
+				e.line("var k = %s;", csEntryDefault(field.Key))
+				e.line("var x = %s;", csEntryDefault(field.Value))
+				e.line("while (!m.Done)")
+				e.line("{")
+				e.line("var (n, t) = m.Tag();")
+				e.line("if (n == 1 && t == %d) k = %s;", field.Key.Kind.WireType(), csReadValue("m", field.Key))
+				e.line("else if (n == 2 && t == %d) x = %s;", field.Value.Kind.WireType(), csReadValue("m", field.Value))
+				e.line("else m.Skip(t);")
+				e.line("}")
+				e.line("%s[k] = x;", expr)
+				e.line("continue;")
+				e.line("}")
+			case field.Repeated:
+				wire := field.Kind.WireType()
+				if wire != 2 { // numeric fields are accepted packed or not
+					e.line("if (wt == 2)")
+					e.line("{")
+					e.line("var p = r.Sub();")
+					e.line("while (!p.Done) %s.Add(%s);", expr, csReadValue("p", &field))
+					e.line("continue;")
+					e.line("}")
+				}
+				e.line("if (wt == %d)", wire)
+				e.line("{")
+				e.line("%s.Add(%s);", expr, csReadValue("r", &field))
+				e.line("continue;")
+				e.line("}")
+			default:
+				e.line("if (wt == %d)", field.Kind.WireType())
+				e.line("{")
+				e.line("%s = %s;", expr, csReadValue("r", &field))
+				e.line("continue;")
+				e.line("}")
+			}
+			e.line("break;")
+		}
+		e.line("}")
+	}
+	e.line("r.Skip(wt);")
+	e.line("}")
+	e.line("return v;")
+	e.line("}")
+}
+
+// csEntryDefault returns the default of a map entry's key or value (where an absent message value is an empty message).
+func csEntryDefault(field *Field) string {
+	switch field.Kind {
+	case KindMessage:
+		return fmt.Sprintf("new %s()", field.Type)
+	case KindString:
+		return `""`
+	case KindBytes:
+		return "Array.Empty<byte>()"
+	}
+	return fmt.Sprintf("default(%s)", csType(field))
+}
+
+func csReadValue(r string, field *Field) string {
+	switch field.Kind {
+	case KindMessage:
+		return fmt.Sprintf("%s.ReadFrom(%s.Sub())", field.Type, r)
+	case KindEnum:
+		return fmt.Sprintf("(%s)%s.Int32()", field.Type, r)
+	}
+	return fmt.Sprintf("%s.%s()", r, csMethod(field.Kind))
+}
+
+// csRuntime is the protobuf wire format support of the generated file.
+const csRuntime = `
+public sealed class ProtoWriter
+{
+	private byte[] buf = new byte[64];
+	private int len;
+
+	public byte[] ToArray() => buf.AsSpan(0, len).ToArray();
+
+	public void Tag(int num, int wireType) => UInt32((uint)(num << 3 | wireType));
+
+	public void UInt32(uint v)
+	{
+		Reserve(5);
+		while (v >= 0x80)
+		{
+			buf[len++] = (byte)(v | 0x80);
+			v >>= 7;
+		}
+		buf[len++] = (byte)v;
+	}
+
+	public void UInt64(ulong v)
+	{
+		Reserve(10);
+		while (v >= 0x80)
+		{
+			buf[len++] = (byte)(v | 0x80);
+			v >>= 7;
+		}
+		buf[len++] = (byte)v;
+	}
+
+	public void Int32(int v)
+	{
+		if (v < 0) UInt64((ulong)(long)v);
+		else UInt32((uint)v);
+	}
+
+	public void Int64(long v) => UInt64((ulong)v);
+	public void SInt32(int v) => UInt32((uint)((v << 1) ^ (v >> 31)));
+	public void SInt64(long v) => UInt64((ulong)((v << 1) ^ (v >> 63)));
+	public void Bool(bool v) => UInt32(v ? 1u : 0u);
+
+	public void Fixed32(uint v)
+	{
+		Reserve(4);
+		BinaryPrimitives.WriteUInt32LittleEndian(buf.AsSpan(len), v);
+		len += 4;
+	}
+
+	public void SFixed32(int v) => Fixed32((uint)v);
+	public void Float(float v) => Fixed32((uint)BitConverter.SingleToInt32Bits(v));
+
+	public void Fixed64(ulong v)
+	{
+		Reserve(8);
+		BinaryPrimitives.WriteUInt64LittleEndian(buf.AsSpan(len), v);
+		len += 8;
+	}
+
+	public void SFixed64(long v) => Fixed64((ulong)v);
+	public void Double(double v) => Fixed64((ulong)BitConverter.DoubleToInt64Bits(v));
+
+	public void Bytes(byte[] v)
+	{
+		UInt32((uint)v.Length);
+		Reserve(v.Length);
+		v.CopyTo(buf, len);
+		len += v.Length;
+	}
+
+	public void String(string v) => Bytes(Encoding.UTF8.GetBytes(v));
+
+	// Message writes the length-delimited output of the given action
+	public void Message(Action<ProtoWriter> write)
+	{
+		var w = new ProtoWriter();
+		write(w);
+		Bytes(w.ToArray());
+	}
+
+	private void Reserve(int n)
+	{
+		if (len + n > buf.Length)
+			Array.Resize(ref buf, Math.Max(2 * buf.Length, len + n));
+	}
+}
+
+public sealed class ProtoReader
+{
+	private readonly byte[] buf;
+	private readonly int end;
+	private int pos;
+
+	public ProtoReader(byte[] buf) : this(buf, 0, buf.Length) { }
+
+	public ProtoReader(byte[] buf, int start, int end)
+	{
+		this.buf = buf;
+		this.pos = start;
+		this.end = end;
+	}
+
+	public bool Done => pos >= end;
+
+	public (int, int) Tag()
+	{
+		var tag = UInt32();
+		return ((int)(tag >> 3), (int)(tag & 7));
+	}
+
+	public ulong UInt64()
+	{
+		ulong v = 0;
+		for (int shift = 0; shift < 70; shift += 7)
+		{
+			var b = buf[Advance(1)];
+			v |= (ulong)(b & 0x7f) << shift;
+			if (b < 0x80) return v;
+		}
+		throw new FormatException("proto: varint overflow");
+	}
+
+	public uint UInt32() => (uint)UInt64();
+	public int Int32() => (int)UInt64();
+	public long Int64() => (long)UInt64();
+
+	public int SInt32()
+	{
+		var v = UInt32();
+		return (int)(v >> 1) ^ -(int)(v & 1);
+	}
+
+	public long SInt64()
+	{
+		var v = UInt64();
+		return (long)(v >> 1) ^ -(long)(v & 1);
+	}
+
+	public bool Bool() => UInt64() != 0;
+	public uint Fixed32() => BinaryPrimitives.ReadUInt32LittleEndian(buf.AsSpan(Advance(4)));
+	public int SFixed32() => (int)Fixed32();
+	public float Float() => BitConverter.Int32BitsToSingle((int)Fixed32());
+	public ulong Fixed64() => BinaryPrimitives.ReadUInt64LittleEndian(buf.AsSpan(Advance(8)));
+	public long SFixed64() => (long)Fixed64();
+	public double Double() => BitConverter.Int64BitsToDouble((long)Fixed64());
+
+	public byte[] Bytes()
+	{
+		var n = (int)UInt32();
+		return buf.AsSpan(Advance(n), n).ToArray();
+	}
+
+	public string String()
+	{
+		var n = (int)UInt32();
+		return Encoding.UTF8.GetString(buf, Advance(n), n);
+	}
+
+	// Sub returns a reader of the next length-delimited value
+	public ProtoReader Sub()
+	{
+		var n = (int)UInt32();
+		var start = Advance(n);
+		return new ProtoReader(buf, start, start + n);
+	}
+
+	public void Skip(int wireType)
+	{
+		switch (wireType)
+		{
+		case 0:
+			UInt64();
+			break;
+		case 1:
+			Advance(8);
+			break;
+		case 2:
+			Advance((int)UInt32());
+			break;
+		case 5:
+			Advance(4);
+			break;
+		default:
+			throw new FormatException("proto: unsupported wire type " + wireType);
+		}
+	}
+
+	// Advance moves past the next n bytes, returning the position of the first
+	private int Advance(int n)
+	{
+		if (n < 0 || pos + n > end) throw new FormatException("proto: unexpected end of buffer");
+		var at = pos;
+		pos += n;
+		return at;
+	}
+}`
@@ -0,0 +1,554 @@
+package codegen
+
+import (
+	"fmt"
+	"io"
+)
+
+// WriteTypeScript writes a self-contained TypeScript module declaring an interface for each of the schema's messages, an enum
+// for each of its enums, and for each message X the functions:
+//
+//	newX(): X                   returns an X whose fields are all default
+//	encodeX(v: X): Uint8Array   returns the protobuf encoding of v
+//	decodeX(buf: Uint8Array): X returns the X encoded in buf (skipping fields it does not know)
+//
+// The module also exports AttrTypes, which maps each attr spec to its AttrType, and attrTypeOf, which returns the AttrType
+// of the attr ID of a TxOp.  64 bit integer fields are represented as bigint and bytes fields as Uint8Array.
+func (schema *Schema) WriteTypeScript(w io.Writer, opts Opts) error {
+	e := &emitter{indent: "  "}
+	e.line("// Code generated by amp/codegen. DO NOT EDIT.")
+	if opts.Header != "" {
+		e.comment("// ", opts.Header)
+	}
+	e.line("")
+	e.lines(tsRuntime)
+
+	for _, enum := range schema.Enums {
+		e.line("")
+		e.line("export enum %s {", enum.Name)
+		for _, val := range enum.Values {
+			e.line("%s = %d,", val.Name, val.Num)
+		}
+		e.line("}")
+	}
+
+	for _, msg := range schema.Messages {
+		e.line("")
+		e.line("export interface %s {", msg.Name)
+		for _, field := range msg.Fields {
+			if field.Kind == KindMessage && !field.Repeated {
+				e.line("%s?: %s;", field.Name, tsType(&field))
+			} else {
+				e.line("%s: %s;", field.Name, tsType(&field))
+			}
+		}
+		e.line("}")
+		schema.tsNew(e, msg)
+		schema.tsWrite(e, msg)
+		schema.tsRead(e, msg)
+	}
+
+	e.line("")
+	e.line("export interface AttrType<T = any> {")
+	e.line("spec: string; // canonic attr spec")
+	e.line("id: string; // attr ID as 48 hex digits (see attrTypeOf)")
+	e.line("elemType: string; // name of T")
+	e.line("encode(v: T): Uint8Array;")
+	e.line("decode(buf: Uint8Array): T;")
+	e.line("}")
+	e.line("")
+	e.line("export const AttrTypes: { [spec: string]: AttrType } = {")
+	for _, attr := range schema.Attrs {
+		e.line("%q: { spec: %q, id: %q, elemType: %q, encode: encode%s, decode: decode%s },",
+			attr.Spec, attr.Spec, attr.ID.Base16(), attr.ElemType, attr.ElemType, attr.ElemType)
+	}
+	e.line("};")
+	e.line("")
+	e.line("const attrTypesByID = new Map<string, AttrType>(Object.values(AttrTypes).map((t) => [t.id, t]));")
+	e.line("")
+	e.line("// attrTypeOf returns the AttrType of the given attr ID (as the three words of TxOp.AttrID), or undefined if unknown.")
+	e.line("export function attrTypeOf(id0: bigint, id1: bigint, id2: bigint): AttrType | undefined {")
+	e.line(`const hex = (w: bigint) => BigInt.asUintN(64, w).toString(16).padStart(16, "0");`)
+	e.line("return attrTypesByID.get(hex(id0) + hex(id1) + hex(id2));")
+	e.line("}")
+
+	_, err := io.WriteString(w, e.buf.String())
+	return err
+}
+
+func tsType(field *Field) string {
+	var typ string
+	switch field.Kind {
+	case KindBool:
+		typ = "boolean"
+	case KindString:
+		typ = "string"
+	case KindBytes:
+		typ = "Uint8Array"
+	case KindEnum, KindMessage:
+		typ = field.Type
+	case KindMap:
+		typ = fmt.Sprintf("Map<%s, %s>", tsType(field.Key), tsType(field.Value))
+	default:
+		if field.Kind.Is64() {
+			typ = "bigint"
+		} else {
+			typ = "number"
+		}
+	}
+	if field.Repeated {
+		typ += "[]"
+	}
+	return typ
+}
+
+// tsDefault returns the default value of the given field, or "" if it is an absent message.
+func (schema *Schema) tsDefault(field *Field) string {
+	switch {
+	case field.Repeated:
+		return "[]"
+	case field.Kind == KindMap:
+		return "new Map()"
+	case field.Kind == KindMessage:
+		return ""
+	case field.Kind == KindBool:
+		return "false"
+	case field.Kind == KindString:
+		return `""`
+	case field.Kind == KindBytes:
+		return "new Uint8Array(0)"
+	case field.Kind == KindEnum:
+		return schema.enumZero(field.Type)
+	case field.Kind.Is64():
+		return "0n"
+	}
+	return "0"
+}
+
+// enumZero returns the TypeScript expression of the named enum's zero value.
+func (schema *Schema) enumZero(name string) string {
+	for _, enum := range schema.Enums {
+		if enum.Name != name {
+			continue
+		}
+		for _, val := range enum.Values {
+			if val.Num == 0 {
+				return name + "." + val.Name
+			}
+		}
+	}
+	return "0 as " + name
+}
+
+// tsNonDefault returns the TypeScript condition that the given expression of the given (non-repeated) field is not its default.
+func tsNonDefault(field *Field, expr string) string {
+	switch {
+	case field.Kind == KindMap:
+		return expr + ".size > 0"
+	case field.Kind == KindMessage:
+		return expr + " !== undefined"
+	case field.Kind == KindBool:
+		return expr
+	case field.Kind == KindString:
+		return expr + ` !== ""`
+	case field.Kind == KindBytes:
+		return expr + ".length > 0"
+	case field.Kind.Is64():
+		return expr + " !== 0n"
+	}
+	return expr + " !== 0"
+}
+
+// tsMethod returns the ProtoWriter / ProtoReader method of the given scalar kind.
+func tsMethod(kind Kind) string {
+	if kind == KindEnum {
+		return "int32"
+	}
+	return kind.String()
+}
+
+func (schema *Schema) tsNew(e *emitter, msg *Message) {
+	e.line("")
+	e.line("export function new%s(): %s {", msg.Name, msg.Name)
+	e.line("return {")
+	for _, field := range msg.Fields {
+		if dflt := schema.tsDefault(&field); dflt != "" {
+			e.line("%s: %s,", field.Name, dflt)
+		}
+	}
+	e.line("};")
+	e.line("}")
+}
+
+func (schema *Schema) tsWrite(e *emitter, msg *Message) {
+	e.line("")
+	e.line("export function encode%s(v: %s): Uint8Array {", msg.Name, msg.Name)
+	e.line("const w = new ProtoWriter();")
+	e.line("write%s(w, v);", msg.Name)
+	e.line("return w.finish();")
+	e.line("}")
+	e.line("")
+	e.line("function write%s(w: ProtoWriter, v: %s): void {", msg.Name, msg.Name)
+	for _, field := range msg.Fields {
+		expr := "v." + field.Name
+		switch {
+		case field.Kind == KindMap:
+			e.line("for (const [k, x] of %s) {", expr)
+			e.line("w.tag(%d, 2);", field.Num)
+			e.line("w.message((e) => {")
+			tsWriteValue(e, "e", field.Key, "k")
+			tsWriteValue(e, "e", field.Value, "x")
+			e.line("});")
+			e.line("}")
+		case field.Packed:
+			e.line("if (%s.length > 0) {", expr)
+			e.line("w.tag(%d, 2);", field.Num)
+			e.line("w.message((p) => %s.forEach((x) => p.%s(x)));", expr, tsMethod(field.Kind))
+			e.line("}")
+		case field.Repeated:
+			e.line("for (const x of %s) {", expr)
+			tsWriteValue(e, "w", &field, "x")
+			e.line("}")
+		default:
+			e.line("if (%s) {", tsNonDefault(&field, expr))
+			tsWriteValue(e, "w", &field, expr)
+			e.line("}")
+		}
+	}
+	e.line("}")
+}
+
+// tsWriteValue emits the writing of the given field's tag and the value of expr via the named writer.
+func tsWriteValue(e *emitter, w string, field *Field, expr string) {
+	e.line("%s.tag(%d, %d);", w, field.Num, field.Kind.WireType())
+	if field.Kind == KindMessage {
+		e.line("%s.message((m) => write%s(m, %s));", w, field.Type, expr)
+	} else {
+		e.line("%s.%s(%s);", w, tsMethod(field.Kind), expr)
+	}
+}
+
+func (schema *Schema) tsRead(e *emitter, msg *Message) {
+	e.line("")
+	e.line("export function decode%s(buf: Uint8Array): %s {", msg.Name, msg.Name)
+	e.line("return read%s(new ProtoReader(buf));", msg.Name)
+	e.line("}")
+	e.line("")
+	e.line("function read%s(r: ProtoReader): %s {", msg.Name, msg.Name)
+	e.line("const v = new%s();", msg.Name)
+	e.line("while (!r.done()) {")
+	e.line("const [num, wt] = r.tag();")
+	if len(msg.Fields) > 0 {
+		e.line("switch (num) {")
+		for _, field := range msg.Fields {
+			expr := "v." + field.Name
+			e.line("case %d:", field.Num)
+			switch {
+			case field.Kind == KindMap:
+				e.line("if (wt === 2) {")
+				e.line("const m = r.sub();")
+				e.line("let k = %s, x = %s;", schema.tsEntryDefault(field.Key), schema.tsEntryDefault(field.Value))
+				e.line("while (!m.done()) {")
+				e.line("const [n, t] = m.tag();")
+				e.line("if (n === 1 && t === %d) k = %s;", field.Key.Kind.WireType(), tsReadValue("m", field.Key))
+				e.line("else if (n === 2 && t === %d) x = %s;", field.Value.Kind.WireType(), tsReadValue("m", field.Value))
+				e.line("else m.skip(t);")
+				e.line("}")
+				e.line("%s.set(k, x);", expr)
+				e.line("continue;")
+				e.line("}")
+			case field.Repeated:
+				wire := field.Kind.WireType()
+				if wire != 2 { // numeric fields are accepted packed or not
+					e.line("if (wt === 2) {")
+					e.line("const p = r.sub();")
+					e.line("while (!p.done()) %s.push(%s);", expr, tsReadValue("p", &field))
+					e.line("continue;")
+					e.line("}")
+				}
+				e.line("if (wt === %d) {", wire)
+				e.line("%s.push(%s);", expr, tsReadValue("r", &field))
+				e.line("continue;")
+				e.line("}")
+			default:
+				e.line("if (wt === %d) {", field.Kind.WireType())
+				e.line("%s = %s;", expr, tsReadValue("r", &field))
+				e.line("continue;")
+				e.line("}")
+			}
+			e.line("break;")
+		}
+		e.line("}")
+	}
+	e.line("r.skip(wt);")
+	e.line("}")
+	e.line("return v;")
+	e.line("}")
+}
+
+// tsEntryDefault returns the default of a map entry's key or value (where an absent message value is an empty message).
+func (schema *Schema) tsEntryDefault(field *Field) string {
+	if field.Kind == KindMessage {
+		return fmt.Sprintf("new%s()", field.Type)
+	}
+	return schema.tsDefault(field)
+}
+
+// tsReadValue returns the expression reading a value of the given field via the named reader.
+func tsReadValue(r string, field *Field) string {
+	switch field.Kind {
+	case KindMessage:
+		return fmt.Sprintf("read%s(%s.sub())", field.Type, r)
+	case KindEnum:
+		return fmt.Sprintf("%s.int32() as %s", r, field.Type)
+	}
+	return fmt.Sprintf("%s.%s()", r, tsMethod(field.Kind))
+}
+
+// tsRuntime is the protobuf wire format support of the generated module.
+const tsRuntime = `
+const textEncoder = new TextEncoder();
+const textDecoder = new TextDecoder();
+
+export class ProtoWriter {
+	private buf = new Uint8Array(64);
+	private view = new DataView(this.buf.buffer);
+	private len = 0;
+
+	finish(): Uint8Array {
+		return this.buf.slice(0, this.len);
+	}
+
+	tag(num: number, wireType: number): void {
+		this.uint32(((num << 3) | wireType) >>> 0);
+	}
+
+	uint32(v: number): void {
+		this.reserve(5);
+		v >>>= 0;
+		while (v >= 0x80) {
+			this.buf[this.len++] = (v & 0x7f) | 0x80;
+			v >>>= 7;
+		}
+		this.buf[this.len++] = v;
+	}
+
+	uint64(v: bigint): void {
+		this.reserve(10);
+		v = BigInt.asUintN(64, v);
+		while (v >= 0x80n) {
+			this.buf[this.len++] = Number(v & 0x7fn) | 0x80;
+			v >>= 7n;
+		}
+		this.buf[this.len++] = Number(v);
+	}
+
+	int32(v: number): void {
+		if (v < 0) this.uint64(BigInt(v));
+		else this.uint32(v);
+	}
+
+	int64(v: bigint): void {
+		this.uint64(v);
+	}
+
+	sint32(v: number): void {
+		this.uint32((v << 1) ^ (v >> 31));
+	}
+
+	sint64(v: bigint): void {
+		v = BigInt.asIntN(64, v);
+		this.uint64((v << 1n) ^ (v >> 63n));
+	}
+
+	bool(v: boolean): void {
+		this.uint32(v ? 1 : 0);
+	}
+
+	fixed32(v: number): void {
+		this.reserve(4);
+		this.view.setUint32(this.len, v, true);
+		this.len += 4;
+	}
+
+	sfixed32(v: number): void {
+		this.reserve(4);
+		this.view.setInt32(this.len, v, true);
+		this.len += 4;
+	}
+
+	float(v: number): void {
+		this.reserve(4);
+		this.view.setFloat32(this.len, v, true);
+		this.len += 4;
+	}
+
+	fixed64(v: bigint): void {
+		this.reserve(8);
+		this.view.setBigUint64(this.len, BigInt.asUintN(64, v), true);
+		this.len += 8;
+	}
+
+	sfixed64(v: bigint): void {
+		this.reserve(8);
+		this.view.setBigInt64(this.len, BigInt.asIntN(64, v), true);
+		this.len += 8;
+	}
+
+	double(v: number): void {
+		this.reserve(8);
+		this.view.setFloat64(this.len, v, true);
+		this.len += 8;
+	}
+
+	bytes(v: Uint8Array): void {
+		this.uint32(v.length);
+		this.reserve(v.length);
+		this.buf.set(v, this.len);
+		this.len += v.length;
+	}
+
+	string(v: string): void {
+		this.bytes(textEncoder.encode(v));
+	}
+
+	// message writes the length-delimited output of the given function
+	message(write: (w: ProtoWriter) => void): void {
+		const w = new ProtoWriter();
+		write(w);
+		this.bytes(w.buf.subarray(0, w.len));
+	}
+
+	private reserve(n: number): void {
+		if (this.len + n <= this.buf.length) return;
+		const buf = new Uint8Array(Math.max(2 * this.buf.length, this.len + n));
+		buf.set(this.buf.subarray(0, this.len));
+		this.buf = buf;
+		this.view = new DataView(buf.buffer);
+	}
+}
+
+export class ProtoReader {
+	private readonly view: DataView;
+	private pos: number;
+
+	constructor(private readonly buf: Uint8Array, start = 0, private readonly end = buf.length) {
+		this.view = new DataView(buf.buffer, buf.byteOffset, buf.byteLength);
+		this.pos = start;
+	}
+
+	done(): boolean {
+		return this.pos >= this.end;
+	}
+
+	tag(): [number, number] {
+		const tag = this.uint32();
+		return [tag >>> 3, tag & 7];
+	}
+
+	uint64(): bigint {
+		let v = 0n;
+		for (let shift = 0n; shift < 70n; shift += 7n) {
+			const b = this.buf[this.advance(1)];
+			v |= BigInt(b & 0x7f) << shift;
+			if (b < 0x80) return BigInt.asUintN(64, v);
+		}
+		throw new Error("proto: varint overflow");
+	}
+
+	uint32(): number {
+		return Number(BigInt.asUintN(32, this.uint64()));
+	}
+
+	int32(): number {
+		return Number(BigInt.asIntN(32, this.uint64()));
+	}
+
+	int64(): bigint {
+		return BigInt.asIntN(64, this.uint64());
+	}
+
+	sint32(): number {
+		const v = this.uint32();
+		return (v >>> 1) ^ -(v & 1);
+	}
+
+	sint64(): bigint {
+		const v = this.uint64();
+		return BigInt.asIntN(64, (v >> 1n) ^ -(v & 1n));
+	}
+
+	bool(): boolean {
+		return this.uint64() !== 0n;
+	}
+
+	fixed32(): number {
+		return this.view.getUint32(this.advance(4), true);
+	}
+
+	sfixed32(): number {
+		return this.view.getInt32(this.advance(4), true);
+	}
+
+	float(): number {
+		return this.view.getFloat32(this.advance(4), true);
+	}
+
+	fixed64(): bigint {
+		return this.view.getBigUint64(this.advance(8), true);
+	}
+
+	sfixed64(): bigint {
+		return this.view.getBigInt64(this.advance(8), true);
+	}
+
+	double(): number {
+		return this.view.getFloat64(this.advance(8), true);
+	}
+
+	bytes(): Uint8Array {
+		const n = this.uint32();
+		const start = this.advance(n);
+		return this.buf.slice(start, start + n);
+	}
+
+	string(): string {
+		const n = this.uint32();
+		const start = this.advance(n);
+		return textDecoder.decode(this.buf.subarray(start, start + n));
+	}
+
+	// sub returns a reader of the next length-delimited value
+	sub(): ProtoReader {
+		const n = this.uint32();
+		const start = this.advance(n);
+		return new ProtoReader(this.buf, start, start + n);
+	}
+
+	skip(wireType: number): void {
+		switch (wireType) {
+		case 0:
+			this.uint64();
+			break;
+		case 1:
+			this.advance(8);
+			break;
+		case 2:
+			this.advance(this.uint32());
+			break;
+		case 5:
+			this.advance(4);
+			break;
+		default:
+			throw new Error("proto: unsupported wire type " + wireType);
+		}
+	}
+
+	// advance moves past the next n bytes, returning the position of the first
+	private advance(n: number): number {
+		const pos = this.pos;
+		if (n < 0 || pos + n > this.end) throw new Error("proto: unexpected end of buffer");
+		this.pos = pos + n;
+		return pos;
+	}
+}`
@@ -1,6 +1,7 @@
 package amp
 
 import (
+	"errors"
 	"fmt"
 )
 
@@ -16,8 +17,14 @@ var (
 	ErrShuttingDown  = ErrCode_ShuttingDown.Error("shutting down")
 	ErrTimeout       = ErrCode_Timeout.Error("timeout")
 	ErrNoAuthToken   = ErrCode_AuthFailed.Error("no auth token")
+	ErrPinIdle       = ErrCode_Timeout.Error("pin idle")
+	ErrSessionDead   = ErrCode_Timeout.Error("session unresponsive")
 )
 
+// RetryAfterDetail is the Err detail holding how long (in milliseconds) a client should wait before issuing a request
+// again, e.g. after ErrCode_RateLimited.
+const RetryAfterDetail = "retry_after_ms"
+
 // Error makes our custom error type conform to a standard Go error
 func (err *Err) Error() string {
 	codeStr, exists := ErrCode_name[int32(err.Code)]
@@ -38,8 +45,9 @@ func (code ErrCode) Error(msg string) error {
 		return nil
 	}
 	return &Err{
-		Code: code,
-		Msg:  msg,
+		Code:      code,
+		Msg:       msg,
+		Retryable: code.Retryable(),
 	}
 }
 
@@ -51,7 +59,8 @@ func (code ErrCode) Errorf(format string, msgArgs ...interface{}) error {
 	}
 
 	err := &Err{
-		Code: code,
+		Code:      code,
+		Retryable: code.Retryable(),
 	}
 	if len(msgArgs) == 0 {
 		err.Msg = format
@@ -67,20 +76,79 @@ func (code ErrCode) Wrap(cause error) error {
 	if cause == nil {
 		return nil
 	}
-	return &Err{
-		Code: code,
-		Msg:  cause.Error(),
+	err := &Err{
+		Code:      code,
+		Msg:       cause.Error(),
+		Retryable: code.Retryable(),
+	}
+	var ampErr *Err
+	if errors.As(cause, &ampErr) {
+		for _, detail := range ampErr.Details {
+			err.Details = append(err.Details, &ErrDetail{Key: detail.Key, Value: detail.Value})
+		}
+	}
+	return err
+}
+
+// Retryable returns true if a request failing with this code may succeed if issued again, which is the default for
+// Err.Retryable of errors formed via Error, Errorf, and Wrap.
+func (code ErrCode) Retryable() bool {
+	switch code {
+	case ErrCode_Timeout, ErrCode_ShuttingDown, ErrCode_NotConnected, ErrCode_RateLimited:
+		return true
 	}
+	return false
 }
 
+// GetErrCode returns the code of the given error (or of the *Err it wraps), ErrCode_UnnamedErr if it has none,
+// or ErrCode_NoErr if it is nil.
 func GetErrCode(err error) ErrCode {
 	if err == nil {
 		return ErrCode_NoErr
 	}
 
-	if arcErr, ok := err.(*Err); ok {
+	var arcErr *Err
+	if errors.As(err, &arcErr) {
 		return arcErr.Code
 	}
 
 	return ErrCode_UnnamedErr
 }
+
+// IsRetryable returns true if the given error (or the *Err it wraps) is marked as retryable (see Err.Retryable).
+func IsRetryable(err error) bool {
+	var arcErr *Err
+	return errors.As(err, &arcErr) && arcErr.Retryable
+}
+
+// Is supports errors.Is, reporting whether target is an *Err having the same Code and either no Msg or the same Msg.
+// So errors.Is(err, &Err{Code: ErrCode_Timeout}) matches any timeout, while errors.Is(err, ErrPinIdle) matches only that one.
+func (err *Err) Is(target error) bool {
+	other, ok := target.(*Err)
+	if !ok || other.Code != err.Code {
+		return false
+	}
+	return other.Msg == "" || other.Msg == err.Msg
+}
+
+// Detail returns the value of the given detail of this error, or false if it has no such detail.
+func (err *Err) Detail(key string) (string, bool) {
+	for _, detail := range err.Details {
+		if detail.Key == key {
+			return detail.Value, true
+		}
+	}
+	return "", false
+}
+
+// WithDetail sets the given detail of this error, replacing any of the same key, and returns this error.
+func (err *Err) WithDetail(key, value string) *Err {
+	for _, detail := range err.Details {
+		if detail.Key == key {
+			detail.Value = value
+			return err
+		}
+	}
+	err.Details = append(err.Details, &ErrDetail{Key: key, Value: value})
+	return err
+}
@@ -0,0 +1,140 @@
+package federation
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/amp-3d/amp-sdk-go/amp"
+	"github.com/amp-3d/amp-sdk-go/amp/auth"
+)
+
+const (
+	DefaultDelegationTTL = 10 * time.Minute
+	DefaultClockSkew     = time.Minute
+	DefaultPeerRole      = "peer"
+)
+
+// Delegation asserts that a host's user is acting through that host on another host (the audience).
+type Delegation struct {
+	Issuer   string `json:"iss"` // name of the host the user is a user of
+	Subject  string `json:"sub"` // the user's UserUID on the issuing host
+	Audience string `json:"aud"` // name of the host the delegation is for
+	Expiry   int64  `json:"exp"` // unix seconds
+}
+
+// UserUID returns the UserUID a delegated session is granted, qualifying the subject by its issuer so users of different peers
+// cannot collide with each other (or with local users).
+func (d *Delegation) UserUID() string {
+	return d.Subject + "@" + d.Issuer
+}
+
+// Delegator issues delegations on behalf of this host's users, signed by this host's key.
+type Delegator struct {
+	Issuer string             // this host's name, as its peers know it (see Trust.Peers)
+	Key    ed25519.PrivateKey // this host's signing key
+	TTL    time.Duration      // how long a delegation is valid (default DefaultDelegationTTL)
+}
+
+// Delegate returns a token delegating the given user to the given peer host (see Trust).
+// The token is of the form {claims}.{signature}, each base64url encoded, where claims is a JSON Delegation.
+func (dr *Delegator) Delegate(user *amp.Identity, audience string) (string, error) {
+	if user == nil || user.UserUID == "" {
+		return "", amp.ErrCode_AuthFailed.Error("federation: no user to delegate")
+	}
+	ttl := dr.TTL
+	if ttl <= 0 {
+		ttl = DefaultDelegationTTL
+	}
+	claims, err := json.Marshal(&Delegation{
+		Issuer:   dr.Issuer,
+		Subject:  user.UserUID,
+		Audience: audience,
+		Expiry:   time.Now().Add(ttl).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+	payload := base64.RawURLEncoding.EncodeToString(claims)
+	sig := ed25519.Sign(dr.Key, []byte(payload))
+	return payload + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+// Trust verifies the delegations peer hosts present in Login.Checkpoint.Token, so a peer's users can be granted access to this host.
+type Trust struct {
+	Audience  string                       // this host's name -- delegations must be issued for it
+	Peers     map[string]ed25519.PublicKey // keys of trusted peers, by name (see Delegator.Issuer)
+	ClockSkew time.Duration                // allowed clock difference with peers (default DefaultClockSkew)
+
+	// Returns the roles granted to a delegated user (e.g. mapping a friend's server to "guest") -- if nil, DefaultPeerRole.
+	Roles func(d *Delegation) []string
+}
+
+var _ auth.Authenticator = (*Trust)(nil)
+
+// Verify returns the delegation of the given token if it was issued by a trusted peer for this host and has not expired.
+func (trust *Trust) Verify(token string) (*Delegation, error) {
+	payload, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, errInvalidDelegation("malformed token")
+	}
+	sigBytes, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil {
+		return nil, errInvalidDelegation("malformed signature")
+	}
+	claims, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return nil, errInvalidDelegation("malformed claims")
+	}
+	d := &Delegation{}
+	if err = json.Unmarshal(claims, d); err != nil {
+		return nil, errInvalidDelegation("malformed claims")
+	}
+
+	skew := trust.ClockSkew
+	if skew <= 0 {
+		skew = DefaultClockSkew
+	}
+	key, trusted := trust.Peers[d.Issuer]
+	switch {
+	case !trusted:
+		return nil, errInvalidDelegation("untrusted issuer %q", d.Issuer)
+	case !ed25519.Verify(key, []byte(payload), sigBytes):
+		return nil, errInvalidDelegation("bad signature")
+	case d.Audience != trust.Audience:
+		return nil, errInvalidDelegation("not issued for this host")
+	case d.Subject == "":
+		return nil, errInvalidDelegation("no subject")
+	case time.Now().After(time.Unix(d.Expiry, 0).Add(skew)):
+		return nil, amp.ErrCode_SessionExpired.Error("delegation expired")
+	}
+	return d, nil
+}
+
+// Authenticate implements auth.Authenticator by verifying the delegation in login.Checkpoint.Token.
+// On success, login.UserUID is set to the delegated identity's UserUID.
+func (trust *Trust) Authenticate(ctx context.Context, login *amp.Login) (*amp.Identity, error) {
+	if login.Checkpoint == nil || login.Checkpoint.Token == "" {
+		return nil, amp.ErrNoAuthToken
+	}
+	d, err := trust.Verify(login.Checkpoint.Token)
+	if err != nil {
+		return nil, err
+	}
+	id := &amp.Identity{
+		UserUID: d.UserUID(),
+		Roles:   []string{DefaultPeerRole},
+	}
+	if trust.Roles != nil {
+		id.Roles = trust.Roles(d)
+	}
+	login.UserUID = id.UserUID
+	return id, nil
+}
+
+func errInvalidDelegation(format string, args ...any) error {
+	return amp.ErrCode_AuthFailed.Errorf("invalid delegation: "+format, args...)
+}
@@ -0,0 +1,360 @@
+// Package federation lets a host mount the spaces of other hosts (peers) under URI prefixes, so that a home server can surface
+// content from a friend's server alongside its own:
+//
+//	app, err := federation.NewApp(&federation.Mount{
+//		Prefix: "amp://alice/music/", // pins of amp://alice/music/{path}...
+//		Remote: "amp://music/",       // ...are proxied as pins of amp://music/{path} on alice's host
+//		Peer:   alice,
+//	})
+//
+// Each session of the mounting host that pins a URL under a mount's Prefix is given its own link to the mount's Peer: a
+// client.Client logged in on behalf of the session's user via a Delegation signed by the mounting host (see Delegator), which
+// the peer verifies on login via a Trust naming the mounting host.  Links are dialed on first use and shared by the session's
+// pins of the same peer and space, closing once the app instance closes.
+//
+// Cell IDs are re-mapped as txs cross a link, so the cells of different peers (or spaces of a peer) cannot collide with each
+// other or with the host's own: each ID a peer pushes is offset by a salt formed from the peer's name and space (see Mount.MapID),
+// and IDs the session sends (the pin target and the ops of a commit) are offset back.
+package federation
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/amp-3d/amp-sdk-go/amp"
+	"github.com/amp-3d/amp-sdk-go/amp/client"
+	"github.com/amp-3d/amp-sdk-go/stdlib/tag"
+	"github.com/amp-3d/amp-sdk-go/stdlib/task"
+)
+
+// AppSpec identifies the federation App.
+var AppSpec = tag.FormSpec(amp.AppSpec, "federation")
+
+// Peer is a remote host whose spaces may be mounted.
+type Peer struct {
+	Name      string                        // the peer's host name, as the Audience of delegations issued for it (see Trust)
+	Dial      func() (amp.Transport, error) // connects to the peer (e.g. via ws.Dial or quic.Dial)
+	Delegator *Delegator                    // issues the delegation each link logs in with on behalf of its session's user
+
+	Reconnect  *amp.ReconnectOpts // if set, a dropped link is redialed and resumed (see client.Opts.Reconnect)
+	SendWindow int64              // if > 0, pins on the peer request this send window (see client.Opts.SendWindow)
+}
+
+// Mount maps a URI prefix on this host to a URI prefix within a peer's space.
+type Mount struct {
+	Prefix string // local URI prefix, e.g. "amp://alice/music/"
+	Remote string // URI prefix on the peer that Prefix maps to, e.g. "amp://music/"
+	Space  string // the space on the peer to log in to (see amp.Login.Space) -- "" for the peer's default
+	Peer   *Peer
+
+	salt tag.ID
+}
+
+// MapID returns the local ID of the given ID pushed by this mount's peer (nil IDs remain nil).
+func (mount *Mount) MapID(remoteID tag.ID) tag.ID {
+	if remoteID.IsNil() {
+		return remoteID
+	}
+	return remoteID.With(mount.salt)
+}
+
+// UnmapID is the inverse of MapID, returning the peer's ID of the given local ID.
+func (mount *Mount) UnmapID(localID tag.ID) tag.ID {
+	if localID.IsNil() {
+		return localID
+	}
+	return localID.Hide(mount.salt)
+}
+
+// RemoteURL returns the peer's URL for the given local URL, or false if it is not under Prefix.
+func (mount *Mount) RemoteURL(localURL string) (string, bool) {
+	if !strings.HasPrefix(localURL, mount.Prefix) {
+		return "", false
+	}
+	return mount.Remote + localURL[len(mount.Prefix):], true
+}
+
+// NewApp returns the federation App, serving pins of URLs under the Prefix of each of the given mounts by proxying them to
+// the mount's peer (see package doc).  The app is invoked by the scheme of each Prefix, or its host if the scheme is "amp".
+// If more than one Prefix matches a URL, the longest is used.
+func NewApp(mounts ...*Mount) (*amp.App, error) {
+	var invocations []string
+	for _, mount := range mounts {
+		if mount.Peer == nil || mount.Peer.Dial == nil || mount.Peer.Delegator == nil {
+			return nil, amp.ErrCode_BadRequest.Errorf("federation: mount %q has no peer to dial", mount.Prefix)
+		}
+		prefix, err := url.Parse(mount.Prefix)
+		if err != nil {
+			return nil, amp.ErrCode_InvalidURI.Errorf("federation: bad mount prefix: %v", err)
+		}
+		invocation := prefix.Scheme
+		if invocation == "amp" || invocation == "" {
+			invocation = prefix.Host
+		}
+		if invocation == "" {
+			return nil, amp.ErrCode_InvalidURI.Errorf("federation: mount prefix %q has no scheme or host", mount.Prefix)
+		}
+		if !containsString(invocations, invocation) {
+			invocations = append(invocations, invocation)
+		}
+		mount.salt = tag.FromString(mount.Peer.Name + "/" + mount.Space)
+	}
+
+	mounts = append([]*Mount(nil), mounts...)
+	sort.SliceStable(mounts, func(i, j int) bool {
+		return len(mounts[i].Prefix) > len(mounts[j].Prefix)
+	})
+	return &amp.App{
+		AppSpec:     AppSpec,
+		Desc:        "surface the spaces of peer hosts",
+		Invocations: invocations,
+		NewAppInstance: func(ctx amp.AppContext) (amp.AppInstance, error) {
+			return &appInst{
+				AppContext: ctx,
+				mounts:     mounts,
+				links:      make(map[linkKey]*link),
+			}, nil
+		},
+	}, nil
+}
+
+func containsString(strs []string, str string) bool {
+	for _, s := range strs {
+		if s == str {
+			return true
+		}
+	}
+	return false
+}
+
+// appInst implements amp.AppInstance by proxying each request to the peer of its mount.
+type appInst struct {
+	amp.AppContext
+	mounts []*Mount // longest Prefix first
+
+	mu     sync.Mutex
+	links  map[linkKey]*link
+	closed bool
+}
+
+type linkKey struct {
+	peer  *Peer
+	space string
+}
+
+// link is a session's client connection to a peer space, dialed on first use.
+type link struct {
+	ready  chan struct{} // closed once dialed
+	client *client.Client
+	err    error
+}
+
+func (inst *appInst) MakeReady(req amp.Requester) error {
+	return nil
+}
+
+func (inst *appInst) ServeRequest(req amp.Requester) (amp.Pin, error) {
+	r := req.Request()
+	if r.PinTarget != nil && r.PinTarget.URL != "" {
+		for _, mount := range inst.mounts {
+			if _, matches := mount.RemoteURL(r.PinTarget.URL); matches {
+				return inst.serve(mount, req)
+			}
+		}
+		return nil, amp.ErrCode_InvalidURI.Errorf("federation: %q is not under a mounted prefix", r.PinTarget.URL)
+	}
+	if len(inst.mounts) != 1 {
+		return nil, amp.ErrCode_BadRequest.Error("federation: pin target needs a URL to select a mount")
+	}
+	return inst.serve(inst.mounts[0], req)
+}
+
+func (inst *appInst) OnClosing() {
+	inst.mu.Lock()
+	links := inst.links
+	inst.links = nil
+	inst.closed = true
+	inst.mu.Unlock()
+
+	for _, link := range links {
+		<-link.ready
+		if link.client != nil {
+			link.client.Close()
+		}
+	}
+}
+
+// getLink returns the session's link to the given mount's peer space, dialing it if needed (or if it has failed).
+func (inst *appInst) getLink(mount *Mount) (*client.Client, error) {
+	key := linkKey{mount.Peer, mount.Space}
+
+	inst.mu.Lock()
+	if inst.closed {
+		inst.mu.Unlock()
+		return nil, amp.ErrShuttingDown
+	}
+	ln := inst.links[key]
+	if ln != nil {
+		select {
+		case <-ln.ready:
+			if ln.err != nil || ln.client.Err() != nil {
+				ln = nil // redial
+			}
+		default:
+		}
+	}
+	dial := ln == nil
+	if dial {
+		ln = &link{ready: make(chan struct{})}
+		inst.links[key] = ln
+	}
+	inst.mu.Unlock()
+
+	if dial {
+		ln.client, ln.err = inst.dial(mount)
+		close(ln.ready)
+	}
+	<-ln.ready
+	return ln.client, ln.err
+}
+
+// dial logs in to the given mount's peer space on behalf of this session's user.
+func (inst *appInst) dial(mount *Mount) (*client.Client, error) {
+	peer := mount.Peer
+	token, err := peer.Delegator.Delegate(amp.IdentityOf(inst.Session()), peer.Name)
+	if err != nil {
+		return nil, err
+	}
+	auth := inst.Session().Auth()
+	c, err := client.Dial(inst, client.Opts{
+		Dial: peer.Dial,
+		Login: amp.Login{
+			UserUID:    auth.UserUID,
+			Space:      mount.Space,
+			Checkpoint: &amp.AuthCheckpoint{Token: token},
+			MediaTypes: auth.MediaTypes,
+			Locales:    auth.Locales,
+		},
+		Reconnect:  peer.Reconnect,
+		Registry:   inst.Session(),
+		SendWindow: peer.SendWindow,
+	})
+	if err != nil {
+		if _, isErr := err.(*amp.Err); isErr {
+			return nil, err // e.g. the peer rejected the delegation
+		}
+		return nil, amp.ErrCode_ProviderErr.Errorf("federation: failed to link to %q: %v", peer.Name, err)
+	}
+	return c, nil
+}
+
+// serve proxies the given request to the given mount's peer.
+func (inst *appInst) serve(mount *Mount, req amp.Requester) (amp.Pin, error) {
+	r := req.Request()
+	pinReq := r.PinRequest
+	if r.PinTarget != nil {
+		target := *r.PinTarget
+		if target.URL != "" {
+			remoteURL, matches := mount.RemoteURL(target.URL)
+			if !matches {
+				return nil, amp.ErrCode_InvalidURI.Errorf("federation: %q is not under %q", target.URL, mount.Prefix)
+			}
+			target.URL = remoteURL
+		}
+		target.SetTagID(mount.UnmapID(target.TagID()))
+		pinReq.PinTarget = &target
+	}
+
+	var commitTx *amp.TxMsg
+	if r.CommitTx != nil {
+		commitTx = r.CommitTx.Derive()
+		for _, op := range r.CommitTx.Ops {
+			op.FromID = mount.UnmapID(op.FromID)
+			op.TargetID = mount.UnmapID(op.TargetID)
+			commitTx.CarryOp(&op)
+		}
+		defer commitTx.ReleaseRef()
+	}
+
+	c, err := inst.getLink(mount)
+	if err != nil {
+		return nil, err
+	}
+	remote, err := c.Commit(pinReq, commitTx)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &pin{
+		inst:   inst,
+		mount:  mount,
+		req:    req,
+		remote: remote,
+	}
+	p.ctx, err = inst.StartChild(&task.Task{
+		Label: "federated pin",
+		OnClosing: func() {
+			p.remote.Close()
+		},
+	})
+	if err != nil {
+		remote.Close()
+		return nil, err
+	}
+	go p.relay()
+	return p, nil
+}
+
+// pin implements amp.Pin for a request proxied to a peer.
+type pin struct {
+	inst   *appInst
+	mount  *Mount
+	req    amp.Requester
+	remote *client.Pin
+	ctx    task.Context
+}
+
+func (p *pin) Context() task.Context {
+	return p.ctx
+}
+
+// ServeRequest proxies a request made of this pin to the same peer.
+func (p *pin) ServeRequest(req amp.Requester) (amp.Pin, error) {
+	return p.inst.serve(p.mount, req)
+}
+
+// relay pushes each update the peer sends for this pin to its requester, re-mapping cell IDs, until the peer completes it.
+func (p *pin) relay() {
+	refused := false
+	for update := range p.remote.Updates() {
+		if refused {
+			continue // drain until the peer confirms the close
+		}
+		if err := p.req.PushTx(p.mount.marshalUpdate(update)); err != nil {
+			refused = true
+			p.remote.Close()
+		}
+	}
+	p.req.OnComplete(p.remote.Err())
+	p.ctx.Close()
+}
+
+// marshalUpdate returns a tx of the given update from this mount's peer, re-mapping its IDs (see MapID).
+func (mount *Mount) marshalUpdate(update *client.Update) *amp.TxMsg {
+	tx := amp.NewTxMsg(true)
+	tx.Status = update.Status
+	for _, op := range update.Ops {
+		tx.MarshalOpWithBuf(&amp.TxOp{
+			OpCode:   op.OpCode,
+			FromID:   mount.MapID(op.FromID),
+			TargetID: mount.MapID(op.TargetID),
+			AttrID:   op.AttrID,
+			SI:       op.SI,
+			Height:   op.Height,
+			Hash:     op.Hash,
+		}, op.Raw)
+	}
+	return tx
+}
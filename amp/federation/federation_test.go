@@ -0,0 +1,300 @@
+package federation_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/amp-3d/amp-sdk-go/amp"
+	"github.com/amp-3d/amp-sdk-go/amp/amptest"
+	"github.com/amp-3d/amp-sdk-go/amp/federation"
+	"github.com/amp-3d/amp-sdk-go/stdlib/tag"
+)
+
+// pipeTransport is one end of an in-memory Transport pair.
+type pipeTransport struct {
+	send      chan<- []byte
+	recv      <-chan []byte
+	closing   chan struct{}
+	closeOnce *sync.Once
+}
+
+func newPipe() (client, host *pipeTransport) {
+	toHost, toClient := make(chan []byte, 64), make(chan []byte, 64)
+	closing, once := make(chan struct{}), &sync.Once{}
+	client = &pipeTransport{send: toHost, recv: toClient, closing: closing, closeOnce: once}
+	host = &pipeTransport{send: toClient, recv: toHost, closing: closing, closeOnce: once}
+	return client, host
+}
+
+func (tr *pipeTransport) Label() string { return "pipe" }
+
+func (tr *pipeTransport) Close() error {
+	tr.closeOnce.Do(func() { close(tr.closing) })
+	return nil
+}
+
+func (tr *pipeTransport) SendTx(tx *amp.TxMsg) error {
+	var buf []byte
+	tx.MarshalToBuffer(&buf)
+	tx.ReleaseRef()
+	select {
+	case tr.send <- buf:
+		return nil
+	case <-tr.closing:
+		return amp.ErrStreamClosed
+	}
+}
+
+func (tr *pipeTransport) RecvTx() (*amp.TxMsg, error) {
+	select {
+	case buf := <-tr.recv:
+		return amp.ReadTxMsg(bytes.NewReader(buf))
+	case <-tr.closing:
+		return nil, amp.ErrStreamClosed
+	}
+}
+
+func sendMeta(tr amp.Transport, val amp.ElemVal) {
+	tx, _ := amp.MarshalMetaAttr(tag.FormSpec(amp.MetaAttrSpec, val.ElemTypeName()).ID, val)
+	tr.SendTx(tx)
+}
+
+// remoteHost is a peer that admits logins delegated by a trusted host, then serves each pin request with a cell whose label
+// is the pin URL.
+type remoteHost struct {
+	trust   *federation.Trust
+	cellID  tag.ID
+	mu      sync.Mutex
+	dials   int
+	logins  []amp.Login
+	targets []tag.ID // PinTarget ID of each request
+	commits []tag.ID // TargetID of each committed op
+}
+
+func (host *remoteHost) dial() (amp.Transport, error) {
+	clientTr, hostTr := newPipe()
+	host.mu.Lock()
+	host.dials++
+	host.mu.Unlock()
+	go host.serve(hostTr)
+	return clientTr, nil
+}
+
+func (host *remoteHost) serve(tr *pipeTransport) {
+	for {
+		tx, err := tr.RecvTx()
+		if err != nil {
+			return
+		}
+		reqID := tx.RequestID()
+		if tx.Status == amp.OpStatus_Closed {
+			closed := amp.NewTxMsg(true)
+			closed.SetRequestID(reqID)
+			closed.Status = amp.OpStatus_Closed
+			tr.SendTx(closed)
+			continue
+		}
+		if len(tx.Ops) == 0 || tx.Ops[0].OpCode != amp.TxOpCode_MetaAttr {
+			continue
+		}
+		switch tx.Ops[0].AttrID {
+		case tag.FormSpec(amp.MetaAttrSpec, "Login").ID:
+			login := &amp.Login{}
+			tx.UnmarshalOpValue(0, login)
+			if _, err := host.trust.Authenticate(context.Background(), login); err != nil {
+				sendMeta(tr, err.(*amp.Err))
+				continue
+			}
+			host.mu.Lock()
+			host.logins = append(host.logins, *login)
+			host.mu.Unlock()
+			sendMeta(tr, &amp.AuthCheckpoint{Token: "session"})
+
+		case amp.PinRequestSpec.ID:
+			pinReq, commitTx, _ := amp.ParsePinRequest(tx)
+			host.mu.Lock()
+			host.targets = append(host.targets, pinReq.PinTarget.TagID())
+			if commitTx != nil {
+				for _, op := range commitTx.Ops {
+					host.commits = append(host.commits, op.TargetID)
+				}
+			}
+			host.mu.Unlock()
+
+			reply := amp.NewTxMsg(true)
+			reply.SetRequestID(reqID)
+			reply.Status = amp.OpStatus_Synced
+			reply.MarshalUpsert(host.cellID, amp.PinnedTabSpec.ID, &amp.TagTab{Label: pinReq.PinTarget.URL})
+			tr.SendTx(reply)
+		}
+	}
+}
+
+func TestFederation(t *testing.T) {
+	homePub, homeKey, _ := ed25519.GenerateKey(nil)
+	host := &remoteHost{
+		cellID: tag.New(),
+		trust: &federation.Trust{
+			Audience: "alice",
+			Peers:    map[string]ed25519.PublicKey{"home": homePub},
+		},
+	}
+	mount := &federation.Mount{
+		Prefix: "amp://alice/music/",
+		Remote: "amp://music/",
+		Space:  "shared",
+		Peer: &federation.Peer{
+			Name:      "alice",
+			Dial:      host.dial,
+			Delegator: &federation.Delegator{Issuer: "home", Key: homeKey},
+		},
+	}
+	app, err := federation.NewApp(mount)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(app.Invocations) != 1 || app.Invocations[0] != "alice" {
+		t.Fatalf("expected invocation alice, got %v", app.Invocations)
+	}
+
+	sess := amptest.NewSession(t, app)
+	sess.User = amp.Identity{UserUID: "bob"}
+
+	// pins are proxied with rewritten URLs, and the peer's cell IDs are re-mapped
+	req := sess.PinURL("amp://alice/music/tracks?sort=name")
+	req.WaitForStatus(amp.OpStatus_Synced)
+	localID := mount.MapID(host.cellID)
+	if localID == host.cellID || mount.UnmapID(localID) != host.cellID {
+		t.Fatalf("cell ID not re-mapped: %v", localID)
+	}
+	tab := &amp.TagTab{}
+	req.RequireAttr(localID, amp.PinnedTabSpec.ID, tab)
+	if tab.Label != "amp://music/tracks?sort=name" {
+		t.Fatalf("expected the remote URL, got %q", tab.Label)
+	}
+
+	host.mu.Lock()
+	if len(host.logins) != 1 || host.logins[0].UserUID != "bob@home" || host.logins[0].Space != "shared" {
+		t.Fatalf("expected a delegated login of bob to the shared space, got %+v", host.logins)
+	}
+	host.mu.Unlock()
+
+	// a pin target ID and committed ops are mapped back to the peer's IDs, sharing the session's link
+	pinReq := amp.PinRequest{PinTarget: &amp.Tag{URL: "amp://alice/music/tracks"}}
+	pinReq.PinTarget.SetTagID(localID)
+	commitTx := amp.NewTxMsg(true)
+	commitTx.MarshalUpsert(localID, amp.PinnedTabSpec.ID, &amp.TagTab{Label: "renamed"})
+	committed, err := sess.TryCommit(pinReq, commitTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	committed.WaitForStatus(amp.OpStatus_Synced)
+
+	host.mu.Lock()
+	if host.dials != 1 {
+		t.Fatalf("expected pins to share a link, got %d dials", host.dials)
+	}
+	if host.targets[1] != host.cellID || len(host.commits) != 1 || host.commits[0] != host.cellID {
+		t.Fatalf("expected IDs mapped back to %v, got targets %v, commits %v", host.cellID, host.targets, host.commits)
+	}
+	host.mu.Unlock()
+
+	// closing a pin closes it on the peer
+	req.Close()
+	if err := req.Wait(); err != nil {
+		t.Fatal(err)
+	}
+
+	// URLs outside any mount are refused
+	if _, err := sess.TryPin(amp.PinRequest{PinTarget: &amp.Tag{URL: "amp://alice/photos/"}}); err == nil {
+		t.Fatal("expected a pin outside the mount to fail")
+	}
+}
+
+func TestFederationUntrusted(t *testing.T) {
+	_, homeKey, _ := ed25519.GenerateKey(nil)
+	otherPub, _, _ := ed25519.GenerateKey(nil)
+	host := &remoteHost{
+		cellID: tag.New(),
+		trust: &federation.Trust{
+			Audience: "alice",
+			Peers:    map[string]ed25519.PublicKey{"home": otherPub},
+		},
+	}
+	app, err := federation.NewApp(&federation.Mount{
+		Prefix: "alice://",
+		Remote: "amp://",
+		Peer: &federation.Peer{
+			Name:      "alice",
+			Dial:      host.dial,
+			Delegator: &federation.Delegator{Issuer: "home", Key: homeKey},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sess := amptest.NewSession(t, app)
+	_, err = sess.TryPin(amp.PinRequest{PinTarget: &amp.Tag{URL: "alice://music"}})
+	if ampErr, _ := err.(*amp.Err); ampErr == nil || ampErr.Code != amp.ErrCode_AuthFailed {
+		t.Fatalf("expected ErrCode_AuthFailed, got %v", err)
+	}
+}
+
+func TestDelegation(t *testing.T) {
+	homePub, homeKey, _ := ed25519.GenerateKey(nil)
+	trust := &federation.Trust{
+		Audience: "alice",
+		Peers:    map[string]ed25519.PublicKey{"home": homePub},
+		Roles: func(d *federation.Delegation) []string {
+			return []string{"guest"}
+		},
+	}
+	delegator := &federation.Delegator{Issuer: "home", Key: homeKey}
+
+	token, err := delegator.Delegate(&amp.Identity{UserUID: "bob"}, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	login := &amp.Login{Checkpoint: &amp.AuthCheckpoint{Token: token}}
+	id, err := trust.Authenticate(context.Background(), login)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id.UserUID != "bob@home" || login.UserUID != "bob@home" || len(id.Roles) != 1 || id.Roles[0] != "guest" {
+		t.Fatalf("unexpected identity %+v", id)
+	}
+
+	expectCode := func(token string, code amp.ErrCode) {
+		t.Helper()
+		_, err := trust.Verify(token)
+		if ampErr, _ := err.(*amp.Err); ampErr == nil || ampErr.Code != code {
+			t.Fatalf("expected %v, got %v", code, err)
+		}
+	}
+
+	// issued for another host
+	other, _ := delegator.Delegate(&amp.Identity{UserUID: "bob"}, "carol")
+	expectCode(other, amp.ErrCode_AuthFailed)
+
+	// tampered claims
+	payload, sig, _ := strings.Cut(token, ".")
+	expectCode(payload[:len(payload)-2]+"x."+sig, amp.ErrCode_AuthFailed)
+	expectCode("garbage", amp.ErrCode_AuthFailed)
+
+	// expired, beyond the allowed clock skew
+	expired := &federation.Delegator{Issuer: "home", Key: homeKey, TTL: time.Nanosecond}
+	stale, _ := expired.Delegate(&amp.Identity{UserUID: "bob"}, "alice")
+	trust.ClockSkew = time.Nanosecond
+	time.Sleep(time.Second)
+	expectCode(stale, amp.ErrCode_SessionExpired)
+
+	if _, err := trust.Authenticate(context.Background(), &amp.Login{}); err != amp.ErrNoAuthToken {
+		t.Fatalf("expected ErrNoAuthToken, got %v", err)
+	}
+}
@@ -0,0 +1,283 @@
+package filesys
+
+import (
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/amp-3d/amp-sdk-go/amp"
+	"github.com/amp-3d/amp-sdk-go/amp/basic"
+	"github.com/amp-3d/amp-sdk-go/stdlib/fswatch"
+	"github.com/amp-3d/amp-sdk-go/stdlib/tag"
+	"github.com/amp-3d/amp-sdk-go/stdlib/task"
+	"github.com/amp-3d/amp-sdk-go/stdlib/utils"
+)
+
+// AppSpec identifies the filesystem App.
+var AppSpec = tag.FormSpec(amp.AppSpec, "os.filesys")
+
+// Opts configures the filesystem App.
+type Opts struct {
+
+	// The directory served as file:/// (default "/").  Pinned paths cannot refer outside of it.
+	Root string
+
+	// How many levels of subdirectories below a pinned directory are also watched (default 0, only the pinned directory).
+	// Watching the level below keeps the ModifiedAt of each subdirectory current as its entries change.
+	WatchDepth int
+
+	// How long changes are collected before a maintained pin pushes them (default fswatch.DefaultDebounce).
+	Debounce time.Duration
+
+	// The limit on directories watched at once across all pins (default fswatch.DefaultMaxWatches).
+	MaxWatches int
+
+	// If set, watched directories are polled rather than watched natively (e.g. for network filesystems).
+	Poll bool
+}
+
+// NewApp returns the filesystem App, serving pins of URLs of the form:
+//
+//	file:///{path}
+//
+// Pinning a directory pushes a window of its entries as child cells (see basic.PagedCell), sorted by name.  Each child's
+// TagTab has the entry's name as its Label and its modification time, and a directory's tab has a TagUse_Pinnable tag
+// that is the URL that pins it.  A child's cell ID is formed from its path, so the same file has the same ID across pins.
+//
+// A cue sheet (see package media/cue) is also pinnable, pushing a child cell for each of its tracks having the track's
+// amp.MediaInfo, its amp.TrackOffset within the file holding it, and a TagUse_Stream amp.ContentSpec tag for that file.
+//
+// A maintained pin of a directory watches it (see package fswatch) to WatchDepth, pushing its window again as entries are
+// created, written, and removed, where a removed entry is pushed as a DeleteCell op.  If the watch limit (MaxWatches) has
+// been reached, a maintained pin fails with ErrCode_PinFailed.
+func NewApp(opts Opts) *amp.App {
+	if opts.Root == "" {
+		opts.Root = "/"
+	}
+	return &amp.App{
+		AppSpec:     AppSpec,
+		Desc:        "browse and watch local files",
+		Invocations: []string{"file"},
+		NewAppInstance: func(ctx amp.AppContext) (amp.AppInstance, error) {
+			root, err := filepath.Abs(opts.Root)
+			if err != nil {
+				return nil, err
+			}
+			watcher, err := fswatch.New(fswatch.Opts{
+				Debounce:   opts.Debounce,
+				MaxWatches: opts.MaxWatches,
+				Poll:       opts.Poll,
+			})
+			if err != nil {
+				return nil, err
+			}
+			app := &appInst{
+				opts:    opts,
+				root:    root,
+				watcher: watcher,
+			}
+			app.AppContext = ctx
+			app.Instance = app
+			return app, nil
+		},
+	}
+}
+
+type appInst struct {
+	basic.App[*appInst]
+	opts    Opts
+	root    string           // absolute OS path of Opts.Root
+	watcher *fswatch.Watcher // shared by all maintained pins
+}
+
+func (app *appInst) ServeRequest(req amp.Requester) (amp.Pin, error) {
+	r := req.Request()
+	if r.URL == nil {
+		return nil, amp.ErrCode_BadRequest.Error("missing pin URL")
+	}
+	if r.URL.Host != "" && r.URL.Host != "localhost" {
+		return nil, amp.ErrCode_InvalidURI.Errorf("unsupported file host %q", r.URL.Host)
+	}
+
+	relPath := path.Clean("/" + r.URL.Path) // so that ".." cannot escape the root
+	osPath := filepath.Join(app.root, filepath.FromSlash(relPath))
+	fi, err := os.Stat(osPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, amp.ErrCellNotFound
+		}
+		return nil, amp.ErrCode_DataFailure.Wrap(err)
+	}
+	if isCueSheet(fi) {
+		return app.PinAndServe(app.newSheet(relPath, osPath, fi), req)
+	}
+	if !fi.IsDir() {
+		return nil, amp.ErrCode_BadRequest.Errorf("%q is not a directory or cue sheet", relPath)
+	}
+
+	dir := app.newDir(relPath, osPath, fi)
+	if r.PinSync != amp.PinSync_Maintain {
+		return app.PinAndServe(dir, req)
+	}
+
+	// Begin watching before the first listing so that no change is missed
+	changed := make(chan struct{}, 1)
+	watch, err := app.watcher.Watch(osPath, app.opts.WatchDepth, func([]fswatch.Event) {
+		select {
+		case changed <- struct{}{}:
+		default:
+		}
+	})
+	if err == fswatch.ErrTooManyWatches {
+		return nil, amp.ErrCode_PinFailed.Errorf("%q: %v", relPath, err)
+	} else if err != nil {
+		return nil, amp.ErrCode_DataFailure.Wrap(err)
+	}
+	pin, err := app.PinAndServe(dir, req)
+	if err != nil {
+		watch.Close()
+		return nil, err
+	}
+	go dir.watch(pin.Context(), watch, changed)
+	return pin, nil
+}
+
+func (app *appInst) OnClosing() {
+	app.watcher.Close()
+}
+
+// newEntry returns the cell for the directory entry at the given path (relative to the root, in slash form).
+func (app *appInst) newEntry(relPath, osPath string, fi os.FileInfo) basic.Cell[*appInst] {
+	if fi.IsDir() {
+		return app.newDir(relPath, osPath, fi)
+	}
+	if isCueSheet(fi) {
+		return app.newSheet(relPath, osPath, fi)
+	}
+	file := &entryCell{}
+	file.init(relPath, fi)
+	return file
+}
+
+func (app *appInst) newDir(relPath, osPath string, fi os.FileInfo) *dirCell {
+	dir := &dirCell{
+		app:    app,
+		osPath: osPath,
+	}
+	dir.init(relPath, fi)
+	dir.Tab.Tags = append(dir.Tab.Tags, &amp.Tag{
+		Use: amp.TagUse_Pinnable,
+		URL: fileURL(relPath),
+	})
+	return dir
+}
+
+// fileURL returns the URL of the given path (relative to the root, in slash form).
+func fileURL(relPath string) string {
+	return (&url.URL{Scheme: "file", Path: relPath}).String()
+}
+
+// relPath returns the path (relative to the root, in slash form) of the given OS path, returning false if it is outside the root.
+func (app *appInst) relPath(osPath string) (string, bool) {
+	rel, err := filepath.Rel(app.root, osPath)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return path.Clean("/" + filepath.ToSlash(rel)), true
+}
+
+// entryCell is a file or directory.
+type entryCell struct {
+	basic.CellInfo[*appInst]
+	relPath string
+}
+
+func (cell *entryCell) init(relPath string, fi os.FileInfo) {
+	cell.relPath = relPath
+	cell.ID = tag.FromToken("file:" + relPath)
+	cell.Tab.Label = fi.Name()
+	if relPath == "/" {
+		cell.Tab.Label = "/"
+	}
+	if !fi.IsDir() {
+		cell.Tab.Caption = utils.FileSize(fi.Size()).String()
+	}
+	cell.Tab.SetModifiedAt(fi.ModTime())
+}
+
+func (cell *entryCell) PinInto(dst *basic.Pinned[*appInst]) error {
+	return nil
+}
+
+// dirCell is a directory, enumerating its entries as a basic.PagedCell.
+type dirCell struct {
+	entryCell
+	app    *appInst
+	osPath string
+
+	mu      sync.Mutex
+	entries []basic.Cell[*appInst] // nil until listed
+}
+
+func (dir *dirCell) ChildCount() int64 {
+	entries, err := dir.list()
+	if err != nil {
+		return -1
+	}
+	return int64(len(entries))
+}
+
+func (dir *dirCell) ChildrenAt(offset, limit int64) ([]basic.Cell[*appInst], error) {
+	entries, err := dir.list()
+	if err != nil {
+		return nil, err
+	}
+	if offset >= int64(len(entries)) {
+		return nil, nil
+	}
+	return entries[offset:min(offset+limit, int64(len(entries)))], nil
+}
+
+// list returns the directory's entries, reading them unless already read since the last change.
+func (dir *dirCell) list() ([]basic.Cell[*appInst], error) {
+	dir.mu.Lock()
+	defer dir.mu.Unlock()
+	if dir.entries != nil {
+		return dir.entries, nil
+	}
+
+	listing, err := os.ReadDir(dir.osPath)
+	if err != nil {
+		return nil, amp.ErrCode_DataFailure.Wrap(err)
+	}
+	entries := make([]basic.Cell[*appInst], 0, len(listing))
+	for _, ent := range listing {
+		fi, err := ent.Info()
+		if err != nil {
+			continue // removed since listed
+		}
+		entries = append(entries, dir.app.newEntry(path.Join(dir.relPath, ent.Name()), filepath.Join(dir.osPath, ent.Name()), fi))
+	}
+	dir.entries = entries
+	return entries, nil
+}
+
+// watch lists the directory again each time it changes, until ctx closes.
+func (dir *dirCell) watch(ctx task.Context, watch *fswatch.Watch, changed <-chan struct{}) {
+	defer watch.Close()
+	for {
+		select {
+		case <-changed:
+			dir.mu.Lock()
+			dir.entries = nil
+			dir.mu.Unlock()
+			dir.Pinned.Invalidate()
+		case <-ctx.Closing():
+			return
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package filesys
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/amp-3d/amp-sdk-go/amp"
+	"github.com/amp-3d/amp-sdk-go/amp/basic"
+	"github.com/amp-3d/amp-sdk-go/stdlib/media/cue"
+	"github.com/amp-3d/amp-sdk-go/stdlib/media/metadata"
+	"github.com/amp-3d/amp-sdk-go/stdlib/tag"
+)
+
+func isCueSheet(fi os.FileInfo) bool {
+	return !fi.IsDir() && strings.EqualFold(filepath.Ext(fi.Name()), cue.Ext)
+}
+
+func (app *appInst) newSheet(relPath, osPath string, fi os.FileInfo) *sheetCell {
+	sheet := &sheetCell{
+		app:    app,
+		osPath: osPath,
+	}
+	sheet.init(relPath, fi)
+	sheet.Tab.Tags = append(sheet.Tab.Tags, &amp.Tag{
+		Use: amp.TagUse_Pinnable,
+		URL: fileURL(relPath),
+	})
+	return sheet
+}
+
+// sheetCell is a cue sheet, whose children are its tracks.
+type sheetCell struct {
+	entryCell
+	app    *appInst
+	osPath string
+}
+
+func (cell *sheetCell) PinInto(dst *basic.Pinned[*appInst]) error {
+	sheet, err := cue.ReadFile(cell.osPath)
+	if err != nil {
+		return amp.ErrCode_DataFailure.Wrap(err)
+	}
+	if sheet.Title != "" {
+		cell.Tab.Label = sheet.Title
+		cell.Tab.Caption = sheet.Performer
+	}
+
+	// The metadata of each file holding tracks, read once for its duration, stream properties, and artwork
+	files := make(map[string]*metadata.Info)
+	fileInfo := func(name string) *metadata.Info {
+		osPath := (&cue.Split{File: name}).Path(cell.osPath)
+		info, read := files[osPath]
+		if !read {
+			if _, within := cell.app.relPath(osPath); within {
+				info, _ = metadata.ExtractFile(osPath, metadata.Opts{})
+			}
+			files[osPath] = info
+		}
+		return info
+	}
+	splits := sheet.Splits(func(name string) time.Duration {
+		if info := fileInfo(name); info != nil {
+			return info.Duration
+		}
+		return 0
+	})
+
+	for i := range splits {
+		split := &splits[i]
+		osPath := split.Path(cell.osPath)
+		filePath, within := cell.app.relPath(osPath)
+		if !within {
+			continue // the sheet refers outside the root
+		}
+		info := split.Info(fileInfo(split.File))
+		track := &trackCell{
+			info:   amp.NewMediaInfo(info),
+			offset: amp.NewTrackOffset(split),
+			stream: &amp.Tag{
+				Use:         amp.TagUse_Stream,
+				URL:         fileURL(filePath),
+				ContentType: info.ContentType,
+			},
+		}
+		track.ID = tag.FromToken("file:" + cell.relPath + "#" + strconv.Itoa(split.Track.Number))
+		track.Tab = amp.MediaTab(info, filepath.Base(osPath))
+		dst.AddChild(track)
+	}
+	return nil
+}
+
+// trackCell is a track of a cue sheet, located within the file holding it.
+type trackCell struct {
+	basic.CellInfo[*appInst]
+	info   *amp.MediaInfo
+	offset *amp.TrackOffset
+	stream *amp.Tag
+}
+
+func (track *trackCell) PinInto(dst *basic.Pinned[*appInst]) error {
+	return nil
+}
+
+func (track *trackCell) MarshalAttrs(pin *basic.Pin[*appInst]) {
+	track.CellInfo.MarshalAttrs(pin)
+	pin.Upsert(track.ID, amp.MediaInfoSpec.ID, tag.Nil, track.info)
+	pin.Upsert(track.ID, amp.TrackOffsetSpec.ID, tag.Nil, track.offset)
+	pin.Upsert(track.ID, amp.ContentSpec.ID, tag.Nil, track.stream)
+}
@@ -0,0 +1,231 @@
+package filesys_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/amp-3d/amp-sdk-go/amp"
+	"github.com/amp-3d/amp-sdk-go/amp/amptest"
+	"github.com/amp-3d/amp-sdk-go/amp/filesys"
+	"github.com/amp-3d/amp-sdk-go/stdlib/tag"
+)
+
+// listing is the state of a directory as of the last tx pushed for a request.
+type listing struct {
+	children map[string]amp.TagTab // by label
+	ids      map[string]tag.ID     // by label
+	deleted  map[tag.ID]bool
+}
+
+func lastListing(t *testing.T, req *amptest.Request) listing {
+	txs := req.Txs()
+	ls := listing{
+		children: make(map[string]amp.TagTab),
+		ids:      make(map[string]tag.ID),
+		deleted:  make(map[tag.ID]bool),
+	}
+	if len(txs) == 0 {
+		return ls
+	}
+	tx := txs[len(txs)-1]
+	for i, op := range tx.Ops {
+		switch {
+		case op.OpCode == amp.TxOpCode_DeleteCell:
+			ls.deleted[op.TargetID] = true
+		case op.OpCode == amp.TxOpCode_UpsertAttr && op.AttrID == amp.ChildTabSpec.ID:
+			var tab amp.TagTab
+			if err := tx.UnmarshalOpValue(i, &tab); err != nil {
+				t.Fatal(err)
+			}
+			ls.children[tab.Label] = tab
+			ls.ids[tab.Label] = op.TargetID
+		}
+	}
+	return ls
+}
+
+// waitForListing waits until the last tx pushed for the request satisfies the given condition.
+func waitForListing(t *testing.T, req *amptest.Request, desc string, cond func(ls listing) bool) listing {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		ls := lastListing(t, req)
+		if cond(ls) {
+			return ls
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s (last listing %v)", desc, ls.children)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestFilesys(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "a.txt"), []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(root, "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	sess := amptest.NewSession(t, filesys.NewApp(filesys.Opts{
+		Root:       root,
+		Debounce:   10 * time.Millisecond,
+		MaxWatches: 2,
+	}))
+
+	// A one-shot pin lists the directory
+	req := sess.PinURL("file:///")
+	req.WaitForStatus(amp.OpStatus_Synced)
+	ls := lastListing(t, req)
+	if len(ls.children) != 2 || ls.children["a.txt"].Caption != "5b" {
+		t.Fatalf("unexpected listing %v", ls.children)
+	}
+	sub := ls.children["sub"]
+	if len(sub.Tags) != 1 || sub.Tags[0].Use != amp.TagUse_Pinnable || sub.Tags[0].URL != "file:///sub" {
+		t.Fatalf("unexpected directory tab %+v", sub)
+	}
+	fileID := ls.ids["a.txt"]
+
+	// Paths are confined to the root
+	escape := sess.PinURL("file:///sub/../../..")
+	escape.WaitForStatus(amp.OpStatus_Synced)
+	if ls := lastListing(t, escape); ls.ids["a.txt"] != fileID {
+		t.Fatalf("expected root listing, got %v", ls.children)
+	}
+	if _, err := sess.TryPin(amp.PinRequest{PinTarget: &amp.Tag{URL: "file:///missing"}}); err == nil {
+		t.Fatal("expected missing directory to fail")
+	}
+	if _, err := sess.TryPin(amp.PinRequest{PinTarget: &amp.Tag{URL: "file:///a.txt"}}); err == nil {
+		t.Fatal("expected file to fail")
+	}
+
+	// A maintained pin pushes its listing again as the directory changes
+	live := sess.Pin(amp.PinRequest{
+		PinTarget: &amp.Tag{URL: "file:///"},
+		PinSync:   amp.PinSync_Maintain,
+	})
+	live.WaitForStatus(amp.OpStatus_Synced)
+	if ls = lastListing(t, live); ls.ids["a.txt"] != fileID {
+		t.Fatal("expected file to have the same cell ID across pins")
+	}
+
+	if err := os.WriteFile(filepath.Join(root, "b.txt"), []byte("b"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	waitForListing(t, live, "created file", func(ls listing) bool {
+		_, exists := ls.children["b.txt"]
+		return exists && len(ls.children) == 3
+	})
+
+	if err := os.WriteFile(filepath.Join(root, "a.txt"), []byte("hello, world"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	waitForListing(t, live, "written file", func(ls listing) bool {
+		return ls.children["a.txt"].Caption == "12b"
+	})
+
+	if err := os.Remove(filepath.Join(root, "a.txt")); err != nil {
+		t.Fatal(err)
+	}
+	waitForListing(t, live, "removed file", func(ls listing) bool {
+		return ls.deleted[fileID] && len(ls.children) == 2
+	})
+
+	// Maintained pins fail once the watch limit is reached, and watches are released as pins close
+	second := sess.Pin(amp.PinRequest{
+		PinTarget: &amp.Tag{URL: "file:///sub"},
+		PinSync:   amp.PinSync_Maintain,
+	})
+	second.WaitForStatus(amp.OpStatus_Synced)
+	if _, err := sess.TryPin(amp.PinRequest{PinTarget: &amp.Tag{URL: "file:///sub/../"}, PinSync: amp.PinSync_Maintain}); err != nil {
+		t.Fatal(err) // the root is already watched
+	}
+	if err := os.Mkdir(filepath.Join(root, "other"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sess.TryPin(amp.PinRequest{PinTarget: &amp.Tag{URL: "file:///other"}, PinSync: amp.PinSync_Maintain}); err == nil {
+		t.Fatal("expected watch limit to be reached")
+	}
+	second.Close()
+	second.RequireComplete()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		other, err := sess.TryPin(amp.PinRequest{PinTarget: &amp.Tag{URL: "file:///other"}, PinSync: amp.PinSync_Maintain})
+		if err == nil {
+			other.WaitForStatus(amp.OpStatus_Synced)
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected watch to be released: %v", err)
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	live.Close()
+	live.RequireComplete()
+}
+
+func TestCueSheet(t *testing.T) {
+	root := t.TempDir()
+	sheet := `PERFORMER "Band"
+TITLE "Album"
+FILE "album.wav" WAVE
+  TRACK 01 AUDIO
+    TITLE "One"
+    INDEX 01 00:00:00
+  TRACK 02 AUDIO
+    TITLE "Two"
+    INDEX 00 03:00:00
+    INDEX 01 03:02:00
+FILE "../outside.wav" WAVE
+  TRACK 03 AUDIO
+    INDEX 01 00:00:00
+`
+	if err := os.WriteFile(filepath.Join(root, "album.cue"), []byte(sheet), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "album.wav"), make([]byte, 100), 0644); err != nil {
+		t.Fatal(err)
+	}
+	sess := amptest.NewSession(t, filesys.NewApp(filesys.Opts{Root: root}))
+
+	dir := sess.PinURL("file:///")
+	dir.WaitForStatus(amp.OpStatus_Synced)
+	tab := lastListing(t, dir).children["album.cue"]
+	if len(tab.Tags) != 1 || tab.Tags[0].URL != "file:///album.cue" {
+		t.Fatalf("expected cue sheet to be pinnable, got %+v", tab)
+	}
+
+	// Each track within the root is a child cell located within its file
+	req := sess.PinURL(tab.Tags[0].URL)
+	req.WaitForStatus(amp.OpStatus_Synced)
+	tracks := lastListing(t, req)
+	if len(tracks.children) != 2 {
+		t.Fatalf("unexpected tracks %v", tracks.children)
+	}
+	two := tracks.ids["Two"]
+	var info amp.MediaInfo
+	req.RequireAttr(two, amp.MediaInfoSpec.ID, &info)
+	if info.Artist != "Band" || info.Album != "Album" || info.TrackNum != 2 {
+		t.Fatalf("unexpected track info %+v", info)
+	}
+	var offset amp.TrackOffset
+	req.RequireAttr(two, amp.TrackOffsetSpec.ID, &offset)
+	if offset.StartMs != 182000 || offset.PregapMs != 2000 || offset.DurationMs != 0 {
+		t.Fatalf("unexpected track offset %+v", offset)
+	}
+	offset = amp.TrackOffset{}
+	req.RequireAttr(tracks.ids["One"], amp.TrackOffsetSpec.ID, &offset)
+	if offset.StartMs != 0 || offset.DurationMs != 180000 {
+		t.Fatalf("unexpected track offset %+v", offset)
+	}
+	var stream amp.Tag
+	req.RequireAttr(two, amp.ContentSpec.ID, &stream)
+	if stream.Use != amp.TagUse_Stream || stream.URL != "file:///album.wav" {
+		t.Fatalf("unexpected stream %+v", stream)
+	}
+}
@@ -0,0 +1,445 @@
+// Package gateway is an HTTP/JSON gateway to the apps on a host, so that web dashboards and scripts can pin cells and commit attrs
+// without implementing the binary tx protocol.
+//
+// Routes, relative to Opts.Prefix, where {url} is the URL to pin (as in amp.PinRequest.PinTarget.URL):
+//
+//	GET  cells?url={url}[&attr={spec}...]   pins url and responds with the cell Tree once the app has synced it
+//	POST cells?url={url}                    commits the Commit in the request body to url and responds as for GET
+//	GET  watch?url={url}[&attr={spec}...]   maintains a pin of url, sending each tx the app pushes as a server-sent "tx" event (see TxEvent)
+//
+// A watch stream ends with a "complete" event, whose data is an Error if the request failed.  Each attr param is an attr spec to pin
+// (see amp.PinRequest.PinAttrs), and a failed request responds with an Error.  A GET of either route may also have offset, limit,
+// or cursor params, which specify the window of children to pin for a cell that pins its children in pages (see amp.PinWindow).
+// A filter param is an expression that the pinned cell's children are filtered by (see amp.ParseFilter).
+//
+// Requests are issued to the apps of the amp.HostSession returned by Opts.Session, subject to the same access control and limits as a
+// binary client of that session (see amp.GuardAppInstance and amp.SessionLimiter).
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/amp-3d/amp-sdk-go/amp"
+	"github.com/amp-3d/amp-sdk-go/stdlib/tag"
+)
+
+const (
+	DefaultPrefix    = "/amp/"
+	DefaultTimeout   = 10 * time.Second
+	DefaultKeepAlive = 30 * time.Second
+	MaxCommitSize    = 4 << 20
+)
+
+// Opts configures a Gateway.
+type Opts struct {
+	Prefix    string        // URL path the gateway's routes are relative to (default DefaultPrefix)
+	Timeout   time.Duration // how long GET and POST wait for a pin to sync (default DefaultTimeout)
+	KeepAlive time.Duration // interval between comments sent to keep an idle watch stream open (default DefaultKeepAlive)
+
+	// Returns the session the given request is issued to -- typically selected by the request's credentials (see amp/auth).
+	// A returned *amp.Err having ErrCode_AuthFailed responds with 401.
+	Session func(r *http.Request) (amp.HostSession, error)
+}
+
+// Gateway is an http.Handler serving the routes described in the package doc.
+type Gateway struct {
+	opts Opts
+}
+
+// New returns a Gateway for the given options, where opts.Session is required.
+func New(opts Opts) *Gateway {
+	if opts.Prefix == "" {
+		opts.Prefix = DefaultPrefix
+	}
+	if !strings.HasSuffix(opts.Prefix, "/") {
+		opts.Prefix += "/"
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	if opts.KeepAlive <= 0 {
+		opts.KeepAlive = DefaultKeepAlive
+	}
+	return &Gateway{
+		opts: opts,
+	}
+}
+
+func (gw *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	route, ok := strings.CutPrefix(r.URL.Path, gw.opts.Prefix)
+	switch {
+	case !ok:
+		http.NotFound(w, r)
+	case route == "cells" && r.Method == http.MethodGet:
+		gw.serveCells(w, r, nil)
+	case route == "cells" && r.Method == http.MethodPost:
+		var commit Commit
+		body := http.MaxBytesReader(w, r.Body, MaxCommitSize)
+		if err := json.NewDecoder(body).Decode(&commit); err != nil {
+			respondErr(w, amp.ErrCode_BadRequest.Errorf("bad commit: %v", err))
+			return
+		}
+		gw.serveCells(w, r, &commit)
+	case route == "watch" && r.Method == http.MethodGet:
+		gw.serveWatch(w, r)
+	case route == "cells" || route == "watch":
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (gw *Gateway) serveCells(w http.ResponseWriter, r *http.Request, commit *Commit) {
+	sess, c, pinReq, err := gw.prepare(r, amp.PinSync_CloseOnSync)
+	if err != nil {
+		respondErr(w, err)
+		return
+	}
+	var commitTx *amp.TxMsg
+	if commit != nil {
+		if commitTx, err = c.decodeCommit(commit); err != nil {
+			respondErr(w, err)
+			return
+		}
+	}
+	req, err := issue(sess, pinReq, commitTx)
+	if err != nil {
+		respondErr(w, err)
+		return
+	}
+	defer req.close()
+
+	ctx, cancel := context.WithTimeout(r.Context(), gw.opts.Timeout)
+	defer cancel()
+
+	tree := newTreeBuilder(c, req.req.TargetID())
+	status := amp.OpStatus_NotStarted
+	for {
+		txs, complete, err := req.take()
+		for _, tx := range txs {
+			tree.apply(tx)
+			status = tx.Status
+			tx.ReleaseRef()
+		}
+		if complete && err != nil {
+			respondErr(w, err)
+			return
+		}
+		if complete || status >= amp.OpStatus_Synced {
+			break
+		}
+
+		select {
+		case <-req.progress:
+		case <-req.done:
+		case <-ctx.Done():
+			if r.Context().Err() == nil {
+				respondErr(w, amp.ErrCode_Timeout.Errorf("timed out waiting for %s to sync", pinReq.PinTarget.URL))
+			}
+			return
+		}
+	}
+
+	respondJSON(w, http.StatusOK, &Tree{
+		RequestID: req.req.ID,
+		Status:    status.String(),
+		Cell:      tree.build(),
+	})
+}
+
+func (gw *Gateway) serveWatch(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		respondErr(w, amp.ErrCode_UnsupportedOp.Error("streaming not supported"))
+		return
+	}
+	sess, c, pinReq, err := gw.prepare(r, amp.PinSync_Maintain)
+	if err != nil {
+		respondErr(w, err)
+		return
+	}
+	req, err := issue(sess, pinReq, nil)
+	if err != nil {
+		respondErr(w, err)
+		return
+	}
+	defer req.close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(gw.opts.KeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		txs, complete, err := req.take()
+		for _, tx := range txs {
+			data, _ := json.Marshal(&TxEvent{
+				Status: tx.Status.String(),
+				Ops:    c.encodeOps(tx),
+			})
+			tx.ReleaseRef()
+			fmt.Fprintf(w, "event: tx\ndata: %s\n\n", data)
+		}
+		if complete {
+			var data []byte
+			if err != nil {
+				data, _ = json.Marshal(errBody(err))
+			} else {
+				data = []byte("{}")
+			}
+			fmt.Fprintf(w, "event: complete\ndata: %s\n\n", data)
+			flusher.Flush()
+			return
+		}
+		flusher.Flush()
+
+		select {
+		case <-req.progress:
+		case <-req.done:
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keepalive\n\n")
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// prepare returns the session and pin request for the given HTTP request.
+func (gw *Gateway) prepare(r *http.Request, pinSync amp.PinSync) (amp.HostSession, *codec, amp.PinRequest, error) {
+	pinReq := amp.PinRequest{
+		PinSync: pinSync,
+	}
+	query := r.URL.Query()
+	pinURL := query.Get("url")
+	if pinURL == "" {
+		return nil, nil, pinReq, amp.ErrCode_InvalidURI.Error("missing url param")
+	}
+	pinReq.PinTarget = &amp.Tag{URL: pinURL}
+
+	if gw.opts.Session == nil {
+		return nil, nil, pinReq, amp.ErrCode_NotConnected.Error("gateway has no session provider")
+	}
+	sess, err := gw.opts.Session(r)
+	if err != nil {
+		return nil, nil, pinReq, err
+	}
+	c := newCodec(sess)
+	for _, spec := range query["attr"] {
+		attrID, err := c.attrID(spec)
+		if err != nil {
+			return nil, nil, pinReq, err
+		}
+		pinReq.PinAttrs = append(pinReq.PinAttrs, amp.FormPinnableTag(tag.Spec{ID: attrID}))
+	}
+	pinReq.PinFilter = query.Get("filter")
+	if pinReq.PinWindow, err = parseWindow(query); err != nil {
+		return nil, nil, pinReq, err
+	}
+	return sess, c, pinReq, nil
+}
+
+// parseWindow returns the window specified by the offset, limit, and cursor params (or nil if there are none).
+func parseWindow(query url.Values) (*amp.PinWindow, error) {
+	if !query.Has("offset") && !query.Has("limit") && !query.Has("cursor") {
+		return nil, nil
+	}
+	win := &amp.PinWindow{
+		Cursor: query.Get("cursor"),
+	}
+	for _, param := range []struct {
+		name string
+		dst  *int64
+	}{
+		{"offset", &win.Offset},
+		{"limit", &win.Limit},
+	} {
+		if str := query.Get(param.name); str != "" {
+			val, err := strconv.ParseInt(str, 10, 64)
+			if err != nil || val < 0 {
+				return nil, amp.ErrCode_BadRequest.Errorf("invalid %s param %q", param.name, str)
+			}
+			*param.dst = val
+		}
+	}
+	return win, nil
+}
+
+// request is the amp.Requester for a gateway request, holding pushed txs until the HTTP handler takes them.
+type request struct {
+	req      *amp.Request
+	pin      amp.Pin
+	done     chan struct{} // closed on OnComplete()
+	progress chan struct{} // signaled on each PushTx()
+
+	mu       sync.Mutex
+	txs      []*amp.TxMsg
+	complete bool
+	err      error
+}
+
+// issue issues the given pin request to the app it invokes, selected by the target URL's scheme (or by its host when the
+// scheme is "amp").
+func issue(sess amp.HostSession, pinReq amp.PinRequest, commitTx *amp.TxMsg) (*request, error) {
+	req := &request{
+		done:     make(chan struct{}),
+		progress: make(chan struct{}, 1),
+		req: &amp.Request{
+			PinRequest: pinReq,
+			ID:         tag.New(),
+			CommitTx:   commitTx,
+		},
+	}
+
+	var err error
+	if req.req.URL, err = url.Parse(pinReq.PinTarget.URL); err != nil {
+		return nil, amp.ErrCode_InvalidURI.Errorf("bad pin URL: %v", err)
+	}
+	req.req.Values = req.req.URL.Query()
+	invocation := req.req.URL.Scheme
+	if invocation == "amp" || invocation == "" {
+		invocation = req.req.URL.Host
+	}
+
+	app, err := sess.GetAppForInvocation(invocation)
+	if err != nil {
+		return nil, err
+	}
+	inst, err := sess.GetAppInstance(app.AppSpec.ID, true)
+	if err != nil {
+		return nil, err
+	}
+	if err = inst.MakeReady(req); err != nil {
+		return nil, err
+	}
+	if req.pin, err = inst.ServeRequest(req); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+func (req *request) Request() *amp.Request {
+	return req.req
+}
+
+func (req *request) PushTx(tx *amp.TxMsg) error {
+	req.mu.Lock()
+	defer req.mu.Unlock()
+
+	if req.complete {
+		tx.ReleaseRef()
+		return amp.ErrRequestClosed
+	}
+	req.txs = append(req.txs, tx)
+	select {
+	case req.progress <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+func (req *request) OnComplete(err error) {
+	req.mu.Lock()
+	defer req.mu.Unlock()
+
+	if req.complete {
+		return
+	}
+	req.complete = true
+	req.err = err
+	close(req.done)
+}
+
+// take returns the txs pushed since the last call and whether the request has completed (and with what error).
+func (req *request) take() (txs []*amp.TxMsg, complete bool, err error) {
+	req.mu.Lock()
+	defer req.mu.Unlock()
+
+	txs = req.txs
+	req.txs = nil
+	return txs, req.complete, req.err
+}
+
+// close closes the request's pin (if still open) and releases any txs not taken.
+func (req *request) close() {
+	if req.pin != nil {
+		req.pin.Context().Close()
+	}
+	req.OnComplete(amp.ErrRequestClosed)
+	txs, _, _ := req.take()
+	for _, tx := range txs {
+		tx.ReleaseRef()
+	}
+}
+
+func respondJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func respondErr(w http.ResponseWriter, err error) {
+	respondJSON(w, httpStatus(err), errBody(err))
+}
+
+func errBody(err error) *Error {
+	body := &Error{
+		Code:  amp.ErrCode_UnnamedErr.String(),
+		Error: err.Error(),
+	}
+	if ampErr, ok := err.(*amp.Err); ok {
+		body.Code = ampErr.Code.String()
+		body.Error = ampErr.Msg
+		body.Retryable = ampErr.Retryable
+		for _, detail := range ampErr.Details {
+			if body.Details == nil {
+				body.Details = make(map[string]string, len(ampErr.Details))
+			}
+			body.Details[detail.Key] = detail.Value
+		}
+	}
+	return body
+}
+
+// httpStatus returns the HTTP status code for the given error.
+func httpStatus(err error) int {
+	ampErr, ok := err.(*amp.Err)
+	if !ok {
+		return http.StatusInternalServerError
+	}
+	switch ampErr.Code {
+	case amp.ErrCode_BadRequest, amp.ErrCode_InvalidURI, amp.ErrCode_BadValue, amp.ErrCode_InvalidTag,
+		amp.ErrCode_InvalidTagSpec, amp.ErrCode_MalformedTx, amp.ErrCode_NothingToCommit:
+		return http.StatusBadRequest
+	case amp.ErrCode_AuthFailed, amp.ErrCode_LoginFailed, amp.ErrCode_SessionExpired:
+		return http.StatusUnauthorized
+	case amp.ErrCode_InsufficientPermissions, amp.ErrCode_ViolatesAppendOnly:
+		return http.StatusForbidden
+	case amp.ErrCode_AppNotFound, amp.ErrCode_CellNotFound, amp.ErrCode_AttrNotFound, amp.ErrCode_PlanetNotFound,
+		amp.ErrCode_RequestNotFound:
+		return http.StatusNotFound
+	case amp.ErrCode_VersionConflict:
+		return http.StatusConflict
+	case amp.ErrCode_RateLimited:
+		return http.StatusTooManyRequests
+	case amp.ErrCode_Timeout:
+		return http.StatusGatewayTimeout
+	case amp.ErrCode_UnsupportedOp, amp.ErrCode_Unimplemented:
+		return http.StatusNotImplemented
+	case amp.ErrCode_ShuttingDown, amp.ErrCode_NotConnected:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
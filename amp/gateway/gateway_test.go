@@ -0,0 +1,231 @@
+package gateway_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/amp-3d/amp-sdk-go/amp"
+	"github.com/amp-3d/amp-sdk-go/amp/amptest"
+	"github.com/amp-3d/amp-sdk-go/amp/basic"
+	"github.com/amp-3d/amp-sdk-go/amp/gateway"
+	"github.com/amp-3d/amp-sdk-go/stdlib/tag"
+)
+
+var testApp = &amp.App{
+	AppSpec:     tag.FormSpec(amp.AppSpec, "test.gateway"),
+	Invocations: []string{"testapp"},
+	NewAppInstance: func(ctx amp.AppContext) (amp.AppInstance, error) {
+		app := &appInst{}
+		app.AppContext = ctx
+		app.Instance = app
+		return app, nil
+	},
+}
+
+type appInst struct {
+	basic.App[*appInst]
+}
+
+func (app *appInst) ServeRequest(req amp.Requester) (amp.Pin, error) {
+	home := &cell{}
+	home.Tab.Label = req.Request().Values.Get("label")
+	if commit := req.Request().CommitTx; commit != nil {
+		var tab amp.TagTab
+		if err := commit.UnmarshalOpValue(0, &tab); err != nil {
+			return nil, err
+		}
+		home.Tab.Label = tab.Label
+	}
+	return app.PinAndServe(home, req)
+}
+
+type cell struct {
+	basic.CellInfo[*appInst]
+}
+
+func (c *cell) PinInto(dst *basic.Pinned[*appInst]) error {
+	for _, label := range []string{"one", "two"} {
+		child := &cell{}
+		child.Tab.Label = label
+		dst.AddChild(child)
+	}
+	return nil
+}
+
+func newServer(t *testing.T) *httptest.Server {
+	sess := amptest.NewSession(t, testApp)
+	sess.Versions = amp.NewAttrVersions()
+	gw := gateway.New(gateway.Opts{
+		Session: func(r *http.Request) (amp.HostSession, error) {
+			if r.Header.Get("Authorization") == "" {
+				return nil, amp.ErrCode_AuthFailed.Error("missing credentials")
+			}
+			return sess, nil
+		},
+	})
+	srv := httptest.NewServer(gw)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func do(t *testing.T, method, target, body string, dst any) int {
+	t.Helper()
+	req, err := http.NewRequest(method, target, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer test")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if dst != nil {
+		if err = json.NewDecoder(resp.Body).Decode(dst); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return resp.StatusCode
+}
+
+func label(t *testing.T, cell *gateway.Cell, attrSpec string) string {
+	t.Helper()
+	var tab amp.TagTab
+	if err := json.Unmarshal(cell.Attrs[attrSpec], &tab); err != nil {
+		t.Fatalf("cell %s has no %s attr: %v", cell.ID, attrSpec, err)
+	}
+	return tab.Label
+}
+
+func TestGateway(t *testing.T) {
+	srv := newServer(t)
+	cellsURL := srv.URL + gateway.DefaultPrefix + "cells?url=" + url.QueryEscape("testapp://cells/home?label=Home")
+
+	// GET responds with the synced cell tree
+	var tree gateway.Tree
+	if status := do(t, http.MethodGet, cellsURL, "", &tree); status != http.StatusOK {
+		t.Fatalf("unexpected status %d", status)
+	}
+	if tree.Status != amp.OpStatus_Synced.String() || tree.Cell == nil || tree.RequestID.IsNil() {
+		t.Fatalf("unexpected tree %+v", tree)
+	}
+	if got := label(t, tree.Cell, amp.PinnedTabSpec.Canonic); got != "Home" {
+		t.Fatalf("unexpected label %q", got)
+	}
+	children := map[string]bool{}
+	for _, child := range tree.Cell.Children {
+		children[label(t, child, amp.ChildTabSpec.Canonic)] = true
+	}
+	if len(children) != 2 || !children["one"] || !children["two"] {
+		t.Fatalf("unexpected children %v", children)
+	}
+
+	// Attr params select which attrs are sent
+	var selected gateway.Tree
+	if status := do(t, http.MethodGet, cellsURL+"&attr="+amp.PinnedTabSpec.Canonic, "", &selected); status != http.StatusOK {
+		t.Fatalf("unexpected status %d", status)
+	}
+	if len(selected.Cell.Children) != 0 || len(selected.Cell.Attrs) != 1 {
+		t.Fatalf("expected only the pinned tab, got %+v", selected.Cell)
+	}
+
+	// POST commits attr values decoded from JSON
+	commit := `{"ops": [{"op": "upsert", "target": "` + tree.Cell.ID.Base32() + `", "attr": "` + amp.PinnedTabSpec.Canonic + `", "value": {"Label": "Renamed"}}]}`
+	if status := do(t, http.MethodPost, cellsURL, commit, &tree); status != http.StatusOK {
+		t.Fatalf("unexpected status %d", status)
+	}
+	if got := label(t, tree.Cell, amp.PinnedTabSpec.Canonic); got != "Renamed" {
+		t.Fatalf("unexpected label %q", got)
+	}
+
+	// Failures map onto HTTP statuses
+	var errBody gateway.Error
+	stale := strings.Replace(commit, `}}]}`, `}, "version": 5}]}`, 1)
+	if status := do(t, http.MethodPost, cellsURL, stale, &errBody); status != http.StatusConflict {
+		t.Fatalf("expected a stale commit to conflict, got %d", status)
+	}
+	version, _ := strconv.ParseUint(errBody.Details[amp.ConflictVersionDetail], 10, 64)
+	if errBody.Code != amp.ErrCode_VersionConflict.String() || uint32(version) != 1 { // written once (see amp.AttrVersions)
+		t.Fatalf("unexpected error %+v", errBody)
+	}
+	if status := do(t, http.MethodPost, cellsURL, `{"ops": [{"op": "upsert", "attr": "nope"}]}`, &errBody); status != http.StatusNotFound {
+		t.Fatalf("expected unknown attr to be not found, got %d", status)
+	}
+	if errBody.Code != amp.ErrCode_AttrNotFound.String() {
+		t.Fatalf("unexpected error %+v", errBody)
+	}
+	if status := do(t, http.MethodGet, srv.URL+gateway.DefaultPrefix+"cells?url=nope://x", "", &errBody); status != http.StatusNotFound {
+		t.Fatalf("expected app not found, got %d", status)
+	}
+	resp, err := http.Get(cellsURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected unauthorized, got %d", resp.StatusCode)
+	}
+}
+
+func TestGatewayWatch(t *testing.T) {
+	srv := newServer(t)
+	watchURL := srv.URL + gateway.DefaultPrefix + "watch?url=" + url.QueryEscape("testapp://cells/home?label=Live")
+
+	req, err := http.NewRequest(http.MethodGet, watchURL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer test")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("unexpected content type %q", ct)
+	}
+
+	events := make(chan [2]string, 16)
+	go func() {
+		var event string
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if name, ok := strings.CutPrefix(line, "event: "); ok {
+				event = name
+			} else if data, ok := strings.CutPrefix(line, "data: "); ok {
+				events <- [2]string{event, data}
+			}
+		}
+		close(events)
+	}()
+
+	// The maintained pin streams its initial state and stays open
+	select {
+	case ev := <-events:
+		var tx gateway.TxEvent
+		if err = json.Unmarshal([]byte(ev[1]), &tx); err != nil || ev[0] != "tx" {
+			t.Fatalf("unexpected event %q: %v", ev[0], err)
+		}
+		if tx.Status != amp.OpStatus_Synced.String() || len(tx.Ops) != 3 || tx.Ops[0].Op != gateway.OpUpsert {
+			t.Fatalf("unexpected tx %+v", tx)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for tx event")
+	}
+	select {
+	case ev, ok := <-events:
+		if ok {
+			t.Fatalf("unexpected event %q", ev[0])
+		}
+		t.Fatal("stream closed before the client")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -0,0 +1,334 @@
+package gateway
+
+import (
+	"encoding/json"
+
+	"github.com/amp-3d/amp-sdk-go/amp"
+	"github.com/amp-3d/amp-sdk-go/stdlib/tag"
+)
+
+// Tree is the response to a GET or POST of a pin URL: the state the app pushed until the request was synced.
+type Tree struct {
+	RequestID tag.ID `json:"request_id"`
+	Status    string `json:"status"`         // amp.OpStatus of the last tx pushed (e.g. "OpStatus_Synced")
+	Cell      *Cell  `json:"cell,omitempty"` // the pinned cell, with the other cells pushed as its descendants
+}
+
+// Cell is a cell and its attrs, where attr values are the JSON form of each attr's registered amp.ElemVal.
+// A value of an attr having no registered prototype is its serialized form (a base64 string).
+type Cell struct {
+	ID       tag.ID                                `json:"id"`
+	Attrs    map[string]json.RawMessage            `json:"attrs,omitempty"` // values with a nil SI, keyed by attr spec
+	Items    map[string]map[string]json.RawMessage `json:"items,omitempty"` // values with a non-nil SI, keyed by attr spec then SI
+	Children []*Cell                               `json:"children,omitempty"`
+}
+
+// TxEvent is the data of each "tx" event sent to a watch stream.
+type TxEvent struct {
+	Status string `json:"status"`
+	Ops    []Op   `json:"ops"`
+}
+
+// Commit is the body of a POST, listing the ops to commit to the pinned cell (see amp.Request.CommitTx).
+type Commit struct {
+	Ops []Op `json:"ops"`
+}
+
+// Op is the JSON form of an amp.TxOp.
+type Op struct {
+	Op     string          `json:"op"` // one of the Op* constants
+	Target tag.ID          `json:"target"`
+	From   *tag.ID         `json:"from,omitempty"`
+	Attr   string          `json:"attr,omitempty"` // attr spec (or attr ID if the spec is not registered)
+	SI     *tag.ID         `json:"si,omitempty"`
+	Value  json.RawMessage `json:"value,omitempty"`
+
+	// The version of the attr item written, if versioned -- in a commit, the version expected (see amp.AttrVersions).
+	Version uint64 `json:"version,omitempty"`
+}
+
+// Op names
+const (
+	OpUpsert     = "upsert"
+	OpDeleteAttr = "delete_attr"
+	OpDeleteCell = "delete_cell"
+	OpUpsertLink = "upsert_link"
+	OpDeleteLink = "delete_link"
+)
+
+var opCodes = map[string]amp.TxOpCode{
+	OpUpsert:     amp.TxOpCode_UpsertAttr,
+	OpDeleteAttr: amp.TxOpCode_DeleteAttr,
+	OpDeleteCell: amp.TxOpCode_DeleteCell,
+	OpUpsertLink: amp.TxOpCode_UpsertLink,
+	OpDeleteLink: amp.TxOpCode_DeleteLink,
+}
+
+var opNames = map[amp.TxOpCode]string{
+	amp.TxOpCode_UpsertAttr:   OpUpsert,
+	amp.TxOpCode_SnapshotAttr: OpUpsert,
+	amp.TxOpCode_DeleteAttr:   OpDeleteAttr,
+	amp.TxOpCode_DeleteCell:   OpDeleteCell,
+	amp.TxOpCode_UpsertLink:   OpUpsertLink,
+	amp.TxOpCode_DeleteLink:   OpDeleteLink,
+}
+
+// Error is the body of a failed response.
+type Error struct {
+	Code      string            `json:"code"` // amp.ErrCode name (e.g. "ErrCode_CellNotFound")
+	Error     string            `json:"error"`
+	Retryable bool              `json:"retryable,omitempty"` // see amp.Err.Retryable
+	Details   map[string]string `json:"details,omitempty"`   // see amp.Err.Details
+}
+
+// codec converts attr values and specs between their tx and JSON forms using a session's registry.
+type codec struct {
+	reg   amp.Registry
+	specs map[tag.ID]string
+	ids   map[string]tag.ID
+}
+
+func newCodec(reg amp.Registry) *codec {
+	attrs := reg.Describe().Attrs
+	c := &codec{
+		reg:   reg,
+		specs: make(map[tag.ID]string, len(attrs)),
+		ids:   make(map[string]tag.ID, len(attrs)),
+	}
+	for _, attr := range attrs {
+		c.specs[attr.ID] = attr.Spec
+		c.ids[attr.Spec] = attr.ID
+	}
+	return c
+}
+
+func (c *codec) attrName(attrID tag.ID) string {
+	if spec, exists := c.specs[attrID]; exists {
+		return spec
+	}
+	return attrID.Base32()
+}
+
+func (c *codec) attrID(name string) (tag.ID, error) {
+	if attrID, exists := c.ids[name]; exists {
+		return attrID, nil
+	}
+	var attrID tag.ID
+	if err := attrID.UnmarshalText([]byte(name)); err != nil {
+		return tag.Nil, amp.ErrCode_AttrNotFound.Errorf("unknown attr %q", name)
+	}
+	return attrID, nil
+}
+
+func (c *codec) encodeValue(attrID tag.ID, data []byte) json.RawMessage {
+	if elem, err := c.reg.NewAttrElem(attrID); err == nil && elem.Unmarshal(data) == nil {
+		if val, err := json.Marshal(elem); err == nil {
+			return val
+		}
+	}
+	val, _ := json.Marshal(data)
+	return val
+}
+
+func (c *codec) encodeOps(tx *amp.TxMsg) []Op {
+	ops := make([]Op, 0, len(tx.Ops))
+	for _, op := range tx.Ops {
+		name, known := opNames[op.OpCode]
+		if !known {
+			continue
+		}
+		jop := Op{
+			Op:     name,
+			Target: op.TargetID,
+		}
+		if op.FromID.IsSet() {
+			from := op.FromID
+			jop.From = &from
+		}
+		if op.AttrID.IsSet() {
+			jop.Attr = c.attrName(op.AttrID)
+		}
+		if op.SI.IsSet() {
+			si := op.SI
+			jop.SI = &si
+		}
+		if op.DataLen > 0 {
+			jop.Value = c.encodeValue(op.AttrID, tx.DataStore[op.DataOfs:op.DataOfs+op.DataLen])
+		}
+		jop.Version = op.Height
+		ops = append(ops, jop)
+	}
+	return ops
+}
+
+// decodeCommit returns the tx to be committed for the given ops.
+func (c *codec) decodeCommit(commit *Commit) (*amp.TxMsg, error) {
+	if len(commit.Ops) == 0 {
+		return nil, amp.ErrCode_NothingToCommit.Error("no ops to commit")
+	}
+	tx := amp.NewTxMsg(true)
+	for i, jop := range commit.Ops {
+		opCode, known := opCodes[jop.Op]
+		if !known {
+			return nil, amp.ErrCode_BadRequest.Errorf("op %d: unknown op %q", i, jop.Op)
+		}
+		op := amp.TxOp{
+			OpCode:   opCode,
+			TargetID: jop.Target,
+			Height:   jop.Version,
+		}
+		if jop.From != nil {
+			op.FromID = *jop.From
+		}
+		if jop.SI != nil {
+			op.SI = *jop.SI
+		}
+		if jop.Attr != "" {
+			var err error
+			if op.AttrID, err = c.attrID(jop.Attr); err != nil {
+				return nil, err
+			}
+		}
+
+		if opCode != amp.TxOpCode_UpsertAttr {
+			if op.TargetID.IsNil() {
+				return nil, amp.ErrCode_BadRequest.Errorf("op %d: missing target", i)
+			}
+			tx.MarshalOpWithBuf(&op, nil)
+			continue
+		}
+		elem, err := c.reg.NewAttrElem(op.AttrID)
+		if err != nil {
+			return nil, err
+		}
+		if err = json.Unmarshal(jop.Value, elem); err != nil {
+			return nil, amp.ErrCode_BadValue.Errorf("op %d: bad %s value: %v", i, jop.Attr, err)
+		}
+		if err = tx.MarshalOp(&op, elem); err != nil {
+			return nil, err
+		}
+	}
+	return tx, nil
+}
+
+// treeBuilder accumulates the txs pushed for a request into a cell tree.
+type treeBuilder struct {
+	*codec
+	root    tag.ID
+	cells   map[tag.ID]*Cell
+	order   []tag.ID          // cell IDs in the order first pushed
+	parents map[tag.ID]tag.ID // from links pushed (FromID to TargetID)
+}
+
+func newTreeBuilder(c *codec, target tag.ID) *treeBuilder {
+	return &treeBuilder{
+		codec:   c,
+		root:    target,
+		cells:   make(map[tag.ID]*Cell),
+		parents: make(map[tag.ID]tag.ID),
+	}
+}
+
+func (tb *treeBuilder) cell(cellID tag.ID) *Cell {
+	cell := tb.cells[cellID]
+	if cell == nil {
+		cell = &Cell{ID: cellID}
+		tb.cells[cellID] = cell
+		tb.order = append(tb.order, cellID)
+	}
+	return cell
+}
+
+func (tb *treeBuilder) apply(tx *amp.TxMsg) {
+	for _, op := range tx.Ops {
+		switch op.OpCode {
+		case amp.TxOpCode_UpsertAttr, amp.TxOpCode_SnapshotAttr:
+			if tb.root.IsNil() && op.AttrID == amp.PinnedTabSpec.ID {
+				tb.root = op.TargetID
+			}
+			if op.FromID.IsSet() {
+				tb.parents[op.TargetID] = op.FromID
+			}
+			cell := tb.cell(op.TargetID)
+			name := tb.attrName(op.AttrID)
+			val := tb.encodeValue(op.AttrID, tx.DataStore[op.DataOfs:op.DataOfs+op.DataLen])
+			if op.SI.IsNil() {
+				if cell.Attrs == nil {
+					cell.Attrs = make(map[string]json.RawMessage)
+				}
+				cell.Attrs[name] = val
+			} else {
+				if cell.Items == nil {
+					cell.Items = make(map[string]map[string]json.RawMessage)
+				}
+				items := cell.Items[name]
+				if items == nil {
+					items = make(map[string]json.RawMessage)
+					cell.Items[name] = items
+				}
+				items[op.SI.Base32()] = val
+			}
+		case amp.TxOpCode_DeleteAttr:
+			if cell := tb.cells[op.TargetID]; cell != nil {
+				name := tb.attrName(op.AttrID)
+				if op.SI.IsNil() {
+					delete(cell.Attrs, name)
+				} else {
+					delete(cell.Items[name], op.SI.Base32())
+				}
+			}
+		case amp.TxOpCode_DeleteCell:
+			delete(tb.cells, op.TargetID)
+			delete(tb.parents, op.TargetID)
+		case amp.TxOpCode_UpsertLink:
+			tb.cell(op.TargetID)
+			tb.parents[op.TargetID] = op.FromID
+		case amp.TxOpCode_DeleteLink:
+			if tb.parents[op.TargetID] == op.FromID {
+				delete(tb.parents, op.TargetID)
+			}
+		}
+	}
+}
+
+// build returns the root cell, where a cell not linked to another (or whose links do not lead to the root) is a child of the root.
+func (tb *treeBuilder) build() *Cell {
+	root := tb.cells[tb.root]
+	if root == nil {
+		for _, cellID := range tb.order {
+			if root = tb.cells[cellID]; root != nil {
+				break
+			}
+		}
+		if root == nil {
+			return nil
+		}
+	}
+
+	for _, cellID := range tb.order {
+		cell := tb.cells[cellID]
+		if cell == nil || cell == root {
+			continue
+		}
+		parent := root
+		if parentID, linked := tb.parents[cellID]; linked && tb.reachesRoot(cellID, root.ID) {
+			parent = tb.cells[parentID]
+		}
+		parent.Children = append(parent.Children, cell)
+	}
+	return root
+}
+
+func (tb *treeBuilder) reachesRoot(cellID, rootID tag.ID) bool {
+	for range tb.cells {
+		parentID, linked := tb.parents[cellID]
+		if !linked || tb.cells[parentID] == nil {
+			return false
+		}
+		if parentID == rootID {
+			return true
+		}
+		cellID = parentID
+	}
+	return false
+}
@@ -0,0 +1,226 @@
+package geo
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/amp-3d/amp-sdk-go/amp"
+	"github.com/amp-3d/amp-sdk-go/amp/basic"
+	"github.com/amp-3d/amp-sdk-go/stdlib/geo"
+	"github.com/amp-3d/amp-sdk-go/stdlib/tag"
+	"github.com/amp-3d/amp-sdk-go/stdlib/task"
+)
+
+// AppSpec identifies the geo App.
+var AppSpec = tag.FormSpec(amp.AppSpec, "geo")
+
+// LocationField is the name of the field of a hit offering its location to a request's PinFilter (see amp.Filter).
+const LocationField = "location"
+
+// NewApp returns the geo App, serving pins of URLs of the form:
+//
+//	geo://?box={south},{west},{north},{east}
+//	geo://?lat={lat}&lon={lon}&radius={meters}
+//	geo://                                       (with a PinFilter such as `location within radius(lat, lon, meters)`)
+//
+// The pinned cell's children are the cells within the region, nearest its center first, each having its location as an
+// amp.GeoPointSpec attr and its label as its amp.ChildTabSpec attr.  Hits are pinned in windows (see basic.PagedCell), so a
+// request's PinWindow selects which hits are pushed, and a maintained pin pushes its window again as the index changes.
+func NewApp(idx *Index) *amp.App {
+	return &amp.App{
+		AppSpec:     AppSpec,
+		Desc:        "spatial queries of indexed cell locations",
+		Invocations: []string{"geo"},
+		NewAppInstance: func(ctx amp.AppContext) (amp.AppInstance, error) {
+			app := &appInst{
+				index: idx,
+			}
+			app.AppContext = ctx
+			app.Instance = app
+			return app, nil
+		},
+	}
+}
+
+type appInst struct {
+	basic.App[*appInst]
+	index *Index
+}
+
+func (app *appInst) ServeRequest(req amp.Requester) (amp.Pin, error) {
+	region, err := requestRegion(req.Request())
+	if err != nil {
+		return nil, err
+	}
+
+	results := &resultsCell{
+		index:  app.index,
+		region: region,
+	}
+	results.Tab.Label = req.Request().URL.RawQuery
+
+	changed := app.index.Changed() // before the first query so no change is missed
+	pin, err := app.PinAndServe(results, req)
+	if err != nil {
+		return nil, err
+	}
+	if req.Request().PinSync == amp.PinSync_Maintain {
+		go results.watch(pin.Context(), changed)
+	}
+	return pin, nil
+}
+
+// requestRegion returns the region given by a request's URL params, or else by its PinFilter.
+func requestRegion(req *amp.Request) (geo.Region, error) {
+	params := req.Values
+	switch {
+	case params.Has("box"):
+		edges, err := parseFloats(params.Get("box"), 4)
+		if err != nil {
+			return nil, amp.ErrCode_BadRequest.Wrap(err)
+		}
+		box := geo.Rect{South: edges[0], West: edges[1], North: edges[2], East: edges[3]}
+		if !box.Valid() {
+			return nil, amp.ErrCode_BadRequest.Errorf("invalid box %q", params.Get("box"))
+		}
+		return box, nil
+
+	case params.Has("radius"):
+		var circle geo.Circle
+		var err error
+		for _, param := range []struct {
+			name string
+			dst  *float64
+		}{
+			{"lat", &circle.Center.Lat},
+			{"lon", &circle.Center.Lon},
+			{"radius", &circle.Radius},
+		} {
+			if *param.dst, err = strconv.ParseFloat(params.Get(param.name), 64); err != nil {
+				return nil, amp.ErrCode_BadRequest.Errorf("invalid %s param %q", param.name, params.Get(param.name))
+			}
+		}
+		if !circle.Center.Valid() || circle.Radius < 0 {
+			return nil, amp.ErrCode_BadRequest.Errorf("invalid radius %v around %v", circle.Radius, circle.Center)
+		}
+		return circle, nil
+	}
+
+	filter, err := amp.ParseFilter(req.PinFilter)
+	if err != nil {
+		return nil, err
+	}
+	if region, ok := filter.Within(LocationField); ok {
+		return region, nil
+	}
+	return nil, amp.ErrCode_BadRequest.Error("missing region (box or radius param, or a location PinFilter)")
+}
+
+func parseFloats(list string, count int) ([]float64, error) {
+	fields := strings.Split(list, ",")
+	if len(fields) != count {
+		return nil, strconv.ErrSyntax
+	}
+	vals := make([]float64, count)
+	for i, field := range fields {
+		val, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+		if err != nil {
+			return nil, err
+		}
+		vals[i] = val
+	}
+	return vals, nil
+}
+
+// resultsCell is the pinned cell of a geo request, enumerating the cells within its region.
+type resultsCell struct {
+	basic.CellInfo[*appInst]
+	index  *Index
+	region geo.Region
+
+	mu   sync.Mutex
+	hits []Hit // nil until first queried
+}
+
+func (results *resultsCell) PinInto(dst *basic.Pinned[*appInst]) error {
+	return nil
+}
+
+func (results *resultsCell) ChildCount() int64 {
+	return int64(len(results.currentHits()))
+}
+
+func (results *resultsCell) ChildrenAt(offset, limit int64) ([]basic.Cell[*appInst], error) {
+	hits := results.currentHits()
+	var children []basic.Cell[*appInst]
+	for i := offset; i < offset+limit && i < int64(len(hits)); i++ {
+		hit := &hitCell{
+			hit: hits[i],
+		}
+		hit.ID = hits[i].CellID
+		hit.Tab.Label = hits[i].Label
+		children = append(children, hit)
+	}
+	return children, nil
+}
+
+func (results *resultsCell) currentHits() []Hit {
+	results.mu.Lock()
+	defer results.mu.Unlock()
+	if results.hits == nil {
+		results.hits = results.query()
+	}
+	return results.hits
+}
+
+func (results *resultsCell) query() []Hit {
+	hits := results.index.Within(results.region, 0)
+	if hits == nil {
+		hits = []Hit{}
+	}
+	return hits
+}
+
+// watch queries again each time the index changes, until ctx closes.
+func (results *resultsCell) watch(ctx task.Context, changed <-chan struct{}) {
+	for {
+		select {
+		case <-changed:
+			changed = results.index.Changed()
+			hits := results.query()
+			results.mu.Lock()
+			results.hits = hits
+			results.mu.Unlock()
+			results.Pinned.Invalidate()
+		case <-ctx.Closing():
+			return
+		}
+	}
+}
+
+// hitCell is a cell within the region of a geo request.
+type hitCell struct {
+	basic.CellInfo[*appInst]
+	hit Hit
+}
+
+func (hit *hitCell) PinInto(dst *basic.Pinned[*appInst]) error {
+	return nil
+}
+
+func (hit *hitCell) MarshalAttrs(pin *basic.Pin[*appInst]) {
+	hit.CellInfo.MarshalAttrs(pin)
+	pin.Upsert(hit.ID, amp.GeoPointSpec.ID, tag.Nil, hit.hit.Point)
+}
+
+// FieldValue offers the hit's location and distance (in meters) from the center of the region to a request's PinFilter.
+func (hit *hitCell) FieldValue(name string) (any, bool) {
+	switch name {
+	case LocationField:
+		return hit.hit.Point, true
+	case "distance":
+		return hit.hit.Distance, true
+	}
+	return hit.CellInfo.FieldValue(name)
+}
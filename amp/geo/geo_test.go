@@ -0,0 +1,135 @@
+package geo_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/amp-3d/amp-sdk-go/amp"
+	"github.com/amp-3d/amp-sdk-go/amp/amptest"
+	"github.com/amp-3d/amp-sdk-go/amp/geo"
+	stdgeo "github.com/amp-3d/amp-sdk-go/stdlib/geo"
+	"github.com/amp-3d/amp-sdk-go/stdlib/tag"
+)
+
+var (
+	empireState = &amp.GeoPoint{Lat: 40.7484, Lon: -73.9857, Alt: 443}
+	timesSquare = &amp.GeoPoint{Lat: 40.7580, Lon: -73.9855}
+	liberty     = &amp.GeoPoint{Lat: 40.6892, Lon: -74.0445}
+	suva        = &amp.GeoPoint{Lat: -18.1416, Lon: 178.4419}
+)
+
+func TestIndex(t *testing.T) {
+	idx := geo.NewIndex()
+	cells := make(map[string]tag.ID)
+	for label, point := range map[string]*amp.GeoPoint{
+		"Empire State":      empireState,
+		"Times Square":      timesSquare,
+		"Statue of Liberty": liberty,
+		"Suva":              suva,
+	} {
+		cells[label] = tag.New()
+		idx.Put(geo.Entry{CellID: cells[label], Point: point, Label: label})
+	}
+	idx.Put(geo.Entry{CellID: tag.New(), Point: &amp.GeoPoint{Lat: 100}}) // ignored
+	if idx.Len() != 4 {
+		t.Fatalf("expected 4 cells, got %d", idx.Len())
+	}
+
+	labels := func(region stdgeo.Region) []string {
+		var got []string
+		for _, hit := range idx.Within(region, 0) {
+			got = append(got, hit.Label)
+		}
+		return got
+	}
+
+	// Hits are nearest the center first
+	near := stdgeo.Circle{Center: empireState.Point(), Radius: 10_000}
+	if got := labels(near); len(got) != 3 || got[0] != "Empire State" || got[1] != "Times Square" {
+		t.Fatalf("unexpected hits %v", got)
+	}
+	hits := idx.Within(near, 1)
+	if len(hits) != 1 || hits[0].Distance != 0 || hits[0].Point.Alt != 443 {
+		t.Fatalf("unexpected hits %+v", hits)
+	}
+	if got := labels(stdgeo.Rect{South: -21, West: 176, North: -12, East: -178}); len(got) != 1 || got[0] != "Suva" {
+		t.Fatalf("unexpected hits %v", got)
+	}
+
+	// Moving a cell keeps its label, and removing it removes it
+	changed := idx.Changed()
+	idx.Put(geo.Entry{CellID: cells["Suva"], Point: &amp.GeoPoint{Lat: 40.75, Lon: -73.99}})
+	select {
+	case <-changed:
+	default:
+		t.Fatal("expected change to be signaled")
+	}
+	if got := labels(near); len(got) != 4 || got[1] != "Suva" {
+		t.Fatalf("unexpected hits %v", got)
+	}
+	idx.Put(geo.Entry{CellID: cells["Times Square"], Remove: true})
+	if got := labels(near); len(got) != 3 || idx.Len() != 3 {
+		t.Fatalf("unexpected hits %v (%d cells)", got, idx.Len())
+	}
+}
+
+func TestGeoApp(t *testing.T) {
+	idx := geo.NewIndex()
+	sess := amptest.NewSession(t, geo.NewApp(idx))
+	defer idx.Listen(sess.Bus())()
+
+	publish := func(cellID tag.ID, point *amp.GeoPoint, label string) {
+		amp.Publish(sess.Bus(), geo.IndexTopic, geo.Entry{CellID: cellID, Point: point, Label: label})
+	}
+	empire, times, far := tag.New(), tag.New(), tag.New()
+	publish(empire, empireState, "Empire State")
+	publish(far, suva, "Suva")
+
+	req := sess.Pin(amp.PinRequest{
+		PinTarget: &amp.Tag{URL: "geo://?lat=40.7484&lon=-73.9857&radius=2000"},
+		PinSync:   amp.PinSync_Maintain,
+	})
+	req.WaitForStatus(amp.OpStatus_Synced)
+	var point amp.GeoPoint
+	req.RequireAttr(empire, amp.GeoPointSpec.ID, &point)
+	if point.Point() != empireState.Point() || point.Alt != 443 {
+		t.Fatalf("unexpected point %+v", point)
+	}
+	if found, _ := req.Attr(far, amp.GeoPointSpec.ID, tag.Nil, &point); found {
+		t.Fatal("expected distant cell to be excluded")
+	}
+
+	// Hits update live as the index changes
+	publish(times, timesSquare, "Times Square")
+	deadline := time.Now().Add(5 * time.Second)
+	for found, _ := req.Attr(times, amp.GeoPointSpec.ID, tag.Nil, &point); !found; found, _ = req.Attr(times, amp.GeoPointSpec.ID, tag.Nil, &point) {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for new hit")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	req.Close()
+	req.RequireComplete()
+
+	// A region may be given as a box, or by a PinFilter, which also applies to each hit
+	req = sess.Pin(amp.PinRequest{
+		PinTarget: &amp.Tag{URL: "geo://?box=-21,176,-12,-178"},
+	})
+	req.WaitForStatus(amp.OpStatus_Synced)
+	req.RequireAttr(far, amp.GeoPointSpec.ID, &point)
+	req = sess.Pin(amp.PinRequest{
+		PinTarget: &amp.Tag{URL: "geo://"},
+		PinFilter: `location within radius(40.7484, -73.9857, 2000) && distance > 0`,
+	})
+	req.WaitForStatus(amp.OpStatus_Synced)
+	req.RequireAttr(times, amp.GeoPointSpec.ID, &point)
+	if found, _ := req.Attr(empire, amp.GeoPointSpec.ID, tag.Nil, &point); found {
+		t.Fatal("expected filter to exclude the center")
+	}
+
+	for _, url := range []string{"geo://", "geo://?box=1,2,3", "geo://?box=10,0,-10,0", "geo://?lat=91&lon=0&radius=1", "geo://?lat=0&radius=1"} {
+		if _, err := sess.TryPin(amp.PinRequest{PinTarget: &amp.Tag{URL: url}}); err == nil {
+			t.Fatalf("%q: expected bad region to be rejected", url)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+// Package geo is an opt-in spatial index subsystem: apps publish the locations of their cells to an Index (see IndexTopic),
+// and a host that registers the geo App (see NewApp) lets clients pin the cells within a bounding box or radius, nearest
+// first and live-updating.
+//
+// A host opts in with:
+//
+//	idx := geo.NewIndex()
+//	idx.Listen(bus)                  // the amp.MessageBus shared by the host's AppContexts
+//	reg.RegisterApp(geo.NewApp(idx))
+//
+// An app indexes a cell's location (typically the GeoPoint it emits as amp.GeoPointSpec) with:
+//
+//	amp.Publish(amp.BusOf(ctx), geo.IndexTopic, geo.Entry{CellID: cellID, Point: point, Label: tab.Label})
+package geo
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/amp-3d/amp-sdk-go/amp"
+	"github.com/amp-3d/amp-sdk-go/stdlib/geo"
+	"github.com/amp-3d/amp-sdk-go/stdlib/tag"
+)
+
+// IndexTopic is the MessageBus topic an Index listens to for entries (see Index.Listen).
+var IndexTopic = amp.FormTopic[Entry]("geo.index")
+
+// Entry adds, moves, or removes the indexed location of a cell.
+type Entry struct {
+	CellID tag.ID        // cell at the location
+	Point  *amp.GeoPoint // location of the cell
+	Label  string        // label for a hit on the cell -- if empty, the label last indexed is kept
+
+	// If set, the cell is removed from the index
+	Remove bool
+}
+
+// Hit is a cell within a region.
+type Hit struct {
+	CellID   tag.ID
+	Label    string
+	Point    *amp.GeoPoint
+	Distance float64 // meters from the center of the region
+}
+
+// Index is a concurrency-safe, in-memory spatial index of cell locations, backed by a geo.RTree.
+type Index struct {
+	mu      sync.RWMutex
+	tree    *geo.RTree[tag.ID]
+	places  map[tag.ID]*place
+	changed chan struct{} // closed and replaced on each change
+}
+
+type place struct {
+	point amp.GeoPoint
+	label string
+}
+
+// NewIndex returns an empty Index.
+func NewIndex() *Index {
+	return &Index{
+		tree:    geo.NewRTree[tag.ID](),
+		places:  make(map[tag.ID]*place),
+		changed: make(chan struct{}),
+	}
+}
+
+// Listen indexes each Entry published to IndexTopic on the given bus until cancel is called.
+func (idx *Index) Listen(bus amp.MessageBus) (cancel func()) {
+	return bus.Subscribe(IndexTopic.ID, func(msg any) {
+		if entry, ok := msg.(Entry); ok {
+			idx.Put(entry)
+		}
+	})
+}
+
+// Changed returns a channel that is closed the next time the index changes.
+func (idx *Index) Changed() <-chan struct{} {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return idx.changed
+}
+
+// Len returns the number of cells indexed.
+func (idx *Index) Len() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return len(idx.places)
+}
+
+// Put applies the given entry to the index, ignoring an entry whose location is missing or out of range.
+func (idx *Index) Put(entry Entry) {
+	if !entry.Remove && (entry.Point == nil || !entry.Point.Point().Valid()) {
+		return
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	pl := idx.places[entry.CellID]
+	if pl != nil {
+		idx.tree.Delete(geo.PointRect(pl.point.Point()), entry.CellID)
+	}
+	if entry.Remove {
+		if pl == nil {
+			return
+		}
+		delete(idx.places, entry.CellID)
+	} else {
+		if pl == nil {
+			pl = &place{}
+			idx.places[entry.CellID] = pl
+		}
+		pl.point = *entry.Point
+		if entry.Label != "" {
+			pl.label = entry.Label
+		}
+		idx.tree.Insert(geo.PointRect(pl.point.Point()), entry.CellID)
+	}
+
+	close(idx.changed)
+	idx.changed = make(chan struct{})
+}
+
+// Within returns the cells within the given region, nearest its center first (the center of a geo.Circle, or else of its
+// bounds), limited to the given number of hits (if > 0).
+func (idx *Index) Within(region geo.Region, limit int) []Hit {
+	center := region.Bounds().Center()
+	if circle, isCircle := region.(geo.Circle); isCircle {
+		center = circle.Center
+	}
+
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	var hits []Hit
+	idx.tree.Search(region.Bounds(), func(_ geo.Rect, cellID tag.ID) bool {
+		pl := idx.places[cellID]
+		if p := pl.point.Point(); region.Contains(p) {
+			point := pl.point
+			hits = append(hits, Hit{
+				CellID:   cellID,
+				Label:    pl.label,
+				Point:    &point,
+				Distance: geo.Distance(center, p),
+			})
+		}
+		return true
+	})
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Distance != hits[j].Distance {
+			return hits[i].Distance < hits[j].Distance
+		}
+		return hits[i].CellID.CompareTo(hits[j].CellID) < 0
+	})
+	if limit > 0 && len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits
+}
@@ -0,0 +1,493 @@
+package music
+
+import (
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/amp-3d/amp-sdk-go/amp"
+	"github.com/amp-3d/amp-sdk-go/amp/basic"
+	"github.com/amp-3d/amp-sdk-go/stdlib/media/transcode"
+	"github.com/amp-3d/amp-sdk-go/stdlib/tag"
+	"github.com/amp-3d/amp-sdk-go/stdlib/task"
+)
+
+// AuthTokenSpec is the app attr holding a user's token for a connector's service (see amp.AppContext.PutAppAttr).
+var AuthTokenSpec = tag.FormSpec(amp.AttrSpec, "music.AuthToken")
+
+// DefaultRefreshMargin is the default for Opts.RefreshMargin.
+const DefaultRefreshMargin = time.Minute
+
+// Opts configures the app serving a Connector.
+type Opts struct {
+
+	// For AuthOAuth, the URL registered with the service to redirect to once the user has signed in.
+	// A client handles the redirect by pinning {scheme}://callback with the query of the URL it was redirected to.
+	RedirectURL string
+
+	// How long before its expiry a token is refreshed (default DefaultRefreshMargin).
+	RefreshMargin time.Duration
+}
+
+// NewApp returns the App serving the given connector, whose AppSpec is "amp.app.music.{Scheme}", serving pins of URLs of the form:
+//
+//	{scheme}://login[?user={username}&password={password}&server={server URL}]
+//	{scheme}://callback?{query}
+//	{scheme}://browse/{item ID}
+//	{scheme}://search?q={query}
+//	{scheme}://track/{item ID}
+//
+// A user signs in by pinning login, with their credentials for AuthPassword.  For AuthOAuth, the app sends a LaunchURL meta attr
+// (see amp.SendMetaAttr) holding the service's sign-in page, and the sign-in completes when the client pins callback (see
+// Opts.RedirectURL).  The token is retained as an app attr (see AuthTokenSpec) and refreshed as it expires.
+//
+// Pinning browse (or just "{scheme}://" for the root of the user's library) or search pushes a window of items as child cells (see
+// basic.PagedCell), each having an amp.MediaInfo attr and a TagTab whose TagUse_Pinnable tag is the URL that pins it.
+// Pinning a track (by URL or as a child cell) resolves it to a stream for the client's Login.MediaTypes, pushed as the cell's
+// amp.ContentSpec attr -- a Tag with TagUse_Stream.  A client reports its playback of a track by committing amp.PlaybackEvent
+// attrs to the track's cell via a request for its URL, whose pin then pushes the track without resolving it again.
+func NewApp(conn Connector, opts Opts) *amp.App {
+	if opts.RefreshMargin <= 0 {
+		opts.RefreshMargin = DefaultRefreshMargin
+	}
+	info := conn.Info()
+	return &amp.App{
+		AppSpec:     tag.FormSpec(amp.AppSpec, "music."+info.Scheme),
+		Desc:        info.Desc,
+		Version:     info.Version,
+		Invocations: []string{info.Scheme},
+		NewAppInstance: func(ctx amp.AppContext) (amp.AppInstance, error) {
+			app := &appInst{
+				conn:    conn,
+				info:    info,
+				opts:    opts,
+				pending: make(map[string]struct{}),
+			}
+			app.AppContext = ctx
+			app.Instance = app
+			return app, nil
+		},
+	}
+}
+
+type appInst struct {
+	basic.App[*appInst]
+	conn Connector
+	info Info
+	opts Opts
+
+	mu      sync.Mutex
+	tok     *amp.AuthToken      // nil until signed in
+	sess    Session             // nil until opened
+	pending map[string]struct{} // states of OAuth sign-ins in progress
+}
+
+func (app *appInst) ServeRequest(req amp.Requester) (amp.Pin, error) {
+	r := req.Request()
+	if r.URL == nil {
+		return nil, amp.ErrCode_BadRequest.Error("missing pin URL")
+	}
+	itemID := strings.TrimPrefix(r.URL.Path, "/")
+
+	switch r.URL.Host {
+	case "login":
+		return app.serveLogin(req)
+	case "callback":
+		return app.serveCallback(req)
+	case "", "browse":
+		sess, err := app.session()
+		if err != nil {
+			return nil, err
+		}
+		list := app.newList(Item{ID: itemID}, func(ctx task.Context, offset, limit int64) (*Page, error) {
+			return sess.Browse(ctx, itemID, offset, limit)
+		})
+		if itemID == "" {
+			list.Tab.Label = app.info.Name
+		}
+		return app.PinAndServe(list, req)
+	case "search":
+		query := r.Values.Get("q")
+		if strings.TrimSpace(query) == "" {
+			return nil, amp.ErrCode_BadRequest.Error("missing search query (q param)")
+		}
+		sess, err := app.session()
+		if err != nil {
+			return nil, err
+		}
+		list := app.newList(Item{Kind: KindFolder, Title: query}, func(ctx task.Context, offset, limit int64) (*Page, error) {
+			return sess.Search(ctx, query, offset, limit)
+		})
+		return app.PinAndServe(list, req)
+	case "track":
+		if itemID == "" {
+			return nil, amp.ErrCode_BadRequest.Error("missing track ID")
+		}
+		track := app.newTrack(Item{ID: itemID, Kind: KindTrack})
+		if r.CommitTx != nil {
+			if err := app.reportPlayback(itemID, r.CommitTx); err != nil {
+				return nil, err
+			}
+			track.resolved = true
+		}
+		return app.PinAndServe(track, req)
+	}
+	return nil, amp.ErrCode_BadRequest.Errorf("unrecognized %s request %q", app.info.Scheme, r.URL)
+}
+
+// serveLogin signs in (or begins signing in) to the service.
+func (app *appInst) serveLogin(req amp.Requester) (amp.Pin, error) {
+	r := req.Request()
+	account := &accountCell{}
+	account.Tab.Label = app.info.Name
+
+	switch app.info.AuthFlow {
+	case AuthNone:
+		if _, err := app.session(); err != nil {
+			return nil, err
+		}
+	case AuthOAuth:
+		if app.opts.RedirectURL == "" {
+			return nil, amp.ErrCode_AuthFailed.Errorf("%s: no redirect URL configured", app.info.Name)
+		}
+		state := tag.New().Base32()
+		authURL, err := app.conn.AuthURL(state, app.opts.RedirectURL)
+		if err != nil {
+			return nil, err
+		}
+		app.mu.Lock()
+		app.pending[state] = struct{}{}
+		app.mu.Unlock()
+		if err = amp.SendMetaAttr(app.Session(), r.ID, amp.OpStatus_Synced, &amp.LaunchURL{URL: authURL}); err != nil {
+			return nil, err
+		}
+		account.Tab.Caption = "Waiting for sign-in"
+		return app.PinAndServe(account, req)
+	case AuthPassword:
+		creds := Credentials{
+			ServerURL: r.Values.Get("server"),
+			Username:  r.Values.Get("user"),
+			Password:  r.Values.Get("password"),
+		}
+		if creds.Username == "" {
+			return nil, amp.ErrCode_BadRequest.Error("missing username (user param)")
+		}
+		if err := app.authorize(creds); err != nil {
+			return nil, err
+		}
+	}
+	account.Tab.Caption = "Signed in"
+	return app.PinAndServe(account, req)
+}
+
+// serveCallback completes an OAuth sign-in begun by serveLogin.
+func (app *appInst) serveCallback(req amp.Requester) (amp.Pin, error) {
+	callback := req.Request().URL
+	state := callback.Query().Get("state")
+
+	app.mu.Lock()
+	_, pending := app.pending[state]
+	delete(app.pending, state)
+	app.mu.Unlock()
+	if !pending {
+		return nil, amp.ErrCode_AuthFailed.Errorf("%s: unknown or expired sign-in", app.info.Name)
+	}
+	if err := app.authorize(Credentials{Callback: callback}); err != nil {
+		return nil, err
+	}
+
+	account := &accountCell{}
+	account.Tab.Label = app.info.Name
+	account.Tab.Caption = "Signed in"
+	return app.PinAndServe(account, req)
+}
+
+// authorize signs in with the given credentials, retaining the resulting token and opening a session with it.
+func (app *appInst) authorize(creds Credentials) error {
+	tok, err := app.conn.Authorize(app.AppContext, creds, app.opts.RedirectURL)
+	if err != nil {
+		return err
+	}
+	if err = app.PutAppAttr(AuthTokenSpec.ID, tok); err != nil {
+		return err
+	}
+
+	app.mu.Lock()
+	defer app.mu.Unlock()
+	app.tok = tok
+	if app.sess != nil {
+		app.sess.Close()
+		app.sess = nil
+	}
+	_, err = app.sessionLocked()
+	return err
+}
+
+// session returns the open session, opening it (or reopening it with a refreshed token) as needed.
+func (app *appInst) session() (Session, error) {
+	app.mu.Lock()
+	defer app.mu.Unlock()
+	return app.sessionLocked()
+}
+
+func (app *appInst) sessionLocked() (Session, error) {
+	tok := app.tok
+	if tok == nil && app.info.AuthFlow != AuthNone {
+		tok = &amp.AuthToken{}
+		if err := app.GetAppAttr(AuthTokenSpec.ID, tok); err != nil {
+			return nil, amp.ErrCode_AuthFailed.Errorf("not signed in to %s (pin %s://login)", app.info.Name, app.info.Scheme)
+		}
+	}
+	if tok != nil && tok.Expiry != 0 && time.Now().Add(app.opts.RefreshMargin).After(time.Unix(tok.Expiry, 0)) {
+		refreshed, err := app.conn.Refresh(app.AppContext, tok)
+		if err != nil {
+			return nil, err
+		}
+		if err = app.PutAppAttr(AuthTokenSpec.ID, refreshed); err != nil {
+			return nil, err
+		}
+		tok = refreshed
+	} else if app.sess != nil {
+		return app.sess, nil
+	}
+
+	if err := app.openLocked(tok); err != nil {
+		return nil, err
+	}
+	return app.sess, nil
+}
+
+// openLocked opens a session with the given token, replacing (and closing) any previously open.
+func (app *appInst) openLocked(tok *amp.AuthToken) error {
+	sess, err := app.conn.Open(app.AppContext, tok)
+	if err != nil {
+		return err
+	}
+	if app.sess != nil {
+		app.sess.Close()
+	}
+	app.tok, app.sess = tok, sess
+	return nil
+}
+
+func (app *appInst) OnClosing() {
+	app.mu.Lock()
+	defer app.mu.Unlock()
+	if app.sess != nil {
+		app.sess.Close()
+		app.sess = nil
+	}
+}
+
+// reportPlayback reports each PlaybackEvent the given tx commits.
+func (app *appInst) reportPlayback(trackID string, commit *amp.TxMsg) error {
+	sess, err := app.session()
+	if err != nil {
+		return err
+	}
+	for i, op := range commit.Ops {
+		if op.OpCode != amp.TxOpCode_UpsertAttr || op.AttrID != amp.PlaybackEventSpec.ID {
+			continue
+		}
+		var ev amp.PlaybackEvent
+		if err = commit.UnmarshalOpValue(i, &ev); err != nil {
+			return err
+		}
+		event := PlaybackEvent{
+			TrackID:  trackID,
+			State:    ev.State,
+			Position: time.Duration(ev.PositionMs) * time.Millisecond,
+			At:       time.UnixMilli(ev.At),
+		}
+		if ev.At == 0 {
+			event.At = time.Now()
+		}
+		if err = sess.ReportPlayback(app.AppContext, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// itemURL returns the URL that pins the given item.
+func (app *appInst) itemURL(item *Item) string {
+	route := "browse"
+	if item.Kind == KindTrack {
+		route = "track"
+	}
+	return app.info.Scheme + "://" + route + "/" + url.PathEscape(item.ID)
+}
+
+func (app *appInst) newCell(item Item) basic.Cell[*appInst] {
+	if item.Kind == KindTrack {
+		return app.newTrack(item)
+	}
+	itemID := item.ID
+	return app.newList(item, func(ctx task.Context, offset, limit int64) (*Page, error) {
+		sess, err := app.session()
+		if err != nil {
+			return nil, err
+		}
+		return sess.Browse(ctx, itemID, offset, limit)
+	})
+}
+
+func (app *appInst) newList(item Item, fetch fetchFunc) *listCell {
+	list := &listCell{
+		fetch: fetch,
+	}
+	list.init(app, item)
+	return list
+}
+
+func (app *appInst) newTrack(item Item) *trackCell {
+	track := &trackCell{}
+	track.init(app, item)
+	return track
+}
+
+// itemCell is a cell for an Item.
+type itemCell struct {
+	basic.CellInfo[*appInst]
+	app  *appInst
+	item Item
+}
+
+func (cell *itemCell) init(app *appInst, item Item) {
+	cell.app = app
+	cell.item = item
+	cell.Tab = amp.TagTab{
+		Label:   item.Title,
+		Caption: item.Artist,
+		About:   item.Album,
+	}
+	if item.ID != "" || item.Kind == KindTrack {
+		cell.Tab.Tags = append(cell.Tab.Tags, &amp.Tag{
+			Use: amp.TagUse_Pinnable,
+			URL: app.itemURL(&item),
+		})
+	}
+	if item.ArtworkURL != "" {
+		cell.Tab.Tags = append(cell.Tab.Tags, &amp.Tag{
+			Use: amp.TagUse_Glyph,
+			URL: item.ArtworkURL,
+		})
+	}
+}
+
+func (cell *itemCell) PinInto(dst *basic.Pinned[*appInst]) error {
+	return nil
+}
+
+func (cell *itemCell) MarshalAttrs(pin *basic.Pin[*appInst]) {
+	cell.CellInfo.MarshalAttrs(pin)
+	if item := &cell.item; item.Title != "" && item.Kind != KindFolder {
+		pin.Upsert(cell.ID, amp.MediaInfoSpec.ID, tag.Nil, &amp.MediaInfo{
+			Title:      item.Title,
+			Artist:     item.Artist,
+			Album:      item.Album,
+			Year:       int32(item.Year),
+			TrackNum:   int32(item.TrackNum),
+			DurationMs: item.Duration.Milliseconds(),
+		})
+	}
+}
+
+type fetchFunc func(ctx task.Context, offset, limit int64) (*Page, error)
+
+// listCell is a browsable item or search, enumerating its items as a basic.PagedCell.
+type listCell struct {
+	itemCell
+	fetch fetchFunc
+
+	mu            sync.Mutex
+	page          *Page // last window fetched (or nil)
+	offset, limit int64 // of page
+}
+
+func (list *listCell) PinInto(dst *basic.Pinned[*appInst]) error {
+	_, err := list.window(0, basic.DefaultPageLimit) // so that ChildCount is known for the first window pushed
+	return err
+}
+
+func (list *listCell) ChildCount() int64 {
+	list.mu.Lock()
+	defer list.mu.Unlock()
+	if list.page == nil {
+		return -1
+	}
+	return list.page.Total
+}
+
+func (list *listCell) ChildrenAt(offset, limit int64) ([]basic.Cell[*appInst], error) {
+	page, err := list.window(offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	children := make([]basic.Cell[*appInst], 0, len(page.Items))
+	for _, item := range page.Items {
+		children = append(children, list.app.newCell(item))
+	}
+	return children, nil
+}
+
+// window returns the given window of items, fetching it unless it was the last fetched.
+func (list *listCell) window(offset, limit int64) (*Page, error) {
+	list.mu.Lock()
+	defer list.mu.Unlock()
+	if list.page != nil && list.offset == offset && list.limit == limit {
+		return list.page, nil
+	}
+	page, err := list.fetch(list.app.AppContext, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	list.page, list.offset, list.limit = page, offset, limit
+	return page, nil
+}
+
+// trackCell is a playable item, resolved to a stream when pinned.
+type trackCell struct {
+	itemCell
+	resolved bool
+	stream   *Stream // non-nil once resolved
+}
+
+func (track *trackCell) PinInto(dst *basic.Pinned[*appInst]) error {
+	if track.resolved {
+		return nil
+	}
+	app := track.app
+	sess, err := app.session()
+	if err != nil {
+		return err
+	}
+	caps := transcode.ParseCaps(app.Session().Auth().MediaTypes)
+	track.stream, err = sess.ResolveStream(app.AppContext, track.item.ID, caps)
+	if err != nil {
+		return err
+	}
+	track.resolved = true
+	return nil
+}
+
+func (track *trackCell) MarshalAttrs(pin *basic.Pin[*appInst]) {
+	track.itemCell.MarshalAttrs(pin)
+	if stream := track.stream; stream != nil {
+		pin.Upsert(track.ID, amp.ContentSpec.ID, tag.Nil, &amp.Tag{
+			Use:         amp.TagUse_Stream,
+			URL:         stream.URL,
+			ContentType: stream.ContentType,
+		})
+	}
+}
+
+// accountCell is the pinned cell of a sign-in.
+type accountCell struct {
+	basic.CellInfo[*appInst]
+}
+
+func (account *accountCell) PinInto(dst *basic.Pinned[*appInst]) error {
+	return nil
+}
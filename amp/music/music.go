@@ -0,0 +1,164 @@
+// Package music lets a streaming music service (e.g. Tidal, Qobuz, or a Subsonic server) be added to amp by implementing a
+// Connector, rather than by writing (or copying) an app of its own.
+//
+// A Connector signs a user in to its service and opens a Session on the user's account, through which the account is browsed
+// and searched, tracks are resolved to stream URLs, and playback is reported back.  NewApp serves a Connector as an amp.App,
+// so that a provider module consists only of its Connector and the call registering NewApp(conn, opts).
+package music
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/amp-3d/amp-sdk-go/amp"
+	"github.com/amp-3d/amp-sdk-go/stdlib/media/transcode"
+)
+
+// Connector is implemented by a module for each music service.
+// A Connector is safe for concurrent use and holds no per-user state -- that is held by the Sessions it opens.
+type Connector interface {
+
+	// Describes this connector's service and how a user signs in to it.
+	Info() Info
+
+	// For AuthOAuth, returns the URL of the service's sign-in page, which redirects to redirectURL with the given state once
+	// the user has signed in.
+	AuthURL(state, redirectURL string) (string, error)
+
+	// Signs in to the service, returning the token a Session is opened with.
+	// For AuthOAuth, creds.Callback is the URL the service redirected to (see AuthURL), and the given redirectURL is that passed to AuthURL.
+	// A connector may encode what it needs of creds (e.g. a server URL) into the returned token.
+	Authorize(ctx context.Context, creds Credentials, redirectURL string) (*amp.AuthToken, error)
+
+	// Returns a new token for the given token, which has expired (or is about to).
+	// Returns ErrCode_SessionExpired if the token cannot be refreshed, requiring the user to sign in again.
+	Refresh(ctx context.Context, tok *amp.AuthToken) (*amp.AuthToken, error)
+
+	// Opens a session on the account of the given token (nil for AuthNone).
+	Open(ctx context.Context, tok *amp.AuthToken) (Session, error)
+}
+
+// Session is a signed-in user's access to a music service, as opened by Connector.Open.
+// A Session is safe for concurrent use.
+//
+// Methods a service does not support return amp.ErrUnimplemented.
+type Session interface {
+
+	// Returns up to limit children of the given browsable item starting at offset, where itemID "" is the root of the user's
+	// library (e.g. "Playlists", "Albums", and "New Releases" folders).
+	Browse(ctx context.Context, itemID string, offset, limit int64) (*Page, error)
+
+	// Returns up to limit items matching the given query starting at offset, in the service's order of relevance.
+	Search(ctx context.Context, query string, offset, limit int64) (*Page, error)
+
+	// Returns a stream of the given track for a client playing the given formats (empty if not known), allowing a service
+	// offering several qualities or codecs to choose among them.
+	ResolveStream(ctx context.Context, trackID string, caps transcode.Caps) (*Stream, error)
+
+	// Reports a user's playback of a track, e.g. so the service counts a play or resumes from the reported position.
+	ReportPlayback(ctx context.Context, event PlaybackEvent) error
+
+	// Releases this session's resources.
+	Close() error
+}
+
+// Info describes a music service.
+type Info struct {
+	Name     string   // human-readable name, e.g. "Tidal"
+	Scheme   string   // URL scheme that invokes the service's app, e.g. "tidal" (see NewApp)
+	Desc     string   // human-readable description of the app
+	Version  string   // connector version (see amp.ParseVersion)
+	AuthFlow AuthFlow // how a user signs in
+}
+
+// AuthFlow is how a user signs in to a music service.
+type AuthFlow int32
+
+const (
+	AuthNone     AuthFlow = iota // no sign-in (e.g. a public server)
+	AuthOAuth                    // the user signs in on the service's sign-in page (see Connector.AuthURL)
+	AuthPassword                 // the user gives a username and password, and the server URL of a self-hosted service
+)
+
+func (flow AuthFlow) String() string {
+	switch flow {
+	case AuthNone:
+		return "none"
+	case AuthOAuth:
+		return "oauth"
+	case AuthPassword:
+		return "password"
+	}
+	return fmt.Sprintf("AuthFlow(%d)", int32(flow))
+}
+
+// Credentials are what a user gives to sign in to a music service (see Connector.Authorize).
+type Credentials struct {
+	ServerURL string   // for AuthPassword: the address of a self-hosted server (e.g. Subsonic), if applicable
+	Username  string   // for AuthPassword
+	Password  string   // for AuthPassword
+	Callback  *url.URL // for AuthOAuth: the URL the service redirected to, whose query holds the authorization code and state
+}
+
+// Kind is the kind of an Item.
+type Kind int32
+
+const (
+	KindTrack    Kind = iota + 1 // playable (see Session.ResolveStream)
+	KindAlbum                    // browsable: the album's tracks
+	KindArtist                   // browsable: e.g. the artist's albums and top tracks
+	KindPlaylist                 // browsable: the playlist's tracks
+	KindFolder                   // browsable: any other grouping, e.g. "New Releases" or a genre
+)
+
+func (kind Kind) String() string {
+	switch kind {
+	case KindTrack:
+		return "track"
+	case KindAlbum:
+		return "album"
+	case KindArtist:
+		return "artist"
+	case KindPlaylist:
+		return "playlist"
+	case KindFolder:
+		return "folder"
+	}
+	return fmt.Sprintf("Kind(%d)", int32(kind))
+}
+
+// Item is a track, album, artist, playlist, or folder of a music service.
+type Item struct {
+	ID         string        // the service's ID for this item, opaque outside its connector
+	Kind       Kind          // what this item is
+	Title      string        // track, album, artist, playlist, or folder name
+	Artist     string        // if applicable
+	Album      string        // if applicable
+	Year       int           // release year, if known
+	TrackNum   int           // position in its album, if known
+	Duration   time.Duration // if known
+	ArtworkURL string        // cover art or artist image, if any
+}
+
+// Page is a window of items returned by Session.Browse or Session.Search.
+type Page struct {
+	Items []Item
+	Total int64 // total number of items (or -1 if not known)
+}
+
+// Stream is a track resolved by Session.ResolveStream.
+type Stream struct {
+	URL         string    // where a client streams the track from
+	ContentType string    // media type of the stream, e.g. "audio/flac" or `audio/mp4; codecs="mp4a.40.2"`
+	Expires     time.Time // when URL stops working (or zero if it does not expire)
+}
+
+// PlaybackEvent is a user's playback of a track, reported via Session.ReportPlayback.
+type PlaybackEvent struct {
+	TrackID  string
+	State    amp.PlaybackState
+	Position time.Duration // playback position when the event occurred
+	At       time.Time     // when the event occurred
+}
@@ -0,0 +1,275 @@
+package music_test
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/amp-3d/amp-sdk-go/amp"
+	"github.com/amp-3d/amp-sdk-go/amp/amptest"
+	"github.com/amp-3d/amp-sdk-go/amp/music"
+	"github.com/amp-3d/amp-sdk-go/stdlib/media/transcode"
+	"github.com/amp-3d/amp-sdk-go/stdlib/tag"
+)
+
+// fakeConnector is a music service having a library of albums, each of two tracks.
+type fakeConnector struct {
+	flow music.AuthFlow
+
+	mu        sync.Mutex
+	refreshes int
+	events    []music.PlaybackEvent
+}
+
+func (conn *fakeConnector) Info() music.Info {
+	return music.Info{
+		Name:     "Fake",
+		Scheme:   "fake" + conn.flow.String(),
+		AuthFlow: conn.flow,
+	}
+}
+
+func (conn *fakeConnector) AuthURL(state, redirectURL string) (string, error) {
+	return "https://fake.example.com/authorize?state=" + url.QueryEscape(state) + "&redirect_uri=" + url.QueryEscape(redirectURL), nil
+}
+
+func (conn *fakeConnector) Authorize(ctx context.Context, creds music.Credentials, redirectURL string) (*amp.AuthToken, error) {
+	switch conn.flow {
+	case music.AuthOAuth:
+		if code := creds.Callback.Query().Get("code"); code != "good" {
+			return nil, amp.ErrCode_AuthFailed.Errorf("bad code %q", code)
+		}
+	case music.AuthPassword:
+		if creds.Password != "secret" {
+			return nil, amp.ErrCode_AuthFailed.Error("wrong password")
+		}
+	}
+	return &amp.AuthToken{
+		AccessToken:  "access",
+		RefreshToken: "refresh",
+		Expiry:       time.Now().Add(-time.Second).Unix(), // so the first session is opened with a refreshed token
+	}, nil
+}
+
+func (conn *fakeConnector) Refresh(ctx context.Context, tok *amp.AuthToken) (*amp.AuthToken, error) {
+	conn.mu.Lock()
+	conn.refreshes++
+	conn.mu.Unlock()
+	return &amp.AuthToken{
+		AccessToken:  "refreshed",
+		RefreshToken: tok.RefreshToken,
+		Expiry:       time.Now().Add(time.Hour).Unix(),
+	}, nil
+}
+
+func (conn *fakeConnector) Open(ctx context.Context, tok *amp.AuthToken) (music.Session, error) {
+	if conn.flow != music.AuthNone && tok.AccessToken != "refreshed" {
+		return nil, amp.ErrCode_AuthFailed.Errorf("stale token %q", tok.AccessToken)
+	}
+	return &fakeSession{conn: conn}, nil
+}
+
+type fakeSession struct {
+	conn *fakeConnector
+}
+
+func (sess *fakeSession) Browse(ctx context.Context, itemID string, offset, limit int64) (*music.Page, error) {
+	var items []music.Item
+	switch {
+	case itemID == "":
+		for i := 0; i < 3; i++ {
+			items = append(items, music.Item{ID: fmt.Sprintf("album/%d", i), Kind: music.KindAlbum, Title: fmt.Sprintf("Album %d", i), Artist: "Band"})
+		}
+	case strings.HasPrefix(itemID, "album/"):
+		for i := 1; i <= 2; i++ {
+			items = append(items, music.Item{
+				ID:       fmt.Sprintf("%s/track/%d", itemID, i),
+				Kind:     music.KindTrack,
+				Title:    fmt.Sprintf("Track %d", i),
+				Artist:   "Band",
+				TrackNum: i,
+				Duration: time.Minute,
+			})
+		}
+	default:
+		return nil, amp.ErrCellNotFound
+	}
+	page := &music.Page{Total: int64(len(items))}
+	if offset < int64(len(items)) {
+		page.Items = items[offset:min(offset+limit, int64(len(items)))]
+	}
+	return page, nil
+}
+
+func (sess *fakeSession) Search(ctx context.Context, query string, offset, limit int64) (*music.Page, error) {
+	return nil, amp.ErrUnimplemented
+}
+
+func (sess *fakeSession) ResolveStream(ctx context.Context, trackID string, caps transcode.Caps) (*music.Stream, error) {
+	stream := &music.Stream{
+		URL:         "https://cdn.example.com/" + trackID + ".mp3",
+		ContentType: "audio/mpeg",
+	}
+	if flac, _ := transcode.ParseFormat("audio/flac"); caps.Plays(flac) {
+		stream.URL = "https://cdn.example.com/" + trackID + ".flac"
+		stream.ContentType = flac.String()
+	}
+	return stream, nil
+}
+
+func (sess *fakeSession) ReportPlayback(ctx context.Context, event music.PlaybackEvent) error {
+	sess.conn.mu.Lock()
+	sess.conn.events = append(sess.conn.events, event)
+	sess.conn.mu.Unlock()
+	return nil
+}
+
+func (sess *fakeSession) Close() error {
+	return nil
+}
+
+// childTabs returns the cell ID of each child cell pushed for the given request by label.
+func childTabs(req *amptest.Request) map[string]tag.ID {
+	children := make(map[string]tag.ID)
+	for _, cellID := range req.Cells() {
+		var tab amp.TagTab
+		if found, _ := req.Attr(cellID, amp.ChildTabSpec.ID, tag.Nil, &tab); found {
+			children[tab.Label] = cellID
+		}
+	}
+	return children
+}
+
+func TestPasswordConnector(t *testing.T) {
+	conn := &fakeConnector{flow: music.AuthPassword}
+	sess := amptest.NewSession(t, music.NewApp(conn, music.Opts{}))
+	sess.Login.MediaTypes = []string{"audio/mpeg", "audio/flac"}
+
+	if _, err := sess.TryPin(amp.PinRequest{PinTarget: &amp.Tag{URL: "fakepassword://"}}); err == nil {
+		t.Fatal("expected browse to require sign-in")
+	}
+	if _, err := sess.TryPin(amp.PinRequest{PinTarget: &amp.Tag{URL: "fakepassword://login?user=me&password=wrong"}}); err == nil {
+		t.Fatal("expected wrong password to be rejected")
+	}
+	login := sess.PinURL("fakepassword://login?user=me&password=secret")
+	login.WaitForStatus(amp.OpStatus_Synced)
+	if conn.refreshes != 1 {
+		t.Fatalf("expected expired token to be refreshed once, got %d", conn.refreshes)
+	}
+
+	// The library root and its albums are browsable
+	root := sess.PinURL("fakepassword://")
+	root.WaitForStatus(amp.OpStatus_Synced)
+	albums := childTabs(root)
+	if len(albums) != 3 {
+		t.Fatalf("unexpected albums %v", albums)
+	}
+	var tab amp.TagTab
+	root.RequireAttr(albums["Album 1"], amp.ChildTabSpec.ID, &tab)
+	if tab.Caption != "Band" || len(tab.Tags) != 1 || tab.Tags[0].Use != amp.TagUse_Pinnable {
+		t.Fatalf("unexpected album tab %+v", tab)
+	}
+
+	album := sess.PinURL(tab.Tags[0].URL)
+	album.WaitForStatus(amp.OpStatus_Synced)
+	tracks := childTabs(album)
+	var info amp.MediaInfo
+	album.RequireAttr(tracks["Track 2"], amp.MediaInfoSpec.ID, &info)
+	if info.TrackNum != 2 || info.DurationMs != 60000 {
+		t.Fatalf("unexpected track info %+v", info)
+	}
+	tab = amp.TagTab{}
+	album.RequireAttr(tracks["Track 2"], amp.ChildTabSpec.ID, &tab)
+	if want := "fakepassword://track/album%2F1%2Ftrack%2F2"; tab.Tags[0].URL != want {
+		t.Fatalf("expected track URL %q, got %q", want, tab.Tags[0].URL)
+	}
+
+	// A track resolves to the best stream the client plays
+	track := sess.PinURL(tab.Tags[0].URL)
+	track.WaitForStatus(amp.OpStatus_Synced)
+	var stream amp.Tag
+	track.RequireAttr(track.Cells()[0], amp.ContentSpec.ID, &stream)
+	if stream.Use != amp.TagUse_Stream || stream.ContentType != "audio/flac" || stream.URL != "https://cdn.example.com/album/1/track/2.flac" {
+		t.Fatalf("unexpected stream %+v", stream)
+	}
+
+	// Playback is reported via commits to the track
+	commit := amp.NewTxMsg(true)
+	at := time.UnixMilli(1700000000000)
+	if err := commit.MarshalUpsert(track.Cells()[0], amp.PlaybackEventSpec.ID, &amp.PlaybackEvent{
+		State:      amp.PlaybackState_Finished,
+		PositionMs: 60000,
+		At:         at.UnixMilli(),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	report, err := sess.TryCommit(amp.PinRequest{PinTarget: &amp.Tag{URL: tab.Tags[0].URL}}, commit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	report.WaitForStatus(amp.OpStatus_Synced)
+	if found, _ := report.Attr(report.Cells()[0], amp.ContentSpec.ID, tag.Nil, &stream); found {
+		t.Fatal("expected a playback report not to resolve the track again")
+	}
+	want := music.PlaybackEvent{TrackID: "album/1/track/2", State: amp.PlaybackState_Finished, Position: time.Minute, At: at}
+	if len(conn.events) != 1 || conn.events[0] != want {
+		t.Fatalf("unexpected playback events %+v", conn.events)
+	}
+
+	if _, err = sess.TryPin(amp.PinRequest{PinTarget: &amp.Tag{URL: "fakepassword://search?q=band"}}); err == nil {
+		t.Fatal("expected unsupported search to fail")
+	}
+	if conn.refreshes != 1 {
+		t.Fatalf("expected refreshed token to be reused, got %d refreshes", conn.refreshes)
+	}
+}
+
+func TestOAuthConnector(t *testing.T) {
+	conn := &fakeConnector{flow: music.AuthOAuth}
+	sess := amptest.NewSession(t, music.NewApp(conn, music.Opts{
+		RedirectURL: "https://amp.example.com/oauth",
+	}))
+
+	login := sess.PinURL("fakeoauth://login")
+	login.WaitForStatus(amp.OpStatus_Synced)
+	sent := sess.SentTxs()
+	if len(sent) != 1 {
+		t.Fatalf("expected a LaunchURL to be sent, got %d txs", len(sent))
+	}
+	var launch amp.LaunchURL
+	if err := sent[0].UnmarshalOpValue(0, &launch); err != nil {
+		t.Fatal(err)
+	}
+	authURL, err := url.Parse(launch.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if authURL.Query().Get("redirect_uri") != "https://amp.example.com/oauth" {
+		t.Fatalf("unexpected auth URL %q", launch.URL)
+	}
+	state := authURL.Query().Get("state")
+
+	if _, err = sess.TryPin(amp.PinRequest{PinTarget: &amp.Tag{URL: "fakeoauth://callback?code=good&state=other"}}); err == nil {
+		t.Fatal("expected unknown state to be rejected")
+	}
+	callback := sess.PinURL("fakeoauth://callback?code=good&state=" + url.QueryEscape(state))
+	callback.WaitForStatus(amp.OpStatus_Synced)
+	if _, err = sess.TryPin(amp.PinRequest{PinTarget: &amp.Tag{URL: "fakeoauth://callback?code=good&state=" + url.QueryEscape(state)}}); err == nil {
+		t.Fatal("expected state to be used only once")
+	}
+
+	root := sess.PinURL("fakeoauth://")
+	root.WaitForStatus(amp.OpStatus_Synced)
+	if albums := childTabs(root); len(albums) != 3 {
+		t.Fatalf("unexpected albums %v", albums)
+	}
+	var page amp.PageInfo
+	root.RequireAttr(root.Cells()[0], amp.PageInfoSpec.ID, &page)
+	if page.Total != 3 || page.Count != 3 {
+		t.Fatalf("unexpected page %+v", page)
+	}
+}
@@ -0,0 +1,344 @@
+// Package quic is a QUIC amp.Transport (via quic-go), an alternative to a TCP link between a host and client.
+//
+// Each side sends the txs of each request on its own unidirectional stream, opened on the request's first tx and closed by
+// a tx with OpStatus_Closed.  Txs are framed the same way as other transports (see amp.TxHeader), and txs that have no
+// request ID share a control stream.  Since QUIC streams are flow controlled independently, a large tx (e.g. an asset)
+// only stalls its own pin rather than every pin in the session.
+//
+// A host selects this transport at startup by attaching the HostService returned by NewService.  Clients connect via Dial.
+//
+// It is a separate module so that hosts not using it do not depend on quic-go.
+package quic
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"io"
+	"net"
+	"sync"
+
+	"github.com/amp-3d/amp-sdk-go/amp"
+	"github.com/amp-3d/amp-sdk-go/stdlib/tag"
+	"github.com/amp-3d/amp-sdk-go/stdlib/task"
+	quicgo "github.com/quic-go/quic-go"
+)
+
+const (
+	// ALPN is the TLS application protocol negotiated by hosts and clients.
+	ALPN = "amp.tx"
+
+	DefaultStreamQueue = 64
+)
+
+// Opts configures the QUIC transport.
+type Opts struct {
+	Addr        string         // UDP listen address used by NewService (e.g. ":5193")
+	TLSConfig   *tls.Config    // required; ALPN is added to NextProtos
+	Config      *quicgo.Config // optional quic-go settings (e.g. flow control windows and idle timeout)
+	StreamQueue int            // txs queued per stream before SendTx blocks (default DefaultStreamQueue)
+}
+
+// Dial connects to a host at the given UDP address.
+func Dial(ctx context.Context, addr string, opts Opts) (amp.Transport, error) {
+	qc, err := quicgo.DialAddr(ctx, addr, withALPN(opts.TLSConfig), opts.Config)
+	if err != nil {
+		return nil, amp.ErrCode_NotConnected.Errorf("quic dial: %v", err)
+	}
+	return newConn("quic:"+addr, qc, opts), nil
+}
+
+// NewService returns a HostService that accepts QUIC connections at opts.Addr and starts a HostSession for each.
+func NewService(opts Opts) amp.HostService {
+	return &service{
+		opts: opts,
+	}
+}
+
+type service struct {
+	task.Context
+	opts     Opts
+	host     amp.Host
+	listener *quicgo.Listener
+}
+
+func (svc *service) StartService(on amp.Host) error {
+	if svc.opts.TLSConfig == nil {
+		return amp.ErrCode_BadValue.Error("quic: Opts.TLSConfig must be set")
+	}
+	svc.host = on
+
+	listener, err := quicgo.ListenAddr(svc.opts.Addr, withALPN(svc.opts.TLSConfig), svc.opts.Config)
+	if err != nil {
+		return err
+	}
+	svc.listener = listener
+
+	svc.Context, err = on.StartChild(&task.Task{
+		Label: "quic.Service " + listener.Addr().String(),
+		OnClosing: func() {
+			listener.Close()
+		},
+	})
+	if err != nil {
+		listener.Close()
+		return err
+	}
+
+	svc.Context.Go("accept", func(ctx task.Context) {
+		for {
+			qc, err := listener.Accept(context.Background())
+			if err != nil {
+				if !errors.Is(err, quicgo.ErrServerClosed) {
+					ctx.Error(err)
+				}
+				return
+			}
+			tr := newConn("quic:"+qc.RemoteAddr().String(), qc, svc.opts)
+			if _, err = svc.host.StartNewSession(svc, tr); err != nil {
+				ctx.Warnf("failed to start session for %s: %v", tr.Label(), err)
+				tr.Close()
+			}
+		}
+	})
+	return nil
+}
+
+// Addr returns the address the service is listening on (e.g. if opts.Addr specified port 0).
+func (svc *service) Addr() net.Addr {
+	return svc.listener.Addr()
+}
+
+func (svc *service) GracefulStop() {
+	svc.listener.Close()
+}
+
+func withALPN(cfg *tls.Config) *tls.Config {
+	if cfg == nil {
+		cfg = &tls.Config{}
+	} else {
+		cfg = cfg.Clone()
+	}
+	for _, proto := range cfg.NextProtos {
+		if proto == ALPN {
+			return cfg
+		}
+	}
+	cfg.NextProtos = append(cfg.NextProtos, ALPN)
+	return cfg
+}
+
+// conn is a QUIC connection carrying the txs of each request on its own stream, implementing amp.Transport.
+type conn struct {
+	label   string
+	qc      quicgo.Connection
+	opts    Opts
+	recv    chan *amp.TxMsg // txs read from all streams
+	closing chan struct{}
+
+	mu      sync.Mutex
+	streams map[tag.ID]*sendStream // by request ID
+	sendErr error                  // set once a stream fails, failing the link
+	recvErr error                  // set once no more streams can be accepted
+	done    chan struct{}          // closed once recvErr is set
+
+	closeOnce sync.Once
+}
+
+var _ amp.Transport = (*conn)(nil)
+
+func newConn(label string, qc quicgo.Connection, opts Opts) *conn {
+	if opts.StreamQueue <= 0 {
+		opts.StreamQueue = DefaultStreamQueue
+	}
+	c := &conn{
+		label:   label,
+		qc:      qc,
+		opts:    opts,
+		recv:    make(chan *amp.TxMsg, opts.StreamQueue),
+		closing: make(chan struct{}),
+		streams: make(map[tag.ID]*sendStream),
+		done:    make(chan struct{}),
+	}
+	go c.acceptStreams()
+	return c
+}
+
+func (c *conn) Label() string {
+	return c.label
+}
+
+func (c *conn) Close() error {
+	c.closeOnce.Do(func() {
+		close(c.closing)
+		c.qc.CloseWithError(0, "")
+	})
+	return nil
+}
+
+// SendTx queues the tx on its request's stream, blocking only if that stream's queue is full.
+func (c *conn) SendTx(tx *amp.TxMsg) error {
+	reqID := tx.RequestID()
+
+	c.mu.Lock()
+	if err := c.sendErr; err != nil {
+		c.mu.Unlock()
+		tx.ReleaseRef()
+		return err
+	}
+	stream := c.streams[reqID]
+	if stream == nil {
+		stream = &sendStream{
+			conn: c,
+			txs:  make(chan *amp.TxMsg, c.opts.StreamQueue),
+		}
+		c.streams[reqID] = stream
+		go stream.writeTxs()
+	}
+	closed := tx.Status == amp.OpStatus_Closed && reqID.IsSet()
+	if closed {
+		delete(c.streams, reqID)
+	}
+	c.mu.Unlock()
+
+	return stream.enqueue(tx, closed)
+}
+
+// RecvTx returns the next tx received on any stream.
+func (c *conn) RecvTx() (*amp.TxMsg, error) {
+	select {
+	case tx := <-c.recv:
+		return tx, nil
+	default:
+	}
+	select {
+	case tx := <-c.recv:
+		return tx, nil
+	case <-c.done:
+		return nil, c.recvErr
+	}
+}
+
+func (c *conn) acceptStreams() {
+	ctx := c.qc.Context()
+	for {
+		stream, err := c.qc.AcceptUniStream(ctx)
+		if err != nil {
+			c.recvErr = c.linkErr(err)
+			close(c.done)
+			return
+		}
+		go c.readTxs(stream)
+	}
+}
+
+func (c *conn) readTxs(stream quicgo.ReceiveStream) {
+	br := bufio.NewReader(stream)
+	for {
+		tx, err := amp.ReadTxMsg(br)
+		if err != nil {
+			if err != io.EOF {
+				stream.CancelRead(0)
+			}
+			return
+		}
+		select {
+		case c.recv <- tx:
+		case <-c.done:
+			tx.ReleaseRef()
+			return
+		}
+	}
+}
+
+// linkErr maps an error from quic-go to the error returned to the transport's user.
+func (c *conn) linkErr(err error) error {
+	var appErr *quicgo.ApplicationError
+	var idleErr *quicgo.IdleTimeoutError
+	switch {
+	case errors.As(err, &appErr), errors.Is(err, net.ErrClosed), errors.Is(err, context.Canceled):
+		return amp.ErrStreamClosed
+	case errors.As(err, &idleErr):
+		return amp.ErrSessionDead
+	default:
+		return amp.ErrCode_NotConnected.Errorf("quic: %v", err)
+	}
+}
+
+func (c *conn) failSend(err error) {
+	c.mu.Lock()
+	if c.sendErr == nil {
+		c.sendErr = c.linkErr(err)
+	}
+	c.mu.Unlock()
+}
+
+// sendStream writes the queued txs of one request to a stream, opening it on the first tx.
+type sendStream struct {
+	conn   *conn
+	mu     sync.Mutex // orders concurrent enqueues
+	closed bool       // set once the request's closing tx is queued
+	txs    chan *amp.TxMsg
+}
+
+func (s *sendStream) enqueue(tx *amp.TxMsg, last bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		tx.ReleaseRef()
+		return amp.ErrCode_BadRequest.Error("quic: tx sent after its request was closed")
+	}
+	select {
+	case s.txs <- tx:
+	case <-s.conn.closing:
+		tx.ReleaseRef()
+		return amp.ErrStreamClosed
+	}
+	if last {
+		s.closed = true
+		close(s.txs)
+	}
+	return nil
+}
+
+func (s *sendStream) writeTxs() {
+	var (
+		stream quicgo.SendStream
+		err    error
+	)
+	defer func() {
+		if stream == nil {
+			return
+		}
+		if err == nil {
+			stream.Close()
+		} else {
+			stream.CancelWrite(0)
+		}
+	}()
+
+	for {
+		var tx *amp.TxMsg
+		select {
+		case tx = <-s.txs:
+			if tx == nil {
+				return
+			}
+		case <-s.conn.closing:
+			err = amp.ErrStreamClosed
+			return
+		}
+
+		if err == nil && stream == nil {
+			stream, err = s.conn.qc.OpenUniStreamSync(s.conn.qc.Context())
+		}
+		if err == nil {
+			if _, err = tx.WriteTo(stream); err != nil {
+				s.conn.failSend(err)
+			}
+		}
+		tx.ReleaseRef()
+	}
+}
@@ -0,0 +1,135 @@
+package quic
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/amp-3d/amp-sdk-go/amp"
+	"github.com/amp-3d/amp-sdk-go/stdlib/tag"
+	quicgo "github.com/quic-go/quic-go"
+)
+
+func testCert(t *testing.T) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+func TestPerPinStreams(t *testing.T) {
+	listener, err := quicgo.ListenAddr("127.0.0.1:0", withALPN(&tls.Config{Certificates: []tls.Certificate{testCert(t)}}), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	// The host echoes every tx back on the same request's stream
+	hostCh := make(chan *conn, 1)
+	go func() {
+		qc, err := listener.Accept(context.Background())
+		if err != nil {
+			return
+		}
+		host := newConn("host", qc, Opts{})
+		hostCh <- host
+		for {
+			tx, err := host.RecvTx()
+			if err != nil {
+				return
+			}
+			host.SendTx(tx)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	tr, err := Dial(ctx, listener.Addr().String(), Opts{
+		TLSConfig: &tls.Config{InsecureSkipVerify: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := tr.(*conn)
+	defer client.Close()
+	host := <-hostCh
+
+	const numPins, numTxs = 4, 20
+	reqIDs := make([]tag.ID, numPins)
+	for i := range reqIDs {
+		reqIDs[i] = tag.New()
+	}
+
+	// The first pin carries a large tx, which must not hold up the others
+	for n := 0; n < numTxs; n++ {
+		for i, reqID := range reqIDs {
+			tx := amp.NewTxMsg(true)
+			tx.SetRequestID(reqID)
+			size := 1
+			if i == 0 && n == 0 {
+				size = 4 << 20
+			}
+			tx.MarshalUpsert(reqID, amp.PinnedTabSpec.ID, &amp.Tag{URL: fmt.Sprint(n), Attachment: make([]byte, size)})
+			if n == numTxs-1 {
+				tx.Status = amp.OpStatus_Closed
+			}
+			if err = client.SendTx(tx); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	next := make(map[tag.ID]int)
+	for received := 0; received < numPins*numTxs; received++ {
+		tx, err := client.RecvTx()
+		if err != nil {
+			t.Fatal(err)
+		}
+		val := &amp.Tag{}
+		if err = tx.UnmarshalOpValue(0, val); err != nil {
+			t.Fatal(err)
+		}
+		reqID := tx.RequestID()
+		if val.URL != fmt.Sprint(next[reqID]) {
+			t.Fatalf("request %v: expected tx %d, got %s", reqID, next[reqID], val.URL)
+		}
+		next[reqID]++
+		tx.ReleaseRef()
+	}
+	for _, reqID := range reqIDs {
+		if next[reqID] != numTxs {
+			t.Fatalf("request %v: expected %d txs, got %d", reqID, numTxs, next[reqID])
+		}
+	}
+
+	// Closed requests release their streams
+	client.mu.Lock()
+	open := len(client.streams)
+	client.mu.Unlock()
+	if open != 0 {
+		t.Fatalf("expected all client streams to be closed, %d open", open)
+	}
+
+	client.Close()
+	if _, err = host.RecvTx(); err != nil && err != amp.ErrStreamClosed {
+		t.Fatalf("expected ErrStreamClosed, got %v", err)
+	}
+}
@@ -0,0 +1,264 @@
+package scripting
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/amp-3d/amp-sdk-go/amp"
+	"github.com/amp-3d/amp-sdk-go/stdlib/tag"
+	"go.starlark.net/starlark"
+)
+
+// Kinds of Event.
+const (
+	CommitEvent = "commit" // passed to on_commit with a request's CommitTx
+	PinEvent    = "pin"    // passed to on_pin with a tx pushed to a request
+)
+
+// Event is the value passed to a script's hooks, the script's only access to the host.  Its fields are:
+//
+//	event.kind      "commit" or "pin" (see CommitEvent and PinEvent)
+//	event.app       canonic spec of the app serving the request (e.g. "amp.app.filesys")
+//	event.url       URL of the request's pin target (or "")
+//	event.target    ID of the request's pinned cell (or "" if pinned by URL)
+//	event.cells     IDs of the cells the tx has ops for, in the order first seen
+//
+// and its methods are:
+//
+//	event.get(cell, attr, default=None)    the value of the given cell attr upserted by the tx (or set by a script), or default
+//	event.set(cell, attr, value)           upserts the given value, replacing any the tx upserts
+//	event.delete(cell, attr)               deletes the given cell attr, replacing any upserts of it
+//
+// where cell is a cell ID (see tag.ID.ShortString) and attr is an attr spec (e.g. "amp.attr.pinned.TagTab").
+// A value is a dict of the fields of the attr's type (see amp.Registry.NewAttrElem), where a field that is itself a struct is a
+// dict and a repeated field is a list, e.g. {"Label": "Blue Train", "Caption": "", ..., "Tags": []}.  Fields omitted from a
+// value passed to set are zero.
+//
+// Only attr items of SI tag.Nil are visible to and replaced by scripts; other ops are passed through unchanged.
+type Event struct {
+	kind   string
+	app    *scriptedApp
+	req    *amp.Request
+	tx     *amp.TxMsg
+	cells  []tag.ID
+	edits  map[attrKey]amp.ElemVal // values set by scripts, where nil denotes a deleted attr
+	order  []attrKey               // keys of edits in the order first edited
+	frozen bool
+}
+
+type attrKey struct {
+	cellID tag.ID
+	attrID tag.ID
+}
+
+var (
+	_ starlark.Value    = (*Event)(nil)
+	_ starlark.HasAttrs = (*Event)(nil)
+)
+
+func newEvent(kind string, app *scriptedApp, req *amp.Request, tx *amp.TxMsg) *Event {
+	event := &Event{
+		kind:  kind,
+		app:   app,
+		req:   req,
+		tx:    tx,
+		edits: make(map[attrKey]amp.ElemVal),
+	}
+	seen := make(map[tag.ID]struct{})
+	for _, op := range tx.Ops {
+		if _, dupe := seen[op.TargetID]; dupe || op.TargetID.IsNil() {
+			continue
+		}
+		seen[op.TargetID] = struct{}{}
+		event.cells = append(event.cells, op.TargetID)
+	}
+	return event
+}
+
+func (event *Event) String() string        { return fmt.Sprintf("<%s event>", event.kind) }
+func (event *Event) Type() string          { return "event" }
+func (event *Event) Truth() starlark.Bool  { return starlark.True }
+func (event *Event) Freeze()               { event.frozen = true }
+func (event *Event) Hash() (uint32, error) { return 0, errors.New("unhashable type: event") }
+func (event *Event) AttrNames() []string   { return eventAttrs }
+func (event *Event) method(name string) *starlark.Builtin {
+	return eventMethods[name].BindReceiver(event)
+}
+
+var eventAttrs = []string{"app", "cells", "delete", "get", "kind", "set", "target", "url"}
+
+var eventMethods = map[string]*starlark.Builtin{
+	"get":    starlark.NewBuiltin("get", eventGet),
+	"set":    starlark.NewBuiltin("set", eventSet),
+	"delete": starlark.NewBuiltin("delete", eventDelete),
+}
+
+func (event *Event) Attr(name string) (starlark.Value, error) {
+	switch name {
+	case "kind":
+		return starlark.String(event.kind), nil
+	case "app":
+		return starlark.String(event.app.app), nil
+	case "url":
+		if target := event.req.PinTarget; target != nil {
+			return starlark.String(target.URL), nil
+		}
+		return starlark.String(""), nil
+	case "target":
+		if cellID := event.req.TargetID(); cellID.IsSet() {
+			return starlark.String(cellID.ShortString()), nil
+		}
+		return starlark.String(""), nil
+	case "cells":
+		cells := make([]starlark.Value, len(event.cells))
+		for i, cellID := range event.cells {
+			cells[i] = starlark.String(cellID.ShortString())
+		}
+		return starlark.NewList(cells), nil
+	case "get", "set", "delete":
+		return event.method(name), nil
+	}
+	return nil, nil
+}
+
+func eventGet(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var cell, attr string
+	var dflt starlark.Value = starlark.None
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "cell", &cell, "attr", &attr, "default?", &dflt); err != nil {
+		return nil, err
+	}
+	key, err := parseAttrKey(cell, attr)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", b.Name(), err)
+	}
+	val, err := b.Receiver().(*Event).get(key)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", b.Name(), err)
+	}
+	if val == nil {
+		return dflt, nil
+	}
+	return toValue(val), nil
+}
+
+func eventSet(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var cell, attr string
+	var value *starlark.Dict
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "cell", &cell, "attr", &attr, "value", &value); err != nil {
+		return nil, err
+	}
+	event := b.Receiver().(*Event)
+	key, err := parseAttrKey(cell, attr)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", b.Name(), err)
+	}
+	val, err := event.app.Session().NewAttrElem(key.attrID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: unknown attr %s", b.Name(), attr)
+	}
+	if err = fromValue(value, val); err != nil {
+		return nil, fmt.Errorf("%s: %s value: %w", b.Name(), attr, err)
+	}
+	if err = event.edit(key, val); err != nil {
+		return nil, fmt.Errorf("%s: %w", b.Name(), err)
+	}
+	return starlark.None, nil
+}
+
+func eventDelete(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var cell, attr string
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "cell", &cell, "attr", &attr); err != nil {
+		return nil, err
+	}
+	key, err := parseAttrKey(cell, attr)
+	if err == nil {
+		err = b.Receiver().(*Event).edit(key, nil)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", b.Name(), err)
+	}
+	return starlark.None, nil
+}
+
+func parseAttrKey(cell, attr string) (attrKey, error) {
+	cellID, err := tag.ParseID(cell)
+	if err != nil {
+		return attrKey{}, fmt.Errorf("invalid cell ID %q", cell)
+	}
+	if attr == "" {
+		return attrKey{}, errors.New("missing attr spec")
+	}
+	return attrKey{
+		cellID: cellID,
+		attrID: tag.FormSpec(tag.Spec{}, attr).ID,
+	}, nil
+}
+
+// get returns the value of the given attr as set by a script or as last upserted by the tx, or nil if it has neither.
+func (event *Event) get(key attrKey) (amp.ElemVal, error) {
+	if val, edited := event.edits[key]; edited {
+		return val, nil
+	}
+	for i := len(event.tx.Ops) - 1; i >= 0; i-- {
+		op := &event.tx.Ops[i]
+		if op.TargetID != key.cellID || op.AttrID != key.attrID || op.SI.IsSet() {
+			continue
+		}
+		switch op.OpCode {
+		case amp.TxOpCode_UpsertAttr:
+			val, err := event.app.Session().NewAttrElem(key.attrID)
+			if err == nil {
+				err = event.tx.UnmarshalOpValue(i, val)
+			}
+			return val, err
+		case amp.TxOpCode_DeleteAttr:
+			return nil, nil
+		}
+	}
+	return nil, nil
+}
+
+func (event *Event) edit(key attrKey, val amp.ElemVal) error {
+	if event.frozen {
+		return errors.New("event is no longer active")
+	}
+	if _, exists := event.edits[key]; !exists {
+		event.order = append(event.order, key)
+	}
+	event.edits[key] = val
+	return nil
+}
+
+// rewrite returns the tx with the edits of scripts applied, or the tx itself if there are none.
+// Ops of edited attrs are dropped, and each edit is then appended as an upsert or delete op.
+func (event *Event) rewrite() (*amp.TxMsg, error) {
+	if len(event.edits) == 0 {
+		return event.tx, nil
+	}
+	rewritten := event.tx.Derive()
+	for i := range event.tx.Ops {
+		op := &event.tx.Ops[i]
+		if op.SI.IsNil() && (op.OpCode == amp.TxOpCode_UpsertAttr || op.OpCode == amp.TxOpCode_DeleteAttr) {
+			if _, edited := event.edits[attrKey{op.TargetID, op.AttrID}]; edited {
+				continue
+			}
+		}
+		rewritten.CarryOp(op)
+	}
+	for _, key := range event.order {
+		op := amp.TxOp{
+			OpCode:   amp.TxOpCode_UpsertAttr,
+			TargetID: key.cellID,
+			AttrID:   key.attrID,
+		}
+		val := event.edits[key]
+		if val == nil {
+			op.OpCode = amp.TxOpCode_DeleteAttr
+		}
+		if err := rewritten.MarshalOp(&op, val); err != nil {
+			rewritten.ReleaseRef()
+			return nil, err
+		}
+	}
+	return rewritten, nil
+}
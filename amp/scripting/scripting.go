@@ -0,0 +1,273 @@
+// Package scripting lets a host attach user-defined automation to the cells its apps serve -- e.g. auto-tagging or renaming
+// rules -- as Starlark scripts (see go.starlark.net) rather than as custom apps that must be compiled and deployed.
+//
+// A script defines either or both of the hooks:
+//
+//	def on_commit(event):   # called with each commit to an app before the app applies it
+//	def on_pin(event):      # called with each tx an app pushes to a pin before the client receives it
+//
+// where event offers the only access a script has to the host (see Event for its fields and methods), e.g.:
+//
+//	def on_commit(event):
+//	    for cell in event.cells:
+//	        tab = event.get(cell, "amp.attr.pinned.TagTab")
+//	        if tab and tab["Label"] != tab["Label"].strip():
+//	            tab["Label"] = tab["Label"].strip()
+//	            event.set(cell, "amp.attr.pinned.TagTab", tab)
+//
+// A host opts in by loading its scripts into an Engine and wrapping each AppInstance it issues:
+//
+//	engine, err := scripting.NewEngine(scripting.EngineOpts{}, scripts...)
+//	...
+//	inst = engine.Instrument(app, inst)
+//
+// Scripts are sandboxed: they have no access to the file system, network, clock, or other cells, and each hook call is
+// bounded by EngineOpts.MaxSteps (and cancelled if the app instance closes), so a runaway script fails the commit or push it
+// was called for rather than stalling the app.
+//
+// It is a separate module so that hosts not using it do not depend on Starlark.
+package scripting
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/amp-3d/amp-sdk-go/amp"
+	"github.com/amp-3d/amp-sdk-go/stdlib/tag"
+	"go.starlark.net/starlark"
+)
+
+// DefaultMaxSteps is the default for EngineOpts.MaxSteps.
+const DefaultMaxSteps = 100000
+
+// Script is the source of a script and the apps it applies to.
+type Script struct {
+	Name   string   // names the script in errors (e.g. "autotag.star")
+	Source string   // Starlark source defining on_commit and/or on_pin
+	Apps   []tag.ID // if set, the script only applies to instances of these apps (by AppSpec.ID)
+}
+
+// EngineOpts configures an Engine.
+type EngineOpts struct {
+	MaxSteps uint64                          // steps a script may spend loading or in each hook call (default DefaultMaxSteps)
+	Print    func(script string, msg string) // if set, called by a script's print() (otherwise its output is discarded)
+}
+
+// Engine runs the hooks of a set of loaded scripts on the commits and pushes of the app instances it instruments -- concurrency safe.
+type Engine struct {
+	opts    EngineOpts
+	scripts []*loadedScript
+}
+
+type loadedScript struct {
+	Script
+	onCommit starlark.Value
+	onPin    starlark.Value
+}
+
+// NewEngine loads the given scripts, failing if any fails to load or defines neither hook.
+// A script's globals are frozen once loaded, so hook calls (which may be concurrent) cannot affect one another.
+func NewEngine(opts EngineOpts, scripts ...Script) (*Engine, error) {
+	if opts.MaxSteps == 0 {
+		opts.MaxSteps = DefaultMaxSteps
+	}
+	engine := &Engine{
+		opts: opts,
+	}
+	for _, script := range scripts {
+		globals, err := starlark.ExecFile(engine.newThread(script.Name), script.Name, script.Source, nil)
+		if err != nil {
+			return nil, amp.ErrCode_BadValue.Errorf("script %s: %s", script.Name, scriptErr(err))
+		}
+		globals.Freeze()
+
+		loaded := &loadedScript{
+			Script: script,
+		}
+		for name, hook := range map[string]*starlark.Value{"on_commit": &loaded.onCommit, "on_pin": &loaded.onPin} {
+			if fn, defined := globals[name]; defined {
+				if _, ok := fn.(starlark.Callable); !ok {
+					return nil, amp.ErrCode_BadValue.Errorf("script %s: %s is a %s, not a function", script.Name, name, fn.Type())
+				}
+				*hook = fn
+			}
+		}
+		if loaded.onCommit == nil && loaded.onPin == nil {
+			return nil, amp.ErrCode_BadValue.Errorf("script %s: defines neither on_commit nor on_pin", script.Name)
+		}
+		engine.scripts = append(engine.scripts, loaded)
+	}
+	return engine, nil
+}
+
+func (engine *Engine) newThread(script string) *starlark.Thread {
+	thread := &starlark.Thread{
+		Name:  script,
+		Print: func(thread *starlark.Thread, msg string) {},
+	}
+	thread.SetMaxExecutionSteps(engine.opts.MaxSteps)
+	if print := engine.opts.Print; print != nil {
+		thread.Print = func(thread *starlark.Thread, msg string) {
+			print(script, msg)
+		}
+	}
+	return thread
+}
+
+// scriptErr returns the message of the given script error, prefixed by the position in the script it was raised at.
+func scriptErr(err error) string {
+	var evalErr *starlark.EvalError
+	if !errors.As(err, &evalErr) {
+		return err.Error() // e.g. a syntax error, which already has its position
+	}
+	for i := range evalErr.CallStack {
+		if frame := evalErr.CallStack.At(i); frame.Pos.IsValid() && frame.Pos.Filename() != "<builtin>" {
+			return fmt.Sprintf("%s: %s", frame.Pos, evalErr.Msg)
+		}
+	}
+	return evalErr.Msg
+}
+
+// Instrument wraps the given instance of app so that the hooks of the scripts applying to app are called with each request's
+// CommitTx (before it is served) and with each tx pushed to the request (before it is forwarded).
+// A host calls this when it issues an AppInstance to a HostSession, before wrapping it further (e.g. GuardAppInstance), so that
+// only permitted requests run scripts and commits rewritten by a script are still validated (see ValidateCommits).
+// If no script applies to app, inst is returned as is.
+func (engine *Engine) Instrument(app *amp.App, inst amp.AppInstance) amp.AppInstance {
+	scripted := &scriptedApp{
+		AppInstance: inst,
+		engine:      engine,
+		app:         app.AppSpec.Canonic,
+	}
+	for _, script := range engine.scripts {
+		if script.appliesTo(app.AppSpec.ID) {
+			scripted.scripts = append(scripted.scripts, script)
+			scripted.onPin = scripted.onPin || script.onPin != nil
+		}
+	}
+	if len(scripted.scripts) == 0 {
+		return inst
+	}
+	return scripted
+}
+
+func (script *loadedScript) appliesTo(appID tag.ID) bool {
+	if len(script.Apps) == 0 {
+		return true
+	}
+	for _, id := range script.Apps {
+		if id == appID {
+			return true
+		}
+	}
+	return false
+}
+
+// runHooks calls the given hook of each script with an event for the given tx, returning the tx as rewritten by the scripts
+// (or tx itself if none changed it).  A script failing (e.g. via fail()) fails with code.
+func (app *scriptedApp) runHooks(kind string, req *amp.Request, tx *amp.TxMsg, code amp.ErrCode) (*amp.TxMsg, error) {
+	event := newEvent(kind, app, req, tx)
+	defer event.Freeze()
+
+	for _, script := range app.scripts {
+		hook := script.onCommit
+		if kind == PinEvent {
+			hook = script.onPin
+		}
+		if hook == nil {
+			continue
+		}
+		if err := app.call(script, hook, event); err != nil {
+			return nil, code.Errorf("script %s: %s", script.Name, scriptErr(err))
+		}
+	}
+	rewritten, err := event.rewrite()
+	if err != nil {
+		return nil, code.Wrap(fmt.Errorf("scripted %s: %w", kind, err))
+	}
+	return rewritten, nil
+}
+
+// call calls the given hook of the given script, cancelling it if the app instance closes meanwhile.
+func (app *scriptedApp) call(script *loadedScript, hook starlark.Value, event *Event) error {
+	thread := app.engine.newThread(script.Name)
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-app.Closing():
+			thread.Cancel("app is closing")
+		case <-done:
+		}
+	}()
+	_, err := starlark.Call(thread, hook, starlark.Tuple{event}, nil)
+	return err
+}
+
+type scriptedApp struct {
+	amp.AppInstance
+	engine  *Engine
+	app     string
+	scripts []*loadedScript
+	onPin   bool // set if any of scripts has an on_pin hook
+}
+
+func (app *scriptedApp) ServeRequest(req amp.Requester) (amp.Pin, error) {
+	return app.serve(app.AppInstance, req)
+}
+
+// serve runs the on_commit hooks on the given request's CommitTx (replacing it if rewritten), then serves the request via
+// the given pinner, wrapping its requester for the on_pin hooks and the pin it returns so that child requests are scripted too.
+func (app *scriptedApp) serve(pinner amp.Pinner, req amp.Requester) (amp.Pin, error) {
+	r := req.Request()
+	if commit := r.CommitTx; commit != nil {
+		rewritten, err := app.runHooks(CommitEvent, r, commit, amp.ErrCode_CommitFailed)
+		if err != nil {
+			return nil, err
+		}
+		r.CommitTx = rewritten // the original is left to the request's owner
+	}
+	if app.onPin {
+		req = &scriptedRequester{
+			Requester: req,
+			app:       app,
+		}
+	}
+
+	pin, err := pinner.ServeRequest(req)
+	if err != nil || pin == nil {
+		return pin, err
+	}
+	return &scriptedPin{
+		Pin: pin,
+		app: app,
+	}, nil
+}
+
+type scriptedPin struct {
+	amp.Pin
+	app *scriptedApp
+}
+
+func (pin *scriptedPin) ServeRequest(req amp.Requester) (amp.Pin, error) {
+	return pin.app.serve(pin.Pin, req)
+}
+
+type scriptedRequester struct {
+	amp.Requester
+	app *scriptedApp
+}
+
+// PushTx runs the on_pin hooks on the given tx, forwarding it as rewritten.  If a hook fails, the tx is not forwarded and the
+// push fails with ErrCode_PinFailed.
+func (req *scriptedRequester) PushTx(tx *amp.TxMsg) error {
+	rewritten, err := req.app.runHooks(PinEvent, req.Request(), tx, amp.ErrCode_PinFailed)
+	if err != nil {
+		tx.ReleaseRef()
+		return err
+	}
+	if rewritten != tx {
+		tx.ReleaseRef() // the rewritten tx holds its own reference (see TxMsg.Derive)
+	}
+	return req.Requester.PushTx(rewritten)
+}
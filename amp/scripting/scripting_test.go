@@ -0,0 +1,269 @@
+package scripting_test
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/amp-3d/amp-sdk-go/amp"
+	"github.com/amp-3d/amp-sdk-go/amp/amptest"
+	"github.com/amp-3d/amp-sdk-go/amp/basic"
+	"github.com/amp-3d/amp-sdk-go/amp/scripting"
+	"github.com/amp-3d/amp-sdk-go/stdlib/tag"
+)
+
+// recordingApp returns an App whose instance is the given appInst.
+func recordingApp(app *appInst) *amp.App {
+	return &amp.App{
+		AppSpec:     tag.FormSpec(amp.AppSpec, "test.scripting"),
+		Invocations: []string{"testapp"},
+		NewAppInstance: func(ctx amp.AppContext) (amp.AppInstance, error) {
+			app.AppContext = ctx
+			app.Instance = app
+			return app, nil
+		},
+	}
+}
+
+// appInst records the tabs committed to it and serves a home cell having the children "one" and "two".
+type appInst struct {
+	basic.App[*appInst]
+	mu        sync.Mutex
+	committed []*amp.TagTab
+}
+
+func (app *appInst) ServeRequest(req amp.Requester) (amp.Pin, error) {
+	if commit := req.Request().CommitTx; commit != nil {
+		app.mu.Lock()
+		defer app.mu.Unlock()
+		for i, op := range commit.Ops {
+			if op.OpCode == amp.TxOpCode_UpsertAttr && op.AttrID == amp.PinnedTabSpec.ID {
+				tab := &amp.TagTab{}
+				if err := commit.UnmarshalOpValue(i, tab); err != nil {
+					return nil, err
+				}
+				app.committed = append(app.committed, tab)
+			}
+		}
+	}
+	return app.PinAndServe(&cell{}, req)
+}
+
+func (app *appInst) MakeReady(req amp.Requester) error {
+	return nil
+}
+
+type cell struct {
+	basic.CellInfo[*appInst]
+}
+
+func (c *cell) PinInto(dst *basic.Pinned[*appInst]) error {
+	for _, label := range []string{"one", "two"} {
+		child := &cell{}
+		child.Tab.Label = label
+		dst.AddChild(child)
+	}
+	return nil
+}
+
+var commitReq = amp.PinRequest{PinTarget: &amp.Tag{URL: "testapp://cells/home"}}
+
+func commitTabs(sess *amptest.Session, tabs ...*amp.TagTab) error {
+	tx := amp.NewTxMsg(true)
+	for _, tab := range tabs {
+		tx.MarshalUpsert(tag.New(), amp.PinnedTabSpec.ID, tab)
+	}
+	_, err := sess.TryCommit(commitReq, tx)
+	return err
+}
+
+const renameScript = `
+def on_commit(event):
+    for cell in event.cells:
+        tab = event.get(cell, "amp.attr.pinned.TagTab")
+        if not tab:
+            continue
+        label = " ".join(tab["Label"].split())
+        if not label:
+            fail("cell", cell, "has no label")
+        tab["Label"] = label.title()
+        if "draft" in label.lower():
+            tab["Tags"] = tab["Tags"] + [{"URL": "tag:draft"}]
+        event.set(cell, "amp.attr.pinned.TagTab", tab)
+`
+
+func TestCommitHooks(t *testing.T) {
+	var app appInst
+	sess := amptest.NewSession(t, recordingApp(&app))
+	engine, err := scripting.NewEngine(scripting.EngineOpts{}, scripting.Script{Name: "rename.star", Source: renameScript})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sess.Scripts = engine
+
+	// Commits are rewritten before the app sees them
+	err = commitTabs(sess, &amp.TagTab{Label: "  blue   train ", Caption: "kept"}, &amp.TagTab{Label: "giant steps (draft)"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	app.mu.Lock()
+	committed := app.committed
+	app.mu.Unlock()
+	if len(committed) != 2 {
+		t.Fatalf("expected 2 committed tabs, got %d", len(committed))
+	}
+	if tab := committed[0]; tab.Label != "Blue Train" || tab.Caption != "kept" || len(tab.Tags) != 0 {
+		t.Fatalf("unexpected tab %v", tab)
+	}
+	if tab := committed[1]; tab.Label != "Giant Steps (Draft)" || len(tab.Tags) != 1 || tab.Tags[0].URL != "tag:draft" {
+		t.Fatalf("unexpected tab %v", tab)
+	}
+
+	// A commit a script fails is rejected before the app sees it
+	err = commitTabs(sess, &amp.TagTab{Label: "ok"}, &amp.TagTab{Label: "   "})
+	ampErr, ok := err.(*amp.Err)
+	if !ok || ampErr.Code != amp.ErrCode_CommitFailed || !strings.Contains(ampErr.Msg, "rename.star:9:17: fail: cell") {
+		t.Fatalf("expected commit to fail, got %v", err)
+	}
+	app.mu.Lock()
+	numCommitted := len(app.committed)
+	app.mu.Unlock()
+	if numCommitted != 2 {
+		t.Fatalf("expected rejected commit to be withheld, got %d committed tabs", numCommitted)
+	}
+}
+
+func TestPinHooks(t *testing.T) {
+	var app appInst
+	sess := amptest.NewSession(t, recordingApp(&app))
+	engine, err := scripting.NewEngine(scripting.EngineOpts{}, scripting.Script{
+		Name: "children.star",
+		Source: `
+def on_pin(event):
+    if event.kind != "pin" or event.app != "amp.app.test.scripting":
+        fail("unexpected event", event.kind, event.app)
+    for cell in event.cells:
+        tab = event.get(cell, "amp.attr.TagTab")
+        if not tab:
+            continue
+        if tab["Label"] == "two":
+            event.delete(cell, "amp.attr.TagTab")
+        else:
+            tab["Label"] = tab["Label"].upper()
+            event.set(cell, "amp.attr.TagTab", tab)
+`,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sess.Scripts = engine
+
+	req := sess.PinURL("testapp://cells/home")
+	req.RequireComplete()
+
+	labels := map[string]bool{}
+	deleted := 0
+	for _, tx := range req.Txs() {
+		for i, op := range tx.Ops {
+			if op.AttrID != amp.ChildTabSpec.ID {
+				continue
+			}
+			switch op.OpCode {
+			case amp.TxOpCode_UpsertAttr:
+				var tab amp.TagTab
+				if err := tx.UnmarshalOpValue(i, &tab); err != nil {
+					t.Fatal(err)
+				}
+				labels[tab.Label] = true
+			case amp.TxOpCode_DeleteAttr:
+				deleted++
+			}
+		}
+	}
+	if len(labels) != 1 || !labels["ONE"] || deleted != 1 {
+		t.Fatalf("unexpected child labels %v (%d deleted)", labels, deleted)
+	}
+}
+
+func TestEngine(t *testing.T) {
+	for _, test := range []struct {
+		src  string
+		want string
+	}{
+		{"def on_commit(event)\n    pass", "script bad.star: bad.star:2:1: got newline, want ':'"},
+		{"x = 1", "defines neither on_commit nor on_pin"},
+		{"on_pin = 1", "on_pin is a int, not a function"},
+		{"x = open('/etc/passwd')", "undefined: open"},
+	} {
+		_, err := scripting.NewEngine(scripting.EngineOpts{}, scripting.Script{Name: "bad.star", Source: test.src})
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Fatalf("expected error %q loading %q, got %v", test.want, test.src, err)
+		}
+	}
+
+	// Hook calls are bounded, and fail the commit they were called for
+	sess := amptest.NewSession(t, recordingApp(&appInst{}))
+	var err error
+	sess.Scripts, err = scripting.NewEngine(scripting.EngineOpts{MaxSteps: 1000}, scripting.Script{
+		Name:   "spin.star",
+		Source: "def on_commit(event):\n    for i in range(1000000):\n        pass",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = commitTabs(sess, &amp.TagTab{Label: "x"}); err == nil || !strings.Contains(err.Error(), "too many steps") {
+		t.Fatalf("expected step limit to be exceeded, got %v", err)
+	}
+
+	// Scripts apply only to the apps they list, and globals are frozen once loaded
+	sess = amptest.NewSession(t, recordingApp(&appInst{}))
+	sess.Scripts, err = scripting.NewEngine(scripting.EngineOpts{}, scripting.Script{
+		Name:   "other.star",
+		Source: "def on_commit(event):\n    fail('not for this app')",
+		Apps:   []tag.ID{tag.FormSpec(amp.AppSpec, "other").ID},
+	}, scripting.Script{
+		Name:   "counter.star",
+		Source: "seen = []\ndef on_commit(event):\n    seen.append(event)",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = commitTabs(sess, &amp.TagTab{Label: "x"})
+	if err == nil || !strings.Contains(err.Error(), "counter.star:3:16: append: cannot append to frozen list") {
+		t.Fatalf("expected frozen globals, got %v", err)
+	}
+
+	// Ints are arbitrary precision
+	bigApp := &appInst{}
+	sess = amptest.NewSession(t, recordingApp(bigApp))
+	sess.Scripts, err = scripting.NewEngine(scripting.EngineOpts{}, scripting.Script{
+		Name:   "big.star",
+		Source: "def on_commit(event):\n    event.set(event.cells[0], 'amp.attr.pinned.TagTab', {'Label': str((1 << 64) + len(str(int('9'*5000))))})",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = commitTabs(sess, &amp.TagTab{Label: "x"}); err != nil {
+		t.Fatal(err)
+	}
+	bigApp.mu.Lock()
+	committed := bigApp.committed
+	bigApp.mu.Unlock()
+	if len(committed) != 1 || committed[0].Label != "18446744073709556616" {
+		t.Fatalf("unexpected committed tabs %v", committed)
+	}
+
+	// Values set by a script are checked against the attr's type
+	sess = amptest.NewSession(t, recordingApp(&appInst{}))
+	sess.Scripts, err = scripting.NewEngine(scripting.EngineOpts{}, scripting.Script{
+		Name:   "typo.star",
+		Source: "def on_commit(event):\n    event.set(event.cells[0], 'amp.attr.pinned.TagTab', {'Lable': 'x'})",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = commitTabs(sess, &amp.TagTab{Label: "x"})
+	if err == nil || !strings.Contains(err.Error(), "set: amp.attr.pinned.TagTab value: TagTab has no field Lable") {
+		t.Fatalf("expected unknown field, got %v", err)
+	}
+}
@@ -0,0 +1,190 @@
+package scripting
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+
+	"github.com/amp-3d/amp-sdk-go/amp"
+	"go.starlark.net/starlark"
+)
+
+// toValue returns the given attr value as a dict of its exported fields (see Event).
+func toValue(val amp.ElemVal) starlark.Value {
+	return toStarlark(reflect.ValueOf(val))
+}
+
+func toStarlark(rv reflect.Value) starlark.Value {
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if rv.IsNil() {
+			return starlark.None
+		}
+		return toStarlark(rv.Elem())
+	case reflect.Struct:
+		rt := rv.Type()
+		dict := starlark.NewDict(rt.NumField())
+		for i := 0; i < rt.NumField(); i++ {
+			if field := rt.Field(i); isScriptField(field) {
+				dict.SetKey(starlark.String(field.Name), toStarlark(rv.Field(i)))
+			}
+		}
+		return dict
+	case reflect.String:
+		return starlark.String(rv.String())
+	case reflect.Bool:
+		return starlark.Bool(rv.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return starlark.MakeInt64(rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return starlark.MakeUint64(rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		return starlark.Float(rv.Float())
+	case reflect.Slice:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return starlark.String(rv.Bytes())
+		}
+		elems := make([]starlark.Value, rv.Len())
+		for i := range elems {
+			elems[i] = toStarlark(rv.Index(i))
+		}
+		return starlark.NewList(elems)
+	}
+	return starlark.None
+}
+
+// isScriptField returns true if the given struct field is visible to scripts, which excludes unexported fields and those of
+// kinds scripts cannot express (e.g. maps and funcs).
+func isScriptField(field reflect.StructField) bool {
+	if !field.IsExported() || strings.HasPrefix(field.Name, "XXX_") {
+		return false
+	}
+	switch field.Type.Kind() {
+	case reflect.Map, reflect.Func, reflect.Chan, reflect.Interface, reflect.UnsafePointer, reflect.Complex64, reflect.Complex128:
+		return false
+	}
+	return true
+}
+
+// fromValue assigns the fields of the given attr value from the given dict, where fields the dict omits are left as is.
+func fromValue(dict *starlark.Dict, val amp.ElemVal) error {
+	return fromStarlark(dict, reflect.ValueOf(val).Elem(), "")
+}
+
+func fromStarlark(v starlark.Value, rv reflect.Value, path string) error {
+	mismatch := func(want string) error {
+		if path == "" {
+			return fmt.Errorf("got %s, want %s", v.Type(), want)
+		}
+		return fmt.Errorf("%s: got %s, want %s", path, v.Type(), want)
+	}
+
+	switch rv.Kind() {
+	case reflect.Pointer:
+		if v == starlark.None {
+			rv.SetZero()
+			return nil
+		}
+		if rv.IsNil() {
+			rv.Set(reflect.New(rv.Type().Elem()))
+		}
+		return fromStarlark(v, rv.Elem(), path)
+	case reflect.Struct:
+		dict, ok := v.(*starlark.Dict)
+		if !ok {
+			return mismatch("dict")
+		}
+		for _, item := range dict.Items() {
+			name, ok := starlark.AsString(item[0])
+			if !ok {
+				return mismatch("dict with string keys")
+			}
+			field, exists := rv.Type().FieldByName(name)
+			if !exists || !isScriptField(field) || len(field.Index) > 1 {
+				return fmt.Errorf("%s has no field %s", rv.Type().Name(), name)
+			}
+			fieldPath := name
+			if path != "" {
+				fieldPath = path + "." + name
+			}
+			if err := fromStarlark(item[1], rv.FieldByIndex(field.Index), fieldPath); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.String:
+		s, ok := starlark.AsString(v)
+		if !ok {
+			return mismatch("string")
+		}
+		rv.SetString(s)
+		return nil
+	case reflect.Bool:
+		b, ok := v.(starlark.Bool)
+		if !ok {
+			return mismatch("bool")
+		}
+		rv.SetBool(bool(b))
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, isInt := v.(starlark.Int)
+		if !isInt {
+			return mismatch("int")
+		}
+		i, ok := n.Int64()
+		if !ok || rv.OverflowInt(i) {
+			return fmt.Errorf("%s: %s out of range", path, n)
+		}
+		rv.SetInt(i)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, isInt := v.(starlark.Int)
+		if !isInt {
+			return mismatch("int")
+		}
+		u, ok := n.Uint64()
+		if !ok || rv.OverflowUint(u) {
+			return fmt.Errorf("%s: %s out of range", path, n)
+		}
+		rv.SetUint(u)
+		return nil
+	case reflect.Float32, reflect.Float64:
+		f, ok := starlark.AsFloat(v)
+		if !ok {
+			return mismatch("float")
+		}
+		if rv.Kind() == reflect.Float32 && !math.IsInf(f, 0) && math.Abs(f) > math.MaxFloat32 {
+			return fmt.Errorf("%s: %v out of range", path, f)
+		}
+		rv.SetFloat(f)
+		return nil
+	case reflect.Slice:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			s, ok := starlark.AsString(v)
+			if !ok {
+				return mismatch("string")
+			}
+			rv.SetBytes([]byte(s))
+			return nil
+		}
+		var elems starlark.Indexable
+		switch v := v.(type) {
+		case *starlark.List:
+			elems = v
+		case starlark.Tuple:
+			elems = v
+		default:
+			return mismatch("list")
+		}
+		slice := reflect.MakeSlice(rv.Type(), elems.Len(), elems.Len())
+		for i := 0; i < elems.Len(); i++ {
+			if err := fromStarlark(elems.Index(i), slice.Index(i), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+		rv.Set(slice)
+		return nil
+	}
+	return fmt.Errorf("%s: unsupported field type %s", path, rv.Type())
+}
@@ -0,0 +1,153 @@
+package search
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/amp-3d/amp-sdk-go/amp"
+	"github.com/amp-3d/amp-sdk-go/amp/basic"
+	"github.com/amp-3d/amp-sdk-go/stdlib/tag"
+	"github.com/amp-3d/amp-sdk-go/stdlib/task"
+)
+
+// AppSpec identifies the search App.
+var AppSpec = tag.FormSpec(amp.AppSpec, "search")
+
+// NewApp returns the search App, serving pins of URLs of the form:
+//
+//	search://?q={query}
+//
+// The pinned cell's children are the hits for the query, each having an amp.SearchHit attr and the hit's label as its
+// amp.ChildTabSpec attr.  Hits are pinned in windows (see basic.PagedCell), so a request's PinWindow selects which hits
+// are pushed, and a maintained pin pushes its window again as the index changes.
+func NewApp(idx Index) *amp.App {
+	return &amp.App{
+		AppSpec:     AppSpec,
+		Desc:        "full-text search of indexed cell attrs",
+		Invocations: []string{"search"},
+		NewAppInstance: func(ctx amp.AppContext) (amp.AppInstance, error) {
+			app := &appInst{
+				index: idx,
+			}
+			app.AppContext = ctx
+			app.Instance = app
+			return app, nil
+		},
+	}
+}
+
+type appInst struct {
+	basic.App[*appInst]
+	index Index
+}
+
+func (app *appInst) ServeRequest(req amp.Requester) (amp.Pin, error) {
+	query := req.Request().Values.Get("q")
+	if strings.TrimSpace(query) == "" {
+		return nil, amp.ErrCode_BadRequest.Error("missing search query (q param)")
+	}
+
+	results := &resultsCell{
+		index: app.index,
+		query: query,
+	}
+	results.Tab.Label = query
+
+	changed := app.index.Changed() // before the first search so no change is missed
+	if err := results.search(); err != nil {
+		return nil, err
+	}
+	pin, err := app.PinAndServe(results, req)
+	if err != nil {
+		return nil, err
+	}
+	if req.Request().PinSync == amp.PinSync_Maintain {
+		go results.watch(pin.Context(), changed)
+	}
+	return pin, nil
+}
+
+// resultsCell is the pinned cell of a search request, enumerating the hits of its query.
+type resultsCell struct {
+	basic.CellInfo[*appInst]
+	index Index
+	query string
+
+	mu   sync.Mutex
+	hits []Hit
+}
+
+func (results *resultsCell) PinInto(dst *basic.Pinned[*appInst]) error {
+	return nil
+}
+
+func (results *resultsCell) ChildCount() int64 {
+	return int64(len(results.currentHits()))
+}
+
+func (results *resultsCell) ChildrenAt(offset, limit int64) ([]basic.Cell[*appInst], error) {
+	hits := results.currentHits()
+	var children []basic.Cell[*appInst]
+	for i := offset; i < offset+limit && i < int64(len(hits)); i++ {
+		hit := &hitCell{
+			hit: amp.SearchHit{
+				Rank:  i,
+				Score: float32(hits[i].Score),
+			},
+		}
+		hit.ID = hits[i].CellID
+		hit.Tab.Label = hits[i].Label
+		children = append(children, hit)
+	}
+	return children, nil
+}
+
+func (results *resultsCell) currentHits() []Hit {
+	results.mu.Lock()
+	defer results.mu.Unlock()
+	return results.hits
+}
+
+// search replaces the current hits with those now matching the query.
+func (results *resultsCell) search() error {
+	hits, err := results.index.Search(results.query, 0)
+	if err != nil {
+		return amp.ErrCode_ProviderErr.Wrap(err)
+	}
+	results.mu.Lock()
+	results.hits = hits
+	results.mu.Unlock()
+	return nil
+}
+
+// watch searches again each time the index changes, until ctx closes.
+func (results *resultsCell) watch(ctx task.Context, changed <-chan struct{}) {
+	for {
+		select {
+		case <-changed:
+			changed = results.index.Changed()
+			if err := results.search(); err != nil {
+				ctx.Warnf("search %q failed: %v", results.query, err)
+				continue
+			}
+			results.Pinned.Invalidate()
+		case <-ctx.Closing():
+			return
+		}
+	}
+}
+
+// hitCell is a cell that matched a search query.
+type hitCell struct {
+	basic.CellInfo[*appInst]
+	hit amp.SearchHit
+}
+
+func (hit *hitCell) PinInto(dst *basic.Pinned[*appInst]) error {
+	return nil
+}
+
+func (hit *hitCell) MarshalAttrs(pin *basic.Pin[*appInst]) {
+	hit.CellInfo.MarshalAttrs(pin)
+	pin.Upsert(hit.ID, amp.SearchHitSpec.ID, tag.Nil, &hit.hit)
+}
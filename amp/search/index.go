@@ -0,0 +1,276 @@
+// Package search is an opt-in full-text search subsystem: apps publish the text of their cells' attrs to an Index
+// (see IndexTopic), and a host that registers the search App (see NewApp) lets clients pin ranked, live-updating hits.
+//
+// A host opts in with:
+//
+//	idx, err := search.NewIndex(filepath.Join(dataPath, "search"))
+//	defer idx.Close()
+//	defer search.Listen(bus, idx)()  // the amp.MessageBus shared by the host's AppContexts
+//	reg.RegisterApp(search.NewApp(idx))
+//
+// An app indexes a cell attr with:
+//
+//	amp.Publish(amp.BusOf(ctx), search.IndexTopic, search.Entry{CellID: cellID, AttrID: attrID, Value: &tab})
+//
+// It is a separate module so that hosts not using it do not depend on Bleve.
+package search
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/blevesearch/bleve/v2"
+	bleve_index "github.com/blevesearch/bleve_index_api"
+
+	"github.com/amp-3d/amp-sdk-go/amp"
+	"github.com/amp-3d/amp-sdk-go/stdlib/tag"
+)
+
+// IndexTopic is the MessageBus topic an Index listens to for entries (see Listen).
+var IndexTopic = amp.FormTopic[Entry]("search.index")
+
+// Entry adds, replaces, or removes the indexed text of a cell attr.
+type Entry struct {
+	CellID tag.ID      // cell the text is from
+	AttrID tag.ID      // attr of the cell the text is from -- each attr of a cell is indexed separately
+	Text   string      // text to index -- if empty, the text of Value is indexed (see TextOf)
+	Value  amp.ElemVal // attr value, used for the text if Text is empty and for a hit's label if it is an amp.TagTab
+
+	// If set, the attr is removed from the index (or the whole cell if AttrID is nil)
+	Remove bool
+}
+
+// Hit is a cell that matches a search query.
+type Hit struct {
+	CellID tag.ID
+	Label  string  // label of the cell's most recently indexed amp.TagTab (if any)
+	Score  float64 // BM25 relevance to the query
+}
+
+// TextOf returns the searchable text of the given attr value: the label, caption, and about of an amp.TagTab,
+// the URL of an amp.Tag, or else "" (an app indexing other values provides Entry.Text).
+func TextOf(val amp.ElemVal) string {
+	switch v := val.(type) {
+	case *amp.TagTab:
+		return v.Label + " " + v.Caption + " " + v.About
+	case *amp.Tag:
+		return v.URL
+	}
+	return ""
+}
+
+// Index is a concurrency-safe full-text index of cell attr text (see NewIndex and NewMemoryIndex).
+type Index interface {
+
+	// Applies the given entry to the index.
+	Put(entry Entry) error
+
+	// Returns the cells matching any term of the given query, best match first, limited to the given number of hits (if > 0).
+	// Unless the query ends with a space, its last term also matches terms it is a prefix of, so hits can update as a user types.
+	Search(query string, limit int) ([]Hit, error)
+
+	// Returns a channel that is closed the next time the index changes.
+	Changed() <-chan struct{}
+
+	// Returns the number of cells indexed.
+	Len() int
+
+	// Releases the index, flushing it to storage if it has any.
+	Close() error
+}
+
+// Listen puts each Entry published to IndexTopic on the given bus into the given Index until cancel is called.
+func Listen(bus amp.MessageBus, idx Index) (cancel func()) {
+	return bus.Subscribe(IndexTopic.ID, func(msg any) {
+		if entry, ok := msg.(Entry); ok {
+			idx.Put(entry)
+		}
+	})
+}
+
+// Fields of a bleve document, whose ID is the cell ID in hex (see tag.ID.Base16)
+const (
+	labelField = "label" // stored only, for Hit.Label
+	attrsField = "attrs" // attrs.{attr ID in hex} holds the text of each indexed attr
+)
+
+// NewIndex opens (or creates) a bleve (github.com/blevesearch/bleve) index in the given directory, ranking hits by BM25.
+// If pathname is "", the index is kept only in memory.
+//
+// Each cell is a bleve document whose fields are the text of its indexed attrs, analyzed by bleve's standard analyzer
+// (unicode word segmentation, lower casing, and English stop words), and queried via the composite "_all" field.
+func NewIndex(pathname string) (Index, error) {
+	mapping := bleve.NewIndexMapping()
+	mapping.ScoringModel = bleve_index.BM25Scoring
+	label := bleve.NewTextFieldMapping()
+	label.Index = false
+	label.IncludeInAll = false
+	mapping.DefaultMapping.AddFieldMappingsAt(labelField, label)
+
+	var index bleve.Index
+	var err error
+	if pathname == "" {
+		index, err = bleve.NewMemOnly(mapping)
+	} else if index, err = bleve.Open(pathname); errors.Is(err, bleve.ErrorIndexPathDoesNotExist) {
+		index, err = bleve.New(pathname, mapping)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &bleveIndex{
+		index:   index,
+		changed: make(chan struct{}),
+	}, nil
+}
+
+// bleveIndex implements Index using a bleve.Index.
+type bleveIndex struct {
+	index   bleve.Index
+	putMu   sync.Mutex // serializes updates of a cell's document
+	mu      sync.Mutex
+	changed chan struct{} // closed and replaced on each change
+}
+
+// bleveDoc is the stored form of a cell's document.
+type bleveDoc struct {
+	label string
+	attrs map[string]string // attr ID (hex) -> text
+}
+
+func (idx *bleveIndex) Changed() <-chan struct{} {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	return idx.changed
+}
+
+func (idx *bleveIndex) Len() int {
+	count, _ := idx.index.DocCount()
+	return int(count)
+}
+
+func (idx *bleveIndex) Put(entry Entry) error {
+	idx.putMu.Lock()
+	defer idx.putMu.Unlock()
+
+	docID := entry.CellID.Base16()
+	doc, err := idx.load(docID)
+	if err != nil {
+		return err
+	}
+	if entry.Remove {
+		if entry.AttrID.IsNil() {
+			clear(doc.attrs)
+		} else {
+			delete(doc.attrs, entry.AttrID.Base16())
+		}
+	} else {
+		text := entry.Text
+		if text == "" {
+			text = TextOf(entry.Value)
+		}
+		if tab, isTab := entry.Value.(*amp.TagTab); isTab {
+			doc.label = tab.Label
+		}
+		if strings.TrimSpace(text) == "" {
+			delete(doc.attrs, entry.AttrID.Base16())
+		} else {
+			doc.attrs[entry.AttrID.Base16()] = text
+		}
+	}
+
+	if len(doc.attrs) == 0 {
+		err = idx.index.Delete(docID)
+	} else {
+		err = idx.index.Index(docID, map[string]any{
+			labelField: doc.label,
+			attrsField: doc.attrs,
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	idx.mu.Lock()
+	close(idx.changed)
+	idx.changed = make(chan struct{})
+	idx.mu.Unlock()
+	return nil
+}
+
+// load reads the stored form of the given document, returning an empty one if it does not exist.
+func (idx *bleveIndex) load(docID string) (bleveDoc, error) {
+	doc := bleveDoc{
+		attrs: make(map[string]string),
+	}
+	stored, err := idx.index.Document(docID)
+	if err != nil || stored == nil {
+		return doc, err
+	}
+	stored.VisitFields(func(field bleve_index.Field) {
+		if name := field.Name(); name == labelField {
+			doc.label = string(field.Value())
+		} else if attrID, isAttr := strings.CutPrefix(name, attrsField+"."); isAttr {
+			doc.attrs[attrID] = string(field.Value())
+		}
+	})
+	return doc, nil
+}
+
+func (idx *bleveIndex) Search(query string, limit int) ([]Hit, error) {
+	terms := tokenize(query)
+	if len(terms) == 0 {
+		return nil, nil
+	}
+
+	// Each query term matches itself, and the last may match as a prefix.
+	q := bleve.NewDisjunctionQuery()
+	for _, term := range terms {
+		q.AddQuery(bleve.NewMatchQuery(term))
+	}
+	if last := terms[len(terms)-1]; !strings.HasSuffix(query, " ") {
+		q.AddQuery(bleve.NewPrefixQuery(last))
+	}
+
+	count, err := idx.index.DocCount()
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 || uint64(limit) > count {
+		limit = int(count)
+	}
+	req := bleve.NewSearchRequestOptions(q, limit, 0, false)
+	req.Fields = []string{labelField}
+	req.SortBy([]string{"-_score", "_id"})
+	res, err := idx.index.Search(req)
+	if err != nil {
+		return nil, err
+	}
+
+	hits := make([]Hit, 0, len(res.Hits))
+	for _, match := range res.Hits {
+		cellID, err := tag.ParseID(match.ID)
+		if err != nil {
+			return nil, err
+		}
+		label, _ := match.Fields[labelField].(string)
+		hits = append(hits, Hit{
+			CellID: cellID,
+			Label:  label,
+			Score:  match.Score,
+		})
+	}
+	return hits, nil
+}
+
+func (idx *bleveIndex) Close() error {
+	return idx.index.Close()
+}
+
+// tokenize returns the lower case words of the given text.
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
@@ -0,0 +1,190 @@
+package search
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/amp-3d/amp-sdk-go/amp"
+	"github.com/amp-3d/amp-sdk-go/stdlib/tag"
+)
+
+// BM25 ranking parameters
+const (
+	rankK1 = 1.2
+	rankB  = 0.75
+)
+
+// memoryIndex is an in-memory inverted index of cell attr text, ranking hits by BM25.
+type memoryIndex struct {
+	mu       sync.RWMutex
+	docs     map[tag.ID]*doc
+	postings map[string]map[tag.ID]int // term -> cell -> term frequency
+	totalLen int                       // sum of doc lengths
+	changed  chan struct{}             // closed and replaced on each change
+}
+
+type doc struct {
+	attrs  map[tag.ID][]string // attr -> terms
+	label  string
+	length int
+}
+
+// NewMemoryIndex returns an empty Index kept only in memory, ranking hits by BM25 as NewIndex() does.
+//
+// It is a fallback for tests and for hosts indexing few cells: unlike NewIndex() it keeps no files and needs no warm-up,
+// and it is small enough to read and trace through -- but it scans every term for prefix matches and does no stemming or
+// stop word removal, so it does not scale to large catalogs.
+func NewMemoryIndex() Index {
+	return &memoryIndex{
+		docs:     make(map[tag.ID]*doc),
+		postings: make(map[string]map[tag.ID]int),
+		changed:  make(chan struct{}),
+	}
+}
+
+func (idx *memoryIndex) Changed() <-chan struct{} {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return idx.changed
+}
+
+func (idx *memoryIndex) Len() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return len(idx.docs)
+}
+
+func (idx *memoryIndex) Put(entry Entry) error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	d := idx.docs[entry.CellID]
+	if entry.Remove {
+		if d == nil {
+			return nil
+		}
+		if entry.AttrID.IsNil() {
+			for attrID := range d.attrs {
+				idx.setAttr(entry.CellID, d, attrID, nil)
+			}
+		} else {
+			idx.setAttr(entry.CellID, d, entry.AttrID, nil)
+		}
+		if len(d.attrs) == 0 {
+			delete(idx.docs, entry.CellID)
+		}
+	} else {
+		if d == nil {
+			d = &doc{
+				attrs: make(map[tag.ID][]string),
+			}
+			idx.docs[entry.CellID] = d
+		}
+		text := entry.Text
+		if text == "" {
+			text = TextOf(entry.Value)
+		}
+		if tab, isTab := entry.Value.(*amp.TagTab); isTab {
+			d.label = tab.Label
+		}
+		idx.setAttr(entry.CellID, d, entry.AttrID, tokenize(text))
+	}
+
+	close(idx.changed)
+	idx.changed = make(chan struct{})
+	return nil
+}
+
+func (idx *memoryIndex) setAttr(cellID tag.ID, d *doc, attrID tag.ID, terms []string) {
+	for _, term := range d.attrs[attrID] {
+		if cells := idx.postings[term]; cells != nil {
+			if cells[cellID]--; cells[cellID] <= 0 {
+				delete(cells, cellID)
+			}
+			if len(cells) == 0 {
+				delete(idx.postings, term)
+			}
+		}
+	}
+	idx.totalLen -= len(d.attrs[attrID])
+	d.length -= len(d.attrs[attrID])
+
+	if len(terms) == 0 {
+		delete(d.attrs, attrID)
+		return
+	}
+	d.attrs[attrID] = terms
+	d.length += len(terms)
+	idx.totalLen += len(terms)
+	for _, term := range terms {
+		cells := idx.postings[term]
+		if cells == nil {
+			cells = make(map[tag.ID]int)
+			idx.postings[term] = cells
+		}
+		cells[cellID]++
+	}
+}
+
+func (idx *memoryIndex) Search(query string, limit int) ([]Hit, error) {
+	terms := tokenize(query)
+	if len(terms) == 0 {
+		return nil, nil
+	}
+
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	// Each query term matches itself, and the last may match as a prefix.
+	matches := make(map[string]struct{}, len(terms))
+	for _, term := range terms {
+		matches[term] = struct{}{}
+	}
+	if last := terms[len(terms)-1]; !strings.HasSuffix(query, " ") {
+		for term := range idx.postings {
+			if strings.HasPrefix(term, last) {
+				matches[term] = struct{}{}
+			}
+		}
+	}
+
+	numDocs := float64(len(idx.docs))
+	avgLen := float64(idx.totalLen) / max(numDocs, 1)
+	scores := make(map[tag.ID]float64)
+	for term := range matches {
+		cells := idx.postings[term]
+		if len(cells) == 0 {
+			continue
+		}
+		idf := math.Log(1 + (numDocs-float64(len(cells))+0.5)/(float64(len(cells))+0.5))
+		for cellID, tf := range cells {
+			norm := rankK1 * (1 - rankB + rankB*float64(idx.docs[cellID].length)/avgLen)
+			scores[cellID] += idf * float64(tf) * (rankK1 + 1) / (float64(tf) + norm)
+		}
+	}
+
+	hits := make([]Hit, 0, len(scores))
+	for cellID, score := range scores {
+		hits = append(hits, Hit{
+			CellID: cellID,
+			Label:  idx.docs[cellID].label,
+			Score:  score,
+		})
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].CellID.CompareTo(hits[j].CellID) < 0
+	})
+	if limit > 0 && len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits, nil
+}
+
+func (idx *memoryIndex) Close() error {
+	return nil
+}
@@ -0,0 +1,150 @@
+package search_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/amp-3d/amp-sdk-go/amp"
+	"github.com/amp-3d/amp-sdk-go/amp/amptest"
+	"github.com/amp-3d/amp-sdk-go/amp/search"
+	"github.com/amp-3d/amp-sdk-go/stdlib/tag"
+)
+
+func TestIndex(t *testing.T) {
+	t.Run("bleve", func(t *testing.T) {
+		idx, err := search.NewIndex("")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer idx.Close()
+		testIndex(t, idx)
+	})
+	t.Run("memory", func(t *testing.T) {
+		testIndex(t, search.NewMemoryIndex())
+	})
+}
+
+func testIndex(t *testing.T, idx search.Index) {
+	cells := make(map[string]tag.ID)
+	for _, label := range []string{"Blue Train", "Blue in Green", "Giant Steps", "Green Onions"} {
+		cells[label] = tag.New()
+		idx.Put(search.Entry{CellID: cells[label], AttrID: amp.ChildTabSpec.ID, Value: &amp.TagTab{Label: label}})
+	}
+	idx.Put(search.Entry{CellID: cells["Giant Steps"], AttrID: amp.ContentSpec.ID, Text: "coltrane blue note sessions"})
+
+	labels := func(query string) []string {
+		var got []string
+		hits, err := idx.Search(query, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, hit := range hits {
+			got = append(got, hit.Label)
+		}
+		return got
+	}
+
+	// Cells matching more (and rarer) terms rank first
+	if got := labels("blue green "); len(got) != 4 || got[0] != "Blue in Green" {
+		t.Fatalf("unexpected hits %v", got)
+	}
+	// The last term matches as a prefix unless followed by a space
+	if got := labels("ste"); len(got) != 1 || got[0] != "Giant Steps" {
+		t.Fatalf("unexpected hits %v", got)
+	}
+	if got := labels("ste "); len(got) != 0 {
+		t.Fatalf("unexpected hits %v", got)
+	}
+	if got, _ := idx.Search("blue", 2); len(got) != 2 {
+		t.Fatalf("expected limit to apply, got %d hits", len(got))
+	}
+
+	// Removing an attr removes only its text, and removing a cell removes all of it
+	changed := idx.Changed()
+	idx.Put(search.Entry{CellID: cells["Giant Steps"], AttrID: amp.ContentSpec.ID, Remove: true})
+	select {
+	case <-changed:
+	default:
+		t.Fatal("expected change to be signaled")
+	}
+	if got := labels("coltrane"); len(got) != 0 {
+		t.Fatalf("unexpected hits %v", got)
+	}
+	idx.Put(search.Entry{CellID: cells["Blue Train"], Remove: true})
+	if got := labels("train"); len(got) != 0 || idx.Len() != 3 {
+		t.Fatalf("unexpected hits %v (%d cells)", got, idx.Len())
+	}
+}
+
+func TestIndexReopen(t *testing.T) {
+	pathname := filepath.Join(t.TempDir(), "search")
+	idx, err := search.NewIndex(pathname)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cellID := tag.New()
+	idx.Put(search.Entry{CellID: cellID, AttrID: amp.ChildTabSpec.ID, Value: &amp.TagTab{Label: "A Love Supreme"}})
+	if err = idx.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Putting another attr of a cell keeps the attrs indexed before the index was reopened
+	if idx, err = search.NewIndex(pathname); err != nil {
+		t.Fatal(err)
+	}
+	defer idx.Close()
+	idx.Put(search.Entry{CellID: cellID, AttrID: amp.ContentSpec.ID, Text: "impulse records"})
+	for _, query := range []string{"supreme ", "impulse "} {
+		hits, err := idx.Search(query, 0)
+		if err != nil || len(hits) != 1 || hits[0].CellID != cellID || hits[0].Label != "A Love Supreme" {
+			t.Fatalf("query %q: unexpected hits %v (%v)", query, hits, err)
+		}
+	}
+}
+
+func TestSearchApp(t *testing.T) {
+	idx, err := search.NewIndex("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idx.Close()
+	sess := amptest.NewSession(t, search.NewApp(idx))
+	defer search.Listen(sess.Bus(), idx)()
+
+	publish := func(cellID tag.ID, label string) {
+		amp.Publish(sess.Bus(), search.IndexTopic, search.Entry{CellID: cellID, AttrID: amp.ChildTabSpec.ID, Value: &amp.TagTab{Label: label}})
+	}
+	first, second := tag.New(), tag.New()
+	publish(first, "Kind of Blue")
+
+	req := sess.Pin(amp.PinRequest{
+		PinTarget: &amp.Tag{URL: "search://?q=blue"},
+		PinSync:   amp.PinSync_Maintain,
+	})
+	req.WaitForStatus(amp.OpStatus_Synced)
+	var hit amp.SearchHit
+	req.RequireAttr(first, amp.SearchHitSpec.ID, &hit)
+	if hit.Rank != 0 || hit.Score <= 0 {
+		t.Fatalf("unexpected hit %+v", hit)
+	}
+
+	// Hits update live as the index changes
+	publish(second, "Blue")
+	deadline := time.Now().Add(5 * time.Second)
+	for found, _ := req.Attr(second, amp.SearchHitSpec.ID, tag.Nil, &hit); !found; found, _ = req.Attr(second, amp.SearchHitSpec.ID, tag.Nil, &hit) {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for new hit")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if hit.Rank != 0 {
+		t.Fatalf("expected shorter label to rank first, got %+v", hit)
+	}
+	req.Close()
+	req.RequireComplete()
+
+	if _, err := sess.TryPin(amp.PinRequest{PinTarget: &amp.Tag{URL: "search://?q="}}); err == nil {
+		t.Fatal("expected empty query to be rejected")
+	}
+}
@@ -0,0 +1,501 @@
+// Package std offers standard cell archetypes -- a shared vocabulary of attrs and helpers for the kinds of cells many apps
+// present -- so that clients can present them generically and apps need not invent their own encodings.
+package std
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/amp-3d/amp-sdk-go/amp"
+	"github.com/amp-3d/amp-sdk-go/stdlib/tag"
+)
+
+// Tables
+//
+// A table cell (e.g. a log, a spreadsheet, or the results of a query) holds:
+//   - its TagTab
+//   - TableHeaderSpec, holding the TableHeader declaring its columns and sort keys
+//   - TableRowSpec, holding a TableRow for each row, where the SI is the row's key (see RowKey)
+//
+// An app keeps a table's rows in a Table and emits them via Table.MarshalSince, so that each push to a client holds only
+// the rows changed since the client's previous push.  A client mirrors a table cell by applying each tx pushed to it via
+// Table.Apply.
+
+var (
+	TableSpec       = tag.FormSpec(tag.Spec{}, "amp.std.table")
+	TableHeaderSpec = tag.FormSpec(amp.AttrSpec, "TableHeader")
+	TableRowSpec    = tag.FormSpec(amp.AttrSpec, "TableRow")
+)
+
+// MaxTableTombstones is how many removed rows a Table remembers, so that a push since an older revision can delete them.
+// A push since a revision older than any remembered resets the client's table (see Table.MarshalSince).
+const MaxTableTombstones = 4096
+
+// RowKey returns the key of the row with the given sequence number (e.g. a line of a log or a row of a spreadsheet).
+func RowKey(seq int64) tag.ID {
+	return tag.ID{0, 0, uint64(seq)}
+}
+
+// TableSchema returns the schema of a table cell having the given columns, so that committed rows are validated.
+func TableSchema(columns []*amp.TableColumn) *amp.Schema {
+	return &amp.Schema{
+		Archetype: TableSpec,
+		Attrs: []amp.AttrSchema{
+			{
+				Attr:     TableHeaderSpec,
+				TypeName: "TableHeader",
+			}, {
+				Attr:     TableRowSpec,
+				TypeName: "TableRow",
+				Check: func(val amp.ElemVal) error {
+					return checkRow(columns, val.(*amp.TableRow))
+				},
+			},
+		},
+	}
+}
+
+// Table is a concurrency-safe set of rows having the same columns, tracking each change by revision so that pushes of it
+// can be incremental.
+type Table struct {
+	mu         sync.Mutex
+	header     amp.TableHeader
+	rows       map[tag.ID]*tableRow
+	tombstones map[tag.ID]uint64 // key of removed row -> revision removed
+	rev        uint64            // incremented on each change
+	headerRev  uint64            // revision the header last changed
+	floor      uint64            // oldest revision a push can be incremental since
+}
+
+type tableRow struct {
+	row *amp.TableRow
+	rev uint64 // revision the row last changed
+}
+
+// NewTable returns an empty table having the given columns.
+func NewTable(columns ...*amp.TableColumn) *Table {
+	return &Table{
+		header: amp.TableHeader{
+			Columns: columns,
+		},
+		rows:       make(map[tag.ID]*tableRow),
+		tombstones: make(map[tag.ID]uint64),
+		rev:        1,
+		headerRev:  1,
+	}
+}
+
+// Header returns the columns and sort keys of this table.
+func (t *Table) Header() *amp.TableHeader {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	header := t.header
+	return &header
+}
+
+// SetSort sets the order of this table's rows.
+func (t *Table) SetSort(keys ...*amp.TableSortKey) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.header.Sort = keys
+	t.rev++
+	t.headerRev = t.rev
+}
+
+// Column returns the index of the named column, or -1 if there is none.
+func (t *Table) Column(name string) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return columnIndex(t.header.Columns, name)
+}
+
+// Len returns the number of rows in this table.
+func (t *Table) Len() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.rows)
+}
+
+// Rev returns the current revision of this table, which increases with each change.
+func (t *Table) Rev() uint64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.rev
+}
+
+// Put adds or replaces the row having the given key, holding a value for each column: a string, integer, float, bool,
+// time.Time, or []byte (as the column's type requires), or nil if the row has no value for the column.
+func (t *Table) Put(key tag.ID, values ...any) error {
+	t.mu.Lock()
+	columns := t.header.Columns
+	t.mu.Unlock()
+
+	if len(values) != len(columns) {
+		return amp.ErrCode_BadSchema.Errorf("table: got %d values for %d columns", len(values), len(columns))
+	}
+	row := &amp.TableRow{
+		Values: make([]*amp.TableValue, len(values)),
+	}
+	for i, val := range values {
+		tv, err := NewTableValue(columns[i].Type, val)
+		if err != nil {
+			return amp.ErrCode_BadSchema.Errorf("table: column %q (%v) cannot hold %T", columns[i].Name, columns[i].Type, val)
+		}
+		row.Values[i] = tv
+	}
+	return t.PutRow(key, row)
+}
+
+// PutRow adds or replaces the row having the given key.
+func (t *Table) PutRow(key tag.ID, row *amp.TableRow) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if err := checkRow(t.header.Columns, row); err != nil {
+		return err
+	}
+	t.rev++
+	t.rows[key] = &tableRow{row: row, rev: t.rev}
+	delete(t.tombstones, key)
+	return nil
+}
+
+// Remove removes the row having the given key, returning false if there is none.
+func (t *Table) Remove(key tag.ID) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.remove(key)
+}
+
+func (t *Table) remove(key tag.ID) bool {
+	if _, exists := t.rows[key]; !exists {
+		return false
+	}
+	t.rev++
+	delete(t.rows, key)
+	t.tombstones[key] = t.rev
+	if len(t.tombstones) > MaxTableTombstones {
+		clear(t.tombstones)
+		t.floor = t.rev
+	}
+	return true
+}
+
+// Row returns the row having the given key.
+func (t *Table) Row(key tag.ID) (*amp.TableRow, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if r := t.rows[key]; r != nil {
+		return r.row, true
+	}
+	return nil, false
+}
+
+// Keys returns the keys of the rows matching the given filter (which may be nil), ordered by the filter's sort keys,
+// then this table's sort keys (where a Null value sorts last), and then by key -- limited as the filter specifies.  A filter refers to columns by name.
+func (t *Table) Keys(filter *amp.Filter) []tag.ID {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	rows := make([]rowFields, 0, len(t.rows))
+	for key, r := range t.rows {
+		fields := rowFields{key: key, columns: t.header.Columns, row: r.row}
+		if filter.Match(fields) {
+			rows = append(rows, fields)
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		a, b := rows[i], rows[j]
+		if filter.Less(a, b) {
+			return true
+		}
+		if filter.Less(b, a) {
+			return false
+		}
+		for _, key := range t.header.Sort {
+			col := columnIndex(t.header.Columns, key.Column)
+			if col < 0 {
+				continue
+			}
+			va, vb := a.row.Values[col], b.row.Values[col]
+			if va.Null || vb.Null {
+				if va.Null != vb.Null {
+					return vb.Null
+				}
+				continue
+			}
+			if c := compareTableValues(va, vb); c != 0 {
+				return (c < 0) != key.Desc
+			}
+		}
+		return a.key.CompareTo(b.key) < 0
+	})
+	if filter != nil && filter.Limit > 0 && len(rows) > filter.Limit {
+		rows = rows[:filter.Limit]
+	}
+
+	keys := make([]tag.ID, len(rows))
+	for i, r := range rows {
+		keys[i] = r.key
+	}
+	return keys
+}
+
+// MarshalSince marshals to the given table cell the changes to this table after the given revision, returning the current
+// revision (to pass as since for the next push).  If since is 0, the whole table is marshaled.
+//
+// If this table no longer remembers the rows removed since then (see MaxTableTombstones), the cell is deleted and then
+// marshaled whole, so the client's table is reset.
+func (t *Table) MarshalSince(tx *amp.TxMsg, cellID tag.ID, since uint64) (rev uint64, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if since > t.rev {
+		since = 0 // not a revision of this table
+	}
+	if since > 0 && since < t.floor {
+		tx.MarshalOpWithBuf(&amp.TxOp{
+			OpCode:   amp.TxOpCode_DeleteCell,
+			TargetID: cellID,
+		}, nil)
+		since = 0
+	}
+
+	if t.headerRev > since {
+		if err = tx.MarshalUpsert(cellID, TableHeaderSpec.ID, &t.header); err != nil {
+			return 0, err
+		}
+	}
+	for key, r := range t.rows {
+		if r.rev <= since {
+			continue
+		}
+		op := amp.TxOp{
+			OpCode:   amp.TxOpCode_UpsertAttr,
+			TargetID: cellID,
+			AttrID:   TableRowSpec.ID,
+			SI:       key,
+		}
+		if err = tx.MarshalOp(&op, r.row); err != nil {
+			return 0, err
+		}
+	}
+	if since > 0 {
+		for key, removed := range t.tombstones {
+			if removed > since {
+				tx.MarshalOpWithBuf(&amp.TxOp{
+					OpCode:   amp.TxOpCode_DeleteAttr,
+					TargetID: cellID,
+					AttrID:   TableRowSpec.ID,
+					SI:       key,
+				}, nil)
+			}
+		}
+	}
+	return t.rev, nil
+}
+
+// Apply applies the ops of the given tx targeting the given table cell (e.g. as pushed to a client), so that this table
+// mirrors the cell.
+func (t *Table) Apply(tx *amp.TxMsg, cellID tag.ID) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for i, op := range tx.Ops {
+		if op.TargetID != cellID {
+			continue
+		}
+		switch {
+		case op.OpCode == amp.TxOpCode_DeleteCell:
+			for key := range t.rows {
+				t.remove(key)
+			}
+		case op.AttrID == TableHeaderSpec.ID && op.OpCode == amp.TxOpCode_UpsertAttr:
+			header := amp.TableHeader{}
+			if err := tx.UnmarshalOpValue(i, &header); err != nil {
+				return err
+			}
+			t.header = header
+			t.rev++
+			t.headerRev = t.rev
+		case op.AttrID == TableRowSpec.ID && op.OpCode == amp.TxOpCode_DeleteAttr:
+			t.remove(op.SI)
+		case op.AttrID == TableRowSpec.ID && op.OpCode == amp.TxOpCode_UpsertAttr:
+			row := &amp.TableRow{}
+			if err := tx.UnmarshalOpValue(i, row); err != nil {
+				return err
+			}
+			if err := checkRow(t.header.Columns, row); err != nil {
+				return err
+			}
+			t.rev++
+			t.rows[op.SI] = &tableRow{row: row, rev: t.rev}
+			delete(t.tombstones, op.SI)
+		}
+	}
+	return nil
+}
+
+// NewTableValue returns the TableValue holding the given value for a column of the given type.
+func NewTableValue(typ amp.ColumnType, val any) (*amp.TableValue, error) {
+	tv := &amp.TableValue{}
+	if val == nil {
+		tv.Null = true
+		return tv, nil
+	}
+	ok := false
+	switch typ {
+	case amp.ColumnType_Text:
+		tv.Text, ok = val.(string)
+	case amp.ColumnType_Int:
+		tv.Int, ok = toInt(val)
+	case amp.ColumnType_Float:
+		switch v := val.(type) {
+		case float32:
+			tv.Float, ok = float64(v), true
+		case float64:
+			tv.Float, ok = v, true
+		default:
+			var n int64
+			n, ok = toInt(val)
+			tv.Float = float64(n)
+		}
+	case amp.ColumnType_Bool:
+		tv.Bool, ok = val.(bool)
+	case amp.ColumnType_Time:
+		if t, isTime := val.(time.Time); isTime {
+			tv.Int, ok = t.UnixMilli(), true
+		} else {
+			tv.Int, ok = toInt(val)
+		}
+	case amp.ColumnType_Bytes:
+		tv.Bytes, ok = val.([]byte)
+	}
+	if !ok {
+		return nil, amp.ErrCode_BadSchema.Errorf("table: %v cannot hold %T", typ, val)
+	}
+	return tv, nil
+}
+
+// Value returns the Go value of the given TableValue for a column of the given type (nil if Null), where a time is a
+// time.Time.
+func Value(typ amp.ColumnType, tv *amp.TableValue) any {
+	if tv == nil || tv.Null {
+		return nil
+	}
+	switch typ {
+	case amp.ColumnType_Text:
+		return tv.Text
+	case amp.ColumnType_Int:
+		return tv.Int
+	case amp.ColumnType_Float:
+		return tv.Float
+	case amp.ColumnType_Bool:
+		return tv.Bool
+	case amp.ColumnType_Time:
+		return time.UnixMilli(tv.Int)
+	case amp.ColumnType_Bytes:
+		return tv.Bytes
+	}
+	return nil
+}
+
+func toInt(val any) (int64, bool) {
+	switch v := val.(type) {
+	case int:
+		return int64(v), true
+	case int8:
+		return int64(v), true
+	case int16:
+		return int64(v), true
+	case int32:
+		return int64(v), true
+	case int64:
+		return v, true
+	case uint:
+		return int64(v), true
+	case uint8:
+		return int64(v), true
+	case uint16:
+		return int64(v), true
+	case uint32:
+		return int64(v), true
+	case uint64:
+		return int64(v), true
+	}
+	return 0, false
+}
+
+func checkRow(columns []*amp.TableColumn, row *amp.TableRow) error {
+	if len(row.Values) != len(columns) {
+		return amp.ErrCode_BadSchema.Errorf("table: row has %d values for %d columns", len(row.Values), len(columns))
+	}
+	for i, tv := range row.Values {
+		if tv == nil {
+			return amp.ErrCode_BadSchema.Errorf("table: column %q: missing value", columns[i].Name)
+		}
+	}
+	return nil
+}
+
+func columnIndex(columns []*amp.TableColumn, name string) int {
+	for i, col := range columns {
+		if col.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// compareTableValues returns -1, 0, or 1 as a sorts before, with, or after b (neither being Null).
+func compareTableValues(a, b *amp.TableValue) int {
+	switch {
+	case a.Text != b.Text:
+		return strings.Compare(a.Text, b.Text)
+	case a.Int != b.Int:
+		if a.Int < b.Int {
+			return -1
+		}
+		return 1
+	case a.Float != b.Float:
+		if a.Float < b.Float {
+			return -1
+		}
+		return 1
+	case a.Bool != b.Bool:
+		if b.Bool {
+			return -1
+		}
+		return 1
+	}
+	return bytes.Compare(a.Bytes, b.Bytes)
+}
+
+// rowFields is a row as an amp.FieldSource, offering each column's value by the column's name (where a time is in unix
+// milliseconds).
+type rowFields struct {
+	key     tag.ID
+	columns []*amp.TableColumn
+	row     *amp.TableRow
+}
+
+func (r rowFields) FieldValue(name string) (any, bool) {
+	col := columnIndex(r.columns, name)
+	if col < 0 {
+		return nil, false
+	}
+	tv := r.row.Values[col]
+	if tv.Null {
+		return nil, false
+	}
+	switch typ := r.columns[col].Type; typ {
+	case amp.ColumnType_Time:
+		return tv.Int, true
+	case amp.ColumnType_Bytes:
+		return nil, false
+	default:
+		return Value(typ, tv), true
+	}
+}
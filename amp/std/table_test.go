@@ -0,0 +1,137 @@
+package std_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/amp-3d/amp-sdk-go/amp"
+	"github.com/amp-3d/amp-sdk-go/amp/std"
+	"github.com/amp-3d/amp-sdk-go/stdlib/tag"
+)
+
+var logColumns = []*amp.TableColumn{
+	{Name: "time", Label: "Time", Type: amp.ColumnType_Time},
+	{Name: "level", Label: "Level", Type: amp.ColumnType_Text},
+	{Name: "msg", Label: "Message", Type: amp.ColumnType_Text},
+	{Name: "latency", Label: "Latency", Type: amp.ColumnType_Float, Unit: "ms"},
+	{Name: "ok", Label: "OK", Type: amp.ColumnType_Bool},
+}
+
+// push marshals the changes to src since the given revision into a tx and applies it to dst, returning the tx.
+func push(t *testing.T, src, dst *std.Table, cellID tag.ID, since *uint64) *amp.TxMsg {
+	tx := amp.NewTxMsg(true)
+	rev, err := src.MarshalSince(tx, cellID, *since)
+	if err != nil {
+		t.Fatal(err)
+	}
+	*since = rev
+	if err = dst.Apply(tx, cellID); err != nil {
+		t.Fatal(err)
+	}
+	return tx
+}
+
+func requireMirrored(t *testing.T, src, dst *std.Table) {
+	t.Helper()
+	if !reflect.DeepEqual(src.Header(), dst.Header()) {
+		t.Fatalf("header not mirrored: %+v", dst.Header())
+	}
+	keys := src.Keys(nil)
+	if got := dst.Keys(nil); !reflect.DeepEqual(keys, got) {
+		t.Fatalf("expected rows %v, got %v", keys, got)
+	}
+	for _, key := range keys {
+		want, _ := src.Row(key)
+		got, _ := dst.Row(key)
+		if !reflect.DeepEqual(want, got) {
+			t.Fatalf("row %v not mirrored: %+v", key, got)
+		}
+	}
+}
+
+func TestTable(t *testing.T) {
+	start := time.UnixMilli(1_700_000_000_000)
+	table := std.NewTable(logColumns...)
+	for i, row := range [][]any{
+		{start, "info", "started", 1.5, true},
+		{start.Add(time.Second), "warn", "slow", 250, true},
+		{start.Add(2 * time.Second), "error", "failed", nil, false},
+		{start.Add(3 * time.Second), "warn", "slower", 900.5, true},
+	} {
+		if err := table.Put(std.RowKey(int64(i)), row...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, bad := range [][]any{
+		{start, "info", "too few"},
+		{start, "info", "msg", "fast", true},
+		{"noon", "info", "msg", 1, true},
+	} {
+		if err := table.Put(std.RowKey(9), bad...); amp.GetErrCode(err) != amp.ErrCode_BadSchema {
+			t.Fatalf("%v: expected bad schema, got %v", bad, err)
+		}
+	}
+	row, _ := table.Row(std.RowKey(1))
+	if got := std.Value(amp.ColumnType_Time, row.Values[0]); got != any(start.Add(time.Second)) || row.Values[3].Float != 250 {
+		t.Fatalf("unexpected row %+v", row)
+	}
+
+	// Rows are ordered by the filter's sort keys, then the table's, then their keys
+	table.SetSort(&amp.TableSortKey{Column: "latency", Desc: true})
+	if got := table.Keys(nil); !reflect.DeepEqual(got, []tag.ID{std.RowKey(3), std.RowKey(1), std.RowKey(0), std.RowKey(2)}) {
+		t.Fatalf("unexpected order %v", got)
+	}
+	filter, err := amp.ParseFilter(`level == "warn" && time > 1700000000000 sort msg limit 1`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := table.Keys(filter); !reflect.DeepEqual(got, []tag.ID{std.RowKey(1)}) {
+		t.Fatalf("unexpected filtered rows %v", got)
+	}
+
+	// A client mirrors the table from the first push, and each push after holds only what changed
+	cellID := tag.New()
+	mirror := std.NewTable()
+	var since uint64
+	push(t, table, mirror, cellID, &since)
+	requireMirrored(t, table, mirror)
+
+	table.Put(std.RowKey(1), start.Add(time.Second), "warn", "slow", 300, true)
+	table.Remove(std.RowKey(2))
+	table.Put(std.RowKey(4), start.Add(4*time.Second), "info", "done", 2, true)
+	tx := push(t, table, mirror, cellID, &since)
+	if len(tx.Ops) != 3 {
+		t.Fatalf("expected 3 ops, got %d", len(tx.Ops))
+	}
+	requireMirrored(t, table, mirror)
+	if tx = push(t, table, mirror, cellID, &since); len(tx.Ops) != 0 {
+		t.Fatalf("expected no ops, got %d", len(tx.Ops))
+	}
+
+	// A push since before the removed rows it no longer remembers resets the client's table
+	for i := 0; i <= std.MaxTableTombstones; i++ {
+		table.Put(std.RowKey(int64(100+i)), start, "debug", "", 0, true)
+	}
+	push(t, table, mirror, cellID, &since)
+	for i := 0; i <= std.MaxTableTombstones; i++ {
+		table.Remove(std.RowKey(int64(100 + i)))
+	}
+	tx = push(t, table, mirror, cellID, &since)
+	if tx.Ops[0].OpCode != amp.TxOpCode_DeleteCell {
+		t.Fatalf("expected reset, got %v", tx.Ops[0].OpCode)
+	}
+	requireMirrored(t, table, mirror)
+	if mirror.Len() != 4 {
+		t.Fatalf("expected 4 rows, got %d", mirror.Len())
+	}
+
+	// Committed rows are validated against the table's columns
+	schema := std.TableSchema(logColumns)
+	if err = schema.Attrs[1].Check(&amp.TableRow{Values: []*amp.TableValue{{Null: true}}}); amp.GetErrCode(err) != amp.ErrCode_BadSchema {
+		t.Fatalf("expected bad schema, got %v", err)
+	}
+	if err = schema.Attrs[1].Check(row); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,217 @@
+package amp
+
+import (
+	"github.com/amp-3d/amp-sdk-go/stdlib/tag"
+)
+
+// RulePolicy is a PolicyProvider that evaluates a fixed list of AccessRules.
+//
+// A matching AccessDeny rule always takes precedence over matching AccessAllow rules.
+// If no rule matches, the operation is allowed only if DefaultAllow is set.
+type RulePolicy struct {
+	Rules        []AccessRule `json:"rules"`
+	DefaultAllow bool         `json:"default_allow,omitempty"`
+}
+
+// Implements PolicyProvider
+func (policy *RulePolicy) Authorize(req *AccessRequest) error {
+	allowed := policy.DefaultAllow
+	for i := range policy.Rules {
+		rule := &policy.Rules[i]
+		if !rule.matches(req) {
+			continue
+		}
+		if rule.Effect == AccessDeny {
+			allowed = false
+			break
+		}
+		allowed = true
+	}
+	if !allowed {
+		return accessDenied(req)
+	}
+	return nil
+}
+
+func (rule *AccessRule) matches(req *AccessRequest) bool {
+	ops := rule.Ops
+	if ops == 0 {
+		ops = AccessOp_All
+	}
+	if ops&req.Op == 0 {
+		return false
+	}
+	if !matchesID(rule.AppID, req.AppID) || !matchesID(rule.CellID, req.CellID) || !matchesID(rule.AttrID, req.AttrID) {
+		return false
+	}
+
+	if len(rule.Users) > 0 || len(rule.Roles) > 0 {
+		if req.Identity == nil {
+			return false
+		}
+		for _, user := range rule.Users {
+			if user == req.Identity.UserUID {
+				return true
+			}
+		}
+		for _, role := range rule.Roles {
+			if req.Identity.HasRole(role) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func matchesID(ruleID, reqID tag.ID) bool {
+	return ruleID.IsNil() || ruleID == reqID
+}
+
+func accessDenied(req *AccessRequest) error {
+	user := ""
+	if req.Identity != nil {
+		user = req.Identity.UserUID
+	}
+	opName := "pin"
+	if req.Op == AccessOp_Commit {
+		opName = "commit"
+	}
+	return ErrCode_InsufficientPermissions.Errorf("%s denied for user %q (cell %s, attr %s)", opName, user, req.CellID, req.AttrID)
+}
+
+// IdentityOf returns the identity the given session acts on behalf of, or nil (an anonymous identity, matching no AccessRule
+// that lists users or roles) if the session does not implement Identifier.
+func IdentityOf(sess HostSession) *Identity {
+	if id, ok := sess.(Identifier); ok {
+		return id.Identity()
+	}
+	return nil
+}
+
+// GuardAppInstance wraps an AppInstance so that the given session identity is authorized by the given policy:
+//   - before MakeReady() and ServeRequest(), for the request's target cell, each of its PinAttrs, and each op of its CommitTx,
+//   - before each op the app pushes to the session, where ops for attrs the identity may not pin are silently withheld.
+//
+// A host calls this when it issues an AppInstance to a HostSession (see IdentityOf).
+func GuardAppInstance(appID tag.ID, inst AppInstance, id *Identity, policy PolicyProvider) AppInstance {
+	return &guardedApp{
+		AppInstance: inst,
+		guard: &accessGuard{
+			appID:  appID,
+			id:     id,
+			policy: policy,
+		},
+	}
+}
+
+type accessGuard struct {
+	appID  tag.ID
+	id     *Identity
+	policy PolicyProvider
+}
+
+func (guard *accessGuard) authorize(op AccessOp, cellID, attrID tag.ID) error {
+	return guard.policy.Authorize(&AccessRequest{
+		Identity: guard.id,
+		Op:       op,
+		AppID:    guard.appID,
+		CellID:   cellID,
+		AttrID:   attrID,
+	})
+}
+
+// authorizeRequest checks the pin and commit operations implied by the given request.
+func (guard *accessGuard) authorizeRequest(req *Request) error {
+	cellID := req.TargetID()
+	if err := guard.authorize(AccessOp_Pin, cellID, tag.Nil); err != nil {
+		return err
+	}
+	for _, attr := range req.PinAttrs {
+		if attr == nil {
+			continue
+		}
+		if err := guard.authorize(AccessOp_Pin, cellID, attr.TagID()); err != nil {
+			return err
+		}
+	}
+	if req.CommitTx != nil {
+		for _, op := range req.CommitTx.Ops {
+			if err := guard.authorize(AccessOp_Commit, op.TargetID, op.AttrID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (guard *accessGuard) serve(pinner Pinner, req Requester) (Pin, error) {
+	if err := guard.authorizeRequest(req.Request()); err != nil {
+		return nil, err
+	}
+	pin, err := pinner.ServeRequest(&guardedRequester{
+		Requester: req,
+		guard:     guard,
+	})
+	if err != nil || pin == nil {
+		return pin, err
+	}
+	return &guardedPin{
+		Pin:   pin,
+		guard: guard,
+	}, nil
+}
+
+type guardedApp struct {
+	AppInstance
+	guard *accessGuard
+}
+
+func (app *guardedApp) MakeReady(req Requester) error {
+	if err := app.guard.authorizeRequest(req.Request()); err != nil {
+		return err
+	}
+	return app.AppInstance.MakeReady(req)
+}
+
+func (app *guardedApp) ServeRequest(req Requester) (Pin, error) {
+	return app.guard.serve(app.AppInstance, req)
+}
+
+type guardedPin struct {
+	Pin
+	guard *accessGuard
+}
+
+func (pin *guardedPin) ServeRequest(req Requester) (Pin, error) {
+	return pin.guard.serve(pin.Pin, req)
+}
+
+type guardedRequester struct {
+	Requester
+	guard *accessGuard
+}
+
+// PushTx withholds ops the session may not pin, forwarding the remainder.
+func (req *guardedRequester) PushTx(tx *TxMsg) error {
+	allowed := make([]bool, len(tx.Ops))
+	numAllowed := 0
+	for i, op := range tx.Ops {
+		if req.guard.authorize(AccessOp_Pin, op.TargetID, op.AttrID) == nil {
+			allowed[i] = true
+			numAllowed++
+		}
+	}
+	if numAllowed == len(tx.Ops) {
+		return req.Requester.PushTx(tx)
+	}
+
+	filtered := tx.Derive()
+	for i := range tx.Ops {
+		if allowed[i] {
+			filtered.CarryOp(&tx.Ops[i])
+		}
+	}
+	tx.ReleaseRef()
+	return req.Requester.PushTx(filtered)
+}
@@ -0,0 +1,50 @@
+package amp
+
+import (
+	"time"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/media"
+)
+
+// PublishAssetTag publishes the given asset via pub (typically an AppContext) and returns a Tag linking to it, which an app emits
+// as a media attr.  If the host signs asset URLs, the URL expires and may only be valid for the client's session.
+// If pub is a media.AssetAdapter (e.g. it transcodes media for the client), the Tag describes the asset as adapted.
+func PublishAssetTag(pub media.Publisher, asset media.Asset, opts media.PublishOpts) (*Tag, error) {
+	adapter, ok := pub.(media.AssetAdapter)
+	if !ok {
+		if ctx, isApp := pub.(AppContext); isApp && ctx.Session() != nil {
+			adapter, ok = ctx.Session().AssetPublisher().(media.AssetAdapter)
+		}
+	}
+	if ok {
+		adapted, err := adapter.AdaptAsset(asset)
+		if err != nil {
+			return nil, err
+		}
+		asset = adapted
+	}
+	url, err := pub.PublishAsset(asset, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &Tag{
+		URL:         url,
+		ContentType: asset.ContentType(),
+	}, nil
+}
+
+// SignAssetURL signs the given URL of an asset served by the host (e.g. by content hash) so that it is valid for the given
+// duration (or the host's default if <= 0).  If pub (or the AssetPublisher of an AppContext's session) is not a media.URLSigner,
+// the host does not sign asset URLs and rawURL is returned as-is.
+func SignAssetURL(pub media.Publisher, rawURL string, expiry time.Duration) (string, error) {
+	signer, ok := pub.(media.URLSigner)
+	if !ok {
+		if ctx, isApp := pub.(AppContext); isApp && ctx.Session() != nil {
+			signer, ok = ctx.Session().AssetPublisher().(media.URLSigner)
+		}
+	}
+	if !ok {
+		return rawURL, nil
+	}
+	return signer.SignURL(rawURL, expiry)
+}
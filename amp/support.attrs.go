@@ -1,6 +1,7 @@
 package amp
 
 import (
+	"errors"
 	"time"
 
 	"github.com/amp-3d/amp-sdk-go/stdlib/tag"
@@ -58,6 +59,9 @@ var (
 
 	PinnedTabSpec = tag.FormSpec(AttrSpec, "pinned.TagTab")
 	ChildTabSpec  = tag.FormSpec(AttrSpec, "TagTab")
+	PageInfoSpec  = tag.FormSpec(AttrSpec, "PageInfo")
+	ProgressSpec  = tag.FormSpec(AttrSpec, "Progress")
+	SearchHitSpec = tag.FormSpec(AttrSpec, "SearchHit")
 
 	//PinnableContent    = FormPinnableTag(ContentSpec)
 	PinnableCatalog = FormPinnableTag(TabCatalogSpec)
@@ -74,6 +78,23 @@ func RegisterBuiltinTypes(reg Registry) error {
 		&LoginResponse{},
 		&AuthCheckpoint{},
 		&PinRequest{},
+		&PageInfo{},
+		&SearchHit{},
+		&LWWRegister{},
+		&ORSet{},
+		&RGAList{},
+		&MigrationLog{},
+		&PinStats{},
+		&BlobChunk{},
+		&MediaInfo{},
+		&WaveformPeaks{},
+		&PlaylistEntry{},
+		&PlaybackEvent{},
+		&TrackOffset{},
+		&GeoPoint{},
+		&TableHeader{},
+		&TableRow{},
+		&Progress{},
 	}
 
 	for _, pi := range prototypes {
@@ -81,6 +102,10 @@ func RegisterBuiltinTypes(reg Registry) error {
 	}
 
 	reg.RegisterPrototype(tag.FormSpec(AttrSpec, "genesis"), &Tag{}, "")
+	reg.RegisterPrototype(AttrSpec, &TagTab{}, "")              // ChildTabSpec
+	reg.RegisterPrototype(AttrSpec, &TagTab{}, "pinned.TagTab") // PinnedTabSpec
+	reg.RegisterPrototype(AttrSpec, &Tag{}, "artwork.Tag")      // ArtworkSpec
+	reg.RegisterPrototype(AttrSpec, &Tag{}, "thumbnail.Tag")    // ThumbnailSpec
 	return nil
 }
 
@@ -103,8 +128,15 @@ func ErrorToValue(v error) ElemVal {
 	}
 	arcErr, _ := v.(*Err)
 	if arcErr == nil {
-		wrapped := ErrCode_UnnamedErr.Wrap(v)
-		arcErr = wrapped.(*Err)
+		var inner *Err
+		code := ErrCode_UnnamedErr
+		if errors.As(v, &inner) {
+			code = inner.Code
+		}
+		arcErr = code.Wrap(v).(*Err)
+		if inner != nil {
+			arcErr.Retryable = inner.Retryable
+		}
 	}
 	return arcErr
 }
@@ -313,6 +345,30 @@ func (v *PinRequest) TargetID() tag.ID {
 	}
 }
 
+func (v *PageInfo) MarshalToStore(in []byte) (out []byte, err error) {
+	return MarshalPbToStore(v, in)
+}
+
+func (v *PageInfo) ElemTypeName() string {
+	return "PageInfo"
+}
+
+func (v *PageInfo) New() ElemVal {
+	return &PageInfo{}
+}
+
+func (v *SearchHit) MarshalToStore(in []byte) (out []byte, err error) {
+	return MarshalPbToStore(v, in)
+}
+
+func (v *SearchHit) ElemTypeName() string {
+	return "SearchHit"
+}
+
+func (v *SearchHit) New() ElemVal {
+	return &SearchHit{}
+}
+
 /*
 func (v *Request) AttrsToPin() map[tag.ID]struct{} {
 	pinAttrs := make(map[tag.ID]struct{}, len(v.PinAttrs))
@@ -328,3 +384,183 @@ func (v *Request) AttrsToPin() map[tag.ID]struct{} {
 	return pinAttrs
 }
 */
+
+func (v *LWWRegister) MarshalToStore(in []byte) (out []byte, err error) {
+	return MarshalPbToStore(v, in)
+}
+
+func (v *LWWRegister) ElemTypeName() string {
+	return "LWWRegister"
+}
+
+func (v *LWWRegister) New() ElemVal {
+	return &LWWRegister{}
+}
+
+func (v *ORSet) MarshalToStore(in []byte) (out []byte, err error) {
+	return MarshalPbToStore(v, in)
+}
+
+func (v *ORSet) ElemTypeName() string {
+	return "ORSet"
+}
+
+func (v *ORSet) New() ElemVal {
+	return &ORSet{}
+}
+
+func (v *RGAList) MarshalToStore(in []byte) (out []byte, err error) {
+	return MarshalPbToStore(v, in)
+}
+
+func (v *RGAList) ElemTypeName() string {
+	return "RGAList"
+}
+
+func (v *RGAList) New() ElemVal {
+	return &RGAList{}
+}
+
+func (v *MigrationLog) MarshalToStore(in []byte) (out []byte, err error) {
+	return MarshalPbToStore(v, in)
+}
+
+func (v *MigrationLog) ElemTypeName() string {
+	return "MigrationLog"
+}
+
+func (v *MigrationLog) New() ElemVal {
+	return &MigrationLog{}
+}
+
+func (v *PinStats) MarshalToStore(in []byte) (out []byte, err error) {
+	return MarshalPbToStore(v, in)
+}
+
+func (v *PinStats) ElemTypeName() string {
+	return "PinStats"
+}
+
+func (v *PinStats) New() ElemVal {
+	return &PinStats{}
+}
+
+func (v *BlobChunk) MarshalToStore(in []byte) (out []byte, err error) {
+	return MarshalPbToStore(v, in)
+}
+
+func (v *BlobChunk) ElemTypeName() string {
+	return "BlobChunk"
+}
+
+func (v *BlobChunk) New() ElemVal {
+	return &BlobChunk{}
+}
+
+func (v *MediaInfo) MarshalToStore(in []byte) (out []byte, err error) {
+	return MarshalPbToStore(v, in)
+}
+
+func (v *MediaInfo) ElemTypeName() string {
+	return "MediaInfo"
+}
+
+func (v *MediaInfo) New() ElemVal {
+	return &MediaInfo{}
+}
+
+func (v *WaveformPeaks) MarshalToStore(in []byte) (out []byte, err error) {
+	return MarshalPbToStore(v, in)
+}
+
+func (v *WaveformPeaks) ElemTypeName() string {
+	return "WaveformPeaks"
+}
+
+func (v *WaveformPeaks) New() ElemVal {
+	return &WaveformPeaks{}
+}
+
+func (v *PlaylistEntry) MarshalToStore(in []byte) (out []byte, err error) {
+	return MarshalPbToStore(v, in)
+}
+
+func (v *PlaylistEntry) ElemTypeName() string {
+	return "PlaylistEntry"
+}
+
+func (v *PlaylistEntry) New() ElemVal {
+	return &PlaylistEntry{}
+}
+
+func (v *PlaybackEvent) MarshalToStore(in []byte) (out []byte, err error) {
+	return MarshalPbToStore(v, in)
+}
+
+func (v *PlaybackEvent) ElemTypeName() string {
+	return "PlaybackEvent"
+}
+
+func (v *PlaybackEvent) New() ElemVal {
+	return &PlaybackEvent{}
+}
+
+func (v *TrackOffset) MarshalToStore(in []byte) (out []byte, err error) {
+	return MarshalPbToStore(v, in)
+}
+
+func (v *TrackOffset) ElemTypeName() string {
+	return "TrackOffset"
+}
+
+func (v *TrackOffset) New() ElemVal {
+	return &TrackOffset{}
+}
+
+func (v *GeoPoint) MarshalToStore(in []byte) (out []byte, err error) {
+	return MarshalPbToStore(v, in)
+}
+
+func (v *GeoPoint) ElemTypeName() string {
+	return "GeoPoint"
+}
+
+func (v *GeoPoint) New() ElemVal {
+	return &GeoPoint{}
+}
+
+func (v *TableHeader) MarshalToStore(in []byte) (out []byte, err error) {
+	return MarshalPbToStore(v, in)
+}
+
+func (v *TableHeader) ElemTypeName() string {
+	return "TableHeader"
+}
+
+func (v *TableHeader) New() ElemVal {
+	return &TableHeader{}
+}
+
+func (v *TableRow) MarshalToStore(in []byte) (out []byte, err error) {
+	return MarshalPbToStore(v, in)
+}
+
+func (v *TableRow) ElemTypeName() string {
+	return "TableRow"
+}
+
+func (v *TableRow) New() ElemVal {
+	return &TableRow{}
+}
+
+func (v *Progress) MarshalToStore(in []byte) (out []byte, err error) {
+	return MarshalPbToStore(v, in)
+}
+
+func (v *Progress) ElemTypeName() string {
+	return "Progress"
+}
+
+func (v *Progress) New() ElemVal {
+	return &Progress{}
+}
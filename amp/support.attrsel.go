@@ -0,0 +1,115 @@
+package amp
+
+import (
+	"github.com/amp-3d/amp-sdk-go/stdlib/tag"
+)
+
+// AttrSelection is a set of attr IDs that a request pins, where a nil AttrSelection selects every attr.
+type AttrSelection map[tag.ID]struct{}
+
+// AttrSelection returns the attrs this request's PinAttrs selects, or nil if PinAttrs is empty (meaning all attrs are pinned).
+// Each PinAttrs tag identifies an attr by its tag ID (see FormPinnableTag) or else by an attr spec in its URL.
+func (v *PinRequest) AttrSelection() AttrSelection {
+	return attrSelectionOf(v.PinAttrs)
+}
+
+func attrSelectionOf(attrs []*Tag) AttrSelection {
+	if len(attrs) == 0 {
+		return nil
+	}
+	sel := make(AttrSelection, len(attrs))
+	for _, attr := range attrs {
+		if attr == nil {
+			continue
+		}
+		attrID := attr.TagID()
+		if attrID.IsNil() && attr.URL != "" {
+			attrID = tag.FormSpec(tag.Spec{}, attr.URL).ID
+		}
+		if attrID.IsSet() {
+			sel[attrID] = struct{}{}
+		}
+	}
+	return sel
+}
+
+// Selects returns true if the given op should be sent to a client whose request has this selection.
+// Ops not specific to an attr (e.g. DeleteCell and links) and meta attr ops are always selected.
+func (sel AttrSelection) Selects(op *TxOp) bool {
+	if sel == nil || op.AttrID.IsNil() || op.OpCode == TxOpCode_MetaAttr {
+		return true
+	}
+	_, selected := sel[op.AttrID]
+	return selected
+}
+
+// SelectAttrs wraps the given AppInstance so that the txs it pushes for a request having PinAttrs only include ops for those attrs,
+// sparing the client updates to attrs of the same cells it did not ask for.
+//
+// A host calls this when it issues an AppInstance to a HostSession, so that every app honors PinAttrs.  An app may also consult
+// Request.AttrSelection() to avoid marshalling attrs that would be withheld.
+func SelectAttrs(inst AppInstance) AppInstance {
+	return &selectingApp{
+		AppInstance: inst,
+	}
+}
+
+func serveSelected(pinner Pinner, req Requester) (Pin, error) {
+	sel := req.Request().AttrSelection()
+	if sel != nil {
+		req = &selectingRequester{
+			Requester: req,
+			sel:       sel,
+		}
+	}
+	pin, err := pinner.ServeRequest(req)
+	if err != nil || pin == nil {
+		return pin, err
+	}
+	return &selectingPin{
+		Pin: pin,
+	}, nil
+}
+
+type selectingApp struct {
+	AppInstance
+}
+
+func (app *selectingApp) ServeRequest(req Requester) (Pin, error) {
+	return serveSelected(app.AppInstance, req)
+}
+
+type selectingPin struct {
+	Pin
+}
+
+func (pin *selectingPin) ServeRequest(req Requester) (Pin, error) {
+	return serveSelected(pin.Pin, req)
+}
+
+type selectingRequester struct {
+	Requester
+	sel AttrSelection
+}
+
+// PushTx withholds ops for attrs the request did not select, forwarding the remainder.
+func (req *selectingRequester) PushTx(tx *TxMsg) error {
+	numSelected := 0
+	for i := range tx.Ops {
+		if req.sel.Selects(&tx.Ops[i]) {
+			numSelected++
+		}
+	}
+	if numSelected == len(tx.Ops) {
+		return req.Requester.PushTx(tx)
+	}
+
+	selected := tx.Derive()
+	for i := range tx.Ops {
+		if req.sel.Selects(&tx.Ops[i]) {
+			selected.CarryOp(&tx.Ops[i])
+		}
+	}
+	tx.ReleaseRef()
+	return req.Requester.PushTx(selected)
+}
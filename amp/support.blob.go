@@ -0,0 +1,288 @@
+package amp
+
+import (
+	"context"
+	"io"
+	"math/rand/v2"
+	"sync"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/tag"
+)
+
+// Chunked blobs
+//
+// A blob too large for one tx (e.g. cover art or a document preview) is pushed by PushBlob as a series of txs, each upserting a
+// BlobChunk carrying at most BlobOpts.FrameSize bytes of it as the BlobChunkSpec attr of a cell, where the op's SI identifies
+// which of the cell's blobs it is.  The receiver passes each tx it receives to a BlobAssembler, which reassembles the frames of
+// each blob, reporting progress as they arrive, and delivers the blob once its final frame is received.
+//
+// A receiver that no longer wants a blob (e.g. the user scrolled away) calls BlobAssembler.Cancel, discarding what has arrived
+// and ignoring its remaining frames.  The sender stops pushing frames once its context is done, which typically follows from
+// the client closing the pin, and ends the blob with a frame marked Canceled.
+
+// BlobChunkSpec is the attr of each frame pushed by PushBlob.
+var BlobChunkSpec = tag.FormSpec(AttrSpec, "BlobChunk")
+
+const (
+	DefaultBlobFrameSize = 256 << 10
+	DefaultMaxBlobSize   = 256 << 20
+)
+
+// BlobOpts configures PushBlob.
+type BlobOpts struct {
+	ContentType string                  // media type of the blob
+	FrameSize   int                     // max bytes of the blob per tx (default DefaultBlobFrameSize)
+	OnProgress  func(sent, total int64) // if set, called after each frame is pushed, where total is < 0 if not known
+}
+
+// PushBlob pushes the content read from r, whose size is given (or < 0 if not known), to req as the blob of the given cell and
+// SI, as described above.  It returns once the final frame is pushed, or when ctx is done or PushTx fails.
+func PushBlob(ctx context.Context, req Requester, cellID, SI tag.ID, r io.Reader, size int64, opts BlobOpts) error {
+	if opts.FrameSize <= 0 {
+		opts.FrameSize = DefaultBlobFrameSize
+	}
+	chunk := BlobChunk{
+		BlobID:      rand.Uint64() | 1, // never zero
+		ContentType: opts.ContentType,
+	}
+	if size > 0 {
+		chunk.TotalSize = size
+	}
+	buf := make([]byte, opts.FrameSize)
+	for {
+		if err := ctx.Err(); err != nil {
+			pushBlobChunk(req, cellID, SI, &BlobChunk{BlobID: chunk.BlobID, Offset: chunk.Offset, Canceled: true})
+			return err
+		}
+		n, err := io.ReadFull(r, buf)
+		if err == io.EOF || err == io.ErrUnexpectedEOF || (size >= 0 && chunk.Offset+int64(n) >= size) {
+			chunk.Final, err = true, nil
+		}
+		if err != nil {
+			pushBlobChunk(req, cellID, SI, &BlobChunk{BlobID: chunk.BlobID, Offset: chunk.Offset, Canceled: true})
+			return err
+		}
+		chunk.Data = buf[:n]
+		if chunk.Final {
+			chunk.TotalSize = chunk.Offset + int64(n)
+		}
+		if err = pushBlobChunk(req, cellID, SI, &chunk); err != nil {
+			return err
+		}
+		chunk.Offset += int64(n)
+		chunk.ContentType = ""
+		if opts.OnProgress != nil {
+			total := size
+			if chunk.Final {
+				total = chunk.Offset
+			}
+			opts.OnProgress(chunk.Offset, total)
+		}
+		if chunk.Final {
+			return nil
+		}
+	}
+}
+
+func pushBlobChunk(req Requester, cellID, SI tag.ID, chunk *BlobChunk) error {
+	tx := NewTxMsg(true)
+	err := tx.MarshalOp(&TxOp{
+		OpCode:   TxOpCode_UpsertAttr,
+		TargetID: cellID,
+		AttrID:   BlobChunkSpec.ID,
+		SI:       SI,
+	}, chunk)
+	if err != nil {
+		tx.ReleaseRef()
+		return err
+	}
+	return req.PushTx(tx)
+}
+
+// Blob is a blob reassembled by a BlobAssembler.
+type Blob struct {
+	TargetID    tag.ID // cell the blob is of
+	SI          tag.ID // which of the cell's blobs this is
+	ContentType string
+	Data        []byte
+}
+
+// BlobProgress reports the frames of a blob received so far.
+type BlobProgress struct {
+	TargetID tag.ID
+	SI       tag.ID
+	Received int64 // bytes received
+	Total    int64 // size of the blob, or zero if not yet known
+}
+
+// BlobAssemblerOpts configures a BlobAssembler.
+type BlobAssemblerOpts struct {
+	MaxSize    int64                       // a blob exceeding this is discarded (default DefaultMaxBlobSize)
+	OnProgress func(progress BlobProgress) // if set, called as each frame is received
+	OnBlob     func(blob *Blob)            // called as each blob is completely received
+}
+
+// BlobAssembler reassembles blobs pushed via PushBlob -- concurrency safe.
+type BlobAssembler struct {
+	opts     BlobAssemblerOpts
+	mu       sync.Mutex
+	pending  map[blobKey]*pendingBlob
+	canceled map[uint64]struct{} // blobs whose remaining frames are ignored
+}
+
+type blobKey struct {
+	targetID tag.ID
+	SI       tag.ID
+}
+
+type pendingBlob struct {
+	id          uint64
+	contentType string
+	total       int64
+	data        []byte
+}
+
+// NewBlobAssembler returns a BlobAssembler for the given options.
+func NewBlobAssembler(opts BlobAssemblerOpts) *BlobAssembler {
+	if opts.MaxSize <= 0 {
+		opts.MaxSize = DefaultMaxBlobSize
+	}
+	return &BlobAssembler{
+		opts:     opts,
+		pending:  make(map[blobKey]*pendingBlob),
+		canceled: make(map[uint64]struct{}),
+	}
+}
+
+// ReceiveTx consumes the BlobChunkSpec ops of the given tx, ignoring other ops.  A frame that does not follow the frames received
+// before it (or that exceeds BlobAssemblerOpts.MaxSize) discards its blob and returns an error after the tx's other frames are consumed.
+func (asm *BlobAssembler) ReceiveTx(tx *TxMsg) error {
+	var firstErr error
+	for i := range tx.Ops {
+		op := &tx.Ops[i]
+		if op.AttrID != BlobChunkSpec.ID || op.OpCode != TxOpCode_UpsertAttr {
+			continue
+		}
+		chunk := &BlobChunk{}
+		err := tx.UnmarshalOpValue(i, chunk)
+		if err == nil {
+			err = asm.receive(blobKey{op.TargetID, op.SI}, chunk)
+		}
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (asm *BlobAssembler) receive(key blobKey, chunk *BlobChunk) error {
+	var (
+		received bool
+		progress BlobProgress
+		blob     *Blob
+	)
+	err := func() error {
+		asm.mu.Lock()
+		defer asm.mu.Unlock()
+
+		if _, canceled := asm.canceled[chunk.BlobID]; canceled {
+			if chunk.Final || chunk.Canceled {
+				delete(asm.canceled, chunk.BlobID)
+			}
+			return nil
+		}
+		pending := asm.pending[key]
+		if pending == nil || pending.id != chunk.BlobID {
+			if chunk.Canceled {
+				return nil
+			}
+			if chunk.Offset != 0 {
+				return ErrCode_MalformedTx.Errorf("blob frame at offset %d received without the frames before it", chunk.Offset)
+			}
+			// A new blob replaces one still being received
+			pending = &pendingBlob{
+				id:          chunk.BlobID,
+				contentType: chunk.ContentType,
+			}
+			asm.pending[key] = pending
+		}
+		if chunk.Canceled {
+			delete(asm.pending, key)
+			return nil
+		}
+		if chunk.Offset != int64(len(pending.data)) {
+			delete(asm.pending, key)
+			return ErrCode_MalformedTx.Errorf("blob frame at offset %d does not follow %d bytes received", chunk.Offset, len(pending.data))
+		}
+		if chunk.TotalSize > 0 {
+			pending.total = chunk.TotalSize
+		}
+		if size := int64(len(pending.data) + len(chunk.Data)); size > asm.opts.MaxSize || pending.total > asm.opts.MaxSize {
+			delete(asm.pending, key)
+			asm.canceled[chunk.BlobID] = struct{}{}
+			return ErrCode_BadValue.Errorf("blob exceeds max size of %d bytes", asm.opts.MaxSize)
+		}
+		if pending.data == nil && pending.total > 0 {
+			pending.data = make([]byte, 0, pending.total)
+		}
+		pending.data = append(pending.data, chunk.Data...)
+
+		received = true
+		progress = BlobProgress{
+			TargetID: key.targetID,
+			SI:       key.SI,
+			Received: int64(len(pending.data)),
+			Total:    pending.total,
+		}
+		if chunk.Final {
+			delete(asm.pending, key)
+			blob = &Blob{
+				TargetID:    key.targetID,
+				SI:          key.SI,
+				ContentType: pending.contentType,
+				Data:        pending.data,
+			}
+		}
+		return nil
+	}()
+	if !received {
+		return err
+	}
+
+	if asm.opts.OnProgress != nil {
+		asm.opts.OnProgress(progress)
+	}
+	if blob != nil && asm.opts.OnBlob != nil {
+		asm.opts.OnBlob(blob)
+	}
+	return nil
+}
+
+// Cancel discards the blob of the given cell and SI being received, ignoring its remaining frames.
+func (asm *BlobAssembler) Cancel(targetID, SI tag.ID) {
+	asm.mu.Lock()
+	defer asm.mu.Unlock()
+
+	key := blobKey{targetID, SI}
+	if pending := asm.pending[key]; pending != nil {
+		delete(asm.pending, key)
+		asm.canceled[pending.id] = struct{}{}
+	}
+}
+
+// Pending returns the progress of each blob being received.
+func (asm *BlobAssembler) Pending() []BlobProgress {
+	asm.mu.Lock()
+	defer asm.mu.Unlock()
+
+	progress := make([]BlobProgress, 0, len(asm.pending))
+	for key, pending := range asm.pending {
+		progress = append(progress, BlobProgress{
+			TargetID: key.targetID,
+			SI:       key.SI,
+			Received: int64(len(pending.data)),
+			Total:    pending.total,
+		})
+	}
+	return progress
+}
@@ -0,0 +1,144 @@
+package amp
+
+import (
+	"sync"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/tag"
+	"github.com/amp-3d/amp-sdk-go/stdlib/task"
+	"github.com/amp-3d/amp-sdk-go/stdlib/utils"
+)
+
+// BusTopicSpec is the parent spec of all MessageBus topics.
+var BusTopicSpec = tag.FormSpec(tag.Spec{}, "amp.bus")
+
+// DefaultBusCapacity is the mailbox capacity Subscribe() uses when MailboxOpts.Capacity is 0.
+const DefaultBusCapacity = 256
+
+// Topic identifies a MessageBus topic whose messages are of type T.
+type Topic[T any] struct {
+	tag.Spec
+}
+
+// FormTopic returns the topic with the given name (a tag.Spec expression such as "filesys.changed") under BusTopicSpec.
+func FormTopic[T any](name string) Topic[T] {
+	return Topic[T]{
+		Spec: tag.FormSpec(BusTopicSpec, name),
+	}
+}
+
+// BusOf returns the host-level bus of the given AppContext, or nil if it does not implement BusProvider.
+func BusOf(ctx AppContext) MessageBus {
+	if bp, ok := ctx.(BusProvider); ok {
+		return bp.Bus()
+	}
+	return nil
+}
+
+// Publish delivers msg to every current subscriber of the given topic, returning the number of subscribers it was delivered to.
+func Publish[T any](bus MessageBus, topic Topic[T], msg T) int {
+	return bus.Publish(topic.ID, msg)
+}
+
+// Subscription receives the messages published to a Topic via its embedded mailbox.
+type Subscription[T any] struct {
+	*utils.MailboxOf[T]
+	cancel func()
+	done   chan struct{}
+	once   sync.Once
+}
+
+// Subscribe subscribes to the given topic until ctx closes or Unsubscribe() is called.
+// Messages are queued in a mailbox created with the given options, using DefaultBusCapacity if opts.Capacity is 0.
+// Messages published to the topic that are not of type T are dropped.
+func Subscribe[T any](ctx task.Context, bus MessageBus, topic Topic[T], opts utils.MailboxOpts) *Subscription[T] {
+	if opts.Capacity == 0 {
+		opts.Capacity = DefaultBusCapacity
+	}
+	sub := &Subscription[T]{
+		MailboxOf: utils.NewMailboxWithOpts[T](opts),
+		done:      make(chan struct{}),
+	}
+
+	sub.cancel = bus.Subscribe(topic.ID, func(msg any) {
+		if v, ok := msg.(T); ok {
+			sub.Deliver(v)
+		}
+	})
+
+	go func() {
+		select {
+		case <-ctx.Closing():
+			sub.Unsubscribe()
+		case <-sub.done:
+		}
+	}()
+	return sub
+}
+
+// Unsubscribe stops delivery to this subscription.  Messages already queued remain available.
+func (sub *Subscription[T]) Unsubscribe() {
+	sub.once.Do(func() {
+		sub.cancel()
+		close(sub.done)
+	})
+}
+
+// NewMessageBus returns a MessageBus, typically created once by a Host and shared by every AppContext it issues.
+func NewMessageBus() MessageBus {
+	return &messageBus{
+		topics: make(map[tag.ID][]*busSub),
+	}
+}
+
+// messageBus implements MessageBus
+type messageBus struct {
+	mu     sync.Mutex
+	topics map[tag.ID][]*busSub // copy-on-write so delivery can occur without holding mu
+}
+
+type busSub struct {
+	deliver func(msg any)
+}
+
+func (bus *messageBus) Publish(topic tag.ID, msg any) int {
+	bus.mu.Lock()
+	subs := bus.topics[topic]
+	bus.mu.Unlock()
+
+	for _, sub := range subs {
+		sub.deliver(msg)
+	}
+	return len(subs)
+}
+
+func (bus *messageBus) Subscribe(topic tag.ID, deliver func(msg any)) (cancel func()) {
+	sub := &busSub{
+		deliver: deliver,
+	}
+
+	bus.mu.Lock()
+	prev := bus.topics[topic]
+	subs := make([]*busSub, len(prev), len(prev)+1)
+	copy(subs, prev)
+	bus.topics[topic] = append(subs, sub)
+	bus.mu.Unlock()
+
+	return func() {
+		bus.mu.Lock()
+		defer bus.mu.Unlock()
+
+		prev := bus.topics[topic]
+		for i, si := range prev {
+			if si == sub {
+				if len(prev) == 1 {
+					delete(bus.topics, topic)
+					break
+				}
+				subs := make([]*busSub, 0, len(prev)-1)
+				subs = append(subs, prev[:i]...)
+				bus.topics[topic] = append(subs, prev[i+1:]...)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,241 @@
+package amp
+
+import (
+	"sync"
+	"time"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/tag"
+)
+
+const (
+	DefaultCoalesceInterval   = 50 * time.Millisecond
+	DefaultCoalesceMaxPending = 1024
+)
+
+// CoalesceOpts configures a CoalescingRequester.
+type CoalesceOpts struct {
+	FlushInterval time.Duration // how long a pushed tx may be held before it is flushed (default DefaultCoalesceInterval)
+	MaxPending    int           // max ops held before a flush is forced (default DefaultCoalesceMaxPending)
+
+	// Attr specs with "latest value wins" semantics: a pending upsert of the same element (target, attr, and SI) is replaced rather
+	// than sent.  If empty, all upserts are coalesced.
+	LatestWins []tag.ID
+}
+
+// CoalesceStats reports the activity of a CoalescingRequester.
+type CoalesceStats struct {
+	TxsPushed    int64 // txs pushed by the app
+	TxsFlushed   int64 // txs pushed to the wrapped Requester
+	OpsCoalesced int64 // upserts replaced by a later upsert before being sent
+}
+
+// CoalescingRequester wraps a Requester, collapsing bursts of pushed txs into one tx per FlushInterval.
+//
+// Ops are sent in the order pushed, except that upserts of a "latest wins" attr element replace any pending upsert of that element.
+// A tx having a different Status than the pending tx flushes it first, and a tx with OpStatus_Closed is flushed immediately.
+// For latency-sensitive sends, an app calls Flush (see TxFlusher).
+type CoalescingRequester struct {
+	Requester
+	opts       CoalesceOpts
+	latestWins map[tag.ID]struct{}
+
+	mu       sync.Mutex // also serializes pushes to the wrapped Requester
+	info     TxInfo     // of the most recently pushed tx
+	pending  []coalescedOp
+	index    map[attrElemKey]int // pending upserts by element
+	timer    *time.Timer
+	flushErr error // set if a timed flush fails, returned by the next PushTx
+	stats    CoalesceStats
+}
+
+type coalescedOp struct {
+	op  TxOp
+	val []byte
+}
+
+var (
+	_ Requester = (*CoalescingRequester)(nil)
+	_ TxFlusher = (*CoalescingRequester)(nil)
+)
+
+// NewCoalescingRequester wraps the given Requester.
+func NewCoalescingRequester(req Requester, opts CoalesceOpts) *CoalescingRequester {
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = DefaultCoalesceInterval
+	}
+	if opts.MaxPending <= 0 {
+		opts.MaxPending = DefaultCoalesceMaxPending
+	}
+	cr := &CoalescingRequester{
+		Requester: req,
+		opts:      opts,
+		index:     make(map[attrElemKey]int),
+	}
+	if len(opts.LatestWins) > 0 {
+		cr.latestWins = make(map[tag.ID]struct{}, len(opts.LatestWins))
+		for _, attrID := range opts.LatestWins {
+			cr.latestWins[attrID] = struct{}{}
+		}
+	}
+	return cr
+}
+
+// Stats returns a snapshot of this requester's activity.
+func (cr *CoalescingRequester) Stats() CoalesceStats {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+	return cr.stats
+}
+
+func (cr *CoalescingRequester) PushTx(tx *TxMsg) error {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+
+	if err := cr.flushErr; err != nil {
+		cr.flushErr = nil
+		tx.ReleaseRef()
+		return err
+	}
+	cr.stats.TxsPushed++
+
+	if len(cr.pending) > 0 && tx.Status != cr.info.Status {
+		if err := cr.flushLocked(); err != nil {
+			tx.ReleaseRef()
+			return err
+		}
+	}
+	cr.info = tx.TxInfo
+
+	// a tx with no ops (e.g. a status change) is not held
+	if len(tx.Ops) == 0 {
+		if err := cr.flushLocked(); err != nil {
+			tx.ReleaseRef()
+			return err
+		}
+		cr.stats.TxsFlushed++
+		return cr.Requester.PushTx(tx)
+	}
+
+	for _, op := range tx.Ops {
+		val := append([]byte(nil), tx.DataStore[op.DataOfs:op.DataOfs+op.DataLen]...)
+		if op.OpCode != TxOpCode_UpsertAttr {
+			// preserve ordering relative to deletes and links
+			for key := range cr.index {
+				delete(cr.index, key)
+			}
+			cr.pending = append(cr.pending, coalescedOp{op, val})
+			continue
+		}
+		key := attrElemKey{op.TargetID, op.AttrID, op.SI}
+		if i, exists := cr.index[key]; exists && cr.isLatestWins(op.AttrID) {
+			cr.pending[i] = coalescedOp{op, val}
+			cr.stats.OpsCoalesced++
+			continue
+		}
+		cr.index[key] = len(cr.pending)
+		cr.pending = append(cr.pending, coalescedOp{op, val})
+	}
+	tx.ReleaseRef()
+
+	if cr.info.Status == OpStatus_Closed || len(cr.pending) >= cr.opts.MaxPending {
+		return cr.flushLocked()
+	}
+	if cr.timer == nil {
+		cr.timer = time.AfterFunc(cr.opts.FlushInterval, cr.onTimer)
+	}
+	return nil
+}
+
+// Flush immediately pushes any pending ops to the wrapped Requester.
+func (cr *CoalescingRequester) Flush() error {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+	return cr.flushLocked()
+}
+
+// OnComplete flushes pending ops before notifying the wrapped Requester.
+func (cr *CoalescingRequester) OnComplete(err error) {
+	cr.Flush()
+	cr.Requester.OnComplete(err)
+}
+
+func (cr *CoalescingRequester) onTimer() {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+
+	cr.timer = nil
+	if err := cr.flushLocked(); err != nil && cr.flushErr == nil {
+		cr.flushErr = err
+	}
+}
+
+func (cr *CoalescingRequester) isLatestWins(attrID tag.ID) bool {
+	if cr.latestWins == nil {
+		return true
+	}
+	_, ok := cr.latestWins[attrID]
+	return ok
+}
+
+func (cr *CoalescingRequester) flushLocked() error {
+	if cr.timer != nil {
+		cr.timer.Stop()
+		cr.timer = nil
+	}
+	if len(cr.pending) == 0 {
+		return nil
+	}
+
+	tx := NewTxMsg(false)
+	tx.TxInfo = cr.info
+	for i := range cr.pending {
+		tx.MarshalOpWithBuf(&cr.pending[i].op, cr.pending[i].val)
+		cr.pending[i] = coalescedOp{}
+	}
+	cr.pending = cr.pending[:0]
+	for key := range cr.index {
+		delete(cr.index, key)
+	}
+
+	cr.stats.TxsFlushed++
+	return cr.Requester.PushTx(tx)
+}
+
+// Coalesce wraps the given AppInstance so that the txs it pushes to each pin's Requester are coalesced.
+// A Pin reaches the CoalescingRequester for its request via the Requester it was served.
+func Coalesce(inst AppInstance, opts CoalesceOpts) AppInstance {
+	return &coalescedApp{
+		AppInstance: inst,
+		opts:        opts,
+	}
+}
+
+func serveCoalesced(pinner Pinner, req Requester, opts CoalesceOpts) (Pin, error) {
+	cr := NewCoalescingRequester(req, opts)
+	pin, err := pinner.ServeRequest(cr)
+	if err != nil || pin == nil {
+		return pin, err
+	}
+	return &coalescedPin{
+		Pin:  pin,
+		opts: opts,
+	}, nil
+}
+
+type coalescedApp struct {
+	AppInstance
+	opts CoalesceOpts
+}
+
+func (app *coalescedApp) ServeRequest(req Requester) (Pin, error) {
+	return serveCoalesced(app.AppInstance, req, app.opts)
+}
+
+type coalescedPin struct {
+	Pin
+	opts CoalesceOpts
+}
+
+func (pin *coalescedPin) ServeRequest(req Requester) (Pin, error) {
+	return serveCoalesced(pin.Pin, req, pin.opts)
+}
@@ -0,0 +1,360 @@
+package amp
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/tag"
+	"github.com/klauspost/compress/s2"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Tx compression
+//
+// A client offers the codecs it supports by sending a CompressAttrSpec meta attr (a Tag whose URL lists codec names in preference
+// order, e.g. "zstd,snappy") as its first tx.  The host replies with the codec it selected ("none" if no codec is shared) and from
+// then on each side compresses txs whose marshalled size is at least CompressOpts.Threshold.
+//
+// A compressed tx is sent as an envelope having the same TxInfo and Seq as the tx it carries and a single CompressedTxAttrSpec meta
+// attr op, where op.Height identifies the codec and the op value is the compressed TxMsg.  Since a receiver always decodes envelopes,
+// a client sends uncompressed until it receives the host's reply.
+
+var (
+	CompressAttrSpec     = tag.FormSpec(MetaAttrSpec, "compress.Tag")
+	CompressedTxAttrSpec = tag.FormSpec(MetaAttrSpec, "compress.tx")
+)
+
+// Codec names used during negotiation
+const (
+	CodecNone   = "none"
+	CodecZstd   = "zstd"
+	CodecSnappy = "snappy"
+)
+
+const (
+	DefaultCompressThreshold = 1024
+	DefaultMaxDecompressed   = 64 << 20
+)
+
+// codec IDs as carried in an envelope's op.Height
+const (
+	codecNone uint64 = iota
+	codecZstd
+	codecSnappy
+)
+
+var _ Transport = (*CompressedTransport)(nil)
+
+// CompressOpts configures a CompressedTransport.
+type CompressOpts struct {
+	Codecs          []string // supported codecs in preference order (default zstd, snappy)
+	Threshold       int      // txs smaller than this (in marshalled bytes) are sent uncompressed (default DefaultCompressThreshold)
+	MaxDecompressed int      // max size of a decompressed tx (default DefaultMaxDecompressed)
+}
+
+// CompressStats reports the activity of a CompressedTransport.
+type CompressStats struct {
+	Codec         string // the codec in use for sending
+	TxsSent       int64  // txs sent
+	TxsCompressed int64  // txs sent compressed
+	BytesIn       int64  // marshalled size of txs sent compressed
+	BytesOut      int64  // compressed size of txs sent compressed
+	TxsInflated   int64  // compressed txs received
+}
+
+// BytesSaved returns how many bytes compression has saved from being sent.
+func (stats CompressStats) BytesSaved() int64 {
+	return stats.BytesIn - stats.BytesOut
+}
+
+// CompressedTransport wraps a Transport, transparently compressing and decompressing txs using a negotiated codec.
+type CompressedTransport struct {
+	raw     Transport
+	opts    CompressOpts
+	codec   atomic.Uint64 // codec used for sending
+	pending *TxMsg        // first tx received by AcceptCompression() that is not an offer
+
+	mu      sync.Mutex // serializes use of scrap and zbuf
+	scrap   []byte
+	zbuf    []byte
+	encoder *zstd.Encoder
+	decoder *zstd.Decoder
+	stats   struct {
+		txsSent, txsCompressed, bytesIn, bytesOut, txsInflated atomic.Int64
+	}
+}
+
+// AcceptCompression reads the first tx from a newly connected Transport and, if it is a compression offer, replies with the selected codec.
+// The returned transport wraps raw and is to be passed to Host.StartNewSession().
+func AcceptCompression(raw Transport, opts CompressOpts) (*CompressedTransport, error) {
+	tr, err := newCompressedTransport(raw, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	first, err := raw.RecvTx()
+	if err != nil {
+		tr.Close()
+		return nil, err
+	}
+	offer, isOffer := parseCompressTx(first)
+	if !isOffer {
+		tr.pending = first
+		return tr, nil
+	}
+	first.ReleaseRef()
+
+	selected := CodecNone
+	for _, name := range strings.Split(offer, ",") {
+		name = strings.TrimSpace(name)
+		if tr.supports(name) {
+			selected = name
+			break
+		}
+	}
+	// Reply before selecting so that the reply itself is not compressed
+	if err = raw.SendTx(marshalCompressTx(selected)); err != nil {
+		tr.Close()
+		return nil, err
+	}
+	tr.codec.Store(codecID(selected))
+	return tr, nil
+}
+
+// OfferCompression sends raw's peer a compression offer and returns a CompressedTransport wrapping raw.
+// Txs are sent uncompressed until the peer's reply is received (via RecvTx).
+func OfferCompression(raw Transport, opts CompressOpts) (*CompressedTransport, error) {
+	tr, err := newCompressedTransport(raw, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err = raw.SendTx(marshalCompressTx(strings.Join(tr.opts.Codecs, ","))); err != nil {
+		tr.Close()
+		return nil, err
+	}
+	return tr, nil
+}
+
+func newCompressedTransport(raw Transport, opts CompressOpts) (*CompressedTransport, error) {
+	if len(opts.Codecs) == 0 {
+		opts.Codecs = []string{CodecZstd, CodecSnappy}
+	}
+	for _, name := range opts.Codecs {
+		if codecID(name) == codecNone && name != CodecNone {
+			return nil, ErrCode_BadValue.Errorf("unsupported compression codec %q", name)
+		}
+	}
+	if opts.Threshold <= 0 {
+		opts.Threshold = DefaultCompressThreshold
+	}
+	if opts.MaxDecompressed <= 0 {
+		opts.MaxDecompressed = DefaultMaxDecompressed
+	}
+
+	tr := &CompressedTransport{
+		raw:  raw,
+		opts: opts,
+	}
+	var err error
+	if tr.encoder, err = zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.SpeedDefault), zstd.WithEncoderConcurrency(1)); err != nil {
+		return nil, err
+	}
+	if tr.decoder, err = zstd.NewReader(nil, zstd.WithDecoderMaxMemory(uint64(opts.MaxDecompressed)), zstd.WithDecoderConcurrency(1)); err != nil {
+		return nil, err
+	}
+	return tr, nil
+}
+
+func (tr *CompressedTransport) supports(name string) bool {
+	for _, ni := range tr.opts.Codecs {
+		if ni == name && name != CodecNone {
+			return true
+		}
+	}
+	return false
+}
+
+// Codec returns the name of the codec used to send txs.
+func (tr *CompressedTransport) Codec() string {
+	return codecName(tr.codec.Load())
+}
+
+// Stats returns a snapshot of this transport's activity.
+func (tr *CompressedTransport) Stats() CompressStats {
+	return CompressStats{
+		Codec:         tr.Codec(),
+		TxsSent:       tr.stats.txsSent.Load(),
+		TxsCompressed: tr.stats.txsCompressed.Load(),
+		BytesIn:       tr.stats.bytesIn.Load(),
+		BytesOut:      tr.stats.bytesOut.Load(),
+		TxsInflated:   tr.stats.txsInflated.Load(),
+	}
+}
+
+func (tr *CompressedTransport) Label() string {
+	return tr.raw.Label()
+}
+
+func (tr *CompressedTransport) Close() error {
+	tr.encoder.Close()
+	tr.decoder.Close()
+	return tr.raw.Close()
+}
+
+func (tr *CompressedTransport) SendTx(tx *TxMsg) error {
+	tr.stats.txsSent.Add(1)
+
+	codec := tr.codec.Load()
+	if codec == codecNone {
+		return tr.raw.SendTx(tx)
+	}
+
+	tr.mu.Lock()
+	tx.MarshalToBuffer(&tr.scrap)
+	if len(tr.scrap) < tr.opts.Threshold {
+		tr.mu.Unlock()
+		return tr.raw.SendTx(tx)
+	}
+
+	switch codec {
+	case codecZstd:
+		tr.zbuf = tr.encoder.EncodeAll(tr.scrap, tr.zbuf[:0])
+	case codecSnappy:
+		tr.zbuf = s2.EncodeSnappy(tr.zbuf[:cap(tr.zbuf)], tr.scrap)
+	}
+	if len(tr.zbuf) >= len(tr.scrap) {
+		tr.mu.Unlock()
+		return tr.raw.SendTx(tx) // incompressible
+	}
+
+	env := NewTxMsg(false)
+	env.TxInfo = tx.TxInfo
+	env.Seq = tx.Seq
+	env.MarshalOpWithBuf(&TxOp{
+		OpCode: TxOpCode_MetaAttr,
+		AttrID: CompressedTxAttrSpec.ID,
+		Height: codec,
+	}, tr.zbuf)
+
+	tr.stats.txsCompressed.Add(1)
+	tr.stats.bytesIn.Add(int64(len(tr.scrap)))
+	tr.stats.bytesOut.Add(int64(len(tr.zbuf)))
+	tr.mu.Unlock()
+
+	tx.ReleaseRef()
+	return tr.raw.SendTx(env)
+}
+
+// RecvTx returns the next tx received, decompressing it as needed.
+func (tr *CompressedTransport) RecvTx() (*TxMsg, error) {
+	if tx := tr.pending; tx != nil {
+		tr.pending = nil
+		return tx, nil
+	}
+
+	for {
+		tx, err := tr.raw.RecvTx()
+		if err != nil {
+			return nil, err
+		}
+		if len(tx.Ops) == 0 || tx.Ops[0].OpCode != TxOpCode_MetaAttr {
+			return tx, nil
+		}
+		switch op := tx.Ops[0]; op.AttrID {
+		case CompressAttrSpec.ID:
+			if selected, ok := parseCompressTx(tx); ok && tr.supports(selected) {
+				tr.codec.Store(codecID(selected))
+			}
+			tx.ReleaseRef()
+		case CompressedTxAttrSpec.ID:
+			inner, err := tr.inflate(tx, op)
+			tx.ReleaseRef()
+			return inner, err
+		default:
+			return tx, nil
+		}
+	}
+}
+
+func (tr *CompressedTransport) inflate(env *TxMsg, op TxOp) (*TxMsg, error) {
+	if n := uint64(len(env.DataStore)); len(env.Ops) != 1 || op.DataOfs > n || op.DataLen > n-op.DataOfs {
+		return nil, ErrCode_MalformedTx.Error("malformed compressed tx")
+	}
+	src := env.DataStore[op.DataOfs : op.DataOfs+op.DataLen]
+
+	var (
+		out []byte
+		err error
+	)
+	switch op.Height {
+	case codecZstd:
+		out, err = tr.decoder.DecodeAll(src, nil)
+	case codecSnappy:
+		var n int
+		if n, err = s2.DecodedLen(src); err == nil {
+			if n > tr.opts.MaxDecompressed {
+				return nil, ErrCode_MalformedTx.Errorf("compressed tx exceeds %d bytes", tr.opts.MaxDecompressed)
+			}
+			out, err = s2.Decode(nil, src)
+		}
+	default:
+		return nil, ErrCode_MalformedTx.Errorf("unknown compression codec %d", op.Height)
+	}
+	if err != nil {
+		return nil, ErrCode_MalformedTx.Errorf("failed to decompress tx: %v", err)
+	}
+
+	tx, err := ReadTxMsg(bytes.NewReader(out))
+	if err != nil {
+		return nil, err
+	}
+	tr.stats.txsInflated.Add(1)
+	return tx, nil
+}
+
+func marshalCompressTx(codecs string) *TxMsg {
+	tx := NewTxMsg(true)
+	val := &Tag{URL: codecs}
+	buf, _ := val.MarshalToStore(nil)
+	tx.MarshalOpWithBuf(&TxOp{
+		OpCode: TxOpCode_MetaAttr,
+		AttrID: CompressAttrSpec.ID,
+	}, buf)
+	return tx
+}
+
+// parseCompressTx returns the codec list of the given compression offer or reply.
+func parseCompressTx(tx *TxMsg) (codecs string, ok bool) {
+	if len(tx.Ops) == 0 || tx.Ops[0].OpCode != TxOpCode_MetaAttr || tx.Ops[0].AttrID != CompressAttrSpec.ID {
+		return "", false
+	}
+	val := &Tag{}
+	if err := tx.UnmarshalOpValue(0, val); err != nil {
+		return "", false
+	}
+	return val.URL, true
+}
+
+func codecID(name string) uint64 {
+	switch name {
+	case CodecZstd:
+		return codecZstd
+	case CodecSnappy:
+		return codecSnappy
+	default:
+		return codecNone
+	}
+}
+
+func codecName(id uint64) string {
+	switch id {
+	case codecZstd:
+		return CodecZstd
+	case codecSnappy:
+		return CodecSnappy
+	default:
+		return CodecNone
+	}
+}
@@ -0,0 +1,412 @@
+package amp
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/tag"
+)
+
+// CRDT attrs
+//
+// A CRDT attr is an attr whose value is a conflict-free replicated data type: LWWRegister, ORSet, or RGAList.  Each write carries
+// a stamp from the writer's CRDTClock, and merging two values is commutative, associative, and idempotent, so sessions editing the
+// same cell (e.g. a collaborative playlist) converge on the same value in whatever order their commits arrive.  An app registers
+// an attr having a CRDT prototype, for example:
+//
+//	reg.RegisterPrototype(amp.AttrSpec, &amp.RGAList{}, "playlist")
+//
+// A client edits its copy of the value and commits the result as an upsert, and the app merges each commit into a CRDTAttrs
+// (see MergeTx) and pushes the merged values to its pins.  Since CRDTAttrs is concurrency safe, commits need not be serialized
+// through one app goroutine.
+
+// CRDT is an attr value that merges with concurrent writes of the same attr item.
+type CRDT interface {
+	ElemVal
+
+	// Merges the state of src, which must have the same type as this value.
+	MergeCRDT(src CRDT) error
+}
+
+// CRDTClock stamps the writes a replica makes to CRDT values -- concurrency safe.
+//
+// Stamps are hybrid logical clock times: the wall time in nanoseconds, advanced past any stamp observed so that successive stamps
+// always increase and order after the writes the replica has seen.
+type CRDTClock struct {
+	Replica uint64 // identifies this replica's writes -- must differ among replicas
+
+	mu   sync.Mutex
+	last int64
+}
+
+// NewCRDTClock returns a CRDTClock for a new replica having a random ID.
+func NewCRDTClock() *CRDTClock {
+	var id [8]byte
+	rand.Read(id[:])
+	return &CRDTClock{
+		Replica: binary.LittleEndian.Uint64(id[:]),
+	}
+}
+
+// Now returns a new stamp, greater than all previous stamps and those observed.
+func (clk *CRDTClock) Now() int64 {
+	clk.mu.Lock()
+	defer clk.mu.Unlock()
+	now := time.Now().UnixNano()
+	if now <= clk.last {
+		now = clk.last + 1
+	}
+	clk.last = now
+	return now
+}
+
+// Observe advances this clock past the given stamp, so that subsequent writes order after it.
+func (clk *CRDTClock) Observe(stamp int64) {
+	clk.mu.Lock()
+	defer clk.mu.Unlock()
+	if stamp > clk.last {
+		clk.last = stamp
+	}
+}
+
+// crdtStamp uniquely identifies a write to a CRDT value.
+type crdtStamp struct {
+	Time    int64
+	Replica uint64
+}
+
+func (s crdtStamp) compare(other crdtStamp) int {
+	switch {
+	case s.Time < other.Time:
+		return -1
+	case s.Time > other.Time:
+		return 1
+	case s.Replica < other.Replica:
+		return -1
+	case s.Replica > other.Replica:
+		return 1
+	}
+	return 0
+}
+
+func crdtTypeMismatch(dst, src CRDT) error {
+	return ErrCode_BadValue.Errorf("cannot merge %s into %s", src.ElemTypeName(), dst.ElemTypeName())
+}
+
+// Set writes the given value to this register.
+func (v *LWWRegister) Set(clock *CRDTClock, value []byte) {
+	clock.Observe(v.Time)
+	v.Value = append(v.Value[:0], value...)
+	v.Time = clock.Now()
+	v.Replica = clock.Replica
+}
+
+func (v *LWWRegister) MergeCRDT(src CRDT) error {
+	other, ok := src.(*LWWRegister)
+	if !ok {
+		return crdtTypeMismatch(v, src)
+	}
+	if (crdtStamp{other.Time, other.Replica}).compare(crdtStamp{v.Time, v.Replica}) > 0 {
+		v.Value = append(v.Value[:0], other.Value...)
+		v.Time = other.Time
+		v.Replica = other.Replica
+	}
+	return nil
+}
+
+// Add adds the given value to this set.
+func (v *ORSet) Add(clock *CRDTClock, value []byte) {
+	v.Adds = append(v.Adds, &ORSetEntry{
+		Value:   append([]byte(nil), value...),
+		Time:    clock.Now(),
+		Replica: clock.Replica,
+	})
+}
+
+// Remove removes the given value from this set, returning false if it was not present.
+// An add of the same value not yet merged into this set (i.e. a concurrent add) is not removed.
+func (v *ORSet) Remove(value []byte) bool {
+	removed := false
+	adds := v.Adds[:0]
+	for _, add := range v.Adds {
+		if bytes.Equal(add.Value, value) {
+			v.Removes = append(v.Removes, &ORSetEntry{
+				Time:    add.Time,
+				Replica: add.Replica,
+			})
+			removed = true
+		} else {
+			adds = append(adds, add)
+		}
+	}
+	v.Adds = adds
+	return removed
+}
+
+// Contains returns true if the given value is in this set.
+func (v *ORSet) Contains(value []byte) bool {
+	for _, add := range v.Adds {
+		if bytes.Equal(add.Value, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// Values returns the distinct values of this set, ordered by when they were first added.
+func (v *ORSet) Values() [][]byte {
+	adds := append([]*ORSetEntry(nil), v.Adds...)
+	sort.Slice(adds, func(i, j int) bool {
+		return (crdtStamp{adds[i].Time, adds[i].Replica}).compare(crdtStamp{adds[j].Time, adds[j].Replica}) < 0
+	})
+	values := make([][]byte, 0, len(adds))
+	seen := make(map[string]struct{}, len(adds))
+	for _, add := range adds {
+		if _, exists := seen[string(add.Value)]; !exists {
+			seen[string(add.Value)] = struct{}{}
+			values = append(values, add.Value)
+		}
+	}
+	return values
+}
+
+func (v *ORSet) MergeCRDT(src CRDT) error {
+	other, ok := src.(*ORSet)
+	if !ok {
+		return crdtTypeMismatch(v, src)
+	}
+	removed := make(map[crdtStamp]struct{}, len(v.Removes)+len(other.Removes))
+	for _, entry := range v.Removes {
+		removed[crdtStamp{entry.Time, entry.Replica}] = struct{}{}
+	}
+	for _, entry := range other.Removes {
+		stamp := crdtStamp{entry.Time, entry.Replica}
+		if _, exists := removed[stamp]; !exists {
+			removed[stamp] = struct{}{}
+			v.Removes = append(v.Removes, &ORSetEntry{Time: entry.Time, Replica: entry.Replica})
+		}
+	}
+
+	added := make(map[crdtStamp]struct{}, len(v.Adds)+len(other.Adds))
+	adds := make([]*ORSetEntry, 0, len(v.Adds)+len(other.Adds))
+	for _, add := range v.Adds {
+		stamp := crdtStamp{add.Time, add.Replica}
+		if _, isRemoved := removed[stamp]; !isRemoved {
+			added[stamp] = struct{}{}
+			adds = append(adds, add)
+		}
+	}
+	for _, add := range other.Adds {
+		stamp := crdtStamp{add.Time, add.Replica}
+		_, isRemoved := removed[stamp]
+		if _, exists := added[stamp]; !exists && !isRemoved {
+			added[stamp] = struct{}{}
+			entry := *add
+			adds = append(adds, &entry)
+		}
+	}
+	v.Adds = adds
+	return nil
+}
+
+// Len returns the number of elements in this list (excluding removed elements).
+func (v *RGAList) Len() int {
+	n := 0
+	for _, elem := range v.Elems {
+		if !elem.Removed {
+			n++
+		}
+	}
+	return n
+}
+
+// Values returns the values of the elements of this list, in order.
+func (v *RGAList) Values() [][]byte {
+	elems := v.ordered()
+	values := make([][]byte, len(elems))
+	for i, elem := range elems {
+		values[i] = elem.Value
+	}
+	return values
+}
+
+// Insert inserts the given value so that it becomes the element at the given index (which may be Len()).
+func (v *RGAList) Insert(clock *CRDTClock, index int, value []byte) error {
+	elems := v.ordered()
+	if index < 0 || index > len(elems) {
+		return ErrCode_BadValue.Errorf("list index %d out of range [0, %d]", index, len(elems))
+	}
+	elem := &RGAElem{
+		Value: append([]byte(nil), value...),
+	}
+	if index > 0 {
+		elem.AfterTime = elems[index-1].Time
+		elem.AfterReplica = elems[index-1].Replica
+	}
+
+	// The new element must order before existing elements inserted at the same position.
+	for _, existing := range v.Elems {
+		clock.Observe(existing.Time)
+	}
+	elem.Time = clock.Now()
+	elem.Replica = clock.Replica
+	v.Elems = append(v.Elems, elem)
+	return nil
+}
+
+// Remove removes the element at the given index.
+func (v *RGAList) Remove(index int) error {
+	elems := v.ordered()
+	if index < 0 || index >= len(elems) {
+		return ErrCode_BadValue.Errorf("list index %d out of range [0, %d)", index, len(elems))
+	}
+	elems[index].Removed = true
+	return nil
+}
+
+func (v *RGAList) MergeCRDT(src CRDT) error {
+	other, ok := src.(*RGAList)
+	if !ok {
+		return crdtTypeMismatch(v, src)
+	}
+	index := make(map[crdtStamp]*RGAElem, len(v.Elems))
+	for _, elem := range v.Elems {
+		index[crdtStamp{elem.Time, elem.Replica}] = elem
+	}
+	for _, elem := range other.Elems {
+		if existing := index[crdtStamp{elem.Time, elem.Replica}]; existing != nil {
+			existing.Removed = existing.Removed || elem.Removed
+		} else {
+			merged := *elem
+			index[crdtStamp{elem.Time, elem.Replica}] = &merged
+			v.Elems = append(v.Elems, &merged)
+		}
+	}
+	return nil
+}
+
+// ordered returns the elements of this list that are not removed, in order.
+//
+// Each element follows the element it was inserted after, and elements inserted after the same element are ordered by descending
+// stamp, which places an insert before those it did not see (see Insert).
+func (v *RGAList) ordered() []*RGAElem {
+	after := make(map[crdtStamp][]*RGAElem, len(v.Elems))
+	for _, elem := range v.Elems {
+		parent := crdtStamp{elem.AfterTime, elem.AfterReplica}
+		after[parent] = append(after[parent], elem)
+	}
+	for _, siblings := range after {
+		sort.Slice(siblings, func(i, j int) bool {
+			return (crdtStamp{siblings[i].Time, siblings[i].Replica}).compare(crdtStamp{siblings[j].Time, siblings[j].Replica}) > 0
+		})
+	}
+
+	elems := make([]*RGAElem, 0, len(v.Elems))
+	var visit func(parent crdtStamp)
+	visit = func(parent crdtStamp) {
+		for _, elem := range after[parent] {
+			if !elem.Removed {
+				elems = append(elems, elem)
+			}
+			visit(crdtStamp{elem.Time, elem.Replica})
+		}
+	}
+	visit(crdtStamp{})
+	return elems
+}
+
+// CRDTAttrs holds the merged values of CRDT attr items -- concurrency safe.
+type CRDTAttrs struct {
+	reg Registry
+
+	mu    sync.Mutex
+	items map[crdtItem]CRDT
+}
+
+type crdtItem struct {
+	cellID, attrID, SI tag.ID
+}
+
+// NewCRDTAttrs returns an empty CRDTAttrs, using the given registry to decode committed values.
+func NewCRDTAttrs(reg Registry) *CRDTAttrs {
+	return &CRDTAttrs{
+		reg:   reg,
+		items: make(map[crdtItem]CRDT),
+	}
+}
+
+// Get returns a copy of the merged value of the given attr item, or nil if none has been merged.
+func (attrs *CRDTAttrs) Get(cellID, attrID, SI tag.ID) CRDT {
+	attrs.mu.Lock()
+	defer attrs.mu.Unlock()
+	val := attrs.items[crdtItem{cellID, attrID, SI}]
+	if val == nil {
+		return nil
+	}
+	return cloneCRDT(val)
+}
+
+// Merge merges the given value into the given attr item, returning a copy of the merged value.
+func (attrs *CRDTAttrs) Merge(cellID, attrID, SI tag.ID, val CRDT) (CRDT, error) {
+	attrs.mu.Lock()
+	defer attrs.mu.Unlock()
+
+	item := crdtItem{cellID, attrID, SI}
+	merged := attrs.items[item]
+	if merged == nil {
+		merged = val.New().(CRDT)
+		attrs.items[item] = merged
+	}
+	if err := merged.MergeCRDT(val); err != nil {
+		return nil, err
+	}
+	return cloneCRDT(merged), nil
+}
+
+// MergeTx merges each upsert of the given tx having a CRDT attr, returning a tx upserting the merged value of each of those items
+// (or nil if the tx has none).  Other ops are ignored.
+func (attrs *CRDTAttrs) MergeTx(tx *TxMsg) (*TxMsg, error) {
+	var merged *TxMsg
+	for i, op := range tx.Ops {
+		if op.OpCode != TxOpCode_UpsertAttr {
+			continue
+		}
+		elem, err := attrs.reg.NewAttrElem(op.AttrID)
+		if err != nil {
+			continue
+		}
+		val, isCRDT := elem.(CRDT)
+		if !isCRDT {
+			continue
+		}
+		if err = tx.UnmarshalOpValue(i, val); err != nil {
+			return nil, err
+		}
+		if val, err = attrs.Merge(op.TargetID, op.AttrID, op.SI, val); err != nil {
+			return nil, err
+		}
+		if merged == nil {
+			merged = NewTxMsg(true)
+		}
+		if err = merged.MarshalOp(&TxOp{
+			OpCode:   TxOpCode_UpsertAttr,
+			TargetID: op.TargetID,
+			AttrID:   op.AttrID,
+			SI:       op.SI,
+		}, val); err != nil {
+			return nil, err
+		}
+	}
+	return merged, nil
+}
+
+func cloneCRDT(val CRDT) CRDT {
+	buf, _ := val.MarshalToStore(nil)
+	clone := val.New().(CRDT)
+	clone.Unmarshal(buf)
+	return clone
+}
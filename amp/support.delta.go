@@ -0,0 +1,446 @@
+package amp
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/fnv"
+	"sync"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/tag"
+)
+
+// Delta encoding
+//
+// A DeltaTransport sends updates to large attrs as patches rather than whole values.  The first upsert of an opted-in attr
+// (see DeltaOpts.Attrs) is sent as TxOpCode_SnapshotAttr, which the receiver retains as a base.  Subsequent upserts of the same
+// attr element (request, target, attr, and SI) are sent as TxOpCode_PatchAttr, where the op value is a patch against the previous
+// value.  Each patch starts with the hash of the value it applies to, allowing the receiver to detect a missing or diverged base.
+// Every DeltaOpts.SnapshotEvery updates, a full snapshot is sent instead.
+//
+// On receipt, snapshots and patches are converted back to TxOpCode_UpsertAttr ops carrying the full value, so apps and client
+// code are unaffected.  The bases of a request are released once a tx with OpStatus_Closed is sent or received for it.
+
+const (
+	DefaultDeltaSnapshotEvery = 32
+	DefaultDeltaMinSize       = 256
+	DefaultDeltaMaxBaseBytes  = 64 << 20
+)
+
+var _ Transport = (*DeltaTransport)(nil)
+
+// DeltaOpts configures a DeltaTransport.
+type DeltaOpts struct {
+	Attrs         []tag.ID // attr specs that opt into delta encoding
+	SnapshotEvery int      // number of patches sent before a full snapshot is sent (default DefaultDeltaSnapshotEvery)
+	MinSize       int      // values smaller than this are always sent in full (default DefaultDeltaMinSize)
+	MaxBaseBytes  int      // max bytes of bases retained for sending; once reached, new attrs are sent in full (default DefaultDeltaMaxBaseBytes)
+}
+
+// DeltaStats reports the activity of a DeltaTransport.
+type DeltaStats struct {
+	Snapshots  int64 // snapshot ops sent
+	Patches    int64 // patch ops sent
+	FullBytes  int64 // size of the values that were sent as patches
+	PatchBytes int64 // size of the patches sent
+}
+
+// BytesSaved returns how many bytes patches have saved from being sent.
+func (stats DeltaStats) BytesSaved() int64 {
+	return stats.FullBytes - stats.PatchBytes
+}
+
+// DeltaTransport wraps a Transport, sending upserts of opted-in attrs as patches and applying patches as they are received.
+// Both ends of a link must be wrapped.
+type DeltaTransport struct {
+	raw   Transport
+	opts  DeltaOpts
+	attrs map[tag.ID]struct{}
+
+	sendMu    sync.Mutex
+	sent      deltaBases
+	sentBytes int
+	stats     DeltaStats
+
+	recvMu sync.Mutex
+	recvd  deltaBases
+}
+
+// deltaBases are the most recent values of attr elements, by request ID.
+type deltaBases map[tag.ID]map[attrElemKey]*deltaBase
+
+type attrElemKey struct {
+	TargetID tag.ID
+	AttrID   tag.ID
+	SI       tag.ID
+}
+
+type deltaBase struct {
+	value   []byte
+	hash    uint64
+	patches int // patches sent since the last snapshot
+}
+
+// NewDeltaTransport wraps raw with delta encoding of the given attrs.
+func NewDeltaTransport(raw Transport, opts DeltaOpts) *DeltaTransport {
+	if opts.SnapshotEvery <= 0 {
+		opts.SnapshotEvery = DefaultDeltaSnapshotEvery
+	}
+	if opts.MinSize <= 0 {
+		opts.MinSize = DefaultDeltaMinSize
+	}
+	if opts.MaxBaseBytes <= 0 {
+		opts.MaxBaseBytes = DefaultDeltaMaxBaseBytes
+	}
+	tr := &DeltaTransport{
+		raw:   raw,
+		opts:  opts,
+		attrs: make(map[tag.ID]struct{}, len(opts.Attrs)),
+		sent:  make(deltaBases),
+		recvd: make(deltaBases),
+	}
+	for _, attrID := range opts.Attrs {
+		tr.attrs[attrID] = struct{}{}
+	}
+	return tr
+}
+
+// Stats returns a snapshot of this transport's activity.
+func (tr *DeltaTransport) Stats() DeltaStats {
+	tr.sendMu.Lock()
+	defer tr.sendMu.Unlock()
+	return tr.stats
+}
+
+func (tr *DeltaTransport) Label() string {
+	return tr.raw.Label()
+}
+
+func (tr *DeltaTransport) Close() error {
+	return tr.raw.Close()
+}
+
+func (tr *DeltaTransport) SendTx(tx *TxMsg) error {
+	reqID := tx.RequestID()
+
+	tr.sendMu.Lock()
+	out := tr.encode(tx, reqID)
+	if tx.Status == OpStatus_Closed {
+		for _, base := range tr.sent[reqID] {
+			tr.sentBytes -= len(base.value)
+		}
+		delete(tr.sent, reqID)
+	}
+	tr.sendMu.Unlock()
+
+	if out != tx {
+		tx.ReleaseRef()
+	}
+	return tr.raw.SendTx(out)
+}
+
+// encode returns tx if it has no opted-in upserts, otherwise a new tx with upserts replaced by snapshots and patches.
+func (tr *DeltaTransport) encode(tx *TxMsg, reqID tag.ID) *TxMsg {
+	if reqID.IsNil() || tx.Status == OpStatus_Closed || !tr.hasDeltaOps(tx) {
+		return tx
+	}
+
+	out := NewTxMsg(false)
+	out.TxInfo = tx.TxInfo
+	out.Seq = tx.Seq
+
+	bases := tr.sent[reqID]
+	for _, op := range tx.Ops {
+		val := tx.DataStore[op.DataOfs : op.DataOfs+op.DataLen]
+		if op.OpCode != TxOpCode_UpsertAttr || !tr.optedIn(op.AttrID) {
+			out.MarshalOpWithBuf(&op, val)
+			continue
+		}
+
+		key := attrElemKey{op.TargetID, op.AttrID, op.SI}
+		base := bases[key]
+		if len(val) < tr.opts.MinSize {
+			if base != nil {
+				tr.sentBytes -= len(base.value)
+				delete(bases, key)
+			}
+			out.MarshalOpWithBuf(&op, val)
+			continue
+		}
+
+		if base != nil && base.patches < tr.opts.SnapshotEvery {
+			if patch := makePatch(base.hash, base.value, val); len(patch) < len(val) {
+				op.OpCode = TxOpCode_PatchAttr
+				out.MarshalOpWithBuf(&op, patch)
+
+				tr.stats.Patches++
+				tr.stats.FullBytes += int64(len(val))
+				tr.stats.PatchBytes += int64(len(patch))
+				tr.sentBytes += len(val) - len(base.value)
+				base.value = append(base.value[:0], val...)
+				base.hash = deltaHash(val)
+				base.patches++
+				continue
+			}
+		}
+
+		if base == nil {
+			if tr.sentBytes+len(val) > tr.opts.MaxBaseBytes {
+				out.MarshalOpWithBuf(&op, val)
+				continue
+			}
+			if bases == nil {
+				bases = make(map[attrElemKey]*deltaBase)
+				tr.sent[reqID] = bases
+			}
+			base = &deltaBase{}
+			bases[key] = base
+		}
+		op.OpCode = TxOpCode_SnapshotAttr
+		out.MarshalOpWithBuf(&op, val)
+
+		tr.stats.Snapshots++
+		tr.sentBytes += len(val) - len(base.value)
+		base.value = append(base.value[:0], val...)
+		base.hash = deltaHash(val)
+		base.patches = 0
+	}
+	return out
+}
+
+func (tr *DeltaTransport) optedIn(attrID tag.ID) bool {
+	_, ok := tr.attrs[attrID]
+	return ok
+}
+
+func (tr *DeltaTransport) hasDeltaOps(tx *TxMsg) bool {
+	for _, op := range tx.Ops {
+		if op.OpCode == TxOpCode_UpsertAttr && tr.optedIn(op.AttrID) {
+			return true
+		}
+	}
+	return false
+}
+
+// RecvTx returns the next tx received, with snapshots and patches converted to upserts.
+func (tr *DeltaTransport) RecvTx() (*TxMsg, error) {
+	tx, err := tr.raw.RecvTx()
+	if err != nil {
+		return nil, err
+	}
+	reqID := tx.RequestID()
+
+	tr.recvMu.Lock()
+	defer tr.recvMu.Unlock()
+
+	out, err := tr.decode(tx, reqID)
+	if tx.Status == OpStatus_Closed {
+		delete(tr.recvd, reqID)
+	}
+	if out != tx {
+		tx.ReleaseRef()
+	}
+	return out, err
+}
+
+func (tr *DeltaTransport) decode(tx *TxMsg, reqID tag.ID) (*TxMsg, error) {
+	hasDelta := false
+	for _, op := range tx.Ops {
+		if op.OpCode == TxOpCode_SnapshotAttr || op.OpCode == TxOpCode_PatchAttr {
+			hasDelta = true
+			break
+		}
+	}
+	if !hasDelta {
+		return tx, nil
+	}
+
+	out := NewTxMsg(false)
+	out.TxInfo = tx.TxInfo
+	out.Seq = tx.Seq
+
+	bases := tr.recvd[reqID]
+	for _, op := range tx.Ops {
+		if n := uint64(len(tx.DataStore)); op.DataOfs > n || op.DataLen > n-op.DataOfs {
+			out.ReleaseRef()
+			return nil, ErrMalformedTx
+		}
+		val := tx.DataStore[op.DataOfs : op.DataOfs+op.DataLen]
+
+		switch op.OpCode {
+		case TxOpCode_SnapshotAttr:
+			if bases == nil {
+				bases = make(map[attrElemKey]*deltaBase)
+				tr.recvd[reqID] = bases
+			}
+			key := attrElemKey{op.TargetID, op.AttrID, op.SI}
+			base := bases[key]
+			if base == nil {
+				base = &deltaBase{}
+				bases[key] = base
+			}
+			base.value = append(base.value[:0], val...)
+			base.hash = deltaHash(val)
+
+		case TxOpCode_PatchAttr:
+			base := bases[attrElemKey{op.TargetID, op.AttrID, op.SI}]
+			if base == nil {
+				out.ReleaseRef()
+				return nil, ErrCode_MalformedTx.Errorf("patch for attr %v has no base", op.AttrID)
+			}
+			next, err := applyPatch(base.hash, base.value, val)
+			if err != nil {
+				out.ReleaseRef()
+				return nil, err
+			}
+			base.value = next
+			base.hash = deltaHash(next)
+			val = next
+		}
+
+		if op.OpCode == TxOpCode_SnapshotAttr || op.OpCode == TxOpCode_PatchAttr {
+			op.OpCode = TxOpCode_UpsertAttr
+		}
+		out.MarshalOpWithBuf(&op, val)
+	}
+	return out, nil
+}
+
+func deltaHash(val []byte) uint64 {
+	h := fnv.New64a()
+	h.Write(val)
+	return h.Sum64()
+}
+
+// Patches
+//
+// A patch is the 8 byte hash of its base and the uvarint length of the value it produces, followed by instructions, where each instruction starts with a uvarint
+// of (n << 1 | isCopy).  A copy instruction is followed by the uvarint offset into the base of n bytes to copy, and an insert
+// instruction is followed by n literal bytes.
+//
+// makePatch indexes the base in deltaBlockSize blocks and slides a rolling hash over the value, so an edit anywhere in a large
+// value costs roughly the size of the edit.
+
+const (
+	deltaBlockSize = 32
+	deltaHashMul   = 0x01000193 // FNV-32 prime
+)
+
+func makePatch(baseHash uint64, base, val []byte) []byte {
+	patch := binary.LittleEndian.AppendUint64(nil, baseHash)
+	patch = binary.AppendUvarint(patch, uint64(len(val)))
+
+	// index each base block by its hash
+	index := make(map[uint32]int, len(base)/deltaBlockSize)
+	for ofs := 0; ofs+deltaBlockSize <= len(base); ofs += deltaBlockSize {
+		h := blockHash(base[ofs : ofs+deltaBlockSize])
+		if _, exists := index[h]; !exists {
+			index[h] = ofs
+		}
+	}
+
+	// deltaHashMul^(deltaBlockSize-1), used to remove the leading byte from the rolling hash
+	var pow uint32 = 1
+	for i := 1; i < deltaBlockSize; i++ {
+		pow *= deltaHashMul
+	}
+
+	literal := 0 // start of pending literal bytes
+	i := 0
+	var h uint32
+	if len(val) >= deltaBlockSize {
+		h = blockHash(val[:deltaBlockSize])
+	}
+	for i+deltaBlockSize <= len(val) {
+		baseOfs, found := index[h]
+		if found && bytes.Equal(base[baseOfs:baseOfs+deltaBlockSize], val[i:i+deltaBlockSize]) {
+			// extend the match backward into the pending literal and forward as far as it goes
+			start, bstart := i, baseOfs
+			for start > literal && bstart > 0 && val[start-1] == base[bstart-1] {
+				start--
+				bstart--
+			}
+			end, bend := i+deltaBlockSize, baseOfs+deltaBlockSize
+			for end < len(val) && bend < len(base) && val[end] == base[bend] {
+				end++
+				bend++
+			}
+			patch = appendInsert(patch, val[literal:start])
+			patch = binary.AppendUvarint(patch, uint64(end-start)<<1|1)
+			patch = binary.AppendUvarint(patch, uint64(bstart))
+
+			i, literal = end, end
+			if i+deltaBlockSize <= len(val) {
+				h = blockHash(val[i : i+deltaBlockSize])
+			}
+			continue
+		}
+
+		if i+deltaBlockSize < len(val) {
+			h = (h-uint32(val[i])*pow)*deltaHashMul + uint32(val[i+deltaBlockSize])
+		}
+		i++
+	}
+	return appendInsert(patch, val[literal:])
+}
+
+func appendInsert(patch, literal []byte) []byte {
+	if len(literal) == 0 {
+		return patch
+	}
+	patch = binary.AppendUvarint(patch, uint64(len(literal))<<1)
+	return append(patch, literal...)
+}
+
+func blockHash(block []byte) uint32 {
+	var h uint32
+	for _, b := range block {
+		h = h*deltaHashMul + uint32(b)
+	}
+	return h
+}
+
+func applyPatch(baseHash uint64, base, patch []byte) ([]byte, error) {
+	if len(patch) < 8 {
+		return nil, ErrCode_MalformedTx.Error("malformed patch")
+	}
+	if binary.LittleEndian.Uint64(patch) != baseHash {
+		return nil, ErrCode_MalformedTx.Error("patch base has diverged")
+	}
+	size, n := binary.Uvarint(patch[8:])
+	if n <= 0 {
+		return nil, ErrCode_MalformedTx.Error("malformed patch")
+	}
+	patch = patch[8+n:]
+
+	out := make([]byte, 0, min(size, uint64(len(base)+len(patch))))
+	for len(patch) > 0 {
+		hdr, n := binary.Uvarint(patch)
+		if n <= 0 {
+			return nil, ErrCode_MalformedTx.Error("malformed patch")
+		}
+		patch = patch[n:]
+		length := hdr >> 1
+
+		if hdr&1 != 0 {
+			ofs, n := binary.Uvarint(patch)
+			if n <= 0 || ofs+length > uint64(len(base)) {
+				return nil, ErrCode_MalformedTx.Error("malformed patch")
+			}
+			patch = patch[n:]
+			out = append(out, base[ofs:ofs+length]...)
+		} else {
+			if length > uint64(len(patch)) {
+				return nil, ErrCode_MalformedTx.Error("malformed patch")
+			}
+			out = append(out, patch[:length]...)
+			patch = patch[length:]
+		}
+		if uint64(len(out)) > size {
+			return nil, ErrCode_MalformedTx.Error("malformed patch")
+		}
+	}
+	if uint64(len(out)) != size {
+		return nil, ErrCode_MalformedTx.Error("malformed patch")
+	}
+	return out, nil
+}
@@ -0,0 +1,352 @@
+package amp
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+	"sync"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/tag"
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/hkdf"
+)
+
+// End-to-end encryption
+//
+// An E2ETransport seals each tx so that relays between a client and host (reverse proxies, federation hops) can route txs without
+// reading cell content.  A sealed tx is an envelope having the same TxInfo and Seq as the tx it carries and a single SealedTxAttrSpec
+// meta attr op, where op.Height is the message counter and the op value is the ChaCha20-Poly1305 encryption of the marshalled tx.
+// The envelope's request ID, status, and Seq are authenticated, so a relay can read but not alter them.
+//
+// Session keys are established via X25519 during the handshake: the client sends an E2EAttrSpec meta attr holding an ephemeral public
+// key as its first tx and the host replies with its own ephemeral key and (optionally) its static key.  If the host has a static key,
+// it is mixed into the session keys, so a client that knows the host's static key (E2EOpts.HostKey) is protected from an active relay.
+// Without it, only passive relays are excluded.
+
+var (
+	E2EAttrSpec      = tag.FormSpec(MetaAttrSpec, "e2e.Tag")
+	SealedTxAttrSpec = tag.FormSpec(MetaAttrSpec, "e2e.tx")
+)
+
+var _ Transport = (*E2ETransport)(nil)
+
+// number of message counters tracked to detect replays
+const e2eReplayWindowSz = 1024
+
+// E2EOpts configures an E2ETransport.
+type E2EOpts struct {
+	StaticKey *ecdh.PrivateKey // host: long-lived X25519 key identifying the host (optional)
+	HostKey   *ecdh.PublicKey  // client: if set, the host must prove it holds the corresponding StaticKey
+	Required  bool             // host: if set, clients that do not offer a key are refused
+}
+
+// E2ETransport wraps a Transport, sealing txs sent and opening txs received.
+type E2ETransport struct {
+	raw     Transport
+	pending *TxMsg // first tx received by AcceptE2E() that is not an offer
+
+	sendMu  sync.Mutex
+	seal    cipher.AEAD // nil if not established
+	counter uint64
+	scrap   []byte
+
+	recvMu sync.Mutex
+	open   cipher.AEAD
+	replay replayWindow
+}
+
+// AcceptE2E reads the first tx from a newly connected Transport and, if it is a key offer, completes the handshake.
+// If no key was offered, the returned transport passes txs through unsealed, unless opts.Required is set.
+// The returned transport wraps raw and is to be passed to Host.StartNewSession().
+func AcceptE2E(raw Transport, opts E2EOpts) (*E2ETransport, error) {
+	first, err := raw.RecvTx()
+	if err != nil {
+		return nil, err
+	}
+	offer, isOffer := parseE2ETx(first)
+	if !isOffer {
+		if opts.Required {
+			first.ReleaseRef()
+			return nil, ErrCode_AuthFailed.Error("end-to-end encryption is required")
+		}
+		return &E2ETransport{raw: raw, pending: first}, nil
+	}
+	first.ReleaseRef()
+
+	clientPub, err := ecdh.X25519().NewPublicKey(offer)
+	if err != nil {
+		return nil, ErrCode_AuthFailed.Errorf("bad e2e key offer: %v", err)
+	}
+	ephemeral, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	reply := ephemeral.PublicKey().Bytes()
+	secret, err := ephemeral.ECDH(clientPub)
+	if err != nil {
+		return nil, ErrCode_AuthFailed.Errorf("e2e key exchange failed: %v", err)
+	}
+	if opts.StaticKey != nil {
+		reply = append(reply, opts.StaticKey.PublicKey().Bytes()...)
+		static, err := opts.StaticKey.ECDH(clientPub)
+		if err != nil {
+			return nil, ErrCode_AuthFailed.Errorf("e2e key exchange failed: %v", err)
+		}
+		secret = append(secret, static...)
+	}
+
+	tr := &E2ETransport{raw: raw}
+	if err = tr.deriveKeys(secret, offer, reply, false); err != nil {
+		return nil, err
+	}
+	if err = raw.SendTx(marshalE2ETx(reply)); err != nil {
+		return nil, err
+	}
+	return tr, nil
+}
+
+// OfferE2E performs the client side of the handshake, returning once session keys are established.
+func OfferE2E(raw Transport, opts E2EOpts) (*E2ETransport, error) {
+	ephemeral, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	offer := ephemeral.PublicKey().Bytes()
+	if err = raw.SendTx(marshalE2ETx(offer)); err != nil {
+		return nil, err
+	}
+
+	tx, err := raw.RecvTx()
+	if err != nil {
+		return nil, err
+	}
+	reply, ok := parseE2ETx(tx)
+	tx.ReleaseRef()
+	if !ok || (len(reply) != 32 && len(reply) != 64) {
+		return nil, ErrCode_AuthFailed.Error("host did not complete e2e handshake")
+	}
+
+	hostPub, err := ecdh.X25519().NewPublicKey(reply[:32])
+	if err != nil {
+		return nil, ErrCode_AuthFailed.Errorf("bad e2e host key: %v", err)
+	}
+	secret, err := ephemeral.ECDH(hostPub)
+	if err != nil {
+		return nil, ErrCode_AuthFailed.Errorf("e2e key exchange failed: %v", err)
+	}
+	switch {
+	case len(reply) == 64:
+		staticPub, err := ecdh.X25519().NewPublicKey(reply[32:])
+		if err != nil {
+			return nil, ErrCode_AuthFailed.Errorf("bad e2e host key: %v", err)
+		}
+		if opts.HostKey != nil && !opts.HostKey.Equal(staticPub) {
+			return nil, ErrCode_AuthFailed.Error("e2e host key mismatch")
+		}
+		static, err := ephemeral.ECDH(staticPub)
+		if err != nil {
+			return nil, ErrCode_AuthFailed.Errorf("e2e key exchange failed: %v", err)
+		}
+		secret = append(secret, static...)
+	case opts.HostKey != nil:
+		return nil, ErrCode_AuthFailed.Error("host did not present a static e2e key")
+	}
+
+	tr := &E2ETransport{raw: raw}
+	if err = tr.deriveKeys(secret, offer, reply, true); err != nil {
+		return nil, err
+	}
+	return tr, nil
+}
+
+// deriveKeys derives a key for each direction from the shared secret and handshake transcript.
+func (tr *E2ETransport) deriveKeys(secret, offer, reply []byte, isClient bool) error {
+	transcript := sha256.Sum256(append(append([]byte(nil), offer...), reply...))
+
+	newAEAD := func(info string) (cipher.AEAD, error) {
+		key := make([]byte, chacha20poly1305.KeySize)
+		if _, err := io.ReadFull(hkdf.New(sha256.New, secret, transcript[:], []byte(info)), key); err != nil {
+			return nil, err
+		}
+		return chacha20poly1305.New(key)
+	}
+	c2h, err := newAEAD("amp.e2e client->host")
+	if err != nil {
+		return err
+	}
+	h2c, err := newAEAD("amp.e2e host->client")
+	if err != nil {
+		return err
+	}
+	if isClient {
+		tr.seal, tr.open = c2h, h2c
+	} else {
+		tr.seal, tr.open = h2c, c2h
+	}
+	return nil
+}
+
+// Encrypted returns true if session keys were established.
+func (tr *E2ETransport) Encrypted() bool {
+	return tr.seal != nil
+}
+
+func (tr *E2ETransport) Label() string {
+	return tr.raw.Label()
+}
+
+func (tr *E2ETransport) Close() error {
+	return tr.raw.Close()
+}
+
+func (tr *E2ETransport) SendTx(tx *TxMsg) error {
+	if tr.seal == nil {
+		return tr.raw.SendTx(tx)
+	}
+
+	tr.sendMu.Lock()
+	tr.counter++
+	counter := tr.counter
+
+	env := NewTxMsg(false)
+	env.TxInfo = tx.TxInfo
+	env.Seq = tx.Seq
+
+	tx.MarshalToBuffer(&tr.scrap)
+	sealed := tr.seal.Seal(nil, e2eNonce(counter), tr.scrap, e2eAAD(env))
+	tr.sendMu.Unlock()
+
+	env.MarshalOpWithBuf(&TxOp{
+		OpCode: TxOpCode_MetaAttr,
+		AttrID: SealedTxAttrSpec.ID,
+		Height: counter,
+	}, sealed)
+	tx.ReleaseRef()
+	return tr.raw.SendTx(env)
+}
+
+// RecvTx returns the next tx received, opening it if sealed.  Once keys are established, unsealed or tampered txs are refused.
+func (tr *E2ETransport) RecvTx() (*TxMsg, error) {
+	if tx := tr.pending; tx != nil {
+		tr.pending = nil
+		return tx, nil
+	}
+
+	env, err := tr.raw.RecvTx()
+	if err != nil || tr.open == nil {
+		return env, err
+	}
+	defer env.ReleaseRef()
+
+	if len(env.Ops) != 1 || env.Ops[0].OpCode != TxOpCode_MetaAttr || env.Ops[0].AttrID != SealedTxAttrSpec.ID {
+		return nil, ErrCode_AuthFailed.Error("received unsealed tx")
+	}
+	op := env.Ops[0]
+	if n := uint64(len(env.DataStore)); op.DataOfs > n || op.DataLen > n-op.DataOfs {
+		return nil, ErrMalformedTx
+	}
+
+	tr.recvMu.Lock()
+	defer tr.recvMu.Unlock()
+
+	if !tr.replay.check(op.Height) {
+		return nil, ErrCode_AuthFailed.Error("replayed tx")
+	}
+	plain, err := tr.open.Open(nil, e2eNonce(op.Height), env.DataStore[op.DataOfs:op.DataOfs+op.DataLen], e2eAAD(env))
+	if err != nil {
+		return nil, ErrCode_AuthFailed.Error("failed to open sealed tx")
+	}
+	tr.replay.mark(op.Height)
+
+	tx, err := ReadTxMsg(bytes.NewReader(plain))
+	if err != nil {
+		return nil, err
+	}
+	if tx.RequestID() != env.RequestID() || tx.Status != env.Status || tx.Seq != env.Seq {
+		tx.ReleaseRef()
+		return nil, ErrCode_AuthFailed.Error("sealed tx does not match its envelope")
+	}
+	return tx, nil
+}
+
+func e2eNonce(counter uint64) []byte {
+	nonce := make([]byte, chacha20poly1305.NonceSize)
+	binary.LittleEndian.PutUint64(nonce[4:], counter)
+	return nonce
+}
+
+// e2eAAD returns the envelope fields that relays may read but not alter.
+func e2eAAD(env *TxMsg) []byte {
+	reqID := env.RequestID()
+	aad := make([]byte, 0, 36)
+	aad = binary.LittleEndian.AppendUint64(aad, reqID[0])
+	aad = binary.LittleEndian.AppendUint64(aad, reqID[1])
+	aad = binary.LittleEndian.AppendUint64(aad, reqID[2])
+	aad = binary.LittleEndian.AppendUint32(aad, uint32(env.Status))
+	aad = binary.LittleEndian.AppendUint32(aad, env.Seq)
+	return aad
+}
+
+func marshalE2ETx(keys []byte) *TxMsg {
+	tx := NewTxMsg(true)
+	buf, _ := (&Tag{Attachment: keys}).MarshalToStore(nil)
+	tx.MarshalOpWithBuf(&TxOp{
+		OpCode: TxOpCode_MetaAttr,
+		AttrID: E2EAttrSpec.ID,
+	}, buf)
+	return tx
+}
+
+// parseE2ETx returns the keys held by the given key offer or reply.
+func parseE2ETx(tx *TxMsg) (keys []byte, ok bool) {
+	if len(tx.Ops) == 0 || tx.Ops[0].OpCode != TxOpCode_MetaAttr || tx.Ops[0].AttrID != E2EAttrSpec.ID {
+		return nil, false
+	}
+	val := &Tag{}
+	if err := tx.UnmarshalOpValue(0, val); err != nil {
+		return nil, false
+	}
+	return val.Attachment, true
+}
+
+// replayWindow tracks which recent message counters have been received, allowing for reordering (e.g. across QUIC streams).
+type replayWindow struct {
+	max  uint64
+	seen [16]uint64 // bit i%1024 is set if counter i (within the window) was received
+}
+
+func (w *replayWindow) check(counter uint64) bool {
+	switch {
+	case counter == 0:
+		return false
+	case counter > w.max:
+		return true
+	case w.max-counter >= uint64(e2eReplayWindowSz):
+		return false
+	default:
+		i := counter % uint64(e2eReplayWindowSz)
+		return w.seen[i/64]&(1<<(i%64)) == 0
+	}
+}
+
+func (w *replayWindow) mark(counter uint64) {
+	if counter > w.max {
+		// clear the bits of counters that have slid into the window
+		if counter-w.max >= uint64(e2eReplayWindowSz) {
+			w.seen = [16]uint64{}
+		} else {
+			for c := w.max + 1; c < counter; c++ {
+				i := c % uint64(e2eReplayWindowSz)
+				w.seen[i/64] &^= 1 << (i % 64)
+			}
+		}
+		w.max = counter
+	}
+	i := counter % uint64(e2eReplayWindowSz)
+	w.seen[i/64] |= 1 << (i % 64)
+}
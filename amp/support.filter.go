@@ -0,0 +1,541 @@
+package amp
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/geo"
+)
+
+// FieldSource is implemented by a value having named fields that a Filter can evaluate.
+//
+// A field value is a string, bool, integer, or float -- or a []string, which satisfies a comparison if any of its elements do.
+// A location is a geo.Point or *GeoPoint.
+type FieldSource interface {
+	FieldValue(name string) (val any, exists bool)
+}
+
+// Filter is a parsed filter expression (see PinRequest.PinFilter) of the form:
+//
+//	[cond] [sort field [asc|desc] {, field [asc|desc]}] [limit n]
+//
+// where cond is comparisons of the form "field op value" combined with &&, ||, !, and parens (or "and", "or", and "not").
+// An op is one of == != < <= > >= or ~ (case-insensitive substring), and a value is a quoted string, a number, true, or false.
+// A location field is compared with a region of the form "field within box(south, west, north, east)" or
+// "field within radius(lat, lon, meters)", in degrees, where a box whose west is greater than its east spans the antimeridian.
+//
+// A comparison involving a missing field or values of differing types is false, and a missing field sorts after all others.
+// For example:
+//
+//	artist == "X" && tags == "live" sort year desc limit 50
+//	location within radius(40.7484, -73.9857, 500) && kind == "photo"
+type Filter struct {
+	Sort  []SortKey
+	Limit int // if 0, there is no limit
+
+	cond filterNode // nil means match all
+}
+
+// SortKey is a field to sort by.
+type SortKey struct {
+	Field string
+	Desc  bool
+}
+
+// ParseFilter parses the given filter expression, returning nil if expr is empty.
+func ParseFilter(expr string) (*Filter, error) {
+	p := filterParser{}
+	if err := p.lex(expr); err != nil {
+		return nil, err
+	}
+	if len(p.toks) == 0 {
+		return nil, nil
+	}
+
+	filter := &Filter{}
+	var err error
+	if !p.isKeyword("sort") && !p.isKeyword("limit") {
+		if filter.cond, err = p.parseOr(); err != nil {
+			return nil, err
+		}
+	}
+	if p.isKeyword("sort") {
+		p.next()
+		for {
+			tok := p.next()
+			if tok.kind != tokIdent {
+				return nil, p.errorf(tok, "expected sort field")
+			}
+			key := SortKey{Field: tok.text}
+			if p.isKeyword("asc") {
+				p.next()
+			} else if p.isKeyword("desc") {
+				p.next()
+				key.Desc = true
+			}
+			filter.Sort = append(filter.Sort, key)
+			if p.peek().text != "," {
+				break
+			}
+			p.next()
+		}
+	}
+	if p.isKeyword("limit") {
+		p.next()
+		tok := p.next()
+		limit, err := strconv.Atoi(tok.text)
+		if tok.kind != tokNumber || err != nil || limit <= 0 {
+			return nil, p.errorf(tok, "expected a positive limit")
+		}
+		filter.Limit = limit
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, p.errorf(tok, "unexpected %q", tok.text)
+	}
+	return filter, nil
+}
+
+// Match returns true if the given source satisfies this filter's condition.
+func (filter *Filter) Match(src FieldSource) bool {
+	return filter == nil || filter.cond == nil || filter.cond.match(src)
+}
+
+// Within returns the region the given location field must be within for this filter's condition to be satisfied, letting
+// an app having a spatial index (see geo.RTree) enumerate only the cells within it.
+func (filter *Filter) Within(field string) (geo.Region, bool) {
+	if filter == nil {
+		return nil, false
+	}
+	var within func(node filterNode) (geo.Region, bool)
+	within = func(node filterNode) (geo.Region, bool) {
+		switch n := node.(type) {
+		case *filterWithin:
+			return n.region, n.field == field
+		case filterAnd:
+			if region, ok := within(n[0]); ok {
+				return region, true
+			}
+			return within(n[1])
+		}
+		return nil, false
+	}
+	return within(filter.cond)
+}
+
+// Less returns true if a sorts before b according to this filter's sort keys.
+func (filter *Filter) Less(a, b FieldSource) bool {
+	if filter == nil {
+		return false
+	}
+	for _, key := range filter.Sort {
+		va, hasA := a.FieldValue(key.Field)
+		vb, hasB := b.FieldValue(key.Field)
+		if !hasA || !hasB {
+			if hasA != hasB {
+				return hasA
+			}
+			continue
+		}
+		if c, ok := compareValues(va, vb); ok && c != 0 {
+			return (c < 0) != key.Desc
+		}
+	}
+	return false
+}
+
+// ApplyFilter returns the items that match the given filter, sorted and limited as it specifies (without modifying items).
+func ApplyFilter[T FieldSource](filter *Filter, items []T) []T {
+	if filter == nil {
+		return items
+	}
+	matched := make([]T, 0, len(items))
+	for _, item := range items {
+		if filter.Match(item) {
+			matched = append(matched, item)
+		}
+	}
+	if len(filter.Sort) > 0 {
+		sort.SliceStable(matched, func(i, j int) bool {
+			return filter.Less(matched[i], matched[j])
+		})
+	}
+	if filter.Limit > 0 && len(matched) > filter.Limit {
+		matched = matched[:filter.Limit]
+	}
+	return matched
+}
+
+type filterNode interface {
+	match(src FieldSource) bool
+}
+
+type filterAnd [2]filterNode
+type filterOr [2]filterNode
+type filterNot struct{ filterNode }
+
+type filterCmp struct {
+	field string
+	op    string
+	val   any
+}
+
+type filterWithin struct {
+	field  string
+	region geo.Region
+}
+
+func (n filterAnd) match(src FieldSource) bool { return n[0].match(src) && n[1].match(src) }
+func (n filterOr) match(src FieldSource) bool  { return n[0].match(src) || n[1].match(src) }
+func (n filterNot) match(src FieldSource) bool { return !n.filterNode.match(src) }
+
+func (n *filterCmp) match(src FieldSource) bool {
+	val, exists := src.FieldValue(n.field)
+	if !exists {
+		return false
+	}
+	if list, isList := val.([]string); isList {
+		for _, elem := range list {
+			if n.matchValue(elem) {
+				return true
+			}
+		}
+		return false
+	}
+	return n.matchValue(val)
+}
+
+func (n *filterWithin) match(src FieldSource) bool {
+	val, exists := src.FieldValue(n.field)
+	if !exists {
+		return false
+	}
+	p, isPoint := toPoint(val)
+	return isPoint && n.region.Contains(p)
+}
+
+func (n *filterCmp) matchValue(val any) bool {
+	if n.op == "~" {
+		str, isStr := val.(string)
+		sub, _ := n.val.(string)
+		return isStr && strings.Contains(strings.ToLower(str), strings.ToLower(sub))
+	}
+	c, ok := compareValues(val, n.val)
+	if !ok {
+		return false
+	}
+	switch n.op {
+	case "==":
+		return c == 0
+	case "!=":
+		return c != 0
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	case ">=":
+		return c >= 0
+	}
+	return false
+}
+
+// compareValues returns -1, 0, or 1 as a is less than, equal to, or greater than b -- or false if they are not comparable.
+func compareValues(a, b any) (int, bool) {
+	if fa, isNum := toFloat(a); isNum {
+		fb, isNum := toFloat(b)
+		if !isNum {
+			return 0, false
+		}
+		switch {
+		case fa < fb:
+			return -1, true
+		case fa > fb:
+			return 1, true
+		}
+		return 0, true
+	}
+	switch va := a.(type) {
+	case string:
+		vb, isStr := b.(string)
+		return strings.Compare(va, vb), isStr
+	case bool:
+		vb, isBool := b.(bool)
+		if !isBool {
+			return 0, false
+		}
+		switch {
+		case va == vb:
+			return 0, true
+		case vb:
+			return -1, true
+		}
+		return 1, true
+	}
+	return 0, false
+}
+
+func toFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+type filterTokKind int
+
+const (
+	tokEOF filterTokKind = iota
+	tokIdent
+	tokString
+	tokNumber
+	tokOp
+)
+
+type filterTok struct {
+	kind filterTokKind
+	text string // for a tokString, the unquoted string
+	pos  int
+}
+
+type filterParser struct {
+	toks []filterTok
+	pos  int
+	end  int
+}
+
+func (p *filterParser) lex(expr string) error {
+	p.end = len(expr)
+	for i := 0; i < len(expr); {
+		c := rune(expr[i])
+		start := i
+		switch {
+		case unicode.IsSpace(c):
+			i++
+			continue
+		case c == '"':
+			i++
+			for i < len(expr) && expr[i] != '"' {
+				if expr[i] == '\\' {
+					i++
+				}
+				i++
+			}
+			if i >= len(expr) {
+				return ErrCode_BadRequest.Errorf("filter: unterminated string at %d", start)
+			}
+			i++
+			str, err := strconv.Unquote(expr[start:i])
+			if err != nil {
+				return ErrCode_BadRequest.Errorf("filter: bad string at %d", start)
+			}
+			p.toks = append(p.toks, filterTok{tokString, str, start})
+			continue
+		case c == '-' || c == '.' || unicode.IsDigit(c):
+			i++
+			for i < len(expr) && (expr[i] == '.' || unicode.IsDigit(rune(expr[i]))) {
+				i++
+			}
+			p.toks = append(p.toks, filterTok{tokNumber, expr[start:i], start})
+			continue
+		case c == '_' || unicode.IsLetter(c):
+			for i < len(expr) && (expr[i] == '_' || expr[i] == '.' || unicode.IsLetter(rune(expr[i])) || unicode.IsDigit(rune(expr[i]))) {
+				i++
+			}
+			p.toks = append(p.toks, filterTok{tokIdent, expr[start:i], start})
+			continue
+		}
+
+		op := ""
+		for _, candidate := range []string{"==", "!=", "<=", ">=", "&&", "||", "<", ">", "~", "!", "(", ")", ","} {
+			if strings.HasPrefix(expr[i:], candidate) {
+				op = candidate
+				break
+			}
+		}
+		if op == "" {
+			return ErrCode_BadRequest.Errorf("filter: unexpected %q at %d", c, i)
+		}
+		p.toks = append(p.toks, filterTok{tokOp, op, start})
+		i += len(op)
+	}
+	return nil
+}
+
+func (p *filterParser) peek() filterTok {
+	if p.pos < len(p.toks) {
+		return p.toks[p.pos]
+	}
+	return filterTok{kind: tokEOF, pos: p.end}
+}
+
+func (p *filterParser) next() filterTok {
+	tok := p.peek()
+	if p.pos < len(p.toks) {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *filterParser) isKeyword(word string) bool {
+	tok := p.peek()
+	return tok.kind == tokIdent && strings.EqualFold(tok.text, word)
+}
+
+func (p *filterParser) isOp(op, word string) bool {
+	tok := p.peek()
+	return (tok.kind == tokOp && tok.text == op) || p.isKeyword(word)
+}
+
+func (p *filterParser) errorf(tok filterTok, format string, args ...any) error {
+	return ErrCode_BadRequest.Errorf("filter: "+format+" at %d", append(args, tok.pos)...)
+}
+
+func (p *filterParser) parseOr() (filterNode, error) {
+	lhs, err := p.parseAnd()
+	for err == nil && p.isOp("||", "or") {
+		p.next()
+		var rhs filterNode
+		if rhs, err = p.parseAnd(); err == nil {
+			lhs = filterOr{lhs, rhs}
+		}
+	}
+	return lhs, err
+}
+
+func (p *filterParser) parseAnd() (filterNode, error) {
+	lhs, err := p.parseUnary()
+	for err == nil && p.isOp("&&", "and") {
+		p.next()
+		var rhs filterNode
+		if rhs, err = p.parseUnary(); err == nil {
+			lhs = filterAnd{lhs, rhs}
+		}
+	}
+	return lhs, err
+}
+
+func (p *filterParser) parseUnary() (filterNode, error) {
+	if p.isOp("!", "not") {
+		p.next()
+		node, err := p.parseUnary()
+		return filterNot{node}, err
+	}
+	if tok := p.peek(); tok.kind == tokOp && tok.text == "(" {
+		p.next()
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if tok = p.next(); tok.kind != tokOp || tok.text != ")" {
+			return nil, p.errorf(tok, "expected )")
+		}
+		return node, nil
+	}
+
+	field := p.next()
+	if field.kind != tokIdent {
+		return nil, p.errorf(field, "expected field name")
+	}
+	if p.isKeyword("within") {
+		p.next()
+		region, err := p.parseRegion()
+		if err != nil {
+			return nil, err
+		}
+		return &filterWithin{
+			field:  field.text,
+			region: region,
+		}, nil
+	}
+
+	op := p.next()
+	switch op.text {
+	case "==", "!=", "<", "<=", ">", ">=", "~":
+	default:
+		return nil, p.errorf(op, "expected comparison after %q", field.text)
+	}
+
+	cmp := &filterCmp{
+		field: field.text,
+		op:    op.text,
+	}
+	tok := p.next()
+	switch {
+	case tok.kind == tokString:
+		cmp.val = tok.text
+	case tok.kind == tokNumber:
+		num, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, p.errorf(tok, "bad number %q", tok.text)
+		}
+		cmp.val = num
+	case tok.kind == tokIdent && (tok.text == "true" || tok.text == "false"):
+		cmp.val = tok.text == "true"
+	default:
+		return nil, p.errorf(tok, "expected value for %q", field.text)
+	}
+	return cmp, nil
+}
+
+func (p *filterParser) parseRegion() (geo.Region, error) {
+	shape := p.next()
+	kind := strings.ToLower(shape.text)
+	var numArgs int
+	switch {
+	case shape.kind == tokIdent && kind == "box":
+		numArgs = 4
+	case shape.kind == tokIdent && kind == "radius":
+		numArgs = 3
+	default:
+		return nil, p.errorf(shape, "expected box or radius")
+	}
+	if tok := p.next(); tok.kind != tokOp || tok.text != "(" {
+		return nil, p.errorf(tok, "expected (")
+	}
+	args := make([]float64, 0, numArgs)
+	for len(args) < numArgs {
+		if len(args) > 0 {
+			if tok := p.next(); tok.kind != tokOp || tok.text != "," {
+				return nil, p.errorf(tok, "expected , in %s", kind)
+			}
+		}
+		tok := p.next()
+		num, err := strconv.ParseFloat(tok.text, 64)
+		if tok.kind != tokNumber || err != nil {
+			return nil, p.errorf(tok, "expected number in %s", kind)
+		}
+		args = append(args, num)
+	}
+	if tok := p.next(); tok.kind != tokOp || tok.text != ")" {
+		return nil, p.errorf(tok, "expected )")
+	}
+
+	if kind == "box" {
+		box := geo.Rect{South: args[0], West: args[1], North: args[2], East: args[3]}
+		if !box.Valid() {
+			return nil, p.errorf(shape, "invalid box")
+		}
+		return box, nil
+	}
+	circle := geo.Circle{Center: geo.Point{Lat: args[0], Lon: args[1]}, Radius: args[2]}
+	if !circle.Center.Valid() || circle.Radius < 0 {
+		return nil, p.errorf(shape, "invalid radius")
+	}
+	return circle, nil
+}
@@ -0,0 +1,344 @@
+package amp
+
+import (
+	"sync"
+	"time"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/tag"
+)
+
+// Flow control
+//
+// A client that consumes txs slower than its host produces them opts into flow control for a pin by setting PinRequest.SendWindow:
+// the host then pushes at most SendWindow txs for the request beyond those the client has acknowledged, rather than queuing them
+// without bound.  The client acknowledges txs by sending a FlowAckAttrSpec meta attr for the request whose op.Height is the number
+// of txs it has consumed so far (see MarshalFlowAck), so a lost or reordered ack is made good by the next.
+//
+// A host creates one FlowController per HostSession, wraps each AppInstance it issues via Control (after any other wrapping, so
+// it sees txs as they are sent), and passes each tx it receives to HandleTx.  Once a pin's window is exhausted, the pin is stalled
+// and its app's emission is paused until an ack reopens the window:
+//   - if the pin implements FlowHandler, OnFlowPaused is called and pushes fail with ErrFlowPaused until OnFlowResumed is called;
+//   - otherwise PushTx blocks, failing with ErrFlowStalled if no ack arrives within FlowOpts.StallTimeout.
+
+var FlowAckAttrSpec = tag.FormSpec(MetaAttrSpec, "flow.ack")
+
+const DefaultFlowMaxWindow = 1024
+
+var (
+	ErrFlowPaused  = ErrCode_RateLimited.Error("send window exhausted")
+	ErrFlowStalled = ErrCode_Timeout.Error("send window stalled")
+)
+
+// FlowOpts configures a FlowController.
+type FlowOpts struct {
+	MaxWindow    int64         // caps the SendWindow a request may set (default DefaultFlowMaxWindow)
+	StallTimeout time.Duration // how long a blocked push waits for an ack before failing with ErrFlowStalled (0 = until the pin closes)
+	Metrics      *HostMetrics  // if set, stalled pins are reported via amp_pins_stalled and amp_pin_stall* (see HostMetrics)
+}
+
+// FlowStats reports the activity of a FlowController.
+type FlowStats struct {
+	Pins      int           // flow controlled pins open
+	Stalled   int           // pins whose send window is currently exhausted
+	Stalls    int64         // times a pin's send window was exhausted
+	StallTime time.Duration // total time pins were stalled (excluding current stalls)
+	Acks      int64         // acks that reopened part of a window
+	Refused   int64         // pushes failed with ErrFlowPaused or ErrFlowStalled
+}
+
+// FlowHandler is optionally implemented by a Pin to pause its emission itself rather than having its pushes block once its
+// send window is exhausted (see FlowController).  Calls are made in order from any goroutine and must return quickly.
+type FlowHandler interface {
+
+	// Called when the pin's send window is exhausted, after which pushes fail with ErrFlowPaused.
+	OnFlowPaused()
+
+	// Called when an ack reopens the pin's send window.
+	OnFlowResumed()
+}
+
+// FlowController enforces the send windows of a session's flow controlled pins -- concurrency safe.
+type FlowController struct {
+	opts  FlowOpts
+	mu    sync.Mutex
+	pins  map[tag.ID]*flowWindow // by Request.ID
+	stats FlowStats
+}
+
+// NewFlowController returns a FlowController applying the given options.
+func NewFlowController(opts FlowOpts) *FlowController {
+	if opts.MaxWindow <= 0 {
+		opts.MaxWindow = DefaultFlowMaxWindow
+	}
+	return &FlowController{
+		opts: opts,
+		pins: make(map[tag.ID]*flowWindow),
+	}
+}
+
+// Stats returns a snapshot of this controller's activity.
+func (fc *FlowController) Stats() FlowStats {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+
+	stats := fc.stats
+	stats.Pins = len(fc.pins)
+	return stats
+}
+
+// Control wraps the given AppInstance so that the pins it serves honor the send window of their requests.
+func (fc *FlowController) Control(inst AppInstance) AppInstance {
+	return &flowApp{
+		AppInstance: inst,
+		fc:          fc,
+	}
+}
+
+// HandleTx applies the given received tx if it is a FlowAckAttrSpec ack, returning true if so (and releasing it).
+func (fc *FlowController) HandleTx(tx *TxMsg) bool {
+	if len(tx.Ops) == 0 || tx.Ops[0].OpCode != TxOpCode_MetaAttr || tx.Ops[0].AttrID != FlowAckAttrSpec.ID {
+		return false
+	}
+	fc.Ack(tx.RequestID(), tx.Ops[0].Height)
+	tx.ReleaseRef()
+	return true
+}
+
+// Ack records that the client has consumed the given number of txs pushed for the given request, reopening its window.
+func (fc *FlowController) Ack(reqID tag.ID, consumed uint64) {
+	fc.mu.Lock()
+	win := fc.pins[reqID]
+	if win == nil || consumed <= win.acked {
+		fc.mu.Unlock()
+		return
+	}
+	win.acked = min(consumed, win.sent)
+	fc.stats.Acks++
+	if !win.stalledAt.IsZero() && !win.exhausted() {
+		fc.unstallLocked(win)
+		close(win.resumed)
+		win.resumed = make(chan struct{})
+		win.notifyLocked(false)
+	}
+	fc.mu.Unlock()
+	win.notify()
+}
+
+// MarshalFlowAck returns the tx a client sends to acknowledge that it has consumed the given number of txs pushed for a request.
+func MarshalFlowAck(reqID tag.ID, consumed uint64) *TxMsg {
+	tx := NewTxMsg(true)
+	tx.SetRequestID(reqID)
+	tx.MarshalOpWithBuf(&TxOp{
+		OpCode: TxOpCode_MetaAttr,
+		AttrID: FlowAckAttrSpec.ID,
+		Height: consumed,
+	}, nil)
+	return tx
+}
+
+func (fc *FlowController) stallLocked(win *flowWindow) {
+	win.stalledAt = time.Now()
+	fc.stats.Stalled++
+	fc.stats.Stalls++
+	if m := fc.opts.Metrics; m != nil {
+		m.pinStalled()
+	}
+}
+
+func (fc *FlowController) unstallLocked(win *flowWindow) {
+	stalled := time.Since(win.stalledAt)
+	win.stalledAt = time.Time{}
+	fc.stats.Stalled--
+	fc.stats.StallTime += stalled
+	if m := fc.opts.Metrics; m != nil {
+		m.pinResumed(stalled)
+	}
+}
+
+func (fc *FlowController) serve(pinner Pinner, req Requester, closing <-chan struct{}) (Pin, error) {
+	size := req.Request().SendWindow
+	if size <= 0 {
+		pin, err := pinner.ServeRequest(req)
+		if err != nil || pin == nil {
+			return pin, err
+		}
+		return &flowPin{Pin: pin, fc: fc}, nil
+	}
+
+	win := &flowWindow{
+		fc:      fc,
+		reqID:   req.Request().ID,
+		size:    uint64(min(size, fc.opts.MaxWindow)),
+		resumed: make(chan struct{}),
+		closing: closing,
+		done:    make(chan struct{}),
+	}
+	fc.mu.Lock()
+	fc.pins[win.reqID] = win
+	fc.mu.Unlock()
+
+	pin, err := pinner.ServeRequest(&flowRequester{
+		Requester: req,
+		win:       win,
+	})
+	if err != nil || pin == nil {
+		fc.remove(win)
+		return pin, err
+	}
+
+	fc.mu.Lock()
+	win.handler, _ = pin.(FlowHandler)
+	if win.handler != nil && win.exhausted() {
+		win.notifyLocked(true)
+	}
+	fc.mu.Unlock()
+	win.notify()
+
+	go func() {
+		<-pin.Context().Done()
+		fc.remove(win)
+	}()
+	return &flowPin{Pin: pin, fc: fc}, nil
+}
+
+func (fc *FlowController) remove(win *flowWindow) {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+
+	if fc.pins[win.reqID] == win {
+		delete(fc.pins, win.reqID)
+	}
+	if !win.stalledAt.IsZero() {
+		fc.unstallLocked(win)
+	}
+	close(win.done)
+}
+
+// flowWindow is the send window of a flow controlled pin -- its fields are protected by fc.mu.
+type flowWindow struct {
+	fc        *FlowController
+	reqID     tag.ID
+	size      uint64        // max txs sent but not acked
+	sent      uint64        // txs pushed
+	acked     uint64        // txs the client has consumed
+	stalledAt time.Time     // when the window was exhausted (or zero if it is open)
+	handler   FlowHandler   // set once the pin is served if it implements FlowHandler
+	resumed   chan struct{} // closed (and replaced) when an ack reopens the window
+	closing   <-chan struct{}
+	done      chan struct{} // closed once the pin is done
+	notes     []bool        // pending handler calls (true for OnFlowPaused)
+	notifying bool          // set while a goroutine is making handler calls
+}
+
+func (win *flowWindow) exhausted() bool {
+	return win.sent-win.acked >= win.size
+}
+
+// admit blocks until the window has room for another tx (or returns why it can't), then counts the tx as sent.
+func (win *flowWindow) admit() error {
+	fc := win.fc
+	var timeout <-chan time.Time
+
+	fc.mu.Lock()
+	for win.exhausted() {
+		if win.handler != nil {
+			fc.stats.Refused++
+			fc.mu.Unlock()
+			return ErrFlowPaused
+		}
+		resumed := win.resumed
+		fc.mu.Unlock()
+
+		if timeout == nil && fc.opts.StallTimeout > 0 {
+			timer := time.NewTimer(fc.opts.StallTimeout)
+			defer timer.Stop()
+			timeout = timer.C
+		}
+		select {
+		case <-resumed:
+		case <-win.done:
+			return ErrRequestClosed
+		case <-win.closing:
+			return ErrShuttingDown
+		case <-timeout:
+			fc.mu.Lock()
+			fc.stats.Refused++
+			fc.mu.Unlock()
+			return ErrFlowStalled
+		}
+		fc.mu.Lock()
+	}
+
+	win.sent++
+	if win.exhausted() {
+		fc.stallLocked(win)
+		win.notifyLocked(true)
+	}
+	fc.mu.Unlock()
+	win.notify()
+	return nil
+}
+
+// notifyLocked queues a handler call to be made by notify -- fc.mu must be locked.
+func (win *flowWindow) notifyLocked(paused bool) {
+	if win.handler != nil {
+		win.notes = append(win.notes, paused)
+	}
+}
+
+// notify makes queued handler calls in order, unless another goroutine is already doing so -- fc.mu must not be locked.
+func (win *flowWindow) notify() {
+	fc := win.fc
+	fc.mu.Lock()
+	if win.notifying {
+		fc.mu.Unlock()
+		return
+	}
+	win.notifying = true
+	for len(win.notes) > 0 {
+		paused := win.notes[0]
+		win.notes = win.notes[1:]
+		fc.mu.Unlock()
+
+		if paused {
+			win.handler.OnFlowPaused()
+		} else {
+			win.handler.OnFlowResumed()
+		}
+		fc.mu.Lock()
+	}
+	win.notifying = false
+	fc.mu.Unlock()
+}
+
+type flowApp struct {
+	AppInstance
+	fc *FlowController
+}
+
+func (app *flowApp) ServeRequest(req Requester) (Pin, error) {
+	return app.fc.serve(app.AppInstance, req, app.Closing())
+}
+
+type flowPin struct {
+	Pin
+	fc *FlowController
+}
+
+func (pin *flowPin) ServeRequest(req Requester) (Pin, error) {
+	return pin.fc.serve(pin.Pin, req, pin.Context().Closing())
+}
+
+type flowRequester struct {
+	Requester
+	win *flowWindow
+}
+
+// PushTx pushes tx once the request's send window has room for it (see FlowController).
+func (req *flowRequester) PushTx(tx *TxMsg) error {
+	if err := req.win.admit(); err != nil {
+		tx.ReleaseRef()
+		return err
+	}
+	return req.Requester.PushTx(tx)
+}
@@ -0,0 +1,51 @@
+package amp
+
+import (
+	"github.com/amp-3d/amp-sdk-go/stdlib/geo"
+	"github.com/amp-3d/amp-sdk-go/stdlib/tag"
+)
+
+// Geospatial attrs
+//
+// A cell having a location (e.g. a photo, a place, or an AR anchor) emits it as GeoPointSpec, holding a GeoPoint.
+// An app's cells are found by location via package amp/geo, which a host opts into, or via a "within" PinFilter
+// (see Filter) on a field whose value is a geo.Point or *GeoPoint.
+
+var GeoPointSpec = tag.FormSpec(AttrSpec, "GeoPoint")
+
+// NewGeoPoint returns the GeoPoint attr of the given point (at sea level).
+func NewGeoPoint(p geo.Point) *GeoPoint {
+	return &GeoPoint{
+		Lat: p.Lat,
+		Lon: p.Lon,
+	}
+}
+
+// Point returns the latitude and longitude of this GeoPoint.
+func (v *GeoPoint) Point() geo.Point {
+	return geo.Point{Lat: v.Lat, Lon: v.Lon}
+}
+
+// Location returns the GeoPoint of where this media was captured, or nil if none is known.
+func (v *MediaInfo) Location() *GeoPoint {
+	if !v.HasLocation {
+		return nil
+	}
+	return &GeoPoint{
+		Lat: v.Latitude,
+		Lon: v.Longitude,
+	}
+}
+
+// toPoint returns the point of a field value that is a location.
+func toPoint(val any) (geo.Point, bool) {
+	switch v := val.(type) {
+	case geo.Point:
+		return v, true
+	case *GeoPoint:
+		if v != nil {
+			return v.Point(), true
+		}
+	}
+	return geo.Point{}, false
+}
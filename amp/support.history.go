@@ -0,0 +1,203 @@
+package amp
+
+import (
+	"sync"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/tag"
+)
+
+// Commit history
+//
+// An app opts into undo and redo by passing each request's CommitTx through a CommitHistory (see Resolve) and applying the tx it
+// returns in place of the commit.  A client undoes (or redoes) the most recent commit to a cell by committing the tx returned by
+// MarshalUndo (or MarshalRedo):
+//
+//	pin, err := c.Commit(amp.PinRequest{PinTarget: &amp.Tag{URL: cellURL}}, amp.MarshalUndo(cellID))
+
+var (
+	UndoSpec = tag.FormSpec(MetaAttrSpec, "undo")
+	RedoSpec = tag.FormSpec(MetaAttrSpec, "redo")
+)
+
+// DefaultHistoryDepth is the number of commits per cell a CommitHistory retains by default.
+const DefaultHistoryDepth = 32
+
+// MarshalUndo returns a commit tx requesting the most recent commit to the given cell be undone (see CommitHistory).
+func MarshalUndo(cellID tag.ID) *TxMsg {
+	return marshalHistoryOp(cellID, UndoSpec.ID)
+}
+
+// MarshalRedo returns a commit tx requesting the most recently undone commit to the given cell be redone (see CommitHistory).
+func MarshalRedo(cellID tag.ID) *TxMsg {
+	return marshalHistoryOp(cellID, RedoSpec.ID)
+}
+
+func marshalHistoryOp(cellID, attrID tag.ID) *TxMsg {
+	tx := NewTxMsg(true)
+	tx.MarshalOpWithBuf(&TxOp{
+		OpCode:   TxOpCode_MetaAttr,
+		TargetID: cellID,
+		AttrID:   attrID,
+	}, nil)
+	return tx
+}
+
+// AttrReader returns the current value of the given cell attr item, or nil if it has none.
+type AttrReader func(cellID, attrID, SI tag.ID) ElemVal
+
+// CommitHistory records the attr values replaced by the most recent commits to each cell so that they can be undone and redone.
+// Each cell retains a ring of its last Depth commits, and a new commit to a cell discards the commits to it that were undone.
+type CommitHistory struct {
+	depth int
+
+	mu    sync.Mutex
+	cells map[tag.ID]*cellHistory
+}
+
+type cellHistory struct {
+	undo []historyEntry // oldest first
+	redo []historyEntry // most recently undone last
+}
+
+// historyEntry is the part of a commit made to one cell.
+type historyEntry []historyItem
+
+type historyItem struct {
+	attrID, SI tag.ID
+	prior      []byte // value before the commit, or nil if absent
+	value      []byte // value after the commit, or nil if deleted
+}
+
+// NewCommitHistory returns a CommitHistory retaining the given number of commits per cell (or DefaultHistoryDepth if <= 0).
+func NewCommitHistory(depth int) *CommitHistory {
+	if depth <= 0 {
+		depth = DefaultHistoryDepth
+	}
+	return &CommitHistory{
+		depth: depth,
+		cells: make(map[tag.ID]*cellHistory),
+	}
+}
+
+// Resolve returns the tx the app should apply for the given commit.
+//
+// If the commit is an undo or redo (see MarshalUndo), Resolve returns a tx that restores the cell's attr values as they were
+// before (or after) that commit, or ErrCode_NothingToCommit if there is none.  Otherwise, the commit's upserts and deletes are
+// recorded along with their prior values (as returned by current) and the commit itself is returned.
+func (h *CommitHistory) Resolve(commit *TxMsg, current AttrReader) (*TxMsg, error) {
+	if commit == nil {
+		return nil, nil
+	}
+	if len(commit.Ops) == 1 && commit.Ops[0].OpCode == TxOpCode_MetaAttr {
+		switch op := commit.Ops[0]; op.AttrID {
+		case UndoSpec.ID:
+			return h.Undo(op.TargetID)
+		case RedoSpec.ID:
+			return h.Redo(op.TargetID)
+		}
+	}
+	return commit, h.Record(commit, current)
+}
+
+// Record records the upserts and deletes of the given commit, given a reader of the values they replace.
+func (h *CommitHistory) Record(commit *TxMsg, current AttrReader) error {
+	entries := make(map[tag.ID]historyEntry)
+	var order []tag.ID
+	for _, op := range commit.Ops {
+		item := historyItem{
+			attrID: op.AttrID,
+			SI:     op.SI,
+		}
+		switch op.OpCode {
+		case TxOpCode_UpsertAttr:
+			item.value = append([]byte{}, commit.DataStore[op.DataOfs:op.DataOfs+op.DataLen]...)
+		case TxOpCode_DeleteAttr:
+		default:
+			continue
+		}
+		if prior := current(op.TargetID, op.AttrID, op.SI); prior != nil {
+			buf, err := prior.MarshalToStore(nil)
+			if err != nil {
+				return err
+			}
+			item.prior = append([]byte{}, buf...)
+		}
+		if _, exists := entries[op.TargetID]; !exists {
+			order = append(order, op.TargetID)
+		}
+		entries[op.TargetID] = append(entries[op.TargetID], item)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, cellID := range order {
+		cell := h.cells[cellID]
+		if cell == nil {
+			cell = &cellHistory{}
+			h.cells[cellID] = cell
+		}
+		cell.undo = append(cell.undo, entries[cellID])
+		if len(cell.undo) > h.depth {
+			cell.undo = cell.undo[len(cell.undo)-h.depth:]
+		}
+		cell.redo = nil
+	}
+	return nil
+}
+
+// Undo returns a tx reverting the most recent commit to the given cell not yet undone, or ErrCode_NothingToCommit if there is none.
+// The returned tx is not itself recorded.
+func (h *CommitHistory) Undo(cellID tag.ID) (*TxMsg, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	cell := h.cells[cellID]
+	if cell == nil || len(cell.undo) == 0 {
+		return nil, ErrCode_NothingToCommit.Errorf("no commit to undo for cell %s", cellID)
+	}
+	entry := cell.undo[len(cell.undo)-1]
+	cell.undo = cell.undo[:len(cell.undo)-1]
+	cell.redo = append(cell.redo, entry)
+
+	// Revert items in reverse order so an item committed more than once ends with its earliest prior value.
+	tx := NewTxMsg(true)
+	for i := len(entry) - 1; i >= 0; i-- {
+		marshalHistoryItem(tx, cellID, &entry[i], entry[i].prior)
+	}
+	return tx, nil
+}
+
+// Redo returns a tx reapplying the most recently undone commit to the given cell, or ErrCode_NothingToCommit if there is none.
+// The returned tx is not itself recorded.
+func (h *CommitHistory) Redo(cellID tag.ID) (*TxMsg, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	cell := h.cells[cellID]
+	if cell == nil || len(cell.redo) == 0 {
+		return nil, ErrCode_NothingToCommit.Errorf("no commit to redo for cell %s", cellID)
+	}
+	entry := cell.redo[len(cell.redo)-1]
+	cell.redo = cell.redo[:len(cell.redo)-1]
+	cell.undo = append(cell.undo, entry)
+
+	tx := NewTxMsg(true)
+	for i := range entry {
+		marshalHistoryItem(tx, cellID, &entry[i], entry[i].value)
+	}
+	return tx, nil
+}
+
+// marshalHistoryItem marshals an op setting the given item to val, or deleting it if val is nil.
+func marshalHistoryItem(tx *TxMsg, cellID tag.ID, item *historyItem, val []byte) {
+	op := TxOp{
+		OpCode:   TxOpCode_UpsertAttr,
+		TargetID: cellID,
+		AttrID:   item.attrID,
+		SI:       item.SI,
+	}
+	if val == nil {
+		op.OpCode = TxOpCode_DeleteAttr
+	}
+	tx.MarshalOpWithBuf(&op, val)
+}
@@ -0,0 +1,134 @@
+package amp
+
+import (
+	"strings"
+)
+
+// Localization
+//
+// A cell offering its text in several languages emits a single TagTab whose Locales holds a LocaleText for each language
+// (see TagTab.SetLocale), rather than a cell tree per language.  A client declares the locales it prefers when logging in
+// (see Login.Locales), and as each attr is emitted to it (e.g. via basic.Pin.Upsert), a value implementing Localizer is
+// replaced by its variant for those locales (see Localize), so only the text the client displays is sent.
+//
+// The locales available are matched via LocaleChain, and each text field falls back along the chain to the value's default
+// text (e.g. a LocaleText having only a Label takes its Caption from the next best locale).
+
+// Localizer is implemented by an attr value having per-locale variants.
+type Localizer interface {
+
+	// Returns this value as emitted to a client preferring the given locales (best first), without modifying this value.
+	Localize(locales []string) ElemVal
+}
+
+// Localize returns the given attr value as emitted to a client preferring the given locales (best first).
+// If no locales are given or the value is not a Localizer, the value is returned as is.
+func Localize(val ElemVal, locales []string) ElemVal {
+	if len(locales) == 0 {
+		return val
+	}
+	if localizer, ok := val.(Localizer); ok {
+		return localizer.Localize(locales)
+	}
+	return val
+}
+
+// LocaleChain returns the indexes of the given available locales that match the given preferred locales, best first.
+//
+// For each preferred locale in turn, an available locale matches if equal to it (case-insensitive, where "_" is taken as
+// "-"), and then if equal to it with trailing subtags removed (e.g. "zh-Hant-TW", then "zh-Hant", then "zh"), and then if
+// it has the same language (e.g. "en-GB" for "en-US").
+func LocaleChain(preferred, available []string) []int {
+	var chain []int
+	matched := make([]bool, len(available))
+	add := func(match func(avail string) bool) {
+		for i, avail := range available {
+			if !matched[i] && match(normalizeLocale(avail)) {
+				matched[i] = true
+				chain = append(chain, i)
+			}
+		}
+	}
+
+	for _, pref := range preferred {
+		pref = normalizeLocale(pref)
+		if pref == "" {
+			continue
+		}
+		for prefix := pref; prefix != ""; prefix = parentLocale(prefix) {
+			add(func(avail string) bool {
+				return avail == prefix
+			})
+		}
+		lang := localeLanguage(pref)
+		add(func(avail string) bool {
+			return localeLanguage(avail) == lang
+		})
+	}
+	return chain
+}
+
+func normalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+}
+
+// parentLocale returns the given locale with its last subtag removed (and a singleton preceding it, as in "x-private"),
+// or "" if it has no subtags.
+func parentLocale(locale string) string {
+	i := strings.LastIndexByte(locale, '-')
+	if i < 0 {
+		return ""
+	}
+	locale = locale[:i]
+	if i = strings.LastIndexByte(locale, '-'); i >= 0 && len(locale)-i == 2 {
+		locale = locale[:i]
+	}
+	return locale
+}
+
+// localeLanguage returns the language of the given locale.
+func localeLanguage(locale string) string {
+	if i := strings.IndexByte(locale, '-'); i >= 0 {
+		return locale[:i]
+	}
+	return locale
+}
+
+// SetLocale adds the given variant of this tab's text, replacing any already present for the same locale.
+func (v *TagTab) SetLocale(text *LocaleText) {
+	for i, existing := range v.Locales {
+		if normalizeLocale(existing.Locale) == normalizeLocale(text.Locale) {
+			v.Locales[i] = text
+			return
+		}
+	}
+	v.Locales = append(v.Locales, text)
+}
+
+// Localize returns a copy of this tab whose text is that of the locales best matching the given locales (see LocaleChain),
+// falling back to this tab's text, and whose Locales is empty.
+func (v *TagTab) Localize(locales []string) ElemVal {
+	if len(v.Locales) == 0 {
+		return v
+	}
+	available := make([]string, len(v.Locales))
+	for i, text := range v.Locales {
+		available[i] = text.Locale
+	}
+	chain := LocaleChain(locales, available)
+
+	tab := *v
+	tab.Locales = nil
+	pick := func(dst *string, field func(text *LocaleText) string) {
+		for _, i := range chain {
+			if str := field(v.Locales[i]); str != "" {
+				*dst = str
+				return
+			}
+		}
+	}
+	pick(&tab.Label, func(text *LocaleText) string { return text.Label })
+	pick(&tab.Caption, func(text *LocaleText) string { return text.Caption })
+	pick(&tab.About, func(text *LocaleText) string { return text.About })
+	return &tab
+}
@@ -0,0 +1,200 @@
+package amp
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"sync"
+	"time"
+)
+
+// Tx journal
+//
+// A JournalTransport records every tx a session receives and sends to a compact binary log, so that a session can be inspected or
+// replayed after the fact (see amptest.Session.Replay).  A journal starts with journalMagic and the start time (int64 UnixNano),
+// followed by one record per tx: the JournalKind byte, the uvarint nanoseconds since the previous record, the uvarint length of
+// the marshalled tx, and the tx itself (see TxMsg.MarshalToBuffer).
+
+var journalMagic = [8]byte{'a', 'm', 'p', 'j', 'r', 'n', 'l', 1}
+
+// JournalKind is the direction of a recorded tx.
+type JournalKind byte
+
+const (
+	JournalRecv JournalKind = 1 // inbound, from the client
+	JournalSend JournalKind = 2 // outbound, to the client
+)
+
+func (kind JournalKind) String() string {
+	switch kind {
+	case JournalRecv:
+		return "recv"
+	case JournalSend:
+		return "send"
+	default:
+		return "unknown"
+	}
+}
+
+// JournalRecord is one tx read from a journal.
+type JournalRecord struct {
+	Kind JournalKind
+	Time time.Time
+	Tx   *TxMsg
+}
+
+// JournalWriter writes a tx journal -- concurrency safe.
+type JournalWriter struct {
+	mu    sync.Mutex
+	bw    *bufio.Writer
+	last  time.Time
+	scrap []byte
+	err   error // sticky write error
+}
+
+// NewJournalWriter writes a journal header to w and returns a JournalWriter appending records to it.
+func NewJournalWriter(w io.Writer) (*JournalWriter, error) {
+	jw := &JournalWriter{
+		bw:   bufio.NewWriter(w),
+		last: time.Now(),
+	}
+	jw.bw.Write(journalMagic[:])
+	jw.scrap = binary.LittleEndian.AppendUint64(jw.scrap[:0], uint64(jw.last.UnixNano()))
+	if _, err := jw.bw.Write(jw.scrap); err != nil {
+		return nil, err
+	}
+	return jw, nil
+}
+
+// Record appends the given tx to the journal.  The tx is not retained.
+func (jw *JournalWriter) Record(kind JournalKind, tx *TxMsg) error {
+	jw.mu.Lock()
+	defer jw.mu.Unlock()
+
+	if jw.err != nil {
+		return jw.err
+	}
+	now := time.Now()
+	elapsed := now.Sub(jw.last)
+	if elapsed < 0 {
+		elapsed = 0
+	}
+	jw.last = jw.last.Add(elapsed)
+
+	tx.MarshalToBuffer(&jw.scrap)
+	var hdr [1 + 2*binary.MaxVarintLen64]byte
+	hdr[0] = byte(kind)
+	n := 1 + binary.PutUvarint(hdr[1:], uint64(elapsed))
+	n += binary.PutUvarint(hdr[n:], uint64(len(jw.scrap)))
+
+	if _, jw.err = jw.bw.Write(hdr[:n]); jw.err == nil {
+		_, jw.err = jw.bw.Write(jw.scrap)
+	}
+	return jw.err
+}
+
+// Flush writes any buffered records to the underlying writer.
+func (jw *JournalWriter) Flush() error {
+	jw.mu.Lock()
+	defer jw.mu.Unlock()
+
+	if jw.err == nil {
+		jw.err = jw.bw.Flush()
+	}
+	return jw.err
+}
+
+// JournalReader reads a journal written by a JournalWriter.
+type JournalReader struct {
+	br   *bufio.Reader
+	last time.Time
+}
+
+// NewJournalReader reads the journal header from r.
+func NewJournalReader(r io.Reader) (*JournalReader, error) {
+	jr := &JournalReader{
+		br: bufio.NewReader(r),
+	}
+	var hdr [16]byte
+	if _, err := io.ReadFull(jr.br, hdr[:]); err != nil {
+		return nil, ErrCode_DataFailure.Errorf("bad journal header: %v", err)
+	}
+	if !bytes.Equal(hdr[:8], journalMagic[:]) {
+		return nil, ErrCode_DataFailure.Error("not a tx journal")
+	}
+	jr.last = time.Unix(0, int64(binary.LittleEndian.Uint64(hdr[8:])))
+	return jr, nil
+}
+
+// Next returns the next record in the journal, or io.EOF once there are no more.
+func (jr *JournalReader) Next() (JournalRecord, error) {
+	kind, err := jr.br.ReadByte()
+	if err != nil {
+		return JournalRecord{}, err
+	}
+	elapsed, err := binary.ReadUvarint(jr.br)
+	if err != nil {
+		return JournalRecord{}, ErrCode_DataFailure.Errorf("truncated journal: %v", err)
+	}
+	size, err := binary.ReadUvarint(jr.br)
+	if err != nil {
+		return JournalRecord{}, ErrCode_DataFailure.Errorf("truncated journal: %v", err)
+	}
+	lr := io.LimitedReader{R: jr.br, N: int64(size)}
+	tx, err := ReadTxMsg(&lr)
+	if err != nil {
+		return JournalRecord{}, ErrCode_DataFailure.Errorf("bad journal record: %v", err)
+	}
+	if lr.N != 0 {
+		tx.ReleaseRef()
+		return JournalRecord{}, ErrCode_DataFailure.Error("bad journal record length")
+	}
+
+	jr.last = jr.last.Add(time.Duration(elapsed))
+	return JournalRecord{
+		Kind: JournalKind(kind),
+		Time: jr.last,
+		Tx:   tx,
+	}, nil
+}
+
+// JournalTransport wraps a Transport, recording each tx sent and received to a JournalWriter.
+type JournalTransport struct {
+	raw     Transport
+	journal *JournalWriter
+}
+
+var _ Transport = (*JournalTransport)(nil)
+
+// NewJournalTransport wraps raw so that its txs are recorded to the given journal.
+// A journal write failure does not interrupt the session; see JournalWriter.Flush for the first error.
+func NewJournalTransport(raw Transport, journal *JournalWriter) *JournalTransport {
+	return &JournalTransport{
+		raw:     raw,
+		journal: journal,
+	}
+}
+
+func (tr *JournalTransport) Label() string {
+	return tr.raw.Label()
+}
+
+// Close flushes the journal and closes the wrapped transport.
+func (tr *JournalTransport) Close() error {
+	tr.journal.Flush()
+	return tr.raw.Close()
+}
+
+func (tr *JournalTransport) SendTx(tx *TxMsg) error {
+	tr.journal.Record(JournalSend, tx)
+	return tr.raw.SendTx(tx)
+}
+
+func (tr *JournalTransport) RecvTx() (*TxMsg, error) {
+	tx, err := tr.raw.RecvTx()
+	if err == nil {
+		tr.journal.Record(JournalRecv, tx)
+	}
+	return tx, err
+}
@@ -0,0 +1,261 @@
+package amp
+
+import (
+	"bytes"
+	"encoding/binary"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/symbol"
+)
+
+// KVStorePrefix prefixes the keys of an app's KVStore within the app's store (see Space.AppKVStore).
+const KVStorePrefix = "kv/"
+
+// StoreOf returns the persisted KVStore of the given AppContext's app, or nil if it does not implement KVStoreProvider.
+func StoreOf(ctx AppContext) KVStore {
+	if sp, ok := ctx.(KVStoreProvider); ok {
+		return sp.Store()
+	}
+	return nil
+}
+
+// NewKVStore returns a KVStore persisting its entries in the given store.
+// Callers should keep a single KVStore per store, since txns are only serialized with writes made through the same KVStore.
+//
+// Each entry is stored as {expires}{value}, where expires is a big endian uint64 of unix nanoseconds (or 0 for never).
+func NewKVStore(store symbol.Store) KVStore {
+	return &kvStore{
+		store: store,
+		now:   time.Now,
+	}
+}
+
+// kvStore implements KVStore over a symbol.Store.
+type kvStore struct {
+	store symbol.Store
+	now   func() time.Time
+	mu    sync.Mutex // serializes writes and txns
+}
+
+// kvWrite is a write buffered by a kvTxn -- a nil entry is a delete.
+type kvWrite struct {
+	entry []byte
+}
+
+const kvEntryHeader = 8
+
+func (kv *kvStore) encode(value []byte, ttl time.Duration) []byte {
+	var expires uint64
+	if ttl > 0 {
+		expires = uint64(kv.now().Add(ttl).UnixNano())
+	}
+	entry := make([]byte, kvEntryHeader, kvEntryHeader+len(value))
+	binary.BigEndian.PutUint64(entry, expires)
+	return append(entry, value...)
+}
+
+// decode returns the value of the given entry, or false if the entry is malformed or has expired.
+func (kv *kvStore) decode(entry []byte, now int64) ([]byte, bool) {
+	if len(entry) < kvEntryHeader {
+		return nil, false
+	}
+	expires := binary.BigEndian.Uint64(entry)
+	if expires != 0 && int64(expires) <= now {
+		return nil, false
+	}
+	return entry[kvEntryHeader:], true
+}
+
+func (kv *kvStore) Get(key []byte) ([]byte, error) {
+	entry, err := kv.store.Get(key)
+	if err != nil || entry == nil {
+		return nil, kvErr(err)
+	}
+	value, _ := kv.decode(entry, kv.now().UnixNano())
+	return value, nil
+}
+
+func (kv *kvStore) Set(key, value []byte, ttl time.Duration) error {
+	entry := kv.encode(value, ttl)
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+
+	if err := kv.store.Set(key, entry); err != nil {
+		return kvErr(err)
+	}
+	return kvErr(kv.store.Commit())
+}
+
+func (kv *kvStore) Delete(key []byte) error {
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+
+	if err := kv.store.Delete(key); err != nil {
+		return kvErr(err)
+	}
+	return kvErr(kv.store.Commit())
+}
+
+func (kv *kvStore) Iterate(prefix []byte, fn func(key, value []byte) error) error {
+	now := kv.now().UnixNano()
+	return kv.store.Iterate(prefix, func(key, entry []byte) error {
+		if value, ok := kv.decode(entry, now); ok {
+			return fn(key, value)
+		}
+		return nil
+	})
+}
+
+func (kv *kvStore) Update(fn func(txn KVTxn) error) error {
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+
+	txn := &kvTxn{
+		kv:     kv,
+		writes: make(map[string]kvWrite),
+	}
+	err := fn(txn)
+	txn.kv = nil
+	if err != nil || len(txn.writes) == 0 {
+		return err
+	}
+
+	keys := make([]string, 0, len(txn.writes))
+	for key := range txn.writes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if entry := txn.writes[key].entry; entry != nil {
+			err = kv.store.Set([]byte(key), entry)
+		} else {
+			err = kv.store.Delete([]byte(key))
+		}
+		if err != nil {
+			return kvErr(err)
+		}
+	}
+	return kvErr(kv.store.Commit())
+}
+
+func (kv *kvStore) Purge() (int, error) {
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+
+	now := kv.now().UnixNano()
+	var expired [][]byte
+	err := kv.store.Iterate(nil, func(key, entry []byte) error {
+		if _, ok := kv.decode(entry, now); !ok {
+			expired = append(expired, append([]byte{}, key...))
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, kvErr(err)
+	}
+	if len(expired) == 0 {
+		return 0, nil
+	}
+	for _, key := range expired {
+		if err = kv.store.Delete(key); err != nil {
+			return 0, kvErr(err)
+		}
+	}
+	return len(expired), kvErr(kv.store.Commit())
+}
+
+func kvErr(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, isErr := err.(*Err); isErr {
+		return err
+	}
+	return ErrCode_DataFailure.Wrap(err)
+}
+
+// kvTxn implements KVTxn by buffering writes until its KVStore.Update completes -- kv.mu is locked throughout.
+type kvTxn struct {
+	kv     *kvStore // nil once the txn has completed
+	writes map[string]kvWrite
+}
+
+func (txn *kvTxn) check() error {
+	if txn.kv == nil {
+		return ErrCode_BadRequest.Error("kv txn has completed")
+	}
+	return nil
+}
+
+func (txn *kvTxn) Get(key []byte) ([]byte, error) {
+	if err := txn.check(); err != nil {
+		return nil, err
+	}
+	if w, found := txn.writes[string(key)]; found {
+		if w.entry == nil {
+			return nil, nil
+		}
+		value, _ := txn.kv.decode(w.entry, txn.kv.now().UnixNano())
+		return append([]byte{}, value...), nil
+	}
+	return txn.kv.Get(key)
+}
+
+func (txn *kvTxn) Set(key, value []byte, ttl time.Duration) error {
+	if err := txn.check(); err != nil {
+		return err
+	}
+	txn.writes[string(key)] = kvWrite{entry: txn.kv.encode(value, ttl)}
+	return nil
+}
+
+func (txn *kvTxn) Delete(key []byte) error {
+	if err := txn.check(); err != nil {
+		return err
+	}
+	txn.writes[string(key)] = kvWrite{}
+	return nil
+}
+
+// Iterate merges the txn's writes with the store's entries, so it reads the store's matching entries before calling fn.
+func (txn *kvTxn) Iterate(prefix []byte, fn func(key, value []byte) error) error {
+	if err := txn.check(); err != nil {
+		return err
+	}
+	kv := txn.kv
+	entries := make(map[string][]byte)
+	err := kv.store.Iterate(prefix, func(key, entry []byte) error {
+		entries[string(key)] = append([]byte{}, entry...)
+		return nil
+	})
+	if err != nil {
+		return kvErr(err)
+	}
+	for key, w := range txn.writes {
+		if !bytes.HasPrefix([]byte(key), prefix) {
+			continue
+		}
+		if w.entry == nil {
+			delete(entries, key)
+		} else {
+			entries[key] = w.entry
+		}
+	}
+
+	keys := make([]string, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	now := kv.now().UnixNano()
+	for _, key := range keys {
+		if value, ok := kv.decode(entries[key], now); ok {
+			if err := fn([]byte(key), value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
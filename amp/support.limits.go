@@ -0,0 +1,262 @@
+package amp
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/utils"
+)
+
+// SessionLimits are the per-session throttling limits enforced by a SessionLimiter.  A zero value means no limit.
+type SessionLimits struct {
+	RequestsPerSec float64       // sustained rate of new requests (pins and commits)
+	RequestBurst   int           // requests allowed in a burst above RequestsPerSec (default 1 + RequestsPerSec)
+	MaxPins        int           // max pins open at once
+	BytesPerSec    int64         // sustained rate of tx bytes pushed to the client
+	BytesBurst     int64         // bytes allowed in a burst above BytesPerSec (default BytesPerSec)
+	MaxDelay       time.Duration // the longest a push is delayed to stay under BytesPerSec before it fails (default 1s)
+}
+
+// SessionLimiterStats reports the activity of a SessionLimiter.
+type SessionLimiterStats struct {
+	Requests  int64 // requests admitted
+	Rejected  int64 // requests rejected with ErrCode_RateLimited
+	OpenPins  int   // pins currently open
+	BytesSent int64 // tx bytes pushed
+	Throttled int64 // pushes delayed to stay under BytesPerSec
+	Dropped   int64 // pushes failed with ErrCode_RateLimited
+}
+
+// SessionLimiter enforces SessionLimits across all the app instances of a session, so that one misbehaving client can't starve the rest.
+// Requests over a limit fail with ErrCode_RateLimited; pushes over BytesPerSec are delayed (up to MaxDelay), applying backpressure to the app.
+//
+// A host creates one SessionLimiter per HostSession and wraps each AppInstance it issues via Limit().
+type SessionLimiter struct {
+	limits   SessionLimits
+	mu       sync.Mutex
+	requests *utils.RateLimiter
+	bytes    *utils.RateLimiter
+	pins     []Pin
+	reserved int // pins admitted by reservePin but not yet served
+	stats    SessionLimiterStats
+}
+
+// NewSessionLimiter returns a SessionLimiter enforcing the given limits.
+func NewSessionLimiter(limits SessionLimits) *SessionLimiter {
+	limits = limits.withDefaults()
+	return &SessionLimiter{
+		limits: limits,
+		requests: utils.NewRateLimiter(utils.RateLimiterOpts{
+			Rate:  limits.RequestsPerSec,
+			Burst: int64(limits.RequestBurst),
+		}),
+		bytes: utils.NewRateLimiter(utils.RateLimiterOpts{
+			Rate:  float64(limits.BytesPerSec),
+			Burst: limits.BytesBurst,
+		}),
+	}
+}
+
+func (limits SessionLimits) withDefaults() SessionLimits {
+	if limits.RequestBurst <= 0 {
+		limits.RequestBurst = 1 + int(limits.RequestsPerSec)
+	}
+	if limits.BytesBurst <= 0 {
+		limits.BytesBurst = limits.BytesPerSec
+	}
+	if limits.MaxDelay <= 0 {
+		limits.MaxDelay = time.Second
+	}
+	return limits
+}
+
+// SetLimits replaces the limits this limiter enforces, taking effect for subsequent requests and pushes (e.g. as an operator tunes
+// them at runtime, see package config).  Pins served while MaxPins was zero do not count toward a MaxPins set later.
+func (lim *SessionLimiter) SetLimits(limits SessionLimits) {
+	limits = limits.withDefaults()
+
+	lim.mu.Lock()
+	defer lim.mu.Unlock()
+	lim.limits = limits
+	lim.requests.SetRate(limits.RequestsPerSec, int64(limits.RequestBurst))
+	lim.bytes.SetRate(float64(limits.BytesPerSec), limits.BytesBurst)
+}
+
+// Stats returns a snapshot of this limiter's activity.
+func (lim *SessionLimiter) Stats() SessionLimiterStats {
+	lim.mu.Lock()
+	defer lim.mu.Unlock()
+
+	lim.prunePins()
+	stats := lim.stats
+	stats.OpenPins = len(lim.pins)
+	return stats
+}
+
+// Limit wraps the given AppInstance so that its requests and pushes count against this limiter.
+func (lim *SessionLimiter) Limit(inst AppInstance) AppInstance {
+	return &limitedApp{
+		AppInstance: inst,
+		lim:         lim,
+	}
+}
+
+// admitRequest consumes a request token, returning ErrCode_RateLimited if none are available.
+func (lim *SessionLimiter) admitRequest() error {
+	lim.mu.Lock()
+	defer lim.mu.Unlock()
+
+	if lim.limits.RequestsPerSec > 0 {
+		if wait := lim.requests.ReserveN(1); wait > 0 {
+			lim.requests.CancelN(1)
+			lim.stats.Rejected++
+			err := ErrCode_RateLimited.Errorf("request rate limit of %g/s exceeded (retry in %v)", lim.limits.RequestsPerSec, wait.Round(time.Millisecond))
+			return err.(*Err).WithDetail(RetryAfterDetail, strconv.FormatInt(wait.Milliseconds(), 10))
+		}
+	}
+	lim.stats.Requests++
+	return nil
+}
+
+// reservePin reserves one of MaxPins for a pin about to be served, so that concurrent requests can't together exceed it.
+func (lim *SessionLimiter) reservePin() (reserved bool, err error) {
+	lim.mu.Lock()
+	defer lim.mu.Unlock()
+	if lim.limits.MaxPins <= 0 {
+		return false, nil
+	}
+
+	lim.prunePins()
+	if len(lim.pins)+lim.reserved >= lim.limits.MaxPins {
+		lim.stats.Rejected++
+		return false, ErrCode_RateLimited.Errorf("limit of %d open pins reached", lim.limits.MaxPins)
+	}
+	lim.reserved++
+	return true, nil
+}
+
+// addPin fills a reservation made by reservePin with the pin served, or releases it if no pin was served.
+func (lim *SessionLimiter) addPin(pin Pin, reserved bool) {
+	if !reserved {
+		return
+	}
+	lim.mu.Lock()
+	defer lim.mu.Unlock()
+	lim.reserved--
+	if pin != nil {
+		lim.pins = append(lim.pins, pin)
+	}
+}
+
+// prunePins forgets pins whose Context is done.
+func (lim *SessionLimiter) prunePins() {
+	open := lim.pins[:0]
+	for _, pin := range lim.pins {
+		select {
+		case <-pin.Context().Done():
+		default:
+			open = append(open, pin)
+		}
+	}
+	for i := len(open); i < len(lim.pins); i++ {
+		lim.pins[i] = nil
+	}
+	lim.pins = open
+}
+
+// throttlePush blocks as needed to keep pushes under BytesPerSec, returning ErrCode_RateLimited if that would exceed MaxDelay.
+func (lim *SessionLimiter) throttlePush(tx *TxMsg, closing <-chan struct{}) error {
+	size := txWireSize(tx)
+
+	lim.mu.Lock()
+	var wait time.Duration
+	if lim.limits.BytesPerSec > 0 {
+		wait = lim.bytes.ReserveN(size)
+		if wait > lim.limits.MaxDelay {
+			lim.bytes.CancelN(size)
+			lim.stats.Dropped++
+			lim.mu.Unlock()
+			return ErrCode_RateLimited.Errorf("bandwidth limit of %d bytes/s exceeded", lim.limits.BytesPerSec)
+		}
+		if wait > 0 {
+			lim.stats.Throttled++
+		}
+	}
+	lim.stats.BytesSent += size
+	lim.mu.Unlock()
+
+	if wait > 0 {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-closing:
+			return ErrShuttingDown
+		}
+	}
+	return nil
+}
+
+func (lim *SessionLimiter) serve(pinner Pinner, req Requester, closing <-chan struct{}) (Pin, error) {
+	reserved, err := lim.reservePin()
+	if err != nil {
+		return nil, err
+	}
+	pin, err := pinner.ServeRequest(&limitedRequester{
+		Requester: req,
+		lim:       lim,
+		closing:   closing,
+	})
+	if err != nil || pin == nil {
+		lim.addPin(nil, reserved)
+		return pin, err
+	}
+	lim.addPin(pin, reserved)
+	return &limitedPin{
+		Pin: pin,
+		lim: lim,
+	}, nil
+}
+
+type limitedApp struct {
+	AppInstance
+	lim *SessionLimiter
+}
+
+func (app *limitedApp) MakeReady(req Requester) error {
+	if err := app.lim.admitRequest(); err != nil {
+		return err
+	}
+	return app.AppInstance.MakeReady(req)
+}
+
+func (app *limitedApp) ServeRequest(req Requester) (Pin, error) {
+	return app.lim.serve(app.AppInstance, req, app.Closing())
+}
+
+type limitedPin struct {
+	Pin
+	lim *SessionLimiter
+}
+
+func (pin *limitedPin) ServeRequest(req Requester) (Pin, error) {
+	if err := pin.lim.admitRequest(); err != nil {
+		return nil, err
+	}
+	return pin.lim.serve(pin.Pin, req, pin.Context().Closing())
+}
+
+type limitedRequester struct {
+	Requester
+	lim     *SessionLimiter
+	closing <-chan struct{}
+}
+
+func (req *limitedRequester) PushTx(tx *TxMsg) error {
+	if err := req.lim.throttlePush(tx, req.closing); err != nil {
+		tx.ReleaseRef() // PushTx takes the caller's reference either way
+		return err
+	}
+	return req.Requester.PushTx(tx)
+}
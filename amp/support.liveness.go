@@ -0,0 +1,333 @@
+package amp
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/tag"
+	"github.com/amp-3d/amp-sdk-go/stdlib/task"
+)
+
+// Liveness
+//
+// A KeepaliveTransport sends a ping (a PingAttrSpec meta attr) every KeepaliveOpts.Interval and answers each ping it receives with
+// a pong.  Any tx received counts as a sign of life, so pings are only needed to detect a link that has gone quiet.  If nothing is
+// received for KeepaliveOpts.Timeout, the underlying transport is closed and RecvTx returns ErrSessionDead, so a host reaps a dead
+// session deterministically rather than waiting on TCP timeouts.
+//
+// A PinReaper closes pins that see no activity for PinReaperOpts.IdleTimeout, notifying pins that implement PinReapHandler.
+
+var (
+	PingAttrSpec = tag.FormSpec(MetaAttrSpec, "keepalive.ping")
+	PongAttrSpec = tag.FormSpec(MetaAttrSpec, "keepalive.pong")
+)
+
+const (
+	DefaultKeepaliveInterval = 15 * time.Second
+	DefaultKeepaliveTimeout  = 45 * time.Second
+)
+
+// KeepaliveOpts configures a KeepaliveTransport.
+type KeepaliveOpts struct {
+	Interval time.Duration   // how often a ping is sent (default DefaultKeepaliveInterval)
+	Timeout  time.Duration   // how long without receiving anything before the link is declared dead (default DefaultKeepaliveTimeout)
+	OnDead   func(err error) // if set, called once when the link is declared dead (e.g. to call PinReaper.ReapAll)
+}
+
+// KeepaliveTransport wraps a Transport with keepalive pings and liveness detection.
+type KeepaliveTransport struct {
+	raw      Transport
+	opts     KeepaliveOpts
+	lastRecv atomic.Int64 // UnixNano of the most recently received tx
+	rtt      atomic.Int64 // most recent ping round trip time
+	dead     atomic.Bool
+	closing  chan struct{}
+	once     sync.Once
+}
+
+var _ Transport = (*KeepaliveTransport)(nil)
+
+// NewKeepaliveTransport wraps raw and starts sending pings.  Both ends of a link should be wrapped so that pings are answered.
+func NewKeepaliveTransport(raw Transport, opts KeepaliveOpts) *KeepaliveTransport {
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultKeepaliveInterval
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultKeepaliveTimeout
+	}
+	tr := &KeepaliveTransport{
+		raw:     raw,
+		opts:    opts,
+		closing: make(chan struct{}),
+	}
+	tr.lastRecv.Store(time.Now().UnixNano())
+	go tr.keepalive()
+	return tr
+}
+
+func (tr *KeepaliveTransport) keepalive() {
+	check := tr.opts.Interval
+	if check > tr.opts.Timeout {
+		check = tr.opts.Timeout
+	}
+	ticker := time.NewTicker(check)
+	defer ticker.Stop()
+
+	lastPing := time.Time{}
+	for {
+		select {
+		case <-tr.closing:
+			return
+		case now := <-ticker.C:
+			if now.Sub(time.Unix(0, tr.lastRecv.Load())) > tr.opts.Timeout {
+				tr.declareDead()
+				return
+			}
+			if now.Sub(lastPing) >= tr.opts.Interval {
+				lastPing = now
+				tr.raw.SendTx(marshalPing(PingAttrSpec.ID, uint64(now.UnixNano())))
+			}
+		}
+	}
+}
+
+func (tr *KeepaliveTransport) declareDead() {
+	if tr.dead.Swap(true) {
+		return
+	}
+	tr.raw.Close()
+	if tr.opts.OnDead != nil {
+		tr.opts.OnDead(ErrSessionDead)
+	}
+}
+
+// RTT returns the round trip time of the most recently answered ping (or 0 if none has been answered).
+func (tr *KeepaliveTransport) RTT() time.Duration {
+	return time.Duration(tr.rtt.Load())
+}
+
+// LastReceived returns when a tx was last received.
+func (tr *KeepaliveTransport) LastReceived() time.Time {
+	return time.Unix(0, tr.lastRecv.Load())
+}
+
+func (tr *KeepaliveTransport) Label() string {
+	return tr.raw.Label()
+}
+
+func (tr *KeepaliveTransport) Close() error {
+	tr.once.Do(func() {
+		close(tr.closing)
+	})
+	return tr.raw.Close()
+}
+
+func (tr *KeepaliveTransport) SendTx(tx *TxMsg) error {
+	return tr.raw.SendTx(tx)
+}
+
+// RecvTx returns the next tx received that is not a ping or pong, or ErrSessionDead if the link was declared dead.
+func (tr *KeepaliveTransport) RecvTx() (*TxMsg, error) {
+	for {
+		tx, err := tr.raw.RecvTx()
+		if err != nil {
+			if tr.dead.Load() {
+				return nil, ErrSessionDead
+			}
+			return nil, err
+		}
+		now := time.Now()
+		tr.lastRecv.Store(now.UnixNano())
+
+		if len(tx.Ops) == 0 || tx.Ops[0].OpCode != TxOpCode_MetaAttr {
+			return tx, nil
+		}
+		switch op := tx.Ops[0]; op.AttrID {
+		case PingAttrSpec.ID:
+			tr.raw.SendTx(marshalPing(PongAttrSpec.ID, op.Height))
+		case PongAttrSpec.ID:
+			if sent := time.Unix(0, int64(op.Height)); sent.Before(now) {
+				tr.rtt.Store(int64(now.Sub(sent)))
+			}
+		default:
+			return tx, nil
+		}
+		tx.ReleaseRef()
+	}
+}
+
+// marshalPing returns a ping or pong tx, where op.Height holds the ping's send time.
+func marshalPing(attrID tag.ID, sentAt uint64) *TxMsg {
+	tx := NewTxMsg(true)
+	tx.MarshalOpWithBuf(&TxOp{
+		OpCode: TxOpCode_MetaAttr,
+		AttrID: attrID,
+		Height: sentAt,
+	}, nil)
+	return tx
+}
+
+// PinReaperOpts configures a PinReaper.
+type PinReaperOpts struct {
+	IdleTimeout time.Duration // how long a pin may go without activity before it is reaped (required)
+	CheckEvery  time.Duration // how often pins are checked (default IdleTimeout / 4)
+}
+
+// PinReaper wraps an AppInstance, reaping its pins (via Pin.Context().Close()) that go idle.
+//
+// A pin is active when the app pushes a tx to its Requester, when it spawns a child pin, or when Touch is called for its request
+// (e.g. when a client commits a tx to it).  Before a pin is reaped, its Requester is completed with the reason and
+// PinReapHandler.OnPinReaped is called if the pin implements it.
+type PinReaper struct {
+	AppInstance
+	opts PinReaperOpts
+	mu   sync.Mutex
+	pins map[tag.ID]*reapedPin // by Request.ID
+}
+
+// NewPinReaper wraps the given AppInstance and starts reaping idle pins until its Context closes.
+func NewPinReaper(inst AppInstance, opts PinReaperOpts) (*PinReaper, error) {
+	if opts.IdleTimeout <= 0 {
+		return nil, ErrCode_BadValue.Error("PinReaperOpts.IdleTimeout must be set")
+	}
+	if opts.CheckEvery <= 0 {
+		opts.CheckEvery = opts.IdleTimeout / 4
+	}
+	reaper := &PinReaper{
+		AppInstance: inst,
+		opts:        opts,
+		pins:        make(map[tag.ID]*reapedPin),
+	}
+	_, err := inst.Go("PinReaper", func(ctx task.Context) {
+		ticker := time.NewTicker(reaper.opts.CheckEvery)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Closing():
+				return
+			case now := <-ticker.C:
+				reaper.reapIdle(now)
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reaper, nil
+}
+
+func (reaper *PinReaper) ServeRequest(req Requester) (Pin, error) {
+	return reaper.serve(reaper.AppInstance, req)
+}
+
+// Touch marks the pin serving the given request as active.
+func (reaper *PinReaper) Touch(reqID tag.ID) {
+	reaper.mu.Lock()
+	pin := reaper.pins[reqID]
+	reaper.mu.Unlock()
+
+	if pin != nil {
+		pin.touch()
+	}
+}
+
+// NumPins returns the number of pins currently tracked.
+func (reaper *PinReaper) NumPins() int {
+	reaper.mu.Lock()
+	defer reaper.mu.Unlock()
+	return len(reaper.pins)
+}
+
+// ReapAll reaps every pin for the given reason -- e.g. when its session is declared dead (see KeepaliveOpts.OnDead).
+func (reaper *PinReaper) ReapAll(reason error) {
+	reaper.mu.Lock()
+	pins := make([]*reapedPin, 0, len(reaper.pins))
+	for reqID, pin := range reaper.pins {
+		pins = append(pins, pin)
+		delete(reaper.pins, reqID)
+	}
+	reaper.mu.Unlock()
+
+	for _, pin := range pins {
+		pin.reap(reason)
+	}
+}
+
+func (reaper *PinReaper) reapIdle(now time.Time) {
+	idleSince := now.Add(-reaper.opts.IdleTimeout).UnixNano()
+
+	var idle []*reapedPin
+	reaper.mu.Lock()
+	for reqID, pin := range reaper.pins {
+		select {
+		case <-pin.Context().Done():
+			delete(reaper.pins, reqID)
+		default:
+			if pin.lastActive.Load() < idleSince {
+				idle = append(idle, pin)
+				delete(reaper.pins, reqID)
+			}
+		}
+	}
+	reaper.mu.Unlock()
+
+	for _, pin := range idle {
+		pin.reap(ErrPinIdle)
+	}
+}
+
+func (reaper *PinReaper) serve(pinner Pinner, req Requester) (Pin, error) {
+	tracked := &reapedPin{
+		reaper: reaper,
+	}
+	tracked.touch()
+	tracked.req = &reapedRequester{
+		Requester: req,
+		pin:       tracked,
+	}
+	pin, err := pinner.ServeRequest(tracked.req)
+	if err != nil || pin == nil {
+		return pin, err
+	}
+	tracked.Pin = pin
+
+	reaper.mu.Lock()
+	reaper.pins[req.Request().ID] = tracked
+	reaper.mu.Unlock()
+	return tracked, nil
+}
+
+type reapedPin struct {
+	Pin
+	reaper     *PinReaper
+	req        Requester
+	lastActive atomic.Int64 // UnixNano
+}
+
+func (pin *reapedPin) touch() {
+	pin.lastActive.Store(time.Now().UnixNano())
+}
+
+func (pin *reapedPin) ServeRequest(req Requester) (Pin, error) {
+	pin.touch()
+	return pin.reaper.serve(pin.Pin, req)
+}
+
+func (pin *reapedPin) reap(reason error) {
+	if handler, ok := pin.Pin.(PinReapHandler); ok {
+		handler.OnPinReaped(reason)
+	}
+	pin.req.OnComplete(reason)
+	pin.Pin.Context().Close()
+}
+
+type reapedRequester struct {
+	Requester
+	pin *reapedPin
+}
+
+func (req *reapedRequester) PushTx(tx *TxMsg) error {
+	req.pin.touch()
+	return req.Requester.PushTx(tx)
+}
@@ -0,0 +1,145 @@
+package amp
+
+import (
+	"github.com/amp-3d/amp-sdk-go/stdlib/media/cue"
+	"github.com/amp-3d/amp-sdk-go/stdlib/media/derive"
+	"github.com/amp-3d/amp-sdk-go/stdlib/media/metadata"
+	"github.com/amp-3d/amp-sdk-go/stdlib/tag"
+)
+
+// Media metadata
+//
+// An app offering media files (e.g. a filesystem or music library app) extracts the metadata of each file via package
+// media/metadata -- typically via a metadata.Indexer scanning the library -- and emits it as the standard attrs of the file's cell:
+//   - its TagTab, as returned by MediaTab
+//   - MediaInfoSpec, holding the MediaInfo returned by NewMediaInfo
+//   - ArtworkSpec, holding a Tag for each embedded picture (see ArtworkTag), where the SI is the picture's index (see ArtworkSI)
+//
+// A file holding several tracks (e.g. an album ripped to a single file, as described by a cue sheet) is offered as a cell per
+// track, each located within the file via package media/cue and emitting the above for its cue.Split (see cue.Split.Info) and:
+//   - TrackOffsetSpec, holding the TrackOffset returned by NewTrackOffset
+//
+// Assets derived from the file via a derive.Generator are emitted as:
+//   - WaveformSpec, holding the WaveformPeaks returned by NewWaveformPeaks
+//   - ThumbnailSpec, holding a Tag for each thumbnail size offered (see ThumbnailTag), where the SI is the size requested (see ThumbnailSI)
+//
+// A client reports its playback of a playable cell by committing PlaybackEventSpec to it, holding a PlaybackEvent.
+
+// MaxInlineGlyphSize is the largest front cover MediaTab attaches inline.
+const MaxInlineGlyphSize = 64 << 10
+
+var (
+	MediaInfoSpec = tag.FormSpec(AttrSpec, "MediaInfo")
+	ArtworkSpec   = tag.FormSpec(AttrSpec, "artwork.Tag")
+	WaveformSpec  = tag.FormSpec(AttrSpec, "WaveformPeaks")
+	ThumbnailSpec = tag.FormSpec(AttrSpec, "thumbnail.Tag")
+
+	TrackOffsetSpec = tag.FormSpec(AttrSpec, "TrackOffset")
+
+	PlaybackEventSpec = tag.FormSpec(AttrSpec, "PlaybackEvent")
+)
+
+// NewMediaInfo returns the MediaInfo attr of the given metadata.
+func NewMediaInfo(md *metadata.Info) *MediaInfo {
+	info := &MediaInfo{
+		ContentType: md.ContentType,
+		Title:       md.Title,
+		Artist:      md.Artist,
+		Album:       md.Album,
+		AlbumArtist: md.AlbumArtist,
+		Composer:    md.Composer,
+		Genre:       md.Genre,
+		Comment:     md.Comment,
+		Year:        int32(md.Year),
+		TrackNum:    int32(md.TrackNum),
+		TrackCount:  int32(md.TrackCount),
+		DiscNum:     int32(md.DiscNum),
+		DiscCount:   int32(md.DiscCount),
+		DurationMs:  md.Duration.Milliseconds(),
+		SampleRate:  int32(md.SampleRate),
+		Channels:    int32(md.Channels),
+		Width:       int32(md.Width),
+		Height:      int32(md.Height),
+		Orientation: int32(md.Orientation),
+		CameraMake:  md.CameraMake,
+		CameraModel: md.CameraModel,
+		HasLocation: md.HasLocation,
+		Latitude:    md.Latitude,
+		Longitude:   md.Longitude,
+
+		EncoderDelay:   int32(md.EncoderDelay),
+		EncoderPadding: int32(md.EncoderPadding),
+	}
+	if !md.TakenAt.IsZero() {
+		info.TakenAt = md.TakenAt.Unix()
+	}
+	return info
+}
+
+// MediaTab returns a TagTab for the given metadata: the title (or label if there is none) with the artist and album, where
+// the front cover (if any) is attached as a glyph if no larger than MaxInlineGlyphSize.
+func MediaTab(md *metadata.Info, label string) TagTab {
+	tab := TagTab{
+		Label:   md.Title,
+		Caption: md.Artist,
+		About:   md.Album,
+	}
+	if tab.Label == "" {
+		tab.Label = label
+	}
+	if cover := md.FrontCover(); cover != nil && len(cover.Data) <= MaxInlineGlyphSize {
+		glyph := ArtworkTag(cover)
+		glyph.Use = TagUse_Glyph
+		tab.Tags = append(tab.Tags, glyph)
+	}
+	return tab
+}
+
+// ArtworkTag returns a Tag holding the given artwork as an inline attachment.
+// For large artwork, an app may instead publish it (see PublishAssetTag) and emit a Tag linking to it.
+func ArtworkTag(art *metadata.Artwork) *Tag {
+	return &Tag{
+		Use:         TagUse_Content,
+		ContentType: art.ContentType,
+		Attachment:  art.Data,
+	}
+}
+
+// ArtworkSI returns the SI of the ArtworkSpec attr for the given artwork index.
+func ArtworkSI(index int) tag.ID {
+	return tag.ID{0, 0, uint64(index)}
+}
+
+// NewTrackOffset returns the TrackOffset attr of the given track.
+func NewTrackOffset(split *cue.Split) *TrackOffset {
+	return &TrackOffset{
+		StartMs:    split.Start.Milliseconds(),
+		DurationMs: split.Duration.Milliseconds(),
+		PregapMs:   split.Pregap.Milliseconds(),
+	}
+}
+
+// NewWaveformPeaks returns the WaveformPeaks attr of the given waveform.
+func NewWaveformPeaks(wave *derive.Waveform) *WaveformPeaks {
+	return &WaveformPeaks{
+		DurationMs: wave.Duration.Milliseconds(),
+		Peaks:      wave.Peaks,
+	}
+}
+
+// ThumbnailTag returns a Tag linking to the given thumbnail at the given URL (e.g. from publisher.Publisher.StoreURL).
+func ThumbnailTag(thumb *derive.Thumbnail, url string) *Tag {
+	return &Tag{
+		Use:         TagUse_Glyph,
+		URL:         url,
+		ContentType: thumb.ContentType,
+		Metric:      Metric_OrthoPixel,
+		Size_0:      float32(thumb.Width),
+		Size_1:      float32(thumb.Height),
+	}
+}
+
+// ThumbnailSI returns the SI of the ThumbnailSpec attr for a thumbnail requested to fit within the given size.
+func ThumbnailSI(width, height int) tag.ID {
+	return tag.ID{0, uint64(width), uint64(height)}
+}
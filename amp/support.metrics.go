@@ -0,0 +1,213 @@
+package amp
+
+import (
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/metrics"
+	"github.com/amp-3d/amp-sdk-go/stdlib/task"
+)
+
+// Host metrics
+//
+// HostMetrics exports a host's internals as Prometheus metrics (see package metrics and github.com/prometheus/client_golang).  A host creates one HostMetrics and plumbs it
+// through the subsystems being measured: each session's Transport is wrapped via HostMetrics.Transport, and each AppInstance it
+// issues via HostMetrics.Instrument.  Task and mailbox metrics are reported from the host's task tree and any mailboxes watched via
+// HostMetrics.Mailboxes.
+//
+//	amp_tasks{state}                   task contexts in the host's tree, by state
+//	amp_mailbox_*{mailbox}             depth, high water, delivered, and dropped counts of watched mailboxes
+//	amp_sessions_active                sessions whose transport is open
+//	amp_sessions_total                 sessions started
+//	amp_pins_open{app}                 pins currently open, by app
+//	amp_pins_total{app}                pins served, by app
+//	amp_tx_bytes_total{dir}            approximate tx bytes received ("in") and sent ("out")
+//	amp_txs_total{dir}                 txs received and sent
+//	amp_commit_seconds{app}            time an app takes to accept a commit
+//	amp_commit_errors_total{app}       commits an app rejected
+//	amp_pins_stalled                   flow controlled pins whose send window is exhausted (see FlowOpts.Metrics)
+//	amp_pin_stalls_total               times a pin's send window was exhausted
+//	amp_pin_stall_seconds              how long pins stay stalled before an ack reopens their window (or they close)
+
+// HostMetrics are the metrics of a host's internals -- concurrency safe.
+type HostMetrics struct {
+	Registry  *prometheus.Registry // where all metrics are registered
+	Mailboxes *metrics.Mailboxes   // mailboxes reported by amp_mailbox_* (see Mailboxes.Watch)
+
+	sessionsActive prometheus.Gauge
+	sessionsTotal  prometheus.Counter
+	pinsOpen       *prometheus.GaugeVec
+	pinsTotal      *prometheus.CounterVec
+	txBytes        *prometheus.CounterVec
+	txs            *prometheus.CounterVec
+	commitSecs     *prometheus.HistogramVec
+	commitErrs     *prometheus.CounterVec
+	pinsStalled    prometheus.Gauge
+	pinStalls      prometheus.Counter
+	pinStallSecs   prometheus.Histogram
+}
+
+// NewHostMetrics registers the host metrics on the given registry, reporting task states from the tree rooted at host
+// (typically the Host itself, or nil to omit amp_tasks).  Panics if any are already registered.
+func NewHostMetrics(reg *prometheus.Registry, host task.Context) *HostMetrics {
+	if host != nil {
+		reg.MustRegister(metrics.NewTasksCollector("amp_tasks", host))
+	}
+	m := &HostMetrics{
+		Registry:       reg,
+		Mailboxes:      metrics.NewMailboxes("amp_mailbox"),
+		sessionsActive: prometheus.NewGauge(prometheus.GaugeOpts{Name: "amp_sessions_active", Help: "Sessions whose transport is open"}),
+		sessionsTotal:  prometheus.NewCounter(prometheus.CounterOpts{Name: "amp_sessions_total", Help: "Sessions started"}),
+		pinsOpen:       prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "amp_pins_open", Help: "Pins currently open"}, []string{"app"}),
+		pinsTotal:      prometheus.NewCounterVec(prometheus.CounterOpts{Name: "amp_pins_total", Help: "Pins served"}, []string{"app"}),
+		txBytes:        prometheus.NewCounterVec(prometheus.CounterOpts{Name: "amp_tx_bytes_total", Help: "Approximate tx bytes received (in) and sent (out)"}, []string{"dir"}),
+		txs:            prometheus.NewCounterVec(prometheus.CounterOpts{Name: "amp_txs_total", Help: "Txs received (in) and sent (out)"}, []string{"dir"}),
+		commitSecs:     prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: "amp_commit_seconds", Help: "Time an app takes to accept a commit"}, []string{"app"}),
+		commitErrs:     prometheus.NewCounterVec(prometheus.CounterOpts{Name: "amp_commit_errors_total", Help: "Commits an app rejected"}, []string{"app"}),
+		pinsStalled:    prometheus.NewGauge(prometheus.GaugeOpts{Name: "amp_pins_stalled", Help: "Flow controlled pins whose send window is exhausted"}),
+		pinStalls:      prometheus.NewCounter(prometheus.CounterOpts{Name: "amp_pin_stalls_total", Help: "Times a pin's send window was exhausted"}),
+		pinStallSecs:   prometheus.NewHistogram(prometheus.HistogramOpts{Name: "amp_pin_stall_seconds", Help: "How long pins stay stalled"}),
+	}
+	reg.MustRegister(m.Mailboxes, m.sessionsActive, m.sessionsTotal, m.pinsOpen, m.pinsTotal, m.txBytes, m.txs,
+		m.commitSecs, m.commitErrs, m.pinsStalled, m.pinStalls, m.pinStallSecs)
+	return m
+}
+
+// Handler returns an http.Handler serving this host's metrics to scrapers.
+func (m *HostMetrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.Registry, promhttp.HandlerOpts{})
+}
+
+// Serve serves this host's metrics at addr's "/metrics" until the returned server is closed, whose Addr is the address bound.
+// This is optional -- a host with its own HTTP server can instead mount Handler().
+func (m *HostMetrics) Serve(addr string) (*http.Server, error) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", m.Handler())
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	srv.Addr = ln.Addr().String()
+	go srv.Serve(ln)
+	return srv, nil
+}
+
+// Transport wraps the given session Transport so that its txs count toward the tx metrics and it counts as an active session
+// until closed.  The returned transport is to be passed to Host.StartNewSession().
+func (m *HostMetrics) Transport(raw Transport) Transport {
+	m.sessionsActive.Add(1)
+	m.sessionsTotal.Inc()
+	return &metricsTransport{
+		Transport: raw,
+		m:         m,
+	}
+}
+
+// Instrument wraps the given instance of app so that its pins and commits are measured.
+// A host calls this when it issues an AppInstance to a HostSession, along with any other wrapping (e.g. SessionLimiter.Limit).
+func (m *HostMetrics) Instrument(app *App, inst AppInstance) AppInstance {
+	return &metricsApp{
+		AppInstance: inst,
+		m:           m,
+		app:         app.AppSpec.Canonic,
+	}
+}
+
+func (m *HostMetrics) countTx(dir string, tx *TxMsg) {
+	m.txs.WithLabelValues(dir).Inc()
+	m.txBytes.WithLabelValues(dir).Add(float64(txWireSize(tx)))
+}
+
+func (m *HostMetrics) pinStalled() {
+	m.pinsStalled.Add(1)
+	m.pinStalls.Inc()
+}
+
+func (m *HostMetrics) pinResumed(stalled time.Duration) {
+	m.pinsStalled.Add(-1)
+	m.pinStallSecs.Observe(stalled.Seconds())
+}
+
+// serve serves req via pinner, measuring the pin it returns and the time taken to accept any commit.
+func (m *HostMetrics) serve(app string, pinner Pinner, req Requester) (Pin, error) {
+	start := time.Now()
+	pin, err := pinner.ServeRequest(req)
+	if req.Request().CommitTx != nil {
+		if err != nil {
+			m.commitErrs.WithLabelValues(app).Inc()
+		} else {
+			m.commitSecs.WithLabelValues(app).Observe(time.Since(start).Seconds())
+		}
+	}
+	if err != nil || pin == nil {
+		return pin, err
+	}
+
+	m.pinsTotal.WithLabelValues(app).Inc()
+	m.pinsOpen.WithLabelValues(app).Inc()
+	go func() {
+		<-pin.Context().Done()
+		m.pinsOpen.WithLabelValues(app).Dec()
+	}()
+	return &metricsPin{
+		Pin: pin,
+		m:   m,
+		app: app,
+	}, nil
+}
+
+type metricsTransport struct {
+	Transport
+	m    *HostMetrics
+	once sync.Once
+}
+
+func (tr *metricsTransport) SendTx(tx *TxMsg) error {
+	tr.m.countTx("out", tx)
+	return tr.Transport.SendTx(tx)
+}
+
+func (tr *metricsTransport) RecvTx() (*TxMsg, error) {
+	tx, err := tr.Transport.RecvTx()
+	if err == nil {
+		tr.m.countTx("in", tx)
+	}
+	return tx, err
+}
+
+func (tr *metricsTransport) Close() error {
+	tr.once.Do(func() {
+		tr.m.sessionsActive.Add(-1)
+	})
+	return tr.Transport.Close()
+}
+
+type metricsApp struct {
+	AppInstance
+	m   *HostMetrics
+	app string
+}
+
+func (app *metricsApp) ServeRequest(req Requester) (Pin, error) {
+	return app.m.serve(app.app, app.AppInstance, req)
+}
+
+type metricsPin struct {
+	Pin
+	m   *HostMetrics
+	app string
+}
+
+func (pin *metricsPin) ServeRequest(req Requester) (Pin, error) {
+	return pin.m.serve(pin.app, pin.Pin, req)
+}
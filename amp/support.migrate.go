@@ -0,0 +1,83 @@
+package amp
+
+import (
+	"time"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/tag"
+)
+
+// MigrationLogSpec is the app attr (see AppContext.GetAppAttr) recording the migrations applied to an app's persisted state.
+var MigrationLogSpec = tag.FormSpec(AttrSpec, "migrations.MigrationLog")
+
+// Migration upgrades an app's persisted state (its app attrs and files in its LocalDataPath) from the prior schema version.
+//
+// An app lists its migrations in App.Migrations, adding one each release that changes how it persists state, so that state
+// written by an earlier release remains readable.  A Migration must not be changed or removed once released.
+type Migration struct {
+	Version uint64 // schema version this migration upgrades to (> 0 and greater than that of the prior migration)
+	Desc    string // describes the change, recorded in the app's MigrationLog
+
+	// Upgrades the app's state from the prior version -- called before the app's NewAppInstance.
+	Migrate func(ctx AppContext) error
+}
+
+// SchemaVersion returns the version of the app's state recorded in the given log (0 if no migrations have been applied).
+func (v *MigrationLog) SchemaVersion() uint64 {
+	if n := len(v.Applied); n > 0 {
+		return v.Applied[n-1].Version
+	}
+	return 0
+}
+
+func validateMigrations(app *App) error {
+	prev := uint64(0)
+	for _, mig := range app.Migrations {
+		if mig.Version <= prev {
+			return ErrCode_BadValue.Errorf("app %s: migration to version %d is out of order", app.AppSpec.Canonic, mig.Version)
+		}
+		if mig.Migrate == nil {
+			return ErrCode_BadValue.Errorf("app %s: migration to version %d has no Migrate func", app.AppSpec.Canonic, mig.Version)
+		}
+		prev = mig.Version
+	}
+	return nil
+}
+
+// RunMigrations applies each of the app's migrations newer than the version recorded in its MigrationLog, in order, recording
+// each in the log as it completes.  It returns the number applied, and if one fails, successive migrations are not applied so
+// they are attempted again the next time the app is started.
+//
+// A host calls this on the app's AppContext when it first starts the app for a session, before calling NewAppInstance.
+func RunMigrations(ctx AppContext, app *App) (int, error) {
+	if len(app.Migrations) == 0 {
+		return 0, nil
+	}
+	if err := validateMigrations(app); err != nil {
+		return 0, err
+	}
+
+	log := &MigrationLog{}
+	if err := ctx.GetAppAttr(MigrationLogSpec.ID, log); err != nil && GetErrCode(err) != ErrCode_AttrNotFound {
+		return 0, err
+	}
+
+	applied := 0
+	for _, mig := range app.Migrations {
+		if mig.Version <= log.SchemaVersion() {
+			continue
+		}
+		if err := mig.Migrate(ctx); err != nil {
+			return applied, ErrCode_DataFailure.Errorf("app %s: migration to version %d failed: %v", app.AppSpec.Canonic, mig.Version, err)
+		}
+		log.Applied = append(log.Applied, &MigrationRecord{
+			Version:   mig.Version,
+			AppliedAt: time.Now().UnixMilli(),
+			Desc:      mig.Desc,
+		})
+		if err := ctx.PutAppAttr(MigrationLogSpec.ID, log); err != nil {
+			return applied, err
+		}
+		applied++
+	}
+	return applied, nil
+}
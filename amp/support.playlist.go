@@ -0,0 +1,130 @@
+package amp
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/media/playlist"
+	"github.com/amp-3d/amp-sdk-go/stdlib/tag"
+)
+
+// Playlists
+//
+// A playlist cell holds:
+//   - its TagTab, as returned by PlaylistTab
+//   - PlaylistEntrySpec, holding a PlaylistEntry for each entry, where the SI is the entry's index (see PlaylistEntrySI)
+//
+// An app imports a playlist file via package media/playlist -- resolving its entries against the app's library via a
+// playlist.Index -- and emits it via MarshalPlaylist.  To export a playlist cell, it reads the cell back via UnmarshalPlaylist
+// and writes it via playlist.Encode or playlist.WriteFile.
+
+var PlaylistEntrySpec = tag.FormSpec(AttrSpec, "PlaylistEntry")
+
+// PlaylistEntrySI returns the SI of the PlaylistEntrySpec attr for the given entry index.
+func PlaylistEntrySI(index int) tag.ID {
+	return tag.ID{0, 0, uint64(index)}
+}
+
+// PlaylistTab returns a TagTab for the given playlist: its title with the number of entries.
+func PlaylistTab(pl *playlist.Playlist) TagTab {
+	caption := fmt.Sprintf("%d items", len(pl.Entries))
+	if len(pl.Entries) == 1 {
+		caption = "1 item"
+	}
+	return TagTab{
+		Label:   pl.Title,
+		Caption: caption,
+	}
+}
+
+// NewPlaylistEntry returns the PlaylistEntry attr of the given entry.
+func NewPlaylistEntry(entry *playlist.Entry) *PlaylistEntry {
+	return &PlaylistEntry{
+		Location:   entry.Location,
+		Title:      entry.Title,
+		Artist:     entry.Artist,
+		Album:      entry.Album,
+		DurationMs: entry.Duration.Milliseconds(),
+		Resolved:   entry.Resolved,
+	}
+}
+
+// Entry returns the playlist entry of this attr.
+func (v *PlaylistEntry) Entry() playlist.Entry {
+	return playlist.Entry{
+		Location: v.Location,
+		Title:    v.Title,
+		Artist:   v.Artist,
+		Album:    v.Album,
+		Duration: time.Duration(v.DurationMs) * time.Millisecond,
+		Resolved: v.Resolved,
+	}
+}
+
+// MarshalPlaylist marshals the attrs of the given playlist to the given cell, as described for playlist cells.
+// If the cell may already hold more entries than the playlist, the caller also deletes those (see PlaylistEntrySI).
+func MarshalPlaylist(tx *TxMsg, cellID tag.ID, pl *playlist.Playlist) error {
+	tab := PlaylistTab(pl)
+	if err := tx.MarshalUpsert(cellID, ChildTabSpec.ID, &tab); err != nil {
+		return err
+	}
+	for i := range pl.Entries {
+		op := TxOp{
+			OpCode:   TxOpCode_UpsertAttr,
+			TargetID: cellID,
+			AttrID:   PlaylistEntrySpec.ID,
+			SI:       PlaylistEntrySI(i),
+		}
+		if err := tx.MarshalOp(&op, NewPlaylistEntry(&pl.Entries[i])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UnmarshalPlaylist returns the playlist held by the given cell in the given tx (e.g. the state of a pinned playlist cell),
+// where entries are ordered by SI and later ops on an entry supersede earlier ones.
+func UnmarshalPlaylist(tx *TxMsg, cellID tag.ID) (*playlist.Playlist, error) {
+	pl := &playlist.Playlist{}
+	entries := make(map[tag.ID]*PlaylistEntry)
+	for i, op := range tx.Ops {
+		if op.TargetID != cellID {
+			continue
+		}
+		switch op.AttrID {
+		case ChildTabSpec.ID, PinnedTabSpec.ID:
+			if op.OpCode != TxOpCode_UpsertAttr {
+				continue
+			}
+			var tab TagTab
+			if err := tx.UnmarshalOpValue(i, &tab); err != nil {
+				return nil, err
+			}
+			pl.Title = tab.Label
+		case PlaylistEntrySpec.ID:
+			if op.OpCode == TxOpCode_DeleteAttr {
+				delete(entries, op.SI)
+				continue
+			}
+			entry := &PlaylistEntry{}
+			if err := tx.UnmarshalOpValue(i, entry); err != nil {
+				return nil, err
+			}
+			entries[op.SI] = entry
+		}
+	}
+
+	order := make([]tag.ID, 0, len(entries))
+	for si := range entries {
+		order = append(order, si)
+	}
+	sort.Slice(order, func(i, j int) bool {
+		return order[i].CompareTo(order[j]) < 0
+	})
+	pl.Entries = make([]playlist.Entry, len(order))
+	for i, si := range order {
+		pl.Entries[i] = entries[si].Entry()
+	}
+	return pl, nil
+}
@@ -0,0 +1,175 @@
+package amp
+
+import (
+	"sync"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/tag"
+	"github.com/amp-3d/amp-sdk-go/stdlib/task"
+)
+
+const (
+	DefaultPrefetchWorkers   = 2
+	DefaultPrefetchMaxQueued = 256
+)
+
+// PrefetchOpts configures a Prefetcher.
+type PrefetchOpts struct {
+	Workers   int // max prefetch work run at once (default DefaultPrefetchWorkers)
+	MaxQueued int // max work awaiting a worker, beyond which the oldest is dropped (default DefaultPrefetchMaxQueued)
+}
+
+// PrefetchStats reports the activity of a Prefetcher.
+type PrefetchStats struct {
+	Queued  int64 // work queued
+	Ran     int64 // work run to completion
+	Failed  int64 // work run that returned an error
+	Dropped int64 // work dropped before it ran (replaced, cancelled, evicted from a full queue, or closed)
+}
+
+// Prefetcher runs the prefetch work of an app -- work servicing a request's PrefetchHint, such as warming the children a client
+// expects to pin next -- at lower priority than serving requests:
+//   - queued work only starts while no request is being served (see Serving), so prefetching never delays a pin;
+//   - the most recently queued work starts first, since when a user scrolls quickly it is the latest hints that still matter;
+//   - queued work is replaced by later work having the same key, and once MaxQueued work awaits a worker, the oldest is dropped.
+//
+// Work that has started is not interrupted when a request is served, but its Context closes when the Prefetcher's does.
+type Prefetcher struct {
+	ctx  task.Context
+	opts PrefetchOpts
+
+	mu      sync.Mutex
+	queue   []*prefetchItem // oldest first
+	byKey   map[tag.ID]*prefetchItem
+	serving int // requests being served
+	running int // work being run
+	stats   PrefetchStats
+}
+
+type prefetchItem struct {
+	key     tag.ID
+	work    func(ctx task.Context) error
+	dropped bool
+}
+
+// NewPrefetcher returns a Prefetcher whose work runs as children of the given Context.
+func NewPrefetcher(ctx task.Context, opts PrefetchOpts) *Prefetcher {
+	if opts.Workers <= 0 {
+		opts.Workers = DefaultPrefetchWorkers
+	}
+	if opts.MaxQueued <= 0 {
+		opts.MaxQueued = DefaultPrefetchMaxQueued
+	}
+	return &Prefetcher{
+		ctx:   ctx,
+		opts:  opts,
+		byKey: make(map[tag.ID]*prefetchItem),
+	}
+}
+
+// Stats returns a snapshot of this prefetcher's activity.
+func (pf *Prefetcher) Stats() PrefetchStats {
+	pf.mu.Lock()
+	defer pf.mu.Unlock()
+	return pf.stats
+}
+
+// Queue queues the given work, replacing any queued work having the same key (e.g. the ID of the cell the work warms).
+// The returned cancel drops the work if it has not yet started (e.g. when the request that hinted it closes).
+func (pf *Prefetcher) Queue(key tag.ID, work func(ctx task.Context) error) (cancel func()) {
+	item := &prefetchItem{
+		key:  key,
+		work: work,
+	}
+	pf.mu.Lock()
+	defer pf.mu.Unlock()
+
+	pf.stats.Queued++
+	if prev := pf.byKey[key]; prev != nil {
+		pf.dropLocked(prev)
+	}
+	for len(pf.queue) >= pf.opts.MaxQueued {
+		pf.dropLocked(pf.queue[0])
+	}
+	pf.queue = append(pf.queue, item)
+	pf.byKey[key] = item
+	pf.startLocked()
+
+	return func() {
+		pf.mu.Lock()
+		defer pf.mu.Unlock()
+		if !item.dropped && pf.byKey[key] == item {
+			pf.dropLocked(item)
+		}
+	}
+}
+
+// Serving marks a request as being served, deferring queued work until the returned done is called.
+func (pf *Prefetcher) Serving() (done func()) {
+	pf.mu.Lock()
+	pf.serving++
+	pf.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			pf.mu.Lock()
+			pf.serving--
+			pf.startLocked()
+			pf.mu.Unlock()
+		})
+	}
+}
+
+func (pf *Prefetcher) dropLocked(item *prefetchItem) {
+	item.dropped = true
+	pf.stats.Dropped++
+	delete(pf.byKey, item.key)
+	for i, queued := range pf.queue {
+		if queued == item {
+			pf.queue = append(pf.queue[:i], pf.queue[i+1:]...)
+			break
+		}
+	}
+}
+
+// startLocked starts the newest queued work while no request is being served and a worker is free.
+func (pf *Prefetcher) startLocked() {
+	for pf.serving == 0 && pf.running < pf.opts.Workers && len(pf.queue) > 0 {
+		select {
+		case <-pf.ctx.Closing():
+			for len(pf.queue) > 0 {
+				pf.dropLocked(pf.queue[0])
+			}
+			return
+		default:
+		}
+
+		item := pf.queue[len(pf.queue)-1]
+		pf.queue = pf.queue[:len(pf.queue)-1]
+		delete(pf.byKey, item.key)
+		pf.running++
+
+		_, err := pf.ctx.Go("prefetch", func(ctx task.Context) {
+			err := item.work(ctx)
+
+			pf.mu.Lock()
+			defer pf.mu.Unlock()
+			pf.running--
+			pf.stats.Ran++
+			if err != nil {
+				pf.stats.Failed++
+			}
+			pf.startLocked()
+		})
+		if err != nil { // the Context is closing
+			pf.running--
+			item.dropped = true
+			pf.stats.Dropped++
+		}
+	}
+}
+
+// AttrSelection returns the attrs this hint names, or nil if it names none (meaning all attrs), as for PinRequest.AttrSelection.
+func (v *PrefetchHint) AttrSelection() AttrSelection {
+	return attrSelectionOf(v.GetAttrs())
+}
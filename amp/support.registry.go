@@ -2,9 +2,12 @@ package amp
 
 import (
 	"reflect"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/amp-3d/amp-sdk-go/stdlib/tag"
+	"github.com/amp-3d/amp-sdk-go/stdlib/task"
 )
 
 func NewRegistry() Registry {
@@ -13,6 +16,7 @@ func NewRegistry() Registry {
 		appsByTag:    make(map[tag.ID]*App),
 		elemDefs:     make(map[tag.ID]AttrDef),
 		attrDefs:     make(map[tag.ID]AttrDef),
+		watchers:     make(map[int64]func(ev AppEvent)),
 	}
 	return reg
 }
@@ -24,6 +28,10 @@ type registry struct {
 	appsByTag    map[tag.ID]*App
 	elemDefs     map[tag.ID]AttrDef
 	attrDefs     map[tag.ID]AttrDef
+
+	watchMu     sync.Mutex // serializes watcher notifications and protects the fields below
+	watchers    map[int64]func(ev AppEvent)
+	nextWatchID int64
 }
 
 func (reg *registry) RegisterPrototype(context tag.Spec, prototype ElemVal, subTags string) tag.Spec {
@@ -72,11 +80,16 @@ func (reg *registry) Import(other Registry) error {
 
 // Implements Registry
 func (reg *registry) RegisterApp(app *App) error {
+	if err := validateAppVersions(app); err != nil {
+		return err
+	}
+	if err := validateMigrations(app); err != nil {
+		return err
+	}
 	appTag := app.AppSpec.ID
 
 	reg.mu.Lock()
-	defer reg.mu.Unlock()
-
+	prev := reg.unregisterApp(appTag)
 	reg.appsByTag[appTag] = app
 
 	for _, invok := range app.Invocations {
@@ -91,10 +104,141 @@ func (reg *registry) RegisterApp(app *App) error {
 	// invoke by first component of app ID
 	_, leafName := app.AppSpec.LeafTags(1)
 	reg.appsByInvoke[leafName] = app
+	reg.mu.Unlock()
 
+	if prev != nil {
+		reg.notify(AppEvent{Op: AppDeregistered, App: prev})
+	}
+	reg.notify(AppEvent{Op: AppRegistered, App: app})
 	return nil
 }
 
+// Implements Registry
+func (reg *registry) DeregisterApp(appTag tag.ID) error {
+	reg.mu.Lock()
+	app := reg.unregisterApp(appTag)
+	reg.mu.Unlock()
+
+	if app == nil {
+		return ErrCode_AppNotFound.Errorf("app not found: %s", appTag)
+	}
+	reg.notify(AppEvent{Op: AppDeregistered, App: app})
+	return nil
+}
+
+// unregisterApp removes the given app and its invocations, returning the removed app (if any) -- reg.mu must be locked.
+func (reg *registry) unregisterApp(appTag tag.ID) *App {
+	app := reg.appsByTag[appTag]
+	if app == nil {
+		return nil
+	}
+	delete(reg.appsByTag, appTag)
+
+	// Only remove invocations still bound to this app (another app may have since claimed an alias)
+	for invok, bound := range reg.appsByInvoke {
+		if bound == app {
+			delete(reg.appsByInvoke, invok)
+		}
+	}
+	return app
+}
+
+// Implements Registry
+func (reg *registry) WatchApps(fn func(ev AppEvent)) (cancel func()) {
+	reg.watchMu.Lock()
+	watchID := reg.nextWatchID
+	reg.nextWatchID++
+	reg.watchers[watchID] = fn
+	reg.watchMu.Unlock()
+
+	return func() {
+		reg.watchMu.Lock()
+		delete(reg.watchers, watchID)
+		reg.watchMu.Unlock()
+	}
+}
+
+func (reg *registry) notify(ev AppEvent) {
+	reg.watchMu.Lock()
+	defer reg.watchMu.Unlock()
+
+	for _, fn := range reg.watchers {
+		fn(ev)
+	}
+}
+
+// Implements Registry
+func (reg *registry) Describe() RegistryInfo {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+
+	info := RegistryInfo{
+		Apps:  make([]AppInfo, 0, len(reg.appsByTag)),
+		Attrs: make([]AttrInfo, 0, len(reg.elemDefs)+len(reg.attrDefs)),
+	}
+
+	invocations := make(map[*App][]string, len(reg.appsByTag))
+	for invok, app := range reg.appsByInvoke {
+		invocations[app] = append(invocations[app], invok)
+	}
+
+	for _, app := range reg.appsByTag {
+		invoks := invocations[app]
+		sort.Strings(invoks)
+		info.Apps = append(info.Apps, AppInfo{
+			AppSpec:      app.AppSpec.Canonic,
+			AppID:        app.AppSpec.ID,
+			Desc:         app.Desc,
+			Version:      app.Version,
+			Invocations:  invoks,
+			AttrDecl:     app.AttrDecl,
+			Dependencies: app.Dependencies,
+			Requires:     app.Requires,
+		})
+	}
+	sort.Slice(info.Apps, func(i, j int) bool {
+		return info.Apps[i].AppSpec < info.Apps[j].AppSpec
+	})
+
+	for _, defs := range []map[tag.ID]AttrDef{reg.elemDefs, reg.attrDefs} {
+		for _, def := range defs {
+			attr := AttrInfo{
+				Spec: def.Canonic,
+				ID:   def.ID,
+			}
+			if def.Prototype != nil {
+				attr.ElemType = def.Prototype.ElemTypeName()
+			}
+			info.Attrs = append(info.Attrs, attr)
+		}
+	}
+	sort.Slice(info.Attrs, func(i, j int) bool {
+		return info.Attrs[i].Spec < info.Attrs[j].Spec
+	})
+
+	return info
+}
+
+// DrainAppInstance gracefully closes the given AppInstance (e.g. one whose App was deregistered) once its open Pins have closed,
+// forcing Close() if it is still open after gracePeriod (if > 0).  This does not block.
+func DrainAppInstance(inst task.Context, gracePeriod time.Duration) {
+	inst.CloseWhenIdle(time.Nanosecond)
+	if gracePeriod <= 0 {
+		return
+	}
+
+	go func() {
+		timer := time.NewTimer(gracePeriod)
+		defer timer.Stop()
+
+		select {
+		case <-inst.Done():
+		case <-timer.C:
+			inst.Close()
+		}
+	}()
+}
+
 // Implements Registry
 func (reg *registry) GetAppByTag(appTag tag.ID) (*App, error) {
 	reg.mu.RLock()
@@ -0,0 +1,692 @@
+package amp
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"sync"
+	"time"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/tag"
+)
+
+// Session resumption
+//
+// A ResumeTable allows a HostSession to outlive the Transport it was started with.
+// The host wraps each newly connected Transport via ResumeTable.Accept(), which sends the client a resume grant (a tx whose
+// first op is a ResumeAttrSpec meta attr holding the session's resume token).  Each tx the session sends is then stamped with a
+// per-request sequence number (TxMsg.Seq) and retained until acknowledged by the client.
+//
+// When the transport drops, the session is suspended (its pins remain open) for ResumeOpts.GraceWindow.  A client reconnects by
+// sending a resume request as its first tx: the grant's token followed by one op per request where op.TargetID is the request ID
+// and op.Height is the last Seq received.  The host attaches the new transport to the suspended session and replays only the txs
+// the client missed.  A client sends the same form (with no token) at any time to acknowledge txs, releasing them from the backlog.
+//
+// ReconnectingTransport implements the client side of this exchange.
+
+var ResumeAttrSpec = tag.FormSpec(MetaAttrSpec, "resume.Tag")
+
+var (
+	_ Transport = (*ResumableTransport)(nil)
+	_ Transport = (*ReconnectingTransport)(nil)
+)
+
+const (
+	DefaultResumeGrace   = 2 * time.Minute
+	DefaultResumeBacklog = 4096
+)
+
+// ResumeOpts configures a ResumeTable.
+type ResumeOpts struct {
+	GraceWindow time.Duration // how long a suspended session is retained (default DefaultResumeGrace)
+	MaxBacklog  int           // max unacknowledged txs retained per session (default DefaultResumeBacklog)
+}
+
+// ResumeTable tracks resumable sessions for a Host -- concurrency safe.
+type ResumeTable struct {
+	opts     ResumeOpts
+	mu       sync.Mutex
+	sessions map[tag.ID]*ResumableTransport
+}
+
+// NewResumeTable returns a new ResumeTable using the given options.
+func NewResumeTable(opts ResumeOpts) *ResumeTable {
+	if opts.GraceWindow <= 0 {
+		opts.GraceWindow = DefaultResumeGrace
+	}
+	if opts.MaxBacklog <= 0 {
+		opts.MaxBacklog = DefaultResumeBacklog
+	}
+	return &ResumeTable{
+		opts:     opts,
+		sessions: make(map[tag.ID]*ResumableTransport),
+	}
+}
+
+// Accept reads the first tx from a newly connected Transport.
+//
+// If the tx is a resume request for a suspended session, the transport is attached to that session, the txs the client missed are
+// replayed, and resumed is returned true -- the session carries on as before and raw is now owned by it.
+// Otherwise, a new ResumableTransport wrapping raw is returned, to be passed to Host.StartNewSession().
+func (rt *ResumeTable) Accept(raw Transport) (tr *ResumableTransport, resumed bool, err error) {
+	first, err := raw.RecvTx()
+	if err != nil {
+		return nil, false, err
+	}
+
+	resumeToken, acks, isResume := parseResumeTx(first)
+	if isResume {
+		first.ReleaseRef()
+		first = nil
+
+		rt.mu.Lock()
+		tr = rt.sessions[resumeToken]
+		rt.mu.Unlock()
+
+		if tr != nil {
+			if err = tr.attach(raw, acks); err == nil {
+				return tr, true, nil
+			}
+		}
+	}
+
+	var token [24]byte
+	if _, err = rand.Read(token[:]); err != nil {
+		return nil, false, err
+	}
+	tr = &ResumableTransport{
+		table:    rt,
+		token:    tag.ID{binary.BigEndian.Uint64(token[0:]), binary.BigEndian.Uint64(token[8:]), binary.BigEndian.Uint64(token[16:])},
+		label:    raw.Label(),
+		pending:  first,
+		pins:     make(map[tag.ID]*resumePin),
+		onAttach: make(chan struct{}),
+		closing:  make(chan struct{}),
+	}
+
+	rt.mu.Lock()
+	rt.sessions[tr.token] = tr
+	rt.mu.Unlock()
+
+	if err = tr.attach(raw, nil); err != nil {
+		tr.Close()
+		return nil, false, err
+	}
+	return tr, false, nil
+}
+
+// ResumableTransport is a Transport for a HostSession that survives the underlying transport dropping (see ResumeTable).
+type ResumableTransport struct {
+	table    *ResumeTable
+	token    tag.ID
+	label    string
+	mu       sync.Mutex // also serializes sends so that txs are sent in Seq order
+	raw      Transport  // nil while suspended
+	pending  *TxMsg     // first tx received by Accept() that is not a resume request
+	pins     map[tag.ID]*resumePin
+	backlog  []resumeEntry // unacknowledged txs in the order sent
+	scrap    []byte
+	onAttach chan struct{} // closed (and replaced) when a transport is attached
+	closing  chan struct{} // closed when closed or the grace window expires
+	closed   bool
+	expiry   *time.Timer
+}
+
+// resumePin is the sequence state of one request on a ResumableTransport.
+type resumePin struct {
+	seq     uint32 // Seq of the last tx sent
+	evicted uint32 // the highest Seq evicted from the backlog before it was acknowledged
+	lost    bool   // set when missed txs were evicted, so the client must re-pin
+	closed  bool   // set once a tx with OpStatus_Closed is sent
+}
+
+type resumeEntry struct {
+	reqID tag.ID
+	seq   uint32
+	buf   []byte // marshalled tx
+}
+
+// Token returns the resume token issued to the client for this session.
+func (tr *ResumableTransport) Token() tag.ID {
+	return tr.token
+}
+
+func (tr *ResumableTransport) Label() string {
+	return "resumable:" + tr.label
+}
+
+// Suspended returns true if the session is currently without a transport and awaiting the client to reconnect.
+func (tr *ResumableTransport) Suspended() bool {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	return tr.raw == nil && !tr.closed
+}
+
+func (tr *ResumableTransport) Close() error {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	tr.closeLocked()
+	return nil
+}
+
+func (tr *ResumableTransport) closeLocked() {
+	if tr.closed {
+		return
+	}
+	tr.closed = true
+	close(tr.closing)
+
+	tr.table.mu.Lock()
+	if tr.table.sessions[tr.token] == tr {
+		delete(tr.table.sessions, tr.token)
+	}
+	tr.table.mu.Unlock()
+
+	if tr.expiry != nil {
+		tr.expiry.Stop()
+	}
+	if tr.raw != nil {
+		tr.raw.Close()
+		tr.raw = nil
+	}
+	if tr.pending != nil {
+		tr.pending.ReleaseRef()
+		tr.pending = nil
+	}
+	tr.backlog = nil
+}
+
+// SendTx assigns tx the next Seq for its request, retains it until acknowledged, and sends it if a transport is attached.
+func (tr *ResumableTransport) SendTx(tx *TxMsg) error {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	if tr.closed {
+		tx.ReleaseRef()
+		return ErrStreamClosed
+	}
+
+	reqID := tx.RequestID()
+	pin := tr.pins[reqID]
+	if pin == nil {
+		pin = &resumePin{}
+		tr.pins[reqID] = pin
+	}
+	if pin.lost {
+		if tx.Status == OpStatus_Closed {
+			delete(tr.pins, reqID)
+		}
+		tx.ReleaseRef()
+		return nil
+	}
+	pin.seq++
+	tx.Seq = pin.seq
+	if tx.Status == OpStatus_Closed {
+		pin.closed = true
+	}
+
+	tx.MarshalToBuffer(&tr.scrap)
+	tr.backlog = append(tr.backlog, resumeEntry{
+		reqID: reqID,
+		seq:   tx.Seq,
+		buf:   append([]byte(nil), tr.scrap...),
+	})
+	if over := len(tr.backlog) - tr.table.opts.MaxBacklog; over > 0 {
+		for _, evicted := range tr.backlog[:over] {
+			if pin := tr.pins[evicted.reqID]; pin != nil {
+				pin.evicted = evicted.seq
+			}
+		}
+		tr.backlog = append(tr.backlog[:0], tr.backlog[over:]...)
+	}
+
+	if tr.raw == nil {
+		tx.ReleaseRef()
+		return nil
+	}
+	if err := tr.raw.SendTx(tx); err != nil {
+		tr.suspendLocked(tr.raw)
+	}
+	return nil
+}
+
+// RecvTx blocks until a tx is received from the client, waiting through suspensions until the session is resumed or expires.
+func (tr *ResumableTransport) RecvTx() (*TxMsg, error) {
+	for {
+		tr.mu.Lock()
+		if tr.closed {
+			tr.mu.Unlock()
+			return nil, ErrStreamClosed
+		}
+		if tx := tr.pending; tx != nil {
+			tr.pending = nil
+			tr.mu.Unlock()
+			return tx, nil
+		}
+		raw, onAttach := tr.raw, tr.onAttach
+		tr.mu.Unlock()
+
+		if raw == nil {
+			select {
+			case <-onAttach:
+			case <-tr.closing:
+			}
+			continue
+		}
+
+		tx, err := raw.RecvTx()
+		if err != nil {
+			tr.mu.Lock()
+			tr.suspendLocked(raw)
+			tr.mu.Unlock()
+			continue
+		}
+
+		if token, acks, isResume := parseResumeTx(tx); isResume && token.IsNil() {
+			tx.ReleaseRef()
+			tr.mu.Lock()
+			tr.ackLocked(acks)
+			tr.mu.Unlock()
+			continue
+		}
+		return tx, nil
+	}
+}
+
+// suspendLocked detaches the given transport (if still attached) and starts the grace window.
+func (tr *ResumableTransport) suspendLocked(raw Transport) {
+	if tr.closed || tr.raw != raw {
+		return
+	}
+	tr.raw = nil
+	raw.Close()
+	tr.startGraceLocked()
+}
+
+func (tr *ResumableTransport) startGraceLocked() {
+	grace := tr.table.opts.GraceWindow
+	if tr.expiry == nil {
+		tr.expiry = time.AfterFunc(grace, tr.expire)
+	} else {
+		tr.expiry.Reset(grace)
+	}
+}
+
+func (tr *ResumableTransport) expire() {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	if tr.raw == nil {
+		tr.closeLocked()
+	}
+}
+
+// ackLocked releases acknowledged txs from the backlog and forgets closed requests that are fully acknowledged.
+func (tr *ResumableTransport) ackLocked(acks map[tag.ID]uint32) {
+	if len(acks) == 0 {
+		return
+	}
+	kept := tr.backlog[:0]
+	for _, entry := range tr.backlog {
+		if entry.seq > acks[entry.reqID] {
+			kept = append(kept, entry)
+		}
+	}
+	for i := len(kept); i < len(tr.backlog); i++ {
+		tr.backlog[i] = resumeEntry{}
+	}
+	tr.backlog = kept
+
+	for reqID, seq := range acks {
+		if pin := tr.pins[reqID]; pin != nil && pin.closed && seq >= pin.seq {
+			delete(tr.pins, reqID)
+		}
+	}
+}
+
+// attach makes raw this session's transport, sending the resume grant followed by the txs the client has not acknowledged.
+func (tr *ResumableTransport) attach(raw Transport, acks map[tag.ID]uint32) error {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	if tr.closed {
+		return ErrCode_SessionExpired.Error("session no longer resumable")
+	}
+	if tr.raw != nil {
+		tr.raw.Close()
+		tr.raw = nil
+	}
+	if tr.expiry != nil {
+		tr.expiry.Stop()
+	}
+	tr.ackLocked(acks)
+
+	err := raw.SendTx(MarshalResumeTx(tr.token, nil))
+
+	// Requests whose missed txs were evicted can't be resumed, so tell the client they are closed.
+	for reqID, pin := range tr.pins {
+		if err != nil {
+			break
+		}
+		if pin.lost || pin.evicted <= acks[reqID] {
+			continue
+		}
+		pin.lost = true
+
+		var reset *TxMsg
+		if reset, err = MarshalErrTx(reqID, &Err{
+			Code:      ErrCode_RequestClosed,
+			Msg:       "missed txs no longer available; re-pin to continue",
+			Retryable: true,
+		}); err == nil {
+			err = raw.SendTx(reset)
+		}
+	}
+
+	for _, entry := range tr.backlog {
+		if err != nil {
+			break
+		}
+		if pin := tr.pins[entry.reqID]; entry.seq <= acks[entry.reqID] || pin == nil || pin.lost {
+			continue
+		}
+		var tx *TxMsg
+		if tx, err = ReadTxMsg(bytes.NewReader(entry.buf)); err == nil {
+			err = raw.SendTx(tx)
+		}
+	}
+
+	if err != nil {
+		raw.Close()
+		tr.startGraceLocked()
+		return err
+	}
+
+	tr.raw = raw
+	close(tr.onAttach)
+	tr.onAttach = make(chan struct{})
+	return nil
+}
+
+// MarshalResumeTx returns a resume tx: a resume grant (host to client) or resume request (client to host) if token is set,
+// or an acknowledgement (client to host) if not.  For each entry in acks, the txs up to and including that Seq are acknowledged.
+func MarshalResumeTx(token tag.ID, acks map[tag.ID]uint32) *TxMsg {
+	tx := NewTxMsg(true)
+
+	tokenTag := &Tag{}
+	tokenTag.SetTagID(token)
+	tokenBuf, _ := tokenTag.MarshalToStore(nil)
+	tx.MarshalOpWithBuf(&TxOp{
+		OpCode: TxOpCode_MetaAttr,
+		AttrID: ResumeAttrSpec.ID,
+	}, tokenBuf)
+
+	for reqID, seq := range acks {
+		tx.MarshalOpWithBuf(&TxOp{
+			OpCode:   TxOpCode_MetaAttr,
+			AttrID:   ResumeAttrSpec.ID,
+			TargetID: reqID,
+			Height:   uint64(seq),
+		}, nil)
+	}
+	return tx
+}
+
+// parseResumeTx returns the token and acknowledgements of the given tx if it was formed by MarshalResumeTx.
+func parseResumeTx(tx *TxMsg) (token tag.ID, acks map[tag.ID]uint32, isResume bool) {
+	if len(tx.Ops) == 0 || tx.Ops[0].OpCode != TxOpCode_MetaAttr || tx.Ops[0].AttrID != ResumeAttrSpec.ID {
+		return
+	}
+	tokenTag := &Tag{}
+	if err := tx.UnmarshalOpValue(0, tokenTag); err != nil {
+		return
+	}
+	acks = make(map[tag.ID]uint32, len(tx.Ops)-1)
+	for _, op := range tx.Ops[1:] {
+		if op.AttrID == ResumeAttrSpec.ID {
+			acks[op.TargetID] = uint32(op.Height)
+		}
+	}
+	return tokenTag.TagID(), acks, true
+}
+
+// ReconnectOpts configures a ReconnectingTransport.
+type ReconnectOpts struct {
+	Dial        func() (Transport, error) // connects to the host
+	MinBackoff  time.Duration             // delay after the first failed redial, doubling after each (default 250ms)
+	MaxBackoff  time.Duration             // max delay between redials (default 10s)
+	GiveUpAfter time.Duration             // how long to keep redialing before failing (default DefaultResumeGrace)
+	AckEvery    int                       // number of txs received between acknowledgements sent to the host (default 256)
+}
+
+// ReconnectingTransport is a client Transport that transparently redials and resumes its session with the host when the
+// underlying transport drops (see ResumeTable).  Txs already received are not delivered again.
+//
+// If the host is unable to resume the session (e.g. its grace window expired), RecvTx returns ErrCode_SessionExpired once, after
+// which the transport continues on the new session the host started, so the client should log in and re-pin.
+type ReconnectingTransport struct {
+	opts      ReconnectOpts
+	closing   chan struct{}
+	closeOnce sync.Once
+	mu        sync.Mutex // protects the fields below and serializes redialing
+	raw       Transport
+	token     tag.ID
+	resuming  bool                // set after redialing until the host's resume grant is received
+	lastSeq   map[tag.ID]uint32   // Seq of the last tx received for each request
+	closedReq map[tag.ID]struct{} // closed requests, forgotten once acknowledged
+	unacked   int
+}
+
+// DialReconnecting dials the host and returns a ReconnectingTransport to be used in place of the dialed transport.
+func DialReconnecting(opts ReconnectOpts) (*ReconnectingTransport, error) {
+	if opts.MinBackoff <= 0 {
+		opts.MinBackoff = 250 * time.Millisecond
+	}
+	if opts.MaxBackoff < opts.MinBackoff {
+		opts.MaxBackoff = 10 * time.Second
+	}
+	if opts.GiveUpAfter <= 0 {
+		opts.GiveUpAfter = DefaultResumeGrace
+	}
+	if opts.AckEvery <= 0 {
+		opts.AckEvery = 256
+	}
+
+	raw, err := opts.Dial()
+	if err != nil {
+		return nil, err
+	}
+	return &ReconnectingTransport{
+		opts:      opts,
+		closing:   make(chan struct{}),
+		raw:       raw,
+		lastSeq:   make(map[tag.ID]uint32),
+		closedReq: make(map[tag.ID]struct{}),
+	}, nil
+}
+
+func (tr *ReconnectingTransport) Label() string {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	return "reconnecting:" + tr.raw.Label()
+}
+
+func (tr *ReconnectingTransport) Close() error {
+	tr.closeOnce.Do(func() {
+		close(tr.closing)
+	})
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	return tr.raw.Close()
+}
+
+func (tr *ReconnectingTransport) isClosed() bool {
+	select {
+	case <-tr.closing:
+		return true
+	default:
+		return false
+	}
+}
+
+// SendTx sends tx to the host, redialing and retrying once if the transport has dropped.
+func (tr *ReconnectingTransport) SendTx(tx *TxMsg) error {
+	tr.mu.Lock()
+	raw := tr.raw
+	tr.mu.Unlock()
+
+	tx.AddRef()
+	err := raw.SendTx(tx)
+	if err != nil && !tr.isClosed() {
+		if err = tr.reconnect(raw); err == nil {
+			tr.mu.Lock()
+			raw = tr.raw
+			tr.mu.Unlock()
+			tx.AddRef()
+			err = raw.SendTx(tx)
+		}
+	}
+	tx.ReleaseRef()
+	return err
+}
+
+// RecvTx blocks until the next tx not already received arrives from the host, redialing as needed.
+func (tr *ReconnectingTransport) RecvTx() (*TxMsg, error) {
+	for {
+		if tr.isClosed() {
+			return nil, ErrStreamClosed
+		}
+		tr.mu.Lock()
+		raw := tr.raw
+		tr.mu.Unlock()
+
+		tx, err := raw.RecvTx()
+		if err != nil {
+			if tr.isClosed() {
+				return nil, ErrStreamClosed
+			}
+			if err = tr.reconnect(raw); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if token, _, isResume := parseResumeTx(tx); isResume {
+			tx.ReleaseRef()
+			if err = tr.onGrant(token); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		reqID := tx.RequestID()
+		tr.mu.Lock()
+		if tx.Seq == 0 {
+			if tx.Status == OpStatus_Closed {
+				delete(tr.lastSeq, reqID)
+			}
+		} else if tx.Seq <= tr.lastSeq[reqID] {
+			tr.mu.Unlock()
+			tx.ReleaseRef()
+			continue
+		} else {
+			tr.lastSeq[reqID] = tx.Seq
+			if tx.Status == OpStatus_Closed {
+				tr.closedReq[reqID] = struct{}{}
+			}
+			tr.unacked++
+		}
+		var ack *TxMsg
+		var acked map[tag.ID]uint32
+		if tr.unacked >= tr.opts.AckEvery {
+			tr.unacked = 0
+			acked = tr.acksLocked()
+			ack = MarshalResumeTx(tag.ID{}, acked)
+		}
+		tr.mu.Unlock()
+
+		if ack != nil && raw.SendTx(ack) == nil {
+			tr.forgetClosed(acked)
+		}
+		return tx, nil
+	}
+}
+
+func (tr *ReconnectingTransport) acksLocked() map[tag.ID]uint32 {
+	acks := make(map[tag.ID]uint32, len(tr.lastSeq))
+	for reqID, seq := range tr.lastSeq {
+		acks[reqID] = seq
+	}
+	return acks
+}
+
+// forgetClosed drops closed requests that the host has been sent an acknowledgement for.
+func (tr *ReconnectingTransport) forgetClosed(acked map[tag.ID]uint32) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	for reqID := range tr.closedReq {
+		if _, isAcked := acked[reqID]; isAcked {
+			delete(tr.closedReq, reqID)
+			delete(tr.lastSeq, reqID)
+		}
+	}
+}
+
+func (tr *ReconnectingTransport) onGrant(token tag.ID) error {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	resuming := tr.resuming
+	tr.resuming = false
+	if !resuming || token == tr.token {
+		tr.token = token
+		return nil
+	}
+
+	tr.token = token
+	tr.lastSeq = make(map[tag.ID]uint32)
+	tr.closedReq = make(map[tag.ID]struct{})
+	tr.unacked = 0
+	return ErrCode_SessionExpired.Error("session could not be resumed; a new session was started")
+}
+
+// reconnect redials the host if failed is still the current transport and sends it a resume request.
+func (tr *ReconnectingTransport) reconnect(failed Transport) error {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	if tr.raw != failed {
+		return nil // already reconnected
+	}
+	failed.Close()
+
+	giveUp := time.Now().Add(tr.opts.GiveUpAfter)
+	backoff := tr.opts.MinBackoff
+	for {
+		raw, err := tr.opts.Dial()
+		if err == nil {
+			if err = raw.SendTx(MarshalResumeTx(tr.token, tr.acksLocked())); err == nil {
+				tr.raw = raw
+				tr.resuming = true
+				tr.unacked = 0
+				return nil
+			}
+			raw.Close()
+		}
+
+		if time.Now().Add(backoff).After(giveUp) {
+			return ErrCode_NotConnected.Errorf("failed to reconnect: %v", err)
+		}
+		select {
+		case <-time.After(backoff):
+		case <-tr.closing:
+			return ErrStreamClosed
+		}
+		backoff *= 2
+		if backoff > tr.opts.MaxBackoff {
+			backoff = tr.opts.MaxBackoff
+		}
+	}
+}
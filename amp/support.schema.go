@@ -0,0 +1,288 @@
+package amp
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/tag"
+)
+
+// Schema declares the attrs of a cell archetype (e.g. a playlist or a track) so that commits to cells of that archetype can be
+// validated before an app applies them, rather than malformed values surfacing later in app code.
+//
+// An app declares schemas by implementing SchemaProvider, and a host validates every request's CommitTx against them by wrapping
+// the app's instances with ValidateCommits.  A commit violating a schema is rejected with ErrCode_BadSchema, listing each
+// violation (see SchemaViolation).
+type Schema struct {
+	Archetype tag.Spec     // identifies the cell archetype this describes
+	Attrs     []AttrSchema // attrs a cell of this archetype may have
+	Strict    bool         // if set, a commit may only upsert attrs listed in Attrs
+}
+
+// AttrSchema declares an attr of a cell archetype.
+type AttrSchema struct {
+	Attr     tag.Spec     // attr spec (its ID is matched against TxOp.AttrID)
+	Required bool         // if set, a cell must have this attr (at SI tag.Nil): a commit may not delete it or create a cell without it
+	TypeName string       // if set, the ElemTypeName the attr's values must have
+	Fields   []FieldRange // constraints on fields of the attr's values
+
+	// If set, called with each committed value (after the above pass), returning a reason the value is invalid.
+	Check func(val ElemVal) error
+}
+
+// FieldRange constrains an exported field of an attr value.
+//
+// A numeric field must be within [Min, Max], and the length of a string, bytes, or slice field must be within [MinLen, MaxLen],
+// where a zero Max or MaxLen means there is no upper bound.
+type FieldRange struct {
+	Field          string
+	Min, Max       float64
+	MinLen, MaxLen int
+}
+
+// SchemaViolation is a way a commit fails to conform to the schema of the cell it targets.
+type SchemaViolation struct {
+	CellID tag.ID
+	AttrID tag.ID
+	Attr   string // attr spec, if declared by the schema
+	Reason string
+}
+
+func (v SchemaViolation) String() string {
+	attr := v.Attr
+	if attr == "" {
+		attr = v.AttrID.String()
+	}
+	return fmt.Sprintf("cell %s attr %s: %s", v.CellID, attr, v.Reason)
+}
+
+// SchemaError returns the given violations as an ErrCode_BadSchema error, or nil if there are none.
+func SchemaError(violations []SchemaViolation) error {
+	if len(violations) == 0 {
+		return nil
+	}
+	msgs := make([]string, len(violations))
+	for i, v := range violations {
+		msgs[i] = v.String()
+	}
+	return ErrCode_BadSchema.Errorf("commit violates schema: %s", strings.Join(msgs, "; "))
+}
+
+// SchemaProvider is implemented by an AppInstance (or a Pin it returns) that declares schemas for its cells.
+type SchemaProvider interface {
+
+	// Returns the schema of the given cell, or nil if commits to it are not validated.
+	CellSchema(cellID tag.ID) *Schema
+
+	// Returns the current value of the given cell attr item, or nil if it has none (see AttrReader).
+	CurrentAttr(cellID, attrID, SI tag.ID) ElemVal
+}
+
+// Validate returns the violations of this schema by the ops of the given commit that target the given cell.
+// reg decodes committed values, and current returns the cell's attr values prior to the commit.
+func (schema *Schema) Validate(reg Registry, cellID tag.ID, commit *TxMsg, current AttrReader) []SchemaViolation {
+	var violations []SchemaViolation
+	violate := func(attrID tag.ID, attr *AttrSchema, reason string, args ...any) {
+		v := SchemaViolation{
+			CellID: cellID,
+			AttrID: attrID,
+			Reason: fmt.Sprintf(reason, args...),
+		}
+		if attr != nil {
+			v.Attr = attr.Attr.Canonic
+		}
+		violations = append(violations, v)
+	}
+
+	set := make(map[tag.ID]bool) // attrs of SI tag.Nil the commit leaves set (true) or deleted (false)
+	for i, op := range commit.Ops {
+		if op.TargetID != cellID {
+			continue
+		}
+		attr := schema.attr(op.AttrID)
+		switch op.OpCode {
+		case TxOpCode_UpsertAttr:
+			if op.SI.IsNil() {
+				set[op.AttrID] = true
+			}
+			if attr == nil {
+				if schema.Strict {
+					violate(op.AttrID, nil, "attr not declared by %s", schema.Archetype.Canonic)
+				}
+				continue
+			}
+			val, err := reg.NewAttrElem(op.AttrID)
+			if err == nil {
+				err = commit.UnmarshalOpValue(i, val)
+			}
+			if err != nil {
+				violate(op.AttrID, attr, "malformed value: %v", err)
+				continue
+			}
+			if reason := attr.check(val); reason != "" {
+				violate(op.AttrID, attr, "%s", reason)
+			}
+		case TxOpCode_DeleteAttr:
+			if op.SI.IsNil() {
+				set[op.AttrID] = false
+			}
+		}
+	}
+
+	for i := range schema.Attrs {
+		attr := &schema.Attrs[i]
+		if !attr.Required {
+			continue
+		}
+		isSet, committed := set[attr.Attr.ID]
+		if !committed {
+			isSet = current != nil && current(cellID, attr.Attr.ID, tag.Nil) != nil
+		}
+		if !isSet {
+			violate(attr.Attr.ID, attr, "required attr missing")
+		}
+	}
+	return violations
+}
+
+func (schema *Schema) attr(attrID tag.ID) *AttrSchema {
+	for i := range schema.Attrs {
+		if schema.Attrs[i].Attr.ID == attrID {
+			return &schema.Attrs[i]
+		}
+	}
+	return nil
+}
+
+// check returns why the given value violates this attr's constraints, or "" if it does not.
+func (attr *AttrSchema) check(val ElemVal) string {
+	if attr.TypeName != "" && val.ElemTypeName() != attr.TypeName {
+		return fmt.Sprintf("expected %s value, got %s", attr.TypeName, val.ElemTypeName())
+	}
+	if len(attr.Fields) > 0 {
+		rv := reflect.ValueOf(val)
+		if rv.Kind() == reflect.Pointer {
+			rv = rv.Elem()
+		}
+		for _, field := range attr.Fields {
+			if reason := field.check(rv); reason != "" {
+				return reason
+			}
+		}
+	}
+	if attr.Check != nil {
+		if err := attr.Check(val); err != nil {
+			return err.Error()
+		}
+	}
+	return ""
+}
+
+func (field *FieldRange) check(rv reflect.Value) string {
+	if rv.Kind() != reflect.Struct {
+		return fmt.Sprintf("value has no field %s", field.Field)
+	}
+	fv := rv.FieldByName(field.Field)
+	if !fv.IsValid() {
+		return fmt.Sprintf("value has no field %s", field.Field)
+	}
+
+	switch fv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return field.checkNum(float64(fv.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return field.checkNum(float64(fv.Uint()))
+	case reflect.Float32, reflect.Float64:
+		return field.checkNum(fv.Float())
+	case reflect.String, reflect.Slice:
+		if n := fv.Len(); n < field.MinLen || (field.MaxLen > 0 && n > field.MaxLen) {
+			return fmt.Sprintf("%s length %d outside [%d, %d]", field.Field, n, field.MinLen, field.MaxLen)
+		}
+		return ""
+	}
+	return fmt.Sprintf("field %s has no range", field.Field)
+}
+
+func (field *FieldRange) checkNum(x float64) string {
+	if x < field.Min || (field.Max != 0 && x > field.Max) {
+		return fmt.Sprintf("%s %v outside [%v, %v]", field.Field, x, field.Min, field.Max)
+	}
+	return ""
+}
+
+// ValidateCommits wraps the given AppInstance so that each request's CommitTx is validated against the schemas the app (or the
+// pin serving a child request) declares (see SchemaProvider), rejecting the request before it is served if the commit violates one.
+//
+// A host calls this when it issues an AppInstance to a HostSession, before wrapping it further (e.g. SelectAttrs).
+// An instance that does not implement SchemaProvider is returned as is.
+func ValidateCommits(inst AppInstance) AppInstance {
+	provider, ok := inst.(SchemaProvider)
+	if !ok {
+		return inst
+	}
+	return &validatingApp{
+		AppInstance: inst,
+		provider:    provider,
+	}
+}
+
+// validateCommit returns the schema violations of the given request's CommitTx as an error.
+func validateCommit(reg Registry, provider SchemaProvider, req *Request) error {
+	commit := req.CommitTx
+	if commit == nil {
+		return nil
+	}
+	var violations []SchemaViolation
+	validated := make(map[tag.ID]struct{})
+	for _, op := range commit.Ops {
+		if _, done := validated[op.TargetID]; done || op.TargetID.IsNil() {
+			continue
+		}
+		validated[op.TargetID] = struct{}{}
+		if schema := provider.CellSchema(op.TargetID); schema != nil {
+			violations = append(violations, schema.Validate(reg, op.TargetID, commit, provider.CurrentAttr)...)
+		}
+	}
+	return SchemaError(violations)
+}
+
+type validatingApp struct {
+	AppInstance
+	provider SchemaProvider
+}
+
+func (app *validatingApp) ServeRequest(req Requester) (Pin, error) {
+	if err := validateCommit(app.Session(), app.provider, req.Request()); err != nil {
+		return nil, err
+	}
+	return app.wrapPin(app.AppInstance.ServeRequest(req))
+}
+
+func (app *validatingApp) wrapPin(pin Pin, err error) (Pin, error) {
+	if err != nil || pin == nil {
+		return pin, err
+	}
+	provider, ok := pin.(SchemaProvider)
+	if !ok {
+		provider = app.provider
+	}
+	return &validatingPin{
+		Pin:      pin,
+		app:      app,
+		provider: provider,
+	}, nil
+}
+
+type validatingPin struct {
+	Pin
+	app      *validatingApp
+	provider SchemaProvider
+}
+
+func (pin *validatingPin) ServeRequest(req Requester) (Pin, error) {
+	if err := validateCommit(pin.app.Session(), pin.provider, req.Request()); err != nil {
+		return nil, err
+	}
+	return pin.app.wrapPin(pin.Pin.ServeRequest(req))
+}
@@ -0,0 +1,409 @@
+package amp
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/tag"
+)
+
+// Version is a parsed semantic version (see https://semver.org), e.g. "v1.4.2" or "2.0.0-beta.1".
+// Build metadata (a "+" suffix) is ignored.
+type Version struct {
+	Major, Minor, Patch int
+	Pre                 string // pre-release identifiers (e.g. "beta.1"), or "" for a release
+}
+
+// ParseVersion parses a semantic version, with an optional leading 'v'.  Omitted minor and patch numbers default to 0.
+func ParseVersion(str string) (Version, error) {
+	v, parts, err := parseVersionParts(str)
+	if err == nil && parts < 0 {
+		err = ErrCode_BadValue.Errorf("invalid version %q: wildcards not allowed", str)
+	}
+	return v, err
+}
+
+// parseVersionParts parses a version that may be partial (e.g. "1.2") or have a trailing wildcard (e.g. "1.x"), returning
+// the number of numeric parts given (or -(parts+1) if a wildcard was given).
+func parseVersionParts(str string) (v Version, parts int, err error) {
+	s := strings.TrimPrefix(strings.TrimSpace(str), "v")
+	if i := strings.IndexByte(s, '+'); i >= 0 {
+		s = s[:i]
+	}
+	if i := strings.IndexByte(s, '-'); i >= 0 {
+		v.Pre = s[i+1:]
+		s = s[:i]
+		if v.Pre == "" {
+			return v, 0, ErrCode_BadValue.Errorf("invalid version %q", str)
+		}
+	}
+
+	nums := [3]*int{&v.Major, &v.Minor, &v.Patch}
+	fields := strings.Split(s, ".")
+	if len(fields) > 3 || s == "" {
+		return v, 0, ErrCode_BadValue.Errorf("invalid version %q", str)
+	}
+	for i, field := range fields {
+		if field == "x" || field == "X" || field == "*" {
+			if i != len(fields)-1 || v.Pre != "" {
+				return v, 0, ErrCode_BadValue.Errorf("invalid version %q", str)
+			}
+			return v, -(i + 1), nil
+		}
+		n, convErr := strconv.Atoi(field)
+		if convErr != nil || n < 0 {
+			return v, 0, ErrCode_BadValue.Errorf("invalid version %q", str)
+		}
+		*nums[i] = n
+	}
+	return v, len(fields), nil
+}
+
+func (v Version) String() string {
+	str := fmt.Sprintf("v%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Pre != "" {
+		str += "-" + v.Pre
+	}
+	return str
+}
+
+// Compare returns -1, 0, or 1 if v is less than, equal to, or greater than other, following semver precedence.
+func (v Version) Compare(other Version) int {
+	if c := compareInts(v.Major, other.Major); c != 0 {
+		return c
+	}
+	if c := compareInts(v.Minor, other.Minor); c != 0 {
+		return c
+	}
+	if c := compareInts(v.Patch, other.Patch); c != 0 {
+		return c
+	}
+
+	// A release has greater precedence than any of its pre-releases
+	switch {
+	case v.Pre == other.Pre:
+		return 0
+	case v.Pre == "":
+		return 1
+	case other.Pre == "":
+		return -1
+	}
+
+	a, b := strings.Split(v.Pre, "."), strings.Split(other.Pre, ".")
+	for i := 0; i < len(a) && i < len(b); i++ {
+		na, errA := strconv.Atoi(a[i])
+		nb, errB := strconv.Atoi(b[i])
+		var c int
+		switch {
+		case errA == nil && errB == nil:
+			c = compareInts(na, nb)
+		case errA == nil: // numeric identifiers have lower precedence
+			c = -1
+		case errB == nil:
+			c = 1
+		default:
+			c = strings.Compare(a[i], b[i])
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return compareInts(len(a), len(b))
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// VersionConstraint is a parsed version constraint (see ParseConstraint).
+type VersionConstraint struct {
+	expr string
+	any  [][]versionBound // satisfied if all of the bounds in any of the alternatives are satisfied
+}
+
+type versionBound struct {
+	op string // one of "=", "!=", ">", ">=", "<", "<="
+	v  Version
+}
+
+func (b versionBound) allows(v Version) bool {
+	c := v.Compare(b.v)
+	switch b.op {
+	case "=":
+		return c == 0
+	case "!=":
+		return c != 0
+	case ">":
+		return c > 0
+	case ">=":
+		return c >= 0
+	case "<":
+		return c < 0
+	default: // "<="
+		return c <= 0
+	}
+}
+
+// ParseConstraint parses a version constraint, in the common form used by npm and Cargo:
+//
+//	"" or "*"           any version
+//	"1.2.3" or "=1.2.3" exactly 1.2.3
+//	">=1.2 <2"          all space (or comma) separated comparators (=, !=, >, >=, <, <=) must be satisfied
+//	"^1.2.3"            >=1.2.3 <2.0.0 (or <0.3.0 for ^0.2.3, and <0.0.4 for ^0.0.3)
+//	"~1.2.3"            >=1.2.3 <1.3.0
+//	"1.x" or "1"        >=1.0.0 <2.0.0
+//	"^1.2 || ^2"        either alternative
+func ParseConstraint(expr string) (VersionConstraint, error) {
+	vc := VersionConstraint{
+		expr: strings.TrimSpace(expr),
+	}
+	for _, alt := range strings.Split(vc.expr, "||") {
+		var bounds []versionBound
+		for _, term := range strings.FieldsFunc(alt, func(r rune) bool { return r == ' ' || r == ',' }) {
+			termBounds, err := parseConstraintTerm(term)
+			if err != nil {
+				return vc, ErrCode_BadValue.Errorf("invalid version constraint %q: %v", expr, err)
+			}
+			bounds = append(bounds, termBounds...)
+		}
+		if len(bounds) == 0 && strings.TrimSpace(alt) == "" && vc.expr != "" {
+			return vc, ErrCode_BadValue.Errorf("invalid version constraint %q", expr)
+		}
+		vc.any = append(vc.any, bounds)
+	}
+	return vc, nil
+}
+
+func parseConstraintTerm(term string) ([]versionBound, error) {
+	op := ""
+	for _, prefix := range []string{">=", "<=", "!=", "==", ">", "<", "=", "^", "~"} {
+		if strings.HasPrefix(term, prefix) {
+			op, term = prefix, term[len(prefix):]
+			break
+		}
+	}
+	if term == "*" || term == "x" || term == "X" {
+		if op != "" {
+			return nil, fmt.Errorf("unexpected %q", op)
+		}
+		return nil, nil
+	}
+
+	v, parts, err := parseVersionParts(term)
+	if err != nil {
+		return nil, err
+	}
+	wildcard := parts < 0
+	if wildcard {
+		parts = -parts - 1
+	}
+
+	// upper returns the first version excluded by a partial version (e.g. 1.2 => 1.3.0)
+	upper := func(parts int) Version {
+		switch parts {
+		case 1:
+			return Version{Major: v.Major + 1}
+		case 2:
+			return Version{Major: v.Major, Minor: v.Minor + 1}
+		}
+		return Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch + 1}
+	}
+
+	switch op {
+	case "", "=", "==":
+		if parts < 3 {
+			return []versionBound{{">=", v}, {"<", upper(parts)}}, nil
+		}
+		return []versionBound{{"=", v}}, nil
+	case "^":
+		switch {
+		case v.Major > 0 || parts == 1:
+			return []versionBound{{">=", v}, {"<", upper(1)}}, nil
+		case v.Minor > 0 || parts == 2:
+			return []versionBound{{">=", v}, {"<", upper(2)}}, nil
+		}
+		return []versionBound{{">=", v}, {"<", upper(3)}}, nil
+	case "~":
+		if parts == 1 {
+			return []versionBound{{">=", v}, {"<", upper(1)}}, nil
+		}
+		return []versionBound{{">=", v}, {"<", upper(2)}}, nil
+	}
+
+	if wildcard {
+		return nil, fmt.Errorf("wildcard not allowed with %q", op)
+	}
+	return []versionBound{{op, v}}, nil
+}
+
+// Allows returns true if the given version satisfies this constraint.
+// As with npm, a pre-release version is only allowed by a comparator naming a pre-release of the same major.minor.patch,
+// so "^1.2.3" allows "1.4.0" but not "1.4.0-beta".
+func (vc VersionConstraint) Allows(v Version) bool {
+	for _, bounds := range vc.any {
+		if v.Pre != "" && len(bounds) > 0 && !namesPreRelease(bounds, v) {
+			continue
+		}
+		allowed := true
+		for _, b := range bounds {
+			if !b.allows(v) {
+				allowed = false
+				break
+			}
+		}
+		if allowed {
+			return true
+		}
+	}
+	return len(vc.any) == 0
+}
+
+// namesPreRelease returns true if any of the given bounds is a pre-release of the same major.minor.patch as v.
+func namesPreRelease(bounds []versionBound, v Version) bool {
+	for _, b := range bounds {
+		if b.v.Pre != "" && b.v.Major == v.Major && b.v.Minor == v.Minor && b.v.Patch == v.Patch {
+			return true
+		}
+	}
+	return false
+}
+
+// allowsAny returns true if this constraint is satisfied by any version, including an unversioned app.
+func (vc VersionConstraint) allowsAny() bool {
+	for _, bounds := range vc.any {
+		if len(bounds) == 0 {
+			return true
+		}
+	}
+	return len(vc.any) == 0
+}
+
+func (vc VersionConstraint) String() string {
+	if vc.expr == "" {
+		return "*"
+	}
+	return vc.expr
+}
+
+// validateAppVersions checks the syntax of the given app's version and version constraints.
+func validateAppVersions(app *App) error {
+	if app.Version != "" {
+		if _, err := ParseVersion(app.Version); err != nil {
+			return ErrCode_BadValue.Errorf("app %s: %v", app.AppSpec.Canonic, err)
+		}
+	}
+	for _, dep := range app.Requires {
+		if _, err := ParseConstraint(dep.Constraint); err != nil {
+			return ErrCode_BadValue.Errorf("app %s: %v", app.AppSpec.Canonic, err)
+		}
+	}
+	return nil
+}
+
+// Implements Registry
+func (reg *registry) ResolveApps() (*AppGraph, error) {
+	reg.mu.RLock()
+	apps := make([]*App, 0, len(reg.appsByTag))
+	for _, app := range reg.appsByTag {
+		apps = append(apps, app)
+	}
+	byTag := make(map[tag.ID]*App, len(reg.appsByTag))
+	for appTag, app := range reg.appsByTag {
+		byTag[appTag] = app
+	}
+	reg.mu.RUnlock()
+
+	// Resolve in a stable order so that errors and the resulting graph are deterministic
+	sort.Slice(apps, func(i, j int) bool {
+		return apps[i].AppSpec.Canonic < apps[j].AppSpec.Canonic
+	})
+
+	var problems []string
+	for _, app := range apps {
+		for _, dep := range app.Requires {
+			required := byTag[dep.AppID]
+			if required == nil {
+				problems = append(problems, fmt.Sprintf("%s requires app %s, which is not registered", app.AppSpec.Canonic, dep.AppID))
+				continue
+			}
+			vc, _ := ParseConstraint(dep.Constraint) // validated by RegisterApp
+			if required.Version == "" {
+				if !vc.allowsAny() {
+					problems = append(problems, fmt.Sprintf("%s requires %s %s, but %s declares no version", app.AppSpec.Canonic, required.AppSpec.Canonic, vc, required.AppSpec.Canonic))
+				}
+				continue
+			}
+			v, _ := ParseVersion(required.Version)
+			if !vc.Allows(v) {
+				problems = append(problems, fmt.Sprintf("%s requires %s %s, but %s is registered", app.AppSpec.Canonic, required.AppSpec.Canonic, vc, v))
+			}
+		}
+	}
+	if len(problems) > 0 {
+		return nil, ErrCode_BadValue.Errorf("unresolved app dependencies:\n  %s", strings.Join(problems, "\n  "))
+	}
+
+	// Order apps so each follows all the apps it requires, detecting cycles along the way
+	graph := &AppGraph{
+		Nodes: make([]*AppNode, 0, len(apps)),
+	}
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := make(map[*App]int, len(apps))
+	var path []string
+	var visit func(app *App) error
+	visit = func(app *App) error {
+		switch state[app] {
+		case visited:
+			return nil
+		case visiting:
+			return ErrCode_BadValue.Errorf("app dependency cycle: %s -> %s", strings.Join(path, " -> "), app.AppSpec.Canonic)
+		}
+		state[app] = visiting
+		path = append(path, app.AppSpec.Canonic)
+
+		node := &AppNode{
+			App:     app,
+			AppSpec: app.AppSpec.Canonic,
+			AppID:   app.AppSpec.ID,
+			Version: app.Version,
+		}
+		for _, dep := range app.Requires {
+			if err := visit(byTag[dep.AppID]); err != nil {
+				return err
+			}
+			node.Requires = append(node.Requires, dep.AppID)
+		}
+
+		path = path[:len(path)-1]
+		state[app] = visited
+		graph.Nodes = append(graph.Nodes, node)
+		return nil
+	}
+	for _, app := range apps {
+		if err := visit(app); err != nil {
+			return nil, err
+		}
+	}
+	return graph, nil
+}
+
+// Node returns the node for the given app, or nil if not present.
+func (graph *AppGraph) Node(appID tag.ID) *AppNode {
+	for _, node := range graph.Nodes {
+		if node.AppID == appID {
+			return node
+		}
+	}
+	return nil
+}
@@ -0,0 +1,310 @@
+package amp
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"hash"
+	"hash/crc32"
+	"io"
+	"sort"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/symbol"
+)
+
+// Host snapshots
+//
+// A host snapshot is a single versioned archive of a host's persisted state -- its symbol tables, a description of its registry,
+// and the stores apps persist their cells in -- so that a host can be backed up or cloned without copying its raw DB directories.
+// A Host implements SnapshotTo and RestoreFrom by passing its state to WriteSnapshot and RestoreSnapshot.
+//
+//	header:  "AMPS" | version:2
+//	record:  kind:1 | nameLen:uvarint | name | dataLen:uvarint | data     (kind != 0)
+//	trailer: 0x00 | recordCount:4 | crc32:4                            (crc32 of all bytes preceding it)
+//
+// A symbol table record holds the table's export (see symbol.Table.ExportTo), the registry record holds its RegistryInfo as JSON,
+// and each entry of a store is a record holding the uvarint key length, the key, and the value.  All fixed-size ints are big endian.
+
+// HostSnapshotVersion is the version of the archive written by WriteSnapshot.
+const HostSnapshotVersion = 1
+
+const (
+	snapshotRecord_Symbols  = byte('t') // name is the symbol table name
+	snapshotRecord_Registry = byte('r') // name is empty
+	snapshotRecord_Store    = byte('s') // name is the store name
+)
+
+var hostSnapshotMagic = [4]byte{'A', 'M', 'P', 'S'}
+
+// HostState is the persisted state of a host captured by WriteSnapshot and replaced by RestoreSnapshot.
+type HostState struct {
+	Registry Registry                // described in the archive (see Registry.Describe); apps themselves are not archived
+	Symbols  map[string]symbol.Table // symbol tables, keyed by a name unique to the host
+	Stores   map[string]symbol.Store // app cell stores, keyed by a name unique to the host (e.g. the app's canonic spec)
+}
+
+// WriteSnapshot writes the given host state to w as a host snapshot archive.
+// Tables and stores are written in name order and the underlying writer is not closed.
+func WriteSnapshot(w io.Writer, state HostState) error {
+	sw := &snapshotWriter{
+		w:   bufio.NewWriter(w),
+		crc: crc32.NewIEEE(),
+	}
+	sw.write(binary.BigEndian.AppendUint16(hostSnapshotMagic[:], HostSnapshotVersion))
+
+	if state.Registry != nil {
+		info, err := json.Marshal(state.Registry.Describe())
+		if err != nil {
+			return ErrCode_ExportErr.Errorf("snapshot: %v", err)
+		}
+		sw.writeRecord(snapshotRecord_Registry, "", info)
+	}
+
+	var buf bytes.Buffer
+	for _, name := range sortedKeys(state.Symbols) {
+		buf.Reset()
+		if err := state.Symbols[name].ExportTo(&buf); err != nil {
+			return ErrCode_ExportErr.Errorf("snapshot: symbol table %q: %v", name, err)
+		}
+		sw.writeRecord(snapshotRecord_Symbols, name, buf.Bytes())
+	}
+
+	var entry []byte
+	for _, name := range sortedKeys(state.Stores) {
+		err := state.Stores[name].Iterate(nil, func(key, value []byte) error {
+			entry = binary.AppendUvarint(entry[:0], uint64(len(key)))
+			entry = append(append(entry, key...), value...)
+			sw.writeRecord(snapshotRecord_Store, name, entry)
+			return sw.err
+		})
+		if err != nil {
+			return ErrCode_ExportErr.Errorf("snapshot: store %q: %v", name, err)
+		}
+	}
+
+	return sw.close()
+}
+
+// RestoreSnapshot reads a host snapshot archive written by WriteSnapshot and replaces the given host state with its contents,
+// returning the description of the registry the archive was written from (or nil if none was archived).
+//
+// Each table and store named in the archive must be present in state: a store is cleared before the archived entries are written
+// to it, and archived symbol entries are merged into the table (see symbol.Table.ImportFrom).  The archive is fully read and verified
+// before any state is modified, so a truncated or corrupt archive leaves state unchanged.
+func RestoreSnapshot(r io.Reader, state HostState) (*RegistryInfo, error) {
+	sr := &snapshotReader{
+		r:   bufio.NewReader(r),
+		crc: crc32.NewIEEE(),
+	}
+	if err := sr.readHeader(); err != nil {
+		return nil, err
+	}
+
+	var info *RegistryInfo
+	symbols := make(map[string][]byte)
+	stores := make(map[string]symbol.Store)
+	for {
+		kind, name, data, err := sr.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch kind {
+		case snapshotRecord_Registry:
+			info = &RegistryInfo{}
+			if err = json.Unmarshal(data, info); err != nil {
+				return nil, ErrCode_DataFailure.Errorf("snapshot: bad registry record: %v", err)
+			}
+		case snapshotRecord_Symbols:
+			if state.Symbols[name] == nil {
+				return nil, ErrCode_BadValue.Errorf("snapshot: no symbol table named %q to restore", name)
+			}
+			symbols[name] = data
+		case snapshotRecord_Store:
+			if state.Stores[name] == nil {
+				return nil, ErrCode_BadValue.Errorf("snapshot: no store named %q to restore", name)
+			}
+			keyLen, n := binary.Uvarint(data)
+			if n <= 0 || uint64(len(data)-n) < keyLen {
+				return nil, ErrCode_DataFailure.Errorf("snapshot: bad entry for store %q", name)
+			}
+			staged := stores[name]
+			if staged == nil {
+				staged = symbol.NewMemoryStore()
+				stores[name] = staged
+			}
+			staged.Set(data[n:n+int(keyLen)], data[n+int(keyLen):])
+		default:
+			return nil, ErrCode_DataFailure.Errorf("snapshot: unknown record kind %d", kind)
+		}
+	}
+
+	for _, name := range sortedKeys(symbols) {
+		if err := state.Symbols[name].ImportFrom(bytes.NewReader(symbols[name])); err != nil {
+			return info, ErrCode_DataFailure.Errorf("snapshot: symbol table %q: %v", name, err)
+		}
+	}
+	for _, name := range sortedKeys(stores) {
+		if err := restoreStore(state.Stores[name], stores[name]); err != nil {
+			return info, ErrCode_DataFailure.Errorf("snapshot: store %q: %v", name, err)
+		}
+	}
+	return info, nil
+}
+
+// restoreStore replaces the entries of dst with those of src and commits dst.
+func restoreStore(dst, src symbol.Store) error {
+	var existing [][]byte
+	err := dst.Iterate(nil, func(key, value []byte) error {
+		existing = append(existing, append([]byte{}, key...))
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, key := range existing {
+		if err = dst.Delete(key); err != nil {
+			return err
+		}
+	}
+	err = src.Iterate(nil, func(key, value []byte) error {
+		return dst.Set(key, value)
+	})
+	if err != nil {
+		return err
+	}
+	return dst.Commit()
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+type snapshotWriter struct {
+	w     *bufio.Writer
+	crc   hash.Hash32
+	count uint32
+	hdr   []byte
+	err   error // sticky write error
+}
+
+func (sw *snapshotWriter) write(buf []byte) {
+	if sw.err != nil {
+		return
+	}
+	sw.crc.Write(buf)
+	_, sw.err = sw.w.Write(buf)
+}
+
+func (sw *snapshotWriter) writeRecord(kind byte, name string, data []byte) {
+	hdr := append(sw.hdr[:0], kind)
+	hdr = binary.AppendUvarint(hdr, uint64(len(name)))
+	hdr = append(hdr, name...)
+	hdr = binary.AppendUvarint(hdr, uint64(len(data)))
+	sw.hdr = hdr
+	sw.write(hdr)
+	sw.write(data)
+	sw.count++
+}
+
+func (sw *snapshotWriter) close() error {
+	sw.write(binary.BigEndian.AppendUint32([]byte{0}, sw.count))
+	if sw.err != nil {
+		return sw.err
+	}
+	if _, err := sw.w.Write(binary.BigEndian.AppendUint32(nil, sw.crc.Sum32())); err != nil {
+		return err
+	}
+	return sw.w.Flush()
+}
+
+type snapshotReader struct {
+	r     *bufio.Reader
+	crc   hash.Hash32
+	count uint32
+}
+
+func (sr *snapshotReader) readFull(buf []byte) error {
+	if _, err := io.ReadFull(sr.r, buf); err != nil {
+		return ErrCode_DataFailure.Errorf("snapshot: truncated: %v", err)
+	}
+	sr.crc.Write(buf)
+	return nil
+}
+
+func (sr *snapshotReader) readUvarint() (uint64, error) {
+	x, err := binary.ReadUvarint(sr.r)
+	if err != nil || x > 1<<30 {
+		return 0, ErrCode_DataFailure.Error("snapshot: bad length")
+	}
+	sr.crc.Write(binary.AppendUvarint(nil, x))
+	return x, nil
+}
+
+func (sr *snapshotReader) readHeader() error {
+	var hdr [6]byte
+	if err := sr.readFull(hdr[:]); err != nil {
+		return err
+	}
+	if !bytes.Equal(hdr[:4], hostSnapshotMagic[:]) {
+		return ErrCode_DataFailure.Error("snapshot: not a host snapshot")
+	}
+	if version := binary.BigEndian.Uint16(hdr[4:]); version != HostSnapshotVersion {
+		return ErrCode_DataFailure.Errorf("snapshot: unsupported version %d", version)
+	}
+	return nil
+}
+
+// next returns the next record, or io.EOF once the trailer has been read and verified.
+func (sr *snapshotReader) next() (kind byte, name string, data []byte, err error) {
+	var kindBuf [1]byte
+	if err = sr.readFull(kindBuf[:]); err != nil {
+		return
+	}
+	if kind = kindBuf[0]; kind == 0 {
+		return kind, "", nil, sr.readTrailer()
+	}
+
+	var n uint64
+	if n, err = sr.readUvarint(); err != nil {
+		return
+	}
+	nameBuf := make([]byte, n)
+	if err = sr.readFull(nameBuf); err != nil {
+		return
+	}
+	if n, err = sr.readUvarint(); err != nil {
+		return
+	}
+	data = make([]byte, n)
+	if err = sr.readFull(data); err != nil {
+		return
+	}
+	sr.count++
+	return kind, string(nameBuf), data, nil
+}
+
+func (sr *snapshotReader) readTrailer() error {
+	var countBuf [4]byte
+	if err := sr.readFull(countBuf[:]); err != nil {
+		return err
+	}
+	expectedCRC := sr.crc.Sum32()
+
+	var crcBuf [4]byte
+	if _, err := io.ReadFull(sr.r, crcBuf[:]); err != nil {
+		return ErrCode_DataFailure.Error("snapshot: truncated trailer")
+	}
+	if binary.BigEndian.Uint32(countBuf[:]) != sr.count || binary.BigEndian.Uint32(crcBuf[:]) != expectedCRC {
+		return ErrCode_DataFailure.Error("snapshot: checksum mismatch")
+	}
+	return io.EOF
+}
@@ -0,0 +1,271 @@
+package amp
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/symbol"
+	"github.com/amp-3d/amp-sdk-go/stdlib/symbol/memory_table"
+)
+
+// Spaces
+//
+// A space is an isolated tenant of a host (e.g. a household or team), so that a single host process can serve several of them.
+// Each space has its own symbol table and its own root for app state, and a session is scoped to the space named by its
+// Login.Space.  A host keeps each space's state in its own directory under a common root:
+//
+//	{root}/{space}/symbols       the space's symbol table (see symbol.OpenFileStore)
+//	{root}/{space}/apps/{app}    an app's LocalDataPath within the space (see Space.AppDataPath)
+//	{root}/{space}/stores/{app}  an app's cell store within the space (see Space.AppStore), holding its KVStore (see Space.AppKVStore)
+
+// DefaultSpace is the space a session is scoped to when its Login does not name one.
+const DefaultSpace = "default"
+
+// SpacesOpts configures a Spaces.
+type SpacesOpts struct {
+	Root       string                 // directory holding the state of each space
+	AutoCreate bool                   // if set, a login naming a space that does not exist creates it (see Spaces.ForLogin)
+	TableOpts  memory_table.TableOpts // used to create each space's symbol table (Store is set to the space's symbol store)
+}
+
+// DefaultSpacesOpts returns the suggested SpacesOpts for spaces kept under the given root.
+func DefaultSpacesOpts(root string) SpacesOpts {
+	return SpacesOpts{
+		Root:      root,
+		TableOpts: memory_table.DefaultOpts(),
+	}
+}
+
+// Spaces are the tenants of a host -- concurrency safe.
+type Spaces struct {
+	opts SpacesOpts
+
+	mu     sync.Mutex
+	spaces map[string]*Space // open spaces by name
+}
+
+// NewSpaces returns the Spaces kept under opts.Root, opening each space as it is first accessed.
+func NewSpaces(opts SpacesOpts) *Spaces {
+	return &Spaces{
+		opts:   opts,
+		spaces: make(map[string]*Space),
+	}
+}
+
+// Space is an isolated tenant of a host (see Spaces).
+type Space struct {
+	Name    string       // unique among the host's spaces
+	Root    string       // directory holding this space's state
+	Symbols symbol.Table // this space's symbol table
+
+	mu       sync.Mutex
+	stores   map[string]symbol.Store // open app stores by app canonic spec
+	kvStores map[string]KVStore      // app KV stores by app canonic spec
+}
+
+// ValidateSpaceName returns an ErrCode_BadValue error if the given name cannot name a space.
+// A space name is non-empty and consists of lower case letters, digits, '-', '_', and '.' (but is not "." or "..").
+func ValidateSpaceName(name string) error {
+	if name == "" || name == "." || name == ".." {
+		return ErrCode_BadValue.Errorf("invalid space name %q", name)
+	}
+	for _, c := range name {
+		switch {
+		case c >= 'a' && c <= 'z', c >= '0' && c <= '9', c == '-', c == '_', c == '.':
+		default:
+			return ErrCode_BadValue.Errorf("invalid space name %q", name)
+		}
+	}
+	return nil
+}
+
+// Open returns the given space, creating it if it does not exist.
+func (s *Spaces) Open(name string) (*Space, error) {
+	return s.open(name, true)
+}
+
+// Get returns the given space, or ErrCode_BadValue if it does not exist.
+func (s *Spaces) Get(name string) (*Space, error) {
+	return s.open(name, false)
+}
+
+// ForLogin returns the space the given login is scoped to (DefaultSpace if Login.Space is empty).
+// The space is created if it does not exist and SpacesOpts.AutoCreate is set, otherwise ErrCode_LoginFailed is returned.
+//
+// A host calls this when it starts a HostSession, giving the session's apps the space's symbol table and state roots.
+func (s *Spaces) ForLogin(login *Login) (*Space, error) {
+	name := login.GetSpace()
+	if name == "" {
+		name = DefaultSpace
+	}
+	space, err := s.open(name, s.opts.AutoCreate || name == DefaultSpace)
+	if err != nil && GetErrCode(err) == ErrCode_BadValue {
+		return nil, ErrCode_LoginFailed.Errorf("login to space %q refused: %v", name, err)
+	}
+	return space, err
+}
+
+// Names returns the names of all existing spaces in order, whether open or not.
+func (s *Spaces) Names() ([]string, error) {
+	entries, err := os.ReadDir(s.opts.Root)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() && ValidateSpaceName(entry.Name()) == nil {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Close closes all open spaces.
+func (s *Spaces) Close() error {
+	s.mu.Lock()
+	spaces := s.spaces
+	s.spaces = make(map[string]*Space)
+	s.mu.Unlock()
+
+	var err error
+	for _, space := range spaces {
+		if closeErr := space.close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+func (s *Spaces) open(name string, create bool) (*Space, error) {
+	if err := ValidateSpaceName(name); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if space := s.spaces[name]; space != nil {
+		return space, nil
+	}
+
+	root := filepath.Join(s.opts.Root, name)
+	if _, err := os.Stat(root); os.IsNotExist(err) && !create {
+		return nil, ErrCode_BadValue.Errorf("space %q does not exist", name)
+	}
+	if err := os.MkdirAll(root, 0700); err != nil {
+		return nil, ErrCode_DataFailure.Errorf("space %q: %v", name, err)
+	}
+
+	store, err := symbol.OpenFileStore(filepath.Join(root, "symbols"))
+	if err != nil {
+		return nil, ErrCode_DataFailure.Errorf("space %q: %v", name, err)
+	}
+	opts := s.opts.TableOpts
+	opts.Store = store
+	table, err := opts.CreateTable()
+	if err != nil {
+		store.Close()
+		return nil, ErrCode_DataFailure.Errorf("space %q: %v", name, err)
+	}
+
+	space := &Space{
+		Name:     name,
+		Root:     root,
+		Symbols:  table,
+		stores:   make(map[string]symbol.Store),
+		kvStores: make(map[string]KVStore),
+	}
+	s.spaces[name] = space
+	return space, nil
+}
+
+// AppDataPath returns the given app's LocalDataPath within this space, creating it if needed (see AppContext.LocalDataPath).
+func (space *Space) AppDataPath(app *App) (string, error) {
+	path := filepath.Join(space.Root, "apps", app.AppSpec.Canonic)
+	if err := os.MkdirAll(path, 0700); err != nil {
+		return "", ErrCode_DataFailure.Errorf("space %q: %v", space.Name, err)
+	}
+	return path, nil
+}
+
+// AppStore returns the store the given app persists its cells in within this space, opening it if needed.
+// The store is owned by this space and closed along with it.
+func (space *Space) AppStore(app *App) (symbol.Store, error) {
+	space.mu.Lock()
+	defer space.mu.Unlock()
+
+	name := app.AppSpec.Canonic
+	if store := space.stores[name]; store != nil {
+		return store, nil
+	}
+	dir := filepath.Join(space.Root, "stores")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, ErrCode_DataFailure.Errorf("space %q: %v", space.Name, err)
+	}
+	store, err := symbol.OpenFileStore(filepath.Join(dir, name))
+	if err != nil {
+		return nil, ErrCode_DataFailure.Errorf("space %q: %v", space.Name, err)
+	}
+	space.stores[name] = store
+	return store, nil
+}
+
+// AppKVStore returns the KVStore of the given app within this space, kept under KVStorePrefix in the app's store (see AppStore),
+// so its entries are included in this space's State.
+func (space *Space) AppKVStore(app *App) (KVStore, error) {
+	store, err := space.AppStore(app)
+	if err != nil {
+		return nil, err
+	}
+
+	space.mu.Lock()
+	defer space.mu.Unlock()
+
+	name := app.AppSpec.Canonic
+	kv := space.kvStores[name]
+	if kv == nil {
+		kv = NewKVStore(symbol.NewPrefixStore(store, []byte(KVStorePrefix)))
+		space.kvStores[name] = kv
+	}
+	return kv, nil
+}
+
+// State returns this space's persisted state (with the given registry), so a space can be snapshotted and restored on its own
+// (see WriteSnapshot).  Only app stores already opened via AppStore are included.
+func (space *Space) State(reg Registry) HostState {
+	space.mu.Lock()
+	defer space.mu.Unlock()
+
+	state := HostState{
+		Registry: reg,
+		Symbols:  map[string]symbol.Table{"symbols": space.Symbols},
+		Stores:   make(map[string]symbol.Store, len(space.stores)),
+	}
+	for name, store := range space.stores {
+		state.Stores[name] = store
+	}
+	return state
+}
+
+func (space *Space) close() error {
+	space.mu.Lock()
+	defer space.mu.Unlock()
+
+	var err error
+	for _, store := range space.stores {
+		if closeErr := store.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+	space.stores = nil
+	if flushErr := space.Symbols.Flush(); flushErr != nil && err == nil {
+		err = flushErr
+	}
+	if closeErr := space.Symbols.Close(); closeErr != nil && err == nil {
+		err = closeErr
+	}
+	return err
+}
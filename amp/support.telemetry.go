@@ -0,0 +1,357 @@
+package amp
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/tag"
+	"github.com/amp-3d/amp-sdk-go/stdlib/task"
+)
+
+// Pin telemetry
+//
+// PinTelemetry records the latency and throughput of each pin an app serves -- the time from the request to the first tx pushed,
+// and the txs and bytes pushed and committed -- so that an operator can find which app or pin is making a client feel slow.
+// A host wraps each AppInstance it issues via PinTelemetry.Instrument (alongside HostMetrics.Instrument, which only aggregates
+// by app), and exposes the stats of open and recently closed pins via PinTelemetry.Handler and/or PinTelemetry.ServeDiagnostics,
+// which serves them as a cell that a client (e.g. a debug overlay) can pin like any other.
+
+// PinStatsSpec is the attr of each child cell pushed by PinTelemetry.ServeDiagnostics.
+var PinStatsSpec = tag.FormSpec(AttrSpec, "PinStats")
+
+const (
+	DefaultPinTelemetryRetain  = 256
+	DefaultDiagnosticsInterval = time.Second
+)
+
+// PinTelemetryOpts configures a PinTelemetry.
+type PinTelemetryOpts struct {
+	Retain int // stats of this many recently closed pins are retained for inspection (default DefaultPinTelemetryRetain)
+}
+
+// PinTelemetry records the stats of each pin served by the instances it instruments -- concurrency safe.
+type PinTelemetry struct {
+	opts   PinTelemetryOpts
+	mu     sync.Mutex
+	open   map[*pinRecord]struct{}
+	closed []*pinRecord // oldest first
+}
+
+// pinRecord holds the stats of a pin, keyed by the ID of the cell reporting it via ServeDiagnostics.
+type pinRecord struct {
+	cellID  tag.ID
+	started time.Time
+	mu      sync.Mutex
+	stats   PinStats
+}
+
+// NewPinTelemetry returns an empty PinTelemetry.
+func NewPinTelemetry(opts PinTelemetryOpts) *PinTelemetry {
+	if opts.Retain <= 0 {
+		opts.Retain = DefaultPinTelemetryRetain
+	}
+	return &PinTelemetry{
+		opts: opts,
+		open: make(map[*pinRecord]struct{}),
+	}
+}
+
+// Instrument wraps the given instance of app so that the pins it serves are recorded.
+// A host calls this when it issues an AppInstance to a HostSession, along with any other wrapping (e.g. HostMetrics.Instrument).
+func (pt *PinTelemetry) Instrument(app *App, inst AppInstance) AppInstance {
+	return &telemetryApp{
+		AppInstance: inst,
+		pt:          pt,
+		app:         app.AppSpec.Canonic,
+	}
+}
+
+// Pins returns the stats of all open and retained closed pins, ordered by when they were opened.
+func (pt *PinTelemetry) Pins() []PinStats {
+	pt.mu.Lock()
+	recs := make([]*pinRecord, 0, len(pt.open)+len(pt.closed))
+	for rec := range pt.open {
+		recs = append(recs, rec)
+	}
+	recs = append(recs, pt.closed...)
+	pt.mu.Unlock()
+
+	sort.Slice(recs, func(i, j int) bool { return recs[i].started.Before(recs[j].started) })
+	pins := make([]PinStats, len(recs))
+	for i, rec := range recs {
+		pins[i] = rec.snapshot()
+	}
+	return pins
+}
+
+// Handler returns an http.Handler that serves Pins as JSON.  The optional query params are:
+//
+//	app=<canonic app spec>      only pins served by this app
+//	open=1                      only pins still open
+//	sort=first_tx|bytes|txs     slowest first tx, most bytes pushed, or most txs pushed first (default is by when opened)
+//	limit=<n>                   at most n pins
+//
+// Like task.DebugHandler, this is intended for operators and should not be exposed publicly.
+func (pt *PinTelemetry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		pins := pt.Pins()
+
+		app, openOnly := query.Get("app"), query.Get("open") == "1"
+		filtered := pins[:0]
+		for _, pin := range pins {
+			if (app == "" || pin.App == app) && (!openOnly || pin.ClosedAt == 0) {
+				filtered = append(filtered, pin)
+			}
+		}
+		pins = filtered
+
+		var key func(pin *PinStats) int64
+		switch query.Get("sort") {
+		case "":
+		case "first_tx":
+			key = func(pin *PinStats) int64 { return pin.FirstTxMicros }
+		case "bytes":
+			key = func(pin *PinStats) int64 { return pin.BytesOut }
+		case "txs":
+			key = func(pin *PinStats) int64 { return pin.TxsOut }
+		default:
+			http.Error(w, "unknown sort "+strconv.Quote(query.Get("sort")), http.StatusBadRequest)
+			return
+		}
+		if key != nil {
+			sort.SliceStable(pins, func(i, j int) bool { return key(&pins[i]) > key(&pins[j]) })
+		}
+		if limit, err := strconv.Atoi(query.Get("limit")); err == nil && limit >= 0 && limit < len(pins) {
+			pins = pins[:limit]
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(pins); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
+
+// ServeDiagnostics serves the given request with a cell whose children are the pins reported by Pins, each having a PinStatsSpec
+// attr, as a child of parent (e.g. an AppInstance).  If the request is PinSync_Maintain, these are pushed again every interval
+// (or DefaultDiagnosticsInterval) until the returned Pin is closed, with the cells of pins no longer retained deleted.
+//
+// A host or app routes a diagnostic URL of its choosing (e.g. "amp://diagnostics/pins") to this from its ServeRequest.
+func (pt *PinTelemetry) ServeDiagnostics(parent task.Context, req Requester, interval time.Duration) (Pin, error) {
+	if interval <= 0 {
+		interval = DefaultDiagnosticsInterval
+	}
+	pin := &diagnosticsPin{
+		pt:     pt,
+		req:    req,
+		pushed: make(map[tag.ID]struct{}),
+	}
+
+	var err error
+	pin.ctx, err = parent.StartChild(&task.Task{
+		Label: "pin: diagnostics",
+		OnRun: func(ctx task.Context) {
+			err := pin.pushTx()
+			if err == nil && req.Request().PinSync == PinSync_Maintain {
+				ticker := time.NewTicker(interval)
+				for err == nil {
+					select {
+					case <-ticker.C:
+						err = pin.pushTx()
+					case <-ctx.Closing():
+						ticker.Stop()
+						req.OnComplete(nil)
+						return
+					}
+				}
+				ticker.Stop()
+			}
+			if err != nil && err != ErrShuttingDown {
+				ctx.Warnf("diagnostics push failed: %v", err)
+			}
+			req.OnComplete(err)
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pin, nil
+}
+
+// serve serves req via pinner, recording the pin it returns.
+func (pt *PinTelemetry) serve(app string, pinner Pinner, req Requester) (Pin, error) {
+	r := req.Request()
+	rec := &pinRecord{
+		cellID:  r.ID,
+		started: time.Now(),
+	}
+	if rec.cellID.IsNil() {
+		rec.cellID = tag.New()
+	}
+	rec.stats.ReqID = rec.cellID.String()
+	rec.stats.App = app
+	rec.stats.OpenedAt = rec.started.UnixMilli()
+	if r.URL != nil {
+		rec.stats.Target = r.URL.String()
+	} else if target := r.TargetID(); target.IsSet() {
+		rec.stats.Target = target.String()
+	}
+	if r.CommitTx != nil {
+		rec.stats.TxsIn = 1
+	}
+
+	pt.mu.Lock()
+	pt.open[rec] = struct{}{}
+	pt.mu.Unlock()
+
+	pin, err := pinner.ServeRequest(&telemetryRequester{
+		Requester: req,
+		rec:       rec,
+	})
+	if err != nil {
+		pt.mu.Lock()
+		delete(pt.open, rec) // a failed request never opened a pin
+		pt.mu.Unlock()
+		return nil, err
+	}
+	if pin == nil {
+		pt.close(rec)
+		return nil, nil
+	}
+
+	go func() {
+		<-pin.Context().Done()
+		pt.close(rec)
+	}()
+	return &telemetryPin{
+		Pin: pin,
+		pt:  pt,
+		app: app,
+	}, nil
+}
+
+func (pt *PinTelemetry) close(rec *pinRecord) {
+	rec.mu.Lock()
+	rec.stats.ClosedAt = time.Now().UnixMilli()
+	rec.mu.Unlock()
+
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+	delete(pt.open, rec)
+	pt.closed = append(pt.closed, rec)
+	if over := len(pt.closed) - pt.opts.Retain; over > 0 {
+		pt.closed = append(pt.closed[:0], pt.closed[over:]...)
+	}
+}
+
+func (rec *pinRecord) snapshot() PinStats {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	return rec.stats
+}
+
+func (rec *pinRecord) pushedSize(size int64) {
+	now := time.Now()
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	if rec.stats.TxsOut == 0 {
+		rec.stats.FirstTxMicros = now.Sub(rec.started).Microseconds()
+		if rec.stats.FirstTxMicros <= 0 {
+			rec.stats.FirstTxMicros = 1 // zero denotes no tx pushed
+		}
+	}
+	rec.stats.TxsOut++
+	rec.stats.BytesOut += size
+	rec.stats.LastTxAt = now.UnixMilli()
+}
+
+type telemetryApp struct {
+	AppInstance
+	pt  *PinTelemetry
+	app string
+}
+
+func (app *telemetryApp) ServeRequest(req Requester) (Pin, error) {
+	return app.pt.serve(app.app, app.AppInstance, req)
+}
+
+type telemetryPin struct {
+	Pin
+	pt  *PinTelemetry
+	app string
+}
+
+func (pin *telemetryPin) ServeRequest(req Requester) (Pin, error) {
+	return pin.pt.serve(pin.app, pin.Pin, req)
+}
+
+type telemetryRequester struct {
+	Requester
+	rec *pinRecord
+}
+
+func (req *telemetryRequester) PushTx(tx *TxMsg) error {
+	size := txWireSize(tx) // tx may be released once pushed
+	err := req.Requester.PushTx(tx)
+	if err == nil {
+		req.rec.pushedSize(size)
+	}
+	return err
+}
+
+type diagnosticsPin struct {
+	pt     *PinTelemetry
+	req    Requester
+	ctx    task.Context
+	pushed map[tag.ID]struct{} // cells pushed by the last pushTx()
+}
+
+func (pin *diagnosticsPin) Context() task.Context {
+	return pin.ctx
+}
+
+func (pin *diagnosticsPin) ServeRequest(req Requester) (Pin, error) {
+	return nil, ErrCellNotFound
+}
+
+func (pin *diagnosticsPin) pushTx() error {
+	pt := pin.pt
+	pt.mu.Lock()
+	recs := make([]*pinRecord, 0, len(pt.open)+len(pt.closed))
+	for rec := range pt.open {
+		recs = append(recs, rec)
+	}
+	recs = append(recs, pt.closed...)
+	pt.mu.Unlock()
+
+	tx := NewTxMsg(true)
+	pushed := make(map[tag.ID]struct{}, len(recs))
+	for _, rec := range recs {
+		stats := rec.snapshot()
+		if err := tx.MarshalUpsert(rec.cellID, PinStatsSpec.ID, &stats); err != nil {
+			tx.ReleaseRef()
+			return err
+		}
+		pushed[rec.cellID] = struct{}{}
+	}
+	for cellID := range pin.pushed {
+		if _, exists := pushed[cellID]; !exists {
+			tx.MarshalOpWithBuf(&TxOp{
+				OpCode:   TxOpCode_DeleteCell,
+				TargetID: cellID,
+			}, nil)
+		}
+	}
+	pin.pushed = pushed
+
+	tx.Status = OpStatus_Synced
+	return pin.req.PushTx(tx)
+}
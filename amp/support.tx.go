@@ -3,9 +3,11 @@ package amp
 import (
 	"encoding/binary"
 	"io"
+	"net"
 	"sync"
 	"sync/atomic"
 
+	"github.com/amp-3d/amp-sdk-go/stdlib/bufs"
 	"github.com/amp-3d/amp-sdk-go/stdlib/tag"
 )
 
@@ -25,6 +27,10 @@ func (header TxHeader) TxDataLen() int {
 	return int(binary.LittleEndian.Uint32(header[8:12]))
 }
 
+func (header TxHeader) TxSeq() uint32 {
+	return binary.LittleEndian.Uint32(header[12:16])
+}
+
 func NewTxMsg(genesis bool) *TxMsg {
 	tx := gTxMsgPool.Get().(*TxMsg)
 	tx.refCount = 1
@@ -98,18 +104,62 @@ func (tx *TxMsg) AddRef() {
 	atomic.AddInt32(&tx.refCount, 1)
 }
 
+// ReleaseRef releases a reference to this tx, returning it (and its DataStore) to their pools once none remain.
 func (tx *TxMsg) ReleaseRef() {
 	if atomic.AddInt32(&tx.refCount, -1) > 0 {
 		return
 	}
 
+	if tx.backing != nil {
+		tx.backing.ReleaseRef()
+	} else {
+		bufs.Put(tx.DataStore)
+	}
 	*tx = TxMsg{
-		Ops:       tx.Ops[:0],
-		DataStore: tx.DataStore[:0],
+		Ops: tx.Ops[:0],
 	}
 	gTxMsgPool.Put(tx)
 }
 
+// Derive returns a new tx having the same TxInfo and Seq as this tx but no ops, whose DataStore references this tx's rather
+// than copying it, so that ops of this tx can be carried into it via CarryOp without copying their values (e.g. to push
+// a subset of this tx's ops).  The new tx holds a reference on this tx until it is released, and ops marshalled into it
+// copy this tx's DataStore into one of its own first (so this tx is never modified).
+func (tx *TxMsg) Derive() *TxMsg {
+	tx.AddRef()
+	derived := NewTxMsg(false)
+	derived.TxInfo = tx.TxInfo
+	derived.NumOps = 0
+	derived.Seq = tx.Seq
+	derived.DataStore = tx.DataStore[:len(tx.DataStore):len(tx.DataStore)]
+	derived.backing = tx
+	return derived
+}
+
+// CarryOp appends the given op of the tx this tx was derived from (see Derive), referencing its value rather than copying it.
+func (tx *TxMsg) CarryOp(op *TxOp) {
+	tx.NumOps += 1
+	tx.Ops = append(tx.Ops, *op)
+}
+
+// reserve ensures DataStore has room for n more bytes, moving it to a larger pooled buffer as needed (see bufs.Pool).
+// A DataStore referencing another tx's is first copied into one of this tx's own.
+func (tx *TxMsg) reserve(n int) {
+	need := len(tx.DataStore) + n
+	if need <= cap(tx.DataStore) {
+		return
+	}
+	buf := bufs.Get(max(need, max(2*cap(tx.DataStore), bufs.MinPoolSize)))[:len(tx.DataStore)]
+	copy(buf, tx.DataStore)
+	if tx.backing != nil {
+		tx.backing.ReleaseRef()
+		tx.backing = nil
+	} else {
+		bufs.Put(tx.DataStore)
+	}
+	tx.DataStore = buf
+}
+
 func MarshalMetaAttr(attrSpec tag.ID, attrVal ElemVal) (*TxMsg, error) {
 
 	tx := NewTxMsg(true)
@@ -128,6 +178,9 @@ func (tx *TxMsg) UnmarshalOpValue(idx int, out ElemVal) error {
 		return ErrCode_MalformedTx.Error("UnmarshalElemVal: index out of range")
 	}
 	op := tx.Ops[idx]
+	if n := uint64(len(tx.DataStore)); op.DataOfs > n || op.DataLen > n-op.DataOfs {
+		return ErrMalformedTx
+	}
 	ofs := op.DataOfs
 	return out.Unmarshal(tx.DataStore[ofs : ofs+op.DataLen])
 }
@@ -145,6 +198,92 @@ func SendMetaAttr(sess HostSession, context tag.ID, status OpStatus, val ElemVal
 	return sess.SendTx(tx)
 }
 
+// ErrAttrSpec is the meta attr of a tx closing a request that failed, holding the *Err it failed with (see MarshalErrTx).
+var ErrAttrSpec = tag.FormSpec(MetaAttrSpec, (&Err{}).ElemTypeName())
+
+// MarshalErrTx returns the tx a host sends to close the given request after it fails with the given error.  Its Status is
+// OpStatus_Closed and its ErrCode is the error's code (see GetErrCode), so a client can branch on the kind of failure from
+// the tx alone, and its only op is an ErrAttrSpec meta attr holding the error as an *Err (see ErrorToValue).
+func MarshalErrTx(reqID tag.ID, err error) (*TxMsg, error) {
+	val := ErrorToValue(err)
+	if val == nil {
+		return nil, ErrCode_BadValue.Error("MarshalErrTx: nil error")
+	}
+	tx, txErr := MarshalMetaAttr(ErrAttrSpec.ID, val)
+	if txErr != nil {
+		return nil, txErr
+	}
+	tx.SetRequestID(reqID)
+	tx.Status = OpStatus_Closed
+	tx.ErrCode = val.(*Err).Code
+	return tx, nil
+}
+
+// CloseErr returns the error the request of this tx failed with if this tx reports it (see MarshalErrTx), or nil otherwise.
+// A tx with OpStatus_Closed and an ErrCode but no ErrAttrSpec op reports an *Err having only that code.
+func (tx *TxMsg) CloseErr() error {
+	if len(tx.Ops) > 0 && tx.Ops[0].OpCode == TxOpCode_MetaAttr && tx.Ops[0].AttrID == ErrAttrSpec.ID {
+		ampErr := &Err{}
+		if err := tx.UnmarshalOpValue(0, ampErr); err != nil {
+			return err
+		}
+		if ampErr.Code == ErrCode_NoErr {
+			ampErr.Code = tx.ErrCode
+		}
+		if ampErr.Code == ErrCode_NoErr {
+			ampErr.Code = ErrCode_UnnamedErr
+		}
+		return ampErr
+	}
+	if tx.Status == OpStatus_Closed && tx.ErrCode != ErrCode_NoErr {
+		return &Err{
+			Code:      tx.ErrCode,
+			Retryable: tx.ErrCode.Retryable(),
+		}
+	}
+	return nil
+}
+
+// PinRequestSpec is the meta attr a client sends to issue a pin request (see SendMetaAttr).
+var PinRequestSpec = tag.FormSpec(MetaAttrSpec, "PinRequest")
+
+// MarshalPinRequest returns the tx a client sends to issue the given pin request, with the given ops to be committed (see ParsePinRequest).
+// commitTx may be nil and is not retained.
+func MarshalPinRequest(reqID tag.ID, pinReq *PinRequest, commitTx *TxMsg) (*TxMsg, error) {
+	tx, err := MarshalMetaAttr(PinRequestSpec.ID, pinReq)
+	if err != nil {
+		return nil, err
+	}
+	tx.SetRequestID(reqID)
+	tx.SetGenesisID(reqID)
+	if commitTx != nil {
+		for _, op := range commitTx.Ops {
+			tx.MarshalOpWithBuf(&op, commitTx.DataStore[op.DataOfs:op.DataOfs+op.DataLen])
+		}
+	}
+	return tx, nil
+}
+
+// ParsePinRequest returns the pin request carried by the given inbound tx, where ops following the PinRequest meta attr are the
+// tx to be committed (or nil if there are none).  ok is false if tx is not a pin request.
+func ParsePinRequest(tx *TxMsg) (pinReq *PinRequest, commitTx *TxMsg, ok bool) {
+	if len(tx.Ops) == 0 || tx.Ops[0].OpCode != TxOpCode_MetaAttr || tx.Ops[0].AttrID != PinRequestSpec.ID {
+		return nil, nil, false
+	}
+	pinReq = &PinRequest{}
+	if err := tx.UnmarshalOpValue(0, pinReq); err != nil {
+		return nil, nil, false
+	}
+	if len(tx.Ops) > 1 {
+		commitTx = NewTxMsg(false)
+		commitTx.TxInfo = tx.TxInfo
+		for _, op := range tx.Ops[1:] {
+			commitTx.MarshalOpWithBuf(&op, tx.DataStore[op.DataOfs:op.DataOfs+op.DataLen])
+		}
+	}
+	return pinReq, commitTx, true
+}
+
 // If nil, nil is returned, then this Tx is a valid TxMsg to be merged into the target Pin.
 func (tx *TxMsg) CheckMetaAttr(reg Registry) (ElemVal, error) {
 	genesisID := tx.GenesisID()
@@ -189,6 +328,9 @@ func (tx *TxMsg) MarshalOp(op *TxOp, val ElemVal) error {
 		op.DataLen = 0
 	} else {
 		var err error
+		if sizer, ok := val.(interface{ Size() int }); ok {
+			tx.reserve(sizer.Size())
+		}
 		op.DataOfs = uint64(len(tx.DataStore))
 		tx.DataStore, err = val.MarshalToStore(tx.DataStore)
 		if err != nil {
@@ -205,6 +347,7 @@ func (tx *TxMsg) MarshalOp(op *TxOp, val ElemVal) error {
 func (tx *TxMsg) MarshalOpWithBuf(op *TxOp, valBuf []byte) {
 	op.DataOfs = uint64(len(tx.DataStore))
 	op.DataLen = uint64(len(valBuf))
+	tx.reserve(len(valBuf))
 	tx.DataStore = append(tx.DataStore, valBuf...)
 	tx.NumOps += 1
 	tx.Ops = append(tx.Ops, *op)
@@ -235,30 +378,50 @@ func ReadTxMsg(stream io.Reader) (*TxMsg, error) {
 		return nil, ErrMalformedTx
 	}
 
-	tx := NewTxMsg(false)
 	bodyLen := header.TxBodyLen()
 	dataLen := header.TxDataLen()
+	if bodyLen < int(Const_TxHeader_Size) {
+		return nil, ErrMalformedTx
+	}
+	if sized, ok := stream.(interface{ Len() int }); ok && bodyLen-int(Const_TxHeader_Size)+dataLen > sized.Len() {
+		return nil, ErrMalformedTx // e.g. a bytes.Reader holding a truncated tx -- don't allocate for what isn't there
+	}
+
+	tx := NewTxMsg(false)
+	tx.Seq = header.TxSeq()
 
 	// Use tx.DataStore to hold the body for unmarshalling.
 	// The tx body contains TxMsg fields and TxOps
 	{
 		needSz := max(bodyLen, dataLen)
 		if cap(tx.DataStore) < needSz {
-			tx.DataStore = make([]byte, max(needSz, 2048))
+			bufs.Put(tx.DataStore)
+			tx.DataStore = bufs.Get(max(needSz, 2048))
 		}
 
 		buf := tx.DataStore[:bodyLen-int(Const_TxHeader_Size)]
 		if err := readBytes(buf); err != nil {
+			tx.ReleaseRef()
 			return nil, err
 		}
 		if err := tx.UnmarshalBody(buf); err != nil {
+			tx.ReleaseRef()
 			return nil, err
 		}
 	}
 
+	// Each op's value must lie within the data store
+	for _, op := range tx.Ops {
+		if op.DataOfs > uint64(dataLen) || op.DataLen > uint64(dataLen)-op.DataOfs {
+			tx.ReleaseRef()
+			return nil, ErrMalformedTx
+		}
+	}
+
 	// Read tx data store -- used for on-demand ElemVal unmarshalling
 	tx.DataStore = tx.DataStore[:dataLen]
 	if err := readBytes(tx.DataStore); err != nil {
+		tx.ReleaseRef()
 		return nil, err
 	}
 
@@ -292,6 +455,26 @@ func (tx *TxMsg) MarshalToBuffer(dst *[]byte) {
 	*dst = append(*dst, tx.DataStore...)
 }
 
+// WriteTo writes this tx to w as MarshalToBuffer would marshal it, except that DataStore is written as is rather than
+// copied into a marshalling buffer (see net.Buffers, which writes both in one writev call to a net.Conn).
+func (tx *TxMsg) WriteTo(w io.Writer) (int64, error) {
+	scrap := bufs.Get(2048)
+	headerBody := scrap
+	tx.MarshalHeaderAndBody(&headerBody)
+	parts := net.Buffers{headerBody, tx.DataStore}
+	n, err := parts.WriteTo(w)
+	if &headerBody[0] != &scrap[0] {
+		bufs.Put(scrap) // outgrown by the body
+	}
+	bufs.Put(headerBody)
+	return n, err
+}
+
+// txWireSize approximates the marshalled size of a tx without marshalling it (for accounting, e.g. SessionLimits.BytesPerSec).
+func txWireSize(tx *TxMsg) int64 {
+	return int64(Const_TxHeader_Size) + int64(len(tx.DataStore)) + 16*int64(len(tx.Ops))
+}
+
 func (tx *TxMsg) MarshalHeaderAndBody(dst *[]byte) {
 	buf := (*dst)[:0]
 	if cap(buf) < 300 {
@@ -308,6 +491,7 @@ func (tx *TxMsg) MarshalHeaderAndBody(dst *[]byte) {
 
 	binary.LittleEndian.PutUint32(header[4:8], uint32(len(headerBody)))
 	binary.LittleEndian.PutUint32(header[8:12], uint32(len(tx.DataStore)))
+	binary.LittleEndian.PutUint32(header[12:16], tx.Seq)
 
 	*dst = headerBody
 }
@@ -389,6 +573,9 @@ func (tx *TxMsg) UnmarshalBody(src []byte) error {
 			return ErrMalformedTx
 		}
 		p += n
+		if infoLen > uint64(len(src)-p) {
+			return ErrMalformedTx
+		}
 
 		tx.TxInfo = TxInfo{}
 		err := tx.TxInfo.Unmarshal(src[p : p+int(infoLen)])
@@ -408,7 +595,7 @@ func (tx *TxMsg) UnmarshalBody(src []byte) error {
 
 		// skip (future use)
 		var skip uint64
-		if skip, n = binary.Uvarint(src[p:]); n <= 0 {
+		if skip, n = binary.Uvarint(src[p:]); n <= 0 || skip > uint64(len(src)-p-n) {
 			return ErrMalformedTx
 		}
 		p += n + int(skip)
@@ -477,8 +664,9 @@ func (tx *TxMsg) UnmarshalBody(src []byte) error {
 	return nil
 }
 
+// Validate checks that op has an attr and a target cell -- a meta attr op need not have a target (see MarshalMetaAttr).
 func (op *TxOp) Validate() error {
-	if op.TargetID.IsNil() {
+	if op.TargetID.IsNil() && op.OpCode != TxOpCode_MetaAttr {
 		return ErrBadTarget
 	}
 	if op.AttrID.IsNil() {
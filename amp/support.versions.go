@@ -0,0 +1,352 @@
+package amp
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/tag"
+)
+
+// Attr versioning
+//
+// Each attr item (cell, attr, and SI) of an app using AttrVersions has a version stamp: the epoch of the AttrVersions (a random
+// number chosen when it is created) in the high 32 bits and the number of writes (upserts and deletes, including the deletion of
+// its cell) made to the item in the low 32 bits.  The version of each item an upsert or delete writes is sent to clients in the
+// op's Height, so a client editing a shared cell knows what version its copy of each item is.
+//
+// A client makes a compare-and-swap commit by setting the Height of each upsert or delete it commits to the version of the item
+// it expects to replace (or VersionAbsent if the item must not exist).  If any is stale, the commit is rejected with
+// ErrCode_VersionConflict (see VersionConflictOf) and none of it is applied, so the client can re-read and retry its edit rather
+// than silently overwriting another session's.  An op having a Height of 0 is unconditional (last writer wins).
+//
+// A host shares one AttrVersions among the sessions of an app whose cells are shared and wraps each AppInstance it issues via
+// Enforce.  An app applying writes of its own (not committed by a client) passes them through Advance so that clients editing
+// those items see them as newer.
+//
+// Versions are held in memory, so a host restart starts them over under a new epoch: every version a client was sent before
+// the restart then conflicts, rather than falsely matching an item written as many times since, and the client re-reads the
+// cell it edits (whose pushes carry versions of the new epoch) before retrying.
+
+// VersionAbsent is the expected version of an item a commit may only write if the item does not currently exist.
+const VersionAbsent = ^uint64(0)
+
+// Details of an ErrCode_VersionConflict error (see VersionConflictOf).
+const (
+	ConflictCellDetail     = "cell"
+	ConflictAttrDetail     = "attr"
+	ConflictSIDetail       = "si"
+	ConflictExpectedDetail = "expected_version"
+	ConflictVersionDetail  = "version"
+	ConflictCountDetail    = "conflicts"
+)
+
+// VersionConflict is an op of a commit whose expected version of an attr item is stale.
+type VersionConflict struct {
+	CellID   tag.ID
+	AttrID   tag.ID
+	SI       tag.ID
+	Expected uint64 // version the commit expected (or VersionAbsent)
+	Version  uint64 // current version of the item
+	Exists   bool   // set if the item currently has a value
+}
+
+func (c VersionConflict) String() string {
+	expected := strconv.FormatUint(c.Expected, 10)
+	if c.Expected == VersionAbsent {
+		expected = "absent"
+	}
+	return fmt.Sprintf("cell %s attr %s SI %s: expected version %s, is %d", c.CellID, c.AttrID, c.SI, expected, c.Version)
+}
+
+// VersionConflictError returns the given conflicts as an ErrCode_VersionConflict error, or nil if there are none.
+// The error's details describe the first conflict (and how many there are).
+func VersionConflictError(conflicts []VersionConflict) error {
+	if len(conflicts) == 0 {
+		return nil
+	}
+	msgs := make([]string, len(conflicts))
+	for i, c := range conflicts {
+		msgs[i] = c.String()
+	}
+	err := ErrCode_VersionConflict.Errorf("commit is stale: %s", strings.Join(msgs, "; ")).(*Err)
+	first := conflicts[0]
+	err.WithDetail(ConflictCellDetail, first.CellID.ShortString())
+	err.WithDetail(ConflictAttrDetail, first.AttrID.ShortString())
+	err.WithDetail(ConflictSIDetail, first.SI.ShortString())
+	err.WithDetail(ConflictExpectedDetail, strconv.FormatUint(first.Expected, 10))
+	err.WithDetail(ConflictVersionDetail, strconv.FormatUint(first.Version, 10))
+	err.WithDetail(ConflictCountDetail, strconv.Itoa(len(conflicts)))
+	return err
+}
+
+// VersionConflictOf returns the first conflict described by the given ErrCode_VersionConflict error (e.g. as received by a
+// client via TxMsg.CloseErr), or false if err is not one.  Exists is not conveyed.
+func VersionConflictOf(err error) (VersionConflict, bool) {
+	var c VersionConflict
+	ampErr, ok := err.(*Err)
+	if !ok || ampErr.Code != ErrCode_VersionConflict {
+		return c, false
+	}
+	ids := []struct {
+		key string
+		dst *tag.ID
+	}{
+		{ConflictCellDetail, &c.CellID},
+		{ConflictAttrDetail, &c.AttrID},
+		{ConflictSIDetail, &c.SI},
+	}
+	for _, id := range ids {
+		str, _ := ampErr.Detail(id.key)
+		parsed, parseErr := tag.ParseID(str)
+		if parseErr != nil {
+			return c, false
+		}
+		*id.dst = parsed
+	}
+	expected, _ := ampErr.Detail(ConflictExpectedDetail)
+	version, _ := ampErr.Detail(ConflictVersionDetail)
+	var parseErr error
+	if c.Expected, parseErr = strconv.ParseUint(expected, 10, 64); parseErr != nil {
+		return c, false
+	}
+	if c.Version, parseErr = strconv.ParseUint(version, 10, 64); parseErr != nil {
+		return c, false
+	}
+	return c, true
+}
+
+// AttrVersions holds the version stamps of attr items -- concurrency safe.
+type AttrVersions struct {
+	epoch uint64 // high 32 bits of each version (see Attr versioning)
+	mu    sync.Mutex
+	items map[versionItem]versionState
+}
+
+type versionItem struct {
+	cellID, attrID, SI tag.ID
+}
+
+type versionState struct {
+	writes uint32
+	exists bool
+}
+
+// NewAttrVersions returns an empty AttrVersions having a new epoch.
+func NewAttrVersions() *AttrVersions {
+	// An epoch of 0 or MaxUint32 could form a version of 0 or VersionAbsent
+	epoch := 1 + rand.Uint32N(math.MaxUint32-1)
+	return &AttrVersions{
+		epoch: uint64(epoch) << 32,
+		items: make(map[versionItem]versionState),
+	}
+}
+
+// Version returns the version of the given attr item, whose low 32 bits are 0 if it has never been written.
+func (av *AttrVersions) Version(cellID, attrID, SI tag.ID) uint64 {
+	av.mu.Lock()
+	defer av.mu.Unlock()
+	return av.version(av.items[versionItem{cellID, attrID, SI}])
+}
+
+func (av *AttrVersions) version(state versionState) uint64 {
+	return av.epoch | uint64(state.writes)
+}
+
+// Check returns the conflicts of the given commit's expected versions with the current versions of the items it writes.
+func (av *AttrVersions) Check(commit *TxMsg) []VersionConflict {
+	av.mu.Lock()
+	defer av.mu.Unlock()
+	return av.checkLocked(commit)
+}
+
+// Commit advances the version of each item the given commit writes if none of its expected versions is stale.  Otherwise, it
+// returns an ErrCode_VersionConflict error (see VersionConflictError) and no version is advanced.  The commit is not modified.
+func (av *AttrVersions) Commit(commit *TxMsg) error {
+	_, err := av.commit(commit)
+	return err
+}
+
+// commit is Commit, also returning a func that reverts the versions it advanced, e.g. if the app then fails to apply the commit.
+// Reverting leaves an item as is if it has been written again since.
+func (av *AttrVersions) commit(commit *TxMsg) (revert func(), err error) {
+	av.mu.Lock()
+	defer av.mu.Unlock()
+
+	if err = VersionConflictError(av.checkLocked(commit)); err != nil {
+		return nil, err
+	}
+	prev := av.advanceLocked(commit, false)
+	advanced := make(map[versionItem]versionState, len(prev))
+	for item := range prev {
+		advanced[item] = av.items[item]
+	}
+	revert = func() {
+		av.mu.Lock()
+		defer av.mu.Unlock()
+		for item, state := range prev {
+			if av.items[item] == advanced[item] {
+				av.items[item] = state
+			}
+		}
+	}
+	return revert, nil
+}
+
+// Advance unconditionally advances the version of each item the given tx writes, setting the Height of each of its upserts and
+// deletes to the item's new version -- used for writes an app makes itself.
+func (av *AttrVersions) Advance(tx *TxMsg) {
+	av.mu.Lock()
+	defer av.mu.Unlock()
+	av.advanceLocked(tx, true)
+}
+
+// Stamp sets the Height of each upsert and delete of the given tx (e.g. to be pushed to a client) to the current version of the
+// item it writes.
+func (av *AttrVersions) Stamp(tx *TxMsg) {
+	av.mu.Lock()
+	defer av.mu.Unlock()
+
+	for i := range tx.Ops {
+		op := &tx.Ops[i]
+		if isVersionedOp(op.OpCode) {
+			op.Height = av.version(av.items[versionItem{op.TargetID, op.AttrID, op.SI}])
+		}
+	}
+}
+
+func isVersionedOp(opCode TxOpCode) bool {
+	switch opCode {
+	case TxOpCode_UpsertAttr, TxOpCode_SnapshotAttr, TxOpCode_DeleteAttr:
+		return true
+	}
+	return false
+}
+
+// checkLocked checks each op against the versions as they were before the commit -- av.mu must be locked.
+func (av *AttrVersions) checkLocked(commit *TxMsg) []VersionConflict {
+	var conflicts []VersionConflict
+	for _, op := range commit.Ops {
+		if !isVersionedOp(op.OpCode) || op.Height == 0 {
+			continue
+		}
+		state := av.items[versionItem{op.TargetID, op.AttrID, op.SI}]
+		stale := av.version(state) != op.Height
+		if op.Height == VersionAbsent {
+			stale = state.exists
+		}
+		if stale {
+			conflicts = append(conflicts, VersionConflict{
+				CellID:   op.TargetID,
+				AttrID:   op.AttrID,
+				SI:       op.SI,
+				Expected: op.Height,
+				Version:  av.version(state),
+				Exists:   state.exists,
+			})
+		}
+	}
+	return conflicts
+}
+
+// advanceLocked advances the versions of the items the given tx writes, setting the Height of its ops to them if stamp is set,
+// and returns the state of each item before the tx -- av.mu must be locked.
+func (av *AttrVersions) advanceLocked(tx *TxMsg, stamp bool) map[versionItem]versionState {
+	prev := make(map[versionItem]versionState)
+	advance := func(item versionItem, state versionState, exists bool) versionState {
+		if _, seen := prev[item]; !seen {
+			prev[item] = state
+		}
+		state.writes++
+		state.exists = exists
+		av.items[item] = state
+		return state
+	}
+
+	for i := range tx.Ops {
+		op := &tx.Ops[i]
+		if op.OpCode == TxOpCode_DeleteCell {
+			for item, state := range av.items {
+				if item.cellID == op.TargetID && state.exists {
+					advance(item, state, false)
+				}
+			}
+			continue
+		}
+		if !isVersionedOp(op.OpCode) {
+			continue
+		}
+		item := versionItem{op.TargetID, op.AttrID, op.SI}
+		state := advance(item, av.items[item], op.OpCode != TxOpCode_DeleteAttr)
+		if stamp {
+			op.Height = av.version(state)
+		}
+	}
+	return prev
+}
+
+// Enforce wraps the given AppInstance so that each request's CommitTx is checked and versioned via Commit before it is served,
+// rejecting a stale commit with ErrCode_VersionConflict, and each tx pushed to a request is stamped via Stamp.  If the app fails
+// to serve a commit, the versions it advanced are reverted, so a client may retry it with the same expected versions.
+//
+// A host calls this when it issues an AppInstance to a HostSession, before wrapping it further, so that a request reaches it only
+// once every other wrapper has admitted it (e.g. ValidateCommits) and pushed txs are stamped before they are filtered.
+func (av *AttrVersions) Enforce(inst AppInstance) AppInstance {
+	return &versionedApp{
+		AppInstance: inst,
+		av:          av,
+	}
+}
+
+func (av *AttrVersions) serve(pinner Pinner, req Requester) (Pin, error) {
+	revert := func() {}
+	if commit := req.Request().CommitTx; commit != nil {
+		var err error
+		if revert, err = av.commit(commit); err != nil {
+			return nil, err
+		}
+	}
+	pin, err := pinner.ServeRequest(&versionedRequester{
+		Requester: req,
+		av:        av,
+	})
+	if err != nil {
+		revert()
+		return nil, err
+	}
+	if pin == nil {
+		return nil, nil
+	}
+	return &versionedPin{Pin: pin, av: av}, nil
+}
+
+type versionedApp struct {
+	AppInstance
+	av *AttrVersions
+}
+
+func (app *versionedApp) ServeRequest(req Requester) (Pin, error) {
+	return app.av.serve(app.AppInstance, req)
+}
+
+type versionedPin struct {
+	Pin
+	av *AttrVersions
+}
+
+func (pin *versionedPin) ServeRequest(req Requester) (Pin, error) {
+	return pin.av.serve(pin.Pin, req)
+}
+
+type versionedRequester struct {
+	Requester
+	av *AttrVersions
+}
+
+func (req *versionedRequester) PushTx(tx *TxMsg) error {
+	req.av.Stamp(tx)
+	return req.Requester.PushTx(tx)
+}
@@ -2,12 +2,34 @@ package amp
 
 import (
 	"bytes"
+	"context"
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/json"
 	fmt "fmt"
 	io "io"
+	"net/http"
 	"reflect"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/bufs"
+	"github.com/amp-3d/amp-sdk-go/stdlib/geo"
+	"github.com/amp-3d/amp-sdk-go/stdlib/media"
+	"github.com/amp-3d/amp-sdk-go/stdlib/media/cue"
+	"github.com/amp-3d/amp-sdk-go/stdlib/media/derive"
+	"github.com/amp-3d/amp-sdk-go/stdlib/media/metadata"
+	"github.com/amp-3d/amp-sdk-go/stdlib/media/playlist"
+	"github.com/amp-3d/amp-sdk-go/stdlib/metrics"
+	"github.com/amp-3d/amp-sdk-go/stdlib/symbol"
+	"github.com/amp-3d/amp-sdk-go/stdlib/symbol/memory_table"
 	"github.com/amp-3d/amp-sdk-go/stdlib/tag"
+	"github.com/amp-3d/amp-sdk-go/stdlib/task"
+	"github.com/amp-3d/amp-sdk-go/stdlib/utils"
 )
 
 func TestTxSerialize(t *testing.T) {
@@ -85,6 +107,12 @@ func TestTxSerialize(t *testing.T) {
 			t.Errorf("ReadTxMsg failed: Op mismatch")
 		}
 	}
+
+	// An op whose value lies outside the data store (even by overflowing) is refused rather than sliced
+	tx2.Ops[0].DataOfs, tx2.Ops[0].DataLen = ^uint64(0), 2
+	if err := tx2.UnmarshalOpValue(0, &Login{}); err == nil {
+		t.Errorf("expected UnmarshalOpValue to refuse an out of bounds op")
+	}
 }
 
 type bufReader struct {
@@ -101,6 +129,207 @@ func (r *bufReader) Read(p []byte) (n int, err error) {
 	return n, nil
 }
 
+// FuzzReadTxMsg checks that ReadTxMsg rejects (rather than panics on) any malformed tx, and that each op it returns
+// has its value within the tx's data store.
+func FuzzReadTxMsg(f *testing.F) {
+	tx := NewTxMsg(true)
+	op := TxOp{
+		OpCode:   TxOpCode_UpsertAttr,
+		TargetID: tag.ID{4, 555, 666},
+		AttrID:   tag.ID{111312232, 22232334444},
+	}
+	tx.MarshalOp(&op, &Login{UserUID: "alan1"})
+	op.SI[1] = 50454123
+	tx.MarshalOpWithBuf(&op, []byte("hello-world"))
+	var txBuf []byte
+	tx.MarshalToBuffer(&txBuf)
+	tx.ReleaseRef()
+
+	f.Add(txBuf)
+	f.Add(txBuf[:Const_TxHeader_Size])
+	f.Add(txBuf[:len(txBuf)-1])
+	f.Fuzz(func(t *testing.T, buf []byte) {
+		tx, err := ReadTxMsg(bytes.NewReader(buf))
+		if err != nil {
+			return
+		}
+		defer tx.ReleaseRef()
+		for i, op := range tx.Ops {
+			if op.DataOfs+op.DataLen > uint64(len(tx.DataStore)) || op.DataOfs+op.DataLen < op.DataOfs {
+				t.Fatalf("op %d: value [%d:+%d] is outside the data store (%d bytes)", i, op.DataOfs, op.DataLen, len(tx.DataStore))
+			}
+			var val Login
+			tx.UnmarshalOpValue(i, &val) // may fail, but not panic
+		}
+	})
+}
+
+func TestTxBufs(t *testing.T) {
+	bufs.DefaultPool.SetDebug(true)
+	defer bufs.DefaultPool.SetDebug(false)
+	bufs.DefaultPool.Close()
+
+	// A tx's DataStore is pooled, and returned to the pool once the tx is released
+	for i := 0; i < 3; i++ {
+		tx := NewTxMsg(true)
+		for j := 0; j < 100; j++ {
+			tx.MarshalUpsert(tag.ID{uint64(j + 1)}, PinnedTabSpec.ID, &TagTab{Label: strings.Repeat("x", 10*j)})
+		}
+		var txBuf []byte
+		tx.MarshalToBuffer(&txBuf)
+		tx.ReleaseRef()
+
+		tx, err := ReadTxMsg(bytes.NewReader(txBuf))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var tab TagTab
+		if err = tx.UnmarshalOpValue(99, &tab); err != nil || len(tab.Label) != 990 {
+			t.Fatalf("unexpected tab %v (%v)", tab.Label, err)
+		}
+		tx.ReleaseRef()
+	}
+	if err := bufs.DefaultPool.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// A tx never released is reported as a leak
+	NewTxMsg(true).MarshalUpsert(tag.New(), PinnedTabSpec.ID, &TagTab{Label: "leaked"})
+	if err := bufs.DefaultPool.Close(); err == nil || !strings.Contains(err.Error(), "amp.TestTxBufs") {
+		t.Fatalf("expected leak, got %v", err)
+	}
+}
+
+// newBenchTx returns a tx upserting the given number of attrs, each having a value of about 200 bytes.
+func newBenchTx(numOps int) *TxMsg {
+	tx := NewTxMsg(true)
+	for i := 0; i < numOps; i++ {
+		tx.MarshalUpsert(tag.ID{uint64(i + 1)}, PinnedTabSpec.ID, &TagTab{
+			Label:   fmt.Sprintf("item %d", i),
+			Caption: strings.Repeat("c", 100),
+			About:   strings.Repeat("a", 80),
+		})
+	}
+	return tx
+}
+
+func TestTxDerive(t *testing.T) {
+	bufs.DefaultPool.SetDebug(true)
+	defer bufs.DefaultPool.SetDebug(false)
+	bufs.DefaultPool.Close()
+
+	// A derived tx references the values of the ops carried into it, keeping its source alive until released
+	tx := newBenchTx(10)
+	tx.Status = OpStatus_Synced
+	derived := tx.Derive()
+	for i := 0; i < len(tx.Ops); i += 2 {
+		derived.CarryOp(&tx.Ops[i])
+	}
+	tx.ReleaseRef()
+	if derived.Status != OpStatus_Synced || len(derived.Ops) != 5 {
+		t.Fatalf("unexpected derived tx %+v", derived.TxInfo)
+	}
+	var tab TagTab
+	if err := derived.UnmarshalOpValue(1, &tab); err != nil || tab.Label != "item 2" {
+		t.Fatalf("unexpected tab %q (%v)", tab.Label, err)
+	}
+
+	// A derived tx is written (and read back) as if its values were its own
+	var want []byte
+	derived.MarshalToBuffer(&want)
+	var written bytes.Buffer
+	if n, err := derived.WriteTo(&written); err != nil || n != int64(len(want)) || !bytes.Equal(written.Bytes(), want) {
+		t.Fatalf("WriteTo wrote %d bytes (%v), expected %d", n, err, len(want))
+	}
+	readBack, err := ReadTxMsg(bytes.NewReader(written.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = readBack.UnmarshalOpValue(4, &tab); err != nil || tab.Label != "item 8" {
+		t.Fatalf("unexpected tab %q (%v)", tab.Label, err)
+	}
+	readBack.ReleaseRef()
+
+	// Marshalling a new value into a derived tx gives it a DataStore of its own, leaving its source unmodified
+	source := newBenchTx(3)
+	derived2 := source.Derive()
+	derived2.CarryOp(&source.Ops[2])
+	derived2.MarshalUpsert(tag.New(), PinnedTabSpec.ID, &TagTab{Label: "new"})
+	sourceStore := append([]byte(nil), source.DataStore...)
+	if &derived2.DataStore[0] == &source.DataStore[0] || !bytes.Equal(source.DataStore, sourceStore) {
+		t.Fatal("expected derived tx to copy its source's DataStore")
+	}
+	if err = derived2.UnmarshalOpValue(0, &tab); err != nil || tab.Label != "item 2" {
+		t.Fatalf("unexpected tab %q (%v)", tab.Label, err)
+	}
+	if err = derived2.UnmarshalOpValue(1, &tab); err != nil || tab.Label != "new" {
+		t.Fatalf("unexpected tab %q (%v)", tab.Label, err)
+	}
+	source.ReleaseRef()
+	derived2.ReleaseRef()
+	derived.ReleaseRef()
+	if err = bufs.DefaultPool.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// BenchmarkTxSend compares marshalling a tx into a buffer (copying its values) with writing it as is via WriteTo.
+func BenchmarkTxSend(b *testing.B) {
+	tx := newBenchTx(100)
+	defer tx.ReleaseRef()
+	var scrap []byte
+	tx.MarshalToBuffer(&scrap)
+	size := int64(len(scrap))
+
+	b.Run("MarshalToBuffer", func(b *testing.B) {
+		b.SetBytes(size)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			tx.MarshalToBuffer(&scrap)
+			io.Discard.Write(scrap)
+		}
+	})
+	b.Run("WriteTo", func(b *testing.B) {
+		b.SetBytes(size)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			tx.WriteTo(io.Discard)
+		}
+	})
+}
+
+// BenchmarkTxSubset compares forming a tx holding half the ops of another by copying their values with deriving it.
+func BenchmarkTxSubset(b *testing.B) {
+	tx := newBenchTx(100)
+	defer tx.ReleaseRef()
+	size := int64(len(tx.DataStore) / 2)
+
+	b.Run("Copy", func(b *testing.B) {
+		b.SetBytes(size)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			subset := NewTxMsg(false)
+			subset.TxInfo = tx.TxInfo
+			for j := 0; j < len(tx.Ops); j += 2 {
+				op := tx.Ops[j]
+				subset.MarshalOpWithBuf(&op, tx.DataStore[op.DataOfs:op.DataOfs+op.DataLen])
+			}
+			subset.ReleaseRef()
+		}
+	})
+	b.Run("Derive", func(b *testing.B) {
+		b.SetBytes(size)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			subset := tx.Derive()
+			for j := 0; j < len(tx.Ops); j += 2 {
+				subset.CarryOp(&tx.Ops[j])
+			}
+			subset.ReleaseRef()
+		}
+	})
+}
+
 func TestRegistry(t *testing.T) {
 	reg := NewRegistry()
 	spec := reg.RegisterPrototype(tag.FormSpec(AttrSpec, "av"), &Tag{}, "")
@@ -127,3 +356,2394 @@ func TestRegistry(t *testing.T) {
 		t.Fatalf("NewAttrElem returned wrong type: %v", reflect.TypeOf(elem))
 	}
 }
+
+func TestRegistryHotPlug(t *testing.T) {
+	reg := NewRegistry()
+
+	var events []AppEvent
+	cancel := reg.WatchApps(func(ev AppEvent) {
+		events = append(events, ev)
+	})
+
+	appV1 := &App{
+		AppSpec:     tag.FormSpec(AppSpec, "test.hotplug"),
+		Invocations: []string{"hp"},
+	}
+	other := &App{
+		AppSpec:     tag.FormSpec(AppSpec, "test.other"),
+		Invocations: []string{"shared"},
+	}
+	if err := reg.RegisterApp(appV1); err != nil {
+		t.Fatal(err)
+	}
+	if app, err := reg.GetAppForInvocation("hp"); err != nil || app != appV1 {
+		t.Fatalf("GetAppForInvocation failed: %v", err)
+	}
+
+	// Replacing an app deregisters the previous version
+	appV2 := &App{
+		AppSpec:     appV1.AppSpec,
+		Invocations: []string{"shared"},
+	}
+	reg.RegisterApp(appV2)
+	reg.RegisterApp(other) // claims "shared" from appV2
+	if _, err := reg.GetAppForInvocation("hp"); err == nil {
+		t.Fatal("stale invocation still registered")
+	}
+
+	if err := reg.DeregisterApp(appV1.AppSpec.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := reg.GetAppByTag(appV1.AppSpec.ID); err == nil {
+		t.Fatal("deregistered app still registered")
+	}
+	if app, err := reg.GetAppForInvocation("shared"); err != nil || app != other {
+		t.Fatal("deregistering removed another app's invocation")
+	}
+	if err := reg.DeregisterApp(appV1.AppSpec.ID); err == nil {
+		t.Fatal("expected error deregistering an unregistered app")
+	}
+
+	expected := []AppEvent{
+		{AppRegistered, appV1},
+		{AppDeregistered, appV1},
+		{AppRegistered, appV2},
+		{AppRegistered, other},
+		{AppDeregistered, appV2},
+	}
+	if !reflect.DeepEqual(events, expected) {
+		t.Fatalf("unexpected events: %v", events)
+	}
+	cancel()
+	reg.DeregisterApp(other.AppSpec.ID)
+	if len(events) != len(expected) {
+		t.Fatal("watcher called after cancel")
+	}
+
+	// Draining waits for open pins to close, and forces close after the grace period
+	root, _ := task.Start(&task.Task{Label: "host"})
+	defer root.Close()
+	for _, test := range []struct {
+		pinLifetime time.Duration
+		gracePeriod time.Duration
+	}{
+		{50 * time.Millisecond, time.Minute},
+		{time.Hour, 50 * time.Millisecond},
+	} {
+		inst, _ := root.StartChild(&task.Task{Label: "app"})
+		pin, _ := inst.StartChild(&task.Task{
+			Label:     "pin",
+			IdleClose: time.Nanosecond,
+			OnRun: func(ctx task.Context) {
+				select {
+				case <-ctx.Closing():
+				case <-time.After(test.pinLifetime):
+				}
+			},
+		})
+		DrainAppInstance(inst, test.gracePeriod)
+		select {
+		case <-inst.Done():
+		case <-time.After(5 * time.Second):
+			t.Fatal("app instance was not drained")
+		}
+		<-pin.Done()
+	}
+}
+
+func TestRegistryDescribe(t *testing.T) {
+	reg := NewRegistry()
+	attrSpec := reg.RegisterPrototype(tag.FormSpec(AttrSpec, "av"), &Tag{}, "")
+	reg.RegisterApp(&App{
+		AppSpec:     tag.FormSpec(AppSpec, "test.describe"),
+		Desc:        "describes itself",
+		Version:     "v1.2.3",
+		Invocations: []string{"describe"},
+	})
+
+	info := reg.Describe()
+	if len(info.Apps) != 1 || len(info.Attrs) != 1 {
+		t.Fatalf("unexpected RegistryInfo: %+v", info)
+	}
+	app := info.Apps[0]
+	if app.AppSpec != "amp.app.test.describe" || app.Version != "v1.2.3" || !reflect.DeepEqual(app.Invocations, []string{"amp.app.test.describe", "describe"}) {
+		t.Fatalf("unexpected AppInfo: %+v", app)
+	}
+	if attr := info.Attrs[0]; attr.Spec != attrSpec.Canonic || attr.ID != attrSpec.ID || attr.ElemType != (&Tag{}).ElemTypeName() {
+		t.Fatalf("unexpected AttrInfo: %+v", attr)
+	}
+
+	buf, err := json.Marshal(info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded RegistryInfo
+	if err := json.Unmarshal(buf, &decoded); err != nil || !reflect.DeepEqual(decoded, info) {
+		t.Fatalf("JSON round trip failed: %s %v", buf, err)
+	}
+}
+
+func TestVersionConstraints(t *testing.T) {
+	for _, tc := range []struct {
+		constraint string
+		allows     []string
+		rejects    []string
+	}{
+		{"", []string{"0.0.1", "v9.9.9"}, nil},
+		{"^1.2.3", []string{"1.2.3", "v1.9.0"}, []string{"1.2.2", "2.0.0", "1.3.0-beta"}},
+		{"^0.2.3", []string{"0.2.3", "0.2.9"}, []string{"0.3.0"}},
+		{"~1.2", []string{"1.2.0", "1.2.7"}, []string{"1.3.0", "1.1.9"}},
+		{"1.x", []string{"1.0.0", "1.99.0"}, []string{"2.0.0", "0.9.0"}},
+		{">=1.4, <2 || 3.1.4", []string{"1.4.0", "1.5.1", "3.1.4"}, []string{"1.3.9", "2.0.0", "3.1.5"}},
+		{"!=1.0.0", []string{"1.0.1"}, []string{"1.0.0"}},
+		{">=2.0.0-beta.2", []string{"2.0.0-beta.10", "2.0.0", "2.1.0"}, []string{"2.0.0-beta.1", "2.1.0-alpha"}},
+	} {
+		vc, err := ParseConstraint(tc.constraint)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, str := range tc.allows {
+			if v, _ := ParseVersion(str); !vc.Allows(v) {
+				t.Errorf("%q should allow %q", tc.constraint, str)
+			}
+		}
+		for _, str := range tc.rejects {
+			if v, _ := ParseVersion(str); vc.Allows(v) {
+				t.Errorf("%q should reject %q", tc.constraint, str)
+			}
+		}
+	}
+
+	ordered := []string{"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta.2", "1.0.0-beta.11", "1.0.0-rc.1", "1.0.0", "v1.0.1"}
+	for i := 1; i < len(ordered); i++ {
+		a, _ := ParseVersion(ordered[i-1])
+		b, _ := ParseVersion(ordered[i])
+		if a.Compare(b) >= 0 || b.Compare(a) <= 0 {
+			t.Errorf("expected %v < %v", a, b)
+		}
+	}
+
+	for _, bad := range []string{"1.2.3.4", "v1.x", "a.b", "1.0.0-"} {
+		if _, err := ParseVersion(bad); err == nil {
+			t.Errorf("ParseVersion(%q) should fail", bad)
+		}
+	}
+	for _, bad := range []string{">=1.x", "^", "1 ||", "^1.2 ~x.y"} {
+		if _, err := ParseConstraint(bad); err == nil {
+			t.Errorf("ParseConstraint(%q) should fail", bad)
+		}
+	}
+}
+
+func TestRegistryResolve(t *testing.T) {
+	reg := NewRegistry()
+	base := &App{
+		AppSpec: tag.FormSpec(AppSpec, "test.base"),
+		Version: "v1.4.0",
+	}
+	codec := &App{
+		AppSpec: tag.FormSpec(AppSpec, "test.codec"),
+		Version: "v0.3.1",
+		Requires: []AppDependency{
+			{AppID: base.AppSpec.ID, Constraint: "^1.2"},
+		},
+	}
+	player := &App{
+		AppSpec: tag.FormSpec(AppSpec, "test.player"),
+		Requires: []AppDependency{
+			{AppID: codec.AppSpec.ID, Constraint: "~0.3"},
+			{AppID: base.AppSpec.ID},
+		},
+	}
+	for _, app := range []*App{player, codec, base} {
+		if err := reg.RegisterApp(app); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	graph, err := reg.ResolveApps()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var order []string
+	for _, node := range graph.Nodes {
+		order = append(order, node.AppSpec)
+	}
+	if !reflect.DeepEqual(order, []string{"amp.app.test.base", "amp.app.test.codec", "amp.app.test.player"}) {
+		t.Fatalf("unexpected resolve order: %v", order)
+	}
+	if node := graph.Node(player.AppSpec.ID); node == nil || node.App != player || len(node.Requires) != 2 {
+		t.Fatalf("unexpected player node: %+v", node)
+	}
+
+	// Malformed versions and constraints are rejected on registration
+	if err := reg.RegisterApp(&App{AppSpec: tag.FormSpec(AppSpec, "test.bad"), Version: "one"}); err == nil {
+		t.Fatal("expected bad version error")
+	}
+	if err := reg.RegisterApp(&App{AppSpec: tag.FormSpec(AppSpec, "test.bad"), Requires: []AppDependency{{AppID: base.AppSpec.ID, Constraint: ">>1"}}}); err == nil {
+		t.Fatal("expected bad constraint error")
+	}
+
+	// Every conflict is reported at once
+	reg.RegisterApp(&App{
+		AppSpec: tag.FormSpec(AppSpec, "test.base"),
+		Version: "v2.0.0",
+	})
+	missing := tag.FormSpec(AppSpec, "test.missing")
+	reg.RegisterApp(&App{
+		AppSpec: tag.FormSpec(AppSpec, "test.plugin"),
+		Requires: []AppDependency{
+			{AppID: missing.ID},
+			{AppID: codec.AppSpec.ID, Constraint: ">=1"},
+		},
+	})
+	_, err = reg.ResolveApps()
+	if err == nil {
+		t.Fatal("expected resolve error")
+	}
+	for _, expect := range []string{"test.codec requires amp.app.test.base ^1.2, but v2.0.0 is registered", missing.ID.String(), "test.plugin requires amp.app.test.codec >=1"} {
+		if !strings.Contains(err.Error(), expect) {
+			t.Errorf("expected %q in error: %v", expect, err)
+		}
+	}
+
+	// Cycles are detected
+	reg = NewRegistry()
+	a, b := tag.FormSpec(AppSpec, "test.a"), tag.FormSpec(AppSpec, "test.b")
+	reg.RegisterApp(&App{AppSpec: a, Requires: []AppDependency{{AppID: b.ID}}})
+	reg.RegisterApp(&App{AppSpec: b, Requires: []AppDependency{{AppID: a.ID}}})
+	if _, err = reg.ResolveApps(); err == nil || !strings.Contains(err.Error(), "cycle") {
+		t.Fatalf("expected cycle error, got %v", err)
+	}
+}
+
+func TestMessageBus(t *testing.T) {
+	type fileChanged struct {
+		Path string
+	}
+	changed := FormTopic[fileChanged]("filesys.changed")
+
+	root, err := task.Start(&task.Task{Label: "TestMessageBus"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer root.Close()
+
+	bus := NewMessageBus()
+	indexer := Subscribe(root, bus, changed, utils.MailboxOpts{})
+	slow := Subscribe(root, bus, changed, utils.MailboxOpts{Capacity: 2, Overflow: utils.DropOldest})
+
+	for _, path := range []string{"/a", "/b", "/c"} {
+		if n := Publish(bus, changed, fileChanged{path}); n != 2 {
+			t.Fatalf("expected 2 subscribers, got %d", n)
+		}
+	}
+	bus.Publish(changed.ID, "not a fileChanged") // dropped by typed subscribers
+
+	if got := indexer.RetrieveAll(); !reflect.DeepEqual(got, []fileChanged{{"/a"}, {"/b"}, {"/c"}}) {
+		t.Fatalf("unexpected messages: %v", got)
+	}
+	if got := slow.RetrieveAll(); !reflect.DeepEqual(got, []fileChanged{{"/b"}, {"/c"}}) || slow.Stats().Dropped != 1 {
+		t.Fatalf("unexpected messages: %v (stats %+v)", got, slow.Stats())
+	}
+
+	indexer.Unsubscribe()
+	if n := Publish(bus, changed, fileChanged{"/d"}); n != 1 {
+		t.Fatalf("expected 1 subscriber, got %d", n)
+	}
+
+	// Subscriptions end when their context closes
+	sub, err := root.StartChild(&task.Task{Label: "sub"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	Subscribe(sub, bus, changed, utils.MailboxOpts{})
+	if n := Publish(bus, changed, fileChanged{"/e"}); n != 2 {
+		t.Fatalf("expected 2 subscribers, got %d", n)
+	}
+	sub.Close()
+	<-sub.Done()
+	for deadline := time.Now().Add(5 * time.Second); Publish(bus, changed, fileChanged{"/f"}) != 1; time.Sleep(time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("subscription not removed on context close")
+		}
+	}
+}
+
+// pipeTransport is one end of an in-memory Transport pair; closing either end drops the link.
+type pipeTransport struct {
+	send  chan<- []byte
+	recv  <-chan []byte
+	done  chan struct{}
+	close *sync.Once
+}
+
+func newPipe() (client, host *pipeTransport) {
+	c2h, h2c := make(chan []byte, 64), make(chan []byte, 64)
+	done, once := make(chan struct{}), &sync.Once{}
+	return &pipeTransport{c2h, h2c, done, once}, &pipeTransport{h2c, c2h, done, once}
+}
+
+func (p *pipeTransport) Label() string { return "pipe" }
+
+func (p *pipeTransport) Close() error {
+	p.close.Do(func() { close(p.done) })
+	return nil
+}
+
+func (p *pipeTransport) SendTx(tx *TxMsg) error {
+	var buf []byte
+	tx.MarshalToBuffer(&buf)
+	tx.ReleaseRef()
+	select {
+	case p.send <- buf:
+		return nil
+	case <-p.done:
+		return ErrStreamClosed
+	}
+}
+
+func (p *pipeTransport) RecvTx() (*TxMsg, error) {
+	select {
+	case buf := <-p.recv:
+		return ReadTxMsg(bytes.NewReader(buf))
+	case <-p.done:
+		return nil, ErrStreamClosed
+	}
+}
+
+func TestSessionResume(t *testing.T) {
+	type accepted struct {
+		tr      *ResumableTransport
+		resumed bool
+		err     error
+	}
+	table := NewResumeTable(ResumeOpts{GraceWindow: 5 * time.Second})
+	acceptCh := make(chan accepted, 4)
+	dial := func() (Transport, error) {
+		client, host := newPipe()
+		go func() {
+			tr, resumed, err := table.Accept(host)
+			acceptCh <- accepted{tr, resumed, err}
+		}()
+		return client, nil
+	}
+	waitFor := func(desc string, cond func() bool) {
+		for deadline := time.Now().Add(5 * time.Second); !cond(); time.Sleep(time.Millisecond) {
+			if time.Now().After(deadline) {
+				t.Fatalf("timed out waiting for %s", desc)
+			}
+		}
+	}
+
+	client, err := DialReconnecting(ReconnectOpts{Dial: dial, MinBackoff: time.Millisecond, AckEvery: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	login := NewTxMsg(true)
+	loginBuf, _ := (&Login{UserUID: "user-1"}).MarshalToStore(nil)
+	login.MarshalOpWithBuf(&TxOp{OpCode: TxOpCode_MetaAttr, AttrID: tag.FormSpec(MetaAttrSpec, "Login").ID}, loginBuf)
+	if err = client.SendTx(login); err != nil {
+		t.Fatal(err)
+	}
+	acc := <-acceptCh
+	if acc.err != nil || acc.resumed {
+		t.Fatalf("unexpected accept: %+v", acc)
+	}
+	host := acc.tr
+	hostRecv := make(chan error, 1)
+	go func() {
+		for {
+			tx, err := host.RecvTx()
+			if err != nil {
+				hostRecv <- err
+				return
+			}
+			tx.ReleaseRef()
+		}
+	}()
+
+	reqID := tag.New()
+	push := func(tr Transport, n int) {
+		tx := NewTxMsg(true)
+		tx.SetRequestID(reqID)
+		tx.MarshalUpsert(reqID, PinnedTabSpec.ID, &Tag{URL: fmt.Sprint(n)})
+		if err := tr.SendTx(tx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expect := func(n int) {
+		tx, err := client.RecvTx()
+		if err != nil {
+			t.Fatal(err)
+		}
+		val := &Tag{}
+		if err = tx.UnmarshalOpValue(0, val); err != nil || val.URL != fmt.Sprint(n) || tx.RequestID() != reqID {
+			t.Fatalf("expected tx %d, got %q (seq %d): %v", n, val.URL, tx.Seq, err)
+		}
+	}
+
+	for n := 1; n <= 3; n++ {
+		push(host, n)
+	}
+	for n := 1; n <= 3; n++ {
+		expect(n)
+	}
+
+	// Drop the link; txs sent while suspended are replayed once the client reconnects
+	client.mu.Lock()
+	client.raw.Close()
+	client.mu.Unlock()
+	waitFor("host to suspend", host.Suspended)
+
+	push(host, 4)
+	push(host, 5)
+	expect(4)
+	acc = <-acceptCh
+	if acc.err != nil || !acc.resumed || acc.tr != host {
+		t.Fatalf("expected session to resume: %+v", acc)
+	}
+	expect(5)
+	push(host, 6)
+	expect(6)
+
+	// Acknowledged txs are released from the backlog
+	waitFor("backlog to be acknowledged", func() bool {
+		host.mu.Lock()
+		defer host.mu.Unlock()
+		return len(host.backlog) <= 1
+	})
+
+	// Once the grace window expires, the client is told its session is gone and continues on a new session
+	table.opts.GraceWindow = 10 * time.Millisecond
+	client.mu.Lock()
+	client.raw.Close()
+	client.mu.Unlock()
+	if err = <-hostRecv; err != ErrStreamClosed {
+		t.Fatalf("expected session to expire, got %v", err)
+	}
+
+	_, err = client.RecvTx()
+	if ampErr, _ := err.(*Err); ampErr == nil || ampErr.Code != ErrCode_SessionExpired {
+		t.Fatalf("expected ErrCode_SessionExpired, got %v", err)
+	}
+	acc = <-acceptCh
+	if acc.err != nil || acc.resumed || acc.tr == host {
+		t.Fatalf("expected a new session: %+v", acc)
+	}
+	push(acc.tr, 1)
+	expect(1)
+}
+
+func TestKeepalive(t *testing.T) {
+	clientRaw, hostRaw := newPipe()
+	deadErr := make(chan error, 1)
+	host := NewKeepaliveTransport(hostRaw, KeepaliveOpts{
+		Interval: 5 * time.Millisecond,
+		Timeout:  50 * time.Millisecond,
+		OnDead:   func(err error) { deadErr <- err },
+	})
+	defer host.Close()
+
+	// The client answers pings until it goes quiet
+	quiet := make(chan struct{})
+	go func() {
+		for {
+			tx, err := clientRaw.RecvTx()
+			if err != nil {
+				return
+			}
+			select {
+			case <-quiet:
+			default:
+				if op := tx.Ops[0]; op.AttrID == PingAttrSpec.ID {
+					clientRaw.SendTx(marshalPing(PongAttrSpec.ID, op.Height))
+				}
+			}
+			tx.ReleaseRef()
+		}
+	}()
+
+	hostRecv := make(chan error, 1)
+	go func() {
+		_, err := host.RecvTx()
+		hostRecv <- err
+	}()
+
+	time.Sleep(150 * time.Millisecond)
+	select {
+	case err := <-hostRecv:
+		t.Fatalf("session declared dead while answering pings: %v", err)
+	default:
+	}
+	if host.RTT() <= 0 {
+		t.Fatal("expected a ping round trip")
+	}
+
+	// Once the client goes quiet, the host declares the session dead
+	close(quiet)
+	select {
+	case err := <-hostRecv:
+		if err != ErrSessionDead {
+			t.Fatalf("expected ErrSessionDead, got %v", err)
+		}
+		if err = <-deadErr; err != ErrSessionDead {
+			t.Fatalf("expected OnDead with ErrSessionDead, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("session not declared dead")
+	}
+}
+
+// testReapApp is a minimal AppInstance serving testReapPins.
+type testReapApp struct {
+	AppContext
+	ctx task.Context
+}
+
+func (app *testReapApp) Go(label string, fn func(ctx task.Context)) (task.Context, error) {
+	return app.ctx.Go(label, fn)
+}
+
+func (app *testReapApp) MakeReady(req Requester) error { return nil }
+func (app *testReapApp) OnClosing()                    {}
+
+func (app *testReapApp) ServeRequest(req Requester) (Pin, error) {
+	pin := &testReapPin{req: req}
+	var err error
+	pin.ctx, err = app.ctx.StartChild(&task.Task{Label: "pin"})
+	return pin, err
+}
+
+type testReapPin struct {
+	ctx    task.Context
+	req    Requester
+	reaped chan error
+}
+
+func (pin *testReapPin) Context() task.Context { return pin.ctx }
+
+func (pin *testReapPin) ServeRequest(req Requester) (Pin, error) {
+	return nil, ErrUnimplemented
+}
+
+func (pin *testReapPin) OnPinReaped(reason error) {
+	pin.reaped <- reason
+}
+
+type testReapRequester struct {
+	req       Request
+	completed chan error
+}
+
+func (req *testReapRequester) Request() *Request      { return &req.req }
+func (req *testReapRequester) PushTx(tx *TxMsg) error { tx.ReleaseRef(); return nil }
+func (req *testReapRequester) OnComplete(err error)   { req.completed <- err }
+
+func TestPinReaper(t *testing.T) {
+	root, err := task.Start(&task.Task{Label: "TestPinReaper"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer root.Close()
+
+	reaper, err := NewPinReaper(&testReapApp{ctx: root}, PinReaperOpts{
+		IdleTimeout: 50 * time.Millisecond,
+		CheckEvery:  5 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pin := func() (*testReapRequester, *testReapPin, Pin) {
+		req := &testReapRequester{req: Request{ID: tag.New()}, completed: make(chan error, 1)}
+		wrapped, err := reaper.ServeRequest(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		inner := wrapped.(*reapedPin).Pin.(*testReapPin)
+		inner.reaped = make(chan error, 1)
+		return req, inner, wrapped
+	}
+	idleReq, idlePin, _ := pin()
+	busyReq, busyPin, _ := pin()
+
+	// A pin kept active is not reaped while an idle one is
+	stopBusy := time.Now().Add(150 * time.Millisecond)
+	for time.Now().Before(stopBusy) {
+		reaper.Touch(busyReq.req.ID)
+		busyPin.req.PushTx(NewTxMsg(true))
+		time.Sleep(5 * time.Millisecond)
+	}
+	select {
+	case reason := <-idlePin.reaped:
+		if reason != ErrPinIdle || <-idleReq.completed != ErrPinIdle {
+			t.Fatalf("unexpected reap reason %v", reason)
+		}
+	default:
+		t.Fatal("expected idle pin to be reaped")
+	}
+	<-idlePin.ctx.Done()
+	select {
+	case <-busyPin.reaped:
+		t.Fatal("active pin was reaped")
+	default:
+	}
+
+	// ReapAll reaps all remaining pins with the given reason
+	reaper.ReapAll(ErrSessionDead)
+	if reason := <-busyPin.reaped; reason != ErrSessionDead {
+		t.Fatalf("unexpected reap reason %v", reason)
+	}
+	if reaper.NumPins() != 0 {
+		t.Fatalf("expected no pins, got %d", reaper.NumPins())
+	}
+}
+
+func TestSessionLimiterBandwidth(t *testing.T) {
+	lim := NewSessionLimiter(SessionLimits{
+		BytesPerSec: 10000,
+		BytesBurst:  1000,
+		MaxDelay:    50 * time.Millisecond,
+	})
+	tx := NewTxMsg(true)
+	tx.DataStore = make([]byte, 600)
+
+	if err := lim.throttlePush(tx, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// Over the burst, a push is delayed until the deficit is repaid
+	start := time.Now()
+	if err := lim.throttlePush(tx, nil); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 10*time.Millisecond {
+		t.Fatalf("expected push to be delayed, took %v", elapsed)
+	}
+
+	// A push that would be delayed more than MaxDelay fails
+	tx.DataStore = make([]byte, 5000)
+	if err := lim.throttlePush(tx, nil); err == nil || err.(*Err).Code != ErrCode_RateLimited {
+		t.Fatalf("expected rate limited, got %v", err)
+	}
+	if stats := lim.Stats(); stats.Throttled != 1 || stats.Dropped != 1 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+
+	// A push that fails still takes the caller's reference
+	req := &limitedRequester{Requester: &testReapRequester{}, lim: lim}
+	tx.AddRef()
+	if err := req.PushTx(tx); err == nil {
+		t.Fatal("expected rate limited")
+	}
+	if tx.refCount != 1 {
+		t.Fatalf("expected the failed push to release its reference, got %d", tx.refCount)
+	}
+}
+
+func TestSessionLimiterPins(t *testing.T) {
+	root, err := task.Start(&task.Task{Label: "TestSessionLimiterPins"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer root.Close()
+
+	// Concurrent requests can't together exceed MaxPins while their pins are being served
+	lim := NewSessionLimiter(SessionLimits{MaxPins: 2})
+	release := make(chan struct{})
+	pinner := testPinner(func(req Requester) (Pin, error) {
+		<-release
+		pin := &testReapPin{req: req}
+		var err error
+		pin.ctx, err = root.StartChild(&task.Task{Label: "pin"})
+		return pin, err
+	})
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		pins     []Pin
+		rejected int
+	)
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			pin, err := lim.serve(pinner, &testReapRequester{}, nil)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				rejected++
+			} else {
+				pins = append(pins, pin)
+			}
+		}()
+	}
+	for lim.Stats().Rejected < 3 {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	wg.Wait()
+	if len(pins) != 2 || rejected != 3 || lim.Stats().OpenPins != 2 {
+		t.Fatalf("expected 2 pins and 3 rejected, got %d and %d", len(pins), rejected)
+	}
+
+	// A pin that fails to be served releases its reservation, as does a pin once done
+	failed := testPinner(func(req Requester) (Pin, error) { return nil, ErrUnimplemented })
+	pins[0].Context().Close()
+	<-pins[0].Context().Done()
+	if _, err := lim.serve(failed, &testReapRequester{}, nil); err != ErrUnimplemented {
+		t.Fatalf("expected ErrUnimplemented, got %v", err)
+	}
+	if _, err := lim.serve(pinner, &testReapRequester{}, nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSessionLimiterSetLimits(t *testing.T) {
+	lim := NewSessionLimiter(SessionLimits{})
+	for i := 0; i < 10; i++ {
+		if err := lim.admitRequest(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Limits set later apply to subsequent requests
+	lim.SetLimits(SessionLimits{RequestsPerSec: 1, RequestBurst: 2})
+	for i := 0; i < 2; i++ {
+		if err := lim.admitRequest(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	err := lim.admitRequest()
+	if err == nil || err.(*Err).Code != ErrCode_RateLimited || !IsRetryable(err) {
+		t.Fatalf("expected rate limited, got %v", err)
+	}
+	if after, ok := err.(*Err).Detail(RetryAfterDetail); !ok || after == "0" {
+		t.Fatalf("unexpected retry after %q", after)
+	}
+
+	lim.SetLimits(SessionLimits{})
+	if err := lim.admitRequest(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCompressedTransport(t *testing.T) {
+	for _, codec := range []string{CodecZstd, CodecSnappy} {
+		clientRaw, hostRaw := newPipe()
+
+		client, err := OfferCompression(clientRaw, CompressOpts{Codecs: []string{codec}})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// The first tx received by the host after the offer is passed through
+		login := NewTxMsg(true)
+		login.MarshalUpsert(tag.New(), PinnedTabSpec.ID, &Tag{URL: "login"})
+		if err = client.SendTx(login); err != nil {
+			t.Fatal(err)
+		}
+		host, err := AcceptCompression(hostRaw, CompressOpts{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if host.Codec() != codec {
+			t.Fatalf("expected host to select %s, got %s", codec, host.Codec())
+		}
+		if tx, err := host.RecvTx(); err != nil || tx.Ops[0].OpCode != TxOpCode_UpsertAttr {
+			t.Fatalf("expected login tx, got %v", err)
+		}
+
+		// A burst of cells compresses well, while a small tx is sent as-is
+		reqID := tag.New()
+		burst := NewTxMsg(true)
+		burst.SetRequestID(reqID)
+		burst.Seq = 7
+		for i := 0; i < 1000; i++ {
+			burst.MarshalUpsert(tag.New(), PinnedTabSpec.ID, &Tag{URL: fmt.Sprintf("amp://music/library/track/%d", i), ContentType: "audio/flac"})
+		}
+		small := NewTxMsg(true)
+		small.SetRequestID(reqID)
+		small.MarshalUpsert(reqID, PinnedTabSpec.ID, &Tag{URL: "small"})
+
+		for _, tx := range []*TxMsg{burst, small} {
+			tx.AddRef()
+			if err = host.SendTx(tx); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		got, err := client.RecvTx()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if client.Codec() != codec {
+			t.Fatalf("expected client to switch to %s, got %s", codec, client.Codec())
+		}
+		if len(got.Ops) != len(burst.Ops) || got.RequestID() != reqID || got.Seq != 7 || !bytes.Equal(got.DataStore, burst.DataStore) {
+			t.Fatalf("burst tx did not survive compression (%d ops, seq %d)", len(got.Ops), got.Seq)
+		}
+		val := &Tag{}
+		if err = got.UnmarshalOpValue(999, val); err != nil || val.URL != "amp://music/library/track/999" {
+			t.Fatalf("unexpected op value %q: %v", val.URL, err)
+		}
+		if got, err = client.RecvTx(); err != nil || got.UnmarshalOpValue(0, val) != nil || val.URL != "small" {
+			t.Fatalf("expected small tx, got %q: %v", val.URL, err)
+		}
+
+		stats := host.Stats()
+		if stats.TxsSent != 2 || stats.TxsCompressed != 1 || stats.BytesSaved() <= stats.BytesOut {
+			t.Fatalf("unexpected stats: %+v", stats)
+		}
+		if client.Stats().TxsInflated != 1 {
+			t.Fatalf("unexpected client stats: %+v", client.Stats())
+		}
+		client.Close()
+		host.Close()
+	}
+
+	// With no codec in common, txs are sent uncompressed
+	clientRaw, hostRaw := newPipe()
+	if _, err := OfferCompression(clientRaw, CompressOpts{Codecs: []string{CodecSnappy}}); err != nil {
+		t.Fatal(err)
+	}
+	host, err := AcceptCompression(hostRaw, CompressOpts{Codecs: []string{CodecZstd}})
+	if err != nil || host.Codec() != CodecNone {
+		t.Fatalf("expected no codec, got %s: %v", host.Codec(), err)
+	}
+	if _, err = OfferCompression(clientRaw, CompressOpts{Codecs: []string{"lz4"}}); err == nil {
+		t.Fatal("expected unsupported codec to be rejected")
+	}
+}
+
+func TestDeltaTransport(t *testing.T) {
+	clientRaw, hostRaw := newPipe()
+	playlistSpec := tag.FormSpec(AttrSpec, "test.playlist")
+	opts := DeltaOpts{
+		Attrs:         []tag.ID{playlistSpec.ID},
+		SnapshotEvery: 3,
+	}
+	tapClient, tap := newPipe() // relays what goes over the wire to the client so it can be inspected
+	host, client := NewDeltaTransport(hostRaw, opts), NewDeltaTransport(tapClient, opts)
+
+	var entries []string
+	for i := 0; i < 10000; i++ {
+		entries = append(entries, fmt.Sprintf("track-%05d", i))
+	}
+	reqID, cellID := tag.New(), tag.New()
+	push := func(status OpStatus) *Tag {
+		playlist := &Tag{URL: "playlist", Attachment: []byte(strings.Join(entries, "\n"))}
+		tx := NewTxMsg(true)
+		tx.SetRequestID(reqID)
+		tx.Status = status
+		tx.MarshalUpsert(cellID, playlistSpec.ID, playlist)
+		tx.MarshalUpsert(cellID, PinnedTabSpec.ID, &Tag{URL: "not opted in"})
+		if err := host.SendTx(tx); err != nil {
+			t.Fatal(err)
+		}
+		return playlist
+	}
+	expect := func(sent *Tag, expectOp TxOpCode) {
+		raw := <-clientRaw.recv
+		tap.send <- raw
+		wire, err := ReadTxMsg(bytes.NewReader(raw))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if wire.Ops[0].OpCode != expectOp || wire.Ops[1].OpCode != TxOpCode_UpsertAttr {
+			t.Fatalf("expected %v on the wire, got %v", expectOp, wire.Ops[0].OpCode)
+		}
+
+		tx, err := client.RecvTx()
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := &Tag{}
+		if err = tx.UnmarshalOpValue(0, got); err != nil || tx.Ops[0].OpCode != TxOpCode_UpsertAttr || !bytes.Equal(got.Attachment, sent.Attachment) || tx.Ops[0].TargetID != cellID {
+			t.Fatalf("client did not reconstruct the playlist: %v", err)
+		}
+	}
+
+	expect(push(OpStatus_Syncing), TxOpCode_SnapshotAttr)
+	for i, op := range []TxOpCode{TxOpCode_PatchAttr, TxOpCode_PatchAttr, TxOpCode_PatchAttr, TxOpCode_SnapshotAttr, TxOpCode_PatchAttr} {
+		entries[1000*i+7] = "edited"
+		entries = append(entries[:5000], append([]string{fmt.Sprint("inserted-", i)}, entries[5000:]...)...)
+		expect(push(OpStatus_Synced), op)
+	}
+
+	stats := host.Stats()
+	if stats.Snapshots != 2 || stats.Patches != 4 || stats.PatchBytes*100 > stats.FullBytes {
+		t.Fatalf("expected patches to be a small fraction of the full size: %+v", stats)
+	}
+
+	// Closing the request releases its bases on both ends
+	expect(push(OpStatus_Closed), TxOpCode_UpsertAttr)
+	if len(host.sent) != 0 || len(client.recvd) != 0 || host.sentBytes != 0 {
+		t.Fatal("expected bases to be released")
+	}
+
+	// A patch against a diverged base is rejected
+	base := []byte(strings.Repeat("abcdefgh", 100))
+	patch := makePatch(deltaHash(base), base, append([]byte("x"), base...))
+	if out, err := applyPatch(deltaHash(base), base, patch); err != nil || string(out) != "x"+string(base) {
+		t.Fatalf("patch failed: %v", err)
+	}
+	if _, err := applyPatch(deltaHash(base[1:]), base[1:], patch); err == nil {
+		t.Fatal("expected diverged base to be detected")
+	}
+}
+
+type testCoalesceRequester struct {
+	req    Request
+	mu     sync.Mutex
+	pushed []*TxMsg
+}
+
+func (req *testCoalesceRequester) Request() *Request    { return &req.req }
+func (req *testCoalesceRequester) OnComplete(err error) {}
+func (req *testCoalesceRequester) PushTx(tx *TxMsg) error {
+	req.mu.Lock()
+	req.pushed = append(req.pushed, tx)
+	req.mu.Unlock()
+	return nil
+}
+
+func (req *testCoalesceRequester) numPushed() int {
+	req.mu.Lock()
+	defer req.mu.Unlock()
+	return len(req.pushed)
+}
+
+func TestCoalescingRequester(t *testing.T) {
+	progressSpec := tag.FormSpec(AttrSpec, "test.progress")
+	inner := &testCoalesceRequester{req: Request{ID: tag.New()}}
+	cr := NewCoalescingRequester(inner, CoalesceOpts{
+		FlushInterval: 20 * time.Millisecond,
+		LatestWins:    []tag.ID{progressSpec.ID},
+	})
+	cellID := tag.New()
+	push := func(status OpStatus, attrID tag.ID, val string) {
+		tx := NewTxMsg(true)
+		tx.Status = status
+		tx.MarshalUpsert(cellID, attrID, &Tag{URL: val})
+		if err := cr.PushTx(tx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	values := func(tx *TxMsg) (vals []string) {
+		for i := range tx.Ops {
+			val := &Tag{}
+			tx.UnmarshalOpValue(i, val)
+			vals = append(vals, val.URL)
+		}
+		return vals
+	}
+
+	// A burst of progress updates collapses into the latest value, while other attrs are sent in full
+	for i := 0; i <= 100; i++ {
+		push(OpStatus_Synced, progressSpec.ID, fmt.Sprint(i, "%"))
+		if i%50 == 0 {
+			push(OpStatus_Synced, PinnedTabSpec.ID, fmt.Sprint("tab ", i))
+		}
+	}
+	if inner.numPushed() != 0 {
+		t.Fatal("expected pushes to be held until the flush interval")
+	}
+	time.Sleep(60 * time.Millisecond)
+	if inner.numPushed() != 1 {
+		t.Fatalf("expected one coalesced tx, got %d", inner.numPushed())
+	}
+	if got := values(inner.pushed[0]); !reflect.DeepEqual(got, []string{"100%", "tab 0", "tab 50", "tab 100"}) {
+		t.Fatalf("unexpected coalesced values %v", got)
+	}
+	if stats := cr.Stats(); stats.TxsPushed != 104 || stats.OpsCoalesced != 100 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+
+	// Flush sends immediately, and a closing tx is never held
+	push(OpStatus_Synced, progressSpec.ID, "a")
+	if err := cr.Flush(); err != nil || inner.numPushed() != 2 {
+		t.Fatalf("expected Flush to push, got %d: %v", inner.numPushed(), err)
+	}
+	push(OpStatus_Synced, progressSpec.ID, "b")
+	push(OpStatus_Closed, progressSpec.ID, "c")
+	if inner.numPushed() != 4 || values(inner.pushed[2])[0] != "b" || inner.pushed[3].Status != OpStatus_Closed {
+		t.Fatalf("expected status change and close to flush, got %d pushes", inner.numPushed())
+	}
+}
+
+func TestE2ETransport(t *testing.T) {
+	hostKey, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	handshake := func(hostOpts, clientOpts E2EOpts) (client, host *E2ETransport, clientRaw, hostRaw *pipeTransport, err error) {
+		clientRaw, hostRaw = newPipe()
+		accepted := make(chan error, 1)
+		go func() {
+			var err error
+			host, err = AcceptE2E(hostRaw, hostOpts)
+			accepted <- err
+		}()
+		client, err = OfferE2E(clientRaw, clientOpts)
+		if hostErr := <-accepted; err == nil {
+			err = hostErr
+		}
+		return
+	}
+
+	// A client expecting a different host key refuses the session
+	otherKey, _ := ecdh.X25519().GenerateKey(rand.Reader)
+	if _, _, _, _, err = handshake(E2EOpts{StaticKey: hostKey}, E2EOpts{HostKey: otherKey.PublicKey()}); err == nil {
+		t.Fatal("expected host key mismatch")
+	}
+
+	client, host, clientRaw, hostRaw, err := handshake(E2EOpts{StaticKey: hostKey, Required: true}, E2EOpts{HostKey: hostKey.PublicKey()})
+	if err != nil || !client.Encrypted() || !host.Encrypted() {
+		t.Fatalf("handshake failed: %v", err)
+	}
+
+	reqID := tag.New()
+	send := func(val string) []byte {
+		tx := NewTxMsg(true)
+		tx.SetRequestID(reqID)
+		tx.Status = OpStatus_Synced
+		tx.MarshalUpsert(reqID, PinnedTabSpec.ID, &Tag{URL: val})
+		if err := client.SendTx(tx); err != nil {
+			t.Fatal(err)
+		}
+		return <-hostRaw.recv
+	}
+	deliver := func(raw []byte) (*TxMsg, error) {
+		clientRaw.send <- raw
+		return host.RecvTx()
+	}
+
+	// A relay can route by request ID but can't read the cell content
+	wire := send("secret cell content")
+	if bytes.Contains(wire, []byte("secret")) {
+		t.Fatal("tx content is visible on the wire")
+	}
+	env, _ := ReadTxMsg(bytes.NewReader(wire))
+	if env.RequestID() != reqID {
+		t.Fatal("expected request ID to be visible for routing")
+	}
+	tx, err := deliver(wire)
+	val := &Tag{}
+	if err != nil || tx.UnmarshalOpValue(0, val) != nil || val.URL != "secret cell content" {
+		t.Fatalf("host failed to open tx: %v", err)
+	}
+
+	// Replayed, altered, and unsealed txs are refused
+	if _, err = deliver(wire); err == nil {
+		t.Fatal("expected replay to be refused")
+	}
+	env, _ = ReadTxMsg(bytes.NewReader(send("b")))
+	env.Status = OpStatus_Closed
+	var altered []byte
+	env.MarshalToBuffer(&altered)
+	if _, err = deliver(altered); err == nil {
+		t.Fatal("expected altered envelope to be refused")
+	}
+	plain := NewTxMsg(true)
+	plain.MarshalUpsert(reqID, PinnedTabSpec.ID, &Tag{URL: "injected"})
+	var injected []byte
+	plain.MarshalToBuffer(&injected)
+	if _, err = deliver(injected); err == nil {
+		t.Fatal("expected unsealed tx to be refused")
+	}
+
+	// Txs reordered within the replay window are accepted
+	first, second := send("1"), send("2")
+	if _, err = deliver(second); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = deliver(first); err != nil {
+		t.Fatal(err)
+	}
+
+	// Host to client
+	reply := NewTxMsg(true)
+	reply.MarshalUpsert(reqID, PinnedTabSpec.ID, &Tag{URL: "reply"})
+	if err = host.SendTx(reply); err != nil {
+		t.Fatal(err)
+	}
+	if tx, err = client.RecvTx(); err != nil || tx.UnmarshalOpValue(0, val) != nil || val.URL != "reply" {
+		t.Fatalf("client failed to open tx: %v", err)
+	}
+
+	// A host requiring encryption refuses a plaintext client
+	clientRaw, hostRaw = newPipe()
+	clientRaw.SendTx(NewTxMsg(true))
+	if _, err = AcceptE2E(hostRaw, E2EOpts{Required: true}); err == nil {
+		t.Fatal("expected plaintext client to be refused")
+	}
+}
+
+func TestJournal(t *testing.T) {
+	clientRaw, hostRaw := newPipe()
+	var buf bytes.Buffer
+	jw, err := NewJournalWriter(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	host := NewJournalTransport(hostRaw, jw)
+
+	reqID := tag.New()
+	val, err := (&PinRequest{PinTarget: &Tag{URL: "amp://cells/home"}}).MarshalToStore(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pinReq := NewTxMsg(true)
+	pinReq.SetGenesisID(reqID)
+	pinReq.MarshalOpWithBuf(&TxOp{OpCode: TxOpCode_MetaAttr, AttrID: PinRequestSpec.ID}, val)
+	pinReq.MarshalUpsert(reqID, PinnedTabSpec.ID, &Tag{URL: "commit"})
+	clientRaw.SendTx(pinReq)
+	if _, err = host.RecvTx(); err != nil {
+		t.Fatal(err)
+	}
+
+	reply := NewTxMsg(true)
+	reply.SetRequestID(reqID)
+	reply.Status = OpStatus_Synced
+	reply.MarshalUpsert(reqID, PinnedTabSpec.ID, &Tag{URL: "reply"})
+	if err = host.SendTx(reply); err != nil {
+		t.Fatal(err)
+	}
+	host.Close()
+
+	jr, err := NewJournalReader(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recv, err := jr.Next()
+	if err != nil || recv.Kind != JournalRecv || recv.Time.IsZero() {
+		t.Fatalf("expected recv record, got %v: %v", recv.Kind, err)
+	}
+	parsed, commitTx, ok := ParsePinRequest(recv.Tx)
+	if !ok || parsed.PinTarget.URL != "amp://cells/home" || recv.Tx.GenesisID() != reqID {
+		t.Fatal("recorded pin request did not survive the journal")
+	}
+	commitVal := &Tag{}
+	if commitTx == nil || len(commitTx.Ops) != 1 || commitTx.UnmarshalOpValue(0, commitVal) != nil || commitVal.URL != "commit" {
+		t.Fatal("expected the commit tx to follow the pin request")
+	}
+
+	send, err := jr.Next()
+	if err != nil || send.Kind != JournalSend || send.Tx.Status != OpStatus_Synced || send.Tx.RequestID() != reqID {
+		t.Fatalf("expected send record, got %v: %v", send.Kind, err)
+	}
+	if send.Time.Before(recv.Time) {
+		t.Fatal("expected records in time order")
+	}
+	if _, err = jr.Next(); err != io.EOF {
+		t.Fatalf("expected EOF, got %v", err)
+	}
+
+	if _, err = NewJournalReader(strings.NewReader("not a journal....")); err == nil {
+		t.Fatal("expected bad header to be rejected")
+	}
+}
+
+type testFields map[string]any
+
+func (f testFields) FieldValue(name string) (any, bool) {
+	val, exists := f[name]
+	return val, exists
+}
+
+func TestFilter(t *testing.T) {
+	tracks := []testFields{
+		{"title": "One", "artist": "X", "year": int64(1999), "tags": []string{"live"}},
+		{"title": "Two", "artist": "X", "year": int64(2004), "tags": []string{"studio"}},
+		{"title": "Three", "artist": "Y", "year": int64(2010)},
+		{"title": "Four", "artist": "X", "year": int64(2012), "tags": []string{"studio", "live"}},
+		{"title": "Five", "artist": "X"},
+	}
+	titles := func(expr string) string {
+		filter, err := ParseFilter(expr)
+		if err != nil {
+			t.Fatalf("%q: %v", expr, err)
+		}
+		var got []string
+		for _, track := range ApplyFilter(filter, tracks) {
+			got = append(got, track["title"].(string))
+		}
+		return strings.Join(got, ",")
+	}
+
+	for expr, want := range map[string]string{
+		``:                                     "One,Two,Three,Four,Five",
+		`artist == "X" sort year desc limit 2`: "Four,Two",
+		`artist == "X" sort year`:              "One,Two,Four,Five",
+		`tags == "live"`:                       "One,Four",
+		`year >= 2004 and not (artist == "Y")`: "Two,Four",
+		`title ~ "t" || year < 2000`:           "One,Two,Three",
+		`year != 2004`:                         "One,Three,Four",
+		`sort artist desc, year desc`:          "Three,Four,Two,One,Five",
+		`limit 1`:                              "One",
+		`artist == "X" && (year < 2000 || year > 2010)`: "One,Four",
+	} {
+		if got := titles(expr); got != want {
+			t.Errorf("%q: got %q, want %q", expr, got, want)
+		}
+	}
+
+	for _, expr := range []string{`artist ==`, `artist "X"`, `(year > 1`, `limit 0`, `year > 1 extra`, `title == "open`, `sort`} {
+		if _, err := ParseFilter(expr); err == nil {
+			t.Errorf("%q: expected parse error", expr)
+		} else if ampErr, ok := err.(*Err); !ok || ampErr.Code != ErrCode_BadRequest {
+			t.Errorf("%q: unexpected error %v", expr, err)
+		}
+	}
+}
+
+func TestGeoFilter(t *testing.T) {
+	places := []testFields{
+		{"name": "Empire State", "location": &GeoPoint{Lat: 40.7484, Lon: -73.9857, Alt: 443}},
+		{"name": "Times Square", "location": geo.Point{Lat: 40.7580, Lon: -73.9855}},
+		{"name": "Statue of Liberty", "location": NewGeoPoint(geo.Point{Lat: 40.6892, Lon: -74.0445})},
+		{"name": "Suva", "location": geo.Point{Lat: -18.1416, Lon: 178.4419}},
+		{"name": "Nowhere"},
+		{"name": "Not a location", "location": "40.7,-73.9"},
+	}
+	names := func(expr string) string {
+		filter, err := ParseFilter(expr)
+		if err != nil {
+			t.Fatalf("%q: %v", expr, err)
+		}
+		var got []string
+		for _, place := range ApplyFilter(filter, places) {
+			got = append(got, place["name"].(string))
+		}
+		return strings.Join(got, ",")
+	}
+
+	for expr, want := range map[string]string{
+		`location within radius(40.7484, -73.9857, 1500)`:                              "Empire State,Times Square",
+		`location within radius(40.7484, -73.9857, 1500) && name ~ "times"`:            "Times Square",
+		`location within box(40.5, -74.1, 40.8, -73.9)`:                                "Empire State,Times Square,Statue of Liberty",
+		`not location within box(40.5, -74.1, 40.8, -73.9) limit 1`:                    "Suva",
+		`location within BOX(-21, 176, -12, -178)`:                                     "Suva",
+		`location within radius(0, 0, 1) || location within radius(-18, 178.5, 20000)`: "Suva",
+	} {
+		if got := names(expr); got != want {
+			t.Errorf("%q: got %q, want %q", expr, got, want)
+		}
+	}
+
+	// The region constraining a field is offered to an app having a spatial index
+	filter, _ := ParseFilter(`name ~ "x" && (location within radius(1, 2, 3) && other within box(0, 0, 1, 1))`)
+	if region, ok := filter.Within("location"); !ok || region != (geo.Circle{Center: geo.Point{Lat: 1, Lon: 2}, Radius: 3}) {
+		t.Fatalf("unexpected region %v", region)
+	}
+	filter, _ = ParseFilter(`location within radius(1, 2, 3) || name == "x"`)
+	if _, ok := filter.Within("location"); ok {
+		t.Fatal("expected disjunct region to not constrain")
+	}
+
+	for _, expr := range []string{
+		`location within`, `location within square(1, 2)`, `location within box(1, 2, 3)`, `location within radius(1, 2, 3`,
+		`location within box(10, 0, -10, 0)`, `location within radius(91, 0, 10)`, `location within radius(0, 0, -1)`,
+	} {
+		if _, err := ParseFilter(expr); err == nil {
+			t.Errorf("%q: expected parse error", expr)
+		}
+	}
+}
+
+func TestLocalize(t *testing.T) {
+	available := []string{"en", "pt-PT", "pt", "zh-Hant", "zh-Hans-CN", "en_GB"}
+	for prefs, want := range map[string]string{
+		"pt-BR":                  "pt,pt-PT",
+		"PT-pt":                  "pt-PT,pt",
+		"zh-Hant-TW,en-US":       "zh-Hant,zh-Hans-CN,en,en_GB",
+		"en-GB":                  "en_GB,en",
+		"fr,de":                  "",
+		"x-private-a,en-x-twain": "en,en_GB",
+	} {
+		var got []string
+		for _, i := range LocaleChain(strings.Split(prefs, ","), available) {
+			got = append(got, available[i])
+		}
+		if strings.Join(got, ",") != want {
+			t.Errorf("%q: got %v, want %q", prefs, got, want)
+		}
+	}
+
+	tab := &TagTab{Label: "Kind of Blue", Caption: "Miles Davis", About: "1959"}
+	tab.SetLocale(&LocaleText{Locale: "ja", Label: "カインド・オブ・ブルー", Caption: "マイルス・デイヴィス"})
+	tab.SetLocale(&LocaleText{Locale: "pt", Label: "Tipo de Azul"})
+	tab.SetLocale(&LocaleText{Locale: "PT", Label: "Espécie de Azul"})
+	if len(tab.Locales) != 2 {
+		t.Fatal("expected a locale to be replaced")
+	}
+
+	// Each field falls back along the chain, then to the tab's own text
+	got := Localize(tab, []string{"pt-BR", "ja"}).(*TagTab)
+	if got.Label != "Espécie de Azul" || got.Caption != "マイルス・デイヴィス" || got.About != "1959" || got.Locales != nil {
+		t.Fatalf("unexpected localized tab %+v", got)
+	}
+	if tab.Label != "Kind of Blue" || len(tab.Locales) != 2 {
+		t.Fatal("expected tab to be unmodified")
+	}
+	got = Localize(tab, []string{"fr"}).(*TagTab)
+	if got.Label != "Kind of Blue" || got.Locales != nil {
+		t.Fatalf("unexpected unmatched tab %+v", got)
+	}
+	if Localize(tab, nil) != ElemVal(tab) {
+		t.Fatal("expected no locales to leave the tab as is")
+	}
+}
+
+type testPinner func(req Requester) (Pin, error)
+
+func (fn testPinner) ServeRequest(req Requester) (Pin, error) {
+	return fn(req)
+}
+
+func TestSelectAttrs(t *testing.T) {
+	labelSpec := tag.FormSpec(AttrSpec, "test.label.Tag")
+	inner := &testCoalesceRequester{req: Request{ID: tag.New()}}
+	inner.req.PinAttrs = []*Tag{FormPinnableTag(labelSpec), {URL: PinnedTabSpec.Canonic}}
+	if sel := inner.req.AttrSelection(); len(sel) != 2 {
+		t.Fatalf("unexpected selection %v", sel)
+	}
+
+	cellID := tag.New()
+	_, err := serveSelected(testPinner(func(req Requester) (Pin, error) {
+		tx := NewTxMsg(true)
+		tx.Status = OpStatus_Synced
+		tx.MarshalUpsert(cellID, labelSpec.ID, &Tag{URL: "label"})
+		tx.MarshalUpsert(cellID, ChildTabSpec.ID, &TagTab{Label: "unselected"})
+		tx.MarshalUpsert(cellID, PinnedTabSpec.ID, &TagTab{Label: "pinned"})
+		tx.MarshalOpWithBuf(&TxOp{OpCode: TxOpCode_DeleteCell, TargetID: tag.New()}, nil)
+		return nil, req.PushTx(tx)
+	}), inner)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Only selected attrs (and ops not specific to an attr) are forwarded
+	tx := inner.pushed[0]
+	if len(tx.Ops) != 3 || tx.Status != OpStatus_Synced {
+		t.Fatalf("expected 3 selected ops, got %d", len(tx.Ops))
+	}
+	if tx.Ops[0].AttrID != labelSpec.ID || tx.Ops[1].AttrID != PinnedTabSpec.ID || tx.Ops[2].OpCode != TxOpCode_DeleteCell {
+		t.Fatalf("unexpected ops %v", tx.Ops)
+	}
+	tab := &TagTab{}
+	if err = tx.UnmarshalOpValue(1, tab); err != nil || tab.Label != "pinned" {
+		t.Fatalf("unexpected value %v: %v", tab, err)
+	}
+
+	// A request without PinAttrs selects everything
+	inner.req.PinAttrs = nil
+	if inner.req.AttrSelection() != nil || !AttrSelection(nil).Selects(&TxOp{AttrID: labelSpec.ID}) {
+		t.Fatal("expected all attrs to be selected")
+	}
+}
+
+func TestCRDT(t *testing.T) {
+	alice, bob := NewCRDTClock(), NewCRDTClock()
+	strs := func(values [][]byte) string {
+		var parts []string
+		for _, val := range values {
+			parts = append(parts, string(val))
+		}
+		return strings.Join(parts, ",")
+	}
+	merged := func(a, b CRDT) CRDT {
+		dst := cloneCRDT(a)
+		if err := dst.MergeCRDT(b); err != nil {
+			t.Fatal(err)
+		}
+		return dst
+	}
+
+	// Concurrent inserts into a list converge on the same order in either merge order, including inserts at the same position
+	var base RGAList
+	base.Insert(alice, 0, []byte("a"))
+	base.Insert(alice, 1, []byte("d"))
+	listA, listB := cloneCRDT(&base).(*RGAList), cloneCRDT(&base).(*RGAList)
+	listA.Insert(alice, 1, []byte("b"))
+	listB.Insert(bob, 1, []byte("c"))
+	listB.Remove(0)
+	ab, ba := merged(listA, listB).(*RGAList), merged(listB, listA).(*RGAList)
+	if strs(ab.Values()) != strs(ba.Values()) || ab.Len() != 3 {
+		t.Fatalf("lists did not converge: %q vs %q", strs(ab.Values()), strs(ba.Values()))
+	}
+	if got := strs(merged(ab, listA).(*RGAList).Values()); got != strs(ab.Values()) {
+		t.Fatalf("merge is not idempotent: %q", got)
+	}
+	if err := ab.Insert(alice, 4, nil); err == nil {
+		t.Fatal("expected out of range insert to fail")
+	}
+
+	// A concurrent add wins over a remove of the same value
+	var setA ORSet
+	setA.Add(alice, []byte("x"))
+	setA.Add(alice, []byte("y"))
+	setB := cloneCRDT(&setA).(*ORSet)
+	setA.Remove([]byte("x"))
+	setB.Add(bob, []byte("x"))
+	setB.Remove([]byte("y"))
+	if got := strs(merged(&setA, setB).(*ORSet).Values()); got != "x" || got != strs(merged(setB, &setA).(*ORSet).Values()) {
+		t.Fatalf("unexpected set %q", got)
+	}
+
+	// The latest write to a register wins
+	var regA, regB LWWRegister
+	regA.Set(alice, []byte("first"))
+	regB.Set(bob, []byte("second"))
+	if got := merged(&regA, &regB).(*LWWRegister); string(got.Value) != "second" {
+		t.Fatalf("unexpected register %q", got.Value)
+	}
+	if err := regA.MergeCRDT(&setA); err == nil {
+		t.Fatal("expected type mismatch to fail")
+	}
+
+	// Commits from concurrent sessions merge as they arrive
+	reg := NewRegistry()
+	RegisterBuiltinTypes(reg)
+	playlistSpec := reg.RegisterPrototype(AttrSpec, &ORSet{}, "playlist")
+	attrs := NewCRDTAttrs(reg)
+	cellID := tag.New()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var set ORSet
+			set.Add(NewCRDTClock(), []byte{byte('a' + i)})
+			tx := NewTxMsg(true)
+			tx.MarshalUpsert(cellID, playlistSpec.ID, &set)
+			tx.MarshalUpsert(cellID, ChildTabSpec.ID, &TagTab{Label: "not a CRDT"})
+			out, err := attrs.MergeTx(tx)
+			if err != nil || out == nil || len(out.Ops) != 1 {
+				t.Errorf("unexpected merge: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if got := attrs.Get(cellID, playlistSpec.ID, tag.Nil).(*ORSet); len(got.Values()) != 8 {
+		t.Fatalf("expected all adds to merge, got %q", strs(got.Values()))
+	}
+}
+
+func TestCommitHistory(t *testing.T) {
+	cellID := tag.New()
+	state := map[tag.ID]*TagTab{}
+	current := func(cellID, attrID, SI tag.ID) ElemVal {
+		if tab := state[attrID]; tab != nil {
+			return tab
+		}
+		return nil
+	}
+	apply := func(tx *TxMsg) {
+		for i, op := range tx.Ops {
+			switch op.OpCode {
+			case TxOpCode_UpsertAttr:
+				tab := &TagTab{}
+				if err := tx.UnmarshalOpValue(i, tab); err != nil {
+					t.Fatal(err)
+				}
+				state[op.AttrID] = tab
+			case TxOpCode_DeleteAttr:
+				delete(state, op.AttrID)
+			}
+		}
+	}
+	history := NewCommitHistory(2)
+	commit := func(commit *TxMsg) error {
+		tx, err := history.Resolve(commit, current)
+		if err == nil {
+			apply(tx)
+		}
+		return err
+	}
+	label := func(attrID tag.ID) string {
+		if tab := state[attrID]; tab != nil {
+			return tab.Label
+		}
+		return "<none>"
+	}
+
+	for _, name := range []string{"one", "two", "three"} {
+		tx := NewTxMsg(true)
+		tx.MarshalUpsert(cellID, PinnedTabSpec.ID, &TagTab{Label: name})
+		if name == "two" {
+			tx.MarshalUpsert(cellID, ChildTabSpec.ID, &TagTab{Label: "child"})
+		}
+		if err := commit(tx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Undo restores prior values (deleting attrs that had none), and only the last Depth commits are retained
+	if err := commit(MarshalUndo(cellID)); err != nil || label(PinnedTabSpec.ID) != "two" {
+		t.Fatalf("unexpected undo: %q, %v", label(PinnedTabSpec.ID), err)
+	}
+	if err := commit(MarshalUndo(cellID)); err != nil || label(PinnedTabSpec.ID) != "one" || label(ChildTabSpec.ID) != "<none>" {
+		t.Fatalf("unexpected undo: %q, %q, %v", label(PinnedTabSpec.ID), label(ChildTabSpec.ID), err)
+	}
+	if err := commit(MarshalUndo(cellID)); err == nil {
+		t.Fatal("expected history to be exhausted")
+	} else if ampErr, ok := err.(*Err); !ok || ampErr.Code != ErrCode_NothingToCommit {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	// Redo reapplies undone commits until a new commit is made
+	if err := commit(MarshalRedo(cellID)); err != nil || label(PinnedTabSpec.ID) != "two" || label(ChildTabSpec.ID) != "child" {
+		t.Fatalf("unexpected redo: %q, %q, %v", label(PinnedTabSpec.ID), label(ChildTabSpec.ID), err)
+	}
+	tx := NewTxMsg(true)
+	tx.MarshalUpsert(cellID, PinnedTabSpec.ID, &TagTab{Label: "four"})
+	if err := commit(tx); err != nil {
+		t.Fatal(err)
+	}
+	if err := commit(MarshalRedo(cellID)); err == nil {
+		t.Fatal("expected redo to be discarded by a new commit")
+	}
+	if err := commit(MarshalUndo(cellID)); err != nil || label(PinnedTabSpec.ID) != "two" {
+		t.Fatalf("unexpected undo: %q, %v", label(PinnedTabSpec.ID), err)
+	}
+}
+
+func TestHostSnapshot(t *testing.T) {
+	newState := func() HostState {
+		table, err := memory_table.DefaultOpts().CreateTable()
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { table.Close() })
+		return HostState{
+			Symbols: map[string]symbol.Table{"host": table},
+			Stores:  map[string]symbol.Store{"app.a": symbol.NewMemoryStore(), "app.b": symbol.NewMemoryStore()},
+		}
+	}
+
+	src := newState()
+	src.Registry = NewRegistry()
+	RegisterBuiltinTypes(src.Registry)
+	strokesID := src.Symbols["host"].GetSymbolID([]byte("strokes"), true)
+	src.Stores["app.a"].Set([]byte("cell/1"), []byte("one"))
+	src.Stores["app.a"].Set([]byte("cell/2"), []byte{})
+	src.Stores["app.b"].Set([]byte("cell/3"), []byte("three"))
+
+	var archive bytes.Buffer
+	if err := WriteSnapshot(&archive, src); err != nil {
+		t.Fatal(err)
+	}
+
+	// A restore replaces existing store entries and reports the archived registry
+	dst := newState()
+	dst.Stores["app.a"].Set([]byte("stale"), []byte("x"))
+	info, err := RestoreSnapshot(bytes.NewReader(archive.Bytes()), dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info == nil || len(info.Attrs) != len(src.Registry.Describe().Attrs) {
+		t.Fatalf("unexpected registry info %+v", info)
+	}
+	if id := dst.Symbols["host"].GetSymbolID([]byte("strokes"), false); id != strokesID {
+		t.Fatalf("symbol not restored: got %d, expected %d", id, strokesID)
+	}
+	for store, entries := range map[string]map[string]string{
+		"app.a": {"cell/1": "one", "cell/2": ""},
+		"app.b": {"cell/3": "three"},
+	} {
+		restored := map[string]string{}
+		dst.Stores[store].Iterate(nil, func(key, value []byte) error {
+			restored[string(key)] = string(value)
+			return nil
+		})
+		if !reflect.DeepEqual(restored, entries) {
+			t.Fatalf("store %s: got %v, expected %v", store, restored, entries)
+		}
+	}
+
+	// A corrupt archive or one naming an absent store is rejected without modifying state
+	corrupt := append([]byte{}, archive.Bytes()...)
+	corrupt[len(corrupt)-10] ^= 0xFF
+	other := newState()
+	other.Stores["app.a"].Set([]byte("kept"), []byte("x"))
+	if _, err = RestoreSnapshot(bytes.NewReader(corrupt), other); GetErrCode(err) != ErrCode_DataFailure {
+		t.Fatalf("expected corrupt archive to be rejected, got %v", err)
+	}
+	delete(other.Stores, "app.b")
+	if _, err = RestoreSnapshot(bytes.NewReader(archive.Bytes()), other); GetErrCode(err) != ErrCode_BadValue {
+		t.Fatalf("expected missing store to be rejected, got %v", err)
+	}
+	if val, _ := other.Stores["app.a"].Get([]byte("kept")); string(val) != "x" {
+		t.Fatalf("failed restore modified state")
+	}
+}
+
+func TestSpaces(t *testing.T) {
+	root := t.TempDir()
+	spaces := NewSpaces(DefaultSpacesOpts(root))
+	app := &App{AppSpec: tag.FormSpec(AppSpec, "test.spaces")}
+
+	// Sessions without a space are scoped to the default space, and other spaces must exist unless created
+	def, err := spaces.ForLogin(&Login{UserUID: "a"})
+	if err != nil || def.Name != DefaultSpace {
+		t.Fatalf("expected default space, got %v (%v)", def, err)
+	}
+	if _, err = spaces.ForLogin(&Login{Space: "smiths"}); GetErrCode(err) != ErrCode_LoginFailed {
+		t.Fatalf("expected login to unknown space to fail, got %v", err)
+	}
+	if _, err = spaces.Open("../escape"); GetErrCode(err) != ErrCode_BadValue {
+		t.Fatalf("expected invalid space name to be rejected, got %v", err)
+	}
+	smiths, err := spaces.Open("smiths")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if space, _ := spaces.ForLogin(&Login{Space: "smiths"}); space != smiths {
+		t.Fatal("expected login to be scoped to the opened space")
+	}
+
+	// Spaces do not share symbols or app state
+	ID := smiths.Symbols.GetSymbolID([]byte("kitchen"), true)
+	if def.Symbols.GetSymbolID([]byte("kitchen"), false) != 0 {
+		t.Fatal("symbol leaked across spaces")
+	}
+	defPath, _ := def.AppDataPath(app)
+	smithsPath, _ := smiths.AppDataPath(app)
+	if defPath == smithsPath {
+		t.Fatalf("spaces share app data path %s", defPath)
+	}
+	store, err := smiths.AppStore(app)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.Set([]byte("cell"), []byte("value"))
+	if state := def.State(nil); len(state.Stores) != 0 {
+		t.Fatalf("unexpected stores in default space %v", state.Stores)
+	}
+
+	// Space state persists once spaces are closed
+	if err = spaces.Close(); err != nil {
+		t.Fatal(err)
+	}
+	spaces = NewSpaces(DefaultSpacesOpts(root))
+	defer spaces.Close()
+	if names, _ := spaces.Names(); !reflect.DeepEqual(names, []string{DefaultSpace, "smiths"}) {
+		t.Fatalf("unexpected spaces %v", names)
+	}
+	if smiths, err = spaces.Get("smiths"); err != nil {
+		t.Fatal(err)
+	}
+	if got := smiths.Symbols.GetSymbolID([]byte("kitchen"), false); got != ID {
+		t.Fatalf("symbol not persisted: got %d, expected %d", got, ID)
+	}
+	if store, err = smiths.AppStore(app); err != nil {
+		t.Fatal(err)
+	}
+	if val, _ := store.Get([]byte("cell")); string(val) != "value" {
+		t.Fatalf("app store not persisted: got %q", val)
+	}
+}
+
+func TestHostMetrics(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m := NewHostMetrics(reg, nil)
+	client, raw := newPipe()
+	host := m.Transport(raw)
+
+	tx := NewTxMsg(true)
+	tx.MarshalUpsert(tag.New(), PinnedTabSpec.ID, &TagTab{Label: "hello"})
+	if err := client.SendTx(tx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := host.RecvTx(); err != nil {
+		t.Fatal(err)
+	}
+	host.SendTx(NewTxMsg(true))
+
+	srv, err := m.Serve("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srv.Close()
+	host.Close()
+	host.Close()
+
+	resp, err := http.Get("http://" + srv.Addr + "/metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var buf bytes.Buffer
+	buf.ReadFrom(resp.Body)
+	for _, line := range []string{
+		"amp_sessions_active 0",
+		"amp_sessions_total 1",
+		`amp_txs_total{dir="in"} 1`,
+		`amp_txs_total{dir="out"} 1`,
+	} {
+		if !strings.Contains(buf.String(), line+"\n") {
+			t.Fatalf("missing %q in:\n%s", line, buf.String())
+		}
+	}
+	if !strings.Contains(buf.String(), `amp_tx_bytes_total{dir="in"} `) {
+		t.Fatalf("missing tx bytes in:\n%s", buf.String())
+	}
+}
+
+func TestBlobs(t *testing.T) {
+	cellID, SI := tag.New(), tag.New()
+	content := make([]byte, 2500)
+	rand.Read(content)
+
+	var sent []int64
+	inner := &testCoalesceRequester{req: Request{ID: tag.New()}}
+	err := PushBlob(context.Background(), inner, cellID, SI, bytes.NewReader(content), int64(len(content)), BlobOpts{
+		ContentType: "image/png",
+		FrameSize:   1000,
+		OnProgress:  func(n, total int64) { sent = append(sent, n) },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(inner.pushed) != 3 || !reflect.DeepEqual(sent, []int64{1000, 2000, 2500}) {
+		t.Fatalf("expected 3 frames, got %d (progress %v)", len(inner.pushed), sent)
+	}
+
+	var (
+		received []BlobProgress
+		blobs    []*Blob
+	)
+	asm := NewBlobAssembler(BlobAssemblerOpts{
+		OnProgress: func(progress BlobProgress) { received = append(received, progress) },
+		OnBlob:     func(blob *Blob) { blobs = append(blobs, blob) },
+	})
+	for i, tx := range inner.pushed {
+		if err := asm.ReceiveTx(tx); err != nil {
+			t.Fatal(err)
+		}
+		if i == 0 {
+			if pending := asm.Pending(); len(pending) != 1 || pending[0].Received != 1000 || pending[0].Total != 2500 {
+				t.Fatalf("unexpected pending: %+v", pending)
+			}
+		}
+	}
+	if len(received) != 3 || received[2].Received != 2500 || len(asm.Pending()) != 0 {
+		t.Fatalf("unexpected progress: %+v", received)
+	}
+	if len(blobs) != 1 || blobs[0].TargetID != cellID || blobs[0].SI != SI || blobs[0].ContentType != "image/png" || !bytes.Equal(blobs[0].Data, content) {
+		t.Fatal("blob not reassembled")
+	}
+
+	// A frame received out of order drops its blob
+	if err := asm.ReceiveTx(inner.pushed[1]); err == nil {
+		t.Fatal("expected out of order frame to fail")
+	}
+
+	// Frames of a canceled blob are ignored
+	blobs = nil
+	asm.ReceiveTx(inner.pushed[0])
+	asm.Cancel(cellID, SI)
+	for _, tx := range inner.pushed[1:] {
+		if err := asm.ReceiveTx(tx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(blobs) != 0 || len(asm.Pending()) != 0 {
+		t.Fatal("expected canceled blob to be discarded")
+	}
+
+	// A blob exceeding MaxSize is discarded
+	small := NewBlobAssembler(BlobAssemblerOpts{
+		MaxSize: 2000,
+		OnBlob:  func(blob *Blob) { blobs = append(blobs, blob) },
+	})
+	if err := small.ReceiveTx(inner.pushed[0]); err == nil {
+		t.Fatal("expected blob exceeding max size to fail")
+	}
+	for _, tx := range inner.pushed[1:] {
+		small.ReceiveTx(tx)
+	}
+	if len(blobs) != 0 {
+		t.Fatal("expected oversized blob to be discarded")
+	}
+
+	// A sender whose context is done ends its blob with a canceled frame
+	ctx, cancel := context.WithCancel(context.Background())
+	canceled := &testCoalesceRequester{req: Request{ID: tag.New()}}
+	err = PushBlob(ctx, canceled, cellID, SI, bytes.NewReader(content), -1, BlobOpts{
+		FrameSize:  1000,
+		OnProgress: func(n, total int64) { cancel() },
+	})
+	if err != context.Canceled || len(canceled.pushed) != 2 {
+		t.Fatalf("expected canceled push, got %v after %d frames", err, len(canceled.pushed))
+	}
+	for _, tx := range canceled.pushed {
+		if err := asm.ReceiveTx(tx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(blobs) != 0 || len(asm.Pending()) != 0 {
+		t.Fatal("expected canceled blob to be discarded")
+	}
+}
+
+func TestMediaInfo(t *testing.T) {
+	md := &metadata.Info{
+		ContentType: "audio/flac",
+		Title:       "Song",
+		Artist:      "Artist",
+		Album:       "Album",
+		TrackNum:    3,
+		Duration:    90 * time.Second,
+		TakenAt:     time.Unix(1700000000, 0),
+		Artwork: []metadata.Artwork{
+			{Type: metadata.PictureBackCover, ContentType: "image/png", Data: []byte("back")},
+			{Type: metadata.PictureFrontCover, ContentType: "image/jpeg", Data: []byte("front")},
+		},
+	}
+
+	cellID := tag.New()
+	tx := NewTxMsg(true)
+	if err := tx.MarshalUpsert(cellID, MediaInfoSpec.ID, NewMediaInfo(md)); err != nil {
+		t.Fatal(err)
+	}
+	var info MediaInfo
+	if err := tx.UnmarshalOpValue(0, &info); err != nil {
+		t.Fatal(err)
+	}
+	if info.Title != "Song" || info.TrackNum != 3 || info.DurationMs != 90000 || info.TakenAt != 1700000000 {
+		t.Fatalf("unexpected MediaInfo: %+v", info)
+	}
+
+	md.EncoderDelay, md.EncoderPadding = 576, 1000
+	if info = *NewMediaInfo(md); info.EncoderDelay != 576 || info.EncoderPadding != 1000 {
+		t.Fatalf("unexpected gapless info: %+v", info)
+	}
+
+	tab := MediaTab(md, "song.flac")
+	if tab.Label != "Song" || tab.Caption != "Artist" || tab.About != "Album" || len(tab.Tags) != 1 || string(tab.Tags[0].Attachment) != "front" {
+		t.Fatalf("unexpected tab: %+v", tab)
+	}
+	md.Title = ""
+	md.Artwork[1].Data = make([]byte, MaxInlineGlyphSize+1)
+	if tab = MediaTab(md, "song.flac"); tab.Label != "song.flac" || len(tab.Tags) != 0 {
+		t.Fatalf("unexpected tab: %+v", tab)
+	}
+}
+
+func TestTrackOffset(t *testing.T) {
+	sheet, err := cue.Parse(strings.NewReader("FILE album.flac WAVE\nTRACK 01 AUDIO\nINDEX 01 00:00:00\nTRACK 02 AUDIO\nINDEX 00 03:00:00\nINDEX 01 03:02:00\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	splits := sheet.Splits(func(string) time.Duration { return 5 * time.Minute })
+
+	cellID := tag.New()
+	tx := NewTxMsg(true)
+	if err := tx.MarshalUpsert(cellID, TrackOffsetSpec.ID, NewTrackOffset(&splits[1])); err != nil {
+		t.Fatal(err)
+	}
+	var offset TrackOffset
+	if err := tx.UnmarshalOpValue(0, &offset); err != nil {
+		t.Fatal(err)
+	}
+	if offset.StartMs != 182000 || offset.DurationMs != 118000 || offset.PregapMs != 2000 {
+		t.Fatalf("unexpected TrackOffset: %+v", offset)
+	}
+}
+
+func TestDerivedMedia(t *testing.T) {
+	wave := &derive.Waveform{Duration: 1500 * time.Millisecond, Peaks: []uint8{0, 128, 255}}
+
+	cellID := tag.New()
+	tx := NewTxMsg(true)
+	if err := tx.MarshalUpsert(cellID, WaveformSpec.ID, NewWaveformPeaks(wave)); err != nil {
+		t.Fatal(err)
+	}
+	var peaks WaveformPeaks
+	if err := tx.UnmarshalOpValue(0, &peaks); err != nil {
+		t.Fatal(err)
+	}
+	if peaks.DurationMs != 1500 || !bytes.Equal(peaks.Peaks, wave.Peaks) {
+		t.Fatalf("unexpected WaveformPeaks: %+v", peaks)
+	}
+
+	thumb := &derive.Thumbnail{Key: "derived/abc/thumb-128x128.jpg", ContentType: "image/jpeg", Width: 128, Height: 72}
+	glyph := ThumbnailTag(thumb, "https://host/abc.jpg")
+	if glyph.URL != "https://host/abc.jpg" || glyph.Metric != Metric_OrthoPixel || glyph.Size_0 != 128 || glyph.Size_1 != 72 {
+		t.Fatalf("unexpected thumbnail tag: %+v", glyph)
+	}
+	if ThumbnailSI(128, 128) == ThumbnailSI(256, 256) {
+		t.Fatal("expected distinct thumbnail SIs")
+	}
+}
+
+func TestPlaylist(t *testing.T) {
+	pl := &playlist.Playlist{
+		Title: "Road Trip",
+		Entries: []playlist.Entry{
+			{Location: "/music/a.mp3", Artist: "Band", Title: "A", Duration: 61500 * time.Millisecond, Resolved: "/library/a.mp3"},
+			{Location: "http://radio.example.com/stream", Title: "Radio"},
+			{Location: "/music/c.flac", Album: "C"},
+		},
+	}
+	if tab := PlaylistTab(pl); tab.Label != "Road Trip" || tab.Caption != "3 items" {
+		t.Fatalf("unexpected playlist tab: %+v", tab)
+	}
+
+	cellID := tag.New()
+	tx := NewTxMsg(true)
+	if err := tx.MarshalUpsert(tag.New(), ChildTabSpec.ID, &TagTab{Label: "other cell"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := MarshalPlaylist(tx, cellID, pl); err != nil {
+		t.Fatal(err)
+	}
+
+	// A later op removing the second entry and retitling the cell
+	tx.MarshalOpWithBuf(&TxOp{OpCode: TxOpCode_DeleteAttr, TargetID: cellID, AttrID: PlaylistEntrySpec.ID, SI: PlaylistEntrySI(1)}, nil)
+	if err := tx.MarshalUpsert(cellID, PinnedTabSpec.ID, &TagTab{Label: "Renamed"}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := UnmarshalPlaylist(tx, cellID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &playlist.Playlist{
+		Title:   "Renamed",
+		Entries: []playlist.Entry{pl.Entries[0], pl.Entries[2]},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected playlist:\n got %+v\nwant %+v", got, want)
+	}
+}
+
+type testAsset struct {
+	contentType string
+}
+
+func (asset *testAsset) Label() string                  { return "test asset" }
+func (asset *testAsset) ContentType() string            { return asset.contentType }
+func (asset *testAsset) OnStart(ctx task.Context) error { return nil }
+func (asset *testAsset) NewAssetReader() (media.AssetReader, error) {
+	return nil, io.EOF
+}
+
+// testAdaptingPublisher adapts every asset to audio/mpeg, as a transcoding publisher would for a client only playing MP3.
+type testAdaptingPublisher struct {
+	published []media.Asset
+}
+
+func (pub *testAdaptingPublisher) AdaptAsset(asset media.Asset) (media.Asset, error) {
+	if asset.ContentType() == "audio/mpeg" {
+		return asset, nil
+	}
+	return &testAsset{contentType: "audio/mpeg"}, nil
+}
+
+func (pub *testAdaptingPublisher) PublishAsset(asset media.Asset, opts media.PublishOpts) (string, error) {
+	adapted, _ := pub.AdaptAsset(asset)
+	pub.published = append(pub.published, adapted)
+	return fmt.Sprintf("http://localhost/asset/%d", len(pub.published)), nil
+}
+
+func TestPublishAssetTag(t *testing.T) {
+	pub := &testAdaptingPublisher{}
+	flac := &testAsset{contentType: "audio/flac"}
+	assetTag, err := PublishAssetTag(pub, flac, media.PublishOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if assetTag.ContentType != "audio/mpeg" || assetTag.URL != "http://localhost/asset/1" || len(pub.published) != 1 {
+		t.Fatalf("unexpected tag: %+v", assetTag)
+	}
+}
+
+func TestPrefetcher(t *testing.T) {
+	root, err := task.Start(&task.Task{Label: "TestPrefetcher"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer root.Close()
+
+	pf := NewPrefetcher(root, PrefetchOpts{Workers: 1, MaxQueued: 3})
+	ran := make(chan string, 10)
+	work := func(name string) func(ctx task.Context) error {
+		return func(ctx task.Context) error {
+			ran <- name
+			if name == "fail" {
+				return ErrCode_InternalErr.Error("failed")
+			}
+			return nil
+		}
+	}
+
+	// While a request is being served, work is held -- and then runs newest first, with replaced, cancelled, and
+	// evicted work dropped
+	served := pf.Serving()
+	keys := [5]tag.ID{tag.New(), tag.New(), tag.New(), tag.New(), tag.New()}
+	pf.Queue(keys[0], work("evicted"))
+	pf.Queue(keys[1], work("replaced"))
+	pf.Queue(keys[2], work("cancelled"))()
+	pf.Queue(keys[1], work("b"))
+	pf.Queue(keys[3], work("c"))
+	pf.Queue(keys[4], work("fail"))
+	select {
+	case name := <-ran:
+		t.Fatalf("work %q ran while a request was served", name)
+	case <-time.After(20 * time.Millisecond):
+	}
+	served()
+	served() // no-op
+
+	var order []string
+	for len(order) < 3 {
+		select {
+		case name := <-ran:
+			order = append(order, name)
+		case <-time.After(time.Second):
+			t.Fatalf("expected queued work to run, got %v", order)
+		}
+	}
+	if fmt.Sprint(order) != "[fail c b]" {
+		t.Fatalf("unexpected run order %v", order)
+	}
+	for i := 0; i < 100 && pf.Stats().Ran < 3; i++ {
+		time.Sleep(time.Millisecond)
+	}
+	if stats := pf.Stats(); stats != (PrefetchStats{Queued: 6, Ran: 3, Failed: 1, Dropped: 3}) {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+}
+
+func TestKVStore(t *testing.T) {
+	root := t.TempDir()
+	spaces := NewSpaces(DefaultSpacesOpts(root))
+	app := &App{AppSpec: tag.FormSpec(AppSpec, "test.kv")}
+	space, err := spaces.Open("smiths")
+	if err != nil {
+		t.Fatal(err)
+	}
+	kv, err := space.AppKVStore(app)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := space.AppKVStore(app); again != kv {
+		t.Fatal("expected a single KVStore per app")
+	}
+	now := time.Unix(1000, 0)
+	kv.(*kvStore).now = func() time.Time { return now }
+
+	// Entries with a TTL appear absent once expired and are removed by Purge
+	kv.Set([]byte("a/1"), []byte("one"), 0)
+	kv.Set([]byte("a/2"), []byte("two"), time.Minute)
+	kv.Set([]byte("b/1"), []byte("three"), 0)
+	if val, _ := kv.Get([]byte("a/2")); string(val) != "two" {
+		t.Fatalf("unexpected value %q", val)
+	}
+	now = now.Add(2 * time.Minute)
+	if val, _ := kv.Get([]byte("a/2")); val != nil {
+		t.Fatalf("expected expired entry to be absent, got %q", val)
+	}
+	var keys []string
+	kv.Iterate([]byte("a/"), func(key, value []byte) error {
+		keys = append(keys, string(key)+"="+string(value))
+		return nil
+	})
+	if !reflect.DeepEqual(keys, []string{"a/1=one"}) {
+		t.Fatalf("unexpected entries %v", keys)
+	}
+	if n, err := kv.Purge(); n != 1 || err != nil {
+		t.Fatalf("expected 1 entry purged, got %d (%v)", n, err)
+	}
+
+	// A txn sees its own writes and applies none of them if it fails
+	errAbort := ErrCode_BadValue.Error("abort")
+	err = kv.Update(func(txn KVTxn) error {
+		txn.Set([]byte("a/1"), []byte("uno"), 0)
+		txn.Delete([]byte("b/1"))
+		if val, _ := txn.Get([]byte("b/1")); val != nil {
+			t.Fatalf("expected txn to see its delete, got %q", val)
+		}
+		return errAbort
+	})
+	if err != errAbort {
+		t.Fatalf("expected txn error, got %v", err)
+	}
+	if val, _ := kv.Get([]byte("a/1")); string(val) != "one" {
+		t.Fatalf("expected failed txn to write nothing, got %q", val)
+	}
+	var leaked KVTxn
+	err = kv.Update(func(txn KVTxn) error {
+		leaked = txn
+		txn.Set([]byte("a/0"), []byte("zero"), 0)
+		txn.Delete([]byte("a/1"))
+		keys = keys[:0]
+		return txn.Iterate([]byte("a/"), func(key, value []byte) error {
+			keys = append(keys, string(key)+"="+string(value))
+			return nil
+		})
+	})
+	if err != nil || !reflect.DeepEqual(keys, []string{"a/0=zero"}) {
+		t.Fatalf("unexpected txn entries %v (%v)", keys, err)
+	}
+	if leaked.Set([]byte("a/9"), nil, 0) == nil {
+		t.Fatal("expected completed txn to be refused")
+	}
+
+	// Entries are kept in the app's store, so they are part of the space's state and persist
+	if state := space.State(nil); len(state.Stores) != 1 {
+		t.Fatalf("expected app store in space state, got %v", state.Stores)
+	}
+	if err = spaces.Close(); err != nil {
+		t.Fatal(err)
+	}
+	spaces = NewSpaces(DefaultSpacesOpts(root))
+	defer spaces.Close()
+	if space, err = spaces.Get("smiths"); err != nil {
+		t.Fatal(err)
+	}
+	if kv, err = space.AppKVStore(app); err != nil {
+		t.Fatal(err)
+	}
+	if val, _ := kv.Get([]byte("a/0")); string(val) != "zero" {
+		t.Fatalf("KV store not persisted: got %q", val)
+	}
+	store, _ := space.AppStore(app)
+	if val, _ := store.Get([]byte(KVStorePrefix + "b/1")); len(val) == 0 {
+		t.Fatal("expected KV entries under KVStorePrefix")
+	}
+}
+
+// testFlowPin is a testReapPin that pauses its own emission (see FlowHandler).
+type testFlowPin struct {
+	testReapPin
+	flow chan bool // true when paused, false when resumed
+}
+
+func (pin *testFlowPin) OnFlowPaused()  { pin.flow <- true }
+func (pin *testFlowPin) OnFlowResumed() { pin.flow <- false }
+
+func TestFlowController(t *testing.T) {
+	root, err := task.Start(&task.Task{Label: "TestFlowController"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer root.Close()
+
+	reg := prometheus.NewRegistry()
+	fc := NewFlowController(FlowOpts{
+		StallTimeout: 50 * time.Millisecond,
+		Metrics:      NewHostMetrics(reg, nil),
+	})
+	app := &testReapApp{ctx: root}
+	serve := func(pinner Pinner, window int64) (*testReapRequester, *flowRequester) {
+		req := &testReapRequester{req: Request{ID: tag.New()}}
+		req.req.SendWindow = window
+		pin, err := fc.serve(pinner, req, root.Closing())
+		if err != nil {
+			t.Fatal(err)
+		}
+		inner := pin.(*flowPin).Pin
+		if flowPin, ok := inner.(*testFlowPin); ok {
+			return req, flowPin.req.(*flowRequester)
+		}
+		return req, inner.(*testReapPin).req.(*flowRequester)
+	}
+
+	// Once a window is exhausted, pushes block until acked, and fail if no ack arrives within StallTimeout
+	req, pushed := serve(app, 2)
+	for i := 0; i < 2; i++ {
+		if err = pushed.PushTx(NewTxMsg(true)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if stats := fc.Stats(); stats.Pins != 1 || stats.Stalled != 1 || stats.Stalls != 1 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		fc.HandleTx(MarshalFlowAck(req.req.ID, 1))
+	}()
+	start := time.Now()
+	if err = pushed.PushTx(NewTxMsg(true)); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 10*time.Millisecond {
+		t.Fatalf("expected push to block until acked, took %v", elapsed)
+	}
+	if err = pushed.PushTx(NewTxMsg(true)); err != ErrFlowStalled {
+		t.Fatalf("expected stalled push to fail, got %v", err)
+	}
+
+	// A pin implementing FlowHandler is paused and resumed instead, and its pushes fail while paused
+	handled := &testFlowPin{flow: make(chan bool, 4)}
+	_, paused := serve(testPinner(func(req Requester) (Pin, error) {
+		handled.req = req
+		handled.ctx, err = root.StartChild(&task.Task{Label: "pin"})
+		return handled, err
+	}), 1)
+	if err = paused.PushTx(NewTxMsg(true)); err != nil || !<-handled.flow {
+		t.Fatalf("expected pin to be paused (%v)", err)
+	}
+	if err = paused.PushTx(NewTxMsg(true)); err != ErrFlowPaused {
+		t.Fatalf("expected paused push to fail, got %v", err)
+	}
+	fc.Ack(paused.win.reqID, 5)
+	if <-handled.flow {
+		t.Fatal("expected pin to be resumed")
+	}
+	if err = paused.PushTx(NewTxMsg(true)); err != nil || !<-handled.flow {
+		t.Fatalf("expected acks to count up to the txs sent (%v)", err)
+	}
+
+	// Requests without a window are not flow controlled, and closed pins are forgotten
+	_, err = fc.serve(app, &testReapRequester{req: Request{ID: tag.New()}}, root.Closing())
+	if err != nil {
+		t.Fatal(err)
+	}
+	handled.ctx.Close()
+	<-handled.ctx.Done()
+	for fc.Stats().Pins != 1 {
+		time.Sleep(time.Millisecond)
+	}
+	if stats := fc.Stats(); stats.Stalled != 1 || stats.Stalls != 4 || stats.Acks != 2 || stats.Refused != 2 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+	var buf bytes.Buffer
+	metrics.WriteText(&buf, reg)
+	for _, line := range []string{"amp_pins_stalled 1", "amp_pin_stalls_total 4", "amp_pin_stall_seconds_count 3"} {
+		if !strings.Contains(buf.String(), line+"\n") {
+			t.Fatalf("missing %q in:\n%s", line, buf.String())
+		}
+	}
+}
+
+func TestAttrVersions(t *testing.T) {
+	cellID, otherSI := tag.New(), tag.New()
+	av := NewAttrVersions()
+	upsert := func(tx *TxMsg, SI tag.ID, version uint64) *TxMsg {
+		if tx == nil {
+			tx = NewTxMsg(true)
+		}
+		tx.MarshalOp(&TxOp{
+			OpCode:   TxOpCode_UpsertAttr,
+			TargetID: cellID,
+			AttrID:   PinnedTabSpec.ID,
+			SI:       SI,
+			Height:   version,
+		}, &TagTab{Label: "edit"})
+		return tx
+	}
+	var appErr error // if set, the app fails to apply commits
+	commit := func(tx *TxMsg) (*testCoalesceRequester, error) {
+		req := &testCoalesceRequester{req: Request{ID: tag.New(), CommitTx: tx}}
+		_, err := av.serve(testPinner(func(req Requester) (Pin, error) {
+			if appErr != nil {
+				return nil, appErr
+			}
+			req.PushTx(upsert(nil, tag.Nil, 0)) // the app pushes the item it applied
+			return nil, nil
+		}), req)
+		return req, err
+	}
+	ver := func(writes uint64) uint64 {
+		return av.epoch | writes
+	}
+	expectVersion := func(SI tag.ID, writes uint64) {
+		t.Helper()
+		if got := av.Version(cellID, PinnedTabSpec.ID, SI); got != ver(writes) {
+			t.Fatalf("expected version %d, got %d", ver(writes), got)
+		}
+	}
+
+	// an unconditional commit advances the item, and pushes are stamped with its version
+	req, err := commit(upsert(nil, tag.Nil, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectVersion(tag.Nil, 1)
+	if len(req.pushed) != 1 || req.pushed[0].Ops[0].Height != ver(1) {
+		t.Fatalf("expected a push stamped with version 1, got %v", req.pushed)
+	}
+	if _, err = commit(upsert(nil, tag.Nil, ver(1))); err != nil {
+		t.Fatal(err)
+	}
+	expectVersion(tag.Nil, 2)
+
+	// a stale commit is rejected with the conflict in its details, without serving it
+	req, err = commit(upsert(nil, tag.Nil, ver(1)))
+	if len(req.pushed) != 0 {
+		t.Fatal("expected a stale commit not to be served")
+	}
+	errTx, _ := MarshalErrTx(req.req.ID, err)
+	conflict, ok := VersionConflictOf(errTx.CloseErr())
+	if !ok || conflict.CellID != cellID || conflict.AttrID != PinnedTabSpec.ID || conflict.SI != tag.Nil ||
+		conflict.Expected != ver(1) || conflict.Version != ver(2) {
+		t.Fatalf("unexpected conflict %+v from %v", conflict, err)
+	}
+	expectVersion(tag.Nil, 2)
+
+	// a commit is applied entirely or not at all
+	if _, err = commit(upsert(upsert(nil, otherSI, VersionAbsent), tag.Nil, ver(1))); GetErrCode(err) != ErrCode_VersionConflict {
+		t.Fatalf("expected ErrCode_VersionConflict, got %v", err)
+	}
+	expectVersion(otherSI, 0)
+	if _, err = commit(upsert(nil, otherSI, VersionAbsent)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = commit(upsert(nil, otherSI, VersionAbsent)); GetErrCode(err) != ErrCode_VersionConflict {
+		t.Fatalf("expected an existing item to conflict with VersionAbsent, got %v", err)
+	}
+
+	// writes an app makes itself advance versions too, including deleting the cell
+	own := upsert(nil, tag.Nil, 0)
+	av.Advance(own)
+	if own.Ops[0].Height != ver(3) {
+		t.Fatalf("expected the app's write stamped with version 3, got %d", own.Ops[0].Height)
+	}
+	deleteCell := NewTxMsg(true)
+	deleteCell.MarshalOpWithBuf(&TxOp{OpCode: TxOpCode_DeleteCell, TargetID: cellID}, nil)
+	av.Advance(deleteCell)
+	expectVersion(tag.Nil, 4)
+	expectVersion(otherSI, 2)
+	if conflicts := av.Check(upsert(nil, otherSI, VersionAbsent)); len(conflicts) != 0 {
+		t.Fatalf("expected a deleted item to be absent, got %v", conflicts)
+	}
+
+	// a commit the app fails to apply does not advance versions, so it can be retried as is
+	appErr = ErrCode_CommitFailed.Error("app is read only")
+	if _, err = commit(upsert(nil, tag.Nil, ver(4))); err != appErr {
+		t.Fatalf("expected the app's error, got %v", err)
+	}
+	expectVersion(tag.Nil, 4)
+	appErr = nil
+	if _, err = commit(upsert(nil, tag.Nil, ver(4))); err != nil {
+		t.Fatal(err)
+	}
+	expectVersion(tag.Nil, 5)
+
+	// items never written are stamped with the epoch, so an edit of one is checked too
+	unversioned := NewTxMsg(true)
+	unversioned.MarshalOp(&TxOp{OpCode: TxOpCode_UpsertAttr, TargetID: tag.New(), AttrID: PinnedTabSpec.ID, Height: 7}, &TagTab{})
+	av.Stamp(unversioned)
+	if unversioned.Ops[0].Height != ver(0) {
+		t.Fatalf("expected an unwritten item stamped with version %d, got %d", ver(0), unversioned.Ops[0].Height)
+	}
+
+	// versions of a restarted host are of a new epoch, so every version sent before the restart conflicts
+	restarted := NewAttrVersions()
+	restarted.Advance(upsert(nil, tag.Nil, 0))
+	if restarted.epoch == av.epoch {
+		t.Fatal("expected a new epoch")
+	}
+	if conflicts := restarted.Check(upsert(nil, tag.Nil, ver(1))); len(conflicts) != 1 {
+		t.Fatalf("expected a version of the old epoch to conflict, got %v", conflicts)
+	}
+}
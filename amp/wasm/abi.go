@@ -0,0 +1,104 @@
+// Package wasm hosts amp apps compiled to WebAssembly so that third-party apps can be run without trusting their native code.
+//
+// A guest module and its host exchange requests and transactions only through the guest's linear memory,
+// using the ABI below (ABIVersion 1).  All pointers and lengths are i32; statuses are i32 amp.ErrCode values (0 is success).
+//
+// Guest exports:
+//
+//	amp_abi_version() i32                          returns ABIVersion
+//	amp_alloc(size i32) i32                        returns a pointer to size bytes the host may write into (owned by the guest thereafter)
+//	amp_make_ready(req_ptr, req_len i32) i32       see amp.AppInstance.MakeReady
+//	amp_serve_request(pin i64, req_ptr, req_len i32) i32  see amp.Pinner.ServeRequest -- pin is a new handle assigned by the host
+//	amp_close_pin(pin i64)                         the pin has closed and should release its resources
+//	amp_on_closing()                               see amp.AppInstance.OnClosing
+//
+// Host imports (module "amp"):
+//
+//	push_tx(pin i64, tx_ptr, tx_len i32) i32       see amp.Requester.PushTx -- tx is a TxMsg as written by TxMsg.MarshalToBuffer
+//	complete(pin i64, code i32, msg_ptr, msg_len i32)  see amp.Requester.OnComplete -- code 0 denotes success
+//	set_err(msg_ptr, msg_len i32)                  sets the message of the error status about to be returned by the guest
+//	log(level, msg_ptr, msg_len i32)               level 0 is info, 1 is warning, and 2 is error
+//
+// A request is marshalled as:
+//
+//	{Request.ID: 24 bytes}{uvarint len}{PinRequest protobuf}[{CommitTx as written by TxMsg.MarshalToBuffer}]
+//
+// Calls into a guest are serialized, and a guest may only call the host during a call from the host.
+// So a guest pushes state for a pin during amp_serve_request (or during a later call) and calls complete() once done.
+//
+// It is a separate module so that hosts not using it do not depend on wazero.
+package wasm
+
+import (
+	"context"
+)
+
+// ABIVersion is the version of the ABI described in the package doc, returned by a guest's amp_abi_version export.
+const ABIVersion = 1
+
+// ImportModule is the module name guests use to import host functions.
+const ImportModule = "amp"
+
+// Runtime is a WebAssembly engine able to compile and instantiate guest modules.
+//
+// NewWazeroRuntime() returns one backed by wazero; a host may adapt another engine to this interface.
+type Runtime interface {
+
+	// Compiles and instantiates the given module binary, binding the given host functions under ImportModule.
+	Instantiate(ctx context.Context, binary []byte, imports []HostFunc) (Module, error)
+}
+
+// Module is an instantiated guest module.  Calls are not required to be concurrency safe.
+type Module interface {
+
+	// Calls the named exported function, returning its results.
+	Call(ctx context.Context, name string, params ...uint64) ([]uint64, error)
+
+	// Returns true if the named function is exported by this module.
+	HasExport(name string) bool
+
+	// Returns this module's linear memory.
+	Memory() Memory
+
+	// Releases this module instance.
+	Close(ctx context.Context) error
+}
+
+// Memory is the linear memory of a guest module.
+type Memory interface {
+
+	// Returns a view of the given range, or false if it is out of range.  The view is only valid until the next call into the guest.
+	Read(offset, byteCount uint32) ([]byte, bool)
+
+	// Writes v at the given offset, returning false if out of range.
+	Write(offset uint32, v []byte) bool
+}
+
+// ValueType is a WebAssembly value type, encoded as in the binary format.
+type ValueType byte
+
+const (
+	ValueTypeI32 ValueType = 0x7f
+	ValueTypeI64 ValueType = 0x7e
+)
+
+// HostFunc is a function exported by the host to guests under ImportModule.
+type HostFunc struct {
+	Name    string
+	Params  []ValueType
+	Results []ValueType
+
+	// Called with the calling module and the params as raw uint64 values, returning the raw results.
+	// The upper 32 bits of an i32 param are undefined.
+	Func func(ctx context.Context, mod Module, params []uint64) []uint64
+}
+
+// Guest exports
+const (
+	exportABIVersion   = "amp_abi_version"
+	exportAlloc        = "amp_alloc"
+	exportMakeReady    = "amp_make_ready"
+	exportServeRequest = "amp_serve_request"
+	exportClosePin     = "amp_close_pin"
+	exportOnClosing    = "amp_on_closing"
+)
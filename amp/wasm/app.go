@@ -0,0 +1,322 @@
+package wasm
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"sync"
+	"sync/atomic"
+
+	"github.com/amp-3d/amp-sdk-go/amp"
+	"github.com/amp-3d/amp-sdk-go/stdlib/tag"
+	"github.com/amp-3d/amp-sdk-go/stdlib/task"
+)
+
+// NewApp returns an amp.App that runs each of its instances in a new instance of the given guest module binary.
+//
+// The given App supplies the app's registration info (AppSpec, Version, Invocations, etc) and its NewAppInstance is ignored.
+// A guest only has access to what passes through the ABI (see package doc) -- it has no access to the host's memory, files, or network.
+func NewApp(app amp.App, binary []byte, rt Runtime) *amp.App {
+	app.NewAppInstance = func(ctx amp.AppContext) (amp.AppInstance, error) {
+		return newAppInstance(ctx, binary, rt)
+	}
+	return &app
+}
+
+// appInstance implements amp.AppInstance by marshalling calls into a guest module.
+type appInstance struct {
+	amp.AppContext
+	mod       Module
+	callMu    sync.Mutex // serializes calls into the guest
+	errMsg    string     // set by set_err during a call into guest (callMu held)
+	pinsMu    sync.Mutex
+	pins      map[int64]*pin
+	nextPinID atomic.Int64
+}
+
+// pin implements amp.Pin for a request served by a guest.
+type pin struct {
+	inst   *appInstance
+	handle int64
+	req    amp.Requester
+	ctx    task.Context
+}
+
+func newAppInstance(ctx amp.AppContext, binary []byte, rt Runtime) (*appInstance, error) {
+	inst := &appInstance{
+		AppContext: ctx,
+		pins:       make(map[int64]*pin),
+	}
+
+	var err error
+	inst.mod, err = rt.Instantiate(ctx, binary, inst.hostFuncs())
+	if err != nil {
+		return nil, amp.ErrCode_ProviderErr.Errorf("failed to instantiate wasm app: %v", err)
+	}
+
+	for _, name := range []string{exportABIVersion, exportAlloc, exportMakeReady, exportServeRequest, exportClosePin, exportOnClosing} {
+		if !inst.mod.HasExport(name) {
+			inst.mod.Close(ctx)
+			return nil, amp.ErrCode_ProviderErr.Errorf("wasm app does not export %q", name)
+		}
+	}
+	results, err := inst.mod.Call(ctx, exportABIVersion)
+	if err == nil && (len(results) != 1 || uint32(results[0]) != ABIVersion) {
+		err = amp.ErrCode_UnsupportedOp.Errorf("wasm app requires unsupported ABI version %v", results)
+	}
+	if err != nil {
+		inst.mod.Close(ctx)
+		return nil, err
+	}
+	return inst, nil
+}
+
+func (inst *appInstance) MakeReady(req amp.Requester) error {
+	buf, err := marshalRequest(req.Request())
+	if err != nil {
+		return err
+	}
+
+	inst.callMu.Lock()
+	defer inst.callMu.Unlock()
+
+	ptr, err := inst.writeToGuest(buf)
+	if err != nil {
+		return err
+	}
+	return inst.callForStatus(exportMakeReady, uint64(ptr), uint64(len(buf)))
+}
+
+func (inst *appInstance) ServeRequest(req amp.Requester) (amp.Pin, error) {
+	buf, err := marshalRequest(req.Request())
+	if err != nil {
+		return nil, err
+	}
+
+	p := &pin{
+		inst:   inst,
+		handle: inst.nextPinID.Add(1),
+		req:    req,
+	}
+	p.ctx, err = inst.StartChild(&task.Task{
+		Label: "wasm pin",
+		OnClosing: func() {
+			inst.closePin(p)
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Register the pin first since the guest may push txs for it before amp_serve_request returns
+	inst.pinsMu.Lock()
+	inst.pins[p.handle] = p
+	inst.pinsMu.Unlock()
+
+	inst.callMu.Lock()
+	ptr, err := inst.writeToGuest(buf)
+	if err == nil {
+		err = inst.callForStatus(exportServeRequest, uint64(p.handle), uint64(ptr), uint64(len(buf)))
+	}
+	inst.callMu.Unlock()
+
+	if err != nil {
+		p.ctx.Close()
+		return nil, err
+	}
+	return p, nil
+}
+
+func (inst *appInstance) OnClosing() {
+	inst.callMu.Lock()
+	defer inst.callMu.Unlock()
+
+	if _, err := inst.mod.Call(inst, exportOnClosing); err != nil {
+		inst.Warnf("wasm app %s failed: %v", exportOnClosing, err)
+	}
+	inst.mod.Close(context.Background())
+}
+
+// closePin removes the given pin and tells the guest to release it.
+func (inst *appInstance) closePin(p *pin) {
+	inst.pinsMu.Lock()
+	_, open := inst.pins[p.handle]
+	delete(inst.pins, p.handle)
+	inst.pinsMu.Unlock()
+	if !open {
+		return
+	}
+
+	inst.callMu.Lock()
+	defer inst.callMu.Unlock()
+	if _, err := inst.mod.Call(inst, exportClosePin, uint64(p.handle)); err != nil {
+		inst.Warnf("wasm app %s failed: %v", exportClosePin, err)
+	}
+}
+
+func (inst *appInstance) getPin(handle uint64) *pin {
+	inst.pinsMu.Lock()
+	defer inst.pinsMu.Unlock()
+	return inst.pins[int64(handle)]
+}
+
+// writeToGuest copies buf into memory allocated by the guest -- callMu must be locked.
+func (inst *appInstance) writeToGuest(buf []byte) (uint32, error) {
+	results, err := inst.mod.Call(inst, exportAlloc, uint64(len(buf)))
+	if err != nil {
+		return 0, amp.ErrCode_ProviderErr.Errorf("wasm app %s failed: %v", exportAlloc, err)
+	}
+	if len(results) != 1 || !inst.mod.Memory().Write(uint32(results[0]), buf) {
+		return 0, amp.ErrCode_ProviderErr.Error("wasm app returned bad allocation")
+	}
+	return uint32(results[0]), nil
+}
+
+// callForStatus calls a guest export that returns an ErrCode status -- callMu must be locked.
+func (inst *appInstance) callForStatus(name string, params ...uint64) error {
+	inst.errMsg = ""
+	results, err := inst.mod.Call(inst, name, params...)
+	if err != nil {
+		return amp.ErrCode_ProviderErr.Errorf("wasm app %s failed: %v", name, err)
+	}
+	if len(results) != 1 {
+		return amp.ErrCode_ProviderErr.Errorf("wasm app %s returned bad status", name)
+	}
+	return statusToErr(int32(results[0]), inst.errMsg)
+}
+
+func statusToErr(code int32, msg string) error {
+	if code == 0 {
+		return nil
+	}
+	if msg == "" {
+		msg = "wasm app error"
+	}
+	return amp.ErrCode(code).Error(msg)
+}
+
+// hostFuncs returns the host functions exported to the guest under ImportModule.
+func (inst *appInstance) hostFuncs() []HostFunc {
+	i32, i64 := ValueTypeI32, ValueTypeI64
+	return []HostFunc{
+		{
+			Name:    "push_tx",
+			Params:  []ValueType{i64, i32, i32},
+			Results: []ValueType{i32},
+			Func: func(ctx context.Context, mod Module, params []uint64) []uint64 {
+				return []uint64{uint64(errToStatus(inst.pushTx(mod, params[0], uint32(params[1]), uint32(params[2]))))}
+			},
+		}, {
+			Name:   "complete",
+			Params: []ValueType{i64, i32, i32, i32},
+			Func: func(ctx context.Context, mod Module, params []uint64) []uint64 {
+				if p := inst.getPin(params[0]); p != nil {
+					msg, _ := mod.Memory().Read(uint32(params[2]), uint32(params[3]))
+					p.req.OnComplete(statusToErr(int32(params[1]), string(msg)))
+					p.ctx.Close()
+				}
+				return nil
+			},
+		}, {
+			Name:   "set_err",
+			Params: []ValueType{i32, i32},
+			Func: func(ctx context.Context, mod Module, params []uint64) []uint64 {
+				msg, _ := mod.Memory().Read(uint32(params[0]), uint32(params[1]))
+				inst.errMsg = string(msg)
+				return nil
+			},
+		}, {
+			Name:   "log",
+			Params: []ValueType{i32, i32, i32},
+			Func: func(ctx context.Context, mod Module, params []uint64) []uint64 {
+				msg, _ := mod.Memory().Read(uint32(params[1]), uint32(params[2]))
+				switch uint32(params[0]) { // the upper bits of an i32 are undefined
+				case 0:
+					inst.Info(0, string(msg))
+				case 1:
+					inst.Warn(string(msg))
+				default:
+					inst.Error(string(msg))
+				}
+				return nil
+			},
+		},
+	}
+}
+
+func (inst *appInstance) pushTx(mod Module, handle uint64, ptr, size uint32) error {
+	p := inst.getPin(handle)
+	if p == nil {
+		return amp.ErrRequestClosed
+	}
+	buf, ok := mod.Memory().Read(ptr, size)
+	if !ok {
+		return amp.ErrCode_MalformedTx.Error("tx out of range")
+	}
+	tx, err := amp.ReadTxMsg(bytes.NewReader(buf))
+	if err != nil {
+		return err
+	}
+	return p.req.PushTx(tx)
+}
+
+func errToStatus(err error) int32 {
+	if err == nil {
+		return 0
+	}
+	if ampErr, ok := err.(*amp.Err); ok {
+		return int32(ampErr.Code)
+	}
+	return int32(amp.ErrCode_UnnamedErr)
+}
+
+func (p *pin) ServeRequest(req amp.Requester) (amp.Pin, error) {
+	return p.inst.ServeRequest(req)
+}
+
+func (p *pin) Context() task.Context {
+	return p.ctx
+}
+
+// marshalRequest marshals the given request as described in the package doc.
+func marshalRequest(req *amp.Request) ([]byte, error) {
+	pinReq, err := req.PinRequest.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	buf := req.ID.AppendTo(make([]byte, 0, 64+len(pinReq)))
+	buf = binary.AppendUvarint(buf, uint64(len(pinReq)))
+	buf = append(buf, pinReq...)
+	if req.CommitTx != nil {
+		var txBuf []byte
+		req.CommitTx.MarshalToBuffer(&txBuf)
+		buf = append(buf, txBuf...)
+	}
+	return buf, nil
+}
+
+// UnmarshalRequest is the inverse of the request marshalling described in the package doc, for use by Go guests (e.g. via TinyGo).
+func UnmarshalRequest(buf []byte) (*amp.Request, error) {
+	if len(buf) < 24 {
+		return nil, amp.ErrCode_MalformedTx.Error("request too short")
+	}
+	req := &amp.Request{}
+	req.ID, _ = tag.FromBytes(buf[:24])
+	buf = buf[24:]
+
+	pinLen, n := binary.Uvarint(buf)
+	if n <= 0 || uint64(len(buf)-n) < pinLen {
+		return nil, amp.ErrMalformedTx
+	}
+	if err := req.PinRequest.Unmarshal(buf[n : n+int(pinLen)]); err != nil {
+		return nil, err
+	}
+	if buf = buf[n+int(pinLen):]; len(buf) > 0 {
+		tx, err := amp.ReadTxMsg(bytes.NewReader(buf))
+		if err != nil {
+			return nil, err
+		}
+		req.CommitTx = tx
+	}
+	return req, nil
+}
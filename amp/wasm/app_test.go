@@ -0,0 +1,272 @@
+package wasm
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/amp-3d/amp-sdk-go/amp"
+	"github.com/amp-3d/amp-sdk-go/stdlib/media"
+	"github.com/amp-3d/amp-sdk-go/stdlib/tag"
+	"github.com/amp-3d/amp-sdk-go/stdlib/task"
+)
+
+func TestWasmApp(t *testing.T) {
+	host, err := task.Start(&task.Task{Label: "wasm host"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer host.Close()
+
+	rt := &goRuntime{}
+	app := NewApp(amp.App{
+		AppSpec: tag.FormSpec(amp.AppSpec, "test.wasm"),
+	}, []byte("guest"), rt)
+
+	inst, err := app.NewAppInstance(&testAppContext{Context: host})
+	if err != nil {
+		t.Fatal(err)
+	}
+	guest := rt.guest
+
+	// Errors (and their codes) cross the boundary
+	err = inst.MakeReady(newTestRequester(nil))
+	if ampErr, ok := err.(*amp.Err); !ok || ampErr.Code != amp.ErrCode_BadRequest || ampErr.Msg != "missing pin target" {
+		t.Fatalf("expected guest error, got %v", err)
+	}
+
+	target := &amp.Tag{URL: "amp://test/cell"}
+	req := newTestRequester(target)
+	if err = inst.MakeReady(req); err != nil {
+		t.Fatal(err)
+	}
+	pin, err := inst.ServeRequest(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case err = <-req.completed:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("pin never completed")
+	}
+	if len(req.txs) != 1 {
+		t.Fatalf("expected 1 tx, got %d", len(req.txs))
+	}
+	var echoed amp.Tag
+	if err = req.txs[0].UnmarshalOpValue(0, &echoed); err != nil || echoed.URL != target.URL {
+		t.Fatalf("unexpected tx value %v: %v", echoed.URL, err)
+	}
+	if op := req.txs[0].Ops[0]; op.TargetID != req.req.ID {
+		t.Fatalf("unexpected tx target %v", op.TargetID)
+	}
+
+	select {
+	case <-pin.Context().Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("pin never closed")
+	}
+	guest.waitFor(t, "closed pin", func() bool { return guest.closedPins == 1 })
+
+	inst.OnClosing()
+	if !guest.closed {
+		t.Fatal("guest module not closed")
+	}
+}
+
+func TestWasmAppABIVersion(t *testing.T) {
+	host, err := task.Start(&task.Task{Label: "wasm host"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer host.Close()
+
+	rt := &goRuntime{abiVersion: ABIVersion + 1}
+	app := NewApp(amp.App{}, nil, rt)
+	if _, err = app.NewAppInstance(&testAppContext{Context: host}); err == nil || !strings.Contains(err.Error(), "ABI") {
+		t.Fatalf("expected ABI version error, got %v", err)
+	}
+	if !rt.guest.closed {
+		t.Fatal("guest module not closed")
+	}
+}
+
+type testAppContext struct {
+	task.Context
+	media.Publisher
+}
+
+func (ctx *testAppContext) Session() amp.HostSession                          { return nil }
+func (ctx *testAppContext) LocalDataPath() string                             { return "" }
+func (ctx *testAppContext) GetAppAttr(attrSpec tag.ID, dst amp.ElemVal) error { return nil }
+func (ctx *testAppContext) PutAppAttr(attrSpec tag.ID, src amp.ElemVal) error { return nil }
+
+type testRequester struct {
+	req       *amp.Request
+	txs       []*amp.TxMsg
+	completed chan error
+}
+
+func newTestRequester(target *amp.Tag) *testRequester {
+	return &testRequester{
+		req: &amp.Request{
+			PinRequest: amp.PinRequest{PinTarget: target},
+			ID:         tag.New(),
+		},
+		completed: make(chan error, 1),
+	}
+}
+
+func (r *testRequester) Request() *amp.Request      { return r.req }
+func (r *testRequester) PushTx(tx *amp.TxMsg) error { r.txs = append(r.txs, tx); return nil }
+func (r *testRequester) OnComplete(err error)       { r.completed <- err }
+
+// goRuntime "instantiates" a goGuest, standing in for a WebAssembly engine running a compiled guest.
+type goRuntime struct {
+	abiVersion uint64
+	guest      *goGuest
+}
+
+func (rt *goRuntime) Instantiate(ctx context.Context, binary []byte, imports []HostFunc) (Module, error) {
+	rt.guest = &goGuest{
+		abiVersion: rt.abiVersion,
+		mem:        &testMemory{buf: make([]byte, 0, 1<<16)},
+		imports:    make(map[string]HostFunc),
+	}
+	if rt.guest.abiVersion == 0 {
+		rt.guest.abiVersion = ABIVersion
+	}
+	for _, fn := range imports {
+		rt.guest.imports[fn.Name] = fn
+	}
+	return rt.guest, nil
+}
+
+// goGuest implements the guest side of the ABI: it validates requests and echoes each pinned target back as a tx.
+type goGuest struct {
+	mu         sync.Mutex
+	abiVersion uint64
+	mem        *testMemory
+	imports    map[string]HostFunc
+	closedPins int
+	closed     bool
+}
+
+func (g *goGuest) waitFor(t *testing.T, what string, cond func() bool) {
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+		g.mu.Lock()
+		done := cond()
+		g.mu.Unlock()
+		if done {
+			return
+		}
+	}
+	t.Fatalf("timed out waiting for %s", what)
+}
+
+func (g *goGuest) HasExport(name string) bool {
+	return strings.HasPrefix(name, "amp_")
+}
+
+func (g *goGuest) Memory() Memory {
+	return g.mem
+}
+
+func (g *goGuest) Close(ctx context.Context) error {
+	g.closed = true
+	return nil
+}
+
+func (g *goGuest) callHost(ctx context.Context, name string, params ...uint64) []uint64 {
+	return g.imports[name].Func(ctx, g, params)
+}
+
+func (g *goGuest) store(buf []byte) (ptr, size uint64) {
+	return uint64(g.mem.alloc(buf)), uint64(len(buf))
+}
+
+func (g *goGuest) fail(ctx context.Context, code amp.ErrCode, msg string) []uint64 {
+	ptr, size := g.store([]byte(msg))
+	g.callHost(ctx, "set_err", ptr, size)
+	return []uint64{uint64(code)}
+}
+
+func (g *goGuest) Call(ctx context.Context, name string, params ...uint64) ([]uint64, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	readRequest := func(ptr, size uint64) (*amp.Request, error) {
+		buf, _ := g.mem.Read(uint32(ptr), uint32(size))
+		return UnmarshalRequest(buf)
+	}
+
+	switch name {
+	case exportABIVersion:
+		return []uint64{g.abiVersion}, nil
+	case exportAlloc:
+		return []uint64{uint64(g.mem.alloc(make([]byte, params[0])))}, nil
+	case exportMakeReady:
+		req, err := readRequest(params[0], params[1])
+		if err != nil {
+			return nil, err
+		}
+		if req.PinTarget == nil {
+			return g.fail(ctx, amp.ErrCode_BadRequest, "missing pin target"), nil
+		}
+		return []uint64{0}, nil
+	case exportServeRequest:
+		req, err := readRequest(params[1], params[2])
+		if err != nil {
+			return nil, err
+		}
+		tx := amp.NewTxMsg(true)
+		if err = tx.MarshalUpsert(req.ID, amp.PinnedTabSpec.ID, req.PinTarget); err != nil {
+			return nil, err
+		}
+		var txBuf []byte
+		tx.MarshalToBuffer(&txBuf)
+		ptr, size := g.store(txBuf)
+		if status := g.callHost(ctx, "push_tx", params[0], ptr, size); status[0] != 0 {
+			return status, nil
+		}
+		g.callHost(ctx, "complete", params[0], 0, 0, 0)
+		return []uint64{0}, nil
+	case exportClosePin:
+		g.closedPins++
+		return nil, nil
+	case exportOnClosing:
+		return nil, nil
+	}
+	return nil, amp.ErrCode_UnsupportedOp.Errorf("unknown export %q", name)
+}
+
+type testMemory struct {
+	buf []byte
+}
+
+func (m *testMemory) alloc(v []byte) uint32 {
+	ptr := len(m.buf)
+	m.buf = append(m.buf, v...)
+	m.buf = append(m.buf, make([]byte, 8-len(m.buf)%8)...) // keep allocations aligned
+	return uint32(ptr)
+}
+
+func (m *testMemory) Read(offset, byteCount uint32) ([]byte, bool) {
+	if uint64(offset)+uint64(byteCount) > uint64(len(m.buf)) {
+		return nil, false
+	}
+	return m.buf[offset : offset+byteCount], true
+}
+
+func (m *testMemory) Write(offset uint32, v []byte) bool {
+	if uint64(offset)+uint64(len(v)) > uint64(len(m.buf)) {
+		return false
+	}
+	copy(m.buf[offset:], v)
+	return true
+}
@@ -0,0 +1,110 @@
+//go:build wasip1
+
+// Command echo_guest is the guest run by TestWazeroApp: it implements the ABI (see package wasm) by echoing each pinned target
+// back as a tx.  The test builds it with:
+//
+//	GOOS=wasip1 GOARCH=wasm go build -buildmode=c-shared
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"unsafe"
+
+	"github.com/amp-3d/amp-sdk-go/amp"
+	"github.com/amp-3d/amp-sdk-go/amp/wasm"
+)
+
+//go:wasmimport amp push_tx
+func pushTx(pin int64, ptr, size uint32) int32
+
+//go:wasmimport amp complete
+func complete(pin int64, code int32, ptr, size uint32)
+
+//go:wasmimport amp set_err
+func setErr(ptr, size uint32)
+
+//go:wasmimport amp log
+func logMsg(level int32, ptr, size uint32)
+
+// allocs holds the buffers handed to the host until the call they were allocated for reads them.
+var allocs = map[uint32][]byte{}
+
+func main() {}
+
+//go:wasmexport amp_abi_version
+func abiVersion() int32 {
+	return wasm.ABIVersion
+}
+
+//go:wasmexport amp_alloc
+func alloc(size uint32) uint32 {
+	buf := make([]byte, size+1) // never empty, so each allocation has its own address
+	ptr := uint32(uintptr(unsafe.Pointer(unsafe.SliceData(buf))))
+	allocs[ptr] = buf[:size]
+	return ptr
+}
+
+//go:wasmexport amp_make_ready
+func makeReady(ptr, size uint32) int32 {
+	req, err := readRequest(ptr, size)
+	if err == nil && req.PinTarget == nil {
+		err = amp.ErrCode_BadRequest.Error("missing pin target")
+	}
+	return status(err)
+}
+
+//go:wasmexport amp_serve_request
+func serveRequest(pin int64, ptr, size uint32) int32 {
+	req, err := readRequest(ptr, size)
+	if err != nil {
+		return status(err)
+	}
+	tx := amp.NewTxMsg(true)
+	if err = tx.MarshalUpsert(req.ID, amp.PinnedTabSpec.ID, req.PinTarget); err != nil {
+		return status(err)
+	}
+	var txBuf []byte
+	tx.MarshalToBuffer(&txBuf)
+	if code := pushTx(pin, bufPtr(txBuf), uint32(len(txBuf))); code != 0 {
+		return code
+	}
+	runtime.KeepAlive(txBuf)
+	complete(pin, 0, 0, 0)
+	return 0
+}
+
+//go:wasmexport amp_close_pin
+func closePin(pin int64) {
+	msg := []byte(fmt.Sprintf("closed pin %d", pin))
+	logMsg(0, bufPtr(msg), uint32(len(msg)))
+	runtime.KeepAlive(msg)
+}
+
+//go:wasmexport amp_on_closing
+func onClosing() {}
+
+func readRequest(ptr, size uint32) (*amp.Request, error) {
+	buf := allocs[ptr]
+	delete(allocs, ptr)
+	return wasm.UnmarshalRequest(buf[:size])
+}
+
+// status returns the ABI status for err, setting its message with the host.
+func status(err error) int32 {
+	if err == nil {
+		return 0
+	}
+	code := amp.ErrCode_UnnamedErr
+	if ampErr, ok := err.(*amp.Err); ok {
+		code, err = ampErr.Code, fmt.Errorf("%s", ampErr.Msg)
+	}
+	msg := []byte(err.Error())
+	setErr(bufPtr(msg), uint32(len(msg)))
+	runtime.KeepAlive(msg)
+	return int32(code)
+}
+
+func bufPtr(buf []byte) uint32 {
+	return uint32(uintptr(unsafe.Pointer(unsafe.SliceData(buf))))
+}
@@ -0,0 +1,117 @@
+package wasm
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+)
+
+// NewWazeroRuntime returns a Runtime that runs guests using wazero (github.com/tetratelabs/wazero), a WebAssembly engine
+// written in Go.  If cache is non-nil, modules compiled by one instance are reused by the next (the caller owns and closes it).
+//
+// Each guest is instantiated as a reactor (its _initialize export, if any, is called first) in its own wazero.Runtime, since
+// each binds its own host functions under ImportModule.  WASI is provided so that guests built by Go (GOOS=wasip1) or TinyGo
+// run, but with no files, env, or args (beyond argv[0]) -- only the clocks, a random source, and discarded stdout / stderr.
+func NewWazeroRuntime(cache wazero.CompilationCache) Runtime {
+	config := wazero.NewRuntimeConfig()
+	if cache != nil {
+		config = config.WithCompilationCache(cache)
+	}
+	return &wazeroRuntime{
+		config: config,
+	}
+}
+
+type wazeroRuntime struct {
+	config wazero.RuntimeConfig
+}
+
+func (rt *wazeroRuntime) Instantiate(ctx context.Context, binary []byte, imports []HostFunc) (Module, error) {
+	r := wazero.NewRuntimeWithConfig(ctx, rt.config)
+	mod, err := rt.instantiate(ctx, r, binary, imports)
+	if err != nil {
+		r.Close(ctx)
+		return nil, err
+	}
+	return &wazeroModule{
+		mod: mod,
+		rt:  r,
+	}, nil
+}
+
+func (rt *wazeroRuntime) instantiate(ctx context.Context, r wazero.Runtime, binary []byte, imports []HostFunc) (api.Module, error) {
+	if _, err := wasi_snapshot_preview1.Instantiate(ctx, r); err != nil {
+		return nil, err
+	}
+
+	host := r.NewHostModuleBuilder(ImportModule)
+	for _, fn := range imports {
+		call := func(ctx context.Context, mod api.Module, stack []uint64) {
+			results := fn.Func(ctx, &wazeroModule{mod: mod}, stack[:len(fn.Params)])
+			copy(stack, results)
+		}
+		host.NewFunctionBuilder().
+			WithGoModuleFunction(api.GoModuleFunc(call), valueTypes(fn.Params), valueTypes(fn.Results)).
+			Export(fn.Name)
+	}
+	if _, err := host.Instantiate(ctx); err != nil {
+		return nil, err
+	}
+
+	compiled, err := r.CompileModule(ctx, binary)
+	if err != nil {
+		return nil, err
+	}
+	mod, err := r.InstantiateModule(ctx, compiled, wazero.NewModuleConfig().
+		WithName("").
+		WithArgs("guest"). // just argv[0], which Go guests expect
+		WithStartFunctions("_initialize").
+		WithSysWalltime().
+		WithSysNanotime().
+		WithRandSource(rand.Reader))
+	if err == nil && mod.Memory() == nil {
+		err = fmt.Errorf("wasm module does not export its memory")
+	}
+	return mod, err
+}
+
+func valueTypes(types []ValueType) []api.ValueType {
+	out := make([]api.ValueType, len(types))
+	for i, t := range types {
+		out[i] = api.ValueType(t)
+	}
+	return out
+}
+
+// wazeroModule implements Module using a wazero module instance.
+type wazeroModule struct {
+	mod api.Module
+	rt  wazero.Runtime // closed along with mod, if set
+}
+
+func (m *wazeroModule) Call(ctx context.Context, name string, params ...uint64) ([]uint64, error) {
+	fn := m.mod.ExportedFunction(name)
+	if fn == nil {
+		return nil, fmt.Errorf("wasm module does not export %q", name)
+	}
+	return fn.Call(ctx, params...)
+}
+
+func (m *wazeroModule) HasExport(name string) bool {
+	return m.mod.ExportedFunction(name) != nil
+}
+
+func (m *wazeroModule) Memory() Memory {
+	return m.mod.Memory() // api.Memory's Read and Write match Memory's
+}
+
+func (m *wazeroModule) Close(ctx context.Context) error {
+	if m.rt != nil {
+		return m.rt.Close(ctx)
+	}
+	return m.mod.Close(ctx)
+}
@@ -0,0 +1,124 @@
+package wasm
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/amp-3d/amp-sdk-go/amp"
+	"github.com/amp-3d/amp-sdk-go/stdlib/tag"
+	"github.com/amp-3d/amp-sdk-go/stdlib/task"
+)
+
+// TestWazeroApp runs testdata/echo_guest, compiled to WebAssembly, through the ABI.
+func TestWazeroApp(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds a wasm guest")
+	}
+	binary := buildGuest(t, "./testdata/echo_guest")
+
+	host, err := task.Start(&task.Task{Label: "wasm host"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer host.Close()
+
+	app := NewApp(amp.App{
+		AppSpec: tag.FormSpec(amp.AppSpec, "test.wasm"),
+	}, binary, NewWazeroRuntime(nil))
+
+	ctx := &loggingAppContext{
+		testAppContext: testAppContext{Context: host},
+		logs:           make(chan string, 8),
+	}
+	inst, err := app.NewAppInstance(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = inst.MakeReady(newTestRequester(nil))
+	if ampErr, ok := err.(*amp.Err); !ok || ampErr.Code != amp.ErrCode_BadRequest || ampErr.Msg != "missing pin target" {
+		t.Fatalf("expected guest error, got %v", err)
+	}
+
+	target := &amp.Tag{URL: "amp://test/cell"}
+	req := newTestRequester(target)
+	if err = inst.MakeReady(req); err != nil {
+		t.Fatal(err)
+	}
+	pin, err := inst.ServeRequest(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err = <-req.completed:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("pin never completed")
+	}
+	if len(req.txs) != 1 {
+		t.Fatalf("expected 1 tx, got %d", len(req.txs))
+	}
+	var echoed amp.Tag
+	if err = req.txs[0].UnmarshalOpValue(0, &echoed); err != nil || echoed.URL != target.URL {
+		t.Fatalf("unexpected tx value %v: %v", echoed.URL, err)
+	}
+	if op := req.txs[0].Ops[0]; op.TargetID != req.req.ID {
+		t.Fatalf("unexpected tx target %v", op.TargetID)
+	}
+
+	select {
+	case <-pin.Context().Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("pin never closed")
+	}
+	select {
+	case msg := <-ctx.logs:
+		if msg != "closed pin 1" {
+			t.Fatalf("unexpected guest log %q", msg)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("guest never closed pin")
+	}
+
+	inst.OnClosing()
+	if _, err = inst.(*appInstance).mod.Call(context.Background(), exportABIVersion); err == nil {
+		t.Fatal("expected guest module to be closed")
+	}
+}
+
+// buildGuest compiles the given guest package with GOOS=wasip1, returning the module binary.
+func buildGuest(t *testing.T, pkg string) []byte {
+	t.Helper()
+	out := filepath.Join(t.TempDir(), "guest.wasm")
+	cmd := exec.Command("go", "build", "-buildmode=c-shared", "-o", out, pkg)
+	cmd.Env = append(os.Environ(), "GOOS=wasip1", "GOARCH=wasm")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("failed to build %s: %v\n%s", pkg, err, output)
+	}
+	binary, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return binary
+}
+
+// loggingAppContext sends the info and warning messages logged for a guest to logs.
+type loggingAppContext struct {
+	testAppContext
+	logs chan string
+}
+
+func (ctx *loggingAppContext) Info(inVerboseLevel int32, args ...interface{}) {
+	ctx.logs <- fmt.Sprint(args...)
+}
+
+func (ctx *loggingAppContext) Warnf(inFormat string, args ...interface{}) {
+	ctx.logs <- fmt.Sprintf(inFormat, args...)
+}
@@ -0,0 +1,328 @@
+package ws
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/amp-3d/amp-sdk-go/amp"
+)
+
+// WebSocket opcodes (RFC 6455 section 5.2)
+const (
+	opContinuation = 0x0
+	opText         = 0x1
+	opBinary       = 0x2
+	opClose        = 0x8
+	opPing         = 0x9
+	opPong         = 0xA
+
+	finBit  = 0x80
+	rsv1Bit = 0x40 // set on messages compressed via permessage-deflate
+	maskBit = 0x80
+
+	maxControlPayload = 125
+)
+
+// Close status codes (RFC 6455 section 7.4.1)
+const (
+	closeNormal        = 1000
+	closeProtocolError = 1002
+	closeUnsupported   = 1003
+	closeTooBig        = 1009
+)
+
+// deflateTail is removed from each compressed message and restored before inflating (RFC 7692 section 7.2.1).
+var deflateTail = []byte{0x00, 0x00, 0xff, 0xff}
+
+// conn is a WebSocket connection carrying one TxMsg per binary message, implementing amp.Transport.
+type conn struct {
+	label    string
+	netConn  net.Conn
+	br       *bufio.Reader
+	isClient bool // clients mask the frames they send (RFC 6455 section 5.3)
+	deflate  bool // permessage-deflate was negotiated
+	maxSize  int
+
+	writeMu sync.Mutex // serializes frame writes
+	scrap   []byte     // tx marshalling buffer, protected by writeMu
+	frame   []byte     // frame buffer, protected by writeMu
+	zbuf    bytes.Buffer
+	zw      *flate.Writer
+
+	closeOnce sync.Once
+	closeErr  error
+}
+
+var _ amp.Transport = (*conn)(nil)
+
+func newConn(label string, netConn net.Conn, br *bufio.Reader, isClient, deflate bool, maxSize int) *conn {
+	if br == nil {
+		br = bufio.NewReader(netConn)
+	}
+	return &conn{
+		label:    label,
+		netConn:  netConn,
+		br:       br,
+		isClient: isClient,
+		deflate:  deflate,
+		maxSize:  maxSize,
+	}
+}
+
+func (c *conn) Label() string {
+	return c.label
+}
+
+// Close sends a close frame (best effort) and closes the underlying connection.
+func (c *conn) Close() error {
+	c.closeOnce.Do(func() {
+		var payload [2]byte
+		binary.BigEndian.PutUint16(payload[:], closeNormal)
+		c.netConn.SetWriteDeadline(time.Now().Add(time.Second))
+		c.writeFrame(opClose, 0, payload[:])
+		c.closeErr = c.netConn.Close()
+	})
+	return c.closeErr
+}
+
+func (c *conn) SendTx(tx *amp.TxMsg) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	// The tx's DataStore is framed as is rather than copied after its header and body
+	tx.MarshalHeaderAndBody(&c.scrap)
+	defer tx.ReleaseRef()
+
+	payload := [][]byte{c.scrap, tx.DataStore}
+	var rsv byte
+	if c.deflate {
+		if c.zw == nil {
+			c.zw, _ = flate.NewWriter(&c.zbuf, flate.DefaultCompression)
+		}
+		c.zbuf.Reset()
+		c.zw.Reset(&c.zbuf)
+		c.zw.Write(c.scrap)
+		c.zw.Write(tx.DataStore)
+		if err := c.zw.Flush(); err != nil {
+			return err
+		}
+		payload = [][]byte{bytes.TrimSuffix(c.zbuf.Bytes(), deflateTail)}
+		rsv = rsv1Bit
+	}
+	if err := c.writeFrameLocked(opBinary, rsv, payload...); err != nil {
+		return amp.ErrCode_NotConnected.Errorf("websocket write failed: %v", err)
+	}
+	return nil
+}
+
+func (c *conn) writeFrame(opcode, rsv byte, payload []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	return c.writeFrameLocked(opcode, rsv, payload)
+}
+
+// writeFrameLocked writes a frame whose payload is the given parts, in order.
+func (c *conn) writeFrameLocked(opcode, rsv byte, payload ...[]byte) error {
+	frame := append(c.frame[:0], finBit|rsv|opcode)
+
+	var mask byte
+	if c.isClient {
+		mask = maskBit
+	}
+	n := 0
+	for _, part := range payload {
+		n += len(part)
+	}
+	switch {
+	case n <= 125:
+		frame = append(frame, mask|byte(n))
+	case n <= 0xFFFF:
+		frame = append(frame, mask|126)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(n))
+	default:
+		frame = append(frame, mask|127)
+		frame = binary.BigEndian.AppendUint64(frame, uint64(n))
+	}
+
+	if c.isClient {
+		var key [4]byte
+		if _, err := rand.Read(key[:]); err != nil {
+			return err
+		}
+		frame = append(frame, key[:]...)
+		start := len(frame)
+		for _, part := range payload {
+			frame = append(frame, part...)
+		}
+		maskBytes(key, frame[start:])
+		c.frame = frame
+
+		_, err := c.netConn.Write(frame)
+		return err
+	}
+	c.frame = frame
+
+	// An unmasked payload is written as is, after the frame header (in one writev call where supported)
+	parts := append(net.Buffers{frame}, payload...)
+	_, err := parts.WriteTo(c.netConn)
+	return err
+}
+
+// RecvTx reads the next binary message and returns the tx it carries, answering pings and closes along the way.
+func (c *conn) RecvTx() (*amp.TxMsg, error) {
+	msg, err := c.readMessage()
+	if err != nil {
+		return nil, err
+	}
+	return amp.ReadTxMsg(bytes.NewReader(msg))
+}
+
+func (c *conn) readMessage() ([]byte, error) {
+	var (
+		msg        []byte
+		msgOp      byte
+		compressed bool
+	)
+	for {
+		var hdr [2]byte
+		if _, err := io.ReadFull(c.br, hdr[:]); err != nil {
+			return nil, c.readErr(err)
+		}
+		fin := hdr[0]&finBit != 0
+		rsv := hdr[0] & 0x70
+		opcode := hdr[0] & 0x0F
+		masked := hdr[1]&maskBit != 0
+
+		length := uint64(hdr[1] & 0x7F)
+		switch length {
+		case 126:
+			var ext [2]byte
+			if _, err := io.ReadFull(c.br, ext[:]); err != nil {
+				return nil, c.readErr(err)
+			}
+			length = uint64(binary.BigEndian.Uint16(ext[:]))
+		case 127:
+			var ext [8]byte
+			if _, err := io.ReadFull(c.br, ext[:]); err != nil {
+				return nil, c.readErr(err)
+			}
+			length = binary.BigEndian.Uint64(ext[:])
+			if length>>63 != 0 {
+				return nil, c.fail(closeProtocolError, "bad frame length")
+			}
+		}
+
+		isControl := opcode&0x08 != 0
+		switch {
+		case masked == c.isClient:
+			return nil, c.fail(closeProtocolError, "bad frame masking")
+		case rsv != 0 && (rsv != rsv1Bit || !c.deflate || isControl || opcode == opContinuation):
+			return nil, c.fail(closeProtocolError, "unexpected reserved bits")
+		case isControl && (length > maxControlPayload || !fin):
+			return nil, c.fail(closeProtocolError, "bad control frame")
+		case !isControl && length > uint64(c.maxSize)-uint64(len(msg)): // len(msg) <= maxSize, so this cannot wrap
+			return nil, c.fail(closeTooBig, "message too big")
+		}
+
+		var key [4]byte
+		if masked {
+			if _, err := io.ReadFull(c.br, key[:]); err != nil {
+				return nil, c.readErr(err)
+			}
+		}
+		payload := make([]byte, length)
+		if _, err := io.ReadFull(c.br, payload); err != nil {
+			return nil, c.readErr(err)
+		}
+		if masked {
+			maskBytes(key, payload)
+		}
+
+		switch opcode {
+		case opPing:
+			if err := c.writeFrame(opPong, 0, payload); err != nil {
+				return nil, c.readErr(err)
+			}
+			continue
+		case opPong:
+			continue
+		case opClose:
+			c.closeOnce.Do(func() {
+				c.writeFrame(opClose, 0, payload[:len(payload)&^1])
+				c.closeErr = c.netConn.Close()
+			})
+			return nil, amp.ErrStreamClosed
+		case opText, opBinary:
+			if msgOp != 0 {
+				return nil, c.fail(closeProtocolError, "expected continuation frame")
+			}
+			msgOp = opcode
+			compressed = rsv == rsv1Bit
+		case opContinuation:
+			if msgOp == 0 {
+				return nil, c.fail(closeProtocolError, "unexpected continuation frame")
+			}
+		default:
+			return nil, c.fail(closeProtocolError, "unknown opcode")
+		}
+
+		msg = append(msg, payload...)
+		if !fin {
+			continue
+		}
+		if msgOp != opBinary {
+			return nil, c.fail(closeUnsupported, "only binary messages are supported")
+		}
+		if compressed {
+			return c.inflate(msg)
+		}
+		return msg, nil
+	}
+}
+
+func (c *conn) inflate(msg []byte) ([]byte, error) {
+	zr := flate.NewReader(io.MultiReader(bytes.NewReader(msg), bytes.NewReader(deflateTail)))
+	defer zr.Close()
+
+	out, err := io.ReadAll(io.LimitReader(zr, int64(c.maxSize)+1))
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return nil, c.fail(closeProtocolError, "bad compressed message")
+	}
+	if len(out) > c.maxSize {
+		return nil, c.fail(closeTooBig, "message too big")
+	}
+	return out, nil
+}
+
+// fail closes the connection with the given status code and returns the corresponding error.
+func (c *conn) fail(code uint16, reason string) error {
+	c.closeOnce.Do(func() {
+		payload := binary.BigEndian.AppendUint16(nil, code)
+		payload = append(payload, reason...)
+		c.netConn.SetWriteDeadline(time.Now().Add(time.Second))
+		c.writeFrame(opClose, 0, payload)
+		c.closeErr = c.netConn.Close()
+	})
+	return amp.ErrCode_MalformedTx.Errorf("websocket: %s", reason)
+}
+
+func (c *conn) readErr(err error) error {
+	if err == io.EOF || err == io.ErrUnexpectedEOF || errors.Is(err, net.ErrClosed) {
+		return amp.ErrStreamClosed
+	}
+	return amp.ErrCode_NotConnected.Errorf("websocket read failed: %v", err)
+}
+
+func maskBytes(key [4]byte, buf []byte) {
+	for i := range buf {
+		buf[i] ^= key[i&3]
+	}
+}
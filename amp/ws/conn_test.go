@@ -0,0 +1,62 @@
+package ws
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/amp-3d/amp-sdk-go/amp"
+)
+
+// readFrames has a host conn read the given raw frames (as sent by a client) and returns the close code the host replied with
+// and the read error.
+func readFrames(t *testing.T, frames ...[]byte) (uint16, error) {
+	t.Helper()
+	clientSide, hostSide := net.Pipe()
+	defer clientSide.Close()
+	host := newConn("test", hostSide, nil, false, false, 4096)
+
+	go func() {
+		for _, frame := range frames {
+			if _, err := clientSide.Write(frame); err != nil {
+				return // the host failed before reading every frame
+			}
+		}
+	}()
+	reply := make(chan []byte, 1)
+	go func() {
+		buf, _ := io.ReadAll(clientSide)
+		reply <- buf
+	}()
+
+	_, err := host.readMessage()
+	closeFrame := <-reply
+	if len(closeFrame) < 4 || closeFrame[0]&0x0F != opClose {
+		t.Fatalf("expected a close frame, got %x", closeFrame)
+	}
+	return binary.BigEndian.Uint16(closeFrame[2:4]), err
+}
+
+// frame64 returns a masked client frame header announcing the given 64-bit payload length.
+func frame64(opcode byte, length uint64) []byte {
+	hdr := []byte{opcode, maskBit | 127}
+	hdr = binary.BigEndian.AppendUint64(hdr, length)
+	return append(hdr, 0, 0, 0, 0) // mask key
+}
+
+func TestFrameLength(t *testing.T) {
+	first := []byte{opBinary, maskBit | 1, 0, 0, 0, 0, 'x'} // non-fin, 1 byte
+
+	// A continuation whose length would wrap the size check must not be allocated
+	code, err := readFrames(t, first, frame64(finBit|opContinuation, 0xffffffffffffffff))
+	if ampErr, _ := err.(*amp.Err); ampErr == nil || ampErr.Code != amp.ErrCode_MalformedTx || code != closeProtocolError {
+		t.Fatalf("expected a protocol error, got %v (close code %d)", err, code)
+	}
+
+	// Lengths with the most significant bit clear are checked against the max message size
+	code, err = readFrames(t, first, frame64(finBit|opContinuation, 1<<62))
+	if err == nil || code != closeTooBig {
+		t.Fatalf("expected message too big, got %v (close code %d)", err, code)
+	}
+}
@@ -0,0 +1,387 @@
+// Package ws is a WebSocket (RFC 6455) amp.Transport, allowing clients to reach a host through HTTP-only infrastructure.
+//
+// Each TxMsg is carried as one binary message with the same framing used by other transports (see amp.TxHeader).
+// Messages are compressed when permessage-deflate (RFC 7692) is negotiated, and browser origins are checked during the upgrade.
+//
+// A host selects this transport at startup by attaching the HostService returned by NewService, or by calling Upgrade from its own http.Handler.
+// Clients connect via Dial.
+package ws
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/tls"
+	"encoding/base64"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/amp-3d/amp-sdk-go/amp"
+	"github.com/amp-3d/amp-sdk-go/stdlib/task"
+)
+
+const (
+	// Subprotocol is offered by clients and selected by the host (Sec-WebSocket-Protocol).
+	Subprotocol = "amp.tx"
+
+	DefaultPath           = "/amp"
+	DefaultMaxMessageSize = 16 << 20
+)
+
+// acceptGUID is appended to Sec-WebSocket-Key to form Sec-WebSocket-Accept (RFC 6455 section 1.3).
+const acceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// Opts configures the host side of the WebSocket transport.
+type Opts struct {
+	Addr      string      // listen address used by NewService (e.g. ":5193")
+	Path      string      // URL path served by NewService (default DefaultPath)
+	TLSConfig *tls.Config // if set, NewService serves wss://
+
+	// Origins allowed to connect, matched against the Origin header's host, where "*" allows any origin and "*.example.com" allows subdomains.
+	// If empty, only requests with no Origin (non-browser clients) or whose Origin matches the request's Host are allowed.
+	AllowedOrigins []string
+
+	// If set, used instead of AllowedOrigins to decide whether a request may be upgraded.
+	CheckOrigin func(r *http.Request) bool
+
+	Compression    bool // if set, permessage-deflate is accepted when a client offers it
+	MaxMessageSize int  // max size of a received message, compressed or not (default DefaultMaxMessageSize)
+}
+
+// DialOpts configures a client connection (see Dial).
+type DialOpts struct {
+	Header         http.Header // additional handshake headers (e.g. Origin or Authorization)
+	TLSConfig      *tls.Config // used for wss:// URLs
+	Compression    bool        // if set, permessage-deflate is offered to the host
+	MaxMessageSize int         // max size of a received message (default DefaultMaxMessageSize)
+}
+
+// Upgrade performs the WebSocket handshake for the given request and returns the resulting amp.Transport.
+// On failure, an HTTP error has been written to w.
+func Upgrade(w http.ResponseWriter, r *http.Request, opts Opts) (amp.Transport, error) {
+	fail := func(status int, msg string) (amp.Transport, error) {
+		http.Error(w, msg, status)
+		return nil, amp.ErrCode_BadRequest.Errorf("websocket upgrade: %s", msg)
+	}
+
+	if r.Method != http.MethodGet {
+		return fail(http.StatusMethodNotAllowed, "method not allowed")
+	}
+	if !headerHasToken(r.Header, "Connection", "upgrade") || !headerHasToken(r.Header, "Upgrade", "websocket") {
+		return fail(http.StatusBadRequest, "not a websocket handshake")
+	}
+	if r.Header.Get("Sec-WebSocket-Version") != "13" {
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		return fail(http.StatusUpgradeRequired, "unsupported websocket version")
+	}
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if decoded, err := base64.StdEncoding.DecodeString(key); err != nil || len(decoded) != 16 {
+		return fail(http.StatusBadRequest, "bad Sec-WebSocket-Key")
+	}
+	checkOrigin := opts.CheckOrigin
+	if checkOrigin == nil {
+		checkOrigin = func(r *http.Request) bool {
+			return originAllowed(r, opts.AllowedOrigins)
+		}
+	}
+	if !checkOrigin(r) {
+		return fail(http.StatusForbidden, "origin not allowed")
+	}
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		return fail(http.StatusInternalServerError, "connection does not support hijacking")
+	}
+
+	deflate := opts.Compression && offersDeflate(r.Header)
+
+	resp := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + acceptKey(key) + "\r\n"
+	if headerHasToken(r.Header, "Sec-WebSocket-Protocol", Subprotocol) {
+		resp += "Sec-WebSocket-Protocol: " + Subprotocol + "\r\n"
+	}
+	if deflate {
+		resp += "Sec-WebSocket-Extensions: permessage-deflate; server_no_context_takeover; client_no_context_takeover\r\n"
+	}
+	resp += "\r\n"
+
+	netConn, brw, err := hijacker.Hijack()
+	if err != nil {
+		return nil, amp.ErrCode_NotConnected.Errorf("websocket upgrade: %v", err)
+	}
+	if _, err = netConn.Write([]byte(resp)); err != nil {
+		netConn.Close()
+		return nil, amp.ErrCode_NotConnected.Errorf("websocket upgrade: %v", err)
+	}
+
+	label := "ws:" + r.RemoteAddr
+	return newConn(label, netConn, brw.Reader, false, deflate, maxSize(opts.MaxMessageSize)), nil
+}
+
+// Dial connects to a host at the given ws:// or wss:// URL.
+func Dial(ctx context.Context, wsURL string, opts DialOpts) (amp.Transport, error) {
+	u, err := url.Parse(wsURL)
+	if err != nil {
+		return nil, amp.ErrCode_InvalidURI.Errorf("bad websocket URL: %v", err)
+	}
+	host := u.Host
+	useTLS := false
+	switch u.Scheme {
+	case "ws":
+	case "wss":
+		useTLS = true
+	default:
+		return nil, amp.ErrCode_InvalidURI.Errorf("unsupported websocket scheme %q", u.Scheme)
+	}
+	if u.Port() == "" {
+		if useTLS {
+			host += ":443"
+		} else {
+			host += ":80"
+		}
+	}
+
+	var dialer net.Dialer
+	netConn, err := dialer.DialContext(ctx, "tcp", host)
+	if err != nil {
+		return nil, amp.ErrCode_NotConnected.Errorf("websocket dial: %v", err)
+	}
+	if useTLS {
+		cfg := opts.TLSConfig
+		if cfg == nil {
+			cfg = &tls.Config{}
+		} else {
+			cfg = cfg.Clone()
+		}
+		if cfg.ServerName == "" {
+			cfg.ServerName = u.Hostname()
+		}
+		tlsConn := tls.Client(netConn, cfg)
+		if err = tlsConn.HandshakeContext(ctx); err != nil {
+			netConn.Close()
+			return nil, amp.ErrCode_NotConnected.Errorf("websocket dial: %v", err)
+		}
+		netConn = tlsConn
+	}
+
+	tr, err := clientHandshake(ctx, netConn, u, opts)
+	if err != nil {
+		netConn.Close()
+		return nil, err
+	}
+	return tr, nil
+}
+
+func clientHandshake(ctx context.Context, netConn net.Conn, u *url.URL, opts DialOpts) (amp.Transport, error) {
+	if deadline, ok := ctx.Deadline(); ok {
+		netConn.SetDeadline(deadline)
+		defer netConn.SetDeadline(time.Time{})
+	}
+
+	var nonce [16]byte
+	if _, err := rand.Read(nonce[:]); err != nil {
+		return nil, err
+	}
+	key := base64.StdEncoding.EncodeToString(nonce[:])
+
+	req := &http.Request{
+		Method:     http.MethodGet,
+		URL:        &url.URL{Path: u.Path, RawQuery: u.RawQuery},
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     http.Header{},
+		Host:       u.Host,
+	}
+	if req.URL.Path == "" {
+		req.URL.Path = "/"
+	}
+	for k, v := range opts.Header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Sec-WebSocket-Key", key)
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Protocol", Subprotocol)
+	if opts.Compression {
+		req.Header.Set("Sec-WebSocket-Extensions", "permessage-deflate; client_no_context_takeover; server_no_context_takeover")
+	}
+	if err := req.Write(netConn); err != nil {
+		return nil, amp.ErrCode_NotConnected.Errorf("websocket handshake: %v", err)
+	}
+
+	br := bufio.NewReader(netConn)
+	resp, err := http.ReadResponse(br, req)
+	if err != nil {
+		return nil, amp.ErrCode_NotConnected.Errorf("websocket handshake: %v", err)
+	}
+	resp.Body.Close()
+
+	switch {
+	case resp.StatusCode != http.StatusSwitchingProtocols:
+		return nil, amp.ErrCode_NotConnected.Errorf("websocket handshake: unexpected status %q", resp.Status)
+	case !headerHasToken(resp.Header, "Upgrade", "websocket") || !headerHasToken(resp.Header, "Connection", "upgrade"):
+		return nil, amp.ErrCode_NotConnected.Error("websocket handshake: missing upgrade headers")
+	case resp.Header.Get("Sec-WebSocket-Accept") != acceptKey(key):
+		return nil, amp.ErrCode_NotConnected.Error("websocket handshake: bad Sec-WebSocket-Accept")
+	}
+
+	deflate := false
+	if ext := resp.Header.Get("Sec-WebSocket-Extensions"); ext != "" {
+		if !opts.Compression || !offersDeflate(resp.Header) {
+			return nil, amp.ErrCode_NotConnected.Errorf("websocket handshake: unexpected extensions %q", ext)
+		}
+		deflate = true
+	}
+
+	return newConn("ws:"+u.Host, netConn, br, true, deflate, maxSize(opts.MaxMessageSize)), nil
+}
+
+// NewService returns a HostService that accepts WebSocket connections at opts.Addr and starts a HostSession for each.
+func NewService(opts Opts) amp.HostService {
+	if opts.Path == "" {
+		opts.Path = DefaultPath
+	}
+	return &service{
+		opts: opts,
+	}
+}
+
+type service struct {
+	task.Context
+	opts     Opts
+	host     amp.Host
+	server   *http.Server
+	listener net.Listener
+}
+
+func (svc *service) StartService(on amp.Host) error {
+	svc.host = on
+
+	listener, err := net.Listen("tcp", svc.opts.Addr)
+	if err != nil {
+		return err
+	}
+	if svc.opts.TLSConfig != nil {
+		listener = tls.NewListener(listener, svc.opts.TLSConfig)
+	}
+	svc.listener = listener
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(svc.opts.Path, svc.serveHTTP)
+	svc.server = &http.Server{
+		Handler: mux,
+	}
+
+	svc.Context, err = on.StartChild(&task.Task{
+		Label: "ws.Service " + listener.Addr().String(),
+		OnClosing: func() {
+			svc.server.Close()
+		},
+	})
+	if err != nil {
+		listener.Close()
+		return err
+	}
+
+	svc.Context.Go("serve", func(ctx task.Context) {
+		if err := svc.server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			ctx.Error(err)
+		}
+	})
+	return nil
+}
+
+// Addr returns the address the service is listening on (e.g. if opts.Addr specified port 0).
+func (svc *service) Addr() net.Addr {
+	return svc.listener.Addr()
+}
+
+func (svc *service) GracefulStop() {
+	svc.server.Shutdown(context.Background())
+}
+
+func (svc *service) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	tr, err := Upgrade(w, r, svc.opts)
+	if err != nil {
+		svc.Info(2, err)
+		return
+	}
+	if _, err = svc.host.StartNewSession(svc, tr); err != nil {
+		svc.Warnf("failed to start session for %s: %v", tr.Label(), err)
+		tr.Close()
+	}
+}
+
+func acceptKey(key string) string {
+	h := sha1.New()
+	h.Write([]byte(key + acceptGUID))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+func maxSize(size int) int {
+	if size <= 0 {
+		return DefaultMaxMessageSize
+	}
+	return size
+}
+
+// headerHasToken returns true if the given comma separated header contains the given token (case insensitive).
+func headerHasToken(header http.Header, name, token string) bool {
+	for _, value := range header.Values(name) {
+		for _, ti := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(ti), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// offersDeflate returns true if the given Sec-WebSocket-Extensions header includes permessage-deflate.
+// Window size parameters are ignored, as windows are never shared across messages in either direction.
+func offersDeflate(header http.Header) bool {
+	for _, value := range header.Values("Sec-WebSocket-Extensions") {
+		for _, ext := range strings.Split(value, ",") {
+			params := strings.Split(ext, ";")
+			if strings.EqualFold(strings.TrimSpace(params[0]), "permessage-deflate") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func originAllowed(r *http.Request, allowed []string) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	if len(allowed) == 0 {
+		return strings.EqualFold(u.Host, r.Host)
+	}
+	for _, pattern := range allowed {
+		switch {
+		case pattern == "*":
+			return true
+		case strings.HasPrefix(pattern, "*."):
+			if strings.HasSuffix(strings.ToLower(u.Hostname()), strings.ToLower(pattern[1:])) {
+				return true
+			}
+		case strings.EqualFold(pattern, u.Host) || strings.EqualFold(pattern, u.Hostname()):
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,140 @@
+package ws
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/amp-3d/amp-sdk-go/amp"
+	"github.com/amp-3d/amp-sdk-go/stdlib/tag"
+)
+
+// echoServer upgrades each request and sends back every tx it receives.
+func echoServer(t *testing.T, opts Opts) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tr, err := Upgrade(w, r, opts)
+		if err != nil {
+			return
+		}
+		defer tr.Close()
+		for {
+			tx, err := tr.RecvTx()
+			if err != nil {
+				return
+			}
+			if err = tr.SendTx(tx); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+	}))
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, compression := range []bool{false, true} {
+		srv := echoServer(t, Opts{Compression: compression})
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+
+		tr, err := Dial(ctx, "ws"+strings.TrimPrefix(srv.URL, "http")+DefaultPath, DialOpts{Compression: compression})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := tr.(*conn).deflate; got != compression {
+			t.Fatalf("expected deflate=%v, got %v", compression, got)
+		}
+
+		reqID := tag.New()
+		for _, size := range []int{1, 200, 70000, 1 << 20} {
+			tx := amp.NewTxMsg(true)
+			tx.SetRequestID(reqID)
+			tx.Seq = uint32(size)
+			tx.MarshalUpsert(reqID, amp.PinnedTabSpec.ID, &amp.Tag{URL: strings.Repeat("x", size)})
+			if err = tr.SendTx(tx); err != nil {
+				t.Fatal(err)
+			}
+
+			echo, err := tr.RecvTx()
+			if err != nil {
+				t.Fatal(err)
+			}
+			val := &amp.Tag{}
+			if err = echo.UnmarshalOpValue(0, val); err != nil {
+				t.Fatal(err)
+			}
+			if len(val.URL) != size || echo.RequestID() != reqID || echo.Seq != uint32(size) {
+				t.Fatalf("echo mismatch for size %d (got %d, seq %d)", size, len(val.URL), echo.Seq)
+			}
+			echo.ReleaseRef()
+		}
+
+		tr.Close()
+		cancel()
+		srv.Close()
+	}
+}
+
+func TestMaxMessageSize(t *testing.T) {
+	srv := echoServer(t, Opts{Compression: true, MaxMessageSize: 4096})
+	defer srv.Close()
+
+	tr, err := Dial(context.Background(), "ws"+strings.TrimPrefix(srv.URL, "http"), DialOpts{Compression: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tr.Close()
+
+	// Compresses to well under the limit, but inflates beyond it
+	tx := amp.NewTxMsg(true)
+	tx.MarshalUpsert(tag.New(), amp.PinnedTabSpec.ID, &amp.Tag{URL: strings.Repeat("x", 8192)})
+	if err = tr.SendTx(tx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = tr.RecvTx(); err != amp.ErrStreamClosed {
+		t.Fatalf("expected host to close the connection, got %v", err)
+	}
+}
+
+func TestOriginCheck(t *testing.T) {
+	srv := echoServer(t, Opts{AllowedOrigins: []string{"app.example.com", "*.amp.dev"}})
+	defer srv.Close()
+	wsURL := "ws" + strings.TrimPrefix(srv.URL, "http")
+
+	for origin, allowed := range map[string]bool{
+		"":                         true,
+		"https://app.example.com":  true,
+		"https://beta.amp.dev":     true,
+		"https://evil.example.com": false,
+		"https://amp.dev.evil.com": false,
+		"null":                     false,
+	} {
+		header := http.Header{}
+		if origin != "" {
+			header.Set("Origin", origin)
+		}
+		tr, err := Dial(context.Background(), wsURL, DialOpts{Header: header})
+		if allowed != (err == nil) {
+			t.Fatalf("origin %q: expected allowed=%v, got %v", origin, allowed, err)
+		}
+		if tr != nil {
+			tr.Close()
+		}
+	}
+
+	// With no AllowedOrigins, only same-host origins are allowed
+	same := echoServer(t, Opts{})
+	defer same.Close()
+	header := http.Header{}
+	header.Set("Origin", same.URL)
+	tr, err := Dial(context.Background(), "ws"+strings.TrimPrefix(same.URL, "http"), DialOpts{Header: header})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr.Close()
+	header.Set("Origin", "https://elsewhere.com")
+	if _, err = Dial(context.Background(), "ws"+strings.TrimPrefix(same.URL, "http"), DialOpts{Header: header}); err == nil {
+		t.Fatal("expected cross-origin request to be rejected")
+	}
+}
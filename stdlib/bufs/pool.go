@@ -0,0 +1,231 @@
+package bufs
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	// MinPoolSize is the capacity of the smallest buffers a Pool holds.
+	MinPoolSize = 1 << minPoolShift
+
+	// MaxPoolSize is the capacity of the largest buffers a Pool holds -- larger buffers are allocated and dropped as usual.
+	MaxPoolSize = 1 << maxPoolShift
+
+	minPoolShift = 8
+	maxPoolShift = 22
+	numClasses   = maxPoolShift - minPoolShift + 1
+
+	maxLeakStacks = 20 // most distinct stacks Pool.Close reports
+)
+
+// DefaultPool is the Pool used by Get and Put.
+var DefaultPool = &Pool{}
+
+// Get returns a buffer from DefaultPool (see Pool.Get).
+func Get(size int) []byte {
+	return DefaultPool.Get(size)
+}
+
+// Put returns a buffer to DefaultPool (see Pool.Put).
+func Put(buf []byte) {
+	DefaultPool.Put(buf)
+}
+
+// Pool is a pool of byte buffers bucketed by size class, where each class holds buffers whose capacity is a power of two
+// from MinPoolSize to MaxPoolSize.  Reusing buffers this way rather than allocating them for each message keeps GC pressure
+// flat at high message rates.  The zero value is ready to use.
+//
+// In debug mode (see SetDebug), a Pool tracks each buffer it hands out along with the stack that got it, and Close reports
+// each buffer not put back (a leak) and each buffer put back more than once.
+type Pool struct {
+	classes [numClasses]sync.Pool // of *[]byte
+	gets    atomic.Int64
+	puts    atomic.Int64
+	allocs  atomic.Int64
+	debug   atomic.Bool
+
+	mu          sync.Mutex
+	outstanding map[*byte][]uintptr // stack of the Get of each buffer not yet put back
+	returned    map[*byte]struct{}  // buffers put back and not got since
+	extraPuts   map[string]int      // count of each stack putting back a buffer already put back
+}
+
+// PoolStats are the running counts of a Pool.
+type PoolStats struct {
+	Gets        int64 // calls to Get
+	Puts        int64 // calls to Put
+	Allocs      int64 // Gets that allocated a new buffer
+	Outstanding int   // buffers got but not put back (in debug mode only)
+}
+
+// Get returns a buffer of the given length, whose capacity is that of its size class, and whose contents are arbitrary.
+// A buffer larger than MaxPoolSize is allocated as usual.
+func (p *Pool) Get(size int) []byte {
+	p.gets.Add(1)
+
+	var buf []byte
+	if class, ok := classFor(size); !ok {
+		p.allocs.Add(1)
+		buf = make([]byte, size)
+	} else if pooled, _ := p.classes[class].Get().(*[]byte); pooled != nil {
+		buf = (*pooled)[:size]
+	} else {
+		p.allocs.Add(1)
+		buf = make([]byte, size, 1<<(class+minPoolShift))
+	}
+
+	if p.debug.Load() && cap(buf) > 0 {
+		stack := make([]uintptr, 32)
+		stack = stack[:runtime.Callers(2, stack)]
+		key := &buf[:cap(buf)][0]
+		p.mu.Lock()
+		if p.outstanding == nil {
+			p.outstanding = make(map[*byte][]uintptr)
+		}
+		p.outstanding[key] = stack
+		delete(p.returned, key)
+		p.mu.Unlock()
+	}
+	return buf
+}
+
+// Put returns the given buffer to this pool, after which the caller must no longer use it (or any slice of it).
+// A buffer need not have come from Get, and one too small or too large to pool is dropped.
+func (p *Pool) Put(buf []byte) {
+	if cap(buf) == 0 {
+		return
+	}
+	p.puts.Add(1)
+
+	if p.debug.Load() {
+		key := &buf[:cap(buf)][0]
+		p.mu.Lock()
+		_, again := p.returned[key]
+		if again {
+			stack := make([]uintptr, 32)
+			stack = stack[:runtime.Callers(2, stack)]
+			if p.extraPuts == nil {
+				p.extraPuts = make(map[string]int)
+			}
+			p.extraPuts[formatStack(stack)]++
+		} else {
+			delete(p.outstanding, key)
+			if p.returned == nil {
+				p.returned = make(map[*byte]struct{})
+			}
+			p.returned[key] = struct{}{}
+		}
+		p.mu.Unlock()
+		if again {
+			return // pooling it again would hand it out twice
+		}
+	}
+
+	// A buffer goes in the largest class it can serve
+	shift := bits.Len(uint(cap(buf))) - 1
+	if shift < minPoolShift || shift > maxPoolShift {
+		return
+	}
+	buf = buf[:0]
+	p.classes[shift-minPoolShift].Put(&buf)
+}
+
+// SetDebug turns debug mode on or off, where buffers got while debug mode is on are tracked until put back or Close.
+func (p *Pool) SetDebug(on bool) {
+	p.debug.Store(on)
+}
+
+// Stats returns the running counts of this pool.
+func (p *Pool) Stats() PoolStats {
+	p.mu.Lock()
+	outstanding := len(p.outstanding)
+	p.mu.Unlock()
+	return PoolStats{
+		Gets:        p.gets.Load(),
+		Puts:        p.puts.Load(),
+		Allocs:      p.allocs.Load(),
+		Outstanding: outstanding,
+	}
+}
+
+// Close stops tracking the buffers got in debug mode, returning an error describing each one not put back (along with the
+// stack that got it) and each buffer put back more than once, or nil if there are none.  The pool remains usable.
+func (p *Pool) Close() error {
+	p.mu.Lock()
+	outstanding, extraPuts := p.outstanding, p.extraPuts
+	p.outstanding, p.returned, p.extraPuts = nil, nil, nil
+	p.mu.Unlock()
+
+	if len(outstanding) == 0 && len(extraPuts) == 0 {
+		return nil
+	}
+
+	leaks := make(map[string]int)
+	for _, stack := range outstanding {
+		leaks[formatStack(stack)]++
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "bufs: %d buffers leaked, %d put back more than once", len(outstanding), sumCounts(extraPuts))
+	writeStacks(&b, "leaked buffer got", leaks)
+	writeStacks(&b, "buffer put back again", extraPuts)
+	return errors.New(b.String())
+}
+
+// classFor returns the index of the smallest class holding buffers of the given size, or false if none does.
+func classFor(size int) (int, bool) {
+	if size > MaxPoolSize {
+		return 0, false
+	}
+	shift := bits.Len(uint(max(size, 1) - 1))
+	return max(shift, minPoolShift) - minPoolShift, true
+}
+
+func formatStack(stack []uintptr) string {
+	var b strings.Builder
+	frames := runtime.CallersFrames(stack)
+	for {
+		frame, more := frames.Next()
+		fmt.Fprintf(&b, "\t%s\n\t\t%s:%d\n", frame.Function, frame.File, frame.Line)
+		if !more {
+			break
+		}
+	}
+	return b.String()
+}
+
+// writeStacks writes the given stacks, most frequent first, to b.
+func writeStacks(b *strings.Builder, label string, counts map[string]int) {
+	stacks := make([]string, 0, len(counts))
+	for stack := range counts {
+		stacks = append(stacks, stack)
+	}
+	sort.Slice(stacks, func(i, j int) bool {
+		if counts[stacks[i]] != counts[stacks[j]] {
+			return counts[stacks[i]] > counts[stacks[j]]
+		}
+		return stacks[i] < stacks[j]
+	})
+	for i, stack := range stacks {
+		if i == maxLeakStacks {
+			fmt.Fprintf(b, "\n... and %d more stacks", len(stacks)-i)
+			break
+		}
+		fmt.Fprintf(b, "\n%d %s at:\n%s", counts[stack], label, stack)
+	}
+}
+
+func sumCounts(counts map[string]int) int {
+	n := 0
+	for _, count := range counts {
+		n += count
+	}
+	return n
+}
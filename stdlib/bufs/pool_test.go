@@ -0,0 +1,56 @@
+package bufs
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPool(t *testing.T) {
+	pool := &Pool{}
+
+	// Buffers have the capacity of their size class
+	for size, want := range map[int]int{0: MinPoolSize, 1: MinPoolSize, MinPoolSize: MinPoolSize, 257: 512, 5000: 8192, MaxPoolSize: MaxPoolSize} {
+		buf := pool.Get(size)
+		require.Equal(t, size, len(buf))
+		require.Equal(t, want, cap(buf), "size %d", size)
+		pool.Put(buf)
+	}
+	huge := pool.Get(MaxPoolSize + 1)
+	require.Equal(t, MaxPoolSize+1, cap(huge))
+	pool.Put(huge)
+
+	// A buffer from elsewhere serves the largest class it can
+	pool.Put(make([]byte, 10, 3000))
+	if buf := pool.Get(2000); cap(buf) != 3000 && cap(buf) != 2048 {
+		t.Fatalf("unexpected capacity %d", cap(buf))
+	}
+	stats := pool.Stats()
+	require.EqualValues(t, 8, stats.Gets)
+	require.EqualValues(t, 8, stats.Puts)
+	require.LessOrEqual(t, stats.Allocs, stats.Gets)
+
+	// In debug mode, leaks and repeated puts are reported with their stacks
+	pool.SetDebug(true)
+	leaked := pool.Get(100)
+	putTwice := pool.Get(100)
+	pool.Put(putTwice)
+	pool.Put(putTwice)
+	pool.Put(make([]byte, 1000)) // never got from the pool, which is fine
+	require.Equal(t, 1, pool.Stats().Outstanding)
+	err := pool.Close()
+	require.Error(t, err)
+	require.True(t, strings.HasPrefix(err.Error(), "bufs: 1 buffers leaked, 1 put back more than once"), err.Error())
+	require.Contains(t, err.Error(), "bufs.TestPool")
+	require.Zero(t, pool.Stats().Outstanding)
+	require.NoError(t, pool.Close())
+
+	// A buffer put back twice is only pooled once
+	a, b := pool.Get(100), pool.Get(100)
+	require.NotSame(t, &a[:1][0], &b[:1][0])
+	pool.Put(a)
+	pool.Put(b)
+	pool.Put(leaked)
+	require.NoError(t, pool.Close())
+}
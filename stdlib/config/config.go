@@ -0,0 +1,503 @@
+// Package config is a host's configuration: typed sections registered per app or module, loaded from a JSON file with environment
+// overrides, validated, and watched so that running tasks are notified as values change and operators can tune a host (e.g. its
+// session limits) without restarting it.
+//
+// Each section is a JSON-compatible struct registered under a name with its defaults.  The config file is a JSON object whose keys
+// are section names, and any field of a section may be overridden by an environment variable named
+// {EnvPrefix}{SECTION}__{FIELD}, where nested struct fields are separated by "__" and names are matched ignoring case and underscores:
+//
+//	// host.json: {"amp.session": {"MaxPins": 64, "IdleTimeout": "90s"}}
+//	// env:       AMP_AMP_SESSION__MAX_PINS=128
+//
+//	cfg := config.New(config.Opts{Path: "host.json"})
+//	limits, err := config.Register(cfg, "amp.session", SessionConfig{MaxPins: 32})
+//	limits.Follow(ctx, func(cur SessionConfig) { ... })  // called now and after each change until ctx closes
+//	cfg.WatchFile(ctx, 0)                                  // reloads as host.json changes
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/task"
+)
+
+const (
+	DefaultEnvPrefix     = "AMP_"
+	DefaultWatchInterval = 2 * time.Second
+)
+
+// Validator is implemented by a section (or a pointer to one) that checks its values once loaded.
+// A section that fails validation is not applied, so running tasks keep the last valid values.
+type Validator interface {
+	Validate() error
+}
+
+// Opts configures a Config.
+type Opts struct {
+	Path      string          // JSON file of sections keyed by name; if empty or missing, sections are their defaults plus env overrides
+	EnvPrefix string          // prefix of env overrides (default DefaultEnvPrefix)
+	Environ   func() []string // returns env vars as "KEY=value" (default os.Environ)
+}
+
+// Config is a set of named, typed sections -- concurrency safe.
+type Config struct {
+	opts     Opts
+	mu       sync.Mutex // serializes loads and registration
+	raw      map[string]json.RawMessage
+	sections map[string]section
+	stamp    fileStamp // of the file last loaded
+}
+
+// section is the untyped form of a Section.
+type section interface {
+	stage(raw json.RawMessage, env []envVar) (apply func() (notify func()), err error)
+	current() any
+}
+
+// New returns a Config with no sections registered and nothing yet loaded (see Load).
+func New(opts Opts) *Config {
+	if opts.EnvPrefix == "" {
+		opts.EnvPrefix = DefaultEnvPrefix
+	}
+	if opts.Environ == nil {
+		opts.Environ = os.Environ
+	}
+	return &Config{
+		opts:     opts,
+		raw:      make(map[string]json.RawMessage),
+		sections: make(map[string]section),
+	}
+}
+
+// Section is a registered config section of type T -- concurrency safe.
+type Section[T any] struct {
+	name     string
+	defaults []byte // T as JSON, decoded afresh for each load so values never share memory with the defaults
+	cur      atomic.Pointer[T]
+
+	mu       sync.Mutex
+	watchers map[int]func(prev, cur T)
+	nextID   int
+}
+
+// Register registers a section with the given name and defaults, as loaded from the file last loaded (if any) and the environment.
+// T must be a struct that round-trips through encoding/json.
+func Register[T any](cfg *Config, name string, defaults T) (*Section[T], error) {
+	if reflect.TypeOf(defaults).Kind() != reflect.Struct {
+		return nil, fmt.Errorf("config: section %q is not a struct", name)
+	}
+	defaultsJSON, err := json.Marshal(defaults)
+	if err != nil {
+		return nil, fmt.Errorf("config: section %q: %w", name, err)
+	}
+	s := &Section[T]{
+		name:     name,
+		defaults: defaultsJSON,
+		watchers: make(map[int]func(prev, cur T)),
+	}
+
+	cfg.mu.Lock()
+	defer cfg.mu.Unlock()
+	if _, exists := cfg.sections[name]; exists {
+		return nil, fmt.Errorf("config: section %q already registered", name)
+	}
+	apply, err := s.stage(cfg.raw[name], cfg.envFor(name))
+	if err != nil {
+		return nil, err
+	}
+	apply()
+	cfg.sections[name] = s
+	return s, nil
+}
+
+// Name returns the name this section is registered under.
+func (s *Section[T]) Name() string {
+	return s.name
+}
+
+// Get returns the current values of this section.  The returned value must be treated as read only.
+func (s *Section[T]) Get() T {
+	return *s.cur.Load()
+}
+
+// Watch calls fn after each load that changes this section's values, until the returned func is called.
+// Calls are made from the goroutine that called Config.Load (one at a time), so fn should not block for long or call Load.
+func (s *Section[T]) Watch(fn func(prev, cur T)) (cancel func()) {
+	s.mu.Lock()
+	id := s.nextID
+	s.nextID++
+	s.watchers[id] = fn
+	s.mu.Unlock()
+	return func() {
+		s.mu.Lock()
+		delete(s.watchers, id)
+		s.mu.Unlock()
+	}
+}
+
+// Follow calls fn with the current values of this section and again after each change (see Watch) until ctx closes.
+func (s *Section[T]) Follow(ctx task.Context, fn func(cur T)) {
+	cancel := s.Watch(func(prev, cur T) {
+		fn(cur)
+	})
+	fn(s.Get())
+	go func() {
+		<-ctx.Done()
+		cancel()
+	}()
+}
+
+func (s *Section[T]) current() any {
+	return s.Get()
+}
+
+func (s *Section[T]) stage(raw json.RawMessage, env []envVar) (func() func(), error) {
+	next := new(T)
+	if err := json.Unmarshal(s.defaults, next); err != nil {
+		return nil, fmt.Errorf("config: section %q: %w", s.name, err)
+	}
+	if len(raw) > 0 {
+		dec := json.NewDecoder(bytes.NewReader(raw))
+		dec.DisallowUnknownFields() // a misspelled field shouldn't silently leave a default in place
+		if err := dec.Decode(next); err != nil {
+			return nil, fmt.Errorf("config: section %q: %w", s.name, err)
+		}
+	}
+	for _, ev := range env {
+		if err := setField(reflect.ValueOf(next).Elem(), ev.path, ev.value); err != nil {
+			return nil, fmt.Errorf("config: section %q: env %s: %w", s.name, ev.key, err)
+		}
+	}
+	if err := validate(next); err != nil {
+		return nil, fmt.Errorf("config: section %q: %w", s.name, err)
+	}
+
+	return func() func() {
+		prev := s.cur.Swap(next)
+		if prev == nil || reflect.DeepEqual(*prev, *next) {
+			return nil
+		}
+		return func() {
+			s.mu.Lock()
+			watchers := make([]func(prev, cur T), 0, len(s.watchers))
+			for _, fn := range s.watchers {
+				watchers = append(watchers, fn)
+			}
+			s.mu.Unlock()
+			for _, fn := range watchers {
+				fn(*prev, *next)
+			}
+		}
+	}, nil
+}
+
+func validate(v any) error {
+	if validator, ok := v.(Validator); ok {
+		return validator.Validate()
+	}
+	if validator, ok := reflect.ValueOf(v).Elem().Interface().(Validator); ok {
+		return validator.Validate()
+	}
+	return nil
+}
+
+// Load reads the config file (if any) and the environment, and applies them to all registered sections.
+//
+// Loading is all or nothing: if the file is malformed or any section fails to decode or validate, the returned error describes
+// every failure and no section changes.  Otherwise, the watchers of each section whose values changed are then called.
+func (cfg *Config) Load() error {
+	cfg.mu.Lock()
+	defer cfg.mu.Unlock()
+
+	raw, stamp, err := cfg.readFile()
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(cfg.sections))
+	for name := range cfg.sections {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errs []error
+	applies := make([]func() func(), 0, len(names))
+	for _, name := range names {
+		apply, err := cfg.sections[name].stage(raw[name], cfg.envFor(name))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		applies = append(applies, apply)
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	cfg.raw = raw
+	cfg.stamp = stamp
+	var notifies []func()
+	for _, apply := range applies {
+		if notify := apply(); notify != nil {
+			notifies = append(notifies, notify)
+		}
+	}
+	for _, notify := range notifies {
+		notify()
+	}
+	return nil
+}
+
+// Values returns the current values of all registered sections keyed by name (e.g. for an operator to inspect as JSON).
+func (cfg *Config) Values() map[string]any {
+	cfg.mu.Lock()
+	defer cfg.mu.Unlock()
+
+	values := make(map[string]any, len(cfg.sections))
+	for name, s := range cfg.sections {
+		values[name] = s.current()
+	}
+	return values
+}
+
+// WatchFile starts a child of ctx that reloads this Config each time its file changes, checking every interval
+// (or DefaultWatchInterval).  A reload that fails is logged to the returned Context and the last valid values remain in effect.
+func (cfg *Config) WatchFile(ctx task.Context, interval time.Duration) (task.Context, error) {
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+	return ctx.StartChild(&task.Task{
+		Label: "config: " + cfg.opts.Path,
+		OnRun: func(watcher task.Context) {
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			var failed fileStamp // of the last file that failed to load, so it is only reported once
+			for {
+				select {
+				case <-watcher.Closing():
+					return
+				case <-ticker.C:
+				}
+				stamp := statFile(cfg.opts.Path)
+				cfg.mu.Lock()
+				changed := stamp != cfg.stamp && stamp != failed
+				cfg.mu.Unlock()
+				if !changed {
+					continue
+				}
+				if err := cfg.Load(); err != nil {
+					watcher.Warnf("reload failed, keeping the last valid config: %v", err)
+					failed = stamp
+				} else {
+					watcher.Infof(1, "reloaded %s", cfg.opts.Path)
+				}
+			}
+		},
+	})
+}
+
+// fileStamp identifies a version of a file.
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+func statFile(path string) fileStamp {
+	if path == "" {
+		return fileStamp{}
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileStamp{}
+	}
+	return fileStamp{info.ModTime(), info.Size()}
+}
+
+func (cfg *Config) readFile() (map[string]json.RawMessage, fileStamp, error) {
+	raw := make(map[string]json.RawMessage)
+	if cfg.opts.Path == "" {
+		return raw, fileStamp{}, nil
+	}
+	stamp := statFile(cfg.opts.Path)
+	data, err := os.ReadFile(cfg.opts.Path)
+	if os.IsNotExist(err) {
+		return raw, fileStamp{}, nil
+	}
+	if err != nil {
+		return nil, stamp, fmt.Errorf("config: %w", err)
+	}
+	if err = json.Unmarshal(data, &raw); err != nil {
+		return nil, stamp, fmt.Errorf("config: %s: %w", cfg.opts.Path, err)
+	}
+	return raw, stamp, nil
+}
+
+// envVar is an env override of a section field.
+type envVar struct {
+	key   string
+	path  []string // normalized field names
+	value string
+}
+
+// envFor returns the env overrides of the given section, in key order.
+func (cfg *Config) envFor(name string) []envVar {
+	prefix := cfg.opts.EnvPrefix + envName(name) + "__"
+	var env []envVar
+	for _, kv := range cfg.opts.Environ() {
+		key, value, _ := strings.Cut(kv, "=")
+		if !strings.HasPrefix(key, prefix) || len(key) == len(prefix) {
+			continue
+		}
+		path := strings.Split(key[len(prefix):], "__")
+		for i := range path {
+			path[i] = normalize(path[i])
+		}
+		env = append(env, envVar{key, path, value})
+	}
+	sort.Slice(env, func(i, j int) bool { return env[i].key < env[j].key })
+	return env
+}
+
+// envName returns the given section name in env var form, e.g. "amp.session" => "AMP_SESSION".
+func envName(name string) string {
+	var b strings.Builder
+	for _, c := range strings.ToUpper(name) {
+		if (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') {
+			b.WriteRune(c)
+		} else {
+			b.WriteByte('_')
+		}
+	}
+	return b.String()
+}
+
+// normalize returns a field name for case and underscore insensitive matching.
+func normalize(name string) string {
+	return strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(name))
+}
+
+var durationTypes = map[reflect.Type]bool{
+	reflect.TypeOf(time.Duration(0)): true,
+	reflect.TypeOf(Duration(0)):      true,
+}
+
+// setField sets the field of v at the given path from its env form: strings as is, numbers, bools, and time.Durations
+// (including Duration, e.g. "90s") as parsed, []string as comma-separated, and all else as JSON.
+func setField(v reflect.Value, path []string, value string) error {
+	for _, name := range path {
+		if v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		if v.Kind() != reflect.Struct {
+			return fmt.Errorf("%s is not a struct field", name)
+		}
+		field, found := reflect.Value{}, false
+		for i := 0; i < v.NumField() && !found; i++ {
+			sf := v.Type().Field(i)
+			if !sf.IsExported() {
+				continue
+			}
+			jsonName, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+			if normalize(sf.Name) == name || (jsonName != "" && jsonName != "-" && normalize(jsonName) == name) {
+				field, found = v.Field(i), true
+			}
+		}
+		if !found {
+			return fmt.Errorf("no field %q", name)
+		}
+		v = field
+	}
+
+	if durationTypes[v.Type()] {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 0, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(value, 0, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	default:
+		if v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.String && !strings.HasPrefix(value, "[") {
+			items := strings.Split(value, ",")
+			for i := range items {
+				items[i] = strings.TrimSpace(items[i])
+			}
+			v.Set(reflect.ValueOf(items).Convert(v.Type()))
+			return nil
+		}
+		return json.Unmarshal([]byte(value), v.Addr().Interface())
+	}
+	return nil
+}
+
+// Duration is a time.Duration that is encoded in JSON as a string (e.g. "90s"), for use in sections.
+// It also decodes from a JSON number of nanoseconds.
+type Duration time.Duration
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var str string
+	if err := json.Unmarshal(data, &str); err != nil {
+		var ns int64
+		if err = json.Unmarshal(data, &ns); err != nil {
+			return fmt.Errorf("bad duration %s", data)
+		}
+		*d = Duration(ns)
+		return nil
+	}
+	parsed, err := time.ParseDuration(str)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// Duration returns d as a time.Duration.
+func (d Duration) Duration() time.Duration {
+	return time.Duration(d)
+}
@@ -0,0 +1,137 @@
+package config_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/config"
+	"github.com/amp-3d/amp-sdk-go/stdlib/task"
+)
+
+type sessionConfig struct {
+	MaxPins     int             `json:"max_pins"`
+	IdleTimeout config.Duration `json:"idle_timeout"`
+	Retry       time.Duration
+	Origins     []string
+	Store       struct {
+		Path string
+	}
+}
+
+func (cfg sessionConfig) Validate() error {
+	if cfg.MaxPins <= 0 {
+		return errors.New("max_pins must be > 0")
+	}
+	return nil
+}
+
+type logConfig struct {
+	Level string
+}
+
+func TestConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "host.json")
+	write := func(contents string) {
+		require.NoError(t, os.WriteFile(path, []byte(contents), 0600))
+	}
+	env := []string{"HOME=/root", "AMP_AMP_SESSION__STORE__PATH=/var/amp", "AMP_AMP_SESSION__RETRY=3s"}
+	cfg := config.New(config.Opts{
+		Path:    path,
+		Environ: func() []string { return env },
+	})
+
+	write(`{"amp.session": {"max_pins": 64, "idle_timeout": "90s"}, "other": {"x": 1}}`)
+	require.NoError(t, cfg.Load())
+
+	sess, err := config.Register(cfg, "amp.session", sessionConfig{MaxPins: 32, Origins: []string{"a"}})
+	require.NoError(t, err)
+	cur := sess.Get()
+	require.Equal(t, 64, cur.MaxPins)
+	require.Equal(t, 90*time.Second, cur.IdleTimeout.Duration())
+	require.Equal(t, 3*time.Second, cur.Retry)
+	require.Equal(t, []string{"a"}, cur.Origins)
+	require.Equal(t, "/var/amp", cur.Store.Path)
+
+	logs, err := config.Register(cfg, "log", logConfig{Level: "info"})
+	require.NoError(t, err)
+	require.Equal(t, "info", logs.Get().Level)
+	_, err = config.Register(cfg, "log", logConfig{})
+	require.Error(t, err)
+
+	var (
+		mu      sync.Mutex
+		changes []sessionConfig
+	)
+	root, err := task.Start(&task.Task{Label: "host"})
+	require.NoError(t, err)
+	defer root.Close()
+	sess.Follow(root, func(cur sessionConfig) {
+		mu.Lock()
+		changes = append(changes, cur)
+		mu.Unlock()
+	})
+	logChanges := 0
+	logs.Watch(func(prev, cur logConfig) { logChanges++ })
+
+	// Env overrides apply over the file; only changed sections notify
+	env = append(env, "AMP_AMP_SESSION__MAX_PINS=128", "AMP_AMP_SESSION__ORIGINS=x, y")
+	require.NoError(t, cfg.Load())
+	mu.Lock()
+	require.Len(t, changes, 2)
+	require.Equal(t, 128, changes[1].MaxPins)
+	require.Equal(t, []string{"x", "y"}, changes[1].Origins)
+	mu.Unlock()
+	require.Zero(t, logChanges)
+
+	// A failed load changes nothing and reports every failure
+	env = env[:3]
+	write(`{"amp.session": {"max_pins": 0}, "log": {"Levle": "debug"}}`)
+	err = cfg.Load()
+	require.Error(t, err)
+	require.True(t, strings.Contains(err.Error(), "max_pins must be > 0") && strings.Contains(err.Error(), "Levle"), err.Error())
+	require.Equal(t, 128, sess.Get().MaxPins)
+	write(`{"amp.session": {"max_pins": 16}}`)
+	require.NoError(t, cfg.Load())
+	require.Equal(t, 16, sess.Get().MaxPins)
+	require.Equal(t, time.Duration(0), sess.Get().IdleTimeout.Duration(), "fields absent from the file revert to defaults")
+
+	values := cfg.Values()
+	require.Len(t, values, 2)
+	require.Equal(t, logConfig{Level: "info"}, values["log"])
+
+	env = append(env, "AMP_LOG__NOPE=1")
+	require.Error(t, cfg.Load())
+}
+
+func TestWatchFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "host.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"log": {"Level": "info"}}`), 0600))
+	cfg := config.New(config.Opts{Path: path, Environ: func() []string { return nil }})
+	require.NoError(t, cfg.Load())
+	logs, err := config.Register(cfg, "log", logConfig{})
+	require.NoError(t, err)
+	require.Equal(t, "info", logs.Get().Level)
+
+	root, err := task.Start(&task.Task{Label: "host"})
+	require.NoError(t, err)
+	defer root.Close()
+	changed := make(chan string, 1)
+	logs.Watch(func(prev, cur logConfig) { changed <- cur.Level })
+	_, err = cfg.WatchFile(root, 5*time.Millisecond)
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"log": {"Level": "debug"}}`), 0600))
+	select {
+	case level := <-changed:
+		require.Equal(t, "debug", level)
+	case <-time.After(5 * time.Second):
+		t.Fatal("config file change not noticed")
+	}
+}
@@ -0,0 +1,289 @@
+// Package diag gathers a process's runtime diagnostics into a single zip bundle that a user can attach to a bug report:
+//
+//	info.json          Go version, platform, goroutine count, and runtime.MemStats
+//	pprof/*            goroutine stacks, heap, allocs, block, mutex, and threadcreate profiles, plus an optional CPU profile
+//	tasks.json/.txt    the task tree (see task.Snapshot and task.PrintContextTree)
+//	stats/{name}.json  each of Opts.Stats (e.g. mailbox, pin, and flow control stats)
+//	metrics.txt        the metrics gathered in the Prometheus text format
+//	log.txt            the most recent log lines (see log.TailSink)
+//	errors.txt         anything that could not be gathered
+//
+// A bundle is streamed to a requester via Handler, written to disk via WriteFile, or written to disk on a signal via Notify:
+//
+//	tail := log.NewTailSink(0)
+//	log.DefaultHandler().AddSink(tail)
+//	opts := diag.Opts{
+//		Root:    host,
+//		Logs:    tail,
+//		Metrics: hostMetrics.Registry,
+//		Stats: map[string]func() any{
+//			"pins": func() any { return telemetry.Pins() },
+//		},
+//	}
+//	mux.Handle("/debug/bundle", diag.Handler(opts))
+//	stop := diag.Notify(opts, nil, syscall.SIGUSR1)
+//
+// Like task.DebugHandler, a bundle exposes a process's internals and is intended for operators only.
+package diag
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/log"
+	"github.com/amp-3d/amp-sdk-go/stdlib/metrics"
+	"github.com/amp-3d/amp-sdk-go/stdlib/task"
+)
+
+// MaxCPUProfile caps the CPU profile duration a requester of Handler may ask for.
+const MaxCPUProfile = time.Minute
+
+// Opts configures what a bundle contains -- each field is optional.
+type Opts struct {
+	Root       task.Context          // the task tree to snapshot
+	Logs       *log.TailSink         // recent log lines
+	Metrics    prometheus.Gatherer   // metrics to export (e.g. a prometheus.Registry)
+	Stats      map[string]func() any // named stats snapshots, each written as JSON (e.g. utils.MailboxOf.Stats)
+	CPUProfile time.Duration         // if > 0, a CPU profile of this duration is included (delaying the bundle by as long)
+	Dir        string                // where WriteFile and Notify write bundles (default os.TempDir())
+}
+
+// profiles are the runtime/pprof profiles included in each bundle, with the debug level each is written with.
+var profiles = []struct {
+	name  string
+	debug int
+}{
+	{"goroutine", 2}, // human-readable stacks of all goroutines
+	{"heap", 0},
+	{"allocs", 0},
+	{"block", 0},
+	{"mutex", 0},
+	{"threadcreate", 0},
+}
+
+// Info describes the process a bundle was gathered from.
+type Info struct {
+	Time         time.Time        `json:"time"`
+	GoVersion    string           `json:"go_version"`
+	GOOS         string           `json:"goos"`
+	GOARCH       string           `json:"goarch"`
+	NumCPU       int              `json:"num_cpu"`
+	NumGoroutine int              `json:"num_goroutine"`
+	Args         []string         `json:"args"`
+	MemStats     runtime.MemStats `json:"mem_stats"`
+}
+
+// WriteBundle writes a zip bundle of the diagnostics configured by opts to w.  A diagnostic that cannot be gathered (e.g. a
+// CPU profile while another is running) is noted in the bundle's errors.txt rather than failing the bundle, so the returned
+// error is only that of writing to w.
+func WriteBundle(w io.Writer, opts Opts) error {
+	b := &bundle{
+		zip: zip.NewWriter(w),
+		now: time.Now(),
+	}
+
+	if opts.CPUProfile > 0 {
+		b.add("pprof/cpu.pb.gz", func(w io.Writer) error {
+			if err := pprof.StartCPUProfile(w); err != nil {
+				return err
+			}
+			time.Sleep(opts.CPUProfile)
+			pprof.StopCPUProfile()
+			return nil
+		})
+	}
+	for _, p := range profiles {
+		name := "pprof/" + p.name + ".pb.gz"
+		if p.debug > 0 {
+			name = "pprof/" + p.name + ".txt"
+		}
+		b.add(name, func(w io.Writer) error {
+			prof := pprof.Lookup(p.name)
+			if prof == nil {
+				return fmt.Errorf("no %s profile", p.name)
+			}
+			return prof.WriteTo(w, p.debug)
+		})
+	}
+
+	b.addJSON("info.json", func() any {
+		info := &Info{
+			Time:         b.now,
+			GoVersion:    runtime.Version(),
+			GOOS:         runtime.GOOS,
+			GOARCH:       runtime.GOARCH,
+			NumCPU:       runtime.NumCPU(),
+			NumGoroutine: runtime.NumGoroutine(),
+			Args:         os.Args,
+		}
+		runtime.ReadMemStats(&info.MemStats)
+		return info
+	})
+
+	if opts.Root != nil {
+		b.addJSON("tasks.json", func() any { return task.SnapshotOf(opts.Root) })
+		b.add("tasks.txt", func(w io.Writer) error {
+			task.PrintContextTree(opts.Root, w, 0)
+			return nil
+		})
+	}
+
+	names := make([]string, 0, len(opts.Stats))
+	for name := range opts.Stats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		b.addJSON("stats/"+name+".json", opts.Stats[name])
+	}
+
+	if opts.Metrics != nil {
+		b.add("metrics.txt", func(w io.Writer) error {
+			return metrics.WriteText(w, opts.Metrics)
+		})
+	}
+	if opts.Logs != nil {
+		b.add("log.txt", func(w io.Writer) error {
+			_, err := opts.Logs.WriteTo(w)
+			return err
+		})
+	}
+
+	if len(b.errs) > 0 {
+		b.add("errors.txt", func(w io.Writer) error {
+			_, err := io.WriteString(w, strings.Join(b.errs, "\n")+"\n")
+			return err
+		})
+	}
+	if b.err != nil {
+		return b.err
+	}
+	return b.zip.Close()
+}
+
+// bundle writes the entries of a zip bundle, recording the diagnostics that fail to be gathered.
+type bundle struct {
+	zip  *zip.Writer
+	now  time.Time
+	errs []string // diagnostics that failed
+	err  error    // first error writing the zip
+}
+
+func (b *bundle) add(name string, write func(w io.Writer) error) {
+	if b.err != nil {
+		return
+	}
+	w, err := b.zip.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: b.now,
+	})
+	if err != nil {
+		b.err = err
+		return
+	}
+	if err = write(w); err != nil {
+		b.errs = append(b.errs, name+": "+err.Error())
+	}
+}
+
+func (b *bundle) addJSON(name string, snapshot func() any) {
+	b.add(name, func(w io.Writer) error {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(snapshot())
+	})
+}
+
+// FileName returns the name of a bundle gathered at the given time, e.g. "diag-20240501-120000.zip".
+func FileName(t time.Time) string {
+	return "diag-" + t.Format("20060102-150405") + ".zip"
+}
+
+// WriteFile writes a bundle (see WriteBundle) to a new file in opts.Dir and returns its path.
+func WriteFile(opts Opts) (string, error) {
+	dir := opts.Dir
+	if dir == "" {
+		dir = os.TempDir()
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, FileName(time.Now()))
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return "", err
+	}
+	err = WriteBundle(file, opts)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return "", err
+	}
+	return path, nil
+}
+
+// Handler returns an http.Handler that streams a bundle (see WriteBundle) as a zip attachment.  The optional query param is:
+//
+//	cpu=<seconds>      include a CPU profile of this duration (at most MaxCPUProfile), overriding opts.CPUProfile
+//
+// Like task.DebugHandler, this is intended for operators and should not be exposed publicly.
+func Handler(opts Opts) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reqOpts := opts
+		if cpu := r.URL.Query().Get("cpu"); cpu != "" {
+			secs, err := strconv.ParseFloat(cpu, 64)
+			if err != nil || secs < 0 {
+				http.Error(w, "bad cpu duration", http.StatusBadRequest)
+				return
+			}
+			reqOpts.CPUProfile = min(time.Duration(secs*float64(time.Second)), MaxCPUProfile)
+		}
+
+		w.Header().Set("Content-Type", "application/zip")
+		w.Header().Set("Content-Disposition", `attachment; filename="`+FileName(time.Now())+`"`)
+		WriteBundle(w, reqOpts)
+	})
+}
+
+// Notify writes a bundle to disk (see WriteFile) each time the process receives one of the given signals (e.g. syscall.SIGUSR1)
+// until the returned func is called.  If onWritten is set, it is called with the path of each bundle written or the error
+// writing it.
+func Notify(opts Opts, onWritten func(path string, err error), sigs ...os.Signal) (stop func()) {
+	ch := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(ch, sigs...)
+	go func() {
+		for {
+			select {
+			case <-ch:
+				path, err := WriteFile(opts)
+				if onWritten != nil {
+					onWritten(path, err)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		signal.Stop(ch)
+		close(done)
+	}
+}
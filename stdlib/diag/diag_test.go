@@ -0,0 +1,120 @@
+package diag_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/diag"
+	"github.com/amp-3d/amp-sdk-go/stdlib/log"
+	"github.com/amp-3d/amp-sdk-go/stdlib/task"
+	"github.com/amp-3d/amp-sdk-go/stdlib/utils"
+)
+
+func readBundle(t *testing.T, data []byte) map[string]string {
+	t.Helper()
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	files := make(map[string]string)
+	for _, f := range zr.File {
+		r, err := f.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(r)
+		require.NoError(t, err)
+		r.Close()
+		files[f.Name] = string(content)
+	}
+	return files
+}
+
+func TestBundle(t *testing.T) {
+	root, err := task.Start(&task.Task{Label: "host"})
+	require.NoError(t, err)
+	defer root.Close()
+
+	tail := log.NewTailSink(10)
+	slog.New(log.NewHandler(nil, tail)).Warn("pin stalled", "pin", 7)
+
+	reg := prometheus.NewRegistry()
+	pins := prometheus.NewCounter(prometheus.CounterOpts{Name: "amp_pins_total", Help: "Pins served"})
+	reg.MustRegister(pins)
+	pins.Inc()
+
+	mailbox := utils.NewMailboxOf[int](4)
+	mailbox.Deliver(1)
+
+	opts := diag.Opts{
+		Root:    root,
+		Logs:    tail,
+		Metrics: reg,
+		Stats: map[string]func() any{
+			"mailbox": func() any { return mailbox.Stats() },
+		},
+	}
+
+	// streamed to a requester, with a CPU profile
+	rec := httptest.NewRecorder()
+	diag.Handler(opts).ServeHTTP(rec, httptest.NewRequest("GET", "/debug/bundle?cpu=0.1", nil))
+	require.Equal(t, "application/zip", rec.Header().Get("Content-Type"))
+	require.Contains(t, rec.Header().Get("Content-Disposition"), "diag-")
+
+	files := readBundle(t, rec.Body.Bytes())
+	for _, name := range []string{"pprof/cpu.pb.gz", "pprof/heap.pb.gz", "pprof/allocs.pb.gz", "pprof/mutex.pb.gz"} {
+		require.NotEmpty(t, files[name], name)
+	}
+	require.Contains(t, files["pprof/goroutine.txt"], "goroutine")
+	require.Contains(t, files["tasks.txt"], "host")
+	require.Contains(t, files["metrics.txt"], "amp_pins_total 1")
+	require.Contains(t, files["log.txt"], "pin stalled pin=7")
+	require.NotContains(t, files, "errors.txt")
+
+	snap := task.Snapshot{}
+	require.NoError(t, json.Unmarshal([]byte(files["tasks.json"]), &snap))
+	require.Equal(t, "host", snap.Label)
+
+	stats := utils.MailboxStats{}
+	require.NoError(t, json.Unmarshal([]byte(files["stats/mailbox.json"]), &stats))
+	require.Equal(t, uint64(1), stats.Delivered)
+
+	info := diag.Info{}
+	require.NoError(t, json.Unmarshal([]byte(files["info.json"]), &info))
+	require.NotEmpty(t, info.GoVersion)
+	require.NotZero(t, info.NumGoroutine)
+
+	rec = httptest.NewRecorder()
+	diag.Handler(opts).ServeHTTP(rec, httptest.NewRequest("GET", "/debug/bundle?cpu=soon", nil))
+	require.Equal(t, 400, rec.Code)
+
+	// written to disk
+	opts.Dir = t.TempDir()
+	path, err := diag.WriteFile(opts)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(path, opts.Dir))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Contains(t, readBundle(t, data), "log.txt")
+}
+
+func TestBundleErrors(t *testing.T) {
+	buf := &bytes.Buffer{}
+	require.NoError(t, diag.WriteBundle(buf, diag.Opts{
+		Stats: map[string]func() any{
+			"bad": func() any { return func() {} }, // not JSON encodable
+		},
+		CPUProfile: 10 * time.Millisecond,
+	}))
+	files := readBundle(t, buf.Bytes())
+	require.Contains(t, files["errors.txt"], "stats/bad.json")
+	require.Contains(t, files, "info.json")
+	require.NotContains(t, files, "tasks.json")
+}
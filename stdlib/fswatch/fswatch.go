@@ -0,0 +1,425 @@
+// Package fswatch notifies of changes to files on disk, so that a pinned directory can reflect them as they happen.
+//
+// A Watcher is shared by everything watching on behalf of an app: each Watch names a directory and how deep below it to
+// watch, and receives the changes within it in debounced batches.  Directories watched by more than one Watch are watched
+// once, and the number of directories watched in total is capped (see Opts.MaxWatches) so that a deep tree cannot exhaust
+// the OS's watch limit.
+//
+// Changes are read from the OS via fsnotify (github.com/fsnotify/fsnotify), i.e. inotify on Linux, kqueue on BSD and
+// macOS, and ReadDirectoryChangesW on Windows.  If Opts.Poll is set (e.g. for network filesystems, whose changes the OS
+// may not report), watched directories are polled instead.
+package fswatch
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrTooManyWatches = errors.New("fswatch: too many directories watched")
+	ErrClosed         = errors.New("fswatch: watcher closed")
+)
+
+// Defaults for Opts
+const (
+	DefaultDebounce     = 100 * time.Millisecond
+	DefaultMaxDelay     = time.Second
+	DefaultMaxWatches   = 4096
+	DefaultPollInterval = 2 * time.Second
+)
+
+// Op is the kind of change an Event reports.
+type Op int32
+
+const (
+	Create Op = iota + 1 // the file was created (or moved into a watched directory)
+	Write                // the file's content was written
+	Remove               // the file was removed (or moved out of a watched directory)
+	Rescan               // changes were lost (e.g. the OS's event queue overflowed), so the watched tree should be read again
+)
+
+func (op Op) String() string {
+	switch op {
+	case Create:
+		return "create"
+	case Write:
+		return "write"
+	case Remove:
+		return "remove"
+	case Rescan:
+		return "rescan"
+	}
+	return fmt.Sprintf("Op(%d)", int32(op))
+}
+
+// Event is a change to a file or directory.
+type Event struct {
+	Path  string // absolute path of the file changed (for Rescan, the root of the Watch)
+	Op    Op
+	IsDir bool // set if the file is a directory (if known)
+}
+
+// Opts configures a Watcher.
+type Opts struct {
+	Debounce     time.Duration // how long a Watch's changes must be quiet before they are delivered (default DefaultDebounce)
+	MaxDelay     time.Duration // the longest a change is held for debouncing while changes continue (default DefaultMaxDelay)
+	MaxWatches   int           // the most directories watched at once (default DefaultMaxWatches)
+	Poll         bool          // poll watched directories rather than reading changes from the OS
+	PollInterval time.Duration // how often directories are polled, if polled (default DefaultPollInterval)
+}
+
+// backend reports changes to the directories added to it.
+type backend interface {
+	add(dir string) error
+	remove(dir string)
+	close() error
+}
+
+// Watcher watches directories on behalf of its Watches.  It is safe for concurrent use.
+type Watcher struct {
+	opts Opts
+	be   backend
+
+	mu      sync.Mutex
+	closed  bool
+	dirs    map[string]int // watched directories by number of watches referencing them
+	watches map[*Watch]struct{}
+}
+
+// New starts a Watcher.
+func New(opts Opts) (*Watcher, error) {
+	if opts.Debounce <= 0 {
+		opts.Debounce = DefaultDebounce
+	}
+	if opts.MaxDelay < opts.Debounce {
+		opts.MaxDelay = max(DefaultMaxDelay, opts.Debounce)
+	}
+	if opts.MaxWatches <= 0 {
+		opts.MaxWatches = DefaultMaxWatches
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = DefaultPollInterval
+	}
+
+	w := &Watcher{
+		opts:    opts,
+		dirs:    make(map[string]int),
+		watches: make(map[*Watch]struct{}),
+	}
+	if opts.Poll {
+		w.be = newPoller(w.dispatch, opts.PollInterval)
+	} else {
+		n, err := newNotifier(w.dispatch)
+		if err != nil {
+			return nil, err
+		}
+		w.be = n
+	}
+	return w, nil
+}
+
+// NumWatched returns the number of directories currently watched.
+func (w *Watcher) NumWatched() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.dirs)
+}
+
+// Close stops all watches.
+func (w *Watcher) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	watches := w.watches
+	w.watches = nil
+	w.mu.Unlock()
+
+	for watch := range watches {
+		watch.stop()
+	}
+	return w.be.close()
+}
+
+// Watch is a directory watched via Watcher.Watch.
+type Watch struct {
+	w       *Watcher
+	root    string
+	depth   int
+	deliver func(batch []Event)
+
+	deliverMu sync.Mutex
+	mu        sync.Mutex
+	dirs      map[string]struct{} // directories this watch references (guarded by Watcher.mu)
+	pending   []Event             // changes not yet delivered, at most one per path
+	first     time.Time           // when the oldest pending change occurred
+	timer     *time.Timer
+	stopped   bool
+}
+
+// Watch watches the given directory and its subdirectories up to the given depth below it (where 0 watches only the
+// directory itself), including subdirectories created after the watch begins.  Each batch of changes is delivered to
+// the given func once no further changes have occurred for Opts.Debounce (or changes have been held for Opts.MaxDelay).
+// deliver is called from a goroutine of its own and ought to return promptly.
+//
+// If watching the tree would exceed Opts.MaxWatches, ErrTooManyWatches is returned and nothing is watched.
+func (w *Watcher) Watch(dir string, depth int, deliver func(batch []Event)) (*Watch, error) {
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	if info, err := os.Stat(root); err != nil {
+		return nil, err
+	} else if !info.IsDir() {
+		return nil, fmt.Errorf("fswatch: %s is not a directory", root)
+	}
+
+	watch := &Watch{
+		w:       w,
+		root:    root,
+		depth:   max(depth, 0),
+		deliver: deliver,
+		dirs:    make(map[string]struct{}),
+	}
+	dirs := []string{root}
+	if watch.depth > 0 {
+		dirs = append(dirs, subdirs(root, watch.depth)...)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return nil, ErrClosed
+	}
+	for _, dir := range dirs {
+		if err := w.addDirLocked(watch, dir); err != nil {
+			w.releaseLocked(watch)
+			return nil, err
+		}
+	}
+	w.watches[watch] = struct{}{}
+	return watch, nil
+}
+
+// Root returns the absolute path of the watched directory.
+func (watch *Watch) Root() string {
+	return watch.root
+}
+
+// Close stops this watch, discarding any changes not yet delivered.
+func (watch *Watch) Close() {
+	w := watch.w
+	w.mu.Lock()
+	if _, exists := w.watches[watch]; exists {
+		delete(w.watches, watch)
+		w.releaseLocked(watch)
+	}
+	w.mu.Unlock()
+	watch.stop()
+}
+
+func (watch *Watch) stop() {
+	watch.mu.Lock()
+	defer watch.mu.Unlock()
+	watch.stopped = true
+	watch.pending = nil
+	if watch.timer != nil {
+		watch.timer.Stop()
+	}
+}
+
+// addDirLocked adds the given directory to those the given watch references, watching it if no other watch does.
+func (w *Watcher) addDirLocked(watch *Watch, dir string) error {
+	if _, exists := watch.dirs[dir]; exists {
+		return nil
+	}
+	if w.dirs[dir] == 0 {
+		if len(w.dirs) >= w.opts.MaxWatches {
+			return ErrTooManyWatches
+		}
+		if err := w.be.add(dir); err != nil {
+			return err
+		}
+	}
+	w.dirs[dir]++
+	watch.dirs[dir] = struct{}{}
+	return nil
+}
+
+// removeDirLocked removes the given directory (and those below it) from those the given watch references.
+func (w *Watcher) removeDirLocked(watch *Watch, dir string) {
+	for watched := range watch.dirs {
+		if watched == dir || isWithin(dir, watched) {
+			delete(watch.dirs, watched)
+			w.unrefLocked(watched)
+		}
+	}
+}
+
+func (w *Watcher) releaseLocked(watch *Watch) {
+	for dir := range watch.dirs {
+		w.unrefLocked(dir)
+	}
+	clear(watch.dirs)
+}
+
+func (w *Watcher) unrefLocked(dir string) {
+	if w.dirs[dir]--; w.dirs[dir] <= 0 {
+		delete(w.dirs, dir)
+		if !w.closed {
+			w.be.remove(dir)
+		}
+	}
+}
+
+// dispatch is called by the backend for each change to a watched directory.
+func (w *Watcher) dispatch(ev Event) {
+	w.mu.Lock()
+	var targets []*Watch
+	for watch := range w.watches {
+		level, within := watch.level(ev.Path)
+		switch {
+		case ev.Op == Rescan:
+			if _, referenced := watch.dirs[ev.Path]; referenced || ev.Path == "" {
+				watch.add(Event{Path: watch.root, Op: Rescan})
+			}
+			continue
+		case !within || level > watch.depth+1:
+			continue
+		case ev.IsDir && ev.Op == Create && level <= watch.depth:
+			// Watch new subdirectories (and those below, if the subdirectory was moved here) up to the watch's depth
+			dirs := append([]string{ev.Path}, subdirs(ev.Path, watch.depth-level)...)
+			for _, dir := range dirs {
+				if err := w.addDirLocked(watch, dir); err != nil {
+					watch.add(Event{Path: watch.root, Op: Rescan})
+					break
+				}
+			}
+		case ev.Op == Remove && level <= watch.depth:
+			w.removeDirLocked(watch, ev.Path)
+		}
+		targets = append(targets, watch)
+	}
+	w.mu.Unlock()
+
+	for _, watch := range targets {
+		watch.add(ev)
+	}
+}
+
+// level returns how many levels below this watch's root the given path is.
+func (watch *Watch) level(path string) (int, bool) {
+	if path == watch.root {
+		return 0, true
+	}
+	if !isWithin(watch.root, path) {
+		return 0, false
+	}
+	rel := path[len(watch.root):]
+	return strings.Count(rel, string(filepath.Separator)), true
+}
+
+// add adds the given change to those pending delivery, (re)scheduling their delivery.
+func (watch *Watch) add(ev Event) {
+	watch.mu.Lock()
+	defer watch.mu.Unlock()
+	if watch.stopped {
+		return
+	}
+
+	wasEmpty := len(watch.pending) == 0
+	watch.pending = coalesce(watch.pending, ev)
+	if len(watch.pending) == 0 {
+		return
+	}
+
+	now := time.Now()
+	if wasEmpty {
+		watch.first = now
+	}
+	delay := watch.w.opts.Debounce
+	if deadline := watch.first.Add(watch.w.opts.MaxDelay); now.Add(delay).After(deadline) {
+		delay = max(deadline.Sub(now), 0)
+	}
+	if watch.timer == nil {
+		watch.timer = time.AfterFunc(delay, watch.flush)
+	} else {
+		watch.timer.Reset(delay)
+	}
+}
+
+// coalesce returns the given pending changes with the given change added, merging it with any pending change to the same path.
+func coalesce(pending []Event, ev Event) []Event {
+	for i := range pending {
+		prev := &pending[i]
+		if prev.Op == Rescan || ev.Op == Rescan {
+			if prev.Op == ev.Op {
+				return pending // a rescan subsumes another
+			}
+			continue
+		}
+		if prev.Path != ev.Path {
+			continue
+		}
+		switch {
+		case prev.Op == Create && ev.Op == Remove:
+			return append(pending[:i], pending[i+1:]...) // never seen, so not reported
+		case prev.Op == Create:
+			// still a create
+		case prev.Op == Remove && ev.Op == Create:
+			prev.Op, prev.IsDir = Write, ev.IsDir // replaced
+		default:
+			prev.Op = ev.Op
+		}
+		return pending
+	}
+	return append(pending, ev)
+}
+
+// flush delivers the pending changes, where deliverMu orders deliveries if the timer fires again during a delivery.
+func (watch *Watch) flush() {
+	watch.deliverMu.Lock()
+	defer watch.deliverMu.Unlock()
+
+	watch.mu.Lock()
+	batch := watch.pending
+	watch.pending = nil
+	stopped := watch.stopped
+	watch.mu.Unlock()
+
+	if len(batch) > 0 && !stopped {
+		watch.deliver(batch)
+	}
+}
+
+// isWithin returns true if path is below dir.
+func isWithin(dir, path string) bool {
+	return len(path) > len(dir) && strings.HasPrefix(path, dir) && (path[len(dir)] == filepath.Separator || strings.HasSuffix(dir, string(filepath.Separator)))
+}
+
+// subdirs returns the subdirectories of the given directory up to the given depth below it, not following symlinks.
+func subdirs(dir string, depth int) []string {
+	if depth <= 0 {
+		return nil
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var dirs []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			sub := filepath.Join(dir, entry.Name())
+			dirs = append(dirs, sub)
+			dirs = append(dirs, subdirs(sub, depth-1)...)
+		}
+	}
+	return dirs
+}
@@ -0,0 +1,213 @@
+package fswatch
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// recorder collects the batches delivered to a Watch.
+type recorder struct {
+	mu      sync.Mutex
+	batches [][]Event
+	changed chan struct{}
+}
+
+func newRecorder() *recorder {
+	return &recorder{changed: make(chan struct{}, 1)}
+}
+
+func (rec *recorder) deliver(batch []Event) {
+	rec.mu.Lock()
+	rec.batches = append(rec.batches, batch)
+	rec.mu.Unlock()
+	select {
+	case rec.changed <- struct{}{}:
+	default:
+	}
+}
+
+// ops returns the latest op delivered for each path.
+func (rec *recorder) ops() map[string]Op {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	ops := make(map[string]Op)
+	for _, batch := range rec.batches {
+		for _, ev := range batch {
+			ops[ev.Path] = ev.Op
+		}
+	}
+	return ops
+}
+
+// waitFor waits until the given path has been delivered with the given op.
+func (rec *recorder) waitFor(t *testing.T, path string, op Op) {
+	t.Helper()
+	timeout := time.After(5 * time.Second)
+	for rec.ops()[path] != op {
+		select {
+		case <-rec.changed:
+		case <-timeout:
+			t.Fatalf("timed out waiting for %v of %s (got %v)", op, path, rec.ops())
+		}
+	}
+}
+
+func backends() map[string]Opts {
+	return map[string]Opts{
+		"fsnotify": {Debounce: 20 * time.Millisecond},
+		"poll":     {Debounce: 20 * time.Millisecond, Poll: true, PollInterval: 20 * time.Millisecond},
+	}
+}
+
+func TestWatch(t *testing.T) {
+	for name, opts := range backends() {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			existing := filepath.Join(dir, "existing.txt")
+			require.NoError(t, os.WriteFile(existing, []byte("a"), 0644))
+			require.NoError(t, os.MkdirAll(filepath.Join(dir, "sub", "deeper"), 0755))
+
+			w, err := New(opts)
+			require.NoError(t, err)
+			defer w.Close()
+
+			rec := newRecorder()
+			watch, err := w.Watch(dir, 1, rec.deliver)
+			require.NoError(t, err)
+			require.Equal(t, 2, w.NumWatched()) // dir and sub
+
+			created := filepath.Join(dir, "new.txt")
+			require.NoError(t, os.WriteFile(created, []byte("b"), 0644))
+			rec.waitFor(t, created, Create)
+
+			require.NoError(t, os.WriteFile(existing, []byte("changed"), 0644))
+			rec.waitFor(t, existing, Write)
+
+			require.NoError(t, os.Remove(existing))
+			rec.waitFor(t, existing, Remove)
+
+			// Within the depth limit, including subdirectories created after the watch began
+			inSub := filepath.Join(dir, "sub", "a.txt")
+			require.NoError(t, os.WriteFile(inSub, []byte("c"), 0644))
+			rec.waitFor(t, inSub, Create)
+
+			newSub := filepath.Join(dir, "later")
+			require.NoError(t, os.Mkdir(newSub, 0755))
+			rec.waitFor(t, newSub, Create)
+			require.Eventually(t, func() bool { return w.NumWatched() == 3 }, 5*time.Second, time.Millisecond)
+			inNewSub := filepath.Join(newSub, "b.txt")
+			require.NoError(t, os.WriteFile(inNewSub, []byte("d"), 0644))
+			rec.waitFor(t, inNewSub, Create)
+
+			// Beyond the depth limit
+			tooDeep := filepath.Join(dir, "sub", "deeper", "c.txt")
+			require.NoError(t, os.WriteFile(tooDeep, []byte("e"), 0644))
+			marker := filepath.Join(dir, "marker.txt")
+			require.NoError(t, os.WriteFile(marker, []byte("f"), 0644))
+			rec.waitFor(t, marker, Create)
+			_, reported := rec.ops()[tooDeep]
+			require.False(t, reported)
+
+			// Removing a subdirectory stops watching it
+			require.NoError(t, os.RemoveAll(newSub))
+			rec.waitFor(t, newSub, Remove)
+			require.Eventually(t, func() bool { return w.NumWatched() == 2 }, 5*time.Second, time.Millisecond)
+
+			watch.Close()
+			require.Equal(t, 0, w.NumWatched())
+		})
+	}
+}
+
+func TestWatchLimits(t *testing.T) {
+	dir := t.TempDir()
+	for _, sub := range []string{"a", "b", "c"} {
+		require.NoError(t, os.Mkdir(filepath.Join(dir, sub), 0755))
+	}
+
+	w, err := New(Opts{MaxWatches: 3})
+	require.NoError(t, err)
+	defer w.Close()
+
+	_, err = w.Watch(dir, 1, func([]Event) {})
+	require.ErrorIs(t, err, ErrTooManyWatches)
+	require.Equal(t, 0, w.NumWatched())
+
+	// Directories watched by several watches count once
+	first, err := w.Watch(dir, 0, func([]Event) {})
+	require.NoError(t, err)
+	second, err := w.Watch(filepath.Join(dir, "a"), 0, func([]Event) {})
+	require.NoError(t, err)
+	third, err := w.Watch(dir, 0, func([]Event) {})
+	require.NoError(t, err)
+	require.Equal(t, 2, w.NumWatched())
+	first.Close()
+	require.Equal(t, 2, w.NumWatched())
+	third.Close()
+	second.Close()
+	require.Equal(t, 0, w.NumWatched())
+
+	_, err = w.Watch(filepath.Join(dir, "missing"), 0, func([]Event) {})
+	require.Error(t, err)
+
+	require.NoError(t, w.Close())
+	_, err = w.Watch(dir, 0, func([]Event) {})
+	require.ErrorIs(t, err, ErrClosed)
+}
+
+func TestCoalesce(t *testing.T) {
+	var pending []Event
+	for _, ev := range []Event{
+		{Path: "/a", Op: Create},
+		{Path: "/a", Op: Write},
+		{Path: "/b", Op: Write},
+		{Path: "/c", Op: Create},
+		{Path: "/c", Op: Remove}, // never seen
+		{Path: "/b", Op: Remove},
+		{Path: "/d", Op: Remove},
+		{Path: "/d", Op: Create, IsDir: true}, // replaced
+		{Path: "/", Op: Rescan},
+		{Path: "/", Op: Rescan},
+	} {
+		pending = coalesce(pending, ev)
+	}
+	require.Equal(t, []Event{
+		{Path: "/a", Op: Create},
+		{Path: "/b", Op: Remove},
+		{Path: "/d", Op: Write, IsDir: true},
+		{Path: "/", Op: Rescan},
+	}, pending)
+}
+
+func TestDebounce(t *testing.T) {
+	w, err := New(Opts{Debounce: 50 * time.Millisecond, MaxDelay: 200 * time.Millisecond, Poll: true, PollInterval: time.Hour})
+	require.NoError(t, err)
+	defer w.Close()
+
+	rec := newRecorder()
+	watch, err := w.Watch(t.TempDir(), 0, rec.deliver)
+	require.NoError(t, err)
+
+	// Changes continuing for longer than MaxDelay are delivered anyway, in more than one batch
+	start := time.Now()
+	for time.Since(start) < 400*time.Millisecond {
+		watch.add(Event{Path: filepath.Join(watch.Root(), "busy"), Op: Write})
+		time.Sleep(10 * time.Millisecond)
+	}
+	rec.mu.Lock()
+	delivered := len(rec.batches)
+	rec.mu.Unlock()
+	require.GreaterOrEqual(t, delivered, 1)
+	rec.waitFor(t, filepath.Join(watch.Root(), "busy"), Write)
+
+	watch.Close()
+	watch.add(Event{Path: filepath.Join(watch.Root(), "after"), Op: Create})
+	time.Sleep(100 * time.Millisecond)
+	_, reported := rec.ops()[filepath.Join(watch.Root(), "after")]
+	require.False(t, reported)
+}
@@ -0,0 +1,106 @@
+package fswatch
+
+import (
+	"errors"
+	"os"
+	"sync"
+	"syscall"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// notifier is a backend reading changes from an fsnotify.Watcher, where each watched directory is added to it.
+type notifier struct {
+	emit func(Event)
+	fsw  *fsnotify.Watcher
+	done chan struct{}
+
+	mu   sync.Mutex
+	dirs map[string]struct{} // watched directories, so that removes of them are reported as directories
+}
+
+func newNotifier(emit func(Event)) (*notifier, error) {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	n := &notifier{
+		emit: emit,
+		fsw:  fsw,
+		done: make(chan struct{}),
+		dirs: make(map[string]struct{}),
+	}
+	go n.run()
+	return n, nil
+}
+
+func (n *notifier) add(dir string) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	err := n.fsw.Add(dir)
+	if errors.Is(err, syscall.ENOSPC) {
+		return ErrTooManyWatches // fs.inotify.max_user_watches reached
+	} else if err != nil {
+		return err
+	}
+	n.dirs[dir] = struct{}{}
+	return nil
+}
+
+func (n *notifier) remove(dir string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	delete(n.dirs, dir)
+	n.fsw.Remove(dir) // fails harmlessly if the directory is already gone
+}
+
+func (n *notifier) close() error {
+	err := n.fsw.Close()
+	<-n.done
+	return err
+}
+
+func (n *notifier) run() {
+	defer close(n.done)
+
+	for {
+		select {
+		case ev, ok := <-n.fsw.Events:
+			if !ok {
+				return
+			}
+			n.handle(ev)
+		case _, ok := <-n.fsw.Errors:
+			if !ok {
+				return
+			}
+			n.emit(Event{Op: Rescan}) // e.g. fsnotify.ErrEventOverflow
+		}
+	}
+}
+
+func (n *notifier) handle(fse fsnotify.Event) {
+	ev := Event{
+		Path: fse.Name,
+	}
+	switch {
+	case fse.Has(fsnotify.Create):
+		info, err := os.Lstat(fse.Name)
+		if err != nil {
+			return // removed since created, which is reported next
+		}
+		ev.Op, ev.IsDir = Create, info.IsDir()
+	case fse.Has(fsnotify.Remove) || fse.Has(fsnotify.Rename):
+		n.mu.Lock()
+		_, ev.IsDir = n.dirs[fse.Name]
+		n.mu.Unlock()
+		ev.Op = Remove
+	case fse.Has(fsnotify.Write):
+		ev.Op = Write
+	default:
+		return
+	}
+	n.emit(ev)
+}
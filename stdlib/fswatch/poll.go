@@ -0,0 +1,144 @@
+package fswatch
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// poller is a backend that lists each watched directory every interval, reporting how it changed since last listed.
+type poller struct {
+	emit     func(Event)
+	interval time.Duration
+
+	mu   sync.Mutex
+	dirs map[string]map[string]fileState // last listing of each watched directory
+	stop chan struct{}
+	done chan struct{}
+}
+
+type fileState struct {
+	isDir   bool
+	size    int64
+	modTime time.Time
+}
+
+func newPoller(emit func(Event), interval time.Duration) *poller {
+	p := &poller{
+		emit:     emit,
+		interval: interval,
+		dirs:     make(map[string]map[string]fileState),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go p.run()
+	return p
+}
+
+func (p *poller) add(dir string) error {
+	listing, err := list(dir)
+	if err != nil {
+		return err
+	}
+	p.mu.Lock()
+	p.dirs[dir] = listing
+	p.mu.Unlock()
+	return nil
+}
+
+func (p *poller) remove(dir string) {
+	p.mu.Lock()
+	delete(p.dirs, dir)
+	p.mu.Unlock()
+}
+
+func (p *poller) close() error {
+	close(p.stop)
+	<-p.done
+	return nil
+}
+
+func (p *poller) run() {
+	defer close(p.done)
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			p.poll()
+		case <-p.stop:
+			return
+		}
+	}
+}
+
+// poll lists each watched directory, emitting its changes since last listed.
+func (p *poller) poll() {
+	p.mu.Lock()
+	dirs := make([]string, 0, len(p.dirs))
+	for dir := range p.dirs {
+		dirs = append(dirs, dir)
+	}
+	p.mu.Unlock()
+
+	for _, dir := range dirs {
+		listing, err := list(dir)
+
+		p.mu.Lock()
+		prev, watched := p.dirs[dir]
+		if watched {
+			if err != nil {
+				delete(p.dirs, dir)
+			} else {
+				p.dirs[dir] = listing
+			}
+		}
+		p.mu.Unlock()
+		if !watched {
+			continue // removed while listing
+		}
+
+		if err != nil {
+			p.emit(Event{Path: dir, Op: Remove, IsDir: true})
+			continue
+		}
+		for name, was := range prev {
+			if _, exists := listing[name]; !exists {
+				p.emit(Event{Path: filepath.Join(dir, name), Op: Remove, IsDir: was.isDir})
+			}
+		}
+		for name, state := range listing {
+			was, existed := prev[name]
+			switch {
+			case !existed || was.isDir != state.isDir:
+				if existed {
+					p.emit(Event{Path: filepath.Join(dir, name), Op: Remove, IsDir: was.isDir})
+				}
+				p.emit(Event{Path: filepath.Join(dir, name), Op: Create, IsDir: state.isDir})
+			case !state.isDir && (was.size != state.size || !was.modTime.Equal(state.modTime)):
+				p.emit(Event{Path: filepath.Join(dir, name), Op: Write})
+			}
+		}
+	}
+}
+
+func list(dir string) (map[string]fileState, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	listing := make(map[string]fileState, len(entries))
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil {
+			continue // removed since listed
+		}
+		listing[entry.Name()] = fileState{
+			isDir:   info.IsDir(),
+			size:    info.Size(),
+			modTime: info.ModTime(),
+		}
+	}
+	return listing, nil
+}
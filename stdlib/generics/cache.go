@@ -0,0 +1,315 @@
+package generics
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+var errLoadPanicked = errors.New("generics: cache load panicked")
+
+// EvictReason is why an entry left a cache, as passed to CacheOpts.OnEvict.
+type EvictReason int
+
+const (
+	Evicted  EvictReason = iota // removed to stay within CacheOpts.MaxEntries or CacheOpts.MaxSize
+	Expired                     // outlived its TTL
+	Removed                     // removed via Remove or Clear
+	Replaced                    // replaced by a Set of the same key
+)
+
+// CacheOpts configures an LRU or TTLCache.  A zero value means no limit.
+type CacheOpts[K comparable, V any] struct {
+	MaxEntries int                      // most entries held
+	MaxSize    int64                    // most total size of the entries held, as given by Size
+	Size       func(key K, val V) int64 // size of an entry (default 1)
+
+	// If set, called with each entry leaving the cache, including the prior value of an entry replaced via Set.
+	// It is called without the cache locked, so it may use the cache.
+	OnEvict func(key K, val V, reason EvictReason)
+
+	Now func() time.Time // returns the current time (default time.Now)
+}
+
+// LRU is a cache holding up to the entries allowed by its CacheOpts, evicting the least recently used entry first.
+// It is safe for concurrent use.
+type LRU[K comparable, V any] struct {
+	opts  CacheOpts[K, V]
+	mu    sync.Mutex
+	items map[K]*cacheEntry[K, V]
+	lru   cacheEntry[K, V] // sentinel: lru.next is the most recently used entry, lru.prev the least
+	size  int64
+	loads map[K]*cacheLoad[V]
+}
+
+type cacheEntry[K comparable, V any] struct {
+	key        K
+	val        V
+	size       int64
+	expires    int64 // in unix nanoseconds, or 0 if never
+	prev, next *cacheEntry[K, V]
+}
+
+type cacheLoad[V any] struct {
+	done chan struct{}
+	val  V
+	err  error
+}
+
+type eviction[K comparable, V any] struct {
+	key    K
+	val    V
+	reason EvictReason
+}
+
+// NewLRU returns a new LRU configured by the given opts.
+func NewLRU[K comparable, V any](opts CacheOpts[K, V]) *LRU[K, V] {
+	c := &LRU[K, V]{}
+	c.init(opts)
+	return c
+}
+
+func (c *LRU[K, V]) init(opts CacheOpts[K, V]) {
+	c.opts = opts
+	c.items = make(map[K]*cacheEntry[K, V])
+	c.lru.next, c.lru.prev = &c.lru, &c.lru
+	if c.opts.Now == nil {
+		c.opts.Now = time.Now
+	}
+}
+
+// Get returns the value held for the given key (marking it as most recently used), or false if there is none.
+func (c *LRU[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	val, ok, evicted := c.get(key, true)
+	c.mu.Unlock()
+	c.notify(evicted)
+	return val, ok
+}
+
+// Peek is like Get but leaves the entry's use unchanged.
+func (c *LRU[K, V]) Peek(key K) (V, bool) {
+	c.mu.Lock()
+	val, ok, evicted := c.get(key, false)
+	c.mu.Unlock()
+	c.notify(evicted)
+	return val, ok
+}
+
+// Set holds the given value for the given key as the most recently used entry, evicting entries as needed.
+func (c *LRU[K, V]) Set(key K, val V) {
+	c.set(key, val, 0)
+}
+
+// Remove removes the entry for the given key, returning false if there was none.
+func (c *LRU[K, V]) Remove(key K) bool {
+	c.mu.Lock()
+	var evicted []eviction[K, V]
+	e := c.items[key]
+	if e != nil {
+		evicted = c.remove(e, Removed, evicted)
+	}
+	c.mu.Unlock()
+	c.notify(evicted)
+	return e != nil
+}
+
+// Clear removes every entry.
+func (c *LRU[K, V]) Clear() {
+	c.mu.Lock()
+	var evicted []eviction[K, V]
+	for c.lru.prev != &c.lru {
+		evicted = c.remove(c.lru.prev, Removed, evicted)
+	}
+	c.mu.Unlock()
+	c.notify(evicted)
+}
+
+// Len returns the number of entries held (including any expired but not yet removed).
+func (c *LRU[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.items)
+}
+
+// Size returns the total size of the entries held (see CacheOpts.Size).
+func (c *LRU[K, V]) Size() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.size
+}
+
+// Load returns the value held for the given key, or if there is none, calls load and holds the value it returns.
+// Concurrent Loads of the same key share a single call to load, and an error it returns is returned (but not held).
+func (c *LRU[K, V]) Load(key K, load func(key K) (V, error)) (V, error) {
+	return c.load(key, load, 0)
+}
+
+func (c *LRU[K, V]) load(key K, load func(key K) (V, error), ttl time.Duration) (V, error) {
+	c.mu.Lock()
+	val, ok, evicted := c.get(key, true)
+	if ok {
+		c.mu.Unlock()
+		c.notify(evicted)
+		return val, nil
+	}
+	if pending := c.loads[key]; pending != nil {
+		c.mu.Unlock()
+		c.notify(evicted)
+		<-pending.done
+		return pending.val, pending.err
+	}
+	pending := &cacheLoad[V]{done: make(chan struct{}), err: errLoadPanicked}
+	if c.loads == nil {
+		c.loads = make(map[K]*cacheLoad[V])
+	}
+	c.loads[key] = pending
+	c.mu.Unlock()
+	c.notify(evicted)
+
+	defer func() {
+		c.mu.Lock()
+		delete(c.loads, key)
+		c.mu.Unlock()
+		close(pending.done)
+	}()
+	pending.val, pending.err = load(key)
+	if pending.err == nil {
+		c.set(key, pending.val, ttl)
+	}
+	return pending.val, pending.err
+}
+
+func (c *LRU[K, V]) set(key K, val V, ttl time.Duration) {
+	size := int64(1)
+	if c.opts.Size != nil {
+		size = c.opts.Size(key, val)
+	}
+	var expires int64
+	if ttl > 0 {
+		expires = c.opts.Now().Add(ttl).UnixNano()
+	}
+
+	c.mu.Lock()
+	var evicted []eviction[K, V]
+	if e := c.items[key]; e != nil {
+		evicted = append(evicted, eviction[K, V]{e.key, e.val, Replaced})
+		c.size += size - e.size
+		e.val, e.size, e.expires = val, size, expires
+		c.unlink(e)
+		c.pushFront(e)
+	} else {
+		e = &cacheEntry[K, V]{key: key, val: val, size: size, expires: expires}
+		c.items[key] = e
+		c.size += size
+		c.pushFront(e)
+	}
+
+	// Evict least recently used entries until within limits (though never the entry just set)
+	for c.lru.prev != c.lru.next && c.overLimits() {
+		evicted = c.remove(c.lru.prev, Evicted, evicted)
+	}
+	c.mu.Unlock()
+	c.notify(evicted)
+}
+
+func (c *LRU[K, V]) overLimits() bool {
+	return (c.opts.MaxEntries > 0 && len(c.items) > c.opts.MaxEntries) || (c.opts.MaxSize > 0 && c.size > c.opts.MaxSize)
+}
+
+// get returns the value held for the given key, removing it if expired -- c.mu must be locked.
+func (c *LRU[K, V]) get(key K, markUsed bool) (val V, ok bool, evicted []eviction[K, V]) {
+	e := c.items[key]
+	if e == nil {
+		return val, false, nil
+	}
+	if e.expires != 0 && c.opts.Now().UnixNano() >= e.expires {
+		return val, false, c.remove(e, Expired, nil)
+	}
+	if markUsed {
+		c.unlink(e)
+		c.pushFront(e)
+	}
+	return e.val, true, nil
+}
+
+// prune removes every expired entry, returning how many were removed.
+func (c *LRU[K, V]) prune() int {
+	now := c.opts.Now().UnixNano()
+	c.mu.Lock()
+	var evicted []eviction[K, V]
+	for _, e := range c.items {
+		if e.expires != 0 && now >= e.expires {
+			evicted = c.remove(e, Expired, evicted)
+		}
+	}
+	c.mu.Unlock()
+	c.notify(evicted)
+	return len(evicted)
+}
+
+// remove removes the given entry, appending it to evicted -- c.mu must be locked.
+func (c *LRU[K, V]) remove(e *cacheEntry[K, V], reason EvictReason, evicted []eviction[K, V]) []eviction[K, V] {
+	c.unlink(e)
+	delete(c.items, e.key)
+	c.size -= e.size
+	return append(evicted, eviction[K, V]{e.key, e.val, reason})
+}
+
+func (c *LRU[K, V]) notify(evicted []eviction[K, V]) {
+	if c.opts.OnEvict == nil {
+		return
+	}
+	for _, ev := range evicted {
+		c.opts.OnEvict(ev.key, ev.val, ev.reason)
+	}
+}
+
+func (c *LRU[K, V]) unlink(e *cacheEntry[K, V]) {
+	e.prev.next = e.next
+	e.next.prev = e.prev
+	e.prev, e.next = nil, nil
+}
+
+func (c *LRU[K, V]) pushFront(e *cacheEntry[K, V]) {
+	e.prev = &c.lru
+	e.next = c.lru.next
+	c.lru.next.prev = e
+	c.lru.next = e
+}
+
+// TTLCache is an LRU whose entries expire a given time after they are set.  An expired entry is removed when it is next
+// looked up, when Prune is called, or when evicted to make room for other entries.  It is safe for concurrent use.
+type TTLCache[K comparable, V any] struct {
+	LRU[K, V]
+	ttl time.Duration
+}
+
+// NewTTLCache returns a new TTLCache whose entries expire the given time after being set, configured by the given opts.
+func NewTTLCache[K comparable, V any](ttl time.Duration, opts CacheOpts[K, V]) *TTLCache[K, V] {
+	c := &TTLCache[K, V]{
+		ttl: ttl,
+	}
+	c.init(opts)
+	return c
+}
+
+// Set holds the given value for the given key until the cache's TTL elapses (see LRU.Set).
+func (c *TTLCache[K, V]) Set(key K, val V) {
+	c.set(key, val, c.ttl)
+}
+
+// SetTTL is like Set, with the given TTL in place of the cache's.
+func (c *TTLCache[K, V]) SetTTL(key K, val V, ttl time.Duration) {
+	c.set(key, val, ttl)
+}
+
+// Load is like LRU.Load, holding a loaded value until the cache's TTL elapses.
+func (c *TTLCache[K, V]) Load(key K, load func(key K) (V, error)) (V, error) {
+	return c.load(key, load, c.ttl)
+}
+
+// Prune removes every expired entry, returning how many were removed.
+func (c *TTLCache[K, V]) Prune() int {
+	return c.prune()
+}
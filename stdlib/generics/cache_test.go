@@ -0,0 +1,142 @@
+package generics
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLRU(t *testing.T) {
+	var evicted []string
+	lru := NewLRU(CacheOpts[string, string]{
+		MaxEntries: 3,
+		MaxSize:    10,
+		Size: func(key, val string) int64 {
+			return int64(len(val))
+		},
+		OnEvict: func(key, val string, reason EvictReason) {
+			evicted = append(evicted, key+"="+val)
+			if reason == Evicted {
+				evicted[len(evicted)-1] += " (evicted)"
+			}
+		},
+	})
+
+	// The least recently used entry is evicted first
+	lru.Set("a", "1")
+	lru.Set("b", "2")
+	lru.Set("c", "3")
+	val, ok := lru.Get("a")
+	require.True(t, ok)
+	require.Equal(t, "1", val)
+	lru.Set("d", "4")
+	require.Equal(t, []string{"b=2 (evicted)"}, evicted)
+	_, ok = lru.Get("b")
+	require.False(t, ok)
+
+	// Peek leaves an entry's use unchanged, so it is still evicted next
+	_, ok = lru.Peek("c")
+	require.True(t, ok)
+	lru.Set("e", "5")
+	require.Equal(t, "c=3 (evicted)", evicted[1])
+
+	// The total size is limited, and replacing an entry reports its prior value
+	lru.Set("a", "123456789")
+	require.Equal(t, []string{"a=1", "d=4 (evicted)"}, evicted[2:])
+	require.EqualValues(t, 10, lru.Size())
+	require.Equal(t, 2, lru.Len())
+
+	// An entry too large for the cache is held until another is set
+	lru.Set("big", "0123456789abc")
+	require.Equal(t, 1, lru.Len())
+	require.True(t, lru.Remove("big"))
+	require.False(t, lru.Remove("big"))
+	require.Zero(t, lru.Size())
+
+	lru.Set("x", "1")
+	lru.Clear()
+	require.Zero(t, lru.Len())
+	require.Equal(t, "x=1", evicted[len(evicted)-1])
+}
+
+func TestLRULoad(t *testing.T) {
+	lru := NewLRU(CacheOpts[int, int]{})
+	var calls atomic.Int32
+	release := make(chan struct{})
+	load := func(key int) (int, error) {
+		calls.Add(1)
+		<-release
+		return key * 10, nil
+	}
+
+	// Concurrent loads of the same key share one call
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			val, err := lru.Load(7, load)
+			require.NoError(t, err)
+			require.Equal(t, 70, val)
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	require.EqualValues(t, 1, calls.Load())
+	val, err := lru.Load(7, load)
+	require.NoError(t, err)
+	require.Equal(t, 70, val)
+	require.EqualValues(t, 1, calls.Load())
+
+	// Errors are returned but not held
+	failure := errors.New("not found")
+	_, err = lru.Load(8, func(int) (int, error) { return 0, failure })
+	require.Equal(t, failure, err)
+	_, ok := lru.Get(8)
+	require.False(t, ok)
+}
+
+func TestTTLCache(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	var expired []string
+	cache := NewTTLCache(time.Minute, CacheOpts[string, int]{
+		Now: func() time.Time { return now },
+		OnEvict: func(key string, val int, reason EvictReason) {
+			if reason == Expired {
+				expired = append(expired, key)
+			}
+		},
+	})
+
+	cache.Set("a", 1)
+	cache.SetTTL("b", 2, time.Hour)
+	cache.Set("c", 3)
+	now = now.Add(30 * time.Second)
+	val, ok := cache.Get("a")
+	require.True(t, ok)
+	require.Equal(t, 1, val)
+
+	// Entries expire once their TTL elapses, whether looked up or pruned
+	now = now.Add(31 * time.Second)
+	_, ok = cache.Get("a")
+	require.False(t, ok)
+	require.Equal(t, 1, cache.Prune())
+	require.Equal(t, []string{"a", "c"}, expired)
+	val, ok = cache.Get("b")
+	require.True(t, ok)
+	require.Equal(t, 2, val)
+
+	// Loaded values expire too
+	val, err := cache.Load("d", func(string) (int, error) { return 4, nil })
+	require.NoError(t, err)
+	require.Equal(t, 4, val)
+	now = now.Add(2 * time.Minute)
+	_, ok = cache.Get("d")
+	require.False(t, ok)
+	require.Equal(t, 1, cache.Len())
+}
@@ -0,0 +1,244 @@
+package generics
+
+import (
+	"sort"
+)
+
+// OrderedMap is a map that remembers the order its keys were first set, ranging over its entries in that order.
+// The zero value is ready to use.  It is not safe for concurrent use.
+type OrderedMap[K comparable, V any] struct {
+	items       map[K]*orderedEntry[K, V]
+	first, last *orderedEntry[K, V]
+}
+
+type orderedEntry[K comparable, V any] struct {
+	key        K
+	val        V
+	prev, next *orderedEntry[K, V]
+}
+
+// Get returns the value set for the given key, or false if there is none.
+func (m *OrderedMap[K, V]) Get(key K) (V, bool) {
+	if e := m.items[key]; e != nil {
+		return e.val, true
+	}
+	var zero V
+	return zero, false
+}
+
+// Has returns true if a value is set for the given key.
+func (m *OrderedMap[K, V]) Has(key K) bool {
+	return m.items[key] != nil
+}
+
+// Set sets the value for the given key, appending the key if it is new or otherwise leaving its order unchanged.
+// Returns true if the key is new.
+func (m *OrderedMap[K, V]) Set(key K, val V) bool {
+	if e := m.items[key]; e != nil {
+		e.val = val
+		return false
+	}
+	if m.items == nil {
+		m.items = make(map[K]*orderedEntry[K, V])
+	}
+	e := &orderedEntry[K, V]{key: key, val: val, prev: m.last}
+	if m.last != nil {
+		m.last.next = e
+	} else {
+		m.first = e
+	}
+	m.last = e
+	m.items[key] = e
+	return true
+}
+
+// Delete removes the given key, returning false if it was not set.
+func (m *OrderedMap[K, V]) Delete(key K) bool {
+	e := m.items[key]
+	if e == nil {
+		return false
+	}
+	if e.prev != nil {
+		e.prev.next = e.next
+	} else {
+		m.first = e.next
+	}
+	if e.next != nil {
+		e.next.prev = e.prev
+	} else {
+		m.last = e.prev
+	}
+	delete(m.items, key)
+	return true
+}
+
+// Clear removes every key.
+func (m *OrderedMap[K, V]) Clear() {
+	clear(m.items)
+	m.first, m.last = nil, nil
+}
+
+// Len returns the number of keys set.
+func (m *OrderedMap[K, V]) Len() int {
+	return len(m.items)
+}
+
+// Range calls fn with each entry in order until fn returns false.
+// fn may delete the entry it is called with (but not others).
+func (m *OrderedMap[K, V]) Range(fn func(key K, val V) bool) {
+	for e := m.first; e != nil; {
+		next := e.next
+		if !fn(e.key, e.val) {
+			return
+		}
+		e = next
+	}
+}
+
+// Keys returns the keys set, in order.
+func (m *OrderedMap[K, V]) Keys() []K {
+	keys := make([]K, 0, len(m.items))
+	for e := m.first; e != nil; e = e.next {
+		keys = append(keys, e.key)
+	}
+	return keys
+}
+
+// Values returns the values set, in the order of their keys.
+func (m *OrderedMap[K, V]) Values() []V {
+	vals := make([]V, 0, len(m.items))
+	for e := m.first; e != nil; e = e.next {
+		vals = append(vals, e.val)
+	}
+	return vals
+}
+
+// SortedSet is a set of items held in the order given by its comparator, where items comparing as equal are the same item.
+// It is not safe for concurrent use.
+type SortedSet[T any] struct {
+	compare func(a, b T) int
+	items   []T
+}
+
+// NewSortedSet returns an empty SortedSet ordered by the given comparator, which returns a negative number if a sorts
+// before b, a positive number if a sorts after b, and 0 if they are the same item (as cmp.Compare does).
+func NewSortedSet[T any](compare func(a, b T) int) *SortedSet[T] {
+	return &SortedSet[T]{
+		compare: compare,
+	}
+}
+
+// Add adds the given item, replacing the item it is the same as (if any).  Returns true if the item is new.
+func (s *SortedSet[T]) Add(item T) bool {
+	i, found := s.search(item)
+	if found {
+		s.items[i] = item
+		return false
+	}
+	var zero T
+	s.items = append(s.items, zero)
+	copy(s.items[i+1:], s.items[i:])
+	s.items[i] = item
+	return true
+}
+
+// Remove removes the item the given item is the same as, returning false if there is none.
+func (s *SortedSet[T]) Remove(item T) bool {
+	i, found := s.search(item)
+	if !found {
+		return false
+	}
+	var zero T
+	copy(s.items[i:], s.items[i+1:])
+	s.items[len(s.items)-1] = zero
+	s.items = s.items[:len(s.items)-1]
+	return true
+}
+
+// Get returns the held item the given item is the same as, or false if there is none.
+func (s *SortedSet[T]) Get(item T) (T, bool) {
+	if i, found := s.search(item); found {
+		return s.items[i], true
+	}
+	var zero T
+	return zero, false
+}
+
+// Has returns true if an item the same as the given item is held.
+func (s *SortedSet[T]) Has(item T) bool {
+	_, found := s.search(item)
+	return found
+}
+
+// Index returns the position the given item has (or would have if added), and whether it is held.
+func (s *SortedSet[T]) Index(item T) (int, bool) {
+	return s.search(item)
+}
+
+// At returns the item at the given position, which must be less than Len().
+func (s *SortedSet[T]) At(i int) T {
+	return s.items[i]
+}
+
+// Len returns the number of items held.
+func (s *SortedSet[T]) Len() int {
+	return len(s.items)
+}
+
+// Clear removes every item.
+func (s *SortedSet[T]) Clear() {
+	clear(s.items)
+	s.items = s.items[:0]
+}
+
+// Items returns a copy of the items held, in order.
+func (s *SortedSet[T]) Items() []T {
+	return append([]T(nil), s.items...)
+}
+
+// Range calls fn with each item in order until fn returns false.  fn must not add or remove items.
+func (s *SortedSet[T]) Range(fn func(item T) bool) {
+	for _, item := range s.items {
+		if !fn(item) {
+			return
+		}
+	}
+}
+
+// RangeFrom is like Range, starting at the first item not sorting before the given item.
+func (s *SortedSet[T]) RangeFrom(from T, fn func(item T) bool) {
+	i, _ := s.search(from)
+	for _, item := range s.items[i:] {
+		if !fn(item) {
+			return
+		}
+	}
+}
+
+// RangeBetween is like Range, over the items from the first not sorting before from up to (but excluding) the first not
+// sorting before to.
+func (s *SortedSet[T]) RangeBetween(from, to T, fn func(item T) bool) {
+	i, _ := s.search(from)
+	j, _ := s.search(to)
+	for ; i < j; i++ {
+		if !fn(s.items[i]) {
+			return
+		}
+	}
+}
+
+// Descend calls fn with each item in reverse order until fn returns false.  fn must not add or remove items.
+func (s *SortedSet[T]) Descend(fn func(item T) bool) {
+	for i := len(s.items) - 1; i >= 0; i-- {
+		if !fn(s.items[i]) {
+			return
+		}
+	}
+}
+
+func (s *SortedSet[T]) search(item T) (int, bool) {
+	i := sort.Search(len(s.items), func(i int) bool {
+		return s.compare(s.items[i], item) >= 0
+	})
+	return i, i < len(s.items) && s.compare(s.items[i], item) == 0
+}
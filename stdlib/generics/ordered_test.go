@@ -0,0 +1,110 @@
+package generics
+
+import (
+	"cmp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderedMap(t *testing.T) {
+	var m OrderedMap[string, int]
+	require.False(t, m.Has("a"))
+	require.True(t, m.Set("c", 1))
+	require.True(t, m.Set("a", 2))
+	require.True(t, m.Set("b", 3))
+
+	// Setting an existing key leaves its order unchanged
+	require.False(t, m.Set("c", 4))
+	require.Equal(t, []string{"c", "a", "b"}, m.Keys())
+	require.Equal(t, []int{4, 2, 3}, m.Values())
+	val, ok := m.Get("c")
+	require.True(t, ok)
+	require.Equal(t, 4, val)
+
+	// A key deleted and set again goes last
+	require.True(t, m.Delete("c"))
+	require.False(t, m.Delete("c"))
+	m.Set("c", 5)
+	require.Equal(t, []string{"a", "b", "c"}, m.Keys())
+
+	// Range may delete the entry it is at, and stops when fn returns false
+	var seen []string
+	m.Range(func(key string, val int) bool {
+		seen = append(seen, key)
+		if key == "b" {
+			m.Delete(key)
+		}
+		return true
+	})
+	require.Equal(t, []string{"a", "b", "c"}, seen)
+	require.Equal(t, []string{"a", "c"}, m.Keys())
+	seen = nil
+	m.Range(func(key string, val int) bool {
+		seen = append(seen, key)
+		return false
+	})
+	require.Equal(t, []string{"a"}, seen)
+
+	m.Clear()
+	require.Zero(t, m.Len())
+	require.Empty(t, m.Keys())
+	m.Set("d", 6)
+	require.Equal(t, []string{"d"}, m.Keys())
+}
+
+func TestSortedSet(t *testing.T) {
+	// Items are the same if their names are, regardless of case
+	type item struct {
+		name string
+		val  int
+	}
+	set := NewSortedSet(func(a, b item) int {
+		return cmp.Compare(strings.ToLower(a.name), strings.ToLower(b.name))
+	})
+	names := func(fn func(func(item) bool)) []string {
+		var names []string
+		fn(func(it item) bool {
+			names = append(names, it.name)
+			return true
+		})
+		return names
+	}
+
+	for i, name := range []string{"delta", "alpha", "echo", "charlie", "bravo"} {
+		require.True(t, set.Add(item{name, i}))
+	}
+	require.False(t, set.Add(item{"Charlie", 10}))
+	require.Equal(t, 5, set.Len())
+	require.Equal(t, []string{"alpha", "bravo", "Charlie", "delta", "echo"}, names(set.Range))
+	got, ok := set.Get(item{name: "CHARLIE"})
+	require.True(t, ok)
+	require.Equal(t, 10, got.val)
+
+	// Ranges start at the first item not sorting before from and end before to
+	require.Equal(t, []string{"Charlie", "delta", "echo"}, names(func(fn func(item) bool) {
+		set.RangeFrom(item{name: "c"}, fn)
+	}))
+	require.Equal(t, []string{"bravo", "Charlie"}, names(func(fn func(item) bool) {
+		set.RangeBetween(item{name: "bravo"}, item{name: "delta"}, fn)
+	}))
+	require.Empty(t, names(func(fn func(item) bool) {
+		set.RangeBetween(item{name: "foxtrot"}, item{name: "zulu"}, fn)
+	}))
+	require.Equal(t, []string{"echo", "delta", "Charlie", "bravo", "alpha"}, names(set.Descend))
+
+	i, ok := set.Index(item{name: "cat"})
+	require.False(t, ok)
+	require.Equal(t, 2, i)
+	require.Equal(t, "Charlie", set.At(i).name)
+
+	require.True(t, set.Remove(item{name: "alpha"}))
+	require.False(t, set.Remove(item{name: "alpha"}))
+	require.False(t, set.Has(item{name: "alpha"}))
+	require.Equal(t, "bravo", set.At(0).name)
+	require.Len(t, set.Items(), 4)
+
+	set.Clear()
+	require.Zero(t, set.Len())
+}
@@ -0,0 +1,140 @@
+// Package geo offers the geometry of locations on the Earth -- points, bounding boxes, and radii -- and an RTree indexing
+// items by their bounds, so that the items within a region can be found without visiting every item.
+package geo
+
+import (
+	"fmt"
+	"math"
+)
+
+// EarthRadius is the mean radius of the Earth in meters, as used by Distance.
+const EarthRadius = 6371008.8
+
+// Point is a location on the Earth in degrees.
+type Point struct {
+	Lat float64 // degrees, positive north, in [-90, 90]
+	Lon float64 // degrees, positive east, in [-180, 180]
+}
+
+// Valid returns true if the point's latitude and longitude are within range.
+func (p Point) Valid() bool {
+	return p.Lat >= -90 && p.Lat <= 90 && p.Lon >= -180 && p.Lon <= 180
+}
+
+func (p Point) String() string {
+	return fmt.Sprintf("%.6f,%.6f", p.Lat, p.Lon)
+}
+
+// Distance returns the great-circle distance between two points in meters (via the haversine formula).
+func Distance(a, b Point) float64 {
+	lat1, lat2 := radians(a.Lat), radians(b.Lat)
+	dLat, dLon := lat2-lat1, radians(b.Lon-a.Lon)
+	h := sq(math.Sin(dLat/2)) + math.Cos(lat1)*math.Cos(lat2)*sq(math.Sin(dLon/2))
+	return 2 * EarthRadius * math.Asin(math.Sqrt(min(h, 1)))
+}
+
+// Region is an area of the Earth's surface.
+type Region interface {
+	Contains(p Point) bool
+
+	// Returns the smallest Rect containing the region.
+	Bounds() Rect
+}
+
+// Rect is the area between two parallels and two meridians, where a Rect whose West is greater than its East spans the
+// antimeridian (e.g. a box around Fiji).
+type Rect struct {
+	South, West, North, East float64 // degrees
+}
+
+// Contains returns true if the given point is within the rect (inclusive of its edges).
+func (r Rect) Contains(p Point) bool {
+	if p.Lat < r.South || p.Lat > r.North {
+		return false
+	}
+	if r.West <= r.East {
+		return p.Lon >= r.West && p.Lon <= r.East
+	}
+	return p.Lon >= r.West || p.Lon <= r.East
+}
+
+func (r Rect) Bounds() Rect {
+	return r
+}
+
+// Valid returns true if the rect's edges are within range, with its south no further north than its north.
+func (r Rect) Valid() bool {
+	return Point{r.South, r.West}.Valid() && Point{r.North, r.East}.Valid() && r.South <= r.North
+}
+
+// Center returns the point midway between the rect's edges.
+func (r Rect) Center() Point {
+	width := r.East - r.West
+	if width < 0 {
+		width += 360
+	}
+	return Point{
+		Lat: (r.South + r.North) / 2,
+		Lon: normalizeLon(r.West + width/2),
+	}
+}
+
+// PointRect returns the rect bounding just the given point.
+func PointRect(p Point) Rect {
+	return Rect{South: p.Lat, West: p.Lon, North: p.Lat, East: p.Lon}
+}
+
+// Circle is the area within a given distance of a point.
+type Circle struct {
+	Center Point
+	Radius float64 // meters
+}
+
+func (c Circle) Contains(p Point) bool {
+	return Distance(c.Center, p) <= c.Radius
+}
+
+// Bounds returns the smallest Rect containing the circle, which spans all longitudes if the circle contains a pole.
+func (c Circle) Bounds() Rect {
+	angle := c.Radius / EarthRadius // radians
+	lat := radians(c.Center.Lat)
+	r := Rect{
+		South: degrees(lat - angle),
+		North: degrees(lat + angle),
+	}
+	if r.South <= -90 || r.North >= 90 || angle >= math.Pi {
+		r.South, r.North = max(r.South, -90), min(r.North, 90)
+		r.West, r.East = -180, 180
+		return r
+	}
+	dLon := degrees(math.Asin(math.Sin(angle) / math.Cos(lat)))
+	r.West, r.East = normalizeLon(c.Center.Lon-dLon), normalizeLon(c.Center.Lon+dLon)
+	return r
+}
+
+// normalizeLon returns the given longitude wrapped into [-180, 180].
+func normalizeLon(lon float64) float64 {
+	if lon < -180 || lon > 180 {
+		lon = math.Mod(lon+180, 360)
+		if lon < 0 {
+			lon += 360
+		}
+		lon -= 180
+	}
+	return lon
+}
+
+// planar returns the rect as one or two rects not spanning the antimeridian.
+func (r Rect) planar() []Rect {
+	if r.West <= r.East {
+		return []Rect{r}
+	}
+	return []Rect{
+		{South: r.South, West: r.West, North: r.North, East: 180},
+		{South: r.South, West: -180, North: r.North, East: r.East},
+	}
+}
+
+func radians(deg float64) float64 { return deg * math.Pi / 180 }
+func degrees(rad float64) float64 { return rad * 180 / math.Pi }
+func sq(x float64) float64        { return x * x }
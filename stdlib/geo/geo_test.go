@@ -0,0 +1,126 @@
+package geo
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	london = Point{51.5074, -0.1278}
+	paris  = Point{48.8566, 2.3522}
+	suva   = Point{-18.1416, 178.4419}
+)
+
+func TestDistance(t *testing.T) {
+	require.InDelta(t, 343_500, Distance(london, paris), 1000)
+	require.Zero(t, Distance(paris, paris))
+	require.InDelta(t, EarthRadius*3.14159265, Distance(Point{0, 0}, Point{0, 180}), 1)
+
+	// Crossing the antimeridian is as short as it looks
+	require.InDelta(t, 2*111_195, Distance(Point{0, 179}, Point{0, -179}), 100)
+}
+
+func TestRegions(t *testing.T) {
+	europe := Rect{South: 35, West: -10, North: 60, East: 30}
+	require.True(t, europe.Valid())
+	require.True(t, europe.Contains(london))
+	require.False(t, europe.Contains(suva))
+
+	// A rect around Fiji spans the antimeridian
+	fiji := Rect{South: -21, West: 176, North: -12, East: -178}
+	require.True(t, fiji.Contains(suva))
+	require.True(t, fiji.Contains(Point{-16, -179}))
+	require.False(t, fiji.Contains(Point{-16, 170}))
+	require.False(t, Rect{South: 10, North: -10}.Valid())
+	require.Equal(t, Point{-16.5, 179}, fiji.Center())
+	require.Equal(t, Point{47.5, 10}, europe.Center())
+
+	// A circle's bounds contain it
+	near := Circle{Center: london, Radius: 400_000}
+	require.True(t, near.Contains(paris))
+	require.False(t, Circle{Center: london, Radius: 300_000}.Contains(paris))
+	bounds := near.Bounds()
+	require.True(t, bounds.Contains(paris))
+	require.InDelta(t, 51.5074-3.597, bounds.South, 0.01)
+	require.Less(t, bounds.West, -5.0)
+
+	// ... including across the antimeridian
+	bounds = Circle{Center: suva, Radius: 500_000}.Bounds()
+	require.Greater(t, bounds.West, bounds.East)
+	require.True(t, bounds.Contains(Point{-18, -177}))
+
+	// ... and spanning all longitudes around a pole
+	bounds = Circle{Center: Point{88, 0}, Radius: 500_000}.Bounds()
+	require.Equal(t, Rect{South: bounds.South, West: -180, North: 90, East: 180}, bounds)
+	require.True(t, Circle{Center: Point{88, 0}, Radius: 500_000}.Contains(Point{88, 180}))
+}
+
+func TestRTree(t *testing.T) {
+	rnd := rand.New(rand.NewSource(7))
+	tree := NewRTree[int]()
+	points := make(map[int]Point)
+	for i := 0; i < 2000; i++ {
+		p := Point{Lat: rnd.Float64()*180 - 90, Lon: rnd.Float64()*360 - 180}
+		points[i] = p
+		tree.Insert(PointRect(p), i)
+	}
+	require.Equal(t, 2000, tree.Len())
+
+	// Remove every third point
+	for i := 0; i < 2000; i += 3 {
+		require.True(t, tree.Delete(PointRect(points[i]), i))
+		delete(points, i)
+	}
+	require.False(t, tree.Delete(PointRect(Point{1, 1}), 0))
+	require.Equal(t, len(points), tree.Len())
+
+	search := func(r Rect) []int {
+		var found []int
+		tree.Search(r, func(bounds Rect, i int) bool {
+			require.Equal(t, PointRect(points[i]), bounds)
+			found = append(found, i)
+			return true
+		})
+		sort.Ints(found)
+		return found
+	}
+	brute := func(r Rect) []int {
+		var found []int
+		for i, p := range points {
+			if r.Contains(p) {
+				found = append(found, i)
+			}
+		}
+		sort.Ints(found)
+		return found
+	}
+	for _, r := range []Rect{
+		{South: -90, West: -180, North: 90, East: 180},
+		{South: 10, West: 20, North: 40, East: 60},
+		{South: -30, West: 150, North: 30, East: -150},
+		{South: 5, West: 5, North: 5, East: 5},
+	} {
+		require.Equal(t, brute(r), search(r), r)
+	}
+
+	// Searching stops when asked
+	count := 0
+	tree.Search(Rect{South: -90, West: -180, North: 90, East: 180}, func(Rect, int) bool {
+		count++
+		return count < 10
+	})
+	require.Equal(t, 10, count)
+
+	// Removing everything leaves an empty tree that still works
+	for i, p := range points {
+		require.True(t, tree.Delete(PointRect(p), i))
+	}
+	require.Zero(t, tree.Len())
+	require.Empty(t, search(Rect{South: -90, West: -180, North: 90, East: 180}))
+	points = map[int]Point{1: paris}
+	tree.Insert(PointRect(paris), 1)
+	require.Equal(t, []int{1}, search(Rect{South: 40, West: 0, North: 50, East: 10}))
+}
@@ -0,0 +1,270 @@
+package geo
+
+import "math"
+
+// R-tree node capacity
+const (
+	rtreeMaxEntries = 16
+	rtreeMinEntries = rtreeMaxEntries * 2 / 5
+)
+
+// RTree indexes items by their bounds (as in Guttman's R-tree with quadratic splits), finding those intersecting a rect in
+// time proportional to the log of the number of items.  The bounds of an item are taken to not span the antimeridian
+// (as for the bounds of a point), whereas a rect searched for may.
+//
+// An RTree is not safe for concurrent use.
+type RTree[T comparable] struct {
+	root *rnode[T]
+	size int
+}
+
+type rnode[T comparable] struct {
+	leaf    bool
+	entries []rentry[T]
+}
+
+type rentry[T comparable] struct {
+	bounds Rect
+	child  *rnode[T] // for an entry of an inner node
+	val    T         // for an entry of a leaf
+}
+
+// NewRTree returns an empty RTree.
+func NewRTree[T comparable]() *RTree[T] {
+	return &RTree[T]{
+		root: &rnode[T]{leaf: true},
+	}
+}
+
+// Len returns the number of items in the tree.
+func (tree *RTree[T]) Len() int {
+	return tree.size
+}
+
+// Insert adds the given item with the given bounds.  An item may be added more than once (e.g. with other bounds).
+func (tree *RTree[T]) Insert(bounds Rect, val T) {
+	tree.insert(rentry[T]{bounds: bounds, val: val}, 0)
+	tree.size++
+}
+
+// insert adds the given entry to a node at the given height above the leaves.
+func (tree *RTree[T]) insert(ent rentry[T], height int) {
+	if split := tree.root.insert(ent, height, tree.height()); split != nil {
+		tree.root = &rnode[T]{entries: []rentry[T]{
+			{bounds: tree.root.bounds(), child: tree.root},
+			{bounds: split.bounds(), child: split},
+		}}
+	}
+}
+
+// height returns the height of the root above the leaves.
+func (tree *RTree[T]) height() int {
+	height := 0
+	for n := tree.root; !n.leaf; n = n.entries[0].child {
+		height++
+	}
+	return height
+}
+
+// insert adds the entry within this node (at the given height), returning the node split off from this one if it overflowed.
+func (n *rnode[T]) insert(ent rentry[T], height, level int) *rnode[T] {
+	if level == height {
+		n.entries = append(n.entries, ent)
+	} else {
+		i := n.chooseSubtree(ent.bounds)
+		child := &n.entries[i]
+		split := child.child.insert(ent, height, level-1)
+		child.bounds = child.child.bounds()
+		if split != nil {
+			n.entries = append(n.entries, rentry[T]{bounds: split.bounds(), child: split})
+		}
+	}
+	if len(n.entries) > rtreeMaxEntries {
+		return n.split()
+	}
+	return nil
+}
+
+// chooseSubtree returns the entry whose bounds need the least enlargement to include the given bounds (the smallest if tied).
+func (n *rnode[T]) chooseSubtree(bounds Rect) int {
+	best, bestGrowth, bestArea := 0, math.Inf(1), math.Inf(1)
+	for i := range n.entries {
+		size := area(n.entries[i].bounds)
+		growth := area(union(n.entries[i].bounds, bounds)) - size
+		if growth < bestGrowth || growth == bestGrowth && size < bestArea {
+			best, bestGrowth, bestArea = i, growth, size
+		}
+	}
+	return best
+}
+
+// split moves about half of this node's entries into a new node (via Guttman's quadratic split), returning the new node.
+func (n *rnode[T]) split() *rnode[T] {
+	entries := n.entries
+
+	// Seed each group with the pair of entries that would waste the most area together
+	seedA, seedB, worst := 0, 1, math.Inf(-1)
+	for i := range entries {
+		for j := i + 1; j < len(entries); j++ {
+			waste := area(union(entries[i].bounds, entries[j].bounds)) - area(entries[i].bounds) - area(entries[j].bounds)
+			if waste > worst {
+				seedA, seedB, worst = i, j, waste
+			}
+		}
+	}
+	groupA := []rentry[T]{entries[seedA]}
+	groupB := []rentry[T]{entries[seedB]}
+	boundsA, boundsB := entries[seedA].bounds, entries[seedB].bounds
+
+	rest := make([]rentry[T], 0, len(entries)-2)
+	for i := range entries {
+		if i != seedA && i != seedB {
+			rest = append(rest, entries[i])
+		}
+	}
+	for len(rest) > 0 {
+		// Ensure each group gets the minimum number of entries
+		if len(groupA)+len(rest) == rtreeMinEntries {
+			groupA = append(groupA, rest...)
+			break
+		}
+		if len(groupB)+len(rest) == rtreeMinEntries {
+			groupB = append(groupB, rest...)
+			break
+		}
+
+		// Assign next the entry having the greatest preference for one group
+		next, nextDiff := 0, math.Inf(-1)
+		for i := range rest {
+			growA := area(union(boundsA, rest[i].bounds)) - area(boundsA)
+			growB := area(union(boundsB, rest[i].bounds)) - area(boundsB)
+			if diff := math.Abs(growA - growB); diff > nextDiff {
+				next, nextDiff = i, diff
+			}
+		}
+		ent := rest[next]
+		rest[next] = rest[len(rest)-1]
+		rest = rest[:len(rest)-1]
+
+		growA := area(union(boundsA, ent.bounds)) - area(boundsA)
+		growB := area(union(boundsB, ent.bounds)) - area(boundsB)
+		if growA < growB || growA == growB && len(groupA) <= len(groupB) {
+			groupA, boundsA = append(groupA, ent), union(boundsA, ent.bounds)
+		} else {
+			groupB, boundsB = append(groupB, ent), union(boundsB, ent.bounds)
+		}
+	}
+
+	n.entries = groupA
+	return &rnode[T]{leaf: n.leaf, entries: groupB}
+}
+
+// Delete removes the given item having the given bounds (as inserted), returning false if not found.
+func (tree *RTree[T]) Delete(bounds Rect, val T) bool {
+	var orphans []orphan[T]
+	if !tree.root.delete(bounds, val, tree.height(), &orphans) {
+		return false
+	}
+	tree.size--
+	for !tree.root.leaf && len(tree.root.entries) == 1 {
+		tree.root = tree.root.entries[0].child
+	}
+	if len(tree.root.entries) == 0 {
+		tree.root = &rnode[T]{leaf: true}
+	}
+
+	// Entries of nodes left underfull are inserted again at their height
+	for _, o := range orphans {
+		tree.insert(o.entry, o.height)
+	}
+	return true
+}
+
+type orphan[T comparable] struct {
+	entry  rentry[T]
+	height int
+}
+
+// delete removes the item from within this node (at the given height), adding the entries of any descendants left
+// underfull to orphans, returning false if not found.
+func (n *rnode[T]) delete(bounds Rect, val T, height int, orphans *[]orphan[T]) bool {
+	if n.leaf {
+		for i := range n.entries {
+			if ent := &n.entries[i]; ent.val == val && ent.bounds == bounds {
+				n.entries = append(n.entries[:i], n.entries[i+1:]...)
+				return true
+			}
+		}
+		return false
+	}
+	for i := range n.entries {
+		ent := &n.entries[i]
+		if !intersects(ent.bounds, bounds) || !ent.child.delete(bounds, val, height-1, orphans) {
+			continue
+		}
+		if len(ent.child.entries) < rtreeMinEntries {
+			for _, sub := range ent.child.entries {
+				*orphans = append(*orphans, orphan[T]{sub, height - 1})
+			}
+			n.entries = append(n.entries[:i], n.entries[i+1:]...)
+		} else {
+			ent.bounds = ent.child.bounds()
+		}
+		return true
+	}
+	return false
+}
+
+// Search calls fn with each item whose bounds intersect the given rect (which may span the antimeridian), in no particular
+// order, until fn returns false.
+func (tree *RTree[T]) Search(within Rect, fn func(bounds Rect, val T) bool) {
+	for _, r := range within.planar() {
+		if !tree.root.search(r, fn) {
+			return
+		}
+	}
+}
+
+func (n *rnode[T]) search(within Rect, fn func(bounds Rect, val T) bool) bool {
+	for i := range n.entries {
+		ent := &n.entries[i]
+		if !intersects(ent.bounds, within) {
+			continue
+		}
+		if n.leaf {
+			if !fn(ent.bounds, ent.val) {
+				return false
+			}
+		} else if !ent.child.search(within, fn) {
+			return false
+		}
+	}
+	return true
+}
+
+func (n *rnode[T]) bounds() Rect {
+	b := n.entries[0].bounds
+	for _, ent := range n.entries[1:] {
+		b = union(b, ent.bounds)
+	}
+	return b
+}
+
+// Planar operations on rects not spanning the antimeridian
+
+func union(a, b Rect) Rect {
+	return Rect{
+		South: min(a.South, b.South),
+		West:  min(a.West, b.West),
+		North: max(a.North, b.North),
+		East:  max(a.East, b.East),
+	}
+}
+
+func intersects(a, b Rect) bool {
+	return a.South <= b.North && b.South <= a.North && a.West <= b.East && b.West <= a.East
+}
+
+func area(r Rect) float64 {
+	return (r.North - r.South) * (r.East - r.West)
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"flag"
 	"fmt"
+	"log/slog"
 	"os"
 	"os/signal"
 	"strings"
@@ -42,6 +43,20 @@ type Logger interface {
 	Fatalf(inFormat string, args ...interface{})
 }
 
+// Slogger is optionally implemented by a Logger that can also log via log/slog (see SlogOf).
+type Slogger interface {
+	Slog() *slog.Logger
+}
+
+// SlogOf returns a slog.Logger that logs through the given Logger: its own if it implements Slogger,
+// or else one that logs each record via the Logger's leveled methods (e.g. Warnw).
+func SlogOf(l Logger) *slog.Logger {
+	if sl, ok := l.(Slogger); ok {
+		return sl.Slog()
+	}
+	return slog.New(&loggerHandler{l: l})
+}
+
 func InitFlags(flagset *flag.FlagSet) {
 	klog.InitFlags(flagset)
 }
@@ -58,7 +73,11 @@ func UseFormatter(inFormatter Formatter) {
 	klog.SetFormatter(inFormatter)
 }
 
+// Flush flushes klog and the sinks of the Handler set via SetDefault (if any).
 func Flush() {
+	if h := gHandler.Load(); h != nil {
+		h.Flush()
+	}
 	klog.Flush()
 }
 
@@ -83,7 +102,14 @@ func (l *logger) Padding() string {
 }
 
 // NewLogger creates and inits a new Logger with the given label.
-func NewLogger(label string) Logger {
+//
+// If a Handler has been set via SetDefault, the returned Logger logs through it, with the label as its ModuleKey attr (unless
+// attrs gives one) along with the given attrs (alternating keys and values, as for slog.Logger.With).  Otherwise it logs through
+// klog, where entries are prefixed by the label and attrs are omitted.
+func NewLogger(label string, attrs ...any) Logger {
+	if h := gHandler.Load(); h != nil {
+		return newStructuredLogger(h, label, attrs)
+	}
 	l := &logger{}
 	if label != "" {
 		l.SetLogLabel(label)
@@ -136,6 +162,14 @@ func (l *logger) GetLogPrefix() string {
 	return l.logPrefix
 }
 
+// Slog returns a slog.Logger that logs through klog, prefixed by this Logger's label.
+func (l *logger) Slog() *slog.Logger {
+	if !l.hasPrefix {
+		return slog.New(&klogHandler{})
+	}
+	return slog.New(&klogHandler{prefix: l.logPrefix})
+}
+
 // LogV returns true if logging is currently enabled for log verbose level.
 func (l *logger) LogV(inVerboseLevel int32) bool {
 	return bool(klog.V(klog.Level(inVerboseLevel)))
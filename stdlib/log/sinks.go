@@ -0,0 +1,520 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// AppendText appends the given entry as a line of text, e.g.
+//
+//	2024-05-01 12:00:00.000 WARN  amp.session host/sessions/ctx_12: pin closed err="timed out" pin=3
+func AppendText(buf []byte, e *Entry, useColor bool) []byte {
+	b := bytes.NewBuffer(buf)
+	b.WriteString(e.Time.Format("2006-01-02 15:04:05.000"))
+	b.WriteByte(' ')
+
+	name := LevelName(e.Level)
+	if useColor {
+		b.WriteString(levelColor(e.Level))
+	}
+	b.WriteString(name)
+	if useColor {
+		b.WriteString("\x1b[0m")
+	}
+	for i := len(name); i < 5; i++ {
+		b.WriteByte(' ')
+	}
+	if e.Module != "" {
+		b.WriteByte(' ')
+		b.WriteString(e.Module)
+	}
+	if e.Task != "" {
+		b.WriteByte(' ')
+		b.WriteString(e.Task)
+	}
+	if e.Module != "" || e.Task != "" {
+		b.WriteByte(':')
+	}
+	b.WriteByte(' ')
+	b.WriteString(e.Msg)
+
+	for _, attr := range e.Attrs {
+		b.WriteByte(' ')
+		appendTextAttr(b, attr)
+	}
+	b.WriteByte('\n')
+	return b.Bytes()
+}
+
+func levelColor(level slog.Level) string {
+	switch {
+	case level >= slog.LevelError:
+		return "\x1b[31m" // red
+	case level >= slog.LevelWarn:
+		return "\x1b[33m" // yellow
+	case level >= LevelSuccess:
+		return "\x1b[32m" // green
+	case level >= slog.LevelInfo:
+		return "\x1b[36m" // cyan
+	}
+	return "\x1b[90m" // gray
+}
+
+func appendTextAttr(b *bytes.Buffer, attr slog.Attr) {
+	b.WriteString(attr.Key)
+	b.WriteByte('=')
+	var str string
+	switch v := attr.Value; v.Kind() {
+	case slog.KindString:
+		str = v.String()
+	case slog.KindTime:
+		str = v.Time().Format(time.RFC3339Nano)
+	default:
+		if err, isErr := v.Any().(error); isErr {
+			str = err.Error()
+		} else {
+			str = v.String()
+		}
+	}
+	if str == "" || strings.ContainsAny(str, " \t\r\n\"=") || !strconv.CanBackquote(str) {
+		str = strconv.Quote(str)
+	}
+	b.WriteString(str)
+}
+
+// AppendJSON appends the given entry as a line of JSON, where attrs are top-level fields, e.g.
+//
+//	{"time":"2024-05-01T12:00:00Z","level":"WARN","module":"amp.session","task":"host/sessions/ctx_12","msg":"pin closed","pin":3}
+func AppendJSON(buf []byte, e *Entry) []byte {
+	b := bytes.NewBuffer(buf)
+	b.WriteString(`{"time":`)
+	writeJSON(b, e.Time.Format(time.RFC3339Nano))
+	b.WriteString(`,"level":`)
+	writeJSON(b, LevelName(e.Level))
+	if e.Module != "" {
+		b.WriteString(`,"module":`)
+		writeJSON(b, e.Module)
+	}
+	if e.Task != "" {
+		b.WriteString(`,"task":`)
+		writeJSON(b, e.Task)
+	}
+	b.WriteString(`,"msg":`)
+	writeJSON(b, e.Msg)
+	for _, attr := range e.Attrs {
+		b.WriteByte(',')
+		writeJSON(b, attr.Key)
+		b.WriteByte(':')
+		switch v := attr.Value; v.Kind() {
+		case slog.KindString:
+			writeJSON(b, v.String())
+		case slog.KindInt64:
+			b.WriteString(strconv.FormatInt(v.Int64(), 10))
+		case slog.KindUint64:
+			b.WriteString(strconv.FormatUint(v.Uint64(), 10))
+		case slog.KindBool:
+			b.WriteString(strconv.FormatBool(v.Bool()))
+		case slog.KindDuration:
+			writeJSON(b, v.Duration().String())
+		case slog.KindTime:
+			writeJSON(b, v.Time().Format(time.RFC3339Nano))
+		default:
+			if err, isErr := v.Any().(error); isErr {
+				writeJSON(b, err.Error())
+			} else {
+				writeJSON(b, v.Any())
+			}
+		}
+	}
+	b.WriteString("}\n")
+	return b.Bytes()
+}
+
+func writeJSON(b *bytes.Buffer, v any) {
+	out, err := json.Marshal(v)
+	if err != nil {
+		out, _ = json.Marshal(fmt.Sprint(v))
+	}
+	b.Write(out)
+}
+
+// NewConsoleSink returns a Sink writing entries as text (see AppendText) to the given writer, typically os.Stderr.
+func NewConsoleSink(w io.Writer, useColor bool) Sink {
+	return &consoleSink{
+		w:        w,
+		useColor: useColor,
+	}
+}
+
+type consoleSink struct {
+	mu       sync.Mutex
+	w        io.Writer
+	useColor bool
+	buf      []byte
+}
+
+func (s *consoleSink) WriteEntry(e *Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.buf = AppendText(s.buf[:0], e, s.useColor)
+	_, err := s.w.Write(s.buf)
+	return err
+}
+
+func (s *consoleSink) Close() error {
+	return nil
+}
+
+// DefaultTailLines is the number of entries a TailSink retains by default.
+const DefaultTailLines = 1000
+
+// TailSink is a Sink retaining the most recent entries as lines of text (see AppendText), e.g. to be included in a
+// diagnostics bundle -- concurrency safe.
+type TailSink struct {
+	mu    sync.Mutex
+	lines [][]byte // ring of retained lines
+	next  int      // index of the oldest line once the ring is full
+	full  bool
+}
+
+// NewTailSink returns a TailSink retaining the last n entries (default DefaultTailLines).
+func NewTailSink(n int) *TailSink {
+	if n <= 0 {
+		n = DefaultTailLines
+	}
+	return &TailSink{
+		lines: make([][]byte, 0, n),
+	}
+}
+
+func (s *TailSink) WriteEntry(e *Entry) error {
+	line := AppendText(nil, e, false)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.full {
+		s.lines = append(s.lines, line)
+		s.full = len(s.lines) == cap(s.lines)
+		return nil
+	}
+	s.lines[s.next] = line
+	s.next = (s.next + 1) % len(s.lines)
+	return nil
+}
+
+// WriteTo writes the retained lines to w, oldest first.
+func (s *TailSink) WriteTo(w io.Writer) (int64, error) {
+	s.mu.Lock()
+	lines := make([][]byte, 0, len(s.lines))
+	lines = append(lines, s.lines[s.next:]...)
+	lines = append(lines, s.lines[:s.next]...)
+	s.mu.Unlock()
+
+	var total int64
+	for _, line := range lines {
+		n, err := w.Write(line)
+		total += int64(n)
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+func (s *TailSink) Close() error {
+	return nil
+}
+
+// RotatingFileOpts configures a RotatingFile.
+type RotatingFileOpts struct {
+	Path       string // file entries are appended to
+	MaxSize    int64  // size that once exceeded, rotates the file (default 64 MiB)
+	MaxBackups int    // rotated files kept as Path.1 (the most recent) through Path.{MaxBackups} (default 5)
+	JSON       bool   // if set, entries are written as JSON (see AppendJSON) rather than text
+}
+
+// RotatingFile is a Sink appending entries to a file that is rotated once it exceeds a given size -- concurrency safe.
+type RotatingFile struct {
+	opts RotatingFileOpts
+
+	mu     sync.Mutex
+	file   *os.File // nil if closed or the last rotation failed to reopen
+	closed bool
+	size   int64
+	buf    []byte
+}
+
+// NewRotatingFile opens (or creates) the file given by opts.Path for appending entries.
+func NewRotatingFile(opts RotatingFileOpts) (*RotatingFile, error) {
+	if opts.MaxSize <= 0 {
+		opts.MaxSize = 64 << 20
+	}
+	if opts.MaxBackups <= 0 {
+		opts.MaxBackups = 5
+	}
+	if err := os.MkdirAll(filepath.Dir(opts.Path), 0700); err != nil {
+		return nil, err
+	}
+	s := &RotatingFile{
+		opts: opts,
+	}
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *RotatingFile) open() error {
+	file, err := os.OpenFile(s.opts.Path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	s.file = file
+	s.size = info.Size()
+	return nil
+}
+
+// Rotate closes the current file, shifts it and the existing backups down by one (discarding the oldest), and opens a new file.
+func (s *RotatingFile) Rotate() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return os.ErrClosed
+	}
+	return s.rotate()
+}
+
+func (s *RotatingFile) rotate() error {
+	if s.file != nil {
+		s.file.Close()
+		s.file = nil
+	}
+	backup := func(i int) string {
+		return s.opts.Path + "." + strconv.Itoa(i)
+	}
+	os.Remove(backup(s.opts.MaxBackups))
+	for i := s.opts.MaxBackups - 1; i >= 1; i-- {
+		os.Rename(backup(i), backup(i+1))
+	}
+	if err := os.Rename(s.opts.Path, backup(1)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return s.open()
+}
+
+func (s *RotatingFile) WriteEntry(e *Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.opts.JSON {
+		s.buf = AppendJSON(s.buf[:0], e)
+	} else {
+		s.buf = AppendText(s.buf[:0], e, false)
+	}
+	if s.closed {
+		return os.ErrClosed
+	}
+	if s.file == nil || (s.size > 0 && s.size+int64(len(s.buf)) > s.opts.MaxSize) {
+		if err := s.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := s.file.Write(s.buf)
+	s.size += int64(n)
+	return err
+}
+
+func (s *RotatingFile) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		return nil
+	}
+	return s.file.Sync()
+}
+
+func (s *RotatingFile) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
+
+// RemoteOpts configures a RemoteSink.
+type RemoteOpts struct {
+	URL           string        // entries are POSTed here in batches as newline-delimited JSON (see AppendJSON)
+	Header        http.Header   // added to each request (e.g. Authorization)
+	Client        *http.Client  // default http.DefaultClient
+	BatchSize     int           // max entries per request (default 100)
+	FlushInterval time.Duration // max time an entry is held before it is sent (default 1s)
+	QueueSize     int           // max entries held while unsent, beyond which entries are dropped (default 10000)
+}
+
+// RemoteSink is a Sink sending entries to a log collector over HTTP -- concurrency safe.
+//
+// Entries are queued and sent in batches by a background goroutine so that logging never waits on the network.  Entries that
+// overflow the queue or whose batch fails to send are dropped and counted (see Dropped).
+type RemoteSink struct {
+	opts    RemoteOpts
+	queue   chan []byte
+	flush   chan chan error
+	closing chan struct{}
+	done    chan struct{}
+	once    sync.Once
+	dropped atomic.Uint64
+}
+
+// NewRemoteSink returns a RemoteSink sending entries to opts.URL until closed.
+func NewRemoteSink(opts RemoteOpts) *RemoteSink {
+	if opts.Client == nil {
+		opts.Client = http.DefaultClient
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 100
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = time.Second
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = 10000
+	}
+	s := &RemoteSink{
+		opts:    opts,
+		queue:   make(chan []byte, opts.QueueSize),
+		flush:   make(chan chan error),
+		closing: make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+// Dropped returns the number of entries discarded because the queue was full or they failed to send.
+func (s *RemoteSink) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+func (s *RemoteSink) WriteEntry(e *Entry) error {
+	select {
+	case s.queue <- AppendJSON(nil, e):
+	default:
+		s.dropped.Add(1)
+	}
+	return nil
+}
+
+// Flush sends all queued entries, returning once they are sent (or failed to send).
+func (s *RemoteSink) Flush() error {
+	reply := make(chan error, 1)
+	select {
+	case s.flush <- reply:
+		return <-reply
+	case <-s.done:
+		return nil
+	}
+}
+
+// Close sends all queued entries and stops the background goroutine.
+func (s *RemoteSink) Close() error {
+	s.once.Do(func() {
+		close(s.closing)
+	})
+	<-s.done
+	return nil
+}
+
+func (s *RemoteSink) run() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.opts.FlushInterval)
+	defer ticker.Stop()
+
+	var batch [][]byte
+	send := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		err := s.send(batch)
+		if err != nil {
+			s.dropped.Add(uint64(len(batch)))
+		}
+		batch = batch[:0]
+		return err
+	}
+	drain := func() error {
+		var err error
+		for {
+			select {
+			case line := <-s.queue:
+				if batch = append(batch, line); len(batch) >= s.opts.BatchSize {
+					if sendErr := send(); sendErr != nil && err == nil {
+						err = sendErr
+					}
+				}
+			default:
+				if sendErr := send(); sendErr != nil && err == nil {
+					err = sendErr
+				}
+				return err
+			}
+		}
+	}
+
+	for {
+		select {
+		case line := <-s.queue:
+			if batch = append(batch, line); len(batch) >= s.opts.BatchSize {
+				send()
+			}
+		case <-ticker.C:
+			send()
+		case reply := <-s.flush:
+			reply <- drain()
+		case <-s.closing:
+			drain()
+			return
+		}
+	}
+}
+
+func (s *RemoteSink) send(batch [][]byte) error {
+	req, err := http.NewRequest(http.MethodPost, s.opts.URL, bytes.NewReader(bytes.Join(batch, nil)))
+	if err != nil {
+		return err
+	}
+	for key, vals := range s.opts.Header {
+		req.Header[key] = vals
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	resp, err := s.opts.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("log collector replied %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,721 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/brynbellomy/klog"
+)
+
+// Structured logging
+//
+// A Handler is a log/slog.Handler that filters entries by the module or task they are from (see Levels) and passes those enabled
+// to one or more Sinks (see NewConsoleSink, NewRotatingFile, and NewRemoteSink).  Once a host installs a Handler via SetDefault,
+// the Loggers subsequently issued by NewLogger -- including the Logger of every task.Context -- log through it, so each entry
+// carries the module and task path it is from:
+//
+//	levels := log.NewLevels(slog.LevelInfo)
+//	levels.Parse("info,amp.session=debug,host/sessions=warn")
+//	log.SetDefault(log.NewHandler(levels, log.NewConsoleSink(os.Stderr, true)))
+//	http.Handle("/debug/log-levels", levels)  // operators can then change verbosity at runtime
+
+// Attr keys given special treatment by a Handler.
+const (
+	ModuleKey = "module" // names the component an entry is from; filtered by Levels
+	TaskKey   = "task"   // the path of the task.Context an entry is from; filtered by Levels
+)
+
+// Levels beyond those defined by slog, used by the Logger methods of the same name.
+const (
+	LevelSuccess = slog.LevelInfo + 1
+	LevelFatal   = slog.LevelError + 4
+)
+
+// LevelName returns the name of the given level as it appears in entries (e.g. "INFO" or "DEBUG-2").
+func LevelName(level slog.Level) string {
+	switch level {
+	case LevelSuccess:
+		return "SUCCESS"
+	case LevelFatal:
+		return "FATAL"
+	}
+	return level.String()
+}
+
+// Levels holds the minimum level enabled for each module -- concurrency safe.
+//
+// A module is a name such as "amp.session" or a task path such as "host/sessions/ctx_12": a level set for a module also applies
+// to the modules under it, delimited by '.' or '/'.  Modules with no level set use the default level.
+type Levels struct {
+	mu      sync.RWMutex
+	def     slog.Level
+	modules map[string]slog.Level
+}
+
+// NewLevels returns Levels enabling the given level (and above) for all modules.
+func NewLevels(def slog.Level) *Levels {
+	return &Levels{
+		def:     def,
+		modules: make(map[string]slog.Level),
+	}
+}
+
+// SetDefault sets the level of modules with no level set.
+func (lv *Levels) SetDefault(level slog.Level) {
+	lv.mu.Lock()
+	lv.def = level
+	lv.mu.Unlock()
+}
+
+// Set sets the level of the given module and those under it.
+func (lv *Levels) Set(module string, level slog.Level) {
+	lv.mu.Lock()
+	lv.modules[module] = level
+	lv.mu.Unlock()
+}
+
+// Unset removes the level set for the given module, which then uses that of its nearest parent (or the default).
+func (lv *Levels) Unset(module string) {
+	lv.mu.Lock()
+	delete(lv.modules, module)
+	lv.mu.Unlock()
+}
+
+// Level returns the level in effect for an entry from the given modules (e.g. the module and task path of a Logger), which is
+// that of the most specific module set (or the default if none are set).
+func (lv *Levels) Level(modules ...string) slog.Level {
+	lv.mu.RLock()
+	defer lv.mu.RUnlock()
+
+	level, matched := lv.def, -1
+	for _, module := range modules {
+		for name := module; name != ""; name = parentModule(name) {
+			if len(name) <= matched {
+				break
+			}
+			if lvl, isSet := lv.modules[name]; isSet {
+				level, matched = lvl, len(name)
+				break
+			}
+		}
+	}
+	return level
+}
+
+func parentModule(module string) string {
+	if i := strings.LastIndexAny(module, "./"); i > 0 {
+		return module[:i]
+	}
+	return ""
+}
+
+// Parse replaces all levels with those given by spec, a comma-separated list of a default level and "module=level" pairs,
+// e.g. "info,amp.session=debug,host/sessions=warn".  Level names are those accepted by slog.Level.UnmarshalText (case-insensitive).
+func (lv *Levels) Parse(spec string) error {
+	def := slog.LevelInfo
+	modules := make(map[string]slog.Level)
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		module, name, hasModule := strings.Cut(item, "=")
+		if !hasModule {
+			module, name = "", item
+		}
+		level, err := parseLevel(name)
+		if err != nil {
+			return err
+		}
+		if module = strings.TrimSpace(module); module == "" {
+			def = level
+		} else {
+			modules[module] = level
+		}
+	}
+
+	lv.mu.Lock()
+	lv.def = def
+	lv.modules = modules
+	lv.mu.Unlock()
+	return nil
+}
+
+func parseLevel(name string) (slog.Level, error) {
+	name = strings.ToUpper(strings.TrimSpace(name))
+	switch name {
+	case "SUCCESS":
+		return LevelSuccess, nil
+	case "FATAL":
+		return LevelFatal, nil
+	}
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(name)); err != nil {
+		return 0, fmt.Errorf("bad log level %q", name)
+	}
+	return level, nil
+}
+
+// String returns these Levels in the form accepted by Parse, with modules in order.
+func (lv *Levels) String() string {
+	lv.mu.RLock()
+	defer lv.mu.RUnlock()
+
+	names := make([]string, 0, len(lv.modules))
+	for name := range lv.modules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString(strings.ToLower(LevelName(lv.def)))
+	for _, name := range names {
+		fmt.Fprintf(&b, ",%s=%s", name, strings.ToLower(LevelName(lv.modules[name])))
+	}
+	return b.String()
+}
+
+// ServeHTTP allows an operator to view (GET) or replace (PUT or POST, with a spec in the body) these Levels -- see Parse.
+func (lv *Levels) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+	case http.MethodPut, http.MethodPost:
+		spec, err := io.ReadAll(io.LimitReader(r.Body, 64<<10))
+		if err == nil {
+			err = lv.Parse(string(spec))
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprintln(w, lv.String())
+}
+
+// Entry is a log entry as passed to a Sink.
+type Entry struct {
+	Time   time.Time
+	Level  slog.Level
+	Module string // value of the ModuleKey attr (if any)
+	Task   string // value of the TaskKey attr (if any)
+	Msg    string
+	Attrs  []slog.Attr // all other attrs, where those within groups are flattened into "group.key" keys
+	PC     uintptr     // program counter of the call that logged this entry (or 0 if unknown)
+}
+
+// Sink is where a Handler writes the entries it enables.
+// WriteEntry is called concurrently from many goroutines, does not retain e, and should not block for long.
+type Sink interface {
+	WriteEntry(e *Entry) error
+	Close() error
+}
+
+// MinLevel returns a Sink that passes only entries at the given level or above to sink (e.g. so only warnings are sent remotely).
+func MinLevel(level slog.Leveler, sink Sink) Sink {
+	return &minLevelSink{sink, level}
+}
+
+type minLevelSink struct {
+	Sink
+	level slog.Leveler
+}
+
+func (s *minLevelSink) WriteEntry(e *Entry) error {
+	if e.Level < s.level.Level() {
+		return nil
+	}
+	return s.Sink.WriteEntry(e)
+}
+
+func (s *minLevelSink) Flush() error {
+	return flushSink(s.Sink)
+}
+
+func flushSink(sink Sink) error {
+	if flusher, ok := sink.(interface{ Flush() error }); ok {
+		return flusher.Flush()
+	}
+	return nil
+}
+
+// Handler is a slog.Handler that filters entries via Levels and writes them to Sinks -- concurrency safe.
+type Handler struct {
+	levels *Levels
+	sinks  *sinkSet // shared with all Handlers derived via WithAttrs and WithGroup
+	module string
+	task   string
+	attrs  []slog.Attr // flattened
+	group  string      // prefix of subsequent attr keys
+}
+
+type sinkSet struct {
+	mu    sync.RWMutex
+	sinks []Sink
+}
+
+var _ slog.Handler = (*Handler)(nil)
+
+// NewHandler returns a Handler filtering entries by the given Levels and writing them to the given sinks.
+func NewHandler(levels *Levels, sinks ...Sink) *Handler {
+	if levels == nil {
+		levels = NewLevels(slog.LevelInfo)
+	}
+	return &Handler{
+		levels: levels,
+		sinks: &sinkSet{
+			sinks: sinks,
+		},
+	}
+}
+
+// Levels returns the Levels this Handler filters by, which may be changed at any time.
+func (h *Handler) Levels() *Levels {
+	return h.levels
+}
+
+// AddSink adds a sink that subsequent entries are written to.
+func (h *Handler) AddSink(sink Sink) {
+	h.sinks.mu.Lock()
+	h.sinks.sinks = append(h.sinks.sinks, sink)
+	h.sinks.mu.Unlock()
+}
+
+// RemoveSink removes the given sink (without closing it).
+func (h *Handler) RemoveSink(sink Sink) {
+	h.sinks.mu.Lock()
+	defer h.sinks.mu.Unlock()
+	for i, si := range h.sinks.sinks {
+		if si == sink {
+			h.sinks.sinks = append(h.sinks.sinks[:i:i], h.sinks.sinks[i+1:]...)
+			return
+		}
+	}
+}
+
+// Flush flushes any sinks that buffer entries (e.g. a remote sink), returning the first error.
+func (h *Handler) Flush() error {
+	h.sinks.mu.RLock()
+	defer h.sinks.mu.RUnlock()
+
+	var err error
+	for _, sink := range h.sinks.sinks {
+		if flushErr := flushSink(sink); flushErr != nil && err == nil {
+			err = flushErr
+		}
+	}
+	return err
+}
+
+// Close flushes and closes all sinks, returning the first error.
+func (h *Handler) Close() error {
+	h.sinks.mu.Lock()
+	sinks := h.sinks.sinks
+	h.sinks.sinks = nil
+	h.sinks.mu.Unlock()
+
+	var err error
+	for _, sink := range sinks {
+		flushErr := flushSink(sink)
+		if closeErr := sink.Close(); closeErr != nil && flushErr == nil {
+			flushErr = closeErr
+		}
+		if flushErr != nil && err == nil {
+			err = flushErr
+		}
+	}
+	return err
+}
+
+// Implements slog.Handler
+func (h *Handler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.levels.Level(h.module, h.task)
+}
+
+// Implements slog.Handler
+func (h *Handler) Handle(_ context.Context, rec slog.Record) error {
+	e := Entry{
+		Time:   rec.Time,
+		Level:  rec.Level,
+		Module: h.module,
+		Task:   h.task,
+		Msg:    rec.Message,
+		PC:     rec.PC,
+	}
+	if rec.NumAttrs() > 0 {
+		e.Attrs = make([]slog.Attr, len(h.attrs), len(h.attrs)+rec.NumAttrs())
+		copy(e.Attrs, h.attrs)
+		rec.Attrs(func(attr slog.Attr) bool {
+			e.Attrs = appendAttr(e.Attrs, h.group, attr)
+			return true
+		})
+	} else {
+		e.Attrs = h.attrs
+	}
+
+	h.sinks.mu.RLock()
+	defer h.sinks.mu.RUnlock()
+
+	var err error
+	for _, sink := range h.sinks.sinks {
+		if writeErr := sink.WriteEntry(&e); writeErr != nil && err == nil {
+			err = writeErr
+		}
+	}
+	return err
+}
+
+// Implements slog.Handler
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	h2 := *h
+	h2.attrs = append([]slog.Attr{}, h.attrs...)
+	for _, attr := range attrs {
+		if h.group == "" && attr.Value.Kind() == slog.KindString {
+			switch attr.Key {
+			case ModuleKey:
+				h2.module = attr.Value.String()
+				continue
+			case TaskKey:
+				h2.task = attr.Value.String()
+				continue
+			}
+		}
+		h2.attrs = appendAttr(h2.attrs, h.group, attr)
+	}
+	return &h2
+}
+
+// Implements slog.Handler
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	h2 := *h
+	h2.group = h.group + name + "."
+	return &h2
+}
+
+// appendAttr appends the given attr, resolved and with any group flattened, following the slog.Handler conventions.
+func appendAttr(attrs []slog.Attr, prefix string, attr slog.Attr) []slog.Attr {
+	attr.Value = attr.Value.Resolve()
+	if attr.Value.Kind() == slog.KindGroup {
+		group := attr.Value.Group()
+		if len(group) == 0 {
+			return attrs
+		}
+		if attr.Key != "" {
+			prefix += attr.Key + "."
+		}
+		for _, member := range group {
+			attrs = appendAttr(attrs, prefix, member)
+		}
+		return attrs
+	}
+	if attr.Equal(slog.Attr{}) {
+		return attrs
+	}
+	attr.Key = prefix + attr.Key
+	return append(attrs, attr)
+}
+
+var gHandler atomic.Pointer[Handler]
+
+// SetDefault sets the Handler that subsequently issued Loggers (see NewLogger) and Default log through.
+// If nil, they log through klog (the initial behavior).
+func SetDefault(h *Handler) {
+	gHandler.Store(h)
+}
+
+// DefaultHandler returns the Handler last given to SetDefault (or nil).
+func DefaultHandler() *Handler {
+	return gHandler.Load()
+}
+
+// Default returns a slog.Logger that logs through the Handler given to SetDefault, or through klog if none is set.
+func Default() *slog.Logger {
+	if h := gHandler.Load(); h != nil {
+		return slog.New(h)
+	}
+	return slog.New(&klogHandler{})
+}
+
+// structuredLogger implements Logger using a slog.Handler.
+type structuredLogger struct {
+	h     slog.Handler
+	label string
+}
+
+func newStructuredLogger(h slog.Handler, label string, attrs []any) *structuredLogger {
+	l := &structuredLogger{
+		label: label,
+	}
+	if label != "" && !hasKey(attrs, ModuleKey) {
+		attrs = append([]any{ModuleKey, label}, attrs...)
+	}
+	l.h = slog.New(h).With(attrs...).Handler()
+	return l
+}
+
+func hasKey(attrs []any, key string) bool {
+	for i := 0; i+1 < len(attrs); i += 2 {
+		if attrs[i] == key {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *structuredLogger) log(level slog.Level, msg string, fields Fields) {
+	if !l.h.Enabled(context.Background(), level) {
+		return
+	}
+	var pcs [1]uintptr
+	runtime.Callers(3, pcs[:]) // skip Callers, log, and the Logger method
+	rec := slog.NewRecord(time.Now(), level, msg, pcs[0])
+	if len(fields) > 0 {
+		keys := make([]string, 0, len(fields))
+		for key := range fields {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			rec.AddAttrs(slog.Any(key, fields[key]))
+		}
+	}
+	l.h.Handle(context.Background(), rec)
+}
+
+// verboseLevel maps a klog verbose level (see Logger.Info) to a slog level: 0 is LevelInfo, and each level above is one lower.
+func verboseLevel(inVerboseLevel int32) slog.Level {
+	return slog.LevelInfo - slog.Level(inVerboseLevel)
+}
+
+func (l *structuredLogger) SetLogLabel(inLabel string) {
+	l.h = l.h.WithAttrs([]slog.Attr{slog.String(ModuleKey, inLabel)})
+	l.label = inLabel
+}
+
+func (l *structuredLogger) GetLogLabel() string {
+	return l.label
+}
+
+func (l *structuredLogger) GetLogPrefix() string {
+	if l.label == "" {
+		return ""
+	}
+	return fmt.Sprintf("[%s] ", l.label)
+}
+
+func (l *structuredLogger) Slog() *slog.Logger {
+	return slog.New(l.h)
+}
+
+func (l *structuredLogger) LogV(inVerboseLevel int32) bool {
+	return l.h.Enabled(context.Background(), verboseLevel(inVerboseLevel))
+}
+
+func (l *structuredLogger) Debug(args ...interface{}) {
+	l.log(slog.LevelDebug, fmt.Sprint(args...), nil)
+}
+
+func (l *structuredLogger) Debugf(inFormat string, args ...interface{}) {
+	l.log(slog.LevelDebug, fmt.Sprintf(inFormat, args...), nil)
+}
+
+func (l *structuredLogger) Debugw(msg string, fields Fields) {
+	l.log(slog.LevelDebug, msg, fields)
+}
+
+func (l *structuredLogger) Success(args ...interface{}) {
+	l.log(LevelSuccess, fmt.Sprint(args...), nil)
+}
+
+func (l *structuredLogger) Successf(inFormat string, args ...interface{}) {
+	l.log(LevelSuccess, fmt.Sprintf(inFormat, args...), nil)
+}
+
+func (l *structuredLogger) Successw(msg string, fields Fields) {
+	l.log(LevelSuccess, msg, fields)
+}
+
+func (l *structuredLogger) Info(inVerboseLevel int32, args ...interface{}) {
+	l.log(verboseLevel(inVerboseLevel), fmt.Sprint(args...), nil)
+}
+
+func (l *structuredLogger) Infof(inVerboseLevel int32, inFormat string, args ...interface{}) {
+	l.log(verboseLevel(inVerboseLevel), fmt.Sprintf(inFormat, args...), nil)
+}
+
+func (l *structuredLogger) Infow(msg string, fields Fields) {
+	l.log(slog.LevelInfo, msg, fields)
+}
+
+func (l *structuredLogger) Warn(args ...interface{}) {
+	l.log(slog.LevelWarn, fmt.Sprint(args...), nil)
+}
+
+func (l *structuredLogger) Warnf(inFormat string, args ...interface{}) {
+	l.log(slog.LevelWarn, fmt.Sprintf(inFormat, args...), nil)
+}
+
+func (l *structuredLogger) Warnw(msg string, fields Fields) {
+	l.log(slog.LevelWarn, msg, fields)
+}
+
+func (l *structuredLogger) Error(args ...interface{}) {
+	l.log(slog.LevelError, fmt.Sprint(args...), nil)
+}
+
+func (l *structuredLogger) Errorf(inFormat string, args ...interface{}) {
+	l.log(slog.LevelError, fmt.Sprintf(inFormat, args...), nil)
+}
+
+func (l *structuredLogger) Errorw(msg string, fields Fields) {
+	l.log(slog.LevelError, msg, fields)
+}
+
+func (l *structuredLogger) Fatalf(inFormat string, args ...interface{}) {
+	l.log(LevelFatal, fmt.Sprintf(inFormat, args...), nil)
+	Flush()
+	os.Exit(1)
+}
+
+// klogHandler is a slog.Handler that logs through klog, used when no Handler is set via SetDefault.
+type klogHandler struct {
+	prefix string
+	attrs  []slog.Attr
+	group  string
+}
+
+func (h *klogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	if level >= slog.LevelInfo {
+		return true
+	}
+	return bool(klog.V(klog.Level(slog.LevelInfo - level)))
+}
+
+func (h *klogHandler) Handle(_ context.Context, rec slog.Record) error {
+	var b bytes.Buffer
+	b.WriteString(h.prefix)
+	b.WriteString(rec.Message)
+	attrs := h.attrs
+	rec.Attrs(func(attr slog.Attr) bool {
+		attrs = appendAttr(attrs, h.group, attr)
+		return true
+	})
+	for _, attr := range attrs {
+		b.WriteByte(' ')
+		appendTextAttr(&b, attr)
+	}
+
+	const depth = 3 // skip Handle and the slog.Logger methods, reporting the caller
+	msg := b.String()
+	switch {
+	case rec.Level >= LevelFatal:
+		klog.FatalDepth(depth, msg)
+	case rec.Level >= slog.LevelError:
+		klog.ErrorDepth(depth, msg)
+	case rec.Level >= slog.LevelWarn:
+		klog.WarningDepth(depth, msg)
+	case rec.Level >= LevelSuccess:
+		klog.SuccessDepth(depth, msg)
+	case rec.Level >= slog.LevelInfo:
+		klog.InfoDepth(depth, msg)
+	default:
+		klog.DebugDepth(depth, msg)
+	}
+	return nil
+}
+
+func (h *klogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	h2 := *h
+	h2.attrs = append([]slog.Attr{}, h.attrs...)
+	for _, attr := range attrs {
+		if h.group == "" && attr.Key == ModuleKey {
+			h2.prefix = fmt.Sprintf("[%s] ", attr.Value.String())
+			continue
+		}
+		h2.attrs = appendAttr(h2.attrs, h.group, attr)
+	}
+	return &h2
+}
+
+func (h *klogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	h2 := *h
+	h2.group = h.group + name + "."
+	return &h2
+}
+
+// loggerHandler is a slog.Handler that logs through a Logger (see SlogOf).
+type loggerHandler struct {
+	l     Logger
+	attrs []slog.Attr
+	group string
+}
+
+func (h *loggerHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= slog.LevelInfo || h.l.LogV(int32(slog.LevelInfo-level))
+}
+
+func (h *loggerHandler) Handle(_ context.Context, rec slog.Record) error {
+	attrs := h.attrs
+	rec.Attrs(func(attr slog.Attr) bool {
+		attrs = appendAttr(attrs, h.group, attr)
+		return true
+	})
+	fields := make(Fields, len(attrs))
+	for _, attr := range attrs {
+		fields[attr.Key] = attr.Value.Any()
+	}
+
+	switch {
+	case rec.Level >= LevelFatal:
+		h.l.Fatalf("%s %v", rec.Message, fields)
+	case rec.Level >= slog.LevelError:
+		h.l.Errorw(rec.Message, fields)
+	case rec.Level >= slog.LevelWarn:
+		h.l.Warnw(rec.Message, fields)
+	case rec.Level >= LevelSuccess:
+		h.l.Successw(rec.Message, fields)
+	case rec.Level >= slog.LevelInfo:
+		h.l.Infow(rec.Message, fields)
+	default:
+		h.l.Debugw(rec.Message, fields)
+	}
+	return nil
+}
+
+func (h *loggerHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	h2 := *h
+	h2.attrs = append([]slog.Attr{}, h.attrs...)
+	for _, attr := range attrs {
+		h2.attrs = appendAttr(h2.attrs, h.group, attr)
+	}
+	return &h2
+}
+
+func (h *loggerHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	h2 := *h
+	h2.group = h.group + name + "."
+	return &h2
+}
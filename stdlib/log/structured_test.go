@@ -0,0 +1,244 @@
+package log_test
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/log"
+	"github.com/amp-3d/amp-sdk-go/stdlib/task"
+)
+
+type memorySink struct {
+	mu      sync.Mutex
+	entries []log.Entry
+}
+
+func (s *memorySink) WriteEntry(e *log.Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = append(s.entries, *e)
+	return nil
+}
+
+func (s *memorySink) Close() error {
+	return nil
+}
+
+func (s *memorySink) take() []log.Entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries := s.entries
+	s.entries = nil
+	return entries
+}
+
+func TestLevels(t *testing.T) {
+	levels := log.NewLevels(slog.LevelInfo)
+	require.NoError(t, levels.Parse("warn, amp.session=debug, host/sessions=error"))
+	require.Equal(t, slog.LevelWarn, levels.Level("other"))
+	require.Equal(t, slog.LevelDebug, levels.Level("amp.session"))
+	require.Equal(t, slog.LevelDebug, levels.Level("amp.session.pins"))
+	require.Equal(t, slog.LevelWarn, levels.Level("amp.sessions"))
+	require.Equal(t, slog.LevelError, levels.Level("amp.session", "host/sessions/ctx_1"), "the more specific module applies")
+	require.Equal(t, "warn,amp.session=debug,host/sessions=error", levels.String())
+
+	levels.Unset("amp.session")
+	require.Equal(t, slog.LevelWarn, levels.Level("amp.session"))
+	require.Error(t, levels.Parse("info,amp=loud"))
+	require.Equal(t, slog.LevelWarn, levels.Level("amp"), "a failed Parse changes nothing")
+
+	srv := httptest.NewServer(levels)
+	defer srv.Close()
+	resp, err := http.Post(srv.URL, "text/plain", strings.NewReader("debug,amp=success"))
+	require.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "debug,amp=success\n", string(body))
+	require.Equal(t, log.LevelSuccess, levels.Level("amp.x"))
+}
+
+func TestHandler(t *testing.T) {
+	sink := &memorySink{}
+	levels := log.NewLevels(slog.LevelInfo)
+	h := log.NewHandler(levels, sink)
+
+	logger := slog.New(h).With(log.ModuleKey, "amp.session", "user", "alice")
+	logger.Debug("hidden")
+	logger.WithGroup("pin").Info("opened", "id", 3, slog.Group("req", "path", "/a"))
+
+	entries := sink.take()
+	require.Len(t, entries, 1)
+	e := entries[0]
+	require.Equal(t, "amp.session", e.Module)
+	require.Equal(t, "opened", e.Msg)
+	require.Len(t, e.Attrs, 3)
+	require.Equal(t, "user", e.Attrs[0].Key)
+	require.Equal(t, "pin.id", e.Attrs[1].Key)
+	require.Equal(t, "pin.req.path", e.Attrs[2].Key)
+
+	// Verbosity can be changed while loggers are in use
+	levels.Set("amp.session", slog.LevelDebug)
+	logger.Debug("shown")
+	require.Len(t, sink.take(), 1)
+
+	text := string(log.AppendText(nil, &e, false))
+	require.True(t, strings.HasSuffix(text, " INFO  amp.session: opened user=alice pin.id=3 pin.req.path=/a\n"), text)
+	json := string(log.AppendJSON(nil, &e))
+	require.True(t, strings.HasSuffix(json, `"level":"INFO","module":"amp.session","msg":"opened","user":"alice","pin.id":3,"pin.req.path":"/a"}`+"\n"), json)
+}
+
+func TestTaskLoggers(t *testing.T) {
+	sink := &memorySink{}
+	levels := log.NewLevels(slog.LevelInfo)
+	log.SetDefault(log.NewHandler(levels, sink))
+	defer log.SetDefault(nil)
+
+	root, err := task.Start(&task.Task{
+		Label: "host",
+	})
+	require.NoError(t, err)
+	defer root.Close()
+	child, err := root.StartChild(&task.Task{
+		Label: "sessions",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "host/sessions", task.PathOf(child))
+
+	child.Infow("started", log.Fields{"n": 2})
+	child.Info(2, "too verbose")
+	log.SlogOf(child).Warn("via slog", "k", "v")
+
+	entries := sink.take()
+	require.Len(t, entries, 2)
+	require.Equal(t, "sessions", entries[0].Module)
+	require.Equal(t, "host/sessions", entries[0].Task)
+	require.Equal(t, "n", entries[0].Attrs[0].Key)
+	require.NotZero(t, entries[0].PC)
+	require.Equal(t, slog.LevelWarn, entries[1].Level)
+	require.Equal(t, "host/sessions", entries[1].Task)
+
+	levels.Set("host/sessions", slog.LevelError)
+	child.Warn("filtered")
+	root.Warn("not filtered")
+	entries = sink.take()
+	require.Len(t, entries, 1)
+	require.Equal(t, "host", entries[0].Task)
+}
+
+// warnLogger is a Logger that does not implement log.Slogger, recording the messages given to Warnw.
+type warnLogger struct {
+	log.Logger
+	warned []string
+}
+
+func (l *warnLogger) Warnw(inFormat string, fields log.Fields) {
+	l.warned = append(l.warned, fmt.Sprint(inFormat, " ", fields))
+}
+
+func TestSlogOf(t *testing.T) {
+	l := &warnLogger{Logger: log.NewLogger("wrapped")}
+	log.SlogOf(l).WithGroup("g").With("a", 1).Warn("via slog", "k", "v")
+	require.Equal(t, []string{"via slog map[g.a:1 g.k:v]"}, l.warned)
+}
+
+func TestRotatingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "host.log")
+	file, err := log.NewRotatingFile(log.RotatingFileOpts{
+		Path:       path,
+		MaxSize:    200,
+		MaxBackups: 2,
+	})
+	require.NoError(t, err)
+
+	logger := slog.New(log.NewHandler(nil, file))
+	for i := 0; i < 20; i++ {
+		logger.Info("a line long enough that a few rotate the file", "i", i)
+	}
+	require.NoError(t, file.Close())
+	require.ErrorIs(t, file.WriteEntry(&log.Entry{}), os.ErrClosed)
+
+	lines := func(name string) []string {
+		f, err := os.Open(name)
+		require.NoError(t, err)
+		defer f.Close()
+		var lines []string
+		for scanner := bufio.NewScanner(f); scanner.Scan(); {
+			lines = append(lines, scanner.Text())
+		}
+		return lines
+	}
+	current := lines(path)
+	require.NotEmpty(t, current)
+	require.True(t, strings.HasSuffix(current[len(current)-1], "i=19"))
+	require.NotEmpty(t, lines(path+".1"))
+	require.NotEmpty(t, lines(path+".2"))
+	_, err = os.Stat(path + ".3")
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestRemoteSink(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		batches []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		batches = append(batches, string(body))
+		mu.Unlock()
+		if r.Header.Get("Authorization") != "Bearer key" {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer srv.Close()
+
+	remote := log.NewRemoteSink(log.RemoteOpts{
+		URL:       srv.URL,
+		Header:    http.Header{"Authorization": {"Bearer key"}},
+		BatchSize: 2,
+	})
+	h := log.NewHandler(nil, log.MinLevel(slog.LevelWarn, remote))
+	logger := slog.New(h)
+	logger.Info("not sent")
+	logger.Warn("one")
+	logger.Warn("two")
+	logger.Error("three")
+	require.NoError(t, h.Flush())
+
+	mu.Lock()
+	require.Len(t, batches, 2)
+	require.Equal(t, 2, strings.Count(batches[0], "\n"))
+	require.Contains(t, batches[0], `"msg":"one"`)
+	require.Contains(t, batches[1], `"msg":"three"`)
+	mu.Unlock()
+	require.Zero(t, remote.Dropped())
+	require.NoError(t, h.Close())
+}
+
+func TestTailSink(t *testing.T) {
+	tail := log.NewTailSink(3)
+	logger := slog.New(log.NewHandler(nil, tail))
+	for i := 0; i < 5; i++ {
+		logger.Info("line", "i", i)
+	}
+
+	buf := &strings.Builder{}
+	_, err := tail.WriteTo(buf)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	require.Len(t, lines, 3)
+	require.True(t, strings.HasSuffix(lines[0], "i=2"))
+	require.True(t, strings.HasSuffix(lines[2], "i=4"))
+}
@@ -1,6 +1,8 @@
 package media
 
 import (
+	"context"
+	"errors"
 	"io"
 	"time"
 
@@ -20,6 +22,18 @@ type Publisher interface {
 	PublishAsset(asset Asset, opts PublishOpts) (URL string, err error)
 }
 
+// URLSigner is implemented by a Publisher whose URLs must be signed to be served (e.g. with an expiry or bound to a session),
+// allowing an app to sign the URL of an asset it did not publish via PublishAsset (e.g. one served by content hash).
+type URLSigner interface {
+	SignURL(rawURL string, expiry time.Duration) (string, error)
+}
+
+// AssetAdapter is implemented by a Publisher that adapts an asset before publishing it (e.g. transcoding it into a format a
+// client can play), returning the asset it publishes in place of the given asset, which may be the given asset itself.
+type AssetAdapter interface {
+	AdaptAsset(asset Asset) (Asset, error)
+}
+
 // MediaAsset is a flexible wrapper for any data asset that can be streamed -- often audio or video.
 type Asset interface {
 
@@ -47,3 +61,39 @@ type Asset interface {
 type AssetReader interface {
 	io.ReadSeekCloser
 }
+
+var (
+	ErrAssetNotFound = errors.New("asset not found")
+	ErrNoDirectURL   = errors.New("asset store does not serve assets directly")
+)
+
+// AssetInfo describes an asset in an AssetStore.
+type AssetInfo struct {
+	Key         string
+	Size        int64 // if < 0 when passed to Put, the size is not known in advance
+	ContentType string
+	ModTime     time.Time
+	ETag        string // opaque version of the content, if the store provides one
+}
+
+// AssetStore stores assets by key -- e.g. on local disk or in object storage (see package media/stores).
+// A key is a relative, slash-separated path such as "albums/1234/cover.jpg".
+type AssetStore interface {
+
+	// Stores the content read from r at the given key (replacing any existing content), where info.Key is ignored.
+	Put(ctx context.Context, key string, r io.Reader, info AssetInfo) error
+
+	// Returns a reader of the asset at the given key or ErrAssetNotFound.
+	// The reader supports seeking, so it can serve range requests without reading what precedes the range.
+	Open(ctx context.Context, key string) (AssetReader, error)
+
+	// Returns info about the asset at the given key or ErrAssetNotFound.
+	Stat(ctx context.Context, key string) (AssetInfo, error)
+
+	// Removes the asset at the given key, returning ErrAssetNotFound if there is none.
+	Delete(ctx context.Context, key string) error
+
+	// Returns a URL a client can fetch the asset from directly (e.g. a presigned object storage URL) that is valid for at least the
+	// given duration, or ErrNoDirectURL if the store can only be read via Open.
+	URL(key string, expiry time.Duration) (string, error)
+}
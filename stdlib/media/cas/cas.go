@@ -0,0 +1,501 @@
+// Package cas is a content-addressed asset store: an asset is split into chunks, each stored once by its BLAKE3 hash, and the asset is
+// addressed by the BLAKE3 hash of its content.  Identical assets (or identical chunks of different assets) are therefore stored once,
+// no matter how many users add them.
+//
+// Each asset has a reference count, incremented by Put and Retain and decremented by Release.  An asset whose count reaches zero
+// remains readable until GC removes it, along with any chunks no other asset refers to.
+//
+// On disk, a Store's Dir contains:
+//
+//	assets/{hash}.json      the manifest of each asset (see Info)
+//	chunks/{hh}/{hash}      each chunk, where hh is the first byte of its hash in hex
+package cas
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"lukechampine.com/blake3"
+)
+
+const (
+	HashSize         = 32
+	DefaultChunkSize = 1 << 20
+)
+
+var (
+	ErrNotFound = errors.New("cas: asset not found")
+	ErrClosed   = errors.New("cas: store closed")
+)
+
+// Hash is the BLAKE3 digest of an asset or chunk and is marshaled as lowercase hex.
+type Hash [HashSize]byte
+
+// NewHasher returns a hash.Hash computing a BLAKE3 digest (see Hash).
+func NewHasher() hash.Hash {
+	return blake3.New(HashSize, nil)
+}
+
+// Sum256 returns the BLAKE3 digest of data.
+func Sum256(data []byte) Hash {
+	return blake3.Sum256(data)
+}
+
+// ParseHash parses the hex form of a Hash.
+func ParseHash(s string) (Hash, error) {
+	var h Hash
+	if len(s) != 2*HashSize {
+		return h, fmt.Errorf("cas: invalid hash %q", s)
+	}
+	if _, err := hex.Decode(h[:], []byte(s)); err != nil {
+		return h, fmt.Errorf("cas: invalid hash %q", s)
+	}
+	return h, nil
+}
+
+func (h Hash) String() string {
+	return hex.EncodeToString(h[:])
+}
+
+func (h Hash) IsZero() bool {
+	return h == Hash{}
+}
+
+func (h Hash) MarshalText() ([]byte, error) {
+	return []byte(h.String()), nil
+}
+
+func (h *Hash) UnmarshalText(text []byte) error {
+	parsed, err := ParseHash(string(text))
+	if err == nil {
+		*h = parsed
+	}
+	return err
+}
+
+// Opts configures a Store.
+type Opts struct {
+	Dir       string // directory the store is kept in (created if needed)
+	ChunkSize int    // size of the chunks assets added by Put are split into (default DefaultChunkSize)
+}
+
+// Info is the manifest of an asset in a Store.
+type Info struct {
+	Hash        Hash      `json:"hash"`
+	Size        int64     `json:"size"`
+	ContentType string    `json:"content_type,omitempty"`
+	ChunkSize   int       `json:"chunk_size"`
+	Chunks      []Hash    `json:"chunks"`
+	Refs        int       `json:"refs"`
+	Created     time.Time `json:"created"`
+}
+
+// Stats summarizes a Store.
+type Stats struct {
+	Assets      int   // assets in the store, including unreferenced ones not yet removed by GC
+	Chunks      int   // distinct chunks stored
+	StoredBytes int64 // bytes of chunks stored
+	AssetBytes  int64 // sum of the size of each asset -- StoredBytes is less than this to the extent chunks are shared
+	Dedups      int64 // Puts of content already stored, since the store was opened
+}
+
+// GCStats reports what a GC removed.
+type GCStats struct {
+	Assets int
+	Chunks int
+	Bytes  int64
+}
+
+// Store is a content-addressed asset store -- concurrency safe.
+type Store struct {
+	opts Opts
+
+	// Held for writing by GC so that chunks written by a Put in progress (not yet referenced by a manifest) are not removed
+	gcMu sync.RWMutex
+
+	mu        sync.Mutex
+	closed    bool
+	assets    map[Hash]*Info
+	chunkRefs map[Hash]int   // for each chunk, the number of manifests referring to it
+	chunkSize map[Hash]int64 // size of each chunk referred to by a manifest
+	dedups    int64
+}
+
+// Open opens (or creates) the store in opts.Dir, loading the manifest of each asset therein.
+func Open(opts Opts) (*Store, error) {
+	if opts.Dir == "" {
+		return nil, errors.New("cas: Opts.Dir is required")
+	}
+	if opts.ChunkSize <= 0 {
+		opts.ChunkSize = DefaultChunkSize
+	}
+	for _, dir := range []string{"assets", "chunks"} {
+		if err := os.MkdirAll(filepath.Join(opts.Dir, dir), 0o755); err != nil {
+			return nil, err
+		}
+	}
+
+	st := &Store{
+		opts:      opts,
+		assets:    make(map[Hash]*Info),
+		chunkRefs: make(map[Hash]int),
+		chunkSize: make(map[Hash]int64),
+	}
+	entries, err := os.ReadDir(filepath.Join(opts.Dir, "assets"))
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		name, isManifest := strings.CutSuffix(entry.Name(), ".json")
+		if !isManifest {
+			continue
+		}
+		buf, err := os.ReadFile(filepath.Join(opts.Dir, "assets", entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		info := &Info{}
+		if err = json.Unmarshal(buf, info); err != nil || info.Hash.String() != name {
+			return nil, fmt.Errorf("cas: corrupt manifest %q", entry.Name())
+		}
+		st.addManifest(info)
+	}
+	return st, nil
+}
+
+// Put stores the content read from r, returning its Info with its reference count incremented.
+// If this content is already in the store, only its reference count changes (though r is read fully to hash it).
+func (st *Store) Put(r io.Reader, contentType string) (Info, error) {
+	st.gcMu.RLock()
+	defer st.gcMu.RUnlock()
+
+	info := &Info{
+		ContentType: contentType,
+		ChunkSize:   st.opts.ChunkSize,
+		Refs:        1,
+		Created:     time.Now().UTC(),
+	}
+	assetHasher := NewHasher()
+	buf := make([]byte, st.opts.ChunkSize)
+	for {
+		n, err := io.ReadFull(r, buf)
+		if n > 0 {
+			chunk := buf[:n]
+			assetHasher.Write(chunk)
+			chunkHash := Sum256(chunk)
+			if err := st.writeChunk(chunkHash, chunk); err != nil {
+				return Info{}, err
+			}
+			info.Chunks = append(info.Chunks, chunkHash)
+			info.Size += int64(n)
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			return Info{}, err
+		}
+	}
+	copy(info.Hash[:], assetHasher.Sum(nil))
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if st.closed {
+		return Info{}, ErrClosed
+	}
+	if existing := st.assets[info.Hash]; existing != nil {
+		st.dedups++
+		if err := st.writeManifest(existing, existing.Refs+1); err != nil {
+			return Info{}, err
+		}
+		return st.copyInfo(existing), nil
+	}
+	if err := st.writeManifest(info, info.Refs); err != nil {
+		return Info{}, err
+	}
+	st.addManifest(info)
+	return st.copyInfo(info), nil
+}
+
+// Stat returns the Info of the given asset or ErrNotFound.
+func (st *Store) Stat(hash Hash) (Info, error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	info := st.assets[hash]
+	if info == nil {
+		return Info{}, ErrNotFound
+	}
+	return st.copyInfo(info), nil
+}
+
+// Retain increments the reference count of the given asset.
+func (st *Store) Retain(hash Hash) error {
+	return st.addRefs(hash, 1)
+}
+
+// Release decrements the reference count of the given asset, making it eligible for removal by GC once it reaches zero.
+func (st *Store) Release(hash Hash) error {
+	return st.addRefs(hash, -1)
+}
+
+func (st *Store) addRefs(hash Hash, delta int) error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if st.closed {
+		return ErrClosed
+	}
+	info := st.assets[hash]
+	if info == nil {
+		return ErrNotFound
+	}
+	refs := info.Refs + delta
+	if refs < 0 {
+		return fmt.Errorf("cas: asset %v released more than retained", hash)
+	}
+	return st.writeManifest(info, refs)
+}
+
+// Open returns a reader of the content of the given asset or ErrNotFound.
+func (st *Store) Open(hash Hash) (*Reader, error) {
+	info, err := st.Stat(hash)
+	if err != nil {
+		return nil, err
+	}
+	return &Reader{
+		st:   st,
+		info: info,
+	}, nil
+}
+
+// GC removes each asset having no references, along with each chunk no longer referred to by an asset (including chunks orphaned by
+// a Put that failed).  Puts issued while a GC is in progress wait until it completes.
+func (st *Store) GC() (GCStats, error) {
+	st.gcMu.Lock()
+	defer st.gcMu.Unlock()
+
+	var stats GCStats
+	st.mu.Lock()
+	for hash, info := range st.assets {
+		if info.Refs > 0 {
+			continue
+		}
+		if err := os.Remove(st.manifestPath(hash)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			st.mu.Unlock()
+			return stats, err
+		}
+		delete(st.assets, hash)
+		for _, chunk := range info.Chunks {
+			if st.chunkRefs[chunk]--; st.chunkRefs[chunk] <= 0 {
+				delete(st.chunkRefs, chunk)
+				delete(st.chunkSize, chunk)
+			}
+		}
+		stats.Assets++
+	}
+	referenced := make(map[Hash]struct{}, len(st.chunkRefs))
+	for chunk := range st.chunkRefs {
+		referenced[chunk] = struct{}{}
+	}
+	st.mu.Unlock()
+
+	err := filepath.WalkDir(filepath.Join(st.opts.Dir, "chunks"), func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		if strings.HasPrefix(entry.Name(), ".tmp-") {
+			return os.Remove(path) // left by a process that exited during a Put
+		}
+		chunk, err := ParseHash(entry.Name())
+		if err != nil {
+			return nil
+		}
+		if _, keep := referenced[chunk]; keep {
+			return nil
+		}
+		fi, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		if err = os.Remove(path); err != nil {
+			return err
+		}
+		stats.Chunks++
+		stats.Bytes += fi.Size()
+		return nil
+	})
+	return stats, err
+}
+
+// Stats returns a summary of this store.
+func (st *Store) Stats() Stats {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	stats := Stats{
+		Assets: len(st.assets),
+		Chunks: len(st.chunkRefs),
+		Dedups: st.dedups,
+	}
+	for _, info := range st.assets {
+		stats.AssetBytes += info.Size
+	}
+	for _, size := range st.chunkSize {
+		stats.StoredBytes += size
+	}
+	return stats
+}
+
+// Close causes subsequent Puts and reference count changes to fail with ErrClosed.
+func (st *Store) Close() error {
+	st.mu.Lock()
+	st.closed = true
+	st.mu.Unlock()
+	return nil
+}
+
+func (st *Store) copyInfo(info *Info) Info {
+	cp := *info
+	cp.Chunks = append([]Hash(nil), info.Chunks...)
+	return cp
+}
+
+// addManifest adds the given asset, assuming st.mu is held or st is not yet shared.
+func (st *Store) addManifest(info *Info) {
+	st.assets[info.Hash] = info
+	remain := info.Size
+	for _, chunk := range info.Chunks {
+		st.chunkRefs[chunk]++
+		st.chunkSize[chunk] = min(remain, int64(info.ChunkSize))
+		remain -= int64(info.ChunkSize)
+	}
+}
+
+// writeManifest persists the given asset having the given reference count, assuming st.mu is held.
+func (st *Store) writeManifest(info *Info, refs int) error {
+	next := *info
+	next.Refs = refs
+	buf, err := json.Marshal(&next)
+	if err != nil {
+		return err
+	}
+	if err = writeFileAtomic(st.manifestPath(info.Hash), buf); err != nil {
+		return err
+	}
+	info.Refs = refs
+	return nil
+}
+
+func (st *Store) writeChunk(hash Hash, chunk []byte) error {
+	path := st.chunkPath(hash)
+	if fi, err := os.Stat(path); err == nil && fi.Size() == int64(len(chunk)) {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return writeFileAtomic(path, chunk)
+}
+
+func (st *Store) manifestPath(hash Hash) string {
+	return filepath.Join(st.opts.Dir, "assets", hash.String()+".json")
+}
+
+func (st *Store) chunkPath(hash Hash) string {
+	name := hash.String()
+	return filepath.Join(st.opts.Dir, "chunks", name[:2], name)
+}
+
+// writeFileAtomic writes to a temp file renamed to path so a reader (or a restart) never sees a partial file.
+func writeFileAtomic(path string, buf []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(buf)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+	}
+	return err
+}
+
+// Reader reads an asset's content across its chunks, implementing io.ReadSeekCloser.
+type Reader struct {
+	st    *Store
+	info  Info
+	pos   int64
+	chunk *os.File // the open chunk containing pos, if any
+	index int      // index of chunk in info.Chunks
+}
+
+// Info returns the Info of the asset being read.
+func (r *Reader) Info() Info {
+	return r.info
+}
+
+func (r *Reader) Read(p []byte) (int, error) {
+	if r.pos >= r.info.Size {
+		return 0, io.EOF
+	}
+	chunkSize := int64(r.info.ChunkSize)
+	index := int(r.pos / chunkSize)
+	if r.chunk == nil || r.index != index {
+		if r.chunk != nil {
+			r.chunk.Close()
+			r.chunk = nil
+		}
+		file, err := os.Open(r.st.chunkPath(r.info.Chunks[index]))
+		if err != nil {
+			return 0, err
+		}
+		r.chunk, r.index = file, index
+	}
+	offset := r.pos - int64(index)*chunkSize
+	p = p[:min(int64(len(p)), chunkSize-offset, r.info.Size-r.pos)]
+	n, err := r.chunk.ReadAt(p, offset)
+	r.pos += int64(n)
+	if err == io.EOF && n == len(p) {
+		err = nil
+	}
+	return n, err
+}
+
+func (r *Reader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += r.pos
+	case io.SeekEnd:
+		offset += r.info.Size
+	default:
+		return r.pos, errors.New("cas: invalid whence")
+	}
+	if offset < 0 {
+		return r.pos, errors.New("cas: negative position")
+	}
+	r.pos = offset
+	return offset, nil
+}
+
+func (r *Reader) Close() error {
+	if r.chunk == nil {
+		return nil
+	}
+	err := r.chunk.Close()
+	r.chunk = nil
+	return err
+}
@@ -0,0 +1,122 @@
+package cas_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/media/cas"
+)
+
+func TestBLAKE3(t *testing.T) {
+	// From the official test vectors, where input[i] = i % 251
+	input := make([]byte, 100000)
+	for i := range input {
+		input[i] = byte(i % 251)
+	}
+	for _, vec := range []struct {
+		len  int
+		hash string
+	}{
+		{0, "af1349b9f5f9a1a6a0404dea36dcc9499bcb25c9adc112b7cc9a93cae41f3262"},
+		{1, "2d3adedff11b61f14c886e35afa036736dcd87a74d27b5c1510225d0f592e213"},
+		{1024, "42214739f095a406f3fc83deb889744ac00df831c10daa55189b5d121c855af7"},
+		{1025, "d00278ae47eb27b34faecf67b4fe263f82d5412916c1ffd97c8cb7fb814b8444"},
+		{2048, "e776b6028c7cd22a4d0ba182a8bf62205d2ef576467e838ed6f2529b85fba24a"},
+		{2049, "5f4d72f40d7a5f82b15ca2b2e44b1de3c2ef86c426c95c1af0b6879522563030"},
+		{3072, "b98cb0ff3623be03326b373de6b9095218513e64f1ee2edd2525c7ad1e5cffd2"},
+		{3073, "7124b49501012f81cc7f11ca069ec9226cecb8a2c850cfe644e327d22d3e1cd3"},
+		{4096, "015094013f57a5277b59d8475c0501042c0b642e531b0a1c8f58d2163229e969"},
+		{8193, "bab6c09cb8ce8cf459261398d2e7aef35700bf488116ceb94a36d0f5f1b7bc3b"},
+		{16384, "f875d6646de28985646f34ee13be9a576fd515f76b5b0a26bb324735041ddde4"},
+		{31744, "62b6960e1a44bcc1eb1a611a8d6235b6b4b78f32e7abc4fb4c6cdcce94895c47"},
+		{100000, "d93c23eedaf165a7e0be908ba86f1a7a520d568d2d13cde787c8580c5c72cc54"},
+	} {
+		require.Equal(t, vec.hash, cas.Sum256(input[:vec.len]).String(), "len %d", vec.len)
+
+		// Incremental writes of any size produce the same digest
+		h := cas.NewHasher()
+		for in := input[:vec.len]; len(in) > 0; {
+			n := min(len(in), 63)
+			h.Write(in[:n])
+			in = in[n:]
+		}
+		require.Equal(t, vec.hash, cas.Hash(h.Sum(nil)).String())
+	}
+}
+
+func TestStore(t *testing.T) {
+	dir := t.TempDir()
+	st, err := cas.Open(cas.Opts{Dir: dir, ChunkSize: 100})
+	require.NoError(t, err)
+
+	content := bytes.Repeat([]byte("0123456789"), 25) // 3 chunks, where the first two are identical
+	a, err := st.Put(bytes.NewReader(content), "text/plain")
+	require.NoError(t, err)
+	require.Equal(t, cas.Sum256(content), a.Hash)
+	require.Equal(t, int64(250), a.Size)
+	require.Len(t, a.Chunks, 3)
+	require.Equal(t, 1, a.Refs)
+
+	// A duplicate is only counted
+	dup, err := st.Put(bytes.NewReader(content), "text/plain")
+	require.NoError(t, err)
+	require.Equal(t, a.Hash, dup.Hash)
+	require.Equal(t, 2, dup.Refs)
+
+	b, err := st.Put(bytes.NewReader(append(content[:100:100], "xyz"...)), "")
+	require.NoError(t, err)
+	stats := st.Stats()
+	require.Equal(t, 2, stats.Assets)
+	require.Equal(t, 3, stats.Chunks)
+	require.Equal(t, int64(153), stats.StoredBytes)
+	require.Equal(t, int64(353), stats.AssetBytes)
+	require.Equal(t, int64(1), stats.Dedups)
+
+	// Reads and seeks span chunks
+	r, err := st.Open(a.Hash)
+	require.NoError(t, err)
+	_, err = r.Seek(95, io.SeekStart)
+	require.NoError(t, err)
+	buf := make([]byte, 10)
+	_, err = io.ReadFull(r, buf)
+	require.NoError(t, err)
+	require.Equal(t, "5678901234", string(buf))
+	r.Seek(0, io.SeekStart)
+	all, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.Equal(t, content, all)
+	require.NoError(t, r.Close())
+
+	// Reference counts survive reopening
+	require.NoError(t, st.Release(b.Hash))
+	require.NoError(t, st.Close())
+	st, err = cas.Open(cas.Opts{Dir: dir})
+	require.NoError(t, err)
+	info, err := st.Stat(b.Hash)
+	require.NoError(t, err)
+	require.Zero(t, info.Refs)
+	require.Error(t, st.Release(b.Hash))
+
+	// GC removes only what is no longer referenced
+	gc, err := st.GC()
+	require.NoError(t, err)
+	require.Equal(t, cas.GCStats{Assets: 1, Chunks: 1, Bytes: 3}, gc)
+	_, err = st.Stat(b.Hash)
+	require.ErrorIs(t, err, cas.ErrNotFound)
+	r, err = st.Open(a.Hash)
+	require.NoError(t, err)
+	all, err = io.ReadAll(r)
+	require.NoError(t, err)
+	require.Equal(t, content, all)
+	r.Close()
+
+	require.NoError(t, st.Release(a.Hash))
+	require.NoError(t, st.Release(a.Hash))
+	gc, err = st.GC()
+	require.NoError(t, err)
+	require.Equal(t, cas.GCStats{Assets: 1, Chunks: 2, Bytes: 150}, gc)
+	require.Equal(t, cas.Stats{Dedups: 0}, st.Stats())
+}
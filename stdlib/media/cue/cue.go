@@ -0,0 +1,421 @@
+// Package cue parses cue sheets, which describe the tracks of an album ripped to a single file (or to a file per track,
+// noting the gaps between them), so that an app can offer each track as a cell of its own.
+//
+// A sheet's tracks are located within their files via Splits, each giving where a track starts and how long it runs, from
+// which Split.Info forms the track's metadata from that of the file holding it.
+package cue
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/media/metadata"
+)
+
+var (
+	ErrSyntax = errors.New("cue: malformed cue sheet")
+)
+
+const (
+	Ext         = ".cue"
+	ContentType = "application/x-cue"
+
+	// FramesPerSecond is the resolution of the times of a cue sheet, given as mm:ss:ff (minutes, seconds, and CD frames).
+	FramesPerSecond = 75
+)
+
+// Sheet is a parsed cue sheet.
+type Sheet struct {
+	Title      string // of the album
+	Performer  string // of the album, and of each track not naming its own
+	Songwriter string
+	Catalog    string // UPC / EAN of the disc
+	Genre      string // REM GENRE
+	Date       string // REM DATE, typically a year
+	Files      []File
+	Tracks     []Track
+
+	// Each REM comment of the sheet (before its first track), keyed by its first word -- e.g. "DISCID", "COMMENT"
+	Rem map[string]string
+}
+
+// File is a media file named by a sheet.
+type File struct {
+	Name string // as given by the sheet, typically relative to the sheet's directory
+	Type string // e.g. "WAVE", "MP3", "AIFF"
+}
+
+// Track is a track of a sheet.
+type Track struct {
+	Number     int
+	DataType   string // "AUDIO" for an audio track, otherwise e.g. "MODE1/2352" for a data track
+	Title      string
+	Performer  string // empty if the track names no performer of its own (see Sheet.Performer)
+	Songwriter string
+	ISRC       string
+	Pregap     time.Duration // silence preceding the track that is not in its file (PREGAP)
+	Postgap    time.Duration // silence following the track that is not in its file (POSTGAP)
+	Indexes    []Index
+}
+
+// Index is a position within a track -- index 1 is where the track begins, and index 0 (if present) where the gap
+// preceding it begins.
+type Index struct {
+	Number int
+	File   string        // name of the file holding the index (see File.Name)
+	Offset time.Duration // within File
+}
+
+// IsAudio returns true if the track is an audio track.
+func (track *Track) IsAudio() bool {
+	return track.DataType == "AUDIO"
+}
+
+// Index returns the index of the given number, or nil if the track has none.
+func (track *Track) Index(number int) *Index {
+	for i := range track.Indexes {
+		if track.Indexes[i].Number == number {
+			return &track.Indexes[i]
+		}
+	}
+	return nil
+}
+
+// start returns the index where the track begins -- index 1, or its first index if it has none.
+func (track *Track) start() *Index {
+	if start := track.Index(1); start != nil {
+		return start
+	}
+	if len(track.Indexes) > 0 {
+		return &track.Indexes[0]
+	}
+	return nil
+}
+
+// ReadFile reads and parses the cue sheet at the given path.
+func ReadFile(path string) (*Sheet, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Parse(f)
+}
+
+// FromInfo parses the cue sheet embedded in the given file's metadata (e.g. the CUESHEET Vorbis comment of a FLAC file),
+// returning false if there is none.
+func FromInfo(md *metadata.Info) (*Sheet, bool) {
+	text, exists := md.Tags["CUESHEET"]
+	if !exists {
+		return nil, false
+	}
+	sheet, err := Parse(strings.NewReader(text))
+	return sheet, err == nil
+}
+
+// Parse parses a cue sheet, as UTF-8 (with or without a BOM) or otherwise ISO-8859-1.
+// Commands not affecting the tracks (e.g. FLAGS, CDTEXTFILE) are ignored.
+func Parse(r io.Reader) (*Sheet, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	content = bytes.TrimPrefix(content, []byte{0xEF, 0xBB, 0xBF})
+	text := string(content)
+	if !utf8.Valid(content) {
+		runes := make([]rune, len(content))
+		for i, b := range content {
+			runes[i] = rune(b)
+		}
+		text = string(runes)
+	}
+
+	sheet := &Sheet{}
+	var track *Track // the track commands apply to, once the first is declared
+	var file string
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		args := splitArgs(scanner.Text())
+		if len(args) == 0 {
+			continue
+		}
+		syntaxErr := func(format string, a ...any) error {
+			return fmt.Errorf("%w: line %d: %s", ErrSyntax, lineNum, fmt.Sprintf(format, a...))
+		}
+		cmd, args := strings.ToUpper(args[0]), args[1:]
+		arg := strings.Join(args, " ")
+
+		switch cmd {
+		case "REM":
+			if len(args) < 2 || track != nil {
+				continue
+			}
+			key, value := strings.ToUpper(args[0]), strings.Join(args[1:], " ")
+			switch key {
+			case "GENRE":
+				sheet.Genre = value
+			case "DATE":
+				sheet.Date = value
+			}
+			if sheet.Rem == nil {
+				sheet.Rem = make(map[string]string)
+			}
+			sheet.Rem[key] = value
+		case "CATALOG":
+			sheet.Catalog = arg
+		case "TITLE", "PERFORMER", "SONGWRITER":
+			dst := map[string]*string{"TITLE": &sheet.Title, "PERFORMER": &sheet.Performer, "SONGWRITER": &sheet.Songwriter}[cmd]
+			if track != nil {
+				dst = map[string]*string{"TITLE": &track.Title, "PERFORMER": &track.Performer, "SONGWRITER": &track.Songwriter}[cmd]
+			}
+			*dst = arg
+		case "FILE":
+			if len(args) == 0 {
+				return nil, syntaxErr("FILE without a name")
+			}
+			f := File{Name: args[0]}
+			if len(args) > 1 {
+				f.Name = strings.Join(args[:len(args)-1], " ") // an unquoted name having spaces
+				f.Type = strings.ToUpper(args[len(args)-1])
+			}
+			file = f.Name
+			sheet.Files = append(sheet.Files, f)
+		case "TRACK":
+			if file == "" {
+				return nil, syntaxErr("TRACK before FILE")
+			}
+			if len(args) < 1 {
+				return nil, syntaxErr("TRACK without a number")
+			}
+			num, err := strconv.Atoi(args[0])
+			if err != nil {
+				return nil, syntaxErr("invalid track number %q", args[0])
+			}
+			sheet.Tracks = append(sheet.Tracks, Track{Number: num, DataType: "AUDIO"})
+			track = &sheet.Tracks[len(sheet.Tracks)-1]
+			if len(args) > 1 {
+				track.DataType = strings.ToUpper(args[1])
+			}
+		case "INDEX", "PREGAP", "POSTGAP", "ISRC":
+			if track == nil {
+				return nil, syntaxErr("%s before TRACK", cmd)
+			}
+			if cmd == "ISRC" {
+				track.ISRC = arg
+				continue
+			}
+			if len(args) < 1 || cmd == "INDEX" && len(args) < 2 {
+				return nil, syntaxErr("%s without a time", cmd)
+			}
+			at, err := ParseTime(args[len(args)-1])
+			if err != nil {
+				return nil, syntaxErr("%v", err)
+			}
+			switch cmd {
+			case "INDEX":
+				num, err := strconv.Atoi(args[0])
+				if err != nil {
+					return nil, syntaxErr("invalid index number %q", args[0])
+				}
+				track.Indexes = append(track.Indexes, Index{Number: num, File: file, Offset: at})
+			case "PREGAP":
+				track.Pregap = at
+			case "POSTGAP":
+				track.Postgap = at
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(sheet.Tracks) == 0 {
+		return nil, fmt.Errorf("%w: no tracks", ErrSyntax)
+	}
+	for i := range sheet.Tracks {
+		if sheet.Tracks[i].start() == nil {
+			return nil, fmt.Errorf("%w: track %d has no index", ErrSyntax, sheet.Tracks[i].Number)
+		}
+	}
+	return sheet, nil
+}
+
+// splitArgs splits a line into its words, where a quoted word may include spaces.
+func splitArgs(line string) []string {
+	var args []string
+	for line = strings.TrimSpace(line); line != ""; line = strings.TrimSpace(line) {
+		if line[0] == '"' {
+			word, rest, _ := strings.Cut(line[1:], `"`)
+			args = append(args, word)
+			line = rest
+			continue
+		}
+		end := strings.IndexAny(line, " \t")
+		if end < 0 {
+			end = len(line)
+		}
+		args = append(args, line[:end])
+		line = line[end:]
+	}
+	return args
+}
+
+// ParseTime parses a time of the form mm:ss:ff (minutes, seconds, and frames of FramesPerSecond), where minutes may exceed 99.
+func ParseTime(s string) (time.Duration, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 3 {
+		return 0, fmt.Errorf("invalid time %q", s)
+	}
+	var n [3]int
+	for i, part := range parts {
+		var err error
+		if n[i], err = strconv.Atoi(part); err != nil || n[i] < 0 {
+			return 0, fmt.Errorf("invalid time %q", s)
+		}
+	}
+	if n[1] >= 60 || n[2] >= FramesPerSecond {
+		return 0, fmt.Errorf("invalid time %q", s)
+	}
+	frames := int64(n[0]*60+n[1])*FramesPerSecond + int64(n[2])
+	return time.Duration(frames) * time.Second / FramesPerSecond, nil
+}
+
+// FormatTime formats a time as mm:ss:ff, rounded to the nearest frame.
+func FormatTime(d time.Duration) string {
+	frames := int64((max(d, 0)*FramesPerSecond + time.Second/2) / time.Second)
+	return fmt.Sprintf("%02d:%02d:%02d", frames/(60*FramesPerSecond), frames/FramesPerSecond%60, frames%FramesPerSecond)
+}
+
+// Split is an audio track of a sheet, located within the file holding it.
+type Split struct {
+	Sheet    *Sheet
+	Track    *Track
+	File     string        // name of the file holding the track (see File.Name)
+	Start    time.Duration // where the track begins within File (index 1)
+	Duration time.Duration // how long the track runs, or 0 if it runs to the end of File and File's duration is not known
+	Pregap   time.Duration // length of the gap preceding Start within File (from index 0), played when playing through
+
+	lastInFile bool
+}
+
+// Splits returns each audio track of the sheet located within its file, in order.  A track runs until the next track in the
+// same file begins (including any gap preceding it), or else until the end of its file, where fileDuration returns the
+// duration of the file of the given name (or 0 if not known) -- fileDuration may be nil if no durations are known.
+func (sheet *Sheet) Splits(fileDuration func(name string) time.Duration) []Split {
+	var splits []Split
+	for i := range sheet.Tracks {
+		track := &sheet.Tracks[i]
+		if !track.IsAudio() {
+			continue
+		}
+		start := track.start()
+		split := Split{
+			Sheet: sheet,
+			Track: track,
+			File:  start.File,
+			Start: start.Offset,
+		}
+		if gap := track.Index(0); gap != nil && gap.File == start.File && gap.Offset < start.Offset {
+			split.Pregap = start.Offset - gap.Offset
+		}
+
+		// A track ends where the next begins, including its gap, unless the next begins in another file
+		var end *Index
+		if i+1 < len(sheet.Tracks) {
+			next := &sheet.Tracks[i+1]
+			end = next.Index(0)
+			if end == nil || end.File != start.File {
+				end = next.start()
+			}
+			if end.File != start.File {
+				end = nil
+			}
+		}
+		if end != nil {
+			split.Duration = max(end.Offset-start.Offset, 0)
+		} else {
+			split.lastInFile = true
+			if fileDuration != nil {
+				if total := fileDuration(start.File); total > start.Offset {
+					split.Duration = total - start.Offset
+				}
+			}
+		}
+		splits = append(splits, split)
+	}
+	return splits
+}
+
+// Info returns the metadata of the track, formed from the sheet and the given metadata of the file holding it (or nil):
+// the track's title, performer, and number, the album's title, performer, genre, and year, and the file's stream properties
+// and artwork.  Since the tracks of a file are contiguous, only the first track of a file has the file's encoder delay and
+// only the last has its padding (see metadata.Info.EncoderDelay).
+func (split *Split) Info(file *metadata.Info) *metadata.Info {
+	sheet, track := split.Sheet, split.Track
+	if file == nil {
+		file = &metadata.Info{}
+	}
+	info := &metadata.Info{
+		ContentType: file.ContentType,
+		Title:       track.Title,
+		Artist:      first(track.Performer, sheet.Performer, file.Artist),
+		Album:       first(sheet.Title, file.Album),
+		AlbumArtist: first(sheet.Performer, file.AlbumArtist),
+		Composer:    first(track.Songwriter, sheet.Songwriter, file.Composer),
+		Genre:       first(sheet.Genre, file.Genre),
+		Comment:     file.Comment,
+		Year:        file.Year,
+		TrackNum:    track.Number,
+		DiscNum:     file.DiscNum,
+		DiscCount:   file.DiscCount,
+		Duration:    split.Duration,
+		SampleRate:  file.SampleRate,
+		Channels:    file.Channels,
+		Artwork:     file.Artwork,
+	}
+	if len(sheet.Date) >= 4 {
+		if year, err := strconv.Atoi(sheet.Date[:4]); err == nil {
+			info.Year = year
+		}
+	}
+	for i := range sheet.Tracks {
+		if sheet.Tracks[i].IsAudio() {
+			info.TrackCount++
+		}
+	}
+	if split.Start-split.Pregap == 0 {
+		info.EncoderDelay = file.EncoderDelay
+	}
+	if split.lastInFile {
+		info.EncoderPadding = file.EncoderPadding
+	}
+	return info
+}
+
+// first returns the first of the given values that is not empty.
+func first(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// Path returns the path of the file holding the track, given the path of the sheet -- a relative name is relative to the
+// sheet's directory.
+func (split *Split) Path(sheetPath string) string {
+	name := filepath.FromSlash(strings.ReplaceAll(split.File, `\`, "/"))
+	if filepath.IsAbs(name) {
+		return name
+	}
+	return filepath.Join(filepath.Dir(sheetPath), name)
+}
@@ -0,0 +1,165 @@
+package cue
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/media/metadata"
+)
+
+const albumSheet = "\xEF\xBB\xBFREM GENRE Jazz\r\n" + `REM DATE 1959
+REM DISCID 6B0A8A08
+CATALOG 0074646593524
+PERFORMER "Miles Davis"
+TITLE "Kind of Blue"
+FILE "Kind of Blue.flac" WAVE
+  TRACK 01 AUDIO
+    TITLE "So What"
+    ISRC USSM15900113
+    INDEX 01 00:00:00
+  TRACK 02 AUDIO
+    TITLE "Freddie Freeloader"
+    INDEX 00 09:22:00
+    INDEX 01 09:24:37
+  TRACK 03 AUDIO
+    TITLE "Blue in Green"
+    PERFORMER "Miles Davis & Bill Evans"
+    FLAGS DCP
+    INDEX 01 19:10:00
+`
+
+func TestParse(t *testing.T) {
+	sheet, err := Parse(strings.NewReader(albumSheet))
+	require.NoError(t, err)
+	require.Equal(t, "Kind of Blue", sheet.Title)
+	require.Equal(t, "Miles Davis", sheet.Performer)
+	require.Equal(t, "Jazz", sheet.Genre)
+	require.Equal(t, "1959", sheet.Date)
+	require.Equal(t, "6B0A8A08", sheet.Rem["DISCID"])
+	require.Equal(t, "0074646593524", sheet.Catalog)
+	require.Equal(t, []File{{Name: "Kind of Blue.flac", Type: "WAVE"}}, sheet.Files)
+	require.Len(t, sheet.Tracks, 3)
+	require.Equal(t, "USSM15900113", sheet.Tracks[0].ISRC)
+	require.Equal(t, "Miles Davis & Bill Evans", sheet.Tracks[2].Performer)
+	require.Equal(t, []Index{
+		{Number: 0, File: "Kind of Blue.flac", Offset: 9*time.Minute + 22*time.Second},
+		{Number: 1, File: "Kind of Blue.flac", Offset: 9*time.Minute + 24*time.Second + 37*time.Second/75},
+	}, sheet.Tracks[1].Indexes)
+
+	// Each track runs until the gap preceding the next, and the last to the end of the file
+	total := 25*time.Minute + 30*time.Second
+	splits := sheet.Splits(func(name string) time.Duration {
+		require.Equal(t, "Kind of Blue.flac", name)
+		return total
+	})
+	require.Len(t, splits, 3)
+	require.Equal(t, time.Duration(0), splits[0].Start)
+	require.Equal(t, 9*time.Minute+22*time.Second, splits[0].Duration)
+	require.Equal(t, sheet.Tracks[1].Indexes[1].Offset, splits[1].Start)
+	require.Equal(t, 2*time.Second+37*time.Second/75, splits[1].Pregap)
+	require.Equal(t, 19*time.Minute+10*time.Second-splits[1].Start, splits[1].Duration)
+	require.Equal(t, total-19*time.Minute-10*time.Second, splits[2].Duration)
+	require.Equal(t, "/music/Kind of Blue.flac", splits[2].Path("/music/Kind of Blue.cue"))
+
+	// Without the file's duration, the last track runs to the end of the file
+	require.Zero(t, sheet.Splits(nil)[2].Duration)
+
+	// Each track's metadata is formed from the sheet and the file, where only the ends have the file's delay and padding
+	file := &metadata.Info{
+		ContentType:    "audio/flac",
+		Album:          "Other",
+		Genre:          "Other",
+		Year:           1997,
+		SampleRate:     44100,
+		Channels:       2,
+		Duration:       total,
+		EncoderDelay:   576,
+		EncoderPadding: 1000,
+	}
+	first := splits[0].Info(file)
+	require.Equal(t, "So What", first.Title)
+	require.Equal(t, "Miles Davis", first.Artist)
+	require.Equal(t, "Kind of Blue", first.Album)
+	require.Equal(t, "Jazz", first.Genre)
+	require.Equal(t, 1959, first.Year)
+	require.Equal(t, 1, first.TrackNum)
+	require.Equal(t, 3, first.TrackCount)
+	require.Equal(t, 44100, first.SampleRate)
+	require.Equal(t, splits[0].Duration, first.Duration)
+	require.Equal(t, 576, first.EncoderDelay)
+	require.Zero(t, first.EncoderPadding)
+	last := splits[2].Info(file)
+	require.Equal(t, "Miles Davis & Bill Evans", last.Artist)
+	require.Equal(t, "Miles Davis", last.AlbumArtist)
+	require.Zero(t, last.EncoderDelay)
+	require.Equal(t, 1000, last.EncoderPadding)
+	require.Equal(t, "Blue in Green", splits[2].Info(nil).Title)
+}
+
+func TestParseFilePerTrack(t *testing.T) {
+	// A rip with a file per track, where each gap is appended to the preceding file
+	sheet, err := Parse(strings.NewReader(`PERFORMER "Band"
+FILE "01.wav" WAVE
+  TRACK 01 AUDIO
+    INDEX 01 00:00:00
+  TRACK 02 AUDIO
+    PREGAP 00:01:00
+    INDEX 00 03:58:00
+FILE "02.wav" WAVE
+    INDEX 01 00:00:00
+  TRACK 03 MODE1/2352
+    INDEX 01 04:00:00
+`))
+	require.NoError(t, err)
+	require.Len(t, sheet.Files, 2)
+	require.Equal(t, time.Second, sheet.Tracks[1].Pregap)
+	require.False(t, sheet.Tracks[2].IsAudio())
+
+	durations := map[string]time.Duration{"01.wav": 4 * time.Minute, "02.wav": 5 * time.Minute}
+	splits := sheet.Splits(func(name string) time.Duration { return durations[name] })
+	require.Len(t, splits, 2) // the data track is skipped
+	require.Equal(t, "01.wav", splits[0].File)
+	require.Equal(t, 3*time.Minute+58*time.Second, splits[0].Duration)
+	require.Equal(t, "02.wav", splits[1].File)
+	require.Zero(t, splits[1].Pregap) // the gap is in the preceding file
+	require.Equal(t, 4*time.Minute, splits[1].Duration)
+	require.Equal(t, 2, splits[1].Info(nil).TrackCount)
+}
+
+func TestParseErrors(t *testing.T) {
+	// ISO-8859-1
+	sheet, err := Parse(strings.NewReader("TITLE \"Caf\xE9\"\nFILE a.wav WAVE\nTRACK 1 AUDIO\nINDEX 1 0:00:00\n"))
+	require.NoError(t, err)
+	require.Equal(t, "Café", sheet.Title)
+
+	for _, bad := range []string{
+		"TRACK 01 AUDIO\nINDEX 01 00:00:00\n",
+		"FILE a.wav WAVE\nINDEX 01 00:00:00\n",
+		"FILE a.wav WAVE\nTRACK 01 AUDIO\nINDEX 01 00:60:00\n",
+		"FILE a.wav WAVE\nTRACK 01 AUDIO\nINDEX 01 00:00:75\n",
+		"FILE a.wav WAVE\nTRACK 01 AUDIO\n",
+		"FILE a.wav WAVE\n",
+	} {
+		_, err = Parse(strings.NewReader(bad))
+		require.ErrorIs(t, err, ErrSyntax, bad)
+	}
+
+	_, ok := FromInfo(&metadata.Info{})
+	require.False(t, ok)
+	sheet, ok = FromInfo(&metadata.Info{Tags: map[string]string{"CUESHEET": albumSheet}})
+	require.True(t, ok)
+	require.Len(t, sheet.Tracks, 3)
+}
+
+func TestTime(t *testing.T) {
+	for _, s := range []string{"00:00:00", "04:10:74", "120:59:01"} {
+		d, err := ParseTime(s)
+		require.NoError(t, err)
+		require.Equal(t, s, FormatTime(d))
+	}
+	_, err := ParseTime("1:2")
+	require.Error(t, err)
+}
@@ -0,0 +1,333 @@
+// Package derive generates assets derived from media assets -- waveform peaks of audio (e.g. for a scrubber) and thumbnails
+// of images, video, and audio artwork -- caching each in a media.AssetStore so that it is generated once per source.
+//
+// Decoding and encoding is performed by a list of Encoders, each of which may decline a given source.  By default, Images
+// handles still images (and the artwork embedded in audio files) natively, and FFmpeg handles the rest.
+package derive
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/media"
+)
+
+var (
+	ErrUnsupported = errors.New("derive: source not supported by encoder")
+)
+
+// Source is a media asset to derive assets from.
+type Source struct {
+	Key   string      // identifies the source's content, forming the keys of its derived assets -- e.g. its content hash or store key
+	Asset media.Asset // the source content
+}
+
+// WaveformOpts specifies a waveform to generate.
+type WaveformOpts struct {
+	Peaks int // number of peaks spanning the audio (default DefaultPeaks)
+}
+
+// Waveform is the peak amplitude of each of a series of equal slices of a source's audio.
+type Waveform struct {
+	Duration time.Duration `json:"duration"`
+	Peaks    []uint8       `json:"peaks"` // peak amplitude of each slice, where 255 is full scale
+}
+
+// ThumbnailOpts specifies a thumbnail to generate.
+type ThumbnailOpts struct {
+	Width       int           // thumbnail fits within Width x Height, preserving the source's aspect ratio
+	Height      int           // if zero, Height is Width
+	ContentType string        // "image/jpeg" (default) or "image/png"
+	At          time.Duration // offset of the frame of a video source
+}
+
+// Thumbnail is a thumbnail generated (or already cached) in the Generator's store.
+type Thumbnail struct {
+	Key         string // store key of the thumbnail -- see media.AssetStore.URL or publisher.Publisher.StoreURL
+	ContentType string
+	Width       int
+	Height      int
+	Size        int64
+}
+
+const (
+	DefaultPeaks  = 1000
+	DefaultPrefix = "derived/"
+
+	// Audio is decoded at this rate to compute waveforms, which is ample for peaks at display resolution
+	waveformSampleRate = 8000
+)
+
+// Input is the source content given to an Encoder.
+type Input struct {
+	ContentType string
+	Reader      media.AssetReader
+
+	spooled string // temp file holding the content, if Path was called
+}
+
+// Path returns the path of a local file holding the input's content, spooling it to a temp file if Reader is not an *os.File.
+func (in *Input) Path() (string, error) {
+	if f, ok := in.Reader.(*os.File); ok {
+		return f.Name(), nil
+	}
+	if in.spooled != "" {
+		return in.spooled, nil
+	}
+	if _, err := in.Reader.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	f, err := os.CreateTemp("", "derive-*")
+	if err != nil {
+		return "", err
+	}
+	_, err = io.Copy(f, in.Reader)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	in.spooled = f.Name()
+	return in.spooled, nil
+}
+
+func (in *Input) close() {
+	in.Reader.Close()
+	if in.spooled != "" {
+		os.Remove(in.spooled)
+	}
+}
+
+// Encoder decodes and encodes media for a Generator, returning ErrUnsupported for a source it does not handle.
+type Encoder interface {
+
+	// DecodeAudio returns the audio of in decoded as mono, signed 16 bit little endian PCM at the given sample rate.
+	DecodeAudio(ctx context.Context, in *Input, sampleRate int) (io.ReadCloser, error)
+
+	// EncodeThumbnail writes a thumbnail of in to w as specified, returning its dimensions.
+	EncodeThumbnail(ctx context.Context, in *Input, opts ThumbnailOpts, w io.Writer) (width, height int, err error)
+}
+
+// Opts configures a Generator.
+type Opts struct {
+	Store    media.AssetStore // where derived assets are cached (required)
+	Prefix   string           // prepended to the key of each derived asset (default DefaultPrefix)
+	Encoders []Encoder        // tried in order for each source (default Images, then FFmpeg)
+}
+
+// Generator generates derived assets, where concurrent requests for the same derived asset share one generation.
+type Generator struct {
+	opts     Opts
+	mu       sync.Mutex
+	inFlight map[string]*generation
+}
+
+type generation struct {
+	done chan struct{}
+	err  error
+}
+
+// New returns a Generator for the given options.
+func New(opts Opts) (*Generator, error) {
+	if opts.Store == nil {
+		return nil, errors.New("derive: Opts.Store is required")
+	}
+	if opts.Prefix == "" {
+		opts.Prefix = DefaultPrefix
+	}
+	if len(opts.Encoders) == 0 {
+		opts.Encoders = []Encoder{Images{}, &FFmpeg{}}
+	}
+	return &Generator{
+		opts:     opts,
+		inFlight: make(map[string]*generation),
+	}, nil
+}
+
+func (g *Generator) key(src Source, name string) string {
+	return g.opts.Prefix + strings.Trim(src.Key, "/") + "/" + name
+}
+
+// Waveform returns the waveform of the given source, generating and caching it if needed.
+func (g *Generator) Waveform(ctx context.Context, src Source, opts WaveformOpts) (*Waveform, error) {
+	if opts.Peaks <= 0 {
+		opts.Peaks = DefaultPeaks
+	}
+	key := g.key(src, "waveform-"+strconv.Itoa(opts.Peaks)+".json")
+	err := g.generate(ctx, key, func() error {
+		wave, err := g.generateWaveform(ctx, src, opts)
+		if err != nil {
+			return err
+		}
+		content, err := json.Marshal(wave)
+		if err != nil {
+			return err
+		}
+		return g.opts.Store.Put(ctx, key, bytes.NewReader(content), media.AssetInfo{
+			Size:        int64(len(content)),
+			ContentType: "application/json",
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := g.opts.Store.Open(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	wave := &Waveform{}
+	if err = json.NewDecoder(r).Decode(wave); err != nil {
+		return nil, fmt.Errorf("derive: cached waveform %q: %w", key, err)
+	}
+	return wave, nil
+}
+
+func (g *Generator) generateWaveform(ctx context.Context, src Source, opts WaveformOpts) (*Waveform, error) {
+	var wave *Waveform
+	err := g.withEncoders(src, func(enc Encoder, in *Input) error {
+		pcm, err := enc.DecodeAudio(ctx, in, waveformSampleRate)
+		if err != nil {
+			return err
+		}
+		defer pcm.Close()
+		wave, err = computeWaveform(pcm, waveformSampleRate, opts.Peaks)
+		return err
+	})
+	return wave, err
+}
+
+// Thumbnail returns a thumbnail of the given source, generating and caching it if needed.
+func (g *Generator) Thumbnail(ctx context.Context, src Source, opts ThumbnailOpts) (*Thumbnail, error) {
+	if opts.Width <= 0 {
+		return nil, errors.New("derive: ThumbnailOpts.Width is required")
+	}
+	if opts.Height <= 0 {
+		opts.Height = opts.Width
+	}
+	ext := ".jpg"
+	switch opts.ContentType {
+	case "", "image/jpeg":
+		opts.ContentType = "image/jpeg"
+	case "image/png":
+		ext = ".png"
+	default:
+		return nil, fmt.Errorf("derive: unsupported thumbnail type %q", opts.ContentType)
+	}
+	name := "thumb-" + strconv.Itoa(opts.Width) + "x" + strconv.Itoa(opts.Height)
+	if opts.At > 0 {
+		name += "-" + strconv.FormatInt(opts.At.Milliseconds(), 10) + "ms"
+	}
+	key := g.key(src, name+ext)
+
+	err := g.generate(ctx, key, func() error {
+		var buf bytes.Buffer
+		err := g.withEncoders(src, func(enc Encoder, in *Input) error {
+			buf.Reset()
+			_, _, err := enc.EncodeThumbnail(ctx, in, opts, &buf)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		return g.opts.Store.Put(ctx, key, &buf, media.AssetInfo{
+			Size:        int64(buf.Len()),
+			ContentType: opts.ContentType,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The dimensions of a cached thumbnail are read from its header
+	r, err := g.opts.Store.Open(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	cfg, _, err := image.DecodeConfig(r)
+	if err != nil {
+		return nil, fmt.Errorf("derive: cached thumbnail %q: %w", key, err)
+	}
+	size, _ := r.Seek(0, io.SeekEnd)
+	return &Thumbnail{
+		Key:         key,
+		ContentType: opts.ContentType,
+		Width:       cfg.Width,
+		Height:      cfg.Height,
+		Size:        size,
+	}, nil
+}
+
+// generate calls fn to generate the derived asset at key unless it is already stored, or waits for a generation already in flight.
+func (g *Generator) generate(ctx context.Context, key string, fn func() error) error {
+	for {
+		if _, err := g.opts.Store.Stat(ctx, key); err == nil {
+			return nil
+		} else if !errors.Is(err, media.ErrAssetNotFound) {
+			return err
+		}
+
+		g.mu.Lock()
+		gen := g.inFlight[key]
+		if gen == nil {
+			gen = &generation{done: make(chan struct{})}
+			g.inFlight[key] = gen
+			g.mu.Unlock()
+
+			gen.err = fn()
+			g.mu.Lock()
+			delete(g.inFlight, key)
+			g.mu.Unlock()
+			close(gen.done)
+			return gen.err
+		}
+		g.mu.Unlock()
+
+		select {
+		case <-gen.done:
+			if gen.err != nil {
+				return gen.err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// withEncoders calls fn with each encoder in turn until one does not return ErrUnsupported.
+func (g *Generator) withEncoders(src Source, fn func(enc Encoder, in *Input) error) error {
+	reader, err := src.Asset.NewAssetReader()
+	if err != nil {
+		return err
+	}
+	in := &Input{
+		ContentType: src.Asset.ContentType(),
+		Reader:      reader,
+	}
+	defer in.close()
+
+	err = ErrUnsupported
+	for _, enc := range g.opts.Encoders {
+		if _, err = in.Reader.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		if err = fn(enc, in); !errors.Is(err, ErrUnsupported) {
+			return err
+		}
+	}
+	return fmt.Errorf("%w: %s (%s)", ErrUnsupported, src.Asset.Label(), in.ContentType)
+}
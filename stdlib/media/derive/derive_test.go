@@ -0,0 +1,254 @@
+package derive
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/media"
+	"github.com/amp-3d/amp-sdk-go/stdlib/media/stores"
+	"github.com/amp-3d/amp-sdk-go/stdlib/task"
+	"github.com/stretchr/testify/require"
+)
+
+type bytesAsset struct {
+	contentType string
+	data        []byte
+}
+
+func (asset *bytesAsset) Label() string                  { return "test asset" }
+func (asset *bytesAsset) ContentType() string            { return asset.contentType }
+func (asset *bytesAsset) OnStart(ctx task.Context) error { return nil }
+
+func (asset *bytesAsset) NewAssetReader() (media.AssetReader, error) {
+	return nopCloser{bytes.NewReader(asset.data)}, nil
+}
+
+type nopCloser struct {
+	*bytes.Reader
+}
+
+func (nopCloser) Close() error { return nil }
+
+// countingEncoder counts the calls made to the Encoder it wraps.
+type countingEncoder struct {
+	Encoder
+	calls atomic.Int32
+}
+
+func (enc *countingEncoder) DecodeAudio(ctx context.Context, in *Input, sampleRate int) (io.ReadCloser, error) {
+	enc.calls.Add(1)
+	return enc.Encoder.DecodeAudio(ctx, in, sampleRate)
+}
+
+func (enc *countingEncoder) EncodeThumbnail(ctx context.Context, in *Input, opts ThumbnailOpts, w io.Writer) (int, int, error) {
+	enc.calls.Add(1)
+	return enc.Encoder.EncodeThumbnail(ctx, in, opts, w)
+}
+
+// pcmEncoder decodes any audio/* input as the given PCM.
+type pcmEncoder struct {
+	Images
+	pcm   []byte
+	delay time.Duration
+}
+
+func (enc *pcmEncoder) DecodeAudio(ctx context.Context, in *Input, sampleRate int) (io.ReadCloser, error) {
+	if !strings.HasPrefix(in.ContentType, "audio/") {
+		return nil, ErrUnsupported
+	}
+	time.Sleep(enc.delay)
+	return io.NopCloser(bytes.NewReader(enc.pcm)), nil
+}
+
+func newGenerator(t *testing.T, encoders ...Encoder) (*Generator, media.AssetStore) {
+	store, err := stores.NewLocal(stores.LocalOpts{Dir: t.TempDir()})
+	require.NoError(t, err)
+	gen, err := New(Opts{Store: store, Encoders: encoders})
+	require.NoError(t, err)
+	return gen, store
+}
+
+func testPNG(t *testing.T, w, h int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.SetRGBA(x, y, color.RGBA{uint8(x), uint8(y), 0x80, 0xFF})
+		}
+	}
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+// testPCM returns mono s16le PCM where the amplitude of a tone ramps from silence to full scale.
+func testPCM(sampleRate int, dur time.Duration) []byte {
+	n := int(dur * time.Duration(sampleRate) / time.Second)
+	pcm := make([]byte, 2*n)
+	for i := 0; i < n; i++ {
+		amp := float64(i) / float64(n-1)
+		s := amp * 32767 * math.Sin(2*math.Pi*440*float64(i)/float64(sampleRate))
+		binary.LittleEndian.PutUint16(pcm[2*i:], uint16(int16(s)))
+	}
+	return pcm
+}
+
+func TestThumbnail(t *testing.T) {
+	enc := &countingEncoder{Encoder: Images{}}
+	gen, store := newGenerator(t, enc)
+	ctx := context.Background()
+	src := Source{Key: "abc123", Asset: &bytesAsset{"image/png", testPNG(t, 400, 200)}}
+
+	thumb, err := gen.Thumbnail(ctx, src, ThumbnailOpts{Width: 100})
+	require.NoError(t, err)
+	require.Equal(t, "derived/abc123/thumb-100x100.jpg", thumb.Key)
+	require.Equal(t, "image/jpeg", thumb.ContentType)
+	require.Equal(t, 100, thumb.Width)
+	require.Equal(t, 50, thumb.Height)
+	info, err := store.Stat(ctx, thumb.Key)
+	require.NoError(t, err)
+	require.Equal(t, thumb.Size, info.Size)
+
+	// cached
+	again, err := gen.Thumbnail(ctx, src, ThumbnailOpts{Width: 100})
+	require.NoError(t, err)
+	require.Equal(t, thumb, again)
+	require.Equal(t, int32(1), enc.calls.Load())
+
+	// never scaled up
+	thumb, err = gen.Thumbnail(ctx, src, ThumbnailOpts{Width: 1000, Height: 800, ContentType: "image/png"})
+	require.NoError(t, err)
+	require.Equal(t, "derived/abc123/thumb-1000x800.png", thumb.Key)
+	require.Equal(t, 400, thumb.Width)
+	require.Equal(t, 200, thumb.Height)
+
+	// tall
+	thumb, err = gen.Thumbnail(ctx, Source{Key: "tall", Asset: &bytesAsset{"image/png", testPNG(t, 90, 300)}}, ThumbnailOpts{Width: 64, Height: 60})
+	require.NoError(t, err)
+	require.Equal(t, 18, thumb.Width)
+	require.Equal(t, 60, thumb.Height)
+
+	_, err = gen.Thumbnail(ctx, Source{Key: "text", Asset: &bytesAsset{"text/plain", []byte("hello")}}, ThumbnailOpts{Width: 64})
+	require.ErrorIs(t, err, ErrUnsupported)
+	_, err = gen.Thumbnail(ctx, src, ThumbnailOpts{Width: 64, ContentType: "image/webp"})
+	require.Error(t, err)
+}
+
+func TestWaveform(t *testing.T) {
+	const rate = waveformSampleRate
+	enc := &countingEncoder{Encoder: &pcmEncoder{pcm: testPCM(rate, 2*time.Second), delay: 20 * time.Millisecond}}
+	gen, _ := newGenerator(t, enc)
+	ctx := context.Background()
+	src := Source{Key: "song", Asset: &bytesAsset{"audio/mpeg", []byte("not decoded")}}
+
+	var (
+		wg    sync.WaitGroup
+		waves [4]*Waveform
+	)
+	for i := range waves {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var err error
+			waves[i], err = gen.Waveform(ctx, src, WaveformOpts{Peaks: 20})
+			require.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	require.Equal(t, int32(1), enc.calls.Load())
+
+	wave := waves[0]
+	require.Equal(t, 2*time.Second, wave.Duration)
+	require.Len(t, wave.Peaks, 20)
+	for i := 1; i < len(wave.Peaks); i++ {
+		require.GreaterOrEqual(t, wave.Peaks[i], wave.Peaks[i-1])
+	}
+	require.Less(t, wave.Peaks[0], uint8(20))
+	require.Greater(t, wave.Peaks[19], uint8(240))
+	for _, other := range waves[1:] {
+		require.Equal(t, wave, other)
+	}
+
+	// more peaks than blocks of audio
+	wave, err := computeWaveform(bytes.NewReader(testPCM(rate, 50*time.Millisecond)), rate, 12)
+	require.NoError(t, err)
+	require.Len(t, wave.Peaks, 12)
+	require.Equal(t, 50*time.Millisecond, wave.Duration)
+
+	wave, err = computeWaveform(bytes.NewReader(nil), rate, 5)
+	require.NoError(t, err)
+	require.Equal(t, []uint8{0, 0, 0, 0, 0}, wave.Peaks)
+
+	_, err = gen.Waveform(ctx, Source{Key: "pic", Asset: &bytesAsset{"image/png", testPNG(t, 4, 4)}}, WaveformOpts{})
+	require.ErrorIs(t, err, ErrUnsupported)
+}
+
+func TestFFmpeg(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires a shell")
+	}
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "out.png"), testPNG(t, 160, 90), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "out.pcm"), testPCM(waveformSampleRate, time.Second), 0644))
+
+	// A stand-in for ffmpeg that records its args and emits canned output
+	script := `#!/bin/sh
+echo "$@" >> "` + dir + `/args"
+for arg; do
+	if [ "$prev" = "-i" ] && [ ! -s "$arg" ]; then
+		echo "$arg: Invalid data found when processing input" >&2
+		exit 1
+	fi
+	prev="$arg"
+done
+case "$*" in
+*s16le*) cat "` + dir + `/out.pcm" ;;
+*) cat "` + dir + `/out.png" ;;
+esac
+`
+	ffmpeg := filepath.Join(dir, "ffmpeg")
+	require.NoError(t, os.WriteFile(ffmpeg, []byte(script), 0755))
+
+	gen, _ := newGenerator(t, Images{}, &FFmpeg{Path: ffmpeg})
+	ctx := context.Background()
+	video := Source{Key: "video", Asset: &bytesAsset{"video/mp4", []byte("not really mp4")}}
+
+	thumb, err := gen.Thumbnail(ctx, video, ThumbnailOpts{Width: 320, Height: 180, At: 1500 * time.Millisecond, ContentType: "image/png"})
+	require.NoError(t, err)
+	require.Equal(t, "derived/video/thumb-320x180-1500ms.png", thumb.Key)
+	require.Equal(t, 160, thumb.Width)
+	require.Equal(t, 90, thumb.Height)
+
+	wave, err := gen.Waveform(ctx, video, WaveformOpts{Peaks: 10})
+	require.NoError(t, err)
+	require.Equal(t, time.Second, wave.Duration)
+	require.Len(t, wave.Peaks, 10)
+
+	args, err := os.ReadFile(filepath.Join(dir, "args"))
+	require.NoError(t, err)
+	require.Contains(t, string(args), "-ss 1.500 -i ")
+	require.Contains(t, string(args), "-c:v png")
+	require.Contains(t, string(args), "-ac 1 -ar 8000 -f s16le")
+
+	_, err = gen.Thumbnail(ctx, Source{Key: "corrupt", Asset: &bytesAsset{"video/mp4", nil}}, ThumbnailOpts{Width: 64, ContentType: "image/png"})
+	require.Error(t, err)
+	require.NotErrorIs(t, err, ErrUnsupported)
+
+	// A missing ffmpeg is treated as unsupported
+	gen, _ = newGenerator(t, &FFmpeg{Path: filepath.Join(dir, "missing")})
+	_, err = gen.Waveform(ctx, video, WaveformOpts{})
+	require.ErrorIs(t, err, ErrUnsupported)
+}
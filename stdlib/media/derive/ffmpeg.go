@@ -0,0 +1,146 @@
+package derive
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// FFmpeg is an Encoder that runs the ffmpeg command, decoding the audio of audio and video files and generating thumbnails
+// of images, video frames, and the artwork attached to audio files.  If ffmpeg cannot be found, it returns ErrUnsupported.
+type FFmpeg struct {
+	Path string // path of the ffmpeg binary (default "ffmpeg", found via $PATH)
+}
+
+func (enc *FFmpeg) command(ctx context.Context, args ...string) (*exec.Cmd, error) {
+	path := enc.Path
+	if path == "" {
+		path = "ffmpeg"
+	}
+	path, err := exec.LookPath(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupported, err)
+	}
+	args = append([]string{"-nostdin", "-hide_banner", "-v", "error"}, args...)
+	return exec.CommandContext(ctx, path, args...), nil
+}
+
+func (enc *FFmpeg) DecodeAudio(ctx context.Context, in *Input, sampleRate int) (io.ReadCloser, error) {
+	if !strings.HasPrefix(in.ContentType, "audio/") && !strings.HasPrefix(in.ContentType, "video/") {
+		return nil, ErrUnsupported
+	}
+	path, err := in.Path()
+	if err != nil {
+		return nil, err
+	}
+	cmd, err := enc.command(ctx, "-i", path, "-vn", "-ac", "1", "-ar", strconv.Itoa(sampleRate), "-f", "s16le", "-")
+	if err != nil {
+		return nil, err
+	}
+
+	pcm := &cmdReader{cmd: cmd}
+	cmd.Stderr = &pcm.stderr
+	if pcm.stdout, err = cmd.StdoutPipe(); err != nil {
+		return nil, err
+	}
+	if err = cmd.Start(); err != nil {
+		return nil, err
+	}
+	return pcm, nil
+}
+
+func (enc *FFmpeg) EncodeThumbnail(ctx context.Context, in *Input, opts ThumbnailOpts, w io.Writer) (int, int, error) {
+	ct := in.ContentType
+	if !strings.HasPrefix(ct, "image/") && !strings.HasPrefix(ct, "video/") && !strings.HasPrefix(ct, "audio/") {
+		return 0, 0, ErrUnsupported
+	}
+	path, err := in.Path()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var args []string
+	if opts.At > 0 {
+		args = append(args, "-ss", strconv.FormatFloat(opts.At.Seconds(), 'f', 3, 64))
+	}
+	codec := "mjpeg"
+	if opts.ContentType == "image/png" {
+		codec = "png"
+	}
+	scale := fmt.Sprintf("scale=w='min(%d,iw)':h='min(%d,ih)':force_original_aspect_ratio=decrease", opts.Width, opts.Height)
+	args = append(args, "-i", path, "-an", "-frames:v", "1", "-vf", scale, "-f", "image2pipe", "-c:v", codec)
+	if codec == "mjpeg" {
+		args = append(args, "-q:v", "3")
+	}
+	cmd, err := enc.command(ctx, append(args, "-")...)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err = cmd.Run(); err != nil {
+		return 0, 0, ffmpegError(err, &stderr)
+	}
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(stdout.Bytes()))
+	if err != nil {
+		return 0, 0, fmt.Errorf("derive: ffmpeg output: %w", err)
+	}
+	if _, err = w.Write(stdout.Bytes()); err != nil {
+		return 0, 0, err
+	}
+	return cfg.Width, cfg.Height, nil
+}
+
+func ffmpegError(err error, stderr *bytes.Buffer) error {
+	msg := strings.TrimSpace(stderr.String())
+	if i := strings.LastIndexByte(msg, '\n'); i >= 0 {
+		msg = msg[i+1:] // the last line is the most specific
+	}
+	if msg == "" {
+		return fmt.Errorf("derive: ffmpeg: %w", err)
+	}
+	return fmt.Errorf("derive: ffmpeg: %w: %s", err, msg)
+}
+
+// cmdReader reads the output of a running command, returning its failure (if any) in place of io.EOF.
+type cmdReader struct {
+	cmd     *exec.Cmd
+	stdout  io.ReadCloser
+	stderr  bytes.Buffer
+	waitErr error
+	once    sync.Once
+}
+
+func (r *cmdReader) wait() error {
+	r.once.Do(func() {
+		if err := r.cmd.Wait(); err != nil {
+			r.waitErr = ffmpegError(err, &r.stderr)
+		}
+	})
+	return r.waitErr
+}
+
+func (r *cmdReader) Read(buf []byte) (int, error) {
+	n, err := r.stdout.Read(buf)
+	if err == io.EOF {
+		if waitErr := r.wait(); waitErr != nil {
+			err = waitErr
+		}
+	}
+	return n, err
+}
+
+func (r *cmdReader) Close() error {
+	r.once.Do(func() {
+		r.cmd.Process.Kill()
+		r.cmd.Wait()
+	})
+	return nil
+}
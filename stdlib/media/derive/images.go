@@ -0,0 +1,119 @@
+package derive
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"strings"
+
+	_ "image/gif"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/media/metadata"
+)
+
+// Images is an Encoder implemented natively that generates thumbnails of JPEG, PNG, and GIF images and of the front cover
+// embedded in audio files (see package media/metadata).  It does not decode audio.
+type Images struct {
+	JPEGQuality int // default 85
+}
+
+func (enc Images) DecodeAudio(ctx context.Context, in *Input, sampleRate int) (io.ReadCloser, error) {
+	return nil, ErrUnsupported
+}
+
+func (enc Images) EncodeThumbnail(ctx context.Context, in *Input, opts ThumbnailOpts, w io.Writer) (int, int, error) {
+	var src io.Reader
+	switch {
+	case in.ContentType == "image/jpeg", in.ContentType == "image/png", in.ContentType == "image/gif":
+		src = in.Reader
+	case strings.HasPrefix(in.ContentType, "audio/"):
+		md, _ := metadata.Extract(in.Reader, metadata.Opts{})
+		if md == nil || md.FrontCover() == nil {
+			return 0, 0, ErrUnsupported // leave it to another encoder (e.g. one reading an attached picture stream)
+		}
+		cover := md.FrontCover()
+		src = bytes.NewReader(cover.Data)
+	default:
+		return 0, 0, ErrUnsupported
+	}
+
+	img, _, err := image.Decode(src)
+	if err != nil {
+		if err == image.ErrFormat {
+			err = ErrUnsupported
+		}
+		return 0, 0, err
+	}
+	if err = ctx.Err(); err != nil {
+		return 0, 0, err
+	}
+
+	thumb := scaleToFit(img, opts.Width, opts.Height)
+	switch opts.ContentType {
+	case "image/png":
+		err = png.Encode(w, thumb)
+	default:
+		quality := enc.JPEGQuality
+		if quality <= 0 {
+			quality = 85
+		}
+		err = jpeg.Encode(w, thumb, &jpeg.Options{Quality: quality})
+	}
+	bounds := thumb.Bounds()
+	return bounds.Dx(), bounds.Dy(), err
+}
+
+// fitWithin returns the size of srcW x srcH scaled to fit within maxW x maxH, preserving its aspect ratio and never scaling up.
+func fitWithin(srcW, srcH, maxW, maxH int) (int, int) {
+	if srcW <= maxW && srcH <= maxH {
+		return srcW, srcH
+	}
+	w, h := maxW, srcH*maxW/srcW
+	if h > maxH {
+		w, h = srcW*maxH/srcH, maxH
+	}
+	return max(w, 1), max(h, 1)
+}
+
+// scaleToFit returns img scaled to fit within maxW x maxH, where each output pixel is the average of the source pixels it covers.
+func scaleToFit(img image.Image, maxW, maxH int) image.Image {
+	bounds := img.Bounds()
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+	dstW, dstH := fitWithin(srcW, srcH, maxW, maxH)
+
+	src, ok := img.(*image.RGBA)
+	if !ok {
+		src = image.NewRGBA(image.Rect(0, 0, srcW, srcH))
+		draw.Draw(src, src.Bounds(), img, bounds.Min, draw.Src)
+	}
+	if dstW == srcW && dstH == srcH {
+		return src
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dstW, dstH))
+	for y := 0; y < dstH; y++ {
+		y0, y1 := y*srcH/dstH, max((y+1)*srcH/dstH, y*srcH/dstH+1)
+		for x := 0; x < dstW; x++ {
+			x0, x1 := x*srcW/dstW, max((x+1)*srcW/dstW, x*srcW/dstW+1)
+			var r, g, b, a, n uint32
+			for sy := y0; sy < y1; sy++ {
+				row := src.Pix[src.PixOffset(src.Rect.Min.X+x0, src.Rect.Min.Y+sy):]
+				for sx := 0; sx < x1-x0; sx++ {
+					px := row[sx*4 : sx*4+4]
+					r += uint32(px[0])
+					g += uint32(px[1])
+					b += uint32(px[2])
+					a += uint32(px[3])
+					n++
+				}
+			}
+			dst.SetRGBA(x, y, color.RGBA{uint8(r / n), uint8(g / n), uint8(b / n), uint8(a / n)})
+		}
+	}
+	return dst
+}
@@ -0,0 +1,69 @@
+package derive
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"time"
+)
+
+// computeWaveform reads mono s16le PCM at the given sample rate, returning the peaks of numPeaks equal slices of it.
+//
+// Since the length of the audio is not known until it is read, the peak of each short block of samples is retained, and the
+// blocks are then combined into the requested number of slices.
+func computeWaveform(pcm io.Reader, sampleRate, numPeaks int) (*Waveform, error) {
+	blockLen := max(sampleRate/100, 1) // 10ms
+	var (
+		blocks  []uint16
+		peak    uint16
+		inBlock int
+		samples int64
+		sample  [2]byte
+	)
+
+	r := bufio.NewReaderSize(pcm, 32<<10)
+	for {
+		if _, err := io.ReadFull(r, sample[:]); err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				break
+			}
+			return nil, err
+		}
+		s := int16(binary.LittleEndian.Uint16(sample[:]))
+		abs := uint16(s)
+		if s < 0 {
+			abs = uint16(-int32(s))
+		}
+		peak = max(peak, abs)
+		samples++
+		if inBlock++; inBlock == blockLen {
+			blocks = append(blocks, peak)
+			peak, inBlock = 0, 0
+		}
+	}
+	if inBlock > 0 {
+		blocks = append(blocks, peak)
+	}
+
+	wave := &Waveform{
+		Duration: time.Duration(samples) * time.Second / time.Duration(sampleRate),
+		Peaks:    make([]uint8, numPeaks),
+	}
+	if len(blocks) == 0 {
+		return wave, nil
+	}
+
+	// Each slice spans the blocks [i*N/numPeaks, (i+1)*N/numPeaks), spanning at least one block when there are fewer blocks than peaks.
+	N := len(blocks)
+	for i := range wave.Peaks {
+		start := i * N / numPeaks
+		end := max((i+1)*N/numPeaks, start+1)
+		var slicePeak uint16
+		for _, b := range blocks[start:min(end, N)] {
+			slicePeak = max(slicePeak, b)
+		}
+		wave.Peaks[i] = uint8(min(int(slicePeak)*255/32767, 255))
+	}
+	return wave, nil
+}
@@ -0,0 +1,343 @@
+// Package hls serves media assets as HTTP Live Streaming (HLS) playlists, segmented by ffmpeg as they are played, so that a
+// pinned media item plays natively on iOS and tvOS clients and in standard web players.
+//
+// A Packager is a media.Publisher: publishing an asset returns the URL of its playlist, and packaging starts when the playlist
+// is first requested.  The playlist is an "event" playlist that grows as segments are produced, so playback begins once the
+// first segment is ready, and it ends with EXT-X-ENDLIST once the whole asset is segmented.  Segments are fragmented MP4,
+// where a source whose codecs HLS supports is segmented as-is and any other source is transcoded (see package media/transcode).
+// A Tag linking to a playlist (e.g. from amp.PublishAssetTag) should have its ContentType set to PlaylistContentType.
+//
+// Routes, relative to Opts.Prefix:
+//
+//	GET {token}/index.m3u8    the playlist of a published asset
+//	GET {token}/{segment}     the init segment or a media segment listed in the playlist
+package hls
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/media"
+	"github.com/amp-3d/amp-sdk-go/stdlib/media/transcode"
+	"github.com/amp-3d/amp-sdk-go/stdlib/task"
+)
+
+const (
+	PlaylistContentType = "application/vnd.apple.mpegurl"
+	PlaylistName        = "index.m3u8"
+
+	DefaultPrefix          = "/hls/"
+	DefaultHostAddr        = "localhost"
+	DefaultExpiry          = 10 * time.Minute
+	DefaultSegmentDuration = 6 * time.Second
+	DefaultStartTimeout    = 30 * time.Second
+)
+
+var (
+	ErrPackagingFailed = errors.New("hls: packaging failed")
+)
+
+// Caps are the formats HLS plays as fragmented MP4 segments, so a source of these codecs is segmented without transcoding.
+var Caps = transcode.Caps{
+	{ContentType: "video/mp4", Codecs: []string{"avc1", "hvc1", "mp4a", "ac-3", "ec-3"}},
+	{ContentType: "audio/mp4", Codecs: []string{"mp4a", "ac-3", "ec-3"}},
+}
+
+// Opts configures a Packager.
+type Opts struct {
+	Prefix          string                // URL path the packager's routes are relative to (default DefaultPrefix)
+	Scheme          string                // scheme of generated URLs (default "http")
+	HostAddr        string                // host[:port] of generated URLs when media.PublishOpts.HostAddr is empty (default DefaultHostAddr)
+	Expiry          time.Duration         // idle expiry of a published asset when media.PublishOpts.Expiry <= 0 (default DefaultExpiry)
+	Dir             string                // where segments are written while an asset is published (default os.TempDir())
+	SegmentDuration time.Duration         // target duration of each segment (default DefaultSegmentDuration)
+	StartTimeout    time.Duration         // how long a playlist request waits for the first segment (default DefaultStartTimeout)
+	Transcoder      *transcode.Transcoder // probes and transcodes sources (default a zero Transcoder, using ffmpeg and ffprobe in $PATH)
+}
+
+// Packager implements media.Publisher and is an http.Handler serving the routes described in the package doc.
+type Packager struct {
+	opts Opts
+	ctx  task.Context
+
+	mu      sync.Mutex
+	streams map[string]*stream // by token
+}
+
+type stream struct {
+	pkg       *Packager
+	asset     media.Asset
+	ctx       task.Context
+	dir       string
+	startOnce sync.Once
+	exited    chan struct{} // closed once ffmpeg exits
+	cmd       *exec.Cmd
+	err       error // set before exited is closed
+}
+
+// New starts a Packager as a child of parent, which expires all published assets when closed.
+func New(parent task.Context, opts Opts) (*Packager, error) {
+	if opts.Prefix == "" {
+		opts.Prefix = DefaultPrefix
+	}
+	if !strings.HasSuffix(opts.Prefix, "/") {
+		opts.Prefix += "/"
+	}
+	if opts.Scheme == "" {
+		opts.Scheme = "http"
+	}
+	if opts.HostAddr == "" {
+		opts.HostAddr = DefaultHostAddr
+	}
+	if opts.Expiry <= 0 {
+		opts.Expiry = DefaultExpiry
+	}
+	if opts.Dir == "" {
+		opts.Dir = os.TempDir()
+	}
+	if opts.SegmentDuration <= 0 {
+		opts.SegmentDuration = DefaultSegmentDuration
+	}
+	if opts.StartTimeout <= 0 {
+		opts.StartTimeout = DefaultStartTimeout
+	}
+	if opts.Transcoder == nil {
+		opts.Transcoder = &transcode.Transcoder{}
+	}
+	pkg := &Packager{
+		opts:    opts,
+		streams: make(map[string]*stream),
+	}
+
+	var err error
+	pkg.ctx, err = parent.StartChild(&task.Task{
+		Label: "hls packager",
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pkg, nil
+}
+
+// PublishAsset implements media.Publisher by returning the URL of the asset's playlist, served until it goes unrequested for
+// its expiry period.  The asset must be audio or video.
+func (pkg *Packager) PublishAsset(asset media.Asset, opts media.PublishOpts) (string, error) {
+	if ct := asset.ContentType(); !strings.HasPrefix(ct, "audio/") && !strings.HasPrefix(ct, "video/") {
+		return "", errors.New("hls: asset is not audio or video: " + ct)
+	}
+	expiry := opts.Expiry
+	if expiry <= 0 {
+		expiry = pkg.opts.Expiry
+	}
+	token, err := newToken()
+	if err != nil {
+		return "", err
+	}
+	dir, err := os.MkdirTemp(pkg.opts.Dir, "hls-*")
+	if err != nil {
+		return "", err
+	}
+
+	st := &stream{
+		pkg:    pkg,
+		asset:  asset,
+		dir:    dir,
+		exited: make(chan struct{}),
+	}
+	st.ctx, err = pkg.ctx.StartChild(&task.Task{
+		Label:       "hls: " + asset.Label(),
+		IdleTimeout: expiry,
+		OnStart: func(ctx task.Context) error {
+			pkg.mu.Lock()
+			pkg.streams[token] = st
+			pkg.mu.Unlock()
+			return asset.OnStart(ctx)
+		},
+		OnClosing: func() {
+			pkg.mu.Lock()
+			delete(pkg.streams, token)
+			pkg.mu.Unlock()
+			st.stop()
+		},
+		OnClosed: func() {
+			if opts.OnExpired != nil {
+				opts.OnExpired()
+			}
+		},
+	})
+	if err != nil {
+		os.RemoveAll(dir)
+		return "", err
+	}
+
+	hostAddr := opts.HostAddr
+	if hostAddr == "" {
+		hostAddr = pkg.opts.HostAddr
+	}
+	u := url.URL{
+		Scheme: pkg.opts.Scheme,
+		Host:   hostAddr,
+		Path:   pkg.opts.Prefix + token + "/" + PlaylistName,
+	}
+	return u.String(), nil
+}
+
+// segmentName matches the files a stream serves, so that a request cannot name any other file.
+var segmentName = regexp.MustCompile(`^(index\.m3u8|init\.mp4|seg[0-9]+\.m4s)$`)
+
+func (pkg *Packager) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	route, ok := strings.CutPrefix(r.URL.Path, pkg.opts.Prefix)
+	token, name, hasName := strings.Cut(route, "/")
+	if !ok || !hasName || !segmentName.MatchString(name) {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	pkg.mu.Lock()
+	st := pkg.streams[token]
+	pkg.mu.Unlock()
+	if st == nil {
+		http.NotFound(w, r)
+		return
+	}
+	task.ReportActivity(st.ctx)
+	st.startOnce.Do(st.start)
+
+	path := filepath.Join(st.dir, name)
+	if name == PlaylistName {
+		if err := st.awaitPlaylist(r.Context(), path); err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		w.Header().Set("Content-Type", PlaylistContentType)
+		w.Header().Set("Cache-Control", "no-cache") // it grows until packaging completes
+	} else {
+		w.Header().Set("Content-Type", "video/mp4")
+	}
+	http.ServeFile(w, r, path)
+}
+
+// awaitPlaylist waits until ffmpeg has written the playlist (listing at least the first segment), ffmpeg fails, or the wait times out.
+func (st *stream) awaitPlaylist(ctx context.Context, path string) error {
+	timeout := time.NewTimer(st.pkg.opts.StartTimeout)
+	defer timeout.Stop()
+	poll := time.NewTicker(50 * time.Millisecond)
+	defer poll.Stop()
+	for {
+		if _, err := os.Stat(path); err == nil {
+			return nil
+		}
+		select {
+		case <-poll.C:
+		case <-st.exited:
+			if _, err := os.Stat(path); err == nil {
+				return nil
+			}
+			if st.err != nil {
+				return st.err
+			}
+			return ErrPackagingFailed
+		case <-timeout.C:
+			return errors.New("hls: timed out waiting for the first segment")
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-st.ctx.Closing():
+			return errors.New("hls: asset expired")
+		}
+	}
+}
+
+// start plans how the asset is segmented and starts ffmpeg, which writes the playlist and segments into st.dir.
+func (st *stream) start() {
+	err := st.run()
+	if err != nil {
+		st.err = err
+		close(st.exited)
+	}
+}
+
+func (st *stream) run() error {
+	opts := &st.pkg.opts
+	ctx, cancel := context.WithTimeout(st.ctx, opts.StartTimeout)
+	defer cancel()
+	plan, err := opts.Transcoder.Plan(ctx, st.asset, Caps)
+	if err != nil {
+		return err
+	}
+
+	source, err := st.asset.NewAssetReader()
+	if err != nil {
+		return err
+	}
+	args := append(plan.MapArgs(), plan.CodecArgs()...)
+	args = append(args,
+		"-f", "hls",
+		"-hls_time", strconv.FormatFloat(opts.SegmentDuration.Seconds(), 'f', -1, 64),
+		"-hls_playlist_type", "event",
+		"-hls_segment_type", "fmp4",
+		"-hls_fmp4_init_filename", "init.mp4",
+		"-hls_flags", "temp_file+independent_segments",
+		"-hls_segment_filename", filepath.Join(st.dir, "seg%05d.m4s"),
+		filepath.Join(st.dir, PlaylistName),
+	)
+	cmd := opts.Transcoder.Command(source, args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err = cmd.Start(); err != nil {
+		source.Close()
+		return err
+	}
+	st.cmd = cmd
+
+	go func() {
+		if err := cmd.Wait(); err != nil {
+			msg := strings.TrimSpace(stderr.String())
+			if i := strings.LastIndexByte(msg, '\n'); i >= 0 {
+				msg = msg[i+1:]
+			}
+			st.err = fmt.Errorf("%w: %v: %s", ErrPackagingFailed, err, msg)
+		}
+		source.Close()
+		close(st.exited)
+	}()
+	return nil
+}
+
+// stop stops ffmpeg (if running) and removes the stream's files.
+func (st *stream) stop() {
+	st.startOnce.Do(func() {
+		close(st.exited) // never started
+	})
+	if st.cmd != nil {
+		st.cmd.Process.Kill()
+	}
+	<-st.exited
+	os.RemoveAll(st.dir)
+}
+
+func newToken() (string, error) {
+	var buf [16]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf[:]), nil
+}
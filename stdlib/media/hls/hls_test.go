@@ -0,0 +1,189 @@
+package hls
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/media"
+	"github.com/amp-3d/amp-sdk-go/stdlib/media/transcode"
+	"github.com/amp-3d/amp-sdk-go/stdlib/task"
+	"github.com/stretchr/testify/require"
+)
+
+type bytesAsset struct {
+	contentType string
+	data        []byte
+}
+
+func (asset *bytesAsset) Label() string                  { return "test asset" }
+func (asset *bytesAsset) ContentType() string            { return asset.contentType }
+func (asset *bytesAsset) OnStart(ctx task.Context) error { return nil }
+
+func (asset *bytesAsset) NewAssetReader() (media.AssetReader, error) {
+	return nopCloser{bytes.NewReader(asset.data)}, nil
+}
+
+type nopCloser struct {
+	*bytes.Reader
+}
+
+func (nopCloser) Close() error { return nil }
+
+// probed returns content that the fake ffprobe (see startPackager) reports as having streams of the given codecs.
+func probed(codecs ...string) []byte {
+	var streams []string
+	for _, codec := range codecs {
+		codecType := "audio"
+		if codec == "h264" {
+			codecType = "video"
+		}
+		streams = append(streams, `{"codec_name": "`+codec+`", "codec_type": "`+codecType+`"}`)
+	}
+	return []byte(`{"streams": [` + strings.Join(streams, ",") + `]}`)
+}
+
+// startPackager returns a Packager running stand-ins for ffprobe, which outputs its input as-is, and ffmpeg, which records its
+// args in the returned file and writes a playlist of two segments, the second after a delay.  If its input contains "fail", it fails.
+func startPackager(t *testing.T) (*Packager, string) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires a shell")
+	}
+	tools := t.TempDir()
+	argsFile := filepath.Join(tools, "args")
+	ffprobe := filepath.Join(tools, "ffprobe")
+	require.NoError(t, os.WriteFile(ffprobe, []byte(`#!/bin/sh
+cat -
+`), 0755))
+	ffmpeg := filepath.Join(tools, "ffmpeg")
+	require.NoError(t, os.WriteFile(ffmpeg, []byte(`#!/bin/sh
+echo "$@" > "`+argsFile+`"
+for arg; do playlist="$arg"; done
+dir=$(dirname "$playlist")
+if cat - | grep -q fail; then
+	echo "pipe:0: Invalid data found when processing input" >&2
+	exit 1
+fi
+printf 'init' > "$dir/init.mp4"
+printf 'seg0' > "$dir/seg00000.m4s"
+printf '#EXTM3U\n#EXT-X-PLAYLIST-TYPE:EVENT\n#EXT-X-MAP:URI="init.mp4"\n#EXTINF:6.0,\nseg00000.m4s\n' > "$dir/index.m3u8.tmp"
+mv "$dir/index.m3u8.tmp" "$dir/index.m3u8"
+sleep 0.2
+printf 'seg1' > "$dir/seg00001.m4s"
+printf '#EXTINF:4.0,\nseg00001.m4s\n#EXT-X-ENDLIST\n' >> "$dir/index.m3u8"
+`), 0755))
+
+	ctx, err := task.Start(&task.Task{Label: "test"})
+	require.NoError(t, err)
+	t.Cleanup(func() { ctx.Close() })
+
+	pkg, err := New(ctx, Opts{
+		Dir:          t.TempDir(),
+		StartTimeout: 5 * time.Second,
+		Transcoder:   &transcode.Transcoder{FFmpegPath: ffmpeg, FFprobePath: ffprobe},
+	})
+	require.NoError(t, err)
+	return pkg, argsFile
+}
+
+func get(t *testing.T, pkg *Packager, rawURL string) (*http.Response, string) {
+	u, err := url.Parse(rawURL)
+	require.NoError(t, err)
+	rec := httptest.NewRecorder()
+	pkg.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, u.Path, nil))
+	resp := rec.Result()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp, string(body)
+}
+
+func TestPackager(t *testing.T) {
+	pkg, argsFile := startPackager(t)
+	video := &bytesAsset{contentType: "video/mp4", data: probed("h264", "aac")}
+
+	playlistURL, err := pkg.PublishAsset(video, media.PublishOpts{HostAddr: "media.local:5192"})
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(playlistURL, "http://media.local:5192/hls/"))
+	require.True(t, strings.HasSuffix(playlistURL, "/index.m3u8"))
+
+	// The playlist is served once it lists the first segment, and grows until packaging completes
+	resp, body := get(t, pkg, playlistURL)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, PlaylistContentType, resp.Header.Get("Content-Type"))
+	require.Contains(t, body, "seg00000.m4s")
+	require.NotContains(t, body, "#EXT-X-ENDLIST")
+
+	base := strings.TrimSuffix(playlistURL, PlaylistName)
+	resp, body = get(t, pkg, base+"init.mp4")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "init", body)
+	resp, body = get(t, pkg, base+"seg00000.m4s")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "seg0", body)
+
+	require.Eventually(t, func() bool {
+		_, body = get(t, pkg, playlistURL)
+		return strings.Contains(body, "#EXT-X-ENDLIST")
+	}, 5*time.Second, 20*time.Millisecond)
+	_, body = get(t, pkg, base+"seg00001.m4s")
+	require.Equal(t, "seg1", body)
+
+	// A source HLS plays is segmented as-is
+	args, err := os.ReadFile(argsFile)
+	require.NoError(t, err)
+	require.Contains(t, string(args), "-i pipe:0 -map 0:v:0 -map 0:a:0? -c copy -f hls -hls_time 6 -hls_playlist_type event -hls_segment_type fmp4")
+
+	// Only the stream's files are served
+	resp, _ = get(t, pkg, base+"args")
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+	resp, _ = get(t, pkg, base+"..%2Fargs")
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+	resp, _ = get(t, pkg, "http://localhost/hls/unknown/index.m3u8")
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	// Packaging stops and files are removed when the asset expires
+	pkg.mu.Lock()
+	require.Len(t, pkg.streams, 1)
+	var st *stream
+	for _, st = range pkg.streams {
+	}
+	pkg.mu.Unlock()
+	st.ctx.Close()
+	<-st.ctx.Done()
+	_, err = os.Stat(st.dir)
+	require.True(t, os.IsNotExist(err))
+	resp, _ = get(t, pkg, playlistURL)
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestTranscoded(t *testing.T) {
+	pkg, argsFile := startPackager(t)
+
+	flac := &bytesAsset{contentType: "audio/flac", data: probed("flac")}
+	playlistURL, err := pkg.PublishAsset(flac, media.PublishOpts{})
+	require.NoError(t, err)
+	resp, _ := get(t, pkg, playlistURL)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	args, err := os.ReadFile(argsFile)
+	require.NoError(t, err)
+	require.Contains(t, string(args), "-map 0:a:0 -c:a aac -b:a 192k -f hls")
+
+	// ffmpeg failing is reported to the client
+	failing := &bytesAsset{contentType: "video/x-matroska", data: probed("h264", "fail")}
+	failingURL, err := pkg.PublishAsset(failing, media.PublishOpts{})
+	require.NoError(t, err)
+	resp, body := get(t, pkg, failingURL)
+	require.Equal(t, http.StatusBadGateway, resp.StatusCode)
+	require.Contains(t, body, "Invalid data found")
+
+	_, err = pkg.PublishAsset(&bytesAsset{contentType: "image/png"}, media.PublishOpts{})
+	require.Error(t, err)
+}
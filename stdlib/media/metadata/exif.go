@@ -0,0 +1,348 @@
+package metadata
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"time"
+)
+
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// EXIF (TIFF) tags
+const (
+	tagImageWidth      = 0x0100
+	tagImageLength     = 0x0101
+	tagMake            = 0x010F
+	tagModel           = 0x0110
+	tagOrientation     = 0x0112
+	tagDateTime        = 0x0132
+	tagThumbnailOffset = 0x0201
+	tagThumbnailLength = 0x0202
+	tagExifIFD         = 0x8769
+	tagGPSIFD          = 0x8825
+	tagDateTimeOrig    = 0x9003
+	tagOffsetTimeOrig  = 0x9011
+	tagPixelXDimension = 0xA002
+	tagPixelYDimension = 0xA003
+
+	tagGPSLatitudeRef  = 1
+	tagGPSLatitude     = 2
+	tagGPSLongitudeRef = 3
+	tagGPSLongitude    = 4
+)
+
+// maxTIFFRead bounds how much of a TIFF file is read to parse its IFDs, where values beyond this are ignored.
+const maxTIFFRead = 4 << 20
+
+// parseJPEG parses the JPEG markers preceding the image data: APP1 for EXIF and SOFn for dimensions.
+func parseJPEG(src *source, info *Info) error {
+	offset := int64(2)
+	for {
+		header, err := src.readAt(offset, 4)
+		if err != nil {
+			return err
+		}
+		if header[0] != 0xFF {
+			return ErrCorrupt
+		}
+		marker := header[1]
+		if marker == 0xFF { // fill byte
+			offset++
+			continue
+		}
+		if marker == 0xD9 || marker == 0xDA { // EOI or SOS
+			return nil
+		}
+		if marker == 0x01 || marker >= 0xD0 && marker <= 0xD7 { // markers without a length
+			offset += 2
+			continue
+		}
+		length := int64(be.Uint16(header[2:]))
+		if length < 2 {
+			return ErrCorrupt
+		}
+		switch {
+		case marker == 0xE1:
+			segment, err := src.readAt(offset+4, length-2)
+			if err != nil {
+				return err
+			}
+			if exif, ok := bytes.CutPrefix(segment, []byte("Exif\x00\x00")); ok {
+				parseTIFF(src, info, exif)
+			}
+		case marker >= 0xC0 && marker <= 0xCF && marker != 0xC4 && marker != 0xC8 && marker != 0xCC:
+			frame, err := src.readAt(offset+4, min(length-2, 5))
+			if err != nil {
+				return err
+			}
+			if len(frame) == 5 {
+				info.Height, info.Width = int(be.Uint16(frame[1:])), int(be.Uint16(frame[3:]))
+			}
+		}
+		offset += 2 + length
+	}
+}
+
+// parsePNG parses the chunks preceding the image data: IHDR for dimensions and eXIf for EXIF.
+func parsePNG(src *source, info *Info) error {
+	offset := int64(len(pngSignature))
+	for {
+		header, err := src.readAt(offset, 8)
+		if err != nil {
+			return err
+		}
+		length, chunkType := int64(be.Uint32(header)), string(header[4:8])
+		switch chunkType {
+		case "IHDR":
+			chunk, err := src.readAt(offset+8, min(length, 8))
+			if err != nil {
+				return err
+			}
+			if len(chunk) == 8 {
+				info.Width, info.Height = int(be.Uint32(chunk)), int(be.Uint32(chunk[4:]))
+			}
+		case "eXIf":
+			chunk, err := src.readAt(offset+8, length)
+			if err != nil {
+				return err
+			}
+			parseTIFF(src, info, chunk)
+		case "IDAT", "IEND":
+			return nil
+		}
+		offset += 12 + length // length, type, data, CRC
+	}
+}
+
+func parseTIFFFile(src *source, info *Info) error {
+	data, err := src.readAt(0, min(src.size, maxTIFFRead))
+	if err != nil {
+		return err
+	}
+	parseTIFF(src, info, data)
+	return nil
+}
+
+// parseTIFF parses the IFDs of a TIFF structure, as in an EXIF block or a TIFF file, ignoring entries that lie outside of it.
+func parseTIFF(src *source, info *Info, data []byte) {
+	t := tiff{data: data}
+	switch {
+	case bytes.HasPrefix(data, []byte("II*\x00")):
+		t.order = binary.LittleEndian
+	case bytes.HasPrefix(data, []byte("MM\x00*")):
+		t.order = binary.BigEndian
+	default:
+		return
+	}
+
+	ifd0 := t.ifd(t.uint32(4))
+	for _, e := range ifd0.entries {
+		switch e.tag {
+		case tagImageWidth:
+			setInt(&info.Width, t.uint(e))
+		case tagImageLength:
+			setInt(&info.Height, t.uint(e))
+		case tagMake:
+			info.CameraMake = t.ascii(e)
+		case tagModel:
+			info.CameraModel = t.ascii(e)
+		case tagOrientation:
+			info.Orientation = t.uint(e)
+		case tagDateTime:
+			if info.TakenAt.IsZero() {
+				info.TakenAt = parseExifTime(t.ascii(e), "")
+			}
+		case tagExifIFD:
+			parseExifIFD(&t, info, t.ifd(uint32(t.uint(e))))
+		case tagGPSIFD:
+			parseGPSIFD(&t, info, t.ifd(uint32(t.uint(e))))
+		}
+	}
+
+	// IFD1 holds the thumbnail
+	if ifd0.next != 0 {
+		var offset, length int
+		for _, e := range t.ifd(ifd0.next).entries {
+			switch e.tag {
+			case tagThumbnailOffset:
+				offset = t.uint(e)
+			case tagThumbnailLength:
+				length = t.uint(e)
+			}
+		}
+		if length > 0 && offset >= 0 && offset+length <= len(data) && src.wantArtwork(int64(length)) {
+			info.addArtwork(Artwork{
+				Type:        PictureOther,
+				Description: "thumbnail",
+				Data:        bytes.Clone(data[offset : offset+length]),
+			})
+		}
+	}
+}
+
+func parseExifIFD(t *tiff, info *Info, ifd tiffIFD) {
+	var taken, zone string
+	for _, e := range ifd.entries {
+		switch e.tag {
+		case tagDateTimeOrig:
+			taken = t.ascii(e)
+		case tagOffsetTimeOrig:
+			zone = t.ascii(e)
+		case tagPixelXDimension:
+			setInt(&info.Width, t.uint(e))
+		case tagPixelYDimension:
+			setInt(&info.Height, t.uint(e))
+		}
+	}
+	if at := parseExifTime(taken, zone); !at.IsZero() {
+		info.TakenAt = at // preferred over DateTime, which is when the file was last changed
+	}
+}
+
+func parseGPSIFD(t *tiff, info *Info, ifd tiffIFD) {
+	var latRef, lonRef string
+	var lat, lon []float64
+	for _, e := range ifd.entries {
+		switch e.tag {
+		case tagGPSLatitudeRef:
+			latRef = t.ascii(e)
+		case tagGPSLatitude:
+			lat = t.rationals(e)
+		case tagGPSLongitudeRef:
+			lonRef = t.ascii(e)
+		case tagGPSLongitude:
+			lon = t.rationals(e)
+		}
+	}
+	if len(lat) != 3 || len(lon) != 3 {
+		return
+	}
+	info.Latitude = lat[0] + lat[1]/60 + lat[2]/3600
+	info.Longitude = lon[0] + lon[1]/60 + lon[2]/3600
+	if latRef == "S" {
+		info.Latitude = -info.Latitude
+	}
+	if lonRef == "W" {
+		info.Longitude = -info.Longitude
+	}
+	info.HasLocation = true
+}
+
+// parseExifTime parses an EXIF date (e.g. "2023:07:04 18:30:00") with an optional offset (e.g. "+02:00").
+func parseExifTime(value, zone string) time.Time {
+	if zone != "" {
+		if at, err := time.Parse("2006:01:02 15:04:05-07:00", value+zone); err == nil {
+			return at.UTC()
+		}
+	}
+	at, _ := time.ParseInLocation("2006:01:02 15:04:05", value, time.Local)
+	return at
+}
+
+func setInt(dst *int, value int) {
+	if *dst == 0 && value > 0 {
+		*dst = value
+	}
+}
+
+type tiff struct {
+	data  []byte
+	order binary.ByteOrder
+}
+
+type tiffEntry struct {
+	tag, typ uint16
+	count    uint32
+	value    []byte // the 4 byte value / offset field
+}
+
+type tiffIFD struct {
+	entries []tiffEntry
+	next    uint32 // offset of the next IFD, or 0
+}
+
+// tiffTypeSizes is the size of each TIFF field type: BYTE, ASCII, SHORT, LONG, RATIONAL, SBYTE, UNDEFINED, SSHORT, SLONG, SRATIONAL
+var tiffTypeSizes = [...]int{1: 1, 2: 1, 3: 2, 4: 4, 5: 8, 6: 1, 7: 1, 8: 2, 9: 4, 10: 8}
+
+func (t *tiff) uint32(offset int) uint32 {
+	if offset < 0 || offset+4 > len(t.data) {
+		return 0
+	}
+	return t.order.Uint32(t.data[offset:])
+}
+
+func (t *tiff) ifd(offset uint32) (ifd tiffIFD) {
+	start := int(offset)
+	if offset == 0 || start+2 > len(t.data) {
+		return
+	}
+	count := int(t.order.Uint16(t.data[start:]))
+	end := start + 2 + 12*count
+	if end+4 > len(t.data) {
+		return
+	}
+	for i := start + 2; i < end; i += 12 {
+		ifd.entries = append(ifd.entries, tiffEntry{
+			tag:   t.order.Uint16(t.data[i:]),
+			typ:   t.order.Uint16(t.data[i+2:]),
+			count: t.order.Uint32(t.data[i+4:]),
+			value: t.data[i+8 : i+12],
+		})
+	}
+	if ifd.next = t.order.Uint32(t.data[end:]); int(ifd.next) == start {
+		ifd.next = 0 // a loop
+	}
+	return
+}
+
+// valueBytes returns the value of the given entry, which is held in the entry if it fits or is otherwise at the offset it holds.
+func (t *tiff) valueBytes(e tiffEntry) []byte {
+	if int(e.typ) >= len(tiffTypeSizes) || tiffTypeSizes[e.typ] == 0 {
+		return nil
+	}
+	size := uint64(tiffTypeSizes[e.typ]) * uint64(e.count)
+	if size <= 4 {
+		return e.value[:size]
+	}
+	offset := uint64(t.order.Uint32(e.value))
+	if offset+size > uint64(len(t.data)) {
+		return nil
+	}
+	return t.data[offset : offset+size]
+}
+
+func (t *tiff) uint(e tiffEntry) int {
+	b := t.valueBytes(e)
+	switch {
+	case e.typ == 3 && len(b) >= 2:
+		return int(t.order.Uint16(b))
+	case e.typ == 4 && len(b) >= 4:
+		return int(t.order.Uint32(b))
+	}
+	return 0
+}
+
+func (t *tiff) ascii(e tiffEntry) string {
+	if e.typ != 2 {
+		return ""
+	}
+	b, _, _ := bytes.Cut(t.valueBytes(e), []byte{0})
+	return strings.TrimSpace(string(b))
+}
+
+func (t *tiff) rationals(e tiffEntry) []float64 {
+	if e.typ != 5 {
+		return nil
+	}
+	b := t.valueBytes(e)
+	values := make([]float64, 0, len(b)/8)
+	for ; len(b) >= 8; b = b[8:] {
+		num, den := t.order.Uint32(b), t.order.Uint32(b[4:])
+		if den == 0 {
+			return nil
+		}
+		values = append(values, float64(num)/float64(den))
+	}
+	return values
+}
@@ -0,0 +1,434 @@
+package metadata
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+// id3Fields maps ID3v2.3/2.4 text frames (and their ID3v2.2 equivalents) to fields.
+var id3Fields = map[string]field{
+	"TIT2": fieldTitle, "TT2": fieldTitle,
+	"TPE1": fieldArtist, "TP1": fieldArtist,
+	"TALB": fieldAlbum, "TAL": fieldAlbum,
+	"TPE2": fieldAlbumArtist, "TP2": fieldAlbumArtist,
+	"TCOM": fieldComposer, "TCM": fieldComposer,
+	"TCON": fieldGenre, "TCO": fieldGenre,
+	"TDRC": fieldDate, "TYER": fieldDate, "TYE": fieldDate, "TORY": fieldDate, "TDOR": fieldDate,
+	"TRCK": fieldTrack, "TRK": fieldTrack,
+	"TPOS": fieldDisc, "TPA": fieldDisc,
+}
+
+// parseMP3 parses an ID3v2 tag (if present) followed by MPEG audio frames, then an ID3v1 tag (if present).
+// An ID3v2 tag may also precede other formats (e.g. FLAC), in which case that format is parsed instead of MPEG audio.
+func parseMP3(src *source, info *Info) error {
+	audioStart, err := parseID3v2(src, info)
+	if err != nil {
+		return err
+	}
+	if head, _ := src.readAt(audioStart, min(4, src.size-audioStart)); bytes.Equal(head, []byte("fLaC")) {
+		info.ContentType = "audio/flac"
+		return parseFLACAt(src, info, audioStart)
+	}
+
+	audioEnd := src.size
+	if src.size >= 128 {
+		if v1, err := src.readAt(src.size-128, 128); err == nil && bytes.HasPrefix(v1, []byte("TAG")) {
+			audioEnd -= 128
+			defer parseID3v1(v1, info) // ID3v2 fields take precedence
+		}
+	}
+	parseMPEGFrames(src, info, audioStart, audioEnd)
+	return nil
+}
+
+// parseID3v2 parses the ID3v2 tag at the start of the file (if any), returning the offset following it.
+func parseID3v2(src *source, info *Info) (int64, error) {
+	header, err := src.readAt(0, min(10, src.size))
+	if err != nil || len(header) < 10 || string(header[:3]) != "ID3" {
+		return 0, nil
+	}
+	version, flags := header[3], header[5]
+	size := int64(syncsafe(header[6:10]))
+	end := 10 + size
+	if flags&0x10 != 0 {
+		end += 10 // footer
+	}
+	if version < 2 || version > 4 {
+		return end, nil
+	}
+	tag, err := src.readAt(10, size)
+	if err != nil {
+		return 0, err
+	}
+	if version < 4 && flags&0x80 != 0 {
+		tag = unsynchronise(tag)
+	}
+	if flags&0x40 != 0 && version >= 3 { // extended header
+		if len(tag) < 4 {
+			return end, ErrCorrupt
+		}
+		extSize := int(be.Uint32(tag))
+		if version == 4 {
+			extSize = int(syncsafe(tag[:4]))
+		} else {
+			extSize += 4
+		}
+		if extSize > len(tag) {
+			return end, ErrCorrupt
+		}
+		tag = tag[extSize:]
+	}
+
+	idLen, headerLen := 4, 10
+	if version == 2 {
+		idLen, headerLen = 3, 6
+	}
+	for len(tag) >= headerLen && tag[0] != 0 {
+		id := string(tag[:idLen])
+		var frameSize int
+		var frameFlags uint16
+		switch version {
+		case 2:
+			frameSize = int(tag[3])<<16 | int(tag[4])<<8 | int(tag[5])
+		case 3:
+			frameSize = int(be.Uint32(tag[4:]))
+			frameFlags = be.Uint16(tag[8:])
+		case 4:
+			frameSize = int(syncsafe(tag[4:8]))
+			frameFlags = be.Uint16(tag[8:])
+		}
+		if frameSize < 0 || frameSize > len(tag)-headerLen {
+			return end, ErrCorrupt
+		}
+		frame := tag[headerLen : headerLen+frameSize]
+		tag = tag[headerLen+frameSize:]
+
+		if version == 4 {
+			if frameFlags&0x0001 != 0 && len(frame) >= 4 { // data length indicator
+				frame = frame[4:]
+			}
+			if frameFlags&0x0002 != 0 {
+				frame = unsynchronise(frame)
+			}
+		}
+		if frameFlags&0x00C0 != 0 && version == 3 || frameFlags&0x000C != 0 && version == 4 {
+			continue // compressed or encrypted
+		}
+		parseID3Frame(src, info, id, frame)
+	}
+	return end, nil
+}
+
+func parseID3Frame(src *source, info *Info, id string, frame []byte) {
+	switch {
+	case id == "APIC" || id == "PIC":
+		if !src.wantArtwork(int64(len(frame))) || len(frame) < 2 {
+			return
+		}
+		enc, rest := frame[0], frame[1:]
+		var mimeType string
+		if id == "PIC" {
+			if len(rest) < 3 {
+				return
+			}
+			mimeType, rest = strings.ToLower(string(rest[:3])), rest[3:]
+		} else {
+			var raw []byte
+			raw, rest, _ = bytes.Cut(rest, []byte{0})
+			mimeType = string(raw)
+		}
+		if mimeType == "-->" || len(rest) < 1 { // a linked picture
+			return
+		}
+		picType := PictureType(rest[0])
+		desc, data := splitID3Text(enc, rest[1:])
+		info.addArtwork(Artwork{
+			Type:        picType,
+			ContentType: mimeType,
+			Description: desc,
+			Data:        bytes.Clone(data),
+		})
+
+	case id == "COMM" || id == "COM":
+		if len(frame) < 4 {
+			return
+		}
+		desc, text := splitID3Text(frame[0], frame[4:])
+		if desc == "iTunSMPB" {
+			parseITunSMPB(info, decodeID3Text(frame[0], text))
+			return
+		}
+		if desc == "" || info.Comment == "" {
+			info.setTag(id, fieldComment, decodeID3Text(frame[0], text))
+		}
+
+	case id == "TLEN" || id == "TLE":
+		if ms, err := strconv.Atoi(strings.TrimSpace(id3Text(frame))); err == nil && ms > 0 {
+			info.Duration = time.Duration(ms) * time.Millisecond
+		}
+
+	case id[0] == 'T' && id != "TXXX" && id != "TXX":
+		value := id3Text(frame)
+		f := id3Fields[id]
+		if f == fieldGenre {
+			value = id3Genre(value)
+		}
+		info.setTag(id, f, value)
+	}
+}
+
+// id3Text decodes the value(s) of a text frame, where multiple values (ID3v2.4) are joined with "; ".
+func id3Text(frame []byte) string {
+	if len(frame) < 1 {
+		return ""
+	}
+	text := decodeID3Text(frame[0], frame[1:])
+	text = strings.TrimRight(text, "\x00")
+	return strings.ReplaceAll(text, "\x00", "; ")
+}
+
+// id3Genre resolves references to ID3v1 genres -- e.g. "(17)", "(17)Rock", or "17".
+func id3Genre(value string) string {
+	if ref, rest, ok := strings.Cut(strings.TrimPrefix(value, "("), ")"); ok && strings.HasPrefix(value, "(") {
+		if rest != "" {
+			return rest
+		}
+		value = ref
+	}
+	if n, err := strconv.Atoi(value); err == nil {
+		return genreName(n)
+	}
+	return value
+}
+
+// splitID3Text splits data at the terminator of the given encoding, returning the decoded text preceding it and what follows.
+func splitID3Text(enc byte, data []byte) (string, []byte) {
+	if enc == 1 || enc == 2 { // UTF-16 is terminated by a 16 bit null
+		for i := 0; i+1 < len(data); i += 2 {
+			if data[i] == 0 && data[i+1] == 0 {
+				return decodeID3Text(enc, data[:i]), data[i+2:]
+			}
+		}
+		return decodeID3Text(enc, data), nil
+	}
+	text, rest, _ := bytes.Cut(data, []byte{0})
+	return decodeID3Text(enc, text), rest
+}
+
+// decodeID3Text decodes text of the given ID3 encoding: ISO-8859-1, UTF-16 with BOM, UTF-16BE, or UTF-8.
+func decodeID3Text(enc byte, data []byte) string {
+	switch enc {
+	case 0:
+		return latin1(data)
+	case 1, 2:
+		bigEndian := enc == 2
+		if len(data) >= 2 {
+			switch {
+			case data[0] == 0xFF && data[1] == 0xFE:
+				bigEndian, data = false, data[2:]
+			case data[0] == 0xFE && data[1] == 0xFF:
+				bigEndian, data = true, data[2:]
+			}
+		}
+		return decodeUTF16(data, bigEndian)
+	default:
+		return string(data)
+	}
+}
+
+func decodeUTF16(data []byte, bigEndian bool) string {
+	units := make([]uint16, len(data)/2)
+	for i := range units {
+		if bigEndian {
+			units[i] = be.Uint16(data[2*i:])
+		} else {
+			units[i] = le.Uint16(data[2*i:])
+		}
+	}
+	return string(utf16.Decode(units))
+}
+
+func latin1(data []byte) string {
+	runes := make([]rune, len(data))
+	for i, b := range data {
+		runes[i] = rune(b)
+	}
+	return string(runes)
+}
+
+func syncsafe(b []byte) uint32 {
+	return uint32(b[0]&0x7F)<<21 | uint32(b[1]&0x7F)<<14 | uint32(b[2]&0x7F)<<7 | uint32(b[3]&0x7F)
+}
+
+// unsynchronise reverses ID3v2 unsynchronisation, where 0xFF 0x00 was written for each 0xFF.
+func unsynchronise(data []byte) []byte {
+	if !bytes.Contains(data, []byte{0xFF, 0x00}) {
+		return data
+	}
+	out := make([]byte, 0, len(data))
+	for i := 0; i < len(data); i++ {
+		out = append(out, data[i])
+		if data[i] == 0xFF && i+1 < len(data) && data[i+1] == 0 {
+			i++
+		}
+	}
+	return out
+}
+
+func parseID3v1(tag []byte, info *Info) {
+	text := func(b []byte) string {
+		s, _, _ := bytes.Cut(b, []byte{0})
+		return latin1(s)
+	}
+	info.setTag("", fieldTitle, text(tag[3:33]))
+	info.setTag("", fieldArtist, text(tag[33:63]))
+	info.setTag("", fieldAlbum, text(tag[63:93]))
+	info.setTag("", fieldDate, text(tag[93:97]))
+	info.setTag("", fieldComment, text(tag[97:127]))
+	if tag[125] == 0 && tag[126] != 0 { // ID3v1.1
+		info.setTag("", fieldTrack, strconv.Itoa(int(tag[126])))
+	}
+	info.setTag("", fieldGenre, genreName(int(tag[127])))
+}
+
+// MPEG audio frame header tables, indexed by [version][layer] where version is 0 (MPEG 1) or 1 (MPEG 2 / 2.5) and layer is 0-2 (layers I-III)
+var (
+	mpegBitrates = [2][3][16]int{
+		{
+			{0, 32, 64, 96, 128, 160, 192, 224, 256, 288, 320, 352, 384, 416, 448},
+			{0, 32, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320, 384},
+			{0, 32, 40, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320},
+		},
+		{
+			{0, 32, 48, 56, 64, 80, 96, 112, 128, 144, 160, 176, 192, 224, 256},
+			{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160},
+			{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160},
+		},
+	}
+	mpegSampleRates = [3][3]int{
+		{44100, 48000, 32000}, // MPEG 1
+		{22050, 24000, 16000}, // MPEG 2
+		{11025, 12000, 8000},  // MPEG 2.5
+	}
+)
+
+func isMPEGSync(b []byte) bool {
+	return len(b) >= 2 && b[0] == 0xFF && b[1]&0xE0 == 0xE0
+}
+
+type mpegFrame struct {
+	bitrate         int // kbps
+	sampleRate      int
+	samplesPerFrame int
+	channels        int
+	sideInfoLen     int // bytes between the header and a Xing / Info header
+}
+
+func parseMPEGHeader(b []byte) (frame mpegFrame, ok bool) {
+	if !isMPEGSync(b) || len(b) < 4 {
+		return frame, false
+	}
+	versionBits, layerBits := (b[1]>>3)&3, (b[1]>>1)&3
+	bitrateIndex, rateIndex := b[2]>>4, (b[2]>>2)&3
+	if versionBits == 1 || layerBits == 0 || bitrateIndex == 0 || bitrateIndex == 15 || rateIndex == 3 {
+		return frame, false
+	}
+	version := 0
+	rates := mpegSampleRates[0]
+	switch versionBits {
+	case 2:
+		version, rates = 1, mpegSampleRates[1]
+	case 0:
+		version, rates = 1, mpegSampleRates[2]
+	}
+	layer := 3 - int(layerBits)
+	frame.bitrate = mpegBitrates[version][layer][bitrateIndex]
+	frame.sampleRate = rates[rateIndex]
+	frame.channels = 2
+	if b[3]>>6 == 3 {
+		frame.channels = 1
+	}
+	switch {
+	case layer == 0:
+		frame.samplesPerFrame = 384
+	case layer == 2 && version == 1:
+		frame.samplesPerFrame = 576
+	default:
+		frame.samplesPerFrame = 1152
+	}
+	switch {
+	case version == 0 && frame.channels == 2:
+		frame.sideInfoLen = 32
+	case version == 0 || frame.channels == 2:
+		frame.sideInfoLen = 17
+	default:
+		frame.sideInfoLen = 9
+	}
+	return frame, true
+}
+
+// parseMPEGFrames finds the first MPEG audio frame and determines the duration from its Xing / Info / VBRI header, if present,
+// or otherwise from its bitrate (assuming a constant bitrate).
+func parseMPEGFrames(src *source, info *Info, start, end int64) {
+	buf, err := src.readAt(start, min(end-start, 64<<10))
+	if err != nil {
+		return
+	}
+	for i := 0; i+4 <= len(buf); i++ {
+		frame, ok := parseMPEGHeader(buf[i:])
+		if !ok {
+			continue
+		}
+		info.SampleRate, info.Channels = frame.sampleRate, frame.channels
+		if info.Duration > 0 {
+			return
+		}
+		numFrames := 0
+		body := buf[i+4:]
+		if xing := body[min(frame.sideInfoLen, len(body)):]; len(xing) >= 12 && (string(xing[:4]) == "Xing" || string(xing[:4]) == "Info") {
+			flags := be.Uint32(xing[4:])
+			if flags&1 != 0 {
+				numFrames = int(be.Uint32(xing[8:]))
+			}
+			parseLAMEHeader(info, xing, flags)
+		} else if vbri := body[min(32, len(body)):]; len(vbri) >= 18 && string(vbri[:4]) == "VBRI" {
+			numFrames = int(be.Uint32(vbri[14:]))
+		}
+		if numFrames > 0 {
+			samples := int64(numFrames)*int64(frame.samplesPerFrame) - int64(info.EncoderDelay+info.EncoderPadding)
+			info.Duration = time.Duration(max(samples, 0) * int64(time.Second) / int64(frame.sampleRate))
+		} else if frame.bitrate > 0 {
+			audioBytes := end - start - int64(i)
+			info.Duration = time.Duration(audioBytes * 8 * int64(time.Millisecond) / int64(frame.bitrate))
+		}
+		return
+	}
+}
+
+// parseLAMEHeader parses the encoder delay and padding from the LAME extension (also written by FFmpeg) following the fields
+// of a Xing / Info header having the given flags, unless already set (e.g. by iTunSMPB).
+func parseLAMEHeader(info *Info, xing []byte, flags uint32) {
+	ofs := 8
+	for _, fieldLen := range []int{4, 4, 100, 4} { // frames, bytes, TOC, quality
+		if flags&1 != 0 {
+			ofs += fieldLen
+		}
+		flags >>= 1
+	}
+	if len(xing) < ofs+24 {
+		return
+	}
+	switch encoder := string(xing[ofs : ofs+4]); encoder {
+	case "LAME", "Lavf", "Lavc":
+	default:
+		return
+	}
+	if info.EncoderDelay == 0 && info.EncoderPadding == 0 {
+		gapless := xing[ofs+21:]
+		info.EncoderDelay = int(gapless[0])<<4 | int(gapless[1])>>4
+		info.EncoderPadding = int(gapless[1]&0x0F)<<8 | int(gapless[2])
+	}
+}
@@ -0,0 +1,209 @@
+package metadata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/task"
+)
+
+// IndexerOpts configures an Indexer.
+type IndexerOpts struct {
+	Workers int           // number of files extracted concurrently (default runtime.NumCPU())
+	Timeout time.Duration // max time spent extracting a file (default DefaultTimeout)
+	Extract Opts          // options passed to Extract
+
+	// If set, returns whether a file found by Scan is extracted (default IsSupported)
+	Match func(path string) bool
+}
+
+const DefaultTimeout = 30 * time.Second
+
+// Result is the outcome of extracting the metadata of a file.
+type Result struct {
+	Path string
+	Info *Info // nil if Err is set, unless Err is ErrCorrupt (see Extract)
+	Err  error
+}
+
+// Indexer extracts the metadata of media files on a pool of workers, where a file taking longer than IndexerOpts.Timeout
+// (e.g. a corrupt file, or one on an unresponsive network volume) fails with ErrTimeout so that it cannot stall a scan.
+type Indexer struct {
+	pool *task.WorkerPool
+	opts IndexerOpts
+}
+
+// StartIndexer starts an Indexer as a child of the given parent -- the Indexer stops when Close() is called or parent closes.
+func StartIndexer(parent task.Context, opts IndexerOpts) (*Indexer, error) {
+	if opts.Workers <= 0 {
+		opts.Workers = runtime.NumCPU()
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	if opts.Match == nil {
+		opts.Match = IsSupported
+	}
+	pool, err := task.StartWorkerPool(parent, task.WorkerPoolOpts{
+		Label:      "metadata indexer",
+		MinWorkers: 1,
+		MaxWorkers: opts.Workers,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Indexer{
+		pool: pool,
+		opts: opts,
+	}, nil
+}
+
+// Close stops the Indexer once files already submitted are extracted.
+func (ix *Indexer) Close() error {
+	return ix.pool.Close()
+}
+
+// Done signals when the Indexer has stopped.
+func (ix *Indexer) Done() <-chan struct{} {
+	return ix.pool.Done()
+}
+
+// ExtractFile extracts the metadata of the given file on one of the Indexer's workers, blocking until complete.
+func (ix *Indexer) ExtractFile(ctx context.Context, path string) (*Info, error) {
+	var res Result
+	job, err := ix.pool.Submit(ctx, path, func(jobCtx task.Context) error {
+		res = ix.extract(jobCtx, path)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	select {
+	case <-job.Done():
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if err = job.Err(); err != nil {
+		return nil, err
+	}
+	return res.Info, res.Err
+}
+
+// Scan walks the directory tree at root and extracts the metadata of each regular file accepted by IndexerOpts.Match, calling
+// onResult as each file completes (one at a time but in no particular order).  Scan returns once every file found has completed,
+// or when ctx is done, where an error walking the tree is returned but does not stop the scan.
+func (ix *Indexer) Scan(ctx context.Context, root string, onResult func(res Result)) error {
+	var (
+		mu      sync.Mutex
+		jobs    []*task.Job
+		walkErr error
+	)
+	deliver := func(res Result) {
+		mu.Lock()
+		defer mu.Unlock()
+		onResult(res)
+	}
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if walkErr == nil {
+				walkErr = err
+			}
+			return nil // skip what cannot be read
+		}
+		if !d.Type().IsRegular() || !ix.opts.Match(path) {
+			return nil
+		}
+		job, err := ix.pool.Submit(ctx, path, func(jobCtx task.Context) error {
+			deliver(ix.extract(jobCtx, path))
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		jobs = append(jobs, job)
+		return nil
+	})
+
+	for _, job := range jobs {
+		select {
+		case <-job.Done():
+			if jobErr := job.Err(); jobErr != nil && err == nil {
+				err = jobErr // e.g. the pool closed before the job ran
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if err == nil {
+		err = walkErr
+	}
+	return err
+}
+
+// extract extracts the given file within the time allowed, abandoning the extraction if it does not complete in time.
+func (ix *Indexer) extract(ctx context.Context, path string) Result {
+	f, err := os.Open(path)
+	if err != nil {
+		return Result{Path: path, Err: err}
+	}
+	return ix.extractFrom(ctx, path, f)
+}
+
+func (ix *Indexer) extractFrom(ctx context.Context, path string, f io.ReadSeekCloser) Result {
+	res := Result{Path: path}
+	ctx, cancel := context.WithTimeout(ctx, ix.opts.Timeout)
+	defer cancel()
+
+	done := make(chan Result, 1)
+	go func() {
+		res := Result{Path: path}
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				res.Info, res.Err = nil, fmt.Errorf("%w: %v", ErrCorrupt, recovered)
+			}
+			done <- res
+		}()
+		res.Info, res.Err = Extract(&ctxReader{ctx: ctx, f: f}, ix.opts.Extract)
+		res.Info.refineContentType(path)
+	}()
+
+	select {
+	case res = <-done:
+	case <-ctx.Done():
+		res.Err = ErrTimeout
+		if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			res.Err = ctx.Err()
+		}
+	}
+	f.Close() // unblocks an abandoned extraction where the OS allows
+	return res
+}
+
+// ctxReader fails reads and seeks once its context is done, so an abandoned extraction stops at its next read.
+type ctxReader struct {
+	ctx context.Context
+	f   io.ReadSeeker
+}
+
+func (r *ctxReader) Read(buf []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.f.Read(buf)
+}
+
+func (r *ctxReader) Seek(offset int64, whence int) (int64, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.f.Seek(offset, whence)
+}
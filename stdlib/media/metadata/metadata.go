@@ -0,0 +1,374 @@
+// Package metadata extracts the tags of media files -- ID3 (MP3), Vorbis comments (FLAC, Ogg), MP4 atoms (M4A, MP4), and EXIF
+// (JPEG, PNG, TIFF and TIFF based raw formats) -- into a format independent Info, including any embedded artwork.
+//
+// Extraction reads only the parts of a file holding metadata, never trusting a size read from the file beyond the file's size.
+// See Indexer for extracting the media files of a directory tree on a pool of workers, bounding the time spent on each file.
+package metadata
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrUnsupported = errors.New("metadata: unsupported format")
+	ErrCorrupt     = errors.New("metadata: corrupt or truncated metadata")
+	ErrTimeout     = errors.New("metadata: extraction timed out")
+)
+
+// Info is the metadata extracted from a media file, where fields not present in the file are zero.
+type Info struct {
+	ContentType string // e.g. "audio/mpeg", "audio/flac", "image/jpeg"
+
+	// Audio tags
+	Title       string
+	Artist      string
+	Album       string
+	AlbumArtist string
+	Composer    string
+	Genre       string
+	Comment     string
+	Year        int
+	TrackNum    int
+	TrackCount  int
+	DiscNum     int
+	DiscCount   int
+
+	// Audio / video stream properties
+	Duration   time.Duration
+	SampleRate int
+	Channels   int
+
+	// Gapless playback: the samples of encoder priming at the start of the stream and of padding at its end, which a player
+	// trims so that consecutive tracks play without a gap -- from a LAME header (MP3), iTunSMPB (MP3, MP4), or Opus pre-skip.
+	// A decoder may add its own delay (e.g. 529 samples for MP3), which is not included.
+	EncoderDelay   int
+	EncoderPadding int
+
+	// Image properties and EXIF
+	Width       int
+	Height      int
+	Orientation int // EXIF orientation (1-8), where 1 is upright
+	CameraMake  string
+	CameraModel string
+	TakenAt     time.Time // when the photo was taken, in UTC if its time zone is recorded, otherwise in time.Local
+	HasLocation bool      // set if Latitude and Longitude are present
+	Latitude    float64   // degrees, positive north
+	Longitude   float64   // degrees, positive east
+
+	Artwork []Artwork
+
+	// Each text tag as found in the file, keyed by its native name -- e.g. "TPE1" (ID3), "ARTIST" (Vorbis), "©ART" (MP4)
+	Tags map[string]string
+}
+
+// PictureType is the kind of an embedded picture, as defined by ID3v2 APIC (and adopted by FLAC).
+type PictureType uint8
+
+const (
+	PictureOther      PictureType = 0
+	PictureFileIcon   PictureType = 1
+	PictureFrontCover PictureType = 3
+	PictureBackCover  PictureType = 4
+	PictureLeaflet    PictureType = 5
+	PictureMedia      PictureType = 6
+	PictureArtist     PictureType = 8
+)
+
+// Artwork is a picture embedded in a media file -- e.g. album art or an EXIF thumbnail.
+type Artwork struct {
+	Type        PictureType
+	ContentType string // e.g. "image/jpeg"
+	Description string
+	Data        []byte
+}
+
+// Opts configures extraction.
+type Opts struct {
+	SkipArtwork    bool  // if set, embedded artwork is not read
+	MaxArtworkSize int64 // artwork larger than this is skipped (default DefaultMaxArtworkSize)
+}
+
+const (
+	DefaultMaxArtworkSize = 16 << 20
+
+	// No metadata block read into memory is larger than this, regardless of the size the file claims
+	maxBlockSize = 64 << 20
+)
+
+// FrontCover returns the front cover artwork, if present, or otherwise the first artwork, or nil if there is none.
+func (info *Info) FrontCover() *Artwork {
+	for i := range info.Artwork {
+		if info.Artwork[i].Type == PictureFrontCover {
+			return &info.Artwork[i]
+		}
+	}
+	if len(info.Artwork) > 0 {
+		return &info.Artwork[0]
+	}
+	return nil
+}
+
+// Extensions lists the (lowercase) file extensions of the formats Extract supports.
+var Extensions = map[string]string{
+	".mp3":  "audio/mpeg",
+	".flac": "audio/flac",
+	".ogg":  "audio/ogg",
+	".oga":  "audio/ogg",
+	".opus": "audio/ogg",
+	".m4a":  "audio/mp4",
+	".m4b":  "audio/mp4",
+	".mp4":  "video/mp4",
+	".m4v":  "video/mp4",
+	".mov":  "video/quicktime",
+	".jpg":  "image/jpeg",
+	".jpeg": "image/jpeg",
+	".png":  "image/png",
+	".tif":  "image/tiff",
+	".tiff": "image/tiff",
+	".dng":  "image/x-adobe-dng",
+	".cr2":  "image/x-canon-cr2",
+	".nef":  "image/x-nikon-nef",
+	".arw":  "image/x-sony-arw",
+}
+
+// IsSupported returns whether the given path has the extension of a format Extract supports.
+func IsSupported(path string) bool {
+	_, ok := Extensions[strings.ToLower(filepath.Ext(path))]
+	return ok
+}
+
+// ExtractFile extracts the metadata of the file at the given path (see Extract).
+func ExtractFile(path string, opts Opts) (*Info, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := Extract(f, opts)
+	info.refineContentType(path)
+	return info, err
+}
+
+// refineContentType sets the content type of a TIFF based file from its extension -- e.g. a raw format.
+func (info *Info) refineContentType(path string) {
+	if info != nil && info.ContentType == "image/tiff" {
+		if contentType := Extensions[strings.ToLower(filepath.Ext(path))]; contentType != "" {
+			info.ContentType = contentType
+		}
+	}
+}
+
+// Extract identifies the format of the given content and extracts its metadata, returning ErrUnsupported if the format is
+// not recognized.  If the metadata is found to be corrupt, the Info extracted up to that point is returned with ErrCorrupt.
+func Extract(r io.ReadSeeker, opts Opts) (*Info, error) {
+	if opts.MaxArtworkSize <= 0 {
+		opts.MaxArtworkSize = DefaultMaxArtworkSize
+	}
+	size, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, err
+	}
+	src := &source{r: r, size: size, opts: opts}
+	head, err := src.readAt(0, min(size, 12))
+	if err != nil {
+		return nil, err
+	}
+
+	var parse func(src *source, info *Info) error
+	info := &Info{}
+	switch {
+	case bytes.HasPrefix(head, []byte("ID3")) || isMPEGSync(head):
+		info.ContentType = "audio/mpeg"
+		parse = parseMP3
+	case bytes.HasPrefix(head, []byte("fLaC")):
+		info.ContentType = "audio/flac"
+		parse = parseFLAC
+	case bytes.HasPrefix(head, []byte("OggS")):
+		info.ContentType = "audio/ogg"
+		parse = parseOgg
+	case len(head) >= 12 && string(head[4:8]) == "ftyp":
+		info.ContentType = mp4ContentType(string(head[8:12]))
+		parse = parseMP4
+	case bytes.HasPrefix(head, []byte{0xFF, 0xD8, 0xFF}):
+		info.ContentType = "image/jpeg"
+		parse = parseJPEG
+	case bytes.HasPrefix(head, pngSignature):
+		info.ContentType = "image/png"
+		parse = parsePNG
+	case bytes.HasPrefix(head, []byte("II*\x00")) || bytes.HasPrefix(head, []byte("MM\x00*")):
+		info.ContentType = "image/tiff"
+		parse = parseTIFFFile
+	default:
+		return nil, ErrUnsupported
+	}
+
+	if err = parse(src, info); err != nil {
+		if !errors.Is(err, ErrCorrupt) && (errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)) {
+			err = fmt.Errorf("%w: %v", ErrCorrupt, err)
+		}
+		return info, err
+	}
+	return info, nil
+}
+
+// source reads the blocks of a file, ensuring each lies within the file.
+type source struct {
+	r    io.ReadSeeker
+	size int64
+	opts Opts
+}
+
+func (src *source) readAt(offset, n int64) ([]byte, error) {
+	if offset < 0 || n < 0 || n > maxBlockSize || offset+n > src.size {
+		return nil, fmt.Errorf("%w: %d bytes at offset %d exceeds file size %d", ErrCorrupt, n, offset, src.size)
+	}
+	if _, err := src.r.Seek(offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+	buf := make([]byte, n)
+	_, err := io.ReadFull(src.r, buf)
+	return buf, err
+}
+
+// wantArtwork returns whether artwork of the given size should be read.
+func (src *source) wantArtwork(size int64) bool {
+	return !src.opts.SkipArtwork && size <= src.opts.MaxArtworkSize
+}
+
+// field is a tag common to the formats, to which each format's native tags are mapped.
+type field int
+
+const (
+	fieldTitle field = iota + 1
+	fieldArtist
+	fieldAlbum
+	fieldAlbumArtist
+	fieldComposer
+	fieldGenre
+	fieldComment
+	fieldDate       // e.g. "2004" or "2004-05-01"
+	fieldTrack      // e.g. "3" or "3/12"
+	fieldTrackCount // e.g. "12"
+	fieldDisc       // e.g. "1" or "1/2"
+	fieldDiscCount
+)
+
+// setTag records the given native tag and sets the field it maps to, unless that field is already set.
+func (info *Info) setTag(key string, f field, value string) {
+	value = strings.TrimSpace(strings.TrimRight(value, "\x00"))
+	if value == "" {
+		return
+	}
+	if key != "" {
+		if info.Tags == nil {
+			info.Tags = make(map[string]string)
+		}
+		if _, exists := info.Tags[key]; !exists {
+			info.Tags[key] = value
+		}
+	}
+	str := func(dst *string) {
+		if *dst == "" {
+			*dst = value
+		}
+	}
+	num := func(dst *int, value string) {
+		if n, err := strconv.Atoi(strings.TrimSpace(value)); err == nil && n > 0 && *dst == 0 {
+			*dst = n
+		}
+	}
+	switch f {
+	case fieldTitle:
+		str(&info.Title)
+	case fieldArtist:
+		str(&info.Artist)
+	case fieldAlbum:
+		str(&info.Album)
+	case fieldAlbumArtist:
+		str(&info.AlbumArtist)
+	case fieldComposer:
+		str(&info.Composer)
+	case fieldGenre:
+		str(&info.Genre)
+	case fieldComment:
+		str(&info.Comment)
+	case fieldDate:
+		if len(value) >= 4 {
+			num(&info.Year, value[:4])
+		}
+	case fieldTrack, fieldDisc:
+		n, count, _ := strings.Cut(value, "/")
+		if f == fieldTrack {
+			num(&info.TrackNum, n)
+			num(&info.TrackCount, count)
+		} else {
+			num(&info.DiscNum, n)
+			num(&info.DiscCount, count)
+		}
+	case fieldTrackCount:
+		num(&info.TrackCount, value)
+	case fieldDiscCount:
+		num(&info.DiscCount, value)
+	}
+}
+
+// addArtwork appends the given artwork, inferring its content type from its content if not given.
+func (info *Info) addArtwork(art Artwork) {
+	if len(art.Data) == 0 {
+		return
+	}
+	switch strings.ToLower(art.ContentType) {
+	case "", "image/jpg", "jpg":
+		art.ContentType = imageContentType(art.Data)
+	case "png":
+		art.ContentType = "image/png"
+	}
+	info.Artwork = append(info.Artwork, art)
+}
+
+func imageContentType(data []byte) string {
+	switch {
+	case bytes.HasPrefix(data, []byte{0xFF, 0xD8, 0xFF}):
+		return "image/jpeg"
+	case bytes.HasPrefix(data, pngSignature):
+		return "image/png"
+	case bytes.HasPrefix(data, []byte("GIF8")):
+		return "image/gif"
+	case len(data) >= 12 && string(data[:4]) == "RIFF" && string(data[8:12]) == "WEBP":
+		return "image/webp"
+	}
+	return "application/octet-stream"
+}
+
+// Genres are the ID3v1 genres, which ID3v2 and MP4 tags may refer to by index.
+var genres = []string{
+	"Blues", "Classic Rock", "Country", "Dance", "Disco", "Funk", "Grunge", "Hip-Hop", "Jazz", "Metal",
+	"New Age", "Oldies", "Other", "Pop", "R&B", "Rap", "Reggae", "Rock", "Techno", "Industrial",
+	"Alternative", "Ska", "Death Metal", "Pranks", "Soundtrack", "Euro-Techno", "Ambient", "Trip-Hop", "Vocal", "Jazz+Funk",
+	"Fusion", "Trance", "Classical", "Instrumental", "Acid", "House", "Game", "Sound Clip", "Gospel", "Noise",
+	"Alternative Rock", "Bass", "Soul", "Punk", "Space", "Meditative", "Instrumental Pop", "Instrumental Rock", "Ethnic", "Gothic",
+	"Darkwave", "Techno-Industrial", "Electronic", "Pop-Folk", "Eurodance", "Dream", "Southern Rock", "Comedy", "Cult", "Gangsta",
+	"Top 40", "Christian Rap", "Pop/Funk", "Jungle", "Native American", "Cabaret", "New Wave", "Psychedelic", "Rave", "Showtunes",
+	"Trailer", "Lo-Fi", "Tribal", "Acid Punk", "Acid Jazz", "Polka", "Retro", "Musical", "Rock & Roll", "Hard Rock",
+}
+
+func genreName(index int) string {
+	if index >= 0 && index < len(genres) {
+		return genres[index]
+	}
+	return ""
+}
+
+var (
+	be = binary.BigEndian
+	le = binary.LittleEndian
+)
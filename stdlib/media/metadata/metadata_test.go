@@ -0,0 +1,463 @@
+package metadata
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/task"
+)
+
+var testJPEG = []byte{0xFF, 0xD8, 0xFF, 0xE0, 0, 4, 'J', 'F', 0xFF, 0xD9}
+
+func id3Frame(id string, data []byte) []byte {
+	frame := append([]byte(id), 0, 0, 0, 0, 0, 0)
+	binary.BigEndian.PutUint32(frame[4:], uint32(len(data)))
+	return append(frame, data...)
+}
+
+func id3TextFrame(id, text string) []byte {
+	return id3Frame(id, append([]byte{3}, text...))
+}
+
+func buildMP3() []byte {
+	var frames []byte
+	frames = append(frames, id3TextFrame("TIT2", "Song")...)
+	frames = append(frames, id3TextFrame("TPE1", "Artist")...)
+	frames = append(frames, id3TextFrame("TRCK", "3/12")...)
+	frames = append(frames, id3TextFrame("TYER", "1999")...)
+	frames = append(frames, id3TextFrame("TCON", "(17)")...)
+	// UTF-16 with BOM
+	frames = append(frames, id3Frame("TALB", []byte{1, 0xFF, 0xFE, 'A', 0, 'l', 0, 'b', 0})...)
+	apic := append([]byte{0}, "image/jpeg\x00"...)
+	apic = append(apic, byte(PictureFrontCover))
+	apic = append(apic, "cover\x00"...)
+	frames = append(frames, id3Frame("APIC", append(apic, testJPEG...))...)
+
+	size := len(frames)
+	tag := []byte{'I', 'D', '3', 3, 0, 0, byte(size >> 21 & 0x7F), byte(size >> 14 & 0x7F), byte(size >> 7 & 0x7F), byte(size & 0x7F)}
+	tag = append(tag, frames...)
+
+	// MPEG 1 layer III, 128 kbps, 44.1 kHz, stereo, with a Xing header giving 1000 frames
+	frame := make([]byte, 417)
+	copy(frame, []byte{0xFF, 0xFB, 0x90, 0x00})
+	copy(frame[4+32:], "Xing\x00\x00\x00\x01")
+	binary.BigEndian.PutUint32(frame[4+32+8:], 1000)
+	return append(tag, frame...)
+}
+
+func TestMP3(t *testing.T) {
+	info, err := Extract(bytes.NewReader(buildMP3()), Opts{})
+	require.NoError(t, err)
+	require.Equal(t, "audio/mpeg", info.ContentType)
+	require.Equal(t, "Song", info.Title)
+	require.Equal(t, "Artist", info.Artist)
+	require.Equal(t, "Alb", info.Album)
+	require.Equal(t, "Rock", info.Genre)
+	require.Equal(t, 1999, info.Year)
+	require.Equal(t, 3, info.TrackNum)
+	require.Equal(t, 12, info.TrackCount)
+	require.Equal(t, 44100, info.SampleRate)
+	require.Equal(t, 2, info.Channels)
+	require.Equal(t, time.Duration(1000*1152)*time.Second/44100, info.Duration)
+	require.Equal(t, "Artist", info.Tags["TPE1"])
+
+	cover := info.FrontCover()
+	require.NotNil(t, cover)
+	require.Equal(t, "image/jpeg", cover.ContentType)
+	require.Equal(t, "cover", cover.Description)
+	require.Equal(t, testJPEG, cover.Data)
+
+	info, err = Extract(bytes.NewReader(buildMP3()), Opts{SkipArtwork: true})
+	require.NoError(t, err)
+	require.Empty(t, info.Artwork)
+
+	// An ID3v1 tag fills in fields missing from the ID3v2 tag
+	v1 := make([]byte, 128)
+	copy(v1, "TAG")
+	copy(v1[3:], "Other")
+	copy(v1[63:], "Album v1")
+	info, err = Extract(bytes.NewReader(append(buildMP3(), v1...)), Opts{})
+	require.NoError(t, err)
+	require.Equal(t, "Song", info.Title)
+	require.Equal(t, "Alb", info.Album)
+
+	// A truncated tag returns what was found so far
+	truncated := buildMP3()[:60]
+	info, err = Extract(bytes.NewReader(truncated), Opts{})
+	require.ErrorIs(t, err, ErrCorrupt)
+	require.NotNil(t, info)
+}
+
+// lameMP3 returns MPEG audio whose Info header has a LAME extension giving the given encoder delay and padding.
+func lameMP3(delay, padding int) []byte {
+	frame := make([]byte, 417)
+	copy(frame, []byte{0xFF, 0xFB, 0x90, 0x00})
+	copy(frame[4+32:], "Info\x00\x00\x00\x01")
+	binary.BigEndian.PutUint32(frame[4+32+8:], 1000)
+	lame := frame[4+32+12:]
+	copy(lame, "LAME3.100")
+	lame[21], lame[22], lame[23] = byte(delay>>4), byte(delay<<4|padding>>8), byte(padding)
+	return frame
+}
+
+func TestGapless(t *testing.T) {
+	info, err := Extract(bytes.NewReader(lameMP3(576, 1000)), Opts{})
+	require.NoError(t, err)
+	require.Equal(t, 576, info.EncoderDelay)
+	require.Equal(t, 1000, info.EncoderPadding)
+	require.Equal(t, time.Duration(1000*1152-576-1000)*time.Second/44100, info.Duration)
+
+	// iTunSMPB takes precedence over the LAME header
+	comm := append([]byte{0}, "eng"...)
+	comm = append(comm, "iTunSMPB\x00 00000000 00000840 000001CA 0000000000119F36"...)
+	frames := id3Frame("COMM", comm)
+	size := len(frames)
+	tag := append([]byte{'I', 'D', '3', 3, 0, 0, 0, 0, byte(size >> 7 & 0x7F), byte(size & 0x7F)}, frames...)
+	info, err = Extract(bytes.NewReader(append(tag, lameMP3(576, 1000)...)), Opts{})
+	require.NoError(t, err)
+	require.Equal(t, 0x840, info.EncoderDelay)
+	require.Equal(t, 0x1CA, info.EncoderPadding)
+	require.Empty(t, info.Comment)
+
+	ilst := mp4Box("ilst",
+		mp4Box("----",
+			mp4Box("mean", make([]byte, 4), []byte("com.apple.iTunes")),
+			mp4Box("name", make([]byte, 4), []byte("iTunSMPB")),
+			mp4Data(1, []byte(" 00000000 00000840 00000254 00000000007A1E6C")),
+		),
+	)
+	file := mp4Box("ftyp", []byte("M4A \x00\x00\x00\x00"))
+	file = append(file, mp4Box("moov", mp4Box("udta", mp4Box("meta", make([]byte, 4), mp4Box("hdlr", make([]byte, 25)), ilst)))...)
+	info, err = Extract(bytes.NewReader(file), Opts{})
+	require.NoError(t, err)
+	require.Equal(t, 0x840, info.EncoderDelay)
+	require.Equal(t, 0x254, info.EncoderPadding)
+	require.Contains(t, info.Tags, "iTunSMPB")
+}
+
+func vorbisComments(comments ...string) []byte {
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.LittleEndian, uint32(4))
+	buf.WriteString("test")
+	binary.Write(&buf, binary.LittleEndian, uint32(len(comments)))
+	for _, c := range comments {
+		binary.Write(&buf, binary.LittleEndian, uint32(len(c)))
+		buf.WriteString(c)
+	}
+	return buf.Bytes()
+}
+
+func flacPicture(data []byte) []byte {
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.BigEndian, uint32(PictureFrontCover))
+	binary.Write(&buf, binary.BigEndian, uint32(len("image/jpeg")))
+	buf.WriteString("image/jpeg")
+	binary.Write(&buf, binary.BigEndian, uint32(0))
+	buf.Write(make([]byte, 16))
+	binary.Write(&buf, binary.BigEndian, uint32(len(data)))
+	buf.Write(data)
+	return buf.Bytes()
+}
+
+func flacBlock(blockType byte, last bool, data []byte) []byte {
+	if last {
+		blockType |= 0x80
+	}
+	return append([]byte{blockType, byte(len(data) >> 16), byte(len(data) >> 8), byte(len(data))}, data...)
+}
+
+func TestFLAC(t *testing.T) {
+	streamInfo := make([]byte, 34)
+	// 48 kHz, 2 channels, 16 bits per sample, 480000 samples
+	binary.BigEndian.PutUint64(streamInfo[10:], 48000<<44|1<<41|15<<36|480000)
+
+	file := []byte("fLaC")
+	file = append(file, flacBlock(0, false, streamInfo)...)
+	file = append(file, flacBlock(4, false, vorbisComments("TITLE=Song", "artist=Artist", "TRACKNUMBER=2", "TRACKTOTAL=9", "DATE=2001-02-03"))...)
+	file = append(file, flacBlock(6, true, flacPicture(testJPEG))...)
+
+	info, err := Extract(bytes.NewReader(file), Opts{})
+	require.NoError(t, err)
+	require.Equal(t, "audio/flac", info.ContentType)
+	require.Equal(t, "Song", info.Title)
+	require.Equal(t, "Artist", info.Artist)
+	require.Equal(t, 2, info.TrackNum)
+	require.Equal(t, 9, info.TrackCount)
+	require.Equal(t, 2001, info.Year)
+	require.Equal(t, 48000, info.SampleRate)
+	require.Equal(t, 2, info.Channels)
+	require.Equal(t, 10*time.Second, info.Duration)
+	require.Len(t, info.Artwork, 1)
+	require.Equal(t, testJPEG, info.Artwork[0].Data)
+}
+
+func oggPage(serial uint32, granule int64, packet []byte) []byte {
+	var segments []byte
+	for n := len(packet); ; n -= 255 {
+		if n < 255 {
+			segments = append(segments, byte(n))
+			break
+		}
+		segments = append(segments, 255)
+	}
+	page := make([]byte, 27)
+	copy(page, "OggS")
+	binary.LittleEndian.PutUint64(page[6:], uint64(granule))
+	binary.LittleEndian.PutUint32(page[14:], serial)
+	page[26] = byte(len(segments))
+	page = append(page, segments...)
+	return append(page, packet...)
+}
+
+func TestOgg(t *testing.T) {
+	head := []byte("OpusHead\x01\x02")
+	head = binary.LittleEndian.AppendUint16(head, 312)
+	head = binary.LittleEndian.AppendUint32(head, 44100)
+	head = append(head, 0, 0, 0)
+
+	file := oggPage(7, 0, head)
+	file = append(file, oggPage(7, 0, append([]byte("OpusTags"), vorbisComments("TITLE=Opus Song", "ALBUM=Album")...))...)
+	file = append(file, oggPage(7, 312+48000*5, make([]byte, 100))...)
+
+	info, err := Extract(bytes.NewReader(file), Opts{})
+	require.NoError(t, err)
+	require.Equal(t, "audio/ogg", info.ContentType)
+	require.Equal(t, "Opus Song", info.Title)
+	require.Equal(t, "Album", info.Album)
+	require.Equal(t, 2, info.Channels)
+	require.Equal(t, 5*time.Second, info.Duration)
+	require.Equal(t, 312, info.EncoderDelay)
+}
+
+func mp4Box(boxType string, content ...[]byte) []byte {
+	body := bytes.Join(content, nil)
+	box := binary.BigEndian.AppendUint32(nil, uint32(8+len(body)))
+	return append(append(box, boxType...), body...)
+}
+
+func mp4Data(dataType uint32, value []byte) []byte {
+	return mp4Box("data", binary.BigEndian.AppendUint32(nil, dataType), make([]byte, 4), value)
+}
+
+func TestMP4(t *testing.T) {
+	mvhd := make([]byte, 100)
+	binary.BigEndian.PutUint32(mvhd[12:], 1000)   // timescale
+	binary.BigEndian.PutUint32(mvhd[16:], 185500) // duration
+	ilst := mp4Box("ilst",
+		mp4Box("\xA9nam", mp4Data(1, []byte("Song"))),
+		mp4Box("\xA9ART", mp4Data(1, []byte("Artist"))),
+		mp4Box("trkn", mp4Data(0, []byte{0, 0, 0, 4, 0, 10, 0, 0})),
+		mp4Box("gnre", mp4Data(0, []byte{0, 18})),
+		mp4Box("covr", mp4Data(13, testJPEG)),
+	)
+	file := mp4Box("ftyp", []byte("M4A \x00\x00\x00\x00"))
+	file = append(file, mp4Box("mdat", make([]byte, 1000))...)
+	file = append(file, mp4Box("moov",
+		mp4Box("mvhd", mvhd),
+		mp4Box("trak", make([]byte, 64)),
+		mp4Box("udta", mp4Box("meta", make([]byte, 4), mp4Box("hdlr", make([]byte, 25)), ilst)),
+	)...)
+
+	info, err := Extract(bytes.NewReader(file), Opts{})
+	require.NoError(t, err)
+	require.Equal(t, "audio/mp4", info.ContentType)
+	require.Equal(t, "Song", info.Title)
+	require.Equal(t, "Artist", info.Artist)
+	require.Equal(t, "Artist", info.Tags["©ART"])
+	require.Equal(t, 4, info.TrackNum)
+	require.Equal(t, 10, info.TrackCount)
+	require.Equal(t, "Rock", info.Genre)
+	require.Equal(t, 185500*time.Millisecond, info.Duration)
+	require.Len(t, info.Artwork, 1)
+	require.Equal(t, "image/jpeg", info.Artwork[0].ContentType)
+}
+
+// tiffBuilder builds a little endian TIFF structure of IFDs, where values larger than 4 bytes are appended after the IFDs.
+type tiffBuilder struct {
+	buf []byte
+}
+
+type testEntry struct {
+	tag, typ uint16
+	count    uint32
+	value    []byte
+}
+
+func (b *tiffBuilder) ifd(entries []testEntry, extra *[]byte, extraBase int) []byte {
+	ifd := binary.LittleEndian.AppendUint16(nil, uint16(len(entries)))
+	for _, e := range entries {
+		ifd = binary.LittleEndian.AppendUint16(ifd, e.tag)
+		ifd = binary.LittleEndian.AppendUint16(ifd, e.typ)
+		ifd = binary.LittleEndian.AppendUint32(ifd, e.count)
+		if len(e.value) <= 4 {
+			ifd = append(ifd, append(e.value, make([]byte, 4-len(e.value))...)...)
+		} else {
+			ifd = binary.LittleEndian.AppendUint32(ifd, uint32(extraBase+len(*extra)))
+			*extra = append(*extra, e.value...)
+		}
+	}
+	return binary.LittleEndian.AppendUint32(ifd, 0)
+}
+
+func ascii(s string) []byte {
+	return append([]byte(s), 0)
+}
+
+func long(v uint32) []byte {
+	return binary.LittleEndian.AppendUint32(nil, v)
+}
+
+func rationals(values ...uint32) []byte {
+	var b []byte
+	for _, v := range values {
+		b = binary.LittleEndian.AppendUint32(b, v)
+		b = binary.LittleEndian.AppendUint32(b, 1)
+	}
+	return b
+}
+
+// buildExif returns a TIFF structure with IFD0 at offset 8, then the Exif IFD, then the GPS IFD, then values.
+func buildExif() []byte {
+	exifEntries := []testEntry{
+		{tagDateTimeOrig, 2, 20, ascii("2023:07:04 18:30:00")},
+		{tagOffsetTimeOrig, 2, 7, ascii("+02:00")},
+	}
+	gpsEntries := []testEntry{
+		{tagGPSLatitudeRef, 2, 2, ascii("S")},
+		{tagGPSLatitude, 5, 3, rationals(33, 52, 0)},
+		{tagGPSLongitudeRef, 2, 2, ascii("E")},
+		{tagGPSLongitude, 5, 3, rationals(151, 12, 36)},
+	}
+	ifdSize := func(n int) int { return 2 + 12*n + 4 }
+	exifOffset := 8 + ifdSize(5)
+	gpsOffset := exifOffset + ifdSize(len(exifEntries))
+	extraBase := gpsOffset + ifdSize(len(gpsEntries))
+
+	var b tiffBuilder
+	var extra []byte
+	ifd0 := b.ifd([]testEntry{
+		{tagMake, 2, 6, ascii("Canon")},
+		{tagModel, 2, 8, ascii("EOS R5 ")},
+		{tagOrientation, 3, 1, []byte{6, 0}},
+		{tagExifIFD, 4, 1, long(uint32(exifOffset))},
+		{tagGPSIFD, 4, 1, long(uint32(gpsOffset))},
+	}, &extra, extraBase)
+	exif := b.ifd(exifEntries, &extra, extraBase)
+	gps := b.ifd(gpsEntries, &extra, extraBase)
+
+	data := []byte("II*\x00\x08\x00\x00\x00")
+	data = append(data, ifd0...)
+	data = append(data, exif...)
+	data = append(data, gps...)
+	return append(data, extra...)
+}
+
+func TestJPEG(t *testing.T) {
+	exif := append([]byte("Exif\x00\x00"), buildExif()...)
+	file := []byte{0xFF, 0xD8}
+	file = append(file, 0xFF, 0xE1, byte((len(exif)+2)>>8), byte(len(exif)+2))
+	file = append(file, exif...)
+	file = append(file, 0xFF, 0xC0, 0, 10, 8, 0x02, 0x58, 0x03, 0x20, 3, 0, 0) // 800 x 600
+	file = append(file, 0xFF, 0xDA, 0, 2, 0xFF, 0xD9)
+
+	info, err := Extract(bytes.NewReader(file), Opts{})
+	require.NoError(t, err)
+	require.Equal(t, "image/jpeg", info.ContentType)
+	require.Equal(t, 800, info.Width)
+	require.Equal(t, 600, info.Height)
+	require.Equal(t, "Canon", info.CameraMake)
+	require.Equal(t, "EOS R5", info.CameraModel)
+	require.Equal(t, 6, info.Orientation)
+	require.Equal(t, time.Date(2023, 7, 4, 16, 30, 0, 0, time.UTC), info.TakenAt)
+	require.True(t, info.HasLocation)
+	require.InDelta(t, -33.8667, info.Latitude, 0.0001)
+	require.InDelta(t, 151.21, info.Longitude, 0.0001)
+}
+
+func TestPNG(t *testing.T) {
+	ihdr := binary.BigEndian.AppendUint32(nil, 640)
+	ihdr = binary.BigEndian.AppendUint32(ihdr, 480)
+	ihdr = append(ihdr, 8, 6, 0, 0, 0)
+	file := append([]byte{}, pngSignature...)
+	file = binary.BigEndian.AppendUint32(file, uint32(len(ihdr)))
+	file = append(append(append(file, "IHDR"...), ihdr...), 0, 0, 0, 0)
+	file = append(file, 0, 0, 0, 0, 'I', 'E', 'N', 'D', 0, 0, 0, 0)
+
+	info, err := Extract(bytes.NewReader(file), Opts{})
+	require.NoError(t, err)
+	require.Equal(t, "image/png", info.ContentType)
+	require.Equal(t, 640, info.Width)
+	require.Equal(t, 480, info.Height)
+
+	_, err = Extract(bytes.NewReader([]byte("plain text")), Opts{})
+	require.ErrorIs(t, err, ErrUnsupported)
+}
+
+// blockingFile blocks each read until closed.
+type blockingFile struct {
+	closed chan struct{}
+}
+
+func (f *blockingFile) Read(buf []byte) (int, error) {
+	<-f.closed
+	return 0, os.ErrClosed
+}
+
+func (f *blockingFile) Seek(offset int64, whence int) (int64, error) { return 100, nil }
+func (f *blockingFile) Close() error                                 { close(f.closed); return nil }
+
+func TestIndexer(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "album"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "album", "01.mp3"), buildMP3(), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "album", "02.MP3"), buildMP3()[:60], 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "album", "bad.flac"), []byte("not a flac"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("skipped"), 0o644))
+
+	ctx, err := task.Start(&task.Task{Label: "test"})
+	require.NoError(t, err)
+	defer ctx.Close()
+
+	ix, err := StartIndexer(ctx, IndexerOpts{Workers: 2, Timeout: time.Second})
+	require.NoError(t, err)
+
+	var results []Result
+	require.NoError(t, ix.Scan(context.Background(), dir, func(res Result) {
+		results = append(results, res)
+	}))
+	sort.Slice(results, func(i, j int) bool { return results[i].Path < results[j].Path })
+	require.Len(t, results, 3)
+	require.NoError(t, results[0].Err)
+	require.Equal(t, "Song", results[0].Info.Title)
+	require.ErrorIs(t, results[1].Err, ErrCorrupt)
+	require.ErrorIs(t, results[2].Err, ErrUnsupported)
+
+	info, err := ix.ExtractFile(context.Background(), filepath.Join(dir, "album", "01.mp3"))
+	require.NoError(t, err)
+	require.Equal(t, "Artist", info.Artist)
+
+	// A read that never completes times out
+	ix.opts.Timeout = 20 * time.Millisecond
+	f := &blockingFile{closed: make(chan struct{})}
+	res := ix.extractFrom(context.Background(), "stuck.mp3", f)
+	require.ErrorIs(t, res.Err, ErrTimeout)
+	select {
+	case <-f.closed:
+	default:
+		t.Fatal("expected the file to be closed")
+	}
+
+	require.NoError(t, ix.Close())
+	<-ix.Done()
+	_, err = ix.ExtractFile(context.Background(), filepath.Join(dir, "album", "01.mp3"))
+	require.True(t, errors.Is(err, task.ErrClosed))
+}
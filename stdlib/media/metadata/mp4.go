@@ -0,0 +1,257 @@
+package metadata
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// mp4Fields maps the iTunes style item atoms of moov/udta/meta/ilst to fields.
+var mp4Fields = map[string]field{
+	"\xA9nam": fieldTitle,
+	"\xA9ART": fieldArtist,
+	"\xA9alb": fieldAlbum,
+	"aART":    fieldAlbumArtist,
+	"\xA9wrt": fieldComposer,
+	"\xA9gen": fieldGenre,
+	"\xA9cmt": fieldComment,
+	"\xA9day": fieldDate,
+}
+
+// mp4ContentType returns the content type implied by the major brand of an ftyp box.
+func mp4ContentType(brand string) string {
+	switch brand {
+	case "M4A ", "M4B ", "M4P ", "F4A ":
+		return "audio/mp4"
+	case "qt  ":
+		return "video/quicktime"
+	}
+	return "video/mp4"
+}
+
+// parseMP4 finds the top level moov box and parses its movie header and iTunes style metadata, reading only those boxes
+// (since the sample tables of a long video can be large).
+func parseMP4(src *source, info *Info) error {
+	return mp4FileBoxes(src, 0, src.size, func(boxType string, offset, size int64) (bool, error) {
+		if boxType != "moov" {
+			return true, nil
+		}
+		return false, mp4FileBoxes(src, offset, offset+size, func(boxType string, offset, size int64) (bool, error) {
+			switch boxType {
+			case "mvhd", "udta", "meta": // some files place meta directly within moov
+				content, err := src.readAt(offset, size)
+				if err != nil {
+					return false, err
+				}
+				return true, parseMoovBox(src, info, boxType, content)
+			}
+			return true, nil
+		})
+	})
+}
+
+// mp4FileBoxes calls fn with the offset and size of the content of each box in the given range of the file, stopping if fn returns false.
+func mp4FileBoxes(src *source, offset, end int64, fn func(boxType string, offset, size int64) (bool, error)) error {
+	for offset+8 <= end {
+		header, err := src.readAt(offset, 8)
+		if err != nil {
+			return err
+		}
+		size, headerLen := int64(be.Uint32(header)), int64(8)
+		switch size {
+		case 0:
+			size = end - offset
+		case 1:
+			large, err := src.readAt(offset+8, 8)
+			if err != nil {
+				return err
+			}
+			size, headerLen = int64(be.Uint64(large)), 16
+		}
+		if size < headerLen || size > end-offset {
+			return ErrCorrupt
+		}
+		if more, err := fn(string(header[4:8]), offset+headerLen, size-headerLen); !more || err != nil {
+			return err
+		}
+		offset += size
+	}
+	return nil
+}
+
+// mp4Boxes calls fn for each box within the given box content, stopping if fn returns false.
+func mp4Boxes(data []byte, fn func(boxType string, content []byte) bool) error {
+	for len(data) >= 8 {
+		size, headerLen := int(be.Uint32(data)), 8
+		switch size {
+		case 0:
+			size = len(data)
+		case 1:
+			if len(data) < 16 {
+				return ErrCorrupt
+			}
+			large := be.Uint64(data[8:])
+			if large > uint64(len(data)) {
+				return ErrCorrupt
+			}
+			size, headerLen = int(large), 16
+		}
+		if size < headerLen || size > len(data) {
+			return ErrCorrupt
+		}
+		if !fn(string(data[4:8]), data[headerLen:size]) {
+			return nil
+		}
+		data = data[size:]
+	}
+	return nil
+}
+
+func parseMoovBox(src *source, info *Info, boxType string, content []byte) error {
+	switch boxType {
+	case "mvhd":
+		parseMvhd(info, content)
+	case "udta":
+		var err error
+		walkErr := mp4Boxes(content, func(boxType string, content []byte) bool {
+			if boxType == "meta" {
+				err = parseMP4Meta(src, info, content)
+			}
+			return err == nil
+		})
+		if err != nil {
+			return err
+		}
+		return walkErr
+	case "meta":
+		return parseMP4Meta(src, info, content)
+	}
+	return nil
+}
+
+func parseMvhd(info *Info, mvhd []byte) {
+	if len(mvhd) < 1 {
+		return
+	}
+	var timescale, duration uint64
+	if mvhd[0] == 1 && len(mvhd) >= 32 {
+		timescale, duration = uint64(be.Uint32(mvhd[20:])), be.Uint64(mvhd[24:])
+	} else if len(mvhd) >= 20 {
+		timescale, duration = uint64(be.Uint32(mvhd[12:])), uint64(be.Uint32(mvhd[16:]))
+	}
+	if timescale > 0 && duration != 0xFFFFFFFF && duration != 1<<64-1 {
+		info.Duration = time.Duration(duration * uint64(time.Second) / timescale)
+	}
+}
+
+// parseMP4Meta parses a meta box, which is a full box (having a version and flags) in MP4 files but not in QuickTime files.
+func parseMP4Meta(src *source, info *Info, meta []byte) error {
+	if len(meta) >= 8 && string(meta[4:8]) != "hdlr" {
+		meta = meta[4:]
+	}
+	var err error
+	walkErr := mp4Boxes(meta, func(boxType string, content []byte) bool {
+		if boxType == "ilst" {
+			err = mp4Boxes(content, func(item string, content []byte) bool {
+				parseMP4Item(src, info, item, content)
+				return true
+			})
+		}
+		return err == nil
+	})
+	if err != nil {
+		return err
+	}
+	return walkErr
+}
+
+// parseMP4Item parses the data box(es) of an ilst item, where each holds a type indicator, a locale, and the value.
+func parseMP4Item(src *source, info *Info, item string, content []byte) {
+	if item == "----" {
+		parseMP4Freeform(info, content)
+		return
+	}
+	mp4Boxes(content, func(boxType string, data []byte) bool {
+		if boxType != "data" || len(data) < 8 {
+			return true
+		}
+		dataType, value := be.Uint32(data)&0xFFFFFF, data[8:]
+		key := item
+		if key[0] == 0xA9 {
+			key = "©" + key[1:] // MacRoman
+		}
+		switch item {
+		case "trkn", "disk":
+			if len(value) >= 6 {
+				n, count := int(be.Uint16(value[2:])), int(be.Uint16(value[4:]))
+				f := fieldTrack
+				if item == "disk" {
+					f = fieldDisc
+				}
+				info.setTag("", f, strconv.Itoa(n)+"/"+strconv.Itoa(count))
+			}
+		case "gnre":
+			if len(value) >= 2 {
+				info.setTag(key, fieldGenre, genreName(int(be.Uint16(value))-1))
+			}
+		case "covr":
+			if src.wantArtwork(int64(len(value))) {
+				contentType := ""
+				switch dataType {
+				case 13:
+					contentType = "image/jpeg"
+				case 14:
+					contentType = "image/png"
+				}
+				info.addArtwork(Artwork{
+					Type:        PictureFrontCover,
+					ContentType: contentType,
+					Data:        bytes.Clone(value),
+				})
+			}
+			return true // an item may hold several covers
+		default:
+			if dataType == 1 { // UTF-8
+				info.setTag(key, mp4Fields[item], string(value))
+			}
+		}
+		return false
+	})
+}
+
+// parseMP4Freeform parses a freeform ilst item, identified by the name following its mean box -- e.g. "iTunSMPB".
+func parseMP4Freeform(info *Info, content []byte) {
+	var name string
+	mp4Boxes(content, func(boxType string, data []byte) bool {
+		switch {
+		case boxType == "name" && len(data) >= 4:
+			name = string(data[4:])
+		case boxType == "data" && len(data) >= 8 && name != "":
+			if be.Uint32(data)&0xFFFFFF != 1 { // UTF-8
+				return false
+			}
+			value := string(data[8:])
+			if name == "iTunSMPB" {
+				parseITunSMPB(info, value)
+			}
+			info.setTag(name, 0, value)
+			return false
+		}
+		return true
+	})
+}
+
+// parseITunSMPB parses the encoder delay and padding from an iTunSMPB tag, a series of hex fields where the second and third
+// are the delay and padding -- e.g. " 00000000 00000840 000001CA 00000000003F31F6 ...".
+func parseITunSMPB(info *Info, value string) {
+	fields := strings.Fields(value)
+	if len(fields) < 3 {
+		return
+	}
+	delay, err1 := strconv.ParseUint(fields[1], 16, 32)
+	padding, err2 := strconv.ParseUint(fields[2], 16, 32)
+	if err1 == nil && err2 == nil {
+		info.EncoderDelay, info.EncoderPadding = int(delay), int(padding)
+	}
+}
@@ -0,0 +1,267 @@
+package metadata
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"strings"
+	"time"
+)
+
+// vorbisFields maps Vorbis comment names (upper case) to fields.
+var vorbisFields = map[string]field{
+	"TITLE":        fieldTitle,
+	"ARTIST":       fieldArtist,
+	"ALBUM":        fieldAlbum,
+	"ALBUMARTIST":  fieldAlbumArtist,
+	"ALBUM ARTIST": fieldAlbumArtist,
+	"COMPOSER":     fieldComposer,
+	"GENRE":        fieldGenre,
+	"COMMENT":      fieldComment,
+	"DESCRIPTION":  fieldComment,
+	"DATE":         fieldDate,
+	"YEAR":         fieldDate,
+	"TRACKNUMBER":  fieldTrack,
+	"TRACKTOTAL":   fieldTrackCount,
+	"TOTALTRACKS":  fieldTrackCount,
+	"DISCNUMBER":   fieldDisc,
+	"DISCTOTAL":    fieldDiscCount,
+	"TOTALDISCS":   fieldDiscCount,
+}
+
+func parseFLAC(src *source, info *Info) error {
+	return parseFLACAt(src, info, 0)
+}
+
+// parseFLACAt parses the metadata blocks following the "fLaC" marker at the given offset.
+func parseFLACAt(src *source, info *Info, offset int64) error {
+	offset += 4
+	for {
+		header, err := src.readAt(offset, 4)
+		if err != nil {
+			return err
+		}
+		last, blockType := header[0]&0x80 != 0, header[0]&0x7F
+		size := int64(header[1])<<16 | int64(header[2])<<8 | int64(header[3])
+		offset += 4
+
+		switch blockType {
+		case 0: // STREAMINFO
+			block, err := src.readAt(offset, size)
+			if err != nil {
+				return err
+			}
+			if len(block) >= 18 {
+				info.SampleRate = int(be.Uint32(block[10:]) >> 12)
+				info.Channels = int(block[12]>>1&7) + 1
+				samples := int64(be.Uint64(block[10:]) & (1<<36 - 1))
+				if info.SampleRate > 0 {
+					info.Duration = time.Duration(samples * int64(time.Second) / int64(info.SampleRate))
+				}
+			}
+		case 4: // VORBIS_COMMENT
+			block, err := src.readAt(offset, size)
+			if err != nil {
+				return err
+			}
+			if err = parseVorbisComments(src, info, block); err != nil {
+				return err
+			}
+		case 6: // PICTURE
+			if src.wantArtwork(size) {
+				block, err := src.readAt(offset, size)
+				if err != nil {
+					return err
+				}
+				if err = parseFLACPicture(info, block); err != nil {
+					return err
+				}
+			}
+		}
+		offset += size
+		if last || blockType == 0x7F {
+			return nil
+		}
+	}
+}
+
+// parseFLACPicture parses a FLAC PICTURE block, also used (base64 encoded) as the METADATA_BLOCK_PICTURE Vorbis comment.
+func parseFLACPicture(info *Info, block []byte) error {
+	var art Artwork
+	r := byteReader{buf: block, order: be}
+	art.Type = PictureType(r.uint32())
+	art.ContentType = string(r.bytes(int(r.uint32())))
+	art.Description = string(r.bytes(int(r.uint32())))
+	r.bytes(16) // width, height, depth, colors
+	art.Data = bytes.Clone(r.bytes(int(r.uint32())))
+	if r.err != nil {
+		return r.err
+	}
+	info.addArtwork(art)
+	return nil
+}
+
+// parseVorbisComments parses a Vorbis comment header -- a vendor string followed by "NAME=value" comments.
+func parseVorbisComments(src *source, info *Info, block []byte) error {
+	r := byteReader{buf: block, order: le}
+	r.bytes(int(r.uint32())) // vendor
+	count := int(r.uint32())
+	for i := 0; i < count && r.err == nil; i++ {
+		comment := r.bytes(int(r.uint32()))
+		name, value, ok := bytes.Cut(comment, []byte("="))
+		if !ok {
+			continue
+		}
+		key := strings.ToUpper(string(name))
+		if key == "METADATA_BLOCK_PICTURE" {
+			if src.wantArtwork(int64(len(value)) * 3 / 4) {
+				if picture, err := base64.StdEncoding.DecodeString(string(value)); err == nil {
+					parseFLACPicture(info, picture)
+				}
+			}
+			continue
+		}
+		info.setTag(key, vorbisFields[key], string(value))
+	}
+	return r.err
+}
+
+// parseOgg parses the identification and comment headers of the first logical stream (Vorbis, Opus, or FLAC) and determines
+// the duration from the granule position of the last page.
+func parseOgg(src *source, info *Info) error {
+	var (
+		offset  int64
+		packets [][]byte
+		packet  []byte
+		serial  uint32
+	)
+	for len(packets) < 2 {
+		header, err := src.readAt(offset, 27)
+		if err != nil {
+			return err
+		}
+		if string(header[:4]) != "OggS" {
+			return ErrCorrupt
+		}
+		pageSerial := le.Uint32(header[14:])
+		numSegments := int64(header[26])
+		segments, err := src.readAt(offset+27, numSegments)
+		if err != nil {
+			return err
+		}
+		pageSize := int64(0)
+		for _, n := range segments {
+			pageSize += int64(n)
+		}
+		body, err := src.readAt(offset+27+numSegments, pageSize)
+		if err != nil {
+			return err
+		}
+		first := offset == 0
+		offset += 27 + numSegments + pageSize
+		if first {
+			serial = pageSerial
+		} else if pageSerial != serial {
+			continue // a page of another logical stream
+		}
+		for _, n := range segments {
+			packet = append(packet, body[:n]...)
+			body = body[n:]
+			if n < 255 {
+				packets = append(packets, packet)
+				packet = nil
+				if len(packets) == 2 {
+					break
+				}
+			}
+		}
+		if len(packet) > maxBlockSize {
+			return ErrCorrupt
+		}
+	}
+
+	ident, comments := packets[0], packets[1]
+	var granuleRate, preSkip int64
+	switch {
+	case len(ident) >= 16 && bytes.HasPrefix(ident, []byte("\x01vorbis")):
+		info.Channels = int(ident[11])
+		info.SampleRate = int(le.Uint32(ident[12:]))
+		granuleRate = int64(info.SampleRate)
+		if bytes.HasPrefix(comments, []byte("\x03vorbis")) {
+			if err := parseVorbisComments(src, info, comments[7:]); err != nil {
+				return err
+			}
+		}
+	case len(ident) >= 16 && bytes.HasPrefix(ident, []byte("OpusHead")):
+		info.Channels = int(ident[9])
+		preSkip = int64(le.Uint16(ident[10:]))
+		info.EncoderDelay = int(preSkip)
+		info.SampleRate = int(le.Uint32(ident[12:]))
+		granuleRate = 48000 // Opus granule positions are always at 48 kHz
+		if bytes.HasPrefix(comments, []byte("OpusTags")) {
+			if err := parseVorbisComments(src, info, comments[8:]); err != nil {
+				return err
+			}
+		}
+	case len(ident) >= 51 && bytes.HasPrefix(ident, []byte("\x7FFLAC")):
+		// FLAC in Ogg: the ident packet holds STREAMINFO following the native "fLaC" marker
+		streamInfo := ident[17:]
+		info.SampleRate = int(be.Uint32(streamInfo[10:]) >> 12)
+		info.Channels = int(streamInfo[12]>>1&7) + 1
+		granuleRate = int64(info.SampleRate)
+		if len(comments) > 4 && comments[0]&0x7F == 4 {
+			if err := parseVorbisComments(src, info, comments[4:]); err != nil {
+				return err
+			}
+		}
+	default:
+		return nil
+	}
+
+	if granule := lastOggGranule(src, serial); granule > preSkip && granuleRate > 0 {
+		info.Duration = time.Duration((granule - preSkip) * int64(time.Second) / granuleRate)
+	}
+	return nil
+}
+
+// lastOggGranule returns the granule position of the last page of the given stream, or 0 if not found.
+func lastOggGranule(src *source, serial uint32) int64 {
+	start := max(0, src.size-64<<10)
+	tail, err := src.readAt(start, src.size-start)
+	if err != nil {
+		return 0
+	}
+	for i := bytes.LastIndex(tail, []byte("OggS")); i >= 0; i = bytes.LastIndex(tail[:i], []byte("OggS")) {
+		if page := tail[i:]; len(page) >= 27 && le.Uint32(page[14:]) == serial {
+			if granule := int64(le.Uint64(page[6:])); granule >= 0 {
+				return granule
+			}
+		}
+	}
+	return 0
+}
+
+// byteReader reads fields from a metadata block, recording ErrCorrupt if the block is too short.
+type byteReader struct {
+	buf   []byte
+	order binary.ByteOrder
+	err   error
+}
+
+func (r *byteReader) bytes(n int) []byte {
+	if r.err != nil || n < 0 || n > len(r.buf) {
+		r.err = ErrCorrupt
+		return nil
+	}
+	b := r.buf[:n]
+	r.buf = r.buf[n:]
+	return b
+}
+
+func (r *byteReader) uint32() uint32 {
+	b := r.bytes(4)
+	if b == nil {
+		return 0
+	}
+	return r.order.Uint32(b)
+}
@@ -0,0 +1,206 @@
+package playlist
+
+import (
+	"net/url"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/media/metadata"
+)
+
+// Resolver resolves playlist entries to the items of a media library.
+type Resolver interface {
+
+	// Resolve returns the path of the library item the given entry names, if any.
+	Resolve(entry *Entry) (path string, ok bool)
+}
+
+// Resolve sets the Resolved path of each entry of pl the given resolver resolves, returning how many entries resolved.
+func Resolve(pl *Playlist, res Resolver) int {
+	resolved := 0
+	for i := range pl.Entries {
+		entry := &pl.Entries[i]
+		if path, ok := res.Resolve(entry); ok {
+			entry.Resolved = path
+			resolved++
+		}
+	}
+	return resolved
+}
+
+// MaxDurationSkew is how much the duration of an entry may differ from that of the item it resolves to by artist and title.
+const MaxDurationSkew = 3 * time.Second
+
+// Index is a Resolver of the items of a media library, typically added as a metadata.Indexer scans the library.
+// An entry resolves to:
+//  1. the item at the entry's path, if any;
+//  2. otherwise, the item sharing the most trailing path components with the entry (e.g. "Artist/Album/01 Song.mp3"), compared
+//     case-insensitively, so that a playlist exported on another device or OS resolves -- unless two items share as many;
+//  3. otherwise, the item having the entry's artist and title, compared case-insensitively, whose duration is within
+//     MaxDurationSkew of the entry's (if both are known).
+type Index struct {
+	mu      sync.RWMutex
+	byPath  map[string]*indexItem
+	byName  map[string][]*indexItem // by lowercased base name
+	byTrack map[string][]*indexItem // by trackKey
+}
+
+type indexItem struct {
+	path     string
+	parts    []string // lowercased path components
+	duration time.Duration
+}
+
+// NewIndex returns an empty Index.
+func NewIndex() *Index {
+	return &Index{
+		byPath:  make(map[string]*indexItem),
+		byName:  make(map[string][]*indexItem),
+		byTrack: make(map[string][]*indexItem),
+	}
+}
+
+// Add adds the library item at the given path having the given metadata, which may be nil if not known.
+func (ix *Index) Add(path string, md *metadata.Info) {
+	item := &indexItem{
+		path:  path,
+		parts: pathParts(path),
+	}
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	if _, exists := ix.byPath[path]; exists {
+		return
+	}
+	ix.byPath[path] = item
+	if n := len(item.parts); n > 0 {
+		name := item.parts[n-1]
+		ix.byName[name] = append(ix.byName[name], item)
+	}
+	if md != nil && md.Title != "" {
+		item.duration = md.Duration
+		artist := md.Artist
+		if artist == "" {
+			artist = md.AlbumArtist
+		}
+		key := trackKey(artist, md.Title)
+		ix.byTrack[key] = append(ix.byTrack[key], item)
+	}
+}
+
+// Len returns the number of items in the Index.
+func (ix *Index) Len() int {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	return len(ix.byPath)
+}
+
+// Resolve implements Resolver as described for Index.
+func (ix *Index) Resolve(entry *Entry) (string, bool) {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	if local, isLocal := localPath(entry.Location); isLocal {
+		if item := ix.byPath[local]; item != nil {
+			return item.path, true
+		}
+		if item := ix.bySuffix(pathParts(local)); item != nil {
+			return item.path, true
+		}
+	}
+	if entry.Title != "" {
+		for _, item := range ix.byTrack[trackKey(entry.Artist, entry.Title)] {
+			skew := item.duration - entry.Duration
+			if entry.Duration == 0 || item.duration == 0 || (skew <= MaxDurationSkew && skew >= -MaxDurationSkew) {
+				return item.path, true
+			}
+		}
+	}
+	return "", false
+}
+
+// bySuffix returns the item sharing the most trailing path components with the given path, or nil if none or several do.
+func (ix *Index) bySuffix(parts []string) *indexItem {
+	if len(parts) == 0 {
+		return nil
+	}
+	var best *indexItem
+	bestN, tied := 0, false
+	for _, item := range ix.byName[parts[len(parts)-1]] {
+		n := 0
+		for n < len(parts) && n < len(item.parts) && parts[len(parts)-1-n] == item.parts[len(item.parts)-1-n] {
+			n++
+		}
+		switch {
+		case n > bestN:
+			best, bestN, tied = item, n, false
+		case n == bestN:
+			tied = true
+		}
+	}
+	if tied {
+		return nil
+	}
+	return best
+}
+
+func trackKey(artist, title string) string {
+	return strings.Join(strings.Fields(strings.ToLower(artist)), " ") + "\x00" + strings.Join(strings.Fields(strings.ToLower(title)), " ")
+}
+
+// pathParts returns the lowercased components of the given path, where both / and \ separate components.
+func pathParts(path string) []string {
+	parts := strings.FieldsFunc(strings.ToLower(path), func(r rune) bool {
+		return r == '/' || r == '\\'
+	})
+	if len(parts) > 0 && isDriveLetter(parts[0]) {
+		parts = parts[1:]
+	}
+	return parts
+}
+
+func isDriveLetter(s string) bool {
+	return len(s) == 2 && s[1] == ':' && (s[0] >= 'a' && s[0] <= 'z' || s[0] >= 'A' && s[0] <= 'Z')
+}
+
+// isWindowsAbs returns true if the given path is an absolute Windows path (e.g. `C:\Music` or `\\server\share`), on any OS.
+func isWindowsAbs(path string) bool {
+	return strings.HasPrefix(path, `\\`) || (len(path) >= 3 && isDriveLetter(path[:2]) && (path[2] == '\\' || path[2] == '/'))
+}
+
+// localPath returns the local path a playlist location names -- itself, or the path of a file URL -- or false if it is a URL
+// of another scheme (e.g. an internet radio stream).
+func localPath(location string) (string, bool) {
+	scheme, rest, hasScheme := strings.Cut(location, "://")
+	if !hasScheme || isDriveLetter(location[:min(2, len(location))]) {
+		return location, true
+	}
+	if !strings.EqualFold(scheme, "file") {
+		return "", false
+	}
+	u, err := url.Parse("file://" + rest)
+	if err != nil {
+		return "", false
+	}
+	path := u.Path
+	if len(path) >= 3 && path[0] == '/' && isDriveLetter(path[1:3]) {
+		path = path[1:] // file:///C:/Music
+	}
+	return filepath.FromSlash(path), true
+}
+
+// fileURL returns the given location as a URI: a local path as a file URL, and a URL as-is.
+func fileURL(location string) string {
+	if _, _, hasScheme := strings.Cut(location, "://"); hasScheme {
+		return location
+	}
+	path := filepath.ToSlash(location)
+	if isWindowsAbs(location) {
+		path = "/" + strings.ReplaceAll(location, `\`, "/")
+	}
+	if !strings.HasPrefix(path, "/") {
+		return (&url.URL{Path: path}).String() // relative reference
+	}
+	return (&url.URL{Scheme: "file", Path: path}).String()
+}
@@ -0,0 +1,104 @@
+package playlist
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// decodeM3U reads plain or extended M3U, where content that is not valid UTF-8 is taken to be Latin-1 (as plain .m3u files
+// traditionally are).  Of the extended directives, #EXTINF, #PLAYLIST, #EXTALB, and #EXTART are read and others are ignored,
+// where #EXTALB and #EXTART apply to each location until the next of the same.
+func decodeM3U(r io.Reader) (*Playlist, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	content = bytes.TrimPrefix(content, utf8BOM)
+	text := string(content)
+	if !utf8.Valid(content) {
+		text = latin1(content)
+	}
+
+	pl := &Playlist{}
+	var next Entry // from directives preceding the next location
+	var album, artist string
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "":
+		case strings.HasPrefix(line, "#EXTINF:"):
+			info := strings.TrimPrefix(line, "#EXTINF:")
+			secs, title, _ := strings.Cut(info, ",")
+			if i := strings.IndexAny(secs, " \t"); i >= 0 {
+				secs = secs[:i] // attributes, e.g. tvg-id="..."
+			}
+			if n, err := strconv.ParseFloat(secs, 64); err == nil && n > 0 {
+				next.Duration = time.Duration(n * float64(time.Second))
+			}
+			next.Artist, next.Title = splitArtistTitle(title)
+		case strings.HasPrefix(line, "#PLAYLIST:"):
+			pl.Title = strings.TrimSpace(strings.TrimPrefix(line, "#PLAYLIST:"))
+		case strings.HasPrefix(line, "#EXTALB:"):
+			album = strings.TrimSpace(strings.TrimPrefix(line, "#EXTALB:"))
+		case strings.HasPrefix(line, "#EXTART:"):
+			artist = strings.TrimSpace(strings.TrimPrefix(line, "#EXTART:"))
+		case strings.HasPrefix(line, "#"):
+		default:
+			next.Location = line
+			if next.Album == "" {
+				next.Album = album
+			}
+			if next.Artist == "" {
+				next.Artist = artist
+			}
+			pl.Entries = append(pl.Entries, next)
+			next = Entry{}
+		}
+	}
+	return pl, scanner.Err()
+}
+
+func encodeM3U(w io.Writer, pl *Playlist) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString("#EXTM3U\n")
+	if pl.Title != "" {
+		bw.WriteString("#PLAYLIST:" + oneLine(pl.Title) + "\n")
+	}
+	album := "" // #EXTALB applies until the next #EXTALB
+	for i := range pl.Entries {
+		entry := &pl.Entries[i]
+		secs := int64(-1)
+		if entry.Duration > 0 {
+			secs = int64((entry.Duration + time.Second/2) / time.Second)
+		}
+		bw.WriteString("#EXTINF:" + strconv.FormatInt(secs, 10) + "," + oneLine(entry.displayTitle()) + "\n")
+		if entry.Album != album {
+			album = entry.Album
+			bw.WriteString("#EXTALB:" + oneLine(album) + "\n")
+		}
+		bw.WriteString(oneLine(entry.exportLocation()) + "\n")
+	}
+	return bw.Flush()
+}
+
+var lineBreaks = strings.NewReplacer("\r\n", " ", "\r", " ", "\n", " ")
+
+// oneLine returns s with any line breaks replaced, so that it cannot be read back as more than one line.
+func oneLine(s string) string {
+	return lineBreaks.Replace(s)
+}
+
+func latin1(content []byte) string {
+	runes := make([]rune, len(content))
+	for i, b := range content {
+		runes[i] = rune(b)
+	}
+	return string(runes)
+}
@@ -0,0 +1,223 @@
+// Package playlist reads and writes playlists in the common interchange formats -- M3U (including extended M3U and M3U8),
+// PLS, and XSPF -- so that a user's playlists can be imported into amp as playlist cells and exported back out.
+//
+// An imported playlist's entries can be resolved against a media library (see Index), so that each entry refers to the item it
+// names even if it was exported on another device (e.g. with other paths or drive letters).
+package playlist
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+var (
+	ErrUnknownFormat = errors.New("playlist: unknown format")
+)
+
+// Playlist is an ordered list of media entries.
+type Playlist struct {
+	Title   string
+	Entries []Entry
+}
+
+// Entry is a media item in a playlist.
+type Entry struct {
+	Location string        // path or URL of the item, as given by the playlist
+	Title    string        // if known
+	Artist   string        // if known
+	Album    string        // if known
+	Duration time.Duration // zero if not known
+	Resolved string        // path of the library item the entry resolves to, if resolved (see Resolver)
+}
+
+// Format is a playlist file format.
+type Format int
+
+const (
+	M3U  Format = iota + 1 // extended M3U, written as UTF-8 (i.e. M3U8)
+	PLS                    // PLS version 2
+	XSPF                   // XML Shareable Playlist Format version 1
+)
+
+func (f Format) String() string {
+	switch f {
+	case M3U:
+		return "M3U"
+	case PLS:
+		return "PLS"
+	case XSPF:
+		return "XSPF"
+	}
+	return fmt.Sprintf("Format(%d)", int(f))
+}
+
+// ContentType returns the media type of this format.
+func (f Format) ContentType() string {
+	switch f {
+	case M3U:
+		return "audio/x-mpegurl"
+	case PLS:
+		return "audio/x-scpls"
+	case XSPF:
+		return "application/xspf+xml"
+	}
+	return "application/octet-stream"
+}
+
+// Ext returns the file extension of this format.
+func (f Format) Ext() string {
+	switch f {
+	case M3U:
+		return ".m3u8"
+	case PLS:
+		return ".pls"
+	case XSPF:
+		return ".xspf"
+	}
+	return ""
+}
+
+// FormatOf returns the format of the given playlist file by its extension.
+func FormatOf(path string) (Format, bool) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".m3u", ".m3u8":
+		return M3U, true
+	case ".pls":
+		return PLS, true
+	case ".xspf":
+		return XSPF, true
+	}
+	return 0, false
+}
+
+// Sniff returns the format of the given playlist content, returning false if it is not recognized.
+// Plain M3U has no signature, so content not otherwise recognized is taken to be M3U if any line is a path or URL.
+func Sniff(content []byte) (Format, bool) {
+	head := bytes.TrimSpace(bytes.TrimPrefix(content, utf8BOM))
+	if len(head) > 512 {
+		head = head[:512]
+	}
+	switch {
+	case bytes.HasPrefix(head, []byte("#EXTM3U")):
+		return M3U, true
+	case bytes.HasPrefix(bytes.ToLower(head), []byte("[playlist]")):
+		return PLS, true
+	case bytes.HasPrefix(head, []byte("<?xml")) || bytes.HasPrefix(head, []byte("<playlist")):
+		if bytes.Contains(head, []byte("xspf.org/ns/0")) || bytes.Contains(head, []byte("<trackList")) {
+			return XSPF, true
+		}
+		return 0, false
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(head))
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" && !strings.HasPrefix(line, "#") {
+			return M3U, !strings.ContainsAny(line, "<>{}")
+		}
+	}
+	return 0, false
+}
+
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// Decode reads a playlist in the given format.
+func Decode(r io.Reader, format Format) (*Playlist, error) {
+	switch format {
+	case M3U:
+		return decodeM3U(r)
+	case PLS:
+		return decodePLS(r)
+	case XSPF:
+		return decodeXSPF(r)
+	}
+	return nil, ErrUnknownFormat
+}
+
+// Encode writes the given playlist in the given format.
+func Encode(w io.Writer, pl *Playlist, format Format) error {
+	switch format {
+	case M3U:
+		return encodeM3U(w, pl)
+	case PLS:
+		return encodePLS(w, pl)
+	case XSPF:
+		return encodeXSPF(w, pl)
+	}
+	return ErrUnknownFormat
+}
+
+// ReadFile reads the playlist file at the given path, of the format its extension (or else its content) indicates.
+// Entry locations that are relative paths are made absolute, relative to the playlist's directory.
+func ReadFile(path string) (*Playlist, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	format, ok := FormatOf(path)
+	if !ok {
+		if format, ok = Sniff(content); !ok {
+			return nil, ErrUnknownFormat
+		}
+	}
+	pl, err := Decode(bytes.NewReader(content), format)
+	if err != nil {
+		return nil, err
+	}
+	if pl.Title == "" {
+		pl.Title = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	dir := filepath.Dir(path)
+	for i := range pl.Entries {
+		entry := &pl.Entries[i]
+		if local, isLocal := localPath(entry.Location); isLocal && !filepath.IsAbs(local) && !isWindowsAbs(local) {
+			entry.Location = filepath.Join(dir, local)
+		}
+	}
+	return pl, nil
+}
+
+// WriteFile writes the given playlist to the given path in the format its extension indicates.
+func WriteFile(path string, pl *Playlist) error {
+	format, ok := FormatOf(path)
+	if !ok {
+		return ErrUnknownFormat
+	}
+	var buf bytes.Buffer
+	if err := Encode(&buf, pl, format); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0644)
+}
+
+// splitArtistTitle splits the "Artist - Title" form used by M3U and PLS titles.
+func splitArtistTitle(s string) (artist, title string) {
+	if artist, title, ok := strings.Cut(s, " - "); ok {
+		return strings.TrimSpace(artist), strings.TrimSpace(title)
+	}
+	return "", strings.TrimSpace(s)
+}
+
+// displayTitle returns the "Artist - Title" form used by M3U and PLS titles.
+func (e *Entry) displayTitle() string {
+	switch {
+	case e.Artist != "" && e.Title != "":
+		return e.Artist + " - " + e.Title
+	case e.Title != "":
+		return e.Title
+	}
+	return e.Artist
+}
+
+// exportLocation returns the location an entry is exported with, preferring the library item it resolved to.
+func (e *Entry) exportLocation() string {
+	if e.Resolved != "" {
+		return e.Resolved
+	}
+	return e.Location
+}
@@ -0,0 +1,220 @@
+package playlist
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/media/metadata"
+	"github.com/stretchr/testify/require"
+)
+
+func testPlaylist() *Playlist {
+	return &Playlist{
+		Title: "Road Trip",
+		Entries: []Entry{
+			{Location: "/music/Boards of Canada/Geogaddi/02 Music Is Math.mp3", Artist: "Boards of Canada", Title: "Music Is Math", Album: "Geogaddi", Duration: 321 * time.Second},
+			{Location: "/music/Björk/Homogenic/01 Hunter.flac", Artist: "Björk", Title: "Hunter", Album: "Homogenic", Duration: 255 * time.Second},
+			{Location: "http://radio.example.com/stream", Title: "Example Radio"},
+		},
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, format := range []Format{M3U, PLS, XSPF} {
+		t.Run(format.String(), func(t *testing.T) {
+			pl := testPlaylist()
+			var buf bytes.Buffer
+			require.NoError(t, Encode(&buf, pl, format))
+
+			sniffed, ok := Sniff(buf.Bytes())
+			require.True(t, ok)
+			require.Equal(t, format, sniffed)
+
+			got, err := Decode(&buf, format)
+			require.NoError(t, err)
+			require.Equal(t, pl.Title, got.Title)
+			require.Len(t, got.Entries, len(pl.Entries))
+			for i, want := range pl.Entries {
+				entry := got.Entries[i]
+				if format == XSPF && !strings.Contains(want.Location, "://") {
+					local, isLocal := localPath(entry.Location)
+					require.True(t, isLocal)
+					require.True(t, strings.HasPrefix(entry.Location, "file:///"), entry.Location)
+					entry.Location = local
+				}
+				if format == PLS {
+					entry.Album = want.Album // not represented
+				}
+				require.Equal(t, want, entry)
+			}
+		})
+	}
+}
+
+func TestDecodeM3U(t *testing.T) {
+	// Plain Latin-1 M3U with CRLF line endings, as written by older players
+	content := []byte("Artist\\Caf\xe9.mp3\r\n# a comment\r\n\r\nhttp://radio.example.com/stream\r\n")
+	format, ok := Sniff(content)
+	require.True(t, ok)
+	require.Equal(t, M3U, format)
+
+	pl, err := Decode(bytes.NewReader(content), M3U)
+	require.NoError(t, err)
+	require.Equal(t, []Entry{
+		{Location: `Artist\Café.mp3`},
+		{Location: "http://radio.example.com/stream"},
+	}, pl.Entries)
+
+	// Extended M3U8 with a BOM, attributes, and album and artist directives
+	content = []byte("\xef\xbb\xbf#EXTM3U\n#PLAYLIST:Mix\n#EXTALB:Album\n#EXTART:Band\n" +
+		"#EXTINF:123.5 tvg-id=\"x\",Song\nsong.mp3\n" +
+		"#EXTINF:-1,Other - Tune\ntune.mp3\n")
+	pl, err = Decode(bytes.NewReader(content), M3U)
+	require.NoError(t, err)
+	require.Equal(t, "Mix", pl.Title)
+	require.Equal(t, []Entry{
+		{Location: "song.mp3", Title: "Song", Artist: "Band", Album: "Album", Duration: 123500 * time.Millisecond},
+		{Location: "tune.mp3", Title: "Tune", Artist: "Other", Album: "Album"},
+	}, pl.Entries)
+}
+
+func TestDecodePLS(t *testing.T) {
+	content := []byte("[Playlist]\nNumberOfEntries=3\nTitle3=Last\nFile3=c.mp3\nFile1=a.mp3\nLength1=60\nTitle1=Band - First\n" +
+		"File10=j.mp3\nTitle5=no file\nVersion=2\n")
+	pl, err := Decode(bytes.NewReader(content), PLS)
+	require.NoError(t, err)
+	require.Equal(t, []Entry{
+		{Location: "a.mp3", Artist: "Band", Title: "First", Duration: time.Minute},
+		{Location: "c.mp3", Title: "Last"},
+		{Location: "j.mp3"},
+	}, pl.Entries)
+}
+
+func TestDecodeXSPF(t *testing.T) {
+	content := []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<playlist version="1" xmlns="http://xspf.org/ns/0/">
+  <title>Favorites</title>
+  <trackList>
+    <track>
+      <location>file:///C:/Music/Song%20One.mp3</location>
+      <location>http://mirror.example.com/song.mp3</location>
+      <creator>Band</creator>
+      <title>Song One</title>
+      <duration>61500</duration>
+    </track>
+    <track><title>no location</title></track>
+  </trackList>
+</playlist>`)
+	format, ok := Sniff(content)
+	require.True(t, ok)
+	require.Equal(t, XSPF, format)
+
+	pl, err := Decode(bytes.NewReader(content), XSPF)
+	require.NoError(t, err)
+	require.Equal(t, "Favorites", pl.Title)
+	require.Equal(t, []Entry{
+		{Location: "file:///C:/Music/Song%20One.mp3", Artist: "Band", Title: "Song One", Duration: 61500 * time.Millisecond},
+	}, pl.Entries)
+
+	local, isLocal := localPath(pl.Entries[0].Location)
+	require.True(t, isLocal)
+	require.Equal(t, filepath.FromSlash("C:/Music/Song One.mp3"), local)
+
+	_, err = Decode(strings.NewReader("<playlist><trackList>"), XSPF)
+	require.Error(t, err)
+}
+
+func TestSniff(t *testing.T) {
+	for _, content := range []string{"", "   \n", "<html><body>", "<?xml version=\"1.0\"?><rss>", "{\"a\": 1}"} {
+		_, ok := Sniff([]byte(content))
+		require.False(t, ok, content)
+	}
+	_, err := Decode(strings.NewReader(""), Format(0))
+	require.ErrorIs(t, err, ErrUnknownFormat)
+}
+
+func TestFiles(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "Chill Out.m3u")
+	require.NoError(t, os.WriteFile(path, []byte("a.mp3\nsub/b.mp3\n/abs/c.mp3\nC:\\d.mp3\nhttp://x.example.com/e.mp3\n"), 0644))
+
+	pl, err := ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "Chill Out", pl.Title)
+	var locations []string
+	for _, entry := range pl.Entries {
+		locations = append(locations, entry.Location)
+	}
+	require.Equal(t, []string{
+		filepath.Join(dir, "a.mp3"),
+		filepath.Join(dir, "sub", "b.mp3"),
+		"/abs/c.mp3",
+		`C:\d.mp3`,
+		"http://x.example.com/e.mp3",
+	}, locations)
+
+	// Sniffed when the extension is not known
+	other := filepath.Join(dir, "list.txt")
+	require.NoError(t, WriteFile(other+".pls", pl))
+	require.NoError(t, os.Rename(other+".pls", other))
+	got, err := ReadFile(other)
+	require.NoError(t, err)
+	require.Equal(t, pl.Title, got.Title)
+	require.Len(t, got.Entries, len(pl.Entries))
+
+	require.ErrorIs(t, WriteFile(other, pl), ErrUnknownFormat)
+}
+
+func TestIndex(t *testing.T) {
+	ix := NewIndex()
+	ix.Add("/home/me/Music/Boards of Canada/Geogaddi/02 Music Is Math.mp3", &metadata.Info{
+		Artist:   "Boards of Canada",
+		Title:    "Music Is Math",
+		Duration: 321 * time.Second,
+	})
+	ix.Add("/home/me/Music/A/Greatest Hits/01 Intro.mp3", nil)
+	ix.Add("/home/me/Music/B/Greatest Hits/01 Intro.mp3", nil)
+	ix.Add("/home/me/Music/Björk/Homogenic/01 Hunter.flac", &metadata.Info{
+		AlbumArtist: "Björk",
+		Title:       "Hunter",
+	})
+	require.Equal(t, 4, ix.Len())
+
+	pl := &Playlist{
+		Entries: []Entry{
+			{Location: "/home/me/Music/Björk/Homogenic/01 Hunter.flac"},                    // by path
+			{Location: `D:\Users\Me\MUSIC\Boards of Canada\Geogaddi\02 Music Is Math.mp3`}, // by suffix
+			{Location: "file:///Volumes/Music/B/Greatest%20Hits/01%20Intro.mp3"},           // by suffix, of similar names
+			{Location: "/elsewhere/01 Intro.mp3"},                                          // ambiguous
+			{Location: "http://x.example.com/h.flac", Artist: "björk", Title: "  Hunter"},  // by artist and title
+			{Location: "/x/math.mp3", Artist: "Boards Of Canada", Title: "Music is Math", Duration: 319 * time.Second},
+			{Location: "/x/math.mp3", Artist: "Boards Of Canada", Title: "Music is Math", Duration: 400 * time.Second},
+			{Location: "/x/y.mp3", Title: "Unknown"},
+		},
+	}
+	require.Equal(t, 5, Resolve(pl, ix))
+	var resolved []string
+	for _, entry := range pl.Entries {
+		resolved = append(resolved, entry.Resolved)
+	}
+	require.Equal(t, []string{
+		"/home/me/Music/Björk/Homogenic/01 Hunter.flac",
+		"/home/me/Music/Boards of Canada/Geogaddi/02 Music Is Math.mp3",
+		"/home/me/Music/B/Greatest Hits/01 Intro.mp3",
+		"",
+		"/home/me/Music/Björk/Homogenic/01 Hunter.flac",
+		"/home/me/Music/Boards of Canada/Geogaddi/02 Music Is Math.mp3",
+		"",
+		"",
+	}, resolved)
+
+	// Exported with the resolved location
+	var buf bytes.Buffer
+	require.NoError(t, Encode(&buf, pl, M3U))
+	require.Contains(t, buf.String(), "\n/home/me/Music/Boards of Canada/Geogaddi/02 Music Is Math.mp3\n")
+	require.Contains(t, buf.String(), "\n/elsewhere/01 Intro.mp3\n")
+}
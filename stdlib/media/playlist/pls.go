@@ -0,0 +1,104 @@
+package playlist
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// decodePLS reads a PLS playlist, whose FileN, TitleN, and LengthN keys may appear in any order and whose numbering may have gaps.
+func decodePLS(r io.Reader) (*Playlist, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	content = bytes.TrimPrefix(content, utf8BOM)
+	text := string(content)
+	if !utf8.Valid(content) {
+		text = latin1(content)
+	}
+
+	pl := &Playlist{}
+	entries := make(map[int]*Entry)
+	entry := func(n int) *Entry {
+		e := entries[n]
+		if e == nil {
+			e = &Entry{}
+			entries[n] = e
+		}
+		return e
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		key, val, ok := strings.Cut(strings.TrimSpace(scanner.Text()), "=")
+		if !ok {
+			continue // "[playlist]", comments, or blank lines
+		}
+		key, val = strings.ToLower(strings.TrimSpace(key)), strings.TrimSpace(val)
+		switch {
+		case key == "x-gnome-title" || key == "title":
+			pl.Title = val
+		case strings.HasPrefix(key, "file"):
+			if n, err := strconv.Atoi(key[4:]); err == nil {
+				entry(n).Location = val
+			}
+		case strings.HasPrefix(key, "title"):
+			if n, err := strconv.Atoi(key[5:]); err == nil {
+				e := entry(n)
+				e.Artist, e.Title = splitArtistTitle(val)
+			}
+		case strings.HasPrefix(key, "length"):
+			if n, err := strconv.Atoi(key[6:]); err == nil {
+				if secs, err := strconv.ParseInt(val, 10, 64); err == nil && secs > 0 {
+					entry(n).Duration = time.Duration(secs) * time.Second
+				}
+			}
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	order := make([]int, 0, len(entries))
+	for n, e := range entries {
+		if e.Location != "" {
+			order = append(order, n)
+		}
+	}
+	sort.Ints(order)
+	for _, n := range order {
+		pl.Entries = append(pl.Entries, *entries[n])
+	}
+	return pl, nil
+}
+
+func encodePLS(w io.Writer, pl *Playlist) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString("[playlist]\n")
+	if pl.Title != "" {
+		bw.WriteString("X-GNOME-Title=" + oneLine(pl.Title) + "\n")
+	}
+	for i := range pl.Entries {
+		entry := &pl.Entries[i]
+		n := strconv.Itoa(i + 1)
+		bw.WriteString("File" + n + "=" + oneLine(entry.exportLocation()) + "\n")
+		if title := entry.displayTitle(); title != "" {
+			bw.WriteString("Title" + n + "=" + oneLine(title) + "\n")
+		}
+		secs := int64(-1)
+		if entry.Duration > 0 {
+			secs = int64((entry.Duration + time.Second/2) / time.Second)
+		}
+		bw.WriteString("Length" + n + "=" + strconv.FormatInt(secs, 10) + "\n")
+	}
+	bw.WriteString("NumberOfEntries=" + strconv.Itoa(len(pl.Entries)) + "\n")
+	bw.WriteString("Version=2\n")
+	return bw.Flush()
+}
@@ -0,0 +1,88 @@
+package playlist
+
+import (
+	"encoding/xml"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const xspfNamespace = "http://xspf.org/ns/0/"
+
+type xspfPlaylist struct {
+	XMLName xml.Name    `xml:"playlist"`
+	Version string      `xml:"version,attr"`
+	XMLNS   string      `xml:"xmlns,attr"`
+	Title   string      `xml:"title,omitempty"`
+	Tracks  []xspfTrack `xml:"trackList>track"`
+}
+
+type xspfTrack struct {
+	Locations []string `xml:"location"`
+	Title     string   `xml:"title,omitempty"`
+	Creator   string   `xml:"creator,omitempty"`
+	Album     string   `xml:"album,omitempty"`
+	Duration  string   `xml:"duration,omitempty"` // milliseconds
+}
+
+// decodeXSPF reads an XSPF playlist, where a track having several locations is read as its first, and a track having none is skipped.
+func decodeXSPF(r io.Reader) (*Playlist, error) {
+	var doc xspfPlaylist
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, err
+	}
+	pl := &Playlist{
+		Title: strings.TrimSpace(doc.Title),
+	}
+	for _, track := range doc.Tracks {
+		if len(track.Locations) == 0 {
+			continue
+		}
+		entry := Entry{
+			Location: strings.TrimSpace(track.Locations[0]),
+			Title:    strings.TrimSpace(track.Title),
+			Artist:   strings.TrimSpace(track.Creator),
+			Album:    strings.TrimSpace(track.Album),
+		}
+		if ms, err := strconv.ParseInt(strings.TrimSpace(track.Duration), 10, 64); err == nil && ms > 0 {
+			entry.Duration = time.Duration(ms) * time.Millisecond
+		}
+		pl.Entries = append(pl.Entries, entry)
+	}
+	return pl, nil
+}
+
+// encodeXSPF writes an XSPF playlist, where each location is written as a URI as XSPF requires (a local path as a file URL).
+func encodeXSPF(w io.Writer, pl *Playlist) error {
+	doc := xspfPlaylist{
+		Version: "1",
+		XMLNS:   xspfNamespace,
+		Title:   pl.Title,
+		Tracks:  make([]xspfTrack, 0, len(pl.Entries)),
+	}
+	for i := range pl.Entries {
+		entry := &pl.Entries[i]
+		track := xspfTrack{
+			Locations: []string{fileURL(entry.exportLocation())},
+			Title:     entry.Title,
+			Creator:   entry.Artist,
+			Album:     entry.Album,
+		}
+		if entry.Duration > 0 {
+			track.Duration = strconv.FormatInt(entry.Duration.Milliseconds(), 10)
+		}
+		doc.Tracks = append(doc.Tracks, track)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(&doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,362 @@
+// Package publisher is a host's built-in media.Publisher: each published asset is served over HTTP at an unguessable URL until it
+// goes unrequested for its expiry period, and the assets of a cas.Store are served at URLs by content hash.
+//
+// Routes, relative to Opts.Prefix:
+//
+//	GET {token}         an asset published via PublishAsset
+//	GET cas/{hash}      the asset in Opts.CAS having the given hash (see HashURL)
+//	GET store/{key}     the asset in Opts.Store having the given key (see StoreURL)
+//
+// If Opts.SigningKey is set, only signed URLs are served (see SignURL).  These routes honor Range requests (responding with Accept-Ranges and Content-Range) so that a client can scrub audio or video without
+// downloading all of it, provided the asset's reader can seek.
+package publisher
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/media"
+	"github.com/amp-3d/amp-sdk-go/stdlib/media/cas"
+	"github.com/amp-3d/amp-sdk-go/stdlib/task"
+)
+
+const (
+	DefaultPrefix   = "/asset/"
+	DefaultHostAddr = "localhost"
+	DefaultExpiry   = time.Hour
+)
+
+// Opts configures a Publisher.
+type Opts struct {
+	Prefix   string           // URL path the publisher's routes are relative to (default DefaultPrefix)
+	Scheme   string           // scheme of generated URLs (default "http")
+	HostAddr string           // host[:port] of generated URLs when media.PublishOpts.HostAddr is empty (default DefaultHostAddr)
+	Expiry   time.Duration    // idle expiry of a published asset when media.PublishOpts.Expiry <= 0 (default DefaultExpiry)
+	CAS      *cas.Store       // if set, its assets are served by hash
+	Store    media.AssetStore // if set, its assets are served by key, via redirect to a direct URL if the store provides one
+
+	SigningKey     []byte                       // if set, only signed URLs are served (see SignURL)
+	URLExpiry      time.Duration                // how long signed URLs are valid (default DefaultURLExpiry)
+	RequestSession func(r *http.Request) string // returns the session ID of a request, checked against URLs bound to a session
+}
+
+// Publisher implements media.Publisher and is an http.Handler serving the routes described in the package doc.
+type Publisher struct {
+	opts Opts
+	ctx  task.Context
+
+	mu        sync.Mutex
+	published map[string]*published // by token
+}
+
+type published struct {
+	asset   media.Asset
+	ctx     task.Context
+	mu      sync.Mutex
+	readers map[media.AssetReader]struct{} // open readers, closed if the asset expires first
+}
+
+// New starts a Publisher as a child of parent, which expires all published assets when closed.
+func New(parent task.Context, opts Opts) (*Publisher, error) {
+	if opts.Prefix == "" {
+		opts.Prefix = DefaultPrefix
+	}
+	if !strings.HasSuffix(opts.Prefix, "/") {
+		opts.Prefix += "/"
+	}
+	if opts.Scheme == "" {
+		opts.Scheme = "http"
+	}
+	if opts.HostAddr == "" {
+		opts.HostAddr = DefaultHostAddr
+	}
+	if opts.Expiry <= 0 {
+		opts.Expiry = DefaultExpiry
+	}
+	if opts.URLExpiry <= 0 {
+		opts.URLExpiry = DefaultURLExpiry
+	}
+	pub := &Publisher{
+		opts:      opts,
+		published: make(map[string]*published),
+	}
+
+	var err error
+	pub.ctx, err = parent.StartChild(&task.Task{
+		Label: "asset publisher",
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pub, nil
+}
+
+// Context returns the Context published assets are started within.
+func (pub *Publisher) Context() task.Context {
+	return pub.ctx
+}
+
+// PublishAsset implements media.Publisher by starting the asset and returning the URL it is served at until it expires.
+func (pub *Publisher) PublishAsset(asset media.Asset, opts media.PublishOpts) (string, error) {
+	return pub.publish(asset, opts, "")
+}
+
+func (pub *Publisher) publish(asset media.Asset, opts media.PublishOpts, sessionID string) (string, error) {
+	expiry := opts.Expiry
+	if expiry <= 0 {
+		expiry = pub.opts.Expiry
+	}
+	token, err := newToken()
+	if err != nil {
+		return "", err
+	}
+
+	item := &published{
+		asset:   asset,
+		readers: make(map[media.AssetReader]struct{}),
+	}
+	item.ctx, err = pub.ctx.StartChild(&task.Task{
+		Label:       "asset: " + asset.Label(),
+		IdleTimeout: expiry,
+		OnStart: func(ctx task.Context) error {
+			pub.mu.Lock()
+			pub.published[token] = item
+			pub.mu.Unlock()
+			return asset.OnStart(ctx)
+		},
+		OnClosing: func() {
+			pub.mu.Lock()
+			delete(pub.published, token)
+			pub.mu.Unlock()
+			item.closeReaders()
+		},
+		OnClosed: func() {
+			if opts.OnExpired != nil {
+				opts.OnExpired()
+			}
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	return pub.url(opts.HostAddr, token, sessionID), nil
+}
+
+// HashURL returns the URL the asset in Opts.CAS having the given hash is served at, where hostAddr overrides Opts.HostAddr if set.
+func (pub *Publisher) HashURL(hash cas.Hash, hostAddr string) string {
+	return pub.url(hostAddr, "cas/"+hash.String(), "")
+}
+
+// StoreURL returns the URL the asset in Opts.Store having the given key is served at, where hostAddr overrides Opts.HostAddr if set.
+func (pub *Publisher) StoreURL(key string, hostAddr string) string {
+	return pub.url(hostAddr, "store/"+key, "")
+}
+
+// url returns the URL of the given route, signed if Opts.SigningKey is set.
+func (pub *Publisher) url(hostAddr, route, sessionID string) string {
+	if hostAddr == "" {
+		hostAddr = pub.opts.HostAddr
+	}
+	u := url.URL{
+		Scheme: pub.opts.Scheme,
+		Host:   hostAddr,
+		Path:   pub.opts.Prefix + route,
+	}
+	if len(pub.opts.SigningKey) > 0 {
+		query := url.Values{}
+		pub.sign(u.Path, query, SignOpts{SessionID: sessionID})
+		u.RawQuery = query.Encode()
+	}
+	return u.String()
+}
+
+func (pub *Publisher) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	route, ok := strings.CutPrefix(r.URL.Path, pub.opts.Prefix)
+	var sigErr error
+	if ok && len(pub.opts.SigningKey) > 0 {
+		sigErr = pub.verify(r)
+	}
+	switch {
+	case !ok:
+		http.NotFound(w, r)
+	case r.Method != http.MethodGet && r.Method != http.MethodHead:
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	case sigErr != nil:
+		http.Error(w, sigErr.Error(), http.StatusForbidden)
+	case strings.HasPrefix(route, "cas/"):
+		pub.serveHash(w, r, strings.TrimPrefix(route, "cas/"))
+	case strings.HasPrefix(route, "store/"):
+		pub.serveStored(w, r, strings.TrimPrefix(route, "store/"))
+	default:
+		pub.servePublished(w, r, route)
+	}
+}
+
+func (pub *Publisher) servePublished(w http.ResponseWriter, r *http.Request, token string) {
+	pub.mu.Lock()
+	item := pub.published[token]
+	pub.mu.Unlock()
+	if item == nil {
+		http.NotFound(w, r)
+		return
+	}
+	task.ReportActivity(item.ctx)
+
+	reader, err := item.openReader()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	defer item.closeReader(reader)
+
+	w.Header().Set("Content-Type", item.asset.ContentType())
+	serveContent(w, r, &activityReader{reader, item.ctx})
+}
+
+func (pub *Publisher) serveHash(w http.ResponseWriter, r *http.Request, hashStr string) {
+	if pub.opts.CAS == nil {
+		http.NotFound(w, r)
+		return
+	}
+	hash, err := cas.ParseHash(hashStr)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	reader, err := pub.opts.CAS.Open(hash)
+	if errors.Is(err, cas.ErrNotFound) {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer reader.Close()
+
+	// Content at a hash URL never changes
+	header := w.Header()
+	header.Set("ETag", `"`+hashStr+`"`)
+	header.Set("Cache-Control", "public, max-age=31536000, immutable")
+	if contentType := reader.Info().ContentType; contentType != "" {
+		header.Set("Content-Type", contentType)
+	}
+	serveContent(w, r, reader)
+}
+
+func (pub *Publisher) serveStored(w http.ResponseWriter, r *http.Request, key string) {
+	if pub.opts.Store == nil {
+		http.NotFound(w, r)
+		return
+	}
+	direct, err := pub.opts.Store.URL(key, pub.opts.URLExpiry)
+	if err == nil {
+		http.Redirect(w, r, direct, http.StatusFound)
+		return
+	}
+	if !errors.Is(err, media.ErrNoDirectURL) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	info, err := pub.opts.Store.Stat(r.Context(), key)
+	if err == nil {
+		var reader media.AssetReader
+		if reader, err = pub.opts.Store.Open(r.Context(), key); err == nil {
+			defer reader.Close()
+			if info.ETag != "" {
+				w.Header().Set("ETag", `"`+info.ETag+`"`)
+			}
+			if info.ContentType != "" {
+				w.Header().Set("Content-Type", info.ContentType)
+			}
+			serveContent(w, r, reader)
+			return
+		}
+	}
+	if errors.Is(err, media.ErrAssetNotFound) {
+		http.NotFound(w, r)
+	} else {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+	}
+}
+
+// serveContent serves the content of the given reader, honoring Range (and If-Range and If-None-Match) headers so a client can seek
+// within media without fetching all of it.  If the reader cannot seek (its Seek returns an error), the content is served in full
+// with "Accept-Ranges: none".
+func serveContent(w http.ResponseWriter, r *http.Request, content io.ReadSeeker) {
+	if _, err := content.Seek(0, io.SeekEnd); err == nil {
+		if _, err = content.Seek(0, io.SeekStart); err == nil {
+			http.ServeContent(w, r, "", time.Time{}, content)
+			return
+		}
+	}
+
+	w.Header().Set("Accept-Ranges", "none")
+	if r.Method == http.MethodHead {
+		return
+	}
+	io.Copy(w, content)
+}
+
+func (item *published) openReader() (media.AssetReader, error) {
+	reader, err := item.asset.NewAssetReader()
+	if err != nil {
+		return nil, err
+	}
+	item.mu.Lock()
+	defer item.mu.Unlock()
+	if item.readers == nil {
+		reader.Close()
+		return nil, errors.New("asset expired")
+	}
+	item.readers[reader] = struct{}{}
+	return reader, nil
+}
+
+func (item *published) closeReader(reader media.AssetReader) {
+	item.mu.Lock()
+	_, open := item.readers[reader]
+	delete(item.readers, reader)
+	item.mu.Unlock()
+	if open {
+		reader.Close()
+	}
+}
+
+func (item *published) closeReaders() {
+	item.mu.Lock()
+	readers := item.readers
+	item.readers = nil
+	item.mu.Unlock()
+	for reader := range readers {
+		reader.Close()
+	}
+}
+
+// activityReader resets the idle expiry of the asset being read so that a long download does not expire it.
+type activityReader struct {
+	io.ReadSeeker
+	ctx task.Context
+}
+
+func (r *activityReader) Read(p []byte) (int, error) {
+	task.ReportActivity(r.ctx)
+	return r.ReadSeeker.Read(p)
+}
+
+func newToken() (string, error) {
+	var buf [16]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf[:]), nil
+}
@@ -0,0 +1,261 @@
+package publisher_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/media"
+	"github.com/amp-3d/amp-sdk-go/stdlib/media/cas"
+	"github.com/amp-3d/amp-sdk-go/stdlib/media/publisher"
+	"github.com/amp-3d/amp-sdk-go/stdlib/media/stores"
+	"github.com/amp-3d/amp-sdk-go/stdlib/task"
+)
+
+type bytesAsset struct {
+	data []byte
+}
+
+func (asset *bytesAsset) Label() string                  { return "bytes" }
+func (asset *bytesAsset) ContentType() string            { return "audio/mpeg" }
+func (asset *bytesAsset) OnStart(ctx task.Context) error { return nil }
+
+func (asset *bytesAsset) NewAssetReader() (media.AssetReader, error) {
+	return nopCloser{bytes.NewReader(asset.data)}, nil
+}
+
+// streamAsset is read from an unseekable stream, e.g. a live transcode
+type streamAsset struct {
+	bytesAsset
+}
+
+func (asset *streamAsset) NewAssetReader() (media.AssetReader, error) {
+	return unseekable{nopCloser{bytes.NewReader(asset.data)}}, nil
+}
+
+type unseekable struct {
+	nopCloser
+}
+
+func (unseekable) Seek(offset int64, whence int) (int64, error) {
+	return 0, errors.New("not seekable")
+}
+
+type nopCloser struct {
+	*bytes.Reader
+}
+
+func (nopCloser) Close() error { return nil }
+
+func get(t *testing.T, url string, header http.Header) (*http.Response, string) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	require.NoError(t, err)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	resp.Body.Close()
+	return resp, string(body)
+}
+
+func TestPublisher(t *testing.T) {
+	root, err := task.Start(&task.Task{Label: "host"})
+	require.NoError(t, err)
+	defer root.Close()
+
+	store, err := cas.Open(cas.Opts{Dir: t.TempDir()})
+	require.NoError(t, err)
+	srv := httptest.NewUnstartedServer(nil)
+	pub, err := publisher.New(root, publisher.Opts{
+		HostAddr: srv.Listener.Addr().String(),
+		CAS:      store,
+	})
+	require.NoError(t, err)
+	srv.Config.Handler = pub
+	srv.Start()
+	defer srv.Close()
+
+	expired := make(chan struct{})
+	url, err := pub.PublishAsset(&bytesAsset{data: []byte("some audio")}, media.PublishOpts{
+		Expiry:    200 * time.Millisecond,
+		OnExpired: func() { close(expired) },
+	})
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(url, srv.URL+publisher.DefaultPrefix), url)
+	resp, body := get(t, url, nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "audio/mpeg", resp.Header.Get("Content-Type"))
+	require.Equal(t, "some audio", body)
+
+	// Content added twice is served from one hash URL
+	info, err := store.Put(strings.NewReader("cover art"), "image/jpeg")
+	require.NoError(t, err)
+	dup, err := store.Put(strings.NewReader("cover art"), "image/jpeg")
+	require.NoError(t, err)
+	require.Equal(t, info.Hash, dup.Hash)
+	hashURL := pub.HashURL(info.Hash, "")
+	resp, body = get(t, hashURL, nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "cover art", body)
+	require.Equal(t, "image/jpeg", resp.Header.Get("Content-Type"))
+	resp, _ = get(t, hashURL, http.Header{"If-None-Match": {resp.Header.Get("ETag")}})
+	require.Equal(t, http.StatusNotModified, resp.StatusCode)
+	resp, _ = get(t, pub.HashURL(cas.Sum256([]byte("nope")), ""), nil)
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	select {
+	case <-expired:
+	case <-time.After(5 * time.Second):
+		t.Fatal("asset did not expire")
+	}
+	resp, _ = get(t, url, nil)
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestRanges(t *testing.T) {
+	root, err := task.Start(&task.Task{Label: "host"})
+	require.NoError(t, err)
+	defer root.Close()
+
+	store, err := cas.Open(cas.Opts{Dir: t.TempDir(), ChunkSize: 4})
+	require.NoError(t, err)
+	srv := httptest.NewUnstartedServer(nil)
+	pub, err := publisher.New(root, publisher.Opts{
+		HostAddr: srv.Listener.Addr().String(),
+		CAS:      store,
+	})
+	require.NoError(t, err)
+	srv.Config.Handler = pub
+	srv.Start()
+	defer srv.Close()
+
+	url, err := pub.PublishAsset(&bytesAsset{data: []byte("0123456789")}, media.PublishOpts{})
+	require.NoError(t, err)
+	resp, body := get(t, url, http.Header{"Range": {"bytes=5-7"}})
+	require.Equal(t, http.StatusPartialContent, resp.StatusCode)
+	require.Equal(t, "bytes 5-7/10", resp.Header.Get("Content-Range"))
+	require.Equal(t, "bytes", resp.Header.Get("Accept-Ranges"))
+	require.Equal(t, "567", body)
+	resp, body = get(t, url, http.Header{"Range": {"bytes=-2"}})
+	require.Equal(t, http.StatusPartialContent, resp.StatusCode)
+	require.Equal(t, "89", body)
+	resp, _ = get(t, url, http.Header{"Range": {"bytes=20-"}})
+	require.Equal(t, http.StatusRequestedRangeNotSatisfiable, resp.StatusCode)
+
+	// Ranges span the chunks of an asset served by hash
+	info, err := store.Put(strings.NewReader("abcdefghij"), "video/mp4")
+	require.NoError(t, err)
+	resp, body = get(t, pub.HashURL(info.Hash, ""), http.Header{"Range": {"bytes=2-8"}})
+	require.Equal(t, http.StatusPartialContent, resp.StatusCode)
+	require.Equal(t, "bytes 2-8/10", resp.Header.Get("Content-Range"))
+	require.Equal(t, "video/mp4", resp.Header.Get("Content-Type"))
+	require.Equal(t, "cdefghi", body)
+
+	// An unseekable asset is served in full
+	url, err = pub.PublishAsset(&streamAsset{bytesAsset{data: []byte("live")}}, media.PublishOpts{})
+	require.NoError(t, err)
+	resp, body = get(t, url, http.Header{"Range": {"bytes=1-2"}})
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "none", resp.Header.Get("Accept-Ranges"))
+	require.Equal(t, "live", body)
+}
+
+func TestSignedURLs(t *testing.T) {
+	root, err := task.Start(&task.Task{Label: "host"})
+	require.NoError(t, err)
+	defer root.Close()
+
+	store, err := cas.Open(cas.Opts{Dir: t.TempDir()})
+	require.NoError(t, err)
+	srv := httptest.NewUnstartedServer(nil)
+	pub, err := publisher.New(root, publisher.Opts{
+		HostAddr:   srv.Listener.Addr().String(),
+		CAS:        store,
+		SigningKey: []byte("secret"),
+		RequestSession: func(r *http.Request) string {
+			return r.Header.Get("X-Session")
+		},
+	})
+	require.NoError(t, err)
+	srv.Config.Handler = pub
+	srv.Start()
+	defer srv.Close()
+
+	signed, err := pub.PublishAsset(&bytesAsset{data: []byte("track")}, media.PublishOpts{})
+	require.NoError(t, err)
+	resp, body := get(t, signed, nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "track", body)
+
+	unsigned, _, _ := strings.Cut(signed, "?")
+	resp, _ = get(t, unsigned, nil)
+	require.Equal(t, http.StatusForbidden, resp.StatusCode)
+	resp, _ = get(t, strings.Replace(signed, "exp=", "exp=9", 1), nil)
+	require.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	// URLs minted for a session are only served to that session
+	sess := pub.ForSession("sess-1")
+	info, err := store.Put(strings.NewReader("cover"), "image/png")
+	require.NoError(t, err)
+	hashURL := sess.HashURL(info.Hash, "")
+	resp, _ = get(t, hashURL, nil)
+	require.Equal(t, http.StatusForbidden, resp.StatusCode)
+	resp, _ = get(t, hashURL, http.Header{"X-Session": {"sess-2"}})
+	require.Equal(t, http.StatusForbidden, resp.StatusCode)
+	resp, body = get(t, hashURL, http.Header{"X-Session": {"sess-1"}})
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "cover", body)
+
+	var signer media.URLSigner = sess
+	short, err := signer.SignURL(pub.HashURL(info.Hash, ""), time.Nanosecond)
+	require.NoError(t, err)
+	time.Sleep(1100 * time.Millisecond)
+	resp, _ = get(t, short, http.Header{"X-Session": {"sess-1"}})
+	require.Equal(t, http.StatusForbidden, resp.StatusCode)
+}
+
+func TestStoredAssets(t *testing.T) {
+	root, err := task.Start(&task.Task{Label: "host"})
+	require.NoError(t, err)
+	defer root.Close()
+
+	store, err := stores.NewLocal(stores.LocalOpts{Dir: t.TempDir()})
+	require.NoError(t, err)
+	require.NoError(t, store.Put(root, "library/a.txt", strings.NewReader("stored track"), media.AssetInfo{Size: -1}))
+
+	srv := httptest.NewUnstartedServer(nil)
+	pub, err := publisher.New(root, publisher.Opts{
+		HostAddr: srv.Listener.Addr().String(),
+		Store:    store,
+	})
+	require.NoError(t, err)
+	srv.Config.Handler = pub
+	srv.Start()
+	defer srv.Close()
+
+	resp, body := get(t, pub.StoreURL("library/a.txt", ""), http.Header{"Range": {"bytes=7-"}})
+	require.Equal(t, http.StatusPartialContent, resp.StatusCode)
+	require.Equal(t, "track", body)
+	resp, _ = get(t, pub.StoreURL("library/b.txt", ""), nil)
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	// A store serving assets directly is redirected to
+	redirecting, err := stores.NewLocal(stores.LocalOpts{Dir: t.TempDir(), BaseURL: "https://cdn.example.com"})
+	require.NoError(t, err)
+	pub, err = publisher.New(root, publisher.Opts{Store: redirecting})
+	require.NoError(t, err)
+	rec := httptest.NewRecorder()
+	pub.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, publisher.DefaultPrefix+"store/library/a.txt", nil))
+	require.Equal(t, http.StatusFound, rec.Code)
+	require.Equal(t, "https://cdn.example.com/library/a.txt", rec.Header().Get("Location"))
+}
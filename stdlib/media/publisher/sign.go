@@ -0,0 +1,138 @@
+package publisher
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/media"
+	"github.com/amp-3d/amp-sdk-go/stdlib/media/cas"
+)
+
+// Signed URLs
+//
+// If Opts.SigningKey is set, every URL the publisher serves must be signed: it has an "exp" param (unix seconds) after which it is
+// rejected, an optional "sid" param binding it to a session, and a "sig" param that is the HMAC-SHA256 of its path, exp, and sid.
+// A URL bound to a session is only served to requests that Opts.RequestSession reports are of that session.
+//
+// URLs returned by PublishAsset, HashURL, and StoreURL are signed, and SignURL signs any other URL served by the publisher.
+// ForSession returns a media.Publisher whose URLs are bound to a session, typically returned by amp.HostSession.AssetPublisher.
+
+const DefaultURLExpiry = time.Hour
+
+var (
+	ErrUnsigned     = errors.New("asset URL is not signed")
+	ErrBadSig       = errors.New("asset URL signature is invalid")
+	ErrURLExpired   = errors.New("asset URL has expired")
+	ErrWrongSession = errors.New("asset URL is bound to another session")
+)
+
+// SignOpts specifies how SignURL signs a URL.
+type SignOpts struct {
+	Expiry    time.Duration // how long the URL is valid (default Opts.URLExpiry)
+	SessionID string        // if set, the URL is only valid for requests of this session
+}
+
+// SignURL returns the given URL signed as described above, or returns it as-is if Opts.SigningKey is not set.
+func (pub *Publisher) SignURL(rawURL string, opts SignOpts) (string, error) {
+	if len(pub.opts.SigningKey) == 0 {
+		return rawURL, nil
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	query := u.Query()
+	query.Del("sid")
+	pub.sign(u.Path, query, opts)
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// sign adds the params signing the given path to query, assuming Opts.SigningKey is set.
+func (pub *Publisher) sign(path string, query url.Values, opts SignOpts) {
+	if opts.Expiry <= 0 {
+		opts.Expiry = pub.opts.URLExpiry
+	}
+	exp := strconv.FormatInt(time.Now().Add(opts.Expiry).Unix(), 10)
+	query.Set("exp", exp)
+	if opts.SessionID != "" {
+		query.Set("sid", opts.SessionID)
+	}
+	query.Set("sig", pub.signature(path, exp, opts.SessionID))
+}
+
+func (pub *Publisher) signature(path, exp, sessionID string) string {
+	mac := hmac.New(sha256.New, pub.opts.SigningKey)
+	mac.Write([]byte(path))
+	mac.Write([]byte{0})
+	mac.Write([]byte(exp))
+	mac.Write([]byte{0})
+	mac.Write([]byte(sessionID))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// verify checks the signature of the given request, assuming Opts.SigningKey is set.
+func (pub *Publisher) verify(r *http.Request) error {
+	query := r.URL.Query()
+	exp, sessionID, sig := query.Get("exp"), query.Get("sid"), query.Get("sig")
+	if sig == "" || exp == "" {
+		return ErrUnsigned
+	}
+	if !hmac.Equal([]byte(sig), []byte(pub.signature(r.URL.Path, exp, sessionID))) {
+		return ErrBadSig
+	}
+	expires, err := strconv.ParseInt(exp, 10, 64)
+	if err != nil {
+		return ErrBadSig
+	}
+	if time.Now().Unix() > expires {
+		return ErrURLExpired
+	}
+	if sessionID != "" && (pub.opts.RequestSession == nil || pub.opts.RequestSession(r) != sessionID) {
+		return ErrWrongSession
+	}
+	return nil
+}
+
+// ForSession returns a media.Publisher (also implementing media.URLSigner) whose URLs are bound to the given session.
+func (pub *Publisher) ForSession(sessionID string) *SessionPublisher {
+	return &SessionPublisher{
+		pub:       pub,
+		sessionID: sessionID,
+	}
+}
+
+// SessionPublisher publishes assets via a Publisher at URLs bound to a session.
+type SessionPublisher struct {
+	pub       *Publisher
+	sessionID string
+}
+
+// PublishAsset implements media.Publisher.
+func (sp *SessionPublisher) PublishAsset(asset media.Asset, opts media.PublishOpts) (string, error) {
+	return sp.pub.publish(asset, opts, sp.sessionID)
+}
+
+// HashURL is as Publisher.HashURL with the URL bound to this session.
+func (sp *SessionPublisher) HashURL(hash cas.Hash, hostAddr string) string {
+	return sp.pub.url(hostAddr, "cas/"+hash.String(), sp.sessionID)
+}
+
+// StoreURL is as Publisher.StoreURL with the URL bound to this session.
+func (sp *SessionPublisher) StoreURL(key string, hostAddr string) string {
+	return sp.pub.url(hostAddr, "store/"+key, sp.sessionID)
+}
+
+// SignURL implements media.URLSigner.
+func (sp *SessionPublisher) SignURL(rawURL string, expiry time.Duration) (string, error) {
+	return sp.pub.SignURL(rawURL, SignOpts{
+		Expiry:    expiry,
+		SessionID: sp.sessionID,
+	})
+}
@@ -0,0 +1,249 @@
+package stores
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/media"
+)
+
+// GCSOpts configures a GCS store.
+type GCSOpts struct {
+	Bucket   string
+	Prefix   string // prepended to each key to form its object name -- e.g. "media/"
+	Endpoint string // default "https://storage.googleapis.com"
+
+	// Returns an OAuth2 access token having the devstorage.read_write scope -- e.g. from the GCE metadata server or an oauth2.TokenSource.
+	Token func(ctx context.Context) (string, error)
+
+	// The service account whose key signs the URLs returned by URL -- if not set, URL returns media.ErrNoDirectURL.
+	ClientEmail string
+	PrivateKey  []byte // PEM encoded RSA key, as in the "private_key" of a service account's JSON key file
+
+	Client *http.Client // default http.DefaultClient
+}
+
+// GCS is a media.AssetStore of objects in a Google Cloud Storage bucket, via its JSON API.
+type GCS struct {
+	opts     GCSOpts
+	endpoint *url.URL
+	key      *rsa.PrivateKey
+}
+
+// NewGCS returns a GCS store for the given options, where Bucket and Token are required.
+func NewGCS(opts GCSOpts) (*GCS, error) {
+	if opts.Bucket == "" || opts.Token == nil {
+		return nil, errors.New("stores: GCSOpts.Bucket and Token are required")
+	}
+	if opts.Endpoint == "" {
+		opts.Endpoint = "https://storage.googleapis.com"
+	}
+	if opts.Client == nil {
+		opts.Client = http.DefaultClient
+	}
+	st := &GCS{
+		opts: opts,
+	}
+	var err error
+	if st.endpoint, err = url.Parse(strings.TrimSuffix(opts.Endpoint, "/")); err != nil {
+		return nil, err
+	}
+	if len(opts.PrivateKey) > 0 {
+		if st.key, err = parseRSAKey(opts.PrivateKey); err != nil {
+			return nil, err
+		}
+	}
+	return st, nil
+}
+
+func parseRSAKey(pemBytes []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, errors.New("stores: GCSOpts.PrivateKey is not PEM encoded")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("stores: GCSOpts.PrivateKey is not an RSA key")
+	}
+	return rsaKey, nil
+}
+
+// objectURL returns the JSON API URL of the given object, where the object name is escaped as a single path segment.
+func (st *GCS) objectURL(key string, query url.Values) (*url.URL, error) {
+	if err := checkKey(key); err != nil {
+		return nil, err
+	}
+	u := *st.endpoint
+	name := st.opts.Prefix + key
+	base := u.Path + "/storage/v1/b/" + st.opts.Bucket + "/o/"
+	u.Path = base + name
+	u.RawPath = uriEncode(base, false) + uriEncode(name, true)
+	u.RawQuery = query.Encode()
+	return &u, nil
+}
+
+func (st *GCS) do(ctx context.Context, method string, u *url.URL, body io.Reader, size int64, header http.Header) (*http.Response, error) {
+	token, err := st.opts.Token(ctx)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	if body != nil {
+		req.ContentLength = size
+	}
+	return st.opts.Client.Do(req)
+}
+
+func (st *GCS) Put(ctx context.Context, key string, r io.Reader, info media.AssetInfo) error {
+	if err := checkKey(key); err != nil {
+		return err
+	}
+	body, size, cleanup, err := sizedBody(r, info)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	u := *st.endpoint
+	u.Path += "/upload/storage/v1/b/" + st.opts.Bucket + "/o"
+	u.RawQuery = url.Values{"uploadType": {"media"}, "name": {st.opts.Prefix + key}}.Encode()
+	contentType := info.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	resp, err := st.do(ctx, http.MethodPost, &u, body, size, http.Header{"Content-Type": {contentType}})
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return statusError("put", resp)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (st *GCS) Open(ctx context.Context, key string) (media.AssetReader, error) {
+	info, err := st.Stat(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	u, err := st.objectURL(key, url.Values{"alt": {"media"}})
+	if err != nil {
+		return nil, err
+	}
+	return &rangedReader{
+		ctx:  ctx,
+		size: info.Size,
+		get: func(ctx context.Context, offset int64) (*http.Response, error) {
+			return st.do(ctx, http.MethodGet, u, nil, 0, http.Header{"Range": {rangeHeader(offset)}})
+		},
+	}, nil
+}
+
+func (st *GCS) Stat(ctx context.Context, key string) (media.AssetInfo, error) {
+	u, err := st.objectURL(key, nil)
+	if err != nil {
+		return media.AssetInfo{}, err
+	}
+	resp, err := st.do(ctx, http.MethodGet, u, nil, 0, nil)
+	if err != nil {
+		return media.AssetInfo{}, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return media.AssetInfo{}, statusError("stat", resp)
+	}
+	defer resp.Body.Close()
+
+	var obj struct {
+		Size        string    `json:"size"`
+		ContentType string    `json:"contentType"`
+		Updated     time.Time `json:"updated"`
+		ETag        string    `json:"etag"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&obj); err != nil {
+		return media.AssetInfo{}, err
+	}
+	info := media.AssetInfo{
+		Key:         key,
+		ContentType: obj.ContentType,
+		ModTime:     obj.Updated,
+		ETag:        obj.ETag,
+	}
+	info.Size, _ = strconv.ParseInt(obj.Size, 10, 64)
+	return info, nil
+}
+
+func (st *GCS) Delete(ctx context.Context, key string) error {
+	u, err := st.objectURL(key, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := st.do(ctx, http.MethodDelete, u, nil, 0, nil)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		return statusError("delete", resp)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// URL returns a V4 signed GET URL of the given key, valid for expiry (capped at DefaultMaxExpiry), if GCSOpts.PrivateKey is set.
+func (st *GCS) URL(key string, expiry time.Duration) (string, error) {
+	if err := checkKey(key); err != nil {
+		return "", err
+	}
+	if st.key == nil {
+		return "", media.ErrNoDirectURL
+	}
+	now := time.Now().UTC()
+	scope := now.Format("20060102") + "/auto/storage/goog4_request"
+
+	u := *st.endpoint
+	setPath(&u, u.Path+"/"+st.opts.Bucket+"/"+st.opts.Prefix+key)
+	query := url.Values{
+		"X-Goog-Algorithm":     {"GOOG4-RSA-SHA256"},
+		"X-Goog-Credential":    {st.opts.ClientEmail + "/" + scope},
+		"X-Goog-Date":          {now.Format(amzDateFormat)},
+		"X-Goog-Expires":       {formatSeconds(expiry)},
+		"X-Goog-SignedHeaders": {"host"},
+	}
+	u.RawQuery = canonicalQuery(query)
+	canonical, _ := canonicalRequest(http.MethodGet, &u, nil, unsignedPayload)
+	digest := sha256.Sum256([]byte("GOOG4-RSA-SHA256\n" + now.Format(amzDateFormat) + "\n" + scope + "\n" + sha256Hex(canonical)))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, st.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", err
+	}
+	u.RawQuery += "&X-Goog-Signature=" + hex.EncodeToString(sig)
+	return u.String(), nil
+}
@@ -0,0 +1,131 @@
+package stores
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/media"
+)
+
+// Support shared by the object storage backends
+
+const (
+	DefaultMaxExpiry = 7 * 24 * time.Hour // the longest presigned URL lifetime S3 and GCS accept
+)
+
+func formatSeconds(d time.Duration) string {
+	secs := int64(d / time.Second)
+	if secs < 1 {
+		secs = 1
+	}
+	return strconv.FormatInt(min(secs, int64(DefaultMaxExpiry/time.Second)), 10)
+}
+
+// statusError returns an error for the unexpected status of resp, draining its body.
+func statusError(op string, resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return media.ErrAssetNotFound
+	}
+	return fmt.Errorf("stores: %s: %s: %s", op, resp.Status, body)
+}
+
+// sizedBody returns a reader of r whose size is known, spooling r to a temp file if info.Size < 0 since object uploads require a
+// Content-Length.  The returned func removes any temp file.
+func sizedBody(r io.Reader, info media.AssetInfo) (io.Reader, int64, func(), error) {
+	if info.Size >= 0 {
+		return io.LimitReader(r, info.Size), info.Size, func() {}, nil
+	}
+	tmp, err := os.CreateTemp("", "asset-upload-*")
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	cleanup := func() {
+		tmp.Close()
+		os.Remove(tmp.Name())
+	}
+	size, err := io.Copy(tmp, r)
+	if err == nil {
+		_, err = tmp.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		cleanup()
+		return nil, 0, nil, err
+	}
+	return tmp, size, cleanup, nil
+}
+
+// rangedReader reads an object via GETs of the range from the current position, so seeking costs a request only when the
+// position then read from is not where the current response is.
+type rangedReader struct {
+	ctx     context.Context
+	size    int64
+	get     func(ctx context.Context, offset int64) (*http.Response, error)
+	pos     int64
+	body    io.ReadCloser
+	bodyPos int64
+}
+
+func (r *rangedReader) Read(p []byte) (int, error) {
+	if r.pos >= r.size {
+		return 0, io.EOF
+	}
+	if r.body != nil && r.bodyPos != r.pos {
+		r.body.Close()
+		r.body = nil
+	}
+	if r.body == nil {
+		resp, err := r.get(r.ctx, r.pos)
+		if err != nil {
+			return 0, err
+		}
+		if resp.StatusCode != http.StatusPartialContent && !(resp.StatusCode == http.StatusOK && r.pos == 0) {
+			return 0, statusError("read", resp)
+		}
+		r.body, r.bodyPos = resp.Body, r.pos
+	}
+	n, err := r.body.Read(p)
+	r.pos += int64(n)
+	r.bodyPos += int64(n)
+	if err == io.EOF && r.pos < r.size {
+		err = io.ErrUnexpectedEOF
+	}
+	return n, err
+}
+
+func (r *rangedReader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += r.pos
+	case io.SeekEnd:
+		offset += r.size
+	default:
+		return r.pos, errors.New("stores: invalid whence")
+	}
+	if offset < 0 {
+		return r.pos, errors.New("stores: negative position")
+	}
+	r.pos = offset
+	return offset, nil
+}
+
+func (r *rangedReader) Close() error {
+	if r.body == nil {
+		return nil
+	}
+	err := r.body.Close()
+	r.body = nil
+	return err
+}
+
+func rangeHeader(offset int64) string {
+	return "bytes=" + strconv.FormatInt(offset, 10) + "-"
+}
@@ -0,0 +1,165 @@
+// Package stores implements media.AssetStore backed by local disk (NewLocal), Amazon S3 or an S3 compatible service (NewS3), and
+// Google Cloud Storage (NewGCS), so a host can serve a large media library from object storage.
+//
+// The object storage backends use their HTTP APIs directly rather than a vendor SDK.  Each stores an asset at the object named
+// Prefix + key, and their URL method returns a presigned URL so that clients fetch large media from the bucket itself.
+package stores
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/media"
+)
+
+// LocalOpts configures a Local store.
+type LocalOpts struct {
+	Dir     string // directory assets are stored in (created if needed)
+	BaseURL string // if set, URL returns BaseURL + key -- e.g. when Dir is also served by a CDN or static file server
+}
+
+// Local is a media.AssetStore of files in a directory, where each asset's content type is inferred from its extension.
+type Local struct {
+	opts LocalOpts
+}
+
+// NewLocal returns a Local store for the given options.
+func NewLocal(opts LocalOpts) (*Local, error) {
+	if opts.Dir == "" {
+		return nil, errors.New("stores: LocalOpts.Dir is required")
+	}
+	if err := os.MkdirAll(opts.Dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &Local{
+		opts: opts,
+	}, nil
+}
+
+func (st *Local) path(key string) (string, error) {
+	if err := checkKey(key); err != nil {
+		return "", err
+	}
+	return filepath.Join(st.opts.Dir, filepath.FromSlash(key)), nil
+}
+
+func (st *Local) Put(ctx context.Context, key string, r io.Reader, info media.AssetInfo) error {
+	pathname, err := st.path(key)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(pathname), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(pathname), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(tmp, &ctxReader{ctx, r})
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), pathname)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+	}
+	return err
+}
+
+func (st *Local) Open(ctx context.Context, key string) (media.AssetReader, error) {
+	pathname, err := st.path(key)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(pathname)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, media.ErrAssetNotFound
+	}
+	return file, err
+}
+
+func (st *Local) Stat(ctx context.Context, key string) (media.AssetInfo, error) {
+	pathname, err := st.path(key)
+	if err != nil {
+		return media.AssetInfo{}, err
+	}
+	fi, err := os.Stat(pathname)
+	if errors.Is(err, fs.ErrNotExist) || (err == nil && fi.IsDir()) {
+		return media.AssetInfo{}, media.ErrAssetNotFound
+	}
+	if err != nil {
+		return media.AssetInfo{}, err
+	}
+	return media.AssetInfo{
+		Key:         key,
+		Size:        fi.Size(),
+		ContentType: mime.TypeByExtension(path.Ext(key)),
+		ModTime:     fi.ModTime(),
+		ETag:        strconv.FormatInt(fi.ModTime().UnixNano(), 36) + "-" + strconv.FormatInt(fi.Size(), 36),
+	}, nil
+}
+
+func (st *Local) Delete(ctx context.Context, key string) error {
+	pathname, err := st.path(key)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(pathname)
+	if errors.Is(err, fs.ErrNotExist) {
+		return media.ErrAssetNotFound
+	}
+	return err
+}
+
+func (st *Local) URL(key string, expiry time.Duration) (string, error) {
+	if err := checkKey(key); err != nil {
+		return "", err
+	}
+	if st.opts.BaseURL == "" {
+		return "", media.ErrNoDirectURL
+	}
+	return strings.TrimSuffix(st.opts.BaseURL, "/") + "/" + escapePath(key), nil
+}
+
+// checkKey returns an error if key is not a relative, slash-separated path within the store.
+func checkKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") || path.Clean(key) != key || key == ".." || strings.HasPrefix(key, "../") {
+		return fmt.Errorf("stores: invalid key %q", key)
+	}
+	return nil
+}
+
+// escapePath escapes each segment of a slash-separated path.
+func escapePath(key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// ctxReader stops reading once its context is done so that a canceled Put stops promptly.
+type ctxReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (r *ctxReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
+}
@@ -0,0 +1,184 @@
+package stores
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/media"
+)
+
+// S3Opts configures an S3 store.
+type S3Opts struct {
+	Bucket       string
+	Region       string // e.g. "us-east-1" (default), or as required by an S3 compatible service
+	Endpoint     string // default "https://s3.{Region}.amazonaws.com" -- set for S3 compatible services (e.g. MinIO, R2)
+	PathStyle    bool   // if set, the bucket is addressed as Endpoint/Bucket/key rather than as a subdomain of Endpoint
+	AccessKey    string
+	SecretKey    string
+	SessionToken string       // set when using temporary credentials
+	Prefix       string       // prepended to each key to form its object name -- e.g. "media/"
+	Client       *http.Client // default http.DefaultClient
+}
+
+// S3 is a media.AssetStore of objects in an S3 bucket.
+type S3 struct {
+	opts     S3Opts
+	endpoint *url.URL
+	sig      sigV4
+}
+
+// NewS3 returns an S3 store for the given options, where Bucket, AccessKey, and SecretKey are required.
+func NewS3(opts S3Opts) (*S3, error) {
+	if opts.Bucket == "" || opts.AccessKey == "" || opts.SecretKey == "" {
+		return nil, errors.New("stores: S3Opts.Bucket, AccessKey, and SecretKey are required")
+	}
+	if opts.Region == "" {
+		opts.Region = "us-east-1"
+	}
+	if opts.Endpoint == "" {
+		opts.Endpoint = "https://s3." + opts.Region + ".amazonaws.com"
+	}
+	if opts.Client == nil {
+		opts.Client = http.DefaultClient
+	}
+	endpoint, err := url.Parse(opts.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	return &S3{
+		opts:     opts,
+		endpoint: endpoint,
+		sig: sigV4{
+			accessKey:    opts.AccessKey,
+			secretKey:    opts.SecretKey,
+			sessionToken: opts.SessionToken,
+			region:       opts.Region,
+			service:      "s3",
+		},
+	}, nil
+}
+
+func (st *S3) objectURL(key string) (*url.URL, error) {
+	if err := checkKey(key); err != nil {
+		return nil, err
+	}
+	u := *st.endpoint
+	name := st.opts.Prefix + key
+	if st.opts.PathStyle {
+		setPath(&u, strings.TrimSuffix(u.Path, "/")+"/"+st.opts.Bucket+"/"+name)
+	} else {
+		u.Host = st.opts.Bucket + "." + u.Host
+		setPath(&u, strings.TrimSuffix(u.Path, "/")+"/"+name)
+	}
+	return &u, nil
+}
+
+func (st *S3) do(ctx context.Context, method, key string, body io.Reader, size int64, header http.Header) (*http.Response, error) {
+	u, err := st.objectURL(key)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	if body != nil {
+		req.ContentLength = size
+	}
+	payloadHash := emptySHA256
+	if body != nil {
+		payloadHash = unsignedPayload
+	}
+	req.URL = u
+	st.sig.sign(req, payloadHash, time.Now())
+	return st.opts.Client.Do(req)
+}
+
+func (st *S3) Put(ctx context.Context, key string, r io.Reader, info media.AssetInfo) error {
+	body, size, cleanup, err := sizedBody(r, info)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+	header := http.Header{}
+	if info.ContentType != "" {
+		header.Set("Content-Type", info.ContentType)
+	}
+	resp, err := st.do(ctx, http.MethodPut, key, body, size, header)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return statusError("put", resp)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (st *S3) Open(ctx context.Context, key string) (media.AssetReader, error) {
+	info, err := st.Stat(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	return &rangedReader{
+		ctx:  ctx,
+		size: info.Size,
+		get: func(ctx context.Context, offset int64) (*http.Response, error) {
+			return st.do(ctx, http.MethodGet, key, nil, 0, http.Header{"Range": {rangeHeader(offset)}})
+		},
+	}, nil
+}
+
+func (st *S3) Stat(ctx context.Context, key string) (media.AssetInfo, error) {
+	resp, err := st.do(ctx, http.MethodHead, key, nil, 0, nil)
+	if err != nil {
+		return media.AssetInfo{}, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return media.AssetInfo{}, statusError("stat", resp)
+	}
+	resp.Body.Close()
+	info := media.AssetInfo{
+		Key:         key,
+		ContentType: resp.Header.Get("Content-Type"),
+		ETag:        strings.Trim(resp.Header.Get("ETag"), `"`),
+	}
+	info.Size, _ = strconv.ParseInt(resp.Header.Get("Content-Length"), 10, 64)
+	info.ModTime, _ = http.ParseTime(resp.Header.Get("Last-Modified"))
+	return info, nil
+}
+
+func (st *S3) Delete(ctx context.Context, key string) error {
+	// S3 deletes succeed whether or not the object exists, so check first to report ErrAssetNotFound
+	if _, err := st.Stat(ctx, key); err != nil {
+		return err
+	}
+	resp, err := st.do(ctx, http.MethodDelete, key, nil, 0, nil)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		return statusError("delete", resp)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// URL returns a presigned GET URL of the given key, valid for expiry (capped at DefaultMaxExpiry).
+func (st *S3) URL(key string, expiry time.Duration) (string, error) {
+	u, err := st.objectURL(key)
+	if err != nil {
+		return "", err
+	}
+	st.sig.presign(u, expiry, time.Now())
+	return u.String(), nil
+}
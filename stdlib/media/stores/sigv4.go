@@ -0,0 +1,168 @@
+package stores
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// AWS Signature Version 4 (https://docs.aws.amazon.com/IAM/latest/UserGuide/reference_sigv-create-signed-request.html),
+// whose canonical request form is also used by GCS V4 signed URLs.
+
+const (
+	amzDateFormat   = "20060102T150405Z"
+	unsignedPayload = "UNSIGNED-PAYLOAD"
+	emptySHA256     = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+)
+
+// uriEncode percent-encodes every byte of s other than unreserved characters (and '/' unless encodeSlash).
+func uriEncode(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-', c == '.', c == '_', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			b.WriteByte('%')
+			b.WriteByte("0123456789ABCDEF"[c>>4])
+			b.WriteByte("0123456789ABCDEF"[c&15])
+		}
+	}
+	return b.String()
+}
+
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var parts []string
+	for _, key := range keys {
+		values := append([]string(nil), query[key]...)
+		sort.Strings(values)
+		for _, value := range values {
+			parts = append(parts, uriEncode(key, true)+"="+uriEncode(value, true))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// canonicalRequest returns the canonical request of the given request and the names of the headers it signs.
+// The headers signed are host and any given in header (whose names must be lowercase).
+func canonicalRequest(method string, u *url.URL, header map[string]string, payloadHash string) (canonical string, signedHeaders string) {
+	names := []string{"host"}
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString(method)
+	b.WriteByte('\n')
+	b.WriteString(uriEncode(u.Path, false))
+	b.WriteByte('\n')
+	b.WriteString(canonicalQuery(u.Query()))
+	b.WriteByte('\n')
+	for _, name := range names {
+		value := u.Host
+		if name != "host" {
+			value = strings.TrimSpace(header[name])
+		}
+		b.WriteString(name)
+		b.WriteByte(':')
+		b.WriteString(value)
+		b.WriteByte('\n')
+	}
+	signedHeaders = strings.Join(names, ";")
+	b.WriteByte('\n')
+	b.WriteString(signedHeaders)
+	b.WriteByte('\n')
+	b.WriteString(payloadHash)
+	return b.String(), signedHeaders
+}
+
+func sha256Hex(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// sigV4 signs requests to a service in a region using an access key.
+type sigV4 struct {
+	accessKey    string
+	secretKey    string
+	sessionToken string
+	region       string
+	service      string
+}
+
+func (sig *sigV4) scope(now time.Time) string {
+	return now.Format("20060102") + "/" + sig.region + "/" + sig.service + "/aws4_request"
+}
+
+func (sig *sigV4) signature(now time.Time, canonical string) string {
+	toSign := "AWS4-HMAC-SHA256\n" + now.Format(amzDateFormat) + "\n" + sig.scope(now) + "\n" + sha256Hex(canonical)
+	key := hmacSHA256([]byte("AWS4"+sig.secretKey), now.Format("20060102"))
+	key = hmacSHA256(key, sig.region)
+	key = hmacSHA256(key, sig.service)
+	key = hmacSHA256(key, "aws4_request")
+	return hex.EncodeToString(hmacSHA256(key, toSign))
+}
+
+// sign adds an Authorization header to req, whose URL path must be unescaped (see setPath).
+func (sig *sigV4) sign(req *http.Request, payloadHash string, now time.Time) {
+	now = now.UTC()
+	header := map[string]string{
+		"x-amz-date":           now.Format(amzDateFormat),
+		"x-amz-content-sha256": payloadHash,
+	}
+	if sig.sessionToken != "" {
+		header["x-amz-security-token"] = sig.sessionToken
+	}
+	if contentType := req.Header.Get("Content-Type"); contentType != "" {
+		header["content-type"] = contentType
+	}
+	for name, value := range header {
+		req.Header.Set(name, value)
+	}
+	canonical, signedHeaders := canonicalRequest(req.Method, req.URL, header, payloadHash)
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+sig.accessKey+"/"+sig.scope(now)+
+		", SignedHeaders="+signedHeaders+", Signature="+sig.signature(now, canonical))
+}
+
+// presign adds query params to u authorizing a GET of it until the given expiry.
+func (sig *sigV4) presign(u *url.URL, expiry time.Duration, now time.Time) {
+	now = now.UTC()
+	query := u.Query()
+	query.Set("X-Amz-Algorithm", "AWS4-HMAC-SHA256")
+	query.Set("X-Amz-Credential", sig.accessKey+"/"+sig.scope(now))
+	query.Set("X-Amz-Date", now.Format(amzDateFormat))
+	query.Set("X-Amz-Expires", formatSeconds(expiry))
+	query.Set("X-Amz-SignedHeaders", "host")
+	if sig.sessionToken != "" {
+		query.Set("X-Amz-Security-Token", sig.sessionToken)
+	}
+	u.RawQuery = canonicalQuery(query)
+	canonical, _ := canonicalRequest(http.MethodGet, u, nil, unsignedPayload)
+	u.RawQuery += "&X-Amz-Signature=" + sig.signature(now, canonical)
+}
+
+// setPath sets the path of u such that it is sent escaped as its canonical form.
+func setPath(u *url.URL, path string) {
+	u.Path = path
+	u.RawPath = uriEncode(path, false)
+}
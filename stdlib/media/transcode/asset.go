@@ -0,0 +1,106 @@
+package transcode
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os/exec"
+	"sync"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/media"
+	"github.com/amp-3d/amp-sdk-go/stdlib/task"
+)
+
+var (
+	ErrNotSeekable = errors.New("transcode: stream cannot seek")
+)
+
+// Asset is a media.Asset that remuxes or transcodes its source asset as planned, where each reader runs ffmpeg and reads its
+// output as it is produced.
+type Asset struct {
+	Plan
+	source media.Asset
+	tc     *Transcoder
+}
+
+func (asset *Asset) Label() string {
+	return asset.source.Label() + " (" + asset.Op.String() + " to " + asset.Output.ContentType + ")"
+}
+
+func (asset *Asset) ContentType() string {
+	return asset.Output.String()
+}
+
+func (asset *Asset) OnStart(ctx task.Context) error {
+	return asset.source.OnStart(ctx)
+}
+
+// NewAssetReader starts ffmpeg reading the source asset and returns a reader of its output, which cannot seek.
+func (asset *Asset) NewAssetReader() (media.AssetReader, error) {
+	source, err := asset.source.NewAssetReader()
+	if err != nil {
+		return nil, err
+	}
+
+	args := append(asset.MapArgs(), asset.CodecArgs()...)
+	args = append(args, asset.Target.MuxArgs...)
+	cmd := asset.tc.Command(source, append(args, "pipe:1")...)
+	stream := &stream{
+		cmd:    cmd,
+		source: source,
+	}
+	cmd.Stderr = &stream.stderr
+	if stream.stdout, err = cmd.StdoutPipe(); err == nil {
+		err = cmd.Start()
+	}
+	if err != nil {
+		source.Close()
+		return nil, err
+	}
+	return stream, nil
+}
+
+// stream reads the output of ffmpeg, returning its failure (if any) in place of io.EOF.
+type stream struct {
+	cmd     *exec.Cmd
+	source  media.AssetReader
+	stdout  io.ReadCloser
+	stderr  bytes.Buffer
+	pos     int64
+	once    sync.Once
+	waitErr error
+}
+
+func (s *stream) Read(buf []byte) (int, error) {
+	n, err := s.stdout.Read(buf)
+	s.pos += int64(n)
+	if err == io.EOF {
+		s.once.Do(func() {
+			if waitErr := s.cmd.Wait(); waitErr != nil {
+				s.waitErr = commandError("ffmpeg", waitErr, &s.stderr)
+			}
+			s.source.Close()
+		})
+		if s.waitErr != nil {
+			err = s.waitErr
+		}
+	}
+	return n, err
+}
+
+// Seek only reports the current position, since the output is produced as it is read.
+func (s *stream) Seek(offset int64, whence int) (int64, error) {
+	if offset == 0 && whence == io.SeekCurrent {
+		return s.pos, nil
+	}
+	return s.pos, ErrNotSeekable
+}
+
+func (s *stream) Close() error {
+	s.once.Do(func() {
+		s.source.Close() // closed first so that copying it to ffmpeg cannot block
+		s.cmd.Process.Kill()
+		s.cmd.Wait()
+	})
+	return nil
+}
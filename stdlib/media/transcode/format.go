@@ -0,0 +1,140 @@
+package transcode
+
+import (
+	"mime"
+	"strings"
+)
+
+// Format is a media type with the codecs of its streams, as in `video/mp4; codecs="avc1.42E01E, mp4a.40.2"` (RFC 6381).
+type Format struct {
+	ContentType string   // e.g. "video/mp4"
+	Codecs      []string // e.g. "avc1.42E01E", "mp4a.40.2", or "opus" -- empty if not known or not restricted
+}
+
+// ParseFormat parses the given media type, returning false if it is malformed.
+func ParseFormat(mediaType string) (Format, bool) {
+	contentType, params, err := mime.ParseMediaType(mediaType)
+	if err != nil || !strings.Contains(contentType, "/") {
+		return Format{}, false
+	}
+	f := Format{ContentType: contentType}
+	for _, codec := range strings.Split(params["codecs"], ",") {
+		if codec = strings.TrimSpace(codec); codec != "" {
+			f.Codecs = append(f.Codecs, codec)
+		}
+	}
+	return f, true
+}
+
+func (f Format) String() string {
+	if len(f.Codecs) == 0 {
+		return f.ContentType
+	}
+	return f.ContentType + `; codecs="` + strings.Join(f.Codecs, ", ") + `"`
+}
+
+// IsAudio returns true if this is an audio format.
+func (f Format) IsAudio() bool {
+	return strings.HasPrefix(f.ContentType, "audio/")
+}
+
+// IsVideo returns true if this is a video format.
+func (f Format) IsVideo() bool {
+	return strings.HasPrefix(f.ContentType, "video/")
+}
+
+// codecFamily returns the codec an RFC 6381 codec string names, less its profile and level -- e.g. "avc1.42E01E" -> "avc1".
+func codecFamily(codec string) string {
+	family, _, _ := strings.Cut(strings.ToLower(codec), ".")
+	switch family {
+	case "avc3":
+		return "avc1"
+	case "hev1":
+		return "hvc1"
+	case "vp9":
+		return "vp09"
+	case "mp4a":
+		if strings.EqualFold(codec, "mp4a.6b") || strings.EqualFold(codec, "mp4a.40.34") {
+			return "mp3" // MP3 within MP4
+		}
+	}
+	return family
+}
+
+// ffprobeCodecs maps the codec names reported by ffprobe to their RFC 6381 codec family.
+var ffprobeCodecs = map[string]string{
+	"h264":      "avc1",
+	"hevc":      "hvc1",
+	"av1":       "av01",
+	"vp8":       "vp8",
+	"vp9":       "vp09",
+	"aac":       "mp4a",
+	"mp3":       "mp3",
+	"opus":      "opus",
+	"vorbis":    "vorbis",
+	"flac":      "flac",
+	"alac":      "alac",
+	"ac3":       "ac-3",
+	"eac3":      "ec-3",
+	"pcm_s16le": "1", // WAVE format tag of PCM, as in `audio/wav; codecs="1"`
+	"pcm_s24le": "1",
+}
+
+// Caps are the formats a client can play (see amp.Login.MediaTypes).
+type Caps []Format
+
+// ParseCaps parses the given media types, skipping any that are malformed.
+func ParseCaps(mediaTypes []string) Caps {
+	caps := make(Caps, 0, len(mediaTypes))
+	for _, mediaType := range mediaTypes {
+		if f, ok := ParseFormat(mediaType); ok {
+			caps = append(caps, f)
+		}
+	}
+	return caps
+}
+
+// Plays returns true if these caps include the given format: a cap of the same content type (where a cap of "audio/*" matches
+// any audio type) where each of the format's codecs is one the cap lists, or any codec if the cap lists none.
+// Codecs are compared by family, so a cap of "avc1.640028" is taken to play "avc1.42E01E".
+func (caps Caps) Plays(f Format) bool {
+	for _, c := range caps {
+		if !matchType(c.ContentType, f.ContentType) {
+			continue
+		}
+		if len(c.Codecs) == 0 {
+			return true
+		}
+		if len(f.Codecs) == 0 {
+			continue // the format's codecs are not known so they cannot be checked against the cap
+		}
+		playsAll := true
+		for _, codec := range f.Codecs {
+			if !hasCodec(c.Codecs, codec) {
+				playsAll = false
+				break
+			}
+		}
+		if playsAll {
+			return true
+		}
+	}
+	return false
+}
+
+func matchType(pattern, contentType string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "/*"); ok {
+		return strings.HasPrefix(contentType, prefix+"/")
+	}
+	return pattern == "*/*" || strings.EqualFold(pattern, contentType)
+}
+
+func hasCodec(codecs []string, codec string) bool {
+	family := codecFamily(codec)
+	for _, c := range codecs {
+		if codecFamily(c) == family {
+			return true
+		}
+	}
+	return false
+}
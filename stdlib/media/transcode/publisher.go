@@ -0,0 +1,65 @@
+package transcode
+
+import (
+	"context"
+	"time"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/media"
+)
+
+// Publisher is a media.Publisher (also implementing media.AssetAdapter and media.URLSigner) that publishes assets via another
+// Publisher, first adapting each for a client's caps.
+type Publisher struct {
+	pub  media.Publisher
+	tc   *Transcoder
+	caps Caps
+}
+
+// ForClient returns a Publisher that publishes via pub, adapting assets for a client playing the given media types (see
+// amp.Login.MediaTypes).  Typically, pub is a session's publisher (e.g. from publisher.Publisher.ForSession).
+func (tc *Transcoder) ForClient(pub media.Publisher, mediaTypes []string) *Publisher {
+	return &Publisher{
+		pub:  pub,
+		tc:   tc,
+		caps: ParseCaps(mediaTypes),
+	}
+}
+
+// Caps returns the caps of this Publisher's client.
+func (pub *Publisher) Caps() Caps {
+	return pub.caps
+}
+
+// AdaptAsset implements media.AssetAdapter.  If the asset cannot be adapted (e.g. ffprobe is unavailable or the client plays
+// no format that can be produced), it is returned as-is, since a client may still be able to play it.
+func (pub *Publisher) AdaptAsset(asset media.Asset) (media.Asset, error) {
+	timeout := pub.tc.ProbeTimeout
+	if timeout <= 0 {
+		timeout = DefaultProbeTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	adapted, err := pub.tc.Adapt(ctx, asset, pub.caps)
+	if err != nil {
+		return asset, nil
+	}
+	return adapted, nil
+}
+
+// PublishAsset implements media.Publisher by publishing the asset as adapted by AdaptAsset.
+func (pub *Publisher) PublishAsset(asset media.Asset, opts media.PublishOpts) (string, error) {
+	adapted, err := pub.AdaptAsset(asset)
+	if err != nil {
+		return "", err
+	}
+	return pub.pub.PublishAsset(adapted, opts)
+}
+
+// SignURL implements media.URLSigner by signing via the underlying Publisher, if it signs URLs.
+func (pub *Publisher) SignURL(rawURL string, expiry time.Duration) (string, error) {
+	if signer, ok := pub.pub.(media.URLSigner); ok {
+		return signer.SignURL(rawURL, expiry)
+	}
+	return rawURL, nil
+}
@@ -0,0 +1,289 @@
+// Package transcode adapts media assets to the formats a client can play, remuxing or transcoding them via ffmpeg.
+//
+// A client declares the media types it plays when logging in (see amp.Login.MediaTypes).  A host typically returns a Publisher
+// from HostSession.AssetPublisher via Transcoder.ForClient, so that each audio or video asset an app publishes is planned:
+//   - passed through if the client plays its format,
+//   - remuxed (its streams copied as-is into another container) if the client plays its codecs in another container, or
+//   - transcoded into the first of the Transcoder's Targets the client plays.
+//
+// A remuxed or transcoded asset is streamed from ffmpeg as it is produced, so playback can begin before transcoding completes;
+// as a consequence, its reader cannot seek.
+package transcode
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/media"
+)
+
+var (
+	ErrNotPlayable = errors.New("transcode: no format the client plays can be produced")
+)
+
+const DefaultProbeTimeout = 10 * time.Second
+
+// Op is how a Plan adapts an asset.
+type Op int
+
+const (
+	Passthrough Op = iota // the asset is published as-is
+	Remux                 // the asset's streams are copied into another container
+	Transcode             // the asset's streams are re-encoded
+)
+
+func (op Op) String() string {
+	switch op {
+	case Passthrough:
+		return "passthrough"
+	case Remux:
+		return "remux"
+	case Transcode:
+		return "transcode"
+	}
+	return fmt.Sprintf("Op(%d)", int(op))
+}
+
+// Target is a format a Transcoder can produce.
+type Target struct {
+	Format           // the content type and codecs produced when transcoding
+	Muxes   []string // codec families the container carries, so that a source having only these codecs is remuxed
+	MuxArgs []string // ffmpeg output options of the container, which must be streamable (e.g. fragmented MP4)
+	EncArgs []string // ffmpeg output options encoding the source into the codecs of Format
+}
+
+var fragmentedMP4 = []string{"-f", "mp4", "-movflags", "frag_keyframe+empty_moov+default_base_moof"}
+
+// DefaultTargets are the targets of a Transcoder if none are given, in order of preference.
+var DefaultTargets = []Target{
+	{
+		Format:  Format{"audio/mpeg", []string{"mp3"}},
+		Muxes:   []string{"mp3"},
+		MuxArgs: []string{"-f", "mp3"},
+		EncArgs: []string{"-c:a", "libmp3lame", "-q:a", "2"},
+	}, {
+		Format:  Format{"audio/mp4", []string{"mp4a.40.2"}},
+		Muxes:   []string{"mp4a", "alac", "mp3", "flac", "opus", "ac-3", "ec-3"},
+		MuxArgs: fragmentedMP4,
+		EncArgs: []string{"-c:a", "aac", "-b:a", "192k"},
+	}, {
+		Format:  Format{"audio/ogg", []string{"opus"}},
+		Muxes:   []string{"opus", "vorbis", "flac"},
+		MuxArgs: []string{"-f", "ogg"},
+		EncArgs: []string{"-c:a", "libopus", "-b:a", "128k"},
+	}, {
+		Format:  Format{"audio/webm", []string{"opus"}},
+		Muxes:   []string{"opus", "vorbis"},
+		MuxArgs: []string{"-f", "webm"},
+		EncArgs: []string{"-c:a", "libopus", "-b:a", "128k"},
+	}, {
+		Format:  Format{"video/mp4", []string{"avc1.640028", "mp4a.40.2"}},
+		Muxes:   []string{"avc1", "hvc1", "av01", "vp09", "mp4a", "mp3", "opus", "ac-3", "ec-3"},
+		MuxArgs: fragmentedMP4,
+		EncArgs: []string{"-c:v", "libx264", "-preset", "veryfast", "-crf", "23", "-pix_fmt", "yuv420p", "-c:a", "aac", "-b:a", "160k"},
+	}, {
+		Format:  Format{"video/webm", []string{"vp09", "opus"}},
+		Muxes:   []string{"vp8", "vp09", "av01", "opus", "vorbis"},
+		MuxArgs: []string{"-f", "webm"},
+		EncArgs: []string{"-c:v", "libvpx-vp9", "-deadline", "realtime", "-row-mt", "1", "-b:v", "0", "-crf", "32", "-c:a", "libopus", "-b:a", "128k"},
+	},
+}
+
+// Plan is how a Transcoder adapts an asset for a client.
+type Plan struct {
+	Op     Op
+	Source Format  // the asset's format, including the codecs found by probing if it was probed
+	Output Format  // the format published, which is Source if Op is Passthrough
+	Target *Target // the target remuxed or transcoded into, or nil if Op is Passthrough
+}
+
+// Transcoder plans how assets are adapted for clients and runs ffmpeg to remux or transcode them.
+// Its zero value is ready to use, assuming ffmpeg and ffprobe are in $PATH.
+type Transcoder struct {
+	FFmpegPath   string        // path of the ffmpeg binary (default "ffmpeg")
+	FFprobePath  string        // path of the ffprobe binary (default "ffprobe")
+	Targets      []Target      // formats that may be produced, in order of preference (default DefaultTargets)
+	ProbeTimeout time.Duration // max time spent probing an asset when publishing it (default DefaultProbeTimeout)
+}
+
+func (tc *Transcoder) targets() []Target {
+	if len(tc.Targets) > 0 {
+		return tc.Targets
+	}
+	return DefaultTargets
+}
+
+// Plan returns how the given asset is adapted for a client having the given caps, probing the asset's codecs if needed.
+// An asset that is not audio or video, or any asset for a client declaring no caps, is passed through.
+func (tc *Transcoder) Plan(ctx context.Context, asset media.Asset, caps Caps) (*Plan, error) {
+	if adapted, isAdapted := asset.(*Asset); isAdapted {
+		return &Plan{Op: Passthrough, Source: adapted.Output, Output: adapted.Output}, nil
+	}
+	src, ok := ParseFormat(asset.ContentType())
+	if !ok || len(caps) == 0 || (!src.IsAudio() && !src.IsVideo()) || caps.Plays(src) {
+		return &Plan{Op: Passthrough, Source: src, Output: src}, nil
+	}
+
+	codecs, err := tc.probe(ctx, asset)
+	if err != nil {
+		return nil, err
+	}
+	if len(src.Codecs) == 0 {
+		src.Codecs = codecs
+	}
+	if caps.Plays(src) {
+		return &Plan{Op: Passthrough, Source: src, Output: src}, nil
+	}
+
+	targets := tc.targets()
+	for i := range targets {
+		target := &targets[i]
+		if target.IsVideo() != src.IsVideo() {
+			continue
+		}
+		remuxed := Format{ContentType: target.ContentType, Codecs: src.Codecs}
+		if len(src.Codecs) > 0 && caps.Plays(remuxed) && target.muxes(src.Codecs) {
+			return &Plan{Op: Remux, Source: src, Output: remuxed, Target: target}, nil
+		}
+	}
+	for i := range targets {
+		target := &targets[i]
+		if target.IsVideo() == src.IsVideo() && caps.Plays(target.Format) {
+			return &Plan{Op: Transcode, Source: src, Output: target.Format, Target: target}, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %v", ErrNotPlayable, src)
+}
+
+// MapArgs returns the ffmpeg options selecting the streams of the source that are output: the first video stream (if the output is
+// video) and audio stream, which excludes attached pictures and secondary tracks.
+func (plan *Plan) MapArgs() []string {
+	if plan.Output.IsVideo() {
+		return []string{"-map", "0:v:0", "-map", "0:a:0?"}
+	}
+	return []string{"-map", "0:a:0"}
+}
+
+// CodecArgs returns the ffmpeg options encoding the output: stream copy unless Op is Transcode.
+func (plan *Plan) CodecArgs() []string {
+	if plan.Op != Transcode {
+		return []string{"-c", "copy"}
+	}
+	return plan.Target.EncArgs
+}
+
+func (target *Target) muxes(codecs []string) bool {
+	for _, codec := range codecs {
+		if !hasCodec(target.Muxes, codec) {
+			return false
+		}
+	}
+	return true
+}
+
+// Adapt returns the asset published in place of the given asset for a client having the given caps: the asset itself if it is
+// passed through, otherwise an Asset that remuxes or transcodes it when read.
+func (tc *Transcoder) Adapt(ctx context.Context, asset media.Asset, caps Caps) (media.Asset, error) {
+	plan, err := tc.Plan(ctx, asset, caps)
+	if err != nil {
+		return nil, err
+	}
+	if plan.Op == Passthrough {
+		return asset, nil
+	}
+	return &Asset{
+		Plan:   *plan,
+		source: asset,
+		tc:     tc,
+	}, nil
+}
+
+// probe returns the codecs of the streams of the given asset, less any attached pictures (e.g. the cover art of a song).
+func (tc *Transcoder) probe(ctx context.Context, asset media.Asset) ([]string, error) {
+	reader, err := asset.NewAssetReader()
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	path := tc.FFprobePath
+	if path == "" {
+		path = "ffprobe"
+	}
+	input, stdin := inputOf(reader)
+	cmd := exec.CommandContext(ctx, path, "-hide_banner", "-v", "error",
+		"-show_entries", "stream=codec_type,codec_name:stream_disposition=attached_pic", "-of", "json", input)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = stdin, &stdout, &stderr
+	if err = cmd.Run(); err != nil {
+		return nil, commandError("ffprobe", err, &stderr)
+	}
+
+	var probed struct {
+		Streams []struct {
+			CodecName   string `json:"codec_name"`
+			CodecType   string `json:"codec_type"`
+			Disposition struct {
+				AttachedPic int `json:"attached_pic"`
+			} `json:"disposition"`
+		} `json:"streams"`
+	}
+	if err = json.Unmarshal(stdout.Bytes(), &probed); err != nil {
+		return nil, fmt.Errorf("transcode: ffprobe output: %w", err)
+	}
+	var codecs []string
+	for _, stream := range probed.Streams {
+		if (stream.CodecType != "audio" && stream.CodecType != "video") || stream.Disposition.AttachedPic != 0 {
+			continue
+		}
+		codec, known := ffprobeCodecs[stream.CodecName]
+		if !known {
+			codec = stream.CodecName
+		}
+		if !hasCodec(codecs, codec) {
+			codecs = append(codecs, codec)
+		}
+	}
+	return codecs, nil
+}
+
+// Command returns an ffmpeg command reading the given source -- directly if it is a file, otherwise via stdin -- and writing
+// the output the given options specify.
+func (tc *Transcoder) Command(source media.AssetReader, outputArgs ...string) *exec.Cmd {
+	path := tc.FFmpegPath
+	if path == "" {
+		path = "ffmpeg"
+	}
+	input, stdin := inputOf(source)
+	args := append([]string{"-hide_banner", "-v", "error", "-i", input}, outputArgs...)
+	cmd := exec.Command(path, args...)
+	cmd.Stdin = stdin
+	return cmd
+}
+
+// inputOf returns the ffmpeg input arg of the given reader: its path if it is a file, otherwise stdin, which it is copied to.
+func inputOf(reader media.AssetReader) (string, io.Reader) {
+	if f, ok := reader.(*os.File); ok {
+		return f.Name(), nil
+	}
+	return "pipe:0", reader
+}
+
+func commandError(name string, err error, stderr *bytes.Buffer) error {
+	msg := strings.TrimSpace(stderr.String())
+	if i := strings.LastIndexByte(msg, '\n'); i >= 0 {
+		msg = msg[i+1:] // the last line is the most specific
+	}
+	if msg == "" {
+		return fmt.Errorf("transcode: %s: %w", name, err)
+	}
+	return fmt.Errorf("transcode: %s: %w: %s", name, err, msg)
+}
@@ -0,0 +1,245 @@
+package transcode
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/media"
+	"github.com/amp-3d/amp-sdk-go/stdlib/task"
+	"github.com/stretchr/testify/require"
+)
+
+type bytesAsset struct {
+	contentType string
+	data        []byte
+	opened      int
+}
+
+func (asset *bytesAsset) Label() string                  { return "test asset" }
+func (asset *bytesAsset) ContentType() string            { return asset.contentType }
+func (asset *bytesAsset) OnStart(ctx task.Context) error { return nil }
+
+func (asset *bytesAsset) NewAssetReader() (media.AssetReader, error) {
+	asset.opened++
+	return nopCloser{bytes.NewReader(asset.data)}, nil
+}
+
+type nopCloser struct {
+	*bytes.Reader
+}
+
+func (nopCloser) Close() error { return nil }
+
+// probed returns content that the fake ffprobe (see fakeTools) reports as having the given streams.
+func probed(streams ...string) []byte {
+	return []byte(`{"streams": [` + strings.Join(streams, ",") + `]}`)
+}
+
+const (
+	h264Stream = `{"codec_name": "h264", "codec_type": "video", "disposition": {"attached_pic": 0}}`
+	aacStream  = `{"codec_name": "aac", "codec_type": "audio", "disposition": {"attached_pic": 0}}`
+	flacStream = `{"codec_name": "flac", "codec_type": "audio", "disposition": {"attached_pic": 0}}`
+	coverArt   = `{"codec_name": "mjpeg", "codec_type": "video", "disposition": {"attached_pic": 1}}`
+)
+
+// fakeTools returns a Transcoder running stand-ins for ffprobe, which outputs its input as-is, and ffmpeg, which records its
+// args in the returned file and outputs "out:" followed by its input.
+func fakeTools(t *testing.T) (*Transcoder, string) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires a shell")
+	}
+	dir := t.TempDir()
+	argsFile := filepath.Join(dir, "args")
+	ffprobe := filepath.Join(dir, "ffprobe")
+	require.NoError(t, os.WriteFile(ffprobe, []byte(`#!/bin/sh
+for arg; do input="$arg"; done
+if [ "$input" = "pipe:0" ]; then input=-; fi
+cat "$input"
+`), 0755))
+	ffmpeg := filepath.Join(dir, "ffmpeg")
+	require.NoError(t, os.WriteFile(ffmpeg, []byte(`#!/bin/sh
+echo "$@" > "`+argsFile+`"
+for arg; do
+	if [ "$prev" = "-i" ]; then input="$arg"; fi
+	prev="$arg"
+done
+if [ "$input" = "pipe:0" ]; then input=-; fi
+printf 'out:'
+cat "$input"
+`), 0755))
+
+	return &Transcoder{FFmpegPath: ffmpeg, FFprobePath: ffprobe}, argsFile
+}
+
+func TestFormat(t *testing.T) {
+	f, ok := ParseFormat(`video/mp4; codecs="avc1.42E01E, mp4a.40.2"`)
+	require.True(t, ok)
+	require.Equal(t, "video/mp4", f.ContentType)
+	require.Equal(t, []string{"avc1.42E01E", "mp4a.40.2"}, f.Codecs)
+	require.Equal(t, `video/mp4; codecs="avc1.42E01E, mp4a.40.2"`, f.String())
+	require.True(t, f.IsVideo())
+	_, ok = ParseFormat("not a type;")
+	require.False(t, ok)
+
+	caps := ParseCaps([]string{`video/mp4; codecs="avc1.640028, mp4a.40.2"`, "audio/mpeg", "audio/*; codecs=opus", "bad;"})
+	require.Len(t, caps, 3)
+	require.True(t, caps.Plays(Format{"video/mp4", []string{"avc1.42E01E"}}))
+	require.True(t, caps.Plays(Format{"video/mp4", []string{"avc3.4d401f", "mp4a.40.5"}}))
+	require.False(t, caps.Plays(Format{"video/mp4", []string{"hvc1"}}))
+	require.False(t, caps.Plays(Format{"video/mp4", nil}))
+	require.True(t, caps.Plays(Format{"audio/mpeg", nil}))
+	require.True(t, caps.Plays(Format{"audio/ogg", []string{"opus"}}))
+	require.False(t, caps.Plays(Format{"audio/ogg", []string{"vorbis"}}))
+	require.False(t, caps.Plays(Format{"video/webm", nil}))
+}
+
+func TestPlan(t *testing.T) {
+	tc, _ := fakeTools(t)
+	ctx := context.Background()
+
+	// Passed through without probing
+	mp3 := &bytesAsset{contentType: "audio/mpeg"}
+	plan, err := tc.Plan(ctx, mp3, ParseCaps([]string{"audio/mpeg"}))
+	require.NoError(t, err)
+	require.Equal(t, Passthrough, plan.Op)
+	plan, err = tc.Plan(ctx, mp3, nil)
+	require.NoError(t, err)
+	require.Equal(t, Passthrough, plan.Op)
+	text := &bytesAsset{contentType: "text/plain"}
+	plan, err = tc.Plan(ctx, text, ParseCaps([]string{"audio/mpeg"}))
+	require.NoError(t, err)
+	require.Equal(t, Passthrough, plan.Op)
+	require.Zero(t, mp3.opened+text.opened)
+
+	// Passed through once probed
+	mp4 := &bytesAsset{contentType: "video/mp4", data: probed(h264Stream, aacStream)}
+	plan, err = tc.Plan(ctx, mp4, ParseCaps([]string{`video/mp4; codecs="avc1.640028, mp4a.40.2"`}))
+	require.NoError(t, err)
+	require.Equal(t, Passthrough, plan.Op)
+	require.Equal(t, []string{"avc1", "mp4a"}, plan.Source.Codecs)
+	require.Equal(t, 1, mp4.opened)
+
+	// Remuxed into a container the client plays
+	mkv := &bytesAsset{contentType: "video/x-matroska", data: probed(h264Stream, aacStream)}
+	plan, err = tc.Plan(ctx, mkv, ParseCaps([]string{`video/mp4; codecs="avc1.640028, mp4a.40.2"`, "audio/mpeg"}))
+	require.NoError(t, err)
+	require.Equal(t, Remux, plan.Op)
+	require.Equal(t, `video/mp4; codecs="avc1, mp4a"`, plan.Output.String())
+
+	// Transcoded, where cover art is not a stream of the song
+	flac := &bytesAsset{contentType: "audio/flac", data: probed(flacStream, coverArt)}
+	plan, err = tc.Plan(ctx, flac, ParseCaps([]string{"video/mp4", "audio/ogg; codecs=vorbis", "audio/mpeg"}))
+	require.NoError(t, err)
+	require.Equal(t, Transcode, plan.Op)
+	require.Equal(t, []string{"flac"}, plan.Source.Codecs)
+	require.Equal(t, "audio/mpeg", plan.Output.ContentType)
+
+	_, err = tc.Plan(ctx, flac, ParseCaps([]string{"audio/x-unknown"}))
+	require.ErrorIs(t, err, ErrNotPlayable)
+	_, err = (&Transcoder{FFprobePath: filepath.Join(t.TempDir(), "missing")}).Plan(ctx, flac, ParseCaps([]string{"audio/mpeg"}))
+	require.Error(t, err)
+}
+
+func TestStream(t *testing.T) {
+	tc, argsFile := fakeTools(t)
+	ctx := context.Background()
+
+	mkv := &bytesAsset{contentType: "video/x-matroska", data: probed(h264Stream, aacStream)}
+	adapted, err := tc.Adapt(ctx, mkv, ParseCaps([]string{`video/mp4; codecs="avc1, mp4a"`}))
+	require.NoError(t, err)
+	require.Equal(t, `video/mp4; codecs="avc1, mp4a"`, adapted.ContentType())
+	require.Contains(t, adapted.Label(), "remux to video/mp4")
+
+	reader, err := adapted.NewAssetReader()
+	require.NoError(t, err)
+	_, err = reader.Seek(0, io.SeekEnd)
+	require.ErrorIs(t, err, ErrNotSeekable)
+	out, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	require.Equal(t, "out:"+string(mkv.data), string(out))
+	pos, err := reader.Seek(0, io.SeekCurrent)
+	require.NoError(t, err)
+	require.Equal(t, int64(len(out)), pos)
+	require.NoError(t, reader.Close())
+
+	args, err := os.ReadFile(argsFile)
+	require.NoError(t, err)
+	require.Contains(t, string(args), "-i pipe:0 -map 0:v:0 -map 0:a:0? -c copy -f mp4 -movflags frag_keyframe+empty_moov+default_base_moof pipe:1")
+
+	// A source on disk is read by ffmpeg directly
+	flac := &bytesAsset{contentType: "audio/flac", data: probed(flacStream)}
+	path := filepath.Join(t.TempDir(), "song.flac")
+	require.NoError(t, os.WriteFile(path, flac.data, 0644))
+	onDisk := &fileAsset{bytesAsset: flac, path: path}
+	adapted, err = tc.Adapt(ctx, onDisk, ParseCaps([]string{"audio/mpeg"}))
+	require.NoError(t, err)
+	reader, err = adapted.NewAssetReader()
+	require.NoError(t, err)
+	out, err = io.ReadAll(reader)
+	require.NoError(t, err)
+	require.Equal(t, "out:"+string(flac.data), string(out))
+	reader.Close()
+	args, err = os.ReadFile(argsFile)
+	require.NoError(t, err)
+	require.Contains(t, string(args), "-i "+path+" -map 0:a:0 -c:a libmp3lame -q:a 2 -f mp3 pipe:1")
+
+	// Closed before the output is read
+	reader, err = adapted.NewAssetReader()
+	require.NoError(t, err)
+	require.NoError(t, reader.Close())
+}
+
+type fileAsset struct {
+	*bytesAsset
+	path string
+}
+
+func (asset *fileAsset) NewAssetReader() (media.AssetReader, error) {
+	return os.Open(asset.path)
+}
+
+type testPublisher struct {
+	published []media.Asset
+}
+
+func (pub *testPublisher) PublishAsset(asset media.Asset, opts media.PublishOpts) (string, error) {
+	pub.published = append(pub.published, asset)
+	return "http://localhost/asset", nil
+}
+
+func TestPublisher(t *testing.T) {
+	tc, _ := fakeTools(t)
+	tc.ProbeTimeout = 5 * time.Second
+	inner := &testPublisher{}
+	pub := tc.ForClient(inner, []string{"audio/mpeg"})
+	require.Len(t, pub.Caps(), 1)
+
+	var _ media.AssetAdapter = pub
+	flac := &bytesAsset{contentType: "audio/flac", data: probed(flacStream)}
+	adapted, err := pub.AdaptAsset(flac)
+	require.NoError(t, err)
+	require.Equal(t, `audio/mpeg; codecs="mp3"`, adapted.ContentType())
+
+	// An already adapted asset is not adapted again
+	_, err = pub.PublishAsset(adapted, media.PublishOpts{})
+	require.NoError(t, err)
+	require.Same(t, adapted, inner.published[0])
+	require.Equal(t, 1, flac.opened)
+
+	// An asset that cannot be adapted is published as-is
+	video := &bytesAsset{contentType: "video/mp4", data: probed(h264Stream)}
+	_, err = pub.PublishAsset(video, media.PublishOpts{})
+	require.NoError(t, err)
+	require.Same(t, video, inner.published[1])
+
+	signed, err := pub.SignURL("http://localhost/asset", time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, "http://localhost/asset", signed)
+}
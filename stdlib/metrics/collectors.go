@@ -0,0 +1,107 @@
+package metrics
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/task"
+	"github.com/amp-3d/amp-sdk-go/stdlib/utils"
+)
+
+// NewTasksCollector returns a collector of a gauge with the given name, labeled by "state", counting the Contexts in the tree
+// rooted at root in each state (see task.StateName) each time it is gathered.
+func NewTasksCollector(name string, root task.Context) prometheus.Collector {
+	return &tasksCollector{
+		desc: prometheus.NewDesc(name, "Task contexts by state", []string{"state"}, nil),
+		root: root,
+	}
+}
+
+type tasksCollector struct {
+	desc *prometheus.Desc
+	root task.Context
+}
+
+func (c *tasksCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *tasksCollector) Collect(ch chan<- prometheus.Metric) {
+	counts := make(map[string]int)
+	var walk func(snap *task.Snapshot)
+	walk = func(snap *task.Snapshot) {
+		counts[snap.State]++
+		for i := range snap.Children {
+			walk(&snap.Children[i])
+		}
+	}
+	snap := task.SnapshotOf(c.root)
+	walk(&snap)
+	for state, n := range counts {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(n), state)
+	}
+}
+
+// Mailboxes is a collector of the stats of a changing set of named mailboxes (see utils.MailboxOf.Stats) -- concurrency safe.
+// Its metrics are named with a prefix and labeled by "mailbox":
+//
+//	{prefix}_depth            items currently queued
+//	{prefix}_high_water       max items queued at any one time
+//	{prefix}_delivered_total  items queued
+//	{prefix}_dropped_total    items discarded due to overflow or Clear()
+type Mailboxes struct {
+	depth     *prometheus.Desc
+	highWater *prometheus.Desc
+	delivered *prometheus.Desc
+	dropped   *prometheus.Desc
+
+	mu    sync.Mutex
+	stats map[string]func() utils.MailboxStats
+}
+
+// NewMailboxes returns a collector of the mailboxes subsequently watched via Mailboxes.Watch, whose metrics are named with
+// the given prefix.
+func NewMailboxes(prefix string) *Mailboxes {
+	labels := []string{"mailbox"}
+	return &Mailboxes{
+		depth:     prometheus.NewDesc(prefix+"_depth", "Items currently queued", labels, nil),
+		highWater: prometheus.NewDesc(prefix+"_high_water", "Max items queued at any one time", labels, nil),
+		delivered: prometheus.NewDesc(prefix+"_delivered_total", "Items queued", labels, nil),
+		dropped:   prometheus.NewDesc(prefix+"_dropped_total", "Items discarded due to overflow or clearing", labels, nil),
+		stats:     make(map[string]func() utils.MailboxStats),
+	}
+}
+
+// Watch reports the given mailbox's stats under the given name until the returned func is called.
+// stats is called (e.g. MailboxOf.Stats) each time the collector is gathered.
+func (mbs *Mailboxes) Watch(name string, stats func() utils.MailboxStats) (unwatch func()) {
+	mbs.mu.Lock()
+	mbs.stats[name] = stats
+	mbs.mu.Unlock()
+	return func() {
+		mbs.mu.Lock()
+		delete(mbs.stats, name)
+		mbs.mu.Unlock()
+	}
+}
+
+func (mbs *Mailboxes) Describe(ch chan<- *prometheus.Desc) {
+	ch <- mbs.depth
+	ch <- mbs.highWater
+	ch <- mbs.delivered
+	ch <- mbs.dropped
+}
+
+func (mbs *Mailboxes) Collect(ch chan<- prometheus.Metric) {
+	mbs.mu.Lock()
+	defer mbs.mu.Unlock()
+
+	for name, stats := range mbs.stats {
+		st := stats()
+		ch <- prometheus.MustNewConstMetric(mbs.depth, prometheus.GaugeValue, float64(st.Depth), name)
+		ch <- prometheus.MustNewConstMetric(mbs.highWater, prometheus.GaugeValue, float64(st.HighWater), name)
+		ch <- prometheus.MustNewConstMetric(mbs.delivered, prometheus.CounterValue, float64(st.Delivered), name)
+		ch <- prometheus.MustNewConstMetric(mbs.dropped, prometheus.CounterValue, float64(st.Dropped), name)
+	}
+}
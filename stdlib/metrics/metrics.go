@@ -0,0 +1,31 @@
+// Package metrics exports a host's internals as Prometheus metrics via github.com/prometheus/client_golang, so that they can
+// be scraped by Prometheus or any compatible collector.
+//
+// Metrics updated as events occur are client_golang's own (e.g. prometheus.NewGaugeVec), while this package offers collectors
+// of values already tracked elsewhere, read each time a registry is gathered (e.g. a mailbox's MailboxStats):
+//
+//	reg := prometheus.NewRegistry()
+//	mailboxes := metrics.NewMailboxes("amp_mailbox")
+//	reg.MustRegister(mailboxes)
+//	mailboxes.Watch("inbox", inbox.Stats)
+//	http.Handle("/metrics", promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
+package metrics
+
+import (
+	"io"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/expfmt"
+)
+
+// WriteText writes the metrics gathered from g in the Prometheus text exposition format, ordered by name and then labels.
+// If gathering fails for some metrics, the rest are still written and the error is returned.
+func WriteText(w io.Writer, g prometheus.Gatherer) error {
+	families, err := g.Gather()
+	for _, fam := range families {
+		if _, werr := expfmt.MetricFamilyToText(w, fam); werr != nil {
+			return werr
+		}
+	}
+	return err
+}
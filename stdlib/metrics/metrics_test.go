@@ -0,0 +1,94 @@
+package metrics_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/metrics"
+	"github.com/amp-3d/amp-sdk-go/stdlib/task"
+	"github.com/amp-3d/amp-sdk-go/stdlib/utils"
+)
+
+func TestWriteText(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	sent := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "sent_bytes_total", Help: "Bytes sent"}, []string{"dir"})
+	open := prometheus.NewGauge(prometheus.GaugeOpts{Name: "open", Help: "Open things\nacross lines"})
+	latency := prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: "latency_seconds", Help: "Latency", Buckets: []float64{0.1, 1}}, []string{"app"})
+	reg.MustRegister(sent, open, latency)
+
+	sent.WithLabelValues("out").Add(10)
+	sent.WithLabelValues(`in"quoted"`).Inc()
+	open.Set(3)
+	open.Add(-1)
+	latency.WithLabelValues("a").Observe(0.05)
+	latency.WithLabelValues("a").Observe(0.5)
+	latency.WithLabelValues("a").Observe(5)
+
+	var buf bytes.Buffer
+	if err := metrics.WriteText(&buf, reg); err != nil {
+		t.Fatal(err)
+	}
+	expected := `# HELP latency_seconds Latency
+# TYPE latency_seconds histogram
+latency_seconds_bucket{app="a",le="0.1"} 1
+latency_seconds_bucket{app="a",le="1"} 2
+latency_seconds_bucket{app="a",le="+Inf"} 3
+latency_seconds_sum{app="a"} 5.55
+latency_seconds_count{app="a"} 3
+# HELP open Open things\nacross lines
+# TYPE open gauge
+open 2
+# HELP sent_bytes_total Bytes sent
+# TYPE sent_bytes_total counter
+sent_bytes_total{dir="in\"quoted\""} 1
+sent_bytes_total{dir="out"} 10
+`
+	if buf.String() != expected {
+		t.Fatalf("unexpected exposition:\n%s", buf.String())
+	}
+}
+
+func TestCollectors(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	root, err := task.Start(&task.Task{Label: "root"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer root.Close()
+	for i := 0; i < 2; i++ {
+		if _, err = root.StartChild(&task.Task{Label: "child"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	reg.MustRegister(metrics.NewTasksCollector("tasks", root))
+
+	mb := utils.NewMailboxOf[int](1)
+	mb.Deliver(1)
+	mb.Deliver(2)
+	mbs := metrics.NewMailboxes("mailbox")
+	reg.MustRegister(mbs)
+	unwatch := mbs.Watch("inbox", mb.Stats)
+
+	var buf bytes.Buffer
+	metrics.WriteText(&buf, reg)
+	for _, line := range []string{
+		`tasks{state="running"} 3`,
+		`mailbox_depth{mailbox="inbox"} 1`,
+		`mailbox_delivered_total{mailbox="inbox"} 2`,
+		`mailbox_dropped_total{mailbox="inbox"} 1`,
+	} {
+		if !strings.Contains(buf.String(), line+"\n") {
+			t.Fatalf("missing %q in:\n%s", line, buf.String())
+		}
+	}
+
+	unwatch()
+	buf.Reset()
+	metrics.WriteText(&buf, reg)
+	if strings.Contains(buf.String(), "inbox") {
+		t.Fatalf("unwatched mailbox still reported:\n%s", buf.String())
+	}
+}
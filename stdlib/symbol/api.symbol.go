@@ -3,6 +3,8 @@ package symbol
 import (
 	"encoding/binary"
 	"errors"
+	"io"
+	"time"
 
 	"github.com/amp-3d/amp-sdk-go/stdlib/generics"
 )
@@ -37,7 +39,20 @@ type Issuer interface {
 	IssueNextID() (ID, error)
 }
 
-var ErrIssuerNotOpen = errors.New("issuer not open")
+// AdvancingIssuer is an Issuer that can be advanced so that it never issues an ID <= a given ID.
+type AdvancingIssuer interface {
+	Issuer
+
+	// Ensures subsequently issued IDs are > the given ID.
+	AdvancePast(ID ID)
+}
+
+var (
+	ErrIssuerNotOpen    = errors.New("issuer not open")
+	ErrStoreClosed      = errors.New("store closed")
+	ErrAccessNotTracked = errors.New("symbol access not tracked")
+	ErrBadNamespace     = errors.New("bad symbol namespace name")
+)
 
 // Table abstracts value-ID storage and two-way lookup.
 type Table interface {
@@ -63,6 +78,53 @@ type Table interface {
 	// Looks up and appends the byte string associated with the given symbol ID to the given buf.
 	// If ID is invalid or not found, nil is returned.
 	GetSymbol(ID ID, io []byte) []byte
+
+	// Writes a versioned snapshot of all entries to the given writer (see SnapshotWriter).
+	ExportTo(w io.Writer) error
+
+	// Reads a snapshot written by ExportTo() and merges its entries into this Table, as if via SetSymbolID().
+	// If this Table's Issuer is an AdvancingIssuer, it is advanced past all imported IDs.
+	// If an error is returned, entries preceding the error may have already been imported.
+	ImportFrom(r io.Reader) error
+
+	// Removes all entries (and their stored values) that have not been accessed for the given duration and returns a report of what was reclaimed.
+	// IDs below the table's issuer floor (i.e. hard-wired IDs) are never reclaimed and reclaimed IDs are not reissued.
+	//
+	// Returns ErrAccessNotTracked if this Table was not created with access tracking enabled.
+	Compact(unusedFor time.Duration) (CompactReport, error)
+
+	// Writes all pending (write-behind) entries to this Table's Store and commits it.
+	// Returns the first store error encountered since the previous Flush(), or nil if this Table has no Store.
+	Flush() error
+
+	// Returns the Table for the given namespace within this Table, creating it if needed.
+	// The returned Table must be closed when no longer needed and all namespaces are closed along with this Table.
+	//
+	// A namespace has its own values, IDs, and Issuer, so lookups only resolve within the namespace they are made on
+	// and a cross-namespace lookup is made by explicitly calling Namespace(name).GetSymbolID() etc.
+	// If this Table has a Store, the namespace's entries are persisted to the same Store with keys prefixed by the namespace name.
+	// Since each namespace is a Table, ExportTo(), ImportFrom(), and Compact() only apply to the namespace they are called on.
+	//
+	// Returns ErrBadNamespace if name is empty or contains a zero byte.
+	Namespace(name string) (Table, error)
+
+	// Returns the current stats of this Table (excluding any namespaces within it).
+	Stats() TableStats
+}
+
+// TableStats summarizes the contents of a Table.
+type TableStats struct {
+	Namespace  string // Full namespace path, with names separated by '/' ("" for a root Table)
+	NumValues  int    // Number of value-to-ID assignments
+	NumIDs     int    // Number of ID-to-value assignments
+	ValueBytes int64  // Total size of all values
+}
+
+// CompactReport summarizes the entries removed by Table.Compact().
+type CompactReport struct {
+	Reclaimed      []SnapshotEntry // Value-to-ID assignments that were removed
+	BytesReclaimed int64           // Total size of the values removed
+	Remaining      int             // Number of value-to-ID assignments remaining
 }
 
 // Reads a big endian encoded uint32 ID from the given byte slice
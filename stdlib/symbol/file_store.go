@@ -0,0 +1,248 @@
+package symbol
+
+import (
+	"bufio"
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+	"os"
+	"sync"
+)
+
+// OpenFileStore opens (or creates) a Store persisted as an append-only log file, with all entries indexed in memory.
+//
+// This requires no external dependencies so it is suitable for constrained platforms (e.g. iOS or WASM with a filesystem),
+// where neither Pebble nor SQLite (see pebble_store and sqlite_store) builds, and it is the default store of a Space.
+// Entries written since the last Commit() may be lost in a crash, and a torn trailing record is discarded when reopened.
+// The log is rewritten during Commit() once it contains mostly overwritten entries.
+func OpenFileStore(pathname string) (Store, error) {
+	file, err := os.OpenFile(pathname, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	fs := &fileStore{
+		pathname: pathname,
+		file:     file,
+		index:    memoryStore{entries: make(map[string][]byte)},
+	}
+	if err = fs.load(); err != nil {
+		file.Close()
+		return nil, err
+	}
+	fs.w = bufio.NewWriter(fs.file)
+	return fs, nil
+}
+
+// fileStore implements symbol.Store using an append-only log file.
+type fileStore struct {
+	pathname string
+	index    memoryStore
+
+	mu      sync.Mutex // protects the fields below
+	file    *os.File
+	w       *bufio.Writer
+	fileSz  int64
+	liveSz  int64 // approx bytes of file in use by live entries
+	scratch []byte
+}
+
+const (
+	fileStoreRecordOverhead = 4 + 4 + 4  // keyLen, valLen, crc32
+	fileStoreTombstone      = ^uint32(0) // valLen denoting a deleted key
+)
+
+func (fs *fileStore) load() error {
+	r := bufio.NewReader(fs.file)
+	var hdr [8]byte
+	for {
+		if _, err := io.ReadFull(r, hdr[:]); err != nil {
+			break
+		}
+		keyLen := binary.BigEndian.Uint32(hdr[0:4])
+		valLen := binary.BigEndian.Uint32(hdr[4:8])
+		tombstone := valLen == fileStoreTombstone
+		if tombstone {
+			valLen = 0
+		}
+		rec := make([]byte, keyLen+valLen+4)
+		if _, err := io.ReadFull(r, rec); err != nil {
+			break
+		}
+		body := rec[:keyLen+valLen]
+		crc := crc32.Update(crc32.ChecksumIEEE(hdr[:]), crc32.IEEETable, body)
+		if crc != binary.BigEndian.Uint32(rec[keyLen+valLen:]) {
+			break
+		}
+		if tombstone {
+			fs.unindexEntry(body)
+		} else {
+			fs.indexEntry(body[:keyLen], body[keyLen:])
+		}
+		fs.fileSz += int64(fileStoreRecordOverhead + len(body))
+	}
+
+	// Drop any torn or corrupt trailing data
+	if err := fs.file.Truncate(fs.fileSz); err != nil {
+		return err
+	}
+	_, err := fs.file.Seek(fs.fileSz, io.SeekStart)
+	return err
+}
+
+// indexEntry updates the in-memory index and live size -- fs.mu must be locked (or the store still loading).
+func (fs *fileStore) indexEntry(key, value []byte) {
+	if prev, found := fs.index.entries[string(key)]; found {
+		fs.liveSz -= int64(fileStoreRecordOverhead + len(key) + len(prev))
+	}
+	fs.index.entries[string(key)] = value
+	fs.liveSz += int64(fileStoreRecordOverhead + len(key) + len(value))
+}
+
+// unindexEntry removes the given key from the in-memory index -- fs.mu must be locked (or the store still loading).
+func (fs *fileStore) unindexEntry(key []byte) {
+	if prev, found := fs.index.entries[string(key)]; found {
+		fs.liveSz -= int64(fileStoreRecordOverhead + len(key) + len(prev))
+		delete(fs.index.entries, string(key))
+	}
+}
+
+func appendFileStoreRecord(dst, key, value []byte) []byte {
+	return appendFileStoreRecordWithLen(dst, key, value, uint32(len(value)))
+}
+
+func appendFileStoreRecordWithLen(dst, key, value []byte, valLen uint32) []byte {
+	start := len(dst)
+	dst = binary.BigEndian.AppendUint32(dst, uint32(len(key)))
+	dst = binary.BigEndian.AppendUint32(dst, valLen)
+	dst = append(dst, key...)
+	dst = append(dst, value...)
+	return binary.BigEndian.AppendUint32(dst, crc32.ChecksumIEEE(dst[start:]))
+}
+
+func (fs *fileStore) Get(key []byte) ([]byte, error) {
+	return fs.index.Get(key)
+}
+
+func (fs *fileStore) Iterate(prefix []byte, fn func(key, value []byte) error) error {
+	return fs.index.Iterate(prefix, fn)
+}
+
+func (fs *fileStore) Set(key, value []byte) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	if fs.file == nil {
+		return ErrStoreClosed
+	}
+
+	fs.scratch = appendFileStoreRecord(fs.scratch[:0], key, value)
+	n, err := fs.w.Write(fs.scratch)
+	fs.fileSz += int64(n)
+	if err != nil {
+		return err
+	}
+
+	fs.index.mu.Lock()
+	fs.indexEntry(key, append([]byte{}, value...))
+	fs.index.mu.Unlock()
+	return nil
+}
+
+func (fs *fileStore) Delete(key []byte) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	if fs.file == nil {
+		return ErrStoreClosed
+	}
+
+	fs.scratch = appendFileStoreRecordWithLen(fs.scratch[:0], key, nil, fileStoreTombstone)
+	n, err := fs.w.Write(fs.scratch)
+	fs.fileSz += int64(n)
+	if err != nil {
+		return err
+	}
+
+	fs.index.mu.Lock()
+	fs.unindexEntry(key)
+	fs.index.mu.Unlock()
+	return nil
+}
+
+func (fs *fileStore) Commit() error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	return fs.commit()
+}
+
+func (fs *fileStore) commit() error {
+	if fs.file == nil {
+		return ErrStoreClosed
+	}
+	if err := fs.w.Flush(); err != nil {
+		return err
+	}
+	if fs.fileSz > 2*fs.liveSz+(1<<20) {
+		return fs.rewrite()
+	}
+	return fs.file.Sync()
+}
+
+// rewrite replaces the log with one containing only live entries -- fs.mu must be locked.
+func (fs *fileStore) rewrite() error {
+	tmpPathname := fs.pathname + ".rewrite"
+	tmp, err := os.OpenFile(tmpPathname, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(tmp)
+	sz := int64(0)
+	fs.index.mu.RLock()
+	for key, val := range fs.index.entries {
+		fs.scratch = appendFileStoreRecord(fs.scratch[:0], []byte(key), val)
+		if _, err = w.Write(fs.scratch); err != nil {
+			break
+		}
+		sz += int64(len(fs.scratch))
+	}
+	fs.index.mu.RUnlock()
+
+	if err == nil {
+		err = w.Flush()
+	}
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if err == nil {
+		err = os.Rename(tmpPathname, fs.pathname)
+	}
+	if err != nil {
+		tmp.Close()
+		os.Remove(tmpPathname)
+		return err
+	}
+
+	fs.file.Close()
+	fs.file = tmp
+	fs.w = bufio.NewWriter(tmp)
+	fs.fileSz = sz
+	fs.liveSz = sz
+	return nil
+}
+
+func (fs *fileStore) Close() error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	if fs.file == nil {
+		return nil
+	}
+	err := fs.commit()
+	if closeErr := fs.file.Close(); err == nil {
+		err = closeErr
+	}
+	fs.file = nil
+	return err
+}
@@ -0,0 +1,72 @@
+// Package grpc_coordinator serves a symbol.RangeCoordinator over gRPC (see coordinator.proto), so that nodes running a
+// symbol.NewLeasingIssuer() can lease ID ranges from a single coordinator process.
+//
+// It is a separate module so that hosts not using it do not depend on gRPC.  "make generate" regenerates its .pb.go files.
+package grpc_coordinator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/symbol"
+)
+
+// Register registers the given RangeCoordinator (e.g. symbol.NewLocalCoordinator()) with the given gRPC server.
+func Register(s grpc.ServiceRegistrar, coord symbol.RangeCoordinator) {
+	RegisterRangeCoordinatorServer(s, &server{
+		coord: coord,
+	})
+}
+
+// server implements RangeCoordinatorServer using a symbol.RangeCoordinator.
+type server struct {
+	UnimplementedRangeCoordinatorServer
+	coord symbol.RangeCoordinator
+}
+
+func (srv *server) LeaseRange(ctx context.Context, req *LeaseRangeRequest) (*LeaseRangeResponse, error) {
+	lease, err := srv.coord.LeaseRange(ctx, req.NodeID, req.Size)
+	if errors.Is(err, symbol.ErrRangeExhausted) {
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
+	}
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	return &LeaseRangeResponse{
+		First: uint64(lease.First),
+		Last:  uint64(lease.Last),
+	}, nil
+}
+
+// NewClient returns a symbol.RangeCoordinator that leases ranges from a coordinator registered via Register().
+func NewClient(conn grpc.ClientConnInterface) symbol.RangeCoordinator {
+	return &client{
+		client: NewRangeCoordinatorClient(conn),
+	}
+}
+
+type client struct {
+	client RangeCoordinatorClient
+}
+
+func (c *client) LeaseRange(ctx context.Context, nodeID string, size uint32) (symbol.IDRange, error) {
+	resp, err := c.client.LeaseRange(ctx, &LeaseRangeRequest{
+		NodeID: nodeID,
+		Size:   size,
+	})
+	if status.Code(err) == codes.ResourceExhausted {
+		return symbol.IDRange{}, fmt.Errorf("%w: %v", symbol.ErrRangeExhausted, err)
+	}
+	if err != nil {
+		return symbol.IDRange{}, err
+	}
+	return symbol.IDRange{
+		First: symbol.ID(resp.First),
+		Last:  symbol.ID(resp.Last),
+	}, nil
+}
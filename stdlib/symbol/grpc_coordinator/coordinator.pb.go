@@ -0,0 +1,197 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: stdlib/symbol/grpc_coordinator/coordinator.proto
+
+// package symbol.coordinator serves a symbol.RangeCoordinator over gRPC, leasing disjoint symbol ID ranges to nodes.
+
+package grpc_coordinator
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type LeaseRangeRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Identifies the node leasing the range.
+	NodeID string `protobuf:"bytes,1,opt,name=NodeID,proto3" json:"NodeID,omitempty"`
+	// Number of IDs to lease.
+	Size          uint32 `protobuf:"varint,2,opt,name=Size,proto3" json:"Size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LeaseRangeRequest) Reset() {
+	*x = LeaseRangeRequest{}
+	mi := &file_stdlib_symbol_grpc_coordinator_coordinator_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LeaseRangeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LeaseRangeRequest) ProtoMessage() {}
+
+func (x *LeaseRangeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_stdlib_symbol_grpc_coordinator_coordinator_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LeaseRangeRequest.ProtoReflect.Descriptor instead.
+func (*LeaseRangeRequest) Descriptor() ([]byte, []int) {
+	return file_stdlib_symbol_grpc_coordinator_coordinator_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *LeaseRangeRequest) GetNodeID() string {
+	if x != nil {
+		return x.NodeID
+	}
+	return ""
+}
+
+func (x *LeaseRangeRequest) GetSize() uint32 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+// LeaseRangeResponse is the inclusive range of IDs leased.
+type LeaseRangeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	First         uint64                 `protobuf:"varint,1,opt,name=First,proto3" json:"First,omitempty"`
+	Last          uint64                 `protobuf:"varint,2,opt,name=Last,proto3" json:"Last,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LeaseRangeResponse) Reset() {
+	*x = LeaseRangeResponse{}
+	mi := &file_stdlib_symbol_grpc_coordinator_coordinator_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LeaseRangeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LeaseRangeResponse) ProtoMessage() {}
+
+func (x *LeaseRangeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_stdlib_symbol_grpc_coordinator_coordinator_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LeaseRangeResponse.ProtoReflect.Descriptor instead.
+func (*LeaseRangeResponse) Descriptor() ([]byte, []int) {
+	return file_stdlib_symbol_grpc_coordinator_coordinator_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *LeaseRangeResponse) GetFirst() uint64 {
+	if x != nil {
+		return x.First
+	}
+	return 0
+}
+
+func (x *LeaseRangeResponse) GetLast() uint64 {
+	if x != nil {
+		return x.Last
+	}
+	return 0
+}
+
+var File_stdlib_symbol_grpc_coordinator_coordinator_proto protoreflect.FileDescriptor
+
+const file_stdlib_symbol_grpc_coordinator_coordinator_proto_rawDesc = "" +
+	"\n" +
+	"0stdlib/symbol/grpc_coordinator/coordinator.proto\x12\x12symbol.coordinator\"?\n" +
+	"\x11LeaseRangeRequest\x12\x16\n" +
+	"\x06NodeID\x18\x01 \x01(\tR\x06NodeID\x12\x12\n" +
+	"\x04Size\x18\x02 \x01(\rR\x04Size\">\n" +
+	"\x12LeaseRangeResponse\x12\x14\n" +
+	"\x05First\x18\x01 \x01(\x04R\x05First\x12\x12\n" +
+	"\x04Last\x18\x02 \x01(\x04R\x04Last2o\n" +
+	"\x10RangeCoordinator\x12[\n" +
+	"\n" +
+	"LeaseRange\x12%.symbol.coordinator.LeaseRangeRequest\x1a&.symbol.coordinator.LeaseRangeResponseB=Z;github.com/amp-3d/amp-sdk-go/stdlib/symbol/grpc_coordinatorb\x06proto3"
+
+var (
+	file_stdlib_symbol_grpc_coordinator_coordinator_proto_rawDescOnce sync.Once
+	file_stdlib_symbol_grpc_coordinator_coordinator_proto_rawDescData []byte
+)
+
+func file_stdlib_symbol_grpc_coordinator_coordinator_proto_rawDescGZIP() []byte {
+	file_stdlib_symbol_grpc_coordinator_coordinator_proto_rawDescOnce.Do(func() {
+		file_stdlib_symbol_grpc_coordinator_coordinator_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_stdlib_symbol_grpc_coordinator_coordinator_proto_rawDesc), len(file_stdlib_symbol_grpc_coordinator_coordinator_proto_rawDesc)))
+	})
+	return file_stdlib_symbol_grpc_coordinator_coordinator_proto_rawDescData
+}
+
+var file_stdlib_symbol_grpc_coordinator_coordinator_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_stdlib_symbol_grpc_coordinator_coordinator_proto_goTypes = []any{
+	(*LeaseRangeRequest)(nil),  // 0: symbol.coordinator.LeaseRangeRequest
+	(*LeaseRangeResponse)(nil), // 1: symbol.coordinator.LeaseRangeResponse
+}
+var file_stdlib_symbol_grpc_coordinator_coordinator_proto_depIdxs = []int32{
+	0, // 0: symbol.coordinator.RangeCoordinator.LeaseRange:input_type -> symbol.coordinator.LeaseRangeRequest
+	1, // 1: symbol.coordinator.RangeCoordinator.LeaseRange:output_type -> symbol.coordinator.LeaseRangeResponse
+	1, // [1:2] is the sub-list for method output_type
+	0, // [0:1] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_stdlib_symbol_grpc_coordinator_coordinator_proto_init() }
+func file_stdlib_symbol_grpc_coordinator_coordinator_proto_init() {
+	if File_stdlib_symbol_grpc_coordinator_coordinator_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_stdlib_symbol_grpc_coordinator_coordinator_proto_rawDesc), len(file_stdlib_symbol_grpc_coordinator_coordinator_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_stdlib_symbol_grpc_coordinator_coordinator_proto_goTypes,
+		DependencyIndexes: file_stdlib_symbol_grpc_coordinator_coordinator_proto_depIdxs,
+		MessageInfos:      file_stdlib_symbol_grpc_coordinator_coordinator_proto_msgTypes,
+	}.Build()
+	File_stdlib_symbol_grpc_coordinator_coordinator_proto = out.File
+	file_stdlib_symbol_grpc_coordinator_coordinator_proto_goTypes = nil
+	file_stdlib_symbol_grpc_coordinator_coordinator_proto_depIdxs = nil
+}
@@ -0,0 +1,131 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.2
+// - protoc             (unknown)
+// source: stdlib/symbol/grpc_coordinator/coordinator.proto
+
+// package symbol.coordinator serves a symbol.RangeCoordinator over gRPC, leasing disjoint symbol ID ranges to nodes.
+
+package grpc_coordinator
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	RangeCoordinator_LeaseRange_FullMethodName = "/symbol.coordinator.RangeCoordinator/LeaseRange"
+)
+
+// RangeCoordinatorClient is the client API for RangeCoordinator service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// RangeCoordinator leases disjoint ranges of symbol IDs to nodes.
+type RangeCoordinatorClient interface {
+	// LeaseRange leases the next range of (at least) the given size to the given node.
+	// Fails with RESOURCE_EXHAUSTED once the ID space is exhausted.
+	LeaseRange(ctx context.Context, in *LeaseRangeRequest, opts ...grpc.CallOption) (*LeaseRangeResponse, error)
+}
+
+type rangeCoordinatorClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewRangeCoordinatorClient(cc grpc.ClientConnInterface) RangeCoordinatorClient {
+	return &rangeCoordinatorClient{cc}
+}
+
+func (c *rangeCoordinatorClient) LeaseRange(ctx context.Context, in *LeaseRangeRequest, opts ...grpc.CallOption) (*LeaseRangeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LeaseRangeResponse)
+	err := c.cc.Invoke(ctx, RangeCoordinator_LeaseRange_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RangeCoordinatorServer is the server API for RangeCoordinator service.
+// All implementations must embed UnimplementedRangeCoordinatorServer
+// for forward compatibility.
+//
+// RangeCoordinator leases disjoint ranges of symbol IDs to nodes.
+type RangeCoordinatorServer interface {
+	// LeaseRange leases the next range of (at least) the given size to the given node.
+	// Fails with RESOURCE_EXHAUSTED once the ID space is exhausted.
+	LeaseRange(context.Context, *LeaseRangeRequest) (*LeaseRangeResponse, error)
+	mustEmbedUnimplementedRangeCoordinatorServer()
+}
+
+// UnimplementedRangeCoordinatorServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedRangeCoordinatorServer struct{}
+
+func (UnimplementedRangeCoordinatorServer) LeaseRange(context.Context, *LeaseRangeRequest) (*LeaseRangeResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method LeaseRange not implemented")
+}
+func (UnimplementedRangeCoordinatorServer) mustEmbedUnimplementedRangeCoordinatorServer() {}
+func (UnimplementedRangeCoordinatorServer) testEmbeddedByValue()                          {}
+
+// UnsafeRangeCoordinatorServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to RangeCoordinatorServer will
+// result in compilation errors.
+type UnsafeRangeCoordinatorServer interface {
+	mustEmbedUnimplementedRangeCoordinatorServer()
+}
+
+func RegisterRangeCoordinatorServer(s grpc.ServiceRegistrar, srv RangeCoordinatorServer) {
+	// If the following call panics, it indicates UnimplementedRangeCoordinatorServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&RangeCoordinator_ServiceDesc, srv)
+}
+
+func _RangeCoordinator_LeaseRange_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LeaseRangeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RangeCoordinatorServer).LeaseRange(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RangeCoordinator_LeaseRange_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RangeCoordinatorServer).LeaseRange(ctx, req.(*LeaseRangeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// RangeCoordinator_ServiceDesc is the grpc.ServiceDesc for RangeCoordinator service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var RangeCoordinator_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "symbol.coordinator.RangeCoordinator",
+	HandlerType: (*RangeCoordinatorServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "LeaseRange",
+			Handler:    _RangeCoordinator_LeaseRange_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "stdlib/symbol/grpc_coordinator/coordinator.proto",
+}
@@ -0,0 +1,80 @@
+package grpc_coordinator_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/symbol"
+	"github.com/amp-3d/amp-sdk-go/stdlib/symbol/grpc_coordinator"
+	"github.com/amp-3d/amp-sdk-go/stdlib/symbol/memory_table"
+)
+
+// dialCoordinator serves the given coordinator over an in-memory gRPC connection, returning a client for it.
+func dialCoordinator(t *testing.T, coord symbol.RangeCoordinator) symbol.RangeCoordinator {
+	lis := bufconn.Listen(1 << 16)
+	srv := grpc.NewServer()
+	grpc_coordinator.Register(srv, coord)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return grpc_coordinator.NewClient(conn)
+}
+
+func TestLeasingIssuer(t *testing.T) {
+	coord := dialCoordinator(t, symbol.NewLocalCoordinator(1000))
+
+	nodes := make([]symbol.Table, 2)
+	for i := range nodes {
+		opts := memory_table.DefaultOpts()
+		opts.Issuer = symbol.NewLeasingIssuer(symbol.LeasingIssuerOpts{
+			NodeID:      strconv.Itoa(i),
+			LeaseSize:   16,
+			Coordinator: coord,
+		})
+		var err error
+		nodes[i], err = opts.CreateTable()
+		require.NoError(t, err)
+		opts.Issuer.Close()
+		defer nodes[i].Close()
+	}
+
+	// Interleave issuing so each node leases several ranges over gRPC
+	issued := make(map[symbol.ID]string)
+	for i := 0; i < 100; i++ {
+		for n, table := range nodes {
+			val := strconv.Itoa(n) + "-" + strconv.Itoa(i)
+			symID := table.GetSymbolID([]byte(val), true)
+			require.Greater(t, symID, symbol.ID(1000))
+			prev, collides := issued[symID]
+			require.False(t, collides, "ID %d issued for both %q and %q", symID, prev, val)
+			issued[symID] = val
+		}
+	}
+}
+
+func TestRangeExhausted(t *testing.T) {
+	coord := dialCoordinator(t, symbol.NewLocalCoordinator(^symbol.ID(0)-10))
+
+	lease, err := coord.LeaseRange(context.Background(), "node", 10)
+	require.NoError(t, err)
+	require.Equal(t, symbol.IDRange{First: ^symbol.ID(0) - 9, Last: ^symbol.ID(0)}, lease)
+
+	_, err = coord.LeaseRange(context.Background(), "node", 10)
+	require.True(t, errors.Is(err, symbol.ErrRangeExhausted), "expected ErrRangeExhausted, got %v", err)
+}
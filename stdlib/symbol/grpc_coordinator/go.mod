@@ -0,0 +1,20 @@
+module github.com/amp-3d/amp-sdk-go/stdlib/symbol/grpc_coordinator
+
+go 1.23
+
+require (
+	github.com/amp-3d/amp-sdk-go v0.0.0-00010101000000-000000000000
+	github.com/stretchr/testify v1.12.1
+	google.golang.org/grpc v1.72.0
+	google.golang.org/protobuf v1.36.11
+)
+
+require (
+	go.yaml.in/yaml/v3 v3.0.5 // indirect
+	golang.org/x/net v0.35.0 // indirect
+	golang.org/x/sys v0.30.0 // indirect
+	golang.org/x/text v0.22.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20250218202821-56aae31c358a // indirect
+)
+
+replace github.com/amp-3d/amp-sdk-go => ../../..
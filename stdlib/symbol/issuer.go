@@ -25,6 +25,15 @@ func (iss *atomicIssuer) IssueNextID() (ID, error) {
 	return ID(nextID), nil
 }
 
+func (iss *atomicIssuer) AdvancePast(ID ID) {
+	for {
+		nextID := iss.nextID.Load()
+		if nextID >= uint32(ID) || iss.nextID.CompareAndSwap(nextID, uint32(ID)) {
+			return
+		}
+	}
+}
+
 func (iss *atomicIssuer) AddRef() {
 	if iss.refCount.Add(1) <= 1 {
 		panic("AddRef() called on closed issuer")
@@ -0,0 +1,128 @@
+package symbol
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// IDRange is an inclusive range of symbol IDs leased to a single node.
+type IDRange struct {
+	First ID
+	Last  ID
+}
+
+// RangeCoordinator leases disjoint ID ranges to nodes so that tables on different hosts never issue colliding IDs
+// and can therefore be merged later (see Table.ImportFrom).  Package grpc_coordinator serves one to remote nodes.
+type RangeCoordinator interface {
+
+	// Leases the next range of (at least) the given size to the given node.
+	// A coordinator must never lease an ID more than once, even across restarts.
+	LeaseRange(ctx context.Context, nodeID string, size uint32) (IDRange, error)
+}
+
+var ErrRangeExhausted = errors.New("symbol ID range exhausted")
+
+// NewLocalCoordinator returns an in-process RangeCoordinator that leases ranges starting after the given ID.
+// It is suitable for a coordinator process (see grpc_coordinator.Register) that persists its floor elsewhere, or for tests.
+func NewLocalCoordinator(startAfter ID) RangeCoordinator {
+	coord := &localCoordinator{}
+	coord.next.Store(uint64(startAfter) + 1)
+	return coord
+}
+
+type localCoordinator struct {
+	next atomic.Uint64
+}
+
+func (coord *localCoordinator) LeaseRange(ctx context.Context, nodeID string, size uint32) (IDRange, error) {
+	if size == 0 {
+		size = 1
+	}
+	first := coord.next.Add(uint64(size)) - uint64(size)
+	last := first + uint64(size) - 1
+	if last > uint64(^ID(0)) {
+		return IDRange{}, ErrRangeExhausted
+	}
+	return IDRange{First: ID(first), Last: ID(last)}, nil
+}
+
+// LeasingIssuerOpts are the options for NewLeasingIssuer().
+type LeasingIssuerOpts struct {
+	NodeID       string           // Identifies this node to the coordinator
+	LeaseSize    uint32           // Number of IDs to lease at a time (default: 1000)
+	LeaseTimeout time.Duration    // Timeout for each lease request (default: 10s)
+	Coordinator  RangeCoordinator // Where ranges are leased from
+}
+
+// NewLeasingIssuer returns an Issuer that issues IDs from ranges leased from a RangeCoordinator.
+//
+// A new range is leased once the current range is exhausted, so IDs issued by one node are sequential but not contiguous.
+// IDs remaining in the current range when the issuer is closed are never issued.
+func NewLeasingIssuer(opts LeasingIssuerOpts) Issuer {
+	if opts.LeaseSize == 0 {
+		opts.LeaseSize = 1000
+	}
+	if opts.LeaseTimeout <= 0 {
+		opts.LeaseTimeout = 10 * time.Second
+	}
+	iss := &leasingIssuer{
+		opts: opts,
+	}
+	iss.refCount.Store(1)
+	return iss
+}
+
+// leasingIssuer implements symbol.Issuer using leased ID ranges
+type leasingIssuer struct {
+	opts     LeasingIssuerOpts
+	refCount atomic.Int32
+	mu       sync.Mutex
+	nextID   ID
+	lease    IDRange
+	leased   bool
+}
+
+func (iss *leasingIssuer) IssueNextID() (ID, error) {
+	if iss.refCount.Load() <= 0 {
+		return 0, ErrIssuerNotOpen
+	}
+
+	iss.mu.Lock()
+	defer iss.mu.Unlock()
+
+	if !iss.leased || iss.nextID > iss.lease.Last || iss.nextID == 0 {
+		ctx, cancel := context.WithTimeout(context.Background(), iss.opts.LeaseTimeout)
+		lease, err := iss.opts.Coordinator.LeaseRange(ctx, iss.opts.NodeID, iss.opts.LeaseSize)
+		cancel()
+		if err != nil {
+			return 0, fmt.Errorf("failed to lease symbol IDs: %w", err)
+		}
+		if lease.First == 0 || lease.Last < lease.First {
+			return 0, fmt.Errorf("coordinator leased invalid range %v", lease)
+		}
+		iss.lease = lease
+		iss.nextID = lease.First
+		iss.leased = true
+	}
+
+	symID := iss.nextID
+	iss.nextID++ // wraps to 0 at the end of the ID space, forcing a new lease
+	return symID, nil
+}
+
+func (iss *leasingIssuer) AddRef() {
+	if iss.refCount.Add(1) <= 1 {
+		panic("AddRef() called on closed issuer")
+	}
+}
+
+func (iss *leasingIssuer) Close() error {
+	if iss.refCount.Add(-1) < 0 {
+		return ErrIssuerNotOpen
+	}
+	return nil
+}
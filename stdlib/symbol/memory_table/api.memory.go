@@ -1,6 +1,10 @@
 package memory_table
 
-import "github.com/amp-3d/amp-sdk-go/stdlib/symbol"
+import (
+	"time"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/symbol"
+)
 
 // CreateTable creates a new memory-based symbol.Table intended to handle extreme loading.
 //
@@ -10,10 +14,27 @@ func (opts TableOpts) CreateTable() (symbol.Table, error) {
 }
 
 type TableOpts struct {
-	symbol.Issuer             // How this table will issue new IDs.  If nil, this table's db will be used as the Issuer
-	IssuerInitsAt   symbol.ID // The floor ID to start issuing from if initializing a new Issuer.
-	WorkingSizeHint int       // anticipated number of entries in working set
-	PoolSz          int32     // Value backing buffer allocation pool sz
+	symbol.Issuer                // How this table will issue new IDs.  If nil, this table's db will be used as the Issuer
+	IssuerInitsAt   symbol.ID    // The floor ID to start issuing from if initializing a new Issuer.
+	WorkingSizeHint int          // anticipated number of entries in working set
+	PoolSz          int32        // Value backing buffer allocation pool sz
+	Store           symbol.Store // If set, entries are loaded from and persisted to this Store (which the table then owns and closes)
+	TrackAccess     bool         // If set, the last access time of each ID is tracked, enabling Table.Compact()
+
+	// If set, values are normalized (e.g. symbol.FoldNFC) before value-to-ID lookups and assignments,
+	// while GetSymbol() returns each value as it was originally given when its ID was issued or set.
+	// Since normalized forms are persisted, a table must always be reopened with the same Normalize func.
+	Normalize symbol.NormalizeFunc
+
+	// If > 0, writes to Store are batched in memory and flushed (and committed) once this many are pending,
+	// once WriteBehindDelay elapses, or when Table.Flush() is called.
+	//
+	// In this mode, new assignments are visible in the table immediately but are lost in a crash until flushed,
+	// so a crash can lose up to WriteBehindDelay worth of issuances.  If a table is to be reopened after a crash
+	// with a non-persistent Issuer, IssuerInitsAt should be set high enough to skip IDs possibly issued but lost.
+	// Batches are applied in order, so the Store always reflects some prefix of the table's writes.
+	WriteBehindSize  int
+	WriteBehindDelay time.Duration // Max time a write remains pending (default: 100ms)
 }
 
 // DefaultOpts is a suggested set of options.
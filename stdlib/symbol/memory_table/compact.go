@@ -0,0 +1,141 @@
+package memory_table
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/bufs"
+	"github.com/amp-3d/amp-sdk-go/stdlib/symbol"
+)
+
+// accessTracker records the last access time (unix seconds) of each symbol ID.
+type accessTracker struct {
+	mu         sync.RWMutex
+	lastAccess map[symbol.ID]*atomic.Int64
+}
+
+func newAccessTracker(sizeHint int) *accessTracker {
+	return &accessTracker{
+		lastAccess: make(map[symbol.ID]*atomic.Int64, sizeHint),
+	}
+}
+
+// touch marks the given ID as accessed now (no-op if access tracking is off).
+func (st *symbolTable) touch(symID symbol.ID) {
+	at := st.access
+	if at == nil || symID == 0 {
+		return
+	}
+	now := time.Now().Unix()
+
+	at.mu.RLock()
+	last := at.lastAccess[symID]
+	at.mu.RUnlock()
+
+	if last == nil {
+		at.mu.Lock()
+		if last = at.lastAccess[symID]; last == nil {
+			last = &atomic.Int64{}
+			at.lastAccess[symID] = last
+		}
+		at.mu.Unlock()
+	}
+	last.Store(now)
+}
+
+func (st *symbolTable) Compact(unusedFor time.Duration) (report symbol.CompactReport, err error) {
+	at := st.access
+	if at == nil {
+		return report, symbol.ErrAccessNotTracked
+	}
+	cutoff := time.Now().Add(-unusedFor).Unix()
+
+	st.valueCacheMu.Lock()
+	defer st.valueCacheMu.Unlock()
+	st.tokenCacheMu.Lock()
+	defer st.tokenCacheMu.Unlock()
+	at.mu.Lock()
+	defer at.mu.Unlock()
+
+	reclaim := func(symID symbol.ID) bool {
+		if symID < st.opts.IssuerInitsAt {
+			return false
+		}
+		last := at.lastAccess[symID]
+		return last == nil || last.Load() < cutoff
+	}
+
+	// Rebuild all entries into fresh pools, leaving behind the reclaimed entries
+	oldPools := st.bufPools
+	oldValues := st.valueCache
+	oldTokens := st.tokenCache
+	oldBuf := func(kv kvEntry) []byte {
+		return oldPools[kv.poolIdx][kv.poolOfs : kv.poolOfs+kv.len]
+	}
+
+	st.curBufPool = nil
+	st.curBufPoolSz = 0
+	st.curBufPoolIdx = -1
+	st.bufPools = nil
+	st.valueCache = make(map[uint64]kvEntry, len(oldValues))
+	st.tokenCache = make(map[symbol.ID]kvEntry, len(oldTokens))
+
+	var keyBuf []byte
+	hasStore := st.opts.Store != nil
+
+	for _, kv := range oldValues {
+		buf := oldBuf(kv)
+		if reclaim(kv.symID) {
+			report.Reclaimed = append(report.Reclaimed, symbol.SnapshotEntry{
+				Flags: symbol.SnapshotFlag_ValueToID,
+				ID:    kv.symID,
+				Value: append([]byte{}, buf...),
+			})
+			report.BytesReclaimed += int64(len(buf))
+			if hasStore {
+				keyBuf = append(append(keyBuf[:0], symbol.StoreKeyValue), buf...)
+				st.storeDelete(keyBuf)
+			}
+			continue
+		}
+
+		hash := bufs.HashBuf(buf)
+		for _, taken := st.valueCache[hash]; taken; _, taken = st.valueCache[hash] {
+			hash++
+		}
+		newKV := st.copyToPool(buf)
+		newKV.symID = kv.symID
+		st.valueCache[hash] = newKV
+	}
+
+	for symID, kv := range oldTokens {
+		if reclaim(symID) {
+			delete(at.lastAccess, symID)
+			if hasStore {
+				keyBuf = symID.AppendTo(append(keyBuf[:0], symbol.StoreKeyID))
+				st.storeDelete(keyBuf)
+			}
+			continue
+		}
+
+		buf := oldBuf(kv)
+		newKV, found := st.lookupEntry(buf)
+		if !found {
+			newKV = st.copyToPool(buf)
+		}
+		newKV.symID = kv.symID
+		st.tokenCache[symID] = newKV
+	}
+
+	// Any remaining access records refer to IDs no longer present
+	for symID := range at.lastAccess {
+		if _, exists := st.tokenCache[symID]; !exists && reclaim(symID) {
+			delete(at.lastAccess, symID)
+		}
+	}
+
+	err = st.Flush()
+	report.Remaining = len(st.valueCache)
+	return report, err
+}
@@ -0,0 +1,80 @@
+package memory_table
+
+import (
+	"strings"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/symbol"
+)
+
+func (st *symbolTable) Namespace(name string) (symbol.Table, error) {
+	if name == "" || strings.IndexByte(name, 0) >= 0 {
+		return nil, symbol.ErrBadNamespace
+	}
+
+	st.nsMu.Lock()
+	defer st.nsMu.Unlock()
+
+	if st.refCount.Load() <= 0 {
+		return nil, symbol.ErrIssuerNotOpen
+	}
+
+	ns := st.namespaces[name]
+	if ns == nil {
+		opts := st.opts
+		opts.Issuer = nil // each namespace has its own ID space
+		if opts.Store != nil {
+			prefix := append([]byte{symbol.StoreKeyNamespace}, name...)
+			opts.Store = symbol.NewPrefixStore(opts.Store, append(prefix, 0))
+		}
+		table, err := createTable(opts)
+		if err != nil {
+			return nil, err
+		}
+		ns = table.(*symbolTable)
+		ns.nsPath = name
+		if st.nsPath != "" {
+			ns.nsPath = st.nsPath + "/" + name
+		}
+		if st.namespaces == nil {
+			st.namespaces = make(map[string]*symbolTable)
+		}
+		st.namespaces[name] = ns
+	}
+
+	ns.AddRef() // ref for the caller -- this table retains the original ref
+	return ns, nil
+}
+
+// closeNamespaces releases the ref held on each namespace created by this table.
+func (st *symbolTable) closeNamespaces() (err error) {
+	st.nsMu.Lock()
+	namespaces := st.namespaces
+	st.namespaces = nil
+	st.nsMu.Unlock()
+
+	for _, ns := range namespaces {
+		if nsErr := ns.Close(); err == nil {
+			err = nsErr
+		}
+	}
+	return err
+}
+
+func (st *symbolTable) Stats() symbol.TableStats {
+	stats := symbol.TableStats{
+		Namespace: st.nsPath,
+	}
+
+	st.valueCacheMu.RLock()
+	stats.NumValues = len(st.valueCache)
+	for _, kv := range st.valueCache {
+		stats.ValueBytes += int64(kv.len)
+	}
+	st.valueCacheMu.RUnlock()
+
+	st.tokenCacheMu.RLock()
+	stats.NumIDs = len(st.tokenCache)
+	st.tokenCacheMu.RUnlock()
+
+	return stats
+}
@@ -0,0 +1,87 @@
+package memory_table
+
+import (
+	"io"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/symbol"
+)
+
+func (st *symbolTable) ExportTo(w io.Writer) error {
+	sw, err := symbol.NewSnapshotWriter(w)
+	if err != nil {
+		return err
+	}
+
+	// Snapshot the entries so that writing to w occurs without holding locks
+	st.valueCacheMu.RLock()
+	st.tokenCacheMu.RLock()
+	entries := make([]symbol.SnapshotEntry, 0, len(st.valueCache)+len(st.tokenCache))
+	for _, kv := range st.valueCache {
+		flags := symbol.SnapshotFlag_ValueToID
+		if st.tokenCache[kv.symID] == kv {
+			flags |= symbol.SnapshotFlag_IDToValue
+		}
+		entries = append(entries, symbol.SnapshotEntry{
+			Flags: flags,
+			ID:    kv.symID,
+			Value: st.bufForEntry(kv),
+		})
+	}
+	for symID, kv := range st.tokenCache {
+		if current, _ := st.lookupEntry(st.bufForEntry(kv)); current != kv {
+			entries = append(entries, symbol.SnapshotEntry{
+				Flags: symbol.SnapshotFlag_IDToValue,
+				ID:    symID,
+				Value: st.bufForEntry(kv),
+			})
+		}
+	}
+	st.tokenCacheMu.RUnlock()
+	st.valueCacheMu.RUnlock()
+
+	for _, entry := range entries {
+		if err = sw.WriteEntry(entry); err != nil {
+			return err
+		}
+	}
+	return sw.Close()
+}
+
+func (st *symbolTable) ImportFrom(r io.Reader) error {
+	sr, err := symbol.NewSnapshotReader(r)
+	if err != nil {
+		return err
+	}
+
+	var maxID symbol.ID
+	var idToValue []symbol.SnapshotEntry
+	for {
+		entry, err := sr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if entry.ID > maxID {
+			maxID = entry.ID
+		}
+		if entry.Flags&symbol.SnapshotFlag_ValueToID != 0 {
+			st.SetSymbolID(entry.Value, entry.ID)
+		}
+		if entry.Flags&symbol.SnapshotFlag_IDToValue != 0 {
+			idToValue = append(idToValue, entry)
+		}
+	}
+
+	// Since SetSymbolID() also (over)writes ID-to-value assignments, restore those afterward
+	for _, entry := range idToValue {
+		st.bindIDToValue(entry.ID, entry.Value)
+		st.persistIDToValue(entry.ID, entry.Value)
+	}
+
+	if iss, ok := st.opts.Issuer.(symbol.AdvancingIssuer); ok {
+		iss.AdvancePast(maxID)
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package memory_table
 
 import (
 	"bytes"
+	"fmt"
 	"sync"
 	"sync/atomic"
 
@@ -10,23 +11,99 @@ import (
 )
 
 func createTable(opts TableOpts) (symbol.Table, error) {
-	if opts.Issuer == nil {
-		opts.Issuer = symbol.NewVolatileIssuer(opts.IssuerInitsAt)
-	} else {
-		opts.Issuer.AddRef()
-	}
-
 	st := &symbolTable{
 		opts:          opts,
 		curBufPoolIdx: -1,
 		valueCache:    make(map[uint64]kvEntry, opts.WorkingSizeHint),
 		tokenCache:    make(map[symbol.ID]kvEntry, opts.WorkingSizeHint),
 	}
+	if opts.TrackAccess {
+		st.access = newAccessTracker(opts.WorkingSizeHint)
+	}
+
+	issuerFloor := opts.IssuerInitsAt
+	if opts.Store != nil {
+		maxID, err := st.loadFromStore()
+		if err != nil {
+			return nil, err
+		}
+		if maxID > issuerFloor {
+			issuerFloor = maxID
+		}
+	}
+
+	if opts.Issuer == nil {
+		st.opts.Issuer = symbol.NewVolatileIssuer(issuerFloor)
+	} else {
+		opts.Issuer.AddRef()
+	}
+
+	if opts.Store != nil && opts.WriteBehindSize > 0 {
+		st.startWriteBehind()
+	}
 
 	st.refCount.Store(1)
 	return st, nil
 }
 
+// loadFromStore populates the cache with all entries in opts.Store, returning the largest ID encountered.
+func (st *symbolTable) loadFromStore() (maxID symbol.ID, err error) {
+	store := st.opts.Store
+
+	err = store.Iterate([]byte{symbol.StoreKeyValue}, func(key, val []byte) error {
+		if len(val) != symbol.IDSz {
+			return fmt.Errorf("symbol store: bad value entry %q", key)
+		}
+		var symID symbol.ID
+		symID.ReadFrom(val)
+		if symID > maxID {
+			maxID = symID
+		}
+		st.allocAndBindToID(key[1:], key[1:], symID)
+		return nil
+	})
+	if err != nil {
+		return
+	}
+
+	// ID-to-value assignments may differ from the value-to-ID assignments (see SetSymbolID)
+	err = store.Iterate([]byte{symbol.StoreKeyID}, func(key, val []byte) error {
+		if len(key) != 1+symbol.IDSz {
+			return fmt.Errorf("symbol store: bad ID entry %q", key)
+		}
+		var symID symbol.ID
+		symID.ReadFrom(key[1:])
+		if symID > maxID {
+			maxID = symID
+		}
+		st.bindIDToValue(symID, val)
+		return nil
+	})
+	return
+}
+
+// persist writes the given value-ID assignment to opts.Store (if set), where key is the normalized form of val.
+func (st *symbolTable) persist(key, val []byte, symID symbol.ID) {
+	if st.opts.Store == nil {
+		return
+	}
+
+	var keyBuf [128]byte
+	storeKey := append(append(keyBuf[:0], symbol.StoreKeyValue), key...)
+	var idBuf [symbol.IDSz]byte
+	st.storeSet(storeKey, symID.AppendTo(idBuf[:0]))
+
+	st.persistIDToValue(symID, val)
+}
+
+// persistIDToValue writes only the given ID-to-value assignment to opts.Store (if set).
+func (st *symbolTable) persistIDToValue(symID symbol.ID, val []byte) {
+	if st.opts.Store != nil {
+		var keyBuf [1 + symbol.IDSz]byte
+		st.storeSet(symID.AppendTo(append(keyBuf[:0], symbol.StoreKeyID)), val)
+	}
+}
+
 func (st *symbolTable) Issuer() symbol.Issuer {
 	return st.opts.Issuer
 }
@@ -43,9 +120,22 @@ func (st *symbolTable) Close() error {
 }
 
 func (st *symbolTable) close() error {
-	err := st.opts.Issuer.Close()
+	err := st.closeNamespaces()
+	if issuerErr := st.opts.Issuer.Close(); err == nil {
+		err = issuerErr
+	}
 	st.opts.Issuer = nil
 
+	if st.opts.Store != nil {
+		if flushErr := st.stopWriteBehind(); err == nil {
+			err = flushErr
+		}
+		if storeErr := st.opts.Store.Close(); err == nil {
+			err = storeErr
+		}
+		st.opts.Store = nil
+	}
+
 	st.valueCache = nil
 	st.tokenCache = nil
 	st.bufPools = nil
@@ -86,27 +176,42 @@ type symbolTable struct {
 	curBufPoolSz  int32
 	curBufPoolIdx int32
 	bufPools      [][]byte
+	access        *accessTracker // nil unless TableOpts.TrackAccess
+	wb            *writeBehind   // nil unless TableOpts.WriteBehindSize > 0
+	nsPath        string         // full namespace path of this table ("" if not a namespace)
+	nsMu          sync.Mutex     // Protects namespaces
+	namespaces    map[string]*symbolTable
 }
 
 func (st *symbolTable) getIDFromCache(buf []byte) symbol.ID {
-	hash := bufs.HashBuf(buf)
+	kv, _ := st.getEntry(buf)
+	return kv.symID
+}
 
+func (st *symbolTable) getEntry(buf []byte) (kvEntry, bool) {
 	st.valueCacheMu.RLock()
 	defer st.valueCacheMu.RUnlock()
+	return st.lookupEntry(buf)
+}
+
+// lookupEntry returns the entry for the given value -- st.valueCacheMu must be locked.
+func (st *symbolTable) lookupEntry(buf []byte) (kvEntry, bool) {
+	hash := bufs.HashBuf(buf)
 
 	kv, found := st.valueCache[hash]
 	for found {
 		if st.equals(&kv, buf) {
-			return kv.symID
+			return kv, true
 		}
 		hash++
 		kv, found = st.valueCache[hash]
 	}
 
-	return 0
+	return kvEntry{}, false
 }
 
-func (st *symbolTable) allocAndBindToID(buf []byte, bindID symbol.ID) kvEntry {
+// allocAndBindToID binds the given lookup key (the normalized form of val) to the given ID and the ID to val.
+func (st *symbolTable) allocAndBindToID(buf, val []byte, bindID symbol.ID) kvEntry {
 	hash := bufs.HashBuf(buf)
 
 	st.valueCacheMu.Lock()
@@ -128,50 +233,91 @@ func (st *symbolTable) allocAndBindToID(buf []byte, bindID symbol.ID) kvEntry {
 
 	// At this point we know [hash] will be the destination element
 	// Add a copy of the buf in our backing buf (in the heap).
-	// If we run out of space in our pool, we start a new pool
+	kv = st.copyToPool(buf)
 	kv.symID = bindID
-	{
-		kv.len = int32(len(buf))
-		if int(st.curBufPoolSz+kv.len) > cap(st.curBufPool) {
-			allocSz := max(st.opts.PoolSz, kv.len)
-			st.curBufPool = make([]byte, allocSz)
-			st.curBufPoolSz = 0
-			st.curBufPoolIdx++
-			st.bufPools = append(st.bufPools, st.curBufPool)
-		}
-		kv.poolIdx = st.curBufPoolIdx
-		kv.poolOfs = st.curBufPoolSz
-		copy(st.curBufPool[kv.poolOfs:kv.poolOfs+kv.len], buf)
-		st.curBufPoolSz += kv.len
-	}
 
 	// Place the now-backed copy at the open hash spot and return the alloced value
 	st.valueCache[hash] = kv
 
+	// When normalized, the ID maps back to the value as originally given
+	tokenKV := kv
+	if !bytes.Equal(buf, val) {
+		tokenKV = st.copyToPool(val)
+		tokenKV.symID = bindID
+	}
+
 	st.tokenCacheMu.Lock()
-	st.tokenCache[kv.symID] = kv
+	st.tokenCache[bindID] = tokenKV
 	st.tokenCacheMu.Unlock()
 
+	st.touch(bindID)
+	return kv
+}
+
+// bindIDToValue (over)writes the ID-to-value assignment for the given ID, leaving value-to-ID assignments unchanged.
+func (st *symbolTable) bindIDToValue(symID symbol.ID, val []byte) {
+	st.valueCacheMu.Lock()
+	kv, found := st.lookupEntry(val)
+	if !found {
+		kv = st.copyToPool(val)
+	}
+	st.valueCacheMu.Unlock()
+	kv.symID = symID
+
+	st.tokenCacheMu.Lock()
+	st.tokenCache[symID] = kv
+	st.tokenCacheMu.Unlock()
+}
+
+// normalize returns the lookup key for the given value, using buf as scratch if TableOpts.Normalize is set.
+func (st *symbolTable) normalize(buf, val []byte) []byte {
+	if st.opts.Normalize == nil {
+		return val
+	}
+	return st.opts.Normalize(buf[:0], val)
+}
+
+// copyToPool copies the given buf into the current backing pool, starting a new pool if out of space -- st.valueCacheMu must be locked.
+func (st *symbolTable) copyToPool(buf []byte) (kv kvEntry) {
+	kv.len = int32(len(buf))
+	if int(st.curBufPoolSz+kv.len) > cap(st.curBufPool) {
+		allocSz := max(st.opts.PoolSz, kv.len)
+		st.curBufPool = make([]byte, allocSz)
+		st.curBufPoolSz = 0
+		st.curBufPoolIdx++
+		st.bufPools = append(st.bufPools, st.curBufPool)
+	}
+	kv.poolIdx = st.curBufPoolIdx
+	kv.poolOfs = st.curBufPoolSz
+	copy(st.curBufPool[kv.poolOfs:kv.poolOfs+kv.len], buf)
+	st.curBufPoolSz += kv.len
 	return kv
 }
 
 func (st *symbolTable) GetSymbolID(val []byte, autoIssue bool) symbol.ID {
-	symID := st.getIDFromCache(val)
+	var keyBuf [128]byte
+	key := st.normalize(keyBuf[:], val)
+
+	symID := st.getIDFromCache(key)
 	if symID != 0 {
+		st.touch(symID)
 		return symID
 	}
 
-	symID = st.getsetValueIDPair(val, 0, autoIssue)
+	symID = st.getsetValueIDPair(key, val, 0, autoIssue)
 	return symID
 }
 
 func (st *symbolTable) SetSymbolID(val []byte, symID symbol.ID) symbol.ID {
+	var keyBuf [128]byte
+	key := st.normalize(keyBuf[:], val)
+
 	// If symID == 0, then behave like GetSymbolID(val, true)
-	return st.getsetValueIDPair(val, symID, symID == 0)
+	return st.getsetValueIDPair(key, val, symID, symID == 0)
 }
 
 // getsetValueIDPair loads and returns the ID for the given value, and/or writes the ID and value assignment to the db,
-// also updating the cache in the process.
+// also updating the cache in the process.  The given key is the normalized form of val (see TableOpts.Normalize).
 //
 //	if symID == 0:
 //	  if the given value has an existing value-ID association:
@@ -183,10 +329,10 @@ func (st *symbolTable) SetSymbolID(val []byte, symID symbol.ID) symbol.ID {
 //	if symID != 0:
 //	    if mapID == false, a new value-to-ID assignment is (over)written and any existing ID-to-value assignment remains.
 //	    if mapID == true, both value-to-ID and ID-to-value assignments are (over)written.
-func (st *symbolTable) getsetValueIDPair(val []byte, symID symbol.ID, mapID bool) symbol.ID {
+func (st *symbolTable) getsetValueIDPair(key, val []byte, symID symbol.ID, mapID bool) symbol.ID {
 
 	// The empty string is always mapped to ID 0
-	if len(val) == 0 {
+	if len(key) == 0 {
 		return 0
 	}
 
@@ -196,7 +342,8 @@ func (st *symbolTable) getsetValueIDPair(val []byte, symID symbol.ID, mapID bool
 
 	// Update the cache
 	if symID != 0 {
-		st.allocAndBindToID(val, symID)
+		st.allocAndBindToID(key, val, symID)
+		st.persist(key, val, symID)
 	}
 	return symID
 }
@@ -215,6 +362,7 @@ func (st *symbolTable) GetSymbol(symID symbol.ID, io []byte) []byte {
 	if symBuf == nil {
 		return nil
 	}
+	st.touch(symID)
 	return append(io, symBuf...)
 }
 
@@ -1,7 +1,12 @@
 package memory_table_test
 
 import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/amp-3d/amp-sdk-go/stdlib/symbol"
 	"github.com/amp-3d/amp-sdk-go/stdlib/symbol/memory_table"
@@ -22,3 +27,409 @@ func Test_memory_table(t *testing.T) {
 
 	tests.DoTableTest(t, 0, open_table)
 }
+
+func Test_memory_table_with_store(t *testing.T) {
+	pathname := filepath.Join(t.TempDir(), "symbols.log")
+
+	open_table := func() (symbol.Table, error) {
+		store, err := symbol.OpenFileStore(pathname)
+		if err != nil {
+			return nil, err
+		}
+		opts := memory_table.DefaultOpts()
+		opts.Store = store
+		return opts.CreateTable()
+	}
+
+	tests.DoTableTest(t, 50000, open_table)
+
+	// Reopening must not reissue IDs already persisted
+	table, err := open_table()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer table.Close()
+	existing := table.GetSymbolID([]byte("1"), false)
+	if existing == 0 {
+		t.Fatal("persisted entry not found")
+	}
+	issued := table.GetSymbolID([]byte("never seen before"), true)
+	if issued <= existing {
+		t.Fatalf("reissued ID %d", issued)
+	}
+}
+
+func Test_memory_table_export_import(t *testing.T) {
+	src, _ := memory_table.DefaultOpts().CreateTable()
+	defer src.Close()
+
+	beatlesID := src.GetSymbolID([]byte("beatles"), true)
+	src.SetSymbolID([]byte("the beatles"), beatlesID) // second value for the same ID
+	src.SetSymbolID([]byte("hardwired"), 7)
+	for i := 0; i < 1000; i++ {
+		src.GetSymbolID([]byte(strconv.Itoa(i)), true)
+	}
+	lastID := src.GetSymbolID([]byte("last"), true)
+
+	var snapshot bytes.Buffer
+	if err := src.ExportTo(&snapshot); err != nil {
+		t.Fatal(err)
+	}
+
+	dst, _ := memory_table.DefaultOpts().CreateTable()
+	defer dst.Close()
+	if err := dst.ImportFrom(bytes.NewReader(snapshot.Bytes())); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, val := range []string{"beatles", "the beatles", "hardwired", "0", "999", "last"} {
+		srcID := src.GetSymbolID([]byte(val), false)
+		if dstID := dst.GetSymbolID([]byte(val), false); dstID != srcID {
+			t.Fatalf("%q: got ID %d, expected %d", val, dstID, srcID)
+		}
+		srcVal := src.GetSymbol(srcID, nil)
+		if dstVal := dst.GetSymbol(srcID, nil); !bytes.Equal(dstVal, srcVal) {
+			t.Fatalf("ID %d: got %q, expected %q", srcID, dstVal, srcVal)
+		}
+	}
+	if newID := dst.GetSymbolID([]byte("new"), true); newID <= lastID {
+		t.Fatalf("issued colliding ID %d", newID)
+	}
+
+	// A corrupted snapshot must be rejected
+	corrupt := append([]byte{}, snapshot.Bytes()...)
+	corrupt[len(corrupt)/2] ^= 0xFF
+	other, _ := memory_table.DefaultOpts().CreateTable()
+	defer other.Close()
+	if err := other.ImportFrom(bytes.NewReader(corrupt)); !errors.Is(err, symbol.ErrBadSnapshot) {
+		t.Fatalf("expected ErrBadSnapshot, got %v", err)
+	}
+}
+
+func Test_memory_table_compact(t *testing.T) {
+	store := symbol.NewMemoryStore()
+	opts := memory_table.DefaultOpts()
+	opts.TrackAccess = true
+	opts.Store = store
+	table, _ := opts.CreateTable()
+	defer table.Close()
+
+	table.SetSymbolID([]byte("hardwired"), 7)
+	for i := 0; i < 100; i++ {
+		table.GetSymbolID([]byte(strconv.Itoa(i)), true)
+	}
+
+	report, err := table.Compact(time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Reclaimed) != 0 || report.Remaining != 101 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+
+	// Everything is "unused" relative to a cutoff in the future
+	report, err = table.Compact(-time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Reclaimed) != 100 || report.Remaining != 1 || report.BytesReclaimed != 190 {
+		t.Fatalf("unexpected report: reclaimed %d, remaining %d, bytes %d", len(report.Reclaimed), report.Remaining, report.BytesReclaimed)
+	}
+	if table.GetSymbolID([]byte("42"), false) != 0 {
+		t.Fatal("reclaimed entry still present")
+	}
+	if table.GetSymbolID([]byte("hardwired"), false) != 7 || string(table.GetSymbol(7, nil)) != "hardwired" {
+		t.Fatal("hard-wired entry was reclaimed")
+	}
+
+	numStored := 0
+	store.Iterate(nil, func(key, value []byte) error {
+		numStored++
+		return nil
+	})
+	if numStored != 2 {
+		t.Fatalf("expected only the hard-wired entry to remain stored, got %d entries", numStored)
+	}
+
+	untracked, _ := memory_table.DefaultOpts().CreateTable()
+	defer untracked.Close()
+	if _, err := untracked.Compact(time.Hour); !errors.Is(err, symbol.ErrAccessNotTracked) {
+		t.Fatalf("expected ErrAccessNotTracked, got %v", err)
+	}
+}
+
+func Test_memory_table_leasing_issuer(t *testing.T) {
+	coord := symbol.NewLocalCoordinator(1000)
+	nodes := make([]symbol.Table, 2)
+	for i := range nodes {
+		opts := memory_table.DefaultOpts()
+		opts.Issuer = symbol.NewLeasingIssuer(symbol.LeasingIssuerOpts{
+			NodeID:      strconv.Itoa(i),
+			LeaseSize:   16,
+			Coordinator: coord,
+		})
+		nodes[i], _ = opts.CreateTable()
+		opts.Issuer.Close()
+		defer nodes[i].Close()
+	}
+
+	// Interleave issuing so each node leases several ranges
+	issued := make(map[symbol.ID]string)
+	for i := 0; i < 100; i++ {
+		for n, table := range nodes {
+			val := strconv.Itoa(n) + "-" + strconv.Itoa(i)
+			symID := table.GetSymbolID([]byte(val), true)
+			if symID <= 1000 {
+				t.Fatalf("ID %d issued below coordinator floor", symID)
+			}
+			if prev, collides := issued[symID]; collides {
+				t.Fatalf("ID %d issued for both %q and %q", symID, prev, val)
+			}
+			issued[symID] = val
+		}
+	}
+
+	// Since IDs never collide, one node's table can be merged into the other
+	var snapshot bytes.Buffer
+	if err := nodes[0].ExportTo(&snapshot); err != nil {
+		t.Fatal(err)
+	}
+	if err := nodes[1].ImportFrom(&snapshot); err != nil {
+		t.Fatal(err)
+	}
+	for symID, val := range issued {
+		if got := nodes[1].GetSymbolID([]byte(val), false); got != symID {
+			t.Fatalf("merged table maps %q to %d, expected %d", val, got, symID)
+		}
+		if got := string(nodes[1].GetSymbol(symID, nil)); got != val {
+			t.Fatalf("merged table maps %d to %q, expected %q", symID, got, val)
+		}
+	}
+}
+
+func Test_memory_table_write_behind(t *testing.T) {
+	pathname := filepath.Join(t.TempDir(), "symbols.log")
+
+	// Close must flush all pending writes
+	tests.DoTableTest(t, 50000, func() (symbol.Table, error) {
+		store, err := symbol.OpenFileStore(pathname)
+		if err != nil {
+			return nil, err
+		}
+		opts := memory_table.DefaultOpts()
+		opts.Store = store
+		opts.WriteBehindSize = 1000
+		return opts.CreateTable()
+	})
+
+	countStored := func(store symbol.Store) int {
+		num := 0
+		store.Iterate(nil, func(key, value []byte) error {
+			num++
+			return nil
+		})
+		return num
+	}
+
+	store := symbol.NewMemoryStore()
+	opts := memory_table.DefaultOpts()
+	opts.Store = store
+	opts.WriteBehindSize = 1000
+	opts.WriteBehindDelay = time.Hour
+	table, _ := opts.CreateTable()
+	defer table.Close()
+
+	for i := 0; i < 10; i++ {
+		table.GetSymbolID([]byte(strconv.Itoa(i)), true)
+	}
+	if num := countStored(store); num != 0 {
+		t.Fatalf("expected writes to be pending, got %d stored", num)
+	}
+	if err := table.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if num := countStored(store); num != 20 {
+		t.Fatalf("expected 20 stored entries after Flush, got %d", num)
+	}
+
+	// Reaching WriteBehindSize triggers a flush
+	for i := 10; i < 1010; i++ {
+		table.GetSymbolID([]byte(strconv.Itoa(i)), true)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for countStored(store) < 2000 {
+		if time.Now().After(deadline) {
+			t.Fatal("pending writes were not flushed")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// benchmarkSetSymbolID measures durable issuance throughput: without write-behind, each new issuance is committed
+// to the store individually, whereas write-behind commits once per batch.
+func benchmarkSetSymbolID(b *testing.B, writeBehindSize int) {
+	store, err := symbol.OpenFileStore(filepath.Join(b.TempDir(), "symbols.log"))
+	if err != nil {
+		b.Fatal(err)
+	}
+	opts := memory_table.DefaultOpts()
+	opts.Store = store
+	opts.WriteBehindSize = writeBehindSize
+	table, _ := opts.CreateTable()
+	defer table.Close()
+
+	var buf [32]byte
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		val := strconv.AppendInt(append(buf[:0], 'v'), int64(i), 10)
+		table.SetSymbolID(val, 0)
+		if writeBehindSize == 0 {
+			table.Flush()
+		}
+	}
+	if err := table.Flush(); err != nil {
+		b.Fatal(err)
+	}
+}
+
+func Benchmark_memory_table_SetSymbolID(b *testing.B) {
+	benchmarkSetSymbolID(b, 0)
+}
+
+func Benchmark_memory_table_SetSymbolID_write_behind(b *testing.B) {
+	benchmarkSetSymbolID(b, 4096)
+}
+
+func Test_memory_table_namespaces(t *testing.T) {
+	pathname := filepath.Join(t.TempDir(), "symbols.log")
+
+	open_table := func() symbol.Table {
+		store, err := symbol.OpenFileStore(pathname)
+		if err != nil {
+			t.Fatal(err)
+		}
+		opts := memory_table.DefaultOpts()
+		opts.Store = store
+		table, err := opts.CreateTable()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return table
+	}
+
+	root := open_table()
+	app1, _ := root.Namespace("app1")
+	app2, _ := root.Namespace("app2")
+	nested, _ := app1.Namespace("nested")
+
+	rootID := root.GetSymbolID([]byte("shared"), true)
+	app1.SetSymbolID([]byte("shared"), rootID+100)
+	app1.GetSymbolID([]byte("only-in-app1"), true)
+	nested.GetSymbolID([]byte("deep"), true)
+
+	if app2.GetSymbolID([]byte("shared"), false) != 0 || root.GetSymbolID([]byte("only-in-app1"), false) != 0 {
+		t.Fatal("namespace lookup resolved outside its namespace")
+	}
+	if app1.GetSymbolID([]byte("shared"), false) != rootID+100 || root.GetSymbolID([]byte("shared"), false) != rootID {
+		t.Fatal("namespaced value assignments are not isolated")
+	}
+
+	stats := app1.Stats()
+	if stats.Namespace != "app1" || stats.NumValues != 2 || stats.NumIDs != 2 || stats.ValueBytes != int64(len("shared")+len("only-in-app1")) {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+	if stats = nested.Stats(); stats.Namespace != "app1/nested" || stats.NumValues != 1 {
+		t.Fatalf("unexpected nested stats %+v", stats)
+	}
+	if _, err := root.Namespace(""); !errors.Is(err, symbol.ErrBadNamespace) {
+		t.Fatalf("expected ErrBadNamespace, got %v", err)
+	}
+
+	nested.Close()
+	app2.Close()
+	app1.Close()
+	if err := root.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// All namespaces share the one store
+	root = open_table()
+	defer root.Close()
+	if root.GetSymbolID([]byte("shared"), false) != rootID || root.GetSymbolID([]byte("deep"), false) != 0 {
+		t.Fatal("root entries not restored")
+	}
+	if stats := root.Stats(); stats.NumValues != 1 {
+		t.Fatalf("unexpected root stats %+v", stats)
+	}
+	app1, _ = root.Namespace("app1")
+	nested, _ = app1.Namespace("nested")
+	defer app1.Close()
+	defer nested.Close()
+	if app1.GetSymbolID([]byte("shared"), false) != rootID+100 || app1.GetSymbolID([]byte("only-in-app1"), false) == 0 {
+		t.Fatal("namespace entries not restored")
+	}
+	if nested.GetSymbolID([]byte("deep"), false) == 0 {
+		t.Fatal("nested namespace entries not restored")
+	}
+}
+
+func Test_memory_table_normalize(t *testing.T) {
+	pathname := filepath.Join(t.TempDir(), "symbols.log")
+
+	open_table := func() symbol.Table {
+		store, err := symbol.OpenFileStore(pathname)
+		if err != nil {
+			t.Fatal(err)
+		}
+		opts := memory_table.DefaultOpts()
+		opts.Store = store
+		opts.Normalize = symbol.FoldCase
+		table, err := opts.CreateTable()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return table
+	}
+
+	check := func(table symbol.Table, symID symbol.ID) {
+		for _, val := range []string{"Beatles", "beatles", "BEATLES"} {
+			if got := table.GetSymbolID([]byte(val), false); got != symID {
+				t.Fatalf("%q resolved to %d, expected %d", val, got, symID)
+			}
+		}
+		if got := string(table.GetSymbol(symID, nil)); got != "Beatles" {
+			t.Fatalf("expected original value, got %q", got)
+		}
+		if got := table.GetSymbolID([]byte("ÉCOLE"), false); got == 0 || string(table.GetSymbol(got, nil)) != "École" {
+			t.Fatalf("non-ASCII value not folded")
+		}
+	}
+
+	table := open_table()
+	symID := table.GetSymbolID([]byte("Beatles"), true)
+	if table.GetSymbolID([]byte("BEATLES"), true) != symID {
+		t.Fatal("differently cased value issued a new ID")
+	}
+	table.GetSymbolID([]byte("École"), true)
+	check(table, symID)
+
+	var snapshot bytes.Buffer
+	if err := table.ExportTo(&snapshot); err != nil {
+		t.Fatal(err)
+	}
+	table.Close()
+
+	table = open_table()
+	check(table, symID)
+	table.Close()
+
+	opts := memory_table.DefaultOpts()
+	opts.Normalize = symbol.FoldCase
+	imported, _ := opts.CreateTable()
+	defer imported.Close()
+	if err := imported.ImportFrom(&snapshot); err != nil {
+		t.Fatal(err)
+	}
+	check(imported, symID)
+}
@@ -0,0 +1,159 @@
+package memory_table
+
+import (
+	"sync"
+	"time"
+)
+
+// writeBehind batches store writes so that they are applied (and committed) off the caller's path.
+type writeBehind struct {
+	maxPending int
+	kick       chan struct{}
+	done       chan struct{}
+	stopped    sync.WaitGroup
+
+	flushMu sync.Mutex // serializes flushes so that batches are applied in order
+	err     error      // first error encountered by a background flush -- flushMu must be locked
+
+	mu      sync.Mutex // protects the fields below
+	ops     []storeOp
+	opBytes []byte // backing buf for ops
+}
+
+// storeOp is a pending Store.Set() or Store.Delete()
+type storeOp struct {
+	key, val []byte
+	del      bool
+}
+
+func (st *symbolTable) startWriteBehind() {
+	opts := st.opts
+	if opts.WriteBehindDelay <= 0 {
+		opts.WriteBehindDelay = 100 * time.Millisecond
+	}
+
+	wb := &writeBehind{
+		maxPending: opts.WriteBehindSize,
+		kick:       make(chan struct{}, 1),
+		done:       make(chan struct{}),
+	}
+	st.wb = wb
+
+	wb.stopped.Add(1)
+	go func() {
+		defer wb.stopped.Done()
+
+		ticker := time.NewTicker(opts.WriteBehindDelay)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-wb.kick:
+			case <-ticker.C:
+			case <-wb.done:
+				return
+			}
+			st.flushPending()
+		}
+	}()
+}
+
+// storeSet writes (or queues if write-behind is enabled) the given key-value to opts.Store.
+func (st *symbolTable) storeSet(key, val []byte) {
+	if st.wb == nil {
+		st.opts.Store.Set(key, val)
+	} else {
+		st.wb.enqueue(key, val, false)
+	}
+}
+
+// storeDelete deletes (or queues if write-behind is enabled) the given key from opts.Store.
+func (st *symbolTable) storeDelete(key []byte) {
+	if st.wb == nil {
+		st.opts.Store.Delete(key)
+	} else {
+		st.wb.enqueue(key, nil, true)
+	}
+}
+
+func (wb *writeBehind) enqueue(key, val []byte, del bool) {
+	wb.mu.Lock()
+	keyOfs := len(wb.opBytes)
+	wb.opBytes = append(wb.opBytes, key...)
+	wb.opBytes = append(wb.opBytes, val...)
+	valOfs := keyOfs + len(key)
+	wb.ops = append(wb.ops, storeOp{
+		key: wb.opBytes[keyOfs:valOfs:valOfs],
+		val: wb.opBytes[valOfs:],
+		del: del,
+	})
+	full := len(wb.ops) >= wb.maxPending
+	wb.mu.Unlock()
+
+	if full {
+		select {
+		case wb.kick <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// flushPending applies all pending writes to opts.Store and commits it.
+func (st *symbolTable) flushPending() error {
+	wb := st.wb
+	wb.flushMu.Lock()
+	defer wb.flushMu.Unlock()
+
+	wb.mu.Lock()
+	ops := wb.ops
+	// ops reference opBytes, so start new bufs rather than reuse
+	wb.ops = make([]storeOp, 0, cap(ops))
+	wb.opBytes = make([]byte, 0, cap(wb.opBytes))
+	wb.mu.Unlock()
+
+	store := st.opts.Store
+	for _, op := range ops {
+		var err error
+		if op.del {
+			err = store.Delete(op.key)
+		} else {
+			err = store.Set(op.key, op.val)
+		}
+		if err != nil && wb.err == nil {
+			wb.err = err
+		}
+	}
+
+	if len(ops) > 0 {
+		if err := store.Commit(); err != nil && wb.err == nil {
+			wb.err = err
+		}
+	}
+
+	err := wb.err
+	wb.err = nil
+	return err
+}
+
+func (st *symbolTable) Flush() error {
+	if st.opts.Store == nil {
+		return nil
+	}
+	if st.wb == nil {
+		return st.opts.Store.Commit()
+	}
+	return st.flushPending()
+}
+
+// stopWriteBehind stops background flushing and flushes all pending writes.
+func (st *symbolTable) stopWriteBehind() error {
+	wb := st.wb
+	if wb == nil {
+		return nil
+	}
+	close(wb.done)
+	wb.stopped.Wait()
+	err := st.flushPending()
+	st.wb = nil
+	return err
+}
@@ -0,0 +1,46 @@
+package symbol
+
+import (
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+// NormalizeFunc appends the normalized form of the given value to dst and returns the extended buffer.
+// It must be deterministic and idempotent (normalizing a normalized value must yield the same value).
+type NormalizeFunc func(dst, value []byte) []byte
+
+// FoldNFC is a NormalizeFunc that applies Unicode NFC normalization and full case folding, so that values differing only
+// by case or by how their characters are composed (e.g. "é" as U+00E9 or as "e" followed by U+0301) are equal.
+// Prefer it to FoldCase for user-facing labels.
+func FoldNFC(dst, value []byte) []byte {
+	folded := cases.Fold().Bytes(norm.NFC.Bytes(value)) // a Caser is stateful, so one is made per call
+	return norm.NFC.Append(dst, folded...)              // folding can leave a composable sequence
+}
+
+// FoldCase is a NormalizeFunc that applies Unicode simple case folding so that values differing only by case are equal.
+// Invalid UTF-8 bytes are passed through unchanged.
+func FoldCase(dst, value []byte) []byte {
+	for i := 0; i < len(value); {
+		c := value[i]
+		if c < utf8.RuneSelf {
+			if 'A' <= c && c <= 'Z' {
+				c += 'a' - 'A'
+			}
+			dst = append(dst, c)
+			i++
+			continue
+		}
+
+		r, sz := utf8.DecodeRune(value[i:])
+		if r == utf8.RuneError && sz == 1 {
+			dst = append(dst, c)
+		} else {
+			dst = utf8.AppendRune(dst, unicode.ToLower(unicode.ToUpper(r)))
+		}
+		i += sz
+	}
+	return dst
+}
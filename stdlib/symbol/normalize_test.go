@@ -0,0 +1,33 @@
+package symbol_test
+
+import (
+	"testing"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/symbol"
+)
+
+func TestFoldNFC(t *testing.T) {
+	for _, equal := range [][]string{
+		{"\u00e9cole", "e\u0301cole", "\u00c9COLE", "E\u0301COLE"}, // precomposed and decomposed é
+		{"Beatles", "beatles", "BEATLES"},
+		{"Straße", "STRASSE", "strasse"},           // full case folding
+		{"\u212b", "\u00c5", "A\u030a", "a\u030a"}, // Angstrom sign, Å, and their decompositions
+	} {
+		want := string(symbol.FoldNFC(nil, []byte(equal[0])))
+		for _, val := range equal {
+			got := symbol.FoldNFC([]byte("prefix:"), []byte(val))
+			if string(got) != "prefix:"+want {
+				t.Fatalf("%q normalized to %q, expected %q", val, got, want)
+			}
+			if again := string(symbol.FoldNFC(nil, got[len("prefix:"):])); again != want {
+				t.Fatalf("normalizing %q again yielded %q", want, again)
+			}
+		}
+	}
+	if a, b := symbol.FoldNFC(nil, []byte("é")), symbol.FoldNFC(nil, []byte("e")); string(a) == string(b) {
+		t.Fatal("expected accents to be preserved")
+	}
+	if got := string(symbol.FoldNFC(nil, []byte("é"))); got != "é" {
+		t.Fatalf("expected a composed é, got %q", got)
+	}
+}
@@ -0,0 +1,96 @@
+// Package pebble_store implements symbol.Store using Pebble (github.com/cockroachdb/pebble), an embedded LSM key-value store
+// suited to large tables on servers and desktops.
+//
+// It is a separate module so that hosts not using it do not depend on Pebble.
+package pebble_store
+
+import (
+	"errors"
+
+	"github.com/cockroachdb/pebble/v2"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/symbol"
+)
+
+// Open opens (or creates) the Pebble database in the given directory as a symbol.Store.
+// If opts is nil, Pebble's defaults are used.
+//
+// Writes are applied without syncing the write-ahead log, which Commit() then syncs, so entries written since the last
+// Commit() may be lost in a crash.
+func Open(dirname string, opts *pebble.Options) (symbol.Store, error) {
+	db, err := pebble.Open(dirname, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &pebbleStore{
+		db: db,
+	}, nil
+}
+
+// pebbleStore implements symbol.Store using a pebble.DB.
+type pebbleStore struct {
+	db *pebble.DB
+}
+
+func (ps *pebbleStore) Get(key []byte) ([]byte, error) {
+	val, closer, err := ps.db.Get(key)
+	if errors.Is(err, pebble.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer closer.Close()
+	return append([]byte{}, val...), nil
+}
+
+func (ps *pebbleStore) Set(key, value []byte) error {
+	return ps.db.Set(key, value, pebble.NoSync)
+}
+
+func (ps *pebbleStore) Delete(key []byte) error {
+	return ps.db.Delete(key, pebble.NoSync)
+}
+
+func (ps *pebbleStore) Iterate(prefix []byte, fn func(key, value []byte) error) error {
+	var opts pebble.IterOptions
+	if len(prefix) > 0 {
+		opts.LowerBound = prefix // pebble rejects an empty (non-nil) bound
+		opts.UpperBound = prefixEnd(prefix)
+	}
+	iter, err := ps.db.NewIter(&opts)
+	if err != nil {
+		return err
+	}
+	for valid := iter.First(); valid; valid = iter.Next() {
+		val, err := iter.ValueAndErr()
+		if err == nil {
+			err = fn(iter.Key(), val)
+		}
+		if err != nil {
+			iter.Close()
+			return err
+		}
+	}
+	return errors.Join(iter.Error(), iter.Close())
+}
+
+// prefixEnd returns the smallest key greater than all keys having the given prefix, or nil if there is none.
+func prefixEnd(prefix []byte) []byte {
+	end := append([]byte{}, prefix...)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i]++; end[i] != 0 {
+			return end[:i+1]
+		}
+	}
+	return nil
+}
+
+func (ps *pebbleStore) Commit() error {
+	return ps.db.LogData(nil, pebble.Sync)
+}
+
+func (ps *pebbleStore) Close() error {
+	err := ps.Commit()
+	return errors.Join(err, ps.db.Close())
+}
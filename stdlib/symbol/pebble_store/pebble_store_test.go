@@ -0,0 +1,43 @@
+package pebble_store_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/cockroachdb/pebble/v2"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/symbol"
+	"github.com/amp-3d/amp-sdk-go/stdlib/symbol/memory_table"
+	"github.com/amp-3d/amp-sdk-go/stdlib/symbol/pebble_store"
+	"github.com/amp-3d/amp-sdk-go/stdlib/symbol/tests"
+)
+
+func quietOpts() *pebble.Options {
+	return &pebble.Options{Logger: quietLogger{}}
+}
+
+type quietLogger struct{}
+
+func (quietLogger) Infof(format string, args ...any)  {}
+func (quietLogger) Errorf(format string, args ...any) {}
+func (quietLogger) Fatalf(format string, args ...any) { panic("pebble: fatal") }
+
+func TestPebbleStore(t *testing.T) {
+	dirname := filepath.Join(t.TempDir(), "symbols")
+	tests.DoStoreTest(t, func() (symbol.Store, error) {
+		return pebble_store.Open(dirname, quietOpts())
+	})
+}
+
+func TestPebbleTable(t *testing.T) {
+	dirname := filepath.Join(t.TempDir(), "symbols")
+	tests.DoTableTest(t, 50000, func() (symbol.Table, error) {
+		store, err := pebble_store.Open(dirname, quietOpts())
+		if err != nil {
+			return nil, err
+		}
+		opts := memory_table.DefaultOpts()
+		opts.Store = store
+		return opts.CreateTable()
+	})
+}
@@ -0,0 +1,176 @@
+package symbol
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+)
+
+// Snapshot format (see Table.ExportTo):
+//
+//	header:  "SYMT" | version:2
+//	entry:   flags:1 | ID:4 | valueLen:uvarint | value:valueLen      (flags != 0)
+//	trailer: 0x00 | entryCount:4 | crc32:4                           (crc32 of all bytes preceding it)
+//
+// All fixed-size ints are big endian.
+const (
+	SnapshotVersion = 1
+
+	SnapshotFlag_ValueToID = byte(1 << 0) // entry is a value-to-ID assignment
+	SnapshotFlag_IDToValue = byte(1 << 1) // entry is an ID-to-value assignment
+)
+
+var (
+	ErrBadSnapshot         = errors.New("bad symbol table snapshot")
+	ErrUnsupportedSnapshot = errors.New("unsupported symbol table snapshot version")
+
+	snapshotMagic = [4]byte{'S', 'Y', 'M', 'T'}
+)
+
+// SnapshotEntry is a single assignment within a symbol table snapshot.
+type SnapshotEntry struct {
+	Flags byte // SnapshotFlag_* bits
+	ID    ID
+	Value []byte
+}
+
+// SnapshotWriter writes a symbol table snapshot.
+type SnapshotWriter struct {
+	w       *bufio.Writer
+	crc     hash.Hash32
+	count   uint32
+	scratch []byte
+}
+
+// NewSnapshotWriter writes a snapshot header to the given writer, returning a SnapshotWriter to write entries.
+// Close() must be called to complete the snapshot.
+func NewSnapshotWriter(w io.Writer) (*SnapshotWriter, error) {
+	sw := &SnapshotWriter{
+		w:   bufio.NewWriter(w),
+		crc: crc32.NewIEEE(),
+	}
+	hdr := binary.BigEndian.AppendUint16(snapshotMagic[:], SnapshotVersion)
+	return sw, sw.write(hdr)
+}
+
+func (sw *SnapshotWriter) write(buf []byte) error {
+	sw.crc.Write(buf)
+	_, err := sw.w.Write(buf)
+	return err
+}
+
+// WriteEntry appends the given entry to the snapshot.
+func (sw *SnapshotWriter) WriteEntry(entry SnapshotEntry) error {
+	if entry.Flags == 0 {
+		return fmt.Errorf("%w: entry has no flags", ErrBadSnapshot)
+	}
+	buf := append(sw.scratch[:0], entry.Flags)
+	buf = entry.ID.AppendTo(buf)
+	buf = binary.AppendUvarint(buf, uint64(len(entry.Value)))
+	buf = append(buf, entry.Value...)
+	sw.scratch = buf
+	sw.count++
+	return sw.write(buf)
+}
+
+// Close writes the snapshot trailer and flushes.  The underlying writer is not closed.
+func (sw *SnapshotWriter) Close() error {
+	trailer := binary.BigEndian.AppendUint32([]byte{0}, sw.count)
+	if err := sw.write(trailer); err != nil {
+		return err
+	}
+	if _, err := sw.w.Write(binary.BigEndian.AppendUint32(nil, sw.crc.Sum32())); err != nil {
+		return err
+	}
+	return sw.w.Flush()
+}
+
+// SnapshotReader reads a symbol table snapshot written by a SnapshotWriter.
+type SnapshotReader struct {
+	r     *bufio.Reader
+	crc   hash.Hash32
+	count uint32
+	done  bool
+}
+
+// NewSnapshotReader reads and validates a snapshot header from the given reader.
+func NewSnapshotReader(r io.Reader) (*SnapshotReader, error) {
+	sr := &SnapshotReader{
+		r:   bufio.NewReader(r),
+		crc: crc32.NewIEEE(),
+	}
+	var hdr [6]byte
+	if err := sr.readFull(hdr[:]); err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(hdr[:4], snapshotMagic[:]) {
+		return nil, ErrBadSnapshot
+	}
+	if version := binary.BigEndian.Uint16(hdr[4:]); version != SnapshotVersion {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedSnapshot, version)
+	}
+	return sr, nil
+}
+
+func (sr *SnapshotReader) readFull(buf []byte) error {
+	if _, err := io.ReadFull(sr.r, buf); err != nil {
+		return fmt.Errorf("%w: %v", ErrBadSnapshot, err)
+	}
+	sr.crc.Write(buf)
+	return nil
+}
+
+// Next returns the next entry in the snapshot, or io.EOF once the trailer has been read and verified.
+func (sr *SnapshotReader) Next() (entry SnapshotEntry, err error) {
+	if sr.done {
+		return entry, io.EOF
+	}
+
+	var hdr [1 + IDSz]byte
+	if err = sr.readFull(hdr[:1]); err != nil {
+		return
+	}
+	if hdr[0] == 0 {
+		return entry, sr.readTrailer()
+	}
+	if err = sr.readFull(hdr[1:]); err != nil {
+		return
+	}
+	entry.Flags = hdr[0]
+	entry.ID.ReadFrom(hdr[1:])
+
+	valueLen, err := binary.ReadUvarint(sr.r)
+	if err != nil || valueLen > 1<<30 {
+		return entry, ErrBadSnapshot
+	}
+	sr.crc.Write(binary.AppendUvarint(nil, valueLen))
+	entry.Value = make([]byte, valueLen)
+	if err = sr.readFull(entry.Value); err != nil {
+		return
+	}
+	sr.count++
+	return entry, nil
+}
+
+func (sr *SnapshotReader) readTrailer() error {
+	var countBuf [4]byte
+	if err := sr.readFull(countBuf[:]); err != nil {
+		return err
+	}
+	expectedCRC := sr.crc.Sum32()
+
+	var crcBuf [4]byte
+	if _, err := io.ReadFull(sr.r, crcBuf[:]); err != nil {
+		return ErrBadSnapshot
+	}
+	if binary.BigEndian.Uint32(countBuf[:]) != sr.count || binary.BigEndian.Uint32(crcBuf[:]) != expectedCRC {
+		return fmt.Errorf("%w: checksum mismatch", ErrBadSnapshot)
+	}
+	sr.done = true
+	return io.EOF
+}
@@ -0,0 +1,204 @@
+// Package sqlite_store implements symbol.Store using SQLite via modernc.org/sqlite, a cgo-free port of SQLite, for hosts that
+// already keep their data in SQLite or want a single-file store that standard tools can inspect.
+//
+// It is a separate module so that hosts not using it do not depend on SQLite.
+package sqlite_store
+
+import (
+	"database/sql"
+	"errors"
+	"sync"
+
+	_ "modernc.org/sqlite" // registers the "sqlite" driver
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/symbol"
+)
+
+// iterateBatch is the number of entries Iterate reads per query, so that fn is never called while a query is open.
+const iterateBatch = 256
+
+// Open opens (or creates) the SQLite database at the given path as a symbol.Store, keeping entries in the table "symbols".
+//
+// Writes are made in a transaction that Commit() commits (and begins anew on the next write), so entries written since the
+// last Commit() are lost in a crash.
+func Open(pathname string) (symbol.Store, error) {
+	db, err := sql.Open("sqlite", pathname)
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(1) // writes are serialized by the store's single open transaction anyway
+
+	_, err = db.Exec(`
+		PRAGMA journal_mode = WAL;
+		PRAGMA synchronous = FULL;
+		CREATE TABLE IF NOT EXISTS symbols (key BLOB PRIMARY KEY, value BLOB NOT NULL) WITHOUT ROWID;
+	`)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &sqliteStore{
+		db: db,
+	}, nil
+}
+
+// sqliteStore implements symbol.Store using a SQLite table.
+type sqliteStore struct {
+	mu sync.Mutex // protects the fields below
+	db *sql.DB
+	tx *sql.Tx // pending writes, if any
+}
+
+// querier is implemented by both *sql.DB and *sql.Tx.
+type querier interface {
+	Exec(query string, args ...any) (sql.Result, error)
+	Query(query string, args ...any) (*sql.Rows, error)
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+// conn returns the pending transaction, or the database if there is none (or begins one if forWrite is set).
+func (ss *sqliteStore) conn(forWrite bool) (querier, error) {
+	if ss.tx == nil && forWrite {
+		tx, err := ss.db.Begin()
+		if err != nil {
+			return nil, err
+		}
+		ss.tx = tx
+	}
+	if ss.tx != nil {
+		return ss.tx, nil
+	}
+	return ss.db, nil
+}
+
+func (ss *sqliteStore) Get(key []byte) ([]byte, error) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	q, err := ss.conn(false)
+	if err != nil {
+		return nil, err
+	}
+	var val []byte
+	err = q.QueryRow(`SELECT value FROM symbols WHERE key = ?`, key).Scan(&val)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if val == nil && err == nil {
+		val = []byte{}
+	}
+	return val, err
+}
+
+func (ss *sqliteStore) Set(key, value []byte) error {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	q, err := ss.conn(true)
+	if err != nil {
+		return err
+	}
+	if value == nil {
+		value = []byte{}
+	}
+	_, err = q.Exec(`INSERT INTO symbols (key, value) VALUES (?, ?) ON CONFLICT (key) DO UPDATE SET value = excluded.value`, key, value)
+	return err
+}
+
+func (ss *sqliteStore) Delete(key []byte) error {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	q, err := ss.conn(true)
+	if err != nil {
+		return err
+	}
+	_, err = q.Exec(`DELETE FROM symbols WHERE key = ?`, key)
+	return err
+}
+
+func (ss *sqliteStore) Iterate(prefix []byte, fn func(key, value []byte) error) error {
+	end := prefixEnd(prefix)
+	from, inclusive := append([]byte{}, prefix...), true // never nil, which SQLite would compare as NULL
+	for {
+		keys, vals, err := ss.readBatch(from, inclusive, end)
+		if err != nil {
+			return err
+		}
+		for i := range keys {
+			if err = fn(keys[i], vals[i]); err != nil {
+				return err
+			}
+		}
+		if len(keys) < iterateBatch {
+			return nil
+		}
+		from, inclusive = keys[len(keys)-1], false
+	}
+}
+
+// readBatch reads up to iterateBatch entries whose keys are from the given key and before end (if set).
+func (ss *sqliteStore) readBatch(from []byte, inclusive bool, end []byte) (keys, vals [][]byte, err error) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	q, err := ss.conn(false)
+	if err != nil {
+		return nil, nil, err
+	}
+	query, args := `SELECT key, value FROM symbols WHERE key > ?`, []any{from}
+	if inclusive {
+		query = `SELECT key, value FROM symbols WHERE key >= ?`
+	}
+	if end != nil {
+		query += ` AND key < ?`
+		args = append(args, end)
+	}
+	rows, err := q.Query(query+` ORDER BY key LIMIT ?`, append(args, iterateBatch)...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var key, val []byte
+		if err = rows.Scan(&key, &val); err != nil {
+			return nil, nil, err
+		}
+		keys, vals = append(keys, key), append(vals, val)
+	}
+	return keys, vals, rows.Err()
+}
+
+// prefixEnd returns the smallest key greater than all keys having the given prefix, or nil if there is none.
+func prefixEnd(prefix []byte) []byte {
+	end := append([]byte{}, prefix...)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i]++; end[i] != 0 {
+			return end[:i+1]
+		}
+	}
+	return nil
+}
+
+func (ss *sqliteStore) Commit() error {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	return ss.commit()
+}
+
+func (ss *sqliteStore) commit() error {
+	if ss.tx == nil {
+		return nil
+	}
+	err := ss.tx.Commit()
+	ss.tx = nil
+	return err
+}
+
+func (ss *sqliteStore) Close() error {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	err := ss.commit()
+	return errors.Join(err, ss.db.Close())
+}
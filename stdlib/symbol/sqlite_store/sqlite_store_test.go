@@ -0,0 +1,31 @@
+package sqlite_store_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/symbol"
+	"github.com/amp-3d/amp-sdk-go/stdlib/symbol/memory_table"
+	"github.com/amp-3d/amp-sdk-go/stdlib/symbol/sqlite_store"
+	"github.com/amp-3d/amp-sdk-go/stdlib/symbol/tests"
+)
+
+func TestSQLiteStore(t *testing.T) {
+	pathname := filepath.Join(t.TempDir(), "symbols.db")
+	tests.DoStoreTest(t, func() (symbol.Store, error) {
+		return sqlite_store.Open(pathname)
+	})
+}
+
+func TestSQLiteTable(t *testing.T) {
+	pathname := filepath.Join(t.TempDir(), "symbols.db")
+	tests.DoTableTest(t, 50000, func() (symbol.Table, error) {
+		store, err := sqlite_store.Open(pathname)
+		if err != nil {
+			return nil, err
+		}
+		opts := memory_table.DefaultOpts()
+		opts.Store = store
+		return opts.CreateTable()
+	})
+}
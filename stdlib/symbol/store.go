@@ -0,0 +1,166 @@
+package symbol
+
+import (
+	"bytes"
+	"sort"
+	"sync"
+)
+
+// Store is a minimal key-value store used to persist the entries of a symbol.Table.
+//
+// Implementations must be safe for concurrent use.  Writes made via Set() are only guaranteed to be durable once Commit() returns.
+// This allows a host to choose a backend appropriate for its platform:
+//
+//	NewMemoryStore          volatile, e.g. for tests and caches
+//	OpenFileStore           an append-only file, needing no dependencies (e.g. for iOS or WASM)
+//	pebble_store.Open       Pebble, an embedded LSM store suited to large tables
+//	sqlite_store.Open       SQLite, e.g. for hosts already keeping their data in SQLite
+//
+// The tests package checks an implementation against this contract (see tests.DoStoreTest).
+type Store interface {
+
+	// Returns the value for the given key, or nil if not found.
+	Get(key []byte) ([]byte, error)
+
+	// Sets the value for the given key.  Neither buffer is retained.
+	Set(key, value []byte) error
+
+	// Removes the entry for the given key (if present).
+	Delete(key []byte) error
+
+	// Calls fn for each entry whose key has the given prefix, in ascending key order.
+	// The buffers given to fn are only valid during the call.  If fn returns an error, iteration stops and that error is returned.
+	Iterate(prefix []byte, fn func(key, value []byte) error) error
+
+	// Makes all prior Set() calls durable.
+	Commit() error
+
+	// Commits and releases this store.
+	Close() error
+}
+
+// Key prefixes used by a symbol.Table to persist entries in a Store.
+const (
+	StoreKeyValue = byte('v') // {StoreKeyValue}{value} => {ID}
+	StoreKeyID    = byte('i') // {StoreKeyID}{ID} => {value}
+
+	StoreKeyNamespace = byte('n') // {StoreKeyNamespace}{name}{0x00} prefixes all keys of a namespace (see Table.Namespace)
+)
+
+// NewPrefixStore returns a Store that reads and writes the given Store with all keys prefixed by the given prefix.
+// Closing the returned Store commits but does not close the underlying Store.
+func NewPrefixStore(store Store, prefix []byte) Store {
+	return &prefixStore{
+		store:  store,
+		prefix: append([]byte{}, prefix...),
+	}
+}
+
+// prefixStore implements symbol.Store by prefixing all keys of an underlying Store.
+type prefixStore struct {
+	store  Store
+	prefix []byte
+}
+
+func (ps *prefixStore) key(key []byte) []byte {
+	return append(append(make([]byte, 0, len(ps.prefix)+len(key)), ps.prefix...), key...)
+}
+
+func (ps *prefixStore) Get(key []byte) ([]byte, error) {
+	return ps.store.Get(ps.key(key))
+}
+
+func (ps *prefixStore) Set(key, value []byte) error {
+	return ps.store.Set(ps.key(key), value)
+}
+
+func (ps *prefixStore) Delete(key []byte) error {
+	return ps.store.Delete(ps.key(key))
+}
+
+func (ps *prefixStore) Iterate(prefix []byte, fn func(key, value []byte) error) error {
+	return ps.store.Iterate(ps.key(prefix), func(key, value []byte) error {
+		return fn(key[len(ps.prefix):], value)
+	})
+}
+
+func (ps *prefixStore) Commit() error {
+	return ps.store.Commit()
+}
+
+func (ps *prefixStore) Close() error {
+	return ps.store.Commit()
+}
+
+// NewMemoryStore returns a volatile Store that keeps all entries in memory.
+func NewMemoryStore() Store {
+	return &memoryStore{
+		entries: make(map[string][]byte),
+	}
+}
+
+// memoryStore implements symbol.Store using a map.
+type memoryStore struct {
+	mu      sync.RWMutex
+	entries map[string][]byte
+}
+
+func (ms *memoryStore) Get(key []byte) ([]byte, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	val, found := ms.entries[string(key)]
+	if !found {
+		return nil, nil
+	}
+	return append([]byte{}, val...), nil
+}
+
+func (ms *memoryStore) Set(key, value []byte) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	ms.entries[string(key)] = append([]byte{}, value...)
+	return nil
+}
+
+func (ms *memoryStore) Delete(key []byte) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	delete(ms.entries, string(key))
+	return nil
+}
+
+func (ms *memoryStore) Iterate(prefix []byte, fn func(key, value []byte) error) error {
+	ms.mu.RLock()
+	keys := make([]string, 0, len(ms.entries))
+	for key := range ms.entries {
+		if bytes.HasPrefix([]byte(key), prefix) {
+			keys = append(keys, key)
+		}
+	}
+	ms.mu.RUnlock()
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		ms.mu.RLock()
+		val, found := ms.entries[key]
+		ms.mu.RUnlock()
+		if !found {
+			continue
+		}
+		if err := fn([]byte(key), val); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ms *memoryStore) Commit() error {
+	return nil
+}
+
+func (ms *memoryStore) Close() error {
+	return nil
+}
@@ -0,0 +1,31 @@
+package symbol_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/symbol"
+	"github.com/amp-3d/amp-sdk-go/stdlib/symbol/tests"
+)
+
+func TestMemoryStore(t *testing.T) {
+	store := symbol.NewMemoryStore()
+	tests.DoStoreTest(t, func() (symbol.Store, error) {
+		return store, nil
+	})
+}
+
+func TestFileStore(t *testing.T) {
+	pathname := filepath.Join(t.TempDir(), "symbols.log")
+	tests.DoStoreTest(t, func() (symbol.Store, error) {
+		return symbol.OpenFileStore(pathname)
+	})
+}
+
+func TestPrefixStore(t *testing.T) {
+	base := symbol.NewMemoryStore()
+	base.Set([]byte("other"), []byte("hidden"))
+	tests.DoStoreTest(t, func() (symbol.Store, error) {
+		return symbol.NewPrefixStore(base, []byte("ns/")), nil
+	})
+}
@@ -0,0 +1,136 @@
+package tests
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/symbol"
+)
+
+// DoStoreTest checks the given symbol.Store implementation against the Store contract.  opener is called to open the store
+// under test and again after it is closed, when the entries committed before it was closed are expected to remain (a volatile
+// store's opener may return the same instance).
+func DoStoreTest(t *testing.T, opener func() (symbol.Store, error)) {
+	store, err := opener()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := func(store symbol.Store, key string, want []byte) {
+		t.Helper()
+		got, err := store.Get([]byte(key))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, want) || (got == nil) != (want == nil) {
+			t.Fatalf("key %q: expected %q, got %q", key, want, got)
+		}
+	}
+	collect := func(store symbol.Store, prefix string) []string {
+		t.Helper()
+		var entries []string
+		err := store.Iterate([]byte(prefix), func(key, value []byte) error {
+			entries = append(entries, string(key)+"="+string(value))
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return entries
+	}
+
+	// Get, Set, and Delete, where buffers given to Set are not retained
+	expect(store, "missing", nil)
+	key, val := []byte("a/1"), []byte("one")
+	if err = store.Set(key, val); err != nil {
+		t.Fatal(err)
+	}
+	key[0], val[0] = 'x', 'x'
+	expect(store, "a/1", []byte("one"))
+	for _, kv := range [][2]string{{"a/3", "three"}, {"a/2", "two"}, {"b/1", "other"}, {"a", "parent"}, {"a/4", ""}} {
+		if err = store.Set([]byte(kv[0]), []byte(kv[1])); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = store.Set([]byte("a/2"), []byte("TWO")); err != nil {
+		t.Fatal(err)
+	}
+	if err = store.Delete([]byte("a/3")); err != nil {
+		t.Fatal(err)
+	}
+	if err = store.Delete([]byte("never set")); err != nil {
+		t.Fatal(err)
+	}
+	expect(store, "a/2", []byte("TWO"))
+	expect(store, "a/3", nil)
+
+	// Iterate visits the entries of a prefix in ascending key order, and stops on error
+	want := fmt.Sprint([]string{"a/1=one", "a/2=TWO", "a/4="})
+	if got := fmt.Sprint(collect(store, "a/")); got != want {
+		t.Fatalf("expected %s, got %s", want, got)
+	}
+	if got := len(collect(store, "")); got != 5 { // an empty (non-nil) prefix visits every entry
+		t.Fatalf("expected 5 entries, got %d", got)
+	}
+	numEntries := 0
+	if err = store.Iterate(nil, func(key, value []byte) error { numEntries++; return nil }); err != nil || numEntries != 5 {
+		t.Fatalf("expected a nil prefix to visit 5 entries, got %d (%v)", numEntries, err)
+	}
+	errStop := errors.New("stop")
+	visited := 0
+	err = store.Iterate([]byte("a/"), func(key, value []byte) error {
+		visited++
+		return errStop
+	})
+	if err != errStop || visited != 1 {
+		t.Fatalf("expected iteration to stop with its error, got %v after %d", err, visited)
+	}
+
+	// Concurrent use
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				key := []byte(fmt.Sprintf("c/%d/%03d", i, j))
+				if err := store.Set(key, key); err != nil {
+					t.Error(err)
+					return
+				}
+				if got, err := store.Get(key); err != nil || !bytes.Equal(got, key) {
+					t.Errorf("key %q: got %q, %v", key, got, err)
+					return
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	if got := len(collect(store, "c/")); got != 800 {
+		t.Fatalf("expected 800 entries, got %d", got)
+	}
+
+	// Committed entries remain once reopened
+	if err = store.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if err = store.Close(); err != nil {
+		t.Fatal(err)
+	}
+	store, err = opener()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	if got := fmt.Sprint(collect(store, "a/")); got != want {
+		t.Fatalf("expected %s once reopened, got %s", want, got)
+	}
+	expect(store, "a", []byte("parent"))
+	expect(store, "a/3", nil)
+	if got := len(collect(store, "c/")); got != 800 {
+		t.Fatalf("expected 800 entries once reopened, got %d", got)
+	}
+}
@@ -11,12 +11,20 @@ import (
 	"github.com/amp-3d/amp-sdk-go/stdlib/symbol"
 )
 
-const kTotalEntries = 1001447
+const (
+	kTotalEntries = 1001447
+	kShortEntries = 2000 // the most entries tested under -short or -race
+)
 
+// DoTableTest fills a symbol.Table from several goroutines with the given number of entries (or kTotalEntries if 0) and
+// checks them once reopened.  Under -short or -race, at most kShortEntries are tested.
 func DoTableTest(t *testing.T, totalEntries int, opener func() (symbol.Table, error)) {
 	if totalEntries == 0 {
 		totalEntries = kTotalEntries
 	}
+	if testing.Short() || raceEnabled {
+		totalEntries = min(totalEntries, kShortEntries)
+	}
 
 	tt := tableTester{
 		errs: make(chan error),
@@ -0,0 +1,272 @@
+package tag
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Expr is a compiled tag expression used to filter tag specs (e.g. the tag.Spec of a cell or attr).
+//
+// Tag expression syntax:
+//
+//	expr    := term  ( '|' term )*       -- union
+//	term    := factor ( '&' factor )*    -- intersection
+//	factor  := '!' factor | '(' expr ')' | pattern
+//	pattern := tag ( '.' tag )* [ '.*' ] | '*'
+//
+// A pattern matches a spec whose canonic tags equal the pattern's tags, in order.
+// A trailing '*' makes the pattern a prefix wildcard: "music.*" matches "music" and every spec that begins with "music".
+// For example, "music.* & !music.podcast.*" matches all music specs except podcasts.
+type Expr struct {
+	root exprNode
+}
+
+var ErrBadExpr = errors.New("bad tag expression")
+
+// CompileExpr parses the given tag expression into an Expr.
+func CompileExpr(expr string) (*Expr, error) {
+	p := exprParser{src: expr}
+	root, err := p.parseUnion()
+	if err == nil && p.peek() != 0 {
+		err = p.errorf("unexpected %q", p.peek())
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &Expr{root: root}, nil
+}
+
+// MustCompileExpr is like CompileExpr but panics if the expression is invalid.
+func MustCompileExpr(expr string) *Expr {
+	e, err := CompileExpr(expr)
+	if err != nil {
+		panic(err)
+	}
+	return e
+}
+
+// Match returns true if the given spec satisfies this expression.
+func (e *Expr) Match(spec Spec) bool {
+	return e.MatchTags(spec.CanonicString())
+}
+
+// MatchTags returns true if the given tags (in any form accepted by FormSpec) satisfy this expression.
+func (e *Expr) MatchTags(tags string) bool {
+	return e.root.match(splitTags(tags))
+}
+
+// String returns this expression in normalized form.
+func (e *Expr) String() string {
+	b := strings.Builder{}
+	e.root.writeTo(&b, 0)
+	return b.String()
+}
+
+func splitTags(tags string) []string {
+	split := sTagSeparator.Split(tags, -1)
+	n := 0
+	for _, ti := range split {
+		if ti != "" { // empty tokens are no-ops
+			split[n] = ti
+			n++
+		}
+	}
+	return split[:n]
+}
+
+// exprNode precedence levels, used to omit redundant parens
+const (
+	precUnion = iota
+	precIntersect
+	precUnary
+)
+
+type exprNode interface {
+	match(tags []string) bool
+	writeTo(b *strings.Builder, prec int)
+}
+
+type patternNode struct {
+	tags   []string
+	prefix bool
+}
+
+func (n *patternNode) match(tags []string) bool {
+	if len(tags) < len(n.tags) || (!n.prefix && len(tags) != len(n.tags)) {
+		return false
+	}
+	for i, ti := range n.tags {
+		if tags[i] != ti {
+			return false
+		}
+	}
+	return true
+}
+
+func (n *patternNode) writeTo(b *strings.Builder, prec int) {
+	for i, ti := range n.tags {
+		if i > 0 {
+			b.WriteRune(CanonicWithRune)
+		}
+		b.WriteString(ti)
+	}
+	if n.prefix {
+		if len(n.tags) > 0 {
+			b.WriteRune(CanonicWithRune)
+		}
+		b.WriteByte('*')
+	}
+}
+
+type notNode struct {
+	operand exprNode
+}
+
+func (n *notNode) match(tags []string) bool {
+	return !n.operand.match(tags)
+}
+
+func (n *notNode) writeTo(b *strings.Builder, prec int) {
+	b.WriteByte('!')
+	n.operand.writeTo(b, precUnary)
+}
+
+type setNode struct {
+	intersect bool
+	operands  []exprNode
+}
+
+func (n *setNode) match(tags []string) bool {
+	for _, op := range n.operands {
+		if op.match(tags) != n.intersect {
+			return !n.intersect
+		}
+	}
+	return n.intersect
+}
+
+func (n *setNode) writeTo(b *strings.Builder, prec int) {
+	opPrec, opStr := precUnion, " | "
+	if n.intersect {
+		opPrec, opStr = precIntersect, " & "
+	}
+	if prec > opPrec {
+		b.WriteByte('(')
+	}
+	for i, op := range n.operands {
+		if i > 0 {
+			b.WriteString(opStr)
+		}
+		op.writeTo(b, opPrec+1)
+	}
+	if prec > opPrec {
+		b.WriteByte(')')
+	}
+}
+
+// exprParser is a recursive descent parser for tag expressions.
+type exprParser struct {
+	src string
+	pos int
+}
+
+func (p *exprParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("%w at offset %d in %q: %s", ErrBadExpr, p.pos, p.src, fmt.Sprintf(format, args...))
+}
+
+// peek skips whitespace and returns the next byte (or 0 if at the end).
+func (p *exprParser) peek() byte {
+	for p.pos < len(p.src) && (p.src[p.pos] == ' ' || p.src[p.pos] == '\t' || p.src[p.pos] == '\n' || p.src[p.pos] == '\r') {
+		p.pos++
+	}
+	if p.pos == len(p.src) {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+func (p *exprParser) parseUnion() (exprNode, error) {
+	return p.parseSet('|', false, p.parseIntersect)
+}
+
+func (p *exprParser) parseIntersect() (exprNode, error) {
+	return p.parseSet('&', true, p.parseFactor)
+}
+
+func (p *exprParser) parseSet(op byte, intersect bool, parseOperand func() (exprNode, error)) (exprNode, error) {
+	node, err := parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	var set *setNode
+	for p.peek() == op {
+		p.pos++
+		operand, err := parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		if set == nil {
+			set = &setNode{intersect: intersect, operands: []exprNode{node}}
+			node = set
+		}
+		set.operands = append(set.operands, operand)
+	}
+	return node, nil
+}
+
+func (p *exprParser) parseFactor() (exprNode, error) {
+	switch p.peek() {
+	case '!':
+		p.pos++
+		operand, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{operand: operand}, nil
+	case '(':
+		p.pos++
+		node, err := p.parseUnion()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ')' {
+			return nil, p.errorf("expected ')'")
+		}
+		p.pos++
+		return node, nil
+	case 0:
+		return nil, p.errorf("unexpected end of expression")
+	}
+	return p.parsePattern()
+}
+
+func (p *exprParser) parsePattern() (exprNode, error) {
+	start := p.pos
+	for p.pos < len(p.src) && !strings.ContainsRune("|&!() \t\n\r", rune(p.src[p.pos])) {
+		p.pos++
+	}
+	if p.pos == start {
+		return nil, p.errorf("expected tag pattern")
+	}
+
+	node := &patternNode{
+		tags: splitTags(p.src[start:p.pos]),
+	}
+	for i, ti := range node.tags {
+		if strings.IndexByte(ti, '*') < 0 {
+			continue
+		}
+		if ti != "*" || i != len(node.tags)-1 {
+			p.pos = start
+			return nil, p.errorf("'*' is only allowed as the last tag of a pattern")
+		}
+		node.tags = node.tags[:i]
+		node.prefix = true
+	}
+	if len(node.tags) == 0 && !node.prefix {
+		p.pos = start
+		return nil, p.errorf("expected tag pattern")
+	}
+	return node, nil
+}
@@ -0,0 +1,245 @@
+package tag
+
+import (
+	"database/sql/driver"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash/crc32"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/bufs"
+)
+
+var (
+	ErrInvalidUUID    = errors.New("invalid UUID")
+	ErrInvalidULID    = errors.New("invalid ULID")
+	ErrInvalidID      = errors.New("invalid tag.ID")
+	ErrExceeds128Bits = errors.New("tag.ID exceeds 128 bits")
+)
+
+// UUID is a 16 byte RFC 4122 UUID (of any version) in its standard big endian byte order.
+type UUID [16]byte
+
+// FromUUID losslessly converts the given UUID to a tag.ID, placing its 128 bits in ID[1] and ID[2].
+func FromUUID(uuid UUID) ID {
+	return From16(uuid[:])
+}
+
+// ToUUID is the inverse of FromUUID, returning ErrExceeds128Bits if this ID does not fit in 128 bits (i.e. ID[0] != 0).
+func (id ID) ToUUID() (uuid UUID, err error) {
+	if id[0] != 0 {
+		return uuid, ErrExceeds128Bits
+	}
+	id.Put16(uuid[:])
+	return uuid, nil
+}
+
+// ParseUUID parses a UUID in its canonical hyphenated form (e.g. "f47ac10b-58cc-4372-a567-0e02b2c3d479")
+// or as 32 hex digits.
+func ParseUUID(str string) (uuid UUID, err error) {
+	var digits [32]byte
+	switch len(str) {
+	case 32:
+		copy(digits[:], str)
+	case 36:
+		if str[8] != '-' || str[13] != '-' || str[18] != '-' || str[23] != '-' {
+			return uuid, ErrInvalidUUID
+		}
+		n := copy(digits[:], str[0:8])
+		n += copy(digits[n:], str[9:13])
+		n += copy(digits[n:], str[14:18])
+		n += copy(digits[n:], str[19:23])
+		copy(digits[n:], str[24:36])
+	default:
+		return uuid, ErrInvalidUUID
+	}
+	if _, err = hex.Decode(uuid[:], digits[:]); err != nil {
+		return uuid, ErrInvalidUUID
+	}
+	return uuid, nil
+}
+
+// String returns this UUID in canonical hyphenated form.
+func (uuid UUID) String() string {
+	var buf [36]byte
+	hex.Encode(buf[0:8], uuid[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], uuid[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], uuid[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], uuid[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], uuid[10:])
+	return string(buf[:])
+}
+
+// crockfordAlphabet is the Crockford base32 alphabet used by ULIDs.
+const crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+var crockfordDecoding = func() (dec [256]byte) {
+	for i := range dec {
+		dec[i] = 0xFF
+	}
+	for i := 0; i < len(crockfordAlphabet); i++ {
+		c := crockfordAlphabet[i]
+		dec[c] = byte(i)
+		if 'A' <= c && c <= 'Z' {
+			dec[c+'a'-'A'] = byte(i)
+		}
+	}
+
+	// Crockford decoding accepts common misreadings
+	dec['O'], dec['o'] = 0, 0
+	dec['I'], dec['i'], dec['L'], dec['l'] = 1, 1, 1, 1
+	return
+}()
+
+// FromULID losslessly converts the given 26 digit ULID string to a tag.ID, placing its 128 bits in ID[1] and ID[2].
+// Since a ULID is big endian, the resulting IDs sort in the same order as the ULIDs they were formed from.
+func FromULID(ulid string) (id ID, err error) {
+	if len(ulid) != 26 {
+		return id, ErrInvalidULID
+	}
+
+	// 26 digits * 5 bits = 130 bits, so the leading digit only contributes 3 bits
+	var hi, lo uint64
+	for i := 0; i < 26; i++ {
+		digit := crockfordDecoding[ulid[i]]
+		if digit == 0xFF || (i == 0 && digit > 7) {
+			return id, ErrInvalidULID
+		}
+		hi = (hi << 5) | (lo >> 59)
+		lo = (lo << 5) | uint64(digit)
+	}
+	return ID{0, hi, lo}, nil
+}
+
+// ToULID is the inverse of FromULID, returning ErrExceeds128Bits if this ID does not fit in 128 bits (i.e. ID[0] != 0).
+func (id ID) ToULID() (string, error) {
+	if id[0] != 0 {
+		return "", ErrExceeds128Bits
+	}
+	var buf [26]byte
+	hi, lo := id[1], id[2]
+	for i := 25; i >= 0; i-- {
+		buf[i] = crockfordAlphabet[lo&0x1F]
+		lo = (lo >> 5) | (hi << 59)
+		hi >>= 5
+	}
+	return string(buf[:]), nil
+}
+
+// MarshalText implements encoding.TextMarshaler using the canonic Base32 form.
+func (id ID) MarshalText() ([]byte, error) {
+	return []byte(id.Base32()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, accepting the canonic Base32 form or a UUID.
+func (id *ID) UnmarshalText(text []byte) error {
+	parsed, err := parseIDText(string(text))
+	if err != nil {
+		return err
+	}
+	*id = parsed
+	return nil
+}
+
+// parseIDText parses the given string as a tag.ID in canonic Base32 form (see ID.Base32) or as a UUID (see ParseUUID).
+func parseIDText(str string) (ID, error) {
+	if len(str) == 40 {
+		var buf [25]byte
+		n, err := bufs.Base32Encoding.Decode(buf[:], []byte(str))
+		if err != nil || n != len(buf) || buf[0] != 0 {
+			return Nil, fmt.Errorf("%w: %q", ErrInvalidID, str)
+		}
+		return FromBytes(buf[1:])
+	}
+	if uuid, err := ParseUUID(str); err == nil {
+		return FromUUID(uuid), nil
+	}
+	return Nil, fmt.Errorf("%w: %q", ErrInvalidID, str)
+}
+
+// Value implements driver.Valuer, storing this ID as its 24 byte big endian binary form.
+func (id ID) Value() (driver.Value, error) {
+	return id.AppendTo(make([]byte, 0, 24)), nil
+}
+
+// Scan implements sql.Scanner, accepting a binary form (24 bytes, or 16 bytes for a UUID), a string accepted by UnmarshalText, or NULL.
+func (id *ID) Scan(src any) (err error) {
+	switch v := src.(type) {
+	case nil:
+		*id = Nil
+	case []byte:
+		if len(v) == 24 || len(v) == 16 {
+			*id, err = FromBytes(v)
+		} else {
+			*id, err = parseIDText(string(v))
+		}
+	case string:
+		*id, err = parseIDText(v)
+	default:
+		err = fmt.Errorf("%w: cannot scan %T", ErrInvalidID, src)
+	}
+	return err
+}
+
+// ShortString returns this ID in a compact form suitable for URLs and chat: Crockford base32 digits (without leading zeros)
+// followed by two check digits.  Parsing (see ParseID) is case-insensitive, treats 'I'/'L' as '1' and 'O' as '0',
+// and detects nearly all transcription errors via the check digits.
+func (id ID) ShortString() string {
+	var buf [39 + 2]byte // ceil(192 bits / 5) digits + check digits
+	i := len(buf) - 2
+	for remain := id; ; {
+		i--
+		buf[i] = crockfordAlphabet[remain[2]&0x1F]
+		remain[2] = (remain[2] >> 5) | (remain[1] << 59)
+		remain[1] = (remain[1] >> 5) | (remain[0] << 59)
+		remain[0] >>= 5
+		if remain.IsNil() {
+			break
+		}
+	}
+	check := id.shortCheck()
+	buf[len(buf)-2] = crockfordAlphabet[check>>5]
+	buf[len(buf)-1] = crockfordAlphabet[check&0x1F]
+	return string(buf[i:])
+}
+
+// shortCheck returns the 10 bit checksum used by ShortString.
+func (id ID) shortCheck() uint32 {
+	var buf [24]byte
+	return crc32.ChecksumIEEE(id.AppendTo(buf[:0])) & 0x3FF
+}
+
+// ParseID parses a tag.ID from either its long hex form (see ID.Base16) or its short form (see ID.ShortString).
+func ParseID(str string) (id ID, err error) {
+	if len(str) == 48 {
+		var buf [24]byte
+		if _, err = hex.Decode(buf[:], []byte(str)); err != nil {
+			return Nil, fmt.Errorf("%w: %q", ErrInvalidID, str)
+		}
+		return FromBytes(buf[:])
+	}
+
+	if len(str) < 3 || len(str) > 39+2 {
+		return Nil, fmt.Errorf("%w: %q", ErrInvalidID, str)
+	}
+	digits := str[:len(str)-2]
+	for i := 0; i < len(digits); i++ {
+		digit := crockfordDecoding[digits[i]]
+		if digit == 0xFF || id[0]>>59 != 0 {
+			return Nil, fmt.Errorf("%w: %q", ErrInvalidID, str)
+		}
+		id[0] = (id[0] << 5) | (id[1] >> 59)
+		id[1] = (id[1] << 5) | (id[2] >> 59)
+		id[2] = (id[2] << 5) | uint64(digit)
+	}
+
+	hi, lo := crockfordDecoding[str[len(str)-2]], crockfordDecoding[str[len(str)-1]]
+	if hi == 0xFF || lo == 0xFF || uint32(hi)<<5|uint32(lo) != id.shortCheck() {
+		return Nil, fmt.Errorf("%w: checksum mismatch in %q", ErrInvalidID, str)
+	}
+	return id, nil
+}
@@ -0,0 +1,84 @@
+package tag
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Generator issues time-ordered tag.IDs that are strictly increasing, even if the system clock regresses
+// or yields the same time for successive calls.
+//
+// When the clock is behind the most recently issued ID, the generator falls back to incrementing ID[2]
+// of that ID (a counter that leaves the embedded time unchanged) until the clock catches up.
+type Generator struct {
+	addEntropy  bool
+	mu          sync.Mutex
+	last        ID
+	regressions atomic.Int64
+}
+
+// NewGenerator returns a Generator, with addEntropy having the same meaning as in FromTime().
+func NewGenerator(addEntropy bool) *Generator {
+	return &Generator{
+		addEntropy: addEntropy,
+	}
+}
+
+var gMonotonic = NewGenerator(true)
+
+// NewMonotonic is like New(), except IDs returned are guaranteed to be strictly increasing within this process.
+func NewMonotonic() ID {
+	return gMonotonic.Next()
+}
+
+// Next returns an ID for the current time that is greater than all IDs previously returned by this Generator.
+func (g *Generator) Next() ID {
+	return g.NextAt(time.Now())
+}
+
+// NextAt returns an ID for the given time that is greater than all IDs previously returned by this Generator.
+func (g *Generator) NextAt(t time.Time) ID {
+	id := FromTime(t, g.addEntropy)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if id.CompareTo(g.last) <= 0 {
+		if id[0] < g.last[0] {
+			g.regressions.Add(1)
+		}
+		id = g.last.Add(ID{0, 0, 1})
+	}
+	g.last = id
+	return id
+}
+
+// Last returns the most recent ID issued by this Generator.
+func (g *Generator) Last() ID {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.last
+}
+
+// Regressions returns how many times this Generator has observed the clock running behind the last issued ID
+// by more than the 16 bit fixed-point fraction of a second (~15us) stored in ID[0].
+func (g *Generator) Regressions() int64 {
+	return g.regressions.Load()
+}
+
+// Time returns the UTC time embedded in this ID (see FromTime), accurate to the nanosecond.
+func (id ID) Time() time.Time {
+	secs := int64(id[0]) >> 16
+	frac := (id[0] << 48) | (id[1] >> 16)
+
+	// Round to the nearest ns since the low bits of the fraction may contain entropy
+	ns := frac / NanosecStep
+	if frac%NanosecStep >= NanosecStep/2 {
+		ns++
+	}
+	if ns >= 1e9 {
+		ns = 1e9 - 1
+	}
+	return time.Unix(secs, int64(ns)).UTC()
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/hex"
 	"regexp"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/amp-3d/amp-sdk-go/stdlib/bufs"
@@ -249,8 +250,14 @@ func FromTime(t time.Time, addEntropy bool) ID {
 	t_06_08 := ns_f64 >> 48
 	t_08_15 := ns_f64 << 16
 	if addEntropy {
-		gTagSeed = ns_f64 ^ (gTagSeed * 5237732522753)
-		t_08_15 ^= gTagSeed & EntropyMask
+		for {
+			prev := atomic.LoadUint64(&gTagSeed)
+			seed := ns_f64 ^ (prev * 5237732522753)
+			if atomic.CompareAndSwapUint64(&gTagSeed, prev, seed) {
+				t_08_15 ^= seed & EntropyMask
+				break
+			}
+		}
 	}
 
 	tag := ID{
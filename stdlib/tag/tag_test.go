@@ -1,7 +1,11 @@
 package tag_test
 
 import (
+	"encoding/json"
+	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/amp-3d/amp-sdk-go/stdlib/tag"
 )
@@ -86,3 +90,241 @@ func TestNewTag(t *testing.T) {
 		prevIDs[i&63] = now
 	}
 }
+
+func TestInterop(t *testing.T) {
+	uuid, err := tag.ParseUUID("f47ac10b-58cc-4372-a567-0e02b2c3d479")
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := tag.FromUUID(uuid)
+	if id != (tag.ID{0, 0xf47ac10b58cc4372, 0xa5670e02b2c3d479}) {
+		t.Fatalf("tag.FromUUID() failed: %x", id)
+	}
+	if back, err := id.ToUUID(); err != nil || back != uuid || back.String() != "f47ac10b-58cc-4372-a567-0e02b2c3d479" {
+		t.Fatalf("tag.ID.ToUUID() failed: %v %v", back, err)
+	}
+	if _, err := tag.New().ToUUID(); !errors.Is(err, tag.ErrExceeds128Bits) {
+		t.Fatalf("expected ErrExceeds128Bits, got %v", err)
+	}
+	if _, err := tag.ParseUUID("f47ac10b-58cc-4372-a567_0e02b2c3d479"); !errors.Is(err, tag.ErrInvalidUUID) {
+		t.Fatalf("expected ErrInvalidUUID, got %v", err)
+	}
+
+	const ulid = "01ARZ3NDEKTSV4RRFFQ69G5FAV"
+	id, err = tag.FromULID(ulid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id[1]>>16 != 0x01563e3ab5d3 { // 48 bit ms timestamp
+		t.Fatalf("tag.FromULID() failed: %x", id)
+	}
+	if str, _ := id.ToULID(); str != ulid {
+		t.Fatalf("tag.ID.ToULID() failed: %v", str)
+	}
+	later, _ := tag.FromULID("01ARZ3NDEKTSV4RRFFQ69G5FAW")
+	if id.CompareTo(later) >= 0 {
+		t.Fatal("ULID order not preserved")
+	}
+	for _, bad := range []string{"81ARZ3NDEKTSV4RRFFQ69G5FAV", "01ARZ3NDEKTSV4RRFFQ69G5FAU", "01ARZ3NDEK"} {
+		if _, err := tag.FromULID(bad); !errors.Is(err, tag.ErrInvalidULID) {
+			t.Fatalf("expected ErrInvalidULID for %q, got %v", bad, err)
+		}
+	}
+
+	// JSON (via encoding.TextMarshaler)
+	type record struct {
+		ID tag.ID `json:"id"`
+	}
+	src := record{ID: tag.New()}
+	buf, err := json.Marshal(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(buf) != `{"id":"`+src.ID.Base32()+`"}` {
+		t.Fatalf("unexpected JSON %s", buf)
+	}
+	var dst record
+	if err := json.Unmarshal(buf, &dst); err != nil || dst != src {
+		t.Fatalf("JSON round trip failed: %v %v", dst, err)
+	}
+	if err := json.Unmarshal([]byte(`{"id":"f47ac10b-58cc-4372-a567-0e02b2c3d479"}`), &dst); err != nil || dst.ID != tag.FromUUID(uuid) {
+		t.Fatalf("JSON UUID failed: %v %v", dst, err)
+	}
+
+	// database/sql
+	val, _ := src.ID.Value()
+	var scanned tag.ID
+	if err := scanned.Scan(val); err != nil || scanned != src.ID {
+		t.Fatalf("sql round trip failed: %v %v", scanned, err)
+	}
+	if err := scanned.Scan(uuid[:]); err != nil || scanned != tag.FromUUID(uuid) {
+		t.Fatalf("sql UUID scan failed: %v %v", scanned, err)
+	}
+	if err := scanned.Scan(src.ID.Base32()); err != nil || scanned != src.ID {
+		t.Fatalf("sql string scan failed: %v %v", scanned, err)
+	}
+	if err := scanned.Scan(nil); err != nil || scanned.IsSet() {
+		t.Fatalf("sql NULL scan failed: %v %v", scanned, err)
+	}
+	if err := scanned.Scan(42); !errors.Is(err, tag.ErrInvalidID) {
+		t.Fatalf("expected ErrInvalidID, got %v", err)
+	}
+}
+
+func TestExpr(t *testing.T) {
+	music := tag.FormSpec(tag.Spec{}, "music")
+	tests := []struct {
+		expr    string
+		matches []string
+		misses  []string
+		norm    string
+	}{
+		{"music", []string{"music", ".music."}, []string{"music.jazz", "podcast"}, "music"},
+		{"music.*", []string{"music", "music.jazz", "music/jazz/bebop"}, []string{"musical", "podcast.music"}, "music.*"},
+		{"music.* & !music.podcast.*", []string{"music.jazz"}, []string{"music.podcast", "music.podcast.daily", "video"}, "music.* & !music.podcast.*"},
+		{"music.* & !podcast", []string{"music.podcast"}, []string{"podcast"}, "music.* & !podcast"},
+		{"a|b.*&!b.c", []string{"a", "b", "b.d"}, []string{"b.c", "c"}, "a | b.* & !b.c"},
+		{"(a | b) & !(a)", []string{"b"}, []string{"a", "c"}, "(a | b) & !a"},
+		{"!!a", []string{"a"}, []string{"b"}, "!!a"},
+		{"*", []string{"anything", "a.b.c", ""}, nil, "*"},
+	}
+	for _, test := range tests {
+		expr, err := tag.CompileExpr(test.expr)
+		if err != nil {
+			t.Fatalf("CompileExpr(%q) failed: %v", test.expr, err)
+		}
+		if str := expr.String(); str != test.norm {
+			t.Errorf("CompileExpr(%q).String() = %q", test.expr, str)
+		}
+		for _, tags := range test.matches {
+			if !expr.MatchTags(tags) {
+				t.Errorf("%q should match %q", test.expr, tags)
+			}
+		}
+		for _, tags := range test.misses {
+			if expr.MatchTags(tags) {
+				t.Errorf("%q should not match %q", test.expr, tags)
+			}
+		}
+	}
+
+	// Specs formed within a context match on their full canonic form
+	if !tag.MustCompileExpr("music.jazz.*").Match(tag.FormSpec(music, "jazz.bebop")) {
+		t.Error("Match() failed for a spec formed within a context")
+	}
+
+	for _, bad := range []string{"", "a &", "(a", "a)", "a.*.b", "mu*sic", "a | .", "&a"} {
+		if _, err := tag.CompileExpr(bad); !errors.Is(err, tag.ErrBadExpr) {
+			t.Errorf("expected ErrBadExpr for %q, got %v", bad, err)
+		}
+	}
+}
+
+func TestMonotonic(t *testing.T) {
+	gen := tag.NewGenerator(true)
+	start := time.Now()
+
+	var prev tag.ID
+	for i := 0; i < 1000; i++ {
+		at := start.Add(time.Duration(i) * time.Microsecond)
+		if i%10 == 5 {
+			at = start.Add(-time.Second) // clock regression
+		}
+		id := gen.NextAt(at)
+		if id.CompareTo(prev) <= 0 {
+			t.Fatalf("ID %d is not increasing: %v <= %v", i, id, prev)
+		}
+		prev = id
+	}
+	if n := gen.Regressions(); n != 100 {
+		t.Fatalf("expected 100 regressions, got %d", n)
+	}
+	if gen.Last() != prev {
+		t.Fatal("Generator.Last() failed")
+	}
+
+	prev = tag.Nil
+	for i := 0; i < 100000; i++ {
+		id := tag.NewMonotonic()
+		if id.CompareTo(prev) <= 0 {
+			t.Fatalf("tag.NewMonotonic() is not increasing: %v <= %v", id, prev)
+		}
+		prev = id
+	}
+
+	for i := 0; i < 1000; i++ {
+		when := time.Unix(int64(i)*7919-3000000, int64(i)*999983%1e9).UTC()
+		if got := tag.FromTime(when, true).Time(); !got.Equal(when) {
+			t.Fatalf("tag.ID.Time() failed: %v != %v", got, when)
+		}
+	}
+}
+
+func TestShortString(t *testing.T) {
+	ids := []tag.ID{tag.Nil, {0, 0, 1}, {0x3, 0x7777777777777777, 0x123456789abcdef0}, {^uint64(0), ^uint64(0), ^uint64(0)}, tag.New()}
+	for _, id := range ids {
+		short := id.ShortString()
+		parsed, err := tag.ParseID(short)
+		if err != nil || parsed != id {
+			t.Fatalf("tag.ParseID(%q) failed: %v %v", short, parsed, err)
+		}
+		if lower, err := tag.ParseID(strings.ToLower(short)); err != nil || lower != id {
+			t.Fatalf("tag.ParseID() is not case-insensitive: %v", err)
+		}
+		if parsed, err = tag.ParseID(id.Base16()); err != nil || parsed != id {
+			t.Fatalf("tag.ParseID(%q) failed: %v %v", id.Base16(), parsed, err)
+		}
+	}
+	if short := (tag.ID{}).ShortString(); len(short) != 3 {
+		t.Fatalf("unexpected short form for Nil: %q", short)
+	}
+	if short := ids[3].ShortString(); len(short) != 41 {
+		t.Fatalf("unexpected short form for max ID: %q", short)
+	}
+
+	// Single digit transcription errors are detected
+	short := []byte(ids[2].ShortString())
+	for i := range short {
+		orig := short[i]
+		short[i] = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"[(strings.IndexByte("0123456789ABCDEFGHJKMNPQRSTVWXYZ", orig)+1)%32]
+		if _, err := tag.ParseID(string(short)); !errors.Is(err, tag.ErrInvalidID) {
+			t.Fatalf("corrupted %q was not detected", short)
+		}
+		short[i] = orig
+	}
+	for _, bad := range []string{"", "0", "U00", "2" + ids[3].ShortString()[1:], ids[3].ShortString() + "0"} {
+		if _, err := tag.ParseID(bad); !errors.Is(err, tag.ErrInvalidID) {
+			t.Fatalf("expected ErrInvalidID for %q, got %v", bad, err)
+		}
+	}
+}
+
+func FuzzShortString(f *testing.F) {
+	f.Add(uint64(0), uint64(0), uint64(0))
+	f.Add(uint64(0x3), uint64(0x7777777777777777), uint64(0x123456789abcdef0))
+	f.Add(^uint64(0), ^uint64(0), ^uint64(0))
+	f.Fuzz(func(t *testing.T, x0, x1, x2 uint64) {
+		id := tag.ID{x0, x1, x2}
+		if parsed, err := tag.ParseID(id.ShortString()); err != nil || parsed != id {
+			t.Fatalf("short form round trip failed for %x: %v %v", id, parsed, err)
+		}
+		if parsed, err := tag.ParseID(id.Base16()); err != nil || parsed != id {
+			t.Fatalf("hex round trip failed for %x: %v %v", id, parsed, err)
+		}
+	})
+}
+
+func FuzzParseID(f *testing.F) {
+	f.Add("000")
+	f.Add(tag.ID{0x3, 0x7777777777777777, 0x123456789abcdef0}.ShortString())
+	f.Add("00000000000000037777777777777777123456789abcdef0")
+	f.Fuzz(func(t *testing.T, str string) {
+		id, err := tag.ParseID(str)
+		if err != nil {
+			return
+		}
+		if reparsed, err := tag.ParseID(id.ShortString()); err != nil || reparsed != id {
+			t.Fatalf("re-parse of %q failed: %v %v", str, reparsed, err)
+		}
+	})
+}
@@ -24,6 +24,48 @@ func Go(parent Context, label string, fn func(ctx Context)) (Context, error) {
 	})
 }
 
+// WithTimeout starts the given Task as a child of parent which is closed with ErrTimeout if it is still running after the given timeout.
+func WithTimeout(parent Context, timeout time.Duration, task *Task) (Context, error) {
+	t := *task
+	t.Timeout = timeout
+	return parent.StartChild(&t)
+}
+
+// WithIdleTimeout starts the given Task as a child of parent which is closed with ErrIdleTimeout if it does not call ReportActivity() within the given timeout.
+func WithIdleTimeout(parent Context, timeout time.Duration, task *Task) (Context, error) {
+	t := *task
+	t.IdleTimeout = timeout
+	return parent.StartChild(&t)
+}
+
+// Pather is optionally implemented by a Context that tracks the labels of its ancestors (see PathOf).
+type Pather interface {
+	// The labels of this Context and its ancestors, root first, joined by "/" -- attached to entries logged via this Context.
+	Path() string
+}
+
+// PathOf returns the given Context's Path() if it implements Pather, or else its Label().
+func PathOf(ctx Context) string {
+	if p, ok := ctx.(Pather); ok {
+		return p.Path()
+	}
+	return ctx.Label()
+}
+
+// ActivityReporter is optionally implemented by a Context that honors Task.IdleTimeout (see ReportActivity).
+type ActivityReporter interface {
+	// Signals that this Context is making progress, resetting its Task.IdleTimeout (if set).
+	ReportActivity()
+}
+
+// ReportActivity signals that the given Context is making progress, resetting its Task.IdleTimeout (if set).
+// This has no effect on a Context that does not implement ActivityReporter.
+func ReportActivity(ctx Context) {
+	if r, ok := ctx.(ActivityReporter); ok {
+		r.ReportActivity()
+	}
+}
+
 // Task is a parameter block used to start a new Context and contains hooks for each stage of the Context's lifecycle.
 type Task struct {
 
@@ -32,6 +74,18 @@ type Task struct {
 	// This will not enter into effect unless OnRun is given or a child is started.
 	IdleClose time.Duration
 
+	// If > 0, the Context is closed with ErrTimeout once this much time has passed since it was started.
+	Timeout time.Duration
+
+	// If > 0, the Context is closed with ErrIdleTimeout if ReportActivity() is not called for this long.
+	IdleTimeout time.Duration
+
+	// When the parent Context closes, this Context is not closed until each of these (sibling) Contexts is done.
+	// For example, a symbol table can be listed to close after the task that accepts new sessions has fully closed.
+	//
+	// Since only already started Contexts can be listed, dependency cycles are not possible.
+	CloseAfter []Context
+
 	ID             tag.ID                  // ID is a universally unique identifier -- auto assigned if nil
 	TaskRef        any                     // TaskRef is offered for open-ended use
 	Label          string                  // Label is used for logging and debugging
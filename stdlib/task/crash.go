@@ -0,0 +1,101 @@
+package task
+
+import (
+	"fmt"
+	"runtime/debug"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// CrashReport describes a panic that occurred within a Context's Task.OnRun (or a job run by Supervise or a WorkerPool).
+//
+// CrashReport is also an error that wraps ErrPanicked.
+type CrashReport struct {
+	Panic      any       // Value passed to panic()
+	Stack      []byte    // Stack trace of the panicking goroutine
+	TaskPath   []string  // Labels of the panicking Context and its ancestors, root first
+	ContextID  int64     // ContextID() of the panicking Context
+	StartedAt  time.Time // When the panicking Context was started
+	PanickedAt time.Time // When the panic was recovered
+}
+
+// CrashHandler receives a CrashReport for each panic recovered within a Context.
+// It is called on the panicking goroutine and should not block for long.
+type CrashHandler func(report *CrashReport)
+
+var gCrashHandler atomic.Value // CrashHandler
+
+// SetCrashHandler registers the given func to receive all subsequent crash reports (or nil to unregister),
+// allowing a host to report crashes to an external service or write a crash file.
+//
+// A panic in Task.OnRun is re-raised once the CrashHandler returns, while a panic in a job run by Supervise or a WorkerPool
+// is contained and reported as that job's error.
+func SetCrashHandler(handler CrashHandler) {
+	gCrashHandler.Store(handler)
+}
+
+func (report *CrashReport) Error() string {
+	return fmt.Sprintf("%v in %s: %v", ErrPanicked, strings.Join(report.TaskPath, "/"), report.Panic)
+}
+
+func (report *CrashReport) Unwrap() error {
+	return ErrPanicked
+}
+
+// Uptime returns how long the panicking Context had been running.
+func (report *CrashReport) Uptime() time.Duration {
+	return report.PanickedAt.Sub(report.StartedAt)
+}
+
+func newCrashReport(c Context, recovered any) *CrashReport {
+	report := &CrashReport{
+		Panic:      recovered,
+		Stack:      debug.Stack(),
+		ContextID:  c.ContextID(),
+		PanickedAt: time.Now(),
+	}
+	if p, ok := c.(*ctx); ok {
+		report.StartedAt = p.startedAt
+		for ; p != nil; p = p.parent {
+			report.TaskPath = append(report.TaskPath, p.task.Label)
+		}
+		for i, j := 0, len(report.TaskPath)-1; i < j; i, j = i+1, j-1 {
+			report.TaskPath[i], report.TaskPath[j] = report.TaskPath[j], report.TaskPath[i]
+		}
+	} else {
+		report.TaskPath = []string{c.Label()}
+	}
+	return report
+}
+
+// deliverCrash logs the given report and passes it to the registered CrashHandler (if any).
+func deliverCrash(c Context, report *CrashReport) {
+	c.Errorf("%v\n%s", report, report.Stack)
+	if handler, _ := gCrashHandler.Load().(CrashHandler); handler != nil {
+		handler(report)
+	}
+}
+
+// run calls Task.OnRun, delivering a CrashReport before re-raising any panic.
+func (p *ctx) run() {
+	defer func() {
+		if r := recover(); r != nil {
+			deliverCrash(p, newCrashReport(p, r))
+			panic(r)
+		}
+	}()
+	p.task.OnRun(p)
+}
+
+// runAndRecover calls fn, converting a panic into a *CrashReport error (which wraps ErrPanicked).
+func runAndRecover(c Context, fn func(ctx Context) error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			report := newCrashReport(c, r)
+			deliverCrash(c, report)
+			err = report
+		}
+	}()
+	return fn(c)
+}
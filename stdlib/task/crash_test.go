@@ -0,0 +1,49 @@
+package task_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/task"
+)
+
+func TestCrashReport(t *testing.T) {
+	chReport := make(chan *task.CrashReport, 1)
+	task.SetCrashHandler(func(report *task.CrashReport) {
+		chReport <- report
+	})
+	defer task.SetCrashHandler(nil)
+
+	root, _ := task.Start(&task.Task{
+		Label: "root",
+	})
+	defer root.Close()
+
+	pool, err := task.StartWorkerPool(root, task.WorkerPoolOpts{
+		Label: "pool",
+	})
+	require.NoError(t, err)
+	defer pool.Close()
+
+	job, err := pool.Submit(context.Background(), "explode", func(ctx task.Context) error {
+		panic("kaboom")
+	})
+	require.NoError(t, err)
+	<-job.Done()
+
+	report := <-chReport
+	require.Equal(t, "kaboom", report.Panic)
+	require.Equal(t, []string{"root", "pool", "worker 1", "explode"}, report.TaskPath)
+	require.True(t, strings.Contains(string(report.Stack), "crash_test.go"))
+	require.False(t, report.StartedAt.IsZero())
+	require.True(t, report.Uptime() >= 0)
+
+	var jobReport *task.CrashReport
+	require.True(t, errors.As(job.Err(), &jobReport))
+	require.Equal(t, report, jobReport)
+	require.ErrorIs(t, job.Err(), task.ErrPanicked)
+}
@@ -0,0 +1,80 @@
+// Package otel adapts an OpenTelemetry trace.TracerProvider to task.TracerProvider, so that each task.Context is traced as an
+// OpenTelemetry span whose parent is the span of its parent Context.
+//
+// It is a separate module so that hosts not tracing tasks do not depend on OpenTelemetry.
+package otel
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/task"
+)
+
+// TracerName is the instrumentation name of the tracer that task spans are started with.
+const TracerName = "github.com/amp-3d/amp-sdk-go/stdlib/task"
+
+// Span attribute keys
+const (
+	LabelKey   = attribute.Key("task.label")
+	IDKey      = attribute.Key("task.id")
+	OutcomeKey = attribute.Key("task.outcome") // "ok", "timeout", or "error"
+)
+
+// NewTracerProvider returns a task.TracerProvider starting spans using the given OpenTelemetry TracerProvider.
+//
+// A root task.Context's span is a root span -- spans of child Contexts are its descendants, mirroring the task tree.
+// Pass the result to task.SetTracerProvider().
+func NewTracerProvider(tp trace.TracerProvider) task.TracerProvider {
+	return &tracerProvider{
+		tracer: tp.Tracer(TracerName),
+	}
+}
+
+type tracerProvider struct {
+	tracer trace.Tracer
+}
+
+func (tp *tracerProvider) StartTaskSpan(parent task.Span, c task.Context) task.Span {
+	ctx := context.Background()
+	if p, ok := parent.(*span); ok {
+		ctx = p.ctx
+	}
+	ctx, s := tp.tracer.Start(ctx, c.Label(), trace.WithAttributes(
+		LabelKey.String(c.Label()),
+		IDKey.String(c.ID().String()),
+	))
+	return &span{
+		ctx:  ctx,
+		span: s,
+	}
+}
+
+// span implements task.Span using an OpenTelemetry span.
+type span struct {
+	ctx  context.Context // carries span, for starting child spans
+	span trace.Span
+}
+
+func (s *span) AddEvent(name string) {
+	s.span.AddEvent(name)
+}
+
+func (s *span) End(err error) {
+	switch {
+	case err == nil:
+		s.span.SetAttributes(OutcomeKey.String("ok"))
+	case errors.Is(err, task.ErrTimeout):
+		s.span.SetAttributes(OutcomeKey.String("timeout"))
+		s.span.SetStatus(codes.Error, err.Error())
+	default:
+		s.span.SetAttributes(OutcomeKey.String("error"))
+		s.span.RecordError(err)
+		s.span.SetStatus(codes.Error, err.Error())
+	}
+	s.span.End()
+}
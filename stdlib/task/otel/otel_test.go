@@ -0,0 +1,64 @@
+package otel_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/task"
+	"github.com/amp-3d/amp-sdk-go/stdlib/task/otel"
+)
+
+func TestTracerProvider(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	task.SetTracerProvider(otel.NewTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))))
+	defer task.SetTracerProvider(nil)
+
+	root, err := task.Start(&task.Task{
+		Label: "root",
+	})
+	require.NoError(t, err)
+	child, err := root.StartChild(&task.Task{
+		Label:   "child",
+		Timeout: time.Millisecond,
+	})
+	require.NoError(t, err)
+	<-child.Done()
+	root.Close()
+	<-root.Done()
+
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = span
+	}
+	rootSpan, childSpan := spans["root"], spans["child"]
+	require.NotNil(t, rootSpan)
+	require.NotNil(t, childSpan)
+
+	require.False(t, rootSpan.Parent().IsValid())
+	require.Equal(t, rootSpan.SpanContext().TraceID(), childSpan.SpanContext().TraceID())
+	require.Equal(t, rootSpan.SpanContext().SpanID(), childSpan.Parent().SpanID())
+
+	attr := func(span sdktrace.ReadOnlySpan, key attribute.Key) string {
+		for _, kv := range span.Attributes() {
+			if kv.Key == key {
+				return kv.Value.AsString()
+			}
+		}
+		return ""
+	}
+	require.Equal(t, "child", attr(childSpan, otel.LabelKey))
+	require.Equal(t, child.ID().String(), attr(childSpan, otel.IDKey))
+	require.Equal(t, "timeout", attr(childSpan, otel.OutcomeKey))
+	require.Equal(t, codes.Error, childSpan.Status().Code)
+	require.Equal(t, "ok", attr(rootSpan, otel.OutcomeKey))
+	require.Equal(t, codes.Unset, rootSpan.Status().Code)
+
+	require.Len(t, childSpan.Events(), 1)
+	require.Equal(t, "closing", childSpan.Events()[0].Name)
+}
@@ -0,0 +1,240 @@
+package task
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Timetable yields successive times at which a scheduled job is to run.
+type Timetable interface {
+
+	// Returns the first scheduled time after the given time, or the zero Time if there are none.
+	Next(after time.Time) time.Time
+}
+
+// Every returns a Timetable that fires at a fixed interval.
+func Every(interval time.Duration) Timetable {
+	return everyTimetable(interval)
+}
+
+type everyTimetable time.Duration
+
+func (interval everyTimetable) Next(after time.Time) time.Time {
+	if interval <= 0 {
+		return time.Time{}
+	}
+	return after.Add(time.Duration(interval))
+}
+
+// ParseTimetable parses a schedule spec, which is either:
+//   - a standard 5-field cron expression: "minute hour day-of-month month day-of-week", where each field is "*", a value, a range "a-b", a list "a,b,c", or any of these with a step "/n"
+//   - "@every <duration>", e.g. "@every 1m30s"
+//   - one of "@yearly", "@annually", "@monthly", "@weekly", "@daily", "@midnight", "@hourly"
+//
+// Cron times are evaluated in the location of the time passed to Next().
+func ParseTimetable(spec string) (Timetable, error) {
+	spec = strings.TrimSpace(spec)
+
+	if strings.HasPrefix(spec, "@every ") {
+		interval, err := time.ParseDuration(strings.TrimSpace(spec[len("@every "):]))
+		if err != nil {
+			return nil, fmt.Errorf("bad schedule %q: %w", spec, err)
+		}
+		if interval <= 0 {
+			return nil, fmt.Errorf("bad schedule %q: interval must be > 0", spec)
+		}
+		return Every(interval), nil
+	}
+
+	switch spec {
+	case "@yearly", "@annually":
+		spec = "0 0 1 1 *"
+	case "@monthly":
+		spec = "0 0 1 * *"
+	case "@weekly":
+		spec = "0 0 * * 0"
+	case "@daily", "@midnight":
+		spec = "0 0 * * *"
+	case "@hourly":
+		spec = "0 * * * *"
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("bad schedule %q: expected 5 fields", spec)
+	}
+
+	cron := &cronTimetable{}
+	var err error
+	if cron.minute, err = parseCronField(fields[0], 0, 59); err != nil {
+		return nil, fmt.Errorf("bad schedule %q: minute: %w", spec, err)
+	}
+	if cron.hour, err = parseCronField(fields[1], 0, 23); err != nil {
+		return nil, fmt.Errorf("bad schedule %q: hour: %w", spec, err)
+	}
+	if cron.dom, err = parseCronField(fields[2], 1, 31); err != nil {
+		return nil, fmt.Errorf("bad schedule %q: day of month: %w", spec, err)
+	}
+	if cron.month, err = parseCronField(fields[3], 1, 12); err != nil {
+		return nil, fmt.Errorf("bad schedule %q: month: %w", spec, err)
+	}
+	if cron.dow, err = parseCronField(fields[4], 0, 7); err != nil {
+		return nil, fmt.Errorf("bad schedule %q: day of week: %w", spec, err)
+	}
+	if cron.dow&(1<<7) != 0 {
+		cron.dow |= 1 // 7 is also Sunday
+	}
+	cron.domAny = fields[2] == "*"
+	cron.dowAny = fields[4] == "*"
+	return cron, nil
+}
+
+// cronTimetable holds a bit set of allowed values for each cron field.
+type cronTimetable struct {
+	minute, hour, dom, month, dow uint64
+	domAny, dowAny                bool
+}
+
+func parseCronField(field string, lo, hi int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if i := strings.IndexByte(part, '/'); i >= 0 {
+			var err error
+			if step, err = strconv.Atoi(part[i+1:]); err != nil || step <= 0 {
+				return 0, fmt.Errorf("bad step in %q", part)
+			}
+			part = part[:i]
+		}
+
+		from, to := lo, hi
+		if part != "*" {
+			bounds := strings.SplitN(part, "-", 2)
+			var err error
+			if from, err = strconv.Atoi(bounds[0]); err != nil {
+				return 0, fmt.Errorf("bad value %q", part)
+			}
+			to = from
+			if len(bounds) == 2 {
+				if to, err = strconv.Atoi(bounds[1]); err != nil {
+					return 0, fmt.Errorf("bad value %q", part)
+				}
+			} else if step > 1 {
+				to = hi // "a/n" means from a through the max
+			}
+		}
+		if from < lo || to > hi || from > to {
+			return 0, fmt.Errorf("%q is out of range %d-%d", part, lo, hi)
+		}
+		for v := from; v <= to; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+func (cron *cronTimetable) dayMatches(t time.Time) bool {
+	domMatch := cron.dom&(1<<uint(t.Day())) != 0
+	dowMatch := cron.dow&(1<<uint(t.Weekday())) != 0
+
+	// Per cron convention, if both day fields are restricted, either may match.
+	if !cron.domAny && !cron.dowAny {
+		return domMatch || dowMatch
+	}
+	return domMatch && dowMatch
+}
+
+func (cron *cronTimetable) Next(after time.Time) time.Time {
+	loc := after.Location()
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if cron.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !cron.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if cron.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+		if cron.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// Schedule starts a child Context of parent that calls fn at each time given by the schedule spec (see ParseTimetable).
+//
+// Each run occurs in its own child Context so it appears in the Context tree and is closed if the scheduler is closed.
+// Runs do not overlap: if a run is still in progress when the next time arrives, that time is skipped.
+// Closing the returned Context cancels all future runs.
+func Schedule(parent Context, spec string, fn func(ctx Context)) (Context, error) {
+	timetable, err := ParseTimetable(spec)
+	if err != nil {
+		return nil, err
+	}
+	return ScheduleTimetable(parent, spec, timetable, fn)
+}
+
+// ScheduleTimetable is similar to Schedule() but accepts an arbitrary Timetable.
+func ScheduleTimetable(parent Context, label string, timetable Timetable, fn func(ctx Context)) (Context, error) {
+	return parent.StartChild(&Task{
+		Label: label,
+		OnRun: func(sched Context) {
+			runScheduled(sched, timetable, fn)
+		},
+	})
+}
+
+func runScheduled(sched Context, timetable Timetable, fn func(ctx Context)) {
+	var timer *time.Timer
+	defer func() {
+		if timer != nil {
+			timer.Stop()
+		}
+	}()
+
+	for run := 1; ; run++ {
+		now := time.Now()
+		next := timetable.Next(now)
+		if next.IsZero() {
+			sched.Close()
+			return
+		}
+
+		if timer == nil {
+			timer = time.NewTimer(next.Sub(now))
+		} else {
+			timer.Reset(next.Sub(now))
+		}
+		select {
+		case <-timer.C:
+		case <-sched.Closing():
+			return
+		}
+
+		child, err := sched.StartChild(&Task{
+			Label:     fmt.Sprintf("%s#%d", sched.Label(), run),
+			IdleClose: time.Nanosecond,
+			OnRun:     fn,
+		})
+		if err != nil {
+			return
+		}
+		select {
+		case <-child.Done():
+		case <-sched.Closing():
+			return
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package task_test
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/task"
+)
+
+func TestParseTimetable(t *testing.T) {
+	at := func(s string) time.Time {
+		t, err := time.Parse("2006-01-02 15:04", s)
+		if err != nil {
+			panic(err)
+		}
+		return t
+	}
+
+	cases := []struct {
+		spec  string
+		after string
+		next  string
+	}{
+		{"*/15 * * * *", "2026-03-04 10:07", "2026-03-04 10:15"},
+		{"0 * * * *", "2026-03-04 10:00", "2026-03-04 11:00"},
+		{"30 2 * * *", "2026-03-04 10:00", "2026-03-05 02:30"},
+		{"0 9 * * 1-5", "2026-03-06 10:00", "2026-03-09 09:00"}, // Fri -> Mon
+		{"0 0 1 */3 *", "2026-02-10 00:00", "2026-04-01 00:00"},
+		{"0 0 29 2 *", "2026-01-01 00:00", "2028-02-29 00:00"},
+		{"0 12 13 * 5", "2026-03-01 00:00", "2026-03-06 12:00"}, // either day field may match
+		{"@daily", "2026-12-31 23:59", "2027-01-01 00:00"},
+		{"@weekly", "2026-03-04 00:00", "2026-03-08 00:00"},
+		{"0 0 * * 7", "2026-03-04 00:00", "2026-03-08 00:00"},
+	}
+	for _, c := range cases {
+		timetable, err := task.ParseTimetable(c.spec)
+		require.NoError(t, err, c.spec)
+		require.Equal(t, at(c.next), timetable.Next(at(c.after)), c.spec)
+	}
+
+	every, err := task.ParseTimetable("@every 90s")
+	require.NoError(t, err)
+	require.Equal(t, at("2026-03-04 10:01").Add(30*time.Second), every.Next(at("2026-03-04 10:00")))
+
+	for _, bad := range []string{"", "* * * *", "60 * * * *", "* * 0 * *", "*/0 * * * *", "a * * * *", "@every nope", "5-1 * * * *"} {
+		_, err := task.ParseTimetable(bad)
+		require.Error(t, err, bad)
+	}
+}
+
+func TestSchedule(t *testing.T) {
+	root, _ := task.Start(&task.Task{
+		Label: "root",
+	})
+	defer root.Close()
+
+	var runs atomic.Int32
+	sched, err := task.Schedule(root, "@every 10ms", func(ctx task.Context) {
+		runs.Add(1)
+	})
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		return runs.Load() >= 3
+	}, 5*time.Second, 5*time.Millisecond)
+
+	sched.Close()
+	select {
+	case <-sched.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("scheduler didn't close")
+	}
+	n := runs.Load()
+	time.Sleep(30 * time.Millisecond)
+	require.Equal(t, n, runs.Load())
+}
@@ -0,0 +1,213 @@
+package task
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Semaphore bounds the concurrent use of a resource (e.g. scans or transcodes), where each holder acquires a weight
+// of the semaphore's size.  Waiters acquire in the order they arrive, so a heavy waiter is not starved by light ones.
+type Semaphore struct {
+	size    int64
+	mu      sync.Mutex
+	held    int64
+	waiters []*semWaiter // oldest first
+}
+
+type semWaiter struct {
+	n     int64
+	ready chan struct{} // closed once n is acquired
+}
+
+// NewSemaphore returns a Semaphore allowing holders of up to the given total weight at once.
+func NewSemaphore(size int64) *Semaphore {
+	return &Semaphore{
+		size: size,
+	}
+}
+
+// Acquire blocks until a weight of n is acquired, which the caller then returns via Release.
+//
+// Returns ErrClosed if ctx is a Context that starts closing first, or ctx.Err() if ctx is otherwise done first.
+func (sem *Semaphore) Acquire(ctx context.Context, n int64) error {
+	if n > sem.size {
+		return fmt.Errorf("task: acquiring %d of a semaphore of size %d", n, sem.size)
+	}
+
+	sem.mu.Lock()
+	if len(sem.waiters) == 0 && sem.held+n <= sem.size {
+		sem.held += n
+		sem.mu.Unlock()
+		return nil
+	}
+	waiter := &semWaiter{n: n, ready: make(chan struct{})}
+	sem.waiters = append(sem.waiters, waiter)
+	sem.mu.Unlock()
+
+	closing, closedErr := closingOf(ctx)
+	select {
+	case <-waiter.ready:
+		return nil
+	case <-closing:
+	}
+
+	sem.mu.Lock()
+	defer sem.mu.Unlock()
+	select {
+	case <-waiter.ready:
+		return nil // acquired while giving up
+	default:
+	}
+	for i, w := range sem.waiters {
+		if w == waiter {
+			sem.waiters = append(sem.waiters[:i], sem.waiters[i+1:]...)
+			break
+		}
+	}
+	sem.wakeLocked() // those behind this waiter may now fit
+	if closedErr != nil {
+		return closedErr
+	}
+	return ctx.Err()
+}
+
+// TryAcquire acquires a weight of n if it is available without waiting, returning false if not.
+func (sem *Semaphore) TryAcquire(n int64) bool {
+	sem.mu.Lock()
+	defer sem.mu.Unlock()
+	if len(sem.waiters) == 0 && sem.held+n <= sem.size {
+		sem.held += n
+		return true
+	}
+	return false
+}
+
+// Release returns a weight of n previously acquired.
+func (sem *Semaphore) Release(n int64) {
+	sem.mu.Lock()
+	defer sem.mu.Unlock()
+	sem.held -= n
+	if sem.held < 0 {
+		panic("task: semaphore released more than acquired")
+	}
+	sem.wakeLocked()
+}
+
+// wakeLocked grants waiters their weight in order while it is available -- sem.mu must be locked.
+func (sem *Semaphore) wakeLocked() {
+	for len(sem.waiters) > 0 {
+		waiter := sem.waiters[0]
+		if sem.held+waiter.n > sem.size {
+			return
+		}
+		sem.held += waiter.n
+		sem.waiters[0] = nil
+		sem.waiters = sem.waiters[1:]
+		close(waiter.ready)
+	}
+}
+
+// closingOf returns a channel signaled once the given context should be abandoned, along with the error to report then
+// -- for a Context, this is when it starts closing (rather than when it is done closing).
+func closingOf(ctx context.Context) (<-chan struct{}, error) {
+	if taskCtx, ok := ctx.(Context); ok {
+		return taskCtx.Closing(), ErrClosed
+	}
+	return ctx.Done(), nil
+}
+
+// Group runs a set of functions, each in its own child Context of the group's Context, where the first to fail closes
+// the group's Context (and so the rest of the group), like errgroup.Group.
+type Group struct {
+	ctx     Context
+	sem     *Semaphore // nil if unlimited
+	running sync.WaitGroup
+
+	mu      sync.Mutex
+	err     error // first error returned by a function
+	skipped bool  // set if a function was not run because the group was closing
+}
+
+// StartGroup starts a Group whose Context is a child of the given parent, running at most limit functions at once (or
+// any number if limit <= 0).
+func StartGroup(parent Context, label string, limit int) (*Group, error) {
+	ctx, err := parent.StartChild(&Task{
+		Label: label,
+	})
+	if err != nil {
+		return nil, err
+	}
+	g := &Group{
+		ctx: ctx,
+	}
+	if limit > 0 {
+		g.sem = NewSemaphore(int64(limit))
+	}
+	return g, nil
+}
+
+// Context returns the Context of this group, which closes once a function fails, Wait returns, or its parent closes.
+func (g *Group) Context() Context {
+	return g.ctx
+}
+
+// Go runs the given function in a new child Context of this group, first waiting for a slot if the group's limit is reached.
+// If the group is closing, the function is not run.
+func (g *Group) Go(label string, fn func(ctx Context) error) {
+	g.running.Add(1)
+	if g.sem != nil {
+		if err := g.sem.Acquire(g.ctx, 1); err != nil {
+			g.skip()
+			return
+		}
+	}
+	_, err := g.ctx.Go(label, func(ctx Context) {
+		defer g.running.Done()
+		if g.sem != nil {
+			defer g.sem.Release(1)
+		}
+		if err := fn(ctx); err != nil {
+			g.fail(err)
+		}
+	})
+	if err != nil {
+		if g.sem != nil {
+			g.sem.Release(1)
+		}
+		g.skip()
+	}
+}
+
+// Wait blocks until every function run via Go has returned, then closes the group's Context.
+// Returns the first error returned by a function, or ErrClosed if the group closed before every function could run.
+func (g *Group) Wait() error {
+	g.running.Wait()
+	g.ctx.Close()
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.err == nil && g.skipped {
+		return ErrClosed
+	}
+	return g.err
+}
+
+func (g *Group) fail(err error) {
+	g.mu.Lock()
+	first := g.err == nil
+	if first {
+		g.err = err
+	}
+	g.mu.Unlock()
+	if first {
+		g.ctx.Close()
+	}
+}
+
+func (g *Group) skip() {
+	g.mu.Lock()
+	g.skipped = true
+	g.mu.Unlock()
+	g.running.Done()
+}
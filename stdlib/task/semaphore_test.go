@@ -0,0 +1,121 @@
+package task_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/task"
+)
+
+func TestSemaphore(t *testing.T) {
+	sem := task.NewSemaphore(3)
+	ctx := context.Background()
+	require.NoError(t, sem.Acquire(ctx, 2))
+	require.True(t, sem.TryAcquire(1))
+	require.False(t, sem.TryAcquire(1))
+	require.Error(t, sem.Acquire(ctx, 4))
+
+	// Waiters acquire in the order they arrive
+	acquired := make(chan int64, 2)
+	go func() {
+		require.NoError(t, sem.Acquire(ctx, 3))
+		acquired <- 3
+	}()
+	time.Sleep(10 * time.Millisecond)
+	go func() {
+		require.NoError(t, sem.Acquire(ctx, 1))
+		acquired <- 1
+	}()
+	time.Sleep(10 * time.Millisecond)
+	require.False(t, sem.TryAcquire(1), "a waiter is ahead")
+	sem.Release(1)
+	sem.Release(2)
+	require.EqualValues(t, 3, <-acquired)
+	sem.Release(3)
+	require.EqualValues(t, 1, <-acquired)
+	sem.Release(1)
+
+	// Acquiring aborts once a Context starts closing, or a context.Context is done
+	root, err := task.Start(&task.Task{Label: "scans"})
+	require.NoError(t, err)
+	require.NoError(t, sem.Acquire(root, 3))
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		root.Close()
+	}()
+	require.ErrorIs(t, sem.Acquire(root, 1), task.ErrClosed)
+	timeout, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, sem.Acquire(timeout, 1), context.DeadlineExceeded)
+	sem.Release(3)
+	require.True(t, sem.TryAcquire(3))
+}
+
+func TestGroup(t *testing.T) {
+	root, err := task.Start(&task.Task{Label: "root"})
+	require.NoError(t, err)
+	defer root.Close()
+
+	// Functions run at most limit at once
+	g, err := task.StartGroup(root, "transcodes", 2)
+	require.NoError(t, err)
+	var running, most atomic.Int32
+	for i := 0; i < 6; i++ {
+		g.Go("transcode", func(ctx task.Context) error {
+			n := running.Add(1)
+			for {
+				prev := most.Load()
+				if n <= prev || most.CompareAndSwap(prev, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			running.Add(-1)
+			return nil
+		})
+	}
+	require.NoError(t, g.Wait())
+	require.EqualValues(t, 2, most.Load())
+	select {
+	case <-g.Context().Closing():
+	default:
+		t.Fatal("group context not closed")
+	}
+
+	// The first failure closes the rest of the group
+	g, err = task.StartGroup(root, "scans", 0)
+	require.NoError(t, err)
+	failure := errors.New("disk unreadable")
+	var cancelled atomic.Int32
+	for i := 0; i < 3; i++ {
+		g.Go("scan", func(ctx task.Context) error {
+			select {
+			case <-ctx.Closing():
+				cancelled.Add(1)
+				return task.ErrClosed
+			case <-time.After(5 * time.Second):
+				return nil
+			}
+		})
+	}
+	g.Go("bad scan", func(ctx task.Context) error {
+		return failure
+	})
+	require.Equal(t, failure, g.Wait())
+	require.EqualValues(t, 3, cancelled.Load())
+
+	// Functions are not run once the group is closing
+	g, err = task.StartGroup(root, "late", 1)
+	require.NoError(t, err)
+	g.Context().Close()
+	g.Go("never", func(ctx task.Context) error {
+		t.Error("ran in a closed group")
+		return nil
+	})
+	require.ErrorIs(t, g.Wait(), task.ErrClosed)
+}
@@ -0,0 +1,67 @@
+package task
+
+import (
+	"os"
+	"os/signal"
+	"time"
+)
+
+// DefaultGracePeriod is the grace period used by RootWithSignals().
+var DefaultGracePeriod = 10 * time.Second
+
+// RootWithSignals starts a root Context that closes gracefully upon the first of the given signals -- see StartWithSignals().
+func RootWithSignals(sigs ...os.Signal) Context {
+	root, _ := StartWithSignals(&Task{Label: "root"}, DefaultGracePeriod, sigs...)
+	return root
+}
+
+// StartWithSignals starts a new root Context for the given Task and wires the given OS signals into it:
+//   - the first signal calls CloseWhenIdle(), allowing children to finish, and Close() is forced if still open after gracePeriod
+//   - a second signal forces an immediate Close()
+//
+// If gracePeriod <= 0, the first signal waits indefinitely for the Context to become idle.
+func StartWithSignals(task *Task, gracePeriod time.Duration, sigs ...os.Signal) (Context, error) {
+	root, err := Start(task)
+	if err != nil {
+		return nil, err
+	}
+
+	chSignal := make(chan os.Signal, 2)
+	signal.Notify(chSignal, sigs...)
+
+	go func() {
+		defer signal.Stop(chSignal)
+
+		var chGraceOver <-chan time.Time
+		for signaled := false; ; {
+			select {
+			case sig := <-chSignal:
+				if signaled {
+					root.Warnf("received %v again, closing now", sig)
+					root.Close()
+					return
+				}
+				signaled = true
+				if gracePeriod > 0 {
+					root.Warnf("received %v, closing when idle (grace period %v)", sig, gracePeriod)
+					timer := time.NewTimer(gracePeriod)
+					defer timer.Stop()
+					chGraceOver = timer.C
+				} else {
+					root.Warnf("received %v, closing when idle", sig)
+				}
+				root.CloseWhenIdle(time.Nanosecond)
+
+			case <-chGraceOver:
+				root.Warnf("grace period of %v elapsed, closing now", gracePeriod)
+				root.Close()
+				return
+
+			case <-root.Closing():
+				return
+			}
+		}
+	}()
+
+	return root, nil
+}
@@ -0,0 +1,77 @@
+//go:build !windows
+
+package task_test
+
+import (
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/task"
+)
+
+func TestStartWithSignals(t *testing.T) {
+	raise := func() {
+		require.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGUSR1))
+	}
+	awaitDone := func(root task.Context) {
+		select {
+		case <-root.Done():
+		case <-time.After(5 * time.Second):
+			t.Fatal("root didn't close")
+		}
+	}
+
+	t.Run("graceful", func(t *testing.T) {
+		root, err := task.StartWithSignals(&task.Task{Label: "root"}, time.Minute, syscall.SIGUSR1)
+		require.NoError(t, err)
+
+		chFinished := make(chan struct{})
+		root.Go("worker", func(ctx task.Context) {
+			time.Sleep(50 * time.Millisecond)
+			close(chFinished)
+		})
+
+		raise()
+		awaitDone(root)
+		select {
+		case <-chFinished:
+		default:
+			t.Fatal("child didn't finish before root closed")
+		}
+	})
+
+	t.Run("second signal forces close", func(t *testing.T) {
+		root, err := task.StartWithSignals(&task.Task{Label: "root"}, time.Minute, syscall.SIGUSR1)
+		require.NoError(t, err)
+
+		root.Go("stuck", func(ctx task.Context) {
+			<-ctx.Closing()
+		})
+
+		raise()
+		time.Sleep(20 * time.Millisecond)
+		select {
+		case <-root.Closing():
+			t.Fatal("root closed while child busy")
+		default:
+		}
+		raise()
+		awaitDone(root)
+	})
+
+	t.Run("grace period", func(t *testing.T) {
+		root, err := task.StartWithSignals(&task.Task{Label: "root"}, 20*time.Millisecond, syscall.SIGUSR1)
+		require.NoError(t, err)
+
+		root.Go("stuck", func(ctx task.Context) {
+			<-ctx.Closing()
+		})
+
+		raise()
+		awaitDone(root)
+	})
+}
@@ -0,0 +1,110 @@
+package task
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// Snapshot is a point-in-time description of a Context and its children, suitable for serializing to JSON.
+type Snapshot struct {
+	ContextID   int64      `json:"context_id"`
+	ID          string     `json:"id"`
+	Label       string     `json:"label"`
+	State       string     `json:"state"`
+	StartedAt   time.Time  `json:"started_at"`
+	NumChildren int        `json:"num_children"`
+	Children    []Snapshot `json:"children,omitempty"`
+}
+
+// StateName returns a human-readable name for the given Context state (e.g. Running).
+func StateName(state int32) string {
+	switch state {
+	case Unstarted:
+		return "unstarted"
+	case Running:
+		return "running"
+	case Closing:
+		return "closing"
+	case Closed:
+		return "closed"
+	default:
+		return "unknown"
+	}
+}
+
+// Snapshotter is optionally implemented by a Context that can describe itself and its children (see SnapshotOf).
+type Snapshotter interface {
+	// Returns a point-in-time snapshot of this Context and its children (recursively), suitable for serializing to JSON.
+	Snapshot() Snapshot
+}
+
+// SnapshotOf returns a point-in-time snapshot of the given Context and its children (recursively), suitable for serializing to JSON.
+// A Context that does not implement Snapshotter (e.g. a type embedding a Context) is described via its Context methods alone.
+func SnapshotOf(c Context) Snapshot {
+	if s, ok := c.(Snapshotter); ok {
+		return s.Snapshot()
+	}
+
+	state := Running
+	select {
+	case <-c.Done():
+		state = Closed
+	case <-c.Closing():
+		state = Closing
+	default:
+	}
+	return snapshot(Snapshot{
+		ContextID: c.ContextID(),
+		ID:        c.ID().String(),
+		Label:     c.Label(),
+		State:     StateName(state),
+	}, c)
+}
+
+func (p *ctx) Snapshot() Snapshot {
+	return snapshot(Snapshot{
+		ContextID: p.id,
+		ID:        p.task.ID.String(),
+		Label:     p.task.Label,
+		State:     StateName(atomic.LoadInt32(&p.state)),
+		StartedAt: p.startedAt,
+	}, p)
+}
+
+// snapshot fills in the children of the given Context's snapshot.
+func snapshot(snap Snapshot, c Context) Snapshot {
+	var subBuf [20]Context
+	children := c.GetChildren(subBuf[:0])
+
+	snap.NumChildren = len(children)
+	if len(children) > 0 {
+		snap.Children = make([]Snapshot, len(children))
+		for i, ci := range children {
+			snap.Children[i] = SnapshotOf(ci)
+		}
+	}
+	return snap
+}
+
+// DebugHandler returns an http.Handler that serves the live task tree rooted at the given Context.
+// By default, the tree is served as JSON (see Snapshot); if the query param "format=text" is given, PrintContextTree() output is served.
+//
+// This is intended for operators to see which tasks are wedged (e.g. during shutdown) and should not be exposed publicly.
+func DebugHandler(root Context) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("format") == "text" {
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			PrintContextTree(root, w, 0)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(SnapshotOf(root)); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
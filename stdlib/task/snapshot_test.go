@@ -0,0 +1,56 @@
+package task_test
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/task"
+)
+
+func TestSnapshot(t *testing.T) {
+	root, err := task.Start(&task.Task{
+		Label: "root",
+	})
+	require.NoError(t, err)
+	defer root.Close()
+
+	child, err := root.StartChild(&task.Task{
+		Label: "child",
+	})
+	require.NoError(t, err)
+	_, err = child.StartChild(&task.Task{
+		Label: "grandchild",
+	})
+	require.NoError(t, err)
+
+	snap := task.SnapshotOf(root)
+	require.Equal(t, "root", snap.Label)
+	require.Equal(t, "running", snap.State)
+	require.Equal(t, 1, snap.NumChildren)
+	require.Equal(t, "child", snap.Children[0].Label)
+	require.Equal(t, "grandchild", snap.Children[0].Children[0].Label)
+	require.False(t, snap.StartedAt.IsZero())
+
+	// A type embedding a Context is described via its Context methods, and its children as usual
+	wrapped := task.SnapshotOf(struct{ task.Context }{root})
+	require.Equal(t, "running", wrapped.State)
+	require.Equal(t, "grandchild", wrapped.Children[0].Children[0].Label)
+	require.Equal(t, "child", task.PathOf(struct{ task.Context }{child}))
+
+	handler := task.DebugHandler(root)
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/debug/tasks", nil))
+	var decoded task.Snapshot
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &decoded))
+	require.Equal(t, snap.ContextID, decoded.ContextID)
+	require.Equal(t, "grandchild", decoded.Children[0].Children[0].Label)
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/debug/tasks?format=text", nil))
+	require.True(t, strings.Contains(w.Body.String(), "grandchild"))
+}
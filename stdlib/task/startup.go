@@ -0,0 +1,118 @@
+package task
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Phase is a group of Tasks that are started together during a staged startup (see StartPhases).
+type Phase struct {
+	Label string  // Used for logging and errors
+	Tasks []*Task // Started concurrently; the phase is complete once every Task's OnStart() has returned
+}
+
+// Startup tracks the progress of a staged startup started via StartPhases().
+type Startup struct {
+	phases   []Phase
+	chPhases []chan struct{}
+	chDone   chan struct{}
+	mu       sync.Mutex
+	started  [][]Context
+	err      error
+}
+
+// StartPhases starts the Tasks of each given Phase as children of parent, where a phase is not begun until all Tasks of the
+// previous phase have been started (i.e. their OnStart() returned).  Place blocking initialization in OnStart() so that
+// critical subsystems (e.g. storage, registry) are fully started before the dependent children of later phases.
+//
+// If a Task fails to start, no further phases are begun and the error is available via Err() -- Contexts already started remain open.
+// This call returns immediately; use Done(), PhaseDone(), or Wait() to await progress.
+func StartPhases(parent Context, phases ...Phase) *Startup {
+	s := &Startup{
+		phases:   phases,
+		chPhases: make([]chan struct{}, len(phases)),
+		chDone:   make(chan struct{}),
+		started:  make([][]Context, len(phases)),
+	}
+	for i := range s.chPhases {
+		s.chPhases[i] = make(chan struct{})
+	}
+
+	go func() {
+		defer close(s.chDone)
+		for i, phase := range s.phases {
+			if err := s.startPhase(parent, i, phase); err != nil {
+				s.mu.Lock()
+				s.err = err
+				s.mu.Unlock()
+				return
+			}
+			close(s.chPhases[i])
+		}
+	}()
+	return s
+}
+
+func (s *Startup) startPhase(parent Context, phaseIdx int, phase Phase) error {
+	select {
+	case <-parent.Closing():
+		return fmt.Errorf("phase %q: %w", phase.Label, ErrClosed)
+	default:
+	}
+
+	started := make([]Context, len(phase.Tasks))
+	errs := make([]error, len(phase.Tasks))
+
+	wg := sync.WaitGroup{}
+	wg.Add(len(phase.Tasks))
+	for i, task := range phase.Tasks {
+		i, task := i, task
+		go func() {
+			started[i], errs[i] = parent.StartChild(task)
+			wg.Done()
+		}()
+	}
+	wg.Wait()
+
+	s.mu.Lock()
+	s.started[phaseIdx] = started
+	s.mu.Unlock()
+
+	for i, err := range errs {
+		if err != nil {
+			return fmt.Errorf("phase %q: task %q failed to start: %w", phase.Label, phase.Tasks[i].Label, err)
+		}
+	}
+	return nil
+}
+
+// PhaseDone signals once all Tasks of the given phase have started.  It is never signaled if an earlier phase fails (see Done).
+func (s *Startup) PhaseDone(phaseIdx int) <-chan struct{} {
+	return s.chPhases[phaseIdx]
+}
+
+// Done signals once all phases have started or startup was aborted due to an error.
+func (s *Startup) Done() <-chan struct{} {
+	return s.chDone
+}
+
+// Err returns the error that aborted startup, if any.
+func (s *Startup) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// Wait blocks until Done() and then returns Err().
+func (s *Startup) Wait() error {
+	<-s.chDone
+	return s.Err()
+}
+
+// Started returns the Contexts started for the given phase (nil until the phase has been attempted).
+// An entry is nil if its Task failed to start.
+func (s *Startup) Started(phaseIdx int) []Context {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.started[phaseIdx]
+}
@@ -0,0 +1,71 @@
+package task_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/task"
+)
+
+func TestStartPhases(t *testing.T) {
+	root, _ := task.Start(&task.Task{
+		Label: "root",
+	})
+	defer root.Close()
+
+	var (
+		mu      sync.Mutex
+		started []string
+	)
+	slowStart := func(label string, delay time.Duration) *task.Task {
+		return &task.Task{
+			Label: label,
+			OnStart: func(ctx task.Context) error {
+				time.Sleep(delay)
+				mu.Lock()
+				started = append(started, label)
+				mu.Unlock()
+				return nil
+			},
+		}
+	}
+
+	t.Run("ordered", func(t *testing.T) {
+		startup := task.StartPhases(root,
+			task.Phase{Label: "core", Tasks: []*task.Task{slowStart("storage", 30*time.Millisecond), slowStart("registry", 10*time.Millisecond)}},
+			task.Phase{Label: "apps", Tasks: []*task.Task{slowStart("app", 0)}},
+		)
+		<-startup.PhaseDone(0)
+		require.NoError(t, startup.Wait())
+
+		require.Len(t, started, 3)
+		require.Equal(t, "app", started[2])
+		require.Len(t, startup.Started(1), 1)
+		require.Equal(t, "app", startup.Started(1)[0].Label())
+	})
+
+	t.Run("aborts on error", func(t *testing.T) {
+		errBoom := errors.New("boom")
+		startup := task.StartPhases(root,
+			task.Phase{Label: "core", Tasks: []*task.Task{{
+				Label: "storage",
+				OnStart: func(ctx task.Context) error {
+					return errBoom
+				},
+			}}},
+			task.Phase{Label: "apps", Tasks: []*task.Task{slowStart("never", 0)}},
+		)
+		err := startup.Wait()
+		require.ErrorIs(t, err, errBoom)
+		require.Nil(t, startup.Started(1))
+		select {
+		case <-startup.PhaseDone(0):
+			t.Fatal("failed phase signaled as done")
+		default:
+		}
+	})
+}
@@ -0,0 +1,140 @@
+package task
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/utils"
+)
+
+// RestartPolicy specifies when a supervised child is restarted after it exits.
+type RestartPolicy int32
+
+const (
+	RestartNever     RestartPolicy = iota // the child is run once
+	RestartOnFailure                      // the child is restarted if it returns an error or panics
+	RestartAlways                         // the child is restarted whenever it exits
+)
+
+// Supervised is a parameter block describing a long-lived child task that is restarted according to its RestartPolicy.
+//
+// Loosely modeled on an Erlang supervisor: if a child needs more than MaxRestarts restarts within RestartWindow,
+// the supervisor gives up, calls OnGiveUp (if set), and closes.
+type Supervised struct {
+	Label         string                   // Label of the supervisor Context; each run of the child is labeled "{Label}#{run}"
+	Restart       RestartPolicy            // When to restart the child
+	MaxRestarts   int                      // If > 0, the max number of restarts allowed within RestartWindow
+	RestartWindow time.Duration            // Sliding window for MaxRestarts; also, a run lasting longer than this resets the backoff
+	BackoffMin    time.Duration            // Delay before the first restart -- doubles for each subsequent restart
+	BackoffMax    time.Duration            // Upper bound for the restart delay
+	Run           func(ctx Context) error  // Body of the child; a returned error or panic is considered a failure
+	OnFailure     func(err error, run int) // Optional: called each time the child fails
+	OnGiveUp      func(err error)          // Optional: called with the last failure when MaxRestarts is exceeded
+}
+
+// Supervise starts a supervisor Context as a child of the given parent, which in turn runs spec.Run in a child Context,
+// restarting it according to spec.Restart (with exponential backoff).
+//
+// Closing the returned Context stops the supervisor and closes the running child.
+// The supervisor Context closes on its own once the child exits and is not to be restarted.
+func Supervise(parent Context, spec Supervised) (Context, error) {
+	if spec.Run == nil {
+		return nil, fmt.Errorf("Supervise: missing Run func")
+	}
+	return parent.StartChild(&Task{
+		Label:     spec.Label,
+		IdleClose: time.Nanosecond,
+		OnRun: func(sup Context) {
+			superviseRuns(sup, &spec)
+		},
+	})
+}
+
+func superviseRuns(sup Context, spec *Supervised) {
+	backoff := utils.ExponentialBackoff{
+		Min: spec.BackoffMin,
+		Max: spec.BackoffMax,
+	}
+	if backoff.Max < backoff.Min {
+		backoff.Max = backoff.Min
+	}
+
+	var restarts []time.Time
+	for run := 1; ; run++ {
+		startedAt := time.Now()
+		err := runSupervised(sup, spec, run)
+
+		select {
+		case <-sup.Closing():
+			return
+		default:
+		}
+
+		if err != nil {
+			sup.Warnf("run #%d failed: %v", run, err)
+			if spec.OnFailure != nil {
+				spec.OnFailure(err, run)
+			}
+		}
+
+		switch spec.Restart {
+		case RestartNever:
+			return
+		case RestartOnFailure:
+			if err == nil {
+				return
+			}
+		}
+
+		now := time.Now()
+		if spec.RestartWindow > 0 {
+			if now.Sub(startedAt) > spec.RestartWindow {
+				backoff.Reset()
+			}
+
+			// Drop restarts that have fallen outside the window
+			cutoff := now.Add(-spec.RestartWindow)
+			n := 0
+			for n < len(restarts) && restarts[n].Before(cutoff) {
+				n++
+			}
+			restarts = restarts[n:]
+		}
+
+		if spec.MaxRestarts > 0 && len(restarts) >= spec.MaxRestarts {
+			sup.Errorf("giving up after %d restarts within %v", len(restarts), spec.RestartWindow)
+			if spec.OnGiveUp != nil {
+				spec.OnGiveUp(err)
+			}
+			return
+		}
+		restarts = append(restarts, now)
+
+		if delay := backoff.Next(); delay > 0 {
+			timer := time.NewTimer(delay)
+			select {
+			case <-timer.C:
+			case <-sup.Closing():
+				timer.Stop()
+				return
+			}
+		}
+	}
+}
+
+// runSupervised runs spec.Run in a new child Context of sup and blocks until that child is done.
+func runSupervised(sup Context, spec *Supervised, run int) error {
+	var runErr error
+	child, err := sup.StartChild(&Task{
+		Label:     fmt.Sprintf("%s#%d", sup.Label(), run),
+		IdleClose: time.Nanosecond,
+		OnRun: func(ctx Context) {
+			runErr = runAndRecover(ctx, spec.Run)
+		},
+	})
+	if err != nil {
+		return err
+	}
+	<-child.Done()
+	return runErr
+}
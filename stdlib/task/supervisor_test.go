@@ -0,0 +1,97 @@
+package task_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/task"
+)
+
+func TestSupervise(t *testing.T) {
+	root, _ := task.Start(&task.Task{
+		Label: "root",
+	})
+	defer root.Close()
+
+	t.Run("restarts on failure", func(t *testing.T) {
+		runs := 0
+		sup, err := task.Supervise(root, task.Supervised{
+			Label:      "flaky",
+			Restart:    task.RestartOnFailure,
+			BackoffMin: time.Millisecond,
+			BackoffMax: 4 * time.Millisecond,
+			Run: func(ctx task.Context) error {
+				runs++
+				if runs == 2 {
+					panic("boom")
+				}
+				if runs < 4 {
+					return errors.New("not yet")
+				}
+				return nil
+			},
+		})
+		require.NoError(t, err)
+
+		select {
+		case <-sup.Done():
+		case <-time.After(5 * time.Second):
+			t.Fatal("supervisor didn't finish")
+		}
+		require.Equal(t, 4, runs)
+	})
+
+	t.Run("gives up", func(t *testing.T) {
+		var lastErr error
+		failures := 0
+		sup, err := task.Supervise(root, task.Supervised{
+			Label:         "doomed",
+			Restart:       task.RestartAlways,
+			MaxRestarts:   3,
+			RestartWindow: time.Minute,
+			Run: func(ctx task.Context) error {
+				panic("always")
+			},
+			OnFailure: func(err error, run int) {
+				failures++
+			},
+			OnGiveUp: func(err error) {
+				lastErr = err
+			},
+		})
+		require.NoError(t, err)
+
+		select {
+		case <-sup.Done():
+		case <-time.After(5 * time.Second):
+			t.Fatal("supervisor didn't give up")
+		}
+		require.Equal(t, 4, failures)
+		require.ErrorIs(t, lastErr, task.ErrPanicked)
+	})
+
+	t.Run("close stops child", func(t *testing.T) {
+		chRunning := make(chan struct{})
+		sup, err := task.Supervise(root, task.Supervised{
+			Label:   "forever",
+			Restart: task.RestartAlways,
+			Run: func(ctx task.Context) error {
+				close(chRunning)
+				<-ctx.Closing()
+				return nil
+			},
+		})
+		require.NoError(t, err)
+
+		<-chRunning
+		sup.Close()
+		select {
+		case <-sup.Done():
+		case <-time.After(5 * time.Second):
+			t.Fatal("supervisor didn't close")
+		}
+	})
+}
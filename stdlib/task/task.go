@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -17,13 +18,18 @@ import (
 type ctx struct {
 	log.Logger
 
-	task Task
+	task   Task
+	parent *ctx   // nil if this is a root Context
+	path   string // labels of this Context and its ancestors, root first, joined by "/"
+	span   Span   // nil unless a TracerProvider is set
 
 	id             int64
 	state          int32
 	idle           bool
 	idleCloseRetry atomic.Int64 // time.Duration
 	idleCloseMin   time.Time
+	startedAt      time.Time
+	lastActivity   atomic.Int64 // unix nanoseconds
 
 	chClosing chan struct{}  // signals Close() has been called and close execution has begun.
 	chClosed  chan struct{}  // signals Close() has been called and all close execution is done.
@@ -38,16 +44,33 @@ var (
 	ErrAlreadyStarted = errors.New("already started")
 	ErrUnstarted      = errors.New("unstarted")
 	ErrClosed         = errors.New("closed")
+	ErrPanicked       = errors.New("panicked")
+	ErrTimeout        = fmt.Errorf("timed out: %w", context.DeadlineExceeded)
+	ErrIdleTimeout    = fmt.Errorf("idle timeout: %w", context.DeadlineExceeded)
 )
 
 var gSpawnCounter = int64(0)
 
+// Slog implements log.Slogger, so that log.SlogOf(c) logs through this Context's own Logger.
+func (p *ctx) Slog() *slog.Logger {
+	return log.SlogOf(p.Logger)
+}
+
 func (p *ctx) Close() error {
+	p.closeWithErr(nil)
+	return nil
+}
+
+// closeWithErr initiates Close(), setting the error returned by Err() if this is the first call to close.
+func (p *ctx) closeWithErr(err error) {
 	first := atomic.CompareAndSwapInt32(&p.state, Running, Closing)
 	if first {
+		p.err = err
+		if p.span != nil {
+			p.span.AddEvent("closing")
+		}
 		close(p.chClosing)
 	}
-	return nil
 }
 
 func (p *ctx) PreventIdleClose(delay time.Duration) bool {
@@ -81,7 +104,9 @@ func (p *ctx) CloseWhenIdle(delay time.Duration) {
 		var timer *time.Timer
 
 		for idleClose := true; idleClose; {
+			p.subsMu.Lock()
 			p.idle = true
+			p.subsMu.Unlock()
 			p.busy.Wait() // wait until there is a chance of catching ctx idle
 
 			retry := false
@@ -137,7 +162,21 @@ func (p *ctx) CloseWhenIdle(delay time.Duration) {
 	}()
 }
 
+// awaitCloseAfter blocks until all of Task.CloseAfter are done or until this Context is closed.
+func (p *ctx) awaitCloseAfter() {
+	for _, dep := range p.task.CloseAfter {
+		select {
+		case <-dep.Done():
+		case <-p.Closing():
+			return
+		}
+	}
+}
+
 func (p *ctx) Deadline() (deadline time.Time, ok bool) {
+	if p.task.Timeout > 0 {
+		return p.startedAt.Add(p.task.Timeout), true
+	}
 	return time.Time{}, false
 }
 
@@ -173,6 +212,10 @@ func (p *ctx) Label() string {
 	return p.task.Label
 }
 
+func (p *ctx) Path() string {
+	return p.path
+}
+
 func printContextTree(ctx Context, out *strings.Builder, depth int, prefix []rune, lastChild bool) {
 	icon := ' '
 	if depth > 0 {
@@ -207,6 +250,8 @@ func (p *ctx) StartChild(task *Task) (Context, error) {
 	child := &ctx{
 		state:     Running,
 		id:        atomic.AddInt64(&gSpawnCounter, 1),
+		parent:    p,
+		startedAt: time.Now(),
 		chClosing: make(chan struct{}),
 		chClosed:  make(chan struct{}),
 	}
@@ -219,14 +264,24 @@ func (p *ctx) StartChild(task *Task) (Context, error) {
 	if child.task.Label == "" {
 		child.task.Label = fmt.Sprintf("ctx_%d", child.id)
 	}
-	child.Logger = log.NewLogger(child.task.Label)
+	if p != nil {
+		child.path = p.path + "/" + child.task.Label
+	} else {
+		child.path = child.task.Label
+	}
+	child.Logger = log.NewLogger(child.task.Label, log.TaskKey, child.path)
+
+	// Account for OnRun before the close goroutine below can wait on child.busy
+	if child.task.OnRun != nil {
+		child.busy.Add(1)
+	}
 
 	// If a parent is given, add the child to the parent's list of children.
 	if p != nil {
 
 		var err error
 		p.subsMu.Lock()
-		if p.state == Running {
+		if atomic.LoadInt32(&p.state) == Running {
 			p.busy.Add(1)
 			p.idle = false
 			p.subs = append(p.subs, child)
@@ -240,6 +295,14 @@ func (p *ctx) StartChild(task *Task) (Context, error) {
 		}
 	}
 
+	if tp := getTracerProvider(); tp != nil {
+		var parentSpan Span
+		if p != nil {
+			parentSpan = p.span
+		}
+		child.span = tp.StartTaskSpan(parentSpan, child)
+	}
+
 	go func() {
 
 		// If there is a parent, wait until child.Close() *or* p.Close()
@@ -247,6 +310,7 @@ func (p *ctx) StartChild(task *Task) (Context, error) {
 		if p != nil {
 			select {
 			case <-p.Closing():
+				child.awaitCloseAfter()
 				child.Close()
 			case <-child.Closing():
 			}
@@ -294,10 +358,13 @@ func (p *ctx) StartChild(task *Task) (Context, error) {
 		}
 
 		// Move to Closed state now that all all that remains is the OnClosed callback and release of the chClosed chan.
-		child.state = Closed
+		atomic.StoreInt32(&child.state, Closed)
 		if child.task.OnClosed != nil {
 			child.task.OnClosed()
 		}
+		if child.span != nil {
+			child.span.End(child.err)
+		}
 		close(child.chClosed)
 
 		// With the child now fully closed, the parent is no longer waiting on this child
@@ -310,19 +377,26 @@ func (p *ctx) StartChild(task *Task) (Context, error) {
 		}
 	}()
 
+	if child.task.Timeout > 0 || child.task.IdleTimeout > 0 {
+		child.lastActivity.Store(child.startedAt.UnixNano())
+		go child.watchTimeouts()
+	}
+
 	if child.task.OnStart != nil {
 		err := child.task.OnStart(child)
 		child.task.OnStart = nil
 		if err != nil {
+			if child.task.OnRun != nil {
+				child.busy.Done()
+			}
 			child.Close()
 			return nil, err
 		}
 	}
 
 	if child.task.OnRun != nil {
-		child.busy.Add(1)
 		go func() {
-			child.task.OnRun(child)
+			child.run()
 			child.task.OnRun = nil
 			child.busy.Done()
 
@@ -2,6 +2,7 @@ package task_test
 
 import (
 	"fmt"
+	"sync"
 	"testing"
 	"time"
 
@@ -149,3 +150,47 @@ func isDone(t *testing.T, chDone <-chan struct{}) bool {
 		return false
 	}
 }
+
+func TestCloseAfter(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		closed []string
+	)
+	onClosed := func(label string) func() {
+		return func() {
+			mu.Lock()
+			closed = append(closed, label)
+			mu.Unlock()
+		}
+	}
+
+	root, _ := task.Start(&task.Task{
+		Label: "root",
+	})
+
+	sessions, _ := root.StartChild(&task.Task{
+		Label: "sessions",
+		OnClosing: func() {
+			time.Sleep(50 * time.Millisecond) // drain
+		},
+		OnClosed: onClosed("sessions"),
+	})
+	cache, _ := root.StartChild(&task.Task{
+		Label:      "cache",
+		CloseAfter: []task.Context{sessions},
+		OnClosed:   onClosed("cache"),
+	})
+	root.StartChild(&task.Task{
+		Label:      "symbols",
+		CloseAfter: []task.Context{sessions, cache},
+		OnClosed:   onClosed("symbols"),
+	})
+
+	root.Close()
+	select {
+	case <-root.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("root didn't close")
+	}
+	require.Equal(t, []string{"sessions", "cache", "symbols"}, closed)
+}
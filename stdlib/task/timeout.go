@@ -0,0 +1,48 @@
+package task
+
+import (
+	"time"
+)
+
+func (p *ctx) ReportActivity() {
+	p.lastActivity.Store(time.Now().UnixNano())
+}
+
+// watchTimeouts closes this Context once Task.Timeout or Task.IdleTimeout is exceeded, exiting when this Context closes.
+func (p *ctx) watchTimeouts() {
+	var deadline time.Time
+	if p.task.Timeout > 0 {
+		deadline = p.startedAt.Add(p.task.Timeout)
+	}
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	<-timer.C
+
+	for {
+		now := time.Now()
+		if !deadline.IsZero() && !now.Before(deadline) {
+			p.closeWithErr(ErrTimeout)
+			return
+		}
+
+		wake := deadline
+		if p.task.IdleTimeout > 0 {
+			idleAt := time.Unix(0, p.lastActivity.Load()).Add(p.task.IdleTimeout)
+			if !now.Before(idleAt) {
+				p.closeWithErr(ErrIdleTimeout)
+				return
+			}
+			if wake.IsZero() || idleAt.Before(wake) {
+				wake = idleAt
+			}
+		}
+
+		timer.Reset(wake.Sub(now))
+		select {
+		case <-timer.C:
+		case <-p.Closing():
+			return
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package task_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/task"
+)
+
+func TestTimeouts(t *testing.T) {
+	root, _ := task.Start(&task.Task{
+		Label: "root",
+	})
+	defer root.Close()
+
+	awaitDone := func(t *testing.T, ctx task.Context) {
+		select {
+		case <-ctx.Done():
+		case <-time.After(5 * time.Second):
+			t.Fatal("child not closed")
+		}
+	}
+
+	t.Run("timeout", func(t *testing.T) {
+		child, err := task.WithTimeout(root, 20*time.Millisecond, &task.Task{
+			Label: "slow",
+		})
+		require.NoError(t, err)
+		deadline, ok := child.Deadline()
+		require.True(t, ok)
+		require.False(t, deadline.IsZero())
+
+		awaitDone(t, child)
+		require.ErrorIs(t, child.Err(), task.ErrTimeout)
+		require.True(t, errors.Is(child.Err(), context.DeadlineExceeded))
+	})
+
+	t.Run("idle timeout", func(t *testing.T) {
+		child, err := task.WithIdleTimeout(root, 50*time.Millisecond, &task.Task{
+			Label: "wedged",
+		})
+		require.NoError(t, err)
+
+		// Activity keeps the child alive well past its idle timeout
+		for i := 0; i < 10; i++ {
+			time.Sleep(10 * time.Millisecond)
+			task.ReportActivity(child)
+		}
+		select {
+		case <-child.Closing():
+			t.Fatal("child closed while active")
+		default:
+		}
+
+		awaitDone(t, child)
+		require.ErrorIs(t, child.Err(), task.ErrIdleTimeout)
+	})
+
+	t.Run("closed normally", func(t *testing.T) {
+		child, err := task.WithTimeout(root, time.Minute, &task.Task{
+			Label: "quick",
+		})
+		require.NoError(t, err)
+		child.Close()
+		awaitDone(t, child)
+		require.Equal(t, context.Canceled, child.Err())
+	})
+}
@@ -0,0 +1,44 @@
+package task
+
+import (
+	"sync/atomic"
+)
+
+// TracerProvider creates a Span for each Context started while it is set (see SetTracerProvider).
+//
+// This decouples the task package from any particular tracing library -- see package task/otel for OpenTelemetry.
+type TracerProvider interface {
+
+	// Starts a span for the given newly started Context -- parent is nil for a root Context (or if the parent was started without a span).
+	StartTaskSpan(parent Span, c Context) Span
+}
+
+// Span traces the lifetime of a single Context.
+type Span interface {
+
+	// Called when a lifecycle event occurs (e.g. "closing").
+	AddEvent(name string)
+
+	// Called once the Context is fully closed, where err is its completion error (nil if closed normally, or e.g. ErrTimeout).
+	End(err error)
+}
+
+type tracerProviderBox struct {
+	tp TracerProvider
+}
+
+var gTracerProvider atomic.Value // *tracerProviderBox
+
+// SetTracerProvider sets the TracerProvider used for all subsequently started Contexts (or nil to disable tracing).
+// When disabled, tracing costs a single atomic load per started Context.
+func SetTracerProvider(tp TracerProvider) {
+	gTracerProvider.Store(&tracerProviderBox{tp})
+}
+
+func getTracerProvider() TracerProvider {
+	box, _ := gTracerProvider.Load().(*tracerProviderBox)
+	if box == nil {
+		return nil
+	}
+	return box.tp
+}
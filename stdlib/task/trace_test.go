@@ -0,0 +1,81 @@
+package task_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/task"
+)
+
+type testSpan struct {
+	tracer *testTracer
+	parent *testSpan
+	label  string
+	events []string
+	ended  bool
+	err    error
+}
+
+func (span *testSpan) AddEvent(name string) {
+	span.tracer.mu.Lock()
+	span.events = append(span.events, name)
+	span.tracer.mu.Unlock()
+}
+
+func (span *testSpan) End(err error) {
+	span.tracer.mu.Lock()
+	span.ended = true
+	span.err = err
+	span.tracer.mu.Unlock()
+}
+
+type testTracer struct {
+	mu    sync.Mutex
+	spans map[string]*testSpan
+}
+
+func (tr *testTracer) StartTaskSpan(parent task.Span, c task.Context) task.Span {
+	span := &testSpan{
+		tracer: tr,
+		label:  c.Label(),
+	}
+	span.parent, _ = parent.(*testSpan)
+	tr.mu.Lock()
+	tr.spans[span.label] = span
+	tr.mu.Unlock()
+	return span
+}
+
+func TestTracerProvider(t *testing.T) {
+	tracer := &testTracer{
+		spans: map[string]*testSpan{},
+	}
+	task.SetTracerProvider(tracer)
+	defer task.SetTracerProvider(nil)
+
+	root, _ := task.Start(&task.Task{
+		Label: "root",
+	})
+	child, _ := root.StartChild(&task.Task{
+		Label:   "child",
+		Timeout: time.Millisecond,
+	})
+	<-child.Done()
+	root.Close()
+	<-root.Done()
+
+	tracer.mu.Lock()
+	defer tracer.mu.Unlock()
+
+	rootSpan, childSpan := tracer.spans["root"], tracer.spans["child"]
+	require.Nil(t, rootSpan.parent)
+	require.Equal(t, rootSpan, childSpan.parent)
+	require.Equal(t, []string{"closing"}, childSpan.events)
+	require.True(t, rootSpan.ended)
+	require.True(t, childSpan.ended)
+	require.ErrorIs(t, childSpan.err, task.ErrTimeout)
+	require.NoError(t, rootSpan.err)
+}
@@ -0,0 +1,264 @@
+package task
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// WorkerPoolOpts are the options used to start a WorkerPool.
+type WorkerPoolOpts struct {
+	Label      string        // Label of the pool's Context
+	MinWorkers int           // Number of workers that are always running (at least 1)
+	MaxWorkers int           // If > MinWorkers, additional workers are started on demand up to this count
+	WorkerIdle time.Duration // Time an on-demand worker waits for a job before exiting (default: 10s)
+	QueueSize  int           // Number of submitted jobs that can await a worker before Submit() blocks
+}
+
+// Job is a unit of work submitted to a WorkerPool.
+type Job struct {
+	Label string
+
+	fn   func(ctx Context) error
+	err  error
+	done chan struct{}
+}
+
+// Done signals when this job has completed (or was discarded since the pool closed).
+func (job *Job) Done() <-chan struct{} {
+	return job.done
+}
+
+// Err returns the job's error once Done() is signaled -- a panic is reported as an error wrapping ErrPanicked.
+func (job *Job) Err() error {
+	select {
+	case <-job.done:
+		return job.err
+	default:
+		return nil
+	}
+}
+
+func (job *Job) complete(err error) {
+	job.err = err
+	close(job.done)
+}
+
+// WorkerPool runs submitted jobs on a bounded set of workers, where each job runs in its own child Context.
+//
+// Close() is graceful: no further jobs are accepted, queued jobs are completed, and then the pool's Context closes.
+// If instead the pool's Context is closed via its parent, running jobs are closed and queued jobs are discarded with ErrClosed.
+type WorkerPool struct {
+	Context
+	opts       WorkerPoolOpts
+	chJobs     chan *Job
+	mu         sync.RWMutex // write locked when chJobs is closed
+	closed     bool
+	workers    sync.WaitGroup
+	numWorkers atomic.Int32
+	numIdle    atomic.Int32
+	workerSeq  atomic.Int32
+}
+
+// StartWorkerPool starts a WorkerPool as a child of the given parent.
+func StartWorkerPool(parent Context, opts WorkerPoolOpts) (*WorkerPool, error) {
+	if opts.MinWorkers < 1 {
+		opts.MinWorkers = 1
+	}
+	if opts.MaxWorkers < opts.MinWorkers {
+		opts.MaxWorkers = opts.MinWorkers
+	}
+	if opts.WorkerIdle <= 0 {
+		opts.WorkerIdle = 10 * time.Second
+	}
+	if opts.Label == "" {
+		opts.Label = "WorkerPool"
+	}
+
+	p := &WorkerPool{
+		opts:   opts,
+		chJobs: make(chan *Job, opts.QueueSize),
+	}
+
+	var err error
+	p.Context, err = parent.StartChild(&Task{
+		Label:    opts.Label,
+		OnClosed: p.discardQueued,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < opts.MinWorkers; i++ {
+		p.numWorkers.Add(1)
+		p.startWorker()
+	}
+	return p, nil
+}
+
+// Submit queues the given function to be run in a new child Context of a worker, blocking while the queue is full.
+//
+// Returns ErrClosed if the pool is closing or ctx.Err() if ctx is done before the job could be queued.
+func (p *WorkerPool) Submit(ctx context.Context, label string, fn func(ctx Context) error) (*Job, error) {
+	job := &Job{
+		Label: label,
+		fn:    fn,
+		done:  make(chan struct{}),
+	}
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.closed {
+		return nil, ErrClosed
+	}
+
+	if p.numIdle.Load() == 0 && p.reserveWorker() {
+		p.startWorker()
+	}
+
+	select {
+	case p.chJobs <- job:
+		return job, nil
+	case <-p.Context.Closing():
+		return nil, ErrClosed
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Close stops accepting jobs and closes the pool once all queued and running jobs have completed.
+func (p *WorkerPool) Close() error {
+	p.mu.Lock()
+	alreadyClosed := p.closed
+	if !alreadyClosed {
+		p.closed = true
+		close(p.chJobs)
+	}
+	p.mu.Unlock()
+
+	if !alreadyClosed {
+		go func() {
+			p.workers.Wait()
+			p.Context.Close()
+		}()
+	}
+	return nil
+}
+
+// NumWorkers returns the number of currently running workers.
+func (p *WorkerPool) NumWorkers() int {
+	return int(p.numWorkers.Load())
+}
+
+// reserveWorker increments numWorkers if another on-demand worker is allowed.
+func (p *WorkerPool) reserveWorker() bool {
+	for {
+		n := p.numWorkers.Load()
+		if int(n) >= p.opts.MaxWorkers {
+			return false
+		}
+		if p.numWorkers.CompareAndSwap(n, n+1) {
+			return true
+		}
+	}
+}
+
+// releaseWorker decrements numWorkers unless doing so would drop below MinWorkers.
+func (p *WorkerPool) releaseWorker() bool {
+	for {
+		n := p.numWorkers.Load()
+		if int(n) <= p.opts.MinWorkers {
+			return false
+		}
+		if p.numWorkers.CompareAndSwap(n, n-1) {
+			return true
+		}
+	}
+}
+
+// startWorker starts a worker already reserved in numWorkers -- p.mu must be read locked (or the pool still starting).
+func (p *WorkerPool) startWorker() {
+	p.workers.Add(1)
+	label := fmt.Sprintf("worker %d", p.workerSeq.Add(1))
+	_, err := p.Context.Go(label, func(ctx Context) {
+		p.work(ctx)
+	})
+	if err != nil {
+		p.numWorkers.Add(-1)
+		p.workers.Done()
+	}
+}
+
+func (p *WorkerPool) work(ctx Context) {
+	defer p.workers.Done()
+
+	idle := time.NewTimer(p.opts.WorkerIdle)
+	defer idle.Stop()
+
+	for {
+		p.numIdle.Add(1)
+		select {
+		case job, ok := <-p.chJobs:
+			p.numIdle.Add(-1)
+			if !ok {
+				p.numWorkers.Add(-1)
+				return
+			}
+			p.runJob(ctx, job)
+
+		case <-idle.C:
+			p.numIdle.Add(-1)
+			if p.releaseWorker() {
+				return
+			}
+
+		case <-ctx.Closing():
+			p.numIdle.Add(-1)
+			p.numWorkers.Add(-1)
+			return
+		}
+
+		if !idle.Stop() {
+			select {
+			case <-idle.C:
+			default:
+			}
+		}
+		idle.Reset(p.opts.WorkerIdle)
+	}
+}
+
+// runJob runs the given job in a child Context of the given worker, isolating any panic to the job.
+func (p *WorkerPool) runJob(worker Context, job *Job) {
+	var jobErr error
+	jobCtx, err := worker.StartChild(&Task{
+		Label:     job.Label,
+		IdleClose: time.Nanosecond,
+		OnRun: func(ctx Context) {
+			jobErr = runAndRecover(ctx, job.fn)
+		},
+	})
+	if err != nil {
+		job.complete(ErrClosed)
+		return
+	}
+	<-jobCtx.Done()
+	job.complete(jobErr)
+}
+
+// discardQueued completes all jobs that never reached a worker with ErrClosed.
+func (p *WorkerPool) discardQueued() {
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.chJobs)
+	}
+	p.mu.Unlock()
+
+	for job := range p.chJobs {
+		job.complete(ErrClosed)
+	}
+}
@@ -0,0 +1,130 @@
+package task_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/task"
+)
+
+func TestWorkerPool(t *testing.T) {
+	root, _ := task.Start(&task.Task{
+		Label: "root",
+	})
+	defer root.Close()
+
+	t.Run("drain on close", func(t *testing.T) {
+		pool, err := task.StartWorkerPool(root, task.WorkerPoolOpts{
+			MinWorkers: 2,
+			QueueSize:  100,
+		})
+		require.NoError(t, err)
+
+		var ran atomic.Int32
+		var jobs []*task.Job
+		for i := 0; i < 50; i++ {
+			job, err := pool.Submit(context.Background(), "job", func(ctx task.Context) error {
+				time.Sleep(time.Millisecond)
+				ran.Add(1)
+				return nil
+			})
+			require.NoError(t, err)
+			jobs = append(jobs, job)
+		}
+		pool.Close()
+
+		_, err = pool.Submit(context.Background(), "late", func(ctx task.Context) error { return nil })
+		require.ErrorIs(t, err, task.ErrClosed)
+
+		select {
+		case <-pool.Done():
+		case <-time.After(5 * time.Second):
+			t.Fatal("pool didn't drain")
+		}
+		require.Equal(t, int32(50), ran.Load())
+		for _, job := range jobs {
+			<-job.Done()
+			require.NoError(t, job.Err())
+		}
+	})
+
+	t.Run("panic isolation", func(t *testing.T) {
+		pool, err := task.StartWorkerPool(root, task.WorkerPoolOpts{})
+		require.NoError(t, err)
+		defer pool.Close()
+
+		bad, err := pool.Submit(context.Background(), "bad", func(ctx task.Context) error {
+			panic("boom")
+		})
+		require.NoError(t, err)
+		good, err := pool.Submit(context.Background(), "good", func(ctx task.Context) error {
+			return errors.New("plain error")
+		})
+		require.NoError(t, err)
+
+		<-bad.Done()
+		require.ErrorIs(t, bad.Err(), task.ErrPanicked)
+		<-good.Done()
+		require.EqualError(t, good.Err(), "plain error")
+	})
+
+	t.Run("elastic", func(t *testing.T) {
+		pool, err := task.StartWorkerPool(root, task.WorkerPoolOpts{
+			MinWorkers: 1,
+			MaxWorkers: 4,
+			WorkerIdle: 20 * time.Millisecond,
+		})
+		require.NoError(t, err)
+		defer pool.Close()
+
+		release := make(chan struct{})
+		var jobs []*task.Job
+		for i := 0; i < 4; i++ {
+			job, err := pool.Submit(context.Background(), "blocker", func(ctx task.Context) error {
+				<-release
+				return nil
+			})
+			require.NoError(t, err)
+			jobs = append(jobs, job)
+			time.Sleep(5 * time.Millisecond)
+		}
+		require.Equal(t, 4, pool.NumWorkers())
+		close(release)
+		for _, job := range jobs {
+			<-job.Done()
+		}
+
+		require.Eventually(t, func() bool {
+			return pool.NumWorkers() == 1
+		}, 5*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("hard close discards queued", func(t *testing.T) {
+		parent, _ := root.StartChild(&task.Task{Label: "parent"})
+		pool, err := task.StartWorkerPool(parent, task.WorkerPoolOpts{
+			QueueSize: 10,
+		})
+		require.NoError(t, err)
+
+		running, err := pool.Submit(context.Background(), "running", func(ctx task.Context) error {
+			<-ctx.Closing()
+			return ctx.Err()
+		})
+		require.NoError(t, err)
+		queued, err := pool.Submit(context.Background(), "queued", func(ctx task.Context) error {
+			return nil
+		})
+		require.NoError(t, err)
+
+		time.Sleep(10 * time.Millisecond)
+		parent.Close()
+		<-running.Done()
+		<-queued.Done()
+		require.ErrorIs(t, queued.Err(), task.ErrClosed)
+	})
+}
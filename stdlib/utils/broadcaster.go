@@ -0,0 +1,93 @@
+package utils
+
+import (
+	"context"
+	"sync"
+)
+
+// Broadcaster is the untyped form of BroadcasterOf.
+type Broadcaster = BroadcasterOf[any]
+
+// NewBroadcaster creates an untyped Broadcaster -- see NewBroadcasterOf().
+func NewBroadcaster(opts MailboxOpts) *Broadcaster {
+	return NewBroadcasterOf[any](opts)
+}
+
+// BroadcasterOf delivers a copy of every item to each of its subscribers, where each subscriber has its own bounded mailbox.
+//
+// Slow subscribers are handled according to MailboxOpts.Overflow:
+//   - DropOldest / DropNewest: a full subscriber mailbox drops items without affecting other subscribers.
+//   - BlockDeliver: Deliver() blocks until every subscriber has room, applying backpressure to the producer.
+type BroadcasterOf[T any] struct {
+	opts MailboxOpts
+	mu   sync.Mutex
+	subs []*MailboxOf[T] // copy-on-write so delivery can occur without holding mu
+}
+
+// NewBroadcasterOf creates a BroadcasterOf[T] whose subscribers each receive a mailbox created with the given options.
+func NewBroadcasterOf[T any](opts MailboxOpts) *BroadcasterOf[T] {
+	return &BroadcasterOf[T]{
+		opts: opts,
+	}
+}
+
+// Subscribe creates and returns a new mailbox that receives all items subsequently delivered to this Broadcaster.
+func (b *BroadcasterOf[T]) Subscribe() *MailboxOf[T] {
+	sub := NewMailboxWithOpts[T](b.opts)
+
+	b.mu.Lock()
+	subs := make([]*MailboxOf[T], len(b.subs), len(b.subs)+1)
+	copy(subs, b.subs)
+	b.subs = append(subs, sub)
+	b.mu.Unlock()
+
+	return sub
+}
+
+// Unsubscribe removes the given mailbox (previously returned by Subscribe) so it receives no further items.
+// Returns false if the given mailbox was not subscribed.
+func (b *BroadcasterOf[T]) Unsubscribe(sub *MailboxOf[T]) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for i, si := range b.subs {
+		if si == sub {
+			subs := make([]*MailboxOf[T], 0, len(b.subs)-1)
+			subs = append(subs, b.subs[:i]...)
+			b.subs = append(subs, b.subs[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// NumSubscribers returns the number of currently subscribed mailboxes.
+func (b *BroadcasterOf[T]) NumSubscribers() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.subs)
+}
+
+// Deliver delivers the given item to every current subscriber -- see MailboxOf.Deliver().
+func (b *BroadcasterOf[T]) Deliver(x T) {
+	for _, sub := range b.subscribers() {
+		sub.Deliver(x)
+	}
+}
+
+// DeliverCtx delivers the given item to every current subscriber, blocking until each has room or ctx is done.
+// If ctx is done, subscribers not yet reached do not receive the item and ctx.Err() is returned.
+func (b *BroadcasterOf[T]) DeliverCtx(ctx context.Context, x T) error {
+	for _, sub := range b.subscribers() {
+		if err := sub.DeliverCtx(ctx, x); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *BroadcasterOf[T]) subscribers() []*MailboxOf[T] {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.subs
+}
@@ -0,0 +1,253 @@
+package utils
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DebounceOpts are the options used to create a Debounce.
+type DebounceOpts[T any] struct {
+	Delay    time.Duration        // how long values must be quiet before the pending value is delivered
+	MaxDelay time.Duration        // most time a pending value is held while values keep coming (default no limit)
+	Merge    func(pending, v T) T // combines a value with the one pending (default keeps the latest)
+}
+
+// Debounce coalesces a burst of values (e.g. a storm of file changes or rapid re-emits of an attr) into a single delivery
+// made once the values have been quiet for DebounceOpts.Delay.  It is safe for concurrent use.
+type Debounce[T any] struct {
+	opts DebounceOpts[T]
+	ctl  coalescer[T]
+}
+
+// NewDebounce returns a Debounce delivering to the given func, which is called from a goroutine of its own (though
+// never concurrently with itself).  The Debounce stops when ctx is done (e.g. when a task.Context closes).
+func NewDebounce[T any](ctx context.Context, opts DebounceOpts[T], fn func(v T)) *Debounce[T] {
+	d := &Debounce[T]{
+		opts: opts,
+	}
+	d.ctl.init(ctx, opts.Merge, fn)
+	return d
+}
+
+// Call adds the given value to the one pending (see DebounceOpts.Merge), (re)scheduling its delivery.
+func (d *Debounce[T]) Call(v T) {
+	if d.ctl.add(v, func(now, first time.Time) time.Duration {
+		delay := d.opts.Delay
+		if d.opts.MaxDelay > 0 {
+			if deadline := first.Add(d.opts.MaxDelay); now.Add(delay).After(deadline) {
+				delay = max(deadline.Sub(now), 0)
+			}
+		}
+		return delay
+	}) {
+		d.ctl.deliverPending()
+	}
+}
+
+// Flush delivers the pending value (if any) now, returning once it has been delivered.
+func (d *Debounce[T]) Flush() {
+	d.ctl.flush()
+}
+
+// Stop discards the pending value (if any) and ignores values subsequently added, returning once any delivery underway
+// completes (so it must not be called from the delivery func).
+func (d *Debounce[T]) Stop() {
+	d.ctl.stop()
+}
+
+// DebounceChan returns a channel receiving the values received from in, debounced per the given opts.  The returned
+// channel is closed once in is closed (after the pending value is delivered) or ctx is done.
+func DebounceChan[T any](ctx context.Context, in <-chan T, opts DebounceOpts[T]) <-chan T {
+	out := make(chan T)
+	d := NewDebounce(ctx, opts, func(v T) {
+		select {
+		case out <- v:
+		case <-ctx.Done():
+		}
+	})
+	go relayChan(ctx, in, out, d.Call, d.Flush, d.Stop)
+	return out
+}
+
+// ThrottleOpts are the options used to create a Throttle.
+type ThrottleOpts[T any] struct {
+	Interval time.Duration        // least time between deliveries
+	Merge    func(pending, v T) T // combines a value with the one pending (default keeps the latest)
+}
+
+// Throttle delivers values at most once per ThrottleOpts.Interval: a value following a quiet interval is delivered at
+// once, and values arriving sooner are coalesced into a single delivery made once the interval has passed.
+// It is safe for concurrent use.
+type Throttle[T any] struct {
+	opts ThrottleOpts[T]
+	fn   func(v T)
+	ctl  coalescer[T]
+	next time.Time // when a delivery may next be made (guarded by ctl.mu)
+}
+
+// NewThrottle returns a Throttle delivering to the given func, which is called from the goroutine calling Call for a
+// value delivered at once and from a goroutine of its own otherwise (though never concurrently with itself).
+// The Throttle stops when ctx is done (e.g. when a task.Context closes).
+func NewThrottle[T any](ctx context.Context, opts ThrottleOpts[T], fn func(v T)) *Throttle[T] {
+	th := &Throttle[T]{
+		opts: opts,
+		fn:   fn,
+	}
+	th.ctl.init(ctx, opts.Merge, th.deliver)
+	return th
+}
+
+// Call delivers the given value at once if the interval since the last delivery has passed, or otherwise adds it to the
+// one pending (see ThrottleOpts.Merge) for delivery once it has.
+func (th *Throttle[T]) Call(v T) {
+	if th.ctl.add(v, func(now, first time.Time) time.Duration {
+		return max(th.next.Sub(now), 0)
+	}) {
+		th.ctl.deliverPending()
+	}
+}
+
+// Flush is like Debounce.Flush.
+func (th *Throttle[T]) Flush() {
+	th.ctl.flush()
+}
+
+// Stop is like Debounce.Stop.
+func (th *Throttle[T]) Stop() {
+	th.ctl.stop()
+}
+
+// deliver is called with ctl.deliverMu locked.
+func (th *Throttle[T]) deliver(v T) {
+	th.ctl.mu.Lock()
+	th.next = time.Now().Add(th.opts.Interval)
+	th.ctl.mu.Unlock()
+	th.fn(v)
+}
+
+// ThrottleChan returns a channel receiving the values received from in, throttled per the given opts.  The returned
+// channel is closed once in is closed (after the pending value is delivered) or ctx is done.
+func ThrottleChan[T any](ctx context.Context, in <-chan T, opts ThrottleOpts[T]) <-chan T {
+	out := make(chan T)
+	th := NewThrottle(ctx, opts, func(v T) {
+		select {
+		case out <- v:
+		case <-ctx.Done():
+		}
+	})
+	go relayChan(ctx, in, out, th.Call, th.Flush, th.Stop)
+	return out
+}
+
+// relayChan passes values from in to the given func until in is closed or ctx is done, then closes out.
+func relayChan[T any](ctx context.Context, in <-chan T, out chan T, call func(T), flush, stop func()) {
+	defer close(out)
+	defer stop()
+	for {
+		select {
+		case v, ok := <-in:
+			if !ok {
+				flush()
+				return
+			}
+			call(v)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// coalescer holds a pending value and schedules its delivery, the basis of Debounce and Throttle.
+type coalescer[T any] struct {
+	merge     func(pending, v T) T
+	deliver   func(v T)
+	stopCtx   func() bool
+	deliverMu sync.Mutex // orders deliveries if the timer fires again during a delivery
+	mu        sync.Mutex
+	pending   T
+	waiting   bool      // set if a value is pending
+	first     time.Time // when the pending value was first added
+	timer     *time.Timer
+	stopped   bool
+}
+
+func (c *coalescer[T]) init(ctx context.Context, merge func(pending, v T) T, deliver func(v T)) {
+	c.merge, c.deliver = merge, deliver
+	c.stopCtx = context.AfterFunc(ctx, c.stop)
+}
+
+// add adds the given value to the one pending, scheduling its delivery after the delay returned by the given func,
+// returning true if that delay is 0.
+func (c *coalescer[T]) add(v T, delayFor func(now, first time.Time) time.Duration) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.stopped {
+		return false
+	}
+
+	now := time.Now()
+	if !c.waiting {
+		c.pending, c.waiting, c.first = v, true, now
+	} else if c.merge != nil {
+		c.pending = c.merge(c.pending, v)
+	} else {
+		c.pending = v
+	}
+
+	delay := delayFor(now, c.first)
+	if delay <= 0 {
+		if c.timer != nil {
+			c.timer.Stop()
+		}
+		return true
+	}
+	if c.timer == nil {
+		c.timer = time.AfterFunc(delay, c.deliverPending)
+	} else {
+		c.timer.Reset(delay)
+	}
+	return false
+}
+
+// flush cancels the scheduled delivery and delivers the pending value (if any) now.
+func (c *coalescer[T]) flush() {
+	c.mu.Lock()
+	if c.timer != nil {
+		c.timer.Stop()
+	}
+	c.mu.Unlock()
+	c.deliverPending()
+}
+
+// deliverPending delivers the pending value (if any) now.
+func (c *coalescer[T]) deliverPending() {
+	c.deliverMu.Lock()
+	defer c.deliverMu.Unlock()
+
+	c.mu.Lock()
+	v, waiting := c.pending, c.waiting && !c.stopped
+	var zero T
+	c.pending, c.waiting = zero, false
+	c.mu.Unlock()
+
+	if waiting {
+		c.deliver(v)
+	}
+}
+
+func (c *coalescer[T]) stop() {
+	c.mu.Lock()
+	var zero T
+	c.stopped = true
+	c.pending, c.waiting = zero, false
+	if c.timer != nil {
+		c.timer.Stop()
+	}
+	c.mu.Unlock()
+	c.stopCtx()
+
+	// Wait for any delivery underway
+	c.deliverMu.Lock()
+	c.deliverMu.Unlock()
+}
@@ -0,0 +1,153 @@
+package utils_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/utils"
+)
+
+// recorder records the values delivered to it.
+type recorder[T any] struct {
+	mu   sync.Mutex
+	vals []T
+}
+
+func (rec *recorder[T]) deliver(v T) {
+	rec.mu.Lock()
+	rec.vals = append(rec.vals, v)
+	rec.mu.Unlock()
+}
+
+func (rec *recorder[T]) get() []T {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	return append([]T(nil), rec.vals...)
+}
+
+func TestDebounce(t *testing.T) {
+	t.Parallel()
+
+	// A burst is delivered once, merged, after it goes quiet
+	rec := &recorder[[]int]{}
+	d := utils.NewDebounce(context.Background(), utils.DebounceOpts[[]int]{
+		Delay: 20 * time.Millisecond,
+		Merge: func(pending, v []int) []int { return append(pending, v...) },
+	}, rec.deliver)
+	for i := 0; i < 5; i++ {
+		d.Call([]int{i})
+		time.Sleep(time.Millisecond)
+	}
+	require.Empty(t, rec.get())
+	require.Eventually(t, func() bool { return len(rec.get()) == 1 }, time.Second, time.Millisecond)
+	require.Equal(t, [][]int{{0, 1, 2, 3, 4}}, rec.get())
+
+	// Flush delivers at once, and Stop discards what is pending
+	d.Call([]int{5})
+	d.Flush()
+	require.Equal(t, []int{5}, rec.get()[1])
+	d.Call([]int{6})
+	d.Stop()
+	d.Call([]int{7})
+	time.Sleep(40 * time.Millisecond)
+	require.Len(t, rec.get(), 2)
+
+	// MaxDelay bounds how long a steady stream is held, and the latest value is kept by default
+	recInts := &recorder[int]{}
+	d2 := utils.NewDebounce(context.Background(), utils.DebounceOpts[int]{
+		Delay:    20 * time.Millisecond,
+		MaxDelay: 50 * time.Millisecond,
+	}, recInts.deliver)
+	defer d2.Stop()
+	start := time.Now()
+	for i := 0; len(recInts.get()) == 0; i++ {
+		require.Less(t, time.Since(start), time.Second)
+		d2.Call(i)
+		time.Sleep(5 * time.Millisecond)
+	}
+	require.Greater(t, recInts.get()[0], 0)
+}
+
+func TestDebounceCancel(t *testing.T) {
+	t.Parallel()
+
+	// A Debounce stops when its context is done
+	ctx, cancel := context.WithCancel(context.Background())
+	rec := &recorder[int]{}
+	d := utils.NewDebounce(ctx, utils.DebounceOpts[int]{Delay: 10 * time.Millisecond}, rec.deliver)
+	d.Call(1)
+	cancel()
+	time.Sleep(30 * time.Millisecond)
+	d.Call(2)
+	d.Flush()
+	require.Empty(t, rec.get())
+
+	// DebounceChan delivers what is pending when its input closes
+	in := make(chan int)
+	out := utils.DebounceChan(context.Background(), in, utils.DebounceOpts[int]{
+		Delay: time.Hour,
+		Merge: func(pending, v int) int { return pending + v },
+	})
+	for i := 1; i <= 4; i++ {
+		in <- i
+	}
+	close(in)
+	require.Equal(t, 10, <-out)
+	_, ok := <-out
+	require.False(t, ok)
+
+	// ... and closes when its context is done
+	ctx, cancel = context.WithCancel(context.Background())
+	out = utils.DebounceChan(ctx, make(chan int), utils.DebounceOpts[int]{Delay: time.Millisecond})
+	cancel()
+	_, ok = <-out
+	require.False(t, ok)
+}
+
+func TestThrottle(t *testing.T) {
+	t.Parallel()
+
+	// The first value is delivered at once, and the values following within the interval are delivered once it passes
+	rec := &recorder[int]{}
+	th := utils.NewThrottle(context.Background(), utils.ThrottleOpts[int]{
+		Interval: 30 * time.Millisecond,
+		Merge:    func(pending, v int) int { return pending + v },
+	}, rec.deliver)
+	defer th.Stop()
+	th.Call(1)
+	require.Equal(t, []int{1}, rec.get())
+	th.Call(2)
+	th.Call(3)
+	require.Equal(t, []int{1}, rec.get())
+	require.Eventually(t, func() bool { return len(rec.get()) == 2 }, time.Second, time.Millisecond)
+	require.Equal(t, []int{1, 5}, rec.get())
+
+	// After a quiet interval, a value is again delivered at once
+	time.Sleep(40 * time.Millisecond)
+	th.Call(4)
+	require.Equal(t, []int{1, 5, 4}, rec.get())
+
+	// ThrottleChan passes at most one value per interval
+	in := make(chan int)
+	out := utils.ThrottleChan(context.Background(), in, utils.ThrottleOpts[int]{Interval: 20 * time.Millisecond})
+	var recvd []int
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for v := range out {
+			recvd = append(recvd, v)
+		}
+	}()
+	for i := 0; i < 10; i++ {
+		in <- i
+	}
+	close(in)
+	<-done
+	require.Equal(t, 0, recvd[0])
+	require.Equal(t, 9, recvd[len(recvd)-1])
+	require.Less(t, len(recvd), 10)
+}
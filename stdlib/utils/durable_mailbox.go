@@ -0,0 +1,346 @@
+package utils
+
+import (
+	"bufio"
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/errors"
+)
+
+// DurableMailboxOpts are the options used to open a DurableMailbox.
+type DurableMailboxOpts struct {
+	SyncWrites     bool  // If set, the journal is fsync'ed after every Deliver() and Ack()
+	CompactAtBytes int64 // If > 0, the journal is compacted during Ack() once it exceeds this size
+}
+
+// DurableMailbox is a mailbox of byte-string items journaled to an append-only file so that queued work survives a process restart.
+//
+// Items are delivered at least once: an item returned by Retrieve() remains in the journal until Ack() is called with its sequence number (or later).
+// When reopened, all items not yet acked are queued again in their original order.
+//
+// Journal records have the form (little endian):
+//
+//	op:1 | seq:8 | dataLen:4 | data:dataLen | crc32:4
+//
+// A truncated or corrupt trailing record (e.g. from a crash mid-write) is discarded when the journal is opened.
+type DurableMailbox struct {
+	opts     DurableMailboxOpts
+	pathname string
+	chNotify chan struct{}
+
+	mu       sync.Mutex
+	file     *os.File
+	fileSz   int64
+	nextSeq  uint64        // seq assigned to the next delivered item
+	ackedSeq uint64        // all items with seq <= ackedSeq are done
+	unacked  []DurableItem // items not yet acked, oldest first
+	inFlight int           // number of leading unacked items already returned by Retrieve()
+}
+
+// DurableItem is an item queued in a DurableMailbox.
+type DurableItem struct {
+	Seq  uint64 // sequence number to pass to Ack() once this item is done
+	Data []byte
+}
+
+const (
+	durableOp_Deliver = byte(1)
+	durableOp_Ack     = byte(2)
+
+	durableRecordOverhead = 1 + 8 + 4 + 4
+)
+
+// OpenDurableMailbox opens (or creates) the journal at the given pathname, queuing all previously delivered items not yet acked.
+func OpenDurableMailbox(pathname string, opts DurableMailboxOpts) (*DurableMailbox, error) {
+	file, err := os.OpenFile(pathname, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &DurableMailbox{
+		opts:     opts,
+		pathname: pathname,
+		chNotify: make(chan struct{}, 1),
+		file:     file,
+		nextSeq:  1,
+	}
+
+	if err = m.replay(); err != nil {
+		file.Close()
+		return nil, errors.Wrapf(err, "failed to replay %q", pathname)
+	}
+
+	if len(m.unacked) > 0 {
+		m.notify()
+	}
+	return m, nil
+}
+
+// replay reads the journal, rebuilding state and truncating any invalid trailing data.
+func (m *DurableMailbox) replay() error {
+	if _, err := m.file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	info, err := m.file.Stat()
+	if err != nil {
+		return err
+	}
+
+	r := bufio.NewReader(m.file)
+	validSz := int64(0)
+	for {
+		op, seq, data, recSz, err := readDurableRecord(r, info.Size()-validSz)
+		if err != nil {
+			break // EOF or a partial / corrupt record
+		}
+		validSz += recSz
+
+		switch op {
+		case durableOp_Deliver:
+			m.unacked = append(m.unacked, DurableItem{Seq: seq, Data: data})
+			if seq >= m.nextSeq {
+				m.nextSeq = seq + 1
+			}
+		case durableOp_Ack:
+			m.applyAck(seq)
+			if seq >= m.nextSeq {
+				m.nextSeq = seq + 1
+			}
+		}
+	}
+
+	if err := m.file.Truncate(validSz); err != nil {
+		return err
+	}
+	if _, err := m.file.Seek(validSz, io.SeekStart); err != nil {
+		return err
+	}
+	m.fileSz = validSz
+	m.inFlight = 0
+	return nil
+}
+
+// readDurableRecord reads the next record, where remain is the number of bytes left in the journal.  Since dataLen is
+// not yet verified by the checksum, a record claiming more data than remains is a torn (or corrupt) tail.
+func readDurableRecord(r io.Reader, remain int64) (op byte, seq uint64, data []byte, recSz int64, err error) {
+	var hdr [1 + 8 + 4]byte
+	if _, err = io.ReadFull(r, hdr[:]); err != nil {
+		return
+	}
+	op = hdr[0]
+	seq = binary.LittleEndian.Uint64(hdr[1:9])
+	dataLen := binary.LittleEndian.Uint32(hdr[9:13])
+	if int64(dataLen) > remain-durableRecordOverhead {
+		err = io.ErrUnexpectedEOF
+		return
+	}
+
+	data = make([]byte, dataLen)
+	if _, err = io.ReadFull(r, data); err != nil {
+		return
+	}
+
+	var crcBuf [4]byte
+	if _, err = io.ReadFull(r, crcBuf[:]); err != nil {
+		return
+	}
+	crc := crc32.Update(crc32.ChecksumIEEE(hdr[:]), crc32.IEEETable, data)
+	if crc != binary.LittleEndian.Uint32(crcBuf[:]) {
+		err = errors.New("journal record checksum mismatch")
+		return
+	}
+	if op != durableOp_Deliver && op != durableOp_Ack {
+		err = errors.New("unrecognized journal record")
+		return
+	}
+	recSz = int64(durableRecordOverhead + len(data))
+	return
+}
+
+func appendDurableRecord(dst []byte, op byte, seq uint64, data []byte) []byte {
+	start := len(dst)
+	dst = append(dst, op)
+	dst = binary.LittleEndian.AppendUint64(dst, seq)
+	dst = binary.LittleEndian.AppendUint32(dst, uint32(len(data)))
+	dst = append(dst, data...)
+	dst = binary.LittleEndian.AppendUint32(dst, crc32.ChecksumIEEE(dst[start:]))
+	return dst
+}
+
+// writeRecord appends a record to the journal -- m.mu must be locked.
+func (m *DurableMailbox) writeRecord(op byte, seq uint64, data []byte) error {
+	if m.file == nil {
+		return errors.ErrClosed
+	}
+	rec := appendDurableRecord(make([]byte, 0, durableRecordOverhead+len(data)), op, seq, data)
+	n, err := m.file.Write(rec)
+	m.fileSz += int64(n)
+	if err != nil {
+		return err
+	}
+	if m.opts.SyncWrites {
+		return m.file.Sync()
+	}
+	return nil
+}
+
+// applyAck drops all unacked items with a seq <= the given seq -- m.mu must be locked.
+func (m *DurableMailbox) applyAck(seq uint64) {
+	if seq <= m.ackedSeq {
+		return
+	}
+	m.ackedSeq = seq
+
+	n := 0
+	for n < len(m.unacked) && m.unacked[n].Seq <= seq {
+		n++
+	}
+	m.unacked = m.unacked[n:]
+	m.inFlight -= n
+	if m.inFlight < 0 {
+		m.inFlight = 0
+	}
+}
+
+func (m *DurableMailbox) Notify() chan struct{} {
+	return m.chNotify
+}
+
+func (m *DurableMailbox) notify() {
+	select {
+	case m.chNotify <- struct{}{}:
+	default:
+	}
+}
+
+// Deliver journals and queues a copy of the given item, returning its sequence number.
+func (m *DurableMailbox) Deliver(data []byte) (uint64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	seq := m.nextSeq
+	if err := m.writeRecord(durableOp_Deliver, seq, data); err != nil {
+		return 0, err
+	}
+	m.nextSeq++
+	m.unacked = append(m.unacked, DurableItem{
+		Seq:  seq,
+		Data: append([]byte(nil), data...),
+	})
+	m.notify()
+	return seq, nil
+}
+
+// Retrieve returns the oldest item not yet retrieved, with ok == false if there is none.
+// The item remains in the journal until acked.
+func (m *DurableMailbox) Retrieve() (item DurableItem, ok bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.inFlight >= len(m.unacked) {
+		return item, false
+	}
+	item = m.unacked[m.inFlight]
+	m.inFlight++
+	return item, true
+}
+
+// Ack marks all items with a sequence number <= seq as done so they are not queued again when reopened.
+func (m *DurableMailbox) Ack(seq uint64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if seq <= m.ackedSeq {
+		return nil
+	}
+	if err := m.writeRecord(durableOp_Ack, seq, nil); err != nil {
+		return err
+	}
+	m.applyAck(seq)
+
+	if m.opts.CompactAtBytes > 0 && m.fileSz > m.opts.CompactAtBytes {
+		return m.compact()
+	}
+	return nil
+}
+
+// Len returns the number of items not yet acked.
+func (m *DurableMailbox) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.unacked)
+}
+
+// Compact rewrites the journal so that it only contains items not yet acked.
+func (m *DurableMailbox) Compact() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.compact()
+}
+
+func (m *DurableMailbox) compact() error {
+	if m.file == nil {
+		return errors.ErrClosed
+	}
+
+	tmpPathname := m.pathname + ".compact"
+	tmp, err := os.OpenFile(tmpPathname, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+
+	// The ack record preserves the seq floor in the event that no items remain.
+	buf := appendDurableRecord(nil, durableOp_Ack, m.ackedSeq, nil)
+	for _, item := range m.unacked {
+		buf = appendDurableRecord(buf, durableOp_Deliver, item.Seq, item.Data)
+	}
+	if _, err = tmp.Write(buf); err == nil {
+		err = tmp.Sync()
+	}
+	if err == nil {
+		err = os.Rename(tmpPathname, m.pathname)
+	}
+	if err != nil {
+		tmp.Close()
+		os.Remove(tmpPathname)
+		return err
+	}
+
+	m.file.Close()
+	m.file = tmp
+	m.fileSz = int64(len(buf))
+
+	// The rename itself is only durable once the directory is synced.
+	return syncDir(filepath.Dir(m.pathname))
+}
+
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	err = d.Sync()
+	if closeErr := d.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// Close closes the underlying journal.  Items not yet acked are queued again when reopened.
+func (m *DurableMailbox) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.file == nil {
+		return nil
+	}
+	err := m.file.Close()
+	m.file = nil
+	return err
+}
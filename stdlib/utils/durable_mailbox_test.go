@@ -0,0 +1,79 @@
+package utils_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/utils"
+)
+
+func TestDurableMailbox(t *testing.T) {
+	t.Parallel()
+
+	pathname := filepath.Join(t.TempDir(), "inbox.journal")
+
+	m, err := utils.OpenDurableMailbox(pathname, utils.DurableMailboxOpts{})
+	require.NoError(t, err)
+	for _, s := range []string{"a", "b", "c", "d"} {
+		_, err = m.Deliver([]byte(s))
+		require.NoError(t, err)
+	}
+
+	item, ok := m.Retrieve()
+	require.True(t, ok)
+	require.Equal(t, "a", string(item.Data))
+	require.NoError(t, m.Ack(item.Seq))
+
+	item, _ = m.Retrieve()
+	require.Equal(t, "b", string(item.Data)) // retrieved but never acked
+	require.NoError(t, m.Close())
+
+	// Simulate a crash mid-write by appending a partial record
+	f, err := os.OpenFile(pathname, os.O_WRONLY|os.O_APPEND, 0600)
+	require.NoError(t, err)
+	f.Write([]byte{1, 9, 9})
+	f.Close()
+
+	m, err = utils.OpenDurableMailbox(pathname, utils.DurableMailboxOpts{})
+	require.NoError(t, err)
+	require.Equal(t, 3, m.Len())
+	require.NoError(t, m.Close())
+
+	// A torn header claiming more data than the journal holds is discarded without being allocated
+	f, err = os.OpenFile(pathname, os.O_WRONLY|os.O_APPEND, 0600)
+	require.NoError(t, err)
+	f.Write([]byte{1, 5, 0, 0, 0, 0, 0, 0, 0, 0xff, 0xff, 0xff, 0xff, 'x'})
+	f.Close()
+
+	m, err = utils.OpenDurableMailbox(pathname, utils.DurableMailboxOpts{})
+	require.NoError(t, err)
+	require.Equal(t, 3, m.Len())
+
+	var got []string
+	for {
+		item, ok := m.Retrieve()
+		if !ok {
+			break
+		}
+		got = append(got, string(item.Data))
+	}
+	require.Equal(t, []string{"b", "c", "d"}, got)
+
+	require.NoError(t, m.Ack(3))
+	require.NoError(t, m.Compact())
+	seq, err := m.Deliver([]byte("e"))
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), seq)
+	require.NoError(t, m.Close())
+
+	m, err = utils.OpenDurableMailbox(pathname, utils.DurableMailboxOpts{})
+	require.NoError(t, err)
+	defer m.Close()
+	item, _ = m.Retrieve()
+	require.Equal(t, utils.DurableItem{Seq: 4, Data: []byte("d")}, item)
+	item, _ = m.Retrieve()
+	require.Equal(t, uint64(5), item.Seq)
+}
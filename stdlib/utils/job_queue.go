@@ -0,0 +1,294 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+var (
+	ErrJobQueueClosed = errors.New("job queue closed")
+	ErrJobQueueFull   = errors.New("job queue full")
+)
+
+// RetryPolicy specifies how a failed job is retried.  A zero value means the defaults.
+type RetryPolicy struct {
+	MaxAttempts int                  // most times a job is run, including the first (default 3)
+	MinBackoff  time.Duration        // delay before the first retry, doubling before each subsequent retry (default 100ms)
+	MaxBackoff  time.Duration        // upper bound for the delay before a retry (default 30s)
+	Jitter      float64              // fraction of each delay randomized to spread out retries, from 0 (none) to 1
+	Retryable   func(err error) bool // returns true if a job failing with the given error may be retried (default all errors)
+}
+
+func (policy RetryPolicy) withDefaults() RetryPolicy {
+	if policy.MaxAttempts <= 0 {
+		policy.MaxAttempts = 3
+	}
+	if policy.MinBackoff <= 0 {
+		policy.MinBackoff = 100 * time.Millisecond
+	}
+	if policy.MaxBackoff < policy.MinBackoff {
+		policy.MaxBackoff = max(30*time.Second, policy.MinBackoff)
+	}
+	policy.Jitter = min(max(policy.Jitter, 0), 1)
+	return policy
+}
+
+// Backoff returns the delay before the retry following the given (1-based) attempt.
+func (policy RetryPolicy) Backoff(attempt int) time.Duration {
+	delay := policy.MinBackoff
+	for i := 1; i < attempt && delay < policy.MaxBackoff; i++ {
+		delay *= 2
+	}
+	delay = min(delay, policy.MaxBackoff)
+	if policy.Jitter > 0 {
+		delay -= time.Duration(float64(delay) * policy.Jitter * rand.Float64())
+	}
+	return delay
+}
+
+// Job is a unit of work submitted to a JobQueue.
+type Job struct {
+	Label   string                          // describes the job (for logging)
+	Run     func(ctx context.Context) error // called for each attempt, where ctx is done once the attempt times out or the queue closes
+	Timeout time.Duration                   // most time an attempt may take (default JobQueueOpts.Timeout)
+	Retry   *RetryPolicy                    // if set, overrides JobQueueOpts.Retry for this job
+}
+
+// JobQueueOpts are the options used to create a JobQueue.
+type JobQueueOpts struct {
+	Workers  int           // number of jobs run at once (default 1)
+	Capacity int           // most jobs outstanding (queued, running, or awaiting retry); if 0, the queue is unbounded
+	Timeout  time.Duration // most time an attempt of a job may take (default no limit)
+	Retry    RetryPolicy   // how failed jobs are retried
+
+	// If set, called with each job failing its last attempt (or failing with an error that is not retryable), along
+	// with the error of that attempt and how many attempts were made.  It is called from a worker and ought to return promptly.
+	OnDeadLetter func(job *Job, err error, attempts int)
+}
+
+// JobQueueStats is a snapshot of a JobQueue's counters.
+type JobQueueStats struct {
+	Submitted    uint64 // jobs accepted by Submit()
+	Succeeded    uint64 // jobs completed without error
+	Retried      uint64 // attempts that failed and were scheduled to run again
+	DeadLettered uint64 // jobs that failed their last attempt
+	Outstanding  int    // jobs queued, running, or awaiting retry
+}
+
+// JobQueue runs submitted jobs on a fixed set of workers, retrying each failed job with exponential backoff and passing a
+// job that exhausts its retries to JobQueueOpts.OnDeadLetter.  It suits connectors calling flaky external services.
+//
+// A JobQueue runs until it is closed or the context it was created with is done (e.g. when a task.Context closes), at
+// which point jobs not yet completed are abandoned.  It is safe for concurrent use.
+type JobQueue struct {
+	opts     JobQueueOpts
+	ctx      context.Context
+	cancel   context.CancelFunc
+	chNotify chan struct{}
+	workers  sync.WaitGroup
+
+	mu     sync.Mutex
+	ready  []*queuedJob  // jobs due to run, oldest first
+	chIdle chan struct{} // closed and replaced each time the queue becomes idle (nil until a caller waits)
+	closed bool
+	stats  JobQueueStats
+}
+
+type queuedJob struct {
+	*Job
+	policy   RetryPolicy
+	attempts int
+}
+
+// NewJobQueue returns a JobQueue with the given options, running until Close is called or ctx is done.
+func NewJobQueue(ctx context.Context, opts JobQueueOpts) *JobQueue {
+	opts.Retry = opts.Retry.withDefaults()
+	if opts.Workers <= 0 {
+		opts.Workers = 1
+	}
+	q := &JobQueue{
+		opts:     opts,
+		chNotify: make(chan struct{}, 1),
+	}
+	q.ctx, q.cancel = context.WithCancel(ctx)
+	for i := 0; i < opts.Workers; i++ {
+		q.workers.Add(1)
+		go q.work()
+	}
+	context.AfterFunc(q.ctx, q.shutdown)
+	return q
+}
+
+// Submit queues the given job, returning ErrJobQueueFull if JobQueueOpts.Capacity jobs are outstanding or
+// ErrJobQueueClosed if the queue is closed.
+func (q *JobQueue) Submit(job *Job) error {
+	policy := q.opts.Retry
+	if job.Retry != nil {
+		policy = job.Retry.withDefaults()
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed || q.ctx.Err() != nil {
+		return ErrJobQueueClosed
+	}
+	if q.opts.Capacity > 0 && q.stats.Outstanding >= q.opts.Capacity {
+		return ErrJobQueueFull
+	}
+	q.stats.Submitted++
+	q.stats.Outstanding++
+	q.pushLocked(&queuedJob{Job: job, policy: policy})
+	return nil
+}
+
+// Stats returns a snapshot of this queue's counters.
+func (q *JobQueue) Stats() JobQueueStats {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.stats
+}
+
+// Drain blocks until no jobs are outstanding, returning ErrJobQueueClosed if the queue closes or ctx.Err() if ctx is done first.
+func (q *JobQueue) Drain(ctx context.Context) error {
+	for {
+		q.mu.Lock()
+		if q.closed {
+			q.mu.Unlock()
+			return ErrJobQueueClosed
+		}
+		if q.stats.Outstanding == 0 {
+			q.mu.Unlock()
+			return nil
+		}
+		if q.chIdle == nil {
+			q.chIdle = make(chan struct{})
+		}
+		chIdle := q.chIdle
+		q.mu.Unlock()
+
+		select {
+		case <-chIdle:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Close stops this queue, abandoning jobs not yet completed, and waits for running attempts to return.
+func (q *JobQueue) Close() {
+	q.cancel()
+	q.shutdown()
+	q.workers.Wait()
+}
+
+func (q *JobQueue) shutdown() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.closed = true
+	q.ready = nil
+	q.signalIdleLocked()
+}
+
+// pushLocked queues the given job to run and wakes a worker -- q.mu must be locked.
+func (q *JobQueue) pushLocked(job *queuedJob) {
+	q.ready = append(q.ready, job)
+	select {
+	case q.chNotify <- struct{}{}:
+	default:
+	}
+}
+
+func (q *JobQueue) work() {
+	defer q.workers.Done()
+	for {
+		select {
+		case <-q.chNotify:
+		case <-q.ctx.Done():
+			return
+		}
+		for {
+			job := q.pop()
+			if job == nil {
+				break
+			}
+			q.run(job)
+		}
+	}
+}
+
+func (q *JobQueue) pop() *queuedJob {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.ready) == 0 || q.closed {
+		return nil
+	}
+	job := q.ready[0]
+	q.ready[0] = nil
+	q.ready = q.ready[1:]
+	if len(q.ready) > 0 {
+		select {
+		case q.chNotify <- struct{}{}: // so another worker picks up the next job
+		default:
+		}
+	}
+	return job
+}
+
+// run runs an attempt of the given job, then retries or dead-letters it if it fails.
+func (q *JobQueue) run(job *queuedJob) {
+	timeout := job.Timeout
+	if timeout <= 0 {
+		timeout = q.opts.Timeout
+	}
+	ctx := q.ctx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	job.attempts++
+	err := job.Run(ctx)
+
+	if q.ctx.Err() != nil {
+		return // abandoned
+	}
+	retry := err != nil && job.attempts < job.policy.MaxAttempts && (job.policy.Retryable == nil || job.policy.Retryable(err))
+	if retry {
+		time.AfterFunc(job.policy.Backoff(job.attempts), func() {
+			q.mu.Lock()
+			defer q.mu.Unlock()
+			if !q.closed {
+				q.pushLocked(job)
+			}
+		})
+	} else if err != nil && q.opts.OnDeadLetter != nil {
+		q.opts.OnDeadLetter(job.Job, err, job.attempts)
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	switch {
+	case err == nil:
+		q.stats.Succeeded++
+	case retry:
+		q.stats.Retried++
+		return
+	default:
+		q.stats.DeadLettered++
+	}
+	q.stats.Outstanding--
+	if q.stats.Outstanding == 0 {
+		q.signalIdleLocked()
+	}
+}
+
+// signalIdleLocked releases any callers blocked in Drain() -- q.mu must be locked.
+func (q *JobQueue) signalIdleLocked() {
+	if q.chIdle != nil {
+		close(q.chIdle)
+		q.chIdle = nil
+	}
+}
@@ -0,0 +1,124 @@
+package utils_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/task"
+	"github.com/amp-3d/amp-sdk-go/stdlib/utils"
+)
+
+func TestJobQueue(t *testing.T) {
+	t.Parallel()
+
+	root, err := task.Start(&task.Task{Label: "connector"})
+	require.NoError(t, err)
+	defer root.Close()
+
+	var (
+		mu   sync.Mutex
+		dead []string
+	)
+	q := utils.NewJobQueue(root, utils.JobQueueOpts{
+		Workers:  2,
+		Capacity: 4,
+		Retry: utils.RetryPolicy{
+			MaxAttempts: 3,
+			MinBackoff:  time.Millisecond,
+			Jitter:      0.5,
+			Retryable:   func(err error) bool { return !errors.Is(err, errFatal) },
+		},
+		OnDeadLetter: func(job *utils.Job, err error, attempts int) {
+			mu.Lock()
+			dead = append(dead, job.Label+": "+err.Error()+" after "+strconv.Itoa(attempts))
+			mu.Unlock()
+		},
+	})
+
+	// A flaky job succeeds once retried
+	var flakyRuns atomic.Int32
+	require.NoError(t, q.Submit(&utils.Job{
+		Label: "flaky",
+		Run: func(ctx context.Context) error {
+			if flakyRuns.Add(1) < 3 {
+				return errors.New("try again")
+			}
+			return nil
+		},
+	}))
+
+	// A job exhausting its retries is dead-lettered, as is one failing with an error that is not retryable
+	require.NoError(t, q.Submit(&utils.Job{
+		Label: "down",
+		Run:   func(ctx context.Context) error { return errors.New("unavailable") },
+	}))
+	require.NoError(t, q.Submit(&utils.Job{
+		Label: "fatal",
+		Run:   func(ctx context.Context) error { return errFatal },
+	}))
+
+	// An attempt outliving its timeout sees its context done
+	require.NoError(t, q.Submit(&utils.Job{
+		Label:   "slow",
+		Timeout: 5 * time.Millisecond,
+		Retry:   &utils.RetryPolicy{MaxAttempts: 1},
+		Run: func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	}))
+	require.ErrorIs(t, q.Submit(&utils.Job{Run: func(ctx context.Context) error { return nil }}), utils.ErrJobQueueFull)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, q.Drain(ctx))
+	require.EqualValues(t, 3, flakyRuns.Load())
+	require.ElementsMatch(t, []string{
+		"down: unavailable after 3",
+		"fatal: fatal after 1",
+		"slow: context deadline exceeded after 1",
+	}, dead)
+	require.Equal(t, utils.JobQueueStats{Submitted: 4, Succeeded: 1, Retried: 4, DeadLettered: 3}, q.Stats())
+
+	// Closing the task.Context the queue runs under abandons its jobs
+	started := make(chan struct{})
+	require.NoError(t, q.Submit(&utils.Job{
+		Label: "stuck",
+		Run: func(ctx context.Context) error {
+			close(started)
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	}))
+	<-started
+	root.Close()
+	q.Close()
+	require.ErrorIs(t, q.Submit(&utils.Job{}), utils.ErrJobQueueClosed)
+	require.ErrorIs(t, q.Drain(ctx), utils.ErrJobQueueClosed)
+	require.Len(t, dead, 3)
+}
+
+var errFatal = errors.New("fatal")
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	t.Parallel()
+
+	policy := utils.RetryPolicy{MinBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+	require.Equal(t, 100*time.Millisecond, policy.Backoff(1))
+	require.Equal(t, 400*time.Millisecond, policy.Backoff(3))
+	require.Equal(t, time.Second, policy.Backoff(10))
+
+	policy.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		delay := policy.Backoff(2)
+		require.GreaterOrEqual(t, delay, 100*time.Millisecond)
+		require.LessOrEqual(t, delay, 200*time.Millisecond)
+	}
+}
@@ -1,77 +1,274 @@
 package utils
 
 import (
+	"context"
 	"sync"
 )
 
-type Mailbox struct {
+// Mailbox is the untyped form of MailboxOf and is retained for compatibility.
+type Mailbox = MailboxOf[any]
+
+// NewMailbox creates an untyped Mailbox -- see NewMailboxOf().
+func NewMailbox(capacity uint64) *Mailbox {
+	return NewMailboxOf[any](capacity)
+}
+
+// OverflowPolicy specifies what Deliver() does when a bounded mailbox is full.
+type OverflowPolicy int32
+
+const (
+	DropOldest OverflowPolicy = iota // the oldest queued item is discarded to make room (default)
+	DropNewest                       // the item being delivered is discarded
+	BlockDeliver                     // Deliver() blocks until space is available
+)
+
+// MailboxOpts are the options used to create a MailboxOf.
+type MailboxOpts struct {
+	Capacity uint64         // Max number of queued items; if 0, the mailbox is unbounded
+	Overflow OverflowPolicy // Deliver() behavior when Capacity is reached
+}
+
+// MailboxStats is a snapshot of a mailbox's counters, suitable for exporting queue health.
+type MailboxStats struct {
+	Delivered uint64 // Number of items queued
+	Retrieved uint64 // Number of items removed via Retrieve() or RetrieveAll()
+	Dropped   uint64 // Number of items discarded due to overflow or Clear()
+	HighWater uint64 // Max number of items queued at any one time
+	Depth     uint64 // Number of items currently queued
+}
+
+// MailboxOf is a concurrency-safe queue of items that signals Notify() on each delivery.
+type MailboxOf[T any] struct {
+	opts     MailboxOpts
 	chNotify chan struct{}
+	chSpace  chan struct{} // closed and replaced each time space is made (nil until a deliverer waits)
 	mu       sync.Mutex
-	queue    []interface{}
-	capacity uint64
+	queue    []T // oldest item first
+	onDrop   func(v T)
+	stats    MailboxStats
 }
 
-func NewMailbox(capacity uint64) *Mailbox {
-	queueCap := capacity
-	if queueCap == 0 {
-		queueCap = 100
-	}
-	return &Mailbox{
+// NewMailboxOf creates a MailboxOf[T] that holds up to the given number of items (or unbounded if capacity == 0).
+// When full, the oldest item is dropped.
+func NewMailboxOf[T any](capacity uint64) *MailboxOf[T] {
+	return NewMailboxWithOpts[T](MailboxOpts{
+		Capacity: capacity,
+	})
+}
+
+// NewMailboxWithOpts creates a MailboxOf[T] with the given options.
+func NewMailboxWithOpts[T any](opts MailboxOpts) *MailboxOf[T] {
+	m := &MailboxOf[T]{
+		opts:     opts,
 		chNotify: make(chan struct{}, 1),
-		queue:    make([]interface{}, 0, queueCap),
-		capacity: capacity,
 	}
+	m.queue = m.newQueue()
+	return m
 }
 
-func (m *Mailbox) Notify() chan struct{} {
+func (m *MailboxOf[T]) newQueue() []T {
+	queueCap := m.opts.Capacity
+	if queueCap == 0 || queueCap > 1000 {
+		queueCap = 100
+	}
+	return make([]T, 0, queueCap)
+}
+
+func (m *MailboxOf[T]) Notify() chan struct{} {
 	return m.chNotify
 }
 
-func (m *Mailbox) Deliver(x interface{}) {
+// OnDrop sets a callback invoked for each item this mailbox discards (outside of any internal lock).
+// The callback must not block as it is called from within Deliver() or Clear().
+func (m *MailboxOf[T]) OnDrop(fn func(v T)) {
+	m.mu.Lock()
+	m.onDrop = fn
+	m.mu.Unlock()
+}
+
+// Stats returns a snapshot of this mailbox's counters.
+func (m *MailboxOf[T]) Stats() MailboxStats {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	m.queue = append([]interface{}{x}, m.queue...)
-	if uint64(len(m.queue)) > m.capacity && m.capacity > 0 {
-		m.queue = m.queue[:len(m.queue)-1]
+	stats := m.stats
+	stats.Depth = uint64(len(m.queue))
+	return stats
+}
+
+// Deliver queues the given item, handling a full mailbox according to MailboxOpts.Overflow.
+func (m *MailboxOf[T]) Deliver(x T) {
+	if m.opts.Overflow == BlockDeliver {
+		m.DeliverCtx(context.Background(), x)
+		return
 	}
 
-	select {
-	case m.chNotify <- struct{}{}:
-	default:
+	var (
+		dropped T
+		didDrop bool
+	)
+
+	m.mu.Lock()
+	if m.isFull() {
+		didDrop = true
+		if m.opts.Overflow == DropNewest {
+			dropped = x
+		} else {
+			dropped = m.popFront()
+			m.pushBack(x)
+		}
+		m.stats.Dropped++
+	} else {
+		m.pushBack(x)
+	}
+	onDrop := m.onDrop
+	m.mu.Unlock()
+
+	if didDrop && onDrop != nil {
+		onDrop(dropped)
 	}
 }
 
-func (m *Mailbox) Retrieve() interface{} {
+// DeliverCtx queues the given item, blocking until space is available or ctx is done.
+// Regardless of MailboxOpts.Overflow, no queued item is dropped.
+//
+// Returns ctx.Err() if ctx is done before the item could be queued.
+func (m *MailboxOf[T]) DeliverCtx(ctx context.Context, x T) error {
+	for {
+		m.mu.Lock()
+		if !m.isFull() {
+			m.pushBack(x)
+			m.mu.Unlock()
+			return nil
+		}
+		if m.chSpace == nil {
+			m.chSpace = make(chan struct{})
+		}
+		chSpace := m.chSpace
+		m.mu.Unlock()
+
+		select {
+		case <-chSpace:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Retrieve removes and returns the oldest item, returning the zero value of T if the mailbox is empty.
+func (m *MailboxOf[T]) Retrieve() T {
+	x, _ := m.TryRetrieve()
+	return x
+}
+
+// TryRetrieve removes and returns the oldest item, with ok == false if the mailbox is empty.
+// Use this when the zero value of T is a valid item.
+func (m *MailboxOf[T]) TryRetrieve() (x T, ok bool) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
 	if len(m.queue) == 0 {
-		return nil
+		return x, false
 	}
-	x := m.queue[len(m.queue)-1]
-	m.queue = m.queue[:len(m.queue)-1]
-	return x
+	x = m.popFront()
+	m.stats.Retrieved++
+	m.signalSpace()
+	return x, true
 }
 
-func (m *Mailbox) RetrieveAll() []interface{} {
+// RetrieveAll removes and returns all queued items, oldest first.
+func (m *MailboxOf[T]) RetrieveAll() []T {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	queue := m.queue
 	m.queue = nil
-	for i, j := 0, len(queue)-1; i < j; i, j = i+1, j-1 {
-		queue[i], queue[j] = queue[j], queue[i]
-	}
+	m.stats.Retrieved += uint64(len(queue))
+	m.signalSpace()
 	return queue
 }
 
-func (m *Mailbox) Clear() {
+// RetrieveUpTo removes and returns up to n of the oldest queued items in a single locked operation, oldest first.
+// Returns nil if the mailbox is empty or n <= 0.
+func (m *MailboxOf[T]) RetrieveUpTo(n int) []T {
+	if n <= 0 {
+		return nil
+	}
+
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	queueCap := m.capacity
-	if queueCap == 0 {
-		queueCap = 100
+	if n >= len(m.queue) {
+		if len(m.queue) == 0 {
+			return nil
+		}
+		queue := m.queue
+		m.queue = m.newQueue()
+		m.stats.Retrieved += uint64(len(queue))
+		m.signalSpace()
+		return queue
+	}
+
+	var zero T
+	batch := make([]T, n)
+	copy(batch, m.queue[:n])
+	for i := 0; i < n; i++ {
+		m.queue[i] = zero
+	}
+	m.queue = m.queue[n:]
+	m.stats.Retrieved += uint64(n)
+	m.signalSpace()
+	return batch
+}
+
+// Clear discards all queued items, counting each as dropped.
+func (m *MailboxOf[T]) Clear() {
+	m.mu.Lock()
+	dropped := m.queue
+	m.queue = m.newQueue()
+	m.stats.Dropped += uint64(len(dropped))
+	m.signalSpace()
+	onDrop := m.onDrop
+	m.mu.Unlock()
+
+	if onDrop != nil {
+		for _, x := range dropped {
+			onDrop(x)
+		}
+	}
+}
+
+// isFull returns true if the queue is at capacity -- m.mu must be locked.
+func (m *MailboxOf[T]) isFull() bool {
+	return m.opts.Capacity > 0 && uint64(len(m.queue)) >= m.opts.Capacity
+}
+
+// pushBack appends x to the queue and signals Notify() -- m.mu must be locked.
+func (m *MailboxOf[T]) pushBack(x T) {
+	m.queue = append(m.queue, x)
+	m.stats.Delivered++
+	if depth := uint64(len(m.queue)); depth > m.stats.HighWater {
+		m.stats.HighWater = depth
+	}
+
+	select {
+	case m.chNotify <- struct{}{}:
+	default:
+	}
+}
+
+// popFront removes and returns the oldest item -- m.mu must be locked and the queue must be non-empty.
+func (m *MailboxOf[T]) popFront() T {
+	var zero T
+	x := m.queue[0]
+	m.queue[0] = zero // show GC some love
+	m.queue = m.queue[1:]
+	return x
+}
+
+// signalSpace releases any deliverers blocked in DeliverCtx() -- m.mu must be locked.
+func (m *MailboxOf[T]) signalSpace() {
+	if m.chSpace != nil {
+		close(m.chSpace)
+		m.chSpace = nil
 	}
-	m.queue = make([]interface{}, 0, queueCap)
 }
@@ -1,6 +1,7 @@
 package utils_test
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -52,3 +53,201 @@ func TestMailbox(t *testing.T) {
 	}
 	require.Equal(t, expected, recvd)
 }
+
+func TestMailboxOf(t *testing.T) {
+	t.Parallel()
+
+	m := utils.NewMailboxOf[int](3)
+	for i := 0; i < 5; i++ {
+		m.Deliver(i)
+	}
+
+	var recvd []int
+	for {
+		x, ok := m.TryRetrieve()
+		if !ok {
+			break
+		}
+		recvd = append(recvd, x)
+	}
+	require.Equal(t, []int{2, 3, 4}, recvd)
+
+	m.Deliver(0)
+	require.Equal(t, 0, m.Retrieve())
+	require.Equal(t, 0, m.Retrieve())
+	_, ok := m.TryRetrieve()
+	require.False(t, ok)
+}
+
+func TestMailboxOverflow(t *testing.T) {
+	t.Parallel()
+
+	t.Run("drop newest", func(t *testing.T) {
+		m := utils.NewMailboxWithOpts[int](utils.MailboxOpts{Capacity: 2, Overflow: utils.DropNewest})
+		for i := 0; i < 4; i++ {
+			m.Deliver(i)
+		}
+		require.Equal(t, []int{0, 1}, m.RetrieveAll())
+	})
+
+	t.Run("deliver ctx", func(t *testing.T) {
+		m := utils.NewMailboxWithOpts[int](utils.MailboxOpts{Capacity: 1, Overflow: utils.BlockDeliver})
+		m.Deliver(0)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		require.ErrorIs(t, m.DeliverCtx(ctx, 1), context.DeadlineExceeded)
+
+		chDelivered := make(chan struct{})
+		go func() {
+			m.Deliver(2)
+			close(chDelivered)
+		}()
+
+		select {
+		case <-chDelivered:
+			t.Fatal("Deliver() should block while full")
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		require.Equal(t, 0, m.Retrieve())
+		<-chDelivered
+		require.Equal(t, []int{2}, m.RetrieveAll())
+	})
+}
+
+func TestMailboxStats(t *testing.T) {
+	t.Parallel()
+
+	m := utils.NewMailboxOf[int](3)
+
+	var dropped []int
+	m.OnDrop(func(v int) {
+		dropped = append(dropped, v)
+	})
+
+	for i := 0; i < 5; i++ {
+		m.Deliver(i)
+	}
+	m.Retrieve()
+	m.Clear()
+
+	require.Equal(t, []int{0, 1, 3, 4}, dropped)
+	require.Equal(t, utils.MailboxStats{
+		Delivered: 5,
+		Retrieved: 1,
+		Dropped:   4,
+		HighWater: 3,
+	}, m.Stats())
+}
+
+func TestPriorityMailbox(t *testing.T) {
+	t.Parallel()
+
+	m := utils.NewPriorityMailboxOf[string](2, 0)
+	m.Deliver(1, "bulk-1")
+	m.Deliver(1, "bulk-2")
+	m.Deliver(0, "ctl-1")
+	m.Deliver(5, "bulk-3")
+	m.Deliver(0, "ctl-2")
+	m.Deliver(0, "ctl-3")
+
+	select {
+	case <-m.Notify():
+	default:
+		t.Fatal("expected notify")
+	}
+
+	x, priority, ok := m.TryRetrieve()
+	require.True(t, ok)
+	require.Equal(t, 0, priority)
+	require.Equal(t, "ctl-2", x)
+	require.Equal(t, []string{"ctl-3", "bulk-1", "bulk-2", "bulk-3"}, m.RetrieveAll())
+	require.Equal(t, uint64(1), m.Lane(0).Stats().Dropped)
+}
+
+func TestMailboxRetrieveUpTo(t *testing.T) {
+	t.Parallel()
+
+	m := utils.NewMailboxOf[int](0)
+	for i := 0; i < 5; i++ {
+		m.Deliver(i)
+	}
+	require.Equal(t, []int{0, 1}, m.RetrieveUpTo(2))
+	require.Equal(t, []int{2, 3, 4}, m.RetrieveUpTo(10))
+	require.Nil(t, m.RetrieveUpTo(10))
+	require.Equal(t, uint64(5), m.Stats().Retrieved)
+}
+
+const benchBatchSz = 256
+
+func BenchmarkMailboxRetrieve(b *testing.B) {
+	benchmarkMailboxDrain(b, func(m *utils.MailboxOf[int]) int {
+		n := 0
+		for {
+			if _, ok := m.TryRetrieve(); !ok {
+				return n
+			}
+			n++
+		}
+	})
+}
+
+func BenchmarkMailboxRetrieveUpTo(b *testing.B) {
+	benchmarkMailboxDrain(b, func(m *utils.MailboxOf[int]) int {
+		n := 0
+		for {
+			batch := m.RetrieveUpTo(benchBatchSz)
+			if len(batch) == 0 {
+				return n
+			}
+			n += len(batch)
+		}
+	})
+}
+
+func BenchmarkMailboxRetrieveAll(b *testing.B) {
+	benchmarkMailboxDrain(b, func(m *utils.MailboxOf[int]) int {
+		return len(m.RetrieveAll())
+	})
+}
+
+// benchmarkMailboxDrain delivers b.N items from a concurrent producer while the consumer drains using the given func.
+func benchmarkMailboxDrain(b *testing.B, drain func(m *utils.MailboxOf[int]) int) {
+	m := utils.NewMailboxOf[int](0)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	go func() {
+		for i := 0; i < b.N; i++ {
+			m.Deliver(i)
+		}
+	}()
+
+	for recvd := 0; recvd < b.N; {
+		<-m.Notify()
+		recvd += drain(m)
+	}
+}
+
+func TestBroadcaster(t *testing.T) {
+	t.Parallel()
+
+	b := utils.NewBroadcasterOf[int](utils.MailboxOpts{Capacity: 2})
+	sub1 := b.Subscribe()
+	sub2 := b.Subscribe()
+
+	b.Deliver(1)
+	require.Equal(t, 1, sub1.Retrieve())
+	b.Deliver(2)
+	b.Deliver(3)
+
+	require.True(t, b.Unsubscribe(sub2))
+	require.False(t, b.Unsubscribe(sub2))
+	b.Deliver(4)
+
+	require.Equal(t, []int{3, 4}, sub1.RetrieveAll())
+	require.Equal(t, []int{2, 3}, sub2.RetrieveAll())
+	require.Equal(t, uint64(1), sub2.Stats().Dropped)
+}
@@ -0,0 +1,170 @@
+package utils
+
+import (
+	"context"
+	"math/bits"
+	"runtime"
+	"sync/atomic"
+)
+
+// MPSCRing is a bounded, lock-free queue of items with many producers and a single consumer, offering the shape of a
+// MailboxOf (Notify and Retrieve) without its lock.  It suits hot paths where many goroutines deliver to one dispatcher
+// and a MailboxOf shows lock contention.
+//
+// Any goroutine may deliver to a MPSCRing, but only one goroutine at a time may retrieve from it.  Unlike a MailboxOf, a
+// full MPSCRing cannot drop its oldest item: Deliver drops the item being delivered, and DeliverCtx waits for space.
+type MPSCRing[T any] struct {
+	mask     uint64
+	slots    []ringSlot[T]
+	chNotify chan struct{}
+
+	_         [64]byte      // keeps the producer and consumer counters on separate cache lines
+	head      atomic.Uint64 // position of the next item delivered
+	dropped   atomic.Uint64
+	_         [64]byte
+	tail      atomic.Uint64 // position of the next item retrieved (stored only by the consumer)
+	highWater atomic.Uint64
+}
+
+type ringSlot[T any] struct {
+	seq atomic.Uint64 // == position if the slot is free to deliver to, position+1 if it holds the item at position
+	val T
+}
+
+// NewMPSCRing creates a MPSCRing holding up to the given number of items, rounded up to a power of two (and at least 2).
+func NewMPSCRing[T any](capacity uint64) *MPSCRing[T] {
+	capacity = 1 << bits.Len64(max(capacity, 2)-1)
+	r := &MPSCRing[T]{
+		mask:     capacity - 1,
+		slots:    make([]ringSlot[T], capacity),
+		chNotify: make(chan struct{}, 1),
+	}
+	for i := range r.slots {
+		r.slots[i].seq.Store(uint64(i))
+	}
+	return r
+}
+
+func (r *MPSCRing[T]) Notify() chan struct{} {
+	return r.chNotify
+}
+
+// Cap returns the most items this ring holds.
+func (r *MPSCRing[T]) Cap() int {
+	return len(r.slots)
+}
+
+// Len returns the number of items queued (including any whose delivery is underway).
+func (r *MPSCRing[T]) Len() int {
+	tail := r.tail.Load()
+	return int(r.head.Load() - tail)
+}
+
+// Stats returns a snapshot of this ring's counters, where HighWater is the most items the consumer has seen queued.
+func (r *MPSCRing[T]) Stats() MailboxStats {
+	tail := r.tail.Load()
+	head := r.head.Load()
+	return MailboxStats{
+		Delivered: head,
+		Retrieved: tail,
+		Dropped:   r.dropped.Load(),
+		HighWater: r.highWater.Load(),
+		Depth:     head - tail,
+	}
+}
+
+// Deliver queues the given item, returning false (and counting it as dropped) if the ring is full.
+func (r *MPSCRing[T]) Deliver(x T) bool {
+	if !r.tryDeliver(x) {
+		r.dropped.Add(1)
+		return false
+	}
+	return true
+}
+
+// DeliverCtx queues the given item, yielding until space is available or ctx is done.
+//
+// Returns ctx.Err() if ctx is done before the item could be queued.
+func (r *MPSCRing[T]) DeliverCtx(ctx context.Context, x T) error {
+	for !r.tryDeliver(x) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		runtime.Gosched()
+	}
+	return nil
+}
+
+func (r *MPSCRing[T]) tryDeliver(x T) bool {
+	pos := r.head.Load()
+	for {
+		slot := &r.slots[pos&r.mask]
+		switch diff := int64(slot.seq.Load() - pos); {
+		case diff == 0:
+			if r.head.CompareAndSwap(pos, pos+1) {
+				slot.val = x
+				slot.seq.Store(pos + 1)
+				if len(r.chNotify) == 0 { // skips locking the channel while a signal is already pending
+					select {
+					case r.chNotify <- struct{}{}:
+					default:
+					}
+				}
+				return true
+			}
+			pos = r.head.Load()
+		case diff < 0:
+			return false // the slot still holds the item from a lap ago
+		default:
+			pos = r.head.Load() // another producer claimed this position
+		}
+	}
+}
+
+// Retrieve removes and returns the oldest item, returning the zero value of T if the ring is empty.
+func (r *MPSCRing[T]) Retrieve() T {
+	x, _ := r.TryRetrieve()
+	return x
+}
+
+// TryRetrieve removes and returns the oldest item, with ok == false if the ring is empty.
+//
+// An item whose delivery is still underway is not yet retrievable, but its delivery signals Notify() once complete.
+func (r *MPSCRing[T]) TryRetrieve() (x T, ok bool) {
+	pos := r.tail.Load()
+	slot := &r.slots[pos&r.mask]
+	if slot.seq.Load() != pos+1 {
+		return x, false
+	}
+	if depth := r.head.Load() - pos; depth > r.highWater.Load() {
+		r.highWater.Store(depth)
+	}
+	var zero T
+	x = slot.val
+	slot.val = zero // show GC some love
+	slot.seq.Store(pos + r.mask + 1)
+	r.tail.Store(pos + 1)
+	return x, true
+}
+
+// RetrieveUpTo removes and returns up to n of the oldest queued items, oldest first.
+// Returns nil if the ring is empty or n <= 0.
+func (r *MPSCRing[T]) RetrieveUpTo(n int) []T {
+	var batch []T
+	for len(batch) < n {
+		x, ok := r.TryRetrieve()
+		if !ok {
+			break
+		}
+		if batch == nil {
+			batch = make([]T, 0, min(n, r.Len()+1))
+		}
+		batch = append(batch, x)
+	}
+	return batch
+}
+
+// RetrieveAll removes and returns all queued items, oldest first.
+func (r *MPSCRing[T]) RetrieveAll() []T {
+	return r.RetrieveUpTo(len(r.slots))
+}
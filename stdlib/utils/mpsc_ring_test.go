@@ -0,0 +1,135 @@
+package utils_test
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/utils"
+)
+
+func TestMPSCRing(t *testing.T) {
+	t.Parallel()
+
+	r := utils.NewMPSCRing[int](3)
+	require.Equal(t, 4, r.Cap())
+	_, ok := r.TryRetrieve()
+	require.False(t, ok)
+
+	// A full ring drops the item being delivered
+	for i := 0; i < 5; i++ {
+		r.Deliver(i)
+	}
+	select {
+	case <-r.Notify():
+	default:
+		t.Fatal("expected notify")
+	}
+	require.Equal(t, 4, r.Len())
+	require.Equal(t, []int{0, 1}, r.RetrieveUpTo(2))
+	r.Deliver(5)
+	require.Equal(t, []int{2, 3, 5}, r.RetrieveAll())
+	require.Nil(t, r.RetrieveAll())
+	require.Equal(t, utils.MailboxStats{Delivered: 5, Retrieved: 5, Dropped: 1, HighWater: 4}, r.Stats())
+
+	// DeliverCtx waits for space
+	for i := 0; i < 4; i++ {
+		require.NoError(t, r.DeliverCtx(context.Background(), i))
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, r.DeliverCtx(ctx, 4), context.DeadlineExceeded)
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		r.Retrieve()
+	}()
+	require.NoError(t, r.DeliverCtx(context.Background(), 4))
+	require.Equal(t, []int{1, 2, 3, 4}, r.RetrieveAll())
+}
+
+func TestMPSCRingProducers(t *testing.T) {
+	t.Parallel()
+
+	const (
+		producers   = 8
+		perProducer = 2000
+	)
+	r := utils.NewMPSCRing[[2]int](64)
+
+	var wg sync.WaitGroup
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			for i := 0; i < perProducer; i++ {
+				require.NoError(t, r.DeliverCtx(context.Background(), [2]int{p, i}))
+			}
+		}(p)
+	}
+
+	// Each producer's items arrive in the order delivered
+	next := make([]int, producers)
+	for recvd := 0; recvd < producers*perProducer; {
+		select {
+		case <-r.Notify():
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out after " + strconv.Itoa(recvd) + " items")
+		}
+		for _, x := range r.RetrieveAll() {
+			require.Equal(t, next[x[0]], x[1])
+			next[x[0]]++
+			recvd++
+		}
+	}
+	wg.Wait()
+	require.Zero(t, r.Len())
+	require.Zero(t, r.Stats().Dropped)
+}
+
+// BenchmarkMPSC compares MPSCRing with MailboxOf, delivering b.N items from concurrent producers to a single consumer.
+func BenchmarkMPSC(b *testing.B) {
+	for _, producers := range []int{1, 4, 16} {
+		b.Run("Mailbox/producers="+strconv.Itoa(producers), func(b *testing.B) {
+			m := utils.NewMailboxWithOpts[int](utils.MailboxOpts{Capacity: 1024, Overflow: utils.BlockDeliver})
+			benchmarkMPSC(b, producers, m.Notify(), m.Deliver, func() int {
+				return len(m.RetrieveUpTo(benchBatchSz))
+			})
+		})
+		b.Run("MPSCRing/producers="+strconv.Itoa(producers), func(b *testing.B) {
+			r := utils.NewMPSCRing[int](1024)
+			benchmarkMPSC(b, producers, r.Notify(), func(x int) {
+				r.DeliverCtx(context.Background(), x)
+			}, func() int {
+				return len(r.RetrieveUpTo(benchBatchSz))
+			})
+		})
+	}
+}
+
+func benchmarkMPSC(b *testing.B, producers int, notify chan struct{}, deliver func(x int), drain func() int) {
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for p := 0; p < producers; p++ {
+		n := b.N / producers
+		if p == 0 {
+			n += b.N % producers
+		}
+		go func() {
+			for i := 0; i < n; i++ {
+				deliver(i)
+			}
+		}()
+	}
+
+	for recvd := 0; recvd < b.N; {
+		<-notify
+		for n := drain(); n > 0; n = drain() {
+			recvd += n
+		}
+	}
+}
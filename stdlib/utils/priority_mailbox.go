@@ -0,0 +1,125 @@
+package utils
+
+// PriorityMailbox is the untyped form of PriorityMailboxOf.
+type PriorityMailbox = PriorityMailboxOf[any]
+
+// NewPriorityMailbox creates an untyped PriorityMailbox -- see NewPriorityMailboxOf().
+func NewPriorityMailbox(capacities ...uint64) *PriorityMailbox {
+	return NewPriorityMailboxOf[any](capacities...)
+}
+
+// PriorityMailboxOf multiplexes several mailbox "lanes" behind a single Notify() channel.
+// Lane 0 has the highest priority: Retrieve() only returns an item from a lane once all lanes before it are empty.
+//
+// This allows low-volume control messages to share a consumer with high-volume bulk messages without being starved.
+type PriorityMailboxOf[T any] struct {
+	chNotify chan struct{}
+	lanes    []*MailboxOf[T]
+}
+
+// NewPriorityMailboxOf creates a PriorityMailboxOf[T] with one lane per given capacity (see NewMailboxOf()).
+// If no capacities are given, a single unbounded lane is created.
+func NewPriorityMailboxOf[T any](capacities ...uint64) *PriorityMailboxOf[T] {
+	opts := make([]MailboxOpts, len(capacities))
+	for i, capacity := range capacities {
+		opts[i].Capacity = capacity
+	}
+	return NewPriorityMailboxWithOpts[T](opts...)
+}
+
+// NewPriorityMailboxWithOpts creates a PriorityMailboxOf[T] with one lane per given MailboxOpts.
+func NewPriorityMailboxWithOpts[T any](laneOpts ...MailboxOpts) *PriorityMailboxOf[T] {
+	if len(laneOpts) == 0 {
+		laneOpts = []MailboxOpts{{}}
+	}
+	m := &PriorityMailboxOf[T]{
+		chNotify: make(chan struct{}, 1),
+		lanes:    make([]*MailboxOf[T], len(laneOpts)),
+	}
+	for i, opts := range laneOpts {
+		m.lanes[i] = NewMailboxWithOpts[T](opts)
+	}
+	return m
+}
+
+func (m *PriorityMailboxOf[T]) Notify() chan struct{} {
+	return m.chNotify
+}
+
+// NumLanes returns the number of priority lanes in this mailbox.
+func (m *PriorityMailboxOf[T]) NumLanes() int {
+	return len(m.lanes)
+}
+
+// Lane returns the underlying mailbox for the given priority, clamped to the available lanes.
+// This offers access to per-lane OnDrop() and Stats().
+//
+// Items delivered directly to a lane do not signal Notify() -- use Deliver() instead.
+func (m *PriorityMailboxOf[T]) Lane(priority int) *MailboxOf[T] {
+	if priority < 0 {
+		priority = 0
+	} else if priority >= len(m.lanes) {
+		priority = len(m.lanes) - 1
+	}
+	return m.lanes[priority]
+}
+
+// Deliver queues the given item in the lane for the given priority (0 is highest).
+// Out of range priorities are clamped to the nearest lane.
+func (m *PriorityMailboxOf[T]) Deliver(priority int, x T) {
+	m.Lane(priority).Deliver(x)
+	m.notify()
+}
+
+// Retrieve removes and returns the oldest item of the highest priority non-empty lane.
+// Returns the zero value of T if all lanes are empty.
+func (m *PriorityMailboxOf[T]) Retrieve() T {
+	x, _, _ := m.TryRetrieve()
+	return x
+}
+
+// TryRetrieve is similar to Retrieve() but also returns the priority of the lane the item came from.
+// If all lanes are empty, ok == false.
+func (m *PriorityMailboxOf[T]) TryRetrieve() (x T, priority int, ok bool) {
+	for i, lane := range m.lanes {
+		if x, ok = lane.TryRetrieve(); ok {
+			return x, i, true
+		}
+	}
+	return x, -1, false
+}
+
+// RetrieveAll removes and returns all queued items in priority order.
+func (m *PriorityMailboxOf[T]) RetrieveAll() []T {
+	var all []T
+	for _, lane := range m.lanes {
+		all = append(all, lane.RetrieveAll()...)
+	}
+	return all
+}
+
+// RetrieveUpTo removes and returns up to n queued items in priority order.
+func (m *PriorityMailboxOf[T]) RetrieveUpTo(n int) []T {
+	var batch []T
+	for _, lane := range m.lanes {
+		if n <= len(batch) {
+			break
+		}
+		batch = append(batch, lane.RetrieveUpTo(n-len(batch))...)
+	}
+	return batch
+}
+
+// Clear discards all queued items in all lanes.
+func (m *PriorityMailboxOf[T]) Clear() {
+	for _, lane := range m.lanes {
+		lane.Clear()
+	}
+}
+
+func (m *PriorityMailboxOf[T]) notify() {
+	select {
+	case m.chNotify <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,172 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// RateLimiterOpts are the options used to create a RateLimiter.
+type RateLimiterOpts struct {
+	Rate  float64          // tokens added per second; if <= 0, the limiter is unlimited
+	Burst int64            // most tokens held at once, and so the most taken at once without waiting (default Rate, and at least 1)
+	Now   func() time.Time // returns the current time (default time.Now)
+}
+
+// RateLimiter is a token bucket: tokens accrue at a given rate up to a given burst, and each event takes tokens.
+// Its balance may go negative, where a negative balance is the debt to be repaid (by waiting) before proceeding, so a
+// large event is never starved by a stream of small ones.  It is safe for concurrent use.
+type RateLimiter struct {
+	now    func() time.Time
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter returns a RateLimiter with the given options, starting with a full bucket.
+func NewRateLimiter(opts RateLimiterOpts) *RateLimiter {
+	lim := &RateLimiter{
+		now: opts.Now,
+	}
+	if lim.now == nil {
+		lim.now = time.Now
+	}
+	lim.rate, lim.burst = opts.Rate, burstFor(opts.Rate, opts.Burst)
+	lim.tokens, lim.last = lim.burst, lim.now()
+	return lim
+}
+
+func burstFor(rate float64, burst int64) float64 {
+	if burst > 0 {
+		return float64(burst)
+	}
+	return math.Max(1, math.Ceil(rate))
+}
+
+// Rate returns the tokens added per second (or <= 0 if unlimited).
+func (lim *RateLimiter) Rate() float64 {
+	lim.mu.Lock()
+	defer lim.mu.Unlock()
+	return lim.rate
+}
+
+// Burst returns the most tokens held at once.
+func (lim *RateLimiter) Burst() int64 {
+	lim.mu.Lock()
+	defer lim.mu.Unlock()
+	return int64(lim.burst)
+}
+
+// Tokens returns the current balance, which is negative while in debt.
+func (lim *RateLimiter) Tokens() float64 {
+	lim.mu.Lock()
+	defer lim.mu.Unlock()
+	lim.accrue(lim.now())
+	return lim.tokens
+}
+
+// SetRate changes the rate and burst (see RateLimiterOpts), with tokens accrued at the previous rate until now.
+// A limiter going from unlimited to limited starts with a full bucket.
+func (lim *RateLimiter) SetRate(rate float64, burst int64) {
+	lim.mu.Lock()
+	defer lim.mu.Unlock()
+
+	now := lim.now()
+	if lim.rate > 0 {
+		lim.accrue(now)
+	} else {
+		lim.tokens = burstFor(rate, burst)
+	}
+	lim.rate, lim.burst, lim.last = rate, burstFor(rate, burst), now
+	lim.tokens = math.Min(lim.tokens, lim.burst)
+}
+
+// Allow is shorthand for AllowN(1).
+func (lim *RateLimiter) Allow() bool {
+	return lim.AllowN(1)
+}
+
+// AllowN takes n tokens if the balance covers them, returning false (and taking none) if not.
+func (lim *RateLimiter) AllowN(n int64) bool {
+	lim.mu.Lock()
+	defer lim.mu.Unlock()
+	if lim.rate <= 0 {
+		return true
+	}
+	lim.accrue(lim.now())
+	if lim.tokens < float64(n) {
+		return false
+	}
+	lim.tokens -= float64(n)
+	return true
+}
+
+// ReserveN takes n tokens, returning how long the caller must wait before proceeding (0 if the balance covered them).
+// Tokens reserved but not used can be returned via CancelN.
+func (lim *RateLimiter) ReserveN(n int64) time.Duration {
+	lim.mu.Lock()
+	defer lim.mu.Unlock()
+	if lim.rate <= 0 {
+		return 0
+	}
+	lim.accrue(lim.now())
+	lim.tokens -= float64(n)
+	if lim.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-lim.tokens / lim.rate * float64(time.Second))
+}
+
+// CancelN returns n tokens previously taken via ReserveN.
+func (lim *RateLimiter) CancelN(n int64) {
+	lim.mu.Lock()
+	defer lim.mu.Unlock()
+	if lim.rate > 0 {
+		lim.tokens = math.Min(lim.tokens+float64(n), lim.burst)
+	}
+}
+
+// Wait is shorthand for WaitN(ctx, 1).
+func (lim *RateLimiter) Wait(ctx context.Context) error {
+	return lim.WaitN(ctx, 1)
+}
+
+// WaitN takes n tokens, blocking until the caller may proceed or ctx is done.
+//
+// If ctx is done first, or its deadline would pass before the caller may proceed, the tokens are returned and an error
+// wrapping the ctx error is returned.
+func (lim *RateLimiter) WaitN(ctx context.Context, n int64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	wait := lim.ReserveN(n)
+	if wait <= 0 {
+		return nil
+	}
+	if deadline, ok := ctx.Deadline(); ok && lim.now().Add(wait).After(deadline) {
+		lim.CancelN(n)
+		return fmt.Errorf("%w: rate limit wait of %v exceeds deadline", context.DeadlineExceeded, wait.Round(time.Millisecond))
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		lim.CancelN(n)
+		return ctx.Err()
+	}
+}
+
+// accrue adds the tokens earned since the last accrual -- lim.mu must be locked.
+func (lim *RateLimiter) accrue(now time.Time) {
+	if elapsed := now.Sub(lim.last); elapsed > 0 {
+		lim.tokens = math.Min(lim.tokens+elapsed.Seconds()*lim.rate, lim.burst)
+		lim.last = now
+	}
+}
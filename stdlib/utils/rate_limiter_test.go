@@ -0,0 +1,84 @@
+package utils_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/utils"
+)
+
+func TestRateLimiter(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1_700_000_000, 0)
+	lim := utils.NewRateLimiter(utils.RateLimiterOpts{
+		Rate:  10,
+		Burst: 5,
+		Now:   func() time.Time { return now },
+	})
+
+	// A full bucket allows a burst, then tokens accrue at the rate
+	for i := 0; i < 5; i++ {
+		require.True(t, lim.Allow())
+	}
+	require.False(t, lim.Allow())
+	now = now.Add(250 * time.Millisecond)
+	require.True(t, lim.AllowN(2))
+	require.False(t, lim.AllowN(1))
+	now = now.Add(time.Hour)
+	require.EqualValues(t, 5, lim.Tokens())
+
+	// A reservation may go into debt, which is repaid by waiting
+	require.Zero(t, lim.ReserveN(5))
+	require.Equal(t, time.Second, lim.ReserveN(10))
+	lim.CancelN(10)
+	require.Zero(t, lim.Tokens())
+
+	// Changing the rate keeps the tokens accrued so far, up to the new burst
+	now = now.Add(200 * time.Millisecond)
+	lim.SetRate(100, 1)
+	require.EqualValues(t, 1, lim.Burst())
+	require.EqualValues(t, 1, lim.Tokens())
+	require.Equal(t, 10*time.Millisecond, lim.ReserveN(2))
+
+	// An unlimited limiter allows everything, and starts full when limited again
+	lim.SetRate(0, 0)
+	require.Zero(t, lim.ReserveN(1_000_000))
+	require.True(t, lim.AllowN(1_000_000))
+	lim.SetRate(1, 3)
+	require.EqualValues(t, 3, lim.Tokens())
+}
+
+func TestRateLimiterWait(t *testing.T) {
+	t.Parallel()
+
+	lim := utils.NewRateLimiter(utils.RateLimiterOpts{Rate: 100})
+	require.EqualValues(t, 100, lim.Burst())
+	ctx := context.Background()
+	require.NoError(t, lim.WaitN(ctx, 100))
+
+	// Waiting repays the debt
+	start := time.Now()
+	require.NoError(t, lim.WaitN(ctx, 2))
+	require.GreaterOrEqual(t, time.Since(start), 15*time.Millisecond)
+
+	// A wait that would outlast the deadline fails at once, returning its tokens
+	deadlineCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	err := lim.WaitN(deadlineCtx, 100)
+	require.True(t, errors.Is(err, context.DeadlineExceeded), err)
+	require.Greater(t, lim.Tokens(), -1.0)
+
+	// A cancelled wait returns its tokens
+	cancelCtx, cancel := context.WithCancel(ctx)
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+	require.ErrorIs(t, lim.WaitN(cancelCtx, 50), context.Canceled)
+	require.Greater(t, lim.Tokens(), -1.0)
+}
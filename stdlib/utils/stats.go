@@ -0,0 +1,290 @@
+package utils
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// Streaming stats
+//
+// EWMA, WindowCounter, and Sketch summarize a stream of samples in constant space, e.g. to report the latency p50 and
+// p99 and the rolling throughput of a subsystem without retaining its samples.  Each is safe for concurrent use.
+
+// EWMA is an exponentially weighted moving average, where each sample moves the average toward it by a given fraction.
+type EWMA struct {
+	alpha float64
+	mu    sync.Mutex
+	value float64
+	count int64
+}
+
+// NewEWMA returns an EWMA where each sample moves the average toward it by the given fraction in (0, 1] -- a sample's
+// weight decays by half after about 0.69/alpha further samples.
+func NewEWMA(alpha float64) *EWMA {
+	return &EWMA{
+		alpha: alpha,
+	}
+}
+
+// Update adds the given sample, where the first sample sets the average.
+func (avg *EWMA) Update(x float64) {
+	avg.mu.Lock()
+	defer avg.mu.Unlock()
+	if avg.count == 0 {
+		avg.value = x
+	} else {
+		avg.value += avg.alpha * (x - avg.value)
+	}
+	avg.count++
+}
+
+// Value returns the average (or 0 if there have been no samples).
+func (avg *EWMA) Value() float64 {
+	avg.mu.Lock()
+	defer avg.mu.Unlock()
+	return avg.value
+}
+
+// Count returns how many samples have been added.
+func (avg *EWMA) Count() int64 {
+	avg.mu.Lock()
+	defer avg.mu.Unlock()
+	return avg.count
+}
+
+// WindowCounterOpts are the options used to create a WindowCounter.
+type WindowCounterOpts struct {
+	Window  time.Duration    // the span of time counted (default 1m)
+	Buckets int              // the window is divided into this many buckets, each dropped as a whole as it ages out (default 60)
+	Now     func() time.Time // returns the current time (default time.Now)
+}
+
+// WindowCounter counts events over a sliding window of time, e.g. to report rolling throughput.
+type WindowCounter struct {
+	opts   WindowCounterOpts
+	width  int64 // nanoseconds spanned by each bucket
+	mu     sync.Mutex
+	counts []int64
+	epochs []int64 // when each bucket began, in units of width, or 0 if never used
+}
+
+// NewWindowCounter returns an empty WindowCounter with the given options.
+func NewWindowCounter(opts WindowCounterOpts) *WindowCounter {
+	if opts.Window <= 0 {
+		opts.Window = time.Minute
+	}
+	if opts.Buckets <= 0 {
+		opts.Buckets = 60
+	}
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+	return &WindowCounter{
+		opts:   opts,
+		width:  max(int64(opts.Window)/int64(opts.Buckets), 1),
+		counts: make([]int64, opts.Buckets),
+		epochs: make([]int64, opts.Buckets),
+	}
+}
+
+// Add counts n events as occurring now.
+func (wc *WindowCounter) Add(n int64) {
+	epoch := wc.opts.Now().UnixNano() / wc.width
+	i := int(epoch % int64(len(wc.counts)))
+
+	wc.mu.Lock()
+	defer wc.mu.Unlock()
+	if wc.epochs[i] != epoch {
+		wc.counts[i], wc.epochs[i] = 0, epoch
+	}
+	wc.counts[i] += n
+}
+
+// Sum returns the number of events counted within the window.
+func (wc *WindowCounter) Sum() int64 {
+	epoch := wc.opts.Now().UnixNano() / wc.width
+	oldest := epoch - int64(len(wc.counts)) + 1
+
+	wc.mu.Lock()
+	defer wc.mu.Unlock()
+	var sum int64
+	for i, count := range wc.counts {
+		if wc.epochs[i] >= oldest && wc.epochs[i] <= epoch {
+			sum += count
+		}
+	}
+	return sum
+}
+
+// Rate returns the events per second within the window.
+func (wc *WindowCounter) Rate() float64 {
+	return float64(wc.Sum()) / wc.opts.Window.Seconds()
+}
+
+const (
+	DefaultSketchAccuracy = 0.01
+	DefaultSketchBins     = 2048
+
+	sketchMinValue = 1e-9 // smaller samples are counted as 0
+)
+
+// Sketch estimates the quantiles of a stream of non-negative samples (e.g. latencies) in constant space, where each
+// quantile is within a given relative accuracy of the true value.  Samples are counted in logarithmically sized bins
+// (as in DDSketch), so that sketches of the same accuracy can be merged, e.g. to combine per-shard or per-interval sketches.
+type Sketch struct {
+	gamma    float64 // ratio of the bounds of each bin
+	logGamma float64
+	maxBins  int
+
+	mu       sync.Mutex
+	minIndex int      // index of the value of counts[0]
+	counts   []uint64 // count of samples in each bin, from minIndex up
+	zeros    uint64   // count of samples too small for a bin
+	count    uint64
+	sum      float64
+	min, max float64
+}
+
+// NewSketch returns an empty Sketch whose quantiles are within the given relative accuracy (default
+// DefaultSketchAccuracy), holding at most maxBins bins (default DefaultSketchBins) -- beyond that, the smallest bins are
+// merged, so that the accuracy of the lowest quantiles degrades first.
+func NewSketch(accuracy float64, maxBins int) *Sketch {
+	if accuracy <= 0 || accuracy >= 1 {
+		accuracy = DefaultSketchAccuracy
+	}
+	if maxBins <= 0 {
+		maxBins = DefaultSketchBins
+	}
+	gamma := (1 + accuracy) / (1 - accuracy)
+	return &Sketch{
+		gamma:    gamma,
+		logGamma: math.Log(gamma),
+		maxBins:  maxBins,
+	}
+}
+
+// Add adds the given sample, where a negative sample is counted as 0.
+func (sk *Sketch) Add(x float64) {
+	sk.mu.Lock()
+	defer sk.mu.Unlock()
+	x = max(x, 0)
+	if sk.count == 0 {
+		sk.min, sk.max = x, x
+	} else {
+		sk.min, sk.max = min(sk.min, x), max(sk.max, x)
+	}
+	sk.count++
+	sk.sum += x
+	if x < sketchMinValue {
+		sk.zeros++
+		return
+	}
+	sk.addToBin(int(math.Ceil(math.Log(x)/sk.logGamma)), 1)
+}
+
+// AddDuration adds the given duration as a sample in seconds.
+func (sk *Sketch) AddDuration(d time.Duration) {
+	sk.Add(d.Seconds())
+}
+
+// addToBin adds n samples to the bin with the given index, growing and then trimming the bins as needed -- sk.mu must be locked.
+func (sk *Sketch) addToBin(index int, n uint64) {
+	switch {
+	case len(sk.counts) == 0:
+		sk.minIndex = index
+		sk.counts = append(sk.counts, 0)
+	case index < sk.minIndex:
+		grown := make([]uint64, sk.minIndex-index+len(sk.counts))
+		copy(grown[sk.minIndex-index:], sk.counts)
+		sk.counts, sk.minIndex = grown, index
+	case index >= sk.minIndex+len(sk.counts):
+		sk.counts = append(sk.counts, make([]uint64, index-sk.minIndex-len(sk.counts)+1)...)
+	}
+	sk.counts[index-sk.minIndex] += n
+
+	if excess := len(sk.counts) - sk.maxBins; excess > 0 {
+		for _, count := range sk.counts[:excess] {
+			sk.counts[excess] += count
+		}
+		sk.counts = append(sk.counts[:0], sk.counts[excess:]...)
+		sk.minIndex += excess
+	}
+}
+
+// Count returns how many samples have been added.
+func (sk *Sketch) Count() uint64 {
+	sk.mu.Lock()
+	defer sk.mu.Unlock()
+	return sk.count
+}
+
+// Mean returns the mean of the samples (or 0 if there are none).
+func (sk *Sketch) Mean() float64 {
+	sk.mu.Lock()
+	defer sk.mu.Unlock()
+	if sk.count == 0 {
+		return 0
+	}
+	return sk.sum / float64(sk.count)
+}
+
+// Quantile returns the estimated value at the given quantile in [0, 1] (e.g. 0.99 for p99), or 0 if there are no samples.
+func (sk *Sketch) Quantile(q float64) float64 {
+	sk.mu.Lock()
+	defer sk.mu.Unlock()
+	if sk.count == 0 {
+		return 0
+	}
+	q = min(max(q, 0), 1)
+	rank := uint64(q * float64(sk.count-1))
+	if rank < sk.zeros {
+		return sk.min
+	}
+
+	seen := sk.zeros
+	for i, count := range sk.counts {
+		seen += count
+		if seen > rank {
+			// The midpoint of the bin (gamma^(index-1), gamma^index] is within the accuracy of every value in it
+			val := 2 * math.Pow(sk.gamma, float64(sk.minIndex+i)) / (sk.gamma + 1)
+			return min(max(val, sk.min), sk.max)
+		}
+	}
+	return sk.max
+}
+
+// Merge adds the samples of the given sketch, which must have been created with the same accuracy, to this one.
+func (sk *Sketch) Merge(other *Sketch) {
+	other.mu.Lock()
+	minIndex, counts := other.minIndex, append([]uint64(nil), other.counts...)
+	zeros, count, sum, otherMin, otherMax := other.zeros, other.count, other.sum, other.min, other.max
+	other.mu.Unlock()
+	if count == 0 {
+		return
+	}
+
+	sk.mu.Lock()
+	defer sk.mu.Unlock()
+	if sk.count == 0 {
+		sk.min, sk.max = otherMin, otherMax
+	} else {
+		sk.min, sk.max = min(sk.min, otherMin), max(sk.max, otherMax)
+	}
+	sk.count += count
+	sk.sum += sum
+	sk.zeros += zeros
+	for i, n := range counts {
+		if n > 0 {
+			sk.addToBin(minIndex+i, n)
+		}
+	}
+}
+
+// Reset removes all samples.
+func (sk *Sketch) Reset() {
+	sk.mu.Lock()
+	defer sk.mu.Unlock()
+	sk.counts = sk.counts[:0]
+	sk.zeros, sk.count, sk.sum, sk.min, sk.max = 0, 0, 0, 0, 0
+}
@@ -0,0 +1,101 @@
+package utils_test
+
+import (
+	"math"
+	"math/rand/v2"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/utils"
+)
+
+func TestEWMA(t *testing.T) {
+	t.Parallel()
+
+	avg := utils.NewEWMA(0.5)
+	require.Zero(t, avg.Value())
+	avg.Update(10)
+	require.Equal(t, 10.0, avg.Value())
+	avg.Update(20)
+	require.Equal(t, 15.0, avg.Value())
+	for i := 0; i < 50; i++ {
+		avg.Update(100)
+	}
+	require.InDelta(t, 100, avg.Value(), 1e-9)
+	require.EqualValues(t, 52, avg.Count())
+}
+
+func TestWindowCounter(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1_700_000_000, 0)
+	wc := utils.NewWindowCounter(utils.WindowCounterOpts{
+		Window:  10 * time.Second,
+		Buckets: 10,
+		Now:     func() time.Time { return now },
+	})
+	for i := 0; i < 10; i++ {
+		wc.Add(int64(i))
+		now = now.Add(time.Second)
+	}
+	// The oldest bucket (0 events) has aged out
+	require.EqualValues(t, 45, wc.Sum())
+	require.Equal(t, 4.5, wc.Rate())
+
+	// Buckets age out as the window slides
+	now = now.Add(5 * time.Second)
+	require.EqualValues(t, 6+7+8+9, wc.Sum())
+	wc.Add(100)
+	require.EqualValues(t, 130, wc.Sum())
+	now = now.Add(time.Hour)
+	require.Zero(t, wc.Sum())
+}
+
+func TestSketch(t *testing.T) {
+	t.Parallel()
+
+	const accuracy = 0.01
+	sk := utils.NewSketch(accuracy, 0)
+	require.Zero(t, sk.Quantile(0.5))
+
+	// Quantiles of a long-tailed distribution are within the sketch's accuracy
+	rng := rand.New(rand.NewPCG(1, 2))
+	samples := make([]float64, 10000)
+	for i := range samples {
+		samples[i] = rng.ExpFloat64() * 0.05
+		sk.Add(samples[i])
+	}
+	sort.Float64s(samples)
+	for _, q := range []float64{0, 0.5, 0.9, 0.99, 0.999, 1} {
+		want := samples[int(q*float64(len(samples)-1))]
+		got := sk.Quantile(q)
+		require.InEpsilon(t, want, got, accuracy*1.01, "q=%g", q)
+	}
+	require.EqualValues(t, len(samples), sk.Count())
+
+	// Merged sketches hold the samples of both
+	other := utils.NewSketch(accuracy, 0)
+	other.AddDuration(10 * time.Second)
+	other.Add(0)
+	other.Add(-1)
+	sk.Merge(other)
+	require.EqualValues(t, len(samples)+3, sk.Count())
+	require.Equal(t, 10.0, sk.Quantile(1))
+	require.Zero(t, sk.Quantile(0))
+
+	// Limiting the bins degrades the lowest quantiles first
+	small := utils.NewSketch(accuracy, 50)
+	for _, x := range samples {
+		small.Add(x)
+	}
+	require.InEpsilon(t, samples[len(samples)*99/100], small.Quantile(0.99), accuracy*1.01)
+	require.Greater(t, math.Abs(small.Quantile(0.01)-samples[len(samples)/100]), accuracy*samples[len(samples)/100])
+
+	sk.Reset()
+	require.Zero(t, sk.Count())
+	sk.Add(2)
+	require.Equal(t, 2.0, sk.Quantile(0.5))
+}
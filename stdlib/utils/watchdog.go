@@ -0,0 +1,91 @@
+package utils
+
+import (
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// WatchdogOpts are the options used to create a Watchdog.
+type WatchdogOpts struct {
+	Interval time.Duration            // most time allowed between kicks before the watchdog fires
+	OnFire   func(idle time.Duration) // if set, called when the watchdog fires, with how long it went without a kick
+	Close    io.Closer                // if set, closed when the watchdog fires (e.g. the task.Context of a wedged pipeline)
+}
+
+// Watchdog detects wedged work (e.g. a stalled ffmpeg process or a hung external API call): it must be kicked at least
+// once per WatchdogOpts.Interval, or else it fires, calling OnFire and then closing Close.  A Watchdog fires at most once.
+//
+// Kick is cheap (an atomic store), so it can be called for each unit of progress.  It is safe for concurrent use.
+type Watchdog struct {
+	opts    WatchdogOpts
+	start   time.Time    // kicks are stored relative to this, so that they use the monotonic clock
+	kicked  atomic.Int64 // time of the last kick, since start
+	chFired chan struct{}
+
+	mu      sync.Mutex
+	timer   *time.Timer
+	stopped bool
+}
+
+// NewWatchdog returns a Watchdog with the given options, counting its first interval from now.
+func NewWatchdog(opts WatchdogOpts) *Watchdog {
+	wd := &Watchdog{
+		opts:    opts,
+		start:   time.Now(),
+		chFired: make(chan struct{}),
+	}
+	wd.mu.Lock()
+	wd.timer = time.AfterFunc(opts.Interval, wd.check)
+	wd.mu.Unlock()
+	return wd
+}
+
+// Kick tells this watchdog that progress was made, restarting its interval.
+func (wd *Watchdog) Kick() {
+	wd.kicked.Store(int64(time.Since(wd.start)))
+}
+
+// Fired is closed once this watchdog has fired.
+func (wd *Watchdog) Fired() <-chan struct{} {
+	return wd.chFired
+}
+
+// Stop disarms this watchdog (e.g. once the watched work completes), returning false if it already fired or was stopped.
+func (wd *Watchdog) Stop() bool {
+	wd.mu.Lock()
+	defer wd.mu.Unlock()
+	if wd.stopped {
+		return false
+	}
+	wd.stopped = true
+	wd.timer.Stop()
+	return true
+}
+
+// check fires this watchdog if an interval has passed without a kick, or otherwise waits for the rest of the interval.
+func (wd *Watchdog) check() {
+	idle := time.Since(wd.start) - time.Duration(wd.kicked.Load())
+
+	wd.mu.Lock()
+	if wd.stopped {
+		wd.mu.Unlock()
+		return
+	}
+	if idle < wd.opts.Interval {
+		wd.timer.Reset(wd.opts.Interval - idle)
+		wd.mu.Unlock()
+		return
+	}
+	wd.stopped = true
+	wd.mu.Unlock()
+
+	close(wd.chFired)
+	if wd.opts.OnFire != nil {
+		wd.opts.OnFire(idle)
+	}
+	if wd.opts.Close != nil {
+		wd.opts.Close.Close()
+	}
+}
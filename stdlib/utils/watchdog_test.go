@@ -0,0 +1,69 @@
+package utils_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/amp-3d/amp-sdk-go/stdlib/task"
+	"github.com/amp-3d/amp-sdk-go/stdlib/utils"
+)
+
+func TestWatchdog(t *testing.T) {
+	t.Parallel()
+
+	// Kicks keep the watchdog from firing
+	fired := make(chan time.Duration, 1)
+	wd := utils.NewWatchdog(utils.WatchdogOpts{
+		Interval: 30 * time.Millisecond,
+		OnFire:   func(idle time.Duration) { fired <- idle },
+	})
+	for i := 0; i < 10; i++ {
+		time.Sleep(10 * time.Millisecond)
+		wd.Kick()
+	}
+	select {
+	case <-wd.Fired():
+		t.Fatal("fired despite kicks")
+	default:
+	}
+
+	// Once kicks stop, it fires once
+	select {
+	case idle := <-fired:
+		require.GreaterOrEqual(t, idle, 30*time.Millisecond)
+	case <-time.After(5 * time.Second):
+		t.Fatal("watchdog did not fire")
+	}
+	<-wd.Fired()
+	require.False(t, wd.Stop())
+
+	// A stopped watchdog never fires
+	wd = utils.NewWatchdog(utils.WatchdogOpts{Interval: 5 * time.Millisecond})
+	require.True(t, wd.Stop())
+	time.Sleep(20 * time.Millisecond)
+	select {
+	case <-wd.Fired():
+		t.Fatal("stopped watchdog fired")
+	default:
+	}
+}
+
+func TestWatchdogClose(t *testing.T) {
+	t.Parallel()
+
+	// A watchdog closes the task.Context running wedged work
+	root, err := task.Start(&task.Task{Label: "transcode"})
+	require.NoError(t, err)
+	defer root.Close()
+	utils.NewWatchdog(utils.WatchdogOpts{
+		Interval: 10 * time.Millisecond,
+		Close:    root,
+	})
+	select {
+	case <-root.Closing():
+	case <-time.After(5 * time.Second):
+		t.Fatal("watchdog did not close the context")
+	}
+}